@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -110,6 +111,34 @@ func NewContext(rawContext string, opts ...ContextOption) Context {
 	return ctx
 }
 
+// RegisteredContext is a registered domain separation context.
+type RegisteredContext struct {
+	// Context is the registered context.
+	Context Context `json:"context"`
+	// ChainSeparation is true iff the context is additionally domain
+	// separated based on the chain context.
+	ChainSeparation bool `json:"chain_separation"`
+}
+
+// RegisteredContexts returns all registered contexts, ordered by context.
+//
+// Note that only contexts registered by packages linked into the binary are
+// returned.
+func RegisteredContexts() []RegisteredContext {
+	var contexts []RegisteredContext
+	registeredContexts.Range(func(key, value interface{}) bool {
+		contexts = append(contexts, RegisteredContext{
+			Context:         key.(Context),
+			ChainSeparation: value.(*contextOptions).chainSeparation,
+		})
+		return true
+	})
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Context < contexts[j].Context
+	})
+	return contexts
+}
+
 // UnsafeResetChainContext resets the chain context.
 //
 // This function should NOT be used during normal operation as changing
@@ -247,17 +276,21 @@ func PrepareSignerContext(context Context) ([]byte, error) {
 	opts := rawOpts.(*contextOptions)
 
 	// Include chain domain separation context if configured.
-	if opts.chainSeparation {
-		chainContextLock.RLock()
-		defer chainContextLock.RUnlock()
+	if !opts.chainSeparation {
+		return []byte(context), nil
+	}
 
-		if chainContext == "" {
-			return nil, errNoChainContext
-		}
-		context = context + chainContextSeparator + chainContext
+	chainContextLock.RLock()
+	defer chainContextLock.RUnlock()
+
+	if chainContext == "" {
+		return nil, errNoChainContext
 	}
+	return chainSeparatedContext(context, chainContext), nil
+}
 
-	return []byte(context), nil
+func chainSeparatedContext(context, chainContext Context) []byte {
+	return []byte(context + chainContextSeparator + chainContext)
 }
 
 // PrepareSignerMessage prepares a context and message for signing by a Signer.
@@ -267,6 +300,10 @@ func PrepareSignerMessage(context Context, message []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	return digestMessage(rawContext, message), nil
+}
+
+func digestMessage(rawContext, message []byte) []byte {
 	// This is stupid, and we should be using RFC 8032's Ed25519ph instead
 	// but when an attempt was made to switch to it (See: #2103), people
 	// complained that certain HSM offerings doesn't support it.
@@ -277,5 +314,5 @@ func PrepareSignerMessage(context Context, message []byte) ([]byte, error) {
 	_, _ = h.Write(message)
 	sum := h.Sum(nil)
 
-	return sum[:], nil
+	return sum[:]
 }
@@ -0,0 +1,321 @@
+// Package pkcs11 provides a PKCS#11 backed signer.
+//
+// This allows the private keys to be stored in hardware security modules
+// (HSMs), or in TPMs via a PKCS#11 module (e.g., tpm2-pkcs11). The module
+// and token must support Ed25519 keys (CKK_EC_EDWARDS) and the CKM_EDDSA
+// signature mechanism.
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+const (
+	// SignerName is the name used to identify the PKCS#11 backed signer.
+	SignerName = "pkcs11"
+
+	// The following constants are defined in PKCS#11 v3.0 and are not yet
+	// provided by the PKCS#11 wrapper.
+	ckkECEdwards       = 0x00000040
+	ckmECEdwardsKeyGen = 0x00001055
+	ckmEdDSA           = 0x00001057
+)
+
+var (
+	_ signature.SignerFactoryCtor = NewFactory
+	_ signature.SignerFactory     = (*Factory)(nil)
+	_ signature.Signer            = (*Signer)(nil)
+
+	// ed25519Params are the DER encoded EC parameters for Ed25519 keys
+	// (OID 1.3.101.112).
+	ed25519Params = []byte{0x06, 0x03, 0x2b, 0x65, 0x70}
+
+	roleKeyLabels = map[signature.SignerRole]string{
+		signature.SignerEntity:    "oasis-entity",
+		signature.SignerNode:      "oasis-identity",
+		signature.SignerP2P:       "oasis-p2p",
+		signature.SignerConsensus: "oasis-consensus",
+	}
+)
+
+// FactoryConfig is the config necessary to create a Factory for PKCS#11
+// Signers.
+type FactoryConfig struct {
+	// Module is the path to the PKCS#11 module (shared library).
+	Module string
+	// TokenLabel is the label of the token holding the keys.
+	TokenLabel string
+	// PIN is the user PIN of the token.
+	PIN string
+}
+
+// Factory is a PKCS#11 backed SignerFactory.
+type Factory struct {
+	sync.Mutex
+
+	roles []signature.SignerRole
+
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+}
+
+// NewFactory creates a new factory with the specified roles.
+func NewFactory(config interface{}, roles ...signature.SignerRole) (signature.SignerFactory, error) {
+	cfg, ok := config.(*FactoryConfig)
+	if !ok {
+		return nil, errors.New("signature/signer/pkcs11: invalid PKCS#11 signer configuration provided")
+	}
+
+	ctx := pkcs11.New(cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to load module '%s'", cfg.Module)
+	}
+	if err := ctx.Initialize(); err != nil && !isError(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to initialize module: %w", err)
+	}
+
+	slot, err := findSlot(ctx, cfg.TokenLabel)
+	if err != nil {
+		return nil, err
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to open session: %w", err)
+	}
+	if err = ctx.Login(session, pkcs11.CKU_USER, cfg.PIN); err != nil && !isError(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		_ = ctx.CloseSession(session)
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to log into token: %w", err)
+	}
+
+	return &Factory{
+		roles:   append([]signature.SignerRole{}, roles...),
+		ctx:     ctx,
+		session: session,
+	}, nil
+}
+
+// EnsureRole ensures that the SignerFactory is configured for the given
+// role.
+func (fac *Factory) EnsureRole(role signature.SignerRole) error {
+	for _, v := range fac.roles {
+		if v == role {
+			return nil
+		}
+	}
+	return signature.ErrRoleMismatch
+}
+
+// Generate will generate and persist a new private key corresponding to the
+// role on the token, and return a Signer ready for use.
+//
+// Note that the key is generated by the token, so `rng` is not used.
+func (fac *Factory) Generate(role signature.SignerRole, _rng io.Reader) (signature.Signer, error) {
+	if err := fac.EnsureRole(role); err != nil {
+		return nil, err
+	}
+
+	fac.Lock()
+	defer fac.Unlock()
+
+	// Ensure that we aren't trying to overwrite an existing key.
+	label := roleKeyLabels[role]
+	existing, err := fac.findObjects(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("signature/signer/pkcs11: key already exists")
+	}
+
+	publicTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, ckkECEdwards),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ed25519Params),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	privateTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, ckkECEdwards),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if _, _, err = fac.ctx.GenerateKeyPair(
+		fac.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(ckmECEdwardsKeyGen, nil)},
+		publicTemplate,
+		privateTemplate,
+	); err != nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to generate key: %w", err)
+	}
+
+	return fac.doLoad(role)
+}
+
+// Load will load the private key corresponding to the role from the token,
+// and return a Signer ready for use.
+func (fac *Factory) Load(role signature.SignerRole) (signature.Signer, error) {
+	if err := fac.EnsureRole(role); err != nil {
+		return nil, err
+	}
+
+	fac.Lock()
+	defer fac.Unlock()
+
+	return fac.doLoad(role)
+}
+
+func (fac *Factory) doLoad(role signature.SignerRole) (signature.Signer, error) {
+	label := roleKeyLabels[role]
+	privateKeys, err := fac.findObjects(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	publicKeys, err := fac.findObjects(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(privateKeys) == 0 || len(publicKeys) == 0:
+		return nil, signature.ErrNotExist
+	case len(privateKeys) > 1 || len(publicKeys) > 1:
+		return nil, fmt.Errorf("signature/signer/pkcs11: multiple keys with label '%s'", label)
+	}
+
+	attrs, err := fac.ctx.GetAttributeValue(fac.session, publicKeys[0], []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to get public key: %w", err)
+	}
+	publicKey, err := decodeECPoint(attrs[0].Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		factory:    fac,
+		privateKey: privateKeys[0],
+		publicKey:  publicKey,
+	}, nil
+}
+
+func (fac *Factory) findObjects(class uint, label string) ([]pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, ckkECEdwards),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := fac.ctx.FindObjectsInit(fac.session, template); err != nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to find objects: %w", err)
+	}
+	objs, _, err := fac.ctx.FindObjects(fac.session, 2)
+	if fErr := fac.ctx.FindObjectsFinal(fac.session); fErr != nil && err == nil {
+		err = fErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to find objects: %w", err)
+	}
+	return objs, nil
+}
+
+// Signer is a PKCS#11 backed Signer.
+type Signer struct {
+	factory    *Factory
+	privateKey pkcs11.ObjectHandle
+	publicKey  signature.PublicKey
+}
+
+// Public returns the PublicKey corresponding to the signer.
+func (s *Signer) Public() signature.PublicKey {
+	return s.publicKey
+}
+
+// ContextSign generates a signature with the private key over the context and
+// message.
+func (s *Signer) ContextSign(context signature.Context, message []byte) ([]byte, error) {
+	data, err := signature.PrepareSignerMessage(context, message)
+	if err != nil {
+		return nil, err
+	}
+
+	fac := s.factory
+	if fac == nil {
+		return nil, errors.New("signature/signer/pkcs11: signer has been reset")
+	}
+
+	fac.Lock()
+	defer fac.Unlock()
+
+	if err = fac.ctx.SignInit(fac.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(ckmEdDSA, nil)}, s.privateKey); err != nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to initialize signing: %w", err)
+	}
+	sig, err := fac.ctx.Sign(fac.session, data)
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/pkcs11: failed to sign: %w", err)
+	}
+	return sig, nil
+}
+
+// String returns the public key of the Signer.
+func (s *Signer) String() string {
+	return fmt.Sprintf("[pkcs11 signer: %s]", s.publicKey)
+}
+
+// Reset tears down the Signer.
+func (s *Signer) Reset() {
+	s.factory = nil
+	s.privateKey = 0
+}
+
+func findSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("signature/signer/pkcs11: failed to get slot list: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("signature/signer/pkcs11: failed to get token info: %w", err)
+		}
+		if info.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("signature/signer/pkcs11: token '%s' not found", tokenLabel)
+}
+
+// decodeECPoint decodes the CKA_EC_POINT attribute of an Ed25519 public key,
+// which is either a DER encoded OCTET STRING or the raw public key.
+func decodeECPoint(raw []byte) (signature.PublicKey, error) {
+	var pk signature.PublicKey
+
+	point := raw
+	if len(raw) != signature.PublicKeySize {
+		if rest, err := asn1.Unmarshal(raw, &point); err != nil || len(rest) != 0 {
+			return pk, fmt.Errorf("signature/signer/pkcs11: malformed public key encoding")
+		}
+	}
+	if err := pk.UnmarshalBinary(point); err != nil {
+		return pk, fmt.Errorf("signature/signer/pkcs11: malformed public key: %w", err)
+	}
+	return pk, nil
+}
+
+func isError(err error, code uint) bool {
+	var pErr pkcs11.Error
+	return errors.As(err, &pErr) && uint(pErr) == code
+}
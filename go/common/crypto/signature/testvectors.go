@@ -0,0 +1,93 @@
+package signature
+
+import (
+	"crypto/sha512"
+
+	"github.com/oasislabs/ed25519"
+)
+
+// TestVectorKeySeed is the seed of the Ed25519 private key used to sign the
+// generated context test vectors.
+const TestVectorKeySeed = "oasis-core/signature: test vector key"
+
+// TestVectorMessages are the canonical payloads that context test vectors are
+// generated for.
+var TestVectorMessages = func() [][]byte {
+	allBytes := make([]byte, 256)
+	for i := range allBytes {
+		allBytes[i] = byte(i)
+	}
+
+	return [][]byte{
+		{},
+		[]byte("oasis-core test vector"),
+		allBytes,
+	}
+}()
+
+// ContextTestVector is a test vector for signing a message under a registered
+// domain separation context.
+type ContextTestVector struct {
+	// Context is the registered context.
+	Context Context `json:"context"`
+	// ChainSeparation is true iff the context is additionally domain
+	// separated based on the chain context.
+	ChainSeparation bool `json:"chain_separation"`
+	// RawContext is the context as used for domain separation, including the
+	// chain context if the context is chain separated.
+	RawContext string `json:"raw_context"`
+	// Message is the message being signed.
+	Message []byte `json:"message"`
+	// Digest is the SHA512/256 digest of the raw context and message, which
+	// is what actually gets signed.
+	Digest []byte `json:"digest"`
+	// PublicKey is the public key of the test vector key.
+	PublicKey PublicKey `json:"public_key"`
+	// Signature is the signature of the digest by the test vector key.
+	Signature RawSignature `json:"signature"`
+}
+
+// GenerateContextTestVectors generates test vectors for every registered
+// context and every canonical payload in TestVectorMessages, using the given
+// chain context for chain separated contexts.
+//
+// The test vectors are signed by a fixed key derived from TestVectorKeySeed
+// so that the output is deterministic.
+func GenerateContextTestVectors(chainContext string) ([]*ContextTestVector, error) {
+	if l := len(chainContext); l == 0 || l > chainContextMaxSize {
+		return nil, errMalformedContext
+	}
+
+	seed := sha512.Sum512_256([]byte(TestVectorKeySeed))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	var publicKey PublicKey
+	if err := publicKey.UnmarshalBinary(privateKey.Public().(ed25519.PublicKey)); err != nil {
+		return nil, err
+	}
+
+	var vectors []*ContextTestVector
+	for _, rc := range RegisteredContexts() {
+		rawContext := []byte(rc.Context)
+		if rc.ChainSeparation {
+			rawContext = chainSeparatedContext(rc.Context, Context(chainContext))
+		}
+
+		for _, msg := range TestVectorMessages {
+			digest := digestMessage(rawContext, msg)
+
+			var sig RawSignature
+			copy(sig[:], ed25519.Sign(privateKey, digest))
+
+			vectors = append(vectors, &ContextTestVector{
+				Context:         rc.Context,
+				ChainSeparation: rc.ChainSeparation,
+				RawContext:      string(rawContext),
+				Message:         msg,
+				Digest:          digest,
+				PublicKey:       publicKey,
+				Signature:       sig,
+			})
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,51 @@
+package signature
+
+import (
+	"testing"
+
+	"github.com/oasislabs/ed25519"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextTestVectors(t *testing.T) {
+	require := require.New(t)
+
+	ctx := NewContext("test: test vector context")
+	chainCtx := NewContext("test: test vector chain context", WithChainSeparation())
+
+	// Registered contexts should be enumerable.
+	contexts := RegisteredContexts()
+	require.Contains(contexts, RegisteredContext{Context: ctx})
+	require.Contains(contexts, RegisteredContext{Context: chainCtx, ChainSeparation: true})
+
+	_, err := GenerateContextTestVectors("")
+	require.Error(err, "GenerateContextTestVectors should fail with an empty chain context")
+
+	vectors, err := GenerateContextTestVectors("test: test vector chain")
+	require.NoError(err, "GenerateContextTestVectors")
+	require.Len(vectors, len(contexts)*len(TestVectorMessages))
+
+	var seen int
+	for _, v := range vectors {
+		switch v.Context {
+		case ctx:
+			require.Equal(string(ctx), v.RawContext)
+			expected, err := PrepareSignerMessage(ctx, v.Message)
+			require.NoError(err, "PrepareSignerMessage")
+			require.Equal(expected, v.Digest, "digest should match the signer digest")
+			require.True(v.PublicKey.Verify(ctx, v.Message, v.Signature[:]), "signature should verify")
+		case chainCtx:
+			require.Equal(string(chainCtx)+" for chain test: test vector chain", v.RawContext)
+		default:
+			continue
+		}
+		require.True(ed25519.Verify(ed25519.PublicKey(v.PublicKey[:]), v.Digest, v.Signature[:]), "signature should verify")
+		seen++
+	}
+	require.Equal(2*len(TestVectorMessages), seen, "all test contexts should have vectors")
+
+	// Output should be deterministic.
+	vectors2, err := GenerateContextTestVectors("test: test vector chain")
+	require.NoError(err, "GenerateContextTestVectors")
+	require.Equal(vectors, vectors2, "test vectors should be deterministic")
+}
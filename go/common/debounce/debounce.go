@@ -0,0 +1,112 @@
+// Package debounce implements a block-height based update debouncer.
+package debounce
+
+import "sort"
+
+type pendingUpdate struct {
+	value  interface{}
+	height int64
+	seq    uint64
+}
+
+// BlockDebouncer holds back keyed updates until they have remained unchanged
+// for a configured number of blocks.
+//
+// Each new update for a key replaces any pending update for the same key and
+// restarts its stability window. This makes it possible to ignore rapid
+// successive updates (e.g., during registration races) and only act on the
+// final one.
+//
+// A BlockDebouncer is not safe for concurrent use.
+type BlockDebouncer struct {
+	stableBlocks int64
+
+	pending map[interface{}]*pendingUpdate
+	seq     uint64
+}
+
+// Enabled returns true iff the debouncer holds back updates. A debouncer
+// configured with zero stable blocks releases all updates immediately.
+func (d *BlockDebouncer) Enabled() bool {
+	return d.stableBlocks > 0
+}
+
+// Len returns the number of pending updates.
+func (d *BlockDebouncer) Len() int {
+	return len(d.pending)
+}
+
+// Update records a new update for the given key, observed at the given
+// block height.
+//
+// A non-positive height means that the current block height is not yet
+// known. In this case the stability window of the update starts at the
+// height passed to the next call to Tick.
+//
+// In case the debouncer is not enabled, the update is returned back
+// immediately and nothing is recorded.
+func (d *BlockDebouncer) Update(key, value interface{}, height int64) []interface{} {
+	if !d.Enabled() {
+		return []interface{}{value}
+	}
+
+	d.seq++
+	d.pending[key] = &pendingUpdate{
+		value:  value,
+		height: height,
+		seq:    d.seq,
+	}
+	return nil
+}
+
+// Tick advances the debouncer to the given block height and returns all
+// updates that have been stable for at least the configured number of
+// blocks, in the order in which they were recorded.
+func (d *BlockDebouncer) Tick(height int64) []interface{} {
+	return d.release(func(p *pendingUpdate) bool {
+		if p.height <= 0 {
+			p.height = height
+		}
+		return height-p.height >= d.stableBlocks
+	})
+}
+
+// Flush returns all pending updates regardless of their stability, in the
+// order in which they were recorded.
+func (d *BlockDebouncer) Flush() []interface{} {
+	return d.release(func(p *pendingUpdate) bool {
+		return true
+	})
+}
+
+func (d *BlockDebouncer) release(cond func(*pendingUpdate) bool) []interface{} {
+	var ready []*pendingUpdate
+	for key, p := range d.pending {
+		if !cond(p) {
+			continue
+		}
+		ready = append(ready, p)
+		delete(d.pending, key)
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		return ready[i].seq < ready[j].seq
+	})
+	values := make([]interface{}, 0, len(ready))
+	for _, p := range ready {
+		values = append(values, p.value)
+	}
+	return values
+}
+
+// NewBlockDebouncer creates a new block debouncer which releases updates
+// once they have been stable for the given number of blocks.
+func NewBlockDebouncer(stableBlocks uint64) *BlockDebouncer {
+	return &BlockDebouncer{
+		stableBlocks: int64(stableBlocks),
+		pending:      make(map[interface{}]*pendingUpdate),
+	}
+}
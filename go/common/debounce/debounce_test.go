@@ -0,0 +1,52 @@
+package debounce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockDebouncerDisabled(t *testing.T) {
+	require := require.New(t)
+
+	d := NewBlockDebouncer(0)
+	require.False(d.Enabled(), "Enabled")
+	require.Equal([]interface{}{"a"}, d.Update("k", "a", 10), "update should be released immediately")
+	require.Equal(0, d.Len(), "Len")
+	require.Nil(d.Tick(11), "Tick")
+}
+
+func TestBlockDebouncer(t *testing.T) {
+	require := require.New(t)
+
+	d := NewBlockDebouncer(2)
+	require.True(d.Enabled(), "Enabled")
+
+	require.Nil(d.Update("k1", "a", 10), "Update")
+	require.Nil(d.Tick(11), "update should not be stable yet")
+
+	// A new update restarts the stability window.
+	require.Nil(d.Update("k1", "b", 11), "Update")
+	require.Nil(d.Update("k2", "c", 11), "Update")
+	require.Equal(2, d.Len(), "Len")
+	require.Nil(d.Tick(12), "updates should not be stable yet")
+	require.Equal([]interface{}{"b", "c"}, d.Tick(13), "stable updates should be released in order")
+	require.Equal(0, d.Len(), "Len")
+
+	require.Nil(d.Update("k2", "d", 14), "Update")
+	require.Nil(d.Update("k1", "e", 14), "Update")
+	require.Equal([]interface{}{"d", "e"}, d.Flush(), "Flush")
+	require.Nil(d.Flush(), "Flush")
+}
+
+func TestBlockDebouncerUnknownHeight(t *testing.T) {
+	require := require.New(t)
+
+	d := NewBlockDebouncer(2)
+
+	// Updates observed before the height is known must still be debounced.
+	require.Nil(d.Update("k", "a", 0), "Update")
+	require.Nil(d.Tick(100), "update should not be stable yet")
+	require.Nil(d.Tick(101), "update should not be stable yet")
+	require.Equal([]interface{}{"a"}, d.Tick(102), "stable update should be released")
+}
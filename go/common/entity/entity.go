@@ -8,10 +8,12 @@ import (
 	"io"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
 
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/jsonschema"
 	"github.com/oasislabs/oasis-core/go/common/prettyprint"
 )
 
@@ -26,12 +28,13 @@ var (
 	testEntitySigner signature.Signer
 
 	_ prettyprint.PrettyPrinter = (*SignedEntity)(nil)
+	_ jsonschema.Customizer     = (*Entity)(nil)
 )
 
 const (
 	// LatestEntityDescriptorVersion is the latest entity descriptor version that should be used for
 	// all new descriptors. Using earlier versions may be rejected.
-	LatestEntityDescriptorVersion = 1
+	LatestEntityDescriptorVersion = 2
 
 	// Minimum and maximum descriptor versions that are allowed.
 	minEntityDescriptorVersion = 0
@@ -57,6 +60,18 @@ type Entity struct { // nolint: maligned
 	// AllowEntitySignedNodes is true iff nodes belonging to this entity
 	// may be signed with the entity signing key.
 	AllowEntitySignedNodes bool `json:"allow_entity_signed_nodes"`
+
+	// Metadata is optional entity identity information.
+	//
+	// Metadata is only supported by descriptor version 2 and later.
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+// CustomizeJSONSchema refines the JSON schema generated for the type.
+func (e Entity) CustomizeJSONSchema(s *jsonschema.Schema) {
+	s.Title = "Entity descriptor"
+	s.Properties["v"].Minimum = json.Number(strconv.Itoa(minEntityDescriptorVersion))
+	s.Properties["v"].Maximum = json.Number(strconv.Itoa(maxEntityDescriptorVersion))
 }
 
 // ValidateBasic performs basic descriptor validity checks.
@@ -79,6 +94,18 @@ func (e *Entity) ValidateBasic(strictVersion bool) error {
 			)
 		}
 	}
+
+	if e.Metadata != nil {
+		if e.DescriptorVersion < minMetadataDescriptorVersion {
+			return fmt.Errorf("entity metadata requires descriptor version %d or later (got: %d)",
+				minMetadataDescriptorVersion,
+				e.DescriptorVersion,
+			)
+		}
+		if err := e.Metadata.ValidateBasic(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -149,6 +176,7 @@ func GenerateWithSigner(baseDir string, signer signature.Signer, template *Entit
 	if template != nil {
 		ent.Nodes = template.Nodes
 		ent.AllowEntitySignedNodes = template.AllowEntitySignedNodes
+		ent.Metadata = template.Metadata
 	}
 
 	if err := ent.Save(baseDir); err != nil {
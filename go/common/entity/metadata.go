@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+)
+
+const (
+	// MaxMetadataNameLength is the maximum length of the entity name in bytes.
+	MaxMetadataNameLength = 50
+	// MaxMetadataURLLength is the maximum length of the entity URL in bytes.
+	MaxMetadataURLLength = 64
+	// MaxMetadataEmailLength is the maximum length of the entity contact
+	// email address in bytes.
+	MaxMetadataEmailLength = 32
+
+	// minMetadataDescriptorVersion is the minimum entity descriptor version
+	// that supports metadata.
+	minMetadataDescriptorVersion = 2
+)
+
+// Metadata is optional entity identity information.
+//
+// As metadata is part of the entity descriptor, it is signed by the entity
+// signing key and can serve as a canonical source of entity identity
+// information (e.g., for block explorers).
+type Metadata struct {
+	// Name is the human readable entity name.
+	Name string `json:"name,omitempty"`
+
+	// URL is the entity's website URL. If set, it must be an HTTPS URL.
+	URL string `json:"url,omitempty"`
+
+	// Email is the entity's contact email address.
+	Email string `json:"email,omitempty"`
+
+	// LogoHash is the hash of the entity's logo image.
+	LogoHash *hash.Hash `json:"logo_hash,omitempty"`
+}
+
+// ValidateBasic performs basic metadata validity checks.
+func (m *Metadata) ValidateBasic() error {
+	if err := validateMetadataText("name", m.Name, MaxMetadataNameLength); err != nil {
+		return err
+	}
+
+	if err := validateMetadataText("url", m.URL, MaxMetadataURLLength); err != nil {
+		return err
+	}
+	if m.URL != "" {
+		u, err := url.Parse(m.URL)
+		if err != nil {
+			return fmt.Errorf("entity: malformed metadata url: %w", err)
+		}
+		if u.Scheme != "https" || u.Host == "" || u.User != nil {
+			return fmt.Errorf("entity: metadata url must be an https url without user information")
+		}
+	}
+
+	if err := validateMetadataText("email", m.Email, MaxMetadataEmailLength); err != nil {
+		return err
+	}
+	if m.Email != "" {
+		addr, err := mail.ParseAddress(m.Email)
+		if err != nil {
+			return fmt.Errorf("entity: malformed metadata email: %w", err)
+		}
+		if addr.Address != m.Email {
+			return fmt.Errorf("entity: metadata email must be a bare address")
+		}
+	}
+
+	if m.LogoHash != nil && m.LogoHash.IsEmpty() {
+		return fmt.Errorf("entity: metadata logo hash must not be the empty hash")
+	}
+
+	return nil
+}
+
+func validateMetadataText(field, s string, maxLength int) error {
+	if len(s) > maxLength {
+		return fmt.Errorf("entity: metadata %s too long (max: %d got: %d)", field, maxLength, len(s))
+	}
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("entity: metadata %s is not valid UTF-8", field)
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("entity: metadata %s contains non-printable characters", field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+)
+
+func TestMetadataValidateBasic(t *testing.T) {
+	require := require.New(t)
+
+	logoHash := hash.NewFromBytes([]byte("logo"))
+	var emptyHash hash.Hash
+	emptyHash.Empty()
+
+	for _, tc := range []struct {
+		md    Metadata
+		valid bool
+		msg   string
+	}{
+		{Metadata{}, true, "empty metadata should be valid"},
+		{Metadata{Name: "Entity", URL: "https://example.com/entity", Email: "entity@example.com", LogoHash: &logoHash}, true, "full metadata should be valid"},
+		{Metadata{Name: strings.Repeat("a", MaxMetadataNameLength)}, true, "name at the length limit should be valid"},
+		{Metadata{Name: strings.Repeat("a", MaxMetadataNameLength+1)}, false, "name over the length limit should be invalid"},
+		{Metadata{Name: "Entity\n"}, false, "name with control characters should be invalid"},
+		{Metadata{Name: "\xff"}, false, "name with invalid UTF-8 should be invalid"},
+		{Metadata{URL: "http://example.com"}, false, "non-https url should be invalid"},
+		{Metadata{URL: "https://user@example.com"}, false, "url with user information should be invalid"},
+		{Metadata{URL: "https://" + strings.Repeat("a", MaxMetadataURLLength) + ".com"}, false, "url over the length limit should be invalid"},
+		{Metadata{Email: "not an email"}, false, "malformed email should be invalid"},
+		{Metadata{Email: "Entity <entity@example.com>"}, false, "email with display name should be invalid"},
+		{Metadata{LogoHash: &emptyHash}, false, "empty logo hash should be invalid"},
+	} {
+		err := tc.md.ValidateBasic()
+		if tc.valid {
+			require.NoError(err, tc.msg)
+		} else {
+			require.Error(err, tc.msg)
+		}
+	}
+
+	// Metadata requires a recent descriptor version.
+	ent := Entity{
+		DescriptorVersion: 1,
+		Metadata:          &Metadata{Name: "Entity"},
+	}
+	require.Error(ent.ValidateBasic(false), "metadata in an old descriptor version should be invalid")
+	ent.DescriptorVersion = LatestEntityDescriptorVersion
+	require.NoError(ent.ValidateBasic(true), "metadata in the latest descriptor version should be valid")
+	ent.Metadata.URL = "ftp://example.com"
+	require.Error(ent.ValidateBasic(true), "invalid metadata should be rejected")
+}
@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/oasislabs/oasis-core/go/common/accessctl"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 )
 
 // PeerCertAuthenticator is a server side gRPC authentication function
@@ -57,6 +58,16 @@ func (auth *PeerCertAuthenticator) AllowPeerCertificate(cert *x509.Certificate)
 	auth.whitelist[subject] = true
 }
 
+// AllowPeerPublicKey allows access to peers presenting a certificate with
+// the given public key.
+func (auth *PeerCertAuthenticator) AllowPeerPublicKey(pubKey signature.PublicKey) {
+	subject := accessctl.SubjectFromPublicKey(pubKey)
+
+	auth.Lock()
+	defer auth.Unlock()
+	auth.whitelist[subject] = true
+}
+
 // NewPeerCertAuthenticator creates a new (empty) PeerCertAuthenticator.
 func NewPeerCertAuthenticator() *PeerCertAuthenticator {
 	return &PeerCertAuthenticator{
@@ -0,0 +1,233 @@
+// Package gateway implements a JSON/HTTP gateway for read-only gRPC query
+// methods.
+//
+// Requests and responses are mapped through the same Go types that are used
+// by the gRPC services so the exact CBOR semantics are preserved. Only methods
+// that have been explicitly marked via MethodDesc.WithJSONGateway are exposed.
+//
+// Each exposed method is available at its full gRPC method name, e.g.:
+//
+//	curl -X POST -d '{"owner":"...","height":0}' \
+//	  http://localhost:8080/oasis-core.Staking/AccountInfo
+//
+// In addition the gateway serves a minimal OpenAPI document describing all
+// exposed methods at /openapi.json.
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasislabs/oasis-core/go/common/errors"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/version"
+)
+
+// OpenAPIPath is the path at which the OpenAPI document is served.
+const OpenAPIPath = "/openapi.json"
+
+// maxRequestSize is the maximum size of a request body.
+const maxRequestSize = 1024 * 1024 // 1 MiB
+
+// Invoker is the interface used by the gateway to invoke gRPC methods.
+//
+// It is implemented by *grpc.ClientConn.
+type Invoker interface {
+	Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error
+}
+
+// Error is the JSON-encoded error returned by the gateway.
+type Error struct {
+	// Module is the module of the error (if known).
+	Module string `json:"module,omitempty"`
+	// Code is the module-specific error code (if known).
+	Code uint32 `json:"code,omitempty"`
+	// Message is the error message.
+	Message string `json:"message"`
+}
+
+// Gateway is a JSON/HTTP gateway for read-only gRPC query methods.
+type Gateway struct {
+	invoker Invoker
+	methods map[string]*cmnGrpc.MethodDesc
+
+	logger *logging.Logger
+}
+
+// ServeHTTP implements http.Handler.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == OpenAPIPath {
+		if r.Method != http.MethodGet {
+			g.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+			return
+		}
+		g.writeJSON(w, http.StatusOK, g.openAPI())
+		return
+	}
+
+	md, ok := g.methods[r.URL.Path]
+	if !ok {
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("unknown method: %s", r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodPost {
+		g.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+
+	// Decode the request (if any) into the method's request type.
+	req := md.NewRequest()
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read request: %w", err))
+		return
+	}
+	if req != nil && len(strings.TrimSpace(string(body))) > 0 {
+		if err = json.Unmarshal(body, req); err != nil {
+			g.writeError(w, http.StatusBadRequest, fmt.Errorf("malformed request: %w", err))
+			return
+		}
+	}
+
+	rsp := md.NewResponse()
+	if err = g.invoker.Invoke(r.Context(), md.FullName(), req, rsp); err != nil {
+		g.logger.Debug("failed to invoke method",
+			"err", err,
+			"method", md.FullName(),
+		)
+		g.writeError(w, httpStatusFromError(err), err)
+		return
+	}
+
+	g.writeJSON(w, http.StatusOK, rsp)
+}
+
+func (g *Gateway) writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		g.logger.Error("failed to marshal response",
+			"err", err,
+		)
+		code = http.StatusInternalServerError
+		data, _ = json.Marshal(&Error{Message: "failed to marshal response"})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
+
+func (g *Gateway) writeError(w http.ResponseWriter, code int, err error) {
+	module, errCode := errors.Code(err)
+	if module == errors.UnknownModule {
+		module, errCode = "", 0
+	}
+	if s, ok := status.FromError(err); ok {
+		err = fmt.Errorf("%s", s.Message())
+	}
+	g.writeJSON(w, code, &Error{
+		Module:  module,
+		Code:    errCode,
+		Message: err.Error(),
+	})
+}
+
+func (g *Gateway) openAPI() interface{} {
+	type operation struct {
+		OperationID string                 `json:"operationId"`
+		Tags        []string               `json:"tags"`
+		RequestBody map[string]interface{} `json:"requestBody,omitempty"`
+		Responses   map[string]interface{} `json:"responses"`
+	}
+	jsonContent := map[string]interface{}{
+		"application/json": map[string]interface{}{
+			"schema": map[string]interface{}{},
+		},
+	}
+
+	paths := make(map[string]interface{})
+	for path, md := range g.methods {
+		op := operation{
+			OperationID: strings.TrimPrefix(string(md.ServiceName()), cmnGrpc.ServicePrefix) + md.ShortName(),
+			Tags:        []string{string(md.ServiceName())},
+			Responses: map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Successful response.",
+					"content":     jsonContent,
+				},
+				"default": map[string]interface{}{
+					"description": "Error response.",
+					"content":     jsonContent,
+				},
+			},
+		}
+		if md.NewRequest() != nil {
+			op.RequestBody = map[string]interface{}{
+				"content": jsonContent,
+			}
+		}
+		paths[path] = map[string]interface{}{
+			"post": op,
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "oasis-node JSON gateway",
+			"version": version.SoftwareVersion,
+		},
+		"paths": paths,
+	}
+}
+
+func httpStatusFromError(err error) int {
+	if module, _ := errors.Code(err); module != errors.UnknownModule {
+		// Errors of known modules are caused by the request.
+		return http.StatusBadRequest
+	}
+
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return http.StatusForbidden
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// New creates a new JSON gateway which invokes methods using the given
+// invoker (usually a connection to the node's internal gRPC server).
+func New(invoker Invoker) *Gateway {
+	g := &Gateway{
+		invoker: invoker,
+		methods: make(map[string]*cmnGrpc.MethodDesc),
+		logger:  logging.GetLogger("common/grpc/gateway"),
+	}
+	for _, md := range cmnGrpc.RegisteredMethods() {
+		if !md.IsJSONGatewayExposed() {
+			continue
+		}
+		g.methods[md.FullName()] = md
+	}
+	return g
+}
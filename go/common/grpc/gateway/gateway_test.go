@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+)
+
+func TestGateway(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := ioutil.TempDir("", "oasis-gateway-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	// Create a new gRPC server with the reflection service.
+	path := filepath.Join(dataDir, "internal.sock")
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "test",
+		Path: path,
+	})
+	require.NoError(err, "NewServer")
+	cmnGrpc.RegisterReflectionService(grpcServer.Server())
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	defer grpcServer.Stop()
+
+	conn, err := cmnGrpc.Dial("unix:"+path, grpc.WithInsecure())
+	require.NoError(err, "Dial")
+	defer conn.Close()
+
+	srv := httptest.NewServer(New(conn))
+	defer srv.Close()
+
+	// Exposed method.
+	rsp, err := http.Post(srv.URL+"/oasis-core.Reflection/ListServices", "application/json", nil)
+	require.NoError(err, "ListServices")
+	defer rsp.Body.Close()
+	require.Equal(http.StatusOK, rsp.StatusCode, "ListServices should succeed")
+
+	var services []cmnGrpc.ServiceInfo
+	err = json.NewDecoder(rsp.Body).Decode(&services)
+	require.NoError(err, "Decode")
+	require.Len(services, 1, "only the reflection service should be registered")
+	require.Equal("oasis-core.Reflection", services[0].Name)
+	require.Len(services[0].Methods, 1)
+	require.Equal("ListServices", services[0].Methods[0].Name)
+	require.True(services[0].Methods[0].IsJSONGatewayExposed, "ListServices should be exposed")
+
+	// Unknown method.
+	rsp2, err := http.Post(srv.URL+"/oasis-core.Reflection/Unknown", "application/json", nil)
+	require.NoError(err, "Unknown")
+	defer rsp2.Body.Close()
+	require.Equal(http.StatusNotFound, rsp2.StatusCode, "unknown methods should not be found")
+
+	// Wrong HTTP method.
+	rsp3, err := http.Get(srv.URL + "/oasis-core.Reflection/ListServices")
+	require.NoError(err, "GET ListServices")
+	defer rsp3.Body.Close()
+	require.Equal(http.StatusMethodNotAllowed, rsp3.StatusCode, "only POST should be allowed")
+
+	// OpenAPI document.
+	rsp4, err := http.Get(srv.URL + OpenAPIPath)
+	require.NoError(err, "OpenAPI")
+	defer rsp4.Body.Close()
+	require.Equal(http.StatusOK, rsp4.StatusCode, "OpenAPI document should be served")
+	body, err := ioutil.ReadAll(rsp4.Body)
+	require.NoError(err, "ReadAll")
+	require.True(strings.Contains(string(body), "/oasis-core.Reflection/ListServices"), "OpenAPI document should include exposed methods")
+}
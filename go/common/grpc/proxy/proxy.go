@@ -20,29 +20,28 @@ import (
 
 // Dialer should return a gRPC ClientConn that will be used
 // to forward calls to.
+//
+// The dialer is called for each proxied stream, so it should return
+// cached connections. This allows the dialer to select a different
+// upstream server (e.g., on failure).
 type Dialer func(ctx context.Context) (*grpc.ClientConn, error)
 
 // Handler returns a gRPC StreamHandler than can be used
 // to proxy requests to the client returned by the proxy dialer.
 func Handler(dialer Dialer) grpc.StreamHandler {
 	proxy := &proxy{
-		logger:       logging.GetLogger("grpc/proxy"),
-		dialer:       dialer,
-		upstreamConn: nil, // Will be dialed on-demand.
+		logger: logging.GetLogger("grpc/proxy"),
+		dialer: dialer,
 	}
 
 	return grpc.StreamHandler(proxy.handler)
 }
 
 type proxy struct {
-	// This is the dialer callback we use to make new connections to the
-	// upstream server if the connection drops, etc.
+	// This is the dialer callback we use to obtain the connection to the
+	// upstream server.
 	dialer Dialer
 
-	// This is a cached client connection to the upstream server, so we
-	// don't have to re-dial it on every call.
-	upstreamConn *grpc.ClientConn
-
 	logger *logging.Logger
 
 	// XXX: Currently for each incoming stream two goroutines are spawned,
@@ -78,25 +77,20 @@ func (p *proxy) handler(srv interface{}, stream grpc.ServerStream) error {
 	// Pass subject header upstream.
 	upstreamCtx = metadata.AppendToOutgoingContext(upstreamCtx, policy.ForwardedSubjectMD, sub)
 
-	// Check if upstream connection was disconnected.
-	if p.upstreamConn != nil && p.upstreamConn.GetState() == connectivity.Shutdown {
-		// We need to redial if the connection was shut down.
-		p.upstreamConn = nil
+	// Obtain the upstream connection.
+	upstreamConn, err := p.dialer(stream.Context())
+	if err != nil {
+		return err
 	}
-
-	// Dial upstream if necessary.
-	if p.upstreamConn == nil {
-		var grr error
-		p.upstreamConn, grr = p.dialer(stream.Context())
-		if grr != nil {
-			return grr
-		}
+	if upstreamConn.GetState() == connectivity.Shutdown {
+		p.logger.Error("dialer returned a connection that was shut down")
+		return status.Errorf(codes.Unavailable, "upstream not available")
 	}
 
 	upstreamStream, err := grpc.NewClientStream(
 		upstreamCtx,
 		desc,
-		p.upstreamConn,
+		upstreamConn,
 		method,
 	)
 
@@ -81,11 +81,16 @@ func TestGRPCProxy(t *testing.T) {
 	})
 
 	// Create upstream dialer.
+	var dialedConn *grpc.ClientConn
 	upstreamDialer := func(ctx context.Context) (*grpc.ClientConn, error) {
+		if dialedConn != nil {
+			return dialedConn, nil
+		}
+
 		// Connect to gRPC server.
 		address := fmt.Sprintf("%s:%d", host, port)
-		conn := connectToGrpcServer(ctx, t, address, clientTLSCreds)
-		return conn, nil
+		dialedConn = connectToGrpcServer(ctx, t, address, clientTLSCreds)
+		return dialedConn, nil
 	}
 
 	// Create a proxy gRPC server.
@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/grpc"
+)
+
+var (
+	// reflectionServiceName is the gRPC service name of the reflection service.
+	reflectionServiceName = NewServiceName("Reflection")
+
+	// methodListServices is the ListServices method.
+	methodListServices = reflectionServiceName.NewMethod("ListServices", nil).WithJSONGateway([]ServiceInfo{})
+
+	// reflectionServiceDesc is the gRPC service descriptor of the reflection service.
+	reflectionServiceDesc = grpc.ServiceDesc{
+		ServiceName: string(reflectionServiceName),
+		HandlerType: (*Reflection)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: methodListServices.ShortName(),
+				Handler:    handlerListServices,
+			},
+		},
+		Streams: []grpc.StreamDesc{},
+	}
+)
+
+// ServiceInfo describes a service registered with a gRPC server.
+type ServiceInfo struct {
+	// Name is the service name.
+	Name string `json:"name"`
+	// Methods are the methods exposed by the service.
+	Methods []MethodInfo `json:"methods"`
+}
+
+// MethodInfo describes a gRPC method.
+type MethodInfo struct {
+	// Name is the short method name.
+	Name string `json:"name"`
+	// FullName is the full method name.
+	FullName string `json:"full_name"`
+	// IsClientStream is true iff the method is a client streaming method.
+	IsClientStream bool `json:"is_client_stream,omitempty"`
+	// IsServerStream is true iff the method is a server streaming method.
+	IsServerStream bool `json:"is_server_stream,omitempty"`
+	// IsJSONGatewayExposed is true iff the method can be exposed via the JSON gateway.
+	IsJSONGatewayExposed bool `json:"is_json_gateway_exposed,omitempty"`
+}
+
+// Reflection is the gRPC reflection interface.
+//
+// Since all oasis-core services use the CBOR codec and have no protobuf
+// descriptors, this is used instead of the standard gRPC server reflection
+// service which requires them.
+type Reflection interface {
+	// ListServices lists all services registered with the gRPC server.
+	ListServices(ctx context.Context) ([]ServiceInfo, error)
+}
+
+type serverReflection struct {
+	server *grpc.Server
+}
+
+func (r *serverReflection) ListServices(ctx context.Context) ([]ServiceInfo, error) {
+	var services []ServiceInfo
+	for name, info := range r.server.GetServiceInfo() {
+		svc := ServiceInfo{
+			Name: name,
+		}
+		for _, m := range info.Methods {
+			mi := MethodInfo{
+				Name:           m.Name,
+				FullName:       "/" + name + "/" + m.Name,
+				IsClientStream: m.IsClientStream,
+				IsServerStream: m.IsServerStream,
+			}
+			if md, err := GetRegisteredMethod(mi.FullName); err == nil {
+				mi.IsJSONGatewayExposed = md.IsJSONGatewayExposed()
+			}
+			svc.Methods = append(svc.Methods, mi)
+		}
+		sort.Slice(svc.Methods, func(i, j int) bool {
+			return svc.Methods[i].Name < svc.Methods[j].Name
+		})
+		services = append(services, svc)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+func handlerListServices( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(Reflection).ListServices(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodListServices.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Reflection).ListServices(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+// RegisterReflectionService registers a new reflection service with the given gRPC server. The
+// reflection service reports on all services registered with the same server.
+func RegisterReflectionService(server *grpc.Server) {
+	server.RegisterService(&reflectionServiceDesc, &serverReflection{server: server})
+}
+
+type reflectionClient struct {
+	conn *grpc.ClientConn
+}
+
+func (c *reflectionClient) ListServices(ctx context.Context) ([]ServiceInfo, error) {
+	var rsp []ServiceInfo
+	if err := c.conn.Invoke(ctx, methodListServices.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// NewReflectionClient creates a new gRPC reflection client.
+func NewReflectionClient(c *grpc.ClientConn) Reflection {
+	return &reflectionClient{c}
+}
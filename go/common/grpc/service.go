@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 
@@ -53,6 +54,20 @@ func GetRegisteredMethod(name string) (*MethodDesc, error) {
 	return m, nil
 }
 
+// RegisteredMethods returns descriptions of all registered methods, sorted by
+// their full name.
+func RegisteredMethods() []*MethodDesc {
+	var methods []*MethodDesc
+	registeredMethods.Range(func(key, value interface{}) bool {
+		methods = append(methods, value.(*MethodDesc))
+		return true
+	})
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].full < methods[j].full
+	})
+	return methods
+}
+
 // NewMethod creates a new method name for the given service.
 func (sn ServiceName) NewMethod(name string, requestType interface{}) *MethodDesc {
 	if strings.Contains(name, "/") {
@@ -60,6 +75,7 @@ func (sn ServiceName) NewMethod(name string, requestType interface{}) *MethodDes
 	}
 
 	md := &MethodDesc{
+		service:     sn,
 		short:       name,
 		full:        fmt.Sprintf("/%s/%s", sn, name),
 		requestType: requestType,
@@ -86,11 +102,21 @@ func (m *MethodDesc) WithAccessControl(f AccessControlFunc) *MethodDesc {
 	return m
 }
 
+// WithJSONGateway marks the endpoint as a read-only query that can be exposed
+// via the JSON gateway. The response type is used to decode the CBOR-encoded
+// responses before they are re-encoded as JSON.
+func (m *MethodDesc) WithJSONGateway(responseType interface{}) *MethodDesc {
+	m.responseType = responseType
+	return m
+}
+
 // MethodDesc is a gRPC method descriptor.
 type MethodDesc struct {
-	short       string
-	full        string
-	requestType interface{}
+	service      ServiceName
+	short        string
+	full         string
+	requestType  interface{}
+	responseType interface{}
 
 	accessControl      AccessControlFunc
 	namespaceExtractor NamespaceExtractorFunc
@@ -106,6 +132,43 @@ func (m *MethodDesc) FullName() string {
 	return m.full
 }
 
+// ServiceName returns the name of the service the method belongs to.
+func (m *MethodDesc) ServiceName() ServiceName {
+	return m.service
+}
+
+// IsJSONGatewayExposed returns true iff the method can be exposed via the JSON
+// gateway.
+func (m *MethodDesc) IsJSONGatewayExposed() bool {
+	return m.responseType != nil
+}
+
+// NewRequest returns a pointer to a new zero value of the method's request
+// type. In case the method takes no request, nil is returned.
+func (m *MethodDesc) NewRequest() interface{} {
+	if m.requestType == nil {
+		return nil
+	}
+	return newValueOf(m.requestType)
+}
+
+// NewResponse returns a pointer to a new zero value of the method's response
+// type. The method must be exposed via the JSON gateway.
+func (m *MethodDesc) NewResponse() interface{} {
+	if m.responseType == nil {
+		panic(fmt.Errorf("service: method has no response type: %s", m.full))
+	}
+	return newValueOf(m.responseType)
+}
+
+func newValueOf(v interface{}) interface{} {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.New(t).Interface()
+}
+
 // IsAccessControlled retruns if method is access controlled.
 func (m *MethodDesc) IsAccessControlled(ctx context.Context, req interface{}) (bool, error) {
 	if m.accessControl == nil {
@@ -0,0 +1,224 @@
+// Package jsonschema implements generation of JSON schemas for the JSON
+// encoding of Go types.
+//
+// The generated schemas describe the field names, types and valid ranges of
+// values so that external tooling can be kept in sync with the code.
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the JSON schema draft that generated schemas conform to.
+const Version = "http://json-schema.org/draft-07/schema#"
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	customizerType    = reflect.TypeOf((*Customizer)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// Schema is a JSON schema.
+type Schema struct { // nolint: maligned
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type            string        `json:"type,omitempty"`
+	Format          string        `json:"format,omitempty"`
+	ContentEncoding string        `json:"contentEncoding,omitempty"`
+	Enum            []interface{} `json:"enum,omitempty"`
+	Minimum         json.Number   `json:"minimum,omitempty"`
+	Maximum         json.Number   `json:"maximum,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Customizer is the interface implemented by types that refine the schema
+// generated for them (e.g., to restrict the set of valid values).
+type Customizer interface {
+	// CustomizeJSONSchema refines the schema generated for the type.
+	CustomizeJSONSchema(s *Schema)
+}
+
+type reflector struct {
+	definitions map[string]*Schema
+	names       map[reflect.Type]string
+}
+
+// Reflect generates a JSON schema for the JSON encoding of the given value's
+// type. Named struct types are placed into the schema's definitions and are
+// referenced from the root schema.
+func Reflect(v interface{}) *Schema {
+	r := &reflector{
+		definitions: make(map[string]*Schema),
+		names:       make(map[reflect.Type]string),
+	}
+
+	s := r.reflect(reflect.TypeOf(v))
+	s.Schema = Version
+	s.Definitions = r.definitions
+	return s
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+func (r *reflector) reflect(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Named struct types go into definitions so that recursive types and
+	// types used in multiple places are only described once.
+	if t.Kind() == reflect.Struct && t.Name() != "" && !implements(t, textMarshalerType) {
+		name, ok := r.names[t]
+		if !ok {
+			name = r.definitionName(t)
+			r.names[t] = name
+			// Reserve the definition before recursing into the type.
+			r.definitions[name] = nil
+			r.definitions[name] = r.reflectType(t)
+		}
+		return &Schema{Ref: "#/definitions/" + name}
+	}
+
+	return r.reflectType(t)
+}
+
+func (r *reflector) definitionName(t reflect.Type) string {
+	// Qualify the name with the last two package path elements as package
+	// names alone (e.g., "api") are ambiguous.
+	pkg := strings.ReplaceAll(path.Join(path.Base(path.Dir(t.PkgPath())), path.Base(t.PkgPath())), "/", ".")
+	base := pkg + "." + t.Name()
+
+	name := base
+	for i := 2; ; i++ {
+		if _, exists := r.definitions[name]; !exists {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+func (r *reflector) reflectType(t reflect.Type) *Schema {
+	var s *Schema
+	switch {
+	case t == timeType:
+		s = &Schema{Type: "string", Format: "date-time"}
+	case implements(t, textMarshalerType):
+		s = &Schema{Type: "string"}
+	case implements(t, jsonMarshalerType):
+		// Custom JSON encoding, nothing can be said about it in general.
+		s = &Schema{}
+	default:
+		s = r.reflectKind(t)
+	}
+
+	if implements(t, customizerType) {
+		reflect.New(t).Interface().(Customizer).CustomizeJSONSchema(s)
+	}
+	return s
+}
+
+func (r *reflector) reflectKind(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := t.Bits()
+		return &Schema{
+			Type:    "integer",
+			Minimum: json.Number(strconv.FormatInt(math.MinInt64>>(64-bits), 10)),
+			Maximum: json.Number(strconv.FormatInt(math.MaxInt64>>(64-bits), 10)),
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		bits := t.Bits()
+		return &Schema{
+			Type:    "integer",
+			Minimum: json.Number("0"),
+			Maximum: json.Number(strconv.FormatUint(math.MaxUint64>>(64-bits), 10)),
+		}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !implements(t.Elem(), textMarshalerType) {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: r.reflect(t.Elem())}
+	case reflect.Array:
+		n := t.Len()
+		return &Schema{Type: "array", Items: r.reflect(t.Elem()), MinItems: &n, MaxItems: &n}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflect(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		r.reflectFields(s, t)
+		return s
+	case reflect.Interface:
+		return &Schema{}
+	default:
+		panic(fmt.Sprintf("jsonschema: unsupported type: %s", t))
+	}
+}
+
+func (r *reflector) reflectFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		// Embedded structs without an explicit name have their fields
+		// promoted, like in encoding/json.
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.reflectFields(s, ft)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = r.reflect(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+type testKind uint8
+
+func (k testKind) CustomizeJSONSchema(s *Schema) {
+	s.Enum = []interface{}{testKind(1), testKind(2)}
+}
+
+type testEmbedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type testNode struct {
+	Value int32 `json:"value"`
+
+	Children []*testNode `json:"children,omitempty"`
+}
+
+type testStruct struct {
+	testEmbedded
+
+	ID       signature.PublicKey   `json:"id"`
+	Kind     testKind              `json:"kind"`
+	Blob     []byte                `json:"blob,omitempty"`
+	Hashes   [2]string             `json:"hashes"`
+	Labels   map[string]uint16     `json:"labels,omitempty"`
+	Tree     testNode              `json:"tree"`
+	Skipped  bool                  `json:"-"`
+	Untagged bool                  // nolint: structtag
+	Any      interface{}           `json:"any,omitempty"`
+	Keys     []signature.PublicKey `json:"keys"`
+
+	unexported bool // nolint: unused, structcheck
+}
+
+func TestReflect(t *testing.T) {
+	require := require.New(t)
+
+	s := Reflect(&testStruct{})
+	require.Equal(Version, s.Schema)
+	require.Equal("#/definitions/common.jsonschema.testStruct", s.Ref)
+
+	def := s.Definitions["common.jsonschema.testStruct"]
+	require.NotNil(def, "root type should be in definitions")
+	require.Equal("object", def.Type)
+	require.Equal([]string{"embedded", "id", "kind", "hashes", "tree", "Untagged", "keys"}, def.Required)
+	require.NotContains(def.Properties, "Skipped")
+	require.NotContains(def.Properties, "unexported")
+
+	require.Equal("string", def.Properties["embedded"].Type, "embedded struct fields should be promoted")
+	require.Equal("string", def.Properties["id"].Type, "text marshalers should be strings")
+	require.Equal("string", def.Properties["blob"].Type)
+	require.Equal("base64", def.Properties["blob"].ContentEncoding)
+	require.Equal("array", def.Properties["hashes"].Type)
+	require.EqualValues(2, *def.Properties["hashes"].MinItems)
+	require.EqualValues(2, *def.Properties["hashes"].MaxItems)
+	require.Equal("string", def.Properties["keys"].Items.Type)
+	require.Equal("object", def.Properties["labels"].Type)
+	require.Equal(json.Number("65535"), def.Properties["labels"].AdditionalProperties.Maximum)
+	require.Equal(&Schema{}, def.Properties["any"])
+
+	kind := def.Properties["kind"]
+	require.Equal("integer", kind.Type)
+	require.Equal([]interface{}{testKind(1), testKind(2)}, kind.Enum, "customizer should be applied")
+
+	// Recursive types should be referenced.
+	require.Equal("#/definitions/common.jsonschema.testNode", def.Properties["tree"].Ref)
+	node := s.Definitions["common.jsonschema.testNode"]
+	require.NotNil(node)
+	require.Equal(json.Number("-2147483648"), node.Properties["value"].Minimum)
+	require.Equal(json.Number("2147483647"), node.Properties["value"].Maximum)
+	require.Equal("#/definitions/common.jsonschema.testNode", node.Properties["children"].Items.Ref)
+
+	// Output should be deterministic.
+	b1, err := json.Marshal(s)
+	require.NoError(err, "Marshal")
+	b2, err := json.Marshal(Reflect(&testStruct{}))
+	require.NoError(err, "Marshal")
+	require.Equal(b1, b2, "schema should be deterministic")
+}
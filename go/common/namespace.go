@@ -107,6 +107,12 @@ func (n Namespace) IsKeyManager() bool {
 	return n.flags()&NamespaceKeyManager != 0
 }
 
+// HasIDPrefix returns true iff the identifier component of the namespace
+// (excluding the flags) starts with the given prefix.
+func (n Namespace) HasIDPrefix(prefix []byte) bool {
+	return bytes.HasPrefix(n[NamespaceSize-NamespaceIDSize:], prefix)
+}
+
 func (n Namespace) isValid() bool {
 	return n.flags()&flagsReserved == 0
 }
@@ -4,9 +4,11 @@
 package node
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/jsonschema"
 	"github.com/oasislabs/oasis-core/go/common/prettyprint"
 	"github.com/oasislabs/oasis-core/go/common/sgx/ias"
 	"github.com/oasislabs/oasis-core/go/common/version"
@@ -31,6 +34,9 @@ var (
 	teeHashContext = []byte("oasis-core/node: TEE RAK binding")
 
 	_ prettyprint.PrettyPrinter = (*MultiSignedNode)(nil)
+	_ jsonschema.Customizer     = (*Node)(nil)
+	_ jsonschema.Customizer     = RolesMask(0)
+	_ jsonschema.Customizer     = TEEHardware(0)
 )
 
 const (
@@ -77,6 +83,13 @@ type Node struct { // nolint: maligned
 	Roles RolesMask `json:"roles"`
 }
 
+// CustomizeJSONSchema refines the JSON schema generated for the type.
+func (n Node) CustomizeJSONSchema(s *jsonschema.Schema) {
+	s.Title = "Node descriptor"
+	s.Properties["v"].Minimum = json.Number(strconv.Itoa(minNodeDescriptorVersion))
+	s.Properties["v"].Maximum = json.Number(strconv.Itoa(maxNodeDescriptorVersion))
+}
+
 // RolesMask is Oasis node roles bitmask.
 type RolesMask uint32
 
@@ -97,6 +110,16 @@ const (
 	RoleReserved RolesMask = ((1 << 32) - 1) & ^((RoleConsensusRPC << 1) - 1)
 )
 
+// CustomizeJSONSchema refines the JSON schema generated for the type.
+func (m RolesMask) CustomizeJSONSchema(s *jsonschema.Schema) {
+	var roles []string
+	for r := RoleComputeWorker; r&RoleReserved == 0; r <<= 1 {
+		roles = append(roles, fmt.Sprintf("%s: %d", r, r))
+	}
+	s.Description = "Bitmask of node roles (" + strings.Join(roles, ", ") + ")."
+	s.Maximum = json.Number(strconv.FormatUint(uint64(^RoleReserved), 10))
+}
+
 // IsSingleRole returns true if RolesMask encodes a single valid role.
 func (m RolesMask) IsSingleRole() bool {
 	// Ensures exactly one bit is set, and the set bit is a valid role.
@@ -303,6 +326,14 @@ func (h TEEHardware) String() string {
 	}
 }
 
+// CustomizeJSONSchema refines the JSON schema generated for the type.
+func (h TEEHardware) CustomizeJSONSchema(s *jsonschema.Schema) {
+	s.Enum = nil
+	for v := TEEHardwareInvalid; v < TEEHardwareReserved; v++ {
+		s.Enum = append(s.Enum, v)
+	}
+}
+
 // FromString deserializes a string into a TEEHardware.
 func (h *TEEHardware) FromString(str string) error {
 	switch strings.ToLower(str) {
@@ -348,7 +379,7 @@ func (c *CapabilityTEE) Verify(ts time.Time) error {
 			return err
 		}
 
-		avr, err := avrBundle.Open(ias.IntelTrustRoots, ts)
+		avr, err := ias.DefaultVerifier.Open(&avrBundle, ts)
 		if err != nil {
 			return err
 		}
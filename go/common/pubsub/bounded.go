@@ -0,0 +1,202 @@
+package pubsub
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/eapache/channels"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrSubscriptionOverflow is the error returned by Subscription.Err when a
+// subscription was closed as its buffer overflowed.
+var ErrSubscriptionOverflow = errors.New("pubsub: subscription buffer overflow")
+
+var (
+	droppedValues = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_pubsub_dropped_values",
+			Help: "Number of values dropped due to subscription buffer overflow.",
+		},
+		[]string{"subscription", "policy"},
+	)
+	closedSubscriptions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_pubsub_overflow_closed_subscriptions",
+			Help: "Number of subscriptions closed due to subscription buffer overflow.",
+		},
+		[]string{"subscription"},
+	)
+
+	pubsubCollectors = []prometheus.Collector{
+		droppedValues,
+		closedSubscriptions,
+	}
+
+	metricsOnce sync.Once
+)
+
+// OverflowPolicy is the policy applied when a value is broadcasted to a
+// subscription with a full buffer.
+type OverflowPolicy uint8
+
+const (
+	// OverflowDropOldest discards the oldest buffered value.
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowDropNewest discards the broadcasted value.
+	OverflowDropNewest
+	// OverflowCloseSubscription closes the subscription, discarding all
+	// further values. Subscription.Err returns ErrSubscriptionOverflow.
+	OverflowCloseSubscription
+)
+
+// String returns a string representation of the overflow policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDropOldest:
+		return "drop_oldest"
+	case OverflowDropNewest:
+		return "drop_newest"
+	case OverflowCloseSubscription:
+		return "close_subscription"
+	default:
+		return "[unknown overflow policy]"
+	}
+}
+
+var _ channels.Channel = (*boundedChannel)(nil)
+
+// boundedChannel is a channel with a bounded buffer that never blocks the
+// writer, applying the overflow policy when the buffer is full.
+//
+// It behaves like channels.RingChannel when using OverflowDropOldest.
+type boundedChannel struct {
+	name   string
+	policy OverflowPolicy
+
+	input, output chan interface{}
+	length        chan int
+	buffer        []interface{}
+	size          int
+
+	errLock sync.Mutex
+	err     error
+}
+
+func (ch *boundedChannel) In() chan<- interface{} {
+	return ch.input
+}
+
+func (ch *boundedChannel) Out() <-chan interface{} {
+	return ch.output
+}
+
+func (ch *boundedChannel) Len() int {
+	n, ok := <-ch.length
+	if !ok {
+		return 0
+	}
+	return n
+}
+
+func (ch *boundedChannel) Cap() channels.BufferCap {
+	return channels.BufferCap(ch.size)
+}
+
+func (ch *boundedChannel) Close() {
+	close(ch.input)
+}
+
+// Err returns the error that caused the channel output to be closed, if any.
+func (ch *boundedChannel) Err() error {
+	ch.errLock.Lock()
+	defer ch.errLock.Unlock()
+
+	return ch.err
+}
+
+// overflow applies the overflow policy when the given value is written to
+// a full buffer and returns false iff the output should be closed.
+func (ch *boundedChannel) overflow(elem interface{}) bool {
+	switch ch.policy {
+	case OverflowDropNewest:
+	case OverflowCloseSubscription:
+		ch.errLock.Lock()
+		ch.err = ErrSubscriptionOverflow
+		ch.errLock.Unlock()
+
+		closedSubscriptions.With(prometheus.Labels{"subscription": ch.name}).Inc()
+		return false
+	default:
+		ch.buffer = append(ch.buffer[1:], elem)
+	}
+
+	droppedValues.With(prometheus.Labels{"subscription": ch.name, "policy": ch.policy.String()}).Inc()
+	return true
+}
+
+func (ch *boundedChannel) worker() {
+	var output chan interface{}
+	var next interface{}
+	input := ch.input
+
+	for input != nil {
+		select {
+		// Prefer to write if possible to reduce the number of dropped values,
+		// just like channels.RingChannel.
+		case output <- next:
+			ch.buffer = ch.buffer[1:]
+		default:
+			select {
+			case elem, open := <-input:
+				switch {
+				case !open:
+					input = nil
+				case len(ch.buffer) < ch.size:
+					ch.buffer = append(ch.buffer, elem)
+				case !ch.overflow(elem):
+					// Discard everything until the channel is closed, so that
+					// the writer is never blocked.
+					close(ch.output)
+					close(ch.length)
+					for range input {
+					}
+					return
+				}
+			case output <- next:
+				ch.buffer = ch.buffer[1:]
+			case ch.length <- len(ch.buffer):
+			}
+		}
+
+		if len(ch.buffer) > 0 {
+			output = ch.output
+			next = ch.buffer[0]
+		} else {
+			output = nil
+			next = nil
+		}
+	}
+
+	close(ch.output)
+	close(ch.length)
+}
+
+func newBoundedChannel(name string, size int64, policy OverflowPolicy) *boundedChannel {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(pubsubCollectors...)
+	})
+
+	ch := &boundedChannel{
+		name:   name,
+		policy: policy,
+		input:  make(chan interface{}),
+		output: make(chan interface{}),
+		length: make(chan int),
+		buffer: make([]interface{}, 0, size),
+		size:   int(size),
+	}
+	go ch.worker()
+
+	return ch
+}
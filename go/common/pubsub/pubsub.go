@@ -61,6 +61,15 @@ func (s *Subscription) Unwrap(ch interface{}) {
 	channels.Unwrap(s.ch, ch)
 }
 
+// Err returns ErrSubscriptionOverflow iff the subscription's output was
+// closed due to its buffer overflowing (see OverflowCloseSubscription).
+func (s *Subscription) Err() error {
+	if ch, ok := s.ch.(*boundedChannel); ok {
+		return ch.Err()
+	}
+	return nil
+}
+
 // Close unsubscribes from the Broker.
 func (s *Subscription) Close() {
 	ctx := &cmdCtx{
@@ -115,16 +124,46 @@ func (b *Broker) SubscribeBuffered(buffer int64) *Subscription {
 // Note: If there is a Broker wide hook set, it will be called
 // after the per-subscription hook is called.
 func (b *Broker) SubscribeEx(buffer int64, onSubscribeHook OnSubscribeHook) *Subscription {
+	return b.SubscribeWithOptions(&SubscriptionOptions{
+		Buffer:          buffer,
+		Policy:          OverflowDropOldest,
+		OnSubscribeHook: onSubscribeHook,
+	})
+}
+
+// SubscriptionOptions are the subscription options.
+type SubscriptionOptions struct {
+	// Name is the name of the subscription used in metrics.
+	Name string
+
+	// Buffer is the capacity of the subscription buffer. In case buffer is
+	// negative (or zero) an unbounded channel is used.
+	Buffer int64
+
+	// Policy is the policy applied when the subscription buffer is full.
+	Policy OverflowPolicy
+
+	// OnSubscribeHook is the per-subscription on-subscribe callback hook.
+	OnSubscribeHook OnSubscribeHook
+}
+
+// SubscribeWithOptions subscribes to the Broker's broadcasts with the given
+// options, and returns a subscription handle that can be used to receive
+// broadcasts.
+//
+// Note: If there is a Broker wide hook set, it will be called
+// after the per-subscription hook is called.
+func (b *Broker) SubscribeWithOptions(opts *SubscriptionOptions) *Subscription {
 	var ch channels.Channel
-	if buffer <= 0 {
+	if opts.Buffer <= 0 {
 		ch = channels.NewInfiniteChannel()
 	} else {
-		ch = channels.NewRingChannel(channels.BufferCap(buffer))
+		ch = newBoundedChannel(opts.Name, opts.Buffer, opts.Policy)
 	}
 	ctx := &cmdCtx{
 		ch:              ch,
 		errCh:           make(chan error),
-		onSubscribeHook: onSubscribeHook,
+		onSubscribeHook: opts.OnSubscribeHook,
 		isSubscribe:     true,
 	}
 
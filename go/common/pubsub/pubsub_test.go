@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/eapache/channels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,6 +21,8 @@ func TestPubSub(t *testing.T) {
 	t.Run("PubLastOnSubscribe", testLastOnSubscribe)
 	t.Run("SubscribeEx", testSubscribeEx)
 	t.Run("NewBrokerEx", testNewBrokerEx)
+	t.Run("OverflowDropNewest", testOverflowDropNewest)
+	t.Run("OverflowCloseSubscription", testOverflowCloseSubscription)
 }
 
 func testBasicInfinity(t *testing.T) {
@@ -155,3 +159,90 @@ func testNewBrokerEx(t *testing.T) {
 		require.Equal(t, sub.ch, callbackCh, "Callback channel != Subscription, inner channel")
 	}
 }
+
+func testOverflowDropNewest(t *testing.T) {
+	broker := NewBroker(false)
+
+	dropped := droppedValues.With(prometheus.Labels{
+		"subscription": "test_drop_newest",
+		"policy":       OverflowDropNewest.String(),
+	})
+	droppedBefore := testutil.ToFloat64(dropped)
+
+	sub := broker.SubscribeWithOptions(&SubscriptionOptions{
+		Name:   "test_drop_newest",
+		Buffer: bufferSize,
+		Policy: OverflowDropNewest,
+	})
+	defer sub.Close()
+
+	const numValues = bufferSize + 10
+	for i := 0; i < numValues; i++ {
+		broker.Broadcast(i)
+	}
+	// Ensure we don't start reading before all messages are processed by the
+	// underlying channel.
+	time.Sleep(100 * time.Millisecond)
+
+	// Only the oldest values should be received, depending on whether the
+	// first value was sent to the output channel before it was buffered.
+	ch := sub.Untyped()
+	var received int
+	for {
+		select {
+		case v := <-ch:
+			require.Equal(t, received, v, "Buffered Broadcast()")
+			received++
+			continue
+		case <-time.After(100 * time.Millisecond):
+		}
+		break
+	}
+	require.True(t, received >= bufferSize && received <= bufferSize+1, "received values should fill the buffer")
+	require.EqualValues(t, numValues-received, testutil.ToFloat64(dropped)-droppedBefore, "dropped values")
+	require.NoError(t, sub.Err(), "Err")
+}
+
+func testOverflowCloseSubscription(t *testing.T) {
+	broker := NewBroker(false)
+
+	closed := closedSubscriptions.With(prometheus.Labels{"subscription": "test_close_subscription"})
+	closedBefore := testutil.ToFloat64(closed)
+
+	sub := broker.SubscribeWithOptions(&SubscriptionOptions{
+		Name:   "test_close_subscription",
+		Buffer: bufferSize,
+		Policy: OverflowCloseSubscription,
+	})
+
+	for i := 0; i < bufferSize+10; i++ {
+		broker.Broadcast(i)
+	}
+	// Ensure we don't start reading before all messages are processed by the
+	// underlying channel.
+	time.Sleep(100 * time.Millisecond)
+
+	// The subscription should be closed once the buffer overflows.
+	ch := sub.Untyped()
+	var received int
+	for {
+		select {
+		case _, ok := <-ch:
+			if ok {
+				received++
+				continue
+			}
+		case <-time.After(recvTimeout):
+			t.Fatalf("Failed to wait for subscription to be closed")
+		}
+		break
+	}
+	require.True(t, received <= bufferSize+1, "received values should not exceed the buffer")
+	require.Equal(t, ErrSubscriptionOverflow, sub.Err(), "Err")
+	require.EqualValues(t, 1, testutil.ToFloat64(closed)-closedBefore, "closed subscriptions")
+
+	// Broadcasting to and closing an overflowed subscription should not block.
+	broker.Broadcast(42)
+	require.NotPanics(t, func() { sub.Close() }, "Close()")
+	require.Len(t, broker.subscribers, 0, "Subscriber map, post Close()")
+}
@@ -0,0 +1,175 @@
+package quantity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrInvalidDecimal is the error returned when parsing a malformed
+	// decimal string.
+	ErrInvalidDecimal = errors.New("invalid decimal quantity")
+
+	// ErrNegative is the error returned when a quantity would be negative.
+	ErrNegative = errors.New("negative quantity")
+
+	// ErrPrecisionLoss is the error returned when a decimal string has more
+	// significant fractional digits than its denomination allows.
+	ErrPrecisionLoss = errors.New("quantity precision loss")
+
+	// ErrOverflow is the error returned when a quantity does not fit into
+	// the requested type.
+	ErrOverflow = errors.New("quantity overflow")
+
+	// ErrDivisionByZero is the error returned on division by zero.
+	ErrDivisionByZero = errors.New("quantity division by zero")
+)
+
+// ParseDecimal parses a decimal string denominated in units with the given
+// number of decimal places into a Quantity of base units.
+//
+// Only plain decimal notation is accepted (e.g., "1", "1.5" or "0.001").
+// Signs, exponents, digit separators and non-decimal bases are rejected,
+// as are fractional digits that cannot be represented in base units.
+func ParseDecimal(s string, decimals uint8) (*Quantity, error) {
+	if strings.HasPrefix(s, "-") {
+		return nil, fmt.Errorf("%w: '%s'", ErrNegative, s)
+	}
+
+	intPart, fracPart := s, ""
+	if idx := strings.IndexByte(s, '.'); idx >= 0 {
+		intPart, fracPart = s[:idx], s[idx+1:]
+		if fracPart == "" {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidDecimal, s)
+		}
+	}
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidDecimal, s)
+	}
+
+	// Trailing zeros do not change the value, anything else beyond the
+	// denomination's precision would be silently truncated.
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > int(decimals) {
+		return nil, fmt.Errorf("%w: '%s' has more than %d decimal places", ErrPrecisionLoss, s, decimals)
+	}
+	fracPart += strings.Repeat("0", int(decimals)-len(fracPart))
+
+	var v big.Int
+	if _, ok := v.SetString(intPart+fracPart, 10); !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidDecimal, s)
+	}
+
+	q := NewQuantity()
+	if err := q.FromBigInt(&v); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// FormatDecimal formats the quantity of base units as a decimal string
+// denominated in units with the given number of decimal places.
+//
+// The output is canonical: it has no leading zeros in the integer part and
+// no trailing zeros in the fractional part, which is omitted if zero. The
+// output can be parsed back using ParseDecimal with the same denomination.
+func (q *Quantity) FormatDecimal(decimals uint8) string {
+	s := q.String()
+	if decimals == 0 || !q.IsValid() {
+		return s
+	}
+
+	if pad := int(decimals) + 1 - len(s); pad > 0 {
+		s = strings.Repeat("0", pad) + s
+	}
+	intPart, fracPart := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if fracPart == "" {
+		return intPart
+	}
+	return intPart + "." + fracPart
+}
+
+// ToUint64 converts the quantity to an uint64, returning an error if it
+// does not fit.
+func (q *Quantity) ToUint64() (uint64, error) {
+	if !q.IsValid() {
+		return 0, ErrInvalidQuantity
+	}
+	if !q.inner.IsUint64() {
+		return 0, ErrOverflow
+	}
+	return q.inner.Uint64(), nil
+}
+
+// CheckedAdd returns a + b without altering either operand.
+func CheckedAdd(a, b *Quantity) (*Quantity, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+
+	r := a.Clone()
+	r.inner.Add(&r.inner, &b.inner)
+	return r, nil
+}
+
+// CheckedSub returns a - b without altering either operand, returning
+// ErrNegative if the result would be negative.
+func CheckedSub(a, b *Quantity) (*Quantity, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	if a.Cmp(b) < 0 {
+		return nil, fmt.Errorf("%w: %s - %s", ErrNegative, a, b)
+	}
+
+	r := a.Clone()
+	r.inner.Sub(&r.inner, &b.inner)
+	return r, nil
+}
+
+// CheckedMul returns a * b without altering either operand.
+func CheckedMul(a, b *Quantity) (*Quantity, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+
+	r := a.Clone()
+	r.inner.Mul(&r.inner, &b.inner)
+	return r, nil
+}
+
+// CheckedQuo returns a / b rounded towards zero without altering either
+// operand, returning ErrDivisionByZero if b is zero.
+func CheckedQuo(a, b *Quantity) (*Quantity, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	if b.IsZero() {
+		return nil, ErrDivisionByZero
+	}
+
+	r := a.Clone()
+	r.inner.Quo(&r.inner, &b.inner)
+	return r, nil
+}
+
+func checkOperands(a, b *Quantity) error {
+	if a == nil || b == nil {
+		return ErrInvalidQuantity
+	}
+	if !a.IsValid() || !b.IsValid() {
+		return ErrNegative
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package quantity
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		s        string
+		decimals uint8
+		expected int
+	}{
+		{"0", 0, 0},
+		{"123", 0, 123},
+		{"010", 0, 10},
+		{"1.0", 0, 1},
+		{"1", 3, 1000},
+		{"1.5", 3, 1500},
+		{"0.001", 3, 1},
+		{"12.3400", 3, 12340},
+	} {
+		q, err := ParseDecimal(tc.s, tc.decimals)
+		require.NoError(err, "ParseDecimal(%s, %d)", tc.s, tc.decimals)
+		require.True(q.eqInt(tc.expected), "ParseDecimal(%s, %d) value: %s", tc.s, tc.decimals, q)
+	}
+
+	for _, tc := range []struct {
+		s        string
+		decimals uint8
+		err      error
+	}{
+		{"", 0, ErrInvalidDecimal},
+		{"-1", 0, ErrNegative},
+		{"+1", 0, ErrInvalidDecimal},
+		{"0x10", 0, ErrInvalidDecimal},
+		{"1_000", 0, ErrInvalidDecimal},
+		{"1,000", 0, ErrInvalidDecimal},
+		{"1e9", 0, ErrInvalidDecimal},
+		{" 1", 0, ErrInvalidDecimal},
+		{"1.", 3, ErrInvalidDecimal},
+		{".5", 3, ErrInvalidDecimal},
+		{"1.2.3", 3, ErrInvalidDecimal},
+		{"1.5", 0, ErrPrecisionLoss},
+		{"0.0001", 3, ErrPrecisionLoss},
+	} {
+		_, err := ParseDecimal(tc.s, tc.decimals)
+		require.True(errors.Is(err, tc.err), "ParseDecimal(%s, %d) error: %v", tc.s, tc.decimals, err)
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		n        int
+		decimals uint8
+		expected string
+	}{
+		{0, 0, "0"},
+		{0, 3, "0"},
+		{1, 3, "0.001"},
+		{1500, 3, "1.5"},
+		{1000, 3, "1"},
+		{123456, 2, "1234.56"},
+		{123456, 9, "0.000123456"},
+	} {
+		q := fromInt(tc.n)
+		s := q.FormatDecimal(tc.decimals)
+		require.Equal(tc.expected, s, "FormatDecimal(%d, %d)", tc.n, tc.decimals)
+
+		// Formatting must round trip.
+		rt, err := ParseDecimal(s, tc.decimals)
+		require.NoError(err, "ParseDecimal(FormatDecimal(%d, %d))", tc.n, tc.decimals)
+		require.Equal(0, q.Cmp(rt), "round trip value")
+	}
+}
+
+func TestToUint64(t *testing.T) {
+	require := require.New(t)
+
+	var q Quantity
+	require.NoError(q.FromUint64(math.MaxUint64), "FromUint64")
+	v, err := q.ToUint64()
+	require.NoError(err, "ToUint64")
+	require.EqualValues(uint64(math.MaxUint64), v, "ToUint64 value")
+
+	require.NoError(q.Add(fromInt(1)), "Add")
+	_, err = q.ToUint64()
+	require.Equal(ErrOverflow, err, "ToUint64 overflow")
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	require := require.New(t)
+
+	a, b := fromInt(7), fromInt(2)
+
+	r, err := CheckedAdd(a, b)
+	require.NoError(err, "CheckedAdd")
+	require.True(r.eqInt(9), "CheckedAdd value")
+
+	r, err = CheckedSub(a, b)
+	require.NoError(err, "CheckedSub")
+	require.True(r.eqInt(5), "CheckedSub value")
+
+	_, err = CheckedSub(b, a)
+	require.True(errors.Is(err, ErrNegative), "CheckedSub underflow")
+
+	r, err = CheckedMul(a, b)
+	require.NoError(err, "CheckedMul")
+	require.True(r.eqInt(14), "CheckedMul value")
+
+	r, err = CheckedQuo(a, b)
+	require.NoError(err, "CheckedQuo")
+	require.True(r.eqInt(3), "CheckedQuo value")
+
+	_, err = CheckedQuo(a, NewQuantity())
+	require.Equal(ErrDivisionByZero, err, "CheckedQuo by zero")
+
+	_, err = CheckedAdd(a, nil)
+	require.Equal(ErrInvalidQuantity, err, "CheckedAdd(nil)")
+
+	// Operands must not be altered.
+	require.True(a.eqInt(7), "a unaltered")
+	require.True(b.eqInt(2), "b unaltered")
+}
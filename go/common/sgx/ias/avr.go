@@ -325,11 +325,15 @@ func (a *AttestationVerificationReport) validate() error { // nolint: gocyclo
 // DecodeAVR decodes and validates an Attestation Verification Report.
 func DecodeAVR(data, encodedSignature, encodedCertChain []byte, trustRoots *x509.CertPool, ts time.Time) (*AttestationVerificationReport, error) {
 	if !unsafeSkipVerify {
-		if err := validateAVRSignature(data, encodedSignature, encodedCertChain, trustRoots, ts); err != nil {
+		if _, err := validateAVRSignature(data, encodedSignature, encodedCertChain, trustRoots, ts); err != nil {
 			return nil, err
 		}
 	}
 
+	return decodeAVRBody(data)
+}
+
+func decodeAVRBody(data []byte) (*AttestationVerificationReport, error) {
 	// Set the ISVEnclaveQuoteStatus to a sentinel value so that it is
 	// possible to detect it being missing from the JSON.
 	a := &AttestationVerificationReport{
@@ -347,10 +351,34 @@ func DecodeAVR(data, encodedSignature, encodedCertChain []byte, trustRoots *x509
 	return a, nil
 }
 
-func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trustRoots *x509.CertPool, ts time.Time) error {
+// signatureValidity is the time window in which a verified AVR signature
+// remains valid, as determined by the validity of its certificate chain.
+type signatureValidity struct {
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+func (v *signatureValidity) contains(ts time.Time) bool {
+	return !ts.Before(v.notBefore) && !ts.After(v.notAfter)
+}
+
+func newSignatureValidity(certs ...*x509.Certificate) *signatureValidity {
+	var v signatureValidity
+	for i, cert := range certs {
+		if i == 0 || cert.NotBefore.After(v.notBefore) {
+			v.notBefore = cert.NotBefore
+		}
+		if i == 0 || cert.NotAfter.Before(v.notAfter) {
+			v.notAfter = cert.NotAfter
+		}
+	}
+	return &v
+}
+
+func decodeCertChain(encodedCertChain []byte) ([]*x509.Certificate, error) {
 	decoded, err := url.QueryUnescape(string(encodedCertChain))
 	if err != nil {
-		return fmt.Errorf("ias/avr: failed to decode certificate chain: %w", err)
+		return nil, fmt.Errorf("ias/avr: failed to decode certificate chain: %w", err)
 	}
 	pemCerts := []byte(decoded)
 
@@ -359,7 +387,7 @@ func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trust
 		var cert *x509.Certificate
 		cert, pemCerts, err = CertFromPEM(pemCerts)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if cert == nil {
 			break
@@ -367,7 +395,27 @@ func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trust
 		certs = append(certs, cert)
 	}
 	if len(certs) != 2 {
-		return fmt.Errorf("ias/avr: unexpected certificate chain length: %d", len(certs))
+		return nil, fmt.Errorf("ias/avr: unexpected certificate chain length: %d", len(certs))
+	}
+	return certs, nil
+}
+
+func checkAVRSignature(signingCert *x509.Certificate, data, encodedSignature []byte) error {
+	signature, err := base64.StdEncoding.DecodeString(string(encodedSignature))
+	if err != nil {
+		return fmt.Errorf("ias/avr: failed to decode signature: %w", err)
+	}
+
+	if err = signingCert.CheckSignature(x509.SHA256WithRSA, data, signature); err != nil {
+		return fmt.Errorf("ias/avr: failed to verify AVR signature: %w", err)
+	}
+	return nil
+}
+
+func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trustRoots *x509.CertPool, ts time.Time) (*signatureValidity, error) {
+	certs, err := decodeCertChain(encodedCertChain)
+	if err != nil {
+		return nil, err
 	}
 
 	signingCert, rootCert := certs[0], certs[1]
@@ -376,22 +424,50 @@ func validateAVRSignature(data, encodedSignature, encodedCertChain []byte, trust
 		CurrentTime: ts,
 	})
 	if err != nil {
-		return fmt.Errorf("ias/avr: failed to verify certificate chain: %w", err)
+		return nil, fmt.Errorf("ias/avr: failed to verify certificate chain: %w", err)
 	}
 	if !certRootsAChain(rootCert, certChains) {
-		return fmt.Errorf("ias/avr: unexpected root in certificate chain")
+		return nil, fmt.Errorf("ias/avr: unexpected root in certificate chain")
 	}
 
-	signature, err := base64.StdEncoding.DecodeString(string(encodedSignature))
+	if err = checkAVRSignature(signingCert, data, encodedSignature); err != nil {
+		return nil, err
+	}
+
+	return newSignatureValidity(signingCert, rootCert), nil
+}
+
+// validatePinnedAVRSignature validates the AVR signature without verifying
+// the certificate chain, instead requiring that the signing certificate is
+// one of the pinned certificates.
+func validatePinnedAVRSignature(data, encodedSignature, encodedCertChain []byte, pinned []*x509.Certificate, ts time.Time) (*signatureValidity, error) {
+	certs, err := decodeCertChain(encodedCertChain)
 	if err != nil {
-		return fmt.Errorf("ias/avr: failed to decode signature: %w", err)
+		return nil, err
 	}
 
-	if err = signingCert.CheckSignature(x509.SHA256WithRSA, data, signature); err != nil {
-		return fmt.Errorf("ias/avr: failed to verify AVR signature: %w", err)
+	signingCert := certs[0]
+	var isPinned bool
+	for _, cert := range pinned {
+		if signingCert.Equal(cert) {
+			isPinned = true
+			break
+		}
+	}
+	if !isPinned {
+		return nil, fmt.Errorf("ias/avr: signing certificate is not pinned")
 	}
 
-	return nil
+	validity := newSignatureValidity(signingCert)
+	if !validity.contains(ts) {
+		return nil, fmt.Errorf("ias/avr: signing certificate not valid at %s", ts)
+	}
+
+	if err = checkAVRSignature(signingCert, data, encodedSignature); err != nil {
+		return nil, err
+	}
+
+	return validity, nil
 }
 
 // SetSkipVerify will disable AVR signature verification for the remainder
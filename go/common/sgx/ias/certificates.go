@@ -58,6 +58,22 @@ func CertFromPEM(raw []byte) (*x509.Certificate, []byte, error) {
 	return cert, rest, nil
 }
 
+// CertsFromPEM parses all PEM-encoded certificates in raw.
+func CertsFromPEM(raw []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		cert, rest, err := CertFromPEM(raw)
+		if err != nil {
+			return nil, err
+		}
+		if cert == nil {
+			return certs, nil
+		}
+		certs = append(certs, cert)
+		raw = rest
+	}
+}
+
 func certRootsAChain(cert *x509.Certificate, chains [][]*x509.Certificate) bool {
 	for _, chain := range chains {
 		if cert.Equal(chain[len(chain)-1]) {
@@ -0,0 +1,188 @@
+package ias
+
+import (
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasislabs/oasis-core/go/common/cache/lru"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+)
+
+const (
+	// DefaultVerifierCacheTTL is the default time for which a successful AVR
+	// signature verification is cached.
+	DefaultVerifierCacheTTL = 1 * time.Hour
+	// DefaultVerifierCacheCapacity is the default maximum number of cached
+	// AVR signature verifications.
+	DefaultVerifierCacheCapacity = 1024
+)
+
+var (
+	avrVerificationLatency = prometheus.NewSummary(
+		prometheus.SummaryOpts{
+			Name: "oasis_ias_avr_verification_latency",
+			Help: "AVR verification latency (seconds).",
+		},
+	)
+	avrVerificationFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_ias_avr_verification_failures",
+			Help: "Number of failed AVR verifications.",
+		},
+	)
+	avrVerificationCacheHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_ias_avr_verification_cache_hits",
+			Help: "Number of AVR verifications served from the cache.",
+		},
+	)
+
+	verifierCollectors = []prometheus.Collector{
+		avrVerificationLatency,
+		avrVerificationFailures,
+		avrVerificationCacheHits,
+	}
+
+	metricsOnce sync.Once
+
+	// DefaultVerifier is the verifier for AVR bundles signed by the Intel
+	// Attestation Service.
+	DefaultVerifier = NewVerifier(IntelTrustRoots)
+)
+
+type verifierCacheEntry struct {
+	validity  *signatureValidity
+	expiresAt time.Time
+}
+
+// Verifier verifies AVR bundles and caches the results of successful AVR
+// signature verifications, keyed by the hash of the AVR bundle (which
+// includes the enclave quote).
+//
+// A cached verification is only used for timestamps at which the AVR
+// certificate chain is valid, so the result of Open is the same whether
+// the verification is cached or not.
+type Verifier struct {
+	trustRoots *x509.CertPool
+	pinned     []*x509.Certificate
+
+	cacheTTL      time.Duration
+	cacheCapacity uint64
+	cache         *lru.Cache
+
+	now func() time.Time
+}
+
+// VerifierOption is a configuration option used when instantiating a
+// verifier.
+type VerifierOption func(v *Verifier)
+
+// WithCacheTTL sets the time for which a successful AVR signature
+// verification is cached.
+func WithCacheTTL(ttl time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.cacheTTL = ttl
+	}
+}
+
+// WithCacheCapacity sets the maximum number of cached AVR signature
+// verifications.
+func WithCacheCapacity(capacity uint64) VerifierOption {
+	return func(v *Verifier) {
+		v.cacheCapacity = capacity
+	}
+}
+
+// WithPinnedCertificates configures the verifier to accept AVRs signed
+// by one of the given certificates instead of verifying the certificate
+// chain against the trust roots.
+//
+// This allows offline verification (e.g., for audits in an air-gapped
+// environment) against a known set of AVR signing certificates.
+func WithPinnedCertificates(certs []*x509.Certificate) VerifierOption {
+	return func(v *Verifier) {
+		v.pinned = certs
+	}
+}
+
+// Open decodes and validates the AVR contained in the bundle at the given
+// timestamp, and returns the Attestation Verification Report iff it is
+// valid.
+func (v *Verifier) Open(b *AVRBundle, ts time.Time) (*AttestationVerificationReport, error) {
+	start := time.Now()
+	avr, err := v.open(b, ts)
+	avrVerificationLatency.Observe(time.Since(start).Seconds())
+	if err != nil {
+		avrVerificationFailures.Inc()
+		return nil, err
+	}
+	return avr, nil
+}
+
+func (v *Verifier) open(b *AVRBundle, ts time.Time) (*AttestationVerificationReport, error) {
+	if !unsafeSkipVerify {
+		if err := v.verifySignature(b, ts); err != nil {
+			return nil, err
+		}
+	}
+
+	// The report body is always decoded and validated as it is cheap and
+	// its validation depends on settings that may change at runtime.
+	return decodeAVRBody(b.Body)
+}
+
+func (v *Verifier) verifySignature(b *AVRBundle, ts time.Time) error {
+	key := hash.NewFrom(b)
+	if cached, ok := v.cache.Get(key); ok {
+		entry := cached.(*verifierCacheEntry)
+		if v.now().Before(entry.expiresAt) && entry.validity.contains(ts) {
+			avrVerificationCacheHits.Inc()
+			return nil
+		}
+	}
+
+	var (
+		validity *signatureValidity
+		err      error
+	)
+	if len(v.pinned) > 0 {
+		validity, err = validatePinnedAVRSignature(b.Body, b.Signature, b.CertificateChain, v.pinned, ts)
+	} else {
+		validity, err = validateAVRSignature(b.Body, b.Signature, b.CertificateChain, v.trustRoots, ts)
+	}
+	if err != nil {
+		return err
+	}
+
+	_ = v.cache.Put(key, &verifierCacheEntry{
+		validity:  validity,
+		expiresAt: v.now().Add(v.cacheTTL),
+	})
+	return nil
+}
+
+// NewVerifier creates a new AVR bundle verifier that verifies AVR
+// certificate chains against the given trust roots.
+func NewVerifier(trustRoots *x509.CertPool, options ...VerifierOption) *Verifier {
+	v := &Verifier{
+		trustRoots:    trustRoots,
+		cacheTTL:      DefaultVerifierCacheTTL,
+		cacheCapacity: DefaultVerifierCacheCapacity,
+		now:           time.Now,
+	}
+	for _, opt := range options {
+		opt(v)
+	}
+	// Creating a cache can only fail due to invalid options and there are
+	// none that could be invalid.
+	v.cache, _ = lru.New(lru.Capacity(v.cacheCapacity, false))
+
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(verifierCollectors...)
+	})
+
+	return v
+}
@@ -0,0 +1,69 @@
+package ias
+
+import (
+	"crypto/x509"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier(t *testing.T) {
+	require := require.New(t)
+
+	// TODO: Generate and test production AVR without debug bit.
+	SetAllowDebugEnclaves()
+	defer UnsetAllowDebugEnclaves()
+
+	raw, sig, certs := loadAVRv4(t)
+	bundle := &AVRBundle{
+		Body:             raw,
+		CertificateChain: certs,
+		Signature:        sig,
+	}
+	ts := time.Date(2020, 5, 11, 9, 21, 15, 0, time.UTC)
+
+	now := ts
+	v := NewVerifier(IntelTrustRoots, WithCacheTTL(time.Minute))
+	v.now = func() time.Time { return now }
+
+	avr, err := v.Open(bundle, ts)
+	require.NoError(err, "Open")
+	require.Equal("323119119247496566074708526703373820736", avr.ID, "id")
+
+	tampered := *bundle
+	tampered.Body = append([]byte{}, raw...)
+	tampered.Body[len(tampered.Body)-2] ^= 0x01
+	_, err = v.Open(&tampered, ts)
+	require.Error(err, "Open should fail with a tampered body")
+
+	// Successful signature verifications are cached.
+	v.trustRoots = x509.NewCertPool()
+	_, err = v.Open(bundle, ts)
+	require.NoError(err, "Open should use the cached verification")
+
+	// Cached verifications are only used while the certificates are valid.
+	_, err = v.Open(bundle, time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Error(err, "Open should fail with expired certificates")
+
+	// Cached verifications expire.
+	now = now.Add(2 * time.Minute)
+	_, err = v.Open(bundle, ts)
+	require.Error(err, "Open should fail after the cached verification expired")
+
+	// Offline verification against pinned certificates.
+	decoded, err := url.QueryUnescape(string(certs))
+	require.NoError(err, "QueryUnescape")
+	chain, err := CertsFromPEM([]byte(decoded))
+	require.NoError(err, "CertsFromPEM")
+	require.Len(chain, 2)
+
+	pv := NewVerifier(x509.NewCertPool(), WithPinnedCertificates(chain[:1]))
+	_, err = pv.Open(bundle, ts)
+	require.NoError(err, "Open with pinned signing certificate")
+
+	pv = NewVerifier(x509.NewCertPool(), WithPinnedCertificates(chain[1:]))
+	_, err = pv.Open(bundle, ts)
+	require.Error(err, "Open should fail if the signing certificate is not pinned")
+}
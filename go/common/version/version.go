@@ -64,7 +64,7 @@ var (
 	// the runtime.
 	//
 	// NOTE: This version must be synced with runtime/src/common/version.rs.
-	RuntimeProtocol = Version{Major: 0, Minor: 14, Patch: 0}
+	RuntimeProtocol = Version{Major: 0, Minor: 17, Patch: 0}
 
 	// CommitteeProtocol versions the P2P protocol used by the
 	// committee members.
@@ -8,6 +8,7 @@ import (
 
 	beacon "github.com/oasislabs/oasis-core/go/beacon/api"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/errors"
 	"github.com/oasislabs/oasis-core/go/common/node"
@@ -41,6 +42,21 @@ var (
 	// ErrVersionNotFound is the error returned when the given version (height) cannot be found,
 	// possibly because it was pruned.
 	ErrVersionNotFound = errors.New(moduleName, 3, "consensus: version not found")
+
+	// ErrEventIndexDisabled is the error returned when searching for events
+	// on a node that does not maintain an event index.
+	ErrEventIndexDisabled = errors.New(moduleName, 4, "consensus: event index disabled")
+
+	// ErrInvalidArgument is the error returned on malformed arguments.
+	ErrInvalidArgument = errors.New(moduleName, 5, "consensus: invalid argument")
+
+	// ErrTxPanicked is the error returned when a transaction handler panicked
+	// while processing the transaction.
+	ErrTxPanicked = errors.New(moduleName, 6, "consensus: transaction handler panicked")
+
+	// ErrTxQuarantined is the error returned when a transaction is rejected
+	// because its handler previously panicked on the local node.
+	ErrTxQuarantined = errors.New(moduleName, 7, "consensus: transaction quarantined")
 )
 
 // ClientBackend is a limited consensus interface used by clients that connect to the local full
@@ -52,6 +68,13 @@ type ClientBackend interface {
 	// SubmitTx submits a signed consensus transaction.
 	SubmitTx(ctx context.Context, tx *transaction.SignedTransaction) error
 
+	// ValidateTx pre-validates a signed consensus transaction against the
+	// latest consensus state without submitting it.
+	//
+	// Failed checks are reported as diagnostics in the result. The same
+	// checks are performed by SubmitTx before broadcasting.
+	ValidateTx(ctx context.Context, tx *transaction.SignedTransaction) (*TxValidationResult, error)
+
 	// StateToGenesis returns the genesis state at the specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error)
 
@@ -86,6 +109,29 @@ type ClientBackend interface {
 
 	// GetStatus returns the current status overview.
 	GetStatus(ctx context.Context) (*Status, error)
+
+	// GetUnconfirmedTransactions returns a list of transactions currently in
+	// the local node's mempool. These are not yet committed to a block.
+	GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error)
+
+	// GetTransactionStatus returns the status of the transaction with the
+	// given hash as seen by the local node.
+	//
+	// Only transactions included in recent blocks, currently in the local
+	// mempool or recently rejected on local submission are known.
+	GetTransactionStatus(ctx context.Context, txHash hash.Hash) (*TransactionStatus, error)
+
+	// SearchEvents returns a page of events with matching attributes that
+	// were emitted by blocks or transactions in the given height range.
+	//
+	// The local node must maintain an event index, otherwise
+	// ErrEventIndexDisabled is returned.
+	SearchEvents(ctx context.Context, query *EventSearchQuery) (*EventSearchPage, error)
+
+	// GetQuarantinedTransactions returns the transactions whose handlers
+	// recently panicked on the local node. Such transactions are rejected
+	// on submission to the local node.
+	GetQuarantinedTransactions(ctx context.Context) ([]*QuarantinedTransaction, error)
 }
 
 // Block is a consensus block.
@@ -126,6 +172,73 @@ type Status struct {
 	GenesisHash []byte `json:"genesis_hash"`
 }
 
+// TransactionStatusKind is the kind of a transaction status.
+type TransactionStatusKind uint8
+
+const (
+	// TransactionStatusUnknown is the status of a transaction that is not
+	// known to the local node.
+	TransactionStatusUnknown TransactionStatusKind = 0
+	// TransactionStatusPending is the status of a transaction that is in the
+	// local mempool waiting to be included in a block.
+	TransactionStatusPending TransactionStatusKind = 1
+	// TransactionStatusIncluded is the status of a transaction that has been
+	// included in a block. Note that the transaction may have still failed.
+	TransactionStatusIncluded TransactionStatusKind = 2
+	// TransactionStatusRejected is the status of a transaction that has been
+	// rejected on submission and was not added to the mempool.
+	TransactionStatusRejected TransactionStatusKind = 3
+)
+
+// String returns a string representation of a transaction status kind.
+func (k TransactionStatusKind) String() string {
+	switch k {
+	case TransactionStatusUnknown:
+		return "unknown"
+	case TransactionStatusPending:
+		return "pending"
+	case TransactionStatusIncluded:
+		return "included"
+	case TransactionStatusRejected:
+		return "rejected"
+	default:
+		return "[unknown transaction status]"
+	}
+}
+
+// TransactionError is a transaction execution error.
+type TransactionError struct {
+	// Module is the module that emitted the error.
+	Module string `json:"module,omitempty"`
+	// Code is the module-specific error code.
+	Code uint32 `json:"code,omitempty"`
+	// Message is the error message.
+	Message string `json:"message,omitempty"`
+}
+
+// TransactionStatus is the status of a transaction.
+type TransactionStatus struct {
+	// Status is the transaction status.
+	Status TransactionStatusKind `json:"status"`
+	// Height is the height of the block that included the transaction (only
+	// set for included transactions).
+	Height int64 `json:"height,omitempty"`
+	// Error is the error that the transaction failed with (if any).
+	Error *TransactionError `json:"error,omitempty"`
+}
+
+// QuarantinedTransaction is a transaction whose handler panicked.
+type QuarantinedTransaction struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+	// Height is the height of the block the transaction was processed for.
+	Height int64 `json:"height"`
+	// Method is the transaction method.
+	Method transaction.MethodName `json:"method"`
+	// Panic is the description of the panic.
+	Panic string `json:"panic"`
+}
+
 // Backend is an interface that a consensus backend must provide.
 type Backend interface {
 	ClientBackend
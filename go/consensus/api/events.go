@@ -0,0 +1,91 @@
+package api
+
+import (
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+)
+
+const (
+	// DefaultEventSearchLimit is the default maximum number of events in an
+	// event search page.
+	DefaultEventSearchLimit = 100
+	// MaxEventSearchLimit is the maximum number of events in an event search
+	// page.
+	MaxEventSearchLimit = 1000
+)
+
+// EventCondition is a condition on a field of a (CBOR-encoded) event.
+type EventCondition struct {
+	// Field is the name of the event field (e.g., "from").
+	Field string `json:"field"`
+	// Value is the CBOR-encoded value that the field must be equal to.
+	Value cbor.RawMessage `json:"value"`
+}
+
+// EventPosition is the position of an event within the consensus chain.
+type EventPosition struct {
+	// Height is the height of the block that emitted the event.
+	Height int64 `json:"height"`
+	// Index is the index of the event among all events emitted by the block,
+	// including events emitted by transactions.
+	Index uint64 `json:"index"`
+}
+
+// EventSearchQuery is a query for events with matching attributes.
+type EventSearchQuery struct {
+	// FromHeight is the first height (inclusive) to search.
+	FromHeight int64 `json:"from_height"`
+	// ToHeight is the last height (inclusive) to search. If set to
+	// HeightLatest, all indexed heights are searched.
+	ToHeight int64 `json:"to_height"`
+
+	// Type is the backend specific event type. In case of the Tendermint
+	// backend, this is the ABCI event type (e.g., "oasis-event-100_staking").
+	Type string `json:"type"`
+	// Key is the event key (e.g., "transfer").
+	Key string `json:"key"`
+	// Conditions are the conditions on the event fields that must all be
+	// satisfied for an event to match.
+	Conditions []EventCondition `json:"conditions,omitempty"`
+
+	// Cursor is the position of the last event of the previous page. If not
+	// set, the first page is returned.
+	Cursor *EventPosition `json:"cursor,omitempty"`
+
+	// Limit is the maximum number of events in the page. If zero,
+	// DefaultEventSearchLimit is used. Limits above MaxEventSearchLimit are
+	// capped.
+	Limit int `json:"limit,omitempty"`
+}
+
+// PageLimit returns the effective maximum number of events in the page.
+func (q *EventSearchQuery) PageLimit() int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultEventSearchLimit
+	case q.Limit > MaxEventSearchLimit:
+		return MaxEventSearchLimit
+	default:
+		return q.Limit
+	}
+}
+
+// EventSearchResult is an event matching an event search query.
+type EventSearchResult struct {
+	EventPosition
+
+	// TxIndex is the index of the transaction that emitted the event within
+	// the block. It is not set for events emitted by the block itself.
+	TxIndex *uint32 `json:"tx_index,omitempty"`
+
+	// Value is the (backend specific) event value.
+	Value cbor.RawMessage `json:"value"`
+}
+
+// EventSearchPage is a page of events matching an event search query.
+type EventSearchPage struct {
+	Events []*EventSearchResult `json:"events"`
+
+	// NextCursor is the cursor that should be used to query the next page.
+	// It is not set if this is the last page.
+	NextCursor *EventPosition `json:"next_cursor,omitempty"`
+}
@@ -5,6 +5,7 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
@@ -20,24 +21,34 @@ var (
 
 	// methodSubmitTx is the SubmitTx method.
 	methodSubmitTx = serviceName.NewMethod("SubmitTx", transaction.SignedTransaction{})
+	// methodValidateTx is the ValidateTx method.
+	methodValidateTx = serviceName.NewMethod("ValidateTx", transaction.SignedTransaction{}).WithJSONGateway(TxValidationResult{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodEstimateGas is the EstimateGas method.
-	methodEstimateGas = serviceName.NewMethod("EstimateGas", &EstimateGasRequest{})
+	methodEstimateGas = serviceName.NewMethod("EstimateGas", &EstimateGasRequest{}).WithJSONGateway(transaction.Gas(0))
 	// methodGetSignerNonce is a GetSignerNonce method.
-	methodGetSignerNonce = serviceName.NewMethod("GetSignerNonce", &GetSignerNonceRequest{})
+	methodGetSignerNonce = serviceName.NewMethod("GetSignerNonce", &GetSignerNonceRequest{}).WithJSONGateway(uint64(0))
 	// methodGetEpoch is the GetEpoch method.
-	methodGetEpoch = serviceName.NewMethod("GetEpoch", int64(0))
+	methodGetEpoch = serviceName.NewMethod("GetEpoch", int64(0)).WithJSONGateway(epochtime.EpochTime(0))
 	// methodWaitEpoch is the WaitEpoch method.
 	methodWaitEpoch = serviceName.NewMethod("WaitEpoch", epochtime.EpochTime(0))
 	// methodGetBlock is the GetBlock method.
-	methodGetBlock = serviceName.NewMethod("GetBlock", int64(0))
+	methodGetBlock = serviceName.NewMethod("GetBlock", int64(0)).WithJSONGateway(Block{})
 	// methodGetTransactions is the GetTransactions method.
-	methodGetTransactions = serviceName.NewMethod("GetTransactions", int64(0))
+	methodGetTransactions = serviceName.NewMethod("GetTransactions", int64(0)).WithJSONGateway([][]byte{})
 	// methodGetGenesisDocument is the GetGenesisDocument method.
-	methodGetGenesisDocument = serviceName.NewMethod("GetGenesisDocument", nil)
+	methodGetGenesisDocument = serviceName.NewMethod("GetGenesisDocument", nil).WithJSONGateway(genesis.Document{})
 	// methodGetStatus is the GetStatus method.
-	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
+	methodGetStatus = serviceName.NewMethod("GetStatus", nil).WithJSONGateway(Status{})
+	// methodGetUnconfirmedTransactions is the GetUnconfirmedTransactions method.
+	methodGetUnconfirmedTransactions = serviceName.NewMethod("GetUnconfirmedTransactions", nil).WithJSONGateway([][]byte{})
+	// methodGetTransactionStatus is the GetTransactionStatus method.
+	methodGetTransactionStatus = serviceName.NewMethod("GetTransactionStatus", hash.Hash{}).WithJSONGateway(TransactionStatus{})
+	// methodSearchEvents is the SearchEvents method.
+	methodSearchEvents = serviceName.NewMethod("SearchEvents", &EventSearchQuery{}).WithJSONGateway(EventSearchPage{})
+	// methodGetQuarantinedTransactions is the GetQuarantinedTransactions method.
+	methodGetQuarantinedTransactions = serviceName.NewMethod("GetQuarantinedTransactions", nil).WithJSONGateway([]*QuarantinedTransaction{})
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", nil)
@@ -58,6 +69,10 @@ var (
 				MethodName: methodSubmitTx.ShortName(),
 				Handler:    handlerSubmitTx,
 			},
+			{
+				MethodName: methodValidateTx.ShortName(),
+				Handler:    handlerValidateTx,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -94,6 +109,22 @@ var (
 				MethodName: methodGetStatus.ShortName(),
 				Handler:    handlerGetStatus,
 			},
+			{
+				MethodName: methodGetUnconfirmedTransactions.ShortName(),
+				Handler:    handlerGetUnconfirmedTransactions,
+			},
+			{
+				MethodName: methodGetTransactionStatus.ShortName(),
+				Handler:    handlerGetTransactionStatus,
+			},
+			{
+				MethodName: methodSearchEvents.ShortName(),
+				Handler:    handlerSearchEvents,
+			},
+			{
+				MethodName: methodGetQuarantinedTransactions.ShortName(),
+				Handler:    handlerGetQuarantinedTransactions,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -148,6 +179,29 @@ func handlerSubmitTx( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerValidateTx( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(transaction.SignedTransaction)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).ValidateTx(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodValidateTx.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).ValidateTx(ctx, req.(*transaction.SignedTransaction))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -347,6 +401,90 @@ func handlerGetStatus( // nolint: golint
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetUnconfirmedTransactions( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(ClientBackend).GetUnconfirmedTransactions(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetUnconfirmedTransactions.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetUnconfirmedTransactions(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetTransactionStatus( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var txHash hash.Hash
+	if err := dec(&txHash); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).GetTransactionStatus(ctx, txHash)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionStatus.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetTransactionStatus(ctx, req.(hash.Hash))
+	}
+	return interceptor(ctx, txHash, info, handler)
+}
+
+func handlerSearchEvents( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query EventSearchQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).SearchEvents(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSearchEvents.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).SearchEvents(ctx, req.(*EventSearchQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetQuarantinedTransactions( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(ClientBackend).GetQuarantinedTransactions(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetQuarantinedTransactions.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).GetQuarantinedTransactions(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -496,6 +634,14 @@ func (c *consensusClient) SubmitTx(ctx context.Context, tx *transaction.SignedTr
 	return c.conn.Invoke(ctx, methodSubmitTx.FullName(), tx, nil)
 }
 
+func (c *consensusClient) ValidateTx(ctx context.Context, tx *transaction.SignedTransaction) (*TxValidationResult, error) {
+	var rsp TxValidationResult
+	if err := c.conn.Invoke(ctx, methodValidateTx.FullName(), tx, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error) {
 	var rsp genesis.Document
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -564,6 +710,38 @@ func (c *consensusClient) GetStatus(ctx context.Context) (*Status, error) {
 	return &rsp, nil
 }
 
+func (c *consensusClient) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
+	var rsp [][]byte
+	if err := c.conn.Invoke(ctx, methodGetUnconfirmedTransactions.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *consensusClient) GetTransactionStatus(ctx context.Context, txHash hash.Hash) (*TransactionStatus, error) {
+	var rsp TransactionStatus
+	if err := c.conn.Invoke(ctx, methodGetTransactionStatus.FullName(), txHash, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) SearchEvents(ctx context.Context, query *EventSearchQuery) (*EventSearchPage, error) {
+	var rsp EventSearchPage
+	if err := c.conn.Invoke(ctx, methodSearchEvents.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) GetQuarantinedTransactions(ctx context.Context) ([]*QuarantinedTransaction, error) {
+	var rsp []*QuarantinedTransaction
+	if err := c.conn.Invoke(ctx, methodGetQuarantinedTransactions.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *consensusClient) WatchBlocks(ctx context.Context) (<-chan *Block, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -12,6 +12,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
 
 const (
@@ -167,3 +168,33 @@ func NewSubmissionManager(backend Backend, priceDiscovery PriceDiscovery, maxFee
 func SignAndSubmitTx(ctx context.Context, backend Backend, signer signature.Signer, tx *transaction.Transaction) error {
 	return backend.SubmissionManager().SignAndSubmitTx(ctx, signer, tx)
 }
+
+// SetTxExpiry sets the expiry of the given transaction relative to the latest
+// consensus block, so that the transaction is no longer valid once more than
+// the given number of blocks or epochs have passed.
+//
+// If the number of blocks (epochs) is zero, the expiry height (epoch) of the
+// transaction is left unchanged.
+func SetTxExpiry(
+	ctx context.Context,
+	backend ClientBackend,
+	tx *transaction.Transaction,
+	blocks uint64,
+	epochs epochtime.EpochTime,
+) error {
+	if blocks > 0 {
+		blk, err := backend.GetBlock(ctx, HeightLatest)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		tx.NotValidAfter = uint64(blk.Height) + blocks
+	}
+	if epochs > 0 {
+		epoch, err := backend.GetEpoch(ctx, HeightLatest)
+		if err != nil {
+			return fmt.Errorf("failed to get current epoch: %w", err)
+		}
+		tx.NotValidAfterEpoch = uint64(epoch + epochs)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+)
+
+type submissionTestBackend struct {
+	Backend
+}
+
+func (b *submissionTestBackend) GetBlock(ctx context.Context, height int64) (*Block, error) {
+	return &Block{Height: 100}, nil
+}
+
+func (b *submissionTestBackend) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
+	return 7, nil
+}
+
+func TestSetTxExpiry(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	backend := &submissionTestBackend{}
+	tx := transaction.NewTransaction(0, nil, transaction.MethodName("test.Method"), nil)
+
+	err := SetTxExpiry(ctx, backend, tx, 0, 0)
+	require.NoError(err, "SetTxExpiry")
+	require.Zero(tx.NotValidAfter, "expiry height should not be set")
+	require.Zero(tx.NotValidAfterEpoch, "expiry epoch should not be set")
+
+	err = SetTxExpiry(ctx, backend, tx, 10, 0)
+	require.NoError(err, "SetTxExpiry")
+	require.EqualValues(110, tx.NotValidAfter, "expiry height should be relative to the latest block")
+	require.Zero(tx.NotValidAfterEpoch, "expiry epoch should not be set")
+
+	err = SetTxExpiry(ctx, backend, tx, 0, 2)
+	require.NoError(err, "SetTxExpiry")
+	require.EqualValues(110, tx.NotValidAfter, "expiry height should be unchanged")
+	require.EqualValues(9, tx.NotValidAfterEpoch, "expiry epoch should be relative to the current epoch")
+}
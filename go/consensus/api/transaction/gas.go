@@ -12,6 +12,10 @@ var (
 
 	// ErrGasPriceTooLow is the error returned when the gas price is too low.
 	ErrGasPriceTooLow = errors.New(moduleName, 3, "transaction: gas price too low")
+
+	// ErrGasLimitExceeded is the error returned when the gas limit of a
+	// transaction exceeds the maximum amount of gas in a block.
+	ErrGasLimitExceeded = errors.New(moduleName, 6, "transaction: gas limit exceeds maximum block gas")
 )
 
 // Gas is the consensus gas representation.
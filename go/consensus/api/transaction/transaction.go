@@ -22,6 +22,14 @@ var (
 	// ErrInvalidNonce is the error returned when a nonce is invalid.
 	ErrInvalidNonce = errors.New(moduleName, 1, "transaction: invalid nonce")
 
+	// ErrExpired is the error returned when a transaction is executed after
+	// its expiry height or epoch.
+	ErrExpired = errors.New(moduleName, 4, "transaction: expired")
+
+	// ErrInvalidSignature is the error returned when a transaction signature
+	// is invalid or was made with an incorrect signature context.
+	ErrInvalidSignature = errors.New(moduleName, 5, "transaction: invalid signature")
+
 	// SignatureContext is the context used for signing transactions.
 	SignatureContext = signature.NewContext("oasis-core/consensus: tx", signature.WithChainSeparation())
 
@@ -38,6 +46,12 @@ type Transaction struct {
 	// Fee is an optional fee that the sender commits to pay to execute this
 	// transaction.
 	Fee *Fee `json:"fee,omitempty"`
+	// NotValidAfter is an optional consensus height after which the
+	// transaction is no longer valid. Zero means that it never expires.
+	NotValidAfter uint64 `json:"not_valid_after,omitempty"`
+	// NotValidAfterEpoch is an optional epoch after which the transaction is
+	// no longer valid. Zero means that it never expires.
+	NotValidAfterEpoch uint64 `json:"not_valid_after_epoch,omitempty"`
 
 	// Method is the method that should be called.
 	Method MethodName `json:"method"`
@@ -54,6 +68,12 @@ func (t Transaction) PrettyPrint(prefix string, w io.Writer) {
 	} else {
 		fmt.Fprintf(w, "%sFee:   none\n", prefix)
 	}
+	if t.NotValidAfter != 0 {
+		fmt.Fprintf(w, "%sNot valid after height: %d\n", prefix, t.NotValidAfter)
+	}
+	if t.NotValidAfterEpoch != 0 {
+		fmt.Fprintf(w, "%sNot valid after epoch: %d\n", prefix, t.NotValidAfterEpoch)
+	}
 	fmt.Fprintf(w, "%sMethod: %s\n", prefix, t.Method)
 	fmt.Fprintf(w, "%sBody:\n", prefix)
 
@@ -91,6 +111,18 @@ func (t *Transaction) SanityCheck() error {
 	return t.Method.SanityCheck()
 }
 
+// IsExpiredAtHeight returns true if the transaction is no longer valid when
+// executed at the given consensus height.
+func (t *Transaction) IsExpiredAtHeight(height int64) bool {
+	return t.NotValidAfter != 0 && height > 0 && uint64(height) > t.NotValidAfter
+}
+
+// IsExpiredAtEpoch returns true if the transaction is no longer valid when
+// executed in the given epoch.
+func (t *Transaction) IsExpiredAtEpoch(epoch uint64) bool {
+	return t.NotValidAfterEpoch != 0 && epoch > t.NotValidAfterEpoch
+}
+
 // NewTransaction creates a new transaction.
 func NewTransaction(nonce uint64, fee *Fee, method MethodName, body interface{}) *Transaction {
 	var rawBody []byte
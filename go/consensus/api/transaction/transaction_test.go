@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+)
+
+func TestTransactionExpiry(t *testing.T) {
+	require := require.New(t)
+
+	tx := NewTransaction(0, nil, MethodName("test.Method"), nil)
+	require.False(tx.IsExpiredAtHeight(1), "transaction without expiry should not expire")
+	require.False(tx.IsExpiredAtHeight(1_000_000), "transaction without expiry should not expire")
+	require.False(tx.IsExpiredAtEpoch(1_000_000), "transaction without expiry should not expire")
+
+	tx.NotValidAfter = 10
+	require.False(tx.IsExpiredAtHeight(9), "transaction should be valid before expiry height")
+	require.False(tx.IsExpiredAtHeight(10), "transaction should be valid at expiry height")
+	require.True(tx.IsExpiredAtHeight(11), "transaction should be expired after expiry height")
+	require.False(tx.IsExpiredAtEpoch(1_000_000), "height expiry should not affect epoch expiry")
+
+	tx.NotValidAfterEpoch = 3
+	require.False(tx.IsExpiredAtEpoch(2), "transaction should be valid before expiry epoch")
+	require.False(tx.IsExpiredAtEpoch(3), "transaction should be valid at expiry epoch")
+	require.True(tx.IsExpiredAtEpoch(4), "transaction should be expired after expiry epoch")
+
+	// Transactions without expiry should keep the same encoding.
+	legacyTx := struct {
+		Nonce  uint64          `json:"nonce"`
+		Fee    *Fee            `json:"fee,omitempty"`
+		Method MethodName      `json:"method"`
+		Body   cbor.RawMessage `json:"body,omitempty"`
+	}{
+		Nonce:  42,
+		Method: MethodName("test.Method"),
+	}
+	tx = NewTransaction(42, nil, MethodName("test.Method"), nil)
+	require.Equal(cbor.Marshal(legacyTx), cbor.Marshal(tx), "encoding without expiry should not change")
+
+	tx.NotValidAfter = 10
+	tx.NotValidAfterEpoch = 3
+	var decTx Transaction
+	err := cbor.Unmarshal(cbor.Marshal(tx), &decTx)
+	require.NoError(err, "Unmarshal")
+	require.EqualValues(10, decTx.NotValidAfter, "expiry height should round-trip")
+	require.EqualValues(3, decTx.NotValidAfterEpoch, "expiry epoch should round-trip")
+}
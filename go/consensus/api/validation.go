@@ -0,0 +1,138 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/errors"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+)
+
+// TxCheck is the kind of a transaction pre-validation check.
+type TxCheck string
+
+const (
+	// TxCheckSize checks that the transaction does not exceed the maximum
+	// transaction size.
+	TxCheckSize TxCheck = "size"
+	// TxCheckSignature checks that the transaction is correctly signed using
+	// the transaction signature context and that it is well-formed.
+	TxCheckSignature TxCheck = "signature"
+	// TxCheckNonce checks that the transaction nonce is not behind the
+	// signer's account nonce.
+	TxCheckNonce TxCheck = "nonce"
+	// TxCheckFee checks that the signer's balance covers the fee.
+	TxCheckFee TxCheck = "fee"
+	// TxCheckGas checks that the gas limit does not exceed the maximum amount
+	// of gas in a block.
+	TxCheckGas TxCheck = "gas"
+)
+
+// TxDiagnostic is a failed transaction pre-validation check.
+type TxDiagnostic struct {
+	// Check is the check that failed.
+	Check TxCheck `json:"check"`
+	// Module is the module of the error the transaction would fail with.
+	Module string `json:"module,omitempty"`
+	// Code is the code of the error the transaction would fail with.
+	Code uint32 `json:"code,omitempty"`
+	// Message is a human readable description of the failure.
+	Message string `json:"message"`
+}
+
+// Err returns the error the transaction would fail with.
+func (d *TxDiagnostic) Err() error {
+	if err := errors.FromCode(d.Module, d.Code); err != nil {
+		return fmt.Errorf("%w: %s", err, d.Message)
+	}
+	return fmt.Errorf("%s", d.Message)
+}
+
+// TxValidationResult is the result of transaction pre-validation.
+type TxValidationResult struct {
+	// Diagnostics are the failed checks, if any.
+	Diagnostics []*TxDiagnostic `json:"diagnostics,omitempty"`
+}
+
+// IsValid returns true iff all pre-validation checks passed.
+func (r *TxValidationResult) IsValid() bool {
+	return len(r.Diagnostics) == 0
+}
+
+// Err returns the error of the first failed check or nil if all checks
+// passed.
+func (r *TxValidationResult) Err() error {
+	if r.IsValid() {
+		return nil
+	}
+	return r.Diagnostics[0].Err()
+}
+
+func (r *TxValidationResult) add(check TxCheck, err error, msg string, args ...interface{}) {
+	module, code := errors.Code(err)
+	r.Diagnostics = append(r.Diagnostics, &TxDiagnostic{
+		Check:   check,
+		Module:  module,
+		Code:    code,
+		Message: fmt.Sprintf(msg, args...),
+	})
+}
+
+// TxValidationState is the consensus state that a transaction is
+// pre-validated against.
+type TxValidationState struct {
+	// Nonce is the signer's account nonce.
+	Nonce uint64
+	// Balance is the signer's general account balance.
+	Balance quantity.Quantity
+
+	// MaxTxSize is the maximum transaction size in bytes. Zero means that
+	// the size is unlimited.
+	MaxTxSize uint64
+	// MaxBlockGas is the maximum amount of gas in a block. Zero means that
+	// the amount is unlimited.
+	MaxBlockGas transaction.Gas
+}
+
+// ValidateTx pre-validates a signed transaction against the given state of
+// its signer so that transactions which are certain to be rejected can be
+// reported before being submitted.
+//
+// Passing pre-validation does not guarantee that the transaction will be
+// accepted as the state may change before it is executed.
+func ValidateTx(sigTx *transaction.SignedTransaction, state *TxValidationState) *TxValidationResult {
+	var result TxValidationResult
+
+	if size := uint64(len(cbor.Marshal(sigTx))); state.MaxTxSize > 0 && size > state.MaxTxSize {
+		result.add(TxCheckSize, ErrOversizedTx, "transaction size %d exceeds maximum %d", size, state.MaxTxSize)
+	}
+
+	// Remaining checks require an authenticated transaction.
+	var tx transaction.Transaction
+	if err := sigTx.Open(&tx); err != nil {
+		result.add(TxCheckSignature, transaction.ErrInvalidSignature, "failed to verify signature: %s", err)
+		return &result
+	}
+	if err := tx.SanityCheck(); err != nil {
+		result.add(TxCheckSignature, err, "malformed transaction: %s", err)
+		return &result
+	}
+
+	if tx.Nonce < state.Nonce {
+		result.add(TxCheckNonce, transaction.ErrInvalidNonce, "nonce %d is behind account nonce %d", tx.Nonce, state.Nonce)
+	}
+
+	fee := tx.Fee
+	if fee == nil {
+		fee = &transaction.Fee{}
+	}
+	if state.Balance.Cmp(&fee.Amount) < 0 {
+		result.add(TxCheckFee, transaction.ErrInsufficientFeeBalance, "fee %s exceeds balance %s", fee.Amount, state.Balance)
+	}
+	if state.MaxBlockGas > 0 && fee.Gas > state.MaxBlockGas {
+		result.add(TxCheckGas, transaction.ErrGasLimitExceeded, "gas limit %d exceeds maximum block gas %d", fee.Gas, state.MaxBlockGas)
+	}
+
+	return &result
+}
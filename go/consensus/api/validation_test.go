@@ -0,0 +1,71 @@
+package api
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/errors"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+)
+
+func TestValidateTx(t *testing.T) {
+	require := require.New(t)
+
+	signature.SetChainContext("test: oasis-core tests")
+
+	signer, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+
+	newTx := func(nonce uint64, amount uint64, gas transaction.Gas) *transaction.SignedTransaction {
+		fee := &transaction.Fee{Gas: gas}
+		require.NoError(fee.Amount.FromUint64(amount), "FromUint64")
+		sigTx, serr := transaction.Sign(signer, transaction.NewTransaction(nonce, fee, transaction.MethodName("test.Method"), nil))
+		require.NoError(serr, "Sign")
+		return sigTx
+	}
+	checks := func(result *TxValidationResult) (checks []TxCheck) {
+		for _, d := range result.Diagnostics {
+			checks = append(checks, d.Check)
+		}
+		return
+	}
+
+	state := &TxValidationState{
+		Nonce:       5,
+		MaxBlockGas: 1000,
+	}
+	require.NoError(state.Balance.FromUint64(100), "FromUint64")
+
+	// Valid transaction.
+	result := ValidateTx(newTx(5, 100, 1000), state)
+	require.True(result.IsValid(), "transaction should be valid")
+	require.NoError(result.Err(), "Err")
+
+	// Nonces ahead of the account nonce may become valid later.
+	result = ValidateTx(newTx(6, 0, 0), state)
+	require.True(result.IsValid(), "transaction with future nonce should be valid")
+
+	// All failed checks should be reported.
+	result = ValidateTx(newTx(4, 101, 1001), state)
+	require.False(result.IsValid(), "transaction should be invalid")
+	require.Equal([]TxCheck{TxCheckNonce, TxCheckFee, TxCheckGas}, checks(result))
+	require.True(errors.Is(result.Err(), transaction.ErrInvalidNonce), "first error should be invalid nonce")
+	require.True(errors.Is(result.Diagnostics[1].Err(), transaction.ErrInsufficientFeeBalance))
+	require.True(errors.Is(result.Diagnostics[2].Err(), transaction.ErrGasLimitExceeded))
+
+	// Oversized transaction.
+	result = ValidateTx(newTx(5, 0, 0), &TxValidationState{Nonce: 5, MaxTxSize: 1})
+	require.Equal([]TxCheck{TxCheckSize}, checks(result))
+	require.True(errors.Is(result.Err(), ErrOversizedTx))
+
+	// Transaction signed with an incorrect signature context.
+	signed, err := signature.SignSigned(signer, signature.NewContext("test: incorrect context"), transaction.NewTransaction(5, nil, transaction.MethodName("test.Method"), nil))
+	require.NoError(err, "SignSigned")
+	result = ValidateTx(&transaction.SignedTransaction{Signed: *signed}, state)
+	require.Equal([]TxCheck{TxCheckSignature}, checks(result))
+	require.True(errors.Is(result.Err(), transaction.ErrInvalidSignature))
+}
@@ -0,0 +1,115 @@
+package abci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	storage "github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+)
+
+const (
+	// checkpointsDir is the subdirectory of the ABCI state directory which
+	// contains ABCI state checkpoints.
+	checkpointsDir = "checkpoints"
+
+	// checkpointVersion is the checkpoint format version.
+	checkpointVersion = 1
+)
+
+// CheckpointerConfig is the ABCI state checkpointer configuration.
+type CheckpointerConfig struct {
+	// Interval is the interval (in blocks) at which ABCI state checkpoints
+	// are created. A zero interval disables checkpoint creation.
+	Interval uint64
+
+	// NumKept is the number of most recent checkpoints to keep.
+	NumKept uint64
+
+	// ChunkSize is the checkpoint chunk size (in bytes).
+	ChunkSize uint64
+}
+
+func (s *applicationState) maybeCheckpoint(ctx context.Context, ndb nodedb.NodeDB, version uint64) error {
+	cfg := s.checkpointerCfg
+
+	// Checkpoints are only created at multiples of the checkpoint interval.
+	cpVersion := version - version%cfg.Interval
+	if cpVersion == 0 {
+		return nil
+	}
+
+	cps, err := s.checkpoints.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
+		Version:   checkpointVersion,
+		Namespace: s.stateRoot.Namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get existing checkpoints: %w", err)
+	}
+	sort.Slice(cps, func(i, j int) bool { return cps[i].Root.Version < cps[j].Root.Version })
+
+	if len(cps) == 0 || cps[len(cps)-1].Root.Version < cpVersion {
+		roots, err := ndb.GetRootsForVersion(ctx, cpVersion)
+		if err != nil {
+			return fmt.Errorf("failed to get state root for version %d: %w", cpVersion, err)
+		}
+		if len(roots) != 1 {
+			return fmt.Errorf("unexpected number of state roots for version %d: %d", cpVersion, len(roots))
+		}
+
+		root := storage.Root{
+			Namespace: s.stateRoot.Namespace,
+			Version:   cpVersion,
+			Hash:      roots[0],
+		}
+		s.logger.Info("creating new ABCI state checkpoint",
+			"root", root,
+			"chunk_size", cfg.ChunkSize,
+		)
+
+		cp, err := s.checkpoints.CreateCheckpoint(ctx, root, cfg.ChunkSize)
+		if err != nil {
+			return fmt.Errorf("failed to create checkpoint: %w", err)
+		}
+		cps = append(cps, cp)
+	}
+
+	// Garbage collect old checkpoints.
+	if uint64(len(cps)) > cfg.NumKept {
+		for _, cp := range cps[:uint64(len(cps))-cfg.NumKept] {
+			if err = s.checkpoints.DeleteCheckpoint(ctx, &checkpoint.DeleteCheckpointRequest{
+				Version: checkpointVersion,
+				Root:    cp.Root,
+			}); err != nil {
+				s.logger.Warn("failed to garbage collect ABCI state checkpoint",
+					"root", cp.Root,
+					"err", err,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *applicationState) checkpointWorker() {
+	defer close(s.checkpointerClosedCh)
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case v := <-s.checkpointerNotifyCh.Out():
+			version := v.(uint64)
+
+			if err := s.maybeCheckpoint(s.ctx, s.storage.NodeDB(), version); err != nil {
+				s.logger.Warn("failed to checkpoint state",
+					"err", err,
+					"block_height", version,
+				)
+			}
+		}
+	}
+}
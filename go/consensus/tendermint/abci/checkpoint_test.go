@@ -0,0 +1,88 @@
+package abci
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+	mkvsDB "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	mkvsBadgerDB "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
+)
+
+func TestCheckpointer(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dir, err := ioutil.TempDir("", "abci-checkpoint.test.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	// Create a Badger-backed Node DB.
+	ndb, err := mkvsBadgerDB.New(&mkvsDB.Config{
+		DB:           filepath.Join(dir, "db"),
+		NoFsync:      true,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	tree := mkvs.New(nil, ndb)
+
+	checkpoints, err := checkpoint.NewFileCreator(filepath.Join(dir, checkpointsDir), ndb)
+	require.NoError(err, "NewFileCreator")
+	s := &applicationState{
+		logger: logging.GetLogger("abci-mux/state/test"),
+		checkpointerCfg: CheckpointerConfig{
+			Interval:  5,
+			NumKept:   2,
+			ChunkSize: 16 * 1024,
+		},
+		checkpoints: checkpoints,
+	}
+
+	ctx := context.Background()
+	getCheckpointVersions := func() (versions []uint64) {
+		cps, cerr := checkpoints.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
+			Version:   checkpointVersion,
+			Namespace: common.Namespace{},
+		})
+		require.NoError(cerr, "GetCheckpoints")
+		for _, cp := range cps {
+			versions = append(versions, cp.Root.Version)
+		}
+		return
+	}
+
+	for i := uint64(1); i <= 17; i++ {
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("key:%d", i)), []byte(fmt.Sprintf("value:%d", i)))
+		require.NoError(err, "Insert")
+
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, common.Namespace{}, i)
+		require.NoError(err, "Commit")
+		err = ndb.Finalize(ctx, i, []hash.Hash{rootHash})
+		require.NoError(err, "Finalize")
+
+		err = s.maybeCheckpoint(ctx, ndb, i)
+		require.NoError(err, "maybeCheckpoint")
+
+		switch {
+		case i < 5:
+			require.Empty(getCheckpointVersions(), "there should be no checkpoints before the first interval")
+		case i < 10:
+			require.ElementsMatch([]uint64{5}, getCheckpointVersions(), "checkpoints should be correct")
+		case i < 15:
+			require.ElementsMatch([]uint64{5, 10}, getCheckpointVersions(), "checkpoints should be correct")
+		default:
+			require.ElementsMatch([]uint64{10, 15}, getCheckpointVersions(), "old checkpoints should be garbage collected")
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"runtime/debug"
 	"sort"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
 	upgrade "github.com/oasislabs/oasis-core/go/upgrade/api"
 )
 
@@ -51,8 +53,15 @@ var (
 			Help: "Total size of the ABCI database (MiB).",
 		},
 	)
+	abciTxPanics = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_abci_tx_panics",
+			Help: "Number of transactions whose handlers panicked.",
+		},
+	)
 	abciCollectors = []prometheus.Collector{
 		abciSize,
+		abciTxPanics,
 	}
 
 	metricsOnce sync.Once
@@ -63,6 +72,7 @@ type ApplicationConfig struct {
 	DataDir         string
 	StorageBackend  string
 	Pruning         PruneConfig
+	Checkpointer    CheckpointerConfig
 	HaltEpochHeight epochtime.EpochTime
 	MinGasPrice     uint64
 	DisableCheckTx  bool
@@ -263,11 +273,36 @@ func (a *ApplicationServer) EstimateGas(caller signature.PublicKey, tx *transact
 	return a.mux.EstimateGas(caller, tx)
 }
 
+// ConsensusParameters returns the consensus parameters as of the last
+// committed block.
+func (a *ApplicationServer) ConsensusParameters() *consensusGenesis.Parameters {
+	return a.mux.state.ConsensusParameters()
+}
+
+// CheckTxDisabled returns true iff CheckTx has been disabled for debugging.
+func (a *ApplicationServer) CheckTxDisabled() bool {
+	return a.mux.state.disableCheckTx
+}
+
+// QuarantinedTransactions returns the transactions whose handlers panicked.
+func (a *ApplicationServer) QuarantinedTransactions() []*consensus.QuarantinedTransaction {
+	return a.mux.quarantine.List()
+}
+
 // BlockHeight returns the last committed block height.
 func (a *ApplicationServer) BlockHeight() int64 {
 	return a.mux.state.BlockHeight()
 }
 
+// Checkpoints returns the provider of ABCI state checkpoints created by this
+// server, or nil if checkpoint creation is disabled.
+func (a *ApplicationServer) Checkpoints() checkpoint.ChunkProvider {
+	if a.mux.state.checkpoints == nil {
+		return nil
+	}
+	return a.mux.state.checkpoints
+}
+
 // NewApplicationServer returns a new ApplicationServer, using the provided
 // directory to persist state.
 func NewApplicationServer(ctx context.Context, upgrader upgrade.Backend, cfg *ApplicationConfig) (*ApplicationServer, error) {
@@ -310,6 +345,9 @@ type abciMux struct {
 	// debugExpiringTxs maps transaction hashes to the time at which they were created. This is only
 	// used in case CheckTx is disabled (for debug purposes only).
 	debugExpiringTxs map[hash.Hash]time.Time
+
+	// quarantine contains the transactions whose handlers panicked.
+	quarantine *txQuarantine
 }
 
 type invalidatedTxSubscription struct {
@@ -483,7 +521,7 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 	case nil:
 		// Everything ok.
 	case upgrade.ErrStopForUpgrade:
-		panic("mux: reached upgrade epoch")
+		panic("mux: reached upgrade boundary")
 	default:
 		panic(fmt.Sprintf("mux: error while trying to perform consensus upgrade: %v", err))
 	}
@@ -598,6 +636,27 @@ func (mux *abciMux) decodeTx(ctx *api.Context, rawTx []byte) (*transaction.Trans
 }
 
 func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txSize int) error {
+	// Reject expired transactions. The transaction is (going to be) executed
+	// in the block following the last committed one.
+	height := ctx.BlockHeight() + 1
+	expired := tx.IsExpiredAtHeight(height)
+	if !expired && tx.NotValidAfterEpoch != 0 {
+		epoch, err := mux.state.GetCurrentEpoch(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get current epoch: %w", err)
+		}
+		expired = epoch != epochtime.EpochInvalid && tx.IsExpiredAtEpoch(uint64(epoch))
+	}
+	if expired {
+		ctx.Logger().Debug("rejecting expired transaction",
+			"tx", tx,
+			"height", height,
+			"not_valid_after", tx.NotValidAfter,
+			"not_valid_after_epoch", tx.NotValidAfterEpoch,
+		)
+		return transaction.ErrExpired
+	}
+
 	// Pass the transaction through the fee handler if configured.
 	if txAuthHandler := mux.state.txAuthHandler; txAuthHandler != nil {
 		if err := txAuthHandler.AuthenticateTx(ctx, tx); err != nil {
@@ -651,16 +710,63 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 	return nil
 }
 
-func (mux *abciMux) executeTx(ctx *api.Context, rawTx []byte) error {
+func (mux *abciMux) executeTx(ctx *api.Context, rawTx []byte) (err error) {
 	tx, sigTx, err := mux.decodeTx(ctx, rawTx)
 	if err != nil {
 		return err
 	}
 
+	// Reject transactions whose handlers already panicked. As the quarantine
+	// is local node state, this must only be done in CheckTx.
+	txHash := hash.NewFromBytes(rawTx)
+	if ctx.IsCheckOnly() && mux.quarantine.IsQuarantined(txHash) {
+		return consensus.ErrTxQuarantined
+	}
+
 	// Set authenticated transaction signer.
 	ctx.SetTxSigner(sigTx.Signature.PublicKey)
 
-	return mux.processTx(ctx, tx, len(rawTx))
+	// Handler panics are deterministic, so they are turned into transaction
+	// failures instead of crashing the node. Any state updates and events of
+	// a panicking handler are discarded.
+	defer func() {
+		if p := recover(); p != nil {
+			err = mux.quarantineTx(ctx, txHash, tx, p)
+		}
+	}()
+	tc := ctx.StartTransactionCheckpoint()
+	defer tc.Close()
+
+	err = mux.processTx(ctx, tx, len(rawTx))
+	tc.Commit()
+	return err
+}
+
+// quarantineTx records a transaction whose handler panicked and returns the
+// error that the transaction fails with.
+func (mux *abciMux) quarantineTx(ctx *api.Context, txHash hash.Hash, tx *transaction.Transaction, p interface{}) error {
+	// Unavailable and/or corrupted state is specific to the local node, so
+	// the transaction may not fail on other nodes.
+	if err, ok := p.(error); ok && api.IsUnavailableStateError(err) {
+		panic(p)
+	}
+
+	ctx.Logger().Error("transaction handler panicked, quarantining transaction",
+		"tx_hash", txHash,
+		"method", tx.Method,
+		"panic", p,
+		"stack", string(debug.Stack()),
+	)
+	abciTxPanics.Inc()
+
+	mux.quarantine.Add(&consensus.QuarantinedTransaction{
+		Hash:   txHash,
+		Height: ctx.BlockHeight() + 1,
+		Method: tx.Method,
+		Panic:  fmt.Sprintf("%v", p),
+	})
+
+	return consensus.ErrTxPanicked
 }
 
 func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Transaction) (transaction.Gas, error) {
@@ -961,6 +1067,7 @@ func newABCIMux(ctx context.Context, upgrader upgrade.Backend, cfg *ApplicationC
 		appsByName:     make(map[string]Application),
 		appsByMethod:   make(map[transaction.MethodName]Application),
 		lastBeginBlock: -1,
+		quarantine:     newTxQuarantine(maxQuarantinedTxs),
 	}
 
 	// Create a map of expiring transactions if CheckTx is disabled (debug only).
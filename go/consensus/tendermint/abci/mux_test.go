@@ -0,0 +1,161 @@
+package abci
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	consensusGenesis "github.com/oasislabs/oasis-core/go/consensus/genesis"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	storageDB "github.com/oasislabs/oasis-core/go/storage/database"
+	"github.com/oasislabs/oasis-core/go/upgrade"
+)
+
+var (
+	testPanicKey = []byte("test/panic")
+
+	methodTestPanic = transaction.NewMethodName("test", "Panic", nil)
+)
+
+// testPanicApplication is an application whose transaction handler updates
+// state and emits an event before panicking.
+type testPanicApplication struct{}
+
+func (app *testPanicApplication) Name() string {
+	return "test"
+}
+
+func (app *testPanicApplication) ID() uint8 {
+	return 0xff
+}
+
+func (app *testPanicApplication) Methods() []transaction.MethodName {
+	return []transaction.MethodName{methodTestPanic}
+}
+
+func (app *testPanicApplication) Blessed() bool {
+	return false
+}
+
+func (app *testPanicApplication) Dependencies() []string {
+	return nil
+}
+
+func (app *testPanicApplication) QueryFactory() interface{} {
+	return nil
+}
+
+func (app *testPanicApplication) OnRegister(state api.ApplicationState) {
+}
+
+func (app *testPanicApplication) OnCleanup() {
+}
+
+func (app *testPanicApplication) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
+	if err := ctx.State().Insert(ctx, testPanicKey, []byte("value")); err != nil {
+		return err
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(testPanicKey, []byte("value")))
+
+	panic("test handler panic")
+}
+
+func (app *testPanicApplication) ForeignExecuteTx(ctx *api.Context, other Application, tx *transaction.Transaction) error {
+	return nil
+}
+
+func (app *testPanicApplication) InitChain(ctx *api.Context, req types.RequestInitChain, doc *genesis.Document) error {
+	return nil
+}
+
+func (app *testPanicApplication) BeginBlock(ctx *api.Context, req types.RequestBeginBlock) error {
+	return nil
+}
+
+func (app *testPanicApplication) EndBlock(ctx *api.Context, req types.RequestEndBlock) (types.ResponseEndBlock, error) {
+	return types.ResponseEndBlock{}, nil
+}
+
+func (app *testPanicApplication) FireTimer(ctx *api.Context, timer *Timer) error {
+	return nil
+}
+
+func newTestMux(t *testing.T) (*abciMux, func()) {
+	require := require.New(t)
+
+	dataDir, err := ioutil.TempDir("", "oasis-abci-mux-test_")
+	require.NoError(err, "TempDir")
+
+	mux, err := newABCIMux(context.Background(), upgrade.NewDummyUpgradeManager(), &ApplicationConfig{
+		DataDir:           dataDir,
+		StorageBackend:    storageDB.BackendNameBadgerDB,
+		MemoryOnlyStorage: true,
+	})
+	if err != nil {
+		os.RemoveAll(dataDir)
+	}
+	require.NoError(err, "newABCIMux")
+	mux.state.blockParams = &consensusGenesis.Parameters{}
+
+	return mux, func() {
+		mux.doCleanup()
+		os.RemoveAll(dataDir)
+	}
+}
+
+func TestEstimateGasExpiry(t *testing.T) {
+	require := require.New(t)
+
+	mux, cleanup := newTestMux(t)
+	defer cleanup()
+	caller := memorySigner.NewTestSigner("consensus/tendermint/abci: estimate gas caller").Public()
+
+	// Estimating gas for transactions that expire at an epoch must not
+	// access application state, which is unavailable during simulation.
+	tx := transaction.NewTransaction(0, nil, transaction.MethodName("test.Method"), nil)
+	tx.NotValidAfterEpoch = 1
+	require.NotPanics(func() {
+		_, err := mux.EstimateGas(caller, tx)
+		require.NoError(err, "EstimateGas")
+	}, "EstimateGas with epoch expiry")
+}
+
+func TestExecuteTxPanicDiscardsUpdates(t *testing.T) {
+	require := require.New(t)
+
+	signature.SetChainContext("test: oasis-core tests")
+	mux, cleanup := newTestMux(t)
+	defer cleanup()
+	err := mux.doRegister(&testPanicApplication{})
+	require.NoError(err, "doRegister")
+
+	signer := memorySigner.NewTestSigner("consensus/tendermint/abci: panic tx signer")
+	sigTx, err := transaction.Sign(signer, transaction.NewTransaction(0, nil, methodTestPanic, nil))
+	require.NoError(err, "Sign")
+	rawTx := cbor.Marshal(sigTx)
+
+	ctx := mux.state.NewContext(api.ContextDeliverTx, mux.currentTime)
+	defer ctx.Close()
+
+	err = mux.executeTx(ctx, rawTx)
+	require.Equal(consensus.ErrTxPanicked, err, "executeTx should fail with a panicked transaction")
+	require.True(mux.quarantine.IsQuarantined(hash.NewFromBytes(rawTx)), "transaction should be quarantined")
+
+	// Neither the state updates nor the events of the panicking handler
+	// should persist.
+	value, err := ctx.State().Get(ctx, testPanicKey)
+	require.NoError(err, "Get")
+	require.Nil(value, "state updates of a panicking handler should be discarded")
+	require.Empty(ctx.GetEvents(), "events of a panicking handler should be discarded")
+}
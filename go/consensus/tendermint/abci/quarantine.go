@@ -0,0 +1,73 @@
+package abci
+
+import (
+	"sync"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+// maxQuarantinedTxs is the maximum number of quarantined transactions. When
+// exceeded, the transactions that were quarantined first are released.
+const maxQuarantinedTxs = 1000
+
+// txQuarantine is a bounded in-memory list of transactions whose handlers
+// panicked on the local node.
+//
+// The quarantine is local node state, so it must only be used to reject
+// transactions in CheckTx and never influence block execution.
+type txQuarantine struct {
+	sync.RWMutex
+
+	maxTxs int
+
+	txs   map[hash.Hash]*consensus.QuarantinedTransaction
+	order []hash.Hash
+}
+
+// Add quarantines the given transaction.
+func (q *txQuarantine) Add(tx *consensus.QuarantinedTransaction) {
+	q.Lock()
+	defer q.Unlock()
+
+	if _, ok := q.txs[tx.Hash]; ok {
+		q.txs[tx.Hash] = tx
+		return
+	}
+
+	if len(q.order) >= q.maxTxs {
+		delete(q.txs, q.order[0])
+		q.order = q.order[1:]
+	}
+	q.txs[tx.Hash] = tx
+	q.order = append(q.order, tx.Hash)
+}
+
+// IsQuarantined returns true iff the given transaction is quarantined.
+func (q *txQuarantine) IsQuarantined(txHash hash.Hash) bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	_, ok := q.txs[txHash]
+	return ok
+}
+
+// List returns all quarantined transactions in the order in which they were
+// quarantined.
+func (q *txQuarantine) List() []*consensus.QuarantinedTransaction {
+	q.RLock()
+	defer q.RUnlock()
+
+	txs := make([]*consensus.QuarantinedTransaction, 0, len(q.order))
+	for _, txHash := range q.order {
+		txs = append(txs, q.txs[txHash])
+	}
+	return txs
+}
+
+func newTxQuarantine(maxTxs int) *txQuarantine {
+	return &txQuarantine{
+		maxTxs: maxTxs,
+		txs:    make(map[hash.Hash]*consensus.QuarantinedTransaction),
+	}
+}
@@ -0,0 +1,46 @@
+package abci
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+func TestTxQuarantine(t *testing.T) {
+	require := require.New(t)
+
+	q := newTxQuarantine(2)
+
+	var txs []*consensus.QuarantinedTransaction
+	for i := 0; i < 3; i++ {
+		txs = append(txs, &consensus.QuarantinedTransaction{
+			Hash:   hash.NewFromBytes([]byte(fmt.Sprintf("tx %d", i))),
+			Height: int64(i + 1),
+			Method: "test.Method",
+			Panic:  "test panic",
+		})
+	}
+
+	require.Empty(q.List(), "quarantine should be empty")
+	require.False(q.IsQuarantined(txs[0].Hash), "transaction should not be quarantined")
+
+	q.Add(txs[0])
+	q.Add(txs[1])
+	require.True(q.IsQuarantined(txs[0].Hash), "transaction should be quarantined")
+	require.True(q.IsQuarantined(txs[1].Hash), "transaction should be quarantined")
+	require.Equal(txs[:2], q.List())
+
+	// Quarantining an already quarantined transaction should not evict others.
+	q.Add(txs[1])
+	require.Equal(txs[:2], q.List())
+
+	// Exceeding the limit should release the oldest transaction.
+	q.Add(txs[2])
+	require.False(q.IsQuarantined(txs[0].Hash), "oldest transaction should be released")
+	require.True(q.IsQuarantined(txs[2].Hash), "transaction should be quarantined")
+	require.Equal(txs[1:], q.List())
+}
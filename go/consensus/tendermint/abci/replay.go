@@ -0,0 +1,183 @@
+package abci
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/abci/types"
+
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	storage "github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+// StateOp is a state operation observed during transaction replay.
+type StateOp string
+
+const (
+	// StateOpGet is a read of a single key.
+	StateOpGet StateOp = "get"
+	// StateOpIterate is a read of a key while iterating over the state.
+	StateOpIterate StateOp = "iterate"
+	// StateOpInsert is a write of a single key.
+	StateOpInsert StateOp = "insert"
+	// StateOpRemove is a removal of a single key.
+	StateOpRemove StateOp = "remove"
+)
+
+// StateTracer is a callback invoked on every state access performed while
+// replaying a transaction.
+//
+// For removals the value is the previous value, if known.
+type StateTracer func(op StateOp, key, value []byte)
+
+// MockReplayTx replays the given raw transaction, included in the block at
+// the given height, against the state at the preceding height and traces all
+// state accesses.
+//
+// Only the given transaction is replayed, so any effects of BeginBlock and
+// of preceding transactions in the same block are not taken into account.
+// The resulting state is discarded.
+func (mux *MockABCIMux) MockReplayTx(
+	ctx context.Context,
+	height int64,
+	now time.Time,
+	rawTx []byte,
+	tracer StateTracer,
+) (rsp *types.ResponseDeliverTx, err error) {
+	if height < 1 {
+		return nil, fmt.Errorf("mux: invalid replay height: %d", height)
+	}
+
+	s := mux.state
+	ndb := s.storage.NodeDB()
+	version := uint64(height - 1)
+	roots, err := ndb.GetRootsForVersion(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("mux: failed to get state roots: %w", err)
+	}
+	switch len(roots) {
+	case 0:
+		// No roots for that state -- it may have been pruned.
+		return nil, consensus.ErrVersionNotFound
+	case 1:
+	default:
+		return nil, fmt.Errorf("mux: incorrect number of roots (%d): %+v", version, roots)
+	}
+	root := storage.Root{
+		Version: version,
+		Hash:    roots[0],
+	}
+	tree := mkvs.NewWithRoot(nil, ndb, root, mkvs.WithoutWriteLog())
+	defer tree.Close()
+
+	// Switch the DeliverTx state over to the replayed height.
+	s.blockLock.Lock()
+	oldRoot, oldTree := s.stateRoot, s.deliverTxTree
+	s.stateRoot = root
+	s.deliverTxTree = tree
+	s.blockCtx = api.NewBlockContext()
+	if err = s.doCommitOrInitChainLocked(now); err != nil {
+		s.stateRoot, s.deliverTxTree = oldRoot, oldTree
+		s.blockLock.Unlock()
+		return nil, err
+	}
+	s.deliverTxTree = &tracingTree{Tree: tree, tracer: tracer}
+	s.blockLock.Unlock()
+	mux.currentTime = now
+
+	defer func() {
+		s.blockLock.Lock()
+		s.stateRoot, s.deliverTxTree = oldRoot, oldTree
+		s.blockLock.Unlock()
+
+		// Unavailable and/or corrupted state is reported by panicking.
+		if p := recover(); p != nil {
+			rsp = nil
+			err = fmt.Errorf("mux: transaction replay panicked: %v", p)
+		}
+	}()
+
+	resp := mux.DeliverTx(types.RequestDeliverTx{
+		Tx: rawTx,
+	})
+	return &resp, nil
+}
+
+// tracingTree is a state tree that reports all state accesses to a tracer.
+type tracingTree struct {
+	mkvs.Tree
+
+	tracer StateTracer
+}
+
+func (t *tracingTree) Get(ctx context.Context, key []byte) ([]byte, error) {
+	value, err := t.Tree.Get(ctx, key)
+	if err == nil {
+		t.tracer(StateOpGet, key, value)
+	}
+	return value, err
+}
+
+func (t *tracingTree) NewIterator(ctx context.Context, options ...mkvs.IteratorOption) mkvs.Iterator {
+	return &tracingIterator{
+		Iterator: t.Tree.NewIterator(ctx, options...),
+		tracer:   t.tracer,
+	}
+}
+
+func (t *tracingTree) Insert(ctx context.Context, key, value []byte) error {
+	err := t.Tree.Insert(ctx, key, value)
+	if err == nil {
+		t.tracer(StateOpInsert, key, value)
+	}
+	return err
+}
+
+func (t *tracingTree) RemoveExisting(ctx context.Context, key []byte) ([]byte, error) {
+	value, err := t.Tree.RemoveExisting(ctx, key)
+	if err == nil {
+		t.tracer(StateOpRemove, key, value)
+	}
+	return value, err
+}
+
+func (t *tracingTree) Remove(ctx context.Context, key []byte) error {
+	err := t.Tree.Remove(ctx, key)
+	if err == nil {
+		t.tracer(StateOpRemove, key, nil)
+	}
+	return err
+}
+
+// tracingIterator is a state iterator that reports all visited keys to a
+// tracer.
+type tracingIterator struct {
+	mkvs.Iterator
+
+	tracer StateTracer
+}
+
+func (it *tracingIterator) trace() {
+	if it.Iterator.Valid() {
+		it.tracer(StateOpIterate, it.Iterator.Key(), it.Iterator.Value())
+	}
+}
+
+func (it *tracingIterator) Rewind() {
+	it.Iterator.Rewind()
+	it.trace()
+}
+
+func (it *tracingIterator) Seek(key node.Key) {
+	it.Iterator.Seek(key)
+	it.trace()
+}
+
+func (it *tracingIterator) Next() {
+	it.Iterator.Next()
+	it.trace()
+}
@@ -0,0 +1,62 @@
+package abci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+)
+
+type traceEntry struct {
+	op    StateOp
+	key   string
+	value string
+}
+
+func TestTracingTree(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	var trace []traceEntry
+	tree := &tracingTree{
+		Tree: mkvs.New(nil, nil),
+		tracer: func(op StateOp, key, value []byte) {
+			trace = append(trace, traceEntry{op, string(key), string(value)})
+		},
+	}
+	defer tree.Close()
+
+	require.NoError(tree.Insert(ctx, []byte("a"), []byte("1")), "Insert")
+	require.NoError(tree.Insert(ctx, []byte("b"), []byte("2")), "Insert")
+	value, err := tree.Get(ctx, []byte("a"))
+	require.NoError(err, "Get")
+	require.EqualValues("1", value)
+
+	it := tree.NewIterator(ctx)
+	for it.Rewind(); it.Valid(); it.Next() {
+	}
+	it.Close()
+
+	_, err = tree.RemoveExisting(ctx, []byte("a"))
+	require.NoError(err, "RemoveExisting")
+	require.NoError(tree.Remove(ctx, []byte("b")), "Remove")
+
+	// Writes performed through an overlay should be traced on commit.
+	overlay := mkvs.NewOverlay(tree)
+	require.NoError(overlay.Insert(ctx, []byte("c"), []byte("3")), "Insert")
+	require.NoError(overlay.Commit(ctx), "Commit")
+	overlay.Close()
+
+	require.Equal([]traceEntry{
+		{StateOpInsert, "a", "1"},
+		{StateOpInsert, "b", "2"},
+		{StateOpGet, "a", "1"},
+		{StateOpIterate, "a", "1"},
+		{StateOpIterate, "b", "2"},
+		{StateOpRemove, "a", "1"},
+		{StateOpRemove, "b", ""},
+		{StateOpInsert, "c", "3"},
+	}, trace)
+}
@@ -25,6 +25,7 @@ import (
 	storage "github.com/oasislabs/oasis-core/go/storage/api"
 	storageDB "github.com/oasislabs/oasis-core/go/storage/database"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
 )
 
 var _ api.ApplicationState = (*applicationState)(nil)
@@ -47,6 +48,11 @@ type applicationState struct { // nolint: maligned
 	prunerClosedCh chan struct{}
 	prunerNotifyCh *channels.RingChannel
 
+	checkpointerCfg      CheckpointerConfig
+	checkpoints          checkpoint.Creator
+	checkpointerClosedCh chan struct{}
+	checkpointerNotifyCh *channels.RingChannel
+
 	blockLock   sync.RWMutex
 	blockTime   time.Time
 	blockCtx    *api.BlockContext
@@ -265,6 +271,10 @@ func (s *applicationState) doCommit(now time.Time) (uint64, error) {
 
 	// Notify pruner of a new block.
 	s.prunerNotifyCh.In() <- s.stateRoot.Version
+	// Notify checkpointer of a new block.
+	if s.checkpoints != nil {
+		s.checkpointerNotifyCh.In() <- s.stateRoot.Version
+	}
 	// Discover the version below which all versions can be discarded from block history.
 	lastRetainedVersion := s.statePruner.GetLastRetainedVersion()
 
@@ -293,6 +303,9 @@ func (s *applicationState) doCleanup() {
 		s.cancelCtx()
 		<-s.prunerClosedCh
 		<-s.metricsClosedCh
+		if s.checkpoints != nil {
+			<-s.checkpointerClosedCh
+		}
 
 		s.storage.Cleanup()
 		s.storage = nil
@@ -460,6 +473,15 @@ func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*applicat
 		return nil, fmt.Errorf("state: failed to create pruner: %w", err)
 	}
 
+	// Initialize the state checkpoint creator, if enabled.
+	var checkpoints checkpoint.Creator
+	if cfg.Checkpointer.Interval > 0 {
+		checkpoints, err = checkpoint.NewFileCreator(filepath.Join(cfg.DataDir, appStateDir, checkpointsDir), ndb)
+		if err != nil {
+			return nil, fmt.Errorf("state: failed to create checkpoint creator: %w", err)
+		}
+	}
+
 	var minGasPrice quantity.Quantity
 	if err = minGasPrice.FromInt64(int64(cfg.MinGasPrice)); err != nil {
 		return nil, fmt.Errorf("state: invalid minimum gas price: %w", err)
@@ -468,21 +490,25 @@ func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*applicat
 	ctx, cancelCtx := context.WithCancel(ctx)
 
 	s := &applicationState{
-		logger:          logging.GetLogger("abci-mux/state"),
-		ctx:             ctx,
-		cancelCtx:       cancelCtx,
-		deliverTxTree:   deliverTxTree,
-		checkTxTree:     checkTxTree,
-		stateRoot:       *stateRoot,
-		storage:         ldb,
-		statePruner:     statePruner,
-		prunerClosedCh:  make(chan struct{}),
-		prunerNotifyCh:  channels.NewRingChannel(1),
-		haltEpochHeight: cfg.HaltEpochHeight,
-		minGasPrice:     minGasPrice,
-		ownTxSigner:     cfg.OwnTxSigner,
-		disableCheckTx:  cfg.DisableCheckTx,
-		metricsClosedCh: make(chan struct{}),
+		logger:               logging.GetLogger("abci-mux/state"),
+		ctx:                  ctx,
+		cancelCtx:            cancelCtx,
+		deliverTxTree:        deliverTxTree,
+		checkTxTree:          checkTxTree,
+		stateRoot:            *stateRoot,
+		storage:              ldb,
+		statePruner:          statePruner,
+		prunerClosedCh:       make(chan struct{}),
+		prunerNotifyCh:       channels.NewRingChannel(1),
+		checkpointerCfg:      cfg.Checkpointer,
+		checkpoints:          checkpoints,
+		checkpointerClosedCh: make(chan struct{}),
+		checkpointerNotifyCh: channels.NewRingChannel(1),
+		haltEpochHeight:      cfg.HaltEpochHeight,
+		minGasPrice:          minGasPrice,
+		ownTxSigner:          cfg.OwnTxSigner,
+		disableCheckTx:       cfg.DisableCheckTx,
+		metricsClosedCh:      make(chan struct{}),
 	}
 
 	// Refresh consensus parameters when loading state if we are past genesis.
@@ -494,6 +520,9 @@ func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*applicat
 
 	go s.metricsWorker()
 	go s.pruneWorker()
+	if checkpoints != nil {
+		go s.checkpointWorker()
+	}
 
 	return s, nil
 }
@@ -74,6 +74,7 @@ type Context struct {
 	blockCtx    *BlockContext
 
 	stateCheckpoint *StateCheckpoint
+	txCheckpoint    *TransactionCheckpoint
 
 	logger *logging.Logger
 }
@@ -126,7 +127,7 @@ func (c *Context) Close() {
 	c.blockCtx = nil
 	c.Context = nil
 
-	if c.stateCheckpoint != nil {
+	if c.stateCheckpoint != nil || c.txCheckpoint != nil {
 		panic("context: open checkpoint was never committed or discarded")
 	}
 }
@@ -248,6 +249,9 @@ func (c *Context) State() mkvs.KeyValueTree {
 	if c.stateCheckpoint != nil {
 		return c.stateCheckpoint.overlay
 	}
+	if c.txCheckpoint != nil {
+		return c.txCheckpoint.overlay
+	}
 	return c.state
 }
 
@@ -287,9 +291,11 @@ func (c *Context) StartCheckpoint() *StateCheckpoint {
 	if c.stateCheckpoint != nil {
 		panic("context: nested checkpoints are not allowed")
 	}
+	parent := c.State()
 	c.stateCheckpoint = &StateCheckpoint{
 		ctx:     c,
-		overlay: mkvs.NewOverlay(c.state),
+		parent:  parent,
+		overlay: mkvs.NewSpeculative(parent),
 	}
 	return c.stateCheckpoint
 }
@@ -297,7 +303,8 @@ func (c *Context) StartCheckpoint() *StateCheckpoint {
 // StateCheckpoint is a state checkpoint that can be used to rollback state.
 type StateCheckpoint struct {
 	ctx     *Context
-	overlay mkvs.OverlayTree
+	parent  mkvs.KeyValueTree
+	overlay mkvs.SpeculativeTree
 }
 
 // Close releases resources associated with the checkpoint without committing it.
@@ -315,12 +322,69 @@ func (sc *StateCheckpoint) Commit() {
 	if sc.ctx == nil {
 		return
 	}
-	if err := sc.overlay.Commit(sc.ctx); err != nil {
+	if err := sc.overlay.Promote(sc.ctx, sc.parent); err != nil {
 		panic(fmt.Errorf("context: failed to commit checkpoint: %w", err))
 	}
 	sc.Close()
 }
 
+// StartTransactionCheckpoint starts a new transaction checkpoint. Any further updates to the
+// context's state and any further emitted events will be discarded unless the checkpoint is
+// explicitly committed.
+//
+// The transaction checkpoint is meant to be used by the transaction dispatcher, so transaction
+// handlers can still start (non-nested) state checkpoints while it is open.
+//
+// The caller must make sure to call either Close or Commit on the checkpoint, otherwise this will
+// leak resources.
+func (c *Context) StartTransactionCheckpoint() *TransactionCheckpoint {
+	if c.stateCheckpoint != nil || c.txCheckpoint != nil {
+		panic("context: nested checkpoints are not allowed")
+	}
+	c.txCheckpoint = &TransactionCheckpoint{
+		ctx:       c,
+		overlay:   mkvs.NewSpeculative(c.state),
+		numEvents: len(c.events),
+	}
+	return c.txCheckpoint
+}
+
+// TransactionCheckpoint is a checkpoint that can be used to rollback state and events emitted by
+// a transaction.
+type TransactionCheckpoint struct {
+	ctx       *Context
+	overlay   mkvs.SpeculativeTree
+	numEvents int
+}
+
+// Close discards any changes performed and any events emitted since the checkpoint was created.
+func (tc *TransactionCheckpoint) Close() {
+	if tc.ctx == nil {
+		return
+	}
+	if tc.ctx.stateCheckpoint != nil {
+		// Handlers that panicked may not have closed their state checkpoints.
+		tc.ctx.stateCheckpoint.Close()
+	}
+	tc.overlay.Close()
+	tc.ctx.events = tc.ctx.events[:tc.numEvents]
+	tc.ctx.txCheckpoint = nil
+	tc.ctx = nil
+}
+
+// Commit commits any changes performed and keeps any events emitted since the checkpoint was
+// created.
+func (tc *TransactionCheckpoint) Commit() {
+	if tc.ctx == nil {
+		return
+	}
+	if err := tc.overlay.Promote(tc.ctx, tc.ctx.state); err != nil {
+		panic(fmt.Errorf("context: failed to commit transaction checkpoint: %w", err))
+	}
+	tc.numEvents = len(tc.ctx.events)
+	tc.Close()
+}
+
 // BlockContextKey is an interface for a block context key.
 type BlockContextKey interface {
 	// NewDefault returns a new default value for the given key.
@@ -74,6 +74,63 @@ func TestContext(t *testing.T) {
 	ctx.Close()
 }
 
+func TestTransactionCheckpoint(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := NewMockApplicationState(MockApplicationStateConfig{})
+	ctx := appState.NewContext(ContextDeliverTx, now)
+	defer ctx.Close()
+
+	tree := ctx.State()
+	err := tree.Insert(ctx, []byte("key"), []byte("value"))
+	require.NoError(err, "Insert")
+	ctx.EmitEvent(NewEventBuilder("test").Attribute([]byte("before"), []byte("value")))
+
+	tc := ctx.StartTransactionCheckpoint()
+	// Should panic on nested transaction checkpoints.
+	require.Panics(func() { ctx.StartTransactionCheckpoint() })
+
+	err = ctx.State().Insert(ctx, []byte("key"), []byte("tx"))
+	require.NoError(err, "Insert")
+	ctx.EmitEvent(NewEventBuilder("test").Attribute([]byte("tx"), []byte("value")))
+
+	// State checkpoints should be allowed within a transaction checkpoint and
+	// commit into it.
+	cp := ctx.StartCheckpoint()
+	err = ctx.State().Insert(ctx, []byte("blah"), []byte("value2"))
+	require.NoError(err, "Insert")
+	cp.Commit()
+	value, err := ctx.State().Get(ctx, []byte("blah"))
+	require.NoError(err, "Get")
+	require.EqualValues([]byte("value2"), value, "state checkpoint should commit into the transaction checkpoint")
+	value, err = tree.Get(ctx, []byte("blah"))
+	require.NoError(err, "Get")
+	require.Nil(value, "updates should not leak outside transaction checkpoint")
+
+	// Discard the transaction checkpoint.
+	tc.Close()
+	value, err = tree.Get(ctx, []byte("key"))
+	require.NoError(err, "Get")
+	require.EqualValues([]byte("value"), value, "updates should have been discarded")
+	value, err = tree.Get(ctx, []byte("blah"))
+	require.NoError(err, "Get")
+	require.Nil(value, "updates should have been discarded")
+	require.Len(ctx.GetEvents(), 1, "events should have been discarded")
+	require.False(ctx.HasEvent("test", []byte("tx")), "events should have been discarded")
+
+	// Commit a transaction checkpoint.
+	tc = ctx.StartTransactionCheckpoint()
+	err = ctx.State().Insert(ctx, []byte("key"), []byte("tx"))
+	require.NoError(err, "Insert")
+	ctx.EmitEvent(NewEventBuilder("test").Attribute([]byte("tx"), []byte("value")))
+	tc.Commit()
+	value, err = tree.Get(ctx, []byte("key"))
+	require.NoError(err, "Get")
+	require.EqualValues([]byte("tx"), value, "updates should have been applied")
+	require.True(ctx.HasEvent("test", []byte("tx")), "events should have been kept")
+}
+
 type testBlockContextKey struct{}
 
 func (k testBlockContextKey) NewDefault() interface{} {
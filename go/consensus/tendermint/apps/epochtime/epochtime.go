@@ -0,0 +1,177 @@
+// Package epochtime implements the epochtime application.
+package epochtime
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+)
+
+var _ abci.Application = (*epochTimeApplication)(nil)
+
+type epochTimeApplication struct {
+	state api.ApplicationState
+}
+
+func (app *epochTimeApplication) Name() string {
+	return AppName
+}
+
+func (app *epochTimeApplication) ID() uint8 {
+	return AppID
+}
+
+func (app *epochTimeApplication) Methods() []transaction.MethodName {
+	return epochtime.Methods
+}
+
+func (app *epochTimeApplication) Blessed() bool {
+	return false
+}
+
+func (app *epochTimeApplication) Dependencies() []string {
+	return nil
+}
+
+func (app *epochTimeApplication) OnRegister(state api.ApplicationState) {
+	app.state = state
+}
+
+func (app *epochTimeApplication) OnCleanup() {
+}
+
+func (app *epochTimeApplication) InitChain(ctx *api.Context, request types.RequestInitChain, doc *genesis.Document) error {
+	st := doc.EpochTime
+	state := newMutableState(ctx.State())
+
+	if err := state.setConsensusParameters(ctx, &st.Parameters); err != nil {
+		return fmt.Errorf("epochtime: failed to set consensus parameters: %w", err)
+	}
+	schedule := epochtime.NewIntervalSchedule(st.Base, st.Parameters.Interval)
+	if err := state.setIntervalSchedule(ctx, schedule); err != nil {
+		return fmt.Errorf("epochtime: failed to set interval schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (app *epochTimeApplication) BeginBlock(ctx *api.Context, request types.RequestBeginBlock) error {
+	return nil
+}
+
+func (app *epochTimeApplication) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
+	state := newMutableState(ctx.State())
+
+	switch tx.Method {
+	case epochtime.MethodUpdateParameters:
+		var upd epochtime.UpdateParameters
+		if err := cbor.Unmarshal(tx.Body, &upd); err != nil {
+			return epochtime.ErrInvalidArgument
+		}
+
+		return app.updateParameters(ctx, state, &upd)
+	default:
+		return fmt.Errorf("epochtime: invalid method: %s", tx.Method)
+	}
+}
+
+func (app *epochTimeApplication) ForeignExecuteTx(ctx *api.Context, other abci.Application, tx *transaction.Transaction) error {
+	return nil
+}
+
+func (app *epochTimeApplication) EndBlock(ctx *api.Context, request types.RequestEndBlock) (types.ResponseEndBlock, error) {
+	return types.ResponseEndBlock{}, nil
+}
+
+func (app *epochTimeApplication) FireTimer(ctx *api.Context, timer *abci.Timer) error {
+	return fmt.Errorf("tendermint/epochtime: unexpected timer")
+}
+
+func (app *epochTimeApplication) updateParameters(
+	ctx *api.Context,
+	state *mutableState,
+	upd *epochtime.UpdateParameters,
+) error {
+	params, err := state.consensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("UpdateParameters: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, epochtime.GasOpUpdateParameters, params.GasCosts); err != nil {
+		return err
+	}
+
+	if !params.IsParameterUpdateAuthority(ctx.TxSigner()) {
+		ctx.Logger().Error("UpdateParameters: signer is not a parameter update authority",
+			"signer", ctx.TxSigner(),
+		)
+		return epochtime.ErrForbidden
+	}
+	if upd.Interval <= 0 {
+		return fmt.Errorf("%w: epoch interval must be > 0", epochtime.ErrInvalidArgument)
+	}
+
+	schedule, err := state.intervalSchedule(ctx)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return fmt.Errorf("epochtime: interval schedule not initialized")
+	}
+
+	height := ctx.BlockHeight() + 1
+	last := schedule.Last()
+	if last.Height > height {
+		return epochtime.ErrIntervalChangePending
+	}
+	if last.Interval == upd.Interval {
+		return fmt.Errorf("%w: epoch interval unchanged", epochtime.ErrInvalidArgument)
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// The new interval takes effect at the first epoch boundary after the
+	// next block so that the epoch of any block that is being processed
+	// before the change is committed remains the same.
+	boundary := last.NextBoundary(height + 1)
+	change := epochtime.IntervalChange{
+		Height:   boundary,
+		Epoch:    last.EpochAt(boundary),
+		Interval: upd.Interval,
+	}
+
+	ctx.Logger().Info("scheduling epoch interval change",
+		"interval", change.Interval,
+		"epoch", change.Epoch,
+		"height", change.Height,
+		"current_height", height,
+	)
+
+	params.Interval = upd.Interval
+	if err = state.setConsensusParameters(ctx, params); err != nil {
+		return fmt.Errorf("epochtime: failed to set consensus parameters: %w", err)
+	}
+	if err = state.setIntervalSchedule(ctx, append(schedule, change)); err != nil {
+		return fmt.Errorf("epochtime: failed to set interval schedule: %w", err)
+	}
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyIntervalChange, cbor.Marshal(change)))
+
+	return nil
+}
+
+// New constructs a new epochtime application instance.
+func New() abci.Application {
+	return &epochTimeApplication{}
+}
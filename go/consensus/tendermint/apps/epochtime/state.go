@@ -0,0 +1,95 @@
+package epochtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/keyformat"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	"github.com/oasislabs/oasis-core/go/epochtime/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+)
+
+var (
+	// parametersKeyFmt is the key format used for consensus parameters.
+	//
+	// Value is CBOR-serialized epochtime.ConsensusParameters.
+	parametersKeyFmt = keyformat.New(0x32)
+	// intervalScheduleKeyFmt is the epoch interval schedule key format.
+	//
+	// Value is CBOR-serialized epochtime.IntervalSchedule.
+	intervalScheduleKeyFmt = keyformat.New(0x33)
+)
+
+type immutableState struct {
+	is *abciAPI.ImmutableState
+}
+
+func (s *immutableState) consensusParameters(ctx context.Context) (*api.ConsensusParameters, error) {
+	data, err := s.is.Get(ctx, parametersKeyFmt.Encode())
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if data == nil {
+		return nil, fmt.Errorf("tendermint/epochtime: expected consensus parameters to be present in app state")
+	}
+
+	var params api.ConsensusParameters
+	if err = cbor.Unmarshal(data, &params); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &params, nil
+}
+
+// intervalSchedule returns the epoch interval schedule or nil in case the
+// schedule has not been initialized.
+func (s *immutableState) intervalSchedule(ctx context.Context) (api.IntervalSchedule, error) {
+	data, err := s.is.Get(ctx, intervalScheduleKeyFmt.Encode())
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var schedule api.IntervalSchedule
+	if err = cbor.Unmarshal(data, &schedule); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return schedule, nil
+}
+
+func newImmutableState(ctx context.Context, state abciAPI.ApplicationQueryState, version int64) (*immutableState, error) {
+	is, err := abciAPI.NewImmutableState(ctx, state, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &immutableState{is}, nil
+}
+
+type mutableState struct {
+	*immutableState
+
+	ms mkvs.KeyValueTree
+}
+
+func (s *mutableState) setConsensusParameters(ctx context.Context, params *api.ConsensusParameters) error {
+	err := s.ms.Insert(ctx, parametersKeyFmt.Encode(), cbor.Marshal(params))
+	return abciAPI.UnavailableStateError(err)
+}
+
+func (s *mutableState) setIntervalSchedule(ctx context.Context, schedule api.IntervalSchedule) error {
+	err := s.ms.Insert(ctx, intervalScheduleKeyFmt.Encode(), cbor.Marshal(schedule))
+	return abciAPI.UnavailableStateError(err)
+}
+
+func newMutableState(tree mkvs.KeyValueTree) *mutableState {
+	return &mutableState{
+		immutableState: &immutableState{
+			&abciAPI.ImmutableState{ImmutableKeyValueTree: tree},
+		},
+		ms: tree,
+	}
+}
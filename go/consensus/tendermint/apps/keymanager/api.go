@@ -22,4 +22,8 @@ var (
 	// KeyStatusUpdate is an ABCI event attribute key for a key manager
 	// status update (value is a CBOR serialized key manager status).
 	KeyStatusUpdate = []byte("status")
+
+	// KeyPolicyUpdate is an ABCI event attribute key for a key manager
+	// policy update (value is a CBOR serialized signed SGX policy).
+	KeyPolicyUpdate = []byte("policy_update")
 )
@@ -9,7 +9,6 @@ import (
 	"github.com/tendermint/tendermint/abci/types"
 
 	"github.com/oasislabs/oasis-core/go/common"
-	"github.com/oasislabs/oasis-core/go/common/cbor"
 	tmapi "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	keymanagerState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/keymanager/state"
 	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
@@ -44,8 +43,15 @@ func (app *keymanagerApplication) InitChain(ctx *tmapi.Context, request types.Re
 		}
 	}
 
-	var toEmit []*keymanager.Status
 	state := keymanagerState.NewMutableState(ctx.State())
+	if err := st.Parameters.SanityCheck(); err != nil {
+		return fmt.Errorf("tendermint/keymanager: sanity check failed: %w", err)
+	}
+	if err := state.SetConsensusParameters(ctx, &st.Parameters); err != nil {
+		return fmt.Errorf("tendermint/keymanager: failed to set consensus parameters: %w", err)
+	}
+
+	var toEmit []*keymanager.Status
 	for i, v := range st.Statuses {
 		if v == nil {
 			return fmt.Errorf("InitChain: Status index %d is nil", i)
@@ -78,7 +84,9 @@ func (app *keymanagerApplication) InitChain(ctx *tmapi.Context, request types.Re
 	}
 
 	if len(toEmit) > 0 {
-		ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal(toEmit)))
+		if err := app.emitStatusUpdates(ctx, state, toEmit); err != nil {
+			return fmt.Errorf("tendermint/keymanager: %w", err)
+		}
 	}
 
 	return nil
@@ -95,6 +103,14 @@ func (kq *keymanagerQuerier) Genesis(ctx context.Context) (*keymanager.Genesis,
 		status.Nodes = nil
 	}
 
-	gen := keymanager.Genesis{Statuses: statuses}
+	params, err := kq.state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := keymanager.Genesis{
+		Parameters: *params,
+		Statuses:   statuses,
+	}
 	return &gen, nil
 }
@@ -157,7 +157,7 @@ func (app *keymanagerApplication) onEpochChange(ctx *tmapi.Context, epoch epocht
 			return fmt.Errorf("failed to query key manager status: %w", err)
 		}
 
-		newStatus := app.generateStatus(ctx, rt, oldStatus, nodes)
+		newStatus := app.generateStatus(ctx, rt, oldStatus, nodes, epoch)
 		if forceEmit || !bytes.Equal(cbor.Marshal(oldStatus), cbor.Marshal(newStatus)) {
 			ctx.Logger().Debug("status updated",
 				"id", newStatus.ID,
@@ -180,13 +180,36 @@ func (app *keymanagerApplication) onEpochChange(ctx *tmapi.Context, epoch epocht
 
 	// Emit the update event if required.
 	if len(toEmit) > 0 {
-		ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal(toEmit)))
+		if err = app.emitStatusUpdates(ctx, state, toEmit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// emitStatusUpdates records the given key manager status updates so that they
+// can be replayed later and emits the corresponding status update event.
+func (app *keymanagerApplication) emitStatusUpdates(ctx *tmapi.Context, state *keymanagerState.MutableState, statuses []*api.Status) error {
+	// Updates take effect in the block that is currently being executed.
+	height := ctx.BlockHeight() + 1
+	for _, status := range statuses {
+		if err := state.AddStatusUpdate(ctx, height, status); err != nil {
+			return fmt.Errorf("failed to record key manager status update: %w", err)
+		}
 	}
 
+	ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal(statuses)))
 	return nil
 }
 
-func (app *keymanagerApplication) generateStatus(ctx *tmapi.Context, kmrt *registry.Runtime, oldStatus *api.Status, nodes []*node.Node) *api.Status {
+func (app *keymanagerApplication) generateStatus(
+	ctx *tmapi.Context,
+	kmrt *registry.Runtime,
+	oldStatus *api.Status,
+	nodes []*node.Node,
+	epoch epochtime.EpochTime,
+) *api.Status {
 	status := &api.Status{
 		ID:            kmrt.ID,
 		IsInitialized: oldStatus.IsInitialized,
@@ -231,7 +254,7 @@ func (app *keymanagerApplication) generateStatus(ctx *tmapi.Context, kmrt *regis
 			continue
 		}
 
-		initResponse, err := api.VerifyExtraInfo(ctx.Logger(), kmrt, nodeRt, ctx.Now())
+		initResponse, err := api.VerifyExtraInfo(ctx.Logger(), kmrt, nodeRt, ctx.Now(), epoch)
 		if err != nil {
 			ctx.Logger().Error("failed to validate ExtraInfo",
 				"err", err,
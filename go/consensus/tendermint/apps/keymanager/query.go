@@ -13,6 +13,7 @@ import (
 type Query interface {
 	Status(context.Context, common.Namespace) (*keymanager.Status, error)
 	Statuses(context.Context) ([]*keymanager.Status, error)
+	StatusUpdates(context.Context, int64) ([]*keymanager.AnnotatedStatus, error)
 	Genesis(context.Context) (*keymanager.Genesis, error)
 }
 
@@ -42,6 +43,10 @@ func (kq *keymanagerQuerier) Statuses(ctx context.Context) ([]*keymanager.Status
 	return kq.state.Statuses(ctx)
 }
 
+func (kq *keymanagerQuerier) StatusUpdates(ctx context.Context, fromHeight int64) ([]*keymanager.AnnotatedStatus, error) {
+	return kq.state.StatusUpdates(ctx, fromHeight)
+}
+
 func (app *keymanagerApplication) QueryFactory() interface{} {
 	return &QueryFactory{app.state}
 }
@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"sort"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
@@ -16,6 +17,16 @@ var (
 	//
 	// Value is CBOR-serialized key manager status.
 	statusKeyFmt = keyformat.New(0x70, keyformat.H(&common.Namespace{}))
+	// statusUpdateKeyFmt is the key manager status update key format.
+	//
+	// Only the latest status update is kept for each key manager runtime.
+	//
+	// Value is CBOR-serialized annotated key manager status.
+	statusUpdateKeyFmt = keyformat.New(0x71, keyformat.H(&common.Namespace{}))
+	// parametersKeyFmt is the key format used for consensus parameters.
+	//
+	// Value is CBOR-serialized api.ConsensusParameters.
+	parametersKeyFmt = keyformat.New(0x72)
 )
 
 // ImmutableState is the immutable key manager state wrapper.
@@ -74,6 +85,57 @@ func (st *ImmutableState) Status(ctx context.Context, id common.Namespace) (*api
 	return &status, nil
 }
 
+// StatusUpdates returns the latest status update of each key manager runtime
+// that was updated at or after the given height, ordered by height.
+func (st *ImmutableState) StatusUpdates(ctx context.Context, fromHeight int64) ([]*api.AnnotatedStatus, error) {
+	it := st.is.NewIterator(ctx)
+	defer it.Close()
+
+	var updates []*api.AnnotatedStatus
+	for it.Seek(statusUpdateKeyFmt.Encode()); it.Valid(); it.Next() {
+		if !statusUpdateKeyFmt.Decode(it.Key()) {
+			break
+		}
+
+		var update api.AnnotatedStatus
+		if err := cbor.Unmarshal(it.Value(), &update); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		if update.Height < fromHeight {
+			continue
+		}
+		updates = append(updates, &update)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].Height < updates[j].Height
+	})
+	return updates, nil
+}
+
+// ConsensusParameters returns the key manager consensus parameters.
+//
+// In case the parameters are not present in the state (e.g., state created
+// before the parameters were introduced), the default parameters are returned.
+func (st *ImmutableState) ConsensusParameters(ctx context.Context) (*api.ConsensusParameters, error) {
+	raw, err := st.is.Get(ctx, parametersKeyFmt.Encode())
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if raw == nil {
+		return &api.ConsensusParameters{}, nil
+	}
+
+	var params api.ConsensusParameters
+	if err = cbor.Unmarshal(raw, &params); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &params, nil
+}
+
 func NewImmutableState(ctx context.Context, state abciAPI.ApplicationQueryState, version int64) (*ImmutableState, error) {
 	is, err := abciAPI.NewImmutableState(ctx, state, version)
 	if err != nil {
@@ -94,6 +156,24 @@ func (st *MutableState) SetStatus(ctx context.Context, status *api.Status) error
 	return abciAPI.UnavailableStateError(err)
 }
 
+// AddStatusUpdate records a key manager status update at the given height so
+// that it can later be replayed by subscribers, replacing any previous status
+// update for the same key manager runtime.
+func (st *MutableState) AddStatusUpdate(ctx context.Context, height int64, status *api.Status) error {
+	update := api.AnnotatedStatus{
+		Height: height,
+		Status: status,
+	}
+	err := st.ms.Insert(ctx, statusUpdateKeyFmt.Encode(&status.ID), cbor.Marshal(update))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// SetConsensusParameters sets key manager consensus parameters.
+func (st *MutableState) SetConsensusParameters(ctx context.Context, params *api.ConsensusParameters) error {
+	err := st.ms.Insert(ctx, parametersKeyFmt.Encode(), cbor.Marshal(params))
+	return abciAPI.UnavailableStateError(err)
+}
+
 // NewMutableState creates a new mutable key manager state wrapper.
 func NewMutableState(tree mkvs.KeyValueTree) *MutableState {
 	return &MutableState{
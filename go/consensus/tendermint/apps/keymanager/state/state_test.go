@@ -0,0 +1,94 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	"github.com/oasislabs/oasis-core/go/keymanager/api"
+)
+
+func TestStatusUpdates(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	id1 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/keymanager/state: km 1"), 0)
+	id2 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/keymanager/state: km 2"), 0)
+
+	updates, err := s.StatusUpdates(ctx, 1)
+	require.NoError(err, "StatusUpdates")
+	require.Empty(updates, "there should be no status updates")
+
+	err = s.AddStatusUpdate(ctx, 5, &api.Status{ID: id1})
+	require.NoError(err, "AddStatusUpdate")
+	err = s.AddStatusUpdate(ctx, 10, &api.Status{ID: id1, IsInitialized: true})
+	require.NoError(err, "AddStatusUpdate")
+	err = s.AddStatusUpdate(ctx, 10, &api.Status{ID: id2})
+	require.NoError(err, "AddStatusUpdate")
+	err = s.AddStatusUpdate(ctx, 300, &api.Status{ID: id2, IsSecure: true})
+	require.NoError(err, "AddStatusUpdate")
+
+	updates, err = s.StatusUpdates(ctx, 1)
+	require.NoError(err, "StatusUpdates")
+	require.Len(updates, 2, "only the latest status update of each runtime should be kept")
+	require.EqualValues(10, updates[0].Height)
+	require.True(updates[0].Status.ID.Equal(&id1), "status update should be for the correct runtime")
+	require.True(updates[0].Status.IsInitialized, "status update should have the correct status")
+	require.EqualValues(300, updates[1].Height)
+	require.True(updates[1].Status.ID.Equal(&id2), "status update should be for the correct runtime")
+
+	updates, err = s.StatusUpdates(ctx, 10)
+	require.NoError(err, "StatusUpdates")
+	require.Len(updates, 2, "status updates at or after the given height should be returned")
+
+	updates, err = s.StatusUpdates(ctx, 11)
+	require.NoError(err, "StatusUpdates")
+	require.Len(updates, 1, "status updates at or after the given height should be returned")
+	require.EqualValues(300, updates[0].Height)
+	require.True(updates[0].Status.ID.Equal(&id2), "status update should be for the correct runtime")
+	require.True(updates[0].Status.IsSecure, "status update should have the correct status")
+
+	// Multiple updates for the same runtime at the same height should only keep the last one.
+	err = s.AddStatusUpdate(ctx, 300, &api.Status{ID: id2})
+	require.NoError(err, "AddStatusUpdate")
+	updates, err = s.StatusUpdates(ctx, 300)
+	require.NoError(err, "StatusUpdates")
+	require.Len(updates, 1)
+	require.False(updates[0].Status.IsSecure, "last status update at a given height should win")
+}
+
+func TestConsensusParameters(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	p, err := s.ConsensusParameters(ctx)
+	require.NoError(err, "ConsensusParameters without parameters")
+	require.Equal(&api.ConsensusParameters{}, p, "ConsensusParameters should default without parameters")
+
+	params := &api.ConsensusParameters{
+		PolicySigners:         []signature.PublicKey{api.TestSigners[1].Public(), api.TestSigners[2].Public()},
+		PolicySignerThreshold: 2,
+	}
+	err = s.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	p, err = s.ConsensusParameters(ctx)
+	require.NoError(err, "ConsensusParameters")
+	require.EqualValues(params, p, "consensus parameters should round-trip")
+}
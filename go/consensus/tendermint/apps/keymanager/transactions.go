@@ -48,6 +48,13 @@ func (app *keymanagerApplication) updatePolicy(
 	if err = api.SanityCheckSignedPolicySGX(oldStatus.Policy, sigPol); err != nil {
 		return err
 	}
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return err
+	}
+	if err = sigPol.VerifyQuorum(params); err != nil {
+		return err
+	}
 
 	if ctx.IsCheckOnly() {
 		return nil
@@ -73,15 +80,22 @@ func (app *keymanagerApplication) updatePolicy(
 	// TODO: It would be possible to update the cohort on each
 	// node-reregistration, but I'm not sure how often the policy
 	// will get updated.
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
 	nodes, _ := regState.Nodes(ctx)
 	registry.SortNodeList(nodes)
 	oldStatus.Policy = sigPol
-	newStatus := app.generateStatus(ctx, rt, oldStatus, nodes)
+	newStatus := app.generateStatus(ctx, rt, oldStatus, nodes, epoch)
 	if err := state.SetStatus(ctx, newStatus); err != nil {
 		panic(fmt.Errorf("failed to set keymanager status: %w", err))
 	}
 
-	ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal([]*api.Status{newStatus})))
+	ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyPolicyUpdate, cbor.Marshal(sigPol)))
+	if err := app.emitStatusUpdates(ctx, state, []*api.Status{newStatus}); err != nil {
+		panic(err)
+	}
 
 	return nil
 }
@@ -47,6 +47,11 @@ var (
 	// become unfrozen (value is CBOR serialized node ID).
 	KeyNodeUnfrozen = []byte("nodes.unfrozen")
 
+	// KeyRuntimeOwnership is the ABCI event attribute for runtime
+	// ownership transfer offers, cancellations and completions (value is
+	// a CBOR serialized registry.RuntimeOwnershipEvent).
+	KeyRuntimeOwnership = []byte("runtime.ownership")
+
 	// KeyRegistryNodeListEpoch is the ABCI event attribute for
 	// registry epochs.
 	KeyRegistryNodeListEpoch = []byte("nodes.epoch")
@@ -91,6 +91,25 @@ func (app *registryApplication) InitChain(ctx *abciAPI.Context, request types.Re
 			return fmt.Errorf("registry: failed to suspend runtime at genesis: %w", err)
 		}
 	}
+	for _, id := range st.GovernanceSuspendedRuntimes {
+		if _, err := state.SuspendedRuntime(ctx, id); err != nil {
+			return fmt.Errorf("registry: genesis governance suspended runtime %s is not suspended: %w", id, err)
+		}
+		if err := state.SetGovernanceSuspended(ctx, id, true); err != nil {
+			return fmt.Errorf("registry: failed to set governance suspension at genesis: %w", err)
+		}
+	}
+	for i, v := range st.PendingRuntimeTransfers {
+		if v == nil {
+			return fmt.Errorf("registry: genesis pending runtime transfer index %d is nil", i)
+		}
+		if _, err := state.AnyRuntime(ctx, v.ID); err != nil {
+			return fmt.Errorf("registry: genesis pending transfer for unknown runtime %s: %w", v.ID, err)
+		}
+		if err := state.SetPendingRuntimeTransfer(ctx, v); err != nil {
+			return fmt.Errorf("registry: failed to set pending runtime transfer at genesis: %w", err)
+		}
+	}
 	for i, v := range st.Nodes {
 		if v == nil {
 			return fmt.Errorf("registry: genesis node index %d is nil", i)
@@ -138,6 +157,14 @@ func (rq *registryQuerier) Genesis(ctx context.Context) (*registry.Genesis, erro
 	if err != nil {
 		return nil, err
 	}
+	governanceSuspendedRuntimes, err := rq.state.GovernanceSuspendedRuntimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pendingRuntimeTransfers, err := rq.state.PendingRuntimeTransfers(ctx)
+	if err != nil {
+		return nil, err
+	}
 	signedNodes, err := rq.state.SignedNodes(ctx)
 	if err != nil {
 		return nil, err
@@ -175,12 +202,14 @@ func (rq *registryQuerier) Genesis(ctx context.Context) (*registry.Genesis, erro
 	}
 
 	gen := registry.Genesis{
-		Parameters:        *params,
-		Entities:          signedEntities,
-		Runtimes:          signedRuntimes,
-		SuspendedRuntimes: suspendedRuntimes,
-		Nodes:             validatorNodes,
-		NodeStatuses:      nodeStatuses,
+		Parameters:                  *params,
+		Entities:                    signedEntities,
+		Runtimes:                    signedRuntimes,
+		SuspendedRuntimes:           suspendedRuntimes,
+		GovernanceSuspendedRuntimes: governanceSuspendedRuntimes,
+		PendingRuntimeTransfers:     pendingRuntimeTransfers,
+		Nodes:                       validatorNodes,
+		NodeStatuses:                nodeStatuses,
 	}
 	return &gen, nil
 }
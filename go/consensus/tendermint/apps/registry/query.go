@@ -10,6 +10,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/node"
 	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 )
 
@@ -17,9 +18,14 @@ import (
 type Query interface {
 	Entity(context.Context, signature.PublicKey) (*entity.Entity, error)
 	Entities(context.Context) ([]*entity.Entity, error)
+	EntitiesPage(context.Context, *signature.PublicKey, int) (*registry.EntitiesPage, error)
 	Node(context.Context, signature.PublicKey) (*node.Node, error)
 	NodeStatus(context.Context, signature.PublicKey) (*registry.NodeStatus, error)
+	NodeByConsensusAddress(context.Context, []byte) (*node.Node, error)
 	Nodes(context.Context) ([]*node.Node, error)
+	NodesPage(context.Context, *signature.PublicKey, int) (*registry.NodesPage, error)
+	EntityNodesPage(context.Context, signature.PublicKey, *signature.PublicKey, int) (*registry.NodesPage, error)
+	NodeList(context.Context) (*registry.NodeList, error)
 	Runtime(context.Context, common.Namespace) (*registry.Runtime, error)
 	Runtimes(context.Context) ([]*registry.Runtime, error)
 	Genesis(context.Context) (*registry.Genesis, error)
@@ -53,6 +59,17 @@ func (rq *registryQuerier) Entities(ctx context.Context) ([]*entity.Entity, erro
 	return rq.state.Entities(ctx)
 }
 
+func (rq *registryQuerier) EntitiesPage(ctx context.Context, cursor *signature.PublicKey, limit int) (*registry.EntitiesPage, error) {
+	entities, nextCursor, err := rq.state.EntitiesPage(ctx, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &registry.EntitiesPage{
+		Entities:   entities,
+		NextCursor: nextCursor,
+	}, nil
+}
+
 func (rq *registryQuerier) Node(ctx context.Context, id signature.PublicKey) (*node.Node, error) {
 	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
 	if err != nil {
@@ -75,6 +92,24 @@ func (rq *registryQuerier) NodeStatus(ctx context.Context, id signature.PublicKe
 	return rq.state.NodeStatus(ctx, id)
 }
 
+func (rq *registryQuerier) NodeByConsensusAddress(ctx context.Context, address []byte) (*node.Node, error) {
+	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	node, err := rq.state.NodeByConsensusAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not return expired nodes.
+	if node.IsExpired(uint64(epoch)) {
+		return nil, registry.ErrNoSuchNode
+	}
+	return node, nil
+}
+
 func (rq *registryQuerier) Nodes(ctx context.Context) ([]*node.Node, error) {
 	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
 	if err != nil {
@@ -97,6 +132,71 @@ func (rq *registryQuerier) Nodes(ctx context.Context) ([]*node.Node, error) {
 	return filteredNodes, nil
 }
 
+func (rq *registryQuerier) NodeList(ctx context.Context) (*registry.NodeList, error) {
+	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	nodes, err := rq.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registry.SortNodeList(nodes)
+
+	return &registry.NodeList{
+		Epoch: epoch,
+		Nodes: nodes,
+	}, nil
+}
+
+func (rq *registryQuerier) NodesPage(ctx context.Context, cursor *signature.PublicKey, limit int) (*registry.NodesPage, error) {
+	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	nodes, nextCursor, err := rq.state.NodesPage(ctx, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNodesPage(nodes, nextCursor, epoch), nil
+}
+
+func (rq *registryQuerier) EntityNodesPage(
+	ctx context.Context,
+	entityID signature.PublicKey,
+	cursor *signature.PublicKey,
+	limit int,
+) (*registry.NodesPage, error) {
+	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	nodes, nextCursor, err := rq.state.EntityNodesPage(ctx, entityID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNodesPage(nodes, nextCursor, epoch), nil
+}
+
+// newNodesPage creates a new page of nodes, filtering out expired nodes.
+func newNodesPage(nodes []*node.Node, nextCursor *signature.PublicKey, epoch epochtime.EpochTime) *registry.NodesPage {
+	page := &registry.NodesPage{
+		NextCursor: nextCursor,
+	}
+	for _, n := range nodes {
+		if n.IsExpired(uint64(epoch)) {
+			continue
+		}
+		page.Nodes = append(page.Nodes, n)
+	}
+	return page
+}
+
 func (rq *registryQuerier) Runtime(ctx context.Context, id common.Namespace) (*registry.Runtime, error) {
 	return rq.state.Runtime(ctx, id)
 }
@@ -95,6 +95,34 @@ func (app *registryApplication) ExecuteTx(ctx *api.Context, tx *transaction.Tran
 		}
 
 		return app.registerRuntime(ctx, state, &sigRt)
+	case registry.MethodAttestRuntimeReadiness:
+		var attestation registry.RuntimeReadinessAttestation
+		if err := cbor.Unmarshal(tx.Body, &attestation); err != nil {
+			return err
+		}
+
+		return app.attestRuntimeReadiness(ctx, state, &attestation)
+	case registry.MethodProveFreshness:
+		var proof registry.FreshnessProof
+		if err := cbor.Unmarshal(tx.Body, &proof); err != nil {
+			return err
+		}
+
+		return app.proveFreshness(ctx, state, &proof)
+	case registry.MethodTransferRuntimeOwnership:
+		var transfer registry.TransferRuntimeOwnership
+		if err := cbor.Unmarshal(tx.Body, &transfer); err != nil {
+			return err
+		}
+
+		return app.transferRuntimeOwnership(ctx, state, &transfer)
+	case registry.MethodAcceptRuntimeOwnership:
+		var sigRt registry.SignedRuntime
+		if err := cbor.Unmarshal(tx.Body, &sigRt); err != nil {
+			return err
+		}
+
+		return app.acceptRuntimeOwnership(ctx, state, &sigRt)
 	default:
 		return registry.ErrInvalidArgument
 	}
@@ -203,6 +231,14 @@ func (app *registryApplication) onRegistryEpochChanged(ctx *api.Context, registr
 		}
 	}
 
+	runtimes, err := state.Runtimes(ctx)
+	if err != nil {
+		ctx.Logger().Error("onRegistryEpochChanged: failed to get runtimes",
+			"err", err,
+		)
+		return fmt.Errorf("registry: onRegistryEpochChanged: failed to get runtimes: %w", err)
+	}
+
 	// Emit the RegistryNodeListEpoch notification event.
 	evb := api.NewEventBuilder(app.Name())
 	// (Dummy value, should be ignored.)
@@ -214,6 +250,24 @@ func (app *registryApplication) onRegistryEpochChanged(ctx *api.Context, registr
 		evb = evb.Attribute(KeyNodesExpired, cbor.Marshal(expiredNodes))
 	}
 
+	// Re-emit the RuntimeRegistered event for any runtimes that have a
+	// deployment becoming active in this epoch so the change is picked up.
+	for _, rt := range runtimes {
+		for _, d := range rt.Deployments {
+			if d.ValidFrom != registryEpoch {
+				continue
+			}
+
+			ctx.Logger().Debug("runtime deployment activated",
+				"runtime_id", rt.ID,
+				"version", d.Version.Version,
+				"epoch", registryEpoch,
+			)
+			evb = evb.Attribute(KeyRuntimeRegistered, cbor.Marshal(rt))
+			break
+		}
+	}
+
 	ctx.EmitEvent(evb)
 
 	return nil
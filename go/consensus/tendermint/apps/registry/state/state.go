@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -69,6 +70,17 @@ var (
 	//
 	// Value is empty.
 	signedRuntimeByEntityKeyFmt = keyformat.New(0x19, keyformat.H(&signature.PublicKey{}), keyformat.H(&common.Namespace{}))
+	// governanceSuspendedRuntimeKeyFmt is the key format used for runtimes
+	// suspended via a SuspendRuntime transaction. Such runtimes are not
+	// resumed when maintenance fees are paid.
+	//
+	// Value is binary runtime ID.
+	governanceSuspendedRuntimeKeyFmt = keyformat.New(0x1a, keyformat.H(&common.Namespace{}))
+	// pendingRuntimeTransferKeyFmt is the key format used for pending
+	// runtime ownership transfers.
+	//
+	// Value is CBOR-serialized registry.PendingRuntimeTransfer.
+	pendingRuntimeTransferKeyFmt = keyformat.New(0x1b, keyformat.H(&common.Namespace{}))
 )
 
 // ImmutableState is the immutable registry state wrapper.
@@ -130,6 +142,44 @@ func (s *ImmutableState) Entities(ctx context.Context) ([]*entity.Entity, error)
 	return entities, nil
 }
 
+// EntitiesPage returns a page of at most limit registered entities, starting
+// after the entity with the given cursor ID (or with the first entity if the
+// cursor is nil), and the cursor for the next page (nil if this is the last
+// page).
+func (s *ImmutableState) EntitiesPage(ctx context.Context, cursor *signature.PublicKey, limit int) ([]*entity.Entity, *signature.PublicKey, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var (
+		entities   []*entity.Entity
+		nextCursor *signature.PublicKey
+	)
+	for seekPage(it, signedEntityKeyFmt, cursor); it.Valid(); it.Next() {
+		if !signedEntityKeyFmt.Decode(it.Key()) {
+			break
+		}
+		if len(entities) >= limit {
+			nextCursor = &entities[len(entities)-1].ID
+			break
+		}
+
+		var signedEntity entity.SignedEntity
+		if err := cbor.Unmarshal(it.Value(), &signedEntity); err != nil {
+			return nil, nil, abciAPI.UnavailableStateError(err)
+		}
+		var entity entity.Entity
+		if err := cbor.Unmarshal(signedEntity.Blob, &entity); err != nil {
+			return nil, nil, abciAPI.UnavailableStateError(err)
+		}
+
+		entities = append(entities, &entity)
+	}
+	if it.Err() != nil {
+		return nil, nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return entities, nextCursor, nil
+}
+
 // SignedEntities returns a list of all registered entities (signed).
 func (s *ImmutableState) SignedEntities(ctx context.Context) ([]*entity.SignedEntity, error) {
 	it := s.is.NewIterator(ctx)
@@ -226,6 +276,127 @@ func (s *ImmutableState) Nodes(ctx context.Context) ([]*node.Node, error) {
 	return nodes, nil
 }
 
+// NodesPage returns a page of at most limit registered nodes, starting after
+// the node with the given cursor ID (or with the first node if the cursor is
+// nil), and the cursor for the next page (nil if this is the last page).
+//
+// NOTE: The returned page includes expired nodes.
+func (s *ImmutableState) NodesPage(ctx context.Context, cursor *signature.PublicKey, limit int) ([]*node.Node, *signature.PublicKey, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var (
+		nodes      []*node.Node
+		nextCursor *signature.PublicKey
+	)
+	for seekPage(it, signedNodeKeyFmt, cursor); it.Valid(); it.Next() {
+		if !signedNodeKeyFmt.Decode(it.Key()) {
+			break
+		}
+		if len(nodes) >= limit {
+			nextCursor = &nodes[len(nodes)-1].ID
+			break
+		}
+
+		var signedNode node.MultiSignedNode
+		if err := cbor.Unmarshal(it.Value(), &signedNode); err != nil {
+			return nil, nil, abciAPI.UnavailableStateError(err)
+		}
+		var node node.Node
+		if err := cbor.Unmarshal(signedNode.Blob, &node); err != nil {
+			return nil, nil, abciAPI.UnavailableStateError(err)
+		}
+
+		nodes = append(nodes, &node)
+	}
+	if it.Err() != nil {
+		return nil, nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return nodes, nextCursor, nil
+}
+
+// EntityNodesPage returns a page of at most limit registered nodes of the
+// given entity, starting after the node with the given cursor ID (or with the
+// first node if the cursor is nil), and the cursor for the next page (nil if
+// this is the last page).
+//
+// NOTE: The returned page includes expired nodes.
+func (s *ImmutableState) EntityNodesPage(
+	ctx context.Context,
+	entityID signature.PublicKey,
+	cursor *signature.PublicKey,
+	limit int,
+) ([]*node.Node, *signature.PublicKey, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	if cursor == nil {
+		it.Seek(signedNodeByEntityKeyFmt.Encode(&entityID))
+	} else {
+		key := signedNodeByEntityKeyFmt.Encode(&entityID, cursor)
+		it.Seek(key)
+		if it.Valid() && bytes.Equal(it.Key(), key) {
+			it.Next()
+		}
+	}
+
+	var (
+		nodes      []*node.Node
+		nextCursor *signature.PublicKey
+	)
+	hEntityID := keyformat.PreHashed(entityID.Hash())
+	for ; it.Valid(); it.Next() {
+		var hKeyEntityID, hNodeID keyformat.PreHashed
+		if !signedNodeByEntityKeyFmt.Decode(it.Key(), &hKeyEntityID, &hNodeID) || !hKeyEntityID.Equal(&hEntityID) {
+			break
+		}
+		if len(nodes) >= limit {
+			nextCursor = &nodes[len(nodes)-1].ID
+			break
+		}
+
+		rawSignedNode, err := s.is.Get(ctx, signedNodeKeyFmt.Encode(&hNodeID))
+		if err != nil {
+			return nil, nil, abciAPI.UnavailableStateError(err)
+		}
+		if rawSignedNode == nil {
+			return nil, nil, abciAPI.UnavailableStateError(errors.New("registry: missing node for entity index entry"))
+		}
+		var signedNode node.MultiSignedNode
+		if err = cbor.Unmarshal(rawSignedNode, &signedNode); err != nil {
+			return nil, nil, abciAPI.UnavailableStateError(err)
+		}
+		var node node.Node
+		if err = cbor.Unmarshal(signedNode.Blob, &node); err != nil {
+			return nil, nil, abciAPI.UnavailableStateError(err)
+		}
+
+		nodes = append(nodes, &node)
+	}
+	if it.Err() != nil {
+		return nil, nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return nodes, nextCursor, nil
+}
+
+// seekPage positions the iterator at the first key after the key for the
+// given cursor ID or at the first key if the cursor is nil.
+//
+// As keys are derived from hashed IDs, this works even if the descriptor
+// with the cursor ID has been removed in the meantime.
+func seekPage(it mkvs.Iterator, keyFmt *keyformat.KeyFormat, cursor *signature.PublicKey) {
+	if cursor == nil {
+		it.Seek(keyFmt.Encode())
+		return
+	}
+
+	key := keyFmt.Encode(cursor)
+	it.Seek(key)
+	if it.Valid() && bytes.Equal(it.Key(), key) {
+		it.Next()
+	}
+}
+
 // SignedNodes returns a list of all registered nodes (in signed form).
 func (s *ImmutableState) SignedNodes(ctx context.Context) ([]*node.MultiSignedNode, error) {
 	it := s.is.NewIterator(ctx)
@@ -475,6 +646,80 @@ func (s *ImmutableState) HasEntityRuntimes(ctx context.Context, id signature.Pub
 	return false, abciAPI.UnavailableStateError(it.Err())
 }
 
+// IsGovernanceSuspended checks whether a runtime was suspended via
+// a SuspendRuntime transaction.
+func (s *ImmutableState) IsGovernanceSuspended(ctx context.Context, id common.Namespace) (bool, error) {
+	data, err := s.is.Get(ctx, governanceSuspendedRuntimeKeyFmt.Encode(&id))
+	if err != nil {
+		return false, abciAPI.UnavailableStateError(err)
+	}
+	return data != nil, nil
+}
+
+// GovernanceSuspendedRuntimes returns the identifiers of all runtimes
+// suspended via a SuspendRuntime transaction.
+func (s *ImmutableState) GovernanceSuspendedRuntimes(ctx context.Context) ([]common.Namespace, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var ids []common.Namespace
+	for it.Seek(governanceSuspendedRuntimeKeyFmt.Encode()); it.Valid(); it.Next() {
+		if !governanceSuspendedRuntimeKeyFmt.Decode(it.Key()) {
+			break
+		}
+
+		var id common.Namespace
+		if err := id.UnmarshalBinary(it.Value()); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		ids = append(ids, id)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return ids, nil
+}
+
+// PendingRuntimeTransfer returns the pending ownership transfer of a runtime.
+func (s *ImmutableState) PendingRuntimeTransfer(ctx context.Context, id common.Namespace) (*registry.PendingRuntimeTransfer, error) {
+	data, err := s.is.Get(ctx, pendingRuntimeTransferKeyFmt.Encode(&id))
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if data == nil {
+		return nil, registry.ErrNoPendingRuntimeTransfer
+	}
+
+	var transfer registry.PendingRuntimeTransfer
+	if err = cbor.Unmarshal(data, &transfer); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &transfer, nil
+}
+
+// PendingRuntimeTransfers returns all pending runtime ownership transfers.
+func (s *ImmutableState) PendingRuntimeTransfers(ctx context.Context) ([]*registry.PendingRuntimeTransfer, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var transfers []*registry.PendingRuntimeTransfer
+	for it.Seek(pendingRuntimeTransferKeyFmt.Encode()); it.Valid(); it.Next() {
+		if !pendingRuntimeTransferKeyFmt.Decode(it.Key()) {
+			break
+		}
+
+		var transfer registry.PendingRuntimeTransfer
+		if err := cbor.Unmarshal(it.Value(), &transfer); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		transfers = append(transfers, &transfer)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return transfers, nil
+}
+
 // ConsensusParameters returns the registry consensus parameters.
 func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*registry.ConsensusParameters, error) {
 	raw, err := s.is.Get(ctx, parametersKeyFmt.Encode())
@@ -656,6 +901,21 @@ func (s *MutableState) SetRuntime(ctx context.Context, rt *registry.Runtime, sig
 	return abciAPI.UnavailableStateError(err)
 }
 
+// TransferRuntime sets a signed runtime descriptor for a registered runtime
+// whose owning entity has changed from the given previous owner.
+func (s *MutableState) TransferRuntime(
+	ctx context.Context,
+	previousOwner signature.PublicKey,
+	rt *registry.Runtime,
+	sigRt *registry.SignedRuntime,
+	suspended bool,
+) error {
+	if err := s.ms.Remove(ctx, signedRuntimeByEntityKeyFmt.Encode(&previousOwner, &rt.ID)); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	return s.SetRuntime(ctx, rt, sigRt, suspended)
+}
+
 // SuspendRuntime marks a runtime as suspended.
 func (s *MutableState) SuspendRuntime(ctx context.Context, id common.Namespace) error {
 	data, err := s.ms.RemoveExisting(ctx, signedRuntimeKeyFmt.Encode(&id))
@@ -682,6 +942,57 @@ func (s *MutableState) ResumeRuntime(ctx context.Context, id common.Namespace) e
 	return abciAPI.UnavailableStateError(err)
 }
 
+// SetGovernanceSuspended sets or clears the flag marking a runtime as
+// suspended via a SuspendRuntime transaction.
+func (s *MutableState) SetGovernanceSuspended(ctx context.Context, id common.Namespace, suspended bool) error {
+	var err error
+	if suspended {
+		var data []byte
+		if data, err = id.MarshalBinary(); err != nil {
+			return err
+		}
+		err = s.ms.Insert(ctx, governanceSuspendedRuntimeKeyFmt.Encode(&id), data)
+	} else {
+		err = s.ms.Remove(ctx, governanceSuspendedRuntimeKeyFmt.Encode(&id))
+	}
+	return abciAPI.UnavailableStateError(err)
+}
+
+// SetPendingRuntimeTransfer sets the pending ownership transfer of a runtime,
+// replacing any previously pending transfer.
+func (s *MutableState) SetPendingRuntimeTransfer(ctx context.Context, transfer *registry.PendingRuntimeTransfer) error {
+	err := s.ms.Insert(ctx, pendingRuntimeTransferKeyFmt.Encode(&transfer.ID), cbor.Marshal(transfer))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// RemovePendingRuntimeTransfer removes the pending ownership transfer of
+// a runtime if one exists.
+func (s *MutableState) RemovePendingRuntimeTransfer(ctx context.Context, id common.Namespace) error {
+	err := s.ms.Remove(ctx, pendingRuntimeTransferKeyFmt.Encode(&id))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// RemovePendingRuntimeTransfersTo removes all pending runtime ownership
+// transfers offered to the given entity and returns the removed transfers.
+func (s *MutableState) RemovePendingRuntimeTransfersTo(ctx context.Context, newOwner signature.PublicKey) ([]*registry.PendingRuntimeTransfer, error) {
+	transfers, err := s.PendingRuntimeTransfers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []*registry.PendingRuntimeTransfer
+	for _, transfer := range transfers {
+		if !transfer.NewOwner.Equal(newOwner) {
+			continue
+		}
+		if err = s.RemovePendingRuntimeTransfer(ctx, transfer.ID); err != nil {
+			return nil, err
+		}
+		removed = append(removed, transfer)
+	}
+	return removed, nil
+}
+
 // SetNodeStatus sets a status for a registered node.
 func (s *MutableState) SetNodeStatus(ctx context.Context, id signature.PublicKey, status *registry.NodeStatus) error {
 	err := s.ms.Insert(ctx, nodeStatusKeyFmt.Encode(&id), cbor.Marshal(status))
@@ -1,13 +1,16 @@
 package state
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/entity"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	tmcrypto "github.com/oasislabs/oasis-core/go/consensus/tendermint/crypto"
@@ -143,3 +146,246 @@ func TestNodeUpdate(t *testing.T) {
 	require.Error(err, "TLS mapping should be gone")
 	require.Equal(registry.ErrNoSuchNode, err, "TLS mapping should be gone")
 }
+
+func TestEntitiesPage(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	const numEntities = 7
+	for i := 0; i < numEntities; i++ {
+		signer := memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/registry/state: entity %d", i))
+		ent := entity.Entity{
+			DescriptorVersion: entity.LatestEntityDescriptorVersion,
+			ID:                signer.Public(),
+		}
+		sigEnt, err := entity.SignEntity(signer, registry.RegisterEntitySignatureContext, &ent)
+		require.NoError(err, "SignEntity")
+		err = s.SetEntity(ctx, &ent, sigEnt)
+		require.NoError(err, "SetEntity")
+	}
+
+	allEntities, err := s.Entities(ctx)
+	require.NoError(err, "Entities")
+	require.Len(allEntities, numEntities)
+
+	// Iterating over pages should return all entities exactly once and in
+	// the same order as returned by Entities.
+	var (
+		pagedEntities []*entity.Entity
+		cursor        *signature.PublicKey
+		numPages      int
+	)
+	for {
+		var page []*entity.Entity
+		page, cursor, err = s.EntitiesPage(ctx, cursor, 3)
+		require.NoError(err, "EntitiesPage")
+		require.True(len(page) <= 3, "page should not exceed the limit")
+		pagedEntities = append(pagedEntities, page...)
+		numPages++
+		if cursor == nil {
+			break
+		}
+		require.EqualValues(page[len(page)-1].ID, *cursor, "cursor should be the ID of the last entity")
+	}
+	require.Equal(3, numPages, "there should be the right number of pages")
+	require.EqualValues(allEntities, pagedEntities, "paged entities should match all entities")
+
+	// A page large enough for all entities should be the last page.
+	page, cursor, err := s.EntitiesPage(ctx, nil, numEntities)
+	require.NoError(err, "EntitiesPage")
+	require.Len(page, numEntities)
+	require.Nil(cursor, "there should be no next page")
+
+	// Removing the cursor entity should not affect the next page.
+	_, cursor, err = s.EntitiesPage(ctx, nil, 3)
+	require.NoError(err, "EntitiesPage")
+	nextPage, _, err := s.EntitiesPage(ctx, cursor, 3)
+	require.NoError(err, "EntitiesPage")
+	_, err = s.RemoveEntity(ctx, *cursor)
+	require.NoError(err, "RemoveEntity")
+	page, _, err = s.EntitiesPage(ctx, cursor, 3)
+	require.NoError(err, "EntitiesPage")
+	require.EqualValues(nextPage, page, "next page should not change when the cursor entity is removed")
+}
+
+func TestNodesPage(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	const numNodes = 5
+	for i := 0; i < numNodes; i++ {
+		signer := memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/registry/state: paged node %d", i))
+		n := node.Node{
+			DescriptorVersion: node.LatestNodeDescriptorVersion,
+			ID:                signer.Public(),
+		}
+		sigNode, err := node.MultiSignNode([]signature.Signer{signer}, registry.RegisterNodeSignatureContext, &n)
+		require.NoError(err, "MultiSignNode")
+		err = s.SetNode(ctx, nil, &n, sigNode)
+		require.NoError(err, "SetNode")
+	}
+
+	allNodes, err := s.Nodes(ctx)
+	require.NoError(err, "Nodes")
+	require.Len(allNodes, numNodes)
+
+	pagedNodes := make(map[signature.PublicKey]bool)
+	var cursor *signature.PublicKey
+	for {
+		var page []*node.Node
+		page, cursor, err = s.NodesPage(ctx, cursor, 2)
+		require.NoError(err, "NodesPage")
+		require.True(len(page) <= 2, "page should not exceed the limit")
+		for _, n := range page {
+			require.False(pagedNodes[n.ID], "node should only be returned once")
+			pagedNodes[n.ID] = true
+		}
+		if cursor == nil {
+			break
+		}
+	}
+	for _, n := range allNodes {
+		require.True(pagedNodes[n.ID], "all nodes should be returned")
+	}
+}
+
+func TestGovernanceSuspended(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	rt := &registry.Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: runtime"), 0),
+		Kind: registry.KindCompute,
+	}
+	err := s.SetRuntime(ctx, rt, &registry.SignedRuntime{}, true)
+	require.NoError(err, "SetRuntime")
+
+	suspended, err := s.IsGovernanceSuspended(ctx, rt.ID)
+	require.NoError(err, "IsGovernanceSuspended")
+	require.False(suspended, "runtime should not be suspended via governance")
+
+	err = s.SetGovernanceSuspended(ctx, rt.ID, true)
+	require.NoError(err, "SetGovernanceSuspended")
+	suspended, err = s.IsGovernanceSuspended(ctx, rt.ID)
+	require.NoError(err, "IsGovernanceSuspended")
+	require.True(suspended, "runtime should be suspended via governance")
+	ids, err := s.GovernanceSuspendedRuntimes(ctx)
+	require.NoError(err, "GovernanceSuspendedRuntimes")
+	require.Equal([]common.Namespace{rt.ID}, ids, "governance suspended runtimes")
+
+	err = s.SetGovernanceSuspended(ctx, rt.ID, false)
+	require.NoError(err, "SetGovernanceSuspended")
+	suspended, err = s.IsGovernanceSuspended(ctx, rt.ID)
+	require.NoError(err, "IsGovernanceSuspended")
+	require.False(suspended, "runtime should no longer be suspended via governance")
+	ids, err = s.GovernanceSuspendedRuntimes(ctx)
+	require.NoError(err, "GovernanceSuspendedRuntimes")
+	require.Empty(ids, "governance suspended runtimes")
+}
+
+func TestRuntimeTransfer(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	owner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry/state: runtime owner").Public()
+	newOwner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry/state: new runtime owner").Public()
+	rt := &registry.Runtime{
+		ID:       common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: transferred runtime"), 0),
+		EntityID: owner,
+		Kind:     registry.KindCompute,
+	}
+	err := s.SetRuntime(ctx, rt, &registry.SignedRuntime{}, false)
+	require.NoError(err, "SetRuntime")
+
+	_, err = s.PendingRuntimeTransfer(ctx, rt.ID)
+	require.Equal(registry.ErrNoPendingRuntimeTransfer, err, "PendingRuntimeTransfer without transfer")
+
+	transfer := &registry.PendingRuntimeTransfer{ID: rt.ID, NewOwner: newOwner}
+	err = s.SetPendingRuntimeTransfer(ctx, transfer)
+	require.NoError(err, "SetPendingRuntimeTransfer")
+	pending, err := s.PendingRuntimeTransfer(ctx, rt.ID)
+	require.NoError(err, "PendingRuntimeTransfer")
+	require.Equal(transfer, pending, "pending transfer")
+	transfers, err := s.PendingRuntimeTransfers(ctx)
+	require.NoError(err, "PendingRuntimeTransfers")
+	require.Equal([]*registry.PendingRuntimeTransfer{transfer}, transfers, "pending transfers")
+
+	transferred := *rt
+	transferred.EntityID = newOwner
+	err = s.TransferRuntime(ctx, owner, &transferred, &registry.SignedRuntime{}, false)
+	require.NoError(err, "TransferRuntime")
+	err = s.RemovePendingRuntimeTransfer(ctx, rt.ID)
+	require.NoError(err, "RemovePendingRuntimeTransfer")
+
+	hasRuntimes, err := s.HasEntityRuntimes(ctx, owner)
+	require.NoError(err, "HasEntityRuntimes")
+	require.False(hasRuntimes, "previous owner should no longer have runtimes")
+	hasRuntimes, err = s.HasEntityRuntimes(ctx, newOwner)
+	require.NoError(err, "HasEntityRuntimes")
+	require.True(hasRuntimes, "new owner should have the runtime")
+	transfers, err = s.PendingRuntimeTransfers(ctx)
+	require.NoError(err, "PendingRuntimeTransfers")
+	require.Empty(transfers, "pending transfers")
+}
+
+func TestRemovePendingRuntimeTransfersTo(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	newOwner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry/state: new runtime owner").Public()
+	otherOwner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry/state: other new runtime owner").Public()
+	toNewOwner := []*registry.PendingRuntimeTransfer{
+		{ID: common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: transferred runtime"), 0), NewOwner: newOwner},
+		{ID: common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: transferred runtime 2"), 0), NewOwner: newOwner},
+	}
+	toOtherOwner := &registry.PendingRuntimeTransfer{
+		ID:       common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: other transferred runtime"), 0),
+		NewOwner: otherOwner,
+	}
+	for _, transfer := range append(toNewOwner, toOtherOwner) {
+		err := s.SetPendingRuntimeTransfer(ctx, transfer)
+		require.NoError(err, "SetPendingRuntimeTransfer")
+	}
+
+	removed, err := s.RemovePendingRuntimeTransfersTo(ctx, newOwner)
+	require.NoError(err, "RemovePendingRuntimeTransfersTo")
+	require.ElementsMatch(toNewOwner, removed, "removed transfers")
+
+	transfers, err := s.PendingRuntimeTransfers(ctx)
+	require.NoError(err, "PendingRuntimeTransfers")
+	require.Equal([]*registry.PendingRuntimeTransfer{toOtherOwner}, transfers, "transfers to other entities should remain")
+
+	removed, err = s.RemovePendingRuntimeTransfersTo(ctx, newOwner)
+	require.NoError(err, "RemovePendingRuntimeTransfersTo")
+	require.Empty(removed, "removed transfers")
+}
@@ -3,12 +3,14 @@ package registry
 import (
 	"fmt"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/entity"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
 	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
@@ -135,6 +137,26 @@ func (app *registryApplication) deregisterEntity(ctx *api.Context, state *regist
 		}
 	}
 
+	// Cancel any runtime ownership transfers offered to the entity as it can
+	// no longer accept them.
+	cancelled, err := state.RemovePendingRuntimeTransfersTo(ctx, id)
+	if err != nil {
+		return fmt.Errorf("DeregisterEntity: failed to remove pending runtime transfers: %w", err)
+	}
+	for _, transfer := range cancelled {
+		var rt *registry.Runtime
+		if rt, err = state.AnyRuntime(ctx, transfer.ID); err != nil {
+			return fmt.Errorf("DeregisterEntity: failed to fetch transferred runtime: %w", err)
+		}
+
+		ev := &registry.RuntimeOwnershipEvent{
+			ID:       rt.ID,
+			Owner:    rt.EntityID,
+			NewOwner: rt.EntityID,
+		}
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyRuntimeOwnership, cbor.Marshal(ev)))
+	}
+
 	ctx.Logger().Debug("DeregisterEntity: complete",
 		"entity_id", id,
 	)
@@ -385,6 +407,14 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 				continue
 			}
 		}
+		// Runtimes suspended via governance can only be resumed via governance.
+		var governanceSuspended bool
+		if governanceSuspended, err = state.IsGovernanceSuspended(ctx, rt.ID); err != nil {
+			return fmt.Errorf("failed to query runtime suspension: %w", err)
+		}
+		if governanceSuspended {
+			continue
+		}
 
 		err := state.ResumeRuntime(ctx, rt.ID)
 		switch err {
@@ -487,6 +517,165 @@ func (app *registryApplication) unfreezeNode(
 	return nil
 }
 
+func (app *registryApplication) attestRuntimeReadiness(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	attestation *registry.RuntimeReadinessAttestation,
+) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("AttestRuntimeReadiness: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if params.ReadinessAttestationExpiration == 0 {
+		return registry.ErrForbidden
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, registry.GasOpAttestRuntimeReadiness, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Readiness attestations must be signed by the node itself.
+	node, err := state.Node(ctx, ctx.TxSigner())
+	if err != nil {
+		ctx.Logger().Error("AttestRuntimeReadiness: failed to fetch node",
+			"err", err,
+			"node_id", ctx.TxSigner(),
+		)
+		return err
+	}
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
+	if node.IsExpired(uint64(epoch)) {
+		return registry.ErrNodeExpired
+	}
+
+	for _, runtimeID := range attestation.Runtimes {
+		if node.GetRuntime(runtimeID) == nil {
+			ctx.Logger().Error("AttestRuntimeReadiness: node does not serve runtime",
+				"node_id", node.ID,
+				"runtime_id", runtimeID,
+			)
+			return registry.ErrNodeDoesNotServeRuntime
+		}
+	}
+
+	status, err := state.NodeStatus(ctx, node.ID)
+	if err != nil {
+		ctx.Logger().Error("AttestRuntimeReadiness: failed to fetch node status",
+			"err", err,
+			"node_id", node.ID,
+		)
+		return err
+	}
+
+	// Drop any expired attestations and record the new ones.
+	readyRuntimes := make(map[common.Namespace]epochtime.EpochTime)
+	for runtimeID, expiration := range status.ReadyRuntimes {
+		if status.IsRuntimeReady(runtimeID, epoch) && node.GetRuntime(runtimeID) != nil {
+			readyRuntimes[runtimeID] = expiration
+		}
+	}
+	expiration := epoch + epochtime.EpochTime(params.ReadinessAttestationExpiration)
+	for _, runtimeID := range attestation.Runtimes {
+		readyRuntimes[runtimeID] = expiration
+	}
+	status.ReadyRuntimes = readyRuntimes
+	if len(status.ReadyRuntimes) == 0 {
+		status.ReadyRuntimes = nil
+	}
+
+	if err = state.SetNodeStatus(ctx, node.ID, status); err != nil {
+		return fmt.Errorf("failed to set node status: %w", err)
+	}
+
+	ctx.Logger().Debug("AttestRuntimeReadiness: runtime readiness attested",
+		"node_id", node.ID,
+		"runtimes", attestation.Runtimes,
+		"expiration", expiration,
+	)
+
+	return nil
+}
+
+func (app *registryApplication) proveFreshness(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	proof *registry.FreshnessProof,
+) error {
+	if len(proof.Blob) > registry.MaxFreshnessProofBlobSize {
+		return fmt.Errorf("%w: freshness proof blob too large", registry.ErrInvalidArgument)
+	}
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("ProveFreshness: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, registry.GasOpProveFreshness, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Freshness proofs must be signed by the node itself.
+	node, err := state.Node(ctx, ctx.TxSigner())
+	if err != nil {
+		ctx.Logger().Error("ProveFreshness: failed to fetch node",
+			"err", err,
+			"node_id", ctx.TxSigner(),
+		)
+		return err
+	}
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
+	if node.IsExpired(uint64(epoch)) {
+		return registry.ErrNodeExpired
+	}
+
+	status, err := state.NodeStatus(ctx, node.ID)
+	if err != nil {
+		ctx.Logger().Error("ProveFreshness: failed to fetch node status",
+			"err", err,
+			"node_id", node.ID,
+		)
+		return err
+	}
+
+	status.LastFreshnessProof = &registry.NodeFreshness{
+		Height: ctx.BlockHeight() + 1,
+		Blob:   proof.Blob,
+	}
+	if err = state.SetNodeStatus(ctx, node.ID, status); err != nil {
+		return fmt.Errorf("failed to set node status: %w", err)
+	}
+
+	ctx.Logger().Debug("ProveFreshness: freshness proven",
+		"node_id", node.ID,
+		"height", status.LastFreshnessProof.Height,
+	)
+
+	return nil
+}
+
 func (app *registryApplication) registerRuntime( // nolint: gocyclo
 	ctx *api.Context,
 	state *registryState.MutableState,
@@ -539,12 +728,18 @@ func (app *registryApplication) registerRuntime( // nolint: gocyclo
 	if rt.TEEHardware != node.TEEHardwareInvalid {
 		switch rt.TEEHardware {
 		case node.TEEHardwareIntelSGX:
-			var vi registry.VersionInfoIntelSGX
-			if err = cbor.Unmarshal(rt.Version.TEE, &vi); err != nil {
-				return err
+			versions := []*registry.VersionInfo{&rt.Version}
+			for _, d := range rt.Deployments {
+				versions = append(versions, &d.Version)
 			}
-			if len(vi.Enclaves) == 0 {
-				return registry.ErrNoEnclaveForRuntime
+			for _, v := range versions {
+				var vi registry.VersionInfoIntelSGX
+				if err = cbor.Unmarshal(v.TEE, &vi); err != nil {
+					return err
+				}
+				if len(vi.Enclaves) == 0 {
+					return registry.ErrNoEnclaveForRuntime
+				}
 			}
 		}
 	}
@@ -573,6 +768,19 @@ func (app *registryApplication) registerRuntime( // nolint: gocyclo
 		if err != nil {
 			return err
 		}
+
+		var epoch epochtime.EpochTime
+		epoch, err = app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+		if err != nil {
+			ctx.Logger().Error("RegisterRuntime: failed to get epoch",
+				"err", err,
+			)
+			return err
+		}
+		err = registry.VerifyRuntimeDeploymentsUpdate(ctx.Logger(), existingRt, rt, epoch)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Make sure that the entity has enough stake.
@@ -608,3 +816,205 @@ func (app *registryApplication) registerRuntime( // nolint: gocyclo
 
 	return nil
 }
+
+func (app *registryApplication) transferRuntimeOwnership(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	transfer *registry.TransferRuntimeOwnership,
+) error {
+	// Only the owning entity may transfer the runtime.
+	rt, err := state.AnyRuntime(ctx, transfer.ID)
+	if err != nil {
+		ctx.Logger().Error("TransferRuntimeOwnership: failed to fetch runtime",
+			"err", err,
+			"runtime_id", transfer.ID,
+		)
+		return err
+	}
+	if !ctx.TxSigner().Equal(rt.EntityID) {
+		return registry.ErrIncorrectTxSigner
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("TransferRuntimeOwnership: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, registry.GasOpTransferRuntimeOwnership, params.GasCosts); err != nil {
+		return err
+	}
+
+	if transfer.NewOwner.Equal(rt.EntityID) {
+		// Offering the runtime to its current owner cancels the pending transfer.
+		if _, err = state.PendingRuntimeTransfer(ctx, rt.ID); err != nil {
+			return err
+		}
+		if err = state.RemovePendingRuntimeTransfer(ctx, rt.ID); err != nil {
+			return fmt.Errorf("failed to remove pending runtime transfer: %w", err)
+		}
+
+		ctx.Logger().Debug("TransferRuntimeOwnership: transfer cancelled",
+			"runtime_id", rt.ID,
+		)
+	} else {
+		// The runtime may only be offered to a registered entity.
+		if _, err = state.Entity(ctx, transfer.NewOwner); err != nil {
+			ctx.Logger().Error("TransferRuntimeOwnership: failed to fetch new owner",
+				"err", err,
+				"new_owner", transfer.NewOwner,
+			)
+			return err
+		}
+
+		pending := &registry.PendingRuntimeTransfer{
+			ID:       rt.ID,
+			NewOwner: transfer.NewOwner,
+		}
+		if err = state.SetPendingRuntimeTransfer(ctx, pending); err != nil {
+			return fmt.Errorf("failed to set pending runtime transfer: %w", err)
+		}
+
+		ctx.Logger().Debug("TransferRuntimeOwnership: transfer offered",
+			"runtime_id", rt.ID,
+			"owner", rt.EntityID,
+			"new_owner", transfer.NewOwner,
+		)
+	}
+
+	ev := &registry.RuntimeOwnershipEvent{
+		ID:       rt.ID,
+		Owner:    rt.EntityID,
+		NewOwner: transfer.NewOwner,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyRuntimeOwnership, cbor.Marshal(ev)))
+
+	return nil
+}
+
+func (app *registryApplication) acceptRuntimeOwnership(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	sigRt *registry.SignedRuntime,
+) error {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("AcceptRuntimeOwnership: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+
+	rt, err := registry.VerifyRegisterRuntimeArgs(params, ctx.Logger(), sigRt, false, false)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, registry.GasOpAcceptRuntimeOwnership, params.GasCosts); err != nil {
+		return err
+	}
+
+	// The transfer must be accepted by the new owner, which also signs the
+	// updated runtime descriptor.
+	if !sigRt.Signature.PublicKey.Equal(ctx.TxSigner()) {
+		return registry.ErrIncorrectTxSigner
+	}
+
+	transfer, err := state.PendingRuntimeTransfer(ctx, rt.ID)
+	if err != nil {
+		return err
+	}
+	if !transfer.NewOwner.Equal(rt.EntityID) {
+		return registry.ErrNoPendingRuntimeTransfer
+	}
+	if _, err = state.Entity(ctx, rt.EntityID); err != nil {
+		ctx.Logger().Error("AcceptRuntimeOwnership: failed to fetch new owner",
+			"err", err,
+			"new_owner", rt.EntityID,
+		)
+		return err
+	}
+
+	// Suspended runtimes can be transferred as well.
+	var suspended bool
+	existingRt, err := state.Runtime(ctx, rt.ID)
+	if err == registry.ErrNoSuchRuntime {
+		existingRt, err = state.SuspendedRuntime(ctx, rt.ID)
+		suspended = true
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch runtime: %w", err)
+	}
+	if err = registry.VerifyRuntimeOwnershipTransfer(ctx.Logger(), existingRt, rt, transfer.NewOwner); err != nil {
+		return err
+	}
+
+	// Move the runtime's stake claim to the new owner, which must have enough
+	// stake to cover it. The updated claims are only committed once the
+	// runtime has been transferred.
+	var stakeAcc *stakingState.StakeAccumulatorCache
+	if !params.DebugBypassStake {
+		claim := registry.StakeClaimForRuntime(rt.ID)
+		thresholds := registry.StakeThresholdsForRuntime(rt)
+
+		if stakeAcc, err = stakingState.NewStakeAccumulatorCache(ctx); err != nil {
+			return fmt.Errorf("failed to create stake accumulator cache: %w", err)
+		}
+		if err = stakeAcc.AddStakeClaim(rt.EntityID, claim, thresholds); err != nil {
+			ctx.Logger().Error("AcceptRuntimeOwnership: insufficient stake",
+				"err", err,
+				"entity_id", rt.EntityID,
+			)
+			return err
+		}
+		if err = stakeAcc.RemoveStakeClaim(existingRt.EntityID, claim); err != nil {
+			return fmt.Errorf("failed to remove previous owner's stake claim: %w", err)
+		}
+	}
+
+	if err = state.TransferRuntime(ctx, existingRt.EntityID, rt, sigRt, suspended); err != nil {
+		ctx.Logger().Error("AcceptRuntimeOwnership: failed to update runtime",
+			"err", err,
+			"runtime", rt,
+		)
+		return fmt.Errorf("failed to set runtime: %w", err)
+	}
+	if err = state.RemovePendingRuntimeTransfer(ctx, rt.ID); err != nil {
+		return fmt.Errorf("failed to remove pending runtime transfer: %w", err)
+	}
+	if stakeAcc != nil {
+		if err = stakeAcc.Commit(); err != nil {
+			return fmt.Errorf("failed to commit stake accumulator updates: %w", err)
+		}
+	}
+
+	ctx.Logger().Debug("AcceptRuntimeOwnership: runtime transferred",
+		"runtime_id", rt.ID,
+		"owner", existingRt.EntityID,
+		"new_owner", rt.EntityID,
+	)
+
+	ev := &registry.RuntimeOwnershipEvent{
+		ID:        rt.ID,
+		Owner:     existingRt.EntityID,
+		NewOwner:  rt.EntityID,
+		Completed: true,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyRuntimeOwnership, cbor.Marshal(ev)))
+	if !suspended {
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyRuntimeRegistered, cbor.Marshal(rt)))
+	}
+
+	return nil
+}
@@ -33,6 +33,9 @@ var (
 	// KeyFinalized is an ABCI event attribute key for finalized blocks
 	// (value is a CBOR serialized ValueFinalized).
 	KeyFinalized = []byte("finalized")
+	// KeyRuntimeSuspension is an ABCI event attribute key for runtime
+	// suspension events (value is a CBOR serialized ValueRuntimeSuspension).
+	KeyRuntimeSuspension = []byte("runtime-suspension")
 )
 
 // ValueFinalized is the value component of a TagFinalized.
@@ -48,6 +51,12 @@ type ValueMergeDiscrepancyDetected struct {
 	ID    common.Namespace                       `json:"id"`
 }
 
+// ValueRuntimeSuspension is the value component of a KeyRuntimeSuspension.
+type ValueRuntimeSuspension struct {
+	ID    common.Namespace                `json:"id"`
+	Event roothash.RuntimeSuspensionEvent `json:"event"`
+}
+
 // ValueExecutionDiscrepancyDetected is the value component of a
 // TagMergeDiscrepancyDetected.
 type ValueExecutionDiscrepancyDetected struct {
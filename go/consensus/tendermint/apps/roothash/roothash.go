@@ -13,6 +13,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
 	tmapi "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
@@ -193,12 +194,24 @@ func (app *rootHashApplication) suspendUnpaidRuntime(
 		"runtime_id", rtState.Runtime.ID,
 	)
 
+	return app.markRuntimeSuspended(ctx, rtState, regState)
+}
+
+// markRuntimeSuspended suspends an active runtime in the registry and
+// terminates its current round.
+func (app *rootHashApplication) markRuntimeSuspended(
+	ctx *tmapi.Context,
+	rtState *roothashState.RuntimeState,
+	regState *registryState.MutableState,
+) error {
 	if err := regState.SuspendRuntime(ctx, rtState.Runtime.ID); err != nil {
 		return err
 	}
 
 	rtState.Suspended = true
 	rtState.Round = nil
+	// There is no round, so there is nothing to time out.
+	rtState.Timer.Stop(ctx)
 
 	// Emity an empty block signalling that the runtime was suspended.
 	app.emitEmptyBlock(ctx, rtState, block.Suspended)
@@ -335,6 +348,34 @@ func (app *rootHashApplication) ExecuteTx(ctx *tmapi.Context, tx *transaction.Tr
 		}
 
 		return app.mergeCommit(ctx, state, &mc)
+	case roothash.MethodSuspendRuntime:
+		var sr roothash.SuspendRuntime
+		if err := cbor.Unmarshal(tx.Body, &sr); err != nil {
+			return err
+		}
+
+		return app.suspendRuntime(ctx, state, &sr)
+	case roothash.MethodResumeRuntime:
+		var rr roothash.ResumeRuntime
+		if err := cbor.Unmarshal(tx.Body, &rr); err != nil {
+			return err
+		}
+
+		return app.resumeRuntime(ctx, state, &rr)
+	case roothash.MethodDebugForceRoundTimeout:
+		var fr roothash.DebugForceRoundTimeout
+		if err := cbor.Unmarshal(tx.Body, &fr); err != nil {
+			return err
+		}
+
+		return app.debugForceRoundTimeout(ctx, state, &fr)
+	case roothash.MethodSubmitEvidence:
+		var ev roothash.Evidence
+		if err := cbor.Unmarshal(tx.Body, &ev); err != nil {
+			return err
+		}
+
+		return app.submitEvidence(ctx, state, &ev)
 	default:
 		return roothash.ErrInvalidArgument
 	}
@@ -481,8 +522,22 @@ func (app *rootHashApplication) FireTimer(ctx *tmapi.Context, timer *abci.Timer)
 		"timer_round", tCtx.Round,
 	)
 
+	if err = app.processRoundTimeouts(ctx, rtState); err != nil {
+		return err
+	}
+
+	if err = state.SetRuntimeState(ctx, rtState); err != nil {
+		return fmt.Errorf("failed to set runtime state: %w", err)
+	}
+
+	return nil
+}
+
+// processRoundTimeouts forces finalization of all committees of the current
+// round whose round timeout has expired.
+func (app *rootHashApplication) processRoundTimeouts(ctx *tmapi.Context, rtState *roothashState.RuntimeState) error {
 	if rtState.Round.MergePool.IsTimeout(ctx.Now()) {
-		if err = app.tryFinalizeBlock(ctx, rtState, true); err != nil {
+		if err := app.tryFinalizeBlock(ctx, rtState, true); err != nil {
 			ctx.Logger().Error("failed to finalize block",
 				"err", err,
 			)
@@ -492,11 +547,6 @@ func (app *rootHashApplication) FireTimer(ctx *tmapi.Context, timer *abci.Timer)
 	for _, pool := range rtState.Round.ExecutorPool.GetTimeoutCommittees(ctx.Now()) {
 		app.tryFinalizeExecute(ctx, rtState, pool, true)
 	}
-
-	if err = state.SetRuntimeState(ctx, rtState); err != nil {
-		return fmt.Errorf("failed to set runtime state: %w", err)
-	}
-
 	return nil
 }
 
@@ -672,10 +722,65 @@ func (app *rootHashApplication) tryFinalizeMerge(
 	return nil
 }
 
+// chargeRuntimeMessages charges the runtime's owning entity for the gas
+// used to execute the given runtime messages.
+func (app *rootHashApplication) chargeRuntimeMessages(
+	ctx *tmapi.Context,
+	rt *registry.Runtime,
+	params *roothash.ConsensusParameters,
+	msgs []*block.Message,
+) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	gas := transaction.Gas(len(msgs)) * params.GasCosts[roothash.GasOpRuntimeMessage]
+	var fee quantity.Quantity
+	if err := fee.FromUint64(uint64(gas)); err != nil {
+		return fmt.Errorf("failed to compute runtime message fee: %w", err)
+	}
+	if err := fee.Mul(&params.RuntimeMessageGasPrice); err != nil {
+		return fmt.Errorf("failed to compute runtime message fee: %w", err)
+	}
+	if err := stakingState.PayFees(ctx, rt.EntityID, &fee); err != nil {
+		return fmt.Errorf("failed to charge runtime message fee: %w", err)
+	}
+	return nil
+}
+
 func (app *rootHashApplication) postProcessFinalizedBlock(ctx *tmapi.Context, rtState *roothashState.RuntimeState, blk *block.Block) error {
+	// Make sure that the runtime messages are within the limits.
+	params, err := roothashState.NewMutableState(ctx.State()).ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = params.ValidateRuntimeMessages(blk.Header.Messages); err != nil {
+		ctx.Logger().Error("runtime messages exceed limits",
+			"err", err,
+			logging.LogEvent, roothash.LogEventMessageUnsat,
+		)
+
+		// Substitute empty block.
+		app.emitEmptyBlock(ctx, rtState, block.RoundFailed)
+
+		return nil
+	}
+
 	sc := ctx.StartCheckpoint()
 	defer sc.Close()
 
+	if err = app.chargeRuntimeMessages(ctx, rtState.Runtime, params, blk.Header.Messages); err != nil {
+		ctx.Logger().Error("failed to charge for runtime messages",
+			"err", err,
+			logging.LogEvent, roothash.LogEventMessageUnsat,
+		)
+
+		// Substitute empty block.
+		app.emitEmptyBlock(ctx, rtState, block.RoundFailed)
+
+		return nil
+	}
+
 	for _, message := range blk.Header.Messages {
 		// Currently there are no valid roothash messages, so any message
 		// is treated as unsatisfactory. This is the place which would
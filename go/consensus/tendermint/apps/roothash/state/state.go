@@ -6,6 +6,8 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/keyformat"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
 	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
@@ -24,6 +26,13 @@ var (
 	//
 	// Value is CBOR-serialized roothash.ConsensusParameters.
 	parametersKeyFmt = keyformat.New(0x21)
+	// evidenceKeyFmt is the key format used for processed equivocation
+	// evidence, keyed by node and the hash of the block that the conflicting
+	// commitments build upon. As the block header commits to the runtime
+	// namespace, the latter also identifies the runtime.
+	//
+	// Value is empty.
+	evidenceKeyFmt = keyformat.New(0x22, &signature.PublicKey{}, &hash.Hash{})
 )
 
 // RuntimeState is the per-runtime roothash state.
@@ -110,6 +119,16 @@ func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*roothash.Con
 	return &params, nil
 }
 
+// EvidenceProcessed returns true iff equivocation evidence for the given node
+// and round has already been processed.
+func (s *ImmutableState) EvidenceProcessed(ctx context.Context, nodeID signature.PublicKey, roundHash hash.Hash) (bool, error) {
+	raw, err := s.is.Get(ctx, evidenceKeyFmt.Encode(&nodeID, &roundHash))
+	if err != nil {
+		return false, abciAPI.UnavailableStateError(err)
+	}
+	return raw != nil, nil
+}
+
 // MutableState is the mutable roothash state wrapper.
 type MutableState struct {
 	*ImmutableState
@@ -137,3 +156,10 @@ func (s *MutableState) SetConsensusParameters(ctx context.Context, params *rooth
 	err := s.ms.Insert(ctx, parametersKeyFmt.Encode(), cbor.Marshal(params))
 	return abciAPI.UnavailableStateError(err)
 }
+
+// SetEvidenceProcessed marks equivocation evidence for the given node and
+// round as processed.
+func (s *MutableState) SetEvidenceProcessed(ctx context.Context, nodeID signature.PublicKey, roundHash hash.Hash) error {
+	err := s.ms.Insert(ctx, evidenceKeyFmt.Encode(&nodeID, &roundHash), []byte{})
+	return abciAPI.UnavailableStateError(err)
+}
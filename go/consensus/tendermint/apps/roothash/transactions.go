@@ -4,14 +4,19 @@ import (
 	"fmt"
 
 	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	registryapp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry"
 	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
 	roothashState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/roothash/state"
 	schedulerState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/scheduler/state"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
 	"github.com/oasislabs/oasis-core/go/roothash/api/commitment"
 	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
 
 var _ commitment.SignatureVerifier = (*roothashSignatureVerifier)(nil)
@@ -94,6 +99,14 @@ func (app *rootHashApplication) getRuntimeState(
 	return rtState, sv, nl, nil
 }
 
+func validateExecutorCommitMessages(params *roothash.ConsensusParameters, commit *commitment.ExecutorCommitment) error {
+	openCom, err := commit.Open()
+	if err != nil {
+		return err
+	}
+	return params.ValidateRuntimeMessages(openCom.Body.Header.Messages)
+}
+
 func (app *rootHashApplication) executorCommit(
 	ctx *abciAPI.Context,
 	state *roothashState.MutableState,
@@ -115,6 +128,16 @@ func (app *rootHashApplication) executorCommit(
 		return err
 	}
 
+	// Reject commitments with runtime messages exceeding the limits.
+	for _, commit := range cc.Commits {
+		if err = validateExecutorCommitMessages(params, &commit); err != nil {
+			ctx.Logger().Error("ComputeCommit: invalid runtime messages",
+				"err", err,
+			)
+			return err
+		}
+	}
+
 	rtState, sv, nl, err := app.getRuntimeState(ctx, state, cc.ID)
 	if err != nil {
 		return err
@@ -168,6 +191,30 @@ func (app *rootHashApplication) mergeCommit(
 		return err
 	}
 
+	// Reject commitments with runtime messages exceeding the limits. Executing
+	// the messages is paid for by the runtime's owning entity once the block
+	// is finalized.
+	for _, commit := range mc.Commits {
+		var openCom *commitment.OpenMergeCommitment
+		if openCom, err = commit.Open(); err != nil {
+			return err
+		}
+		for _, ec := range openCom.Body.ExecutorCommits {
+			if err = validateExecutorCommitMessages(params, &ec); err != nil {
+				ctx.Logger().Error("MergeCommit: invalid runtime messages in executor commitment",
+					"err", err,
+				)
+				return err
+			}
+		}
+		if err = params.ValidateRuntimeMessages(openCom.Body.Header.Messages); err != nil {
+			ctx.Logger().Error("MergeCommit: invalid runtime messages",
+				"err", err,
+			)
+			return err
+		}
+	}
+
 	rtState, sv, nl, err := app.getRuntimeState(ctx, state, mc.ID)
 	if err != nil {
 		return err
@@ -199,3 +246,286 @@ func (app *rootHashApplication) mergeCommit(
 
 	return nil
 }
+
+// checkRuntimeSuspensionAuthority charges gas for a runtime suspension
+// transaction and checks that its signer is allowed to suspend and resume
+// runtimes.
+func (app *rootHashApplication) checkRuntimeSuspensionAuthority(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+) error {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("SuspendRuntime: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, roothash.GasOpSuspendRuntime, params.GasCosts); err != nil {
+		return err
+	}
+
+	if !params.IsRuntimeSuspensionAuthority(ctx.TxSigner()) {
+		ctx.Logger().Error("SuspendRuntime: signer is not a runtime suspension authority",
+			"signer", ctx.TxSigner(),
+		)
+		return roothash.ErrForbidden
+	}
+	return nil
+}
+
+func (app *rootHashApplication) suspendRuntime(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	sr *roothash.SuspendRuntime,
+) error {
+	if err := app.checkRuntimeSuspensionAuthority(ctx, state); err != nil {
+		return err
+	}
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	regState := registryState.NewMutableState(ctx.State())
+	rt, err := regState.AnyRuntime(ctx, sr.ID)
+	if err != nil {
+		return err
+	}
+	if !rt.IsCompute() {
+		return fmt.Errorf("%w: not a compute runtime", roothash.ErrInvalidArgument)
+	}
+	suspended, err := regState.IsGovernanceSuspended(ctx, sr.ID)
+	if err != nil {
+		return err
+	}
+	if suspended {
+		return roothash.ErrRuntimeSuspended
+	}
+
+	if err = regState.SetGovernanceSuspended(ctx, sr.ID, true); err != nil {
+		return fmt.Errorf("failed to set runtime suspension: %w", err)
+	}
+
+	// A runtime suspended for lack of paying maintenance fees has no round in
+	// progress, in which case it only needs to be kept suspended.
+	_, err = regState.Runtime(ctx, sr.ID)
+	switch err {
+	case nil:
+		var rtState *roothashState.RuntimeState
+		if rtState, err = state.RuntimeState(ctx, sr.ID); err != nil {
+			return fmt.Errorf("failed to fetch runtime state: %w", err)
+		}
+		if err = app.markRuntimeSuspended(ctx, rtState, regState); err != nil {
+			return err
+		}
+		if err = state.SetRuntimeState(ctx, rtState); err != nil {
+			return fmt.Errorf("failed to set runtime state: %w", err)
+		}
+	case registry.ErrNoSuchRuntime:
+	default:
+		return err
+	}
+
+	ctx.Logger().Info("SuspendRuntime: runtime suspended",
+		"runtime_id", sr.ID,
+		"signer", ctx.TxSigner(),
+	)
+
+	tagV := ValueRuntimeSuspension{
+		ID:    sr.ID,
+		Event: roothash.RuntimeSuspensionEvent{Suspended: true},
+	}
+	ctx.EmitEvent(abciAPI.NewEventBuilder(app.Name()).Attribute(KeyRuntimeSuspension, cbor.Marshal(tagV)))
+
+	return nil
+}
+
+func (app *rootHashApplication) resumeRuntime(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	rr *roothash.ResumeRuntime,
+) error {
+	if err := app.checkRuntimeSuspensionAuthority(ctx, state); err != nil {
+		return err
+	}
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	regState := registryState.NewMutableState(ctx.State())
+	suspended, err := regState.IsGovernanceSuspended(ctx, rr.ID)
+	if err != nil {
+		return err
+	}
+	if !suspended {
+		return fmt.Errorf("%w: runtime not suspended via SuspendRuntime", roothash.ErrInvalidArgument)
+	}
+
+	if err = regState.SetGovernanceSuspended(ctx, rr.ID, false); err != nil {
+		return fmt.Errorf("failed to clear runtime suspension: %w", err)
+	}
+	if err = regState.ResumeRuntime(ctx, rr.ID); err != nil {
+		return fmt.Errorf("failed to resume runtime: %w", err)
+	}
+	rt, err := regState.Runtime(ctx, rr.ID)
+	if err != nil {
+		return err
+	}
+
+	// Runtimes suspended at genesis do not have any per-runtime state.
+	_, err = state.RuntimeState(ctx, rr.ID)
+	switch err {
+	case nil:
+	case roothash.ErrInvalidRuntime:
+		if err = app.onNewRuntime(ctx, rt, nil); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("failed to fetch runtime state: %w", err)
+	}
+
+	// The runtime gets new committees (or is suspended again in case its
+	// maintenance fees are not paid) at the next epoch transition.
+	ctx.Logger().Info("ResumeRuntime: runtime resumed",
+		"runtime_id", rr.ID,
+		"signer", ctx.TxSigner(),
+	)
+
+	ctx.EmitEvent(abciAPI.NewEventBuilder(registryapp.AppName).Attribute(registryapp.KeyRuntimeRegistered, cbor.Marshal(rt)))
+	tagV := ValueRuntimeSuspension{
+		ID:    rr.ID,
+		Event: roothash.RuntimeSuspensionEvent{Suspended: false},
+	}
+	ctx.EmitEvent(abciAPI.NewEventBuilder(app.Name()).Attribute(KeyRuntimeSuspension, cbor.Marshal(tagV)))
+
+	return nil
+}
+
+func (app *rootHashApplication) debugForceRoundTimeout(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	fr *roothash.DebugForceRoundTimeout,
+) error {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("DebugForceRoundTimeout: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if !params.DebugAllowForceRoundTimeout {
+		return roothash.ErrForbidden
+	}
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	rtState, _, _, err := app.getRuntimeState(ctx, state, fr.ID)
+	if err != nil {
+		return err
+	}
+
+	// Make all armed timeouts of the current round expire immediately.
+	now := ctx.Now()
+	var armed bool
+	if !rtState.Round.MergePool.NextTimeout.IsZero() {
+		rtState.Round.MergePool.NextTimeout = now
+		armed = true
+	}
+	for _, pool := range rtState.Round.ExecutorPool.Committees {
+		if !pool.NextTimeout.IsZero() {
+			pool.NextTimeout = now
+			armed = true
+		}
+	}
+	if !armed {
+		return fmt.Errorf("%w: no round timeout is armed", roothash.ErrNoRound)
+	}
+
+	ctx.Logger().Warn("DebugForceRoundTimeout: forcing round timeout",
+		"runtime_id", fr.ID,
+		"round", rtState.CurrentBlock.Header.Round,
+	)
+
+	if err = app.processRoundTimeouts(ctx, rtState); err != nil {
+		return err
+	}
+
+	if err = state.SetRuntimeState(ctx, rtState); err != nil {
+		return fmt.Errorf("failed to set runtime state: %w", err)
+	}
+
+	return nil
+}
+
+func (app *rootHashApplication) submitEvidence(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	ev *roothash.Evidence,
+) error {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("SubmitEvidence: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, roothash.GasOpSubmitEvidence, params.GasCosts); err != nil {
+		return err
+	}
+
+	nodeID, roundHash, err := ev.Verify()
+	if err != nil {
+		ctx.Logger().Error("SubmitEvidence: invalid evidence",
+			"err", err,
+			"runtime_id", ev.ID,
+		)
+		return err
+	}
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	if _, err = state.RuntimeState(ctx, ev.ID); err != nil {
+		return err
+	}
+	processed, err := state.EvidenceProcessed(ctx, nodeID, roundHash)
+	if err != nil {
+		return err
+	}
+	if processed {
+		return roothash.ErrDuplicateEvidence
+	}
+
+	// Note that in order for this to work even in light of node expirations,
+	// the node descriptor must be available for at least the debonding period
+	// after expiration.
+	regState := registryState.NewMutableState(ctx.State())
+	node, err := regState.Node(ctx, nodeID)
+	switch err {
+	case nil:
+	case registry.ErrNoSuchNode:
+		return fmt.Errorf("%w: unknown node", roothash.ErrInvalidEvidence)
+	default:
+		return err
+	}
+
+	ctx.Logger().Warn("SubmitEvidence: executor node equivocation detected",
+		"runtime_id", ev.ID,
+		"node_id", nodeID,
+		"entity_id", node.EntityID,
+		"previous_hash", roundHash,
+	)
+
+	if !params.DebugBypassStake {
+		if err = stakingState.SlashNode(ctx, node, staking.SlashRuntimeEquivocation); err != nil {
+			return err
+		}
+	}
+
+	if err = state.SetEvidenceProcessed(ctx, nodeID, roundHash); err != nil {
+		return fmt.Errorf("failed to set evidence processed: %w", err)
+	}
+
+	return nil
+}
@@ -23,4 +23,13 @@ var (
 	// KeyElected is the ABCI event attribute key for the elected
 	// committee types.
 	KeyElected = []byte("elected")
+
+	// KeyUpcomingElected is the ABCI event attribute key for the committee
+	// types elected ahead of the next epoch.
+	KeyUpcomingElected = []byte("upcoming_elected")
+
+	// KeyElectionFailed is the ABCI event attribute key for failed
+	// committee elections (value is a CBOR serialized
+	// scheduler.ElectionFailedEvent).
+	KeyElectionFailed = []byte("election_failed")
 )
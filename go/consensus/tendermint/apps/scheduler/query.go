@@ -15,6 +15,8 @@ type Query interface {
 	Validators(context.Context) ([]*scheduler.Validator, error)
 	AllCommittees(context.Context) ([]*scheduler.Committee, error)
 	KindsCommittees(context.Context, []scheduler.CommitteeKind) ([]*scheduler.Committee, error)
+	AllUpcomingCommittees(context.Context) ([]*scheduler.Committee, error)
+	KindsUpcomingCommittees(context.Context, []scheduler.CommitteeKind) ([]*scheduler.Committee, error)
 	Genesis(context.Context) (*scheduler.Genesis, error)
 }
 
@@ -98,6 +100,14 @@ func (sq *schedulerQuerier) KindsCommittees(ctx context.Context, kinds []schedul
 	return sq.state.KindsCommittees(ctx, kinds)
 }
 
+func (sq *schedulerQuerier) AllUpcomingCommittees(ctx context.Context) ([]*scheduler.Committee, error) {
+	return sq.state.AllUpcomingCommittees(ctx)
+}
+
+func (sq *schedulerQuerier) KindsUpcomingCommittees(ctx context.Context, kinds []scheduler.CommitteeKind) ([]*scheduler.Committee, error) {
+	return sq.state.KindsUpcomingCommittees(ctx, kinds)
+}
+
 func (app *schedulerApplication) QueryFactory() interface{} {
 	return &QueryFactory{app.state}
 }
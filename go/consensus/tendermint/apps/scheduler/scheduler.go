@@ -2,7 +2,9 @@ package scheduler
 
 import (
 	"bytes"
+	"context"
 	"crypto"
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"sort"
@@ -12,6 +14,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/drbg"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/mathrand"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/logging"
@@ -41,6 +44,7 @@ var (
 	RNGContextMerge                = []byte("EkS-ABCI-Merge")
 	RNGContextValidators           = []byte("EkS-ABCI-Validators")
 	RNGContextEntities             = []byte("EkS-ABCI-Entities")
+	RNGContextUpcoming             = []byte("EkS-ABCI-Upcoming")
 )
 
 type schedulerApplication struct {
@@ -84,6 +88,10 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 	// TODO: We'll later have this for each type of committee.
 	epochChanged, epoch := app.state.EpochChanged(ctx)
 
+	if err := app.electUpcomingCommittees(ctx, epoch, slashed); err != nil {
+		return fmt.Errorf("tendermint/scheduler: couldn't elect upcoming committees: %w", err)
+	}
+
 	if epochChanged || slashed {
 		// The 0th epoch will not have suitable entropy for elections, nor
 		// will it have useful node registrations.
@@ -105,30 +113,9 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 		if err != nil {
 			return fmt.Errorf("tendermint/scheduler: couldn't get runtimes: %w", err)
 		}
-		allNodes, err := regState.Nodes(ctx)
+		nodes, nodeStatuses, err := schedulableNodes(ctx, regState.ImmutableState, epoch)
 		if err != nil {
-			return fmt.Errorf("tendermint/scheduler: couldn't get nodes: %w", err)
-		}
-
-		// Filter nodes.
-		var nodes []*node.Node
-		for _, node := range allNodes {
-			var status *registry.NodeStatus
-			status, err = regState.NodeStatus(ctx, node.ID)
-			if err != nil {
-				return fmt.Errorf("tendermint/scheduler: couldn't get node status: %w", err)
-			}
-
-			// Nodes which are currently frozen cannot be scheduled.
-			if status.IsFrozen() {
-				continue
-			}
-			// Expired nodes cannot be scheduled (nodes can be expired and not yet removed).
-			if node.IsExpired(uint64(epoch)) {
-				continue
-			}
-
-			nodes = append(nodes, node)
+			return err
 		}
 
 		state := schedulerState.NewMutableState(ctx.State())
@@ -173,12 +160,19 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 			scheduler.KindStorage,
 		}
 		for _, kind := range kinds {
-			if err = app.electAllCommittees(ctx, request, epoch, beacon, stakeAcc, entitiesEligibleForReward, runtimes, nodes, kind); err != nil {
+			if err = app.electAllCommittees(ctx, request, epoch, epochChanged, beacon, stakeAcc, entitiesEligibleForReward, runtimes, nodes, nodeStatuses, kind, params); err != nil {
 				return fmt.Errorf("tendermint/scheduler: couldn't elect %s committees: %w", kind, err)
 			}
 		}
 		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyElected, cbor.Marshal(kinds)))
 
+		// Upcoming committees have either been promoted or are stale.
+		if epochChanged {
+			if err = dropUpcomingCommittees(ctx, state); err != nil {
+				return fmt.Errorf("tendermint/scheduler: couldn't drop upcoming committees: %w", err)
+			}
+		}
+
 		var kindNames []string
 		for _, kind := range kinds {
 			kindNames = append(kindNames, kind.String())
@@ -204,6 +198,201 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 	return nil
 }
 
+// upcomingElectionBeacon derives the beacon used for electing committees
+// for the given epoch ahead of time from the beacon of the current epoch, so
+// that the upcoming committees differ from the current ones.
+//
+// Note that unlike the beacon used at the start of an epoch, the derived
+// beacon is known for the whole epoch before the upcoming committees are
+// elected. Entities can therefore predict the outcome of the election and
+// try to influence it by (de)registering nodes in the meantime, so the
+// election offset should only be configured on networks where this is an
+// acceptable trade-off for the reduced epoch transition latency.
+func upcomingElectionBeacon(beacon []byte, epoch epochtime.EpochTime) []byte {
+	var rawEpoch [8]byte
+	binary.BigEndian.PutUint64(rawEpoch[:], uint64(epoch))
+	h := hash.NewFromBytes(RNGContextUpcoming, beacon, rawEpoch[:])
+	return h[:]
+}
+
+// upcomingElectionEpoch returns the epoch for which upcoming committees
+// should be elected in the current block, or EpochInvalid if there is no
+// such epoch.
+//
+// Upcoming committees are elected in the block at which the start of the
+// next epoch is exactly the election offset away, and are re-elected in
+// later blocks of the same epoch whenever the current committees are.
+func (app *schedulerApplication) upcomingElectionEpoch(
+	ctx *api.Context,
+	epoch epochtime.EpochTime,
+	reelect bool,
+	params *scheduler.ConsensusParameters,
+) (epochtime.EpochTime, error) {
+	if params.ElectionOffset == 0 || epoch == epochtime.EpochInvalid || epoch == app.baseEpoch {
+		return epochtime.EpochInvalid, nil
+	}
+
+	// The block being processed follows the last committed one.
+	height := app.state.BlockHeight() + 1
+	nextEpoch, err := app.state.GetEpoch(ctx, height+int64(params.ElectionOffset))
+	if err != nil {
+		return epochtime.EpochInvalid, fmt.Errorf("couldn't get epoch: %w", err)
+	}
+	if nextEpoch != epoch+1 {
+		return epochtime.EpochInvalid, nil
+	}
+	if reelect {
+		return nextEpoch, nil
+	}
+
+	prevEpoch, err := app.state.GetEpoch(ctx, height+int64(params.ElectionOffset)-1)
+	if err != nil {
+		return epochtime.EpochInvalid, fmt.Errorf("couldn't get epoch: %w", err)
+	}
+	if prevEpoch == nextEpoch {
+		return epochtime.EpochInvalid, nil
+	}
+	return nextEpoch, nil
+}
+
+// electUpcomingCommittees elects the runtime committees for the next epoch
+// ahead of time if configured by the election offset, so that committee
+// nodes can prepare before the epoch starts.
+//
+// Upcoming committees that fail to be elected are not stored and are
+// instead elected at the start of the epoch.
+func (app *schedulerApplication) electUpcomingCommittees(ctx *api.Context, epoch epochtime.EpochTime, reelect bool) error {
+	state := schedulerState.NewMutableState(ctx.State())
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	nextEpoch, err := app.upcomingElectionEpoch(ctx, epoch, reelect, params)
+	if err != nil || nextEpoch == epochtime.EpochInvalid {
+		return err
+	}
+
+	beacState := beaconState.NewMutableState(ctx.State())
+	beacon, err := beacState.Beacon(ctx)
+	if err != nil {
+		return fmt.Errorf("couldn't get beacon: %w", err)
+	}
+	beacon = upcomingElectionBeacon(beacon, nextEpoch)
+
+	regState := registryState.NewMutableState(ctx.State())
+	runtimes, err := regState.Runtimes(ctx)
+	if err != nil {
+		return fmt.Errorf("couldn't get runtimes: %w", err)
+	}
+	nodes, nodeStatuses, err := schedulableNodes(ctx, regState.ImmutableState, nextEpoch)
+	if err != nil {
+		return err
+	}
+
+	var stakeAcc *stakingState.StakeAccumulatorCache
+	if !params.DebugBypassStake {
+		stakeAcc, err = stakingState.NewStakeAccumulatorCache(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create stake accumulator cache: %w", err)
+		}
+		defer stakeAcc.Discard()
+	}
+
+	kinds := []scheduler.CommitteeKind{
+		scheduler.KindComputeExecutor,
+		scheduler.KindComputeTxnScheduler,
+		scheduler.KindComputeMerge,
+		scheduler.KindStorage,
+	}
+	for _, kind := range kinds {
+		for _, rt := range runtimes {
+			var (
+				members []*scheduler.CommitteeNode
+				reason  scheduler.ElectionFailureReason
+			)
+			members, reason, err = electCommitteeMembers(ctx, nextEpoch, beacon, stakeAcc, nil, rt, nodes, nodeStatuses, kind, params)
+			switch {
+			case err != nil:
+				return err
+			case reason != scheduler.ElectionFailureInvalid || members == nil:
+				if err = state.DropUpcomingCommittee(ctx, kind, rt.ID); err != nil {
+					return fmt.Errorf("failed to drop upcoming committee: %w", err)
+				}
+				continue
+			}
+
+			err = state.PutUpcomingCommittee(ctx, &scheduler.Committee{
+				Kind:      kind,
+				RuntimeID: rt.ID,
+				Members:   members,
+				ValidFor:  nextEpoch,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save upcoming committee: %w", err)
+			}
+		}
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyUpcomingElected, cbor.Marshal(kinds)))
+
+	ctx.Logger().Debug("finished electing upcoming committees",
+		"epoch", epoch,
+		"upcoming_epoch", nextEpoch,
+	)
+
+	return nil
+}
+
+// dropUpcomingCommittees removes all committees elected ahead of the next
+// epoch.
+func dropUpcomingCommittees(ctx *api.Context, state *schedulerState.MutableState) error {
+	committees, err := state.AllUpcomingCommittees(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range committees {
+		if err = state.DropUpcomingCommittee(ctx, c.Kind, c.RuntimeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schedulableNodes returns the registered nodes that can be elected into
+// committees in the given epoch, together with their statuses.
+func schedulableNodes(
+	ctx context.Context,
+	regState *registryState.ImmutableState,
+	epoch epochtime.EpochTime,
+) ([]*node.Node, map[signature.PublicKey]*registry.NodeStatus, error) {
+	allNodes, err := regState.Nodes(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tendermint/scheduler: couldn't get nodes: %w", err)
+	}
+
+	var nodes []*node.Node
+	nodeStatuses := make(map[signature.PublicKey]*registry.NodeStatus)
+	for _, node := range allNodes {
+		var status *registry.NodeStatus
+		status, err = regState.NodeStatus(ctx, node.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tendermint/scheduler: couldn't get node status: %w", err)
+		}
+
+		// Nodes which are currently frozen cannot be scheduled.
+		if status.IsFrozen() {
+			continue
+		}
+		// Expired nodes cannot be scheduled (nodes can be expired and not yet removed).
+		if node.IsExpired(uint64(epoch)) {
+			continue
+		}
+
+		nodes = append(nodes, node)
+		nodeStatuses[node.ID] = status
+	}
+	return nodes, nodeStatuses, nil
+}
+
 func (app *schedulerApplication) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
 	return fmt.Errorf("tendermint/scheduler: unexpected transaction")
 }
@@ -284,7 +473,7 @@ func (app *schedulerApplication) FireTimer(ctx *api.Context, t *abci.Timer) erro
 	return fmt.Errorf("tendermint/scheduler: unexpected timer")
 }
 
-func (app *schedulerApplication) isSuitableExecutorWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+func isSuitableExecutorWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
 	if !n.HasRoles(node.RoleComputeWorker) {
 		return false
 	}
@@ -320,7 +509,7 @@ func (app *schedulerApplication) isSuitableExecutorWorker(ctx *api.Context, n *n
 	return false
 }
 
-func (app *schedulerApplication) isSuitableStorageWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+func isSuitableStorageWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
 	if !n.HasRoles(node.RoleStorageWorker) {
 		return false
 	}
@@ -333,7 +522,7 @@ func (app *schedulerApplication) isSuitableStorageWorker(ctx *api.Context, n *no
 	return false
 }
 
-func (app *schedulerApplication) isSuitableTransactionScheduler(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+func isSuitableTransactionScheduler(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
 	if !n.HasRoles(node.RoleComputeWorker) {
 		return false
 	}
@@ -346,7 +535,7 @@ func (app *schedulerApplication) isSuitableTransactionScheduler(ctx *api.Context
 	return false
 }
 
-func (app *schedulerApplication) isSuitableMergeWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+func isSuitableMergeWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
 	if !n.HasRoles(node.RoleComputeWorker) {
 		return false
 	}
@@ -359,6 +548,60 @@ func (app *schedulerApplication) isSuitableMergeWorker(ctx *api.Context, n *node
 	return false
 }
 
+// suitabilityFn returns the function used to check whether a node is suitable
+// for a committee of the given kind.
+func suitabilityFn(kind scheduler.CommitteeKind) func(*api.Context, *node.Node, *registry.Runtime) bool {
+	switch kind {
+	case scheduler.KindComputeExecutor:
+		return isSuitableExecutorWorker
+	case scheduler.KindComputeMerge:
+		return isSuitableMergeWorker
+	case scheduler.KindComputeTxnScheduler:
+		return isSuitableTransactionScheduler
+	case scheduler.KindStorage:
+		return isSuitableStorageWorker
+	default:
+		return func(*api.Context, *node.Node, *registry.Runtime) bool { return false }
+	}
+}
+
+// upcomingMembersEligible returns true iff all members of a committee that
+// was elected ahead of the given epoch are still schedulable and eligible for
+// the committee at the start of the epoch.
+func upcomingMembersEligible(
+	ctx *api.Context,
+	epoch epochtime.EpochTime,
+	stakeAcc *stakingState.StakeAccumulatorCache,
+	rt *registry.Runtime,
+	nodes []*node.Node,
+	nodeStatuses map[signature.PublicKey]*registry.NodeStatus,
+	upcoming *scheduler.Committee,
+	params *scheduler.ConsensusParameters,
+) bool {
+	nodesByID := make(map[signature.PublicKey]*node.Node)
+	for _, n := range nodes {
+		nodesByID[n.ID] = n
+	}
+
+	isSuitableFn := suitabilityFn(upcoming.Kind)
+	for _, member := range upcoming.Members {
+		n := nodesByID[member.PublicKey]
+		if n == nil || !isSuitableFn(ctx, n, rt) {
+			return false
+		}
+		if stakeAcc != nil && stakeAcc.CheckStakeClaims(n.EntityID) != nil {
+			return false
+		}
+		if params.RuntimeReadinessPolicy == scheduler.ReadinessPolicyRequire {
+			status := nodeStatuses[n.ID]
+			if status == nil || !status.IsRuntimeReady(rt.ID, epoch) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // GetPerm generates a permutation that we use to choose nodes from a list of eligible nodes to elect.
 func GetPerm(beacon []byte, runtimeID common.Namespace, rngCtx []byte, nrNodes int) ([]int, error) {
 	drbg, err := drbg.New(crypto.SHA512, beacon, runtimeID[:], rngCtx)
@@ -375,16 +618,86 @@ func GetPerm(beacon []byte, runtimeID common.Namespace, rngCtx []byte, nrNodes i
 func (app *schedulerApplication) electCommittee(
 	ctx *api.Context,
 	epoch epochtime.EpochTime,
+	epochChanged bool,
 	beacon []byte,
 	stakeAcc *stakingState.StakeAccumulatorCache,
 	entitiesEligibleForReward map[signature.PublicKey]bool,
 	rt *registry.Runtime,
 	nodes []*node.Node,
+	nodeStatuses map[signature.PublicKey]*registry.NodeStatus,
 	kind scheduler.CommitteeKind,
+	params *scheduler.ConsensusParameters,
 ) error {
+	state := schedulerState.NewMutableState(ctx.State())
+	var upcoming *scheduler.Committee
+	if epochChanged {
+		var err error
+		if upcoming, err = state.UpcomingCommittee(ctx, kind, rt.ID); err != nil {
+			return fmt.Errorf("failed to fetch upcoming committee: %w", err)
+		}
+		if upcoming != nil && upcoming.ValidFor != epoch {
+			upcoming = nil
+		}
+		if upcoming != nil && !upcomingMembersEligible(ctx, epoch, stakeAcc, rt, nodes, nodeStatuses, upcoming, params) {
+			// Some members are no longer eligible (e.g., their nodes expired
+			// or their entities were slashed since the committee was elected),
+			// so fall back to the committee elected at the start of the epoch.
+			ctx.Logger().Warn("upcoming committee members no longer eligible, not promoting",
+				"kind", kind,
+				"runtime_id", rt.ID,
+				"epoch", epoch,
+			)
+			upcoming = nil
+		}
+	}
+
+	members, reason, err := electCommitteeMembers(ctx, epoch, beacon, stakeAcc, entitiesEligibleForReward, rt, nodes, nodeStatuses, kind, params)
+	switch {
+	case err != nil:
+		return err
+	case upcoming != nil:
+		// The committee was elected ahead of the epoch so that its members
+		// could prepare for it, the election above only determines the
+		// entities that are eligible for rewards.
+		members = upcoming.Members
+	case reason != scheduler.ElectionFailureInvalid:
+		return app.handleElectionFailure(ctx, epoch, rt, kind, params, reason)
+	case members == nil:
+		// The runtime does not need a committee of this kind.
+		return nil
+	}
+
+	err = state.PutCommittee(ctx, &scheduler.Committee{
+		Kind:      kind,
+		RuntimeID: rt.ID,
+		Members:   members,
+		ValidFor:  epoch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save committee: %w", err)
+	}
+	return nil
+}
+
+// electCommitteeMembers runs the election of the given committee without
+// modifying the state, returning either the elected members or the reason
+// why the election failed. No members and no failure reason are returned
+// if the runtime does not need a committee of the given kind.
+func electCommitteeMembers(
+	ctx *api.Context,
+	epoch epochtime.EpochTime,
+	beacon []byte,
+	stakeAcc *stakingState.StakeAccumulatorCache,
+	entitiesEligibleForReward map[signature.PublicKey]bool,
+	rt *registry.Runtime,
+	nodes []*node.Node,
+	nodeStatuses map[signature.PublicKey]*registry.NodeStatus,
+	kind scheduler.CommitteeKind,
+	params *scheduler.ConsensusParameters,
+) ([]*scheduler.CommitteeNode, scheduler.ElectionFailureReason, error) {
 	// Only generic compute runtimes need to elect all the committees.
 	if !rt.IsCompute() && kind != scheduler.KindComputeExecutor {
-		return nil
+		return nil, scheduler.ElectionFailureInvalid, nil
 	}
 
 	// Determine the context, committee size, and pre-filter the node-list
@@ -392,8 +705,7 @@ func (app *schedulerApplication) electCommittee(
 	var (
 		nodeList []*node.Node
 
-		rngCtx       []byte
-		isSuitableFn func(*api.Context, *node.Node, *registry.Runtime) bool
+		rngCtx []byte
 
 		workerSize, backupSize int
 	)
@@ -401,29 +713,26 @@ func (app *schedulerApplication) electCommittee(
 	switch kind {
 	case scheduler.KindComputeExecutor:
 		rngCtx = RNGContextExecutor
-		isSuitableFn = app.isSuitableExecutorWorker
 		workerSize = int(rt.Executor.GroupSize)
 		backupSize = int(rt.Executor.GroupBackupSize)
 	case scheduler.KindComputeMerge:
 		rngCtx = RNGContextMerge
-		isSuitableFn = app.isSuitableMergeWorker
 		workerSize = int(rt.Merge.GroupSize)
 		backupSize = int(rt.Merge.GroupBackupSize)
 	case scheduler.KindComputeTxnScheduler:
 		rngCtx = RNGContextTransactionScheduler
-		isSuitableFn = app.isSuitableTransactionScheduler
 		workerSize = int(rt.TxnScheduler.GroupSize)
 	case scheduler.KindStorage:
 		rngCtx = RNGContextStorage
-		isSuitableFn = app.isSuitableStorageWorker
 		workerSize = int(rt.Storage.GroupSize)
 	default:
-		return fmt.Errorf("tendermint/scheduler: invalid committee type: %v", kind)
+		return nil, scheduler.ElectionFailureInvalid, fmt.Errorf("tendermint/scheduler: invalid committee type: %v", kind)
 	}
+	isSuitableFn := suitabilityFn(kind)
 
 	needsLeader, err := kind.NeedsLeader()
 	if err != nil {
-		return fmt.Errorf("tendermint/scheduler: error while calling needsLeader() on kind %v: %w", kind, err)
+		return nil, scheduler.ElectionFailureInvalid, fmt.Errorf("tendermint/scheduler: error while calling needsLeader() on kind %v: %w", kind, err)
 	}
 
 	for _, n := range nodes {
@@ -441,50 +750,89 @@ func (app *schedulerApplication) electCommittee(
 		}
 	}
 
+	isReady := func(n *node.Node) bool {
+		status := nodeStatuses[n.ID]
+		return status != nil && status.IsRuntimeReady(rt.ID, epoch)
+	}
+
+	// Only consider nodes that have attested readiness for the runtime if
+	// required by the readiness policy.
+	var nrNotReady int
+	if params.RuntimeReadinessPolicy == scheduler.ReadinessPolicyRequire {
+		var readyNodes []*node.Node
+		for _, n := range nodeList {
+			if isReady(n) {
+				readyNodes = append(readyNodes, n)
+			}
+		}
+		nrNotReady = len(nodeList) - len(readyNodes)
+		nodeList = readyNodes
+	}
+
 	// Ensure that it is theoretically possible to elect a valid committee.
 	if workerSize == 0 {
 		ctx.Logger().Error("empty committee not allowed",
 			"kind", kind,
 			"runtime_id", rt.ID,
 		)
-		if err = schedulerState.NewMutableState(ctx.State()).DropCommittee(ctx, kind, rt.ID); err != nil {
-			return fmt.Errorf("failed to drop committee: %w", err)
-		}
-		return nil
+		return nil, scheduler.ElectionFailureEmptyCommittee, nil
 	}
 
 	nrNodes, wantedNodes := len(nodeList), workerSize+backupSize
 	if wantedNodes > nrNodes {
+		reason := scheduler.ElectionFailureInsufficientNodes
+		if wantedNodes <= nrNodes+nrNotReady {
+			reason = scheduler.ElectionFailureInsufficientReadyNodes
+		}
 		ctx.Logger().Error("committee size exceeds available nodes (pre-stake)",
 			"kind", kind,
 			"runtime_id", rt.ID,
 			"worker_size", workerSize,
 			"backup_size", backupSize,
 			"nr_nodes", nrNodes,
+			"nr_not_ready", nrNotReady,
 		)
-		if err = schedulerState.NewMutableState(ctx.State()).DropCommittee(ctx, kind, rt.ID); err != nil {
-			return fmt.Errorf("failed to drop committee: %w", err)
-		}
-		return nil
+		return nil, reason, nil
 	}
 
 	// Do the actual election.
 	idxs, err := GetPerm(beacon, rt.ID, rngCtx, nrNodes)
 	if err != nil {
-		return err
+		return nil, scheduler.ElectionFailureInvalid, err
+	}
+	if params.RuntimeReadinessPolicy == scheduler.ReadinessPolicyPrefer {
+		// Consider nodes that have attested readiness for the runtime first,
+		// while otherwise keeping the order of the permutation.
+		sort.SliceStable(idxs, func(i, j int) bool {
+			return isReady(nodeList[idxs[i]]) && !isReady(nodeList[idxs[j]])
+		})
 	}
 
-	var members []*scheduler.CommitteeNode
+	var (
+		members       []*scheduler.CommitteeNode
+		entityMembers = make(map[signature.PublicKey]int)
+		skipped       bool
+	)
 	for i := 0; i < len(idxs); i++ {
+		n := nodeList[idxs[i]]
+
+		// Skip nodes of entities that already have as many committee
+		// members as they are allowed to.
+		if params.MaxCommitteeMembersPerEntity > 0 && entityMembers[n.EntityID] >= params.MaxCommitteeMembersPerEntity {
+			skipped = true
+			continue
+		}
+		entityMembers[n.EntityID]++
+
 		role := scheduler.Worker
-		if i == 0 && needsLeader {
+		if len(members) == 0 && needsLeader {
 			role = scheduler.Leader
-		} else if i >= workerSize {
+		} else if len(members) >= workerSize {
 			role = scheduler.BackupWorker
 		}
 		members = append(members, &scheduler.CommitteeNode{
 			Role:      role,
-			PublicKey: nodeList[idxs[i]].ID,
+			PublicKey: n.ID,
 		})
 		if len(members) >= wantedNodes {
 			break
@@ -492,28 +840,65 @@ func (app *schedulerApplication) electCommittee(
 	}
 
 	if len(members) != wantedNodes {
+		reason := scheduler.ElectionFailureInsufficientNodes
+		if skipped {
+			reason = scheduler.ElectionFailureInsufficientDiversity
+		}
 		ctx.Logger().Error("insufficent nodes with adequate stake to elect",
 			"kind", kind,
 			"runtime_id", rt.ID,
 			"worker_size", workerSize,
 			"backup_size", backupSize,
 			"available", len(members),
+			"reason", reason,
 		)
-		if err = schedulerState.NewMutableState(ctx.State()).DropCommittee(ctx, kind, rt.ID); err != nil {
-			return fmt.Errorf("failed to drop committee: %w", err)
-		}
-		return nil
+		return nil, reason, nil
 	}
 
-	err = schedulerState.NewMutableState(ctx.State()).PutCommittee(ctx, &scheduler.Committee{
+	return members, scheduler.ElectionFailureInvalid, nil
+}
+
+// handleElectionFailure either keeps the previous committee for the given
+// epoch or drops it, depending on the consensus parameters, and emits an
+// election failed event.
+func (app *schedulerApplication) handleElectionFailure(
+	ctx *api.Context,
+	epoch epochtime.EpochTime,
+	rt *registry.Runtime,
+	kind scheduler.CommitteeKind,
+	params *scheduler.ConsensusParameters,
+	reason scheduler.ElectionFailureReason,
+) error {
+	state := schedulerState.NewMutableState(ctx.State())
+	ev := &scheduler.ElectionFailedEvent{
 		Kind:      kind,
 		RuntimeID: rt.ID,
-		Members:   members,
-		ValidFor:  epoch,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to save committee: %w", err)
+		Epoch:     epoch,
+		Reason:    reason,
 	}
+
+	if params.KeepCommitteeOnElectionFailure {
+		committee, err := state.Committee(ctx, kind, rt.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch previous committee: %w", err)
+		}
+		if committee != nil {
+			// Extend the validity of the previous committee, so that
+			// the committee nodes keep working with it.
+			committee.ValidFor = epoch
+			if err = state.PutCommittee(ctx, committee); err != nil {
+				return fmt.Errorf("failed to save previous committee: %w", err)
+			}
+			ev.KeptPrevious = true
+		}
+	}
+	if !ev.KeptPrevious {
+		if err := state.DropCommittee(ctx, kind, rt.ID); err != nil {
+			return fmt.Errorf("failed to drop committee: %w", err)
+		}
+	}
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyElectionFailed, cbor.Marshal(ev)))
 	return nil
 }
 
@@ -522,15 +907,18 @@ func (app *schedulerApplication) electAllCommittees(
 	ctx *api.Context,
 	request types.RequestBeginBlock,
 	epoch epochtime.EpochTime,
+	epochChanged bool,
 	beacon []byte,
 	stakeAcc *stakingState.StakeAccumulatorCache,
 	entitiesEligibleForReward map[signature.PublicKey]bool,
 	runtimes []*registry.Runtime,
 	nodes []*node.Node,
+	nodeStatuses map[signature.PublicKey]*registry.NodeStatus,
 	kind scheduler.CommitteeKind,
+	params *scheduler.ConsensusParameters,
 ) error {
 	for _, runtime := range runtimes {
-		if err := app.electCommittee(ctx, epoch, beacon, stakeAcc, entitiesEligibleForReward, runtime, nodes, kind); err != nil {
+		if err := app.electCommittee(ctx, epoch, epochChanged, beacon, stakeAcc, entitiesEligibleForReward, runtime, nodes, nodeStatuses, kind, params); err != nil {
 			return err
 		}
 	}
@@ -1,14 +1,25 @@
 package scheduler
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/abci/types"
 
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	schedulerState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/scheduler/state"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
 )
 
 func TestDiffValidators(t *testing.T) {
@@ -59,3 +70,309 @@ func TestDiffValidators(t *testing.T) {
 		require.Equal(t, tt.result, diffValidators(logger, tt.current, tt.pending), tt.msg)
 	}
 }
+
+func TestElectCommitteeDiversity(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := api.NewMockApplicationState(api.MockApplicationStateConfig{})
+	ctx := appState.NewContext(api.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	app := &schedulerApplication{state: appState}
+	state := schedulerState.NewMutableState(ctx.State())
+
+	rt := &registry.Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/scheduler: runtime"), 0),
+		Kind: registry.KindCompute,
+	}
+	rt.Executor.GroupSize = 2
+
+	// Three nodes belong to the first entity and one to the second.
+	var nodes []*node.Node
+	for i, entitySeed := range []string{"entity 1", "entity 1", "entity 1", "entity 2"} {
+		n := &node.Node{
+			ID:       memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/scheduler: node %d", i)).Public(),
+			EntityID: memorySigner.NewTestSigner("consensus/tendermint/apps/scheduler: " + entitySeed).Public(),
+			Roles:    node.RoleComputeWorker,
+			Runtimes: []*node.Runtime{{ID: rt.ID}},
+		}
+		nodes = append(nodes, n)
+	}
+	beacon := []byte("consensus/tendermint/apps/scheduler: beacon")
+	params := &scheduler.ConsensusParameters{
+		MaxCommitteeMembersPerEntity: 1,
+	}
+
+	electionFailures := func() []*scheduler.ElectionFailedEvent {
+		var evs []*scheduler.ElectionFailedEvent
+		for _, ev := range ctx.GetEvents() {
+			for _, pair := range ev.GetAttributes() {
+				if !bytes.Equal(pair.GetKey(), KeyElectionFailed) {
+					continue
+				}
+				var failure scheduler.ElectionFailedEvent
+				require.NoError(cbor.Unmarshal(pair.GetValue(), &failure), "election failed event should unmarshal")
+				evs = append(evs, &failure)
+			}
+		}
+		return evs
+	}
+
+	// There are enough diverse nodes for a committee of two.
+	err := app.electCommittee(ctx, 1, false, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err := state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.NotNil(committee, "committee should be elected")
+	require.Len(committee.Members, 2, "committee should have all members")
+	require.EqualValues(1, committee.ValidFor)
+	require.Empty(electionFailures(), "no election failed events should be emitted")
+
+	entities := make(map[signature.PublicKey]bool)
+	for _, m := range committee.Members {
+		for _, n := range nodes {
+			if n.ID.Equal(m.PublicKey) {
+				require.False(entities[n.EntityID], "committee members should belong to distinct entities")
+				entities[n.EntityID] = true
+			}
+		}
+	}
+
+	// A committee of three would violate the diversity constraints, keep
+	// the previous committee.
+	rt.Executor.GroupSize = 3
+	params.KeepCommitteeOnElectionFailure = true
+	err = app.electCommittee(ctx, 2, false, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	kept, err := state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.NotNil(kept, "previous committee should be kept")
+	require.Equal(committee.Members, kept.Members, "previous committee members should be kept")
+	require.EqualValues(2, kept.ValidFor, "previous committee should be valid for the new epoch")
+
+	failures := electionFailures()
+	require.Len(failures, 1, "election failed event should be emitted")
+	require.Equal(scheduler.ElectionFailedEvent{
+		Kind:         scheduler.KindComputeExecutor,
+		RuntimeID:    rt.ID,
+		Epoch:        2,
+		Reason:       scheduler.ElectionFailureInsufficientDiversity,
+		KeptPrevious: true,
+	}, *failures[0])
+
+	// Without keeping the previous committee, it should be dropped.
+	params.KeepCommitteeOnElectionFailure = false
+	err = app.electCommittee(ctx, 3, false, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err = state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.Nil(committee, "committee should be dropped")
+
+	failures = electionFailures()
+	require.Len(failures, 2, "election failed event should be emitted")
+	require.Equal(scheduler.ElectionFailureInsufficientDiversity, failures[1].Reason)
+	require.False(failures[1].KeptPrevious, "previous committee should not be kept")
+
+	// Without the diversity constraint, more members per entity are allowed.
+	params.MaxCommitteeMembersPerEntity = 0
+	err = app.electCommittee(ctx, 4, false, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err = state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.NotNil(committee, "committee should be elected")
+	require.Len(committee.Members, 3, "committee should have all members")
+	require.Len(electionFailures(), 2, "no new election failed events should be emitted")
+}
+
+func TestElectCommitteeReadiness(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := api.NewMockApplicationState(api.MockApplicationStateConfig{})
+	ctx := appState.NewContext(api.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	app := &schedulerApplication{state: appState}
+	state := schedulerState.NewMutableState(ctx.State())
+
+	rt := &registry.Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/scheduler: runtime"), 0),
+		Kind: registry.KindCompute,
+	}
+	rt.Executor.GroupSize = 2
+
+	// Only the first two nodes have attested readiness, the attestation of
+	// the third node has expired.
+	var nodes []*node.Node
+	nodeStatuses := make(map[signature.PublicKey]*registry.NodeStatus)
+	for i, expiration := range []epochtime.EpochTime{5, 5, 1, 0, 0} {
+		n := &node.Node{
+			ID:       memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/scheduler: node %d", i)).Public(),
+			EntityID: memorySigner.NewTestSigner("consensus/tendermint/apps/scheduler: entity").Public(),
+			Roles:    node.RoleComputeWorker,
+			Runtimes: []*node.Runtime{{ID: rt.ID}},
+		}
+		nodes = append(nodes, n)
+
+		status := &registry.NodeStatus{}
+		if expiration > 0 {
+			status.ReadyRuntimes = map[common.Namespace]epochtime.EpochTime{rt.ID: expiration}
+		}
+		nodeStatuses[n.ID] = status
+	}
+	beacon := []byte("consensus/tendermint/apps/scheduler: beacon")
+	params := &scheduler.ConsensusParameters{}
+
+	requireReadyMembers := func(committee *scheduler.Committee) {
+		require.NotNil(committee, "committee should be elected")
+		require.Len(committee.Members, 2, "committee should have all members")
+		for _, m := range committee.Members {
+			require.True(nodeStatuses[m.PublicKey].IsRuntimeReady(rt.ID, 2), "committee members should be ready")
+		}
+	}
+
+	for _, policy := range []scheduler.ReadinessPolicy{
+		scheduler.ReadinessPolicyPrefer,
+		scheduler.ReadinessPolicyRequire,
+	} {
+		params.RuntimeReadinessPolicy = policy
+		err := app.electCommittee(ctx, 2, false, beacon, nil, nil, rt, nodes, nodeStatuses, scheduler.KindComputeExecutor, params)
+		require.NoError(err, "electCommittee(%s)", policy)
+		committee, err := state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+		require.NoError(err, "Committee")
+		requireReadyMembers(committee)
+	}
+
+	// With backup workers, the preferred policy falls back to nodes that
+	// are not ready, while the required policy fails.
+	rt.Executor.GroupBackupSize = 1
+	params.RuntimeReadinessPolicy = scheduler.ReadinessPolicyPrefer
+	err := app.electCommittee(ctx, 3, false, beacon, nil, nil, rt, nodes, nodeStatuses, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err := state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.NotNil(committee, "committee should be elected")
+	require.Len(committee.Members, 3, "committee should have all members")
+	for _, m := range committee.Members {
+		require.Equal(m.Role == scheduler.BackupWorker, !nodeStatuses[m.PublicKey].IsRuntimeReady(rt.ID, 3),
+			"ready nodes should be elected first")
+	}
+
+	params.RuntimeReadinessPolicy = scheduler.ReadinessPolicyRequire
+	err = app.electCommittee(ctx, 4, false, beacon, nil, nil, rt, nodes, nodeStatuses, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err = state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.Nil(committee, "committee should be dropped")
+
+	var reason scheduler.ElectionFailureReason
+	for _, ev := range ctx.GetEvents() {
+		for _, pair := range ev.GetAttributes() {
+			if !bytes.Equal(pair.GetKey(), KeyElectionFailed) {
+				continue
+			}
+			var failure scheduler.ElectionFailedEvent
+			require.NoError(cbor.Unmarshal(pair.GetValue(), &failure), "election failed event should unmarshal")
+			reason = failure.Reason
+		}
+	}
+	require.Equal(scheduler.ElectionFailureInsufficientReadyNodes, reason, "election should fail due to insufficient ready nodes")
+}
+
+func TestElectCommitteePromotion(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := api.NewMockApplicationState(api.MockApplicationStateConfig{})
+	ctx := appState.NewContext(api.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	app := &schedulerApplication{state: appState}
+	state := schedulerState.NewMutableState(ctx.State())
+
+	rt := &registry.Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/scheduler: runtime"), 0),
+		Kind: registry.KindCompute,
+	}
+	rt.Executor.GroupSize = 1
+
+	var nodes []*node.Node
+	for i := 0; i < 3; i++ {
+		nodes = append(nodes, &node.Node{
+			ID:       memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/scheduler: node %d", i)).Public(),
+			EntityID: memorySigner.NewTestSigner("consensus/tendermint/apps/scheduler: entity").Public(),
+			Roles:    node.RoleComputeWorker,
+			Runtimes: []*node.Runtime{{ID: rt.ID}},
+		})
+	}
+	beacon := []byte("consensus/tendermint/apps/scheduler: beacon")
+	params := &scheduler.ConsensusParameters{}
+
+	// Pick an upcoming committee that differs from the one elected at the
+	// start of the epoch.
+	err := app.electCommittee(ctx, 2, false, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	elected, err := state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.NotNil(elected, "committee should be elected")
+	var upcomingMembers []*scheduler.CommitteeNode
+	for _, n := range nodes {
+		if !n.ID.Equal(elected.Members[0].PublicKey) {
+			upcomingMembers = append(upcomingMembers, &scheduler.CommitteeNode{Role: scheduler.Worker, PublicKey: n.ID})
+			break
+		}
+	}
+
+	// An upcoming committee for a different epoch should be ignored.
+	err = state.PutUpcomingCommittee(ctx, &scheduler.Committee{
+		Kind:      scheduler.KindComputeExecutor,
+		Members:   upcomingMembers,
+		RuntimeID: rt.ID,
+		ValidFor:  3,
+	})
+	require.NoError(err, "PutUpcomingCommittee")
+	err = app.electCommittee(ctx, 2, true, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err := state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.Equal(elected.Members, committee.Members, "upcoming committee for a different epoch should not be promoted")
+
+	// Re-elections within an epoch should not promote upcoming committees.
+	err = app.electCommittee(ctx, 3, false, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err = state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.Equal(elected.Members, committee.Members, "upcoming committee should not be promoted")
+
+	// An upcoming committee with members that are no longer schedulable
+	// should not be promoted.
+	var remainingNodes []*node.Node
+	for _, n := range nodes {
+		if !n.ID.Equal(upcomingMembers[0].PublicKey) {
+			remainingNodes = append(remainingNodes, n)
+		}
+	}
+	err = app.electCommittee(ctx, 3, true, beacon, nil, nil, rt, remainingNodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err = state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.NotEqual(upcomingMembers, committee.Members, "upcoming committee with ineligible members should not be promoted")
+
+	// The upcoming committee should be promoted once the epoch changes.
+	err = app.electCommittee(ctx, 3, true, beacon, nil, nil, rt, nodes, nil, scheduler.KindComputeExecutor, params)
+	require.NoError(err, "electCommittee")
+	committee, err = state.Committee(ctx, scheduler.KindComputeExecutor, rt.ID)
+	require.NoError(err, "Committee")
+	require.Equal(upcomingMembers, committee.Members, "upcoming committee should be promoted")
+	require.EqualValues(3, committee.ValidFor)
+}
+
+func TestUpcomingElectionBeacon(t *testing.T) {
+	require := require.New(t)
+
+	beacon := []byte("consensus/tendermint/apps/scheduler: beacon")
+	require.NotEqual(beacon, upcomingElectionBeacon(beacon, 1), "upcoming beacon should differ from the epoch beacon")
+	require.Equal(upcomingElectionBeacon(beacon, 1), upcomingElectionBeacon(beacon, 1), "upcoming beacon should be deterministic")
+	require.NotEqual(upcomingElectionBeacon(beacon, 1), upcomingElectionBeacon(beacon, 2), "upcoming beacon should depend on the epoch")
+}
@@ -32,6 +32,11 @@ var (
 	//
 	// Value is CBOR-serialized api.ConsensusParameters.
 	parametersKeyFmt = keyformat.New(0x63)
+	// upcomingCommitteeKeyFmt is the key format used for committees elected
+	// ahead of the next epoch.
+	//
+	// Value is CBOR-serialized committee.
+	upcomingCommitteeKeyFmt = keyformat.New(0x64, uint8(0), keyformat.H(&common.Namespace{}))
 )
 
 // ImmutableState is the immutable scheduler state wrapper.
@@ -39,9 +44,8 @@ type ImmutableState struct {
 	is *abciAPI.ImmutableState
 }
 
-// Committee returns a specific elected committee.
-func (s *ImmutableState) Committee(ctx context.Context, kind api.CommitteeKind, runtimeID common.Namespace) (*api.Committee, error) {
-	raw, err := s.is.Get(ctx, committeeKeyFmt.Encode(uint8(kind), &runtimeID))
+func (s *ImmutableState) committee(ctx context.Context, keyFmt *keyformat.KeyFormat, kind api.CommitteeKind, runtimeID common.Namespace) (*api.Committee, error) {
+	raw, err := s.is.Get(ctx, keyFmt.Encode(uint8(kind), &runtimeID))
 	if err != nil {
 		return nil, abciAPI.UnavailableStateError(err)
 	}
@@ -56,44 +60,26 @@ func (s *ImmutableState) Committee(ctx context.Context, kind api.CommitteeKind,
 	return committee, nil
 }
 
-// AllCommittees returns a list of all elected committees.
-func (s *ImmutableState) AllCommittees(ctx context.Context) ([]*api.Committee, error) {
+// committees returns a list of committees stored under the given key format,
+// either of specific kinds or all of them if no kinds are given.
+func (s *ImmutableState) committees(ctx context.Context, keyFmt *keyformat.KeyFormat, kinds []api.CommitteeKind) ([]*api.Committee, error) {
 	it := s.is.NewIterator(ctx)
 	defer it.Close()
 
-	var committees []*api.Committee
-	for it.Seek(committeeKeyFmt.Encode()); it.Valid(); it.Next() {
-		var k uint8
-		var hRuntimeID keyformat.PreHashed
-		if !committeeKeyFmt.Decode(it.Key(), &k, &hRuntimeID) {
-			break
-		}
-
-		var c api.Committee
-		if err := cbor.Unmarshal(it.Value(), &c); err != nil {
-			err = fmt.Errorf("malformed committee %s (kind %d): %w", hRuntimeID, k, err)
-			return nil, abciAPI.UnavailableStateError(err)
+	prefixes := [][]byte{keyFmt.Encode()}
+	if kinds != nil {
+		prefixes = nil
+		for _, kind := range kinds {
+			prefixes = append(prefixes, keyFmt.Encode(uint8(kind)))
 		}
-
-		committees = append(committees, &c)
-	}
-	if it.Err() != nil {
-		return nil, abciAPI.UnavailableStateError(it.Err())
 	}
-	return committees, nil
-}
-
-// KindsCommittees returns a list of all committees of specific kinds.
-func (s *ImmutableState) KindsCommittees(ctx context.Context, kinds []api.CommitteeKind) ([]*api.Committee, error) {
-	it := s.is.NewIterator(ctx)
-	defer it.Close()
 
 	var committees []*api.Committee
-	for _, kind := range kinds {
-		for it.Seek(committeeKeyFmt.Encode(uint8(kind))); it.Valid(); it.Next() {
+	for i, prefix := range prefixes {
+		for it.Seek(prefix); it.Valid(); it.Next() {
 			var k uint8
 			var hRuntimeID keyformat.PreHashed
-			if !committeeKeyFmt.Decode(it.Key(), &k, &hRuntimeID) || k != uint8(kind) {
+			if !keyFmt.Decode(it.Key(), &k, &hRuntimeID) || (kinds != nil && k != uint8(kinds[i])) {
 				break
 			}
 
@@ -112,6 +98,45 @@ func (s *ImmutableState) KindsCommittees(ctx context.Context, kinds []api.Commit
 	return committees, nil
 }
 
+// Committee returns a specific elected committee.
+func (s *ImmutableState) Committee(ctx context.Context, kind api.CommitteeKind, runtimeID common.Namespace) (*api.Committee, error) {
+	return s.committee(ctx, committeeKeyFmt, kind, runtimeID)
+}
+
+// AllCommittees returns a list of all elected committees.
+func (s *ImmutableState) AllCommittees(ctx context.Context) ([]*api.Committee, error) {
+	return s.committees(ctx, committeeKeyFmt, nil)
+}
+
+// KindsCommittees returns a list of all committees of specific kinds.
+func (s *ImmutableState) KindsCommittees(ctx context.Context, kinds []api.CommitteeKind) ([]*api.Committee, error) {
+	if kinds == nil {
+		return nil, nil
+	}
+	return s.committees(ctx, committeeKeyFmt, kinds)
+}
+
+// UpcomingCommittee returns a specific committee elected ahead of the next
+// epoch.
+func (s *ImmutableState) UpcomingCommittee(ctx context.Context, kind api.CommitteeKind, runtimeID common.Namespace) (*api.Committee, error) {
+	return s.committee(ctx, upcomingCommitteeKeyFmt, kind, runtimeID)
+}
+
+// AllUpcomingCommittees returns a list of all committees elected ahead of
+// the next epoch.
+func (s *ImmutableState) AllUpcomingCommittees(ctx context.Context) ([]*api.Committee, error) {
+	return s.committees(ctx, upcomingCommitteeKeyFmt, nil)
+}
+
+// KindsUpcomingCommittees returns a list of all committees of specific kinds
+// elected ahead of the next epoch.
+func (s *ImmutableState) KindsUpcomingCommittees(ctx context.Context, kinds []api.CommitteeKind) ([]*api.Committee, error) {
+	if kinds == nil {
+		return nil, nil
+	}
+	return s.committees(ctx, upcomingCommitteeKeyFmt, kinds)
+}
+
 // CurrentValidators returns a list of current validators.
 func (s *ImmutableState) CurrentValidators(ctx context.Context) (map[signature.PublicKey]int64, error) {
 	raw, err := s.is.Get(ctx, validatorsCurrentKeyFmt.Encode())
@@ -191,6 +216,20 @@ func (s *MutableState) DropCommittee(ctx context.Context, kind api.CommitteeKind
 	return abciAPI.UnavailableStateError(err)
 }
 
+// PutUpcomingCommittee sets a committee elected ahead of the next epoch for
+// a specific runtime.
+func (s *MutableState) PutUpcomingCommittee(ctx context.Context, c *api.Committee) error {
+	err := s.ms.Insert(ctx, upcomingCommitteeKeyFmt.Encode(uint8(c.Kind), &c.RuntimeID), cbor.Marshal(c))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// DropUpcomingCommittee removes a committee of a specific kind elected ahead
+// of the next epoch for a specific runtime.
+func (s *MutableState) DropUpcomingCommittee(ctx context.Context, kind api.CommitteeKind, runtimeID common.Namespace) error {
+	err := s.ms.Remove(ctx, upcomingCommitteeKeyFmt.Encode(uint8(kind), &runtimeID))
+	return abciAPI.UnavailableStateError(err)
+}
+
 // PutCurrentValidators stores the current set of validators.
 func (s *MutableState) PutCurrentValidators(ctx context.Context, validators map[signature.PublicKey]int64) error {
 	err := s.ms.Insert(ctx, validatorsCurrentKeyFmt.Encode(), cbor.Marshal(validators))
@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	beaconState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/beacon/state"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	schedulerState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/scheduler/state"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+)
+
+// VerifyElection replays the election of the committee of the given kind
+// and runtime that took place in the block at the given height and compares
+// it to the stored committee.
+//
+// The time is the timestamp of the block at the given height, which is
+// needed to verify the TEE attestations of the nodes.
+func (sf *QueryFactory) VerifyElection(
+	ctx context.Context,
+	height int64,
+	now time.Time,
+	kind scheduler.CommitteeKind,
+	runtimeID common.Namespace,
+) (*scheduler.ElectionVerification, error) {
+	// The state before the block at the first height does not exist, and
+	// the height must be explicit as it would be treated as the latest one
+	// otherwise.
+	if height <= 1 {
+		return nil, fmt.Errorf("tendermint/scheduler: invalid election height: %d", height)
+	}
+
+	epoch, err := sf.state.GetEpoch(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get epoch: %w", err)
+	}
+	beacState, err := beaconState.NewImmutableState(ctx, sf.state, height)
+	if err != nil {
+		return nil, err
+	}
+	beacon, err := beacState.Beacon(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get beacon: %w", err)
+	}
+	state, err := schedulerState.NewImmutableState(ctx, sf.state, height)
+	if err != nil {
+		return nil, err
+	}
+	committee, err := state.Committee(ctx, kind, runtimeID)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get committee: %w", err)
+	}
+
+	// Replay the election against the state as it was before the block
+	// in which the election took place.
+	replayCtx, err := sf.replayContext(ctx, height-1, now)
+	if err != nil {
+		return nil, err
+	}
+	defer replayCtx.Close()
+
+	regState := registryState.NewMutableState(replayCtx.State())
+	rt, err := regState.Runtime(replayCtx, runtimeID)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get runtime: %w", err)
+	}
+	nodes, nodeStatuses, err := schedulableNodes(replayCtx, regState.ImmutableState, epoch)
+	if err != nil {
+		return nil, err
+	}
+	replayState := schedulerState.NewMutableState(replayCtx.State())
+	params, err := replayState.ConsensusParameters(replayCtx)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get consensus parameters: %w", err)
+	}
+	upcoming, err := replayState.UpcomingCommittee(replayCtx, kind, runtimeID)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get upcoming committee: %w", err)
+	}
+
+	var stakeAcc *stakingState.StakeAccumulatorCache
+	if !params.DebugBypassStake {
+		stakeAcc, err = stakingState.NewStakeAccumulatorCache(replayCtx)
+		if err != nil {
+			return nil, fmt.Errorf("tendermint/scheduler: failed to create stake accumulator cache: %w", err)
+		}
+		defer stakeAcc.Discard()
+	}
+
+	members, reason, err := electCommitteeMembers(replayCtx, epoch, beacon, stakeAcc, nil, rt, nodes, nodeStatuses, kind, params)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &scheduler.ElectionVerification{
+		Height:        height,
+		Epoch:         epoch,
+		Committee:     committee,
+		Expected:      members,
+		FailureReason: reason,
+	}
+	if upcoming != nil && committee != nil && upcoming.ValidFor == epoch && committee.ValidFor == epoch {
+		// The upcoming committee elected ahead of the epoch was promoted.
+		upcomingHash, committeeHash := upcoming.EncodedMembersHash(), committee.EncodedMembersHash()
+		v.Promoted = upcomingHash.Equal(&committeeHash)
+	}
+	compareElection(v, params)
+	return v, nil
+}
+
+// replayContext returns a read-only context over the state at the given
+// height for replaying elections.
+func (sf *QueryFactory) replayContext(ctx context.Context, height int64, now time.Time) (*abciAPI.Context, error) {
+	is, err := abciAPI.NewImmutableState(ctx, sf.state, height)
+	if err != nil {
+		return nil, err
+	}
+	tree, ok := is.ImmutableKeyValueTree.(mkvs.Tree)
+	if !ok {
+		is.Close()
+		return nil, fmt.Errorf("tendermint/scheduler: state at height %d is not replayable", height)
+	}
+
+	// Use the simulation mode, so that the tree is released once the
+	// context is closed.
+	return abciAPI.NewContext(
+		ctx,
+		abciAPI.ContextSimulateTx,
+		now,
+		abciAPI.NewNopGasAccountant(),
+		nil,
+		tree,
+		height,
+		nil,
+	), nil
+}
+
+// compareElection compares the members elected by a replayed election to
+// the stored committee and records the differences.
+func compareElection(v *scheduler.ElectionVerification, params *scheduler.ConsensusParameters) {
+	if v.Committee != nil && v.Committee.ValidFor != v.Epoch {
+		v.StaleCommittee = true
+	}
+
+	var actual []*scheduler.CommitteeNode
+	if v.Committee != nil {
+		actual = v.Committee.Members
+	}
+	if v.Promoted {
+		// The members were elected ahead of the epoch using a different
+		// beacon, they cannot be compared to the replayed election.
+		return
+	}
+	if v.FailureReason != scheduler.ElectionFailureInvalid && v.Committee != nil && params.KeepCommitteeOnElectionFailure {
+		// The previous committee was kept in place of the one that failed
+		// to be elected, its members cannot be replayed.
+		v.KeptPrevious = true
+		return
+	}
+
+	n := len(v.Expected)
+	if len(actual) > n {
+		n = len(actual)
+	}
+	for i := 0; i < n; i++ {
+		var expected, member *scheduler.CommitteeNode
+		if i < len(v.Expected) {
+			expected = v.Expected[i]
+		}
+		if i < len(actual) {
+			member = actual[i]
+		}
+		if expected != nil && member != nil && expected.Role == member.Role && expected.PublicKey.Equal(member.PublicKey) {
+			continue
+		}
+		v.Mismatches = append(v.Mismatches, &scheduler.ElectionMismatch{
+			Index:    i,
+			Expected: expected,
+			Actual:   member,
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
+)
+
+func TestCompareElection(t *testing.T) {
+	require := require.New(t)
+
+	var members []*scheduler.CommitteeNode
+	for i, role := range []scheduler.Role{scheduler.Worker, scheduler.Worker, scheduler.BackupWorker} {
+		members = append(members, &scheduler.CommitteeNode{
+			Role:      role,
+			PublicKey: memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/scheduler: member %d", i)).Public(),
+		})
+	}
+	committee := func(members ...*scheduler.CommitteeNode) *scheduler.Committee {
+		return &scheduler.Committee{
+			Kind:     scheduler.KindComputeExecutor,
+			Members:  members,
+			ValidFor: 2,
+		}
+	}
+	params := &scheduler.ConsensusParameters{}
+
+	// Identical committees.
+	v := &scheduler.ElectionVerification{
+		Epoch:     2,
+		Committee: committee(members...),
+		Expected:  members,
+	}
+	compareElection(v, params)
+	require.True(v.IsValid(), "identical committees should be valid")
+
+	// Members in a different order and a missing member.
+	v = &scheduler.ElectionVerification{
+		Epoch:     2,
+		Committee: committee(members[1], members[0]),
+		Expected:  members,
+	}
+	compareElection(v, params)
+	require.False(v.IsValid(), "different committees should not be valid")
+	require.Equal([]*scheduler.ElectionMismatch{
+		{Index: 0, Expected: members[0], Actual: members[1]},
+		{Index: 1, Expected: members[1], Actual: members[0]},
+		{Index: 2, Expected: members[2]},
+	}, v.Mismatches)
+
+	// Committee for a different epoch.
+	v = &scheduler.ElectionVerification{
+		Epoch:     3,
+		Committee: committee(members...),
+		Expected:  members,
+	}
+	compareElection(v, params)
+	require.True(v.StaleCommittee, "committee for a different epoch should be stale")
+	require.False(v.IsValid(), "stale committee should not be valid")
+
+	// Failed election with a stored committee.
+	v = &scheduler.ElectionVerification{
+		Epoch:         2,
+		Committee:     committee(members...),
+		FailureReason: scheduler.ElectionFailureInsufficientNodes,
+	}
+	compareElection(v, params)
+	require.False(v.KeptPrevious, "previous committee should not be kept")
+	require.Len(v.Mismatches, len(members), "all stored members should be reported")
+	for _, m := range v.Mismatches {
+		require.Nil(m.Expected, "no members should be expected")
+	}
+
+	params.KeepCommitteeOnElectionFailure = true
+	v = &scheduler.ElectionVerification{
+		Epoch:         2,
+		Committee:     committee(members...),
+		FailureReason: scheduler.ElectionFailureInsufficientNodes,
+	}
+	compareElection(v, params)
+	require.True(v.KeptPrevious, "previous committee should be kept")
+	require.True(v.IsValid(), "kept committee should be valid")
+
+	// Failed election without a stored committee.
+	v = &scheduler.ElectionVerification{
+		Epoch:         2,
+		FailureReason: scheduler.ElectionFailureInsufficientNodes,
+	}
+	compareElection(v, params)
+	require.False(v.KeptPrevious, "no committee should be kept")
+	require.True(v.IsValid(), "missing committee should be valid")
+
+	// Promoted upcoming committee elected using a different beacon.
+	v = &scheduler.ElectionVerification{
+		Epoch:     2,
+		Committee: committee(members[1], members[0]),
+		Expected:  members,
+		Promoted:  true,
+	}
+	compareElection(v, params)
+	require.Empty(v.Mismatches, "promoted committee should not be compared")
+	require.True(v.IsValid(), "promoted committee should be valid")
+}
@@ -37,7 +37,25 @@ var (
 	// an api.BurnEvent).
 	KeyBurn = []byte("burn")
 
+	// KeyMint is an ABCI event attribute key for minting rewards (value is
+	// an api.MintEvent).
+	KeyMint = stakingState.KeyMint
+
 	// KeyAddEscrow is an ABCI event attribute key for AddEscrow calls
 	// (value is an api.AddEscrowEvent).
 	KeyAddEscrow = stakingState.KeyAddEscrow
+
+	// KeyCommissionDestinations is an ABCI event attribute key for
+	// SetCommissionDestinations calls (value is an
+	// api.CommissionDestinationsEvent).
+	KeyCommissionDestinations = []byte("commission_destinations")
+
+	// KeyAllowanceChange is an ABCI event attribute key for allowance changes
+	// caused by Allow and Withdraw calls (value is an api.AllowanceChangeEvent).
+	KeyAllowanceChange = []byte("allowance_change")
+
+	// KeyPendingSlash is an ABCI event attribute key for pending slashes
+	// being scheduled, cancelled or executed (value is an
+	// api.PendingSlashEvent).
+	KeyPendingSlash = stakingState.KeyPendingSlash
 )
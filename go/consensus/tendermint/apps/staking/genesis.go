@@ -151,6 +151,9 @@ func (app *stakingApplication) initTotalSupply(
 	if err := state.SetTotalSupply(ctx, totalSupply); err != nil {
 		return fmt.Errorf("tendermint/staking: failed to set total supply: %w", err)
 	}
+	if err := state.SetTotalMinted(ctx, &st.TotalMinted); err != nil {
+		return fmt.Errorf("tendermint/staking: failed to set total minted: %w", err)
+	}
 
 	return nil
 }
@@ -226,6 +229,19 @@ func (app *stakingApplication) initDebondingDelegations(ctx *abciAPI.Context, st
 	return nil
 }
 
+func (app *stakingApplication) initPendingSlashes(ctx *abciAPI.Context, state *stakingState.MutableState, st *staking.Genesis) error {
+	if err := staking.SanityCheckPendingSlashes(&st.Parameters, st.PendingSlashes); err != nil {
+		return fmt.Errorf("tendermint/staking: %w", err)
+	}
+
+	for _, ps := range st.PendingSlashes {
+		if err := state.SetPendingSlash(ctx, ps); err != nil {
+			return fmt.Errorf("tendermint/staking: failed to set pending slash: %w", err)
+		}
+	}
+	return nil
+}
+
 // InitChain initializes the chain from genesis.
 func (app *stakingApplication) InitChain(ctx *abciAPI.Context, request types.RequestInitChain, doc *genesis.Document) error {
 	st := &doc.Staking
@@ -263,6 +279,10 @@ func (app *stakingApplication) InitChain(ctx *abciAPI.Context, request types.Req
 		return err
 	}
 
+	if err := app.initPendingSlashes(ctx, state, st); err != nil {
+		return err
+	}
+
 	ctx.Logger().Debug("InitChain: allocations complete",
 		"common_pool", st.CommonPool,
 		"total_supply", totalSupply,
@@ -288,6 +308,11 @@ func (sq *stakingQuerier) Genesis(ctx context.Context) (*staking.Genesis, error)
 		return nil, err
 	}
 
+	totalMinted, err := sq.state.TotalMinted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	accounts, err := sq.state.Accounts(ctx)
 	if err != nil {
 		return nil, err
@@ -314,6 +339,11 @@ func (sq *stakingQuerier) Genesis(ctx context.Context) (*staking.Genesis, error)
 		return nil, err
 	}
 
+	pendingSlashes, err := sq.state.PendingSlashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params, err := sq.state.ConsensusParameters(ctx)
 	if err != nil {
 		return nil, err
@@ -324,9 +354,11 @@ func (sq *stakingQuerier) Genesis(ctx context.Context) (*staking.Genesis, error)
 		TotalSupply:          *totalSupply,
 		CommonPool:           *commonPool,
 		LastBlockFees:        *lastBlockFees,
+		TotalMinted:          *totalMinted,
 		Ledger:               ledger,
 		Delegations:          delegations,
 		DebondingDelegations: debondingDelegations,
+		PendingSlashes:       pendingSlashes,
 	}
 	return &gen, nil
 }
@@ -1,7 +1,10 @@
 package staking
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
@@ -22,7 +25,13 @@ type Query interface {
 	AccountInfo(context.Context, signature.PublicKey) (*staking.Account, error)
 	Delegations(context.Context, signature.PublicKey) (map[signature.PublicKey]*staking.Delegation, error)
 	DebondingDelegations(context.Context, signature.PublicKey) (map[signature.PublicKey][]*staking.DebondingDelegation, error)
+	DelegationInfosFor(context.Context, signature.PublicKey) (map[signature.PublicKey]*staking.DelegationInfo, error)
+	EscrowSummary(context.Context, signature.PublicKey) (*staking.EscrowSummary, error)
+	StakeClaims(context.Context, signature.PublicKey) (*staking.StakeClaimSummary, error)
+	PendingSlashes(context.Context) ([]*staking.PendingSlash, error)
+	SimulateEpochRewards(context.Context) (*staking.RewardSimulation, error)
 	Genesis(context.Context) (*staking.Genesis, error)
+	ExportLedger(context.Context, uint32, func(*staking.LedgerChunk) error) error
 	ConsensusParameters(context.Context) (*staking.ConsensusParameters, error)
 }
 
@@ -37,11 +46,13 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	if err != nil {
 		return nil, err
 	}
-	return &stakingQuerier{state}, nil
+	return &stakingQuerier{sf.state, state, height}, nil
 }
 
 type stakingQuerier struct {
-	state *stakingState.ImmutableState
+	queryState abciAPI.ApplicationQueryState
+	state      *stakingState.ImmutableState
+	height     int64
 }
 
 func (sq *stakingQuerier) TotalSupply(ctx context.Context) (*quantity.Quantity, error) {
@@ -112,6 +123,209 @@ func (sq *stakingQuerier) DebondingDelegations(ctx context.Context, id signature
 	return sq.state.DebondingDelegationsFor(ctx, id)
 }
 
+func (sq *stakingQuerier) DelegationInfosFor(ctx context.Context, id signature.PublicKey) (map[signature.PublicKey]*staking.DelegationInfo, error) {
+	delegations, err := sq.state.DelegationsFor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	epoch, err := sq.queryState.GetEpoch(ctx, sq.height)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make(map[signature.PublicKey]*staking.DelegationInfo, len(delegations))
+	for escrowID, delegation := range delegations {
+		var escrow *staking.Account
+		if escrow, err = sq.state.Account(ctx, escrowID); err != nil {
+			return nil, err
+		}
+
+		var amount *quantity.Quantity
+		if amount, err = escrow.Escrow.Active.TokensForShares(&delegation.Shares); err != nil {
+			return nil, err
+		}
+
+		info := &staking.DelegationInfo{
+			Delegation: *delegation,
+			Pool:       escrow.Escrow.Active,
+			Amount:     *amount,
+		}
+		if rate := escrow.Escrow.CommissionSchedule.CurrentRate(epoch); rate != nil {
+			info.CommissionRate = *rate
+		}
+		infos[escrowID] = info
+	}
+	return infos, nil
+}
+
+func (sq *stakingQuerier) EscrowSummary(ctx context.Context, id signature.PublicKey) (*staking.EscrowSummary, error) {
+	acct, err := sq.state.Account(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	epoch, err := sq.queryState.GetEpoch(ctx, sq.height)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &staking.EscrowSummary{
+		Active:    acct.Escrow.Active.Balance,
+		Debonding: acct.Escrow.Debonding.Balance,
+	}
+	if rate := acct.Escrow.CommissionSchedule.CurrentRate(epoch); rate != nil {
+		summary.CommissionRate = *rate
+	}
+
+	// Commission is deposited into the escrow itself if no destinations are set.
+	dsts := acct.Escrow.CommissionDestinations
+	if len(dsts) == 0 {
+		dsts = []staking.CommissionDestination{{Account: id}}
+	}
+	for _, dst := range dsts {
+		var delegation *staking.Delegation
+		if delegation, err = sq.state.Delegation(ctx, dst.Account, id); err != nil {
+			return nil, err
+		}
+
+		var amount *quantity.Quantity
+		if amount, err = acct.Escrow.Active.TokensForShares(&delegation.Shares); err != nil {
+			return nil, err
+		}
+		if err = summary.CommissionStake.Add(amount); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+func (sq *stakingQuerier) StakeClaims(ctx context.Context, id signature.PublicKey) (*staking.StakeClaimSummary, error) {
+	acct, err := sq.state.Account(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := sq.state.Thresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return acct.Escrow.StakeClaimSummary(thresholds)
+}
+
+func (sq *stakingQuerier) PendingSlashes(ctx context.Context) ([]*staking.PendingSlash, error) {
+	return sq.state.PendingSlashes(ctx)
+}
+
+func (sq *stakingQuerier) SimulateEpochRewards(ctx context.Context) (*staking.RewardSimulation, error) {
+	epoch, err := sq.queryState.GetEpoch(ctx, sq.height)
+	if err != nil {
+		return nil, err
+	}
+	// Rewards are disbursed at the start of the next epoch.
+	epoch++
+
+	// This must match rewardEpochSigning.
+	params, err := sq.state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	epochSigning, err := sq.state.EpochSigning(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if params.SigningRewardThresholdDenominator == 0 || epochSigning.Total == 0 {
+		return &staking.RewardSimulation{Epoch: epoch}, nil
+	}
+
+	eligibleEntities, err := epochSigning.EligibleEntities(params.SigningRewardThresholdNumerator, params.SigningRewardThresholdDenominator)
+	if err != nil {
+		return nil, fmt.Errorf("determining eligibility: %w", err)
+	}
+
+	return sq.state.SimulateRewards(ctx, epoch, &params.RewardFactorEpochSigned, eligibleEntities)
+}
+
+func (sq *stakingQuerier) ExportLedger(ctx context.Context, chunkSize uint32, fn func(*staking.LedgerChunk) error) error {
+	accounts, err := sq.state.Accounts(ctx)
+	if err != nil {
+		return err
+	}
+	delegations, err := sq.state.Delegations(ctx)
+	if err != nil {
+		return err
+	}
+	debondingDelegations, err := sq.state.DebondingDelegations(ctx)
+	if err != nil {
+		return err
+	}
+
+	total := uint64(len(accounts))
+	for _, dels := range delegations {
+		total += uint64(len(dels))
+	}
+	for _, debs := range debondingDelegations {
+		total += uint64(len(debs))
+	}
+	exporter := staking.NewLedgerExporter(sq.height, total, chunkSize, fn)
+
+	// Accounts are already ordered by the state iterator, so they can be
+	// fetched as they are exported.
+	for _, id := range accounts {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		var acct *staking.Account
+		if acct, err = sq.state.Account(ctx, id); err != nil {
+			return fmt.Errorf("tendermint/staking: failed to fetch account: %w", err)
+		}
+		if err = exporter.AddAccount(id, acct); err != nil {
+			return err
+		}
+	}
+	escrowIDs := make([]signature.PublicKey, 0, len(delegations))
+	for escrowID := range delegations {
+		escrowIDs = append(escrowIDs, escrowID)
+	}
+	for _, escrowID := range sortIDs(escrowIDs) {
+		dels := delegations[escrowID]
+		delegatorIDs := make([]signature.PublicKey, 0, len(dels))
+		for delegatorID := range dels {
+			delegatorIDs = append(delegatorIDs, delegatorID)
+		}
+		for _, delegatorID := range sortIDs(delegatorIDs) {
+			if err = exporter.AddDelegation(escrowID, delegatorID, dels[delegatorID]); err != nil {
+				return err
+			}
+		}
+	}
+
+	escrowIDs = make([]signature.PublicKey, 0, len(debondingDelegations))
+	for escrowID := range debondingDelegations {
+		escrowIDs = append(escrowIDs, escrowID)
+	}
+	for _, escrowID := range sortIDs(escrowIDs) {
+		debs := debondingDelegations[escrowID]
+		delegatorIDs := make([]signature.PublicKey, 0, len(debs))
+		for delegatorID := range debs {
+			delegatorIDs = append(delegatorIDs, delegatorID)
+		}
+		for _, delegatorID := range sortIDs(delegatorIDs) {
+			if err = exporter.AddDebondingDelegations(escrowID, delegatorID, debs[delegatorID]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return exporter.Finish()
+}
+
+// sortIDs sorts the given account IDs in ascending order.
+func sortIDs(ids []signature.PublicKey) []signature.PublicKey {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+	return ids
+}
+
 func (sq *stakingQuerier) ConsensusParameters(ctx context.Context) (*staking.ConsensusParameters, error) {
 	return sq.state.ConsensusParameters(ctx)
 }
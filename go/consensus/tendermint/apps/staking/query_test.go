@@ -0,0 +1,103 @@
+package staking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+func TestDelegationInfosAndEscrowSummary(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 10,
+	})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := stakingState.NewMutableState(ctx.State())
+
+	escrowID := signature.PublicKey{1}
+	otherEscrowID := signature.PublicKey{2}
+	delegatorID := signature.PublicKey{3}
+	commissionID := signature.PublicKey{4}
+
+	// Escrow with a share price of 3 tokens per share and a commission
+	// schedule where only the first step has started.
+	escrow := &staking.Account{
+		Escrow: staking.EscrowAccount{
+			Active: staking.SharePool{
+				Balance:     mustQuantity(t, 300),
+				TotalShares: mustQuantity(t, 100),
+			},
+			Debonding: staking.SharePool{
+				Balance:     mustQuantity(t, 50),
+				TotalShares: mustQuantity(t, 50),
+			},
+			CommissionSchedule: staking.CommissionSchedule{
+				Rates: []staking.CommissionRateStep{
+					{Start: 0, Rate: mustQuantity(t, 20_000)},
+					{Start: 20, Rate: mustQuantity(t, 40_000)},
+				},
+			},
+			CommissionDestinations: []staking.CommissionDestination{
+				{Account: escrowID, Share: mustQuantity(t, 50_000)},
+				{Account: commissionID, Share: mustQuantity(t, 50_000)},
+			},
+		},
+	}
+	require.NoError(s.SetAccount(ctx, escrowID, escrow), "SetAccount")
+	require.NoError(s.SetDelegation(ctx, delegatorID, escrowID, &staking.Delegation{Shares: mustQuantity(t, 50)}), "SetDelegation")
+	require.NoError(s.SetDelegation(ctx, escrowID, escrowID, &staking.Delegation{Shares: mustQuantity(t, 30)}), "SetDelegation")
+	require.NoError(s.SetDelegation(ctx, commissionID, escrowID, &staking.Delegation{Shares: mustQuantity(t, 20)}), "SetDelegation")
+
+	// Escrow without a commission schedule.
+	otherEscrow := &staking.Account{
+		Escrow: staking.EscrowAccount{
+			Active: staking.SharePool{
+				Balance:     mustQuantity(t, 10),
+				TotalShares: mustQuantity(t, 20),
+			},
+		},
+	}
+	require.NoError(s.SetAccount(ctx, otherEscrowID, otherEscrow), "SetAccount")
+	require.NoError(s.SetDelegation(ctx, delegatorID, otherEscrowID, &staking.Delegation{Shares: mustQuantity(t, 20)}), "SetDelegation")
+
+	q, err := NewQueryFactory(appState).QueryAt(ctx, 1)
+	require.NoError(err, "QueryAt")
+
+	infos, err := q.DelegationInfosFor(ctx, delegatorID)
+	require.NoError(err, "DelegationInfosFor")
+	require.Len(infos, 2, "delegator should have two delegations")
+
+	info := infos[escrowID]
+	require.NotNil(info, "delegation info for escrow")
+	require.Equal(mustQuantity(t, 50), info.Shares, "shares")
+	require.Equal(escrow.Escrow.Active, info.Pool, "pool")
+	require.Equal(mustQuantity(t, 150), info.Amount, "amount should be shares times share price")
+	require.Equal(mustQuantity(t, 20_000), info.CommissionRate, "commission rate should be the current rate")
+
+	info = infos[otherEscrowID]
+	require.NotNil(info, "delegation info for other escrow")
+	require.Equal(mustQuantity(t, 10), info.Amount, "amount should be shares times share price")
+	require.True(info.CommissionRate.IsZero(), "commission rate should be zero without a schedule")
+
+	summary, err := q.EscrowSummary(ctx, escrowID)
+	require.NoError(err, "EscrowSummary")
+	require.Equal(mustQuantity(t, 300), summary.Active, "active")
+	require.Equal(mustQuantity(t, 50), summary.Debonding, "debonding")
+	require.Equal(mustQuantity(t, 20_000), summary.CommissionRate, "commission rate")
+	require.Equal(mustQuantity(t, 150), summary.CommissionStake, "commission stake should cover all destinations")
+
+	summary, err = q.EscrowSummary(ctx, otherEscrowID)
+	require.NoError(err, "EscrowSummary")
+	require.Equal(mustQuantity(t, 10), summary.Active, "active")
+	require.True(summary.CommissionStake.IsZero(), "commission stake should be zero without a self-delegation")
+}
@@ -0,0 +1,91 @@
+package staking
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+// sweepRewardSources sweeps the scheduled amounts from the reward source
+// accounts into the common pool.
+//
+// In case of errors the state may be inconsistent.
+func (app *stakingApplication) sweepRewardSources(ctx *abciAPI.Context, epoch epochtime.EpochTime) error {
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	params, err := stakeState.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("loading consensus parameters: %w", err)
+	}
+	if len(params.RewardSources) == 0 {
+		return nil
+	}
+
+	// Sweep in a deterministic order, so that events are always emitted in
+	// the same order.
+	ids := make([]signature.PublicKey, 0, len(params.RewardSources))
+	for id := range params.RewardSources {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+
+	commonPool, err := stakeState.CommonPool(ctx)
+	if err != nil {
+		return fmt.Errorf("loading common pool: %w", err)
+	}
+
+	for _, id := range ids {
+		src := params.RewardSources[id]
+		step := src.CurrentStep(epoch)
+		if step == nil {
+			continue
+		}
+
+		var acct *staking.Account
+		if acct, err = stakeState.Account(ctx, id); err != nil {
+			return fmt.Errorf("failed to fetch reward source account %s: %w", id, err)
+		}
+		var amount *quantity.Quantity
+		if amount, err = step.SweepAmount(&acct.General.Balance); err != nil {
+			return fmt.Errorf("computing sweep amount: %w", err)
+		}
+		if amount.IsZero() {
+			continue
+		}
+
+		if err = quantity.Move(commonPool, &acct.General.Balance, amount); err != nil {
+			return fmt.Errorf("sweeping reward source %s: %w", id, err)
+		}
+		if err = stakeState.SetAccount(ctx, id, acct); err != nil {
+			return fmt.Errorf("failed to set reward source account %s: %w", id, err)
+		}
+
+		ctx.Logger().Debug("swept reward source into common pool",
+			"reward_source", id,
+			"amount", amount,
+		)
+
+		evt := &staking.TransferEvent{
+			From:   id,
+			To:     staking.CommonPoolAccountID,
+			Tokens: *amount,
+		}
+		ctx.EmitEvent(abciAPI.NewEventBuilder(app.Name()).Attribute(KeyTransfer, cbor.Marshal(evt)))
+	}
+
+	if err = stakeState.SetCommonPool(ctx, commonPool); err != nil {
+		return fmt.Errorf("failed to set common pool: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package staking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+func TestSweepRewardSources(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock, now)
+	defer ctx.Close()
+
+	app := &stakingApplication{}
+	state := stakingState.NewMutableState(ctx.State())
+
+	amountID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: reward source amount").Public()
+	rateID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: reward source rate").Public()
+
+	for _, id := range []signature.PublicKey{amountID, rateID} {
+		var acct staking.Account
+		acct.General.Balance = mustQuantity(t, 1000)
+		err := state.SetAccount(ctx, id, &acct)
+		require.NoError(err, "SetAccount")
+	}
+	commonPool := mustQuantity(t, 100)
+	err := state.SetCommonPool(ctx, &commonPool)
+	require.NoError(err, "SetCommonPool")
+
+	// 25% rate.
+	rate := mustQuantity(t, 25_000)
+	params := &staking.ConsensusParameters{
+		RewardSources: map[signature.PublicKey]staking.RewardSource{
+			amountID: {
+				Schedule: []staking.RewardSourceStep{
+					{Until: 2, Amount: mustQuantity(t, 600)},
+				},
+			},
+			rateID: {
+				Schedule: []staking.RewardSourceStep{
+					{Until: 1, Amount: mustQuantity(t, 10)},
+					{Until: 3, Rate: rate},
+				},
+			},
+		},
+	}
+	for _, src := range params.RewardSources {
+		require.NoError(src.SanityCheck(), "reward source should pass sanity check")
+	}
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	checkBalances := func(amountBalance, rateBalance, commonPoolBalance uint64) {
+		acct, err := state.Account(ctx, amountID)
+		require.NoError(err, "Account")
+		require.Equal(mustQuantity(t, amountBalance), acct.General.Balance, "amount reward source balance")
+		acct, err = state.Account(ctx, rateID)
+		require.NoError(err, "Account")
+		require.Equal(mustQuantity(t, rateBalance), acct.General.Balance, "rate reward source balance")
+		cp, err := state.CommonPool(ctx)
+		require.NoError(err, "CommonPool")
+		require.Equal(mustQuantity(t, commonPoolBalance), *cp, "common pool balance")
+	}
+
+	err = app.sweepRewardSources(ctx, 1)
+	require.NoError(err, "sweepRewardSources")
+	checkBalances(400, 750, 950)
+	require.Len(ctx.GetEvents(), 2, "a transfer event should be emitted for each reward source")
+
+	// The fixed amount is capped at the remaining balance.
+	err = app.sweepRewardSources(ctx, 1)
+	require.NoError(err, "sweepRewardSources")
+	checkBalances(0, 563, 1537)
+
+	// Past the end of the schedules, nothing is swept.
+	err = app.sweepRewardSources(ctx, 3)
+	require.NoError(err, "sweepRewardSources")
+	checkBalances(0, 563, 1537)
+
+	// Invalid schedules should fail the sanity check.
+	invalid := staking.RewardSource{
+		Schedule: []staking.RewardSourceStep{
+			{Until: 1, Amount: mustQuantity(t, 10), Rate: rate},
+		},
+	}
+	require.Error(invalid.SanityCheck(), "step with both amount and rate should be invalid")
+	invalid.Schedule = []staking.RewardSourceStep{{Until: 1}}
+	require.Error(invalid.SanityCheck(), "step with neither amount nor rate should be invalid")
+	invalid.Schedule = []staking.RewardSourceStep{{Until: 1, Rate: mustQuantity(t, 200_000)}}
+	require.Error(invalid.SanityCheck(), "step with rate over denominator should be invalid")
+}
@@ -123,3 +123,155 @@ func TestOnEvidenceDoubleSign(t *testing.T) {
 	require.True(status.IsFrozen(), "node should be frozen after slashing")
 	require.EqualValues(registry.FreezeForever, status.FreezeEndTime, "node should be frozen forever")
 }
+
+func TestPendingSlash(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 42,
+	})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := &stakingApplication{}
+	regState := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	// A non-consensus fault for which slashing can be delayed.
+	reason := staking.SlashRuntimeEquivocation
+
+	authorities := []signature.PublicKey{
+		memorySigner.NewTestSigner("consensus/tendermint/apps/staking: slashing authority 1").Public(),
+		memorySigner.NewTestSigner("consensus/tendermint/apps/staking: slashing authority 2").Public(),
+		memorySigner.NewTestSigner("consensus/tendermint/apps/staking: slashing authority 3").Public(),
+	}
+	err := stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		Slashing: map[staking.SlashReason]staking.Slash{
+			reason: staking.Slash{
+				Amount:         mustQuantity(t, 100),
+				FreezeInterval: 1,
+				Delay:          2,
+			},
+		},
+		SlashingAuthorities: map[signature.PublicKey]bool{
+			authorities[0]: true,
+			authorities[1]: true,
+			authorities[2]: true,
+		},
+		SlashCancelThreshold: 2,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	// Add a staked entity with a node.
+	ent, _, _ := entity.TestEntity()
+	err = stakeState.SetAccount(ctx, ent.ID, &staking.Account{
+		Escrow: staking.EscrowAccount{
+			Active: staking.SharePool{
+				Balance:     mustQuantity(t, 1000),
+				TotalShares: mustQuantity(t, 1000),
+			},
+		},
+	})
+	require.NoError(err, "SetAccount")
+	nod := &node.Node{
+		DescriptorVersion: node.LatestNodeDescriptorVersion,
+		ID:                memorySigner.NewTestSigner("consensus/tendermint/apps/staking: pending slash node").Public(),
+		EntityID:          ent.ID,
+	}
+	err = regState.SetNodeStatus(ctx, nod.ID, &registry.NodeStatus{})
+	require.NoError(err, "SetNodeStatus")
+
+	// Slashing should only schedule a pending slash.
+	err = stakingState.SlashNode(ctx, nod, reason)
+	require.NoError(err, "SlashNode")
+	pending, err := stakeState.PendingSlashes(ctx)
+	require.NoError(err, "PendingSlashes")
+	require.Len(pending, 1, "there should be a pending slash")
+	require.EqualValues(0, pending[0].ID)
+	require.EqualValues(42, pending[0].DetectedAt)
+	require.EqualValues(44, pending[0].ExecuteAt)
+	require.Equal(mustQuantity(t, 100), pending[0].Amount)
+	acct, err := stakeState.Account(ctx, ent.ID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 1000), acct.Escrow.Active.Balance, "stake should not be slashed yet")
+
+	// Repeated faults should not accumulate pending slashes.
+	err = stakingState.SlashNode(ctx, nod, reason)
+	require.NoError(err, "SlashNode")
+	pending, err = stakeState.PendingSlashes(ctx)
+	require.NoError(err, "PendingSlashes")
+	require.Len(pending, 1, "there should still be a single pending slash")
+
+	// Only slashing authorities can vote to cancel.
+	ctx.SetTxSigner(ent.ID)
+	err = app.cancelSlash(ctx, stakeState, &staking.CancelSlash{ID: 0})
+	require.Equal(staking.ErrForbidden, err, "cancel by non-authority should fail")
+
+	ctx.SetTxSigner(authorities[0])
+	err = app.cancelSlash(ctx, stakeState, &staking.CancelSlash{ID: 1})
+	require.Equal(staking.ErrNoSuchPendingSlash, err, "cancelling a non-existent pending slash should fail")
+	err = app.cancelSlash(ctx, stakeState, &staking.CancelSlash{ID: 0})
+	require.NoError(err, "cancelSlash")
+	err = app.cancelSlash(ctx, stakeState, &staking.CancelSlash{ID: 0})
+	require.Equal(staking.ErrInvalidArgument, err, "voting twice should fail")
+	ps, err := stakeState.PendingSlash(ctx, 0)
+	require.NoError(err, "PendingSlash")
+	require.Equal([]signature.PublicKey{authorities[0]}, ps.CancelVotes, "vote should be recorded")
+
+	// Reaching the threshold cancels the slash.
+	ctx.SetTxSigner(authorities[1])
+	err = app.cancelSlash(ctx, stakeState, &staking.CancelSlash{ID: 0})
+	require.NoError(err, "cancelSlash")
+	pending, err = stakeState.PendingSlashes(ctx)
+	require.NoError(err, "PendingSlashes")
+	require.Empty(pending, "pending slash should be cancelled")
+	err = stakingState.ExecutePendingSlashes(ctx, 44)
+	require.NoError(err, "executePendingSlashes")
+	acct, err = stakeState.Account(ctx, ent.ID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 1000), acct.Escrow.Active.Balance, "cancelled slash should not be executed")
+
+	// Pending slashes that are not cancelled get executed once the delay elapses.
+	err = stakingState.SlashNode(ctx, nod, reason)
+	require.NoError(err, "SlashNode")
+	err = stakingState.ExecutePendingSlashes(ctx, 43)
+	require.NoError(err, "executePendingSlashes")
+	pending, err = stakeState.PendingSlashes(ctx)
+	require.NoError(err, "PendingSlashes")
+	require.Len(pending, 1, "pending slash should not be executed before the delay elapses")
+	require.EqualValues(1, pending[0].ID, "pending slash identifiers should not be reused")
+
+	err = stakingState.ExecutePendingSlashes(ctx, 44)
+	require.NoError(err, "executePendingSlashes")
+	pending, err = stakeState.PendingSlashes(ctx)
+	require.NoError(err, "PendingSlashes")
+	require.Empty(pending, "pending slash should be executed")
+	acct, err = stakeState.Account(ctx, ent.ID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 900), acct.Escrow.Active.Balance, "stake should be slashed")
+	status, err := regState.NodeStatus(ctx, nod.ID)
+	require.NoError(err, "NodeStatus")
+	require.EqualValues(43, status.FreezeEndTime, "node should be frozen")
+
+	// Debonding after the fault is detected should not escape the slash.
+	err = regState.SetNodeStatus(ctx, nod.ID, &registry.NodeStatus{})
+	require.NoError(err, "SetNodeStatus")
+	err = stakingState.SlashNode(ctx, nod, reason)
+	require.NoError(err, "SlashNode")
+	acct, err = stakeState.Account(ctx, ent.ID)
+	require.NoError(err, "Account")
+	acct.Escrow.Debonding = acct.Escrow.Active
+	acct.Escrow.Active = staking.SharePool{}
+	err = stakeState.SetAccount(ctx, ent.ID, acct)
+	require.NoError(err, "SetAccount")
+
+	err = stakingState.ExecutePendingSlashes(ctx, 44)
+	require.NoError(err, "executePendingSlashes")
+	pending, err = stakeState.PendingSlashes(ctx)
+	require.NoError(err, "PendingSlashes")
+	require.Empty(pending, "pending slash should be executed")
+	acct, err = stakeState.Account(ctx, ent.ID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 800), acct.Escrow.Debonding.Balance, "debonding stake should be slashed")
+}
@@ -111,6 +111,13 @@ func (app *stakingApplication) ExecuteTx(ctx *api.Context, tx *transaction.Trans
 		}
 
 		return app.transfer(ctx, state, &xfer)
+	case staking.MethodTransferBatch:
+		var batch staking.TransferBatch
+		if err := cbor.Unmarshal(tx.Body, &batch); err != nil {
+			return err
+		}
+
+		return app.transferBatch(ctx, state, &batch)
 	case staking.MethodBurn:
 		var burn staking.Burn
 		if err := cbor.Unmarshal(tx.Body, &burn); err != nil {
@@ -139,6 +146,34 @@ func (app *stakingApplication) ExecuteTx(ctx *api.Context, tx *transaction.Trans
 		}
 
 		return app.amendCommissionSchedule(ctx, state, &amend)
+	case staking.MethodSetCommissionDestinations:
+		var set staking.SetCommissionDestinations
+		if err := cbor.Unmarshal(tx.Body, &set); err != nil {
+			return err
+		}
+
+		return app.setCommissionDestinations(ctx, state, &set)
+	case staking.MethodAllow:
+		var allow staking.Allow
+		if err := cbor.Unmarshal(tx.Body, &allow); err != nil {
+			return err
+		}
+
+		return app.allow(ctx, state, &allow)
+	case staking.MethodWithdraw:
+		var withdraw staking.Withdraw
+		if err := cbor.Unmarshal(tx.Body, &withdraw); err != nil {
+			return err
+		}
+
+		return app.withdraw(ctx, state, &withdraw)
+	case staking.MethodCancelSlash:
+		var cancel staking.CancelSlash
+		if err := cbor.Unmarshal(tx.Body, &cancel); err != nil {
+			return err
+		}
+
+		return app.cancelSlash(ctx, state, &cancel)
 	default:
 		return staking.ErrInvalidArgument
 	}
@@ -239,6 +274,16 @@ func (app *stakingApplication) onEpochChange(ctx *api.Context, epoch epochtime.E
 		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyReclaimEscrow, cbor.Marshal(evt)))
 	}
 
+	// Execute pending slashes that have not been cancelled.
+	if err := stakingState.ExecutePendingSlashes(ctx, epoch); err != nil {
+		return fmt.Errorf("staking/tendermint: failed to execute pending slashes: %w", err)
+	}
+
+	// Top up the common pool from reward sources before paying rewards.
+	if err := app.sweepRewardSources(ctx, epoch); err != nil {
+		return fmt.Errorf("staking/tendermint: failed to sweep reward sources: %w", err)
+	}
+
 	// Add signing rewards.
 	if err := app.rewardEpochSigning(ctx, epoch); err != nil {
 		ctx.Logger().Error("failed to add signing rewards",
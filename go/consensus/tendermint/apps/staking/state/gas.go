@@ -115,6 +115,39 @@ func AuthenticateAndPayFees(
 	return nil
 }
 
+// PayFees transfers the given fee amount from the general balance of the
+// given account to the per-block fee accumulator.
+//
+// Unlike AuthenticateAndPayFees, this does not authenticate the account or
+// update its nonce, so it can be used to charge accounts for operations that
+// are not performed by transactions they signed.
+func PayFees(ctx *abciAPI.Context, id signature.PublicKey, amount *quantity.Quantity) error {
+	state := NewMutableState(ctx.State())
+
+	account, err := state.Account(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account state: %w", err)
+	}
+
+	feeAcc := ctx.BlockContext().Get(feeAccumulatorKey{}).(*feeAccumulator)
+	if err = quantity.Move(&feeAcc.balance, &account.General.Balance, amount); err != nil {
+		return fmt.Errorf("staking: failed to pay fees: %w", err)
+	}
+
+	if err = state.SetAccount(ctx, id, account); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+
+	ev := cbor.Marshal(&staking.TransferEvent{
+		From:   id,
+		To:     staking.FeeAccumulatorAccountID,
+		Tokens: *amount,
+	})
+	ctx.EmitEvent(abciAPI.NewEventBuilder(AppName).Attribute(KeyTransfer, ev))
+
+	return nil
+}
+
 // BlockFees returns the accumulated fee balance for the current block.
 func BlockFees(ctx *abciAPI.Context) quantity.Quantity {
 	// Fetch accumulated fees in the current block.
@@ -0,0 +1,384 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+// PendingSlash returns the pending slash with the given identifier.
+func (s *ImmutableState) PendingSlash(ctx context.Context, id uint64) (*staking.PendingSlash, error) {
+	value, err := s.is.Get(ctx, pendingSlashKeyFmt.Encode(id))
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if value == nil {
+		return nil, staking.ErrNoSuchPendingSlash
+	}
+
+	var ps staking.PendingSlash
+	if err = cbor.Unmarshal(value, &ps); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &ps, nil
+}
+
+// PendingSlashes returns all pending slashes, ordered by identifier.
+func (s *ImmutableState) PendingSlashes(ctx context.Context) ([]*staking.PendingSlash, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var slashes []*staking.PendingSlash
+	for it.Seek(pendingSlashKeyFmt.Encode()); it.Valid(); it.Next() {
+		if !pendingSlashKeyFmt.Decode(it.Key()) {
+			break
+		}
+
+		var ps staking.PendingSlash
+		if err := cbor.Unmarshal(it.Value(), &ps); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		slashes = append(slashes, &ps)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return slashes, nil
+}
+
+// ExpiredPendingSlashes returns the pending slashes that should be executed
+// at or before the given epoch.
+func (s *ImmutableState) ExpiredPendingSlashes(ctx context.Context, epoch epochtime.EpochTime) ([]*staking.PendingSlash, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var slashes []*staking.PendingSlash
+	for it.Seek(pendingSlashQueueKeyFmt.Encode()); it.Valid(); it.Next() {
+		var decEpoch, id uint64
+		if !pendingSlashQueueKeyFmt.Decode(it.Key(), &decEpoch, &id) || decEpoch > uint64(epoch) {
+			break
+		}
+
+		ps, err := s.PendingSlash(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		slashes = append(slashes, ps)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return slashes, nil
+}
+
+func (s *ImmutableState) pendingSlashSequence(ctx context.Context) (uint64, error) {
+	value, err := s.is.Get(ctx, pendingSlashSeqKeyFmt.Encode())
+	if err != nil {
+		return 0, abciAPI.UnavailableStateError(err)
+	}
+	if value == nil {
+		return 0, nil
+	}
+
+	var seq uint64
+	if err = cbor.Unmarshal(value, &seq); err != nil {
+		return 0, abciAPI.UnavailableStateError(err)
+	}
+	return seq, nil
+}
+
+// SetPendingSlash inserts or updates a pending slash and makes sure that it
+// is queued for execution at its execution epoch.
+func (s *MutableState) SetPendingSlash(ctx context.Context, ps *staking.PendingSlash) error {
+	seq, err := s.pendingSlashSequence(ctx)
+	if err != nil {
+		return err
+	}
+	if ps.ID >= seq {
+		if err = s.ms.Insert(ctx, pendingSlashSeqKeyFmt.Encode(), cbor.Marshal(ps.ID+1)); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+	}
+
+	if err = s.ms.Insert(ctx, pendingSlashQueueKeyFmt.Encode(uint64(ps.ExecuteAt), ps.ID), []byte{}); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	if err = s.ms.Insert(ctx, pendingSlashKeyFmt.Encode(ps.ID), cbor.Marshal(ps)); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	return nil
+}
+
+// AddPendingSlash assigns a new identifier to the given pending slash,
+// inserts it and emits the corresponding event.
+//
+// WARNING: This is an internal routine to be used to implement staking policy,
+// and MUST NOT be exposed outside of backend implementations.
+func (s *MutableState) AddPendingSlash(ctx *abciAPI.Context, ps *staking.PendingSlash) error {
+	seq, err := s.pendingSlashSequence(ctx)
+	if err != nil {
+		return err
+	}
+	ps.ID = seq
+
+	if err = s.SetPendingSlash(ctx, ps); err != nil {
+		return err
+	}
+
+	if !ctx.IsCheckOnly() {
+		ev := cbor.Marshal(&staking.PendingSlashEvent{
+			Scheduled: ps,
+		})
+		ctx.EmitEvent(abciAPI.NewEventBuilder(AppName).Attribute(KeyPendingSlash, ev))
+	}
+	return nil
+}
+
+// RemovePendingSlash removes a pending slash and its execution queue entry.
+func (s *MutableState) RemovePendingSlash(ctx context.Context, ps *staking.PendingSlash) error {
+	if err := s.ms.Remove(ctx, pendingSlashQueueKeyFmt.Encode(uint64(ps.ExecuteAt), ps.ID)); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	if err := s.ms.Remove(ctx, pendingSlashKeyFmt.Encode(ps.ID)); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	return nil
+}
+
+// SlashNode slashes the entity operating the given node for the given reason.
+//
+// WARNING: This is an internal routine to be used to implement staking policy,
+// and MUST NOT be exposed outside of backend implementations.
+//
+// In case the slashing configuration for the reason specifies a delay, the
+// slash is only scheduled for execution and can still be cancelled by the
+// slashing authorities. Consensus faults are always slashed immediately.
+func SlashNode(ctx *abciAPI.Context, node *node.Node, reason staking.SlashReason) error {
+	regState := registryState.NewMutableState(ctx.State())
+	stakeState := NewMutableState(ctx.State())
+
+	nodeStatus, err := regState.NodeStatus(ctx, node.ID)
+	if err != nil {
+		ctx.Logger().Warn("failed to get node status",
+			"err", err,
+			"node_id", node.ID,
+		)
+		return nil
+	}
+
+	// Do not slash a frozen node.
+	if nodeStatus.IsFrozen() {
+		ctx.Logger().Debug("not slashing frozen node",
+			"node_id", node.ID,
+			"entity_id", node.EntityID,
+			"freeze_end_time", nodeStatus.FreezeEndTime,
+			"reason", reason,
+		)
+		return nil
+	}
+
+	// Retrieve the slash procedure.
+	st, err := stakeState.Slashing(ctx)
+	if err != nil {
+		ctx.Logger().Error("failed to get slashing table entry",
+			"err", err,
+			"reason", reason,
+		)
+		return err
+	}
+
+	penalty := st[reason]
+
+	if penalty.Delay > 0 && !reason.IsConsensusFault() {
+		return schedulePendingSlash(ctx, stakeState, node, reason, &penalty)
+	}
+
+	if err = executeSlash(ctx, regState, stakeState, node.ID, node.EntityID, &penalty.Amount, penalty.FreezeInterval); err != nil {
+		return err
+	}
+
+	ctx.Logger().Warn("slashed node",
+		"node_id", node.ID,
+		"entity_id", node.EntityID,
+		"reason", reason,
+	)
+
+	return nil
+}
+
+func schedulePendingSlash(
+	ctx *abciAPI.Context,
+	stakeState *MutableState,
+	node *node.Node,
+	reason staking.SlashReason,
+	penalty *staking.Slash,
+) error {
+	// Only keep a single pending slash per node and reason, so that repeated
+	// faults during the delay do not accumulate.
+	pending, err := stakeState.PendingSlashes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, ps := range pending {
+		if ps.NodeID.Equal(node.ID) && ps.Reason == reason {
+			ctx.Logger().Debug("node already has a pending slash",
+				"node_id", node.ID,
+				"entity_id", node.EntityID,
+				"reason", reason,
+				"pending_slash_id", ps.ID,
+			)
+			return nil
+		}
+	}
+
+	epoch, err := ctx.AppState().GetEpoch(context.Background(), ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
+	executeAt := epoch + penalty.Delay
+	if math.MaxUint64-penalty.Delay < epoch {
+		executeAt = epochtime.EpochInvalid
+	}
+
+	ps := &staking.PendingSlash{
+		Reason:         reason,
+		EntityID:       node.EntityID,
+		NodeID:         node.ID,
+		Amount:         *penalty.Amount.Clone(),
+		FreezeInterval: penalty.FreezeInterval,
+		DetectedAt:     epoch,
+		ExecuteAt:      executeAt,
+	}
+	if err = stakeState.AddPendingSlash(ctx, ps); err != nil {
+		ctx.Logger().Error("failed to schedule pending slash",
+			"err", err,
+			"node_id", node.ID,
+			"entity_id", node.EntityID,
+		)
+		return err
+	}
+
+	ctx.Logger().Warn("scheduled pending slash",
+		"node_id", node.ID,
+		"entity_id", node.EntityID,
+		"reason", reason,
+		"pending_slash_id", ps.ID,
+		"execute_at", ps.ExecuteAt,
+	)
+
+	return nil
+}
+
+// executeSlash slashes the escrow account of the given entity and freezes
+// the given node.
+func executeSlash(
+	ctx *abciAPI.Context,
+	regState *registryState.MutableState,
+	stakeState *MutableState,
+	nodeID signature.PublicKey,
+	entityID signature.PublicKey,
+	amount *quantity.Quantity,
+	freezeInterval epochtime.EpochTime,
+) error {
+	// The node may no longer exist when executing a pending slash, in which
+	// case it cannot be frozen but the entity still gets slashed.
+	nodeStatus, err := regState.NodeStatus(ctx, nodeID)
+	switch err {
+	case nil:
+	case registry.ErrNoSuchNode:
+		nodeStatus = nil
+	default:
+		return err
+	}
+
+	// Freeze node to prevent it being slashed again. This also prevents the
+	// node from being scheduled in the next epoch.
+	if nodeStatus != nil && freezeInterval > 0 {
+		var epoch epochtime.EpochTime
+		epoch, err = ctx.AppState().GetEpoch(context.Background(), ctx.BlockHeight()+1)
+		if err != nil {
+			return err
+		}
+
+		// Check for overflow.
+		freezeEndTime := registry.FreezeForever
+		if math.MaxUint64-freezeInterval >= epoch {
+			freezeEndTime = epoch + freezeInterval
+		}
+		if freezeEndTime > nodeStatus.FreezeEndTime {
+			nodeStatus.FreezeEndTime = freezeEndTime
+		}
+	}
+
+	// Slash entity.
+	_, err = stakeState.SlashEscrow(ctx, entityID, amount)
+	if err != nil {
+		ctx.Logger().Error("failed to slash entity",
+			"err", err,
+			"node_id", nodeID,
+			"entity_id", entityID,
+		)
+		return err
+	}
+
+	if nodeStatus == nil {
+		return nil
+	}
+	if err = regState.SetNodeStatus(ctx, nodeID, nodeStatus); err != nil {
+		ctx.Logger().Error("failed to set node status",
+			"err", err,
+			"node_id", nodeID,
+			"entity_id", entityID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ExecutePendingSlashes executes all pending slashes scheduled for execution
+// at or before the given epoch.
+//
+// WARNING: This is an internal routine to be used to implement staking policy,
+// and MUST NOT be exposed outside of backend implementations.
+func ExecutePendingSlashes(ctx *abciAPI.Context, epoch epochtime.EpochTime) error {
+	regState := registryState.NewMutableState(ctx.State())
+	stakeState := NewMutableState(ctx.State())
+
+	slashes, err := stakeState.ExpiredPendingSlashes(ctx, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to query expired pending slashes: %w", err)
+	}
+	for _, ps := range slashes {
+		if err = executeSlash(ctx, regState, stakeState, ps.NodeID, ps.EntityID, &ps.Amount, ps.FreezeInterval); err != nil {
+			return fmt.Errorf("failed to execute pending slash %d: %w", ps.ID, err)
+		}
+		if err = stakeState.RemovePendingSlash(ctx, ps); err != nil {
+			return fmt.Errorf("failed to remove pending slash %d: %w", ps.ID, err)
+		}
+
+		ctx.Logger().Warn("executed pending slash",
+			"node_id", ps.NodeID,
+			"entity_id", ps.EntityID,
+			"reason", ps.Reason,
+			"pending_slash_id", ps.ID,
+		)
+
+		evt := staking.PendingSlashEvent{
+			Executed: ps,
+		}
+		ctx.EmitEvent(abciAPI.NewEventBuilder(AppName).Attribute(KeyPendingSlash, cbor.Marshal(evt)))
+	}
+
+	return nil
+}
@@ -31,6 +31,13 @@ var (
 	// KeyTransfer is an ABCI event attribute key for Transfers (value is
 	// an app.TransferEvent).
 	KeyTransfer = []byte("transfer")
+	// KeyMint is an ABCI event attribute key for minting rewards (value is
+	// an api.MintEvent).
+	KeyMint = []byte("mint")
+	// KeyPendingSlash is an ABCI event attribute key for pending slashes
+	// being scheduled, cancelled or executed (value is an
+	// api.PendingSlashEvent).
+	KeyPendingSlash = []byte("pending_slash")
 
 	// accountKeyFmt is the key format used for accounts (account id).
 	//
@@ -70,6 +77,26 @@ var (
 	//
 	// Value is CBOR-serialized EpochSigning.
 	epochSigningKeyFmt = keyformat.New(0x58)
+	// totalMintedKeyFmt is the key format used for the total amount of
+	// tokens minted to pay rewards.
+	//
+	// Value is a CBOR-serialized quantity.
+	totalMintedKeyFmt = keyformat.New(0x59)
+	// pendingSlashKeyFmt is the key format used for pending slashes
+	// (pending slash id).
+	//
+	// Value is CBOR-serialized staking.PendingSlash.
+	pendingSlashKeyFmt = keyformat.New(0x5a, uint64(0))
+	// pendingSlashQueueKeyFmt is the pending slash queue key format (epoch,
+	// pending slash id).
+	//
+	// Value is empty.
+	pendingSlashQueueKeyFmt = keyformat.New(0x5b, uint64(0), uint64(0))
+	// pendingSlashSeqKeyFmt is the key format used for the next pending
+	// slash id.
+	//
+	// Value is a CBOR-serialized uint64.
+	pendingSlashSeqKeyFmt = keyformat.New(0x5c)
 
 	logger = logging.GetLogger("tendermint/staking")
 )
@@ -112,6 +139,23 @@ func (s *ImmutableState) CommonPool(ctx context.Context) (*quantity.Quantity, er
 	return &q, nil
 }
 
+// TotalMinted returns the total amount of tokens minted to pay rewards.
+func (s *ImmutableState) TotalMinted(ctx context.Context) (*quantity.Quantity, error) {
+	value, err := s.is.Get(ctx, totalMintedKeyFmt.Encode())
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if value == nil {
+		return &quantity.Quantity{}, nil
+	}
+
+	var q quantity.Quantity
+	if err := cbor.Unmarshal(value, &q); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &q, nil
+}
+
 func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*staking.ConsensusParameters, error) {
 	raw, err := s.is.Get(ctx, parametersKeyFmt.Encode())
 	if err != nil {
@@ -524,6 +568,11 @@ func (s *MutableState) SetCommonPool(ctx context.Context, q *quantity.Quantity)
 	return abciAPI.UnavailableStateError(err)
 }
 
+func (s *MutableState) SetTotalMinted(ctx context.Context, q *quantity.Quantity) error {
+	err := s.ms.Insert(ctx, totalMintedKeyFmt.Encode(), cbor.Marshal(q))
+	return abciAPI.UnavailableStateError(err)
+}
+
 func (s *MutableState) SetConsensusParameters(ctx context.Context, params *staking.ConsensusParameters) error {
 	err := s.ms.Insert(ctx, parametersKeyFmt.Encode(), cbor.Marshal(params))
 	return abciAPI.UnavailableStateError(err)
@@ -710,6 +759,281 @@ func (s *MutableState) TransferFromCommon(ctx *abciAPI.Context, toID signature.P
 	return ret, nil
 }
 
+// depositCommission deposits the commission from the common pool into the
+// active escrow balance of the given account, splitting it among the
+// configured commission destinations (if any).
+func (s *MutableState) depositCommission(
+	ctx *abciAPI.Context,
+	id signature.PublicKey,
+	ent *staking.Account,
+	commonPool *quantity.Quantity,
+	com *quantity.Quantity,
+) error {
+	dsts := ent.Escrow.CommissionDestinations
+	parts := []*quantity.Quantity{com}
+	if len(dsts) == 0 {
+		dsts = []staking.CommissionDestination{{Account: id}}
+	} else {
+		var err error
+		if parts, err = staking.SplitCommission(dsts, com); err != nil {
+			return fmt.Errorf("tendermint/staking: failed to split commission: %w", err)
+		}
+	}
+
+	for i, dst := range dsts {
+		part := parts[i]
+		if part.IsZero() {
+			continue
+		}
+
+		delegation, err := s.Delegation(ctx, dst.Account, id)
+		if err != nil {
+			return fmt.Errorf("tendermint/staking: failed to query delegation: %w", err)
+		}
+
+		if err = ent.Escrow.Active.Deposit(&delegation.Shares, commonPool, part); err != nil {
+			return fmt.Errorf("tendermint/staking: failed depositing commission: %w", err)
+		}
+
+		if err = s.SetDelegation(ctx, dst.Account, id, delegation); err != nil {
+			return fmt.Errorf("tendermint/staking: failed to set delegation: %w", err)
+		}
+
+		ev := cbor.Marshal(&staking.AddEscrowEvent{
+			Owner:  staking.CommonPoolAccountID,
+			Escrow: id,
+			Tokens: *part,
+		})
+		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyAddEscrow, ev))
+	}
+
+	return nil
+}
+
+// rewardMinter mints (a portion of) rewards into the common pool before they
+// are paid out, according to the minting parameters.
+type rewardMinter struct {
+	rate      *quantity.Quantity
+	available *quantity.Quantity
+	minted    quantity.Quantity
+}
+
+// newRewardMinter creates a new reward minter for the given epoch. If minting
+// is not configured or is not active, the returned minter is a no-op.
+func (s *ImmutableState) newRewardMinter(ctx context.Context, time epochtime.EpochTime) (*rewardMinter, error) {
+	var m rewardMinter
+
+	params, err := s.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if params.Minting == nil {
+		return &m, nil
+	}
+	if m.rate = params.Minting.CurrentRate(time); m.rate == nil || m.rate.IsZero() {
+		m.rate = nil
+		return &m, nil
+	}
+
+	totalSupply, err := s.TotalSupply(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/staking: failed to query total supply: %w", err)
+	}
+	m.available = params.Minting.SupplyCap.Clone()
+	if m.available.Cmp(totalSupply) <= 0 {
+		// Supply cap has been reached.
+		m.rate = nil
+		return &m, nil
+	}
+	if err = m.available.Sub(totalSupply); err != nil {
+		return nil, fmt.Errorf("tendermint/staking: failed computing mintable amount: %w", err)
+	}
+
+	return &m, nil
+}
+
+// mint mints the configured portion of the given reward into the common pool,
+// never exceeding the supply cap.
+func (m *rewardMinter) mint(commonPool, reward *quantity.Quantity) error {
+	if m.rate == nil || m.available.IsZero() {
+		return nil
+	}
+
+	amount := reward.Clone()
+	// Multiply first.
+	if err := amount.Mul(m.rate); err != nil {
+		return fmt.Errorf("tendermint/staking: failed multiplying by minting rate: %w", err)
+	}
+	if err := amount.Quo(staking.RewardAmountDenominator); err != nil {
+		return fmt.Errorf("tendermint/staking: failed dividing by reward amount denominator: %w", err)
+	}
+
+	minted, err := quantity.MoveUpTo(commonPool, m.available, amount)
+	if err != nil {
+		return fmt.Errorf("tendermint/staking: failed minting into common pool: %w", err)
+	}
+	if err = m.minted.Add(minted); err != nil {
+		return fmt.Errorf("tendermint/staking: failed accumulating minted amount: %w", err)
+	}
+	return nil
+}
+
+// commit updates the total supply and total minted amounts and emits a mint
+// event if any tokens were minted.
+func (m *rewardMinter) commit(ctx *abciAPI.Context, s *MutableState) error {
+	if m.minted.IsZero() {
+		return nil
+	}
+
+	totalSupply, err := s.TotalSupply(ctx)
+	if err != nil {
+		return fmt.Errorf("tendermint/staking: failed to query total supply: %w", err)
+	}
+	if err = totalSupply.Add(&m.minted); err != nil {
+		return fmt.Errorf("tendermint/staking: failed adding minted amount to total supply: %w", err)
+	}
+	if err = s.SetTotalSupply(ctx, totalSupply); err != nil {
+		return fmt.Errorf("tendermint/staking: failed to set total supply: %w", err)
+	}
+
+	totalMinted, err := s.TotalMinted(ctx)
+	if err != nil {
+		return fmt.Errorf("tendermint/staking: failed to query total minted: %w", err)
+	}
+	if err = totalMinted.Add(&m.minted); err != nil {
+		return fmt.Errorf("tendermint/staking: failed adding minted amount to total minted: %w", err)
+	}
+	if err = s.SetTotalMinted(ctx, totalMinted); err != nil {
+		return fmt.Errorf("tendermint/staking: failed to set total minted: %w", err)
+	}
+
+	ev := cbor.Marshal(&staking.MintEvent{
+		Tokens: m.minted,
+	})
+	ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyMint, ev))
+
+	return nil
+}
+
+// activeRewardStep returns the reward schedule step active at the given epoch
+// or nil if the epoch is past the end of the schedule.
+func (s *ImmutableState) activeRewardStep(ctx context.Context, time epochtime.EpochTime) (*staking.RewardStep, error) {
+	steps, err := s.RewardSchedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range steps {
+		if time < step.Until {
+			return &step, nil
+		}
+	}
+	return nil, nil
+}
+
+// simulateRewardSourceSweeps adds the amounts that would be swept from the
+// reward sources at the given epoch to the given common pool balance.
+func (s *ImmutableState) simulateRewardSourceSweeps(ctx context.Context, time epochtime.EpochTime, commonPool *quantity.Quantity) error {
+	params, err := s.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("tendermint/staking: loading consensus parameters: %w", err)
+	}
+	for id, src := range params.RewardSources {
+		step := src.CurrentStep(time)
+		if step == nil {
+			continue
+		}
+
+		var acct *staking.Account
+		if acct, err = s.Account(ctx, id); err != nil {
+			return fmt.Errorf("tendermint/staking: failed to fetch reward source account %s: %w", id, err)
+		}
+		var amount *quantity.Quantity
+		if amount, err = step.SweepAmount(&acct.General.Balance); err != nil {
+			return fmt.Errorf("tendermint/staking: computing sweep amount: %w", err)
+		}
+		if err = commonPool.Add(amount); err != nil {
+			return fmt.Errorf("tendermint/staking: failed adding sweep amount: %w", err)
+		}
+	}
+	return nil
+}
+
+// SimulateRewards computes the rewards that AddRewards would disburse to the
+// given accounts, without modifying any state.
+func (s *ImmutableState) SimulateRewards(
+	ctx context.Context,
+	time epochtime.EpochTime,
+	factor *quantity.Quantity,
+	accounts []signature.PublicKey,
+) (*staking.RewardSimulation, error) {
+	sim := &staking.RewardSimulation{
+		Epoch:   time,
+		Rewards: make(map[signature.PublicKey]*staking.RewardProjection),
+	}
+
+	activeStep, err := s.activeRewardStep(ctx, time)
+	if err != nil {
+		return nil, err
+	}
+	if activeStep == nil {
+		// We're past the end of the schedule.
+		return sim, nil
+	}
+
+	commonPool, err := s.CommonPool(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/staking: loading common pool: %w", err)
+	}
+	// Reward sources are swept into the common pool before rewards are paid.
+	if err = s.simulateRewardSourceSweeps(ctx, time, commonPool); err != nil {
+		return nil, err
+	}
+
+	minter, err := s.newRewardMinter(ctx, time)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range accounts {
+		var ent *staking.Account
+		ent, err = s.Account(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("tendermint/staking: failed to fetch account %s: %w", id, err)
+		}
+
+		var q, com *quantity.Quantity
+		if q, com, err = staking.ComputeReward(ent, time, factor, &activeStep.Scale); err != nil {
+			return nil, err
+		}
+		if q.IsZero() {
+			continue
+		}
+
+		if err = minter.mint(commonPool, q); err != nil {
+			return nil, err
+		}
+		if err = sim.Total.Add(q); err != nil {
+			return nil, fmt.Errorf("tendermint/staking: failed accumulating total reward: %w", err)
+		}
+
+		var proj staking.RewardProjection
+		if com != nil {
+			if err = q.Sub(com); err != nil {
+				return nil, fmt.Errorf("tendermint/staking: failed subtracting commission: %w", err)
+			}
+			proj.Commission = *com
+			proj.CommissionRate = *ent.Escrow.CommissionSchedule.CurrentRate(time)
+		}
+		proj.Reward = *q
+		sim.Rewards[id] = &proj
+	}
+
+	sim.Minted = minter.minted
+	sim.InsufficientCommonPool = commonPool.Cmp(&sim.Total) < 0
+
+	return sim, nil
+}
+
 // AddRewards computes and transfers a staking reward to active escrow accounts.
 // If an error occurs, the pool and affected accounts are left in an invalid state.
 // This may fail due to the common pool running out of tokens. In this case, the
@@ -722,17 +1046,10 @@ func (s *MutableState) AddRewards(
 	factor *quantity.Quantity,
 	accounts []signature.PublicKey,
 ) error {
-	steps, err := s.RewardSchedule(ctx)
+	activeStep, err := s.activeRewardStep(ctx, time)
 	if err != nil {
 		return err
 	}
-	var activeStep *staking.RewardStep
-	for _, step := range steps {
-		if time < step.Until {
-			activeStep = &step
-			break
-		}
-	}
 	if activeStep == nil {
 		// We're past the end of the schedule.
 		return nil
@@ -743,6 +1060,11 @@ func (s *MutableState) AddRewards(
 		return fmt.Errorf("tendermint/staking: loading common pool: %w", err)
 	}
 
+	minter, err := s.newRewardMinter(ctx, time)
+	if err != nil {
+		return err
+	}
+
 	for _, id := range accounts {
 		var ent *staking.Account
 		ent, err = s.Account(ctx, id)
@@ -750,34 +1072,19 @@ func (s *MutableState) AddRewards(
 			return fmt.Errorf("tendermint/staking: failed to fetch account %s: %w", id, err)
 		}
 
-		q := ent.Escrow.Active.Balance.Clone()
-		// Multiply first.
-		if err = q.Mul(factor); err != nil {
-			return fmt.Errorf("tendermint/staking: failed multiplying by reward factor: %w", err)
+		var q, com *quantity.Quantity
+		if q, com, err = staking.ComputeReward(ent, time, factor, &activeStep.Scale); err != nil {
+			return err
 		}
-		if err = q.Mul(&activeStep.Scale); err != nil {
-			return fmt.Errorf("tendermint/staking: failed multiplying by reward step scale: %w", err)
-		}
-		if err = q.Quo(staking.RewardAmountDenominator); err != nil {
-			return fmt.Errorf("tendermint/staking: failed dividing by reward amount denominator: %w", err)
-		}
-
 		if q.IsZero() {
 			continue
 		}
 
-		var com *quantity.Quantity
-		rate := ent.Escrow.CommissionSchedule.CurrentRate(time)
-		if rate != nil {
-			com = q.Clone()
-			// Multiply first.
-			if err = com.Mul(rate); err != nil {
-				return fmt.Errorf("tendermint/staking: failed multiplying by commission rate: %w", err)
-			}
-			if err = com.Quo(staking.CommissionRateDenominator); err != nil {
-				return fmt.Errorf("tendermint/staking: failed dividing by commission rate denominator: %w", err)
-			}
+		if err = minter.mint(commonPool, q); err != nil {
+			return err
+		}
 
+		if com != nil {
 			if err = q.Sub(com); err != nil {
 				return fmt.Errorf("tendermint/staking: failed subtracting commission: %w", err)
 			}
@@ -796,26 +1103,9 @@ func (s *MutableState) AddRewards(
 		}
 
 		if com != nil && !com.IsZero() {
-			var delegation *staking.Delegation
-			delegation, err = s.Delegation(ctx, id, id)
-			if err != nil {
-				return fmt.Errorf("tendermint/staking: failed to query delegation: %w", err)
-			}
-
-			if err = ent.Escrow.Active.Deposit(&delegation.Shares, commonPool, com); err != nil {
-				return fmt.Errorf("tendermint/staking: depositing commission: %w", err)
-			}
-
-			if err = s.SetDelegation(ctx, id, id, delegation); err != nil {
-				return fmt.Errorf("tendermint/staking: failed to set delegation: %w", err)
+			if err = s.depositCommission(ctx, id, ent, commonPool, com); err != nil {
+				return err
 			}
-
-			ev := cbor.Marshal(&staking.AddEscrowEvent{
-				Owner:  staking.CommonPoolAccountID,
-				Escrow: id,
-				Tokens: *com,
-			})
-			ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyAddEscrow, ev))
 		}
 
 		if err = s.SetAccount(ctx, id, ent); err != nil {
@@ -826,6 +1116,9 @@ func (s *MutableState) AddRewards(
 	if err = s.SetCommonPool(ctx, commonPool); err != nil {
 		return fmt.Errorf("tendermint/staking: failed to set common pool: %w", err)
 	}
+	if err = minter.commit(ctx, s); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -867,6 +1160,11 @@ func (s *MutableState) AddRewardSingleAttenuated(
 		return fmt.Errorf("tendermint/staking: failed loading common pool: %w", err)
 	}
 
+	minter, err := s.newRewardMinter(ctx, time)
+	if err != nil {
+		return err
+	}
+
 	ent, err := s.Account(ctx, account)
 	if err != nil {
 		return fmt.Errorf("tendermint/staking: failed to query account %s: %w", account, err)
@@ -894,6 +1192,10 @@ func (s *MutableState) AddRewardSingleAttenuated(
 		return nil
 	}
 
+	if err = minter.mint(commonPool, q); err != nil {
+		return err
+	}
+
 	var com *quantity.Quantity
 	rate := ent.Escrow.CommissionSchedule.CurrentRate(time)
 	if rate != nil {
@@ -924,26 +1226,9 @@ func (s *MutableState) AddRewardSingleAttenuated(
 	}
 
 	if com != nil && !com.IsZero() {
-		var delegation *staking.Delegation
-		delegation, err = s.Delegation(ctx, account, account)
-		if err != nil {
-			return fmt.Errorf("tendermint/staking: failed to query delegation: %w", err)
+		if err = s.depositCommission(ctx, account, ent, commonPool, com); err != nil {
+			return err
 		}
-
-		if err = ent.Escrow.Active.Deposit(&delegation.Shares, commonPool, com); err != nil {
-			return fmt.Errorf("tendermint/staking: failed depositing commission: %w", err)
-		}
-
-		if err = s.SetDelegation(ctx, account, account, delegation); err != nil {
-			return fmt.Errorf("tendermint/staking: failed to set delegation: %w", err)
-		}
-
-		ev := cbor.Marshal(&staking.AddEscrowEvent{
-			Owner:  staking.CommonPoolAccountID,
-			Escrow: account,
-			Tokens: *com,
-		})
-		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyAddEscrow, ev))
 	}
 
 	if err = s.SetAccount(ctx, account, ent); err != nil {
@@ -953,6 +1238,9 @@ func (s *MutableState) AddRewardSingleAttenuated(
 	if err = s.SetCommonPool(ctx, commonPool); err != nil {
 		return fmt.Errorf("tendermint/staking: failed to set common pool: %w", err)
 	}
+	if err = minter.commit(ctx, s); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"crypto/rand"
 	"math/big"
 	"testing"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
@@ -333,3 +335,215 @@ func TestEpochSigning(t *testing.T) {
 	require.Zero(esClear.Total, "cleared epoch signing info total")
 	require.Empty(esClear.ByEntity, "cleared epoch signing info by entity")
 }
+
+func TestPayFees(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	var id signature.PublicKey
+	err := id.UnmarshalHex("1111111111111111111111111111111111111111111111111111111111111111")
+	require.NoError(err, "initializing account ID")
+
+	account := &staking.Account{}
+	account.General.Balance = mustInitQuantity(t, 100)
+	err = s.SetAccount(ctx, id, account)
+	require.NoError(err, "SetAccount")
+
+	err = PayFees(ctx, id, mustInitQuantityP(t, 20))
+	require.NoError(err, "PayFees")
+	err = PayFees(ctx, id, mustInitQuantityP(t, 1000))
+	require.Error(err, "PayFees over balance")
+
+	account, err = s.Account(ctx, id)
+	require.NoError(err, "Account")
+	require.Equal(mustInitQuantity(t, 80), account.General.Balance, "balance after fees")
+	fees := BlockFees(ctx)
+	require.Equal(mustInitQuantity(t, 20), fees, "block fees")
+}
+
+func TestRewardMinting(t *testing.T) {
+	require := require.New(t)
+
+	escrowID := memorySigner.NewTestSigner("reward minting test: escrow").Public()
+	escrowAccountOnly := []signature.PublicKey{escrowID}
+
+	escrowAccount := &staking.Account{}
+	del := &staking.Delegation{}
+	err := escrowAccount.Escrow.Active.Deposit(&del.Shares, mustInitQuantityP(t, 100), mustInitQuantityP(t, 100))
+	require.NoError(err, "active escrow deposit")
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	err = s.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		RewardSchedule: []staking.RewardStep{
+			{
+				Until: 100,
+				Scale: mustInitQuantity(t, 1000),
+			},
+		},
+		Minting: &staking.MintingParameters{
+			Schedule: []staking.MintingStep{
+				{
+					Until: 20,
+					Rate:  mustInitQuantity(t, 100_000),
+				},
+				{
+					Until: 40,
+					Rate:  mustInitQuantity(t, 50_000),
+				},
+			},
+			SupplyCap: mustInitQuantity(t, 1250),
+		},
+	})
+	require.NoError(err, "SetConsensusParameters")
+	err = s.SetAccount(ctx, escrowID, escrowAccount)
+	require.NoError(err, "SetAccount")
+	err = s.SetDelegation(ctx, escrowID, escrowID, del)
+	require.NoError(err, "SetDelegation")
+	err = s.SetCommonPool(ctx, mustInitQuantityP(t, 1000))
+	require.NoError(err, "SetCommonPool")
+	err = s.SetTotalSupply(ctx, mustInitQuantityP(t, 1100))
+	require.NoError(err, "SetTotalSupply")
+
+	requireTotals := func(msg string, escrow, commonPool, totalSupply, totalMinted int64) {
+		acct, err := s.Account(ctx, escrowID)
+		require.NoError(err, "Account")
+		require.Equal(mustInitQuantity(t, escrow), acct.Escrow.Active.Balance, "%s - escrow active escrow", msg)
+		q, err := s.CommonPool(ctx)
+		require.NoError(err, "CommonPool")
+		require.Equal(mustInitQuantityP(t, commonPool), q, "%s - common pool", msg)
+		q, err = s.TotalSupply(ctx)
+		require.NoError(err, "TotalSupply")
+		require.Equal(mustInitQuantityP(t, totalSupply), q, "%s - total supply", msg)
+		q, err = s.TotalMinted(ctx)
+		require.NoError(err, "TotalMinted")
+		require.Equal(mustInitQuantityP(t, totalMinted), q, "%s - total minted", msg)
+	}
+	requireMinted := func(msg string, minted int64) {
+		var total quantity.Quantity
+		for _, ev := range ctx.GetEvents() {
+			for _, pair := range ev.GetAttributes() {
+				if !bytes.Equal(pair.GetKey(), KeyMint) {
+					continue
+				}
+				var e staking.MintEvent
+				require.NoError(cbor.Unmarshal(pair.GetValue(), &e), "unmarshal mint event")
+				require.NoError(total.Add(&e.Tokens))
+			}
+		}
+		require.Equal(mustInitQuantity(t, minted), total, "%s - minted tokens in events", msg)
+	}
+
+	// Epoch 10 is during the first minting step, the whole reward is minted.
+	require.NoError(s.AddRewards(ctx, 10, mustInitQuantityP(t, 100), escrowAccountOnly), "add rewards epoch 10")
+	requireTotals("first minting step", 200, 1000, 1200, 100)
+	requireMinted("first minting step", 100)
+
+	// Epoch 30 is during the second minting step, half of the reward would be
+	// minted but only 50 tokens can be minted before reaching the supply cap.
+	require.NoError(s.AddRewards(ctx, 30, mustInitQuantityP(t, 100), escrowAccountOnly), "add rewards epoch 30")
+	requireTotals("supply cap reached", 400, 850, 1250, 150)
+	requireMinted("supply cap reached", 150)
+
+	// Once the supply cap is reached, rewards are paid from the common pool.
+	require.NoError(s.AddRewards(ctx, 35, mustInitQuantityP(t, 100), escrowAccountOnly), "add rewards epoch 35")
+	requireTotals("after supply cap", 800, 450, 1250, 150)
+	requireMinted("after supply cap", 150)
+
+	params, err := s.ConsensusParameters(ctx)
+	require.NoError(err, "ConsensusParameters")
+	totalSupply, err := s.TotalSupply(ctx)
+	require.NoError(err, "TotalSupply")
+	totalMinted, err := s.TotalMinted(ctx)
+	require.NoError(err, "TotalMinted")
+	require.NoError(staking.SanityCheckMinting(params, totalSupply, totalMinted), "SanityCheckMinting")
+}
+
+func TestSimulateRewards(t *testing.T) {
+	require := require.New(t)
+
+	escrowID := memorySigner.NewTestSigner("simulate rewards test: escrow").Public()
+	escrowAccountOnly := []signature.PublicKey{escrowID}
+
+	escrowAccount := &staking.Account{}
+	escrowAccount.Escrow.CommissionSchedule.Rates = []staking.CommissionRateStep{
+		{Start: 0, Rate: mustInitQuantity(t, 20_000)},
+	}
+	del := &staking.Delegation{}
+	err := escrowAccount.Escrow.Active.Deposit(&del.Shares, mustInitQuantityP(t, 100), mustInitQuantityP(t, 100))
+	require.NoError(err, "active escrow deposit")
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	err = s.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		RewardSchedule: []staking.RewardStep{
+			{
+				Until: 100,
+				Scale: mustInitQuantity(t, 1000),
+			},
+		},
+	})
+	require.NoError(err, "SetConsensusParameters")
+	err = s.SetAccount(ctx, escrowID, escrowAccount)
+	require.NoError(err, "SetAccount")
+	err = s.SetDelegation(ctx, escrowID, escrowID, del)
+	require.NoError(err, "SetDelegation")
+	err = s.SetCommonPool(ctx, mustInitQuantityP(t, 1000))
+	require.NoError(err, "SetCommonPool")
+
+	sim, err := s.SimulateRewards(ctx, 10, mustInitQuantityP(t, 100), escrowAccountOnly)
+	require.NoError(err, "SimulateRewards")
+	require.EqualValues(10, sim.Epoch, "epoch")
+	require.Equal(mustInitQuantity(t, 100), sim.Total, "total")
+	require.True(sim.Minted.IsZero(), "nothing should be minted")
+	require.False(sim.InsufficientCommonPool, "common pool should be sufficient")
+	require.Len(sim.Rewards, 1, "rewards")
+	proj := sim.Rewards[escrowID]
+	require.Equal(mustInitQuantity(t, 80), proj.Reward, "reward")
+	require.Equal(mustInitQuantity(t, 20), proj.Commission, "commission")
+	require.Equal(mustInitQuantity(t, 20_000), proj.CommissionRate, "commission rate")
+
+	// Simulation must not modify state.
+	q, err := s.CommonPool(ctx)
+	require.NoError(err, "CommonPool")
+	require.Equal(mustInitQuantityP(t, 1000), q, "common pool after simulation")
+
+	// The actual disbursement must match the simulation.
+	require.NoError(s.AddRewards(ctx, 10, mustInitQuantityP(t, 100), escrowAccountOnly), "add rewards epoch 10")
+	acct, err := s.Account(ctx, escrowID)
+	require.NoError(err, "Account")
+	require.Equal(mustInitQuantity(t, 200), acct.Escrow.Active.Balance, "active escrow after rewards")
+	q, err = s.CommonPool(ctx)
+	require.NoError(err, "CommonPool")
+	require.Equal(mustInitQuantityP(t, 900), q, "common pool after rewards")
+
+	// An insufficient common pool should be reported.
+	err = s.SetCommonPool(ctx, mustInitQuantityP(t, 50))
+	require.NoError(err, "SetCommonPool")
+	sim, err = s.SimulateRewards(ctx, 20, mustInitQuantityP(t, 100), escrowAccountOnly)
+	require.NoError(err, "SimulateRewards")
+	require.Equal(mustInitQuantity(t, 200), sim.Total, "total")
+	require.True(sim.InsufficientCommonPool, "common pool should be insufficient")
+
+	// No rewards past the end of the schedule.
+	sim, err = s.SimulateRewards(ctx, 100, mustInitQuantityP(t, 100), escrowAccountOnly)
+	require.NoError(err, "SimulateRewards")
+	require.Empty(sim.Rewards, "no rewards past the end of the schedule")
+	require.True(sim.Total.IsZero(), "total past the end of the schedule")
+}
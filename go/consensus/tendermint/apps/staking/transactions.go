@@ -1,6 +1,7 @@
 package staking
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/oasislabs/oasis-core/go/common/cbor"
@@ -22,6 +23,15 @@ func isTransferPermitted(params *staking.ConsensusParameters, fromID signature.P
 	return
 }
 
+// quantityError converts an insufficient balance error returned by quantity
+// operations into the corresponding staking error.
+func quantityError(err error) error {
+	if errors.Is(err, quantity.ErrInsufficientBalance) {
+		return staking.ErrInsufficientBalance
+	}
+	return err
+}
+
 func (app *stakingApplication) transfer(ctx *api.Context, state *stakingState.MutableState, xfer *staking.Transfer) error {
 	if ctx.IsCheckOnly() {
 		return nil
@@ -38,12 +48,87 @@ func (app *stakingApplication) transfer(ctx *api.Context, state *stakingState.Mu
 
 	fromID := ctx.TxSigner()
 	if !isTransferPermitted(params, fromID) {
-		return staking.ErrForbidden
+		return staking.ErrTransfersDisabled
+	}
+
+	evt, err := app.doTransfer(ctx, state, fromID, xfer)
+	if err != nil {
+		return err
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyTransfer, cbor.Marshal(evt)))
+
+	return nil
+}
+
+func (app *stakingApplication) transferBatch(ctx *api.Context, state *stakingState.MutableState, batch *staking.TransferBatch) error {
+	if len(batch.Transfers) == 0 {
+		return staking.ErrInvalidArgument
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction, the same as for the equivalent number
+	// of individual transfers.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(len(batch.Transfers), staking.GasOpTransfer, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Batch transfers are disabled in case there is no max batch size.
+	if params.MaxTransferBatchSize == 0 {
+		return staking.ErrTransferBatchDisabled
+	}
+	if uint64(len(batch.Transfers)) > uint64(params.MaxTransferBatchSize) {
+		ctx.Logger().Error("TransferBatch: too many transfers",
+			"num_transfers", len(batch.Transfers),
+			"max_transfer_batch_size", params.MaxTransferBatchSize,
+		)
+		return staking.ErrTooManyTransfers
+	}
+
+	fromID := ctx.TxSigner()
+	if !isTransferPermitted(params, fromID) {
+		return staking.ErrTransfersDisabled
+	}
+
+	// Create a new state checkpoint and rollback in case any transfer fails.
+	sc := ctx.StartCheckpoint()
+	defer sc.Close()
+	cpState := stakingState.NewMutableState(ctx.State())
+
+	evts := make([]*staking.TransferEvent, 0, len(batch.Transfers))
+	for i := range batch.Transfers {
+		var evt *staking.TransferEvent
+		if evt, err = app.doTransfer(ctx, cpState, fromID, &batch.Transfers[i]); err != nil {
+			return err
+		}
+		evts = append(evts, evt)
+	}
+
+	sc.Commit()
+
+	for _, evt := range evts {
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyTransfer, cbor.Marshal(evt)))
 	}
 
+	return nil
+}
+
+// doTransfer moves tokens from the given account to the transfer destination.
+func (app *stakingApplication) doTransfer(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	fromID signature.PublicKey,
+	xfer *staking.Transfer,
+) (*staking.TransferEvent, error) {
 	from, err := state.Account(ctx, fromID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch account: %w", err)
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
 	}
 
 	if fromID.Equal(xfer.To) {
@@ -56,7 +141,7 @@ func (app *stakingApplication) transfer(ctx *api.Context, state *stakingState.Mu
 				"to", xfer.To,
 				"amount", xfer.Tokens,
 			)
-			return err
+			return nil, err
 		}
 	} else {
 		// Source and destination MUST be separate accounts with how
@@ -64,7 +149,7 @@ func (app *stakingApplication) transfer(ctx *api.Context, state *stakingState.Mu
 		var to *staking.Account
 		to, err = state.Account(ctx, xfer.To)
 		if err != nil {
-			return fmt.Errorf("failed to fetch account: %w", err)
+			return nil, fmt.Errorf("failed to fetch account: %w", err)
 		}
 		if err = quantity.Move(&to.General.Balance, &from.General.Balance, &xfer.Tokens); err != nil {
 			ctx.Logger().Error("Transfer: failed to move balance",
@@ -73,16 +158,16 @@ func (app *stakingApplication) transfer(ctx *api.Context, state *stakingState.Mu
 				"to", xfer.To,
 				"amount", xfer.Tokens,
 			)
-			return err
+			return nil, quantityError(err)
 		}
 
 		if err = state.SetAccount(ctx, xfer.To, to); err != nil {
-			return fmt.Errorf("failed to set account: %w", err)
+			return nil, fmt.Errorf("failed to set account: %w", err)
 		}
 	}
 
 	if err = state.SetAccount(ctx, fromID, from); err != nil {
-		return fmt.Errorf("failed to fetch account: %w", err)
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
 	}
 
 	ctx.Logger().Debug("Transfer: executed transfer",
@@ -91,14 +176,11 @@ func (app *stakingApplication) transfer(ctx *api.Context, state *stakingState.Mu
 		"amount", xfer.Tokens,
 	)
 
-	evt := &staking.TransferEvent{
+	return &staking.TransferEvent{
 		From:   fromID,
 		To:     xfer.To,
 		Tokens: xfer.Tokens,
-	}
-	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyTransfer, cbor.Marshal(evt)))
-
-	return nil
+	}, nil
 }
 
 func (app *stakingApplication) burn(ctx *api.Context, state *stakingState.MutableState, burn *staking.Burn) error {
@@ -126,7 +208,7 @@ func (app *stakingApplication) burn(ctx *api.Context, state *stakingState.Mutabl
 			"err", err,
 			"from", id, "amount", burn.Tokens,
 		)
-		return err
+		return quantityError(err)
 	}
 
 	totalSupply, err := state.TotalSupply(ctx)
@@ -173,7 +255,7 @@ func (app *stakingApplication) addEscrow(ctx *api.Context, state *stakingState.M
 
 	// Check if sender provided at least a minimum amount of tokens.
 	if escrow.Tokens.Cmp(&params.MinDelegationAmount) < 0 {
-		return staking.ErrInvalidArgument
+		return staking.ErrBelowMinDelegationAmount
 	}
 
 	id := ctx.TxSigner()
@@ -191,7 +273,7 @@ func (app *stakingApplication) addEscrow(ctx *api.Context, state *stakingState.M
 		to = from
 	} else {
 		if params.DisableDelegation {
-			return staking.ErrForbidden
+			return staking.ErrDelegationDisabled
 		}
 		to, err = state.Account(ctx, escrow.Account)
 		if err != nil {
@@ -212,7 +294,7 @@ func (app *stakingApplication) addEscrow(ctx *api.Context, state *stakingState.M
 			"to", escrow.Account,
 			"amount", escrow.Tokens,
 		)
-		return err
+		return quantityError(err)
 	}
 
 	// Commit accounts.
@@ -279,7 +361,7 @@ func (app *stakingApplication) reclaimEscrow(ctx *api.Context, state *stakingSta
 		from = to
 	} else {
 		if params.DisableDelegation {
-			return staking.ErrForbidden
+			return staking.ErrDelegationDisabled
 		}
 		from, err = state.Account(ctx, reclaim.Account)
 		if err != nil {
@@ -319,6 +401,9 @@ func (app *stakingApplication) reclaimEscrow(ctx *api.Context, state *stakingSta
 			"from", reclaim.Account,
 			"shares", reclaim.Shares,
 		)
+		if errors.Is(err, quantity.ErrInsufficientBalance) {
+			return staking.ErrInsufficientShares
+		}
 		return err
 	}
 	tokenAmount := tokens.Clone()
@@ -394,12 +479,300 @@ func (app *stakingApplication) amendCommissionSchedule(
 			"err", err,
 			"from", id,
 		)
+		return fmt.Errorf("%w: %s", staking.ErrInvalidCommissionSchedule, err)
+	}
+
+	if err = state.SetAccount(ctx, id, from); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+
+	return nil
+}
+
+func (app *stakingApplication) setCommissionDestinations(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	setCommissionDestinations *staking.SetCommissionDestinations,
+) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpSetCommissionDestinations, params.GasCosts); err != nil {
 		return err
 	}
 
+	id := ctx.TxSigner()
+	if err = staking.ValidateCommissionDestinations(setCommissionDestinations.Destinations); err != nil {
+		ctx.Logger().Error("SetCommissionDestinations: destinations not acceptable",
+			"err", err,
+			"from", id,
+		)
+		return fmt.Errorf("%w: %s", staking.ErrInvalidCommissionDestinations, err)
+	}
+
+	from, err := state.Account(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account: %w", err)
+	}
+	from.Escrow.CommissionDestinations = setCommissionDestinations.Destinations
+
 	if err = state.SetAccount(ctx, id, from); err != nil {
 		return fmt.Errorf("failed to set account: %w", err)
 	}
 
+	evt := &staking.CommissionDestinationsEvent{
+		Escrow:       id,
+		Destinations: setCommissionDestinations.Destinations,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyCommissionDestinations, cbor.Marshal(evt)))
+
+	return nil
+}
+
+func (app *stakingApplication) allow(ctx *api.Context, state *stakingState.MutableState, allow *staking.Allow) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpAllow, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Allowances are disabled in case there are no max allowances.
+	if params.MaxAllowances == 0 {
+		return staking.ErrAllowancesDisabled
+	}
+
+	id := ctx.TxSigner()
+	if id.Equal(allow.Beneficiary) {
+		ctx.Logger().Error("Allow: self-allowance is not permitted",
+			"owner", id,
+		)
+		return staking.ErrSelfAllowance
+	}
+
+	acct, err := state.Account(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	allowance := acct.General.Allowances[allow.Beneficiary]
+	allowance = *allowance.Clone()
+	if allow.Negative {
+		// Decrease the allowance, clamping it at zero.
+		if _, err = allowance.SubUpTo(&allow.AmountChange); err != nil {
+			return fmt.Errorf("failed to change allowance: %w", err)
+		}
+	} else {
+		if err = allowance.Add(&allow.AmountChange); err != nil {
+			return fmt.Errorf("failed to change allowance: %w", err)
+		}
+	}
+
+	if allowance.IsZero() {
+		delete(acct.General.Allowances, allow.Beneficiary)
+	} else {
+		if acct.General.Allowances == nil {
+			acct.General.Allowances = make(map[signature.PublicKey]quantity.Quantity)
+		}
+		if _, exists := acct.General.Allowances[allow.Beneficiary]; !exists &&
+			uint32(len(acct.General.Allowances)) >= params.MaxAllowances {
+			ctx.Logger().Error("Allow: too many allowances",
+				"owner", id,
+				"beneficiary", allow.Beneficiary,
+				"max_allowances", params.MaxAllowances,
+			)
+			return staking.ErrTooManyAllowances
+		}
+		acct.General.Allowances[allow.Beneficiary] = allowance
+	}
+
+	if err = state.SetAccount(ctx, id, acct); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+
+	evt := &staking.AllowanceChangeEvent{
+		Owner:        id,
+		Beneficiary:  allow.Beneficiary,
+		Allowance:    allowance,
+		Negative:     allow.Negative,
+		AmountChange: allow.AmountChange,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyAllowanceChange, cbor.Marshal(evt)))
+
+	return nil
+}
+
+func (app *stakingApplication) withdraw(ctx *api.Context, state *stakingState.MutableState, withdraw *staking.Withdraw) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpWithdraw, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Allowances are disabled in case there are no max allowances.
+	if params.MaxAllowances == 0 {
+		return staking.ErrAllowancesDisabled
+	}
+
+	// The withdrawn tokens are transferred from the owner's account, so the
+	// owner must be permitted to transfer.
+	if !isTransferPermitted(params, withdraw.From) {
+		return staking.ErrTransfersDisabled
+	}
+
+	toID := ctx.TxSigner()
+	if toID.Equal(withdraw.From) {
+		ctx.Logger().Error("Withdraw: withdrawal from self is not permitted",
+			"from", withdraw.From,
+		)
+		return staking.ErrSelfAllowance
+	}
+
+	from, err := state.Account(ctx, withdraw.From)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	allowance := from.General.Allowances[toID]
+	allowance = *allowance.Clone()
+	if err = allowance.Sub(&withdraw.Tokens); err != nil {
+		ctx.Logger().Error("Withdraw: withdrawal greater than allowance",
+			"err", err,
+			"from", withdraw.From,
+			"to", toID,
+			"amount", withdraw.Tokens,
+		)
+		return staking.ErrInsufficientAllowance
+	}
+	if allowance.IsZero() {
+		delete(from.General.Allowances, toID)
+	} else {
+		from.General.Allowances[toID] = allowance
+	}
+
+	// Source and destination MUST be separate accounts with how
+	// quantity.Move is implemented.
+	to, err := state.Account(ctx, toID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account: %w", err)
+	}
+	if err = quantity.Move(&to.General.Balance, &from.General.Balance, &withdraw.Tokens); err != nil {
+		ctx.Logger().Error("Withdraw: failed to move balance",
+			"err", err,
+			"from", withdraw.From,
+			"to", toID,
+			"amount", withdraw.Tokens,
+		)
+		return quantityError(err)
+	}
+
+	if err = state.SetAccount(ctx, withdraw.From, from); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+	if err = state.SetAccount(ctx, toID, to); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+
+	ctx.Logger().Debug("Withdraw: executed withdrawal",
+		"from", withdraw.From,
+		"to", toID,
+		"amount", withdraw.Tokens,
+	)
+
+	xferEvt := &staking.TransferEvent{
+		From:   withdraw.From,
+		To:     toID,
+		Tokens: withdraw.Tokens,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyTransfer, cbor.Marshal(xferEvt)))
+
+	allowanceEvt := &staking.AllowanceChangeEvent{
+		Owner:        withdraw.From,
+		Beneficiary:  toID,
+		Allowance:    allowance,
+		Negative:     true,
+		AmountChange: withdraw.Tokens,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyAllowanceChange, cbor.Marshal(allowanceEvt)))
+
+	return nil
+}
+
+func (app *stakingApplication) cancelSlash(ctx *api.Context, state *stakingState.MutableState, cancel *staking.CancelSlash) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpCancelSlash, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Only slashing authorities can vote to cancel pending slashes.
+	id := ctx.TxSigner()
+	if !params.SlashingAuthorities[id] {
+		return staking.ErrForbidden
+	}
+
+	ps, err := state.PendingSlash(ctx, cancel.ID)
+	if err != nil {
+		return err
+	}
+	if ps.HasCancelVote(id) {
+		ctx.Logger().Error("CancelSlash: already voted",
+			"pending_slash_id", ps.ID,
+			"authority", id,
+		)
+		return staking.ErrInvalidArgument
+	}
+	ps.CancelVotes = append(ps.CancelVotes, id)
+
+	if uint64(len(ps.CancelVotes)) < params.SlashCancelThreshold {
+		// Not enough votes yet, just record the vote.
+		if err = state.SetPendingSlash(ctx, ps); err != nil {
+			return fmt.Errorf("failed to set pending slash: %w", err)
+		}
+		return nil
+	}
+
+	if err = state.RemovePendingSlash(ctx, ps); err != nil {
+		return fmt.Errorf("failed to remove pending slash: %w", err)
+	}
+
+	ctx.Logger().Info("CancelSlash: pending slash cancelled",
+		"pending_slash_id", ps.ID,
+		"node_id", ps.NodeID,
+		"entity_id", ps.EntityID,
+		"reason", ps.Reason,
+	)
+
+	evt := &staking.PendingSlashEvent{
+		Cancelled: ps,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyPendingSlash, cbor.Marshal(evt)))
+
 	return nil
 }
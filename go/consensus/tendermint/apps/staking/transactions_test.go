@@ -2,13 +2,24 @@ package staking
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	abciAPI "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
 	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
 
+func mustQuantity(t *testing.T, n uint64) quantity.Quantity {
+	var q quantity.Quantity
+	require.NoError(t, q.FromUint64(n), "FromUint64")
+	return q
+}
+
 func TestIsTransferPermitted(t *testing.T) {
 	for _, tt := range []struct {
 		msg       string
@@ -56,3 +67,211 @@ func TestIsTransferPermitted(t *testing.T) {
 		require.Equal(t, tt.permitted, isTransferPermitted(tt.params, tt.fromID), tt.msg)
 	}
 }
+
+func TestAllowAndWithdraw(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := &stakingApplication{}
+	state := stakingState.NewMutableState(ctx.State())
+
+	ownerID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: allow owner").Public()
+	beneficiaryID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: allow beneficiary").Public()
+
+	var owner staking.Account
+	owner.General.Balance = mustQuantity(t, 1000)
+	err := state.SetAccount(ctx, ownerID, &owner)
+	require.NoError(err, "SetAccount")
+
+	params := &staking.ConsensusParameters{}
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	// Allowances are disabled when there are no max allowances.
+	ctx.SetTxSigner(ownerID)
+	allow := &staking.Allow{Beneficiary: beneficiaryID, AmountChange: mustQuantity(t, 300)}
+	err = app.allow(ctx, state, allow)
+	require.Equal(staking.ErrAllowancesDisabled, err, "allow should fail when allowances are disabled")
+
+	params.MaxAllowances = 1
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	// Self-allowances are not permitted.
+	err = app.allow(ctx, state, &staking.Allow{Beneficiary: ownerID, AmountChange: mustQuantity(t, 300)})
+	require.Equal(staking.ErrSelfAllowance, err, "self-allowance should fail")
+
+	err = app.allow(ctx, state, allow)
+	require.NoError(err, "allow")
+	acct, err := state.Account(ctx, ownerID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 300), acct.General.Allowances[beneficiaryID], "allowance should be set")
+
+	// The number of allowances is limited.
+	otherID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: allow other").Public()
+	err = app.allow(ctx, state, &staking.Allow{Beneficiary: otherID, AmountChange: mustQuantity(t, 1)})
+	require.Equal(staking.ErrTooManyAllowances, err, "allow should fail when there are too many allowances")
+
+	// Withdrawals are limited by the allowance.
+	ctx.SetTxSigner(beneficiaryID)
+	err = app.withdraw(ctx, state, &staking.Withdraw{From: ownerID, Tokens: mustQuantity(t, 301)})
+	require.Equal(staking.ErrInsufficientAllowance, err, "withdrawal greater than allowance should fail")
+
+	err = app.withdraw(ctx, state, &staking.Withdraw{From: ownerID, Tokens: mustQuantity(t, 200)})
+	require.NoError(err, "withdraw")
+	acct, err = state.Account(ctx, ownerID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 800), acct.General.Balance, "owner balance should be decreased")
+	require.Equal(mustQuantity(t, 100), acct.General.Allowances[beneficiaryID], "allowance should be decreased")
+	acct, err = state.Account(ctx, beneficiaryID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 200), acct.General.Balance, "beneficiary balance should be increased")
+
+	// Decreasing the allowance below zero removes it.
+	ctx.SetTxSigner(ownerID)
+	err = app.allow(ctx, state, &staking.Allow{Beneficiary: beneficiaryID, Negative: true, AmountChange: mustQuantity(t, 500)})
+	require.NoError(err, "allow (negative)")
+	acct, err = state.Account(ctx, ownerID)
+	require.NoError(err, "Account")
+	require.Empty(acct.General.Allowances, "allowance should be removed")
+
+	ctx.SetTxSigner(beneficiaryID)
+	err = app.withdraw(ctx, state, &staking.Withdraw{From: ownerID, Tokens: mustQuantity(t, 1)})
+	require.Equal(staking.ErrInsufficientAllowance, err, "withdrawal without allowance should fail")
+}
+
+func TestTransferAndEscrowErrors(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := &stakingApplication{}
+	state := stakingState.NewMutableState(ctx.State())
+
+	fromID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: errors from").Public()
+	toID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: errors to").Public()
+
+	var from staking.Account
+	from.General.Balance = mustQuantity(t, 100)
+	err := state.SetAccount(ctx, fromID, &from)
+	require.NoError(err, "SetAccount")
+
+	params := &staking.ConsensusParameters{
+		DisableTransfers:    true,
+		DisableDelegation:   true,
+		MinDelegationAmount: mustQuantity(t, 10),
+	}
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	ctx.SetTxSigner(fromID)
+	err = app.transfer(ctx, state, &staking.Transfer{To: toID, Tokens: mustQuantity(t, 1)})
+	require.Equal(staking.ErrTransfersDisabled, err, "transfer should fail when transfers are disabled")
+
+	err = app.addEscrow(ctx, state, &staking.Escrow{Account: toID, Tokens: mustQuantity(t, 1)})
+	require.Equal(staking.ErrBelowMinDelegationAmount, err, "escrow below minimum delegation amount should fail")
+
+	err = app.addEscrow(ctx, state, &staking.Escrow{Account: toID, Tokens: mustQuantity(t, 10)})
+	require.Equal(staking.ErrDelegationDisabled, err, "escrow should fail when delegation is disabled")
+
+	params.DisableTransfers = false
+	params.DisableDelegation = false
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	err = app.transfer(ctx, state, &staking.Transfer{To: toID, Tokens: mustQuantity(t, 101)})
+	require.Equal(staking.ErrInsufficientBalance, err, "transfer greater than balance should fail")
+
+	err = app.burn(ctx, state, &staking.Burn{Tokens: mustQuantity(t, 101)})
+	require.Equal(staking.ErrInsufficientBalance, err, "burn greater than balance should fail")
+
+	err = app.addEscrow(ctx, state, &staking.Escrow{Account: toID, Tokens: mustQuantity(t, 101)})
+	require.Equal(staking.ErrInsufficientBalance, err, "escrow greater than balance should fail")
+}
+
+func TestTransferBatch(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := &stakingApplication{}
+	state := stakingState.NewMutableState(ctx.State())
+
+	fromID := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: batch from").Public()
+	toID1 := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: batch to 1").Public()
+	toID2 := memorySigner.NewTestSigner("consensus/tendermint/apps/staking: batch to 2").Public()
+
+	var from staking.Account
+	from.General.Balance = mustQuantity(t, 100)
+	err := state.SetAccount(ctx, fromID, &from)
+	require.NoError(err, "SetAccount")
+
+	params := &staking.ConsensusParameters{}
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	batch := &staking.TransferBatch{
+		Transfers: []staking.Transfer{
+			{To: toID1, Tokens: mustQuantity(t, 10)},
+			{To: toID2, Tokens: mustQuantity(t, 20)},
+			{To: toID1, Tokens: mustQuantity(t, 30)},
+		},
+	}
+
+	// Batch transfers are disabled when there is no max batch size.
+	ctx.SetTxSigner(fromID)
+	err = app.transferBatch(ctx, state, batch)
+	require.Equal(staking.ErrTransferBatchDisabled, err, "batch transfer should fail when batch transfers are disabled")
+
+	params.MaxTransferBatchSize = 2
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	err = app.transferBatch(ctx, state, &staking.TransferBatch{})
+	require.Equal(staking.ErrInvalidArgument, err, "empty batch transfer should fail")
+
+	err = app.transferBatch(ctx, state, batch)
+	require.Equal(staking.ErrTooManyTransfers, err, "batch transfer should fail when there are too many transfers")
+
+	params.MaxTransferBatchSize = 3
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	// A failing transfer should revert the whole batch.
+	err = app.transferBatch(ctx, state, &staking.TransferBatch{
+		Transfers: []staking.Transfer{
+			{To: toID1, Tokens: mustQuantity(t, 50)},
+			{To: toID2, Tokens: mustQuantity(t, 51)},
+		},
+	})
+	require.Equal(staking.ErrInsufficientBalance, err, "batch transfer greater than balance should fail")
+	acct, err := state.Account(ctx, fromID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 100), acct.General.Balance, "source balance should be unchanged")
+	acct, err = state.Account(ctx, toID1)
+	require.NoError(err, "Account")
+	require.True(acct.General.Balance.IsZero(), "destination balance should be unchanged")
+
+	err = app.transferBatch(ctx, state, batch)
+	require.NoError(err, "transferBatch")
+	require.Len(ctx.GetEvents(), 3, "a transfer event should be emitted for each transfer")
+	acct, err = state.Account(ctx, fromID)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 40), acct.General.Balance, "source balance should be decreased")
+	acct, err = state.Account(ctx, toID1)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 40), acct.General.Balance, "destination balance should be increased")
+	acct, err = state.Account(ctx, toID2)
+	require.NoError(err, "Account")
+	require.Equal(mustQuantity(t, 20), acct.General.Balance, "destination balance should be increased")
+}
@@ -60,6 +60,20 @@ func checkRegistry(ctx *abciAPI.Context, now epochtime.EpochTime) error {
 	if err != nil {
 		return fmt.Errorf("SanityCheckRuntimes: %w", err)
 	}
+	governanceSuspendedRuntimes, err := st.GovernanceSuspendedRuntimes(ctx)
+	if err != nil {
+		return fmt.Errorf("GovernanceSuspendedRuntimes: %w", err)
+	}
+	if err = registry.SanityCheckGovernanceSuspendedRuntimes(governanceSuspendedRuntimes, runtimeLookup); err != nil {
+		return fmt.Errorf("SanityCheckGovernanceSuspendedRuntimes: %w", err)
+	}
+	pendingRuntimeTransfers, err := st.PendingRuntimeTransfers(ctx)
+	if err != nil {
+		return fmt.Errorf("PendingRuntimeTransfers: %w", err)
+	}
+	if err = registry.SanityCheckPendingRuntimeTransfers(pendingRuntimeTransfers, seenEntities, runtimeLookup); err != nil {
+		return fmt.Errorf("SanityCheckPendingRuntimeTransfers: %w", err)
+	}
 
 	// Check nodes.
 	signedNodes, err := st.SignedNodes(ctx)
@@ -153,6 +167,14 @@ func checkStaking(ctx *abciAPI.Context, now epochtime.EpochTime) error {
 		return fmt.Errorf("balances in accounts plus common pool (%s) plus last block fees (%s) does not add up to total supply (%s)", total.String(), totalFees.String(), totalSupply.String())
 	}
 
+	totalMinted, err := st.TotalMinted(ctx)
+	if err != nil {
+		return fmt.Errorf("TotalMinted: %w", err)
+	}
+	if err = staking.SanityCheckMinting(parameters, totalSupply, totalMinted); err != nil {
+		return err
+	}
+
 	// All shares of all delegations for a given account must add up to account's Escrow.Active.TotalShares.
 	delegationses, err := st.Delegations(ctx)
 	if err != nil {
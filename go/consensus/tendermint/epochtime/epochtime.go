@@ -2,15 +2,20 @@
 package epochtime
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"errors"
 	"sync"
 
 	"github.com/eapache/channels"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
 	tmtypes "github.com/tendermint/tendermint/types"
 
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	app "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/epochtime"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/service"
 	"github.com/oasislabs/oasis-core/go/epochtime/api"
 )
@@ -23,9 +28,12 @@ type tendermintBackend struct {
 	logger *logging.Logger
 
 	service  service.TendermintService
+	querier  *app.QueryFactory
 	notifier *pubsub.Broker
 
-	interval     int64
+	intervalNotifier *pubsub.Broker
+
+	genesis      *api.Genesis
 	lastNotified api.EpochTime
 	epoch        api.EpochTime
 	base         api.EpochTime
@@ -41,18 +49,48 @@ func (t *tendermintBackend) GetEpoch(ctx context.Context, height int64) (api.Epo
 		defer t.RUnlock()
 		return t.epoch, nil
 	}
-	epoch := t.base + api.EpochTime(height/t.interval)
+	schedule, err := t.intervalSchedule(ctx)
+	if err != nil {
+		return api.EpochInvalid, err
+	}
 
-	return epoch, nil
+	return schedule.EpochAt(height), nil
 }
 
 func (t *tendermintBackend) GetEpochBlock(ctx context.Context, epoch api.EpochTime) (int64, error) {
-	if epoch < t.base {
-		return 0, fmt.Errorf("epochtime/tendermint: epoch predates base")
+	schedule, err := t.intervalSchedule(ctx)
+	if err != nil {
+		return 0, err
 	}
-	height := int64(epoch-t.base) * t.interval
 
-	return height, nil
+	return schedule.HeightAt(epoch)
+}
+
+// intervalSchedule returns the most recent epoch interval schedule.
+//
+// Interval changes always take effect after the block that scheduled them
+// has been committed, so the latest committed schedule is valid for all
+// heights up to and including the block that is currently being processed.
+func (t *tendermintBackend) intervalSchedule(ctx context.Context) (api.IntervalSchedule, error) {
+	genesisSchedule := api.NewIntervalSchedule(t.base, t.genesis.Parameters.Interval)
+
+	q, err := t.querier.QueryAt(ctx, consensus.HeightLatest)
+	switch {
+	case err == nil:
+	case errors.Is(err, consensus.ErrNoCommittedBlocks):
+		return genesisSchedule, nil
+	default:
+		return nil, err
+	}
+
+	schedule, err := q.IntervalSchedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return genesisSchedule, nil
+	}
+	return schedule, nil
 }
 
 func (t *tendermintBackend) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
@@ -71,18 +109,34 @@ func (t *tendermintBackend) WatchLatestEpoch() (<-chan api.EpochTime, *pubsub.Su
 	return typedCh, sub
 }
 
+func (t *tendermintBackend) WatchIntervalChanges() (<-chan *api.IntervalChange, *pubsub.Subscription) {
+	typedCh := make(chan *api.IntervalChange)
+	sub := t.intervalNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
+
 func (t *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
 	now, err := t.GetEpoch(ctx, height)
 	if err != nil {
 		return nil, err
 	}
 
+	q, err := t.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	params, err := q.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any pending interval change is applied immediately as the exported
+	// state starts a new epoch anyway.
 	return &api.Genesis{
-		Parameters: api.ConsensusParameters{
-			DebugMockBackend: false,
-			Interval:         t.interval,
-		},
-		Base: now,
+		Parameters: *params,
+		Base:       now,
 	}, nil
 }
 
@@ -103,6 +157,64 @@ func (t *tendermintBackend) worker(ctx context.Context) {
 	}
 }
 
+func (t *tendermintBackend) eventWorker(ctx context.Context) {
+	sub, err := t.service.Subscribe("epochtime-worker", app.QueryApp)
+	if err != nil {
+		t.logger.Error("failed to subscribe",
+			"err", err,
+		)
+		return
+	}
+	defer t.service.Unsubscribe("epochtime-worker", app.QueryApp) // nolint: errcheck
+
+	for {
+		var event interface{}
+
+		select {
+		case msg := <-sub.Out():
+			event = msg.Data()
+		case <-sub.Cancelled():
+			t.logger.Debug("worker: terminating, subscription closed")
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		switch ev := event.(type) {
+		case tmtypes.EventDataTx:
+			t.onABCIEvents(ev.Result.Events)
+		default:
+		}
+	}
+}
+
+func (t *tendermintBackend) onABCIEvents(tmEvents []abcitypes.Event) {
+	for _, tmEv := range tmEvents {
+		if tmEv.GetType() != app.EventType {
+			continue
+		}
+
+		for _, pair := range tmEv.GetAttributes() {
+			if bytes.Equal(pair.GetKey(), app.KeyIntervalChange) {
+				var change api.IntervalChange
+				if err := cbor.Unmarshal(pair.GetValue(), &change); err != nil {
+					t.logger.Error("worker: malformed interval change",
+						"err", err,
+					)
+					continue
+				}
+
+				t.logger.Debug("epoch interval change scheduled",
+					"interval", change.Interval,
+					"epoch", change.Epoch,
+					"height", change.Height,
+				)
+				t.intervalNotifier.Broadcast(&change)
+			}
+		}
+	}
+}
+
 func (t *tendermintBackend) updateCached(ctx context.Context, block *tmtypes.Block) bool {
 	t.Lock()
 	defer t.Unlock()
@@ -122,9 +234,14 @@ func (t *tendermintBackend) updateCached(ctx context.Context, block *tmtypes.Blo
 	return false
 }
 
-// New constructs a new tendermint backed epochtime Backend instance,
-// with the specified epoch interval.
-func New(ctx context.Context, service service.TendermintService, interval int64) (api.Backend, error) {
+// New constructs a new tendermint backed epochtime Backend instance.
+func New(ctx context.Context, service service.TendermintService) (api.Backend, error) {
+	// Initialize and register the tendermint service component.
+	a := app.New()
+	if err := service.RegisterApplication(a); err != nil {
+		return nil, err
+	}
+
 	genDoc, err := service.GetGenesisDocument(ctx)
 	if err != nil {
 		return nil, err
@@ -132,11 +249,13 @@ func New(ctx context.Context, service service.TendermintService, interval int64)
 
 	base := genDoc.EpochTime.Base
 	r := &tendermintBackend{
-		logger:   logging.GetLogger("epochtime/tendermint"),
-		service:  service,
-		interval: interval,
-		base:     base,
-		epoch:    base,
+		logger:           logging.GetLogger("epochtime/tendermint"),
+		service:          service,
+		querier:          a.QueryFactory().(*app.QueryFactory),
+		intervalNotifier: pubsub.NewBroker(false),
+		genesis:          &genDoc.EpochTime,
+		base:             base,
+		epoch:            base,
 	}
 	r.notifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
 		r.RLock()
@@ -148,6 +267,7 @@ func New(ctx context.Context, service service.TendermintService, interval int64)
 	})
 
 	go r.worker(ctx)
+	go r.eventWorker(ctx)
 
 	return r, nil
 }
@@ -37,6 +37,8 @@ type tendermintMockBackend struct {
 	querier  *app.QueryFactory
 	notifier *pubsub.Broker
 
+	intervalNotifier *pubsub.Broker
+
 	lastNotified api.EpochTime
 	epoch        api.EpochTime
 	currentBlock int64
@@ -88,6 +90,15 @@ func (t *tendermintMockBackend) WatchLatestEpoch() (<-chan api.EpochTime, *pubsu
 	return typedCh, sub
 }
 
+func (t *tendermintMockBackend) WatchIntervalChanges() (<-chan *api.IntervalChange, *pubsub.Subscription) {
+	// The mock backend does not use epoch intervals, so this never fires.
+	typedCh := make(chan *api.IntervalChange)
+	sub := t.intervalNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
+
 func (t *tendermintMockBackend) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
 	now, err := t.GetEpoch(ctx, height)
 	if err != nil {
@@ -236,9 +247,10 @@ func New(ctx context.Context, service service.TendermintService) (api.SetableBac
 	}
 
 	r := &tendermintMockBackend{
-		logger:  logging.GetLogger("epochtime/tendermint_mock"),
-		service: service,
-		querier: a.QueryFactory().(*app.QueryFactory),
+		logger:           logging.GetLogger("epochtime/tendermint_mock"),
+		service:          service,
+		querier:          a.QueryFactory().(*app.QueryFactory),
+		intervalNotifier: pubsub.NewBroker(false),
 	}
 	r.notifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
 		r.RLock()
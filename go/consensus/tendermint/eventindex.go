@@ -0,0 +1,306 @@
+package tendermint
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	tmabcitypes "github.com/tendermint/tendermint/abci/types"
+	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmdb "github.com/tendermint/tm-db"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/keyformat"
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+// maxIndexedFieldSize is the maximum size of an encoded event field value
+// for the field to be indexed. Conditions on larger fields are still
+// evaluated, but only after looking up candidate heights by event type.
+const maxIndexedFieldSize = 128
+
+var (
+	// eventKeyFmt is the key format used for heights with events of a given
+	// type and key.
+	//
+	// Value is empty.
+	eventKeyFmt = keyformat.New(0x01, &hash.Hash{}, uint64(0))
+	// eventFieldKeyFmt is the key format used for heights with events of a
+	// given type and key that have a field with the given value.
+	//
+	// Value is empty.
+	eventFieldKeyFmt = keyformat.New(0x02, &hash.Hash{}, uint64(0))
+	// lastHeightKeyFmt is the key format used for the last indexed height.
+	//
+	// Value is the big-endian encoded height.
+	lastHeightKeyFmt = keyformat.New(0x03)
+)
+
+// blockResultsFunc returns the ABCI results of the block at the given height.
+type blockResultsFunc func(height int64) (*tmrpctypes.ResultBlockResults, error)
+
+// eventIndex is a persistent index of the heights of blocks that emitted
+// events of a given type and key, optionally with given field values.
+//
+// Only heights are indexed, the events themselves are retrieved from the
+// stored ABCI block results when searching.
+type eventIndex struct {
+	sync.RWMutex
+
+	db         tmdb.DB
+	getResults blockResultsFunc
+	closed     bool
+}
+
+func eventHash(typ string, key []byte) hash.Hash {
+	return hash.NewFromBytes([]byte(typ), []byte{0x00}, key)
+}
+
+func eventFieldHash(typ string, key []byte, field string, value []byte) hash.Hash {
+	return hash.NewFromBytes([]byte(typ), []byte{0x00}, key, []byte{0x00}, []byte(field), []byte{0x00}, value)
+}
+
+// decodeEventFields decodes the top-level fields of a CBOR-encoded event.
+func decodeEventFields(value []byte) map[string]cbor.RawMessage {
+	var fields map[string]cbor.RawMessage
+	if err := cbor.Unmarshal(value, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// visitEvents calls fn for each event attribute in the block results in the
+// order in which they were emitted, starting with BeginBlock events, followed
+// by transaction events and EndBlock events.
+func visitEvents(results *tmrpctypes.ResultBlockResults, fn func(index uint64, txIndex *uint32, typ string, key, value []byte) bool) {
+	var index uint64
+	visit := func(txIndex *uint32, events []tmabcitypes.Event) bool {
+		for _, ev := range events {
+			for _, pair := range ev.Attributes {
+				if !fn(index, txIndex, ev.Type, pair.GetKey(), pair.GetValue()) {
+					return false
+				}
+				index++
+			}
+		}
+		return true
+	}
+
+	if !visit(nil, results.BeginBlockEvents) {
+		return
+	}
+	for i, tx := range results.TxsResults {
+		txIndex := uint32(i)
+		if !visit(&txIndex, tx.Events) {
+			return
+		}
+	}
+	visit(nil, results.EndBlockEvents)
+}
+
+// LastHeight returns the last indexed height.
+func (idx *eventIndex) LastHeight() (int64, error) {
+	idx.RLock()
+	defer idx.RUnlock()
+
+	return idx.lastHeightLocked()
+}
+
+func (idx *eventIndex) lastHeightLocked() (int64, error) {
+	if idx.closed {
+		return 0, fmt.Errorf("tendermint/eventindex: index closed")
+	}
+
+	raw, err := idx.db.Get(lastHeightKeyFmt.Encode())
+	if err != nil {
+		return 0, err
+	}
+	if raw == nil {
+		return 0, nil
+	}
+
+	var height uint64
+	if err = cbor.Unmarshal(raw, &height); err != nil {
+		return 0, fmt.Errorf("tendermint/eventindex: corrupted last height: %w", err)
+	}
+	return int64(height), nil
+}
+
+// Index indexes the events emitted by the block at the given height.
+//
+// Heights must be indexed in increasing order.
+func (idx *eventIndex) Index(height int64) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	lastHeight, err := idx.lastHeightLocked()
+	if err != nil {
+		return err
+	}
+	if height <= lastHeight {
+		return fmt.Errorf("tendermint/eventindex: height %d already indexed", height)
+	}
+
+	results, err := idx.getResults(height)
+	if err != nil {
+		return fmt.Errorf("tendermint/eventindex: failed to get block results: %w", err)
+	}
+
+	batch := idx.db.NewBatch()
+	defer batch.Close()
+
+	if results != nil {
+		visitEvents(results, func(index uint64, txIndex *uint32, typ string, key, value []byte) bool {
+			h := eventHash(typ, key)
+			batch.Set(eventKeyFmt.Encode(&h, uint64(height)), []byte{})
+
+			for field, fieldValue := range decodeEventFields(value) {
+				if len(fieldValue) > maxIndexedFieldSize {
+					continue
+				}
+				fh := eventFieldHash(typ, key, field, fieldValue)
+				batch.Set(eventFieldKeyFmt.Encode(&fh, uint64(height)), []byte{})
+			}
+			return true
+		})
+	}
+	batch.Set(lastHeightKeyFmt.Encode(), cbor.Marshal(uint64(height)))
+
+	return batch.WriteSync()
+}
+
+// Search returns a page of events matching the given query.
+func (idx *eventIndex) Search(query *consensusAPI.EventSearchQuery) (*consensusAPI.EventSearchPage, error) {
+	if query.Type == "" || query.Key == "" {
+		return nil, fmt.Errorf("%w: event type and key must be set", consensusAPI.ErrInvalidArgument)
+	}
+
+	idx.RLock()
+	defer idx.RUnlock()
+
+	lastHeight, err := idx.lastHeightLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	fromHeight, toHeight := query.FromHeight, query.ToHeight
+	if fromHeight < 1 {
+		fromHeight = 1
+	}
+	if toHeight == consensusAPI.HeightLatest || toHeight > lastHeight {
+		toHeight = lastHeight
+	}
+	if query.Cursor != nil && query.Cursor.Height > fromHeight {
+		fromHeight = query.Cursor.Height
+	}
+
+	page := &consensusAPI.EventSearchPage{
+		Events: []*consensusAPI.EventSearchResult{},
+	}
+	if fromHeight > toHeight {
+		return page, nil
+	}
+
+	// Use the most selective index available to find candidate heights.
+	keyFmt, h := eventKeyFmt, eventHash(query.Type, []byte(query.Key))
+	for _, cond := range query.Conditions {
+		if len(cond.Value) <= maxIndexedFieldSize {
+			keyFmt, h = eventFieldKeyFmt, eventFieldHash(query.Type, []byte(query.Key), cond.Field, cond.Value)
+			break
+		}
+	}
+
+	it, err := idx.db.Iterator(keyFmt.Encode(&h, uint64(fromHeight)), keyFmt.Encode(&h, uint64(toHeight)+1))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	limit := query.PageLimit()
+	for ; it.Valid(); it.Next() {
+		var (
+			decHash hash.Hash
+			height  uint64
+		)
+		if !keyFmt.Decode(it.Key(), &decHash, &height) {
+			break
+		}
+
+		results, err := idx.getResults(int64(height))
+		if err != nil {
+			return nil, fmt.Errorf("tendermint/eventindex: failed to get block results: %w", err)
+		}
+		if results == nil {
+			continue
+		}
+
+		visitEvents(results, func(index uint64, txIndex *uint32, typ string, key, value []byte) bool {
+			if query.Cursor != nil && int64(height) == query.Cursor.Height && index <= query.Cursor.Index {
+				return true
+			}
+			if typ != query.Type || string(key) != query.Key {
+				return true
+			}
+			if !matchesConditions(value, query.Conditions) {
+				return true
+			}
+
+			pos := consensusAPI.EventPosition{Height: int64(height), Index: index}
+			if len(page.Events) == limit {
+				// There are more results, the last event is the cursor.
+				last := page.Events[len(page.Events)-1].EventPosition
+				page.NextCursor = &last
+				return false
+			}
+			page.Events = append(page.Events, &consensusAPI.EventSearchResult{
+				EventPosition: pos,
+				TxIndex:       txIndex,
+				Value:         append(cbor.RawMessage{}, value...),
+			})
+			return true
+		})
+		if page.NextCursor != nil {
+			break
+		}
+	}
+	if err = it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func matchesConditions(value []byte, conditions []consensusAPI.EventCondition) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+
+	fields := decodeEventFields(value)
+	for _, cond := range conditions {
+		fieldValue, ok := fields[cond.Field]
+		if !ok || !bytes.Equal(fieldValue, cond.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Close closes the event index.
+func (idx *eventIndex) Close() error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	if idx.closed {
+		return nil
+	}
+	idx.closed = true
+	return idx.db.Close()
+}
+
+func newEventIndex(db tmdb.DB, getResults blockResultsFunc) *eventIndex {
+	return &eventIndex{
+		db:         db,
+		getResults: getResults,
+	}
+}
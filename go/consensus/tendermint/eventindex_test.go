@@ -0,0 +1,130 @@
+package tendermint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmabcitypes "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/kv"
+	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmdb "github.com/tendermint/tm-db"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+type testTransferEvent struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount uint64 `json:"amount"`
+}
+
+func testTransfer(from, to string, amount uint64) tmabcitypes.Event {
+	return tmabcitypes.Event{
+		Type: "oasis-event-test",
+		Attributes: []kv.Pair{
+			{Key: []byte("transfer"), Value: cbor.Marshal(&testTransferEvent{From: from, To: to, Amount: amount})},
+		},
+	}
+}
+
+func TestEventIndex(t *testing.T) {
+	require := require.New(t)
+
+	blocks := map[int64]*tmrpctypes.ResultBlockResults{
+		1: {
+			Height:           1,
+			BeginBlockEvents: []tmabcitypes.Event{testTransfer("a", "b", 1)},
+		},
+		2: {
+			Height: 2,
+			TxsResults: []*tmabcitypes.ResponseDeliverTx{
+				{Events: []tmabcitypes.Event{testTransfer("b", "c", 2)}},
+				{Events: []tmabcitypes.Event{testTransfer("a", "c", 3), testTransfer("a", "b", 4)}},
+			},
+		},
+		3: {
+			Height:         3,
+			EndBlockEvents: []tmabcitypes.Event{testTransfer("c", "a", 5)},
+		},
+	}
+	idx := newEventIndex(tmdb.NewMemDB(), func(height int64) (*tmrpctypes.ResultBlockResults, error) {
+		return blocks[height], nil
+	})
+
+	_, err := idx.Search(&consensusAPI.EventSearchQuery{})
+	require.Error(err, "Search without type and key should fail")
+
+	query := &consensusAPI.EventSearchQuery{
+		Type: "oasis-event-test",
+		Key:  "transfer",
+	}
+	page, err := idx.Search(query)
+	require.NoError(err, "Search")
+	require.Empty(page.Events, "nothing should be returned before indexing")
+
+	for h := int64(1); h <= 3; h++ {
+		require.NoError(idx.Index(h), "Index")
+	}
+	require.Error(idx.Index(2), "indexing an already indexed height should fail")
+	lastHeight, err := idx.LastHeight()
+	require.NoError(err, "LastHeight")
+	require.EqualValues(3, lastHeight)
+
+	amounts := func(page *consensusAPI.EventSearchPage) (result []uint64) {
+		for _, ev := range page.Events {
+			var transfer testTransferEvent
+			require.NoError(cbor.Unmarshal(ev.Value, &transfer), "event value should decode")
+			result = append(result, transfer.Amount)
+		}
+		return
+	}
+
+	page, err = idx.Search(query)
+	require.NoError(err, "Search")
+	require.Equal([]uint64{1, 2, 3, 4, 5}, amounts(page))
+	require.Nil(page.NextCursor, "last page should not have a cursor")
+	require.Nil(page.Events[0].TxIndex, "BeginBlock event should not have a transaction index")
+	require.EqualValues(1, *page.Events[2].TxIndex)
+	require.Equal(consensusAPI.EventPosition{Height: 2, Index: 2}, page.Events[3].EventPosition)
+
+	// Conditions.
+	query.Conditions = []consensusAPI.EventCondition{
+		{Field: "from", Value: cbor.Marshal("a")},
+	}
+	page, err = idx.Search(query)
+	require.NoError(err, "Search")
+	require.Equal([]uint64{1, 3, 4}, amounts(page))
+
+	query.Conditions = append(query.Conditions, consensusAPI.EventCondition{Field: "to", Value: cbor.Marshal("b")})
+	page, err = idx.Search(query)
+	require.NoError(err, "Search")
+	require.Equal([]uint64{1, 4}, amounts(page))
+
+	// Height range.
+	query.FromHeight = 2
+	page, err = idx.Search(query)
+	require.NoError(err, "Search")
+	require.Equal([]uint64{4}, amounts(page))
+
+	// Pagination.
+	query = &consensusAPI.EventSearchQuery{
+		Type:  "oasis-event-test",
+		Key:   "transfer",
+		Limit: 2,
+	}
+	var all []uint64
+	for {
+		page, err = idx.Search(query)
+		require.NoError(err, "Search")
+		require.True(len(page.Events) <= 2, "page should respect the limit")
+		all = append(all, amounts(page)...)
+		if page.NextCursor == nil {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	require.Equal([]uint64{1, 2, 3, 4, 5}, all)
+
+	require.NoError(idx.Close(), "Close")
+}
@@ -48,12 +48,90 @@ func (tb *tendermintBackend) GetStatuses(ctx context.Context, height int64) ([]*
 	return q.Statuses(ctx)
 }
 
-func (tb *tendermintBackend) WatchStatuses() (<-chan *api.Status, *pubsub.Subscription) {
-	sub := tb.notifier.Subscribe()
-	ch := make(chan *api.Status)
-	sub.Unwrap(ch)
+func (tb *tendermintBackend) WatchStatuses(ctx context.Context, fromHeight int64) (<-chan *api.AnnotatedStatus, pubsub.ClosableSubscription, error) {
+	if fromHeight < 0 {
+		return nil, nil, fmt.Errorf("keymanager/tendermint: invalid replay height: %d", fromHeight)
+	}
+
+	// Determine the initial statuses to send, and the height up to which
+	// they cover status changes, from within the broker so that no live
+	// updates can be broadcast in between.
+	var (
+		numInitial  int
+		lastHeight  int64
+		initErr     error
+		onSubscribe = func(ch channels.Channel) {
+			var initial []*api.AnnotatedStatus
+			lastHeight = tb.service.GetLastCommittedHeight()
+			if initial, initErr = tb.getInitialStatuses(ctx, lastHeight, fromHeight); initErr != nil {
+				return
+			}
+
+			numInitial = len(initial)
+			wr := ch.In()
+			for _, v := range initial {
+				wr <- v
+			}
+		}
+	)
+	sub := tb.notifier.SubscribeEx(-1, onSubscribe)
+	if initErr != nil {
+		sub.Close()
+		tb.logger.Error("status notifier: unable to get initial statuses",
+			"err", initErr,
+			"height", lastHeight,
+			"from_height", fromHeight,
+		)
+		return nil, nil, initErr
+	}
+
+	ch := make(chan *api.AnnotatedStatus)
+	go func() {
+		defer close(ch)
+
+		var n int
+		for v := range sub.Untyped() {
+			st := v.(*api.AnnotatedStatus)
+
+			// Skip live updates already covered by the initial statuses.
+			n++
+			if n > numInitial && st.Height <= lastHeight {
+				continue
+			}
+
+			select {
+			case ch <- st:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (tb *tendermintBackend) getInitialStatuses(ctx context.Context, height, fromHeight int64) ([]*api.AnnotatedStatus, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromHeight != consensus.HeightLatest {
+		return q.StatusUpdates(ctx, fromHeight)
+	}
 
-	return ch, sub
+	statuses, err := q.Statuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	annotated := make([]*api.AnnotatedStatus, 0, len(statuses))
+	for _, status := range statuses {
+		annotated = append(annotated, &api.AnnotatedStatus{
+			Height: height,
+			Status: status,
+		})
+	}
+	return annotated, nil
 }
 
 func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
@@ -101,14 +179,14 @@ func (tb *tendermintBackend) onEventDataNewBlock(ctx context.Context, ev tmtypes
 	events := append([]abcitypes.Event{}, ev.ResultBeginBlock.GetEvents()...)
 	events = append(events, ev.ResultEndBlock.GetEvents()...)
 
-	tb.onABCIEvents(ctx, events)
+	tb.onABCIEvents(ctx, events, ev.Block.Header.Height)
 }
 
 func (tb *tendermintBackend) onEventDataTx(ctx context.Context, tx tmtypes.EventDataTx) {
-	tb.onABCIEvents(ctx, tx.Result.Events)
+	tb.onABCIEvents(ctx, tx.Result.Events, tx.Height)
 }
 
-func (tb *tendermintBackend) onABCIEvents(ctx context.Context, tmEvents []abcitypes.Event) {
+func (tb *tendermintBackend) onABCIEvents(ctx context.Context, tmEvents []abcitypes.Event, height int64) {
 	for _, tmEv := range tmEvents {
 		if tmEv.GetType() != app.EventType {
 			continue
@@ -125,7 +203,10 @@ func (tb *tendermintBackend) onABCIEvents(ctx context.Context, tmEvents []abcity
 				}
 
 				for _, status := range statuses {
-					tb.notifier.Broadcast(status)
+					tb.notifier.Broadcast(&api.AnnotatedStatus{
+						Height: height,
+						Status: status,
+					})
 				}
 			}
 		}
@@ -145,20 +226,7 @@ func New(ctx context.Context, service service.TendermintService) (api.Backend, e
 		service: service,
 		querier: a.QueryFactory().(*app.QueryFactory),
 	}
-	tb.notifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
-		statuses, err := tb.GetStatuses(ctx, consensus.HeightLatest)
-		if err != nil {
-			tb.logger.Error("status notifier: unable to get a list of statuses",
-				"err", err,
-			)
-			return
-		}
-
-		wr := ch.In()
-		for _, v := range statuses {
-			wr <- v
-		}
-	})
+	tb.notifier = pubsub.NewBroker(false)
 	go tb.worker(ctx)
 
 	return tb, nil
@@ -59,6 +59,15 @@ func (tb *tendermintBackend) GetEntities(ctx context.Context, height int64) ([]*
 	return q.Entities(ctx)
 }
 
+func (tb *tendermintBackend) GetEntitiesPage(ctx context.Context, query *api.PageQuery) (*api.EntitiesPage, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.EntitiesPage(ctx, query.Cursor, query.PageLimit())
+}
+
 func (tb *tendermintBackend) WatchEntities(ctx context.Context) (<-chan *api.EntityEvent, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.EntityEvent)
 	sub := tb.entityNotifier.Subscribe()
@@ -85,6 +94,15 @@ func (tb *tendermintBackend) GetNodeStatus(ctx context.Context, query *api.IDQue
 	return q.NodeStatus(ctx, query.ID)
 }
 
+func (tb *tendermintBackend) GetNodeByConsensusAddress(ctx context.Context, query *api.ConsensusAddressQuery) (*node.Node, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.NodeByConsensusAddress(ctx, query.Address)
+}
+
 func (tb *tendermintBackend) GetNodes(ctx context.Context, height int64) ([]*node.Node, error) {
 	q, err := tb.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -94,6 +112,24 @@ func (tb *tendermintBackend) GetNodes(ctx context.Context, height int64) ([]*nod
 	return q.Nodes(ctx)
 }
 
+func (tb *tendermintBackend) GetNodesPage(ctx context.Context, query *api.PageQuery) (*api.NodesPage, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.NodesPage(ctx, query.Cursor, query.PageLimit())
+}
+
+func (tb *tendermintBackend) GetNodesForEntity(ctx context.Context, query *api.EntityNodesQuery) (*api.NodesPage, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.EntityNodesPage(ctx, query.EntityID, query.Cursor, query.PageLimit())
+}
+
 func (tb *tendermintBackend) WatchNodes(ctx context.Context) (<-chan *api.NodeEvent, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.NodeEvent)
 	sub := tb.nodeNotifier.Subscribe()
@@ -361,6 +397,13 @@ func (tb *tendermintBackend) onABCIEvents(ctx context.Context, tmEvents []abcity
 				} else {
 					events = append(events, api.Event{NodeEvent: nev})
 				}
+			} else if bytes.Equal(key, app.KeyRuntimeOwnership) && !doBroadcast {
+				// Runtime ownership event.
+				var ev api.RuntimeOwnershipEvent
+				if err := cbor.Unmarshal(val, &ev); err != nil {
+					return nil, fmt.Errorf("registry: corrupt RuntimeOwnership event: %w", err)
+				}
+				events = append(events, api.Event{RuntimeOwnershipEvent: &ev})
 			} else if bytes.Equal(key, app.KeyNodeUnfrozen) && !doBroadcast {
 				// Node unfrozen event.
 				var nid signature.PublicKey
@@ -380,22 +423,16 @@ func (tb *tendermintBackend) onABCIEvents(ctx context.Context, tmEvents []abcity
 }
 
 func (tb *tendermintBackend) getNodeList(ctx context.Context, height int64) (*api.NodeList, error) {
-	// Generate the nodelist.
 	q, err := tb.querier.QueryAt(ctx, height)
 	if err != nil {
 		return nil, err
 	}
 
-	nodes, err := q.Nodes(ctx)
+	nl, err := q.NodeList(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("registry: failed to query nodes: %w", err)
+		return nil, fmt.Errorf("registry: failed to query node list: %w", err)
 	}
-
-	api.SortNodeList(nodes)
-
-	return &api.NodeList{
-		Nodes: nodes,
-	}, nil
+	return nl, nil
 }
 
 // New constructs a new tendermint backed registry Backend instance.
@@ -407,13 +444,24 @@ func New(ctx context.Context, service service.TendermintService) (api.Backend, e
 	}
 
 	tb := &tendermintBackend{
-		logger:           logging.GetLogger("registry/tendermint"),
-		service:          service,
-		querier:          a.QueryFactory().(*app.QueryFactory),
-		entityNotifier:   pubsub.NewBroker(false),
-		nodeNotifier:     pubsub.NewBroker(false),
-		nodeListNotifier: pubsub.NewBroker(true),
+		logger:         logging.GetLogger("registry/tendermint"),
+		service:        service,
+		querier:        a.QueryFactory().(*app.QueryFactory),
+		entityNotifier: pubsub.NewBroker(false),
+		nodeNotifier:   pubsub.NewBroker(false),
 	}
+	tb.nodeListNotifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
+		// Send the node list for the current epoch upon subscription.
+		nl, err := tb.getNodeList(ctx, consensus.HeightLatest)
+		if err != nil {
+			tb.logger.Error("node list notifier: unable to get the node list",
+				"err", err,
+			)
+			return
+		}
+
+		ch.In() <- nl
+	})
 	tb.runtimeNotifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
 		wr := ch.In()
 		runtimes, err := tb.GetRuntimes(ctx, consensus.HeightLatest)
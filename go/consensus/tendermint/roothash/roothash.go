@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"time"
 
 	"github.com/eapache/channels"
 	"github.com/tendermint/tendermint/abci/types"
@@ -53,6 +54,7 @@ type tendermintBackend struct {
 	allBlockNotifier *pubsub.Broker
 	runtimeNotifiers map[common.Namespace]*runtimeBrokers
 	genesisBlocks    map[common.Namespace]*block.Block
+	blockHistory     map[common.Namespace]api.BlockHistory
 
 	closeOnce      sync.Once
 	closedCh       chan struct{}
@@ -197,6 +199,42 @@ func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (
 }
 
 func (tb *tendermintBackend) GetEvents(ctx context.Context, height int64) ([]api.Event, error) {
+	return tb.getEvents(ctx, height, nil)
+}
+
+func (tb *tendermintBackend) GetBlocksByTimeRange(
+	ctx context.Context,
+	id common.Namespace,
+	from time.Time,
+	to time.Time,
+) ([]*block.Block, error) {
+	if to.Before(from) {
+		return nil, api.ErrInvalidArgument
+	}
+
+	bh := tb.getBlockHistory(id)
+	if bh == nil {
+		return nil, api.ErrRuntimeNotTracked
+	}
+	return bh.GetBlocksByTimeRange(ctx, from, to)
+}
+
+func (tb *tendermintBackend) GetRoundEvents(ctx context.Context, id common.Namespace, round uint64) ([]api.Event, error) {
+	bh := tb.getBlockHistory(id)
+	if bh == nil {
+		return nil, api.ErrRuntimeNotTracked
+	}
+
+	annBlk, err := bh.GetAnnotatedBlock(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	return tb.getEvents(ctx, annBlk.Height, &id)
+}
+
+// getEvents returns the events at the specified block height, optionally
+// only including events for the given runtime.
+func (tb *tendermintBackend) getEvents(ctx context.Context, height int64, id *common.Namespace) ([]api.Event, error) {
 	// Get block results at given height.
 	var results *tmrpctypes.ResultBlockResults
 	results, err := tb.service.GetBlockResults(height)
@@ -223,8 +261,15 @@ func (tb *tendermintBackend) GetEvents(ctx context.Context, height int64) ([]api
 		for _, pair := range tmEv.GetAttributes() {
 			if bytes.Equal(pair.GetKey(), app.KeyMergeDiscrepancyDetected) {
 				// Merge discrepancy event.
+				var mddValue app.ValueMergeDiscrepancyDetected
+				if err := cbor.Unmarshal(pair.GetValue(), &mddValue); err != nil {
+					return nil, fmt.Errorf("roothash: corrupt MergeDiscrepancyDetected event: %w", err)
+				}
+				if id != nil && !mddValue.ID.Equal(id) {
+					continue
+				}
 				evt := api.Event{
-					MergeDiscrepancyDetected: &api.MergeDiscrepancyDetectedEvent{},
+					MergeDiscrepancyDetected: &mddValue.Event,
 				}
 				events = append(events, evt)
 			} else if bytes.Equal(pair.GetKey(), app.KeyExecutionDiscrepancyDetected) {
@@ -233,10 +278,26 @@ func (tb *tendermintBackend) GetEvents(ctx context.Context, height int64) ([]api
 				if err := cbor.Unmarshal(pair.GetValue(), &eddValue); err != nil {
 					return nil, fmt.Errorf("roothash: corrupt ExecutionDiscrepancyDetected event: %w", err)
 				}
+				if id != nil && !eddValue.ID.Equal(id) {
+					continue
+				}
 				evt := api.Event{
 					ExecutionDiscrepancyDetected: &eddValue.Event,
 				}
 				events = append(events, evt)
+			} else if bytes.Equal(pair.GetKey(), app.KeyRuntimeSuspension) {
+				// Runtime suspension event.
+				var rsValue app.ValueRuntimeSuspension
+				if err := cbor.Unmarshal(pair.GetValue(), &rsValue); err != nil {
+					return nil, fmt.Errorf("roothash: corrupt RuntimeSuspension event: %w", err)
+				}
+				if id != nil && !rsValue.ID.Equal(id) {
+					continue
+				}
+				evt := api.Event{
+					RuntimeSuspension: &rsValue.Event,
+				}
+				events = append(events, evt)
 			}
 		}
 	}
@@ -266,6 +327,13 @@ func (tb *tendermintBackend) getRuntimeNotifiers(id common.Namespace) *runtimeBr
 	return notifiers
 }
 
+func (tb *tendermintBackend) getBlockHistory(id common.Namespace) api.BlockHistory {
+	tb.RLock()
+	defer tb.RUnlock()
+
+	return tb.blockHistory[id]
+}
+
 func (tb *tendermintBackend) reindexBlocks(bh api.BlockHistory) error {
 	var err error
 	var lastHeight int64
@@ -368,9 +436,6 @@ func (tb *tendermintBackend) worker(ctx context.Context) { // nolint: gocyclo
 
 	close(tb.initCh)
 
-	// Initialize block history keepers.
-	blockHistory := make(map[common.Namespace]api.BlockHistory)
-
 	// Process transactions and emit notifications for our subscribers.
 	for {
 		var event interface{}
@@ -383,7 +448,6 @@ func (tb *tendermintBackend) worker(ctx context.Context) { // nolint: gocyclo
 			return
 		case bh := <-tb.blockHistoryCh:
 			// We need to start watching a new block history.
-			blockHistory[bh.RuntimeID()] = bh
 			// Perform reindex if required.
 			if err = tb.reindexBlocks(bh); err != nil {
 				tb.logger.Error("failed to reindex blocks",
@@ -393,6 +457,10 @@ func (tb *tendermintBackend) worker(ctx context.Context) { // nolint: gocyclo
 
 				panic("roothash: failed to reindex blocks")
 			}
+
+			tb.Lock()
+			tb.blockHistory[bh.RuntimeID()] = bh
+			tb.Unlock()
 			continue
 		case <-ctx.Done():
 			return
@@ -446,7 +514,7 @@ func (tb *tendermintBackend) worker(ctx context.Context) { // nolint: gocyclo
 					}
 
 					// Commit the block to history if needed.
-					if bh, ok := blockHistory[value.ID]; ok {
+					if bh := tb.getBlockHistory(value.ID); bh != nil {
 						crash.Here(crashPointBlockBeforeIndex)
 
 						err = bh.Commit(annBlk)
@@ -490,6 +558,17 @@ func (tb *tendermintBackend) worker(ctx context.Context) { // nolint: gocyclo
 
 					notifiers := tb.getRuntimeNotifiers(value.ID)
 					notifiers.eventNotifier.Broadcast(&api.Event{ExecutionDiscrepancyDetected: &value.Event})
+				} else if bytes.Equal(pair.GetKey(), app.KeyRuntimeSuspension) {
+					var value app.ValueRuntimeSuspension
+					if err := cbor.Unmarshal(pair.GetValue(), &value); err != nil {
+						tb.logger.Error("worker: failed to get runtime suspension from tag",
+							"err", err,
+						)
+						continue
+					}
+
+					notifiers := tb.getRuntimeNotifiers(value.ID)
+					notifiers.eventNotifier.Broadcast(&api.Event{RuntimeSuspension: &value.Event})
 				}
 			}
 		}
@@ -516,6 +595,7 @@ func New(
 		allBlockNotifier: pubsub.NewBroker(false),
 		runtimeNotifiers: make(map[common.Namespace]*runtimeBrokers),
 		genesisBlocks:    make(map[common.Namespace]*block.Block),
+		blockHistory:     make(map[common.Namespace]api.BlockHistory),
 		closedCh:         make(chan struct{}),
 		initCh:           make(chan struct{}),
 		blockHistoryCh:   make(chan api.BlockHistory, runtimeRegistry.MaxRuntimeCount),
@@ -9,6 +9,7 @@ import (
 	"github.com/eapache/channels"
 	tmtypes "github.com/tendermint/tendermint/types"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
@@ -26,7 +27,9 @@ type tendermintBackend struct {
 	service service.TendermintService
 	querier *app.QueryFactory
 
-	notifier *pubsub.Broker
+	notifier                *pubsub.Broker
+	upcomingNotifier        *pubsub.Broker
+	electionFailureNotifier *pubsub.Broker
 }
 
 func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
@@ -60,14 +63,44 @@ func (tb *tendermintBackend) GetCommittees(ctx context.Context, request *api.Get
 		return nil, err
 	}
 
+	return filterRuntimeCommittees(committees, request.RuntimeID), nil
+}
+
+func (tb *tendermintBackend) GetUpcomingCommittees(ctx context.Context, request *api.GetCommitteesRequest) ([]*api.Committee, error) {
+	q, err := tb.querier.QueryAt(ctx, request.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	committees, err := q.AllUpcomingCommittees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterRuntimeCommittees(committees, request.RuntimeID), nil
+}
+
+func filterRuntimeCommittees(committees []*api.Committee, runtimeID common.Namespace) []*api.Committee {
 	var runtimeCommittees []*api.Committee
 	for _, c := range committees {
-		if c.RuntimeID.Equal(&request.RuntimeID) {
+		if c.RuntimeID.Equal(&runtimeID) {
 			runtimeCommittees = append(runtimeCommittees, c)
 		}
 	}
+	return runtimeCommittees
+}
 
-	return runtimeCommittees, nil
+func (tb *tendermintBackend) VerifyElection(ctx context.Context, request *api.VerifyElectionRequest) (*api.ElectionVerification, error) {
+	// The block timestamp is needed to verify node TEE attestations.
+	blk, err := tb.service.GetTendermintBlock(ctx, request.Height)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: failed to get block: %w", err)
+	}
+	if blk == nil {
+		return nil, consensus.ErrNoCommittedBlocks
+	}
+
+	return tb.querier.VerifyElection(ctx, blk.Header.Height, blk.Header.Time, request.Kind, request.RuntimeID)
 }
 
 func (tb *tendermintBackend) WatchCommittees(ctx context.Context) (<-chan *api.Committee, pubsub.ClosableSubscription, error) {
@@ -78,6 +111,22 @@ func (tb *tendermintBackend) WatchCommittees(ctx context.Context) (<-chan *api.C
 	return typedCh, sub, nil
 }
 
+func (tb *tendermintBackend) WatchUpcomingCommittees(ctx context.Context) (<-chan *api.Committee, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan *api.Committee)
+	sub := tb.upcomingNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
+func (tb *tendermintBackend) WatchElectionFailures(ctx context.Context) (<-chan *api.ElectionFailedEvent, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan *api.ElectionFailedEvent)
+	sub := tb.electionFailureNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
 func (tb *tendermintBackend) getCurrentCommittees() ([]*api.Committee, error) {
 	q, err := tb.querier.QueryAt(context.TODO(), consensus.HeightLatest)
 	if err != nil {
@@ -126,6 +175,25 @@ func (tb *tendermintBackend) worker(ctx context.Context) {
 	}
 }
 
+// electedCommittees returns the (upcoming) committees of the kinds in the
+// given elected event value, as stored at the given height.
+func (tb *tendermintBackend) electedCommittees(ctx context.Context, height int64, rawKinds []byte, upcoming bool) ([]*api.Committee, error) {
+	var kinds []api.CommitteeKind
+	if err := cbor.Unmarshal(rawKinds, &kinds); err != nil {
+		return nil, fmt.Errorf("malformed elected committee types list: %w", err)
+	}
+
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if upcoming {
+		return q.KindsUpcomingCommittees(ctx, kinds)
+	}
+	return q.KindsCommittees(ctx, kinds)
+}
+
 // Called from worker.
 func (tb *tendermintBackend) onEventDataNewBlock(ctx context.Context, ev tmtypes.EventDataNewBlock) {
 	events := ev.ResultBeginBlock.GetEvents()
@@ -137,33 +205,39 @@ func (tb *tendermintBackend) onEventDataNewBlock(ctx context.Context, ev tmtypes
 
 		for _, pair := range tmEv.GetAttributes() {
 			if bytes.Equal(pair.GetKey(), app.KeyElected) {
-				var kinds []api.CommitteeKind
-				if err := cbor.Unmarshal(pair.GetValue(), &kinds); err != nil {
-					tb.logger.Error("worker: malformed elected committee types list",
+				committees, err := tb.electedCommittees(ctx, ev.Block.Header.Height, pair.GetValue(), false)
+				if err != nil {
+					tb.logger.Error("worker: couldn't query elected committees",
 						"err", err,
 					)
 					continue
 				}
 
-				q, err := tb.querier.QueryAt(ctx, ev.Block.Header.Height)
+				for _, c := range committees {
+					tb.notifier.Broadcast(c)
+				}
+			} else if bytes.Equal(pair.GetKey(), app.KeyUpcomingElected) {
+				committees, err := tb.electedCommittees(ctx, ev.Block.Header.Height, pair.GetValue(), true)
 				if err != nil {
-					tb.logger.Error("worker: couldn't query elected committees",
+					tb.logger.Error("worker: couldn't query elected upcoming committees",
 						"err", err,
 					)
 					continue
 				}
 
-				committees, err := q.KindsCommittees(ctx, kinds)
-				if err != nil {
-					tb.logger.Error("worker: couldn't query elected committees",
+				for _, c := range committees {
+					tb.upcomingNotifier.Broadcast(c)
+				}
+			} else if bytes.Equal(pair.GetKey(), app.KeyElectionFailed) {
+				var failure api.ElectionFailedEvent
+				if err := cbor.Unmarshal(pair.GetValue(), &failure); err != nil {
+					tb.logger.Error("worker: malformed election failed event",
 						"err", err,
 					)
 					continue
 				}
 
-				for _, c := range committees {
-					tb.notifier.Broadcast(c)
-				}
+				tb.electionFailureNotifier.Broadcast(&failure)
 			}
 		}
 	}
@@ -181,6 +255,9 @@ func New(ctx context.Context, service service.TendermintService) (api.Backend, e
 		logger:  logging.GetLogger("scheduler/tendermint"),
 		service: service,
 		querier: a.QueryFactory().(*app.QueryFactory),
+
+		upcomingNotifier:        pubsub.NewBroker(false),
+		electionFailureNotifier: pubsub.NewBroker(false),
 	}
 	tb.notifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
 		currentCommittees, err := tb.getCurrentCommittees()
@@ -35,6 +35,11 @@ type TendermintService interface {
 	// GetHeight returns the Tendermint block height.
 	GetHeight(ctx context.Context) (int64, error)
 
+	// GetLastCommittedHeight returns the height of the last consensus state
+	// committed to the local database. Unlike GetHeight, this does not wait
+	// for the service to be started.
+	GetLastCommittedHeight() int64
+
 	// GetBlock returns the Tendermint block at the specified height.
 	GetTendermintBlock(ctx context.Context, height int64) (*tmtypes.Block, error)
 
@@ -16,6 +16,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
 	app "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/service"
@@ -34,6 +35,7 @@ type tendermintBackend struct {
 	approvalNotifier *pubsub.Broker
 	burnNotifier     *pubsub.Broker
 	escrowNotifier   *pubsub.Broker
+	eventNotifier    *pubsub.Broker
 
 	closedCh chan struct{}
 }
@@ -118,6 +120,51 @@ func (tb *tendermintBackend) DebondingDelegations(ctx context.Context, query *ap
 	return q.DebondingDelegations(ctx, query.Owner)
 }
 
+func (tb *tendermintBackend) DelegationInfos(ctx context.Context, query *api.OwnerQuery) (map[signature.PublicKey]*api.DelegationInfo, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.DelegationInfosFor(ctx, query.Owner)
+}
+
+func (tb *tendermintBackend) EscrowSummary(ctx context.Context, query *api.OwnerQuery) (*api.EscrowSummary, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.EscrowSummary(ctx, query.Owner)
+}
+
+func (tb *tendermintBackend) StakeClaims(ctx context.Context, query *api.OwnerQuery) (*api.StakeClaimSummary, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.StakeClaims(ctx, query.Owner)
+}
+
+func (tb *tendermintBackend) PendingSlashes(ctx context.Context, height int64) ([]*api.PendingSlash, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.PendingSlashes(ctx)
+}
+
+func (tb *tendermintBackend) SimulateEpochRewards(ctx context.Context, height int64) (*api.RewardSimulation, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.SimulateEpochRewards(ctx)
+}
+
 func (tb *tendermintBackend) WatchTransfers(ctx context.Context) (<-chan *api.TransferEvent, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.TransferEvent)
 	sub := tb.transferNotifier.Subscribe()
@@ -142,6 +189,14 @@ func (tb *tendermintBackend) WatchEscrows(ctx context.Context) (<-chan *api.Escr
 	return typedCh, sub, nil
 }
 
+func (tb *tendermintBackend) WatchEvents(ctx context.Context) (<-chan *api.Event, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan *api.Event)
+	sub := tb.eventNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
 func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
 	q, err := tb.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -151,6 +206,25 @@ func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (
 	return q.Genesis(ctx)
 }
 
+func (tb *tendermintBackend) ExportLedger(ctx context.Context, query *api.LedgerExportQuery, fn func(*api.LedgerChunk) error) error {
+	// Resolve the height so that all chunks refer to the same state.
+	height := query.Height
+	if height == consensus.HeightLatest {
+		blk, err := tb.service.GetBlock(ctx, height)
+		if err != nil {
+			return err
+		}
+		height = blk.Height
+	}
+
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	return q.ExportLedger(ctx, query.ChunkSize, fn)
+}
+
 func convertTmBlockEvents(beginBlockEvents []abcitypes.Event, endBlockEvents []abcitypes.Event) []abciEventWithHash {
 	var tmEvents []abciEventWithHash
 	for _, bbe := range beginBlockEvents {
@@ -286,118 +360,116 @@ func (tb *tendermintBackend) onABCIEvents(context context.Context, tmEvents []ab
 			continue
 		}
 
-		eh := tmEv.TxHash
-
 		for _, pair := range tmEv.GetAttributes() {
-			key := pair.GetKey()
-			val := pair.GetValue()
-			if bytes.Equal(key, app.KeyTakeEscrow) {
-				// Take escrow event.
-				var e api.TakeEscrowEvent
-				if err := cbor.Unmarshal(val, &e); err != nil {
-					tb.logger.Error("worker: failed to get take escrow event from tag",
-						"err", err,
-					)
-					if doBroadcast {
-						continue
-					} else {
-						return nil, fmt.Errorf("staking: corrupt TakeEscrow event: %w", err)
-					}
-				}
-
-				ee := &api.EscrowEvent{Take: &e}
-
+			ev, err := decodeEvent(pair.GetKey(), pair.GetValue())
+			if err != nil {
+				tb.logger.Error("worker: failed to decode event",
+					"err", err,
+					"height", height,
+				)
 				if doBroadcast {
-					tb.escrowNotifier.Broadcast(ee)
-				} else {
-					events = append(events, api.Event{TxHash: eh, EscrowEvent: ee})
-				}
-			} else if bytes.Equal(key, app.KeyTransfer) {
-				// Transfer event.
-				var e api.TransferEvent
-				if err := cbor.Unmarshal(val, &e); err != nil {
-					tb.logger.Error("worker: failed to get transfer event from tag",
-						"err", err,
-					)
-					if doBroadcast {
-						continue
-					} else {
-						return nil, fmt.Errorf("staking: corrupt Transfer event: %w", err)
-					}
-				}
-
-				if doBroadcast {
-					tb.transferNotifier.Broadcast(&e)
-				} else {
-					events = append(events, api.Event{TxHash: eh, TransferEvent: &e})
-				}
-			} else if bytes.Equal(key, app.KeyReclaimEscrow) {
-				// Reclaim escrow event.
-				var e api.ReclaimEscrowEvent
-				if err := cbor.Unmarshal(val, &e); err != nil {
-					tb.logger.Error("worker: failed to get reclaim escrow event from tag",
-						"err", err,
-					)
-					if doBroadcast {
-						continue
-					} else {
-						return nil, fmt.Errorf("staking: corrupt ReclaimEscrow event: %w", err)
-					}
-				}
-
-				ee := &api.EscrowEvent{Reclaim: &e}
-
-				if doBroadcast {
-					tb.escrowNotifier.Broadcast(ee)
-				} else {
-					events = append(events, api.Event{TxHash: eh, EscrowEvent: ee})
-				}
-			} else if bytes.Equal(key, app.KeyAddEscrow) {
-				// Add escrow event.
-				var e api.AddEscrowEvent
-				if err := cbor.Unmarshal(val, &e); err != nil {
-					tb.logger.Error("worker: failed to get escrow event from tag",
-						"err", err,
-					)
-					if doBroadcast {
-						continue
-					} else {
-						return nil, fmt.Errorf("staking: corrupt AddEscrow event: %w", err)
-					}
+					continue
 				}
+				return nil, err
+			}
+			if ev == nil {
+				// Not a staking event that we know about.
+				continue
+			}
+			ev.Height = height
+			ev.TxHash = tmEv.TxHash
 
-				ee := &api.EscrowEvent{Add: &e}
-
-				if doBroadcast {
-					tb.escrowNotifier.Broadcast(ee)
-				} else {
-					events = append(events, api.Event{TxHash: eh, EscrowEvent: ee})
-				}
-			} else if bytes.Equal(key, app.KeyBurn) {
-				// Burn event.
-				var e api.BurnEvent
-				if err := cbor.Unmarshal(val, &e); err != nil {
-					tb.logger.Error("worker: failed to get burn event from tag",
-						"err", err,
-					)
-					if doBroadcast {
-						continue
-					} else {
-						return nil, fmt.Errorf("staking: corrupt Burn event: %w", err)
-					}
-				}
+			if !doBroadcast {
+				events = append(events, *ev)
+				continue
+			}
 
-				if doBroadcast {
-					tb.burnNotifier.Broadcast(&e)
-				} else {
-					events = append(events, api.Event{TxHash: eh, BurnEvent: &e})
-				}
+			switch {
+			case ev.TransferEvent != nil:
+				tb.transferNotifier.Broadcast(ev.TransferEvent)
+			case ev.BurnEvent != nil:
+				tb.burnNotifier.Broadcast(ev.BurnEvent)
+			case ev.EscrowEvent != nil:
+				tb.escrowNotifier.Broadcast(ev.EscrowEvent)
 			}
+			tb.eventNotifier.Broadcast(ev)
 		}
 	}
 	return events, nil
 }
 
+// decodeEvent decodes a staking ABCI event attribute. It returns nil if the
+// attribute key does not correspond to a known staking event.
+func decodeEvent(key, val []byte) (*api.Event, error) {
+	switch {
+	case bytes.Equal(key, app.KeyTakeEscrow):
+		// Take escrow event.
+		var e api.TakeEscrowEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt TakeEscrow event: %w", err)
+		}
+		return &api.Event{EscrowEvent: &api.EscrowEvent{Take: &e}}, nil
+	case bytes.Equal(key, app.KeyTransfer):
+		// Transfer event.
+		var e api.TransferEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt Transfer event: %w", err)
+		}
+		return &api.Event{TransferEvent: &e}, nil
+	case bytes.Equal(key, app.KeyReclaimEscrow):
+		// Reclaim escrow event.
+		var e api.ReclaimEscrowEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt ReclaimEscrow event: %w", err)
+		}
+		return &api.Event{EscrowEvent: &api.EscrowEvent{Reclaim: &e}}, nil
+	case bytes.Equal(key, app.KeyAddEscrow):
+		// Add escrow event.
+		var e api.AddEscrowEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt AddEscrow event: %w", err)
+		}
+		return &api.Event{EscrowEvent: &api.EscrowEvent{Add: &e}}, nil
+	case bytes.Equal(key, app.KeyBurn):
+		// Burn event.
+		var e api.BurnEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt Burn event: %w", err)
+		}
+		return &api.Event{BurnEvent: &e}, nil
+	case bytes.Equal(key, app.KeyMint):
+		// Mint event.
+		var e api.MintEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt Mint event: %w", err)
+		}
+		return &api.Event{MintEvent: &e}, nil
+	case bytes.Equal(key, app.KeyCommissionDestinations):
+		// Commission destinations event.
+		var e api.CommissionDestinationsEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt CommissionDestinations event: %w", err)
+		}
+		return &api.Event{CommissionDestinationsEvent: &e}, nil
+	case bytes.Equal(key, app.KeyAllowanceChange):
+		// Allowance change event.
+		var e api.AllowanceChangeEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt AllowanceChange event: %w", err)
+		}
+		return &api.Event{AllowanceChangeEvent: &e}, nil
+	case bytes.Equal(key, app.KeyPendingSlash):
+		// Pending slash event.
+		var e api.PendingSlashEvent
+		if err := cbor.Unmarshal(val, &e); err != nil {
+			return nil, fmt.Errorf("staking: corrupt PendingSlash event: %w", err)
+		}
+		return &api.Event{PendingSlashEvent: &e}, nil
+	default:
+		return nil, nil
+	}
+}
+
 // New constructs a new tendermint backed staking Backend instance.
 func New(ctx context.Context, service service.TendermintService) (api.Backend, error) {
 	// Initialize and register the tendermint service component.
@@ -419,6 +491,7 @@ func New(ctx context.Context, service service.TendermintService) (api.Backend, e
 		approvalNotifier: pubsub.NewBroker(false),
 		burnNotifier:     pubsub.NewBroker(false),
 		escrowNotifier:   pubsub.NewBroker(false),
+		eventNotifier:    pubsub.NewBroker(false),
 		closedCh:         make(chan struct{}),
 	}
 
@@ -0,0 +1,181 @@
+package tendermint
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/spf13/viper"
+	tmconfig "github.com/tendermint/tendermint/config"
+	tmlite "github.com/tendermint/tendermint/lite2"
+	tmnode "github.com/tendermint/tendermint/node"
+	tmstore "github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"google.golang.org/grpc"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/identity"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/statesync"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+)
+
+func (t *tendermintService) newStateSyncConfig(chainID string) (*statesync.Config, []*grpc.ClientConn, error) {
+	trustHash, err := hex.DecodeString(viper.GetString(CfgConsensusStateSyncTrustHash))
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed trusted header hash: %w", err)
+	}
+
+	var checkpointSigner signature.PublicKey
+	if err = checkpointSigner.UnmarshalText([]byte(viper.GetString(CfgConsensusStateSyncCheckpointSigner))); err != nil {
+		return nil, nil, fmt.Errorf("malformed checkpoint manifest signer: %w", err)
+	}
+	checkpoints, err := checkpoint.NewHTTPChunkProvider(viper.GetString(CfgConsensusStateSyncCheckpointURL), checkpointSigner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		conns        []*grpc.ClientConn
+		lightClients []consensusAPI.LightClientBackend
+	)
+	for _, rawAddr := range viper.GetStringSlice(CfgConsensusStateSyncConsensusNode) {
+		var addr node.TLSAddress
+		if err = addr.UnmarshalText([]byte(rawAddr)); err != nil {
+			return nil, conns, fmt.Errorf("malformed consensus node address '%s': %w", rawAddr, err)
+		}
+
+		creds, cerr := cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
+			CommonName: identity.CommonName,
+			ServerPubKeys: map[signature.PublicKey]bool{
+				addr.PubKey: true,
+			},
+		})
+		if cerr != nil {
+			return nil, conns, cerr
+		}
+		conn, cerr := cmnGrpc.Dial(addr.String(), grpc.WithTransportCredentials(creds)) // nolint: staticcheck
+		if cerr != nil {
+			return nil, conns, fmt.Errorf("failed to dial consensus node '%s': %w", rawAddr, cerr)
+		}
+		conns = append(conns, conn)
+		lightClients = append(lightClients, consensusAPI.NewConsensusLightClient(conn))
+	}
+
+	return &statesync.Config{
+		ChainID: chainID,
+		TrustOptions: tmlite.TrustOptions{
+			Period: viper.GetDuration(CfgConsensusStateSyncTrustPeriod),
+			Height: viper.GetInt64(CfgConsensusStateSyncTrustHeight),
+			Hash:   trustHash,
+		},
+		LightClients: lightClients,
+		Checkpoints:  checkpoints,
+	}, conns, nil
+}
+
+// maybeStateSync bootstraps the node from a verified ABCI state checkpoint
+// in case state sync is enabled and there is no local state yet.
+func (t *tendermintService) maybeStateSync(
+	appConfig *abci.ApplicationConfig,
+	tenderConfig *tmconfig.Config,
+	dbProvider tmnode.DBProvider,
+	tmGenDoc *tmtypes.GenesisDoc,
+) error {
+	if !viper.GetBool(CfgConsensusStateSyncEnabled) {
+		return nil
+	}
+
+	ldb, ndb, stateRoot, err := abci.InitStateStorage(t.ctx, appConfig)
+	if err != nil {
+		return fmt.Errorf("state sync: failed to initialize ABCI state storage: %w", err)
+	}
+	defer ldb.Cleanup()
+
+	stateDB, err := dbProvider(&tmnode.DBContext{ID: "state", Config: tenderConfig})
+	if err != nil {
+		return fmt.Errorf("state sync: failed to open state database: %w", err)
+	}
+	defer stateDB.Close()
+	blockStoreDB, err := dbProvider(&tmnode.DBContext{ID: "blockstore", Config: tenderConfig})
+	if err != nil {
+		return fmt.Errorf("state sync: failed to open block store database: %w", err)
+	}
+	defer blockStoreDB.Close()
+
+	if stateRoot.Version > 0 || tmstore.LoadBlockStoreStateJSON(blockStoreDB).Height > 0 {
+		t.Logger.Info("local state exists, skipping state sync",
+			"height", stateRoot.Version,
+		)
+		return nil
+	}
+
+	cfg, conns, err := t.newStateSyncConfig(tmGenDoc.ChainID)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	if err != nil {
+		return fmt.Errorf("state sync: %w", err)
+	}
+
+	t.Logger.Info("starting state sync",
+		"trust_height", cfg.TrustOptions.Height,
+	)
+
+	if _, err = statesync.Sync(t.ctx, cfg, tmGenDoc, ndb, stateDB, blockStoreDB); err != nil {
+		return fmt.Errorf("state sync: %w", err)
+	}
+	return nil
+}
+
+// checkpointHTTPServer serves ABCI state checkpoints over plain HTTP so that
+// other nodes can use them for state sync.
+type checkpointHTTPServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+func (t *tendermintService) startCheckpointHTTPServer() error {
+	address := viper.GetString(CfgABCICheckpointerHTTPAddress)
+	checkpoints := t.mux.Checkpoints()
+	if address == "" || checkpoints == nil {
+		return nil
+	}
+
+	t.Logger.Info("ABCI state checkpoint HTTP endpoint is enabled",
+		"address", address,
+	)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("tendermint: failed to start checkpoint HTTP endpoint: %w", err)
+	}
+	t.checkpointSrv = &checkpointHTTPServer{
+		listener: listener,
+		server:   &http.Server{Handler: checkpoint.NewHTTPHandler(checkpoints, t.nodeSigner)},
+	}
+
+	go func() {
+		if err := t.checkpointSrv.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			t.Logger.Error("checkpoint HTTP server terminated uncleanly",
+				"err", err,
+			)
+		}
+	}()
+
+	return nil
+}
+
+func (t *tendermintService) stopCheckpointHTTPServer() {
+	if t.checkpointSrv == nil {
+		return
+	}
+	_ = t.checkpointSrv.server.Shutdown(context.Background())
+}
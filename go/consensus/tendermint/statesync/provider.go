@@ -0,0 +1,97 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tmamino "github.com/tendermint/go-amino"
+	tmliteprovider "github.com/tendermint/tendermint/lite2/provider"
+	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+// We must use Tendermint's amino codec as some Tendermint's types are not easily unmarshallable.
+var aminoCodec = tmamino.NewCodec()
+
+func init() {
+	tmrpctypes.RegisterAmino(aminoCodec)
+}
+
+var _ tmliteprovider.Provider = (*lightProvider)(nil)
+
+// lightProvider is a Tendermint light client provider backed by a consensus
+// light client backend (e.g., the public gRPC endpoint of another node).
+type lightProvider struct {
+	ctx     context.Context
+	chainID string
+	client  consensusAPI.LightClientBackend
+}
+
+// Implements tmliteprovider.Provider.
+func (lp *lightProvider) ChainID() string {
+	return lp.chainID
+}
+
+// Implements tmliteprovider.Provider.
+func (lp *lightProvider) SignedHeader(height int64) (*tmtypes.SignedHeader, error) {
+	shdr, err := lp.client.GetSignedHeader(lp.ctx, height)
+	switch {
+	case err == nil:
+	case errors.Is(err, consensusAPI.ErrVersionNotFound):
+		return nil, tmliteprovider.ErrSignedHeaderNotFound
+	default:
+		return nil, fmt.Errorf("statesync: failed to fetch signed header: %w", err)
+	}
+
+	var sh tmtypes.SignedHeader
+	if err = aminoCodec.UnmarshalBinaryBare(shdr.Meta, &sh); err != nil {
+		return nil, fmt.Errorf("statesync: malformed signed header: %w", err)
+	}
+	return &sh, nil
+}
+
+// Implements tmliteprovider.Provider.
+func (lp *lightProvider) ValidatorSet(height int64) (*tmtypes.ValidatorSet, error) {
+	vs, err := lp.client.GetValidatorSet(lp.ctx, height)
+	switch {
+	case err == nil:
+	case errors.Is(err, consensusAPI.ErrVersionNotFound):
+		return nil, tmliteprovider.ErrValidatorSetNotFound
+	default:
+		return nil, fmt.Errorf("statesync: failed to fetch validator set: %w", err)
+	}
+
+	var vals tmtypes.ValidatorSet
+	if err = aminoCodec.UnmarshalBinaryBare(vs.Meta, &vals); err != nil {
+		return nil, fmt.Errorf("statesync: malformed validator set: %w", err)
+	}
+	return &vals, nil
+}
+
+// consensusParams fetches the consensus parameters for the given height.
+//
+// The parameters are not verified, so the caller must check them against
+// the consensus hash of a verified header.
+func (lp *lightProvider) consensusParams(height int64) (*tmtypes.ConsensusParams, error) {
+	p, err := lp.client.GetParameters(lp.ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("statesync: failed to fetch consensus parameters: %w", err)
+	}
+
+	var params tmtypes.ConsensusParams
+	if err = aminoCodec.UnmarshalBinaryBare(p.Meta, &params); err != nil {
+		return nil, fmt.Errorf("statesync: malformed consensus parameters: %w", err)
+	}
+	return &params, nil
+}
+
+func newLightProvider(ctx context.Context, chainID string, client consensusAPI.LightClientBackend) *lightProvider {
+	return &lightProvider{
+		ctx:     ctx,
+		chainID: chainID,
+		client:  client,
+	}
+}
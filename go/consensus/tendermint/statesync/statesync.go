@@ -0,0 +1,296 @@
+// Package statesync implements bootstrapping a new consensus node from an
+// ABCI state checkpoint verified using a light client, instead of replaying
+// all of the blocks since genesis.
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	tmlite "github.com/tendermint/tendermint/lite2"
+	tmliteprovider "github.com/tendermint/tendermint/lite2/provider"
+	tmlitedb "github.com/tendermint/tendermint/lite2/store/db"
+	tmstate "github.com/tendermint/tendermint/state"
+	tmstore "github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+	tmdb "github.com/tendermint/tm-db"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+)
+
+// checkpointVersion is the checkpoint format version.
+const checkpointVersion = 1
+
+// Config is the state sync configuration.
+type Config struct {
+	// ChainID is the Tendermint chain identifier.
+	ChainID string
+
+	// TrustOptions are the light client trust options. The trusted header
+	// must be obtained from a trusted source.
+	TrustOptions tmlite.TrustOptions
+
+	// LightClients are the consensus light client backends used to fetch
+	// headers and validator sets. The first backend is used as the primary
+	// source while all others are used as witnesses.
+	LightClients []consensusAPI.LightClientBackend
+
+	// Checkpoints is the provider of ABCI state checkpoints.
+	Checkpoints checkpoint.ChunkProvider
+}
+
+// verifiedCheckpoint is an ABCI state checkpoint together with the
+// Tendermint state at the checkpoint height, as verified by the light client.
+type verifiedCheckpoint struct {
+	checkpoint *checkpoint.Metadata
+	state      tmstate.State
+	commit     *tmtypes.Commit
+}
+
+type stateSync struct {
+	logger *logging.Logger
+
+	cfg     *Config
+	genDoc  *tmtypes.GenesisDoc
+	primary *lightProvider
+	client  *tmlite.Client
+}
+
+func (s *stateSync) verifyCheckpoint(cp *checkpoint.Metadata) (*verifiedCheckpoint, error) {
+	// The ABCI state at height H is the result of executing block H, which
+	// is committed to in the header at height H+1. The validator set for the
+	// height after that is needed to bootstrap the Tendermint state.
+	height := int64(cp.Root.Version)
+	now := time.Now()
+
+	var (
+		headers [3]*tmtypes.SignedHeader
+		vals    [3]*tmtypes.ValidatorSet
+	)
+	for i := range headers {
+		h, err := s.client.VerifyHeaderAtHeight(height+int64(i), now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify header at height %d: %w", height+int64(i), err)
+		}
+		headers[i] = h
+
+		vs, _, err := s.client.TrustedValidatorSet(height + int64(i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator set at height %d: %w", height+int64(i), err)
+		}
+		vals[i] = vs
+	}
+
+	if !bytes.Equal(headers[1].AppHash, cp.Root.Hash[:]) {
+		return nil, fmt.Errorf("checkpoint state root does not match verified application hash")
+	}
+
+	params, err := s.primary.consensusParams(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(params.Hash(), headers[1].ConsensusHash) {
+		return nil, fmt.Errorf("consensus parameters do not match verified consensus hash")
+	}
+
+	state, err := tmstate.MakeGenesisState(s.genDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genesis state: %w", err)
+	}
+	state.Version.Consensus = headers[1].Version
+	state.LastBlockHeight = height
+	state.LastBlockID = headers[1].LastBlockID
+	state.LastBlockTime = headers[0].Time
+	state.LastValidators = vals[0]
+	state.Validators = vals[1]
+	state.NextValidators = vals[2]
+	state.LastHeightValidatorsChanged = height + 2
+	state.ConsensusParams = *params
+	state.LastHeightConsensusParamsChanged = height + 1
+	state.LastResultsHash = headers[1].LastResultsHash
+	state.AppHash = headers[1].AppHash
+
+	return &verifiedCheckpoint{
+		checkpoint: cp,
+		state:      state,
+		commit:     headers[0].Commit,
+	}, nil
+}
+
+func (s *stateSync) findCheckpoint(ctx context.Context) (*verifiedCheckpoint, error) {
+	cps, err := s.cfg.Checkpoints.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
+		Version: checkpointVersion,
+		// The ABCI state always uses the empty namespace.
+		Namespace: common.Namespace{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("statesync: failed to get checkpoints: %w", err)
+	}
+
+	// Prefer the most recent checkpoint that can be verified.
+	sort.Slice(cps, func(i, j int) bool { return cps[i].Root.Version > cps[j].Root.Version })
+	for _, cp := range cps {
+		vcp, err := s.verifyCheckpoint(cp)
+		if err != nil {
+			s.logger.Warn("failed to verify checkpoint",
+				"root", cp.Root,
+				"err", err,
+			)
+			continue
+		}
+		return vcp, nil
+	}
+	return nil, fmt.Errorf("statesync: no verifiable checkpoints available")
+}
+
+func (s *stateSync) restoreCheckpoint(ctx context.Context, ndb nodedb.NodeDB, cp *checkpoint.Metadata) error {
+	rs, err := checkpoint.NewRestorer(ndb)
+	if err != nil {
+		return err
+	}
+	if err = rs.StartRestore(ctx, cp); err != nil {
+		return fmt.Errorf("statesync: failed to start restore: %w", err)
+	}
+
+	for idx := range cp.Chunks {
+		var chunk *checkpoint.ChunkMetadata
+		if chunk, err = cp.GetChunkMetadata(uint64(idx)); err != nil {
+			return err
+		}
+
+		s.logger.Debug("restoring checkpoint chunk",
+			"root", cp.Root,
+			"index", idx,
+			"num_chunks", len(cp.Chunks),
+		)
+
+		var buf bytes.Buffer
+		if err = s.cfg.Checkpoints.GetCheckpointChunk(ctx, chunk, &buf); err != nil {
+			return fmt.Errorf("statesync: failed to fetch chunk %d: %w", idx, err)
+		}
+		if _, err = rs.RestoreChunk(ctx, uint64(idx), &buf); err != nil {
+			return fmt.Errorf("statesync: failed to restore chunk %d: %w", idx, err)
+		}
+	}
+
+	if err = ndb.Finalize(ctx, cp.Root.Version, []hash.Hash{cp.Root.Hash}); err != nil {
+		return fmt.Errorf("statesync: failed to finalize restored state: %w", err)
+	}
+	return nil
+}
+
+// bootstrapStores populates the Tendermint state and block stores so that
+// Tendermint continues from the checkpoint height instead of from genesis.
+func bootstrapStores(vcp *verifiedCheckpoint, stateDB, blockStoreDB tmdb.DB) error {
+	height := vcp.state.LastBlockHeight
+
+	// Tendermint only saves the next validator set when saving the state,
+	// so the validator sets at the checkpoint height and the height after
+	// it need to be saved explicitly.
+	for i, vs := range []*tmtypes.ValidatorSet{vcp.state.LastValidators, vcp.state.Validators} {
+		h := height + int64(i)
+		valInfo := &tmstate.ValidatorsInfo{
+			ValidatorSet:      vs,
+			LastHeightChanged: h,
+		}
+		if err := stateDB.Set([]byte(fmt.Sprintf("validatorsKey:%d", h)), valInfo.Bytes()); err != nil {
+			return fmt.Errorf("statesync: failed to save validator set: %w", err)
+		}
+	}
+	tmstate.SaveState(stateDB, vcp.state)
+
+	// Consensus needs the commit for the last block to reconstruct the last
+	// commit when starting at the next height.
+	if err := blockStoreDB.Set([]byte(fmt.Sprintf("SC:%d", height)), aminoCodec.MustMarshalBinaryBare(vcp.commit)); err != nil {
+		return fmt.Errorf("statesync: failed to save seen commit: %w", err)
+	}
+	tmstore.BlockStoreStateJSON{Base: height, Height: height}.Save(blockStoreDB)
+
+	return nil
+}
+
+// Sync bootstraps the ABCI state in the given node database and the
+// Tendermint state and block stores from the most recent ABCI state
+// checkpoint that can be verified using the light client.
+//
+// All of the databases must be empty. Returns the height of the restored
+// state.
+func Sync(
+	ctx context.Context,
+	cfg *Config,
+	genDoc *tmtypes.GenesisDoc,
+	ndb nodedb.NodeDB,
+	stateDB tmdb.DB,
+	blockStoreDB tmdb.DB,
+) (int64, error) {
+	if len(cfg.LightClients) == 0 {
+		return 0, errors.New("statesync: no light client backends configured")
+	}
+	if cfg.Checkpoints == nil {
+		return 0, errors.New("statesync: no checkpoint provider configured")
+	}
+
+	s := &stateSync{
+		logger: logging.GetLogger("consensus/tendermint/statesync"),
+		cfg:    cfg,
+		genDoc: genDoc,
+	}
+
+	var providers []tmliteprovider.Provider
+	for _, lc := range cfg.LightClients {
+		lp := newLightProvider(ctx, cfg.ChainID, lc)
+		if s.primary == nil {
+			s.primary = lp
+		}
+		providers = append(providers, lp)
+	}
+	// The light client requires at least one witness, so in case only a
+	// single backend is configured, use it as a witness as well.
+	witnesses := providers[1:]
+	if len(witnesses) == 0 {
+		witnesses = providers
+	}
+
+	var err error
+	s.client, err = tmlite.NewClient(
+		cfg.ChainID,
+		cfg.TrustOptions,
+		providers[0],
+		witnesses,
+		tmlitedb.New(tmdb.NewMemDB(), ""),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("statesync: failed to create light client: %w", err)
+	}
+
+	vcp, err := s.findCheckpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("restoring ABCI state from checkpoint",
+		"root", vcp.checkpoint.Root,
+		"num_chunks", len(vcp.checkpoint.Chunks),
+	)
+	if err = s.restoreCheckpoint(ctx, ndb, vcp.checkpoint); err != nil {
+		return 0, err
+	}
+	if err = bootstrapStores(vcp, stateDB, blockStoreDB); err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("state sync completed",
+		"height", vcp.state.LastBlockHeight,
+	)
+
+	return vcp.state.LastBlockHeight, nil
+}
@@ -77,14 +77,25 @@ const (
 	configDir = "config"
 
 	// CfgCoreListenAddress configures the tendermint core network listen address.
-	CfgCoreListenAddress   = "tendermint.core.listen_address"
-	cfgCoreExternalAddress = "tendermint.core.external_address"
+	CfgCoreListenAddress = "tendermint.core.listen_address"
+	// CfgCoreExternalAddress configures the tendermint core address advertised to other nodes.
+	CfgCoreExternalAddress = "tendermint.core.external_address"
 
 	// CfgABCIPruneStrategy configures the ABCI state pruning strategy.
 	CfgABCIPruneStrategy = "tendermint.abci.prune.strategy"
 	// CfgABCIPruneNumKept configures the amount of kept heights if pruning is enabled.
 	CfgABCIPruneNumKept = "tendermint.abci.prune.num_kept"
 
+	// CfgABCICheckpointerInterval configures the ABCI state checkpoint interval (in blocks).
+	CfgABCICheckpointerInterval = "tendermint.abci.checkpointer.interval"
+	// CfgABCICheckpointerNumKept configures the number of kept ABCI state checkpoints.
+	CfgABCICheckpointerNumKept = "tendermint.abci.checkpointer.num_kept"
+	// CfgABCICheckpointerChunkSize configures the ABCI state checkpoint chunk size (in bytes).
+	CfgABCICheckpointerChunkSize = "tendermint.abci.checkpointer.chunk_size"
+	// CfgABCICheckpointerHTTPAddress configures the address of the HTTP endpoint serving ABCI
+	// state checkpoints for state sync.
+	CfgABCICheckpointerHTTPAddress = "tendermint.abci.checkpointer.http_address"
+
 	// CfgSentryUpstreamAddress defines nodes for which we act as a sentry for.
 	CfgSentryUpstreamAddress = "tendermint.sentry.upstream_address"
 
@@ -126,9 +137,28 @@ const (
 	CfgConsensusSubmissionGasPrice = "consensus.tendermint.submission.gas_price"
 	// CfgConsensusSubmissionMaxFee configures the maximum fee that can be set.
 	CfgConsensusSubmissionMaxFee = "consensus.tendermint.submission.max_fee"
+	// CfgConsensusEventIndexEnabled enables the event index used for event searches.
+	CfgConsensusEventIndexEnabled = "consensus.tendermint.event_index.enabled"
 	// CfgConsensusDebugDisableCheckTx disables CheckTx.
 	CfgConsensusDebugDisableCheckTx = "consensus.tendermint.debug.disable_check_tx"
 
+	// CfgConsensusStateSyncEnabled enables bootstrapping a new node using state sync.
+	CfgConsensusStateSyncEnabled = "consensus.state_sync.enabled"
+	// CfgConsensusStateSyncConsensusNode configures the consensus node(s) used by the state sync
+	// light client, of the form pubkey@ip:port (where pubkey is the node's TLS public key).
+	CfgConsensusStateSyncConsensusNode = "consensus.state_sync.consensus_node"
+	// CfgConsensusStateSyncTrustPeriod configures the state sync light client trust period.
+	CfgConsensusStateSyncTrustPeriod = "consensus.state_sync.trust_period"
+	// CfgConsensusStateSyncTrustHeight configures the height of the trusted header.
+	CfgConsensusStateSyncTrustHeight = "consensus.state_sync.trust_height"
+	// CfgConsensusStateSyncTrustHash configures the hash of the trusted header.
+	CfgConsensusStateSyncTrustHash = "consensus.state_sync.trust_hash"
+	// CfgConsensusStateSyncCheckpointURL configures the base URL of the ABCI state checkpoints.
+	CfgConsensusStateSyncCheckpointURL = "consensus.state_sync.checkpoint_url"
+	// CfgConsensusStateSyncCheckpointSigner configures the trusted ABCI state checkpoint
+	// manifest signer (the identity of the node serving the checkpoints).
+	CfgConsensusStateSyncCheckpointSigner = "consensus.state_sync.checkpoint_signer"
+
 	// StateDir is the name of the directory located inside the node's data
 	// directory which contains the tendermint state.
 	StateDir = "tendermint"
@@ -205,9 +235,13 @@ type tendermintService struct {
 	client        *tmcli.Local
 	blockNotifier *pubsub.Broker
 	failMonitor   *failMonitor
+	txIndex       *recentTxIndex
+	eventIndex    *eventIndex
 
 	stateDb tmdb.DB
 
+	checkpointSrv *checkpointHTTPServer
+
 	beacon          beaconAPI.Backend
 	epochtime       epochtimeAPI.Backend
 	keymanager      keymanagerAPI.Backend
@@ -262,8 +296,14 @@ func (t *tendermintService) Start() error {
 		if err := t.node.Start(); err != nil {
 			return fmt.Errorf("tendermint: failed to start service: %w", err)
 		}
+		if err := t.startCheckpointHTTPServer(); err != nil {
+			return err
+		}
 		go t.syncWorker()
 		go t.worker()
+		if t.eventIndex != nil {
+			go t.eventIndexWorker()
+		}
 		if viper.GetString(cmmetrics.CfgMetricsMode) != cmmetrics.MetricsModeNone {
 			go t.metrics()
 		}
@@ -302,9 +342,16 @@ func (t *tendermintService) Stop() {
 		t.Logger.Error("Error on stopping node", err)
 	}
 
+	t.stopCheckpointHTTPServer()
 	t.svcMgr.Stop()
 	t.mux.Stop()
 	t.node.Wait()
+
+	if t.eventIndex != nil {
+		if err := t.eventIndex.Close(); err != nil {
+			t.Logger.Error("Error on closing event index", err)
+		}
+	}
 }
 
 func (t *tendermintService) Started() <-chan struct{} {
@@ -316,7 +363,7 @@ func (t *tendermintService) Synced() <-chan struct{} {
 }
 
 func (t *tendermintService) GetAddresses() ([]node.ConsensusAddress, error) {
-	addrURI := viper.GetString(cfgCoreExternalAddress)
+	addrURI := viper.GetString(CfgCoreExternalAddress)
 	if addrURI == "" {
 		addrURI = viper.GetString(CfgCoreListenAddress)
 	}
@@ -464,6 +511,22 @@ func (t *tendermintService) RegisterHaltHook(hook func(context.Context, int64, e
 }
 
 func (t *tendermintService) SubmitTx(ctx context.Context, tx *transaction.SignedTransaction) error {
+	// Reject transactions that are certain to fail before broadcasting them.
+	if !t.mux.CheckTxDisabled() {
+		result, err := t.ValidateTx(ctx, tx)
+		switch err {
+		case nil:
+			if err = result.Err(); err != nil {
+				return err
+			}
+		default:
+			// CheckTx remains authoritative, so proceed with broadcasting.
+			t.Logger.Debug("failed to pre-validate transaction",
+				"err", err,
+			)
+		}
+	}
+
 	// Subscribe to the transaction being included in a block.
 	data := cbor.Marshal(tx)
 	query := tmtypes.EventQueryTxFor(data)
@@ -515,6 +578,39 @@ func (t *tendermintService) SubmitTx(ctx context.Context, tx *transaction.Signed
 	}
 }
 
+func (t *tendermintService) ValidateTx(ctx context.Context, tx *transaction.SignedTransaction) (*consensusAPI.TxValidationResult, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	signer := tx.Signature.PublicKey
+	nonce, err := t.GetSignerNonce(ctx, &consensusAPI.GetSignerNonceRequest{
+		ID:     signer,
+		Height: consensusAPI.HeightLatest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to get signer nonce: %w", err)
+	}
+	account, err := t.Staking().AccountInfo(ctx, &stakingAPI.OwnerQuery{
+		Owner:  signer,
+		Height: consensusAPI.HeightLatest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to get signer account: %w", err)
+	}
+
+	state := &consensusAPI.TxValidationState{
+		Nonce:   nonce,
+		Balance: account.General.Balance,
+	}
+	if params := t.mux.ConsensusParameters(); params != nil {
+		state.MaxTxSize = params.MaxTxSize
+		state.MaxBlockGas = params.MaxBlockGas
+	}
+
+	return consensusAPI.ValidateTx(tx, state), nil
+}
+
 func (t *tendermintService) broadcastTxRaw(data []byte) error {
 	// We could use t.client.BroadcastTxSync but that is annoying as it
 	// doesn't give you the right fields when CheckTx fails.
@@ -532,6 +628,8 @@ func (t *tendermintService) broadcastTxRaw(data []byte) error {
 
 	rsp := <-ch
 	if result := rsp.GetCheckTx(); !result.IsOK() {
+		t.txIndex.Rejected(hash.NewFromBytes(data), t.GetLastCommittedHeight(), result)
+
 		err := errors.FromCode(result.GetCodespace(), result.GetCode())
 		if err == nil {
 			// Fallback to an ordinary error.
@@ -709,6 +807,66 @@ func (t *tendermintService) GetTransactions(ctx context.Context, height int64) (
 	return txs, nil
 }
 
+func (t *tendermintService) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	mempoolTxs := t.node.Mempool().ReapMaxTxs(-1)
+	txs := make([][]byte, 0, len(mempoolTxs))
+	for _, v := range mempoolTxs {
+		txs = append(txs, v[:])
+	}
+	return txs, nil
+}
+
+func (t *tendermintService) GetTransactionStatus(ctx context.Context, txHash hash.Hash) (*consensusAPI.TransactionStatus, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	// Included transactions take precedence as the mempool may not have
+	// been updated yet after the block was committed.
+	status := t.txIndex.Get(txHash)
+	if status != nil && status.Status == consensusAPI.TransactionStatusIncluded {
+		return status, nil
+	}
+
+	for _, v := range t.node.Mempool().ReapMaxTxs(-1) {
+		if h := hash.NewFromBytes(v); h.Equal(&txHash) {
+			return &consensusAPI.TransactionStatus{
+				Status: consensusAPI.TransactionStatusPending,
+			}, nil
+		}
+	}
+
+	if status != nil {
+		return status, nil
+	}
+	return &consensusAPI.TransactionStatus{
+		Status: consensusAPI.TransactionStatusUnknown,
+	}, nil
+}
+
+func (t *tendermintService) SearchEvents(ctx context.Context, query *consensusAPI.EventSearchQuery) (*consensusAPI.EventSearchPage, error) {
+	if t.eventIndex == nil {
+		return nil, consensusAPI.ErrEventIndexDisabled
+	}
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	return t.eventIndex.Search(query)
+}
+
+func (t *tendermintService) GetQuarantinedTransactions(ctx context.Context) ([]*consensusAPI.QuarantinedTransaction, error) {
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	return t.mux.QuarantinedTransactions(), nil
+}
+
 func (t *tendermintService) GetStatus(ctx context.Context) (*consensusAPI.Status, error) {
 	// Genesis block is hardcoded as block 1, since tendermint doesn't have
 	// a genesis block as such, but some external tooling expects there to be
@@ -887,6 +1045,10 @@ func (t *tendermintService) GetHeight(ctx context.Context) (int64, error) {
 	return blk.Header.Height, nil
 }
 
+func (t *tendermintService) GetLastCommittedHeight() int64 {
+	return t.mux.BlockHeight()
+}
+
 func (t *tendermintService) GetBlockResults(height int64) (*tmrpctypes.ResultBlockResults, error) {
 	if t.client == nil {
 		panic("client not available yet")
@@ -937,7 +1099,7 @@ func (t *tendermintService) initEpochtime() error {
 			return err
 		}
 	} else {
-		epochTime, err = epochtime.New(t.ctx, t, t.genesis.EpochTime.Parameters.Interval)
+		epochTime, err = epochtime.New(t.ctx, t)
 		if err != nil {
 			t.Logger.Error("initEpochtime: failed to initialize epochtime backend",
 				"err", err,
@@ -965,19 +1127,19 @@ func (t *tendermintService) lazyInit() error {
 	pruneCfg.NumKept = viper.GetUint64(CfgABCIPruneNumKept)
 
 	appConfig := &abci.ApplicationConfig{
-		DataDir:         filepath.Join(t.dataDir, StateDir),
-		StorageBackend:  db.GetBackendName(),
-		Pruning:         pruneCfg,
+		DataDir:        filepath.Join(t.dataDir, StateDir),
+		StorageBackend: db.GetBackendName(),
+		Pruning:        pruneCfg,
+		Checkpointer: abci.CheckpointerConfig{
+			Interval:  viper.GetUint64(CfgABCICheckpointerInterval),
+			NumKept:   viper.GetUint64(CfgABCICheckpointerNumKept),
+			ChunkSize: viper.GetUint64(CfgABCICheckpointerChunkSize),
+		},
 		HaltEpochHeight: t.genesis.HaltEpoch,
 		MinGasPrice:     viper.GetUint64(CfgConsensusMinGasPrice),
 		OwnTxSigner:     t.nodeSigner.Public(),
 		DisableCheckTx:  viper.GetBool(CfgConsensusDebugDisableCheckTx) && cmflags.DebugDontBlameOasis(),
 	}
-	t.mux, err = abci.NewApplicationServer(t.ctx, t.upgrader, appConfig)
-	if err != nil {
-		return err
-	}
-
 	// Tendermint needs the on-disk directories to be present when
 	// launched like this, so create the relevant sub-directories
 	// under the node DataDir.
@@ -1001,7 +1163,7 @@ func (t *tendermintService) lazyInit() error {
 	tenderConfig.Instrumentation.PrometheusListenAddr = ""
 	tenderConfig.TxIndex.Indexer = "null"
 	tenderConfig.P2P.ListenAddress = viper.GetString(CfgCoreListenAddress)
-	tenderConfig.P2P.ExternalAddress = viper.GetString(cfgCoreExternalAddress)
+	tenderConfig.P2P.ExternalAddress = viper.GetString(CfgCoreExternalAddress)
 	tenderConfig.P2P.PexReactor = !viper.GetBool(CfgP2PDisablePeerExchange)
 	tenderConfig.P2P.MaxNumInboundPeers = viper.GetInt(CfgP2PMaxNumInboundPeers)
 	tenderConfig.P2P.MaxNumOutboundPeers = viper.GetInt(CfgP2PMaxNumOutboundPeers)
@@ -1082,6 +1244,31 @@ func (t *tendermintService) lazyInit() error {
 		return err
 	}
 
+	if viper.GetBool(CfgConsensusEventIndexEnabled) {
+		eventDb, derr := dbProvider(&tmnode.DBContext{ID: "eventindex", Config: tenderConfig})
+		if derr != nil {
+			t.Logger.Error("failed to open event index database",
+				"err", derr,
+			)
+			return derr
+		}
+		t.eventIndex = newEventIndex(eventDb, t.GetBlockResults)
+	}
+
+	// Bootstrap the node using state sync if enabled. This must happen before the ABCI
+	// application is created as it opens the ABCI state storage.
+	if err = t.maybeStateSync(appConfig, tenderConfig, dbProvider, tmGenDoc); err != nil {
+		t.Logger.Error("failed to perform state sync",
+			"err", err,
+		)
+		return err
+	}
+
+	t.mux, err = abci.NewApplicationServer(t.ctx, t.upgrader, appConfig)
+	if err != nil {
+		return err
+	}
+
 	// HACK: Wrap the provider so we can extract the state database handle. This is required because
 	// Tendermint does not expose a way to access the state database and we need it to bypass some
 	// stupid things like pagination on the in-process "client".
@@ -1312,15 +1499,79 @@ func (t *tendermintService) worker() {
 	}
 	defer t.Unsubscribe("tendermint/worker", tmtypes.EventQueryNewBlock) // nolint:errcheck
 
+	txSub, err := t.Subscribe("tendermint/worker", tmtypes.EventQueryTx)
+	if err != nil {
+		t.Logger.Error("worker: failed to subscribe to transaction events",
+			"err", err,
+		)
+		return
+	}
+	defer t.Unsubscribe("tendermint/worker", tmtypes.EventQueryTx) // nolint:errcheck
+
 	for {
 		select {
 		case <-t.node.Quit():
 			return
 		case <-sub.Cancelled():
 			return
+		case <-txSub.Cancelled():
+			return
 		case v := <-sub.Out():
 			ev := v.Data().(tmtypes.EventDataNewBlock)
+			t.txIndex.Prune(ev.Block.Height)
 			t.blockNotifier.Broadcast(ev.Block)
+		case v := <-txSub.Out():
+			ev := v.Data().(tmtypes.EventDataTx)
+			t.txIndex.Included(hash.NewFromBytes(ev.Tx), ev.Height, &ev.Result)
+		}
+	}
+}
+
+// eventIndexWorker indexes the events of committed blocks.
+func (t *tendermintService) eventIndexWorker() {
+	blkCh, blkSub := t.WatchTendermintBlocks()
+	defer blkSub.Close()
+
+	indexUpTo := func(height int64) {
+		lastHeight, err := t.eventIndex.LastHeight()
+		if err != nil {
+			t.Logger.Error("event index: failed to get last indexed height",
+				"err", err,
+			)
+			return
+		}
+		// Heights below the block store base (e.g., after state sync) are
+		// not available and cannot be indexed.
+		if base := t.node.BlockStore().Base(); lastHeight < base-1 {
+			lastHeight = base - 1
+		}
+
+		for h := lastHeight + 1; h <= height; h++ {
+			select {
+			case <-t.node.Quit():
+				return
+			default:
+			}
+
+			if err = t.eventIndex.Index(h); err != nil {
+				t.Logger.Error("event index: failed to index block",
+					"err", err,
+					"height", h,
+				)
+				return
+			}
+		}
+	}
+
+	// Catch up with blocks committed while the index was not running.
+	indexUpTo(t.GetLastCommittedHeight())
+
+	for {
+		select {
+		case <-t.node.Quit():
+			return
+		case blk := <-blkCh:
+			indexUpTo(blk.Height)
 		}
 	}
 }
@@ -1395,6 +1646,7 @@ func New(ctx context.Context, dataDir string, identity *identity.Identity, upgra
 		svcMgr:                cmbackground.NewServiceManager(logging.GetLogger("tendermint/servicemanager")),
 		upgrader:              upgrader,
 		blockNotifier:         pubsub.NewBroker(false),
+		txIndex:               newRecentTxIndex(recentTxIndexBlocks),
 		consensusSigner:       identity.ConsensusSigner,
 		nodeSigner:            identity.NodeSigner,
 		genesis:               genesisDoc,
@@ -1542,9 +1794,13 @@ func newLogAdapter(suppressDebug bool) tmlog.Logger {
 
 func init() {
 	Flags.String(CfgCoreListenAddress, "tcp://0.0.0.0:26656", "tendermint core listen address")
-	Flags.String(cfgCoreExternalAddress, "", "tendermint address advertised to other nodes")
+	Flags.String(CfgCoreExternalAddress, "", "tendermint address advertised to other nodes")
 	Flags.String(CfgABCIPruneStrategy, abci.PruneDefault, "ABCI state pruning strategy")
 	Flags.Uint64(CfgABCIPruneNumKept, 3600, "ABCI state versions kept (when applicable)")
+	Flags.Uint64(CfgABCICheckpointerInterval, 0, "ABCI state checkpoint interval in blocks (0 disables checkpoints)")
+	Flags.Uint64(CfgABCICheckpointerNumKept, 2, "ABCI state checkpoints kept")
+	Flags.Uint64(CfgABCICheckpointerChunkSize, 8*1024*1024, "ABCI state checkpoint chunk size in bytes")
+	Flags.String(CfgABCICheckpointerHTTPAddress, "", "ABCI state checkpoint HTTP endpoint address (disabled if empty)")
 	Flags.StringSlice(CfgSentryUpstreamAddress, []string{}, "Tendermint nodes for which we act as sentry of the form ID@ip:port")
 	Flags.StringSlice(CfgP2PPersistentPeer, []string{}, "Tendermint persistent peer(s) of the form ID@ip:port")
 	Flags.StringSlice(CfgP2PUnconditionalPeerIDs, []string{}, "Tendermint unconditional peer IDs")
@@ -1562,8 +1818,16 @@ func init() {
 	Flags.Uint64(CfgConsensusMinGasPrice, 0, "minimum gas price")
 	Flags.Uint64(CfgConsensusSubmissionGasPrice, 0, "gas price used when submitting consensus transactions")
 	Flags.Uint64(CfgConsensusSubmissionMaxFee, 0, "maximum transaction fee when submitting consensus transactions")
+	Flags.Bool(CfgConsensusEventIndexEnabled, false, "maintain an event index for event searches")
 	Flags.Bool(CfgConsensusDebugDisableCheckTx, false, "do not perform CheckTx on incoming transactions (UNSAFE)")
 	Flags.Bool(CfgDebugUnsafeReplayRecoverCorruptedWAL, false, "Enable automatic recovery from corrupted WAL during replay (UNSAFE).")
+	Flags.Bool(CfgConsensusStateSyncEnabled, false, "bootstrap the node using state sync if there is no local state")
+	Flags.StringSlice(CfgConsensusStateSyncConsensusNode, []string{}, "consensus node(s) used for state sync of the form pubkey@ip:port")
+	Flags.Duration(CfgConsensusStateSyncTrustPeriod, 168*time.Hour, "state sync light client trust period")
+	Flags.Int64(CfgConsensusStateSyncTrustHeight, 0, "state sync trusted header height")
+	Flags.String(CfgConsensusStateSyncTrustHash, "", "state sync trusted header hash (hex-encoded)")
+	Flags.String(CfgConsensusStateSyncCheckpointURL, "", "state sync ABCI state checkpoint base URL")
+	Flags.String(CfgConsensusStateSyncCheckpointSigner, "", "state sync trusted ABCI state checkpoint manifest signer")
 
 	_ = Flags.MarkHidden(cfgLogDebug)
 	_ = Flags.MarkHidden(CfgDebugP2PAddrBookLenient)
@@ -0,0 +1,136 @@
+package tendermint
+
+import (
+	"sync"
+
+	tmabcitypes "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+// recentTxIndexBlocks is the number of recent blocks for which transaction
+// statuses are retained.
+const recentTxIndexBlocks = 100
+
+type recentTxIndexEntry struct {
+	height int64
+	status *consensusAPI.TransactionStatus
+}
+
+// recentTxIndex is a bounded in-memory index of the statuses of transactions
+// that were recently included in blocks or rejected on local submission.
+//
+// Tendermint's own transaction indexer is disabled, so this only covers
+// transactions observed since the node was started.
+type recentTxIndex struct {
+	sync.RWMutex
+
+	maxBlocks int64
+
+	txs     map[hash.Hash]*recentTxIndexEntry
+	heights map[int64][]hash.Hash
+}
+
+func (idx *recentTxIndex) addLocked(txHash hash.Hash, height int64, status *consensusAPI.TransactionStatus) {
+	if old, ok := idx.txs[txHash]; ok && old.height == height {
+		// Only overwrite the status, the hash is already tracked at this height.
+		old.status = status
+		return
+	}
+
+	idx.txs[txHash] = &recentTxIndexEntry{
+		height: height,
+		status: status,
+	}
+	idx.heights[height] = append(idx.heights[height], txHash)
+}
+
+// pruneLocked removes all entries older than maxBlocks before the given
+// height.
+func (idx *recentTxIndex) pruneLocked(height int64) {
+	for h, txHashes := range idx.heights {
+		if h > height-idx.maxBlocks {
+			continue
+		}
+		for _, txHash := range txHashes {
+			// An entry may have been superseded at a later height.
+			if entry := idx.txs[txHash]; entry != nil && entry.height == h {
+				delete(idx.txs, txHash)
+			}
+		}
+		delete(idx.heights, h)
+	}
+}
+
+// Included records the result of a transaction that was included in a block
+// at the given height.
+func (idx *recentTxIndex) Included(txHash hash.Hash, height int64, result *tmabcitypes.ResponseDeliverTx) {
+	status := &consensusAPI.TransactionStatus{
+		Status: consensusAPI.TransactionStatusIncluded,
+		Height: height,
+	}
+	if !result.IsOK() {
+		status.Error = &consensusAPI.TransactionError{
+			Module:  result.GetCodespace(),
+			Code:    result.GetCode(),
+			Message: result.GetLog(),
+		}
+	}
+
+	idx.Lock()
+	defer idx.Unlock()
+
+	idx.addLocked(txHash, height, status)
+}
+
+// Rejected records a transaction that was rejected on local submission when
+// the latest block height was the given height.
+func (idx *recentTxIndex) Rejected(txHash hash.Hash, height int64, result *tmabcitypes.ResponseCheckTx) {
+	status := &consensusAPI.TransactionStatus{
+		Status: consensusAPI.TransactionStatusRejected,
+		Error: &consensusAPI.TransactionError{
+			Module:  result.GetCodespace(),
+			Code:    result.GetCode(),
+			Message: result.GetLog(),
+		},
+	}
+
+	idx.Lock()
+	defer idx.Unlock()
+
+	if entry, ok := idx.txs[txHash]; ok && entry.status.Status == consensusAPI.TransactionStatusIncluded {
+		// Resubmitting an already included transaction must not mask the
+		// fact that it was included.
+		return
+	}
+	idx.addLocked(txHash, height, status)
+}
+
+// Prune discards entries that are too old given the latest block height.
+func (idx *recentTxIndex) Prune(height int64) {
+	idx.Lock()
+	defer idx.Unlock()
+
+	idx.pruneLocked(height)
+}
+
+// Get returns the recorded status of the given transaction, if any.
+func (idx *recentTxIndex) Get(txHash hash.Hash) *consensusAPI.TransactionStatus {
+	idx.RLock()
+	defer idx.RUnlock()
+
+	entry, ok := idx.txs[txHash]
+	if !ok {
+		return nil
+	}
+	return entry.status
+}
+
+func newRecentTxIndex(maxBlocks int64) *recentTxIndex {
+	return &recentTxIndex{
+		maxBlocks: maxBlocks,
+		txs:       make(map[hash.Hash]*recentTxIndexEntry),
+		heights:   make(map[int64][]hash.Hash),
+	}
+}
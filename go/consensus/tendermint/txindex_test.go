@@ -0,0 +1,61 @@
+package tendermint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmabcitypes "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+func TestRecentTxIndex(t *testing.T) {
+	require := require.New(t)
+
+	idx := newRecentTxIndex(2)
+
+	txOk := hash.NewFromBytes([]byte("ok"))
+	txFailed := hash.NewFromBytes([]byte("failed"))
+	txRejected := hash.NewFromBytes([]byte("rejected"))
+
+	require.Nil(idx.Get(txOk), "unknown transaction should not have a status")
+
+	idx.Included(txOk, 10, &tmabcitypes.ResponseDeliverTx{})
+	idx.Included(txFailed, 10, &tmabcitypes.ResponseDeliverTx{Codespace: "staking", Code: 2, Log: "failed"})
+	idx.Rejected(txRejected, 10, &tmabcitypes.ResponseCheckTx{Codespace: "consensus", Code: 3, Log: "rejected"})
+
+	status := idx.Get(txOk)
+	require.NotNil(status, "included transaction should have a status")
+	require.Equal(consensusAPI.TransactionStatusIncluded, status.Status)
+	require.EqualValues(10, status.Height)
+	require.Nil(status.Error, "successful transaction should not have an error")
+
+	status = idx.Get(txFailed)
+	require.NotNil(status, "failed transaction should have a status")
+	require.Equal(consensusAPI.TransactionStatusIncluded, status.Status)
+	require.Equal(&consensusAPI.TransactionError{Module: "staking", Code: 2, Message: "failed"}, status.Error)
+
+	status = idx.Get(txRejected)
+	require.NotNil(status, "rejected transaction should have a status")
+	require.Equal(consensusAPI.TransactionStatusRejected, status.Status)
+	require.EqualValues(0, status.Height, "rejected transaction should not have a height")
+	require.Equal(&consensusAPI.TransactionError{Module: "consensus", Code: 3, Message: "rejected"}, status.Error)
+
+	// Rejecting a resubmission of an included transaction should not mask
+	// its inclusion, but a later inclusion should supersede a rejection.
+	idx.Rejected(txOk, 11, &tmabcitypes.ResponseCheckTx{Code: 1})
+	require.Equal(consensusAPI.TransactionStatusIncluded, idx.Get(txOk).Status)
+	idx.Included(txRejected, 11, &tmabcitypes.ResponseDeliverTx{})
+	require.Equal(consensusAPI.TransactionStatusIncluded, idx.Get(txRejected).Status)
+
+	// Entries should be pruned once they are too old.
+	idx.Prune(11)
+	require.NotNil(idx.Get(txOk), "recent entries should not be pruned")
+	idx.Prune(12)
+	require.Nil(idx.Get(txOk), "old entries should be pruned")
+	require.Nil(idx.Get(txFailed), "old entries should be pruned")
+	require.NotNil(idx.Get(txRejected), "superseded entries should be retained")
+	idx.Prune(13)
+	require.Nil(idx.Get(txRejected), "old entries should be pruned")
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
@@ -47,6 +48,13 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	_, err = backend.GetTransactions(ctx, consensus.HeightLatest)
 	require.NoError(err, "GetTransactions")
 
+	_, err = backend.GetUnconfirmedTransactions(ctx)
+	require.NoError(err, "GetUnconfirmedTransactions")
+
+	txStatus, err := backend.GetTransactionStatus(ctx, hash.NewFromBytes([]byte("unknown transaction")))
+	require.NoError(err, "GetTransactionStatus")
+	require.Equal(consensus.TransactionStatusUnknown, txStatus.Status, "unknown transaction status should be unknown")
+
 	blockCh, blockSub, err := backend.WatchBlocks(ctx)
 	require.NoError(err, "WatchBlocks")
 	defer blockSub.Close()
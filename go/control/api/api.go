@@ -4,6 +4,7 @@ package api
 import (
 	"context"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/errors"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
@@ -27,8 +28,8 @@ type NodeController interface {
 	IsSynced(ctx context.Context) (bool, error)
 
 	// UpgradeBinary submits an upgrade descriptor to a running node.
-	// The node will wait for the appropriate epoch, then update its binaries
-	// and shut down.
+	// The node will wait for the appropriate epoch or height, then update its
+	// binaries and shut down.
 	UpgradeBinary(ctx context.Context, descriptor *upgrade.Descriptor) error
 
 	// CancelUpgrade cancels a pending upgrade, unless it is already in progress.
@@ -36,6 +37,13 @@ type NodeController interface {
 
 	// GetStatus returns the current status overview of the node.
 	GetStatus(ctx context.Context) (*Status, error)
+
+	// ReloadRuntimes reloads the runtime host configuration of the node.
+	//
+	// Hosted runtimes whose configuration has changed are re-provisioned and
+	// the old instances are stopped once the new instances have started and
+	// all in-flight calls have completed.
+	ReloadRuntimes(ctx context.Context) error
 }
 
 // Status is the current status overview.
@@ -45,14 +53,28 @@ type Status struct {
 
 	// Consensus is the status overview of the consensus layer.
 	Consensus consensus.Status `json:"consensus"`
+
+	// Upgrade is the currently pending upgrade, if any.
+	Upgrade *upgrade.PendingUpgrade `json:"upgrade,omitempty"`
 }
 
-// Shutdownable is an interface the node presents for shutting itself down.
-type Shutdownable interface {
+// ControlledNode is an interface the node presents for being controlled.
+type ControlledNode interface {
 	// RequestShutdown is the method called by the control server to trigger node shutdown.
 	RequestShutdown() (<-chan struct{}, error)
+
+	// ReloadRuntimes is the method called by the control server to trigger a reload of the
+	// runtime host configuration.
+	ReloadRuntimes() error
 }
 
+// ModuleName is the module name for the controller service.
+const ModuleName = "control"
+
+// ErrNoHostedRuntimes is the error returned when the node does not host any
+// runtimes.
+var ErrNoHostedRuntimes = errors.New(ModuleName, 1, "control: node does not host any runtimes")
+
 // DebugModuleName is the module name for the debug controller service.
 const DebugModuleName = "control/debug"
 
@@ -68,6 +90,29 @@ type DebugController interface {
 	//       return an error.
 	SetEpoch(ctx context.Context, epoch epochtime.EpochTime) error
 
+	// SetEpochSchedule configures a script of epoch transitions which are
+	// applied automatically once their consensus height or wall clock time
+	// is reached. Any previously configured schedule is replaced and an
+	// empty schedule stops it.
+	//
+	// NOTE: This only works with a mock epochtime backend and will otherwise
+	//       return an error.
+	SetEpochSchedule(ctx context.Context, schedule epochtime.EpochSchedule) error
+
 	// WaitNodesRegistered waits for the given number of nodes to register.
 	WaitNodesRegistered(ctx context.Context, count int) error
+
+	// TriggerElection advances the current epoch by one, triggering an
+	// immediate re-election of all committees.
+	//
+	// NOTE: This only works with a mock epochtime backend and will otherwise
+	//       return an error.
+	TriggerElection(ctx context.Context) error
+
+	// ForceRoundTimeout forces the current round of the given runtime to
+	// time out immediately.
+	//
+	// NOTE: This only works when forced round timeouts are enabled in the
+	//       roothash consensus parameters and will otherwise return an error.
+	ForceRoundTimeout(ctx context.Context, runtimeID common.Namespace) error
 }
@@ -5,6 +5,7 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
@@ -15,8 +16,14 @@ var (
 
 	// methodSetEpoch is the SetEpoch method.
 	methodSetEpoch = debugServiceName.NewMethod("SetEpoch", epochtime.EpochTime(0))
+	// methodSetEpochSchedule is the SetEpochSchedule method.
+	methodSetEpochSchedule = debugServiceName.NewMethod("SetEpochSchedule", epochtime.EpochSchedule{})
 	// methodWaitNodesRegistered is the WaitNodesRegistered method.
 	methodWaitNodesRegistered = debugServiceName.NewMethod("WaitNodesRegistered", int(0))
+	// methodTriggerElection is the TriggerElection method.
+	methodTriggerElection = debugServiceName.NewMethod("TriggerElection", nil)
+	// methodForceRoundTimeout is the ForceRoundTimeout method.
+	methodForceRoundTimeout = debugServiceName.NewMethod("ForceRoundTimeout", common.Namespace{})
 
 	// debugServiceDesc is the gRPC service descriptor.
 	debugServiceDesc = grpc.ServiceDesc{
@@ -27,10 +34,22 @@ var (
 				MethodName: methodSetEpoch.ShortName(),
 				Handler:    handlerSetEpoch,
 			},
+			{
+				MethodName: methodSetEpochSchedule.ShortName(),
+				Handler:    handlerSetEpochSchedule,
+			},
 			{
 				MethodName: methodWaitNodesRegistered.ShortName(),
 				Handler:    handlerWaitNodesRegistered,
 			},
+			{
+				MethodName: methodTriggerElection.ShortName(),
+				Handler:    handlerTriggerElection,
+			},
+			{
+				MethodName: methodForceRoundTimeout.ShortName(),
+				Handler:    handlerForceRoundTimeout,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -59,6 +78,29 @@ func handlerSetEpoch( // nolint: golint
 	return interceptor(ctx, epoch, info, handler)
 }
 
+func handlerSetEpochSchedule( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var schedule epochtime.EpochSchedule
+	if err := dec(&schedule); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetEpochSchedule(ctx, schedule)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetEpochSchedule.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(DebugController).SetEpochSchedule(ctx, req.(epochtime.EpochSchedule))
+	}
+	return interceptor(ctx, schedule, info, handler)
+}
+
 func handlerWaitNodesRegistered( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -82,6 +124,48 @@ func handlerWaitNodesRegistered( // nolint: golint
 	return interceptor(ctx, count, info, handler)
 }
 
+func handlerTriggerElection( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return nil, srv.(DebugController).TriggerElection(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodTriggerElection.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(DebugController).TriggerElection(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerForceRoundTimeout( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).ForceRoundTimeout(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodForceRoundTimeout.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(DebugController).ForceRoundTimeout(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
 // RegisterDebugService registers a new debug controller service with the given gRPC server.
 func RegisterDebugService(server *grpc.Server, service DebugController) {
 	server.RegisterService(&debugServiceDesc, service)
@@ -95,10 +179,22 @@ func (c *debugControllerClient) SetEpoch(ctx context.Context, epoch epochtime.Ep
 	return c.conn.Invoke(ctx, methodSetEpoch.FullName(), epoch, nil)
 }
 
+func (c *debugControllerClient) SetEpochSchedule(ctx context.Context, schedule epochtime.EpochSchedule) error {
+	return c.conn.Invoke(ctx, methodSetEpochSchedule.FullName(), schedule, nil)
+}
+
 func (c *debugControllerClient) WaitNodesRegistered(ctx context.Context, count int) error {
 	return c.conn.Invoke(ctx, methodWaitNodesRegistered.FullName(), count, nil)
 }
 
+func (c *debugControllerClient) TriggerElection(ctx context.Context) error {
+	return c.conn.Invoke(ctx, methodTriggerElection.FullName(), nil, nil)
+}
+
+func (c *debugControllerClient) ForceRoundTimeout(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodForceRoundTimeout.FullName(), runtimeID, nil)
+}
+
 // NewDebugControllerClient creates a new gRPC debug controller client service.
 func NewDebugControllerClient(c *grpc.ClientConn) DebugController {
 	return &debugControllerClient{c}
@@ -11,7 +11,7 @@ import (
 )
 
 type nodeController struct {
-	node      control.Shutdownable
+	node      control.ControlledNode
 	consensus consensus.Backend
 	upgrader  upgrade.Backend
 }
@@ -66,14 +66,24 @@ func (c *nodeController) GetStatus(ctx context.Context) (*control.Status, error)
 		return nil, err
 	}
 
+	pu, err := c.upgrader.GetPendingUpgrade(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return &control.Status{
 		SoftwareVersion: version.SoftwareVersion,
 		Consensus:       *cs,
+		Upgrade:         pu,
 	}, nil
 }
 
+func (c *nodeController) ReloadRuntimes(ctx context.Context) error {
+	return c.node.ReloadRuntimes()
+}
+
 // New creates a new oasis-node controller.
-func New(node control.Shutdownable, consensus consensus.Backend, upgrader upgrade.Backend) control.NodeController {
+func New(node control.ControlledNode, consensus consensus.Backend, upgrader upgrade.Backend) control.NodeController {
 	return &nodeController{
 		node:      node,
 		consensus: consensus,
@@ -2,16 +2,33 @@ package control
 
 import (
 	"context"
-	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/control/api"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
 )
 
+var testSigner signature.Signer
+
 type debugController struct {
+	sync.Mutex
+
+	consensus  consensus.Backend
 	timeSource epochtime.Backend
 	registry   registry.Backend
+
+	cancelSchedule context.CancelFunc
+
+	logger *logging.Logger
 }
 
 func (c *debugController) SetEpoch(ctx context.Context, epoch epochtime.EpochTime) error {
@@ -23,6 +40,114 @@ func (c *debugController) SetEpoch(ctx context.Context, epoch epochtime.EpochTim
 	return mockTS.SetEpoch(ctx, epoch)
 }
 
+func (c *debugController) SetEpochSchedule(ctx context.Context, schedule epochtime.EpochSchedule) error {
+	mockTS, ok := c.timeSource.(epochtime.SetableBackend)
+	if !ok {
+		return api.ErrIncompatibleBackend
+	}
+	if err := schedule.SanityCheck(); err != nil {
+		return fmt.Errorf("debug: invalid epoch schedule: %w", err)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.cancelSchedule != nil {
+		c.cancelSchedule()
+		c.cancelSchedule = nil
+	}
+	if len(schedule) == 0 {
+		return nil
+	}
+
+	// The schedule outlives the request, so it must not use its context.
+	sctx, cancel := context.WithCancel(context.Background())
+	c.cancelSchedule = cancel
+	go c.runEpochSchedule(sctx, mockTS, schedule)
+
+	return nil
+}
+
+func (c *debugController) runEpochSchedule(ctx context.Context, mockTS epochtime.SetableBackend, schedule epochtime.EpochSchedule) {
+	blkCh, blkSub, err := c.consensus.WatchBlocks(ctx)
+	if err != nil {
+		c.logger.Error("failed to watch blocks",
+			"err", err,
+		)
+		return
+	}
+	defer blkSub.Close()
+
+	for i := range schedule {
+		e := &schedule[i]
+		if err = c.waitScheduledEpoch(ctx, blkCh, e); err != nil {
+			return
+		}
+
+		c.logger.Info("applying scheduled epoch transition",
+			"epoch", e.Epoch,
+		)
+		if err = mockTS.SetEpoch(ctx, e.Epoch); err != nil {
+			c.logger.Error("failed to apply scheduled epoch transition",
+				"err", err,
+				"epoch", e.Epoch,
+			)
+			return
+		}
+	}
+}
+
+func (c *debugController) waitScheduledEpoch(ctx context.Context, blkCh <-chan *consensus.Block, e *epochtime.ScheduledEpoch) error {
+	if !e.Time.IsZero() {
+		select {
+		case <-time.After(time.Until(e.Time)):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	blk, err := c.consensus.GetBlock(ctx, consensus.HeightLatest)
+	if err == nil && blk.Height >= e.Height {
+		return nil
+	}
+	for {
+		select {
+		case newBlk, ok := <-blkCh:
+			if !ok {
+				return context.Canceled
+			}
+			if newBlk.Height >= e.Height {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *debugController) TriggerElection(ctx context.Context) error {
+	mockTS, ok := c.timeSource.(epochtime.SetableBackend)
+	if !ok {
+		return api.ErrIncompatibleBackend
+	}
+
+	epoch, err := mockTS.GetEpoch(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("debug: failed to get current epoch: %w", err)
+	}
+
+	return mockTS.SetEpoch(ctx, epoch+1)
+}
+
+func (c *debugController) ForceRoundTimeout(ctx context.Context, runtimeID common.Namespace) error {
+	tx := roothash.NewDebugForceRoundTimeoutTx(0, nil, runtimeID)
+	if err := consensus.SignAndSubmitTx(ctx, c.consensus, testSigner, tx); err != nil {
+		return fmt.Errorf("debug: force round timeout failed: %w", err)
+	}
+	return nil
+}
+
 func (c *debugController) WaitNodesRegistered(ctx context.Context, count int) error {
 	ch, sub, err := c.registry.WatchNodes(ctx)
 	if err != nil {
@@ -65,7 +190,13 @@ Loop:
 // New creates a new oasis-node debug controller.
 func NewDebug(consensus consensus.Backend) api.DebugController {
 	return &debugController{
+		consensus:  consensus,
 		timeSource: consensus.EpochTime(),
 		registry:   consensus.Registry(),
+		logger:     logging.GetLogger("control/debug"),
 	}
 }
+
+func init() {
+	testSigner = memorySigner.NewTestSigner("oasis-core debug controller key seed")
+}
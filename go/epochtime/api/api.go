@@ -5,10 +5,38 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/errors"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 )
 
+// ModuleName is a unique module name for the epochtime module.
+const ModuleName = "epochtime"
+
+var (
+	// ErrInvalidArgument is the error returned on malformed arguments.
+	ErrInvalidArgument = errors.New(ModuleName, 1, "epochtime: invalid argument")
+
+	// ErrForbidden is the error returned when an operation is forbidden by
+	// policy.
+	ErrForbidden = errors.New(ModuleName, 2, "epochtime: forbidden by policy")
+
+	// ErrIntervalChangePending is the error returned when an epoch interval
+	// change is requested while another one has not yet taken effect.
+	ErrIntervalChangePending = errors.New(ModuleName, 3, "epochtime: interval change already pending")
+
+	// MethodUpdateParameters is the method name for updating the epochtime
+	// consensus parameters.
+	MethodUpdateParameters = transaction.NewMethodName(ModuleName, "UpdateParameters", UpdateParameters{})
+
+	// Methods is the list of all methods supported by the epochtime backend.
+	Methods = []transaction.MethodName{
+		MethodUpdateParameters,
+	}
+)
+
 // EpochTime is the number of intervals (epochs) since a fixed instant
 // in time (epoch date).
 type EpochTime uint64
@@ -43,6 +71,10 @@ type Backend interface {
 	// Upon subscription the current epoch is sent immediately.
 	WatchLatestEpoch() (<-chan EpochTime, *pubsub.Subscription)
 
+	// WatchIntervalChanges returns a channel that produces a stream of
+	// scheduled epoch interval changes.
+	WatchIntervalChanges() (<-chan *IntervalChange, *pubsub.Subscription)
+
 	// StateToGenesis returns the genesis state at the specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 }
@@ -71,6 +103,47 @@ type ConsensusParameters struct {
 
 	// DebugMockBackend is flag for enabling mock epochtime backend.
 	DebugMockBackend bool `json:"debug_mock_backend"`
+
+	// ParameterUpdateAuthorities is the set of accounts allowed to update
+	// the epochtime consensus parameters via UpdateParameters transactions.
+	// If empty, such transactions are not allowed.
+	ParameterUpdateAuthorities []signature.PublicKey `json:"parameter_update_authorities,omitempty"`
+
+	// GasCosts are the epochtime transaction gas costs.
+	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
+}
+
+// GasOpUpdateParameters is the gas operation identifier for updating the
+// epochtime consensus parameters.
+const GasOpUpdateParameters transaction.Op = "update_parameters"
+
+// DefaultGasCosts are the "default" gas costs for operations.
+var DefaultGasCosts = transaction.Costs{
+	GasOpUpdateParameters: 1000,
+}
+
+// IsParameterUpdateAuthority returns true iff the given account is allowed
+// to update the epochtime consensus parameters.
+func (p *ConsensusParameters) IsParameterUpdateAuthority(id signature.PublicKey) bool {
+	for _, v := range p.ParameterUpdateAuthorities {
+		if v.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateParameters is an epochtime consensus parameter update.
+type UpdateParameters struct {
+	// Interval is the new epoch interval (in blocks). The new interval takes
+	// effect at an epoch boundary, so the epoch in progress is not affected.
+	Interval int64 `json:"interval"`
+}
+
+// NewUpdateParametersTx creates a new epochtime consensus parameter update
+// transaction.
+func NewUpdateParametersTx(nonce uint64, fee *transaction.Fee, upd *UpdateParameters) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodUpdateParameters, upd)
 }
 
 // SanityCheck does basic sanity checking on the genesis state.
@@ -88,5 +161,14 @@ func (g *Genesis) SanityCheck() error {
 		return fmt.Errorf("epochtime: sanity check failed: starting epoch is invalid")
 	}
 
+	for _, id := range g.Parameters.ParameterUpdateAuthorities {
+		if !id.IsValid() {
+			return fmt.Errorf("epochtime: sanity check failed: invalid parameter update authority: %s", id)
+		}
+	}
+	if len(g.Parameters.ParameterUpdateAuthorities) > 0 && g.Parameters.DebugMockBackend {
+		return fmt.Errorf("epochtime: sanity check failed: parameter updates are not supported by the mock backend")
+	}
+
 	return nil
 }
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScheduledEpoch is an epoch transition scripted for a mock epochtime
+// backend. Exactly one of Height and Time must be set.
+type ScheduledEpoch struct {
+	// Epoch is the epoch to transition to.
+	Epoch EpochTime `json:"epoch"`
+	// Height is the consensus height at which the transition should happen.
+	Height int64 `json:"height,omitempty"`
+	// Time is the wall clock time at which the transition should happen.
+	Time time.Time `json:"time,omitempty"`
+}
+
+// EpochSchedule is a script of epoch transitions for a mock epochtime
+// backend, ordered by epoch.
+type EpochSchedule []ScheduledEpoch
+
+// SanityCheck performs a sanity check on the epoch schedule.
+func (s EpochSchedule) SanityCheck() error {
+	var (
+		lastHeight int64
+		lastTime   time.Time
+	)
+	for i, e := range s {
+		if i > 0 && e.Epoch <= s[i-1].Epoch {
+			return fmt.Errorf("epochtime: schedule epochs must be increasing (%d after %d)", e.Epoch, s[i-1].Epoch)
+		}
+
+		switch {
+		case e.Height > 0 && e.Time.IsZero():
+			if e.Height < lastHeight {
+				return fmt.Errorf("epochtime: epoch %d scheduled at height %d before height %d", e.Epoch, e.Height, lastHeight)
+			}
+			lastHeight = e.Height
+		case e.Height == 0 && !e.Time.IsZero():
+			if e.Time.Before(lastTime) {
+				return fmt.Errorf("epochtime: epoch %d scheduled at time %s before time %s", e.Epoch, e.Time, lastTime)
+			}
+			lastTime = e.Time
+		default:
+			return fmt.Errorf("epochtime: epoch %d must be scheduled at either a height or a time", e.Epoch)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpochSchedule(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	schedule := EpochSchedule{
+		{Epoch: 1, Height: 10},
+		{Epoch: 2, Time: now},
+		{Epoch: 3, Height: 10},
+		{Epoch: 5, Time: now.Add(time.Minute)},
+	}
+	require.NoError(schedule.SanityCheck(), "SanityCheck")
+	require.NoError(EpochSchedule{}.SanityCheck(), "SanityCheck empty schedule")
+
+	for _, invalid := range []EpochSchedule{
+		{{Epoch: 2, Height: 10}, {Epoch: 2, Height: 20}},
+		{{Epoch: 2, Height: 10}, {Epoch: 1, Height: 20}},
+		{{Epoch: 1, Height: 20}, {Epoch: 2, Height: 10}},
+		{{Epoch: 1, Time: now}, {Epoch: 2, Time: now.Add(-time.Minute)}},
+		{{Epoch: 1}},
+		{{Epoch: 1, Height: 10, Time: now}},
+	} {
+		require.Error(invalid.SanityCheck(), "SanityCheck invalid schedule %+v", invalid)
+	}
+}
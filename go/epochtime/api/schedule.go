@@ -0,0 +1,102 @@
+package api
+
+import "fmt"
+
+// IntervalChange is a change of the epoch interval, which starts at an epoch
+// boundary.
+type IntervalChange struct {
+	// Height is the block height at which the interval takes effect. It is
+	// the first block of Epoch.
+	Height int64 `json:"height"`
+	// Epoch is the first epoch using the interval.
+	Epoch EpochTime `json:"epoch"`
+	// Interval is the epoch interval (in blocks).
+	Interval int64 `json:"interval"`
+}
+
+// EpochAt returns the epoch at the given block height, assuming that the
+// interval is in effect at that height.
+func (c *IntervalChange) EpochAt(height int64) EpochTime {
+	return c.Epoch + EpochTime((height-c.Height)/c.Interval)
+}
+
+// NextBoundary returns the height of the first epoch boundary after the
+// given block height.
+func (c *IntervalChange) NextBoundary(height int64) int64 {
+	return c.Height + ((height-c.Height)/c.Interval+1)*c.Interval
+}
+
+// IntervalSchedule is the history of epoch interval changes, ordered by
+// height. The first entry is the interval configured at genesis.
+type IntervalSchedule []IntervalChange
+
+// NewIntervalSchedule creates a new schedule with a single interval starting
+// at the given base epoch.
+func NewIntervalSchedule(base EpochTime, interval int64) IntervalSchedule {
+	return IntervalSchedule{
+		{Height: 0, Epoch: base, Interval: interval},
+	}
+}
+
+// At returns the interval change in effect at the given block height.
+func (s IntervalSchedule) At(height int64) *IntervalChange {
+	for i := len(s) - 1; i > 0; i-- {
+		if s[i].Height <= height {
+			return &s[i]
+		}
+	}
+	return &s[0]
+}
+
+// Last returns the most recent interval change, which may not yet be in
+// effect.
+func (s IntervalSchedule) Last() *IntervalChange {
+	return &s[len(s)-1]
+}
+
+// EpochAt returns the epoch at the given block height.
+func (s IntervalSchedule) EpochAt(height int64) EpochTime {
+	return s.At(height).EpochAt(height)
+}
+
+// HeightAt returns the block height at the start of the given epoch.
+func (s IntervalSchedule) HeightAt(epoch EpochTime) (int64, error) {
+	if epoch < s[0].Epoch {
+		return 0, fmt.Errorf("epochtime: epoch predates base")
+	}
+
+	c := &s[0]
+	for i := len(s) - 1; i > 0; i-- {
+		if s[i].Epoch <= epoch {
+			c = &s[i]
+			break
+		}
+	}
+	return c.Height + int64(epoch-c.Epoch)*c.Interval, nil
+}
+
+// SanityCheck does basic sanity checking on the interval schedule.
+func (s IntervalSchedule) SanityCheck() error {
+	if len(s) == 0 {
+		return fmt.Errorf("epochtime: empty interval schedule")
+	}
+	for i, c := range s {
+		if c.Interval <= 0 {
+			return fmt.Errorf("epochtime: interval change %d: epoch interval must be > 0", i)
+		}
+		if i == 0 {
+			continue
+		}
+
+		// Interval changes must start at epoch boundaries of the previous
+		// interval.
+		prev := &s[i-1]
+		if c.Height <= prev.Height || (c.Height-prev.Height)%prev.Interval != 0 {
+			return fmt.Errorf("epochtime: interval change %d does not start at an epoch boundary", i)
+		}
+		if c.Epoch != prev.EpochAt(c.Height) {
+			return fmt.Errorf("epochtime: interval change %d has inconsistent epoch", i)
+		}
+	}
+	return nil
+}
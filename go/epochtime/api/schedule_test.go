@@ -0,0 +1,57 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalSchedule(t *testing.T) {
+	require := require.New(t)
+
+	schedule := NewIntervalSchedule(10, 5)
+	require.NoError(schedule.SanityCheck(), "SanityCheck")
+	require.EqualValues(10, schedule.EpochAt(0), "EpochAt genesis")
+	require.EqualValues(11, schedule.EpochAt(7), "EpochAt")
+
+	// Change the interval from 5 to 3 at the boundary after height 12.
+	last := schedule.Last()
+	boundary := last.NextBoundary(12)
+	require.EqualValues(15, boundary, "NextBoundary")
+	require.EqualValues(20, last.NextBoundary(15), "NextBoundary at boundary")
+	schedule = append(schedule, IntervalChange{
+		Height:   boundary,
+		Epoch:    last.EpochAt(boundary),
+		Interval: 3,
+	})
+	require.NoError(schedule.SanityCheck(), "SanityCheck")
+
+	require.EqualValues(12, schedule.EpochAt(14), "EpochAt before change")
+	require.EqualValues(13, schedule.EpochAt(15), "EpochAt change")
+	require.EqualValues(13, schedule.EpochAt(17), "EpochAt after change")
+	require.EqualValues(14, schedule.EpochAt(18), "EpochAt after change")
+
+	for _, tc := range []struct {
+		epoch  EpochTime
+		height int64
+	}{
+		{10, 0},
+		{12, 10},
+		{13, 15},
+		{15, 21},
+	} {
+		height, err := schedule.HeightAt(tc.epoch)
+		require.NoError(err, "HeightAt")
+		require.EqualValues(tc.height, height, "HeightAt(%d)", tc.epoch)
+	}
+	_, err := schedule.HeightAt(9)
+	require.Error(err, "HeightAt before base")
+
+	// Changes must start at epoch boundaries.
+	invalid := append(IntervalSchedule{}, schedule...)
+	invalid[1].Height = 16
+	require.Error(invalid.SanityCheck(), "SanityCheck should fail for misaligned change")
+	invalid = append(IntervalSchedule{}, schedule...)
+	invalid[1].Epoch = 14
+	require.Error(invalid.SanityCheck(), "SanityCheck should fail for inconsistent epoch")
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+)
+
+// CheckpointManifest is a manifest of the runtime state checkpoints that are
+// referenced by a genesis document.
+//
+// The checkpoints themselves are pinned by the genesis document, while the
+// manifest contains additional information (e.g., chunk sizes and storage
+// nodes serving the checkpoints) useful for bootstrapping runtime state.
+type CheckpointManifest struct {
+	// GenesisHash is the hash of the genesis document that references the
+	// checkpoints.
+	GenesisHash hash.Hash `json:"genesis_hash"`
+	// Runtimes are the per-runtime checkpoint manifests.
+	Runtimes map[common.Namespace]*RuntimeCheckpointManifest `json:"runtimes"`
+}
+
+// RuntimeCheckpointManifest is a manifest of a single runtime state checkpoint.
+type RuntimeCheckpointManifest struct {
+	// Checkpoint is the checkpoint metadata.
+	Checkpoint checkpoint.Metadata `json:"checkpoint"`
+	// ChunkSizes are the sizes of the checkpoint chunks in bytes.
+	ChunkSizes []uint64 `json:"chunk_sizes"`
+	// StorageNodes are the storage nodes that were serving the checkpoint
+	// at the time the manifest was generated.
+	StorageNodes []*CheckpointStorageNode `json:"storage_nodes,omitempty"`
+}
+
+// CheckpointStorageNode is a storage node serving a checkpoint.
+type CheckpointStorageNode struct {
+	// ID is the node's public key.
+	ID signature.PublicKey `json:"id"`
+	// Addresses are the addresses at which the node can be reached.
+	Addresses []node.TLSAddress `json:"addresses"`
+}
+
+// Verify verifies that the manifest matches the checkpoints referenced by
+// the given genesis document.
+func (m *CheckpointManifest) Verify(doc *Document) error {
+	if h := doc.Hash(); !m.GenesisHash.Equal(&h) {
+		return fmt.Errorf("genesis: checkpoint manifest is for a different genesis document")
+	}
+
+	var numCheckpoints int
+	for id, rtg := range doc.RootHash.RuntimeStates {
+		if rtg.Checkpoint == nil {
+			continue
+		}
+		numCheckpoints++
+
+		rtm := m.Runtimes[id]
+		if rtm == nil {
+			return fmt.Errorf("genesis: checkpoint manifest is missing runtime %s", id)
+		}
+		if rtm.Checkpoint.EncodedHash() != rtg.Checkpoint.EncodedHash() {
+			return fmt.Errorf("genesis: checkpoint manifest for runtime %s does not match genesis checkpoint", id)
+		}
+		if len(rtm.ChunkSizes) != len(rtm.Checkpoint.Chunks) {
+			return fmt.Errorf("genesis: checkpoint manifest for runtime %s has %d chunk sizes (expected %d)",
+				id, len(rtm.ChunkSizes), len(rtm.Checkpoint.Chunks),
+			)
+		}
+	}
+	if len(m.Runtimes) != numCheckpoints {
+		return fmt.Errorf("genesis: checkpoint manifest contains runtimes without genesis checkpoints")
+	}
+
+	return nil
+}
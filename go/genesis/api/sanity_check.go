@@ -1,11 +1,68 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	keymanager "github.com/oasislabs/oasis-core/go/keymanager/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
 
+// SanityCheckError is a single violation found while sanity checking the
+// genesis document.
+type SanityCheckError struct {
+	// Path is the JSON path of the offending part of the genesis document.
+	Path string
+	// Err is the violation.
+	Err error
+}
+
+// Error returns the error message.
+func (e *SanityCheckError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying violation.
+func (e *SanityCheckError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the violation into JSON.
+func (e *SanityCheckError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path  string `json:"path"`
+		Error string `json:"error"`
+	}{
+		Path:  e.Path,
+		Error: e.Err.Error(),
+	})
+}
+
+// SanityCheckErrors is a list of violations found while sanity checking the
+// genesis document.
+type SanityCheckErrors []*SanityCheckError
+
+// Error returns the error message.
+func (e SanityCheckErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("genesis: sanity check failed with %d error(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+func (e *SanityCheckErrors) add(path string, err error) {
+	*e = append(*e, &SanityCheckError{Path: path, Err: err})
+}
+
 // SanityCheck does basic sanity checking on the contents of the genesis document.
 func (d *Document) SanityCheck() error {
 	if d.Height < 0 {
@@ -39,6 +96,9 @@ func (d *Document) SanityCheck() error {
 	if err = d.Scheduler.SanityCheck(&d.Staking.TotalSupply); err != nil {
 		return err
 	}
+	if err = d.sanityCheckElectionOffset(); err != nil {
+		return fmt.Errorf("scheduler: sanity check failed: %w", err)
+	}
 	if err = d.Beacon.SanityCheck(); err != nil {
 		return err
 	}
@@ -52,3 +112,212 @@ func (d *Document) SanityCheck() error {
 
 	return nil
 }
+
+// SanityCheckAll performs the same checks as SanityCheck, but instead of
+// aborting on the first problem it walks all of the subsystems and returns
+// all violations found, or nil if the genesis document is valid.
+//
+// Individual entries (e.g., entities, nodes, accounts) are checked one by
+// one. Checks that cross-reference entries are only performed once all of
+// the entries they depend on are valid.
+func (d *Document) SanityCheckAll() SanityCheckErrors {
+	var errs SanityCheckErrors
+
+	if d.Height < 0 {
+		errs.add("height", fmt.Errorf("height must be >= 0"))
+	}
+	if d.Time.After(time.Now()) {
+		errs.add("genesis_time", fmt.Errorf("time of genesis document is in the future"))
+	}
+	if strings.TrimSpace(d.ChainID) == "" {
+		errs.add("chain_id", fmt.Errorf("chain ID must not be empty"))
+	}
+
+	if err := d.EpochTime.SanityCheck(); err != nil {
+		errs.add("epochtime", err)
+	}
+	d.sanityCheckRegistry(&errs)
+	d.sanityCheckRootHash(&errs)
+	d.sanityCheckStaking(&errs)
+	d.sanityCheckKeyManager(&errs)
+	if err := d.Scheduler.SanityCheck(&d.Staking.TotalSupply); err != nil {
+		errs.add("scheduler", err)
+	}
+	if err := d.sanityCheckElectionOffset(); err != nil {
+		errs.add("scheduler.params.election_offset", err)
+	}
+	if err := d.Beacon.SanityCheck(); err != nil {
+		errs.add("beacon", err)
+	}
+	if err := d.Consensus.SanityCheck(); err != nil {
+		errs.add("consensus", err)
+	}
+
+	if d.HaltEpoch < d.EpochTime.Base {
+		errs.add("halt_epoch", fmt.Errorf("halt epoch is in the past"))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// sanityCheckElectionOffset checks that upcoming committees can be elected
+// before the start of each epoch.
+func (d *Document) sanityCheckElectionOffset() error {
+	offset := d.Scheduler.Parameters.ElectionOffset
+	if offset == 0 {
+		return nil
+	}
+	if d.EpochTime.Parameters.DebugMockBackend {
+		return fmt.Errorf("election offset is not supported by the mock epochtime backend")
+	}
+	if offset >= uint64(d.EpochTime.Parameters.Interval) {
+		return fmt.Errorf("election offset must be less than the epoch interval")
+	}
+	return nil
+}
+
+func (d *Document) sanityCheckRegistry(errs *SanityCheckErrors) {
+	logger := logging.GetLogger("genesis/sanity-check")
+	g := &d.Registry
+	numErrs := len(*errs)
+
+	seenEntities := make(map[signature.PublicKey]*entity.Entity)
+	for i, signedEnt := range g.Entities {
+		ent, err := registry.VerifyRegisterEntityArgs(logger, signedEnt, true, true)
+		if err != nil {
+			errs.add(fmt.Sprintf("registry.entities[%d]", i), err)
+			continue
+		}
+		seenEntities[ent.ID] = ent
+	}
+
+	runtimesValid := true
+	for _, v := range []struct {
+		path     string
+		runtimes []*registry.SignedRuntime
+	}{
+		{"registry.runtimes", g.Runtimes},
+		{"registry.suspended_runtimes", g.SuspendedRuntimes},
+	} {
+		for i, signedRt := range v.runtimes {
+			if _, err := registry.VerifyRegisterRuntimeArgs(&g.Parameters, logger, signedRt, true, true); err != nil {
+				errs.add(fmt.Sprintf("%s[%d]", v.path, i), err)
+				runtimesValid = false
+			}
+		}
+	}
+	if !runtimesValid {
+		return
+	}
+
+	// Nodes reference runtimes, so they can only be checked once all the
+	// runtimes are valid.
+	runtimesLookup, err := registry.SanityCheckRuntimes(logger, &g.Parameters, g.Runtimes, g.SuspendedRuntimes, true)
+	if err != nil {
+		errs.add("registry.runtimes", err)
+		return
+	}
+	if err = registry.SanityCheckGovernanceSuspendedRuntimes(g.GovernanceSuspendedRuntimes, runtimesLookup); err != nil {
+		errs.add("registry.governance_suspended_runtimes", err)
+	}
+	for i, signedNode := range g.Nodes {
+		_, err = registry.SanityCheckNodes(
+			logger,
+			&g.Parameters,
+			[]*node.MultiSignedNode{signedNode},
+			seenEntities,
+			runtimesLookup,
+			true,
+			d.EpochTime.Base,
+		)
+		if err != nil {
+			errs.add(fmt.Sprintf("registry.nodes[%d]", i), err)
+		}
+	}
+
+	// Check the registry as a whole (e.g., parameters, stake) once all
+	// individual entries are valid.
+	if len(*errs) == numErrs {
+		if err = g.SanityCheck(d.EpochTime.Base, d.Staking.Ledger, d.Staking.Parameters.Thresholds); err != nil {
+			errs.add("registry", err)
+		}
+	}
+}
+
+func (d *Document) sanityCheckRootHash(errs *SanityCheckErrors) {
+	g := &d.RootHash
+	numErrs := len(*errs)
+
+	for id, rtg := range g.RuntimeStates {
+		path := fmt.Sprintf("roothash.runtime_states.%s", id)
+		if err := rtg.SanityCheck(true); err != nil {
+			errs.add(path, err)
+			continue
+		}
+		if rtg.Checkpoint != nil && !rtg.Checkpoint.Root.Namespace.Equal(&id) {
+			errs.add(path, fmt.Errorf("checkpoint namespace does not match runtime %s", id))
+		}
+	}
+
+	if len(*errs) == numErrs {
+		if err := g.SanityCheck(); err != nil {
+			errs.add("roothash", err)
+		}
+	}
+}
+
+func (d *Document) sanityCheckStaking(errs *SanityCheckErrors) {
+	g := &d.Staking
+	numErrs := len(*errs)
+
+	if err := g.Parameters.SanityCheck(); err != nil {
+		errs.add("staking.params", err)
+	}
+
+	for id, acct := range g.Ledger {
+		path := fmt.Sprintf("staking.ledger.%s", id)
+		if acct == nil {
+			errs.add(path, fmt.Errorf("account is missing"))
+			continue
+		}
+
+		var total quantity.Quantity
+		if err := staking.SanityCheckAccount(&total, &g.Parameters, d.EpochTime.Base, id, acct); err != nil {
+			errs.add(path, err)
+		}
+		if len(acct.Escrow.StakeAccumulator.Claims) > 0 {
+			errs.add(path, fmt.Errorf("non-empty stake accumulator in genesis"))
+		}
+		if err := staking.SanityCheckAccountShares(id, acct, g.Delegations[id], g.DebondingDelegations[id]); err != nil {
+			errs.add(path, err)
+		}
+	}
+	for id := range g.Delegations {
+		if g.Ledger[id] == nil {
+			errs.add(fmt.Sprintf("staking.delegations.%s", id), fmt.Errorf("delegation specified for a nonexisting account"))
+		}
+	}
+	for id := range g.DebondingDelegations {
+		if g.Ledger[id] == nil {
+			errs.add(fmt.Sprintf("staking.debonding_delegations.%s", id), fmt.Errorf("debonding delegation specified for a nonexisting account"))
+		}
+	}
+
+	// Check the totals once all individual accounts are valid.
+	if len(*errs) == numErrs {
+		if err := g.SanityCheck(d.EpochTime.Base); err != nil {
+			errs.add("staking", err)
+		}
+	}
+}
+
+func (d *Document) sanityCheckKeyManager(errs *SanityCheckErrors) {
+	for i, status := range d.KeyManager.Statuses {
+		if err := keymanager.SanityCheckStatuses([]*keymanager.Status{status}); err != nil {
+			errs.add(fmt.Sprintf("keymanager.statuses[%d]", i), err)
+		}
+	}
+}
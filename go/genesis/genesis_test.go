@@ -2,6 +2,7 @@ package genesis
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"math"
 	"testing"
 	"time"
@@ -128,7 +129,55 @@ func TestGenesisChainContext(t *testing.T) {
 	//       on each run.
 	stableDoc.Staking = staking.Genesis{}
 
-	require.Equal(t, "1024b5ca04a34e17cab59fdae43c32c05e1a51875841b99ea49321a4ec83adb3", stableDoc.ChainContext())
+	require.Equal(t, "9b8d9aa5ce2711cc179c5819d26962e5bfafd864d2381633ef7ac3605e0afef5", stableDoc.ChainContext())
+}
+
+func TestGenesisSanityCheckAll(t *testing.T) {
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, true)
+	require := require.New(t)
+
+	require.Nil(testDoc.SanityCheckAll(), "test genesis document should be valid")
+
+	invalidPK := hex2pk("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	signature.BuildPublicKeyBlacklist(true)
+
+	d := *testDoc
+	d.Height = -1
+	d.ChainID = ""
+	d.EpochTime.Base = 10
+	d.HaltEpoch = 5
+	d.KeyManager = keymanager.Genesis{
+		Statuses: []*keymanager.Status{
+			{
+				ID: hex2ns("0000000000000000000000000000000000000000000000000000000000000001", false),
+			},
+			{
+				ID:    hex2ns("4000000000000000ffffffffffffffffffffffffffffffffffffffffffffffff", false),
+				Nodes: []signature.PublicKey{invalidPK},
+			},
+		},
+	}
+
+	errs := d.SanityCheckAll()
+	require.Error(errs, "invalid genesis document should fail sanity check")
+	require.Error(d.SanityCheck(), "SanityCheck should also fail")
+
+	var paths []string
+	for _, err := range errs {
+		require.Error(err.Err, "violation should have an error")
+		paths = append(paths, err.Path)
+	}
+	require.EqualValues([]string{
+		"height",
+		"chain_id",
+		"keymanager.statuses[0]",
+		"keymanager.statuses[1]",
+		"halt_epoch",
+	}, paths, "all violations should be reported")
+
+	data, err := json.Marshal(errs[0])
+	require.NoError(err, "json.Marshal")
+	require.JSONEq(`{"path":"height","error":"height must be >= 0"}`, string(data))
 }
 
 func TestGenesisSanityCheck(t *testing.T) {
@@ -292,6 +341,19 @@ func TestGenesisSanityCheck(t *testing.T) {
 	d.EpochTime.Parameters.DebugMockBackend = false
 	require.Error(d.SanityCheck(), "invalid epoch interval should be rejected")
 
+	d = *testDoc
+	d.Scheduler.Parameters.ElectionOffset = 5
+	require.Error(d.SanityCheck(), "election offset with the mock epochtime backend should be rejected")
+
+	d = *testDoc
+	d.EpochTime.Parameters.Interval = 5
+	d.EpochTime.Parameters.DebugMockBackend = false
+	d.Scheduler.Parameters.ElectionOffset = 5
+	require.Error(d.SanityCheck(), "election offset not less than the epoch interval should be rejected")
+
+	d.Scheduler.Parameters.ElectionOffset = 4
+	require.NoError(d.SanityCheck(), "election offset less than the epoch interval should be valid")
+
 	// Test keymanager genesis checks.
 	d = *testDoc
 	d.KeyManager = keymanager.Genesis{
@@ -654,6 +716,30 @@ func TestGenesisSanityCheck(t *testing.T) {
 	d.Staking.LastBlockFees = stakingTests.QtyFromInt(100)
 	require.Error(d.SanityCheck(), "invalid last block fees should be rejected")
 
+	d = *testDoc
+	d.Staking.Parameters.Minting = &staking.MintingParameters{
+		Schedule: []staking.MintingStep{
+			{Until: 10, Rate: stakingTests.QtyFromInt(100_000)},
+		},
+		SupplyCap: stakingTests.QtyFromInt(math.MaxInt64),
+	}
+	require.NoError(d.SanityCheck(), "valid minting parameters should pass")
+
+	d = *testDoc
+	d.Staking.Parameters.Minting = &staking.MintingParameters{
+		SupplyCap: stakingTests.QtyFromInt(1),
+	}
+	require.Error(d.SanityCheck(), "total supply over minting supply cap should be rejected")
+
+	d = *testDoc
+	d.Staking.Parameters.Minting = &staking.MintingParameters{
+		Schedule: []staking.MintingStep{
+			{Until: 10, Rate: stakingTests.QtyFromInt(200_000)},
+		},
+		SupplyCap: stakingTests.QtyFromInt(math.MaxInt64),
+	}
+	require.Error(d.SanityCheck(), "invalid minting rate should be rejected")
+
 	d = *testDoc
 	d.Staking.Ledger[stakingTests.DebugStateSrcID].General.Balance = stakingTests.QtyFromInt(100)
 	require.Error(d.SanityCheck(), "invalid general balance should be rejected")
@@ -13,6 +13,7 @@ replace (
 )
 
 require (
+	github.com/DataDog/zstd v1.4.1
 	github.com/RoaringBitmap/roaring v0.4.18 // indirect
 	github.com/blevesearch/bleve v0.8.0
 	github.com/blevesearch/blevex v0.0.0-20180227211930-4b158bb555a3 // indirect
@@ -37,6 +38,7 @@ require (
 	github.com/ipfs/go-log/v2 v2.0.8 // indirect
 	github.com/libp2p/go-libp2p v0.9.1
 	github.com/libp2p/go-libp2p-core v0.5.6
+	github.com/miekg/pkcs11 v1.0.3
 	github.com/multiformats/go-multiaddr v0.2.2
 	github.com/multiformats/go-multiaddr-net v0.1.5
 	github.com/oasislabs/deoxysii v0.0.0-20190807103041-6159f99c2236
@@ -15,6 +15,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 )
 
@@ -27,6 +28,11 @@ const (
 
 	// EnclaveRPCEndpoint is the name of the key manager EnclaveRPC endpoint.
 	EnclaveRPCEndpoint = "key-manager"
+
+	// LogEventNodeFailover is a log event value that signals that a key
+	// manager client stopped using a key manager node that misbehaved and
+	// failed over to another one.
+	LogEventNodeFailover = "keymanager/node_failover"
 )
 
 var (
@@ -74,6 +80,16 @@ type Status struct {
 	Policy *SignedPolicySGX `json:"policy"`
 }
 
+// AnnotatedStatus is a key manager status annotated with the consensus
+// height at which it came into effect.
+type AnnotatedStatus struct {
+	// Height is the consensus height at which the status came into effect.
+	Height int64 `json:"height"`
+
+	// Status is the key manager status.
+	Status *Status `json:"status"`
+}
+
 // Backend is a key manager management implementation.
 type Backend interface {
 	// GetStatus returns a key manager status by key manager ID.
@@ -83,10 +99,14 @@ type Backend interface {
 	GetStatuses(context.Context, int64) ([]*Status, error)
 
 	// WatchStatuses returns a channel that produces a stream of messages
-	// containing the key manager statuses as it changes over time.
+	// containing the key manager statuses as it changes over time, each
+	// annotated with the consensus height of the change.
 	//
-	// Upon subscription the current status is sent immediately.
-	WatchStatuses() (<-chan *Status, *pubsub.Subscription)
+	// If fromHeight is consensus.HeightLatest, the current statuses are sent
+	// immediately upon subscription. Otherwise the latest status change of
+	// each key manager that happened at or after fromHeight is replayed
+	// first, followed by any live status changes.
+	WatchStatuses(ctx context.Context, fromHeight int64) (<-chan *AnnotatedStatus, pubsub.ClosableSubscription, error)
 
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(context.Context, int64) (*Genesis, error)
@@ -112,6 +132,18 @@ type SignedInitResponse struct {
 	Signature    []byte       `json:"signature"`
 }
 
+// SignInitResponse signs the given initialization response.
+func SignInitResponse(signer signature.Signer, initResponse *InitResponse) (*SignedInitResponse, error) {
+	sig, err := signer.ContextSign(initResponseContext, cbor.Marshal(initResponse))
+	if err != nil {
+		return nil, err
+	}
+	return &SignedInitResponse{
+		InitResponse: *initResponse,
+		Signature:    sig,
+	}, nil
+}
+
 func (r *SignedInitResponse) Verify(pk signature.PublicKey) error {
 	raw := cbor.Marshal(r.InitResponse)
 	if !pk.Verify(initResponseContext, raw, r.Signature) {
@@ -122,7 +154,13 @@ func (r *SignedInitResponse) Verify(pk signature.PublicKey) error {
 
 // VerifyExtraInfo verifies and parses the per-node + per-runtime ExtraInfo
 // blob for a key manager.
-func VerifyExtraInfo(logger *logging.Logger, rt *registry.Runtime, nodeRt *node.Runtime, ts time.Time) (*InitResponse, error) {
+func VerifyExtraInfo(
+	logger *logging.Logger,
+	rt *registry.Runtime,
+	nodeRt *node.Runtime,
+	ts time.Time,
+	epoch epochtime.EpochTime,
+) (*InitResponse, error) {
 	var (
 		hw  node.TEEHardware
 		rak signature.PublicKey
@@ -136,7 +174,7 @@ func VerifyExtraInfo(logger *logging.Logger, rt *registry.Runtime, nodeRt *node.
 	}
 	if hw != rt.TEEHardware {
 		return nil, fmt.Errorf("keymanager: TEEHardware mismatch")
-	} else if err := registry.VerifyNodeRuntimeEnclaveIDs(logger, nodeRt, rt, ts); err != nil {
+	} else if err := registry.VerifyNodeRuntimeEnclaveIDs(logger, nodeRt, rt, ts, epoch); err != nil {
 		return nil, err
 	}
 	if nodeRt.ExtraInfo == nil {
@@ -153,8 +191,50 @@ func VerifyExtraInfo(logger *logging.Logger, rt *registry.Runtime, nodeRt *node.
 	return &untrustedSignedInitResponse.InitResponse, nil
 }
 
+// ConsensusParameters are the key manager consensus parameters.
+type ConsensusParameters struct {
+	// PolicySigners is the set of keys that may sign key manager policy
+	// updates. If empty, any validly signed policy update is accepted.
+	PolicySigners []signature.PublicKey `json:"policy_signers,omitempty"`
+
+	// PolicySignerThreshold is the number of distinct policy signers that
+	// must sign a key manager policy update.
+	PolicySignerThreshold uint8 `json:"policy_signer_threshold,omitempty"`
+}
+
+// SanityCheck performs a sanity check on the consensus parameters.
+func (p *ConsensusParameters) SanityCheck() error {
+	if len(p.PolicySigners) == 0 {
+		if p.PolicySignerThreshold != 0 {
+			return fmt.Errorf("keymanager: sanity check failed: policy signer threshold set without policy signers")
+		}
+		return nil
+	}
+
+	seen := make(map[signature.PublicKey]bool)
+	for _, pk := range p.PolicySigners {
+		if !pk.IsValid() {
+			return fmt.Errorf("keymanager: sanity check failed: policy signer %s is invalid", pk)
+		}
+		if seen[pk] {
+			return fmt.Errorf("keymanager: sanity check failed: duplicate policy signer %s", pk)
+		}
+		seen[pk] = true
+	}
+	if p.PolicySignerThreshold == 0 || int(p.PolicySignerThreshold) > len(p.PolicySigners) {
+		return fmt.Errorf("keymanager: sanity check failed: invalid policy signer threshold %d for %d signers",
+			p.PolicySignerThreshold,
+			len(p.PolicySigners),
+		)
+	}
+	return nil
+}
+
 // Genesis is the key manager management genesis state.
 type Genesis struct {
+	// Parameters are the key manager consensus parameters.
+	Parameters ConsensusParameters `json:"params"`
+
 	Statuses []*Status `json:"statuses,omitempty"`
 }
 
@@ -185,6 +265,10 @@ func SanityCheckStatuses(statuses []*Status) error {
 
 // SanityCheck does basic sanity checking on the genesis state.
 func (g *Genesis) SanityCheck() error {
+	if err := g.Parameters.SanityCheck(); err != nil {
+		return err
+	}
+
 	err := SanityCheckStatuses(g.Statuses)
 	if err != nil {
 		return err
@@ -8,6 +8,7 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	enclaverpc "github.com/oasislabs/oasis-core/go/runtime/enclaverpc/api"
 )
@@ -25,6 +26,9 @@ var (
 	// methodGetStatuses is the GetStatuses method.
 	methodGetStatuses = serviceName.NewMethod("GetStatuses", int64(0))
 
+	// methodWatchStatuses is the WatchStatuses method.
+	methodWatchStatuses = serviceName.NewMethod("WatchStatuses", int64(0))
+
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
 		ServiceName: string(serviceName),
@@ -39,6 +43,13 @@ var (
 				Handler:    handlerGetStatuses,
 			},
 		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    methodWatchStatuses.ShortName(),
+				Handler:       handlerWatchStatuses,
+				ServerStreams: true,
+			},
+		},
 	}
 )
 
@@ -115,6 +126,35 @@ func handlerGetStatuses( //nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerWatchStatuses(srv interface{}, stream grpc.ServerStream) error {
+	var fromHeight int64
+	if err := stream.RecvMsg(&fromHeight); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchStatuses(ctx, fromHeight)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case st, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(st); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new keymanager backend service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -141,6 +181,41 @@ func (c *KeymanagerClient) GetStatuses(ctx context.Context, height int64) ([]*St
 	return resp, nil
 }
 
+func (c *KeymanagerClient) WatchStatuses(ctx context.Context, fromHeight int64) (<-chan *AnnotatedStatus, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], methodWatchStatuses.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(fromHeight); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *AnnotatedStatus)
+	go func() {
+		defer close(ch)
+
+		for {
+			var st AnnotatedStatus
+			if serr := stream.RecvMsg(&st); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &st:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 // NewKeymanagerClient creates a new gRPC keymanager client service.
 func NewKeymanagerClient(c *grpc.ClientConn) *KeymanagerClient {
 	return &KeymanagerClient{c}
@@ -78,3 +78,30 @@ func SanityCheckSignedPolicySGX(currentSigPol, newSigPol *SignedPolicySGX) error
 
 	return nil
 }
+
+// VerifyQuorum verifies that the policy is signed by a quorum of the policy
+// signers configured in the consensus parameters. Signatures must already
+// have been verified via SanityCheckSignedPolicySGX.
+func (sp *SignedPolicySGX) VerifyQuorum(params *ConsensusParameters) error {
+	if len(params.PolicySigners) == 0 {
+		return nil
+	}
+
+	signers := make(map[signature.PublicKey]bool)
+	for _, pk := range params.PolicySigners {
+		signers[pk] = true
+	}
+	signed := make(map[signature.PublicKey]bool)
+	for _, sig := range sp.Signatures {
+		if signers[sig.PublicKey] {
+			signed[sig.PublicKey] = true
+		}
+	}
+	if len(signed) < int(params.PolicySignerThreshold) {
+		return fmt.Errorf("keymanager: SGX policy has %d policy signer signatures, need %d",
+			len(signed),
+			params.PolicySignerThreshold,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+func TestPolicySignerQuorum(t *testing.T) {
+	require := require.New(t)
+
+	signers := TestSigners[1:]
+	var signerKeys []signature.PublicKey
+	for _, signer := range signers {
+		signerKeys = append(signerKeys, signer.Public())
+	}
+
+	require.NoError((&ConsensusParameters{}).SanityCheck(), "empty parameters should be valid")
+	require.Error((&ConsensusParameters{PolicySignerThreshold: 1}).SanityCheck(), "threshold without signers should be invalid")
+	require.Error((&ConsensusParameters{PolicySigners: signerKeys}).SanityCheck(), "zero threshold should be invalid")
+	require.Error((&ConsensusParameters{PolicySigners: signerKeys, PolicySignerThreshold: 4}).SanityCheck(), "threshold above the number of signers should be invalid")
+	require.Error((&ConsensusParameters{PolicySigners: append(signerKeys, signerKeys[0]), PolicySignerThreshold: 2}).SanityCheck(), "duplicate signers should be invalid")
+
+	params := &ConsensusParameters{PolicySigners: signerKeys, PolicySignerThreshold: 2}
+	require.NoError(params.SanityCheck(), "SanityCheck")
+
+	sigPol := &SignedPolicySGX{
+		Policy: PolicySGX{
+			Serial: 1,
+			ID:     common.NewTestNamespaceFromSeed([]byte("policy signer quorum"), common.NamespaceKeyManager),
+		},
+	}
+	rawPol := cbor.Marshal(sigPol.Policy)
+	sign := func(signer signature.Signer) {
+		sig, err := signature.Sign(signer, PolicySGXSignatureContext, rawPol)
+		require.NoError(err, "Sign")
+		sigPol.Signatures = append(sigPol.Signatures, *sig)
+	}
+
+	require.NoError(sigPol.VerifyQuorum(&ConsensusParameters{}), "any policy should be accepted without policy signers")
+
+	sign(signers[0])
+	require.Error(sigPol.VerifyQuorum(params), "policy below threshold should be rejected")
+	sign(signers[0])
+	require.Error(sigPol.VerifyQuorum(params), "duplicate signatures should not count towards the threshold")
+	sign(TestSigners[0])
+	require.Error(sigPol.VerifyQuorum(params), "signatures from other keys should not count towards the threshold")
+	sign(signers[1])
+	require.NoError(SanityCheckSignedPolicySGX(nil, sigPol), "SanityCheckSignedPolicySGX")
+	require.NoError(sigPol.VerifyQuorum(params), "policy at threshold should be accepted")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/keymanager/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
@@ -26,10 +27,19 @@ import (
 const (
 	retryInterval = 1 * time.Second
 	maxRetries    = 15
+
+	// callTimeout is the time after which a key manager node that does not
+	// reply to a call is considered bad. It must be longer than the time
+	// key manager nodes take to process a call.
+	callTimeout = 10 * time.Second
 )
 
-// ErrKeyManagerNotAvailable is the error when a key manager is not available.
-var ErrKeyManagerNotAvailable = errors.New("keymanager/client: key manager not available")
+var (
+	// ErrKeyManagerNotAvailable is the error when a key manager is not available.
+	ErrKeyManagerNotAvailable = errors.New("keymanager/client: key manager not available")
+
+	errRejectedReply = errors.New("keymanager/client: reply rejected by the runtime")
+)
 
 // Client is a key manager client instance.
 type Client struct {
@@ -45,6 +55,8 @@ type Client struct {
 	committeeNodes  committee.NodeDescriptorWatcher
 	committeeClient committee.Client
 
+	sessions sessionTracker
+
 	logger *logging.Logger
 }
 
@@ -72,17 +84,52 @@ func (c *Client) CallRemote(ctx context.Context, data []byte) ([]byte, error) {
 			c.logger.Warn("no key manager connection for runtime")
 			return ErrKeyManagerNotAvailable
 		}
+		if c.sessions.Begin(data, conn) {
+			// The runtime abandoned its session after the selected key
+			// manager node replied, so it must have rejected the reply.
+			c.failover(errRejectedReply)
+			if conn = c.committeeClient.GetConnection(); conn == nil {
+				return ErrKeyManagerNotAvailable
+			}
+			c.sessions.Begin(data, conn)
+		}
 		client := enclaverpc.NewTransportClient(conn)
 
+		callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+		defer cancel()
+
 		var err error
-		resp, err = client.CallEnclave(ctx, &enclaverpc.CallEnclaveRequest{
+		resp, err = client.CallEnclave(callCtx, &enclaverpc.CallEnclaveRequest{
 			RuntimeID: c.runtime.ID(),
 			Endpoint:  api.EnclaveRPCEndpoint,
 			Payload:   data,
 		})
-		if status.Code(err) == codes.PermissionDenied {
+		c.sessions.Complete(conn, err)
+		switch status.Code(err) {
+		case codes.OK:
+			return nil
+		case codes.PermissionDenied:
 			// Calls can fail around epoch transitions, as the access policy
-			// is being updated, so we must retry.
+			// is being updated, so we must retry. As the selected node may
+			// also be enforcing a stale policy, fail over to the next node.
+			c.failover(err)
+			return err
+		case codes.Unavailable:
+			// The selected key manager node is not reachable (e.g., it has
+			// failed), fail over to the next node and retry.
+			c.logger.Warn("key manager node unavailable, failing over",
+				"err", err,
+			)
+			c.committeeClient.UpdateNodeSelectionPolicy(committee.NodeSelectionFeedback{Bad: err})
+			return err
+		case codes.DeadlineExceeded:
+			if ctx.Err() != nil {
+				return backoff.Permanent(ctx.Err())
+			}
+
+			// The selected key manager node did not reply in time (e.g., it
+			// is withholding replies), fail over to the next node and retry.
+			c.failover(err)
 			return err
 		}
 		// Request failed, communicate that to the node selection policy.
@@ -96,9 +143,50 @@ func (c *Client) CallRemote(ctx context.Context, data []byte) ([]byte, error) {
 	return resp, err
 }
 
+// failover stops using the selected key manager node due to misbehavior and
+// fails over to the next node.
+func (c *Client) failover(reason error) {
+	c.logger.Warn("key manager node misbehaved, failing over",
+		"err", reason,
+		logging.LogEvent, api.LogEventNodeFailover,
+	)
+	c.committeeClient.UpdateNodeSelectionPolicy(committee.NodeSelectionFeedback{Bad: reason})
+}
+
+// watchStatuses subscribes to key manager status updates, retrying until
+// either the subscription succeeds or the client is stopped.
+func (c *Client) watchStatuses() (<-chan *api.AnnotatedStatus, pubsub.ClosableSubscription, error) {
+	var (
+		ch  <-chan *api.AnnotatedStatus
+		sub pubsub.ClosableSubscription
+	)
+	watch := func() (err error) {
+		ch, sub, err = c.backend.WatchStatuses(c.ctx, consensus.HeightLatest)
+		if err != nil {
+			c.logger.Warn("failed to watch key manager statuses, retrying",
+				"err", err,
+			)
+		}
+		return
+	}
+
+	sched := backoff.NewExponentialBackOff()
+	sched.MaxElapsedTime = 0
+	if err := backoff.Retry(watch, backoff.WithContext(sched, c.ctx)); err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
 func (c *Client) worker() {
-	stCh, stSub := c.backend.WatchStatuses()
-	defer stSub.Close()
+	stCh, stSub, err := c.watchStatuses()
+	if err != nil {
+		// The client has been stopped.
+		return
+	}
+	defer func() {
+		stSub.Close()
+	}()
 
 	rtCh, rtSub, err := c.runtime.WatchRegistryDescriptor()
 	if err != nil {
@@ -114,7 +202,17 @@ func (c *Client) worker() {
 		select {
 		case <-c.ctx.Done():
 			return
-		case st := <-stCh:
+		case annSt, ok := <-stCh:
+			if !ok {
+				c.logger.Warn("key manager status subscription closed, resubscribing")
+				stSub.Close()
+				if stCh, stSub, err = c.watchStatuses(); err != nil {
+					return
+				}
+				continue
+			}
+
+			st := annSt.Status
 			// Ignore status updates if key manager is not yet known (is nil) or if the status
 			// update is for a different key manager.
 			if !st.ID.Equal(kmID) {
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	enclaverpc "github.com/oasislabs/oasis-core/go/runtime/enclaverpc/api"
+)
+
+// sessionTracker tracks the EnclaveRPC session used by the runtime to detect
+// key manager nodes whose replies were rejected by the runtime.
+//
+// Replies are encrypted end-to-end, so they can only be checked inside the
+// runtime. The runtime resets its session whenever it fails to process a
+// reply (e.g., because the reply is malformed) and retries in a new session.
+// A new session that follows a session in which the selected node replied
+// without error therefore means that the reply of the node was rejected.
+//
+// Nodes are opaque identifiers (e.g., the connection used to reach a node).
+// This assumes that the runtime uses a single session at a time, which holds
+// for the runtime key manager client.
+type sessionTracker struct {
+	sync.Mutex
+
+	session []byte
+	node    interface{}
+	replied bool
+}
+
+// Begin records that a request in the session of the given raw frame is
+// about to be sent to the given node and returns true iff the previous
+// session was abandoned after the same node replied to it.
+func (t *sessionTracker) Begin(data []byte, node interface{}) bool {
+	var frame enclaverpc.Frame
+	if err := cbor.Unmarshal(data, &frame); err != nil || len(frame.Session) == 0 {
+		return false
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	var rejected bool
+	if !bytes.Equal(t.session, frame.Session) {
+		rejected = t.replied && t.node == node
+		t.session = frame.Session
+		t.replied = false
+	}
+	if t.node != node {
+		t.node = node
+		t.replied = false
+	}
+	return rejected
+}
+
+// Complete records the outcome of the last request sent to the given node.
+func (t *sessionTracker) Complete(node interface{}, err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.node != node {
+		return
+	}
+	t.replied = err == nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	enclaverpc "github.com/oasislabs/oasis-core/go/runtime/enclaverpc/api"
+)
+
+func TestSessionTracker(t *testing.T) {
+	require := require.New(t)
+
+	frame := func(session string) []byte {
+		return cbor.Marshal(&enclaverpc.Frame{
+			Session: []byte(session),
+			Payload: []byte("payload"),
+		})
+	}
+	errCall := errors.New("call failed")
+
+	var tracker sessionTracker
+	require.False(tracker.Begin(frame("session 1"), 1), "first session should not be rejected")
+	tracker.Complete(1, nil)
+	require.False(tracker.Begin(frame("session 1"), 1), "same session should not be rejected")
+	tracker.Complete(1, nil)
+
+	// The runtime abandoned the session after the node replied.
+	require.True(tracker.Begin(frame("session 2"), 1), "reply of the node should be rejected")
+	// The client failed over to a different node.
+	require.False(tracker.Begin(frame("session 2"), 2), "same session should not be rejected")
+	tracker.Complete(2, errCall)
+
+	// The runtime abandoned the session after the call failed.
+	require.False(tracker.Begin(frame("session 3"), 2), "failed call should not be rejected")
+	tracker.Complete(2, nil)
+
+	// The client failed over to a different node mid-session.
+	require.False(tracker.Begin(frame("session 3"), 1), "same session should not be rejected")
+	tracker.Complete(2, nil)
+	require.False(tracker.Begin(frame("session 4"), 2), "session that moved to a different node should not be rejected")
+
+	// Requests that are not frames should be ignored.
+	require.False(tracker.Begin([]byte("garbage"), 2), "invalid frame should be ignored")
+}
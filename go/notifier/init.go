@@ -0,0 +1,124 @@
+package notifier
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+)
+
+const (
+	// CfgWebhookURL configures the generic webhook sink URLs.
+	CfgWebhookURL = "notifier.webhook.url"
+	// CfgSlackWebhookURL configures the Slack incoming webhook sink URLs.
+	CfgSlackWebhookURL = "notifier.slack.webhook_url"
+	// CfgEmailSMTPAddress configures the email sink SMTP server address.
+	CfgEmailSMTPAddress = "notifier.email.smtp.address"
+	// CfgEmailSMTPUsername configures the email sink SMTP username.
+	CfgEmailSMTPUsername = "notifier.email.smtp.username"
+	// CfgEmailSMTPPasswordFile configures the file containing the email sink
+	// SMTP password.
+	CfgEmailSMTPPasswordFile = "notifier.email.smtp.password_file"
+	// CfgEmailFrom configures the email sink sender address.
+	CfgEmailFrom = "notifier.email.from"
+	// CfgEmailTo configures the email sink recipient addresses.
+	CfgEmailTo = "notifier.email.to"
+
+	// CfgPeerCheckInterval configures the consensus peer count check interval.
+	CfgPeerCheckInterval = "notifier.peers.check_interval"
+	// CfgMinPeers configures the consensus peer count alert threshold.
+	CfgMinPeers = "notifier.peers.min"
+	// CfgAttestationExpiryEpochs configures the runtime readiness attestation
+	// expiry alert threshold.
+	CfgAttestationExpiryEpochs = "notifier.attestation.expiry_epochs"
+	// CfgMissedRounds configures the missed rounds alert threshold.
+	CfgMissedRounds = "notifier.missed_rounds"
+)
+
+// Flags has the configuration flags.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+func newSinks() ([]Sink, error) {
+	var sinks []Sink
+	for _, url := range viper.GetStringSlice(CfgWebhookURL) {
+		sinks = append(sinks, NewWebhookSink(url))
+	}
+	for _, url := range viper.GetStringSlice(CfgSlackWebhookURL) {
+		sinks = append(sinks, NewSlackSink(url))
+	}
+
+	if addr := viper.GetString(CfgEmailSMTPAddress); addr != "" {
+		var password string
+		if f := viper.GetString(CfgEmailSMTPPasswordFile); f != "" {
+			b, err := ioutil.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("notifier: failed to read SMTP password: %w", err)
+			}
+			password = strings.TrimSpace(string(b))
+		}
+
+		sink, err := NewEmailSink(
+			addr,
+			viper.GetString(CfgEmailSMTPUsername),
+			password,
+			viper.GetString(CfgEmailFrom),
+			viper.GetStringSlice(CfgEmailTo),
+		)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+// NewFromFlags creates a new node event notifier configured from flags.
+//
+// The notifier is only enabled if at least one sink is configured.
+func NewFromFlags(consensus consensus.Backend, nodeID signature.PublicKey, runtimeIDs []common.Namespace) (*Notifier, error) {
+	sinks, err := newSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		NodeID:                  nodeID,
+		RuntimeIDs:              runtimeIDs,
+		Sinks:                   sinks,
+		PeerCheckInterval:       viper.GetDuration(CfgPeerCheckInterval),
+		MinPeers:                viper.GetInt(CfgMinPeers),
+		AttestationExpiryEpochs: epochtime.EpochTime(viper.GetUint64(CfgAttestationExpiryEpochs)),
+		MissedRounds:            viper.GetUint64(CfgMissedRounds),
+	}
+	if cfg.MinPeers > 0 && cfg.PeerCheckInterval <= 0 {
+		return nil, fmt.Errorf("notifier: invalid peer check interval: %s", cfg.PeerCheckInterval)
+	}
+
+	return New(consensus, cfg), nil
+}
+
+func init() {
+	Flags.StringSlice(CfgWebhookURL, nil, "Webhook URL(s) to POST node event notifications to")
+	Flags.StringSlice(CfgSlackWebhookURL, nil, "Slack incoming webhook URL(s) to post node event notifications to")
+	Flags.String(CfgEmailSMTPAddress, "", "SMTP server address (host:port) for email node event notifications")
+	Flags.String(CfgEmailSMTPUsername, "", "SMTP username for email node event notifications")
+	Flags.String(CfgEmailSMTPPasswordFile, "", "File containing the SMTP password for email node event notifications")
+	Flags.String(CfgEmailFrom, "", "Sender address for email node event notifications")
+	Flags.StringSlice(CfgEmailTo, nil, "Recipient address(es) for email node event notifications")
+
+	Flags.Duration(CfgPeerCheckInterval, 1*time.Minute, "Consensus peer count check interval")
+	Flags.Int(CfgMinPeers, 1, "Notify if the consensus peer count is below this threshold (0 disables)")
+	Flags.Uint64(CfgAttestationExpiryEpochs, 1, "Notify if a runtime readiness attestation expires within this many epochs")
+	Flags.Uint64(CfgMissedRounds, 3, "Notify after this many consecutive missed rounds as a storage committee member (0 disables)")
+
+	_ = viper.BindPFlags(Flags)
+}
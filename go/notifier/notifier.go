@@ -0,0 +1,459 @@
+// Package notifier implements operator notifications for critical node
+// conditions.
+//
+// The notifier follows the consensus and roothash event streams and notifies
+// the configured sinks (webhooks, Slack, email) when a critical condition is
+// detected, and again once the condition has been resolved.
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
+)
+
+const notifyTimeout = 10 * time.Second
+
+// EventKind is the kind of a critical node condition.
+type EventKind string
+
+const (
+	// EventNodeFrozen is the condition of the node being frozen.
+	EventNodeFrozen EventKind = "node_frozen"
+	// EventAttestationExpiry is the condition of a runtime readiness
+	// attestation having expired or being about to expire.
+	EventAttestationExpiry EventKind = "attestation_expiry"
+	// EventMissedRounds is the condition of the node having missed a number
+	// of consecutive runtime rounds as a storage committee member.
+	EventMissedRounds EventKind = "missed_rounds"
+	// EventLowPeerCount is the condition of the number of consensus peers
+	// being below the configured threshold.
+	EventLowPeerCount EventKind = "low_peer_count"
+)
+
+// Event is a notification about a critical node condition.
+type Event struct {
+	// Kind is the kind of the condition.
+	Kind EventKind `json:"kind"`
+	// Resolved is true iff the condition has been resolved.
+	Resolved bool `json:"resolved"`
+	// NodeID is the identifier of the node.
+	NodeID signature.PublicKey `json:"node_id"`
+	// RuntimeID is the identifier of the runtime the condition applies to
+	// (if any).
+	RuntimeID *common.Namespace `json:"runtime_id,omitempty"`
+	// Message is a human readable description of the condition.
+	Message string `json:"message"`
+	// Time is the time at which the condition was detected.
+	Time time.Time `json:"time"`
+}
+
+// Subject returns a short summary of the event.
+func (ev *Event) Subject() string {
+	status := "ALERT"
+	if ev.Resolved {
+		status = "RESOLVED"
+	}
+	return fmt.Sprintf("[%s] %s on node %s", status, ev.Kind, ev.NodeID)
+}
+
+// String returns a string representation of the event.
+func (ev *Event) String() string {
+	return ev.Subject() + ": " + ev.Message
+}
+
+// Config is the notifier configuration.
+type Config struct {
+	// NodeID is the identifier of the monitored node.
+	NodeID signature.PublicKey
+	// RuntimeIDs are the runtimes supported by the monitored node.
+	RuntimeIDs []common.Namespace
+
+	// Sinks are the notification destinations.
+	Sinks []Sink
+
+	// PeerCheckInterval is the interval for checking the consensus peer
+	// count.
+	PeerCheckInterval time.Duration
+	// MinPeers is the consensus peer count below which an alert is raised.
+	// Zero disables the check.
+	MinPeers int
+	// AttestationExpiryEpochs is the number of epochs before a runtime
+	// readiness attestation expires at which an alert is raised.
+	AttestationExpiryEpochs epochtime.EpochTime
+	// MissedRounds is the number of consecutive missed rounds as a storage
+	// committee member at which an alert is raised. Zero disables the check.
+	MissedRounds uint64
+}
+
+// Notifier is the node event notifier service.
+type Notifier struct {
+	sync.Mutex
+
+	cfg       Config
+	consensus consensus.Backend
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	quitCh chan struct{}
+
+	// active is the set of currently active conditions.
+	active map[string]bool
+	// attestedRuntimes is the set of runtimes for which a readiness
+	// attestation has been observed.
+	attestedRuntimes map[common.Namespace]bool
+
+	logger *logging.Logger
+}
+
+// Name returns the service name.
+func (n *Notifier) Name() string {
+	return "node event notifier"
+}
+
+// Enabled returns true iff any notification sinks are configured.
+func (n *Notifier) Enabled() bool {
+	return len(n.cfg.Sinks) > 0
+}
+
+// Start starts the service.
+func (n *Notifier) Start() error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	n.logger.Info("starting node event notifier",
+		"sinks", len(n.cfg.Sinks),
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(2 + len(n.cfg.RuntimeIDs))
+	go func() {
+		defer wg.Done()
+		n.watchConsensusBlocks()
+	}()
+	go func() {
+		defer wg.Done()
+		n.watchPeers()
+	}()
+	for _, runtimeID := range n.cfg.RuntimeIDs {
+		go func(runtimeID common.Namespace) {
+			defer wg.Done()
+			n.watchRuntimeBlocks(runtimeID)
+		}(runtimeID)
+	}
+	go func() {
+		// Individual watchers may terminate early (e.g., if a check is
+		// disabled), only terminate the service once stopped.
+		<-n.ctx.Done()
+		wg.Wait()
+		close(n.quitCh)
+	}()
+
+	return nil
+}
+
+// Stop halts the service.
+func (n *Notifier) Stop() {
+	n.cancel()
+	if !n.Enabled() {
+		close(n.quitCh)
+	}
+}
+
+// Quit returns a channel that will be closed when the service terminates.
+func (n *Notifier) Quit() <-chan struct{} {
+	return n.quitCh
+}
+
+// Cleanup performs the service specific post-termination cleanup.
+func (n *Notifier) Cleanup() {
+}
+
+// setCondition updates the state of the given condition, notifying the sinks
+// on any change.
+func (n *Notifier) setCondition(kind EventKind, runtimeID *common.Namespace, active bool, message string) {
+	key := string(kind)
+	if runtimeID != nil {
+		key += "/" + runtimeID.String()
+	}
+
+	n.Lock()
+	wasActive := n.active[key]
+	if active {
+		n.active[key] = true
+	} else {
+		delete(n.active, key)
+	}
+	n.Unlock()
+
+	if active == wasActive {
+		return
+	}
+
+	n.notify(&Event{
+		Kind:      kind,
+		Resolved:  !active,
+		NodeID:    n.cfg.NodeID,
+		RuntimeID: runtimeID,
+		Message:   message,
+		Time:      time.Now(),
+	})
+}
+
+func (n *Notifier) notify(ev *Event) {
+	n.logger.Warn("notifying node event",
+		"kind", ev.Kind,
+		"resolved", ev.Resolved,
+		"message", ev.Message,
+	)
+
+	ctx, cancel := context.WithTimeout(n.ctx, notifyTimeout)
+	defer cancel()
+
+	for _, sink := range n.cfg.Sinks {
+		if err := sink.Notify(ctx, ev); err != nil {
+			n.logger.Error("failed to deliver notification",
+				"err", err,
+				"sink", sink.Name(),
+				"kind", ev.Kind,
+			)
+		}
+	}
+}
+
+func (n *Notifier) watchConsensusBlocks() {
+	// Wait for consensus to be synced as node status is meaningless before.
+	select {
+	case <-n.consensus.Synced():
+	case <-n.ctx.Done():
+		return
+	}
+
+	ch, sub, err := n.consensus.WatchBlocks(n.ctx)
+	if err != nil {
+		n.logger.Error("failed to watch consensus blocks",
+			"err", err,
+		)
+		return
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case blk, ok := <-ch:
+			if !ok {
+				return
+			}
+			n.checkNodeStatus(blk.Height)
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *Notifier) checkNodeStatus(height int64) {
+	status, err := n.consensus.Registry().GetNodeStatus(n.ctx, &registry.IDQuery{
+		ID:     n.cfg.NodeID,
+		Height: height,
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, registry.ErrNoSuchNode):
+		// Node is not registered (yet).
+		return
+	default:
+		n.logger.Error("failed to query node status",
+			"err", err,
+			"height", height,
+		)
+		return
+	}
+
+	if status.IsFrozen() {
+		n.setCondition(EventNodeFrozen, nil, true, fmt.Sprintf("node is frozen until epoch %d", status.FreezeEndTime))
+	} else {
+		n.setCondition(EventNodeFrozen, nil, false, "node is no longer frozen")
+	}
+
+	epoch, err := n.consensus.EpochTime().GetEpoch(n.ctx, height)
+	if err != nil {
+		n.logger.Error("failed to query epoch",
+			"err", err,
+			"height", height,
+		)
+		return
+	}
+
+	for runtimeID := range status.ReadyRuntimes {
+		n.attestedRuntimes[runtimeID] = true
+	}
+	for runtimeID := range n.attestedRuntimes {
+		runtimeID := runtimeID
+		expiration, ok := status.ReadyRuntimes[runtimeID]
+		switch {
+		case !ok || !status.IsRuntimeReady(runtimeID, epoch):
+			n.setCondition(EventAttestationExpiry, &runtimeID, true, "runtime readiness attestation has expired")
+		case expiration < epoch+n.cfg.AttestationExpiryEpochs:
+			n.setCondition(EventAttestationExpiry, &runtimeID, true, fmt.Sprintf(
+				"runtime readiness attestation expires after epoch %d (current epoch: %d)", expiration, epoch,
+			))
+		default:
+			n.setCondition(EventAttestationExpiry, &runtimeID, false, fmt.Sprintf(
+				"runtime readiness attestation renewed until epoch %d", expiration,
+			))
+		}
+	}
+}
+
+func (n *Notifier) watchPeers() {
+	if n.cfg.MinPeers <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(n.cfg.PeerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-n.ctx.Done():
+			return
+		}
+
+		status, err := n.consensus.GetStatus(n.ctx)
+		if err != nil {
+			n.logger.Error("failed to query consensus status",
+				"err", err,
+			)
+			continue
+		}
+
+		peers := len(status.NodePeers)
+		if peers < n.cfg.MinPeers {
+			n.setCondition(EventLowPeerCount, nil, true, fmt.Sprintf(
+				"consensus peer count %d is below threshold %d", peers, n.cfg.MinPeers,
+			))
+		} else {
+			n.setCondition(EventLowPeerCount, nil, false, fmt.Sprintf("consensus peer count recovered to %d", peers))
+		}
+	}
+}
+
+func (n *Notifier) watchRuntimeBlocks(runtimeID common.Namespace) {
+	if n.cfg.MissedRounds == 0 {
+		return
+	}
+
+	ch, sub, err := n.consensus.RootHash().WatchBlocks(runtimeID)
+	if err != nil {
+		n.logger.Error("failed to watch runtime blocks",
+			"err", err,
+			"runtime_id", runtimeID,
+		)
+		return
+	}
+	defer sub.Close()
+
+	var missed uint64
+	for {
+		select {
+		case blk, ok := <-ch:
+			if !ok {
+				return
+			}
+			if blk.Block.Header.HeaderType != block.Normal {
+				continue
+			}
+
+			member, err := n.isStorageCommitteeMember(runtimeID, blk.Height)
+			if err != nil {
+				n.logger.Error("failed to query committees",
+					"err", err,
+					"runtime_id", runtimeID,
+					"height", blk.Height,
+				)
+				continue
+			}
+
+			switch {
+			case !member:
+				missed = 0
+			case hasSignature(blk.Block.Header.StorageSignatures, n.cfg.NodeID):
+				missed = 0
+			default:
+				missed++
+			}
+
+			if missed >= n.cfg.MissedRounds {
+				n.setCondition(EventMissedRounds, &runtimeID, true, fmt.Sprintf(
+					"storage committee member missed %d consecutive rounds (last round: %d)", missed, blk.Block.Header.Round,
+				))
+			} else if missed == 0 {
+				n.setCondition(EventMissedRounds, &runtimeID, false, fmt.Sprintf(
+					"node participated in round %d", blk.Block.Header.Round,
+				))
+			}
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *Notifier) isStorageCommitteeMember(runtimeID common.Namespace, height int64) (bool, error) {
+	committees, err := n.consensus.Scheduler().GetCommittees(n.ctx, &scheduler.GetCommitteesRequest{
+		RuntimeID: runtimeID,
+		Height:    height,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, committee := range committees {
+		if committee.Kind != scheduler.KindStorage {
+			continue
+		}
+		for _, member := range committee.Members {
+			if member.PublicKey.Equal(n.cfg.NodeID) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func hasSignature(sigs []signature.Signature, id signature.PublicKey) bool {
+	for _, sig := range sigs {
+		if sig.PublicKey.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// New creates a new node event notifier.
+func New(consensus consensus.Backend, cfg *Config) *Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Notifier{
+		cfg:              *cfg,
+		consensus:        consensus,
+		ctx:              ctx,
+		cancel:           cancel,
+		quitCh:           make(chan struct{}),
+		active:           make(map[string]bool),
+		attestedRuntimes: make(map[common.Namespace]bool),
+		logger:           logging.GetLogger("notifier"),
+	}
+}
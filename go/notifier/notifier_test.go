@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+type testSink struct {
+	sync.Mutex
+
+	events []*Event
+}
+
+func (s *testSink) Name() string {
+	return "test"
+}
+
+func (s *testSink) Notify(ctx context.Context, ev *Event) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func TestSetCondition(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := memorySigner.NewTestSigner("notifier test node").Public()
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("notifier test runtime"), 0)
+	sink := &testSink{}
+	n := New(nil, &Config{
+		NodeID: nodeID,
+		Sinks:  []Sink{sink},
+	})
+	require.True(n.Enabled(), "notifier with sinks should be enabled")
+
+	n.setCondition(EventNodeFrozen, nil, false, "not frozen")
+	require.Empty(sink.events, "inactive condition should not notify")
+
+	n.setCondition(EventNodeFrozen, nil, true, "frozen")
+	n.setCondition(EventNodeFrozen, nil, true, "still frozen")
+	require.Len(sink.events, 1, "condition should only notify once while active")
+	require.Equal(EventNodeFrozen, sink.events[0].Kind)
+	require.False(sink.events[0].Resolved)
+	require.Equal(nodeID, sink.events[0].NodeID)
+	require.Equal("frozen", sink.events[0].Message)
+
+	// Conditions for different runtimes are independent.
+	n.setCondition(EventMissedRounds, &runtimeID, true, "missed rounds")
+	require.Len(sink.events, 2, "condition for a runtime should notify")
+	require.Equal(runtimeID, *sink.events[1].RuntimeID)
+
+	n.setCondition(EventNodeFrozen, nil, false, "unfrozen")
+	require.Len(sink.events, 3, "resolved condition should notify")
+	require.True(sink.events[2].Resolved)
+	require.Equal("unfrozen", sink.events[2].Message)
+
+	n.setCondition(EventNodeFrozen, nil, true, "frozen again")
+	require.Len(sink.events, 4, "reactivated condition should notify")
+
+	require.False(New(nil, &Config{}).Enabled(), "notifier without sinks should be disabled")
+}
+
+func TestWebhookSinks(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		bodies   [][]byte
+		failNext bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(http.MethodPost, r.Method)
+		require.Equal("application/json", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(err, "ReadAll")
+		bodies = append(bodies, body)
+		if failNext {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	ev := &Event{
+		Kind:    EventLowPeerCount,
+		NodeID:  memorySigner.NewTestSigner("notifier test node").Public(),
+		Message: "consensus peer count 0 is below threshold 1",
+	}
+	ctx := context.Background()
+
+	err := NewWebhookSink(srv.URL).Notify(ctx, ev)
+	require.NoError(err, "webhook Notify")
+	var decEv Event
+	require.NoError(json.Unmarshal(bodies[0], &decEv), "Unmarshal")
+	require.Equal(ev.Kind, decEv.Kind)
+	require.Equal(ev.NodeID, decEv.NodeID)
+	require.Equal(ev.Message, decEv.Message)
+
+	err = NewSlackSink(srv.URL).Notify(ctx, ev)
+	require.NoError(err, "slack Notify")
+	var msg struct {
+		Text string `json:"text"`
+	}
+	require.NoError(json.Unmarshal(bodies[1], &msg), "Unmarshal")
+	require.Equal(ev.String(), msg.Text)
+
+	failNext = true
+	err = NewWebhookSink(srv.URL).Notify(ctx, ev)
+	require.Error(err, "Notify should fail on non-2xx responses")
+}
+
+func TestNewEmailSink(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewEmailSink("", "", "", "node@example.com", []string{"ops@example.com"})
+	require.Error(err, "email sink without a server should fail")
+	_, err = NewEmailSink("smtp.example.com:587", "", "", "node@example.com", nil)
+	require.Error(err, "email sink without recipients should fail")
+	_, err = NewEmailSink("smtp.example.com", "user", "pass", "node@example.com", []string{"ops@example.com"})
+	require.Error(err, "email sink with authentication requires a port")
+	_, err = NewEmailSink("smtp.example.com:587", "user", "pass", "node@example.com", []string{"ops@example.com"})
+	require.NoError(err, "NewEmailSink")
+}
@@ -0,0 +1,154 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+)
+
+// Sink is a notification destination.
+type Sink interface {
+	// Name returns the sink name.
+	Name() string
+
+	// Notify delivers the event to the sink.
+	Notify(ctx context.Context, ev *Event) error
+}
+
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Notify(ctx context.Context, ev *Event) error {
+	return postJSON(ctx, s.client, s.url, ev)
+}
+
+// NewWebhookSink creates a new sink that POSTs JSON-serialized events to
+// the given URL.
+func NewWebhookSink(url string) Sink {
+	return &webhookSink{
+		url:    url,
+		client: http.DefaultClient,
+	}
+}
+
+type slackSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *slackSink) Name() string {
+	return "slack"
+}
+
+func (s *slackSink) Notify(ctx context.Context, ev *Event) error {
+	msg := struct {
+		Text string `json:"text"`
+	}{
+		Text: ev.String(),
+	}
+	return postJSON(ctx, s.client, s.url, &msg)
+}
+
+// NewSlackSink creates a new sink that posts events to the given Slack
+// incoming webhook URL.
+func NewSlackSink(url string) Sink {
+	return &slackSink{
+		url:    url,
+		client: http.DefaultClient,
+	}
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notifier: unexpected response status: %s", resp.Status)
+	}
+	return nil
+}
+
+type emailSink struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+func (s *emailSink) Name() string {
+	return "email"
+}
+
+func (s *emailSink) Notify(ctx context.Context, ev *Event) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", ev.Subject())
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n")
+	fmt.Fprintf(&msg, "\r\n%s\r\n", ev.String())
+
+	// The SMTP client does not support contexts, so run it in the
+	// background to at least return early on cancellation.
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(s.addr, s.auth, s.from, s.to, []byte(msg.String()))
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewEmailSink creates a new sink that emails events to the given recipients
+// via the given SMTP server (host:port). If username is non-empty, PLAIN
+// authentication is used.
+func NewEmailSink(addr, username, password, from string, to []string) (Sink, error) {
+	if addr == "" || from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("notifier: email sink requires a server, sender and recipients")
+	}
+
+	s := &emailSink{
+		addr: addr,
+		from: from,
+		to:   to,
+	}
+	if username != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("notifier: malformed SMTP server address: %w", err)
+		}
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s, nil
+}
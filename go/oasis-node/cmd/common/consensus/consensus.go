@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	genesisAPI "github.com/oasislabs/oasis-core/go/genesis/api"
 	genesisFile "github.com/oasislabs/oasis-core/go/genesis/file"
@@ -28,8 +29,20 @@ const (
 	// CfgTxFeeGas configures the maximum gas limit.
 	CfgTxFeeGas = "transaction.fee.gas"
 
+	// CfgTxNotValidAfter configures the consensus height after which the
+	// transaction is no longer valid.
+	CfgTxNotValidAfter = "transaction.not_valid_after"
+
+	// CfgTxNotValidAfterEpoch configures the epoch after which the
+	// transaction is no longer valid.
+	CfgTxNotValidAfterEpoch = "transaction.not_valid_after_epoch"
+
 	// CfgTxFile configures the filename for the transaction.
 	CfgTxFile = "transaction.file"
+
+	// CfgTxUnsigned configures whether the transaction should be saved
+	// without signing it, so that it can be signed offline.
+	CfgTxUnsigned = "transaction.unsigned"
 )
 
 var (
@@ -75,54 +88,87 @@ func InitGenesis() *genesisAPI.Document {
 func GetTxNonceAndFee() (uint64, *transaction.Fee) {
 	var fee transaction.Fee
 	nonce := viper.GetUint64(CfgTxNonce)
-	if err := fee.Amount.UnmarshalText([]byte(viper.GetString(CfgTxFeeAmount))); err != nil {
+	amount, err := quantity.ParseDecimal(viper.GetString(CfgTxFeeAmount), 0)
+	if err != nil {
 		logger.Error("failed to parse fee amount",
 			"err", err,
 		)
 		os.Exit(1)
 	}
+	fee.Amount = *amount
 	fee.Gas = transaction.Gas(viper.GetUint64(CfgTxFeeGas))
 	return nonce, &fee
 }
 
+// SignAndSaveTx signs the transaction with the entity signer and saves the
+// signed transaction to the transaction file.
+//
+// In case CfgTxUnsigned is set, the transaction is saved without signing it.
 func SignAndSaveTx(tx *transaction.Transaction) {
-	entityDir, err := cmdSigner.CLIDirOrPwd()
+	tx.NotValidAfter = viper.GetUint64(CfgTxNotValidAfter)
+	tx.NotValidAfterEpoch = viper.GetUint64(CfgTxNotValidAfterEpoch)
+
+	if viper.GetBool(CfgTxUnsigned) {
+		rawTx, err := json.Marshal(tx)
+		if err != nil {
+			logger.Error("failed to marshal transaction",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		if err = ioutil.WriteFile(viper.GetString(CfgTxFile), rawTx, 0600); err != nil {
+			logger.Error("failed to save transaction",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
+	sigTx := SignTx(tx)
+
+	rawTx, err := json.Marshal(sigTx)
 	if err != nil {
-		logger.Error("failed to retrieve signer dir",
+		logger.Error("failed to marshal transaction",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	_, signer, err := cmdCommon.LoadEntity(cmdSigner.Backend(), entityDir)
-	if err != nil {
-		logger.Error("failed to load account entity",
+	if err = ioutil.WriteFile(viper.GetString(CfgTxFile), rawTx, 0600); err != nil {
+		logger.Error("failed to save transaction",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	defer signer.Reset()
+}
 
-	sigTx, err := transaction.Sign(signer, tx)
+// SignTx signs the transaction with the entity signer.
+func SignTx(tx *transaction.Transaction) *transaction.SignedTransaction {
+	entityDir, err := cmdSigner.CLIDirOrPwd()
 	if err != nil {
-		logger.Error("failed to sign transaction",
+		logger.Error("failed to retrieve signer dir",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-
-	rawTx, err := json.Marshal(sigTx)
+	_, signer, err := cmdCommon.LoadEntity(cmdSigner.Backend(), entityDir)
 	if err != nil {
-		logger.Error("failed to marshal transaction",
+		logger.Error("failed to load account entity",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	if err = ioutil.WriteFile(viper.GetString(CfgTxFile), rawTx, 0600); err != nil {
-		logger.Error("failed to save transaction",
+	defer signer.Reset()
+
+	sigTx, err := transaction.Sign(signer, tx)
+	if err != nil {
+		logger.Error("failed to sign transaction",
 			"err", err,
 		)
 		os.Exit(1)
 	}
+
+	return sigTx
 }
 
 func init() {
@@ -132,6 +178,9 @@ func init() {
 	TxFlags.Uint64(CfgTxNonce, 0, "nonce of the signing account")
 	TxFlags.Uint64(CfgTxFeeAmount, 0, "transaction fee in tokens")
 	TxFlags.String(CfgTxFeeGas, "0", "maximum transaction gas limit")
+	TxFlags.Uint64(CfgTxNotValidAfter, 0, "consensus height after which the transaction is no longer valid (0 means it never expires)")
+	TxFlags.Uint64(CfgTxNotValidAfterEpoch, 0, "epoch after which the transaction is no longer valid (0 means it never expires)")
+	TxFlags.Bool(CfgTxUnsigned, false, "save the transaction without signing it (sign it using consensus sign_tx)")
 	_ = viper.BindPFlags(TxFlags)
 	TxFlags.AddFlagSet(TxFileFlags)
 	TxFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
@@ -0,0 +1,119 @@
+// Package gateway implements the JSON/HTTP gateway service.
+package gateway
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/grpc/gateway"
+	"github.com/oasislabs/oasis-core/go/common/service"
+	cmdGrpc "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/grpc"
+)
+
+// CfgGatewayBind configures the JSON gateway listen address.
+const CfgGatewayBind = "grpc.gateway.bind"
+
+// Flags has the flags used by the JSON gateway service.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+type gatewayService struct {
+	service.BaseBackgroundService
+
+	address string
+
+	conn     *grpc.ClientConn
+	listener net.Listener
+	server   *http.Server
+
+	ctx   context.Context
+	errCh chan error
+}
+
+func (g *gatewayService) Start() error {
+	if g.address == "" {
+		return nil
+	}
+
+	g.Logger.Info("JSON gateway HTTP endpoint is enabled",
+		"address", g.address,
+	)
+
+	path, err := cmdGrpc.LocalSocketPath()
+	if err != nil {
+		return err
+	}
+	conn, err := cmnGrpc.Dial("unix:"+path, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", g.address)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	g.conn = conn
+	g.listener = listener
+	g.server = &http.Server{Handler: gateway.New(conn)}
+
+	go func() {
+		if err := g.server.Serve(g.listener); err != nil {
+			g.BaseBackgroundService.Stop()
+			g.errCh <- err
+		}
+	}()
+
+	return nil
+}
+
+func (g *gatewayService) Stop() {
+	if g.server != nil {
+		select {
+		case err := <-g.errCh:
+			if err != nil {
+				g.Logger.Error("JSON gateway server terminated uncleanly",
+					"err", err,
+				)
+			}
+		default:
+			_ = g.server.Shutdown(g.ctx)
+		}
+		g.server = nil
+	}
+}
+
+func (g *gatewayService) Cleanup() {
+	if g.listener != nil {
+		_ = g.listener.Close()
+		g.listener = nil
+	}
+	if g.conn != nil {
+		_ = g.conn.Close()
+		g.conn = nil
+	}
+}
+
+// New constructs a new JSON gateway service.
+func New(ctx context.Context) (service.BackgroundService, error) {
+	address := viper.GetString(CfgGatewayBind)
+
+	return &gatewayService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("gateway"),
+		address:               address,
+		ctx:                   ctx,
+		errCh:                 make(chan error),
+	}, nil
+}
+
+func init() {
+	Flags.String(CfgGatewayBind, "", "enable the JSON gateway for read-only queries at given address")
+
+	_ = viper.BindPFlags(Flags)
+}
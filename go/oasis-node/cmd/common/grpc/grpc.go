@@ -29,6 +29,8 @@ const (
 	CfgWait = "wait"
 	// CfgDebugGrpcInternalSocketPath sets custom internal socket path.
 	CfgDebugGrpcInternalSocketPath = "debug.grpc.internal.socket_path"
+	// CfgServerReflection enables the gRPC reflection service on the internal server.
+	CfgServerReflection = "grpc.reflection.enabled"
 
 	// LocalSocketFilename is the filename of the unix socket in node datadir.
 	LocalSocketFilename = "internal.sock"
@@ -69,14 +71,9 @@ func NewServerTCP(cert *tls.Certificate, installWrapper bool) (*cmnGrpc.Server,
 // This internally takes a snapshot of the current global tracer, so
 // make sure you initialize the global tracer before calling this.
 func NewServerLocal(installWrapper bool) (*cmnGrpc.Server, error) {
-	dataDir := common.DataDir()
-	if dataDir == "" {
-		return nil, errors.New("data directory must be set")
-	}
-	path := filepath.Join(dataDir, LocalSocketFilename)
-	if viper.IsSet(CfgDebugGrpcInternalSocketPath) && flags.DebugDontBlameOasis() {
-		logger.Info("overriding internal socket path", "path", viper.GetString(CfgDebugGrpcInternalSocketPath))
-		path = viper.GetString(CfgDebugGrpcInternalSocketPath)
+	path, err := LocalSocketPath()
+	if err != nil {
+		return nil, err
 	}
 
 	config := &cmnGrpc.ServerConfig{
@@ -85,7 +82,30 @@ func NewServerLocal(installWrapper bool) (*cmnGrpc.Server, error) {
 		InstallWrapper: installWrapper,
 	}
 
-	return cmnGrpc.NewServer(config)
+	srv, err := cmnGrpc.NewServer(config)
+	if err != nil {
+		return nil, err
+	}
+	if viper.GetBool(CfgServerReflection) {
+		cmnGrpc.RegisterReflectionService(srv.Server())
+	}
+
+	return srv, nil
+}
+
+// LocalSocketPath returns the path of the AF_LOCAL socket used by the local
+// gRPC server.
+func LocalSocketPath() (string, error) {
+	dataDir := common.DataDir()
+	if dataDir == "" {
+		return "", errors.New("data directory must be set")
+	}
+	path := filepath.Join(dataDir, LocalSocketFilename)
+	if viper.IsSet(CfgDebugGrpcInternalSocketPath) && flags.DebugDontBlameOasis() {
+		logger.Info("overriding internal socket path", "path", viper.GetString(CfgDebugGrpcInternalSocketPath))
+		path = viper.GetString(CfgDebugGrpcInternalSocketPath)
+	}
+	return path, nil
 }
 
 func NewClient(cmd *cobra.Command) (*grpc.ClientConn, error) {
@@ -119,6 +139,7 @@ func init() {
 
 	ServerLocalFlags.String(CfgDebugGrpcInternalSocketPath, "", "use custom internal unix socket path")
 	_ = ServerLocalFlags.MarkHidden(CfgDebugGrpcInternalSocketPath)
+	ServerLocalFlags.Bool(CfgServerReflection, false, "enable the gRPC reflection service")
 	_ = viper.BindPFlags(ServerLocalFlags)
 	ServerLocalFlags.AddFlagSet(cmnGrpc.Flags)
 
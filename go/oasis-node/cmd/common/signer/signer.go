@@ -14,6 +14,7 @@ import (
 	fileSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/file"
 	ledgerSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/ledger"
 	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	pkcs11Signer "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/pkcs11"
 	remoteSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/remote"
 	"github.com/oasislabs/oasis-core/go/common/crypto/tls"
 )
@@ -37,6 +38,10 @@ const (
 	cfgSignerRemoteClientKey  = "signer.remote.client.key"
 	cfgSignerRemoteServerCert = "signer.remote.server.certificate"
 
+	cfgSignerPKCS11Module = "signer.pkcs11.module"
+	cfgSignerPKCS11Token  = "signer.pkcs11.token"
+	cfgSignerPKCS11PIN    = "signer.pkcs11.pin"
+
 	cfgSignerCompositeBackends = "signer.composite.backends"
 )
 
@@ -128,6 +133,13 @@ func doNewFactory(signerBackend string, signerDir string, roles ...signature.Sig
 		config.ServerCertificate = serverCert
 
 		return remoteSigner.NewFactory(config, roles...)
+	case pkcs11Signer.SignerName:
+		config := &pkcs11Signer.FactoryConfig{
+			Module:     viper.GetString(cfgSignerPKCS11Module),
+			TokenLabel: viper.GetString(cfgSignerPKCS11Token),
+			PIN:        viper.GetString(cfgSignerPKCS11PIN),
+		}
+		return pkcs11Signer.NewFactory(config, roles...)
 	default:
 		return nil, fmt.Errorf("unsupported signer backend: %s", signerBackend)
 	}
@@ -176,13 +188,16 @@ func doNewComposite(signerDir string, roles ...signature.SignerRole) (signature.
 }
 
 func init() {
-	Flags.StringP(CfgSigner, "s", "file", "signer backend [file, ledger, remote, composite]")
+	Flags.StringP(CfgSigner, "s", "file", "signer backend [file, ledger, remote, pkcs11, composite]")
 	Flags.String(cfgSignerLedgerAddress, "", "Ledger signer: select Ledger device based on this specified address. If blank, any available Ledger device will be connected to.")
 	Flags.Uint32(cfgSignerLedgerIndex, 0, "Ledger signer: address index used to derive address on Ledger device")
 	Flags.String(cfgSignerRemoteAddress, "", "remote signer server address")
 	Flags.String(cfgSignerRemoteClientCert, "", "remote signer client certificate path")
 	Flags.String(cfgSignerRemoteClientKey, "", "remote signer client certificate key path")
 	Flags.String(cfgSignerRemoteServerCert, "", "remote signer server certificate path")
+	Flags.String(cfgSignerPKCS11Module, "", "PKCS#11 signer: path to the PKCS#11 module (shared library)")
+	Flags.String(cfgSignerPKCS11Token, "", "PKCS#11 signer: label of the token holding the keys")
+	Flags.String(cfgSignerPKCS11PIN, "", "PKCS#11 signer: user PIN of the token")
 	Flags.String(cfgSignerCompositeBackends, "", "composite signer backends")
 
 	_ = viper.BindPFlags(Flags)
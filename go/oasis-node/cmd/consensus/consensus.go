@@ -4,13 +4,16 @@ package consensus
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
@@ -18,9 +21,15 @@ import (
 	cmdConsensus "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/consensus"
 	cmdFlags "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/grpc"
+	cmdSigner "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/signer"
 )
 
+// CfgTxUnsignedFile configures the filename for the unsigned transaction.
+const CfgTxUnsignedFile = "transaction.unsigned_file"
+
 var (
+	signTxFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
 	consensusCmd = &cobra.Command{
 		Use:   "consensus",
 		Short: "consensus backend commands",
@@ -32,12 +41,33 @@ var (
 		Run:   doSubmitTx,
 	}
 
+	validateTxCmd = &cobra.Command{
+		Use:     "validate_tx",
+		Aliases: []string{"validate-tx"},
+		Short:   "Pre-validate a pre-signed transaction against the latest state",
+		Run:     doValidateTx,
+	}
+
 	showTxCmd = &cobra.Command{
 		Use:   "show_tx",
 		Short: "Show the content a pre-signed transaction",
 		Run:   doShowTx,
 	}
 
+	signTxCmd = &cobra.Command{
+		Use:     "sign_tx",
+		Aliases: []string{"sign-tx"},
+		Short:   "Sign an unsigned transaction (e.g., on an offline machine)",
+		Run:     doSignTx,
+	}
+
+	broadcastTxCmd = &cobra.Command{
+		Use:     "broadcast_tx",
+		Aliases: []string{"broadcast-tx"},
+		Short:   "Broadcast a signed CBOR-serialized transaction",
+		Run:     doBroadcastTx,
+	}
+
 	logger = logging.GetLogger("cmd/consensus")
 )
 
@@ -54,6 +84,8 @@ func doConnect(cmd *cobra.Command) (*grpc.ClientConn, consensus.ClientBackend) {
 	return conn, client
 }
 
+// loadTx loads the signed transaction from the transaction file, which is
+// either JSON or CBOR-serialized.
 func loadTx() *transaction.SignedTransaction {
 	rawTx, err := ioutil.ReadFile(viper.GetString(cmdConsensus.CfgTxFile))
 	if err != nil {
@@ -64,14 +96,93 @@ func loadTx() *transaction.SignedTransaction {
 	}
 
 	var tx transaction.SignedTransaction
+	if jsonErr := json.Unmarshal(rawTx, &tx); jsonErr != nil {
+		if err = cbor.Unmarshal(rawTx, &tx); err != nil {
+			logger.Error("failed to parse serialized transaction",
+				"err", jsonErr,
+			)
+			os.Exit(1)
+		}
+	}
+
+	return &tx
+}
+
+func doSignTx(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	rawTx, err := ioutil.ReadFile(viper.GetString(CfgTxUnsignedFile))
+	if err != nil {
+		logger.Error("failed to read unsigned transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var tx transaction.Transaction
 	if err = json.Unmarshal(rawTx, &tx); err != nil {
-		logger.Error("failed to parse serialized transaction",
+		logger.Error("failed to parse unsigned transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = tx.SanityCheck(); err != nil {
+		logger.Error("invalid unsigned transaction",
 			"err", err,
 		)
 		os.Exit(1)
 	}
 
-	return &tx
+	// Show what is being signed so it can be reviewed.
+	tx.PrettyPrint("", os.Stdout)
+
+	sigTx := cmdConsensus.SignTx(&tx)
+	if err = ioutil.WriteFile(viper.GetString(cmdConsensus.CfgTxFile), cbor.Marshal(sigTx), 0600); err != nil {
+		logger.Error("failed to save signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doBroadcastTx(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	rawTx, err := ioutil.ReadFile(viper.GetString(cmdConsensus.CfgTxFile))
+	if err != nil {
+		logger.Error("failed to read signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var sigTx transaction.SignedTransaction
+	if err = cbor.Unmarshal(rawTx, &sigTx); err != nil {
+		logger.Error("failed to parse signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	if err = client.SubmitTx(context.Background(), &sigTx); err != nil {
+		logger.Error("failed to submit transaction",
+			"err", err,
+			"tx_hash", sigTx.Hash(),
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Transaction %s included in a block\n", sigTx.Hash())
 }
 
 func doSubmitTx(cmd *cobra.Command, args []string) {
@@ -92,6 +203,38 @@ func doSubmitTx(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doValidateTx(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	tx := loadTx()
+
+	result, err := client.ValidateTx(context.Background(), tx)
+	if err != nil {
+		logger.Error("failed to validate transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	raw, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		logger.Error("failed to marshal validation result into JSON",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(raw))
+
+	if !result.IsValid() {
+		os.Exit(1)
+	}
+}
+
 func doShowTx(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -107,7 +250,10 @@ func doShowTx(cmd *cobra.Command, args []string) {
 func Register(parentCmd *cobra.Command) {
 	for _, v := range []*cobra.Command{
 		submitTxCmd,
+		validateTxCmd,
 		showTxCmd,
+		signTxCmd,
+		broadcastTxCmd,
 	} {
 		consensusCmd.AddCommand(v)
 	}
@@ -115,8 +261,26 @@ func Register(parentCmd *cobra.Command) {
 	submitTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
 	submitTxCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
+	validateTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
+	validateTxCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
 	showTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
 	showTxCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
 
+	signTxCmd.Flags().AddFlagSet(signTxFlags)
+	signTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
+	signTxCmd.Flags().AddFlagSet(cmdFlags.DebugTestEntityFlags)
+	signTxCmd.Flags().AddFlagSet(cmdSigner.Flags)
+	signTxCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
+	signTxCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
+
+	broadcastTxCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
+	broadcastTxCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
 	parentCmd.AddCommand(consensusCmd)
 }
+
+func init() {
+	signTxFlags.String(CfgTxUnsignedFile, "", "path to the unsigned transaction")
+	_ = viper.BindPFlags(signTxFlags)
+}
@@ -57,6 +57,18 @@ var (
 		Run:   doCancelUpgrade,
 	}
 
+	controlReloadRuntimesCmd = &cobra.Command{
+		Use:   "reload-runtimes",
+		Short: "reload the runtime host configuration and re-provision changed runtimes",
+		Run:   doReloadRuntimes,
+	}
+
+	controlStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "show node status, including any pending upgrade",
+		Run:   doStatus,
+	}
+
 	logger = logging.GetLogger("cmd/control")
 )
 
@@ -177,6 +189,41 @@ func doCancelUpgrade(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doReloadRuntimes(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	err := client.ReloadRuntimes(context.Background())
+	if err != nil {
+		logger.Error("failed to reload runtimes",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doStatus(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	status, err := client.GetStatus(context.Background())
+	if err != nil {
+		logger.Error("failed to query node status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyStatus, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		logger.Error("failed to format node status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyStatus))
+}
+
 // Register registers the client sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
@@ -188,5 +235,7 @@ func Register(parentCmd *cobra.Command) {
 	controlCmd.AddCommand(controlShutdownCmd)
 	controlCmd.AddCommand(controlUpgradeBinaryCmd)
 	controlCmd.AddCommand(controlCancelUpgradeCmd)
+	controlCmd.AddCommand(controlReloadRuntimesCmd)
+	controlCmd.AddCommand(controlStatusCmd)
 	parentCmd.AddCommand(controlCmd)
 }
@@ -3,13 +3,14 @@ package byzantine
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
-	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/sgx/ias"
@@ -19,7 +20,6 @@ import (
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/grpc"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/metrics"
-	"github.com/oasislabs/oasis-core/go/roothash/api/commitment"
 	"github.com/oasislabs/oasis-core/go/runtime/transaction"
 	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
 	"github.com/oasislabs/oasis-core/go/worker/common/p2p"
@@ -33,6 +33,8 @@ const (
 	CfgVersionFakeEnclaveID = "runtime.version.fake_enclave_id"
 	// CfgActivationEpoch configures the epoch at which the Byzantine node activates.
 	CfgActivationEpoch = "activation_epoch"
+	// CfgScenarioFile configures the path to the scenario file used by the scenario command.
+	CfgScenarioFile = "scenario.file"
 )
 
 var (
@@ -72,6 +74,11 @@ var (
 		Short: "act as a merge worker that registers and doesn't do any work",
 		Run:   doMergeStraggler,
 	}
+	scenarioCmd = &cobra.Command{
+		Use:   "scenario",
+		Short: "act as a worker that injects the faults described by a scenario file",
+		Run:   doScenario,
+	}
 )
 
 func activateCommonConfig(cmd *cobra.Command, args []string) {
@@ -625,37 +632,13 @@ func doMergeWrong(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
 	// Process the merge wrong.
-	origCommitments := mbc.commitments
-	var emptyRoot hash.Hash
-	emptyRoot.Empty()
-	mbc.commitments = []*commitment.OpenExecutorCommitment{
-		&commitment.OpenExecutorCommitment{
-			Body: &commitment.ComputeBody{
-				Header: commitment.ComputeResultsHeader{
-					IORoot:    emptyRoot,
-					StateRoot: mbc.currentBlock.Header.StateRoot,
-				},
-			},
-		},
-	}
-
-	if err = mbc.process(ctx, hnss); err != nil {
+	if err = mbc.processWrong(ctx, hnss); err != nil {
 		panic(fmt.Sprintf("merge process failed: %+v", err))
 	}
 	logger.Debug("merge wrong: processed",
 		"new_block", mbc.newBlock,
 	)
 
-	mbc.commitments = origCommitments
-
-	// Sanity check the merge results.
-	if mbc.newBlock.Header.IORoot != emptyRoot {
-		panic(fmt.Sprintf("merge of empty IO trees should be empty. got %s, expected %s", mbc.newBlock.Header.IORoot, emptyRoot))
-	}
-	if mbc.newBlock.Header.StateRoot != mbc.currentBlock.Header.StateRoot {
-		panic(fmt.Sprintf("merge of identical state trees should be the same. got %s, expected %s", mbc.newBlock.Header.StateRoot, mbc.currentBlock.Header.StateRoot))
-	}
-
 	if err = mbc.createCommitment(defaultIdentity); err != nil {
 		panic(fmt.Sprintf("merge create commitment failed: %+v", err))
 	}
@@ -742,6 +725,282 @@ func doMergeStraggler(cmd *cobra.Command, args []string) {
 	logger.Debug("merge straggler: bailing")
 }
 
+func doScenario(cmd *cobra.Command, args []string) {
+	if err := common.Init(); err != nil {
+		common.EarlyLogAndExit(err)
+	}
+
+	sc, err := LoadScenario(viper.GetString(CfgScenarioFile))
+	if err != nil {
+		common.EarlyLogAndExit(err)
+	}
+
+	defaultIdentity, err := initDefaultIdentity(common.DataDir())
+	if err != nil {
+		panic(fmt.Sprintf("init default identity failed: %+v", err))
+	}
+
+	ht := newHonestTendermint()
+	if err = ht.start(defaultIdentity, common.DataDir()); err != nil {
+		panic(fmt.Sprintf("honest Tendermint start failed: %+v", err))
+	}
+	defer func() {
+		if err1 := ht.stop(); err1 != nil {
+			panic(fmt.Sprintf("honest Tendermint stop failed: %+v", err1))
+		}
+	}()
+
+	ph := newP2PHandle()
+	if err = ph.start(defaultIdentity, defaultRuntimeID); err != nil {
+		panic(fmt.Sprintf("P2P start failed: %+v", err))
+	}
+	defer func() {
+		if err1 := ph.stop(); err1 != nil {
+			panic(fmt.Sprintf("P2P stop failed: %+v", err1))
+		}
+	}()
+
+	if err = epochtimeWaitForEpoch(ht.service, epochtime.EpochTime(viper.GetUint64(CfgActivationEpoch))); err != nil {
+		panic(fmt.Sprintf("epochtimeWaitForEpoch: %+v", err))
+	}
+
+	if sc.Role == RoleKeyManager {
+		if err = scenarioKeyManager(context.Background(), sc, ht, defaultIdentity, common.DataDir()); err != nil {
+			panic(fmt.Sprintf("scenario key manager failed: %+v", err))
+		}
+		return
+	}
+
+	var capabilities *node.Capabilities
+	var rak signature.Signer
+	if sc.Role == RoleExecutor && viper.GetBool(CfgFakeSGX) {
+		if rak, capabilities, err = initFakeCapabilitiesSGX(); err != nil {
+			panic(fmt.Sprintf("initFakeCapabilitiesSGX: %+v", err))
+		}
+	}
+	if err = registryRegisterNode(ht.service, defaultIdentity, common.DataDir(), fakeAddresses, ph.service.Addresses(), defaultRuntimeID, capabilities, node.RoleComputeWorker); err != nil {
+		panic(fmt.Sprintf("registryRegisterNode: %+v", err))
+	}
+
+	electionHeight, err := schedulerNextElectionHeight(ht.service, scheduler.KindComputeExecutor)
+	if err != nil {
+		panic(fmt.Sprintf("scheduler next election height failed: %+v", err))
+	}
+	committees := make(map[scheduler.CommitteeKind]*scheduler.Committee)
+	for _, kind := range []scheduler.CommitteeKind{
+		scheduler.KindComputeExecutor,
+		scheduler.KindComputeMerge,
+		scheduler.KindComputeTxnScheduler,
+		scheduler.KindStorage,
+	} {
+		if committees[kind], err = schedulerGetCommittee(ht, electionHeight, kind, defaultRuntimeID); err != nil {
+			panic(fmt.Sprintf("scheduler get committee %s failed: %+v", kind, err))
+		}
+	}
+
+	// Make sure we are only scheduled as a worker in the committee of our role.
+	ourKind := scheduler.KindComputeExecutor
+	if sc.Role == RoleMerge {
+		ourKind = scheduler.KindComputeMerge
+	}
+	for _, kind := range []scheduler.CommitteeKind{
+		scheduler.KindComputeExecutor,
+		scheduler.KindComputeMerge,
+		scheduler.KindComputeTxnScheduler,
+	} {
+		if kind == ourKind {
+			if err = schedulerCheckScheduled(committees[kind], defaultIdentity.NodeSigner.Public(), scheduler.Worker); err != nil {
+				panic(fmt.Sprintf("scheduler check scheduled failed: %+v", err))
+			}
+			continue
+		}
+		if err = schedulerCheckNotScheduled(committees[kind], defaultIdentity.NodeSigner.Public()); err != nil {
+			panic(fmt.Sprintf("scheduler check not scheduled %s failed: %+v", kind, err))
+		}
+	}
+	logger.Debug("scenario: schedule ok",
+		"role", sc.Role,
+	)
+
+	logger.Debug("scenario: connecting to storage committee")
+	hnss, err := storageConnectToCommittee(ht, electionHeight, committees[scheduler.KindStorage], scheduler.Worker, defaultIdentity)
+	if err != nil {
+		panic(fmt.Sprintf("storage connect to committee failed: %+v", err))
+	}
+	defer storageBroadcastCleanup(hnss)
+
+	ctx := context.Background()
+	for round := uint64(0); round < sc.NumRounds(); round++ {
+		switch sc.Role {
+		case RoleExecutor:
+			err = scenarioExecutorRound(ctx, sc, round, ht, ph, hnss, defaultIdentity, rak, electionHeight, committees)
+		case RoleMerge:
+			err = scenarioMergeRound(ctx, sc, round, ht, ph, hnss, defaultIdentity)
+		}
+		if err != nil {
+			panic(fmt.Sprintf("scenario round %d failed: %+v", round, err))
+		}
+	}
+}
+
+func scenarioMaybeDelay(sc *Scenario, round uint64) {
+	if f := sc.fault(FaultLateCommitment, round); f != nil {
+		logger.Debug("scenario: delaying commitment",
+			"round", round,
+			"delay", f.Delay,
+		)
+		time.Sleep(f.Delay)
+	}
+}
+
+func scenarioExecutorRound(
+	ctx context.Context,
+	sc *Scenario,
+	round uint64,
+	ht *honestTendermint,
+	ph *p2pHandle,
+	hnss []*honestNodeStorage,
+	id *identity.Identity,
+	rak signature.Signer,
+	electionHeight int64,
+	committees map[scheduler.CommitteeKind]*scheduler.Committee,
+) error {
+	cbc := newComputeBatchContext()
+
+	if err := cbc.receiveBatch(ph); err != nil {
+		return fmt.Errorf("compute receive batch: %w", err)
+	}
+	logger.Debug("scenario executor: received batch", "round", round, "bd", cbc.bd)
+
+	if err := cbc.openTrees(ctx, hnss[0]); err != nil {
+		return fmt.Errorf("compute open trees: %w", err)
+	}
+	defer cbc.closeTrees()
+
+	value := []byte("hello_value")
+	if sc.fault(FaultWrongStateRoot, round) != nil {
+		value = []byte("wrong")
+	}
+	if err := cbc.stateTree.Insert(ctx, []byte("hello_key"), value); err != nil {
+		return fmt.Errorf("compute state tree set: %w", err)
+	}
+	if err := cbc.addResultSuccess(ctx, cbc.txs[0], nil, transaction.Tags{
+		transaction.Tag{Key: []byte("kv_op"), Value: []byte("insert")},
+		transaction.Tag{Key: []byte("kv_key"), Value: []byte("hello_key")},
+	}); err != nil {
+		return fmt.Errorf("compute add result success: %w", err)
+	}
+
+	if err := cbc.commitTrees(ctx); err != nil {
+		return fmt.Errorf("compute commit trees: %w", err)
+	}
+	logger.Debug("scenario executor: committed storage trees",
+		"round", round,
+		"new_io_root", cbc.newIORoot,
+		"new_state_root", cbc.newStateRoot,
+	)
+
+	if err := cbc.uploadBatch(ctx, hnss); err != nil {
+		return fmt.Errorf("compute upload batch: %w", err)
+	}
+	if sc.fault(FaultEquivocatingStorageReceipts, round) != nil {
+		if err := cbc.uploadEquivocatingBatch(ctx, hnss); err != nil {
+			return fmt.Errorf("compute upload equivocating batch: %w", err)
+		}
+		logger.Debug("scenario executor: using equivocating storage receipts", "round", round)
+	}
+
+	if err := cbc.createCommitment(id, rak, committees[scheduler.KindComputeExecutor].EncodedMembersHash()); err != nil {
+		return fmt.Errorf("compute create commitment: %w", err)
+	}
+
+	scenarioMaybeDelay(sc, round)
+
+	skip, dropAll := sc.dropPeers(round)
+	if dropAll {
+		logger.Debug("scenario executor: dropping commitment", "round", round)
+		return nil
+	}
+	if err := cbc.publishToCommitteeExcept(ht, electionHeight, committees[scheduler.KindComputeMerge], scheduler.Worker, ph, defaultRuntimeID, electionHeight, skip); err != nil {
+		return fmt.Errorf("compute publish to committee merge worker: %w", err)
+	}
+	logger.Debug("scenario executor: commitment sent", "round", round)
+
+	return nil
+}
+
+func scenarioMergeRound(
+	ctx context.Context,
+	sc *Scenario,
+	round uint64,
+	ht *honestTendermint,
+	ph *p2pHandle,
+	hnss []*honestNodeStorage,
+	id *identity.Identity,
+) error {
+	mbc := newMergeBatchContext()
+
+	// Receive 1 committee * 2 commitments per committee.
+	if err := mbc.receiveCommitments(ph, 2); err != nil {
+		return fmt.Errorf("merge receive commitments: %w", err)
+	}
+	logger.Debug("scenario merge: received commitments", "round", round, "commitments", mbc.commitments)
+
+	// Load the current block after receiving the commitments, so the block
+	// from any previous round has been finalized.
+	if err := mbc.loadCurrentBlock(ht, defaultRuntimeID); err != nil {
+		return fmt.Errorf("merge load current block: %w", err)
+	}
+
+	if sc.fault(FaultWrongStateRoot, round) != nil {
+		if err := mbc.processWrong(ctx, hnss); err != nil {
+			return fmt.Errorf("merge process wrong: %w", err)
+		}
+	} else {
+		if err := mbc.process(ctx, hnss); err != nil {
+			return fmt.Errorf("merge process: %w", err)
+		}
+	}
+	logger.Debug("scenario merge: processed",
+		"round", round,
+		"new_block", mbc.newBlock,
+	)
+
+	if sc.fault(FaultEquivocatingStorageReceipts, round) != nil {
+		// Attach storage receipts for a different merge result.
+		newBlock := mbc.newBlock
+		var err error
+		if sc.fault(FaultWrongStateRoot, round) != nil {
+			err = mbc.process(ctx, hnss)
+		} else {
+			err = mbc.processWrong(ctx, hnss)
+		}
+		if err != nil {
+			return fmt.Errorf("merge process equivocating: %w", err)
+		}
+		newBlock.Header.StorageSignatures = mbc.newBlock.Header.StorageSignatures
+		mbc.newBlock = newBlock
+		logger.Debug("scenario merge: using equivocating storage receipts", "round", round)
+	}
+
+	if err := mbc.createCommitment(id); err != nil {
+		return fmt.Errorf("merge create commitment: %w", err)
+	}
+
+	scenarioMaybeDelay(sc, round)
+
+	if _, dropAll := sc.dropPeers(round); dropAll {
+		logger.Debug("scenario merge: dropping commitment", "round", round)
+		return nil
+	}
+	if err := mbc.publishToChain(ht.service, id, defaultRuntimeID); err != nil {
+		return fmt.Errorf("merge publish to chain: %w", err)
+	}
+	logger.Debug("scenario merge: commitment sent", "round", round)
+
+	return nil
+}
+
 // Register registers the byzantine sub-command and all of its children.
 func Register(parentCmd *cobra.Command) {
 	byzantineCmd.AddCommand(executorHonestCmd)
@@ -750,6 +1009,7 @@ func Register(parentCmd *cobra.Command) {
 	byzantineCmd.AddCommand(mergeHonestCmd)
 	byzantineCmd.AddCommand(mergeWrongCmd)
 	byzantineCmd.AddCommand(mergeStragglerCmd)
+	byzantineCmd.AddCommand(scenarioCmd)
 	parentCmd.AddCommand(byzantineCmd)
 }
 
@@ -769,4 +1029,10 @@ func init() {
 	byzantineCmd.PersistentFlags().AddFlagSet(p2p.Flags)
 	byzantineCmd.PersistentFlags().AddFlagSet(tendermint.Flags)
 	byzantineCmd.PersistentFlags().AddFlagSet(registration.Flags)
+
+	scenarioFlags := flag.NewFlagSet("", flag.ContinueOnError)
+	scenarioFlags.String(CfgScenarioFile, "", "path to the JSON-encoded scenario file")
+	scenarioFlags.Uint16(CfgKeyManagerPort, 9200, "port of the key manager EnclaveRPC server (key manager role)")
+	_ = viper.BindPFlags(scenarioFlags)
+	scenarioCmd.Flags().AddFlagSet(scenarioFlags)
 }
@@ -152,6 +152,29 @@ func (cbc *computeBatchContext) uploadBatch(ctx context.Context, hnss []*honestN
 	return nil
 }
 
+// uploadEquivocatingBatch replaces the storage receipts with receipts for a batch that leaves
+// the IO and state roots unchanged, so they do not match the committed roots.
+func (cbc *computeBatchContext) uploadEquivocatingBatch(ctx context.Context, hnss []*honestNodeStorage) error {
+	var err error
+	cbc.storageReceipts, err = storageBroadcastApplyBatch(ctx, hnss, cbc.bd.Header.Namespace, cbc.bd.Header.Round+1, []storage.ApplyOp{
+		storage.ApplyOp{
+			SrcRound: cbc.bd.Header.Round + 1,
+			SrcRoot:  cbc.bd.IORoot,
+			DstRoot:  cbc.bd.IORoot,
+		},
+		storage.ApplyOp{
+			SrcRound: cbc.bd.Header.Round,
+			SrcRoot:  cbc.bd.Header.StateRoot,
+			DstRoot:  cbc.bd.Header.StateRoot,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("storage broadcast apply equivocating batch: %w", err)
+	}
+
+	return nil
+}
+
 func (cbc *computeBatchContext) createCommitment(id *identity.Identity, rak signature.Signer, committeeID hash.Hash) error {
 	var storageSigs []signature.Signature
 	for _, receipt := range cbc.storageReceipts {
@@ -190,14 +213,18 @@ func (cbc *computeBatchContext) createCommitment(id *identity.Identity, rak sign
 }
 
 func (cbc *computeBatchContext) publishToCommittee(ht *honestTendermint, height int64, committee *scheduler.Committee, role scheduler.Role, ph *p2pHandle, runtimeID common.Namespace, groupVersion int64) error {
-	if err := schedulerPublishToCommittee(ht, height, committee, role, ph, &p2p.Message{
+	return cbc.publishToCommitteeExcept(ht, height, committee, role, ph, runtimeID, groupVersion, nil)
+}
+
+func (cbc *computeBatchContext) publishToCommitteeExcept(ht *honestTendermint, height int64, committee *scheduler.Committee, role scheduler.Role, ph *p2pHandle, runtimeID common.Namespace, groupVersion int64, skip map[int]bool) error {
+	if err := schedulerPublishToCommitteeExcept(ht, height, committee, role, ph, &p2p.Message{
 		RuntimeID:    runtimeID,
 		GroupVersion: groupVersion,
 		SpanContext:  nil,
 		ExecutorWorkerFinished: &p2p.ExecutorWorkerFinished{
 			Commitment: *cbc.commit,
 		},
-	}); err != nil {
+	}, skip); err != nil {
 		return fmt.Errorf("scheduler publish to committee: %w", err)
 	}
 
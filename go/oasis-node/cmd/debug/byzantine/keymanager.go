@@ -0,0 +1,264 @@
+package byzantine
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/sha3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/accessctl"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/identity"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	keymanager "github.com/oasislabs/oasis-core/go/keymanager/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	enclaverpc "github.com/oasislabs/oasis-core/go/runtime/enclaverpc/api"
+)
+
+const (
+	// CfgKeyManagerPort configures the port of the key manager EnclaveRPC
+	// server used by the key manager role.
+	CfgKeyManagerPort = "keymanager.port"
+
+	// LogEventKeyManagerFaultInjected is a log event value that signals that
+	// a key manager node injected a fault into an EnclaveRPC request.
+	LogEventKeyManagerFaultInjected = "byzantine/keymanager_fault_injected"
+
+	// malformedReplySize is the size of malformed key manager replies.
+	malformedReplySize = 64
+)
+
+var _ enclaverpc.Transport = (*keyManagerNode)(nil)
+
+// keyManagerNode is a key manager node that registers as an initialized key
+// manager, acknowledging the current key manager status and policy, while
+// injecting faults into all EnclaveRPC requests.
+//
+// As the node does not have access to the master secret, requests that no
+// fault is injected into are rejected as if the node was unavailable.
+type keyManagerNode struct {
+	sc      *Scenario
+	ht      *honestTendermint
+	id      *identity.Identity
+	dataDir string
+
+	runtimeID    common.Namespace
+	capabilities *node.Capabilities
+	rak          signature.Signer
+}
+
+// CallEnclave injects faults into the given EnclaveRPC request.
+func (km *keyManagerNode) CallEnclave(ctx context.Context, request *enclaverpc.CallEnclaveRequest) ([]byte, error) {
+	// Key manager nodes do not participate in rounds.
+	switch {
+	case km.sc.fault(FaultStalePolicy, 0) != nil:
+		km.logFault(ctx, FaultStalePolicy, request)
+		return nil, status.Error(codes.PermissionDenied, "byzantine: request not allowed by policy")
+	case km.withhold(ctx):
+		km.logFault(ctx, FaultWithholdReplies, request)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case km.sc.fault(FaultMalformedReplies, 0) != nil:
+		km.logFault(ctx, FaultMalformedReplies, request)
+		rsp := make([]byte, malformedReplySize)
+		if _, err := rand.Read(rsp); err != nil {
+			return nil, err
+		}
+		return rsp, nil
+	default:
+		return nil, status.Error(codes.Unavailable, "byzantine: key manager not available")
+	}
+}
+
+func (km *keyManagerNode) logFault(ctx context.Context, kind FaultKind, request *enclaverpc.CallEnclaveRequest) {
+	logger.Debug("scenario key manager: injecting fault",
+		"kind", kind,
+		"runtime_id", request.RuntimeID,
+		"peer", peerSubjectFromContext(ctx),
+		logging.LogEvent, LogEventKeyManagerFaultInjected,
+	)
+}
+
+// withhold returns true iff the reply to the peer of the given request
+// context should be withheld.
+func (km *keyManagerNode) withhold(ctx context.Context) bool {
+	f := km.sc.fault(FaultWithholdReplies, 0)
+	if f == nil {
+		return false
+	}
+	if len(f.Nodes) == 0 {
+		return true
+	}
+
+	subject := peerSubjectFromContext(ctx)
+	for _, nodeID := range f.Nodes {
+		n, err := registryGetNode(km.ht, consensus.HeightLatest, nodeID)
+		if err != nil {
+			logger.Warn("scenario key manager: failed to resolve node",
+				"err", err,
+				"node_id", nodeID,
+			)
+			continue
+		}
+		for _, pk := range []signature.PublicKey{n.TLS.PubKey, n.TLS.NextPubKey} {
+			if pk.IsValid() && accessctl.SubjectFromPublicKey(pk) == subject {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// initResponse returns the initialization response that matches the given
+// key manager status.
+func (km *keyManagerNode) initResponse(st *keymanager.Status) *keymanager.InitResponse {
+	// This must match the policy checksum computed by the key manager
+	// consensus application.
+	var rawPolicy []byte
+	if st.Policy != nil {
+		rawPolicy = cbor.Marshal(st.Policy)
+	}
+	policyChecksum := sha3.Sum256(rawPolicy)
+
+	return &keymanager.InitResponse{
+		IsSecure:       st.IsSecure,
+		Checksum:       st.Checksum,
+		PolicyChecksum: policyChecksum[:],
+	}
+}
+
+// register (re-)registers the node with the given initialization response.
+func (km *keyManagerNode) register(initResponse *keymanager.InitResponse, addresses []node.Address) error {
+	signedInitResponse, err := keymanager.SignInitResponse(km.rak, initResponse)
+	if err != nil {
+		return fmt.Errorf("keymanager SignInitResponse: %w", err)
+	}
+
+	rt := &node.Runtime{
+		ID:        km.runtimeID,
+		ExtraInfo: cbor.Marshal(signedInitResponse),
+	}
+	if km.capabilities != nil {
+		rt.Capabilities = *km.capabilities
+	}
+	if err = registryRegisterNodeRuntime(km.ht.service, km.id, km.dataDir, addresses, nil, rt, node.RoleKeyManager); err != nil {
+		return fmt.Errorf("registryRegisterNodeRuntime: %w", err)
+	}
+	return nil
+}
+
+func peerSubjectFromContext(ctx context.Context) accessctl.Subject {
+	peer, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsAuth, ok := peer.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsAuth.State.PeerCertificates) != 1 {
+		return ""
+	}
+	return accessctl.SubjectFromX509Certificate(tlsAuth.State.PeerCertificates[0])
+}
+
+// scenarioKeyManager runs the key manager role of the given scenario until
+// the node is terminated.
+func scenarioKeyManager(ctx context.Context, sc *Scenario, ht *honestTendermint, id *identity.Identity, dataDir string) error {
+	rt, err := ht.service.Registry().GetRuntime(ctx, &registry.NamespaceQuery{
+		ID:     defaultRuntimeID,
+		Height: consensus.HeightLatest,
+	})
+	if err != nil {
+		return fmt.Errorf("registry GetRuntime: %w", err)
+	}
+	if rt.KeyManager == nil {
+		return fmt.Errorf("runtime %s does not use a key manager", defaultRuntimeID)
+	}
+
+	km := &keyManagerNode{
+		sc:        sc,
+		ht:        ht,
+		id:        id,
+		dataDir:   dataDir,
+		runtimeID: *rt.KeyManager,
+		rak:       keymanager.TestSigners[0],
+	}
+	if viper.GetBool(CfgFakeSGX) {
+		if km.rak, km.capabilities, err = initFakeCapabilitiesSGX(); err != nil {
+			return fmt.Errorf("initFakeCapabilitiesSGX: %w", err)
+		}
+	}
+
+	port := uint16(viper.GetInt(CfgKeyManagerPort))
+	server, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name:     "byzantine/keymanager",
+		Port:     port,
+		Identity: id,
+	})
+	if err != nil {
+		return fmt.Errorf("grpc NewServer: %w", err)
+	}
+	enclaverpc.RegisterService(server.Server(), km)
+	if err = server.Start(); err != nil {
+		return fmt.Errorf("grpc server Start: %w", err)
+	}
+	defer server.Stop()
+
+	addresses := []node.Address{
+		node.Address{
+			TCPAddr: net.TCPAddr{
+				IP:   net.IPv4(127, 0, 0, 1),
+				Port: int(port),
+			},
+		},
+	}
+
+	ch, sub, err := ht.service.KeyManager().WatchStatuses(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("keymanager WatchStatuses: %w", err)
+	}
+	defer sub.Close()
+
+	// Re-register whenever the initialization response changes, so that
+	// policy updates are always acknowledged and the node remains part of
+	// the key manager committee.
+	var registered []byte
+	for {
+		var annSt *keymanager.AnnotatedStatus
+		select {
+		case annSt = <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		st := annSt.Status
+		if !st.ID.Equal(&km.runtimeID) || !st.IsInitialized {
+			continue
+		}
+		initResponse := km.initResponse(st)
+		rawInitResponse := cbor.Marshal(initResponse)
+		if bytes.Equal(rawInitResponse, registered) {
+			continue
+		}
+
+		if err = km.register(initResponse, addresses); err != nil {
+			return err
+		}
+		registered = rawInitResponse
+		logger.Debug("scenario key manager: registered",
+			"runtime_id", km.runtimeID,
+			"height", annSt.Height,
+		)
+	}
+}
@@ -117,6 +117,42 @@ func (mbc *mergeBatchContext) process(ctx context.Context, hnss []*honestNodeSto
 	return nil
 }
 
+// processWrong processes the merge as if all executor committees committed to an empty IO root
+// and an unchanged state root.
+func (mbc *mergeBatchContext) processWrong(ctx context.Context, hnss []*honestNodeStorage) error {
+	origCommitments := mbc.commitments
+	defer func() {
+		mbc.commitments = origCommitments
+	}()
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+	mbc.commitments = []*commitment.OpenExecutorCommitment{
+		&commitment.OpenExecutorCommitment{
+			Body: &commitment.ComputeBody{
+				Header: commitment.ComputeResultsHeader{
+					IORoot:    emptyRoot,
+					StateRoot: mbc.currentBlock.Header.StateRoot,
+				},
+			},
+		},
+	}
+
+	if err := mbc.process(ctx, hnss); err != nil {
+		return err
+	}
+
+	// Sanity check the merge results.
+	if mbc.newBlock.Header.IORoot != emptyRoot {
+		return fmt.Errorf("merge of empty IO trees should be empty. got %s, expected %s", mbc.newBlock.Header.IORoot, emptyRoot)
+	}
+	if mbc.newBlock.Header.StateRoot != mbc.currentBlock.Header.StateRoot {
+		return fmt.Errorf("merge of identical state trees should be the same. got %s, expected %s", mbc.newBlock.Header.StateRoot, mbc.currentBlock.Header.StateRoot)
+	}
+
+	return nil
+}
+
 func (mbc *mergeBatchContext) createCommitment(id *identity.Identity) error {
 	var executorCommits []commitment.ExecutorCommitment
 	for _, openCom := range mbc.commitments {
@@ -16,6 +16,16 @@ import (
 )
 
 func registryRegisterNode(svc service.TendermintService, id *identity.Identity, dataDir string, addresses []node.Address, p2pAddresses []node.Address, runtimeID common.Namespace, capabilities *node.Capabilities, roles node.RolesMask) error {
+	rt := &node.Runtime{
+		ID: runtimeID,
+	}
+	if capabilities != nil {
+		rt.Capabilities = *capabilities
+	}
+	return registryRegisterNodeRuntime(svc, id, dataDir, addresses, p2pAddresses, rt, roles)
+}
+
+func registryRegisterNodeRuntime(svc service.TendermintService, id *identity.Identity, dataDir string, addresses []node.Address, p2pAddresses []node.Address, rt *node.Runtime, roles node.RolesMask) error {
 	entityID, registrationSigner, err := registration.GetRegistrationSigner(logging.GetLogger("cmd/byzantine/registration"), dataDir, id)
 	if err != nil {
 		return fmt.Errorf("registration GetRegistrationSigner: %w", err)
@@ -26,11 +36,7 @@ func registryRegisterNode(svc service.TendermintService, id *identity.Identity,
 
 	var runtimes []*node.Runtime
 	if roles&registry.RuntimesRequiredRoles != 0 {
-		runtimes = []*node.Runtime{
-			&node.Runtime{
-				ID: runtimeID,
-			},
-		}
+		runtimes = []*node.Runtime{rt}
 	}
 
 	var tlsAddresses []node.TLSAddress
@@ -60,9 +66,6 @@ func registryRegisterNode(svc service.TendermintService, id *identity.Identity,
 		Runtimes: runtimes,
 		Roles:    roles,
 	}
-	if capabilities != nil {
-		nodeDesc.Runtimes[0].Capabilities = *capabilities
-	}
 	signedNode, err := node.MultiSignNode(
 		[]signature.Signer{
 			registrationSigner,
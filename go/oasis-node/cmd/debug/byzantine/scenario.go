@@ -0,0 +1,216 @@
+package byzantine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+// Role is the role a Byzantine node assumes in a scenario.
+type Role string
+
+const (
+	// RoleExecutor is the executor worker role.
+	RoleExecutor Role = "executor"
+	// RoleMerge is the merge worker role.
+	RoleMerge Role = "merge"
+	// RoleKeyManager is the key manager role.
+	//
+	// Key manager nodes do not participate in rounds, instead they serve
+	// EnclaveRPC requests until they are terminated.
+	RoleKeyManager Role = "keymanager"
+)
+
+// FaultKind is the kind of fault injected by a Byzantine node.
+type FaultKind string
+
+const (
+	// FaultWrongStateRoot makes the node commit to a wrong state root.
+	//
+	// Executor nodes alter the state when processing the batch, merge nodes
+	// merge an empty set of executor results.
+	FaultWrongStateRoot FaultKind = "wrong_state_root"
+	// FaultLateCommitment makes the node wait for the configured delay before
+	// publishing its commitment.
+	FaultLateCommitment FaultKind = "late_commitment"
+	// FaultEquivocatingStorageReceipts makes the node commit to the honest
+	// roots while attaching storage receipts for a different set of roots.
+	FaultEquivocatingStorageReceipts FaultKind = "equivocating_storage_receipts"
+	// FaultDropMessages makes the node drop its outgoing commitment.
+	//
+	// Executor nodes drop the commitment sent to the merge committee members
+	// with the configured indices (or to all members if no indices are
+	// configured), merge nodes never submit their commitment to the chain.
+	FaultDropMessages FaultKind = "drop_messages"
+
+	// FaultStalePolicy makes the key manager node acknowledge policy updates
+	// in its registration while rejecting requests as if it was still
+	// enforcing a stale access policy.
+	FaultStalePolicy FaultKind = "stale_policy"
+	// FaultWithholdReplies makes the key manager node never reply to requests
+	// from the configured nodes (or from all nodes if no nodes are
+	// configured).
+	FaultWithholdReplies FaultKind = "withhold_replies"
+	// FaultMalformedReplies makes the key manager node reply to requests with
+	// malformed ciphertexts.
+	FaultMalformedReplies FaultKind = "malformed_replies"
+)
+
+// isKeyManagerFault returns true iff the fault is injected by key manager nodes.
+func (k FaultKind) isKeyManagerFault() bool {
+	switch k {
+	case FaultStalePolicy, FaultWithholdReplies, FaultMalformedReplies:
+		return true
+	default:
+		return false
+	}
+}
+
+// Fault is a fault injected by a Byzantine node.
+type Fault struct {
+	// Kind is the kind of the fault.
+	Kind FaultKind `json:"kind"`
+	// Rounds are the indices of the rounds the fault is injected in, relative
+	// to the first round the node participates in. If empty, the fault is
+	// injected in all rounds.
+	Rounds []uint64 `json:"rounds,omitempty"`
+	// Delay is the delay before publishing the commitment (late_commitment).
+	Delay time.Duration `json:"delay,omitempty"`
+	// Peers are the indices of the committee members that the commitment is
+	// not sent to (drop_messages).
+	Peers []int `json:"peers,omitempty"`
+	// Nodes are the IDs of the nodes whose requests are withheld
+	// (withhold_replies).
+	Nodes []signature.PublicKey `json:"nodes,omitempty"`
+}
+
+// Scenario is a Byzantine node scenario composed of faults.
+type Scenario struct {
+	// Role is the role the node is expected to be scheduled for.
+	Role Role `json:"role"`
+	// Rounds is the number of rounds the node participates in. If zero, the
+	// node participates in a single round.
+	Rounds uint64 `json:"rounds,omitempty"`
+	// Faults are the faults injected by the node.
+	Faults []Fault `json:"faults,omitempty"`
+}
+
+// NumRounds returns the number of rounds the node participates in.
+func (s *Scenario) NumRounds() uint64 {
+	if s.Rounds == 0 {
+		return 1
+	}
+	return s.Rounds
+}
+
+// Validate performs basic validation of the scenario.
+func (s *Scenario) Validate() error {
+	switch s.Role {
+	case RoleExecutor, RoleMerge:
+	case RoleKeyManager:
+		if s.Rounds != 0 {
+			return fmt.Errorf("byzantine: key manager nodes do not participate in rounds")
+		}
+	default:
+		return fmt.Errorf("byzantine: invalid scenario role: '%s'", s.Role)
+	}
+
+	for i, f := range s.Faults {
+		if f.Kind.isKeyManagerFault() != (s.Role == RoleKeyManager) {
+			return fmt.Errorf("byzantine: fault %d: '%s' is not supported by the %s role", i, f.Kind, s.Role)
+		}
+
+		switch f.Kind {
+		case FaultWrongStateRoot, FaultEquivocatingStorageReceipts:
+		case FaultLateCommitment:
+			if f.Delay <= 0 {
+				return fmt.Errorf("byzantine: fault %d: late commitment requires a positive delay", i)
+			}
+		case FaultDropMessages:
+			if s.Role == RoleMerge && len(f.Peers) > 0 {
+				return fmt.Errorf("byzantine: fault %d: merge commitments are not sent to peers", i)
+			}
+			for _, p := range f.Peers {
+				if p < 0 {
+					return fmt.Errorf("byzantine: fault %d: invalid peer index: %d", i, p)
+				}
+			}
+		case FaultStalePolicy, FaultMalformedReplies:
+		case FaultWithholdReplies:
+			for _, n := range f.Nodes {
+				if !n.IsValid() {
+					return fmt.Errorf("byzantine: fault %d: invalid node ID: %s", i, n)
+				}
+			}
+		default:
+			return fmt.Errorf("byzantine: fault %d: invalid fault kind: '%s'", i, f.Kind)
+		}
+
+		for _, r := range f.Rounds {
+			if r >= s.NumRounds() {
+				return fmt.Errorf("byzantine: fault %d: round %d out of range", i, r)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fault returns the fault of the given kind injected in the given round, if any.
+func (s *Scenario) fault(kind FaultKind, round uint64) *Fault {
+	for i := range s.Faults {
+		f := &s.Faults[i]
+		if f.Kind != kind {
+			continue
+		}
+		if len(f.Rounds) == 0 {
+			return f
+		}
+		for _, r := range f.Rounds {
+			if r == round {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// dropPeers returns the set of committee member indices that messages
+// should not be sent to in the given round and whether all messages should
+// be dropped.
+func (s *Scenario) dropPeers(round uint64) (map[int]bool, bool) {
+	f := s.fault(FaultDropMessages, round)
+	if f == nil {
+		return nil, false
+	}
+	if len(f.Peers) == 0 {
+		return nil, true
+	}
+
+	peers := make(map[int]bool)
+	for _, p := range f.Peers {
+		peers[p] = true
+	}
+	return peers, false
+}
+
+// LoadScenario loads and validates a JSON-encoded scenario from a file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("byzantine: failed to read scenario: %w", err)
+	}
+
+	var s Scenario
+	if err = json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("byzantine: failed to parse scenario: %w", err)
+	}
+	if err = s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
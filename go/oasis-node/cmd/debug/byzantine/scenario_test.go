@@ -0,0 +1,125 @@
+package byzantine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+func TestScenarioValidate(t *testing.T) {
+	require := require.New(t)
+
+	var nodeID signature.PublicKey
+	require.NoError(nodeID.UnmarshalHex("4ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35"), "UnmarshalHex")
+
+	for _, sc := range []Scenario{
+		{Role: RoleExecutor},
+		{Role: RoleMerge, Faults: []Fault{{Kind: FaultWrongStateRoot}}},
+		{Role: RoleExecutor, Rounds: 3, Faults: []Fault{
+			{Kind: FaultLateCommitment, Rounds: []uint64{0, 2}, Delay: time.Second},
+			{Kind: FaultDropMessages, Peers: []int{1}},
+			{Kind: FaultEquivocatingStorageReceipts, Rounds: []uint64{1}},
+		}},
+		{Role: RoleMerge, Faults: []Fault{{Kind: FaultDropMessages}}},
+		{Role: RoleKeyManager},
+		{Role: RoleKeyManager, Faults: []Fault{
+			{Kind: FaultStalePolicy},
+			{Kind: FaultWithholdReplies, Nodes: []signature.PublicKey{nodeID}},
+			{Kind: FaultMalformedReplies},
+		}},
+	} {
+		require.NoError(sc.Validate(), "Validate(%+v)", sc)
+	}
+
+	for _, sc := range []Scenario{
+		{},
+		{Role: "storage"},
+		{Role: RoleExecutor, Faults: []Fault{{Kind: "invalid"}}},
+		{Role: RoleExecutor, Faults: []Fault{{Kind: FaultLateCommitment}}},
+		{Role: RoleExecutor, Faults: []Fault{{Kind: FaultWrongStateRoot, Rounds: []uint64{1}}}},
+		{Role: RoleExecutor, Faults: []Fault{{Kind: FaultDropMessages, Peers: []int{-1}}}},
+		{Role: RoleMerge, Faults: []Fault{{Kind: FaultDropMessages, Peers: []int{0}}}},
+		{Role: RoleExecutor, Faults: []Fault{{Kind: FaultStalePolicy}}},
+		{Role: RoleKeyManager, Rounds: 2},
+		{Role: RoleKeyManager, Faults: []Fault{{Kind: FaultWrongStateRoot}}},
+		{Role: RoleKeyManager, Faults: []Fault{{Kind: FaultMalformedReplies, Rounds: []uint64{1}}}},
+	} {
+		require.Error(sc.Validate(), "Validate(%+v)", sc)
+	}
+}
+
+func TestScenarioFaults(t *testing.T) {
+	require := require.New(t)
+
+	sc := Scenario{
+		Role:   RoleExecutor,
+		Rounds: 3,
+		Faults: []Fault{
+			{Kind: FaultWrongStateRoot},
+			{Kind: FaultLateCommitment, Rounds: []uint64{1}, Delay: time.Second},
+			{Kind: FaultLateCommitment, Rounds: []uint64{2}, Delay: 2 * time.Second},
+			{Kind: FaultDropMessages, Rounds: []uint64{0}, Peers: []int{0, 2}},
+			{Kind: FaultDropMessages, Rounds: []uint64{2}},
+		},
+	}
+	require.EqualValues(3, sc.NumRounds(), "NumRounds")
+
+	for round := uint64(0); round < sc.NumRounds(); round++ {
+		require.NotNil(sc.fault(FaultWrongStateRoot, round), "wrong state root should apply to all rounds")
+		require.Nil(sc.fault(FaultEquivocatingStorageReceipts, round), "unconfigured fault should not apply")
+	}
+
+	require.Nil(sc.fault(FaultLateCommitment, 0), "late commitment should not apply to round 0")
+	require.Equal(time.Second, sc.fault(FaultLateCommitment, 1).Delay, "late commitment delay in round 1")
+	require.Equal(2*time.Second, sc.fault(FaultLateCommitment, 2).Delay, "late commitment delay in round 2")
+
+	skip, dropAll := sc.dropPeers(0)
+	require.False(dropAll, "round 0 should drop selectively")
+	require.Equal(map[int]bool{0: true, 2: true}, skip, "round 0 dropped peers")
+	skip, dropAll = sc.dropPeers(1)
+	require.False(dropAll, "round 1 should not drop")
+	require.Empty(skip, "round 1 should not drop")
+	_, dropAll = sc.dropPeers(2)
+	require.True(dropAll, "round 2 should drop all messages")
+
+	require.EqualValues(1, (&Scenario{Role: RoleMerge}).NumRounds(), "NumRounds should default to one")
+}
+
+func TestLoadScenario(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-byzantine-scenario-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "scenario.json")
+	require.NoError(ioutil.WriteFile(path, []byte(`{
+		"role": "executor",
+		"rounds": 2,
+		"faults": [
+			{"kind": "wrong_state_root", "rounds": [1]},
+			{"kind": "late_commitment", "delay": 5000000000}
+		]
+	}`), 0600))
+
+	sc, err := LoadScenario(path)
+	require.NoError(err, "LoadScenario")
+	require.Equal(RoleExecutor, sc.Role, "role")
+	require.EqualValues(2, sc.Rounds, "rounds")
+	require.Len(sc.Faults, 2, "faults")
+	require.Equal([]uint64{1}, sc.Faults[0].Rounds, "fault rounds")
+	require.Equal(5*time.Second, sc.Faults[1].Delay, "fault delay")
+
+	require.NoError(ioutil.WriteFile(path, []byte(`{"role": "executor", "faults": [{"kind": "late_commitment"}]}`), 0600))
+	_, err = LoadScenario(path)
+	require.Error(err, "LoadScenario should fail for invalid scenarios")
+
+	_, err = LoadScenario(filepath.Join(dir, "nonexistent.json"))
+	require.Error(err, "LoadScenario should fail for missing files")
+}
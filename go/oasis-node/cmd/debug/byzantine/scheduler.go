@@ -122,7 +122,23 @@ func schedulerForRoleInCommittee(ht *honestTendermint, height int64, committee *
 }
 
 func schedulerPublishToCommittee(ht *honestTendermint, height int64, committee *scheduler.Committee, role scheduler.Role, ph *p2pHandle, message *p2p.Message) error {
+	return schedulerPublishToCommitteeExcept(ht, height, committee, role, ph, message, nil)
+}
+
+// schedulerPublishToCommitteeExcept publishes the message to all members of the committee with
+// the given role, except for the ones whose index (among members with the role) is in skip.
+func schedulerPublishToCommitteeExcept(ht *honestTendermint, height int64, committee *scheduler.Committee, role scheduler.Role, ph *p2pHandle, message *p2p.Message, skip map[int]bool) error {
+	var idx int
 	if err := schedulerForRoleInCommittee(ht, height, committee, role, func(n *node.Node) error {
+		defer func() { idx++ }()
+		if skip[idx] {
+			logger.Debug("dropping message to committee member",
+				"node", n.ID,
+				"index", idx,
+			)
+			return nil
+		}
+
 		ph.service.Publish(ph.context, n, message)
 
 		return nil
@@ -0,0 +1,279 @@
+// Package consensus implements the consensus debug sub-commands.
+package consensus
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/abci/types"
+	tmconfig "github.com/tendermint/tendermint/config"
+	tmnode "github.com/tendermint/tendermint/node"
+	tmstore "github.com/tendermint/tendermint/store"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	beaconApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/beacon"
+	epochtimeApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/epochtime"
+	epochtimeMockApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/epochtime_mock"
+	keymanagerApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/keymanager"
+	registryApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry"
+	roothashApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/roothash"
+	schedulerApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/scheduler"
+	stakingApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/db"
+	genesisFile "github.com/oasislabs/oasis-core/go/genesis/file"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
+	"github.com/oasislabs/oasis-core/go/upgrade"
+)
+
+const (
+	cfgReplayHeight  = "height"
+	cfgReplayTxIndex = "tx-index"
+)
+
+var (
+	consensusCmd = &cobra.Command{
+		Use:   "consensus",
+		Short: "consensus debug utilities",
+	}
+
+	replayCmd = &cobra.Command{
+		Use:   "replay",
+		Short: "replay a single transaction and trace all state accesses",
+		Long: "Replay a single transaction from the on-disk block store against the ABCI state\n" +
+			"at the preceding height and trace all state reads and writes.\n\n" +
+			"Only the given transaction is replayed, so any effects of the beginning of the\n" +
+			"block and of preceding transactions in the same block are not taken into account.\n" +
+			"The node must not be running.",
+		Run: doReplay,
+	}
+
+	replayFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logger = logging.GetLogger("cmd/debug/consensus")
+)
+
+// replayReport is the report of a replayed transaction.
+type replayReport struct {
+	Height  int64     `json:"height"`
+	TxIndex int       `json:"tx_index"`
+	TxHash  hash.Hash `json:"tx_hash"`
+
+	Codespace string        `json:"codespace,omitempty"`
+	Code      uint32        `json:"code"`
+	Log       string        `json:"log,omitempty"`
+	GasWanted int64         `json:"gas_wanted"`
+	GasUsed   int64         `json:"gas_used"`
+	Events    []types.Event `json:"events,omitempty"`
+
+	Trace []*traceEntry `json:"trace"`
+}
+
+// traceEntry is a single traced state access.
+type traceEntry struct {
+	Op    abci.StateOp `json:"op"`
+	Key   string       `json:"key"`
+	Value string       `json:"value,omitempty"`
+}
+
+func doReplay(cmd *cobra.Command, args []string) {
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		return
+	}
+
+	height := viper.GetInt64(cfgReplayHeight)
+	txIndex := viper.GetInt(cfgReplayTxIndex)
+	if height < 1 || txIndex < 0 {
+		logger.Error("invalid transaction position",
+			"height", height,
+			"tx_index", txIndex,
+		)
+		return
+	}
+
+	// Load the genesis document, required for configuring the time source.
+	fp, err := genesisFile.NewFileProvider(flags.GenesisFile())
+	if err != nil {
+		logger.Error("failed to load genesis document",
+			"err", err,
+		)
+		return
+	}
+	genesisDoc, err := fp.GetGenesisDocument()
+	if err != nil {
+		logger.Error("failed to get genesis document",
+			"err", err,
+		)
+		return
+	}
+
+	// Fetch the transaction from the block store.
+	tendermintDataDir := filepath.Join(dataDir, tendermint.StateDir)
+	tenderConfig := tmconfig.DefaultConfig()
+	tenderConfig.SetRoot(tendermintDataDir)
+	dbProvider, err := db.GetProvider()
+	if err != nil {
+		logger.Error("failed to obtain database provider",
+			"err", err,
+		)
+		return
+	}
+	blockStoreDB, err := dbProvider(&tmnode.DBContext{ID: "blockstore", Config: tenderConfig})
+	if err != nil {
+		logger.Error("failed to open block store database",
+			"err", err,
+		)
+		return
+	}
+	blk := tmstore.NewBlockStore(blockStoreDB).LoadBlock(height)
+	blockStoreDB.Close()
+	if blk == nil {
+		logger.Error("block not found",
+			"height", height,
+		)
+		return
+	}
+	if txIndex >= len(blk.Data.Txs) {
+		logger.Error("transaction not found",
+			"height", height,
+			"tx_index", txIndex,
+			"num_txs", len(blk.Data.Txs),
+		)
+		return
+	}
+	rawTx := blk.Data.Txs[txIndex]
+
+	// Initialize the ABCI multiplexer with all applications.
+	ctx := context.Background()
+	mux, err := abci.NewMockMux(ctx, upgrade.NewDummyUpgradeManager(), &abci.ApplicationConfig{
+		DataDir:         tendermintDataDir,
+		StorageBackend:  db.GetBackendName(),
+		HaltEpochHeight: math.MaxUint64,
+	})
+	if err != nil {
+		logger.Error("failed to initialize ABCI multiplexer",
+			"err", err,
+		)
+		return
+	}
+	defer mux.MockClose()
+
+	staking := stakingApp.New()
+	apps := []abci.Application{
+		beaconApp.New(),
+		keymanagerApp.New(),
+		registryApp.New(),
+		roothashApp.New(),
+		schedulerApp.New(),
+		staking,
+	}
+	epochtime := epochtimeApp.New()
+	if genesisDoc.EpochTime.Parameters.DebugMockBackend {
+		epochtime = epochtimeMockApp.New()
+	}
+	apps = append(apps, epochtime)
+	for _, app := range apps {
+		if err = mux.MockRegisterApp(app); err != nil {
+			logger.Error("failed to register ABCI application",
+				"app", app.Name(),
+				"err", err,
+			)
+			return
+		}
+	}
+
+	// Query factories are bound to the application state on registration.
+	timeSource := &replayTimeSource{
+		genesis: &genesisDoc.EpochTime,
+	}
+	switch qf := epochtime.QueryFactory().(type) {
+	case *epochtimeApp.QueryFactory:
+		timeSource.querier = qf
+	case *epochtimeMockApp.QueryFactory:
+		timeSource.mockQuerier = qf
+	}
+	mux.MockSetEpochtime(timeSource)
+	mux.MockSetTransactionAuthHandler(staking.(abci.TransactionAuthHandler))
+
+	// Replay the transaction.
+	report := &replayReport{
+		Height:  height,
+		TxIndex: txIndex,
+		TxHash:  hash.NewFromBytes(rawTx),
+	}
+	tracer := func(op abci.StateOp, key, value []byte) {
+		entry := &traceEntry{
+			Op:    op,
+			Key:   hex.EncodeToString(key),
+			Value: hex.EncodeToString(value),
+		}
+		logger.Debug("state access",
+			"op", entry.Op,
+			"key", entry.Key,
+			"value", entry.Value,
+		)
+		report.Trace = append(report.Trace, entry)
+	}
+	rsp, err := mux.MockReplayTx(ctx, height, blk.Header.Time, rawTx, tracer)
+	if err != nil {
+		logger.Error("failed to replay transaction",
+			"err", err,
+		)
+		return
+	}
+	report.Codespace = rsp.Codespace
+	report.Code = rsp.Code
+	report.Log = rsp.Log
+	report.GasWanted = rsp.GasWanted
+	report.GasUsed = rsp.GasUsed
+	report.Events = rsp.Events
+
+	raw, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		logger.Error("failed to marshal replay report into JSON",
+			"err", err,
+		)
+		return
+	}
+	fmt.Println(string(raw))
+
+	ok = true
+}
+
+// Register registers the consensus debug sub-commands.
+func Register(parentCmd *cobra.Command) {
+	replayCmd.Flags().AddFlagSet(flags.GenesisFileFlags)
+	replayCmd.Flags().AddFlagSet(replayFlags)
+	consensusCmd.AddCommand(replayCmd)
+	parentCmd.AddCommand(consensusCmd)
+}
+
+func init() {
+	replayFlags.Int64(cfgReplayHeight, 0, "height of the block containing the transaction")
+	replayFlags.Int(cfgReplayTxIndex, 0, "index of the transaction in the block")
+	_ = viper.BindPFlags(replayFlags)
+}
@@ -0,0 +1,85 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	epochtimeApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/epochtime"
+	epochtimeMockApp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/epochtime_mock"
+	"github.com/oasislabs/oasis-core/go/epochtime/api"
+)
+
+// replayTimeSource is a time source backed by the replayed ABCI state.
+type replayTimeSource struct {
+	genesis *api.Genesis
+
+	// Exactly one of the queriers is set, depending on the configured
+	// epochtime backend.
+	querier     *epochtimeApp.QueryFactory
+	mockQuerier *epochtimeMockApp.QueryFactory
+}
+
+func (ts *replayTimeSource) GetBaseEpoch(ctx context.Context) (api.EpochTime, error) {
+	return ts.genesis.Base, nil
+}
+
+func (ts *replayTimeSource) GetEpoch(ctx context.Context, height int64) (api.EpochTime, error) {
+	if ts.mockQuerier != nil {
+		q, err := ts.mockQuerier.QueryAt(ctx, height)
+		if err != nil {
+			return api.EpochInvalid, err
+		}
+		epoch, _, err := q.Epoch(ctx)
+		return epoch, err
+	}
+
+	schedule, err := ts.intervalSchedule(ctx, height)
+	if err != nil {
+		return api.EpochInvalid, err
+	}
+	return schedule.EpochAt(height), nil
+}
+
+func (ts *replayTimeSource) GetEpochBlock(ctx context.Context, epoch api.EpochTime) (int64, error) {
+	if ts.mockQuerier != nil {
+		return 0, fmt.Errorf("debug/consensus/epochtime: GetEpochBlock not supported")
+	}
+
+	schedule, err := ts.intervalSchedule(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	return schedule.HeightAt(epoch)
+}
+
+func (ts *replayTimeSource) intervalSchedule(ctx context.Context, height int64) (api.IntervalSchedule, error) {
+	q, err := ts.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := q.IntervalSchedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return api.NewIntervalSchedule(ts.genesis.Base, ts.genesis.Parameters.Interval), nil
+	}
+	return schedule, nil
+}
+
+func (ts *replayTimeSource) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
+	panic("debug/consensus/epochtime: WatchEpochs not supported")
+}
+
+func (ts *replayTimeSource) WatchLatestEpoch() (<-chan api.EpochTime, *pubsub.Subscription) {
+	panic("debug/consensus/epochtime: WatchLatestEpoch not supported")
+}
+
+func (ts *replayTimeSource) WatchIntervalChanges() (<-chan *api.IntervalChange, *pubsub.Subscription) {
+	panic("debug/consensus/epochtime: WatchIntervalChanges not supported")
+}
+
+func (ts *replayTimeSource) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
+	return nil, fmt.Errorf("debug/consensus/epochtime: StateToGenesis not supported")
+}
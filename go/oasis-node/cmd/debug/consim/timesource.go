@@ -41,6 +41,10 @@ func (b *simTimeSource) WatchLatestEpoch() (<-chan api.EpochTime, *pubsub.Subscr
 	panic("consim/epochtime: WatchLatestEpoch not supported")
 }
 
+func (b *simTimeSource) WatchIntervalChanges() (<-chan *api.IntervalChange, *pubsub.Subscription) {
+	panic("consim/epochtime: WatchIntervalChanges not supported")
+}
+
 func (b *simTimeSource) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
 	// WARNING: This ignores the height because it's only used for the final
 	// dump.
@@ -3,11 +3,13 @@ package control
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	control "github.com/oasislabs/oasis-core/go/control/api"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
@@ -36,6 +38,30 @@ var (
 		Run:   doWaitNodes,
 	}
 
+	controlTriggerElectionCmd = &cobra.Command{
+		Use:   "trigger-election",
+		Short: "advance mock epochtime by one epoch, triggering committee elections",
+		Run:   doTriggerElection,
+	}
+
+	controlForceRoundTimeoutCmd = &cobra.Command{
+		Use:   "force-round-timeout runtime-id (hex)",
+		Short: "force the current round of the given runtime to time out",
+		Args: func(cmd *cobra.Command, args []string) error {
+			nrFn := cobra.ExactArgs(1)
+			if err := nrFn(cmd, args); err != nil {
+				return err
+			}
+			var id common.Namespace
+			if err := id.UnmarshalHex(args[0]); err != nil {
+				return fmt.Errorf("malformed runtime id '%v': %w", args[0], err)
+			}
+
+			return nil
+		},
+		Run: doForceRoundTimeout,
+	}
+
 	logger = logging.GetLogger("cmd/debug/control")
 )
 
@@ -90,6 +116,39 @@ func doWaitNodes(cmd *cobra.Command, args []string) {
 	logger.Info("enough nodes have been registered")
 }
 
+func doTriggerElection(cmd *cobra.Command, args []string) {
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	logger.Info("triggering election")
+
+	if err := client.TriggerElection(context.Background()); err != nil {
+		logger.Error("failed to trigger election",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doForceRoundTimeout(cmd *cobra.Command, args []string) {
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	var id common.Namespace
+	_ = id.UnmarshalHex(args[0]) // Validated in Args.
+
+	logger.Info("forcing round timeout",
+		"runtime_id", id,
+	)
+
+	if err := client.ForceRoundTimeout(context.Background(), id); err != nil {
+		logger.Error("failed to force round timeout",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
 // Register registers the dummy sub-command and all of its children.
 func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
@@ -98,5 +157,7 @@ func Register(parentCmd *cobra.Command) {
 
 	controlCmd.AddCommand(controlSetEpochCmd)
 	controlCmd.AddCommand(controlWaitNodesCmd)
+	controlCmd.AddCommand(controlTriggerElectionCmd)
+	controlCmd.AddCommand(controlForceRoundTimeoutCmd)
 	parentCmd.AddCommand(controlCmd)
 }
@@ -5,10 +5,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/byzantine"
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/consensus"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/consim"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/control"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/dumpdb"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/fixgenesis"
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/history"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/storage"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/txsource"
 )
@@ -27,6 +29,8 @@ func Register(parentCmd *cobra.Command) {
 	control.Register(debugCmd)
 	consim.Register(debugCmd)
 	dumpdb.Register(debugCmd)
+	consensus.Register(debugCmd)
+	history.Register(debugCmd)
 
 	parentCmd.AddCommand(debugCmd)
 }
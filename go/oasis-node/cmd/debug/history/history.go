@@ -0,0 +1,177 @@
+// Package history implements the runtime history debug sub-commands.
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
+	"github.com/oasislabs/oasis-core/go/runtime/history"
+	runtimeRegistry "github.com/oasislabs/oasis-core/go/runtime/registry"
+)
+
+const (
+	cfgExportDir     = "history.export.dir"
+	cfgExportNumKept = "history.export.num_kept"
+	cfgExportMaxAge  = "history.export.max_age"
+)
+
+var (
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "runtime history utilities",
+	}
+
+	historyExportCmd = &cobra.Command{
+		Use:   "export runtime-id (hex)",
+		Short: "prune a stopped node's runtime history, exporting pruned rounds into archive files",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			var id common.Namespace
+			if err := id.UnmarshalHex(args[0]); err != nil {
+				return fmt.Errorf("malformed runtime id '%v': %w", args[0], err)
+			}
+			return nil
+		},
+		Run: doExport,
+	}
+
+	historyExportFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logger = logging.GetLogger("cmd/debug/history")
+)
+
+// countingHandler is a prune handler that counts the number of rounds
+// pruned by the last prune.
+type countingHandler struct {
+	count int
+}
+
+func (h *countingHandler) Prune(ctx context.Context, rounds []uint64) error {
+	h.count = len(rounds)
+	return nil
+}
+
+func doExport(cmd *cobra.Command, args []string) {
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var id common.Namespace
+	if err := id.UnmarshalHex(args[0]); err != nil {
+		logger.Error("malformed runtime id",
+			"err", err,
+		)
+		return
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		return
+	}
+
+	destDir := viper.GetString(cfgExportDir)
+	if destDir == "" {
+		logger.Error("export directory must be set")
+		return
+	}
+
+	if err := exportRuntime(filepath.Join(dataDir, runtimeRegistry.RuntimesDir, id.String()), destDir, id); err != nil {
+		logger.Error("failed to export runtime history",
+			"err", err,
+			"runtime_id", id,
+		)
+		return
+	}
+
+	ok = true
+}
+
+func exportRuntime(dataDir, destDir string, id common.Namespace) error {
+	ctx := context.Background()
+
+	cfg := history.NewDefaultConfig()
+	cfg.Pruner = history.NewRetentionPruner(history.RetentionPolicy{
+		NumKept: viper.GetUint64(cfgExportNumKept),
+		MaxAge:  viper.GetDuration(cfgExportMaxAge),
+	})
+	h, err := history.New(dataDir, id, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open runtime history: %w", err)
+	}
+	defer h.Close()
+
+	latestBlk, err := h.GetLatestBlock(ctx)
+	switch err {
+	case nil:
+	case roothash.ErrNotFound:
+		logger.Info("runtime history is empty, nothing to export")
+		return nil
+	default:
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	ah, err := history.NewArchiveHandler(destDir, h)
+	if err != nil {
+		return err
+	}
+	h.Pruner().RegisterHandler(ah)
+
+	// Prune until there is nothing left to prune, as each prune is limited
+	// by the size of a single database transaction.
+	var counter countingHandler
+	h.Pruner().RegisterHandler(&counter)
+	var total int
+	for {
+		counter.count = 0
+		if err = h.Pruner().Prune(ctx, latestBlk.Header.Round); err != nil {
+			return fmt.Errorf("failed to prune runtime history: %w", err)
+		}
+		if counter.count == 0 {
+			break
+		}
+		total += counter.count
+	}
+
+	logger.Info("exported runtime history",
+		"runtime_id", id,
+		"latest_round", latestBlk.Header.Round,
+		"exported_rounds", total,
+		"dir", destDir,
+	)
+	return nil
+}
+
+// Register registers the history sub-command and all of it's children.
+func Register(parentCmd *cobra.Command) {
+	historyExportCmd.Flags().AddFlagSet(historyExportFlags)
+
+	historyCmd.AddCommand(historyExportCmd)
+	parentCmd.AddCommand(historyCmd)
+}
+
+func init() {
+	historyExportFlags.String(cfgExportDir, "", "the destination directory for history archive files")
+	historyExportFlags.Uint64(cfgExportNumKept, 600, "number of last rounds to keep (0 for no limit)")
+	historyExportFlags.Duration(cfgExportMaxAge, 0, "maximum age of rounds to keep (0 for no limit)")
+	_ = viper.BindPFlags(historyExportFlags)
+}
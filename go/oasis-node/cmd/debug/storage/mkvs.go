@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	runtimeRegistry "github.com/oasislabs/oasis-core/go/runtime/registry"
+	storageAPI "github.com/oasislabs/oasis-core/go/storage/api"
+	storageDatabase "github.com/oasislabs/oasis-core/go/storage/database"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	badgerNodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/flat"
+)
+
+const (
+	cfgMkvsFile    = "storage.mkvs.file"
+	cfgMkvsVersion = "storage.mkvs.version"
+	cfgMkvsRoot    = "storage.mkvs.root"
+)
+
+var (
+	storageMkvsCmd = &cobra.Command{
+		Use:   "mkvs",
+		Short: "MKVS flat export, import, inspection and benchmarking utilities",
+	}
+
+	storageMkvsExportCmd = &cobra.Command{
+		Use:   "export runtime-id (hex)",
+		Short: "export all key/value pairs under a storage root into a flat file",
+		Args:  validateSingleRuntimeID,
+		Run:   doMkvsExport,
+	}
+
+	storageMkvsImportCmd = &cobra.Command{
+		Use:   "import runtime-id (hex)",
+		Short: "import a flat file into the runtime's node database, verifying its root",
+		Args:  validateSingleRuntimeID,
+		Run:   doMkvsImport,
+	}
+
+	storageMkvsVersionsCmd = &cobra.Command{
+		Use:   "versions runtime-id (hex)",
+		Short: "list the versions in the runtime's node database together with their metadata",
+		Args:  validateSingleRuntimeID,
+		Run:   doMkvsVersions,
+	}
+
+	storageMkvsExportFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	storageMkvsFileFlags   = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func validateSingleRuntimeID(cmd *cobra.Command, args []string) error {
+	if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+		return err
+	}
+	if err := ValidateRuntimeIDStr(args[0]); err != nil {
+		return fmt.Errorf("malformed runtime id '%v': %w", args[0], err)
+	}
+	return nil
+}
+
+func mkvsRuntimeDataDir(args []string) (string, common.Namespace, error) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var id common.Namespace
+	if err := id.UnmarshalHex(args[0]); err != nil {
+		return "", id, fmt.Errorf("malformed runtime id: %w", err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		return "", id, fmt.Errorf("data directory must be set")
+	}
+	return filepath.Join(dataDir, runtimeRegistry.RuntimesDir, id.String()), id, nil
+}
+
+func mkvsInit(args []string) (string, common.Namespace, string, error) {
+	dataDir, id, err := mkvsRuntimeDataDir(args)
+	if err != nil {
+		return "", id, "", err
+	}
+
+	fn := viper.GetString(cfgMkvsFile)
+	if fn == "" {
+		return "", id, "", fmt.Errorf("flat export file must be set")
+	}
+
+	return dataDir, id, fn, nil
+}
+
+func doMkvsExport(cmd *cobra.Command, args []string) {
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	dataDir, id, fn, err := mkvsInit(args)
+	if err != nil {
+		logger.Error("failed to initialize",
+			"err", err,
+		)
+		return
+	}
+
+	root := storageAPI.Root{
+		Namespace: id,
+		Version:   viper.GetUint64(cfgMkvsVersion),
+	}
+	if err = root.Hash.UnmarshalHex(viper.GetString(cfgMkvsRoot)); err != nil {
+		logger.Error("malformed storage root hash",
+			"err", err,
+		)
+		return
+	}
+
+	storageBackend, err := newDirectStorageBackend(dataDir, id)
+	if err != nil {
+		logger.Error("failed to construct storage backend",
+			"err", err,
+		)
+		return
+	}
+
+	logger.Info("waiting for storage backend initialization")
+	<-storageBackend.Initialized()
+	defer storageBackend.Cleanup()
+
+	f, err := os.Create(fn)
+	if err != nil {
+		logger.Error("failed to create flat export file",
+			"err", err,
+			"fn", fn,
+		)
+		return
+	}
+	defer f.Close()
+
+	tree := mkvs.NewWithRoot(storageBackend, nil, root)
+	defer tree.Close()
+
+	if err = flat.Export(context.Background(), tree, root, f); err != nil {
+		logger.Error("failed to export storage root",
+			"err", err,
+			"root", root,
+		)
+		return
+	}
+
+	logger.Info("exported storage root",
+		"root", root,
+		"fn", fn,
+	)
+
+	ok = true
+}
+
+func doMkvsImport(cmd *cobra.Command, args []string) {
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	dataDir, id, fn, err := mkvsInit(args)
+	if err != nil {
+		logger.Error("failed to initialize",
+			"err", err,
+		)
+		return
+	}
+
+	f, err := os.Open(fn)
+	if err != nil {
+		logger.Error("failed to open flat export file",
+			"err", err,
+			"fn", fn,
+		)
+		return
+	}
+	defer f.Close()
+
+	if err = common.Mkdir(dataDir); err != nil {
+		logger.Error("failed to create runtime data directory",
+			"err", err,
+			"dir", dataDir,
+		)
+		return
+	}
+
+	ndb, err := badgerNodedb.New(&nodedb.Config{
+		DB:        filepath.Join(dataDir, storageDatabase.DefaultFileName(storageDatabase.BackendNameBadgerDB)),
+		Namespace: id,
+	})
+	if err != nil {
+		logger.Error("failed to open node database",
+			"err", err,
+		)
+		return
+	}
+	defer ndb.Close()
+
+	ctx := context.Background()
+	root, err := flat.Import(ctx, ndb, f)
+	if err != nil {
+		logger.Error("failed to import flat export",
+			"err", err,
+			"fn", fn,
+		)
+		return
+	}
+	if err = ndb.Finalize(ctx, root.Version, []hash.Hash{root.Hash}); err != nil {
+		logger.Error("failed to finalize imported root",
+			"err", err,
+			"root", root,
+		)
+		return
+	}
+
+	logger.Info("imported and verified storage root",
+		"root", root,
+		"fn", fn,
+	)
+
+	ok = true
+}
+
+func doMkvsVersions(cmd *cobra.Command, args []string) {
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	dataDir, id, err := mkvsRuntimeDataDir(args)
+	if err != nil {
+		logger.Error("failed to initialize",
+			"err", err,
+		)
+		return
+	}
+
+	ndb, err := badgerNodedb.New(&nodedb.Config{
+		DB:        filepath.Join(dataDir, storageDatabase.DefaultFileName(storageDatabase.BackendNameBadgerDB)),
+		Namespace: id,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		logger.Error("failed to open node database",
+			"err", err,
+		)
+		return
+	}
+	defer ndb.Close()
+
+	versions, err := ndb.GetVersions(context.Background())
+	if err != nil {
+		logger.Error("failed to get versions",
+			"err", err,
+		)
+		return
+	}
+
+	data, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		logger.Error("failed to marshal versions into JSON",
+			"err", err,
+		)
+		return
+	}
+	fmt.Println(string(data))
+
+	ok = true
+}
+
+func init() {
+	storageMkvsFileFlags.String(cfgMkvsFile, "", "path to the flat export file")
+	_ = viper.BindPFlags(storageMkvsFileFlags)
+
+	storageMkvsExportFlags.Uint64(cfgMkvsVersion, 0, "version of the storage root to export")
+	storageMkvsExportFlags.String(cfgMkvsRoot, "", "hash of the storage root to export (hex)")
+	_ = viper.BindPFlags(storageMkvsExportFlags)
+}
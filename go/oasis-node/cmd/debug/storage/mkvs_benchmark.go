@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/bench"
+)
+
+const (
+	cfgMkvsBenchProfiles     = "storage.mkvs.benchmark.profiles"
+	cfgMkvsBenchBackends     = "storage.mkvs.benchmark.backends"
+	cfgMkvsBenchSeed         = "storage.mkvs.benchmark.seed"
+	cfgMkvsBenchRounds       = "storage.mkvs.benchmark.rounds"
+	cfgMkvsBenchOpsPerRound  = "storage.mkvs.benchmark.ops_per_round"
+	cfgMkvsBenchKeySpace     = "storage.mkvs.benchmark.key_space"
+	cfgMkvsBenchValueSize    = "storage.mkvs.benchmark.value_size"
+	cfgMkvsBenchProofSamples = "storage.mkvs.benchmark.proof_samples"
+	cfgMkvsBenchNoFsync      = "storage.mkvs.benchmark.no_fsync"
+	cfgMkvsBenchOutput       = "storage.mkvs.benchmark.output"
+)
+
+var (
+	storageMkvsBenchmarkCmd = &cobra.Command{
+		Use:   "benchmark",
+		Short: "run MKVS macro-benchmarks with workload profiles and output JSON results",
+		Args:  cobra.NoArgs,
+		Run:   doMkvsBenchmark,
+	}
+
+	storageMkvsBenchmarkFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doMkvsBenchmark(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	var results []*bench.Result
+	for _, profile := range viper.GetStringSlice(cfgMkvsBenchProfiles) {
+		for _, backend := range viper.GetStringSlice(cfgMkvsBenchBackends) {
+			cfg := bench.Config{
+				Profile:      profile,
+				Backend:      backend,
+				Seed:         viper.GetInt64(cfgMkvsBenchSeed),
+				Rounds:       viper.GetUint64(cfgMkvsBenchRounds),
+				OpsPerRound:  viper.GetUint64(cfgMkvsBenchOpsPerRound),
+				KeySpace:     viper.GetUint64(cfgMkvsBenchKeySpace),
+				ValueSize:    viper.GetUint64(cfgMkvsBenchValueSize),
+				ProofSamples: viper.GetUint64(cfgMkvsBenchProofSamples),
+				NoFsync:      viper.GetBool(cfgMkvsBenchNoFsync),
+			}
+
+			logger.Info("running MKVS benchmark",
+				"profile", profile,
+				"backend", backend,
+			)
+
+			res, err := bench.Run(context.Background(), &cfg)
+			if err != nil {
+				logger.Error("failed to run MKVS benchmark",
+					"err", err,
+					"profile", profile,
+					"backend", backend,
+				)
+				return
+			}
+
+			logger.Info("MKVS benchmark finished",
+				"profile", profile,
+				"backend", backend,
+				"duration", res.Duration,
+				"commit_latency_p50", res.CommitLatency.P50,
+				"proof_size_mean", res.ProofSize.Mean,
+			)
+			results = append(results, res)
+		}
+	}
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		logger.Error("failed to marshal benchmark results into JSON",
+			"err", err,
+		)
+		return
+	}
+
+	switch fn := viper.GetString(cfgMkvsBenchOutput); fn {
+	case "":
+		fmt.Println(string(data))
+	default:
+		if err = ioutil.WriteFile(fn, data, 0600); err != nil {
+			logger.Error("failed to write benchmark results",
+				"err", err,
+				"fn", fn,
+			)
+			return
+		}
+	}
+
+	ok = true
+}
+
+func init() {
+	defaultCfg := bench.DefaultConfig(bench.ProfileUniform)
+
+	storageMkvsBenchmarkFlags.StringSlice(cfgMkvsBenchProfiles, bench.Profiles, fmt.Sprintf("workload profiles to run (%s)", strings.Join(bench.Profiles, ", ")))
+	storageMkvsBenchmarkFlags.StringSlice(cfgMkvsBenchBackends, []string{bench.BackendBadger}, fmt.Sprintf("node database backends to run on (%s)", strings.Join(bench.Backends, ", ")))
+	storageMkvsBenchmarkFlags.Int64(cfgMkvsBenchSeed, defaultCfg.Seed, "seed of the workload generator")
+	storageMkvsBenchmarkFlags.Uint64(cfgMkvsBenchRounds, defaultCfg.Rounds, "number of committed rounds")
+	storageMkvsBenchmarkFlags.Uint64(cfgMkvsBenchOpsPerRound, defaultCfg.OpsPerRound, "number of write operations in each round")
+	storageMkvsBenchmarkFlags.Uint64(cfgMkvsBenchKeySpace, defaultCfg.KeySpace, "number of distinct keys that may be written")
+	storageMkvsBenchmarkFlags.Uint64(cfgMkvsBenchValueSize, defaultCfg.ValueSize, "size of each written value in bytes")
+	storageMkvsBenchmarkFlags.Uint64(cfgMkvsBenchProofSamples, defaultCfg.ProofSamples, "number of sampled reads in each round")
+	storageMkvsBenchmarkFlags.Bool(cfgMkvsBenchNoFsync, false, "disable fsync() in the node database")
+	storageMkvsBenchmarkFlags.String(cfgMkvsBenchOutput, "", "path to the JSON results file (default: stdout)")
+	_ = viper.BindPFlags(storageMkvsBenchmarkFlags)
+}
@@ -3,6 +3,7 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -74,6 +75,13 @@ var (
 		Run: doForceFinalize,
 	}
 
+	storageUsageCmd = &cobra.Command{
+		Use:   "usage runtime-id (hex)",
+		Short: "show the storage usage of the given runtime on the node",
+		Args:  validateSingleRuntimeID,
+		Run:   doUsage,
+	}
+
 	logger = logging.GetLogger("cmd/storage")
 )
 
@@ -270,6 +278,39 @@ func doForceFinalize(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doUsage(cmd *cobra.Command, args []string) {
+	conn, _ := cmdControl.DoConnect(cmd)
+	storageWorkerClient := storageWorkerAPI.NewStorageWorkerClient(conn)
+	defer conn.Close()
+
+	var id common.Namespace
+	if err := id.UnmarshalHex(args[0]); err != nil {
+		logger.Error("failed to decode runtime id",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	usage, err := storageWorkerClient.GetUsage(context.Background(), &storageWorkerAPI.GetUsageRequest{
+		RuntimeID: id,
+	})
+	if err != nil {
+		logger.Error("failed to get storage usage",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		logger.Error("failed to marshal storage usage into JSON",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
+
 // Register registers the storage sub-command and all of its children.
 func Register(parentCmd *cobra.Command) {
 	storageCheckRootsCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
@@ -279,6 +320,9 @@ func Register(parentCmd *cobra.Command) {
 	storageForceFinalizeCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	storageForceFinalizeCmd.PersistentFlags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 
+	storageUsageCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+	storageUsageCmd.PersistentFlags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
+
 	storageExportCmd.Flags().AddFlagSet(storage.Flags)
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
@@ -286,9 +330,21 @@ func Register(parentCmd *cobra.Command) {
 
 	storageBenchmarkCmd.Flags().AddFlagSet(storageBenchmarkFlags)
 
+	storageMkvsExportCmd.Flags().AddFlagSet(storage.Flags)
+	storageMkvsExportCmd.Flags().AddFlagSet(storageMkvsExportFlags)
+	storageMkvsExportCmd.Flags().AddFlagSet(storageMkvsFileFlags)
+	storageMkvsImportCmd.Flags().AddFlagSet(storageMkvsFileFlags)
+	storageMkvsBenchmarkCmd.Flags().AddFlagSet(storageMkvsBenchmarkFlags)
+	storageMkvsCmd.AddCommand(storageMkvsExportCmd)
+	storageMkvsCmd.AddCommand(storageMkvsImportCmd)
+	storageMkvsCmd.AddCommand(storageMkvsVersionsCmd)
+	storageMkvsCmd.AddCommand(storageMkvsBenchmarkCmd)
+
 	storageCmd.AddCommand(storageCheckRootsCmd)
 	storageCmd.AddCommand(storageForceFinalizeCmd)
+	storageCmd.AddCommand(storageUsageCmd)
 	storageCmd.AddCommand(storageExportCmd)
 	storageCmd.AddCommand(storageBenchmarkCmd)
+	storageCmd.AddCommand(storageMkvsCmd)
 	parentCmd.AddCommand(storageCmd)
 }
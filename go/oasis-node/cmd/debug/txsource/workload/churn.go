@@ -0,0 +1,463 @@
+package workload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+const (
+	// NameChurn is the name of the staking delegation churn workload.
+	NameChurn = "churn"
+
+	churnNumAccounts = 8
+	// Amount each account is initially funded with.
+	churnFundAmount = 100_000
+	// Max amount escrowed in a single transaction (on top of the minimum
+	// delegation amount).
+	churnMaxEscrowAmount = 1_000
+	// Max number of rate change intervals a commission schedule amendment
+	// is additionally delayed by.
+	churnMaxAmendmentDelayIntervals = 5
+)
+
+type churnAccount struct {
+	signer        signature.Signer
+	reckonedNonce uint64
+}
+
+type churn struct {
+	logger *logging.Logger
+
+	params         *staking.ConsensusParameters
+	accounts       []*churnAccount
+	fundingAccount signature.Signer
+
+	lastCheckedEpoch epochtime.EpochTime
+}
+
+func (c *churn) submitTx(ctx context.Context, cnsc consensus.ClientBackend, acc *churnAccount, tx *transaction.Transaction) error {
+	acc.reckonedNonce++
+	if err := fundSignAndSubmitTx(ctx, c.logger, cnsc, acc.signer, tx, c.fundingAccount); err != nil {
+		c.logger.Error("failed to sign and submit transaction",
+			"tx", tx,
+			"signer", acc.signer.Public(),
+		)
+		return fmt.Errorf("failed to sign and submit tx: %w", err)
+	}
+	return nil
+}
+
+func (c *churn) doAddEscrow(ctx context.Context, rng *rand.Rand, cnsc consensus.ClientBackend, stakingClient staking.Backend) error {
+	acc := c.accounts[rng.Intn(len(c.accounts))]
+	to := c.accounts[rng.Intn(len(c.accounts))]
+
+	// [minDelegation + 1, minDelegation + churnMaxEscrowAmount]
+	amount := c.params.MinDelegationAmount.Clone()
+	var extra quantity.Quantity
+	_ = extra.FromInt64(rng.Int63n(churnMaxEscrowAmount) + 1)
+	_ = amount.Add(&extra)
+
+	account, err := stakingClient.AccountInfo(ctx, &staking.OwnerQuery{
+		Height: consensus.HeightLatest,
+		Owner:  acc.signer.Public(),
+	})
+	if err != nil {
+		return fmt.Errorf("stakingClient.AccountInfo %s: %w", acc.signer.Public(), err)
+	}
+	if account.General.Balance.Cmp(amount) < 0 {
+		// Top up the account so that it can cover the escrow.
+		if err = transferFunds(ctx, c.logger, cnsc, c.fundingAccount, acc.signer.Public(), amount.ToBigInt().Int64()); err != nil {
+			return fmt.Errorf("account funding failure: %w", err)
+		}
+	}
+
+	c.logger.Debug("add escrow",
+		"account", acc.signer.Public(),
+		"to", to.signer.Public(),
+		"amount", amount,
+	)
+
+	escrow := &staking.Escrow{
+		Account: to.signer.Public(),
+		Tokens:  *amount,
+	}
+	tx := staking.NewAddEscrowTx(acc.reckonedNonce, &transaction.Fee{}, escrow)
+	return c.submitTx(ctx, cnsc, acc, tx)
+}
+
+func (c *churn) doReclaimEscrow(ctx context.Context, rng *rand.Rand, cnsc consensus.ClientBackend, stakingClient staking.Backend) error {
+	acc := c.accounts[rng.Intn(len(c.accounts))]
+
+	delegations, err := stakingClient.Delegations(ctx, &staking.OwnerQuery{
+		Height: consensus.HeightLatest,
+		Owner:  acc.signer.Public(),
+	})
+	if err != nil {
+		return fmt.Errorf("stakingClient.Delegations %s: %w", acc.signer.Public(), err)
+	}
+	if len(delegations) == 0 {
+		c.logger.Debug("account not delegating, skipping reclaim",
+			"account", acc.signer.Public(),
+		)
+		return nil
+	}
+
+	// Select a delegation in a deterministic way.
+	var targets []signature.PublicKey
+	for to := range delegations {
+		targets = append(targets, to)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return bytes.Compare(targets[i][:], targets[j][:]) < 0
+	})
+	to := targets[rng.Intn(len(targets))]
+
+	// Reclaim [1, 100] percent of the delegated shares.
+	shares := delegations[to].Shares.Clone()
+	var pct, hundred quantity.Quantity
+	_ = pct.FromInt64(rng.Int63n(100) + 1)
+	_ = hundred.FromInt64(100)
+	_ = shares.Mul(&pct)
+	_ = shares.Quo(&hundred)
+	if shares.IsZero() {
+		shares = delegations[to].Shares.Clone()
+	}
+
+	c.logger.Debug("reclaim escrow",
+		"account", acc.signer.Public(),
+		"from", to,
+		"shares", shares,
+	)
+
+	reclaim := &staking.ReclaimEscrow{
+		Account: to,
+		Shares:  *shares,
+	}
+	tx := staking.NewReclaimEscrowTx(acc.reckonedNonce, &transaction.Fee{}, reclaim)
+	return c.submitTx(ctx, cnsc, acc, tx)
+}
+
+func (c *churn) doAmendCommissionSchedule(ctx context.Context, rng *rand.Rand, cnsc consensus.ClientBackend, stakingClient staking.Backend) error {
+	acc := c.accounts[rng.Intn(len(c.accounts))]
+	rules := c.params.CommissionScheduleRules
+
+	currentEpoch, err := cnsc.GetEpoch(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("GetEpoch: %w", err)
+	}
+	account, err := stakingClient.AccountInfo(ctx, &staking.OwnerQuery{
+		Height: consensus.HeightLatest,
+		Owner:  acc.signer.Public(),
+	})
+	if err != nil {
+		return fmt.Errorf("stakingClient.AccountInfo %s: %w", acc.signer.Public(), err)
+	}
+	schedule := account.Escrow.CommissionSchedule
+	schedule.Prune(currentEpoch)
+
+	alignedEpoch := func(epoch epochtime.EpochTime) epochtime.EpochTime {
+		aligned := (((epoch - 1) / rules.RateChangeInterval) + 1) * rules.RateChangeInterval
+		delay := epochtime.EpochTime(rng.Intn(churnMaxAmendmentDelayIntervals)) * rules.RateChangeInterval
+		return aligned + delay
+	}
+	randomRate := func(start epochtime.EpochTime) staking.CommissionRateStep {
+		step := staking.CommissionRateStep{Start: start}
+		_ = step.Rate.FromInt64(rng.Int63n(staking.CommissionRateDenominator.ToBigInt().Int64() + 1))
+		return step
+	}
+
+	// All bounds allow the full range of rates so that any rate amendment
+	// is valid. The extra +1 accounts for a possible epoch transition before
+	// the transaction is executed.
+	var amendment staking.AmendCommissionSchedule
+	switch {
+	case len(schedule.Bounds) == 0:
+		// No schedule yet, start with a bound and a matching rate step.
+		start := alignedEpoch(currentEpoch + rules.RateBoundLead + 1 + 1)
+		bound := staking.CommissionRateBoundStep{
+			Start:   start,
+			RateMax: *staking.CommissionRateDenominator.Clone(),
+		}
+		amendment.Amendment.Bounds = []staking.CommissionRateBoundStep{bound}
+		amendment.Amendment.Rates = []staking.CommissionRateStep{randomRate(start)}
+	case schedule.Bounds[0].Start > currentEpoch:
+		c.logger.Debug("commission schedule not yet active, skipping amendment",
+			"account", acc.signer.Public(),
+			"schedule", schedule,
+		)
+		return nil
+	default:
+		// Amend rates only, making sure not to exceed the max rate steps.
+		start := alignedEpoch(currentEpoch + 1 + 1)
+		steps := 1
+		for _, step := range schedule.Rates {
+			if step.Start < start {
+				steps++
+			}
+		}
+		if steps > int(rules.MaxRateSteps) {
+			c.logger.Debug("too many rate steps, skipping amendment",
+				"account", acc.signer.Public(),
+				"schedule", schedule,
+			)
+			return nil
+		}
+		amendment.Amendment.Rates = []staking.CommissionRateStep{randomRate(start)}
+	}
+
+	c.logger.Debug("amend commission schedule",
+		"account", acc.signer.Public(),
+		"amendment", amendment,
+		"existing", schedule,
+	)
+
+	tx := staking.NewAmendCommissionScheduleTx(acc.reckonedNonce, &transaction.Fee{}, &amendment)
+	return c.submitTx(ctx, cnsc, acc, tx)
+}
+
+// checkInvariants validates ledger invariants at the latest height.
+func (c *churn) checkInvariants(ctx context.Context, cnsc consensus.ClientBackend, stakingClient staking.Backend) error {
+	blk, err := cnsc.GetBlock(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("GetBlock: %w", err)
+	}
+	height := blk.Height
+
+	c.logger.Debug("checking ledger invariants",
+		"height", height,
+	)
+
+	// Make sure total supply matches sum of all balances and fees.
+	total, err := stakingClient.TotalSupply(ctx, height)
+	if err != nil {
+		return fmt.Errorf("stakingClient.TotalSupply: %w", err)
+	}
+	commonPool, err := stakingClient.CommonPool(ctx, height)
+	if err != nil {
+		return fmt.Errorf("stakingClient.CommonPool: %w", err)
+	}
+	lastBlockFees, err := stakingClient.LastBlockFees(ctx, height)
+	if err != nil {
+		return fmt.Errorf("stakingClient.LastBlockFees: %w", err)
+	}
+	ids, err := stakingClient.Accounts(ctx, height)
+	if err != nil {
+		return fmt.Errorf("stakingClient.Accounts: %w", err)
+	}
+
+	accounts := make(map[signature.PublicKey]*staking.Account)
+	var totalSum quantity.Quantity
+	for _, id := range ids {
+		var acc *staking.Account
+		acc, err = stakingClient.AccountInfo(ctx, &staking.OwnerQuery{Owner: id, Height: height})
+		if err != nil {
+			return fmt.Errorf("stakingClient.AccountInfo %s: %w", id, err)
+		}
+		accounts[id] = acc
+		_ = totalSum.Add(&acc.General.Balance)
+		_ = totalSum.Add(&acc.Escrow.Active.Balance)
+		_ = totalSum.Add(&acc.Escrow.Debonding.Balance)
+	}
+	_ = totalSum.Add(commonPool)
+	_ = totalSum.Add(lastBlockFees)
+	if total.Cmp(&totalSum) != 0 {
+		c.logger.Error("staking total supply mismatch",
+			"height", height,
+			"total_supply", total,
+			"total_sum", totalSum,
+		)
+		return fmt.Errorf("staking total supply mismatch")
+	}
+
+	// Only workload accounts delegate to workload accounts, so the sum of
+	// their (debonding) delegations must match the escrow pools.
+	activeShares := make(map[signature.PublicKey]*quantity.Quantity)
+	debondingShares := make(map[signature.PublicKey]*quantity.Quantity)
+	for _, acc := range c.accounts {
+		activeShares[acc.signer.Public()] = quantity.NewQuantity()
+		debondingShares[acc.signer.Public()] = quantity.NewQuantity()
+	}
+	for _, acc := range c.accounts {
+		owner := acc.signer.Public()
+
+		var infos map[signature.PublicKey]*staking.DelegationInfo
+		infos, err = stakingClient.DelegationInfos(ctx, &staking.OwnerQuery{Owner: owner, Height: height})
+		if err != nil {
+			return fmt.Errorf("stakingClient.DelegationInfos %s: %w", owner, err)
+		}
+		for to, info := range infos {
+			escrow := accounts[to]
+			if escrow == nil || activeShares[to] == nil {
+				c.logger.Error("delegation to unexpected escrow account",
+					"height", height,
+					"delegator", owner,
+					"account", to,
+				)
+				return fmt.Errorf("delegation to unexpected escrow account %s", to)
+			}
+			if info.Pool.Balance.Cmp(&escrow.Escrow.Active.Balance) != 0 || info.Pool.TotalShares.Cmp(&escrow.Escrow.Active.TotalShares) != 0 {
+				c.logger.Error("delegation info pool mismatch",
+					"height", height,
+					"delegator", owner,
+					"account", to,
+					"pool", info.Pool,
+					"active", escrow.Escrow.Active,
+				)
+				return fmt.Errorf("delegation info pool mismatch")
+			}
+			var amount *quantity.Quantity
+			if amount, err = escrow.Escrow.Active.TokensForShares(&info.Shares); err != nil {
+				return fmt.Errorf("TokensForShares: %w", err)
+			}
+			if info.Amount.Cmp(amount) != 0 {
+				c.logger.Error("delegation info amount mismatch",
+					"height", height,
+					"delegator", owner,
+					"account", to,
+					"shares", info.Shares,
+					"amount", info.Amount,
+					"expected_amount", amount,
+				)
+				return fmt.Errorf("delegation info amount mismatch")
+			}
+			_ = activeShares[to].Add(&info.Shares)
+		}
+
+		var debDelegations map[signature.PublicKey][]*staking.DebondingDelegation
+		debDelegations, err = stakingClient.DebondingDelegations(ctx, &staking.OwnerQuery{Owner: owner, Height: height})
+		if err != nil {
+			return fmt.Errorf("stakingClient.DebondingDelegations %s: %w", owner, err)
+		}
+		for to, debs := range debDelegations {
+			if debondingShares[to] == nil {
+				return fmt.Errorf("debonding delegation to unexpected escrow account %s", to)
+			}
+			for _, deb := range debs {
+				_ = debondingShares[to].Add(&deb.Shares)
+			}
+		}
+	}
+
+	for _, acc := range c.accounts {
+		id := acc.signer.Public()
+		var escrow staking.EscrowAccount
+		if account := accounts[id]; account != nil {
+			escrow = account.Escrow
+		}
+
+		if activeShares[id].Cmp(&escrow.Active.TotalShares) != 0 || debondingShares[id].Cmp(&escrow.Debonding.TotalShares) != 0 {
+			c.logger.Error("escrow shares mismatch",
+				"height", height,
+				"account", id,
+				"active_shares", activeShares[id],
+				"debonding_shares", debondingShares[id],
+				"escrow", escrow,
+			)
+			return fmt.Errorf("escrow shares mismatch")
+		}
+
+		var summary *staking.EscrowSummary
+		summary, err = stakingClient.EscrowSummary(ctx, &staking.OwnerQuery{Owner: id, Height: height})
+		if err != nil {
+			return fmt.Errorf("stakingClient.EscrowSummary %s: %w", id, err)
+		}
+		if summary.Active.Cmp(&escrow.Active.Balance) != 0 || summary.Debonding.Cmp(&escrow.Debonding.Balance) != 0 {
+			c.logger.Error("escrow summary mismatch",
+				"height", height,
+				"account", id,
+				"summary", summary,
+				"escrow", escrow,
+			)
+			return fmt.Errorf("escrow summary mismatch")
+		}
+	}
+
+	return nil
+}
+
+func (c *churn) Run(
+	gracefulExit context.Context,
+	rng *rand.Rand,
+	conn *grpc.ClientConn,
+	cnsc consensus.ClientBackend,
+	fundingAccount signature.Signer,
+) error {
+	var err error
+	ctx := context.Background()
+
+	c.logger = logging.GetLogger("cmd/txsource/workload/churn")
+	c.fundingAccount = fundingAccount
+
+	stakingClient := staking.NewStakingClient(conn)
+	if c.params, err = stakingClient.ConsensusParameters(ctx, consensus.HeightLatest); err != nil {
+		return fmt.Errorf("stakingClient.ConsensusParameters failure: %w", err)
+	}
+
+	fac := memorySigner.NewFactory()
+	c.accounts = make([]*churnAccount, churnNumAccounts)
+	for i := range c.accounts {
+		c.accounts[i] = &churnAccount{}
+		c.accounts[i].signer, err = fac.Generate(signature.SignerEntity, rng)
+		if err != nil {
+			return fmt.Errorf("memory signer factory Generate account %d: %w", i, err)
+		}
+
+		if err = transferFunds(ctx, c.logger, cnsc, fundingAccount, c.accounts[i].signer.Public(), churnFundAmount); err != nil {
+			return fmt.Errorf("account funding failure: %w", err)
+		}
+	}
+
+	if c.lastCheckedEpoch, err = cnsc.GetEpoch(ctx, consensus.HeightLatest); err != nil {
+		return fmt.Errorf("GetEpoch: %w", err)
+	}
+
+	for {
+		switch rng.Intn(3) {
+		case 0:
+			err = c.doAddEscrow(ctx, rng, cnsc, stakingClient)
+		case 1:
+			err = c.doReclaimEscrow(ctx, rng, cnsc, stakingClient)
+		case 2:
+			err = c.doAmendCommissionSchedule(ctx, rng, cnsc, stakingClient)
+		default:
+			return fmt.Errorf("unimplemented")
+		}
+		if err != nil {
+			return err
+		}
+
+		// Validate ledger invariants after each epoch transition.
+		var epoch epochtime.EpochTime
+		if epoch, err = cnsc.GetEpoch(ctx, consensus.HeightLatest); err != nil {
+			return fmt.Errorf("GetEpoch: %w", err)
+		}
+		if epoch > c.lastCheckedEpoch {
+			if err = c.checkInvariants(ctx, cnsc, stakingClient); err != nil {
+				return err
+			}
+			c.lastCheckedEpoch = epoch
+		}
+
+		select {
+		case <-time.After(1 * time.Second):
+		case <-gracefulExit.Done():
+			c.logger.Debug("time's up")
+			return nil
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/quantity"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	control "github.com/oasislabs/oasis-core/go/control/api"
 	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
 
@@ -22,15 +23,18 @@ const (
 	// Transfer workload continiously submits transfer and burn transactions.
 	NameTransfer = "transfer"
 
-	transferNumAccounts = 10
-	transferAmount      = 1
-	transferBurnAmount  = 10
+	transferNumAccounts    = 10
+	transferAmount         = 1
+	transferBurnAmount     = 10
+	transferVerifyInterval = 20
 )
 
 type transfer struct {
 	logger *logging.Logger
 
 	consensus consensus.ClientBackend
+	staking   staking.Backend
+	control   control.NodeController
 
 	accounts []struct {
 		signer          signature.Signer
@@ -105,6 +109,46 @@ func (t *transfer) doBurnTx(ctx context.Context, idx int) error {
 	return nil
 }
 
+// verifyAccounts checks that the on-chain nonces and balances of all accounts
+// match the reckoned ones, which ensures that no transactions have been lost
+// or applied more than once.
+func (t *transfer) verifyAccounts(ctx context.Context) error {
+	// Make sure the node is synced as it may have been restarted in the meantime.
+	if err := t.control.WaitSync(ctx); err != nil {
+		return fmt.Errorf("node controller client WaitSync: %w", err)
+	}
+
+	for i := range t.accounts {
+		acc := &t.accounts[i]
+		account, err := t.staking.AccountInfo(ctx, &staking.OwnerQuery{
+			Height: consensus.HeightLatest,
+			Owner:  acc.signer.Public(),
+		})
+		if err != nil {
+			return fmt.Errorf("stakingClient.AccountInfo %s: %w", acc.signer.Public(), err)
+		}
+
+		if account.General.Nonce != acc.reckonedNonce {
+			return fmt.Errorf("account %s nonce mismatch (reckoned: %d actual: %d)",
+				acc.signer.Public(),
+				acc.reckonedNonce,
+				account.General.Nonce,
+			)
+		}
+		if account.General.Balance.Cmp(&acc.reckonedBalance) != 0 {
+			return fmt.Errorf("account %s balance mismatch (reckoned: %s actual: %s)",
+				acc.signer.Public(),
+				acc.reckonedBalance,
+				account.General.Balance,
+			)
+		}
+	}
+
+	t.logger.Debug("verified account states")
+
+	return nil
+}
+
 func (t *transfer) Run(
 	gracefulExit context.Context,
 	rng *rand.Rand,
@@ -136,6 +180,8 @@ func (t *transfer) Run(
 
 	// Read all the account info up front.
 	stakingClient := staking.NewStakingClient(conn)
+	t.staking = stakingClient
+	t.control = control.NewNodeControllerClient(conn)
 	for i := range t.accounts {
 		fundAmount := transferAmount // funds for for a transfer
 		if err = transferFunds(ctx, t.logger, cnsc, t.fundingAccount, t.accounts[i].signer.Public(), int64(fundAmount)); err != nil {
@@ -162,8 +208,7 @@ func (t *transfer) Run(
 	if err = minBalance.FromInt64(transferAmount); err != nil {
 		return fmt.Errorf("min balance FromInt64 %d: %w", transferAmount, err)
 	}
-	for {
-
+	for i := uint64(1); ; i++ {
 		// Decide between doing a transfer or burn tx.
 		switch rng.Intn(2) {
 		case 0:
@@ -192,9 +237,19 @@ func (t *transfer) Run(
 		default:
 			return fmt.Errorf("unimplemented")
 		}
+
+		if i%transferVerifyInterval == 0 {
+			if err = t.verifyAccounts(ctx); err != nil {
+				return fmt.Errorf("account verification failure: %w", err)
+			}
+		}
+
 		select {
 		case <-gracefulExit.Done():
 			t.logger.Debug("time's up")
+			if err = t.verifyAccounts(ctx); err != nil {
+				return fmt.Errorf("account verification failure: %w", err)
+			}
 			return nil
 		default:
 		}
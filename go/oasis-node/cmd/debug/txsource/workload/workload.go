@@ -12,6 +12,7 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/errors"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
@@ -30,7 +31,8 @@ const (
 // FundAccountFromTestEntity funds an account from test entity.
 func FundAccountFromTestEntity(ctx context.Context, logger *logging.Logger, cnsc consensus.ClientBackend, to signature.PublicKey) error {
 	_, testEntitySigner, _ := entity.TestEntity()
-	return transferFunds(ctx, logger, cnsc, testEntitySigner, to, fundAccountAmount)
+	// The test entity account is shared between all workloads.
+	return doTransferFunds(ctx, logger, cnsc, testEntitySigner, to, fundAccountAmount, true)
 }
 
 func fundSignAndSubmitTx(ctx context.Context, logger *logging.Logger, cnsc consensus.ClientBackend, caller signature.Signer, tx *transaction.Transaction, fundingAccount signature.Signer) error {
@@ -67,28 +69,93 @@ func fundSignAndSubmitTx(ctx context.Context, logger *logging.Logger, cnsc conse
 		"tx_caller", caller.Public(),
 	)
 
-	// SubmitTx.
-	// Wait for a maximum of 60 seconds to submit transaction.
-	submitCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
-	err = cnsc.SubmitTx(submitCtx, signedTx)
-	switch err {
-	case nil:
-		cancel()
-		return nil
-	default:
-		cancel()
-		logger.Error("failed to submit transaction",
+	return submitSignedTx(ctx, logger, cnsc, caller.Public(), tx, signedTx)
+}
+
+// submitSignedTx submits a signed transaction, resubmitting the same signed
+// transaction on failures (e.g., when the node is being restarted) until it
+// is either accepted or the signer's nonce indicates that it has already
+// been applied. Since the same signed transaction is resubmitted, it can
+// never be applied more than once.
+//
+// The signer account must not be used concurrently, as otherwise an advanced
+// nonce does not imply that this transaction has been applied.
+func submitSignedTx(
+	ctx context.Context,
+	logger *logging.Logger,
+	cnsc consensus.ClientBackend,
+	signer signature.PublicKey,
+	tx *transaction.Transaction,
+	signedTx *transaction.SignedTransaction,
+) error {
+	sched := backoff.NewExponentialBackOff()
+	sched.MaxInterval = maxSubmissionRetryInterval
+	sched.MaxElapsedTime = maxSubmissionRetryElapsedTime
+
+	return backoff.Retry(func() error {
+		// Wait for a maximum of 60 seconds to submit transaction.
+		submitCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+		err := cnsc.SubmitTx(submitCtx, signedTx)
+		if err == nil {
+			return nil
+		}
+
+		// Check if the transaction has been applied even though the submission failed (e.g.,
+		// because the node was restarted after the transaction was included in a block).
+		nonce, nerr := cnsc.GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
+			ID:     signer,
+			Height: consensus.HeightLatest,
+		})
+		if nerr == nil && nonce > tx.Nonce {
+			logger.Warn("transaction submission failed, but transaction has been applied",
+				"err", err,
+				"tx_caller", signer,
+				"nonce", tx.Nonce,
+			)
+			return nil
+		}
+
+		// Transactions rejected by the consensus layer will not succeed on retry.
+		if module, _ := errors.Code(err); module != errors.UnknownModule && !errors.Is(err, transaction.ErrInvalidNonce) {
+			logger.Error("failed to submit transaction",
+				"err", err,
+				"tx", tx,
+				"signed_tx", signedTx,
+				"tx_caller", signer,
+			)
+			return backoff.Permanent(fmt.Errorf("cnsc.SubmitTx: %w", err))
+		}
+
+		logger.Warn("failed to submit transaction, retrying",
 			"err", err,
-			"tx", tx,
-			"signed_tx", signedTx,
-			"tx_caller", caller.Public(),
+			"tx_caller", signer,
+			"nonce", tx.Nonce,
 		)
 		return fmt.Errorf("cnsc.SubmitTx: %w", err)
-	}
+	}, backoff.WithContext(sched, ctx))
 }
 
 // transferFunds transfer funds between accounts.
+//
+// The from account must not be used concurrently.
 func transferFunds(ctx context.Context, logger *logging.Logger, cnsc consensus.ClientBackend, from signature.Signer, to signature.PublicKey, transferAmount int64) error {
+	return doTransferFunds(ctx, logger, cnsc, from, to, transferAmount, false)
+}
+
+func doTransferFunds(
+	ctx context.Context,
+	logger *logging.Logger,
+	cnsc consensus.ClientBackend,
+	from signature.Signer,
+	to signature.PublicKey,
+	transferAmount int64,
+	shared bool,
+) error {
+	var (
+		submitted     bool
+		lastNonceUsed uint64
+	)
 	sched := backoff.NewExponentialBackOff()
 	sched.MaxInterval = maxSubmissionRetryInterval
 	sched.MaxElapsedTime = maxSubmissionRetryElapsedTime
@@ -105,7 +172,20 @@ func transferFunds(ctx context.Context, logger *logging.Logger, cnsc consensus.C
 			Height: consensus.HeightLatest,
 		})
 		if err != nil {
-			return backoff.Permanent(fmt.Errorf("GetSignerNonce TestEntity error: %w", err))
+			// The node may be restarting, retry.
+			return fmt.Errorf("GetSignerNonce error: %w", err)
+		}
+
+		// In case a previous submission failed but its nonce has been used since, the previous
+		// transaction must have been applied unless the account is shared with other workloads.
+		// Retrying in this case would transfer the funds twice.
+		if submitted && !shared && nonce > lastNonceUsed {
+			logger.Warn("transfer submission failed, but transfer has been applied",
+				"from", from.Public(),
+				"to", to,
+				"nonce", lastNonceUsed,
+			)
+			return nil
 		}
 
 		transfer := staking.Transfer{
@@ -141,6 +221,7 @@ func transferFunds(ctx context.Context, logger *logging.Logger, cnsc consensus.C
 		// is skipping all CheckTx checks.
 		submitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
+		submitted, lastNonceUsed = true, nonce
 		if err = cnsc.SubmitTx(submitCtx, signedTx); err != nil {
 			// Expected errors are:
 			// - invalid nonce
@@ -175,6 +256,7 @@ type Workload interface {
 
 // ByName is the registry of workloads that you can access with `--workload <name>` on the command line.
 var ByName = map[string]Workload{
+	NameChurn:        &churn{},
 	NameCommission:   &commission{},
 	NameDelegation:   &delegation{},
 	NameOversized:    oversized{},
@@ -0,0 +1,291 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+)
+
+var diffGenesisCmd = &cobra.Command{
+	Use:   "diff <old genesis file> <new genesis file>",
+	Short: "show the field-level differences between two genesis files",
+	Args:  cobra.ExactArgs(2),
+	Run:   doDiffGenesis,
+}
+
+// differenceKind is the kind of a difference between two genesis documents.
+type differenceKind byte
+
+const (
+	differenceAdded   differenceKind = '+'
+	differenceRemoved differenceKind = '-'
+	differenceChanged differenceKind = '~'
+)
+
+// difference is a single field-level difference between two genesis
+// documents.
+type difference struct {
+	Kind differenceKind
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// String returns a string representation of a difference.
+func (d *difference) String() string {
+	switch d.Kind {
+	case differenceAdded:
+		return fmt.Sprintf("%c %s: %s", d.Kind, d.Path, formatDiffValue(d.New))
+	case differenceRemoved:
+		return fmt.Sprintf("%c %s: %s", d.Kind, d.Path, formatDiffValue(d.Old))
+	default:
+		return fmt.Sprintf("%c %s: %s -> %s", d.Kind, d.Path, formatDiffValue(d.Old), formatDiffValue(d.New))
+	}
+}
+
+func formatDiffValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+func doDiffGenesis(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var docs [2]*genesis.Document
+	for i, filename := range args {
+		doc, err := loadGenesisDocument(filename)
+		if err != nil {
+			logger.Error("failed to load genesis file",
+				"err", err,
+				"filename", filename,
+			)
+			os.Exit(1)
+		}
+		docs[i] = doc
+	}
+
+	diffs, err := diffGenesisDocuments(docs[0], docs[1])
+	if err != nil {
+		logger.Error("failed to diff genesis documents",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	writeDifferences(os.Stdout, diffs)
+}
+
+// loadGenesisDocument loads a genesis document without sanity checking it, so
+// that documents that are still being prepared can be compared as well.
+func loadGenesisDocument(filename string) (*genesis.Document, error) {
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc genesis.Document
+	if err = json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("malformed genesis file: %w", err)
+	}
+	return &doc, nil
+}
+
+func writeDifferences(w io.Writer, diffs []*difference) {
+	for _, d := range diffs {
+		fmt.Fprintln(w, d.String())
+	}
+}
+
+// diffGenesisDocuments returns the field-level differences between two genesis
+// documents, ordered by path.
+//
+// Signed registry descriptors are compared by their (unverified) contents and
+// matched by their IDs, instead of being compared as opaque signed blobs.
+func diffGenesisDocuments(oldDoc, newDoc *genesis.Document) ([]*difference, error) {
+	oldValue, err := diffableDocument(oldDoc)
+	if err != nil {
+		return nil, fmt.Errorf("old document: %w", err)
+	}
+	newValue, err := diffableDocument(newDoc)
+	if err != nil {
+		return nil, fmt.Errorf("new document: %w", err)
+	}
+
+	var diffs []*difference
+	diffValues("", oldValue, newValue, &diffs)
+	return diffs, nil
+}
+
+// diffableDocument converts a genesis document into its generic JSON
+// representation, with the signed registry descriptors replaced by maps of
+// descriptor IDs to descriptor contents.
+func diffableDocument(doc *genesis.Document) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := toDiffableValue(doc, &v); err != nil {
+		return nil, err
+	}
+
+	entities := make(map[string]interface{})
+	for _, sigEnt := range doc.Registry.Entities {
+		var ent entity.Entity
+		if err := cbor.Unmarshal(sigEnt.Blob, &ent); err != nil {
+			return nil, fmt.Errorf("malformed entity descriptor: %w", err)
+		}
+		if err := addDiffableDescriptor(entities, ent.ID.String(), &ent); err != nil {
+			return nil, err
+		}
+	}
+
+	nodes := make(map[string]interface{})
+	for _, sigNode := range doc.Registry.Nodes {
+		var n node.Node
+		if err := cbor.Unmarshal(sigNode.Blob, &n); err != nil {
+			return nil, fmt.Errorf("malformed node descriptor: %w", err)
+		}
+		if err := addDiffableDescriptor(nodes, n.ID.String(), &n); err != nil {
+			return nil, err
+		}
+	}
+
+	runtimes := make(map[string]interface{})
+	suspendedRuntimes := make(map[string]interface{})
+	for _, rts := range []struct {
+		sigRts []*registry.SignedRuntime
+		m      map[string]interface{}
+	}{
+		{doc.Registry.Runtimes, runtimes},
+		{doc.Registry.SuspendedRuntimes, suspendedRuntimes},
+	} {
+		for _, sigRt := range rts.sigRts {
+			var rt registry.Runtime
+			if err := cbor.Unmarshal(sigRt.Blob, &rt); err != nil {
+				return nil, fmt.Errorf("malformed runtime descriptor: %w", err)
+			}
+			if err := addDiffableDescriptor(rts.m, rt.ID.String(), &rt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	reg, _ := v["registry"].(map[string]interface{})
+	if reg == nil {
+		reg = make(map[string]interface{})
+		v["registry"] = reg
+	}
+	reg["entities"] = entities
+	reg["nodes"] = nodes
+	reg["runtimes"] = runtimes
+	reg["suspended_runtimes"] = suspendedRuntimes
+
+	return v, nil
+}
+
+func addDiffableDescriptor(m map[string]interface{}, id string, descriptor interface{}) error {
+	var v interface{}
+	if err := toDiffableValue(descriptor, &v); err != nil {
+		return err
+	}
+	m[id] = v
+	return nil
+}
+
+// toDiffableValue converts the given value into its generic JSON
+// representation, keeping numbers as json.Number to avoid losing precision.
+func toDiffableValue(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed to marshal into JSON: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(dst); err != nil {
+		return fmt.Errorf("failed to unmarshal from JSON: %w", err)
+	}
+	return nil
+}
+
+func diffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func diffValues(path string, oldValue, newValue interface{}, diffs *[]*difference) {
+	switch o := oldValue.(type) {
+	case map[string]interface{}:
+		n, ok := newValue.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make(map[string]bool)
+		for k := range o {
+			keys[k] = true
+		}
+		for k := range n {
+			keys[k] = true
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, k := range sortedKeys {
+			ov, inOld := o[k]
+			nv, inNew := n[k]
+			switch {
+			case !inOld:
+				*diffs = append(*diffs, &difference{Kind: differenceAdded, Path: diffPath(path, k), New: nv})
+			case !inNew:
+				*diffs = append(*diffs, &difference{Kind: differenceRemoved, Path: diffPath(path, k), Old: ov})
+			default:
+				diffValues(diffPath(path, k), ov, nv, diffs)
+			}
+		}
+		return
+	case []interface{}:
+		n, ok := newValue.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < len(o) || i < len(n); i++ {
+			elemPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(o):
+				*diffs = append(*diffs, &difference{Kind: differenceAdded, Path: elemPath, New: n[i]})
+			case i >= len(n):
+				*diffs = append(*diffs, &difference{Kind: differenceRemoved, Path: elemPath, Old: o[i]})
+			default:
+				diffValues(elemPath, o[i], n[i], diffs)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*diffs = append(*diffs, &difference{Kind: differenceChanged, Path: path, Old: oldValue, New: newValue})
+	}
+}
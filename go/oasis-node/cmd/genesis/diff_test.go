@@ -0,0 +1,93 @@
+package genesis
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+func mustSignEntity(t *testing.T, signer signature.Signer, ent *entity.Entity) *entity.SignedEntity {
+	sigEnt, err := entity.SignEntity(signer, registry.RegisterGenesisEntitySignatureContext, ent)
+	require.NoError(t, err, "SignEntity")
+	return sigEnt
+}
+
+func mustQuantity(t *testing.T, n uint64) quantity.Quantity {
+	var q quantity.Quantity
+	require.NoError(t, q.FromUint64(n), "FromUint64")
+	return q
+}
+
+func TestDiffGenesisDocuments(t *testing.T) {
+	require := require.New(t)
+
+	signer1 := memorySigner.NewTestSigner("oasis-node/cmd/genesis: entity 1")
+	signer2 := memorySigner.NewTestSigner("oasis-node/cmd/genesis: entity 2")
+	ent1 := &entity.Entity{ID: signer1.Public()}
+	ent2 := &entity.Entity{ID: signer2.Public()}
+
+	newDoc := func() *genesis.Document {
+		return &genesis.Document{
+			Height:  1,
+			ChainID: "diff-test",
+			Registry: registry.Genesis{
+				Entities: []*entity.SignedEntity{mustSignEntity(t, signer1, ent1)},
+			},
+			Staking: staking.Genesis{
+				Ledger: map[signature.PublicKey]*staking.Account{
+					signer1.Public(): {General: staking.GeneralAccount{Balance: mustQuantity(t, 100)}},
+				},
+			},
+		}
+	}
+
+	// Identical documents should have no differences.
+	diffs, err := diffGenesisDocuments(newDoc(), newDoc())
+	require.NoError(err, "diffGenesisDocuments")
+	require.Empty(diffs, "identical documents should have no differences")
+
+	// Entities with different signatures but identical contents should
+	// have no differences.
+	doc := newDoc()
+	ent1Copy := *ent1
+	doc.Registry.Entities[0] = mustSignEntity(t, signer1, &ent1Copy)
+	doc.Registry.Entities[0].Signature.Signature[0] ^= 0xff
+	diffs, err = diffGenesisDocuments(newDoc(), doc)
+	require.NoError(err, "diffGenesisDocuments")
+	require.Empty(diffs, "signatures should not be compared")
+
+	doc = newDoc()
+	doc.ChainID = "diff-test-2"
+	doc.Registry.Entities = append(doc.Registry.Entities, mustSignEntity(t, signer2, ent2))
+	doc.Staking.Ledger[signer1.Public()].General.Balance = mustQuantity(t, 200)
+	doc.Scheduler.Parameters.MaxValidators = 10
+	diffs, err = diffGenesisDocuments(newDoc(), doc)
+	require.NoError(err, "diffGenesisDocuments")
+
+	var buf bytes.Buffer
+	writeDifferences(&buf, diffs)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal([]string{
+		`~ chain_id: "diff-test" -> "diff-test-2"`,
+		`+ registry.entities.` + ent2.ID.String() + `: {"allow_entity_signed_nodes":false,"id":"` + ent2.ID.String() + `","nodes":null}`,
+		`~ scheduler.params.max_validators: 0 -> 10`,
+		`~ staking.ledger.` + signer1.Public().String() + `.general.balance: "100" -> "200"`,
+	}, lines, "differences should be field-level and ordered by path")
+
+	// Removals should be reported as well.
+	diffs, err = diffGenesisDocuments(doc, newDoc())
+	require.NoError(err, "diffGenesisDocuments")
+	require.Len(diffs, 4)
+	require.Equal(differenceRemoved, diffs[1].Kind, "entity should be removed")
+	require.Equal("registry.entities."+ent2.ID.String(), diffs[1].Path)
+}
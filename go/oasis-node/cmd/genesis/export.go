@@ -0,0 +1,237 @@
+package genesis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/grpc"
+	storage "github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+)
+
+const (
+	cfgCheckpointManifest = "checkpoint_manifest"
+
+	// checkpointVersion is the version of the checkpoints referenced in
+	// the exported genesis document.
+	checkpointVersion = 1
+)
+
+var (
+	exportGenesisFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	exportGenesisCmd = &cobra.Command{
+		Use:   "export",
+		Short: "dump state into genesis file with runtime state pinned to storage checkpoints",
+		Run:   doExportGenesis,
+	}
+)
+
+func doExportGenesis(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	manifestFile := viper.GetString(cfgCheckpointManifest)
+	if manifestFile == "" {
+		logger.Error("checkpoint manifest file must be set")
+		os.Exit(1)
+	}
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	client := consensus.NewConsensusClient(conn)
+
+	doc, err := client.StateToGenesis(ctx, viper.GetInt64(cfgBlockHeight))
+	if err != nil {
+		logger.Error("failed to generate genesis document",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = filterDumpedGenesis(doc); err != nil {
+		logger.Error("failed to filter genesis document",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	manifest, err := pinRuntimeCheckpoints(ctx, storage.NewStorageClient(conn), doc)
+	if err != nil {
+		logger.Error("failed to pin runtime state checkpoints",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		logger.Error("failed to marshal checkpoint manifest into JSON",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(manifestFile, data, 0o600); err != nil {
+		logger.Error("failed to write checkpoint manifest",
+			"err", err,
+			"filename", manifestFile,
+		)
+		os.Exit(1)
+	}
+
+	writeGenesisDocument(cmd, doc)
+}
+
+// pinRuntimeCheckpoints references a storage checkpoint of the state of each
+// runtime with non-empty state in the given genesis document and returns the
+// corresponding checkpoint manifest.
+//
+// All checkpoint chunks are fetched and verified to ensure that the
+// checkpoints are complete and available.
+func pinRuntimeCheckpoints(ctx context.Context, chunkProvider checkpoint.ChunkProvider, doc *genesis.Document) (*genesis.CheckpointManifest, error) {
+	storageNodes, err := runtimeStorageNodes(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &genesis.CheckpointManifest{
+		Runtimes: make(map[common.Namespace]*genesis.RuntimeCheckpointManifest),
+	}
+	for id, rtg := range doc.RootHash.RuntimeStates {
+		if rtg.StateRoot.IsEmpty() {
+			continue
+		}
+
+		logger.Info("pinning runtime state checkpoint",
+			"runtime_id", id,
+			"round", rtg.Round,
+			"state_root", rtg.StateRoot,
+		)
+
+		round := rtg.Round
+		cps, err := chunkProvider.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
+			Version:     checkpointVersion,
+			Namespace:   id,
+			RootVersion: &round,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checkpoints for runtime %s: %w", id, err)
+		}
+
+		var cp *checkpoint.Metadata
+		for _, c := range cps {
+			if c.Root.Hash.Equal(&rtg.StateRoot) {
+				cp = c
+				break
+			}
+		}
+		if cp == nil {
+			return nil, fmt.Errorf("no checkpoint of runtime %s state at round %d", id, rtg.Round)
+		}
+
+		chunkSizes, err := fetchCheckpointChunks(ctx, chunkProvider, cp)
+		if err != nil {
+			return nil, fmt.Errorf("runtime %s: %w", id, err)
+		}
+
+		rtg.Checkpoint = cp
+		manifest.Runtimes[id] = &genesis.RuntimeCheckpointManifest{
+			Checkpoint:   *cp,
+			ChunkSizes:   chunkSizes,
+			StorageNodes: storageNodes[id],
+		}
+	}
+	manifest.GenesisHash = doc.Hash()
+
+	return manifest, nil
+}
+
+// chunkWriter computes the size and digest of a checkpoint chunk.
+type chunkWriter struct {
+	size uint64
+	hb   *hash.Builder
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.size += uint64(len(p))
+	return w.hb.Write(p)
+}
+
+// fetchCheckpointChunks fetches all of the checkpoint chunks, verifies their
+// digests and returns their sizes.
+func fetchCheckpointChunks(ctx context.Context, chunkProvider checkpoint.ChunkProvider, cp *checkpoint.Metadata) ([]uint64, error) {
+	chunkSizes := make([]uint64, 0, len(cp.Chunks))
+	for idx := range cp.Chunks {
+		chunk, err := cp.GetChunkMetadata(uint64(idx))
+		if err != nil {
+			return nil, err
+		}
+
+		w := &chunkWriter{hb: hash.NewBuilder()}
+		if err = chunkProvider.GetCheckpointChunk(ctx, chunk, w); err != nil {
+			return nil, fmt.Errorf("failed to fetch checkpoint chunk %d: %w", idx, err)
+		}
+		if digest := w.hb.Build(); !digest.Equal(&chunk.Digest) {
+			return nil, fmt.Errorf("%w: chunk %d digest incorrect (expected: %s got: %s)",
+				checkpoint.ErrChunkCorrupted,
+				idx,
+				chunk.Digest,
+				digest,
+			)
+		}
+		chunkSizes = append(chunkSizes, w.size)
+	}
+	return chunkSizes, nil
+}
+
+// runtimeStorageNodes returns the storage nodes of each runtime, as registered
+// in the given genesis document.
+func runtimeStorageNodes(doc *genesis.Document) (map[common.Namespace][]*genesis.CheckpointStorageNode, error) {
+	storageNodes := make(map[common.Namespace][]*genesis.CheckpointStorageNode)
+	for _, sigNode := range doc.Registry.Nodes {
+		// The descriptors have been verified when the nodes were registered.
+		var n node.Node
+		if err := cbor.Unmarshal(sigNode.Blob, &n); err != nil {
+			return nil, fmt.Errorf("malformed node descriptor: %w", err)
+		}
+		if !n.HasRoles(node.RoleStorageWorker) {
+			continue
+		}
+
+		for _, rt := range n.Runtimes {
+			storageNodes[rt.ID] = append(storageNodes[rt.ID], &genesis.CheckpointStorageNode{
+				ID:        n.ID,
+				Addresses: n.TLS.Addresses,
+			})
+		}
+	}
+	return storageNodes, nil
+}
+
+func init() {
+	exportGenesisFlags.String(cfgCheckpointManifest, "", "path to the checkpoint manifest output file")
+	_ = viper.BindPFlags(exportGenesisFlags)
+}
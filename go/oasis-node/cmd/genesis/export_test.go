@@ -0,0 +1,129 @@
+package genesis
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
+	storage "github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+)
+
+type testChunkProvider struct {
+	checkpoints []*checkpoint.Metadata
+	chunks      map[hash.Hash][]byte
+}
+
+func (p *testChunkProvider) GetCheckpoints(ctx context.Context, request *checkpoint.GetCheckpointsRequest) ([]*checkpoint.Metadata, error) {
+	var cps []*checkpoint.Metadata
+	for _, cp := range p.checkpoints {
+		if !cp.Root.Namespace.Equal(&request.Namespace) {
+			continue
+		}
+		if request.RootVersion != nil && cp.Root.Version != *request.RootVersion {
+			continue
+		}
+		cps = append(cps, cp)
+	}
+	return cps, nil
+}
+
+func (p *testChunkProvider) GetCheckpointChunk(ctx context.Context, chunk *checkpoint.ChunkMetadata, w io.Writer) error {
+	data, ok := p.chunks[chunk.Digest]
+	if !ok {
+		return checkpoint.ErrChunkNotFound
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func TestPinRuntimeCheckpoints(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"), "UnmarshalHex")
+	stateRoot := hash.NewFromBytes([]byte("state root"))
+
+	chunks := [][]byte{[]byte("chunk 0"), []byte("longer chunk 1")}
+	provider := &testChunkProvider{chunks: make(map[hash.Hash][]byte)}
+	cp := &checkpoint.Metadata{
+		Version: checkpointVersion,
+		Root: storage.Root{
+			Namespace: runtimeID,
+			Version:   42,
+			Hash:      stateRoot,
+		},
+	}
+	for _, data := range chunks {
+		digest := hash.NewFromBytes(data)
+		cp.Chunks = append(cp.Chunks, digest)
+		provider.chunks[digest] = data
+	}
+	provider.checkpoints = []*checkpoint.Metadata{cp}
+
+	nodeSigner := memorySigner.NewTestSigner("oasis-node/cmd/genesis: storage node")
+	storageNode := &node.Node{
+		DescriptorVersion: node.LatestNodeDescriptorVersion,
+		ID:                nodeSigner.Public(),
+		Roles:             node.RoleStorageWorker,
+		Runtimes:          []*node.Runtime{{ID: runtimeID}},
+	}
+	sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterGenesisNodeSignatureContext, storageNode)
+	require.NoError(err, "MultiSignNode")
+
+	newDoc := func(round uint64) *genesis.Document {
+		return &genesis.Document{
+			ChainID: "export-test",
+			Registry: registry.Genesis{
+				Nodes: []*node.MultiSignedNode{sigNode},
+			},
+			RootHash: roothash.Genesis{
+				RuntimeStates: map[common.Namespace]*registry.RuntimeGenesis{
+					runtimeID: {
+						StateRoot: stateRoot,
+						Round:     round,
+					},
+				},
+			},
+		}
+	}
+
+	doc := newDoc(42)
+	manifest, err := pinRuntimeCheckpoints(context.Background(), provider, doc)
+	require.NoError(err, "pinRuntimeCheckpoints")
+	require.NoError(manifest.Verify(doc), "manifest should match the genesis document")
+
+	rtg := doc.RootHash.RuntimeStates[runtimeID]
+	require.NotNil(rtg.Checkpoint, "checkpoint should be pinned in genesis")
+	require.EqualValues(cp, rtg.Checkpoint)
+	require.NoError(rtg.SanityCheck(true), "runtime genesis with checkpoint should be sane")
+
+	rtm := manifest.Runtimes[runtimeID]
+	require.NotNil(rtm, "manifest should contain the runtime")
+	require.Equal([]uint64{7, 14}, rtm.ChunkSizes, "chunk sizes should be correct")
+	require.Len(rtm.StorageNodes, 1, "manifest should contain the storage node")
+	require.Equal(storageNode.ID, rtm.StorageNodes[0].ID)
+
+	// Modifying the genesis document should invalidate the manifest.
+	doc.ChainID = "export-test-2"
+	require.Error(manifest.Verify(doc), "manifest should not match a different genesis document")
+
+	// Exporting should fail when there is no checkpoint for the genesis round.
+	_, err = pinRuntimeCheckpoints(context.Background(), provider, newDoc(43))
+	require.Error(err, "pinRuntimeCheckpoints should fail without a checkpoint")
+
+	// Exporting should fail when a chunk is corrupted.
+	provider.chunks[cp.Chunks[1]] = []byte("corrupted chunk")
+	_, err = pinRuntimeCheckpoints(context.Background(), provider, newDoc(42))
+	require.Error(err, "pinRuntimeCheckpoints should fail with a corrupted chunk")
+}
@@ -0,0 +1,212 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+)
+
+const (
+	cfgDumpDropDebugEntities = "dump.drop_debug_entities"
+	cfgDumpDropExpiredNodes  = "dump.drop_expired_nodes"
+	cfgDumpZeroBalancesBelow = "dump.zero_balances_below"
+	cfgDumpChainID           = "dump.chain_id"
+	cfgDumpReport            = "dump.report"
+)
+
+// dumpFilterFlags are the flags for filtering dumped genesis documents.
+var dumpFilterFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// dumpFilter is a filter applied to a dumped genesis document, e.g. to
+// prepare a fork of the network state.
+type dumpFilter struct {
+	// dropDebugEntities drops the built-in debug test entity and its nodes.
+	dropDebugEntities bool
+	// dropExpiredNodes drops the nodes that expired before the base epoch.
+	dropExpiredNodes bool
+	// zeroBalancesBelow moves general balances that are non-zero and below
+	// the threshold into the common pool.
+	zeroBalancesBelow *quantity.Quantity
+	// chainID is the new chain ID.
+	chainID string
+}
+
+// filterReport is the report of the changes made by a dump filter.
+type filterReport struct {
+	// ChainID is the chain ID of the filtered document.
+	ChainID string `json:"chain_id"`
+	// PreviousChainID is set iff the chain ID was remapped.
+	PreviousChainID string `json:"previous_chain_id,omitempty"`
+	// DroppedEntities are the dropped entities.
+	DroppedEntities []signature.PublicKey `json:"dropped_entities,omitempty"`
+	// DroppedNodes are the dropped nodes.
+	DroppedNodes []signature.PublicKey `json:"dropped_nodes,omitempty"`
+	// ZeroedBalances are the general balances moved into the common pool.
+	ZeroedBalances map[signature.PublicKey]quantity.Quantity `json:"zeroed_balances,omitempty"`
+}
+
+// newDumpFilter creates a new dump filter from the configuration.
+func newDumpFilter() (*dumpFilter, error) {
+	f := &dumpFilter{
+		dropDebugEntities: viper.GetBool(cfgDumpDropDebugEntities),
+		dropExpiredNodes:  viper.GetBool(cfgDumpDropExpiredNodes),
+		chainID:           viper.GetString(cfgDumpChainID),
+	}
+	if s := viper.GetString(cfgDumpZeroBalancesBelow); s != "" {
+		var q quantity.Quantity
+		if err := q.UnmarshalText([]byte(s)); err != nil {
+			return nil, fmt.Errorf("malformed balance threshold: %w", err)
+		}
+		f.zeroBalancesBelow = &q
+	}
+	return f, nil
+}
+
+// apply applies the filter to the given genesis document in place.
+func (f *dumpFilter) apply(doc *genesis.Document) (*filterReport, error) {
+	report := &filterReport{
+		ChainID: doc.ChainID,
+	}
+
+	if f.chainID != "" && f.chainID != doc.ChainID {
+		report.PreviousChainID = doc.ChainID
+		report.ChainID = f.chainID
+		doc.ChainID = f.chainID
+	}
+
+	droppedEntities := make(map[signature.PublicKey]bool)
+	if f.dropDebugEntities {
+		testEntity, _, _ := entity.TestEntity()
+		droppedEntities[testEntity.ID] = true
+	}
+	if err := f.filterRegistry(doc, droppedEntities, report); err != nil {
+		return nil, err
+	}
+
+	if f.zeroBalancesBelow != nil {
+		if err := f.zeroBalances(doc, report); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func (f *dumpFilter) filterRegistry(doc *genesis.Document, droppedEntities map[signature.PublicKey]bool, report *filterReport) error {
+	entities := doc.Registry.Entities[:0]
+	for _, sigEnt := range doc.Registry.Entities {
+		// The descriptors have been verified when the entities were registered.
+		var ent entity.Entity
+		if err := cbor.Unmarshal(sigEnt.Blob, &ent); err != nil {
+			return fmt.Errorf("malformed entity descriptor: %w", err)
+		}
+		if droppedEntities[ent.ID] {
+			report.DroppedEntities = append(report.DroppedEntities, ent.ID)
+			continue
+		}
+		entities = append(entities, sigEnt)
+	}
+	doc.Registry.Entities = entities
+
+	// Runtimes cannot be dropped without also dropping their state, so
+	// refuse to drop the entities that own them.
+	for _, sigRts := range [][]*registry.SignedRuntime{doc.Registry.Runtimes, doc.Registry.SuspendedRuntimes} {
+		for _, sigRt := range sigRts {
+			var rt registry.Runtime
+			if err := cbor.Unmarshal(sigRt.Blob, &rt); err != nil {
+				return fmt.Errorf("malformed runtime descriptor: %w", err)
+			}
+			if droppedEntities[rt.EntityID] {
+				return fmt.Errorf("can't drop entity %s owning runtime %s", rt.EntityID, rt.ID)
+			}
+		}
+	}
+
+	nodes := doc.Registry.Nodes[:0]
+	for _, sigNode := range doc.Registry.Nodes {
+		var n node.Node
+		if err := cbor.Unmarshal(sigNode.Blob, &n); err != nil {
+			return fmt.Errorf("malformed node descriptor: %w", err)
+		}
+		if droppedEntities[n.EntityID] || (f.dropExpiredNodes && n.IsExpired(uint64(doc.EpochTime.Base))) {
+			report.DroppedNodes = append(report.DroppedNodes, n.ID)
+			delete(doc.Registry.NodeStatuses, n.ID)
+			continue
+		}
+		nodes = append(nodes, sigNode)
+	}
+	doc.Registry.Nodes = nodes
+
+	return nil
+}
+
+func (f *dumpFilter) zeroBalances(doc *genesis.Document, report *filterReport) error {
+	for id, acct := range doc.Staking.Ledger {
+		balance := acct.General.Balance.Clone()
+		if balance.IsZero() || balance.Cmp(f.zeroBalancesBelow) >= 0 {
+			continue
+		}
+		if err := quantity.Move(&doc.Staking.CommonPool, &acct.General.Balance, balance); err != nil {
+			return fmt.Errorf("failed to zero balance of account %s: %w", id, err)
+		}
+		if report.ZeroedBalances == nil {
+			report.ZeroedBalances = make(map[signature.PublicKey]quantity.Quantity)
+		}
+		report.ZeroedBalances[id] = *balance
+	}
+	return nil
+}
+
+// filterDumpedGenesis applies the configured dump filter to the given genesis
+// document and logs and writes the report.
+func filterDumpedGenesis(doc *genesis.Document) error {
+	f, err := newDumpFilter()
+	if err != nil {
+		return err
+	}
+	report, err := f.apply(doc)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("filtered genesis document",
+		"chain_id", report.ChainID,
+		"previous_chain_id", report.PreviousChainID,
+		"dropped_entities", len(report.DroppedEntities),
+		"dropped_nodes", len(report.DroppedNodes),
+		"zeroed_balances", len(report.ZeroedBalances),
+	)
+
+	reportFile := viper.GetString(cfgDumpReport)
+	if reportFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter report into JSON: %w", err)
+	}
+	if err = ioutil.WriteFile(reportFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write filter report: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	dumpFilterFlags.Bool(cfgDumpDropDebugEntities, false, "drop the debug test entity and its nodes")
+	dumpFilterFlags.Bool(cfgDumpDropExpiredNodes, false, "drop nodes that expired before the base epoch")
+	dumpFilterFlags.String(cfgDumpZeroBalancesBelow, "", "move general balances below the threshold into the common pool")
+	dumpFilterFlags.String(cfgDumpChainID, "", "remap the chain id")
+	dumpFilterFlags.String(cfgDumpReport, "", "path to the filter report output file")
+	_ = viper.BindPFlags(dumpFilterFlags)
+}
@@ -0,0 +1,123 @@
+package genesis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+func TestDumpFilter(t *testing.T) {
+	require := require.New(t)
+
+	testEntity, testEntitySigner, err := entity.TestEntity()
+	require.NoError(err, "TestEntity")
+	entitySigner := memorySigner.NewTestSigner("oasis-node/cmd/genesis: filter entity")
+	ent := &entity.Entity{
+		DescriptorVersion: entity.LatestEntityDescriptorVersion,
+		ID:                entitySigner.Public(),
+	}
+
+	var sigEnts []*entity.SignedEntity
+	for _, v := range []struct {
+		signer signature.Signer
+		ent    *entity.Entity
+	}{
+		{testEntitySigner, testEntity},
+		{entitySigner, ent},
+	} {
+		sigEnt, err := entity.SignEntity(v.signer, registry.RegisterGenesisEntitySignatureContext, v.ent)
+		require.NoError(err, "SignEntity")
+		sigEnts = append(sigEnts, sigEnt)
+	}
+
+	var (
+		sigNodes []*node.MultiSignedNode
+		nodeIDs  []signature.PublicKey
+	)
+	for i, v := range []struct {
+		entityID   signature.PublicKey
+		expiration uint64
+	}{
+		{testEntity.ID, 20},
+		{ent.ID, 5},
+		{ent.ID, 20},
+	} {
+		nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("oasis-node/cmd/genesis: filter node %d", i))
+		n := &node.Node{
+			DescriptorVersion: node.LatestNodeDescriptorVersion,
+			ID:                nodeSigner.Public(),
+			EntityID:          v.entityID,
+			Expiration:        v.expiration,
+		}
+		sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterGenesisNodeSignatureContext, n)
+		require.NoError(err, "MultiSignNode")
+		sigNodes = append(sigNodes, sigNode)
+		nodeIDs = append(nodeIDs, n.ID)
+	}
+
+	doc := &genesis.Document{
+		ChainID: "filter-test",
+		EpochTime: epochtime.Genesis{
+			Base: 10,
+		},
+		Registry: registry.Genesis{
+			Entities: sigEnts,
+			Nodes:    sigNodes,
+			NodeStatuses: map[signature.PublicKey]*registry.NodeStatus{
+				nodeIDs[0]: {},
+				nodeIDs[1]: {},
+				nodeIDs[2]: {},
+			},
+		},
+		Staking: staking.Genesis{
+			CommonPool: mustQuantity(t, 100),
+			Ledger: map[signature.PublicKey]*staking.Account{
+				testEntity.ID: {General: staking.GeneralAccount{Balance: mustQuantity(t, 5)}},
+				ent.ID:        {General: staking.GeneralAccount{Balance: mustQuantity(t, 50)}},
+			},
+		},
+	}
+
+	threshold := mustQuantity(t, 10)
+	f := &dumpFilter{
+		dropDebugEntities: true,
+		dropExpiredNodes:  true,
+		zeroBalancesBelow: &threshold,
+		chainID:           "filter-test-fork",
+	}
+	report, err := f.apply(doc)
+	require.NoError(err, "apply")
+
+	require.Equal("filter-test-fork", doc.ChainID, "chain id should be remapped")
+	require.Equal("filter-test", report.PreviousChainID, "previous chain id should be reported")
+
+	require.Equal([]*entity.SignedEntity{sigEnts[1]}, doc.Registry.Entities, "debug entity should be dropped")
+	require.Equal([]signature.PublicKey{testEntity.ID}, report.DroppedEntities)
+
+	require.Equal([]*node.MultiSignedNode{sigNodes[2]}, doc.Registry.Nodes, "debug entity and expired nodes should be dropped")
+	require.Equal(nodeIDs[:2], report.DroppedNodes)
+	require.Len(doc.Registry.NodeStatuses, 1, "statuses of dropped nodes should be dropped")
+	require.NotNil(doc.Registry.NodeStatuses[nodeIDs[2]], "status of kept node should be kept")
+
+	require.True(doc.Staking.Ledger[testEntity.ID].General.Balance.IsZero(), "balance below threshold should be zeroed")
+	require.Equal(mustQuantity(t, 50), doc.Staking.Ledger[ent.ID].General.Balance, "balance above threshold should be kept")
+	require.Equal(mustQuantity(t, 105), doc.Staking.CommonPool, "zeroed balance should be moved into the common pool")
+	require.Equal(map[signature.PublicKey]quantity.Quantity{testEntity.ID: mustQuantity(t, 5)}, report.ZeroedBalances)
+
+	// An empty filter should not change anything.
+	report, err = (&dumpFilter{}).apply(doc)
+	require.NoError(err, "apply")
+	require.Equal(&filterReport{ChainID: "filter-test-fork"}, report, "empty filter should not change anything")
+	require.Len(doc.Registry.Nodes, 1, "empty filter should not drop nodes")
+}
@@ -55,17 +55,22 @@ const (
 	// Registry config flags.
 	CfgRegistryMaxNodeExpiration                      = "registry.max_node_expiration"
 	CfgRegistryDisableRuntimeRegistration             = "registry.disable_runtime_registration"
+	cfgRegistryReadinessAttestationExpiration         = "registry.readiness_attestation_expiration"
 	cfgRegistryDebugAllowUnroutableAddresses          = "registry.debug.allow_unroutable_addresses"
 	CfgRegistryDebugAllowTestRuntimes                 = "registry.debug.allow_test_runtimes"
 	cfgRegistryDebugAllowEntitySignedNodeRegistration = "registry.debug.allow_entity_signed_registration"
 	cfgRegistryDebugBypassStake                       = "registry.debug.bypass_stake" // nolint: gosec
 
 	// Scheduler config flags.
-	cfgSchedulerMinValidators          = "scheduler.min_validators"
-	cfgSchedulerMaxValidators          = "scheduler.max_validators"
-	cfgSchedulerMaxValidatorsPerEntity = "scheduler.max_validators_per_entity"
-	cfgSchedulerDebugBypassStake       = "scheduler.debug.bypass_stake" // nolint: gosec
-	cfgSchedulerDebugStaticValidators  = "scheduler.debug.static_validators"
+	cfgSchedulerMinValidators                  = "scheduler.min_validators"
+	cfgSchedulerMaxValidators                  = "scheduler.max_validators"
+	cfgSchedulerMaxValidatorsPerEntity         = "scheduler.max_validators_per_entity"
+	cfgSchedulerMaxCommitteeMembersPerEntity   = "scheduler.max_committee_members_per_entity"
+	cfgSchedulerKeepCommitteeOnElectionFailure = "scheduler.keep_committee_on_election_failure"
+	cfgSchedulerRuntimeReadinessPolicy         = "scheduler.runtime_readiness_policy"
+	cfgSchedulerElectionOffset                 = "scheduler.election_offset"
+	cfgSchedulerDebugBypassStake               = "scheduler.debug.bypass_stake" // nolint: gosec
+	cfgSchedulerDebugStaticValidators          = "scheduler.debug.static_validators"
 
 	// Beacon config flags.
 	cfgBeaconDebugDeterministic = "beacon.debug.deterministic"
@@ -73,11 +78,18 @@ const (
 	// EpochTime config flags.
 	cfgEpochTimeDebugMockBackend   = "epochtime.debug.mock_backend"
 	cfgEpochTimeTendermintInterval = "epochtime.tendermint.interval"
+	cfgEpochTimeUpdateAuthorities  = "epochtime.parameter_update_authorities"
 
 	// Roothash config flags.
+	cfgRoothashMaxRuntimeMessages        = "roothash.max_runtime_messages"
+	cfgRoothashMaxRuntimeMessagesSize    = "roothash.max_runtime_messages_size"
+	cfgRoothashRuntimeMessageGasPrice    = "roothash.runtime_message_gas_price"
+	cfgRoothashSuspensionAuthorities     = "roothash.runtime_suspension_authorities"
 	cfgRoothashDebugDoNotSuspendRuntimes = "roothash.debug.do_not_suspend_runtimes"
 	cfgRoothashDebugBypassStake          = "roothash.debug.bypass_stake" // nolint: gosec
 
+	CfgRoothashDebugAllowForceRoundTimeout = "roothash.debug.allow_force_round_timeout"
+
 	// Tendermint config flags.
 	cfgConsensusTimeoutCommit        = "consensus.tendermint.timeout_commit"
 	cfgConsensusSkipTimeoutCommit    = "consensus.tendermint.skip_timeout_commit"
@@ -194,11 +206,15 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 
 	doc.Scheduler = scheduler.Genesis{
 		Parameters: scheduler.ConsensusParameters{
-			MinValidators:          viper.GetInt(cfgSchedulerMinValidators),
-			MaxValidators:          viper.GetInt(cfgSchedulerMaxValidators),
-			MaxValidatorsPerEntity: viper.GetInt(cfgSchedulerMaxValidatorsPerEntity),
-			DebugBypassStake:       viper.GetBool(cfgSchedulerDebugBypassStake),
-			DebugStaticValidators:  viper.GetBool(cfgSchedulerDebugStaticValidators),
+			MinValidators:                  viper.GetInt(cfgSchedulerMinValidators),
+			MaxValidators:                  viper.GetInt(cfgSchedulerMaxValidators),
+			MaxValidatorsPerEntity:         viper.GetInt(cfgSchedulerMaxValidatorsPerEntity),
+			MaxCommitteeMembersPerEntity:   viper.GetInt(cfgSchedulerMaxCommitteeMembersPerEntity),
+			KeepCommitteeOnElectionFailure: viper.GetBool(cfgSchedulerKeepCommitteeOnElectionFailure),
+			RuntimeReadinessPolicy:         scheduler.ReadinessPolicy(viper.GetUint(cfgSchedulerRuntimeReadinessPolicy)),
+			ElectionOffset:                 viper.GetUint64(cfgSchedulerElectionOffset),
+			DebugBypassStake:               viper.GetBool(cfgSchedulerDebugBypassStake),
+			DebugStaticValidators:          viper.GetBool(cfgSchedulerDebugStaticValidators),
 		},
 	}
 
@@ -212,8 +228,21 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 		Parameters: epochtime.ConsensusParameters{
 			DebugMockBackend: viper.GetBool(cfgEpochTimeDebugMockBackend),
 			Interval:         viper.GetInt64(cfgEpochTimeTendermintInterval),
+			// TODO: Make these configurable.
+			GasCosts: epochtime.DefaultGasCosts,
 		},
 	}
+	for _, v := range viper.GetStringSlice(cfgEpochTimeUpdateAuthorities) {
+		var id signature.PublicKey
+		if err := id.UnmarshalText([]byte(v)); err != nil {
+			logger.Error("failed to parse epochtime parameter update authority",
+				"err", err,
+				"authority", v,
+			)
+			return
+		}
+		doc.EpochTime.Parameters.ParameterUpdateAuthorities = append(doc.EpochTime.Parameters.ParameterUpdateAuthorities, id)
+	}
 
 	doc.Consensus = consensusGenesis.Genesis{
 		Backend: viper.GetString(cfgConsensusBackend),
@@ -263,6 +292,7 @@ func AppendRegistryState(doc *genesis.Document, entities, runtimes, nodes []stri
 			GasCosts:                               registry.DefaultGasCosts, // TODO: Make these configurable.
 			MaxNodeExpiration:                      viper.GetUint64(CfgRegistryMaxNodeExpiration),
 			DisableRuntimeRegistration:             viper.GetBool(CfgRegistryDisableRuntimeRegistration),
+			ReadinessAttestationExpiration:         viper.GetUint64(cfgRegistryReadinessAttestationExpiration),
 		},
 		Entities: make([]*entity.SignedEntity, 0, len(entities)),
 		Runtimes: make([]*registry.SignedRuntime, 0, len(runtimes)),
@@ -400,13 +430,39 @@ func AppendRootHashState(doc *genesis.Document, exports []string, l *logging.Log
 		RuntimeStates: make(map[common.Namespace]*registry.RuntimeGenesis),
 
 		Parameters: roothash.ConsensusParameters{
+			MaxRuntimeMessages:        viper.GetUint32(cfgRoothashMaxRuntimeMessages),
+			MaxRuntimeMessagesSize:    viper.GetUint64(cfgRoothashMaxRuntimeMessagesSize),
 			DebugDoNotSuspendRuntimes: viper.GetBool(cfgRoothashDebugDoNotSuspendRuntimes),
 			DebugBypassStake:          viper.GetBool(cfgRoothashDebugBypassStake),
+
+			DebugAllowForceRoundTimeout: viper.GetBool(CfgRoothashDebugAllowForceRoundTimeout),
 			// TODO: Make these configurable.
 			GasCosts: roothash.DefaultGasCosts,
 		},
 	}
 
+	for _, v := range viper.GetStringSlice(cfgRoothashSuspensionAuthorities) {
+		var id signature.PublicKey
+		if err := id.UnmarshalText([]byte(v)); err != nil {
+			l.Error("failed to parse runtime suspension authority",
+				"err", err,
+				"authority", v,
+			)
+			return err
+		}
+		rootSt.Parameters.RuntimeSuspensionAuthorities = append(rootSt.Parameters.RuntimeSuspensionAuthorities, id)
+	}
+
+	if v := viper.GetString(cfgRoothashRuntimeMessageGasPrice); v != "" {
+		if err := rootSt.Parameters.RuntimeMessageGasPrice.UnmarshalText([]byte(v)); err != nil {
+			l.Error("failed to parse runtime message gas price",
+				"err", err,
+				"gas_price", v,
+			)
+			return err
+		}
+	}
+
 	for _, v := range exports {
 		b, err := ioutil.ReadFile(v)
 		if err != nil {
@@ -595,7 +651,19 @@ func doDumpGenesis(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
+	if err = filterDumpedGenesis(doc); err != nil {
+		logger.Error("failed to filter genesis document",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	writeGenesisDocument(cmd, doc)
+}
 
+// writeGenesisDocument writes the genesis document into the configured
+// genesis file (or standard output).
+func writeGenesisDocument(cmd *cobra.Command, doc *genesis.Document) {
 	w, shouldClose, err := cmdCommon.GetOutputWriter(cmd, flags.CfgGenesisFile)
 	if err != nil {
 		logger.Error("failed to get writer for genesis file",
@@ -639,9 +707,13 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	err = doc.SanityCheck()
-	if err != nil {
-		logger.Error("genesis document sanity check failed", "err", err)
+	if errs := doc.SanityCheckAll(); errs != nil {
+		for _, err := range errs {
+			logger.Error("genesis document sanity check failed",
+				"path", err.Path,
+				"err", err.Err,
+			)
+		}
 		os.Exit(1)
 	}
 
@@ -652,13 +724,20 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 func Register(parentCmd *cobra.Command) {
 	initGenesisCmd.Flags().AddFlagSet(initGenesisFlags)
 	dumpGenesisCmd.Flags().AddFlagSet(dumpGenesisFlags)
+	dumpGenesisCmd.Flags().AddFlagSet(dumpFilterFlags)
 	dumpGenesisCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+	exportGenesisCmd.Flags().AddFlagSet(dumpGenesisFlags)
+	exportGenesisCmd.Flags().AddFlagSet(dumpFilterFlags)
+	exportGenesisCmd.Flags().AddFlagSet(exportGenesisFlags)
+	exportGenesisCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	checkGenesisCmd.Flags().AddFlagSet(checkGenesisFlags)
 
 	for _, v := range []*cobra.Command{
 		initGenesisCmd,
 		dumpGenesisCmd,
+		exportGenesisCmd,
 		checkGenesisCmd,
+		diffGenesisCmd,
 	} {
 		genesisCmd.AddCommand(v)
 	}
@@ -685,6 +764,7 @@ func init() {
 	// Registry config flags.
 	initGenesisFlags.Uint64(CfgRegistryMaxNodeExpiration, 5, "maximum node registration lifespan in epochs")
 	initGenesisFlags.Bool(CfgRegistryDisableRuntimeRegistration, false, "disable non-genesis runtime registration")
+	initGenesisFlags.Uint64(cfgRegistryReadinessAttestationExpiration, 0, "runtime readiness attestation lifespan in epochs (0 = disabled)")
 	initGenesisFlags.Bool(cfgRegistryDebugAllowUnroutableAddresses, false, "allow unroutable addreses (UNSAFE)")
 	initGenesisFlags.Bool(CfgRegistryDebugAllowTestRuntimes, false, "enable test runtime registration")
 	initGenesisFlags.Bool(cfgRegistryDebugAllowEntitySignedNodeRegistration, false, "allow entity signed node registration (UNSAFE)")
@@ -698,6 +778,10 @@ func init() {
 	initGenesisFlags.Int(cfgSchedulerMinValidators, 1, "minumum number of validators")
 	initGenesisFlags.Int(cfgSchedulerMaxValidators, 100, "maximum number of validators")
 	initGenesisFlags.Int(cfgSchedulerMaxValidatorsPerEntity, 1, "maximum number of validators per entity")
+	initGenesisFlags.Int(cfgSchedulerMaxCommitteeMembersPerEntity, 0, "maximum number of runtime committee members per entity (0 = unlimited)")
+	initGenesisFlags.Bool(cfgSchedulerKeepCommitteeOnElectionFailure, false, "keep the previous runtime committee if an election fails")
+	initGenesisFlags.Uint8(cfgSchedulerRuntimeReadinessPolicy, 0, "runtime readiness policy for committee elections (0 = none, 1 = prefer, 2 = require)")
+	initGenesisFlags.Uint64(cfgSchedulerElectionOffset, 0, "number of blocks before the start of an epoch at which runtime committees are elected (0 = disabled)")
 	initGenesisFlags.Bool(cfgSchedulerDebugBypassStake, false, "bypass all stake checks and operations (UNSAFE)")
 	initGenesisFlags.Bool(cfgSchedulerDebugStaticValidators, false, "bypass all validator elections (UNSAFE)")
 	_ = initGenesisFlags.MarkHidden(cfgSchedulerDebugBypassStake)
@@ -710,13 +794,20 @@ func init() {
 	// EpochTime config flags.
 	initGenesisFlags.Bool(cfgEpochTimeDebugMockBackend, false, "use debug mock Epoch time backend")
 	initGenesisFlags.Int64(cfgEpochTimeTendermintInterval, 86400, "Epoch interval (in blocks)")
+	initGenesisFlags.StringSlice(cfgEpochTimeUpdateAuthorities, nil, "public keys of accounts allowed to update the epoch interval")
 	_ = initGenesisFlags.MarkHidden(cfgEpochTimeDebugMockBackend)
 
 	// Roothash config flags.
+	initGenesisFlags.Uint32(cfgRoothashMaxRuntimeMessages, 0, "maximum number of messages a runtime can emit per round")
+	initGenesisFlags.Uint64(cfgRoothashMaxRuntimeMessagesSize, 0, "maximum total size of messages a runtime can emit per round (in bytes)")
+	initGenesisFlags.String(cfgRoothashRuntimeMessageGasPrice, "0", "price per gas unit charged to the runtime owner for executing runtime messages")
+	initGenesisFlags.StringSlice(cfgRoothashSuspensionAuthorities, nil, "public keys of accounts allowed to suspend and resume runtimes")
 	initGenesisFlags.Bool(cfgRoothashDebugDoNotSuspendRuntimes, false, "do not suspend runtimes (UNSAFE)")
 	initGenesisFlags.Bool(cfgRoothashDebugBypassStake, false, "bypass all roothash stake checks and operations (UNSAFE)")
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugDoNotSuspendRuntimes)
+	initGenesisFlags.Bool(CfgRoothashDebugAllowForceRoundTimeout, false, "allow forcing round timeouts via the debug controller (UNSAFE)")
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugBypassStake)
+	_ = initGenesisFlags.MarkHidden(CfgRoothashDebugAllowForceRoundTimeout)
 
 	// Tendermint config flags.
 	initGenesisFlags.Duration(cfgConsensusTimeoutCommit, 1*time.Second, "tendermint commit timeout")
@@ -2,6 +2,7 @@
 package identity
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"os"
 
@@ -9,6 +10,8 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	fileSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/file"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	tlsCert "github.com/oasislabs/oasis-core/go/common/crypto/tls"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
@@ -28,6 +31,12 @@ var (
 		Run:   doNodeInit,
 	}
 
+	identityShowSentryClientPubkeyCmd = &cobra.Command{
+		Use:   "show-sentry-client-pubkey",
+		Short: "outputs node's sentry client TLS public key",
+		Run:   doShowSentryClientPubkey,
+	}
+
 	logger = logging.GetLogger("cmd/identity")
 )
 
@@ -60,12 +69,37 @@ func doNodeInit(cmd *cobra.Command, args []string) {
 	fmt.Printf("Generated identity files in: %s\n", dataDir)
 }
 
+func doShowSentryClientPubkey(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	certPath, keyPath := identity.TLSSentryClientCertPaths(dataDir)
+	cert, err := tlsCert.Load(certPath, keyPath)
+	if err != nil {
+		logger.Error("failed to load sentry client TLS certificate",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	signer := memory.NewFromRuntime(cert.PrivateKey.(ed25519.PrivateKey))
+	fmt.Println(signer.Public())
+}
+
 // Register registers the client sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	tendermint.Register(identityCmd)
 
 	identityInitCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
 	identityCmd.AddCommand(identityInitCmd)
+	identityCmd.AddCommand(identityShowSentryClientPubkeyCmd)
 
 	parentCmd.AddCommand(identityCmd)
 }
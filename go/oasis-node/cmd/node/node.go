@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crash"
@@ -33,9 +34,11 @@ import (
 	"github.com/oasislabs/oasis-core/go/ias"
 	iasAPI "github.com/oasislabs/oasis-core/go/ias/api"
 	keymanagerAPI "github.com/oasislabs/oasis-core/go/keymanager/api"
+	"github.com/oasislabs/oasis-core/go/notifier"
 	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/background"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/gateway"
 	cmdGrpc "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/grpc"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/metrics"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/pprof"
@@ -43,6 +46,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/tracing"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/supplementarysanity"
 	registryAPI "github.com/oasislabs/oasis-core/go/registry/api"
+	roothashAPI "github.com/oasislabs/oasis-core/go/roothash/api"
 	runtimeClient "github.com/oasislabs/oasis-core/go/runtime/client"
 	runtimeClientAPI "github.com/oasislabs/oasis-core/go/runtime/client/api"
 	enclaverpc "github.com/oasislabs/oasis-core/go/runtime/enclaverpc/api"
@@ -69,7 +73,7 @@ import (
 )
 
 var (
-	_ controlAPI.Shutdownable = (*Node)(nil)
+	_ controlAPI.ControlledNode = (*Node)(nil)
 
 	// Flags has the configuration flags.
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
@@ -118,6 +122,8 @@ type Node struct {
 	RuntimeRegistry runtimeRegistry.Registry
 	RuntimeClient   runtimeClientAPI.RuntimeClient
 
+	Notifier *notifier.Notifier
+
 	CommonWorker               *workerCommon.Worker
 	ExecutorWorker             *executor.Worker
 	StorageWorker              *workerStorage.Worker
@@ -166,6 +172,14 @@ func (n *Node) RequestShutdown() (<-chan struct{}, error) {
 	return n.RegistrationWorker.Quit(), nil
 }
 
+func (n *Node) ReloadRuntimes() error {
+	rh := n.CommonWorker.GetConfig().RuntimeHost
+	if rh == nil {
+		return controlAPI.ErrNoHostedRuntimes
+	}
+	return rh.Reload()
+}
+
 func (n *Node) RegistrationStopped() {
 	n.Stop()
 }
@@ -190,6 +204,7 @@ func (n *Node) initBackends() error {
 	stakingAPI.RegisterService(grpcSrv, n.Consensus.Staking())
 	keymanagerAPI.RegisterService(grpcSrv, n.Consensus.KeyManager())
 	consensusAPI.RegisterService(grpcSrv, n.Consensus)
+	roothashAPI.RegisterService(grpcSrv, n.Consensus.RootHash())
 
 	cmdCommon.Logger().Debug("backends initialized")
 
@@ -314,6 +329,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 		dataDir,
 		n.CommonWorker,
 		n.MergeWorker,
+		n.StorageWorker,
 		n.RegistrationWorker,
 	)
 	if err != nil {
@@ -445,6 +461,46 @@ func (n *Node) initGenesis(testNode bool) error {
 	return nil
 }
 
+func (n *Node) selfCheck(logger *logging.Logger) error {
+	repair := viper.GetBool(runtimeRegistry.CfgSelfCheckRepair)
+	issues, err := runtimeRegistry.SelfCheck(
+		n.svcMgr.Ctx,
+		cmdCommon.DataDir(),
+		n.svcTmnt.GetLastCommittedHeight(),
+		repair,
+	)
+	if err != nil {
+		return err
+	}
+
+	var unrepaired int
+	for _, issue := range issues {
+		logFn := logger.Warn
+		if issue.Severity == runtimeRegistry.SeverityInfo || issue.Repaired {
+			logFn = logger.Info
+		} else if issue.Severity == runtimeRegistry.SeverityError {
+			logFn = logger.Error
+			unrepaired++
+		}
+		logFn("self-check: detected store divergence",
+			"runtime_id", issue.RuntimeID,
+			"store", issue.Store,
+			"severity", issue.Severity,
+			"description", issue.Description,
+			"repair", issue.Repair,
+			"repaired", issue.Repaired,
+		)
+	}
+	if unrepaired > 0 {
+		return fmt.Errorf("self-check: %d unrepaired store divergence(s) detected, see log for suggested repair actions or use --%s",
+			unrepaired,
+			runtimeRegistry.CfgSelfCheckRepair,
+		)
+	}
+
+	return nil
+}
+
 func (n *Node) dumpGenesis(ctx context.Context, blockHeight int64, epoch epochtime.EpochTime) error {
 	doc, err := n.svcTmnt.StateToGenesis(ctx, blockHeight)
 	if err != nil {
@@ -608,6 +664,16 @@ func newNode(testNode bool) (*Node, error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Initialize the JSON gateway server.
+	jsonGateway, err := gateway.New(node.svcMgr.Ctx)
+	if err != nil {
+		logger.Error("failed to initialize JSON gateway server",
+			"err", err,
+		)
+		return nil, err
+	}
+	node.svcMgr.Register(jsonGateway)
+
 	// Initialize the genesis provider.
 	if err = node.initGenesis(testNode); err != nil {
 		logger.Error("failed to initialize the genesis provider",
@@ -699,6 +765,14 @@ func newNode(testNode bool) (*Node, error) { // nolint: gocyclo
 
 	logger.Info("starting Oasis node")
 
+	// Verify consistency of persisted runtime state before it is opened.
+	if err = node.selfCheck(logger); err != nil {
+		logger.Error("startup self-check failed",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	// Initialize the node's runtime registry.
 	node.RuntimeRegistry, err = runtimeRegistry.New(node.svcMgr.Ctx, cmdCommon.DataDir(), node.Consensus, node.Identity)
 	if err != nil {
@@ -745,6 +819,26 @@ func newNode(testNode bool) (*Node, error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Initialize and start the node event notifier.
+	var runtimeIDs []common.Namespace
+	for _, rt := range node.RuntimeRegistry.Runtimes() {
+		runtimeIDs = append(runtimeIDs, rt.ID())
+	}
+	node.Notifier, err = notifier.NewFromFlags(node.Consensus, node.Identity.NodeSigner.Public(), runtimeIDs)
+	if err != nil {
+		logger.Error("failed to initialize node event notifier",
+			"err", err,
+		)
+		return nil, err
+	}
+	node.svcMgr.Register(node.Notifier)
+	if err = node.Notifier.Start(); err != nil {
+		logger.Error("failed to start node event notifier",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	// Initialize and start the node controller.
 	node.NodeController = control.New(node, node.Consensus, node.Upgrader)
 	controlAPI.RegisterService(node.grpcInternal.Server(), node.NodeController)
@@ -773,6 +867,14 @@ func newNode(testNode bool) (*Node, error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Start the JSON gateway server.
+	if err = jsonGateway.Start(); err != nil {
+		logger.Error("failed to start JSON gateway server",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	logger.Info("initialization complete: ready to serve")
 	startOk = true
 
@@ -798,14 +900,17 @@ func init() {
 		metrics.Flags,
 		tracing.Flags,
 		cmdGrpc.ServerLocalFlags,
+		gateway.Flags,
 		pprof.Flags,
 		storage.Flags,
 		supplementarysanity.Flags,
 		tendermint.Flags,
 		ias.Flags,
+		notifier.Flags,
 		workerKeymanager.Flags,
 		runtimeRegistry.Flags,
 		compute.Flags,
+		executor.Flags,
 		p2p.Flags,
 		registration.Flags,
 		txnscheduler.Flags,
@@ -814,6 +919,7 @@ func init() {
 		workerSentry.Flags,
 		workerConsensusRPC.Flags,
 		crash.InitFlags(),
+		upgrade.Flags,
 	} {
 		Flags.AddFlagSet(v)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/entity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
@@ -32,12 +33,17 @@ const (
 	CfgNodeID                 = "entity.node.id"
 	CfgNodeDescriptor         = "entity.node.descriptor"
 	CfgReuseSigner            = "entity.reuse_signer"
+	CfgMetadataName           = "entity.metadata.name"
+	CfgMetadataURL            = "entity.metadata.url"
+	CfgMetadataEmail          = "entity.metadata.email"
+	CfgMetadataLogoHash       = "entity.metadata.logo_hash"
 
 	entityGenesisFilename = "entity_genesis.json"
 )
 
 var (
 	entityFlags               = flag.NewFlagSet("", flag.ContinueOnError)
+	metadataFlags             = flag.NewFlagSet("", flag.ContinueOnError)
 	initFlags                 = flag.NewFlagSet("", flag.ContinueOnError)
 	updateFlags               = flag.NewFlagSet("", flag.ContinueOnError)
 	registerOrDeregisterFlags = flag.NewFlagSet("", flag.ContinueOnError)
@@ -158,8 +164,15 @@ func doUpdate(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Update the entity.
+	// Update the entity, upgrading the descriptor to the latest version.
+	ent.DescriptorVersion = entity.LatestEntityDescriptorVersion
 	ent.AllowEntitySignedNodes = viper.GetBool(cfgAllowEntitySignedNodes)
+	if ent.Metadata, err = metadataFromFlags(); err != nil {
+		logger.Error("failed to parse entity metadata",
+			"err", err,
+		)
+		os.Exit(1)
+	}
 
 	ent.Nodes = nil
 	for _, v := range viper.GetStringSlice(CfgNodeID) {
@@ -229,6 +242,13 @@ func doUpdate(cmd *cobra.Command, args []string) {
 		ent.Nodes = append(ent.Nodes, k)
 	}
 
+	if err = ent.ValidateBasic(true); err != nil {
+		logger.Error("invalid entity descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
 	// Save the entity descriptor.
 	if err = ent.Save(dataDir); err != nil {
 		logger.Error("failed to persist entity descriptor",
@@ -247,6 +267,29 @@ func doUpdate(cmd *cobra.Command, args []string) {
 	)
 }
 
+func metadataFromFlags() (*entity.Metadata, error) {
+	md := entity.Metadata{
+		Name:  viper.GetString(CfgMetadataName),
+		URL:   viper.GetString(CfgMetadataURL),
+		Email: viper.GetString(CfgMetadataEmail),
+	}
+	if v := viper.GetString(CfgMetadataLogoHash); v != "" {
+		var h hash.Hash
+		if err := h.UnmarshalHex(v); err != nil {
+			return nil, fmt.Errorf("malformed logo hash: %w", err)
+		}
+		md.LogoHash = &h
+	}
+	if md == (entity.Metadata{}) {
+		return nil, nil
+	}
+
+	if err := md.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &md, nil
+}
+
 func signAndWriteEntityGenesis(dataDir string, signer signature.Signer, ent *entity.Entity) error {
 	// Sign the entity registration for use in a genesis document.
 	signed, err := entity.SignEntity(signer, registry.RegisterGenesisEntitySignatureContext, ent)
@@ -378,6 +421,9 @@ func loadOrGenerateEntity(dataDir string, generate bool) (*entity.Entity, signat
 			DescriptorVersion:      entity.LatestEntityDescriptorVersion,
 			AllowEntitySignedNodes: viper.GetBool(cfgAllowEntitySignedNodes),
 		}
+		if template.Metadata, err = metadataFromFlags(); err != nil {
+			return nil, nil, err
+		}
 
 		if viper.GetBool(CfgReuseSigner) {
 			signer, err := entitySignerFactory.Load(signature.SignerEntity)
@@ -423,11 +469,18 @@ func init() {
 	_ = entityFlags.MarkHidden(cfgAllowEntitySignedNodes)
 	_ = viper.BindPFlags(entityFlags)
 
+	metadataFlags.String(CfgMetadataName, "", "Entity name")
+	metadataFlags.String(CfgMetadataURL, "", "Entity website URL (must be an HTTPS URL)")
+	metadataFlags.String(CfgMetadataEmail, "", "Entity contact email address")
+	metadataFlags.String(CfgMetadataLogoHash, "", "Hex-encoded hash of the entity logo")
+	_ = viper.BindPFlags(metadataFlags)
+
 	initFlags.Bool(CfgReuseSigner, false, "Reuse entity signer instead of generating a new one")
 	initFlags.AddFlagSet(cmdFlags.ForceFlags)
 	initFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
 	initFlags.AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 	initFlags.AddFlagSet(entityFlags)
+	initFlags.AddFlagSet(metadataFlags)
 	_ = viper.BindPFlags(initFlags)
 
 	updateFlags.StringSlice(CfgNodeID, nil, "ID(s) of nodes associated with this entity")
@@ -436,6 +489,7 @@ func init() {
 	updateFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
 	updateFlags.AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 	updateFlags.AddFlagSet(entityFlags)
+	updateFlags.AddFlagSet(metadataFlags)
 
 	registerOrDeregisterFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
 	registerOrDeregisterFlags.AddFlagSet(cmdConsensus.TxFlags)
@@ -22,5 +22,7 @@ func Register(parentCmd *cobra.Command) {
 	node.Register(registryCmd)
 	runtime.Register(registryCmd)
 
+	registryCmd.AddCommand(schemaCmd)
+
 	parentCmd.AddCommand(registryCmd)
 }
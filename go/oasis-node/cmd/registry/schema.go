@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/jsonschema"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+)
+
+var (
+	// schemaDescriptors are the descriptors that JSON schemas can be exported for.
+	schemaDescriptors = map[string]interface{}{
+		"entity":  entity.Entity{},
+		"node":    node.Node{},
+		"runtime": registry.Runtime{},
+	}
+
+	schemaCmd = &cobra.Command{
+		Use:       "schema <" + strings.Join(schemaDescriptorNames(), "|") + ">",
+		Short:     "export the JSON schema of a registry descriptor",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: schemaDescriptorNames(),
+		Run:       doSchema,
+	}
+
+	logger = logging.GetLogger("cmd/registry")
+)
+
+func schemaDescriptorNames() []string {
+	var names []string
+	for name := range schemaDescriptors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func doSchema(cmd *cobra.Command, args []string) {
+	schema := jsonschema.Reflect(schemaDescriptors[args[0]])
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		logger.Error("failed to marshal JSON schema",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Printf("%s\n", data)
+}
@@ -56,6 +56,9 @@ func Register(parentCmd *cobra.Command) {
 	exportCmd.Flags().AddFlagSet(cmdSigner.Flags)
 	exportCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
 
+	testVectorsCmd.Flags().AddFlagSet(testVectorsFlags)
+
 	signerCmd.AddCommand(exportCmd)
+	signerCmd.AddCommand(testVectorsCmd)
 	parentCmd.AddCommand(signerCmd)
 }
@@ -0,0 +1,57 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+)
+
+// CfgTestVectorsChainContext configures the chain context used for chain
+// separated contexts when generating test vectors.
+const CfgTestVectorsChainContext = "signer.test_vectors.chain_context"
+
+var (
+	testVectorsCmd = &cobra.Command{
+		Use:   "test-vectors",
+		Short: "dump signature domain separation test vectors for all registered contexts",
+		Run:   doTestVectors,
+	}
+
+	testVectorsFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doTestVectors(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	vectors, err := signature.GenerateContextTestVectors(viper.GetString(CfgTestVectorsChainContext))
+	if err != nil {
+		logger.Error("failed to generate test vectors",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		logger.Error("failed to marshal test vectors",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Printf("%s\n", data)
+}
+
+func init() {
+	testVectorsFlags.String(CfgTestVectorsChainContext, genesisTestHelpers.TestChainContext, "chain domain separation context")
+	_ = viper.BindPFlags(testVectorsFlags)
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
 	cmdConsensus "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/consensus"
 	cmdFlags "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
@@ -26,6 +30,10 @@ const (
 	// CfgAmount configures the amount of tokens.
 	CfgAmount = "stake.amount"
 
+	// CfgDenominationDecimals configures the number of decimal places of
+	// token amounts.
+	CfgDenominationDecimals = "stake.denomination.decimals"
+
 	// CfgShares configures the amount of shares.
 	CfgShares = "stake.shares"
 
@@ -40,15 +48,44 @@ const (
 
 	// CfgCommissionScheduleBounds configures the commission schedule rate bound steps.
 	CfgCommissionScheduleBounds = "stake.commission_schedule.bounds"
+
+	// CfgCommissionDestinations configures the commission destination accounts.
+	CfgCommissionDestinations = "stake.commission_destinations"
+
+	// CfgAllowBeneficiary configures the beneficiary of the allowance.
+	CfgAllowBeneficiary = "stake.allow.beneficiary"
+
+	// CfgAllowNegative configures whether the allowance should be decreased.
+	CfgAllowNegative = "stake.allow.negative"
+
+	// CfgWithdrawSource configures the account to withdraw from.
+	CfgWithdrawSource = "stake.withdraw.source"
+
+	// CfgCommissionProjectionFrom configures the first epoch of the commission
+	// projection window.
+	CfgCommissionProjectionFrom = "stake.commission_projection.from"
+
+	// CfgCommissionProjectionEpochs configures the number of epochs in the
+	// commission projection window.
+	CfgCommissionProjectionEpochs = "stake.commission_projection.epochs"
+
+	// CfgHeight configures the consensus height at which to query the state.
+	CfgHeight = "stake.height"
 )
 
 var (
 	accountInfoFlags        = flag.NewFlagSet("", flag.ContinueOnError)
+	heightFlags             = flag.NewFlagSet("", flag.ContinueOnError)
+	denominationFlags       = flag.NewFlagSet("", flag.ContinueOnError)
 	amountFlags             = flag.NewFlagSet("", flag.ContinueOnError)
 	sharesFlags             = flag.NewFlagSet("", flag.ContinueOnError)
 	commonEscrowFlags       = flag.NewFlagSet("", flag.ContinueOnError)
 	commissionScheduleFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	commissionDstFlags      = flag.NewFlagSet("", flag.ContinueOnError)
 	accountTransferFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	accountAllowFlags       = flag.NewFlagSet("", flag.ContinueOnError)
+	accountWithdrawFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	commissionProjFlags     = flag.NewFlagSet("", flag.ContinueOnError)
 
 	accountCmd = &cobra.Command{
 		Use:   "account",
@@ -61,6 +98,12 @@ var (
 		Run:   doAccountInfo,
 	}
 
+	accountCommissionProjectionCmd = &cobra.Command{
+		Use:   "commission-projection",
+		Short: "project the account's effective commission rates",
+		Run:   doAccountCommissionProjection,
+	}
+
 	accountTransferCmd = &cobra.Command{
 		Use:   "gen_transfer",
 		Short: "generate a transfer transaction",
@@ -90,8 +133,45 @@ var (
 		Short: "Generate an amend_commission_schedule transaction",
 		Run:   doAccountAmendCommissionSchedule,
 	}
+
+	accountSetCommissionDestinationsCmd = &cobra.Command{
+		Use:   "gen_set_commission_destinations",
+		Short: "Generate a set_commission_destinations transaction",
+		Run:   doAccountSetCommissionDestinations,
+	}
+
+	accountAllowCmd = &cobra.Command{
+		Use:   "gen_allow",
+		Short: "Generate an allow transaction",
+		Run:   doAccountAllow,
+	}
+
+	accountWithdrawCmd = &cobra.Command{
+		Use:   "gen_withdraw",
+		Short: "Generate a withdraw transaction",
+		Run:   doAccountWithdraw,
+	}
 )
 
+// parseAmount parses a token amount in the configured denomination into
+// base units.
+func parseAmount(dst *quantity.Quantity, raw string) error {
+	q, err := quantity.ParseDecimal(raw, uint8(viper.GetUint(CfgDenominationDecimals)))
+	if err != nil {
+		return err
+	}
+	return dst.FromBigInt(q.ToBigInt())
+}
+
+// parseShares parses an integral amount of shares.
+func parseShares(dst *quantity.Quantity, raw string) error {
+	q, err := quantity.ParseDecimal(raw, 0)
+	if err != nil {
+		return err
+	}
+	return dst.FromBigInt(q.ToBigInt())
+}
+
 func doAccountInfo(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -109,11 +189,70 @@ func doAccountInfo(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	ctx := context.Background()
-	ai := getAccountInfo(ctx, cmd, id, client)
+	ai := getAccountInfo(ctx, cmd, id, client, viper.GetInt64(CfgHeight))
 	b, _ := json.Marshal(ai)
 	fmt.Printf("%v\n", string(b))
 }
 
+func doAccountCommissionProjection(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var id signature.PublicKey
+	if err := id.UnmarshalText([]byte(viper.GetString(CfgAccountID))); err != nil {
+		logger.Error("failed to parse account ID",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	numEpochs := epochtime.EpochTime(viper.GetUint64(CfgCommissionProjectionEpochs))
+	if numEpochs == 0 {
+		logger.Error("number of projected epochs must be positive")
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	ctx := context.Background()
+	from := epochtime.EpochTime(viper.GetUint64(CfgCommissionProjectionFrom))
+	if !viper.IsSet(CfgCommissionProjectionFrom) {
+		// Default to the current epoch.
+		consensusClient := consensus.NewConsensusClient(conn)
+		doWithRetries(cmd, "query current epoch", func() error {
+			var err error
+			from, err = consensusClient.GetEpoch(ctx, consensus.HeightLatest)
+			return err
+		})
+	}
+
+	ai := getAccountInfo(ctx, cmd, id, client, consensus.HeightLatest)
+	intervals := ai.Escrow.CommissionSchedule.ProjectRates(from, from+numEpochs)
+
+	if cmdFlags.Verbose() {
+		b, _ := json.Marshal(intervals)
+		fmt.Printf("%v\n", string(b))
+		return
+	}
+
+	formatRate := func(q *quantity.Quantity) string {
+		if q == nil {
+			return "none"
+		}
+		return fmt.Sprintf("%v/%v", q, staking.CommissionRateDenominator)
+	}
+	for _, interval := range intervals {
+		fmt.Printf("epochs %d-%d: rate %s (min %s, max %s)\n",
+			interval.Start,
+			interval.End-1,
+			formatRate(interval.Rate),
+			formatRate(interval.RateMin),
+			formatRate(interval.RateMax),
+		)
+	}
+}
+
 func doAccountTransfer(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -129,7 +268,7 @@ func doAccountTransfer(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	if err := xfer.Tokens.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+	if err := parseAmount(&xfer.Tokens, viper.GetString(CfgAmount)); err != nil {
 		logger.Error("failed to parse transfer amount",
 			"err", err,
 		)
@@ -151,7 +290,7 @@ func doAccountBurn(cmd *cobra.Command, args []string) {
 	cmdConsensus.AssertTxFileOK()
 
 	var burn staking.Burn
-	if err := burn.Tokens.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+	if err := parseAmount(&burn.Tokens, viper.GetString(CfgAmount)); err != nil {
 		logger.Error("failed to parse burn amount",
 			"err", err,
 		)
@@ -179,7 +318,7 @@ func doAccountEscrow(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	if err := escrow.Tokens.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+	if err := parseAmount(&escrow.Tokens, viper.GetString(CfgAmount)); err != nil {
 		logger.Error("failed to parse escrow amount",
 			"err", err,
 		)
@@ -207,7 +346,7 @@ func doAccountReclaimEscrow(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
-	if err := reclaim.Shares.UnmarshalText([]byte(viper.GetString(CfgShares))); err != nil {
+	if err := parseShares(&reclaim.Shares, viper.GetString(CfgShares)); err != nil {
 		logger.Error("failed to parse escrow reclaim shares",
 			"err", err,
 		)
@@ -298,19 +437,132 @@ func doAccountAmendCommissionSchedule(cmd *cobra.Command, args []string) {
 	cmdConsensus.SignAndSaveTx(tx)
 }
 
+func scanCommissionDestination(dst *staking.CommissionDestination, raw string) error {
+	split := strings.SplitN(raw, "/", 2)
+	if len(split) != 2 {
+		return fmt.Errorf("malformed commission destination (need account_id/share_numerator)")
+	}
+	if err := dst.Account.UnmarshalText([]byte(split[0])); err != nil {
+		return fmt.Errorf("account: %w", err)
+	}
+	if err := parseShares(&dst.Share, split[1]); err != nil {
+		return fmt.Errorf("share: %w", err)
+	}
+	return nil
+}
+
+func doAccountSetCommissionDestinations(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	var setCommissionDestinations staking.SetCommissionDestinations
+	rawDsts := viper.GetStringSlice(CfgCommissionDestinations)
+	if rawDsts != nil {
+		setCommissionDestinations.Destinations = make([]staking.CommissionDestination, len(rawDsts))
+		for i, rawDst := range rawDsts {
+			if err := scanCommissionDestination(&setCommissionDestinations.Destinations[i], rawDst); err != nil {
+				logger.Error("failed to parse commission destination",
+					"err", err,
+					"index", i,
+					"raw_destination", rawDst,
+				)
+				os.Exit(1)
+			}
+		}
+	}
+	if err := staking.ValidateCommissionDestinations(setCommissionDestinations.Destinations); err != nil {
+		logger.Error("invalid commission destinations",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := staking.NewSetCommissionDestinationsTx(nonce, fee, &setCommissionDestinations)
+
+	cmdConsensus.SignAndSaveTx(tx)
+}
+
+func doAccountAllow(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	var allow staking.Allow
+	if err := allow.Beneficiary.UnmarshalText([]byte(viper.GetString(CfgAllowBeneficiary))); err != nil {
+		logger.Error("failed to parse allowance beneficiary ID",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	allow.Negative = viper.GetBool(CfgAllowNegative)
+	if err := parseAmount(&allow.AmountChange, viper.GetString(CfgAmount)); err != nil {
+		logger.Error("failed to parse allowance amount change",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := staking.NewAllowTx(nonce, fee, &allow)
+
+	cmdConsensus.SignAndSaveTx(tx)
+}
+
+func doAccountWithdraw(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	var withdraw staking.Withdraw
+	if err := withdraw.From.UnmarshalText([]byte(viper.GetString(CfgWithdrawSource))); err != nil {
+		logger.Error("failed to parse withdrawal source ID",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err := parseAmount(&withdraw.Tokens, viper.GetString(CfgAmount)); err != nil {
+		logger.Error("failed to parse withdrawal amount",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := staking.NewWithdrawTx(nonce, fee, &withdraw)
+
+	cmdConsensus.SignAndSaveTx(tx)
+}
+
 func registerAccountCmd() {
 	for _, v := range []*cobra.Command{
 		accountInfoCmd,
+		accountCommissionProjectionCmd,
 		accountTransferCmd,
 		accountBurnCmd,
 		accountEscrowCmd,
 		accountReclaimEscrowCmd,
 		accountAmendCommissionScheduleCmd,
+		accountSetCommissionDestinationsCmd,
+		accountAllowCmd,
+		accountWithdrawCmd,
 	} {
 		accountCmd.AddCommand(v)
 	}
 
 	accountInfoCmd.Flags().AddFlagSet(accountInfoFlags)
+	accountInfoCmd.Flags().AddFlagSet(heightFlags)
+	accountCommissionProjectionCmd.Flags().AddFlagSet(commissionProjFlags)
 	accountTransferCmd.Flags().AddFlagSet(accountTransferFlags)
 	accountBurnCmd.Flags().AddFlagSet(cmdConsensus.TxFlags)
 	accountBurnCmd.Flags().AddFlagSet(amountFlags)
@@ -319,6 +571,9 @@ func registerAccountCmd() {
 	accountReclaimEscrowCmd.Flags().AddFlagSet(commonEscrowFlags)
 	accountReclaimEscrowCmd.Flags().AddFlagSet(sharesFlags)
 	accountAmendCommissionScheduleCmd.Flags().AddFlagSet(commissionScheduleFlags)
+	accountSetCommissionDestinationsCmd.Flags().AddFlagSet(commissionDstFlags)
+	accountAllowCmd.Flags().AddFlagSet(accountAllowFlags)
+	accountWithdrawCmd.Flags().AddFlagSet(accountWithdrawFlags)
 }
 
 func init() {
@@ -327,8 +582,21 @@ func init() {
 	accountInfoFlags.AddFlagSet(cmdFlags.RetriesFlags)
 	accountInfoFlags.AddFlagSet(cmdGrpc.ClientFlags)
 
+	heightFlags.Int64(CfgHeight, consensus.HeightLatest, "consensus height at which to query the state (0 means latest)")
+	_ = viper.BindPFlags(heightFlags)
+
+	commissionProjFlags.Uint64(CfgCommissionProjectionFrom, 0, "first epoch of the projection (defaults to the current epoch)")
+	commissionProjFlags.Uint64(CfgCommissionProjectionEpochs, 100, "number of epochs to project")
+	_ = viper.BindPFlags(commissionProjFlags)
+	commissionProjFlags.AddFlagSet(accountInfoFlags)
+	commissionProjFlags.AddFlagSet(cmdFlags.VerboseFlags)
+
+	denominationFlags.Uint8(CfgDenominationDecimals, 0, "number of decimal places of token amounts (0 means base units)")
+	_ = viper.BindPFlags(denominationFlags)
+
 	amountFlags.String(CfgAmount, "0", "amount of tokens for the transaction")
 	_ = viper.BindPFlags(amountFlags)
+	amountFlags.AddFlagSet(denominationFlags)
 
 	sharesFlags.String(CfgShares, "0", "amount of shares for the transaction")
 	_ = viper.BindPFlags(sharesFlags)
@@ -355,4 +623,23 @@ func init() {
 	))
 	_ = viper.BindPFlags(commissionScheduleFlags)
 	commissionScheduleFlags.AddFlagSet(cmdConsensus.TxFlags)
+
+	commissionDstFlags.StringSlice(CfgCommissionDestinations, nil, fmt.Sprintf(
+		"commission destination. Multiple of this flag is allowed. "+
+			"Each destination is in the format account_id/share_numerator. "+
+			"The share is share_numerator divided by %v", staking.CommissionRateDenominator,
+	))
+	_ = viper.BindPFlags(commissionDstFlags)
+	commissionDstFlags.AddFlagSet(cmdConsensus.TxFlags)
+
+	accountAllowFlags.String(CfgAllowBeneficiary, "", "allowance beneficiary account ID")
+	accountAllowFlags.Bool(CfgAllowNegative, false, "decrease the allowance instead of increasing it")
+	_ = viper.BindPFlags(accountAllowFlags)
+	accountAllowFlags.AddFlagSet(cmdConsensus.TxFlags)
+	accountAllowFlags.AddFlagSet(amountFlags)
+
+	accountWithdrawFlags.String(CfgWithdrawSource, "", "ID of the account to withdraw from")
+	_ = viper.BindPFlags(accountWithdrawFlags)
+	accountWithdrawFlags.AddFlagSet(cmdConsensus.TxFlags)
+	accountWithdrawFlags.AddFlagSet(amountFlags)
 }
@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
@@ -16,6 +18,8 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	genesisFile "github.com/oasislabs/oasis-core/go/genesis/file"
 	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
 	cmdFlags "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/grpc"
@@ -40,10 +44,28 @@ var (
 		Run:   doList,
 	}
 
+	rewardsCmd = &cobra.Command{
+		Use:   "rewards",
+		Short: "simulate the next epoch's signing reward disbursement",
+		Run:   doRewards,
+	}
+
+	reportCmd = &cobra.Command{
+		Use:   "report [genesis-file]",
+		Short: "report the network's economic parameters and token distribution",
+		Long: "Report the network's economic parameters and token distribution. " +
+			"If a genesis file is given, the report is generated from the genesis " +
+			"document, otherwise from the latest state of the node.",
+		Args: cobra.MaximumNArgs(1),
+		Run:  doReport,
+	}
+
 	logger = logging.GetLogger("cmd/stake")
 
-	infoFlags = flag.NewFlagSet("", flag.ContinueOnError)
-	listFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	infoFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	listFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	rewardsFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	reportFlags  = flag.NewFlagSet("", flag.ContinueOnError)
 )
 
 func doConnect(cmd *cobra.Command) (*grpc.ClientConn, api.Backend) {
@@ -63,9 +85,17 @@ func doWithRetries(cmd *cobra.Command, descr string, fn func() error) {
 	nrRetries := cmdFlags.Retries()
 	for i := 0; i <= nrRetries; i++ {
 		err := fn()
-		switch err {
-		case nil:
+		switch {
+		case err == nil:
 			return
+		case errors.Is(err, consensus.ErrVersionNotFound):
+			// Retrying will not help if the state at the given height is
+			// not available (e.g., because it has been pruned).
+			logger.Error("failed to "+descr+", state not available at given height",
+				"err", err,
+				"height", viper.GetInt64(CfgHeight),
+			)
+			os.Exit(1)
 		default:
 			logger.Warn("failed to "+descr,
 				"err", err,
@@ -78,6 +108,11 @@ func doWithRetries(cmd *cobra.Command, descr string, fn func() error) {
 	os.Exit(1)
 }
 
+// formatAmount formats a token amount in the configured denomination.
+func formatAmount(q *quantity.Quantity) string {
+	return q.FormatDecimal(uint8(viper.GetUint(CfgDenominationDecimals)))
+}
+
 func doInfo(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -87,34 +122,35 @@ func doInfo(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	ctx := context.Background()
+	height := viper.GetInt64(CfgHeight)
 
 	doWithRetries(cmd, "query token total supply", func() error {
-		q, err := client.TotalSupply(ctx, consensus.HeightLatest)
+		q, err := client.TotalSupply(ctx, height)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("Total supply: %v\n", q)
+		fmt.Printf("Total supply: %s\n", formatAmount(q))
 		return nil
 	})
 
 	doWithRetries(cmd, "query token common pool", func() error {
-		q, err := client.CommonPool(ctx, consensus.HeightLatest)
+		q, err := client.CommonPool(ctx, height)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("Common pool: %v\n", q)
+		fmt.Printf("Common pool: %s\n", formatAmount(q))
 		return nil
 	})
 
 	doWithRetries(cmd, "query last block fees", func() error {
-		q, err := client.LastBlockFees(ctx, consensus.HeightLatest)
+		q, err := client.LastBlockFees(ctx, height)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("Last block fees: %v\n", q)
+		fmt.Printf("Last block fees: %s\n", formatAmount(q))
 		return nil
 	})
 
@@ -138,7 +174,7 @@ func doInfo(cmd *cobra.Command, args []string) {
 				continue
 			}
 
-			q, err := client.Threshold(ctx, &api.ThresholdQuery{Kind: k, Height: consensus.HeightLatest})
+			q, err := client.Threshold(ctx, &api.ThresholdQuery{Kind: k, Height: height})
 			if err != nil {
 				if errors.Is(err, api.ErrInvalidThreshold) {
 					logger.Warn(fmt.Sprintf("invalid staking threshold kind: %s", k))
@@ -157,7 +193,7 @@ func doInfo(cmd *cobra.Command, args []string) {
 	for _, k := range thresholdsToQuery {
 		thres := thresholds[k]
 		if thres.valid {
-			fmt.Printf("Staking threshold (%s): %v\n", k, thres.value)
+			fmt.Printf("Staking threshold (%s): %s\n", k, formatAmount(thres.value))
 		}
 	}
 }
@@ -171,18 +207,19 @@ func doList(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	ctx := context.Background()
+	height := viper.GetInt64(CfgHeight)
 
 	var ids []signature.PublicKey
 	doWithRetries(cmd, "query accounts", func() error {
 		var err error
-		ids, err = client.Accounts(ctx, consensus.HeightLatest)
+		ids, err = client.Accounts(ctx, height)
 		return err
 	})
 
 	if cmdFlags.Verbose() {
 		accts := make(map[signature.PublicKey]*api.Account)
 		for _, v := range ids {
-			accts[v] = getAccountInfo(ctx, cmd, v, client)
+			accts[v] = getAccountInfo(ctx, cmd, v, client, height)
 		}
 		b, _ := json.Marshal(accts)
 		fmt.Printf("%v\n", string(b))
@@ -193,11 +230,193 @@ func doList(cmd *cobra.Command, args []string) {
 	}
 }
 
-func getAccountInfo(ctx context.Context, cmd *cobra.Command, id signature.PublicKey, client api.Backend) *api.Account {
+func doRewards(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	ctx := context.Background()
+
+	var sim *api.RewardSimulation
+	doWithRetries(cmd, "simulate epoch rewards", func() error {
+		var err error
+		sim, err = client.SimulateEpochRewards(ctx, consensus.HeightLatest)
+		return err
+	})
+
+	if cmdFlags.Verbose() {
+		b, _ := json.Marshal(sim)
+		fmt.Printf("%v\n", string(b))
+		return
+	}
+
+	fmt.Printf("Epoch: %d\n", sim.Epoch)
+	fmt.Printf("Total rewards: %s\n", formatAmount(&sim.Total))
+	fmt.Printf("Minted: %s\n", formatAmount(&sim.Minted))
+	if sim.InsufficientCommonPool {
+		fmt.Printf("WARNING: common pool is insufficient to pay out all rewards\n")
+	}
+	for id, proj := range sim.Rewards {
+		fmt.Printf("%v: reward %s, commission %s\n", id, formatAmount(&proj.Reward), formatAmount(&proj.Commission))
+	}
+}
+
+func doReport(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var (
+		state *api.Genesis
+		epoch epochtime.EpochTime
+	)
+	if len(args) > 0 {
+		provider, err := genesisFile.NewFileProvider(args[0])
+		if err != nil {
+			logger.Error("failed to open genesis file",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		doc, err := provider.GetGenesisDocument()
+		if err != nil {
+			logger.Error("failed to get genesis document",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		state, epoch = &doc.Staking, doc.EpochTime.Base
+	} else {
+		conn, client := doConnect(cmd)
+		defer conn.Close()
+
+		ctx := context.Background()
+		consensusClient := consensus.NewConsensusClient(conn)
+		doWithRetries(cmd, "query current epoch", func() error {
+			var err error
+			epoch, err = consensusClient.GetEpoch(ctx, consensus.HeightLatest)
+			return err
+		})
+		doWithRetries(cmd, "query staking state", func() error {
+			var err error
+			state, err = client.StateToGenesis(ctx, consensus.HeightLatest)
+			return err
+		})
+	}
+
+	report, err := api.NewEconomicReport(state, epoch)
+	if err != nil {
+		logger.Error("failed to generate economic report",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if cmdFlags.Verbose() {
+		b, _ := json.Marshal(report)
+		fmt.Printf("%v\n", string(b))
+		return
+	}
+	printReport(report)
+}
+
+func printReport(report *api.EconomicReport) {
+	formatRate := func(q *quantity.Quantity, denominator *quantity.Quantity) string {
+		return fmt.Sprintf("%v/%v", q, denominator)
+	}
+	printDistribution := func(name string, d *api.RateDistribution) {
+		if d == nil {
+			fmt.Printf("  %s: none\n", name)
+			return
+		}
+		fmt.Printf("  %s: min %s, median %s, max %s (%d accounts)\n",
+			name,
+			formatRate(&d.Min, api.CommissionRateDenominator),
+			formatRate(&d.Median, api.CommissionRateDenominator),
+			formatRate(&d.Max, api.CommissionRateDenominator),
+			d.Count,
+		)
+	}
+
+	fmt.Printf("Epoch: %d\n", report.Epoch)
+
+	supply := &report.Supply
+	fmt.Printf("Supply:\n")
+	fmt.Printf("  Total supply: %s\n", formatAmount(&supply.TotalSupply))
+	if supply.SupplyCap != nil {
+		fmt.Printf("  Supply cap: %s\n", formatAmount(supply.SupplyCap))
+	}
+	fmt.Printf("  Total minted: %s\n", formatAmount(&supply.TotalMinted))
+	fmt.Printf("  Circulating: %s\n", formatAmount(&supply.Circulating))
+	fmt.Printf("  Escrowed (active): %s\n", formatAmount(&supply.EscrowActive))
+	fmt.Printf("  Escrowed (debonding): %s\n", formatAmount(&supply.EscrowDebonding))
+	fmt.Printf("  Common pool: %s\n", formatAmount(&supply.CommonPool))
+	fmt.Printf("  Last block fees: %s\n", formatAmount(&supply.LastBlockFees))
+	fmt.Printf("  Accounts: %d (%d with active escrow)\n", supply.Accounts, supply.EscrowAccounts)
+
+	fmt.Printf("Staking:\n")
+	fmt.Printf("  Debonding interval: %d epochs\n", report.DebondingInterval)
+	fmt.Printf("  Minimum delegation: %s\n", formatAmount(&report.MinDelegationAmount))
+	kinds := make([]api.ThresholdKind, 0, len(report.Thresholds))
+	for kind := range report.Thresholds {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	for _, kind := range kinds {
+		thres := report.Thresholds[kind]
+		fmt.Printf("  Threshold (%s): %s\n", kind, formatAmount(&thres))
+	}
+
+	rewards := &report.Rewards
+	fmt.Printf("Rewards:\n")
+	fmt.Printf("  Epoch signing reward factor: %s\n", formatRate(&rewards.FactorEpochSigned, api.RewardAmountDenominator))
+	fmt.Printf("  Block proposal reward factor: %s\n", formatRate(&rewards.FactorBlockProposed, api.RewardAmountDenominator))
+	fmt.Printf("  Signing threshold: %d/%d\n", rewards.SigningThresholdNumerator, rewards.SigningThresholdDenominator)
+	for _, step := range rewards.Schedule {
+		fmt.Printf("  Reward scale until epoch %d: %s\n", step.Until, formatRate(&step.Scale, api.RewardAmountDenominator))
+	}
+	fmt.Printf("  Reward schedule horizon: epoch %d (%d epochs remaining)\n", rewards.Horizon, rewards.RemainingEpochs)
+	if rewards.Minting != nil {
+		for _, step := range rewards.Minting.Schedule {
+			fmt.Printf("  Minting rate until epoch %d: %s\n", step.Until, formatRate(&step.Rate, api.RewardAmountDenominator))
+		}
+	}
+
+	commission := &report.Commission
+	fmt.Printf("Commission:\n")
+	fmt.Printf("  Rate change interval: %d epochs\n", commission.Rules.RateChangeInterval)
+	fmt.Printf("  Rate bound lead: %d epochs\n", commission.Rules.RateBoundLead)
+	fmt.Printf("  Maximum rate steps: %d\n", commission.Rules.MaxRateSteps)
+	fmt.Printf("  Maximum bound steps: %d\n", commission.Rules.MaxBoundSteps)
+	printDistribution("Rates", commission.Rates)
+	printDistribution("Lower bounds", commission.BoundsMin)
+	printDistribution("Upper bounds", commission.BoundsMax)
+	fmt.Printf("  Accounts without rate: %d\n", commission.WithoutRate)
+	fmt.Printf("  Accounts without bounds: %d\n", commission.WithoutBound)
+
+	fmt.Printf("Projected signing reward emission:\n")
+	if len(report.Emission) == 0 {
+		fmt.Printf("  none\n")
+	}
+	for _, period := range report.Emission {
+		fmt.Printf("  epochs %d-%d: %s per epoch, %s total, %s minted\n",
+			period.Start,
+			period.End-1,
+			formatAmount(&period.EpochReward),
+			formatAmount(&period.Reward),
+			formatAmount(&period.Minted),
+		)
+	}
+}
+
+func getAccountInfo(ctx context.Context, cmd *cobra.Command, id signature.PublicKey, client api.Backend, height int64) *api.Account {
 	var acct *api.Account
 	doWithRetries(cmd, "query account "+id.String(), func() error {
 		var err error
-		acct, err = client.AccountInfo(ctx, &api.OwnerQuery{Owner: id, Height: consensus.HeightLatest})
+		acct, err = client.AccountInfo(ctx, &api.OwnerQuery{Owner: id, Height: height})
 		return err
 	})
 
@@ -210,6 +429,8 @@ func Register(parentCmd *cobra.Command) {
 	for _, v := range []*cobra.Command{
 		infoCmd,
 		listCmd,
+		rewardsCmd,
+		reportCmd,
 		accountCmd,
 	} {
 		stakeCmd.AddCommand(v)
@@ -217,15 +438,30 @@ func Register(parentCmd *cobra.Command) {
 
 	infoCmd.Flags().AddFlagSet(infoFlags)
 	listCmd.Flags().AddFlagSet(listFlags)
+	rewardsCmd.Flags().AddFlagSet(rewardsFlags)
+	reportCmd.Flags().AddFlagSet(reportFlags)
 
 	parentCmd.AddCommand(stakeCmd)
 }
 
 func init() {
+	infoFlags.AddFlagSet(denominationFlags)
+	infoFlags.AddFlagSet(heightFlags)
 	infoFlags.AddFlagSet(cmdFlags.RetriesFlags)
 	infoFlags.AddFlagSet(cmdGrpc.ClientFlags)
 
+	listFlags.AddFlagSet(heightFlags)
 	listFlags.AddFlagSet(cmdFlags.RetriesFlags)
 	listFlags.AddFlagSet(cmdFlags.VerboseFlags)
 	listFlags.AddFlagSet(cmdGrpc.ClientFlags)
+
+	rewardsFlags.AddFlagSet(denominationFlags)
+	rewardsFlags.AddFlagSet(cmdFlags.RetriesFlags)
+	rewardsFlags.AddFlagSet(cmdFlags.VerboseFlags)
+	rewardsFlags.AddFlagSet(cmdGrpc.ClientFlags)
+
+	reportFlags.AddFlagSet(denominationFlags)
+	reportFlags.AddFlagSet(cmdFlags.RetriesFlags)
+	reportFlags.AddFlagSet(cmdFlags.VerboseFlags)
+	reportFlags.AddFlagSet(cmdGrpc.ClientFlags)
 }
@@ -42,6 +42,9 @@ type argBuilder struct {
 
 	// dontBlameOasis is true, if CfgDebugDontBlameOasis is passed.
 	dontBlameOasis bool
+
+	// consensusPort is the consensus P2P port, if CfgCoreListenAddress is passed.
+	consensusPort uint16
 }
 
 func (args *argBuilder) internalSocketAddress(path string) *argBuilder {
@@ -139,6 +142,14 @@ func (args *argBuilder) tendermintCoreListenAddress(port uint16) *argBuilder {
 	args.vec = append(args.vec, []string{
 		"--" + tendermint.CfgCoreListenAddress, "tcp://0.0.0.0:" + strconv.Itoa(int(port)),
 	}...)
+	args.consensusPort = port
+	return args
+}
+
+func (args *argBuilder) tendermintCoreExternalAddress(port uint16) *argBuilder {
+	args.vec = append(args.vec, []string{
+		"--" + tendermint.CfgCoreExternalAddress, "tcp://127.0.0.1:" + strconv.Itoa(int(port)),
+	}...)
 	return args
 }
 
@@ -378,12 +389,11 @@ func (args *argBuilder) addSentries(sentries []*Sentry) *argBuilder {
 }
 
 func (args *argBuilder) addValidatorsAsSentryUpstreams(validators []*Validator) *argBuilder {
-	var addrs, ids []string
+	var addrs []string
 	for _, val := range validators {
 		addrs = append(addrs, fmt.Sprintf("%s@127.0.0.1:%d", val.tmAddress, val.consensusPort))
-		ids = append(ids, val.NodeID.String())
 	}
-	return args.tendermintSentryUpstreamAddress(addrs).grpcSentryUpstreamIDs(ids)
+	return args.tendermintSentryUpstreamAddress(addrs)
 }
 
 func (args *argBuilder) addSentryStorageWorkers(storageWorkers []*Storage) *argBuilder {
@@ -506,6 +516,16 @@ func (args *argBuilder) byzantineActivationEpoch(epoch epochtime.EpochTime) *arg
 	return args
 }
 
+func (args *argBuilder) byzantineScenarioFile(path string) *argBuilder {
+	args.vec = append(args.vec, "--"+byzantine.CfgScenarioFile, path)
+	return args
+}
+
+func (args *argBuilder) byzantineKeyManagerPort(port uint16) *argBuilder {
+	args.vec = append(args.vec, "--"+byzantine.CfgKeyManagerPort, strconv.Itoa(int(port)))
+	return args
+}
+
 func newArgBuilder() *argBuilder {
 	return &argBuilder{}
 }
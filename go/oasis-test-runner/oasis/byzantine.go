@@ -1,10 +1,14 @@
 package oasis
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/oasislabs/oasis-core/go/common/node"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/byzantine"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 )
 
@@ -12,11 +16,13 @@ import (
 type Byzantine struct {
 	Node
 
-	script string
-	entity *Entity
+	script       string
+	scenarioPath string
+	entity       *Entity
 
 	consensusPort   uint16
 	p2pPort         uint16
+	keyManagerPort  uint16
 	activationEpoch epochtime.EpochTime
 }
 
@@ -25,6 +31,7 @@ type ByzantineCfg struct {
 	NodeCfg
 
 	Script       string
+	Scenario     *byzantine.Scenario
 	IdentitySeed string
 	Entity       *Entity
 
@@ -43,13 +50,23 @@ func (worker *Byzantine) startNode() error {
 		appendEntity(worker.entity).
 		byzantineActivationEpoch(worker.activationEpoch)
 
+	// Key manager Byzantine nodes register for the key manager runtime.
+	runtimeKind := registry.KindCompute
+	if worker.keyManagerPort != 0 {
+		runtimeKind = registry.KindKeyManager
+		args = args.byzantineKeyManagerPort(worker.keyManagerPort)
+	}
 	for _, v := range worker.net.Runtimes() {
-		if v.kind == registry.KindCompute && v.teeHardware == node.TEEHardwareIntelSGX {
+		if v.kind == runtimeKind && v.teeHardware == node.TEEHardwareIntelSGX {
 			args = args.byzantineFakeSGX()
 			args = args.byzantineVersionFakeEnclaveID(v)
 		}
 	}
 
+	if worker.scenarioPath != "" {
+		args = args.byzantineScenarioFile(worker.scenarioPath)
+	}
+
 	if err := worker.net.startOasisNode(&worker.Node, []string{"debug", "byzantine", worker.script}, args); err != nil {
 		return fmt.Errorf("oasis/byzantine: failed to launch node %s: %w", worker.Name, err)
 	}
@@ -70,6 +87,27 @@ func (net *Network) NewByzantine(cfg *ByzantineCfg) (*Byzantine, error) {
 		return nil, fmt.Errorf("oasis/byzantine: failed to create byzantine node subdir: %w", err)
 	}
 
+	var scenarioPath string
+	if cfg.Scenario != nil {
+		if cfg.Script != "" {
+			return nil, fmt.Errorf("oasis/byzantine: script and scenario are mutually exclusive")
+		}
+		if err = cfg.Scenario.Validate(); err != nil {
+			return nil, fmt.Errorf("oasis/byzantine: invalid scenario: %w", err)
+		}
+
+		// Write the scenario to a file consumed by the scenario command.
+		var data []byte
+		if data, err = json.Marshal(cfg.Scenario); err != nil {
+			return nil, fmt.Errorf("oasis/byzantine: failed to serialize scenario: %w", err)
+		}
+		scenarioPath = filepath.Join(byzantineDir.String(), "scenario.json")
+		if err = ioutil.WriteFile(scenarioPath, data, 0600); err != nil {
+			return nil, fmt.Errorf("oasis/byzantine: failed to write scenario: %w", err)
+		}
+		cfg.Script = "scenario"
+	}
+
 	if cfg.Script == "" {
 		return nil, fmt.Errorf("oasis/byzantine: empty script name: %w", err)
 	}
@@ -100,6 +138,7 @@ func (net *Network) NewByzantine(cfg *ByzantineCfg) (*Byzantine, error) {
 			consensus:                                cfg.Consensus,
 		},
 		script:          cfg.Script,
+		scenarioPath:    scenarioPath,
 		entity:          cfg.Entity,
 		consensusPort:   net.nextNodePort,
 		p2pPort:         net.nextNodePort + 1,
@@ -107,9 +146,14 @@ func (net *Network) NewByzantine(cfg *ByzantineCfg) (*Byzantine, error) {
 	}
 	worker.doStartNode = worker.startNode
 	copy(worker.NodeID[:], publicKey[:])
+	net.nextNodePort += 2
+
+	if cfg.Scenario != nil && cfg.Scenario.Role == byzantine.RoleKeyManager {
+		worker.keyManagerPort = net.nextNodePort
+		net.nextNodePort++
+	}
 
 	net.byzantine = append(net.byzantine, worker)
-	net.nextNodePort += 2
 
 	if err := net.AddLogWatcher(&worker.Node); err != nil {
 		net.logger.Error("failed to add log watcher",
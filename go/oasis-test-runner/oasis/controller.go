@@ -9,8 +9,10 @@ import (
 	keymanager "github.com/oasislabs/oasis-core/go/keymanager/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	runtimeClient "github.com/oasislabs/oasis-core/go/runtime/client/api"
+	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
 	staking "github.com/oasislabs/oasis-core/go/staking/api"
 	storage "github.com/oasislabs/oasis-core/go/storage/api"
+	storageWorker "github.com/oasislabs/oasis-core/go/worker/storage/api"
 )
 
 // Controller is a network controller that connects to one of the
@@ -22,8 +24,10 @@ type Controller struct {
 	Consensus     consensus.ClientBackend
 	Staking       staking.Backend
 	Registry      registry.Backend
+	Scheduler     scheduler.Backend
 	RuntimeClient runtimeClient.RuntimeClient
 	Storage       storage.Backend
+	StorageWorker storageWorker.StorageWorker
 	Keymanager    *keymanager.KeymanagerClient
 
 	conn *grpc.ClientConn
@@ -52,8 +56,10 @@ func NewController(socketPath string) (*Controller, error) {
 		Consensus:       consensus.NewConsensusClient(conn),
 		Staking:         staking.NewStakingClient(conn),
 		Registry:        registry.NewRegistryClient(conn),
+		Scheduler:       scheduler.NewSchedulerClient(conn),
 		RuntimeClient:   runtimeClient.NewRuntimeClient(conn),
 		Storage:         storage.NewStorageClient(conn),
+		StorageWorker:   storageWorker.NewStorageWorkerClient(conn),
 		Keymanager:      keymanager.NewKeymanagerClient(conn),
 
 		conn: conn,
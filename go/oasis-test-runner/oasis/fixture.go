@@ -8,6 +8,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/sgx"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/byzantine"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/log"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
@@ -424,9 +425,10 @@ func (f *ClientFixture) Create(net *Network) (*Client, error) {
 
 // ByzantineFixture is a byzantine node fixture.
 type ByzantineFixture struct {
-	Script       string `json:"script"`
-	IdentitySeed string `json:"identity_seed"`
-	Entity       int    `json:"entity"`
+	Script       string              `json:"script"`
+	Scenario     *byzantine.Scenario `json:"scenario,omitempty"`
+	IdentitySeed string              `json:"identity_seed"`
+	Entity       int                 `json:"entity"`
 
 	ActivationEpoch epochtime.EpochTime `json:"activation_epoch"`
 
@@ -451,6 +453,7 @@ func (f *ByzantineFixture) Create(net *Network) (*Byzantine, error) {
 			Consensus:                                f.Consensus,
 		},
 		Script:          f.Script,
+		Scenario:        f.Scenario,
 		IdentitySeed:    f.IdentitySeed,
 		Entity:          entity,
 		ActivationEpoch: f.ActivationEpoch,
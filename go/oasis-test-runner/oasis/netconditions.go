@@ -0,0 +1,459 @@
+package oasis
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/logging"
+)
+
+const (
+	// defaultRetransmitDelay is the default delay of data that is lost and
+	// needs to be retransmitted.
+	defaultRetransmitDelay = 200 * time.Millisecond
+
+	netProxyDialTimeout = 5 * time.Second
+	netProxyBufferSize  = 32 * 1024
+	netProxyQueueSize   = 128
+)
+
+// NetworkConditionsCfg is the configuration of network conditions injected
+// between nodes.
+//
+// The conditions are applied by an in-process proxy placed in front of the
+// consensus P2P port of each selected node, which the node advertises to its
+// peers instead of its own port. They therefore only affect connections to
+// the selected nodes, not connections that selected nodes open to nodes that
+// are not selected.
+type NetworkConditionsCfg struct {
+	// Nodes are the names of the selected nodes (e.g., validator-1).
+	Nodes []string `json:"nodes"`
+
+	// Latency is the delay added to all data sent over affected connections.
+	Latency time.Duration `json:"latency,omitempty"`
+	// Jitter is the maximum random delay added on top of Latency.
+	Jitter time.Duration `json:"jitter,omitempty"`
+
+	// PacketLoss is the probability (between 0 and 1) that a chunk of data
+	// sent over affected connections is lost. As connections are reliable,
+	// lost data is delayed by RetransmitDelay instead of being dropped.
+	PacketLoss float64 `json:"packet_loss,omitempty"`
+	// RetransmitDelay is the delay of lost data. If zero, a default of 200ms
+	// is used.
+	RetransmitDelay time.Duration `json:"retransmit_delay,omitempty"`
+
+	// PartitionInterval is the interval between the starts of periodic
+	// partitions, during which all connections to the selected nodes are
+	// closed and new connections are refused. If zero, there are no periodic
+	// partitions.
+	PartitionInterval time.Duration `json:"partition_interval,omitempty"`
+	// PartitionDuration is the duration of each periodic partition.
+	PartitionDuration time.Duration `json:"partition_duration,omitempty"`
+}
+
+// ValidateBasic performs basic network conditions configuration checks.
+func (cfg *NetworkConditionsCfg) ValidateBasic() error {
+	if len(cfg.Nodes) == 0 {
+		return fmt.Errorf("no nodes selected")
+	}
+	if cfg.Latency < 0 || cfg.Jitter < 0 || cfg.RetransmitDelay < 0 {
+		return fmt.Errorf("negative delay")
+	}
+	if cfg.PacketLoss < 0 || cfg.PacketLoss > 1 {
+		return fmt.Errorf("packet loss must be between 0 and 1")
+	}
+	if cfg.PartitionInterval > 0 && (cfg.PartitionDuration <= 0 || cfg.PartitionDuration >= cfg.PartitionInterval) {
+		return fmt.Errorf("partition duration must be positive and shorter than the partition interval")
+	}
+	return nil
+}
+
+type delayedChunk struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+// netProxy is a TCP proxy that forwards connections to a node while applying
+// network conditions.
+type netProxy struct {
+	sync.Mutex
+
+	cfg    *NetworkConditionsCfg
+	target string
+
+	listener    net.Listener
+	conns       map[net.Conn]bool
+	rng         *rand.Rand
+	partitioned bool
+	disabled    bool
+	closed      bool
+
+	logger *logging.Logger
+}
+
+func (p *netProxy) port() uint16 {
+	return uint16(p.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// delay returns the delay of the next chunk of forwarded data.
+func (p *netProxy) delay() time.Duration {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.disabled {
+		return 0
+	}
+
+	delay := p.cfg.Latency
+	if p.cfg.Jitter > 0 {
+		delay += time.Duration(p.rng.Int63n(int64(p.cfg.Jitter)))
+	}
+	if p.cfg.PacketLoss > 0 && p.rng.Float64() < p.cfg.PacketLoss {
+		delay += p.cfg.RetransmitDelay
+	}
+	return delay
+}
+
+func (p *netProxy) track(conns ...net.Conn) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.partitioned || p.closed {
+		return false
+	}
+	for _, conn := range conns {
+		p.conns[conn] = true
+	}
+	return true
+}
+
+func (p *netProxy) untrack(conns ...net.Conn) {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, conn := range conns {
+		delete(p.conns, conn)
+		_ = conn.Close()
+	}
+}
+
+func (p *netProxy) closeConnsLocked() {
+	for conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.conns = make(map[net.Conn]bool)
+}
+
+func (p *netProxy) setPartitioned(partitioned bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.partitioned = partitioned
+	if partitioned {
+		p.closeConnsLocked()
+	}
+}
+
+func (p *netProxy) disable() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.disabled = true
+	p.partitioned = false
+}
+
+func (p *netProxy) close() {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.closed {
+		return
+	}
+	p.closed = true
+	_ = p.listener.Close()
+	p.closeConnsLocked()
+}
+
+func (p *netProxy) serve() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p *netProxy) handle(conn net.Conn) {
+	if !p.track(conn) {
+		// Refuse connections during partitions.
+		_ = conn.Close()
+		return
+	}
+
+	upstream, err := net.DialTimeout("tcp", p.target, netProxyDialTimeout)
+	if err != nil {
+		p.logger.Debug("failed to dial proxy target",
+			"err", err,
+			"target", p.target,
+		)
+		p.untrack(conn)
+		return
+	}
+	if !p.track(upstream) {
+		p.untrack(conn, upstream)
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go p.forward(&wg, upstream, conn)
+	go p.forward(&wg, conn, upstream)
+	wg.Wait()
+
+	p.untrack(conn, upstream)
+}
+
+func (p *netProxy) forward(wg *sync.WaitGroup, dst, src net.Conn) {
+	defer wg.Done()
+
+	queueCh := make(chan *delayedChunk, netProxyQueueSize)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+
+		for chunk := range queueCh {
+			time.Sleep(time.Until(chunk.deliverAt))
+			if _, err := dst.Write(chunk.data); err != nil {
+				// Make sure that the reader terminates and drain the queue.
+				_ = src.Close()
+				for range queueCh {
+				}
+				return
+			}
+		}
+	}()
+
+	var last time.Time
+	buf := make([]byte, netProxyBufferSize)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			// Data must be delivered in order, so it can never overtake any
+			// data that was delayed more.
+			deliverAt := time.Now().Add(p.delay())
+			if deliverAt.Before(last) {
+				deliverAt = last
+			}
+			last = deliverAt
+
+			queueCh <- &delayedChunk{
+				data:      append([]byte{}, buf[:n]...),
+				deliverAt: deliverAt,
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				p.logger.Debug("proxied connection terminated",
+					"err", err,
+				)
+			}
+			break
+		}
+	}
+	close(queueCh)
+	<-doneCh
+
+	// Terminate the connection in both directions.
+	_ = dst.Close()
+}
+
+func newNetProxy(logger *logging.Logger, cfg *NetworkConditionsCfg, port, targetPort uint16) (*netProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(int(port)))
+	if err != nil {
+		return nil, fmt.Errorf("oasis/netconditions: failed to listen: %w", err)
+	}
+
+	p := &netProxy{
+		cfg:      cfg,
+		target:   "127.0.0.1:" + strconv.Itoa(int(targetPort)),
+		listener: listener,
+		conns:    make(map[net.Conn]bool),
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		logger:   logger,
+	}
+	go p.serve()
+
+	return p, nil
+}
+
+// NetworkConditions controls the network conditions injected between nodes.
+type NetworkConditions struct {
+	sync.Mutex
+
+	cfg      NetworkConditionsCfg
+	selected map[string]bool
+	proxies  map[string]*netProxy
+
+	partitioned bool
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+
+	logger *logging.Logger
+}
+
+// Partition closes all connections to the selected nodes and refuses new
+// connections until Heal is called.
+//
+// Note that periodic partitions, if configured, also heal the partition when
+// they end.
+func (nc *NetworkConditions) Partition() {
+	nc.setPartitioned(true)
+}
+
+// Heal ends the current partition.
+func (nc *NetworkConditions) Heal() {
+	nc.setPartitioned(false)
+}
+
+// IsPartitioned returns true iff the selected nodes are currently partitioned.
+func (nc *NetworkConditions) IsPartitioned() bool {
+	nc.Lock()
+	defer nc.Unlock()
+
+	return nc.partitioned
+}
+
+// Disable stops periodic partitions, heals any current partition and removes
+// all network conditions, so that the network can be checked for recovery.
+func (nc *NetworkConditions) Disable() {
+	nc.stopPartitions()
+
+	nc.Lock()
+	defer nc.Unlock()
+
+	nc.logger.Info("disabling network conditions")
+
+	nc.partitioned = false
+	for _, p := range nc.proxies {
+		p.disable()
+	}
+}
+
+func (nc *NetworkConditions) setPartitioned(partitioned bool) {
+	nc.Lock()
+	defer nc.Unlock()
+
+	nc.logger.Info("changing network partition state",
+		"partitioned", partitioned,
+		"nodes", nc.cfg.Nodes,
+	)
+
+	nc.partitioned = partitioned
+	for _, p := range nc.proxies {
+		p.setPartitioned(partitioned)
+	}
+}
+
+// proxy returns the proxy of the given node's consensus P2P port, creating
+// it if needed. It returns nil if the node is not selected.
+func (nc *NetworkConditions) proxy(network *Network, node *Node, consensusPort uint16) (*netProxy, error) {
+	if !nc.selected[node.Name] {
+		return nil, nil
+	}
+
+	nc.Lock()
+	defer nc.Unlock()
+
+	// Proxies persist across node restarts.
+	if p := nc.proxies[node.Name]; p != nil {
+		return p, nil
+	}
+
+	p, err := newNetProxy(nc.logger.With("node", node.Name), &nc.cfg, network.nextNodePort, consensusPort)
+	if err != nil {
+		return nil, err
+	}
+	network.nextNodePort++
+	p.setPartitioned(nc.partitioned)
+	nc.proxies[node.Name] = p
+
+	nc.logger.Debug("provisioned network conditions proxy",
+		"node", node.Name,
+		"port", p.port(),
+		"target_port", consensusPort,
+	)
+
+	return p, nil
+}
+
+func (nc *NetworkConditions) startPartitions() {
+	if nc.cfg.PartitionInterval == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(nc.cfg.PartitionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-nc.stopCh:
+				return
+			case <-ticker.C:
+			}
+
+			nc.Partition()
+			select {
+			case <-nc.stopCh:
+				return
+			case <-time.After(nc.cfg.PartitionDuration):
+			}
+			nc.Heal()
+		}
+	}()
+}
+
+func (nc *NetworkConditions) stopPartitions() {
+	nc.stopOnce.Do(func() {
+		close(nc.stopCh)
+	})
+}
+
+func (nc *NetworkConditions) close() {
+	nc.stopPartitions()
+
+	nc.Lock()
+	defer nc.Unlock()
+
+	for _, p := range nc.proxies {
+		p.close()
+	}
+}
+
+func newNetworkConditions(logger *logging.Logger, cfg *NetworkConditionsCfg) (*NetworkConditions, error) {
+	if err := cfg.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("oasis/netconditions: invalid configuration: %w", err)
+	}
+
+	nc := &NetworkConditions{
+		cfg:      *cfg,
+		selected: make(map[string]bool),
+		proxies:  make(map[string]*netProxy),
+		stopCh:   make(chan struct{}),
+		logger:   logger,
+	}
+	if nc.cfg.RetransmitDelay == 0 {
+		nc.cfg.RetransmitDelay = defaultRetransmitDelay
+	}
+	for _, name := range cfg.Nodes {
+		nc.selected[name] = true
+	}
+
+	return nc, nil
+}
@@ -0,0 +1,126 @@
+package oasis
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/logging"
+)
+
+func startEchoServer(t *testing.T) (uint16, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "Listen")
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return uint16(listener.Addr().(*net.TCPAddr).Port), func() { listener.Close() }
+}
+
+func proxyRoundTrip(t *testing.T, port uint16) (time.Duration, error) {
+	conn, err := net.Dial("tcp", "127.0.0.1:"+strconv.Itoa(int(port)))
+	require.NoError(t, err, "Dial")
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	start := time.Now()
+	msg := []byte("hello")
+	if _, err = conn.Write(msg); err != nil {
+		return 0, err
+	}
+	rsp := make([]byte, len(msg))
+	if _, err = io.ReadFull(conn, rsp); err != nil {
+		return 0, err
+	}
+	require.Equal(t, msg, rsp, "echoed message should be unchanged")
+	return time.Since(start), nil
+}
+
+func TestNetworkConditionsCfg(t *testing.T) {
+	require := require.New(t)
+
+	cfg := NetworkConditionsCfg{
+		Nodes:             []string{"validator-1"},
+		Latency:           100 * time.Millisecond,
+		PacketLoss:        0.1,
+		PartitionInterval: 10 * time.Second,
+		PartitionDuration: 5 * time.Second,
+	}
+	require.NoError(cfg.ValidateBasic(), "ValidateBasic")
+
+	invalid := cfg
+	invalid.Nodes = nil
+	require.Error(invalid.ValidateBasic(), "no selected nodes")
+	invalid = cfg
+	invalid.PacketLoss = 1.5
+	require.Error(invalid.ValidateBasic(), "packet loss above 1")
+	invalid = cfg
+	invalid.PartitionDuration = invalid.PartitionInterval
+	require.Error(invalid.ValidateBasic(), "partition as long as the interval")
+	invalid = cfg
+	invalid.PartitionDuration = 0
+	require.Error(invalid.ValidateBasic(), "periodic partition without duration")
+}
+
+func TestNetworkConditionsProxy(t *testing.T) {
+	require := require.New(t)
+
+	echoPort, stopEcho := startEchoServer(t)
+	defer stopEcho()
+
+	logger := logging.GetLogger("oasis/netconditions/test")
+	nc, err := newNetworkConditions(logger, &NetworkConditionsCfg{
+		Nodes:   []string{"validator-1"},
+		Latency: 50 * time.Millisecond,
+	})
+	require.NoError(err, "newNetworkConditions")
+	defer nc.close()
+
+	network := &Network{nextNodePort: 0}
+	p, err := nc.proxy(network, &Node{Name: "validator-0"}, echoPort)
+	require.NoError(err, "proxy")
+	require.Nil(p, "nodes that are not selected should not be proxied")
+	p, err = nc.proxy(network, &Node{Name: "validator-1"}, echoPort)
+	require.NoError(err, "proxy")
+	require.NotNil(p, "selected nodes should be proxied")
+	p2, err := nc.proxy(network, &Node{Name: "validator-1"}, echoPort)
+	require.NoError(err, "proxy")
+	require.Equal(p, p2, "proxies should be reused")
+
+	// Latency should be added in both directions.
+	rtt, err := proxyRoundTrip(t, p.port())
+	require.NoError(err, "round trip")
+	require.True(rtt >= 100*time.Millisecond, "round trip should be delayed (rtt: %s)", rtt)
+
+	// Connections should be refused during partitions.
+	nc.Partition()
+	require.True(nc.IsPartitioned(), "IsPartitioned")
+	_, err = proxyRoundTrip(t, p.port())
+	require.Error(err, "round trip during partition")
+
+	nc.Heal()
+	require.False(nc.IsPartitioned(), "IsPartitioned")
+	_, err = proxyRoundTrip(t, p.port())
+	require.NoError(err, "round trip after partition")
+
+	// Disabling network conditions should remove all delays.
+	nc.Disable()
+	rtt, err = proxyRoundTrip(t, p.port())
+	require.NoError(err, "round trip")
+	require.True(rtt < 100*time.Millisecond, "round trip should not be delayed (rtt: %s)", rtt)
+}
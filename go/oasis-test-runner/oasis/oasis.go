@@ -2,6 +2,7 @@
 package oasis
 
 import (
+	"context"
 	"crypto"
 	"fmt"
 	"io"
@@ -23,6 +24,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/node"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	genesisFile "github.com/oasislabs/oasis-core/go/genesis/file"
 	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
@@ -38,6 +40,7 @@ const (
 	baseNodePort = 20000
 
 	validatorStartDelay = 3 * time.Second
+	gracefulStopTimeout = 30 * time.Second
 
 	defaultConsensusBackend            = "tendermint"
 	defaultConsensusTimeoutCommit      = 250 * time.Millisecond
@@ -103,7 +106,7 @@ func (n *Node) DataDir() string {
 	return n.dir.String()
 }
 
-func (n *Node) stopNode() error {
+func (n *Node) stopNode(graceful bool) error {
 	if n.cmd == nil {
 		return nil
 	}
@@ -114,7 +117,17 @@ func (n *Node) stopNode() error {
 	n.Unlock()
 
 	// Stop the node and wait for it to stop.
-	_ = n.cmd.Process.Kill()
+	if graceful {
+		_ = n.cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-n.Exit():
+		case <-time.After(gracefulStopTimeout):
+			// Node failed to stop in time, kill it.
+			_ = n.cmd.Process.Kill()
+		}
+	} else {
+		_ = n.cmd.Process.Kill()
+	}
 	_ = n.cmd.Wait()
 	<-n.Exit()
 	n.cmd = nil
@@ -124,12 +137,22 @@ func (n *Node) stopNode() error {
 
 // Stop stops the node.
 func (n *Node) Stop() error {
-	return n.stopNode()
+	return n.stopNode(false)
 }
 
 // Restart kills the node, waits for it to stop, and starts it again.
 func (n *Node) Restart() error {
-	if err := n.stopNode(); err != nil {
+	if err := n.stopNode(false); err != nil {
+		return err
+	}
+	return n.doStartNode()
+}
+
+// RestartGracefully requests the node to shut down by sending it a SIGTERM,
+// waits for it to stop, and starts it again. In case the node does not stop
+// within a reasonable amount of time, it is killed.
+func (n *Node) RestartGracefully() error {
+	if err := n.stopNode(true); err != nil {
 		return err
 	}
 	return n.doStartNode()
@@ -204,6 +227,8 @@ type Network struct {
 	seedNode *seedNode
 	iasProxy *iasProxy
 
+	networkConditions *NetworkConditions
+
 	cfg          *NetworkCfg
 	nextNodePort uint16
 
@@ -254,12 +279,25 @@ type NetworkCfg struct { // nolint: maligned
 	// EpochtimeTendermintInterval is the tendermint epochtime block interval.
 	EpochtimeTendermintInterval int64 `json:"epochtime_tendermint_interval"`
 
+	// EpochSchedule is an optional script of epoch transitions that is
+	// applied automatically via the debug controller once the network is
+	// started. It requires the mock epochtime backend.
+	EpochSchedule epochtime.EpochSchedule `json:"epoch_schedule,omitempty"`
+
 	// DeterministicIdentities is the deterministic identities flag.
 	DeterministicIdentities bool `json:"deterministic_identities"`
 
+	// RoothashDebugAllowForceRoundTimeout is the flag allowing round timeouts
+	// to be forced via the debug controller.
+	RoothashDebugAllowForceRoundTimeout bool `json:"roothash_debug_allow_force_round_timeout"`
+
 	// IAS is the Network IAS configuration.
 	IAS IASCfg `json:"ias"`
 
+	// NetworkConditions is an optional configuration of network conditions
+	// (latency, packet loss and periodic partitions) injected between nodes.
+	NetworkConditions *NetworkConditionsCfg `json:"network_conditions,omitempty"`
+
 	// StakingGenesis is the name of a file with a staking genesis document to use if GenesisFile isn't set.
 	StakingGenesis string `json:"staking_genesis"`
 
@@ -322,6 +360,12 @@ func (net *Network) Byzantine() []*Byzantine {
 	return net.byzantine
 }
 
+// NetworkConditions returns the controller of the network conditions
+// injected between nodes, or nil if no network conditions are configured.
+func (net *Network) NetworkConditions() *NetworkConditions {
+	return net.networkConditions
+}
+
 // Errors returns the channel by which node failures will be conveyed.
 func (net *Network) Errors() <-chan error {
 	return net.errCh
@@ -618,11 +662,41 @@ func (net *Network) Start() error { // nolint: gocyclo
 		break
 	}
 
+	if net.networkConditions != nil {
+		net.networkConditions.startPartitions()
+	}
+
+	if len(net.cfg.EpochSchedule) > 0 {
+		if err = net.applyEpochSchedule(); err != nil {
+			net.logger.Error("failed to apply epoch schedule",
+				"err", err,
+			)
+			return err
+		}
+	}
+
 	net.logger.Info("network started")
 
 	return nil
 }
 
+func (net *Network) applyEpochSchedule() error {
+	if !net.cfg.EpochtimeMock {
+		return fmt.Errorf("oasis: epoch schedule requires the mock epochtime backend")
+	}
+	if net.controller == nil {
+		return fmt.Errorf("oasis: epoch schedule requires a started validator")
+	}
+
+	net.logger.Debug("applying epoch schedule",
+		"schedule", net.cfg.EpochSchedule,
+	)
+	if err := net.controller.SetEpochSchedule(context.Background(), net.cfg.EpochSchedule); err != nil {
+		return fmt.Errorf("oasis: failed to set epoch schedule: %w", err)
+	}
+	return nil
+}
+
 // Stop stops the network.
 func (net *Network) Stop() {
 	net.env.Cleanup()
@@ -709,6 +783,16 @@ func (net *Network) startOasisNode(
 			tendermintDebugAddrBookLenient().
 			tendermintDebugAllowDuplicateIP()
 	}
+	if net.networkConditions != nil && extraArgs.consensusPort != 0 {
+		// Advertise the network conditions proxy instead of the node's own port.
+		proxy, err := net.networkConditions.proxy(net, node, extraArgs.consensusPort)
+		if err != nil {
+			return fmt.Errorf("oasis: failed to provision network conditions proxy: %w", err)
+		}
+		if proxy != nil {
+			extraArgs = extraArgs.tendermintCoreExternalAddress(proxy.port())
+		}
+	}
 	if net.cfg.UseShortGrpcSocketPaths {
 		// Keep the socket, if it was already generated!
 		if node.customGrpcSocketPath == "" {
@@ -795,6 +879,9 @@ func (net *Network) makeGenesis() error {
 	if net.cfg.DeterministicIdentities {
 		args = append(args, "--beacon.debug.deterministic")
 	}
+	if net.cfg.RoothashDebugAllowForceRoundTimeout {
+		args = append(args, "--"+genesis.CfgRoothashDebugAllowForceRoundTimeout)
+	}
 	for _, v := range net.entities {
 		args = append(args, v.toGenesisDescriptorArgs()...)
 	}
@@ -895,14 +982,23 @@ func New(env *env.Env, cfg *NetworkCfg) (*Network, error) {
 		cfgCopy.HaltEpoch = defaultHaltEpoch
 	}
 
-	return &Network{
+	net := &Network{
 		logger:       logging.GetLogger("oasis/" + env.Name()),
 		env:          env,
 		baseDir:      baseDir,
 		cfg:          &cfgCopy,
 		nextNodePort: baseNodePort,
 		errCh:        make(chan error, maxNodes),
-	}, nil
+	}
+
+	if cfgCopy.NetworkConditions != nil {
+		if net.networkConditions, err = newNetworkConditions(net.logger.With("component", "netconditions"), cfgCopy.NetworkConditions); err != nil {
+			return nil, err
+		}
+		env.AddOnCleanup(net.networkConditions.close)
+	}
+
+	return net, nil
 }
 
 func nodeLogPath(dir *env.Dir) string {
@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/byzantine"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/log"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis"
@@ -36,6 +37,19 @@ var (
 		oasis.LogAssertExecutionDiscrepancyDetected(),
 		oasis.LogAssertNoMergeDiscrepancyDetected(),
 	}, oasis.ByzantineSlot3IdentitySeed)
+	// ByzantineExecutorScenarioWrongStateRoot is the byzantine executor scenario
+	// that commits to a wrong state root, described by a fault-injection scenario.
+	ByzantineExecutorScenarioWrongStateRoot scenario.Scenario = newByzantineScenarioImpl("executor-scenario-wrong-state-root", &byzantine.Scenario{
+		Role: byzantine.RoleExecutor,
+		Faults: []byzantine.Fault{
+			{Kind: byzantine.FaultWrongStateRoot},
+		},
+	}, []log.WatcherHandlerFactory{
+		oasis.LogAssertNoTimeouts(),
+		oasis.LogAssertNoRoundFailures(),
+		oasis.LogAssertExecutionDiscrepancyDetected(),
+		oasis.LogAssertNoMergeDiscrepancyDetected(),
+	}, oasis.ByzantineSlot3IdentitySeed)
 
 	// ByzantineMergeHonest is the byzantine merge honest scenario.
 	ByzantineMergeHonest scenario.Scenario = newByzantineImpl("merge-honest", nil, oasis.ByzantineSlot1IdentitySeed)
@@ -59,6 +73,7 @@ type byzantineImpl struct {
 	runtimeImpl
 
 	script                     string
+	scenario                   *byzantine.Scenario
 	identitySeed               string
 	logWatcherHandlerFactories []log.WatcherHandlerFactory
 }
@@ -76,10 +91,26 @@ func newByzantineImpl(script string, logWatcherHandlerFactories []log.WatcherHan
 	}
 }
 
+// newByzantineScenarioImpl creates a byzantine scenario where the byzantine
+// node injects the faults described by the given fault-injection scenario.
+func newByzantineScenarioImpl(name string, byzantineScenario *byzantine.Scenario, logWatcherHandlerFactories []log.WatcherHandlerFactory, identitySeed string) scenario.Scenario {
+	return &byzantineImpl{
+		runtimeImpl: *newRuntimeImpl(
+			"byzantine/"+name,
+			"simple-keyvalue-ops-client",
+			[]string{"set", "hello_key", "hello_value"},
+		),
+		scenario:                   byzantineScenario,
+		identitySeed:               identitySeed,
+		logWatcherHandlerFactories: logWatcherHandlerFactories,
+	}
+}
+
 func (sc *byzantineImpl) Clone() scenario.Scenario {
 	return &byzantineImpl{
 		runtimeImpl:                *sc.runtimeImpl.Clone().(*runtimeImpl),
 		script:                     sc.script,
+		scenario:                   sc.scenario,
 		identitySeed:               sc.identitySeed,
 		logWatcherHandlerFactories: sc.logWatcherHandlerFactories,
 	}
@@ -104,6 +135,7 @@ func (sc *byzantineImpl) Fixture() (*oasis.NetworkFixture, error) {
 	f.ByzantineNodes = []oasis.ByzantineFixture{
 		oasis.ByzantineFixture{
 			Script:          sc.script,
+			Scenario:        sc.scenario,
 			IdentitySeed:    sc.identitySeed,
 			Entity:          1,
 			ActivationEpoch: 1,
@@ -0,0 +1,171 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	keymanager "github.com/oasislabs/oasis-core/go/keymanager/api"
+	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/byzantine"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/log"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/scenario"
+)
+
+const (
+	// byzantineKmTxs is the number of transactions that must succeed while
+	// the Byzantine key manager is part of the key manager committee.
+	byzantineKmTxs = 5
+	// byzantineKmTimeout is the timeout for the whole load phase.
+	byzantineKmTimeout = 5 * time.Minute
+)
+
+var (
+	// ByzantineKeymanagerStalePolicy is the byzantine key manager scenario
+	// where the key manager acknowledges policy updates but enforces a stale
+	// policy.
+	ByzantineKeymanagerStalePolicy scenario.Scenario = newByzantineKmImpl("stale-policy", byzantine.FaultStalePolicy)
+	// ByzantineKeymanagerWithholdReplies is the byzantine key manager scenario
+	// where the key manager never replies to requests.
+	ByzantineKeymanagerWithholdReplies scenario.Scenario = newByzantineKmImpl("withhold-replies", byzantine.FaultWithholdReplies)
+	// ByzantineKeymanagerMalformedReplies is the byzantine key manager
+	// scenario where the key manager replies with malformed ciphertexts.
+	ByzantineKeymanagerMalformedReplies scenario.Scenario = newByzantineKmImpl("malformed-replies", byzantine.FaultMalformedReplies)
+)
+
+type byzantineKmImpl struct {
+	runtimeImpl
+
+	scenario *byzantine.Scenario
+}
+
+func newByzantineKmImpl(name string, kind byzantine.FaultKind) scenario.Scenario {
+	return &byzantineKmImpl{
+		runtimeImpl: *newRuntimeImpl("byzantine/keymanager-"+name, "", nil),
+		scenario: &byzantine.Scenario{
+			Role: byzantine.RoleKeyManager,
+			Faults: []byzantine.Fault{
+				{Kind: kind},
+			},
+		},
+	}
+}
+
+func (sc *byzantineKmImpl) Clone() scenario.Scenario {
+	return &byzantineKmImpl{
+		runtimeImpl: *sc.runtimeImpl.Clone().(*runtimeImpl),
+		scenario:    sc.scenario,
+	}
+}
+
+func (sc *byzantineKmImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.runtimeImpl.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	// Epoch transitions are triggered manually.
+	f.Network.EpochtimeMock = true
+	// Compute nodes must route around the Byzantine key manager.
+	f.Network.DefaultLogWatcherHandlerFactories = []log.WatcherHandlerFactory{
+		oasis.LogAssertNoRoundFailures(),
+	}
+	// Provision a Byzantine key manager node. It registers once the honest
+	// key manager has initialized the key manager status.
+	f.ByzantineNodes = []oasis.ByzantineFixture{
+		oasis.ByzantineFixture{
+			Scenario:        sc.scenario,
+			IdentitySeed:    oasis.ByzantineDefaultIdentitySeed,
+			Entity:          1,
+			ActivationEpoch: 1,
+		},
+	}
+	return f, nil
+}
+
+func (sc *byzantineKmImpl) Run(childEnv *env.Env) error {
+	if err := sc.net.Start(); err != nil {
+		return err
+	}
+
+	if err := sc.initialEpochTransitions(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), byzantineKmTimeout)
+	defer cancel()
+
+	// Make sure that the Byzantine node is part of the key manager committee.
+	honest := sc.net.Keymanagers()[0]
+	byzantineNode := sc.net.Byzantine()[0]
+	if err := sc.waitKeymanagerNodes(ctx, []signature.PublicKey{honest.NodeID, byzantineNode.NodeID}); err != nil {
+		return err
+	}
+
+	// Each transaction uses a new key and thus requires a new key manager
+	// request, all of which must eventually be served by the honest node.
+	sc.logger.Info("submitting transactions")
+	for i := 0; i < byzantineKmTxs; i++ {
+		if err := sc.submitKeyValueRuntimeEncInsertTx(ctx, runtimeID, fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i)); err != nil {
+			return fmt.Errorf("transaction failed: %w", err)
+		}
+	}
+
+	// Compute nodes select key manager nodes at random, so the Byzantine
+	// node may not have been used. If it was, compute nodes must have
+	// detected it and failed over.
+	faults, err := countLogEvents(byzantineNode.LogPath(), byzantine.LogEventKeyManagerFaultInjected)
+	if err != nil {
+		return err
+	}
+	var failovers int
+	for _, n := range sc.net.ComputeWorkers() {
+		var count int
+		if count, err = countLogEvents(n.LogPath(), keymanager.LogEventNodeFailover); err != nil {
+			return err
+		}
+		failovers += count
+	}
+	sc.logger.Info("byzantine key manager faults",
+		"faults", faults,
+		"failovers", failovers,
+	)
+	if faults > 0 && failovers == 0 {
+		return fmt.Errorf("byzantine key manager not detected by compute nodes (%d faults injected)", faults)
+	}
+
+	return sc.net.CheckLogWatchers()
+}
+
+// countLogEvents returns the number of times the given event was emitted
+// based on the JSON log output at the given path.
+func countLogEvents(path, event string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	var count int
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		var kvs map[string]interface{}
+		if err = json.Unmarshal(scanner.Bytes(), &kvs); err != nil {
+			continue
+		}
+		if v, ok := kvs[logging.LogEvent].(string); ok && v == event {
+			count++
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read log: %w", err)
+	}
+	return count, nil
+}
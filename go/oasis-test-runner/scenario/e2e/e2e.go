@@ -2,9 +2,14 @@
 package e2e
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	flag "github.com/spf13/pflag"
 
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/cmd"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis"
@@ -13,6 +18,10 @@ import (
 
 const (
 	cfgNodeBinary = "node.binary"
+
+	// consensusPollInterval is the interval at which the consensus height is
+	// polled while waiting for progress.
+	consensusPollInterval = 1 * time.Second
 )
 
 var (
@@ -65,6 +74,63 @@ func (sc *e2eImpl) Init(childEnv *env.Env, net *oasis.Network) error {
 	return nil
 }
 
+func consensusHeight(ctx context.Context, ctrl *oasis.Controller) (int64, error) {
+	blk, err := ctrl.Consensus.GetBlock(ctx, consensus.HeightLatest)
+	if err != nil {
+		return 0, err
+	}
+	return blk.Height, nil
+}
+
+// waitForConsensusHeight waits until the consensus layer of the node behind
+// the given controller reaches the given height, failing after the timeout.
+func waitForConsensusHeight(ctx context.Context, ctrl *oasis.Controller, height int64, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(consensusPollInterval)
+	defer ticker.Stop()
+
+	var current int64
+	for {
+		// Errors are expected while the node is unreachable, so keep polling.
+		if h, err := consensusHeight(ctx, ctrl); err == nil {
+			current = h
+			if current >= height {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("consensus height %d not reached in %s (current height: %d)", height, timeout, current)
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForConsensusProgress asserts consensus liveness by waiting until the
+// node behind the given controller observes the given number of new blocks,
+// failing after the timeout.
+func waitForConsensusProgress(ctx context.Context, ctrl *oasis.Controller, blocks int64, timeout time.Duration) error {
+	height, err := consensusHeight(ctx, ctrl)
+	if err != nil {
+		return fmt.Errorf("failed to query consensus height: %w", err)
+	}
+	return waitForConsensusHeight(ctx, ctrl, height+blocks, timeout)
+}
+
+// waitForConsensusCatchUp asserts recovery by waiting until the node behind
+// ctrl reaches the height of the node behind ref at the time of the call,
+// failing after the timeout.
+func waitForConsensusCatchUp(ctx context.Context, ctrl, ref *oasis.Controller, timeout time.Duration) error {
+	height, err := consensusHeight(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to query reference consensus height: %w", err)
+	}
+	return waitForConsensusHeight(ctx, ctrl, height, timeout)
+}
+
 // RegisterScenarios registers all end-to-end scenarios.
 func RegisterScenarios() error {
 	// Register non-scenario-specific parameters.
@@ -80,12 +146,19 @@ func RegisterScenarios() error {
 		ByzantineExecutorHonest,
 		ByzantineExecutorWrong,
 		ByzantineExecutorStraggler,
+		ByzantineExecutorScenarioWrongStateRoot,
 		// Byzantine merge node.
 		ByzantineMergeHonest,
 		ByzantineMergeWrong,
 		ByzantineMergeStraggler,
+		// Byzantine key manager node.
+		ByzantineKeymanagerStalePolicy,
+		ByzantineKeymanagerWithholdReplies,
+		ByzantineKeymanagerMalformedReplies,
 		// Storage sync test.
 		StorageSync,
+		// Storage committee rotation test.
+		StorageRotation,
 		// Sentry test.
 		Sentry,
 		SentryEncryption,
@@ -93,6 +166,8 @@ func RegisterScenarios() error {
 		KeymanagerRestart,
 		// Keymanager replicate test.
 		KeymanagerReplicate,
+		// Keymanager failover test.
+		KeymanagerFailover,
 		// Dump/restore test.
 		DumpRestore,
 		// Halt test.
@@ -128,6 +203,8 @@ func RegisterScenarios() error {
 		RestoreV206,
 		// KeymanagerUpgrade test.
 		KeymanagerUpgrade,
+		// Network chaos test.
+		NetworkChaos,
 	} {
 		if err := cmd.Register(s); err != nil {
 			return err
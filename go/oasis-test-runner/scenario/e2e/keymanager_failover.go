@@ -0,0 +1,229 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/scenario"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	runtimeClient "github.com/oasislabs/oasis-core/go/runtime/client/api"
+)
+
+const (
+	// kmFailoverTxsBefore is the number of transactions that must succeed
+	// before the primary key manager is killed.
+	kmFailoverTxsBefore = 3
+	// kmFailoverTxsAfter is the number of transactions that must succeed
+	// after the primary key manager is killed.
+	kmFailoverTxsAfter = 5
+	// kmFailoverMaxRounds is the maximum number of runtime rounds after
+	// the primary key manager is killed within which a replica must take
+	// over.
+	kmFailoverMaxRounds = 3
+	// kmFailoverTimeout is the timeout for the whole load phase.
+	kmFailoverTimeout = 5 * time.Minute
+	// kmFailoverStatusTimeout is the timeout for waiting on the key manager
+	// status after the primary key manager is killed.
+	kmFailoverStatusTimeout = 1 * time.Minute
+)
+
+var (
+	// KeymanagerFailover is the keymanager failover scenario.
+	KeymanagerFailover scenario.Scenario = newKmFailoverImpl()
+)
+
+type kmFailoverImpl struct {
+	runtimeImpl
+}
+
+func newKmFailoverImpl() scenario.Scenario {
+	return &kmFailoverImpl{
+		runtimeImpl: *newRuntimeImpl("keymanager-failover", "", nil),
+	}
+}
+
+func (sc *kmFailoverImpl) Clone() scenario.Scenario {
+	return &kmFailoverImpl{
+		runtimeImpl: *sc.runtimeImpl.Clone().(*runtimeImpl),
+	}
+}
+
+func (sc *kmFailoverImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.runtimeImpl.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	// Epoch transitions are triggered manually.
+	f.Network.EpochtimeMock = true
+	// This requires multiple keymanagers.
+	f.Keymanagers = []oasis.KeymanagerFixture{
+		oasis.KeymanagerFixture{Runtime: 0, Entity: 1},
+		oasis.KeymanagerFixture{Runtime: 0, Entity: 1},
+	}
+
+	return f, nil
+}
+
+type kmFailoverTxResult struct {
+	round uint64
+	err   error
+}
+
+func (sc *kmFailoverImpl) Run(childEnv *env.Env) error {
+	if err := sc.net.Start(); err != nil {
+		return err
+	}
+
+	if err := sc.initialEpochTransitions(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kmFailoverTimeout)
+	defer cancel()
+
+	// Make sure that the replica has replicated the master secret before
+	// killing the primary.
+	primary := sc.net.Keymanagers()[0]
+	replica := sc.net.Keymanagers()[1]
+	if err := sc.waitKeymanagerNodes(ctx, []signature.PublicKey{primary.NodeID, replica.NodeID}); err != nil {
+		return err
+	}
+
+	// Generate continuous transaction load. Each transaction uses a new
+	// key and thus requires a new key manager request.
+	resultCh := make(chan *kmFailoverTxResult)
+	go func() {
+		defer close(resultCh)
+		for i := 0; ; i++ {
+			err := sc.submitKeyValueRuntimeEncInsertTx(ctx, runtimeID, fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i))
+			var round uint64
+			if err == nil {
+				round, err = sc.latestRuntimeRound(ctx)
+			}
+			select {
+			case resultCh <- &kmFailoverTxResult{round: round, err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	nextResult := func() (*kmFailoverTxResult, error) {
+		select {
+		case err := <-sc.net.Errors():
+			return nil, err
+		case res, ok := <-resultCh:
+			if !ok {
+				return nil, ctx.Err()
+			}
+			if res.err != nil {
+				return nil, fmt.Errorf("transaction failed: %w", res.err)
+			}
+			return res, nil
+		}
+	}
+
+	sc.logger.Info("waiting for transactions to succeed before killing the primary key manager")
+	for i := 0; i < kmFailoverTxsBefore; i++ {
+		if _, err := nextResult(); err != nil {
+			return err
+		}
+	}
+
+	killRound, err := sc.latestRuntimeRound(ctx)
+	if err != nil {
+		return err
+	}
+	sc.logger.Info("killing the primary key manager",
+		"node_id", primary.NodeID,
+		"round", killRound,
+	)
+	if err = primary.Stop(); err != nil {
+		return fmt.Errorf("failed to kill the primary key manager: %w", err)
+	}
+
+	sc.logger.Info("waiting for transactions to succeed after killing the primary key manager")
+	for i := 0; i < kmFailoverTxsAfter; i++ {
+		res, err := nextResult()
+		if err != nil {
+			return err
+		}
+		if i == 0 && res.round > killRound+kmFailoverMaxRounds {
+			return fmt.Errorf("replica took over too late (killed at round %d, first success at round %d)",
+				killRound,
+				res.round,
+			)
+		}
+	}
+	cancel()
+
+	// The replica must still be serving the key manager.
+	statusCtx, statusCancel := context.WithTimeout(context.Background(), kmFailoverStatusTimeout)
+	defer statusCancel()
+	if err = sc.waitKeymanagerNodes(statusCtx, []signature.PublicKey{replica.NodeID}); err != nil {
+		return err
+	}
+
+	return sc.net.CheckLogWatchers()
+}
+
+// waitKeymanagerNodes waits for the key manager status to be initialized
+// and to include all of the given nodes.
+func (sc *runtimeImpl) waitKeymanagerNodes(ctx context.Context, nodeIDs []signature.PublicKey) error {
+	sc.logger.Info("waiting for key manager nodes",
+		"node_ids", nodeIDs,
+	)
+
+	ch, sub, err := sc.net.ClientController().Keymanager.WatchStatuses(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("failed to watch key manager statuses: %w", err)
+	}
+	defer sub.Close()
+
+	status, err := sc.net.ClientController().Keymanager.GetStatus(ctx, &registry.NamespaceQuery{
+		ID:     keymanagerID,
+		Height: consensus.HeightLatest,
+	})
+	for {
+		if err == nil && status.IsInitialized && status.ID.Equal(&keymanagerID) {
+			nodes := make(map[signature.PublicKey]bool)
+			for _, v := range status.Nodes {
+				nodes[v] = true
+			}
+			ready := true
+			for _, v := range nodeIDs {
+				ready = ready && nodes[v]
+			}
+			if ready {
+				return nil
+			}
+		}
+
+		select {
+		case annSt, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("key manager status subscription closed")
+			}
+			status, err = annSt.Status, nil
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for key manager nodes: %w", ctx.Err())
+		}
+	}
+}
+
+func (sc *kmFailoverImpl) latestRuntimeRound(ctx context.Context) (uint64, error) {
+	blk, err := sc.net.ClientController().RuntimeClient.GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: runtimeID,
+		Round:     runtimeClient.RoundLatest,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch latest runtime block: %w", err)
+	}
+	return blk.Header.Round, nil
+}
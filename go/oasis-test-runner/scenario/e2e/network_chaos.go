@@ -0,0 +1,108 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/scenario"
+)
+
+const (
+	// chaosValidator is the name of the validator subject to degraded
+	// network conditions.
+	chaosValidator = "validator-3"
+
+	chaosPartitionInterval = 30 * time.Second
+	chaosPartitionDuration = 10 * time.Second
+
+	// chaosBlocks is the number of blocks that need to be produced while the
+	// network is degraded. It is chosen so that the network needs to make
+	// progress over multiple partitions.
+	chaosBlocks = 200
+)
+
+// NetworkChaos is the scenario where one of the validators is subject to
+// latency, packet loss and periodic partitions.
+var NetworkChaos scenario.Scenario = &networkChaosImpl{
+	runtimeImpl: *newRuntimeImpl("network-chaos", "", nil),
+}
+
+type networkChaosImpl struct {
+	runtimeImpl
+}
+
+func (sc *networkChaosImpl) Clone() scenario.Scenario {
+	return &networkChaosImpl{
+		runtimeImpl: *sc.runtimeImpl.Clone().(*runtimeImpl),
+	}
+}
+
+func (sc *networkChaosImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.runtimeImpl.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use four validators so that consensus can make progress while one of
+	// them is partitioned.
+	f.Validators = append(f.Validators, oasis.ValidatorFixture{Entity: 1})
+	f.Network.NetworkConditions = &oasis.NetworkConditionsCfg{
+		Nodes:             []string{chaosValidator},
+		Latency:           100 * time.Millisecond,
+		Jitter:            50 * time.Millisecond,
+		PacketLoss:        0.01,
+		PartitionInterval: chaosPartitionInterval,
+		PartitionDuration: chaosPartitionDuration,
+	}
+
+	return f, nil
+}
+
+func (sc *networkChaosImpl) Run(childEnv *env.Env) error {
+	if err := sc.net.Start(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	ctrl := sc.net.Controller()
+
+	sc.logger.Info("waiting for nodes to register")
+	if err := ctrl.WaitNodesRegistered(ctx, sc.net.NumRegisterNodes()); err != nil {
+		return fmt.Errorf("failed to wait for nodes to register: %w", err)
+	}
+
+	// The network should remain live while degraded.
+	sc.logger.Info("checking consensus liveness with degraded network conditions")
+	if err := waitForConsensusProgress(ctx, ctrl, chaosBlocks, 5*chaosPartitionInterval); err != nil {
+		return fmt.Errorf("consensus not live with degraded network conditions: %w", err)
+	}
+
+	// The degraded validator should catch up once network conditions recover.
+	sc.logger.Info("checking recovery of the degraded validator")
+	sc.net.NetworkConditions().Disable()
+
+	var chaosCtrl *oasis.Controller
+	for _, v := range sc.net.Validators() {
+		if v.Name != chaosValidator {
+			continue
+		}
+		var err error
+		if chaosCtrl, err = oasis.NewController(v.SocketPath()); err != nil {
+			return fmt.Errorf("failed to create controller for %s: %w", chaosValidator, err)
+		}
+	}
+	if chaosCtrl == nil {
+		return fmt.Errorf("validator %s not found", chaosValidator)
+	}
+	if err := waitForConsensusCatchUp(ctx, chaosCtrl, ctrl, 2*chaosPartitionInterval); err != nil {
+		return fmt.Errorf("%s did not recover: %w", chaosValidator, err)
+	}
+	if err := waitForConsensusProgress(ctx, chaosCtrl, 10, chaosPartitionInterval); err != nil {
+		return fmt.Errorf("%s did not recover: %w", chaosValidator, err)
+	}
+
+	return sc.net.CheckLogWatchers()
+}
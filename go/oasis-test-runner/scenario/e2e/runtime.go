@@ -565,6 +565,30 @@ func (sc *runtimeImpl) submitKeyValueRuntimeInsertTx(ctx context.Context, id com
 	return err
 }
 
+func (sc *runtimeImpl) submitKeyValueRuntimeGetTx(ctx context.Context, id common.Namespace, key string) (string, error) {
+	rsp, err := sc.submitRuntimeTx(ctx, id, "get", key)
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	if err = cbor.Unmarshal(rsp, &value); err != nil {
+		return "", fmt.Errorf("failed to unmarshal get tx response from runtime: %w", err)
+	}
+	return value, nil
+}
+
+func (sc *runtimeImpl) submitKeyValueRuntimeEncInsertTx(ctx context.Context, id common.Namespace, key, value string) error {
+	_, err := sc.submitRuntimeTx(ctx, id, "enc_insert", struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}{
+		Key:   key,
+		Value: value,
+	})
+	return err
+}
+
 func (sc *runtimeImpl) waitNodesSynced() error {
 	ctx := context.Background()
 
@@ -0,0 +1,218 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/scenario"
+	runtimeClient "github.com/oasislabs/oasis-core/go/runtime/client/api"
+	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
+	"github.com/oasislabs/oasis-core/go/storage/database"
+	storageWorker "github.com/oasislabs/oasis-core/go/worker/storage/api"
+)
+
+var (
+	// StorageRotation is the storage committee rotation scenario.
+	StorageRotation scenario.Scenario = newStorageRotationImpl()
+)
+
+const (
+	// rotationNumStorageWorkers is the number of provisioned storage workers,
+	// which is larger than the storage committee size so that committee
+	// membership changes between epochs.
+	rotationNumStorageWorkers = 4
+	// rotationNumEpochs is the number of epoch transitions performed. With
+	// two out of four storage workers elected each epoch, the probability
+	// that the committee never changes is negligible.
+	rotationNumEpochs = 8
+	// rotationSyncTimeout is the time the storage workers have to catch up
+	// with the latest round after a committee rotation.
+	rotationSyncTimeout = 30 * time.Second
+)
+
+type storageRotationImpl struct {
+	runtimeImpl
+}
+
+func newStorageRotationImpl() scenario.Scenario {
+	return &storageRotationImpl{
+		runtimeImpl: *newRuntimeImpl("storage-rotation", "", nil),
+	}
+}
+
+func (sc *storageRotationImpl) Clone() scenario.Scenario {
+	return &storageRotationImpl{
+		runtimeImpl: *sc.runtimeImpl.Clone().(*runtimeImpl),
+	}
+}
+
+func (sc *storageRotationImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.runtimeImpl.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	// Epoch transitions are triggered by the scenario.
+	f.Network.EpochtimeMock = true
+	// Provision more storage workers than the storage committee size.
+	f.StorageWorkers = nil
+	for i := 0; i < rotationNumStorageWorkers; i++ {
+		f.StorageWorkers = append(f.StorageWorkers, oasis.StorageWorkerFixture{
+			Backend: database.BackendNameBadgerDB,
+			Entity:  1,
+		})
+	}
+
+	return f, nil
+}
+
+func (sc *storageRotationImpl) Run(childEnv *env.Env) error {
+	if err := sc.net.Start(); err != nil {
+		return err
+	}
+
+	if err := sc.initialEpochTransitions(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	members, err := sc.storageCommittee(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rotations int
+	for i := 0; i < rotationNumEpochs; i++ {
+		// Write before the transition, so that there is state to hand over.
+		key := fmt.Sprintf("rotation %d", i)
+		if err = sc.submitKeyValueRuntimeInsertTx(ctx, runtimeID, key, fmt.Sprintf("before epoch %d", i)); err != nil {
+			return fmt.Errorf("failed to insert before epoch transition: %w", err)
+		}
+
+		epoch := epochtime.EpochTime(3 + i)
+		sc.logger.Info("triggering epoch transition",
+			"epoch", epoch,
+		)
+		if err = sc.net.Controller().SetEpoch(ctx, epoch); err != nil {
+			return fmt.Errorf("failed to set epoch: %w", err)
+		}
+
+		var newMembers map[signature.PublicKey]bool
+		if newMembers, err = sc.storageCommittee(ctx); err != nil {
+			return err
+		}
+		var joined []signature.PublicKey
+		for id := range newMembers {
+			if !members[id] {
+				joined = append(joined, id)
+			}
+		}
+		if len(joined) > 0 || len(newMembers) != len(members) {
+			rotations++
+		}
+		sc.logger.Info("storage committee elected",
+			"epoch", epoch,
+			"joined", joined,
+			"rotations", rotations,
+		)
+
+		// Reads and writes must keep working during the handover. Any round
+		// that fails due to missing storage receipts is caught by the log
+		// watchers.
+		var value string
+		if value, err = sc.submitKeyValueRuntimeGetTx(ctx, runtimeID, key); err != nil {
+			return fmt.Errorf("failed to get after epoch transition: %w", err)
+		}
+		if expected := fmt.Sprintf("before epoch %d", i); value != expected {
+			return fmt.Errorf("unexpected value after epoch transition (expected: %s got: %s)", expected, value)
+		}
+		if err = sc.submitKeyValueRuntimeInsertTx(ctx, runtimeID, key, fmt.Sprintf("after epoch %d", i)); err != nil {
+			return fmt.Errorf("failed to insert after epoch transition: %w", err)
+		}
+
+		// All storage workers, including the ones that joined or left the
+		// committee, must have caught up with the latest round.
+		if err = sc.waitStorageWorkersSynced(ctx); err != nil {
+			return err
+		}
+
+		members = newMembers
+	}
+
+	if rotations == 0 {
+		return fmt.Errorf("storage committee did not rotate in %d epochs", rotationNumEpochs)
+	}
+	return nil
+}
+
+// storageCommittee returns the members of the current storage committee.
+func (sc *storageRotationImpl) storageCommittee(ctx context.Context) (map[signature.PublicKey]bool, error) {
+	committees, err := sc.net.Controller().Scheduler.GetCommittees(ctx, &scheduler.GetCommitteesRequest{
+		Height:    consensus.HeightLatest,
+		RuntimeID: runtimeID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get committees: %w", err)
+	}
+
+	for _, committee := range committees {
+		if committee.Kind != scheduler.KindStorage {
+			continue
+		}
+		members := make(map[signature.PublicKey]bool)
+		for _, member := range committee.Members {
+			members[member.PublicKey] = true
+		}
+		return members, nil
+	}
+	return nil, fmt.Errorf("no storage committee elected")
+}
+
+// waitStorageWorkersSynced waits for all storage workers to sync the latest
+// runtime round.
+func (sc *storageRotationImpl) waitStorageWorkersSynced(ctx context.Context) error {
+	blk, err := sc.net.ClientController().RuntimeClient.GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: runtimeID,
+		Round:     runtimeClient.RoundLatest,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	syncCtx, cancel := context.WithTimeout(ctx, rotationSyncTimeout)
+	defer cancel()
+
+	for _, sw := range sc.net.StorageWorkers() {
+		ctrl, cerr := oasis.NewController(sw.SocketPath())
+		if cerr != nil {
+			return fmt.Errorf("failed to connect with storage worker %s: %w", sw.Name, cerr)
+		}
+		for {
+			rsp, rerr := ctrl.StorageWorker.GetLastSyncedRound(syncCtx, &storageWorker.GetLastSyncedRoundRequest{
+				RuntimeID: runtimeID,
+			})
+			if rerr == nil && rsp.Round >= blk.Header.Round {
+				break
+			}
+
+			select {
+			case <-syncCtx.Done():
+				ctrl.Close()
+				return fmt.Errorf("storage worker %s did not sync round %d: %w", sw.Name, blk.Header.Round, syncCtx.Err())
+			case <-time.After(1 * time.Second):
+			}
+		}
+		ctrl.Close()
+	}
+
+	sc.logger.Info("storage workers synced",
+		"round", blk.Header.Round,
+	)
+	return nil
+}
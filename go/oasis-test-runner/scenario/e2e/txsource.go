@@ -18,6 +18,7 @@ import (
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
+	cmdGrpc "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/grpc"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/txsource"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/debug/txsource/workload"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
@@ -31,6 +32,8 @@ const (
 	timeLimitLong  = 6 * time.Hour
 
 	nodeRestartIntervalLong = 2 * time.Minute
+	nodeKillProbabilityLong = 0.5
+	nodeRecoveryTimeout     = 5 * time.Minute
 	livenessCheckInterval   = 1 * time.Minute
 	txSourceGasPrice        = 1
 )
@@ -39,6 +42,7 @@ const (
 var TxSourceMultiShort scenario.Scenario = &txSourceImpl{
 	runtimeImpl: *newRuntimeImpl("txsource-multi-short", "", nil),
 	workloads: []string{
+		workload.NameChurn,
 		workload.NameCommission,
 		workload.NameDelegation,
 		workload.NameOversized,
@@ -59,6 +63,7 @@ var TxSourceMultiShort scenario.Scenario = &txSourceImpl{
 var TxSourceMulti scenario.Scenario = &txSourceImpl{
 	runtimeImpl: *newRuntimeImpl("txsource-multi", "", nil),
 	workloads: []string{
+		workload.NameChurn,
 		workload.NameCommission,
 		workload.NameDelegation,
 		workload.NameOversized,
@@ -70,6 +75,8 @@ var TxSourceMulti scenario.Scenario = &txSourceImpl{
 	},
 	timeLimit:                         timeLimitLong,
 	nodeRestartInterval:               nodeRestartIntervalLong,
+	nodeKillProbability:               nodeKillProbabilityLong,
+	restartClients:                    true,
 	livenessCheckInterval:             livenessCheckInterval,
 	consensusPruneDisabledProbability: 0.1,
 	consensusPruneMinKept:             100,
@@ -88,6 +95,13 @@ type txSourceImpl struct { // nolint: maligned
 	nodeRestartInterval   time.Duration
 	livenessCheckInterval time.Duration
 
+	// nodeKillProbability is the probability that a node is killed instead of
+	// being gracefully restarted.
+	nodeKillProbability float32
+	// restartClients controls whether the client node that the workloads are
+	// connected to is also restarted, forcing the workloads to reconnect.
+	restartClients bool
+
 	consensusPruneDisabledProbability float32
 	consensusPruneMinKept             int64
 	consensusPruneMaxKept             int64
@@ -213,6 +227,11 @@ func (sc *txSourceImpl) manager(env *env.Env, errCh chan error) {
 	for _, v := range sc.net.Validators() {
 		nodes = append(nodes, &v.Node)
 	}
+	if sc.restartClients {
+		for _, v := range sc.net.Clients() {
+			nodes = append(nodes, &v.Node)
+		}
+	}
 	// TODO: Consider including storage/compute workers.
 
 	restartTicker := time.NewTicker(sc.nodeRestartInterval)
@@ -236,16 +255,7 @@ func (sc *txSourceImpl) manager(env *env.Env, errCh chan error) {
 			}
 
 			// Choose a random node and restart it.
-			node := nodes[nodeIndex]
-			sc.logger.Info("restarting node",
-				"node", node.Name,
-			)
-
-			if err := node.Restart(); err != nil {
-				sc.logger.Error("failed to restart node",
-					"node", node.Name,
-					"err", err,
-				)
+			if err := sc.restartNode(nodes[nodeIndex]); err != nil {
 				errCh <- err
 				return
 			}
@@ -280,6 +290,53 @@ func (sc *txSourceImpl) manager(env *env.Env, errCh chan error) {
 	}
 }
 
+// restartNode either kills or gracefully restarts the given node and waits
+// for it to recover.
+func (sc *txSourceImpl) restartNode(node *oasis.Node) error {
+	kill := sc.rng.Float32() < sc.nodeKillProbability
+	sc.logger.Info("restarting node",
+		"node", node.Name,
+		"kill", kill,
+	)
+
+	var err error
+	if kill {
+		err = node.Restart()
+	} else {
+		err = node.RestartGracefully()
+	}
+	if err != nil {
+		sc.logger.Error("failed to restart node",
+			"node", node.Name,
+			"err", err,
+		)
+		return err
+	}
+
+	// Make sure the node recovers in time.
+	ctrl, err := oasis.NewController(node.SocketPath())
+	if err != nil {
+		return fmt.Errorf("failed to create controller for node %s: %w", node.Name, err)
+	}
+	defer ctrl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), nodeRecoveryTimeout)
+	defer cancel()
+	if err = ctrl.WaitSync(ctx); err != nil {
+		sc.logger.Error("node failed to recover after restart",
+			"node", node.Name,
+			"err", err,
+		)
+		return fmt.Errorf("node %s failed to recover after restart: %w", node.Name, err)
+	}
+
+	sc.logger.Info("node recovered after restart",
+		"node", node.Name,
+	)
+
+	return nil
+}
+
 func (sc *txSourceImpl) startWorkload(childEnv *env.Env, errCh chan error, name string) error {
 	sc.logger.Info("starting workload",
 		"name", name,
@@ -301,6 +358,8 @@ func (sc *txSourceImpl) startWorkload(childEnv *env.Env, errCh chan error, name
 	args := []string{
 		"debug", "txsource",
 		"--address", "unix:" + sc.net.Clients()[0].SocketPath(),
+		// Wait for the client node to become available in case it is being restarted.
+		"--" + cmdGrpc.CfgWait,
 		"--" + common.CfgDebugAllowTestKeys,
 		"--" + common.CfgDataDir, d.String(),
 		"--" + flags.CfgDebugDontBlameOasis,
@@ -345,6 +404,8 @@ func (sc *txSourceImpl) Clone() scenario.Scenario {
 		workloads:                         sc.workloads,
 		timeLimit:                         sc.timeLimit,
 		nodeRestartInterval:               sc.nodeRestartInterval,
+		nodeKillProbability:               sc.nodeKillProbability,
+		restartClients:                    sc.restartClients,
 		livenessCheckInterval:             sc.livenessCheckInterval,
 		consensusPruneDisabledProbability: sc.consensusPruneDisabledProbability,
 		consensusPruneMinKept:             sc.consensusPruneMinKept,
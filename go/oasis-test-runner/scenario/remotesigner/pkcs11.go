@@ -0,0 +1,98 @@
+package remotesigner
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	pkcs11Signer "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/pkcs11"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/scenario"
+)
+
+const (
+	cfgPKCS11Module = "module"
+	cfgPKCS11Token  = "token"
+	cfgPKCS11PIN    = "pin"
+)
+
+var (
+	// PKCS11 is the PKCS#11 backed remote signer test case.
+	//
+	// The configured token (e.g., a freshly initialized SoftHSM token) must
+	// support Ed25519 keys and must not contain any keys provisioned by a
+	// previous run.
+	PKCS11 scenario.Scenario = newPKCS11Impl()
+)
+
+func newPKCS11Impl() *pkcs11Impl {
+	return &pkcs11Impl{
+		remoteSignerImpl: *newRemoteSignerImpl("pkcs11"),
+	}
+}
+
+type pkcs11Impl struct {
+	remoteSignerImpl
+
+	module string
+	token  string
+	pin    string
+}
+
+func (sc *pkcs11Impl) Clone() scenario.Scenario {
+	return &pkcs11Impl{
+		remoteSignerImpl: sc.remoteSignerImpl.Clone(),
+		module:           sc.module,
+		token:            sc.token,
+		pin:              sc.pin,
+	}
+}
+
+func (sc *pkcs11Impl) Parameters() *flag.FlagSet {
+	fs := sc.remoteSignerImpl.Parameters()
+	fs.StringVar(&sc.module, cfgPKCS11Module, sc.module, "path to the PKCS#11 module")
+	fs.StringVar(&sc.token, cfgPKCS11Token, sc.token, "label of the PKCS#11 token")
+	fs.StringVar(&sc.pin, cfgPKCS11PIN, sc.pin, "user PIN of the PKCS#11 token")
+
+	return fs
+}
+
+func (sc *pkcs11Impl) Run(childEnv *env.Env) error {
+	if sc.module == "" {
+		return fmt.Errorf("PKCS#11 module not configured")
+	}
+
+	signerArgs := []string{
+		"--signer", pkcs11Signer.SignerName,
+		"--signer.pkcs11.module", sc.module,
+		"--signer.pkcs11.token", sc.token,
+		"--signer.pkcs11.pin", sc.pin,
+	}
+
+	serverCert, err := sc.provisionServer(childEnv, signerArgs)
+	if err != nil {
+		return err
+	}
+
+	// Since the server is backed by the PKCS#11 token, load the keys directly
+	// from the token so that comparisons can be made.
+	psf, err := pkcs11Signer.NewFactory(
+		&pkcs11Signer.FactoryConfig{
+			Module:     sc.module,
+			TokenLabel: sc.token,
+			PIN:        sc.pin,
+		},
+		signature.SignerRoles...,
+	)
+	if err != nil {
+		return err
+	}
+
+	sf, err := sc.startServer(childEnv, serverCert, signerArgs)
+	if err != nil {
+		return err
+	}
+
+	return sc.testSigners(childEnv, sf, psf)
+}
@@ -2,12 +2,22 @@
 package remotesigner
 
 import (
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"time"
+
 	flag "github.com/spf13/pflag"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	remoteSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/remote"
+	tlsCert "github.com/oasislabs/oasis-core/go/common/crypto/tls"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/cmd"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/env"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasislabs/oasis-core/go/oasis-test-runner/oasis/cli"
 	"github.com/oasislabs/oasis-core/go/oasis-test-runner/scenario"
 )
 
@@ -67,6 +77,141 @@ func (sc *remoteSignerImpl) Init(childEnv *env.Env, net *oasis.Network) error {
 	return nil
 }
 
+// provisionServer provisions the server keys and the client authentication
+// certificates, returning the server certificate.
+func (sc *remoteSignerImpl) provisionServer(childEnv *env.Env, signerArgs []string) (*tls.Certificate, error) {
+	sc.logger.Info("provisioning the server keys")
+	if err := cli.RunSubCommand(
+		childEnv,
+		sc.logger,
+		"init",
+		sc.serverBinary,
+		append([]string{
+			"--" + cmdCommon.CfgDataDir, childEnv.Dir(),
+			"init",
+		}, signerArgs...),
+	); err != nil {
+		return nil, err
+	}
+	serverCert, err := tlsCert.LoadCertificate(filepath.Join(childEnv.Dir(), "remote_signer_server_cert.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.logger.Info("provisioning the client authentication certificates")
+	if err = cli.RunSubCommand(
+		childEnv,
+		sc.logger,
+		"init_client",
+		sc.serverBinary,
+		[]string{
+			"--" + cmdCommon.CfgDataDir, childEnv.Dir(),
+			"init_client",
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return serverCert, nil
+}
+
+// startServer starts the server and returns a client signer factory
+// connected to it.
+func (sc *remoteSignerImpl) startServer(childEnv *env.Env, serverCert *tls.Certificate, signerArgs []string) (signature.SignerFactory, error) {
+	sc.logger.Info("starting server")
+	lw, err := childEnv.CurrentDir().NewLogWriter("server.log")
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := cli.StartSubCommand(
+		childEnv,
+		sc.logger,
+		"server",
+		sc.serverBinary,
+		append([]string{
+			"--" + cmdCommon.CfgDataDir, childEnv.Dir(),
+			"--client.certificate", filepath.Join(childEnv.Dir(), "remote_signer_client_cert.pem"),
+		}, signerArgs...),
+		lw,
+		lw,
+	)
+	if err != nil {
+		return nil, err
+	}
+	childEnv.AddTermOnCleanup(cmd)
+	time.Sleep(2 * time.Second) // TODO: Is this needed?
+
+	// Initialize a client.
+	sc.logger.Info("initializing in-process client")
+	clientCert, err := tlsCert.Load(
+		filepath.Join(childEnv.Dir(), "remote_signer_client_cert.pem"),
+		filepath.Join(childEnv.Dir(), "remote_signer_client_key.pem"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return remoteSigner.NewFactory(
+		&remoteSigner.FactoryConfig{
+			Address:           "127.0.0.1:9001",
+			ClientCertificate: clientCert,
+			ServerCertificate: serverCert,
+		},
+		signature.SignerRoles...,
+	)
+}
+
+// testSigners tests that the remote signers work, and that they match the
+// signers of the factory backing the server.
+func (sc *remoteSignerImpl) testSigners(childEnv *env.Env, sf, backingSf signature.SignerFactory) error {
+	// EnsureRole()
+	sc.logger.Info("testing EnsureRole")
+	for _, v := range signature.SignerRoles {
+		if err := sf.EnsureRole(v); err != nil {
+			return fmt.Errorf("failed to EnsureRole(%v): %w", v, err)
+		}
+	}
+
+	// Test each sub-key.
+	for _, v := range signature.SignerRoles {
+		// Load()
+		si, err := sf.Load(v)
+		if err != nil {
+			return fmt.Errorf("failed to Load(%v): %w", v, err)
+		}
+
+		pk := si.Public()
+		sc.logger.Info("remote signer loaded",
+			"public_key", pk,
+			"descr", si.String(),
+		)
+
+		// Ensure that the remote signer is reporting a matching public key.
+		bsi, err := backingSf.Load(v)
+		if err != nil {
+			return fmt.Errorf("failed to Load(%v) from backing signer: %w", v, err)
+		}
+		if !pk.Equal(bsi.Public()) {
+			return fmt.Errorf("public key mismatch: %v (expected: %v)", pk, bsi.Public())
+		}
+
+		msg := []byte("Alesia, alisanos, wake me when I'm gone")
+
+		ctx := signature.NewContext(fmt.Sprintf("test context: %v using datadir: %s", v, childEnv.Dir()))
+		sig, err := si.ContextSign(ctx, msg)
+		if err != nil {
+			return fmt.Errorf("failed to Sign(%v): %w", v, err)
+		}
+
+		// Verify that the signature is sensible, no need to re-sign with
+		// the backing signer since the public key and context are sensible.
+		if !pk.Verify(ctx, msg, sig) {
+			return fmt.Errorf("failed to verify signature: %v", v)
+		}
+	}
+
+	return nil
+}
+
 // RegisterScenarios registers all scenarios for remote-signer.
 func RegisterScenarios() error {
 	// Register non-scenario-specific parameters.
@@ -82,5 +227,15 @@ func RegisterScenarios() error {
 		}
 	}
 
+	// Register non-default scenarios which are executed on-demand only.
+	for _, s := range []scenario.Scenario{
+		// PKCS#11 backed remote signer test case.
+		PKCS11,
+	} {
+		if err := cmd.RegisterNondefault(s); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
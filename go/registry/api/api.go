@@ -130,6 +130,14 @@ var (
 	// has runtimes.
 	ErrEntityHasRuntimes = errors.New(ModuleName, 19, "registry: entity still has runtimes")
 
+	// ErrNodeDoesNotServeRuntime is the error returned when a node attests
+	// readiness for a runtime that is not in its descriptor.
+	ErrNodeDoesNotServeRuntime = errors.New(ModuleName, 20, "registry: node does not serve runtime")
+
+	// ErrNoPendingRuntimeTransfer is the error returned when accepting
+	// ownership of a runtime that has no matching pending ownership transfer.
+	ErrNoPendingRuntimeTransfer = errors.New(ModuleName, 21, "registry: no pending runtime ownership transfer")
+
 	// MethodRegisterEntity is the method name for entity registrations.
 	MethodRegisterEntity = transaction.NewMethodName(ModuleName, "RegisterEntity", entity.SignedEntity{})
 	// MethodDeregisterEntity is the method name for entity deregistrations.
@@ -140,6 +148,14 @@ var (
 	MethodUnfreezeNode = transaction.NewMethodName(ModuleName, "UnfreezeNode", UnfreezeNode{})
 	// MethodRegisterRuntime is the method name for registering runtimes.
 	MethodRegisterRuntime = transaction.NewMethodName(ModuleName, "RegisterRuntime", SignedRuntime{})
+	// MethodAttestRuntimeReadiness is the method name for runtime readiness attestations.
+	MethodAttestRuntimeReadiness = transaction.NewMethodName(ModuleName, "AttestRuntimeReadiness", RuntimeReadinessAttestation{})
+	// MethodProveFreshness is the method name for node freshness proofs.
+	MethodProveFreshness = transaction.NewMethodName(ModuleName, "ProveFreshness", FreshnessProof{})
+	// MethodTransferRuntimeOwnership is the method name for offering runtime ownership transfers.
+	MethodTransferRuntimeOwnership = transaction.NewMethodName(ModuleName, "TransferRuntimeOwnership", TransferRuntimeOwnership{})
+	// MethodAcceptRuntimeOwnership is the method name for accepting runtime ownership transfers.
+	MethodAcceptRuntimeOwnership = transaction.NewMethodName(ModuleName, "AcceptRuntimeOwnership", SignedRuntime{})
 
 	// Methods is the list of all methods supported by the registry backend.
 	Methods = []transaction.MethodName{
@@ -148,6 +164,10 @@ var (
 		MethodRegisterNode,
 		MethodUnfreezeNode,
 		MethodRegisterRuntime,
+		MethodAttestRuntimeReadiness,
+		MethodProveFreshness,
+		MethodTransferRuntimeOwnership,
+		MethodAcceptRuntimeOwnership,
 	}
 
 	// RuntimesRequiredRoles are the Node roles that require runtimes.
@@ -182,6 +202,9 @@ type Backend interface {
 	// GetEntities gets a list of all registered entities.
 	GetEntities(context.Context, int64) ([]*entity.Entity, error)
 
+	// GetEntitiesPage gets a page of registered entities.
+	GetEntitiesPage(context.Context, *PageQuery) (*EntitiesPage, error)
+
 	// WatchEntities returns a channel that produces a stream of
 	// EntityEvent on entity registration changes.
 	WatchEntities(context.Context) (<-chan *EntityEvent, pubsub.ClosableSubscription, error)
@@ -192,9 +215,19 @@ type Backend interface {
 	// GetNodeStatus returns a node's status.
 	GetNodeStatus(context.Context, *IDQuery) (*NodeStatus, error)
 
+	// GetNodeByConsensusAddress gets a node by its consensus address (the
+	// Tendermint validator address derived from its consensus key).
+	GetNodeByConsensusAddress(context.Context, *ConsensusAddressQuery) (*node.Node, error)
+
 	// GetNodes gets a list of all registered nodes.
 	GetNodes(context.Context, int64) ([]*node.Node, error)
 
+	// GetNodesPage gets a page of registered nodes.
+	GetNodesPage(context.Context, *PageQuery) (*NodesPage, error)
+
+	// GetNodesForEntity gets a page of registered nodes of a given entity.
+	GetNodesForEntity(context.Context, *EntityNodesQuery) (*NodesPage, error)
+
 	// WatchNodes returns a channel that produces a stream of
 	// NodeEvent on node registration changes.
 	WatchNodes(context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error)
@@ -243,6 +276,77 @@ type NamespaceQuery struct {
 	ID     common.Namespace `json:"id"`
 }
 
+// ConsensusAddressQuery is a registry query by consensus address.
+type ConsensusAddressQuery struct {
+	Height  int64  `json:"height"`
+	Address []byte `json:"address"`
+}
+
+const (
+	// DefaultPageLimit is the default maximum number of descriptors in a
+	// page.
+	DefaultPageLimit = 100
+	// MaxPageLimit is the maximum number of descriptors in a page.
+	MaxPageLimit = 1000
+)
+
+// PageQuery is a registry query for a page of entities or nodes.
+//
+// Pages are returned in a stable order that is not related to the ordering of
+// the descriptor IDs.
+type PageQuery struct {
+	Height int64 `json:"height"`
+
+	// Cursor is the ID of the last descriptor of the previous page. If not
+	// set, the first page is returned.
+	Cursor *signature.PublicKey `json:"cursor,omitempty"`
+
+	// Limit is the maximum number of descriptors in the page. If zero,
+	// DefaultPageLimit is used. Limits above MaxPageLimit are capped.
+	Limit int `json:"limit,omitempty"`
+}
+
+// PageLimit returns the effective maximum number of descriptors in the page.
+func (q *PageQuery) PageLimit() int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultPageLimit
+	case q.Limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return q.Limit
+	}
+}
+
+// EntityNodesQuery is a registry query for a page of nodes of a given entity.
+type EntityNodesQuery struct {
+	PageQuery
+
+	// EntityID is the ID of the entity whose nodes should be returned.
+	EntityID signature.PublicKey `json:"entity_id"`
+}
+
+// EntitiesPage is a page of registered entities.
+type EntitiesPage struct {
+	Entities []*entity.Entity `json:"entities"`
+
+	// NextCursor is the cursor that should be used to query the next page.
+	// It is not set if this is the last page.
+	NextCursor *signature.PublicKey `json:"next_cursor,omitempty"`
+}
+
+// NodesPage is a page of registered nodes.
+//
+// As expired nodes are omitted, a page may contain fewer nodes than the
+// requested limit even if it is not the last page.
+type NodesPage struct {
+	Nodes []*node.Node `json:"nodes"`
+
+	// NextCursor is the cursor that should be used to query the next page.
+	// It is not set if this is the last page.
+	NextCursor *signature.PublicKey `json:"next_cursor,omitempty"`
+}
+
 // NewRegisterEntityTx creates a new register entity transaction.
 func NewRegisterEntityTx(nonce uint64, fee *transaction.Fee, sigEnt *entity.SignedEntity) *transaction.Transaction {
 	return transaction.NewTransaction(nonce, fee, MethodRegisterEntity, sigEnt)
@@ -268,6 +372,26 @@ func NewRegisterRuntimeTx(nonce uint64, fee *transaction.Fee, sigRt *SignedRunti
 	return transaction.NewTransaction(nonce, fee, MethodRegisterRuntime, sigRt)
 }
 
+// NewAttestRuntimeReadinessTx creates a new runtime readiness attestation transaction.
+func NewAttestRuntimeReadinessTx(nonce uint64, fee *transaction.Fee, attestation *RuntimeReadinessAttestation) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodAttestRuntimeReadiness, attestation)
+}
+
+// NewProveFreshnessTx creates a new node freshness proof transaction.
+func NewProveFreshnessTx(nonce uint64, fee *transaction.Fee, proof *FreshnessProof) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodProveFreshness, proof)
+}
+
+// NewTransferRuntimeOwnershipTx creates a new runtime ownership transfer offer transaction.
+func NewTransferRuntimeOwnershipTx(nonce uint64, fee *transaction.Fee, transfer *TransferRuntimeOwnership) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodTransferRuntimeOwnership, transfer)
+}
+
+// NewAcceptRuntimeOwnershipTx creates a new runtime ownership transfer acceptance transaction.
+func NewAcceptRuntimeOwnershipTx(nonce uint64, fee *transaction.Fee, sigRt *SignedRuntime) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodAcceptRuntimeOwnership, sigRt)
+}
+
 // EntityEvent is the event that is returned via WatchEntities to signify
 // entity registration changes and updates.
 type EntityEvent struct {
@@ -292,16 +416,36 @@ type NodeUnfrozenEvent struct {
 	NodeID signature.PublicKey `json:"node_id"`
 }
 
+// RuntimeOwnershipEvent signifies a change in the state of a runtime
+// ownership transfer.
+type RuntimeOwnershipEvent struct {
+	// ID is the runtime identifier.
+	ID common.Namespace `json:"id"`
+	// Owner is the entity that owned the runtime before the event.
+	Owner signature.PublicKey `json:"owner"`
+	// NewOwner is the entity the runtime is being transferred to. If it is
+	// equal to Owner and the transfer is not completed, the pending transfer
+	// has been cancelled.
+	NewOwner signature.PublicKey `json:"new_owner"`
+	// Completed is true iff the new owner has accepted the transfer.
+	Completed bool `json:"completed,omitempty"`
+}
+
 // Event is a registry event returned via GetEvents.
 type Event struct {
-	RuntimeEvent      *RuntimeEvent      `json:"runtime,omitempty"`
-	EntityEvent       *EntityEvent       `json:"entity,omitempty"`
-	NodeEvent         *NodeEvent         `json:"node,omitempty"`
-	NodeUnfrozenEvent *NodeUnfrozenEvent `json:"node_unfrozen,omitempty"`
+	RuntimeEvent          *RuntimeEvent          `json:"runtime,omitempty"`
+	EntityEvent           *EntityEvent           `json:"entity,omitempty"`
+	NodeEvent             *NodeEvent             `json:"node,omitempty"`
+	NodeUnfrozenEvent     *NodeUnfrozenEvent     `json:"node_unfrozen,omitempty"`
+	RuntimeOwnershipEvent *RuntimeOwnershipEvent `json:"runtime_ownership,omitempty"`
 }
 
 // NodeList is a per-epoch immutable node list.
 type NodeList struct {
+	// Epoch is the epoch of the node list.
+	Epoch epochtime.EpochTime `json:"epoch"`
+	// Nodes are the nodes that are registered (and not expired) in the
+	// given epoch.
 	Nodes []*node.Node `json:"nodes"`
 }
 
@@ -552,7 +696,7 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 
 			// If the node indicates TEE support for any of it's runtimes,
 			// validate the attestation evidence.
-			if err := VerifyNodeRuntimeEnclaveIDs(logger, rt, regRt, now); err != nil {
+			if err := VerifyNodeRuntimeEnclaveIDs(logger, rt, regRt, now, epoch); err != nil {
 				return nil, nil, err
 			}
 
@@ -746,7 +890,16 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 }
 
 // VerifyNodeRuntimeEnclaveIDs verifies TEE-specific attributes of the node's runtime.
-func VerifyNodeRuntimeEnclaveIDs(logger *logging.Logger, rt *node.Runtime, regRt *Runtime, ts time.Time) error {
+//
+// The enclave identities are checked against the runtime deployment that is
+// active at the given epoch.
+func VerifyNodeRuntimeEnclaveIDs(
+	logger *logging.Logger,
+	rt *node.Runtime,
+	regRt *Runtime,
+	ts time.Time,
+	epoch epochtime.EpochTime,
+) error {
 	// If no TEE available, do nothing.
 	if rt.Capabilities.TEE == nil {
 		return nil
@@ -761,7 +914,7 @@ func VerifyNodeRuntimeEnclaveIDs(logger *logging.Logger, rt *node.Runtime, regRt
 			return err
 		}
 
-		avr, err := avrBundle.Open(ias.IntelTrustRoots, ts)
+		avr, err := ias.DefaultVerifier.Open(&avrBundle, ts)
 		if err != nil {
 			return err
 		}
@@ -783,7 +936,7 @@ func VerifyNodeRuntimeEnclaveIDs(logger *logging.Logger, rt *node.Runtime, regRt
 		}
 
 		var vi VersionInfoIntelSGX
-		if err := cbor.Unmarshal(regRt.Version.TEE, &vi); err != nil {
+		if err := cbor.Unmarshal(regRt.ActiveDeployment(epoch).TEE, &vi); err != nil {
 			return err
 		}
 		var eidValid bool
@@ -1046,6 +1199,13 @@ func VerifyRegisterRuntimeArgs(
 		)
 		return nil, ErrInvalidArgument
 	}
+	if err := rt.ValidateDeployments(); err != nil {
+		logger.Error("RegisterRuntime: invalid runtime deployments",
+			"runtime", rt,
+			"err", err,
+		)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+	}
 
 	switch rt.Kind {
 	case KindCompute:
@@ -1109,6 +1269,14 @@ func VerifyRegisterRuntimeArgs(
 		)
 		return nil, fmt.Errorf("%w: test runtime not allowed", ErrInvalidArgument)
 	}
+	if err := VerifyRuntimeNamespace(params, &rt); err != nil {
+		logger.Error("RegisterRuntime: runtime ID not allowed",
+			"id", rt.ID,
+			"entity_id", rt.EntityID,
+			"err", err,
+		)
+		return nil, err
+	}
 
 	if err := rt.Genesis.SanityCheck(isGenesis); err != nil {
 		return nil, err
@@ -1268,6 +1436,63 @@ func VerifyRuntimeUpdate(logger *logging.Logger, currentRt, newRt *Runtime) erro
 	return nil
 }
 
+// VerifyRuntimeOwnershipTransfer verifies that the descriptor submitted when
+// accepting a runtime ownership transfer only changes the runtime owner.
+//
+// Any other descriptor changes must be made by the new owner through a regular
+// runtime update once the transfer is complete.
+func VerifyRuntimeOwnershipTransfer(logger *logging.Logger, currentRt, newRt *Runtime, newOwner signature.PublicKey) error {
+	if !newRt.EntityID.Equal(newOwner) {
+		logger.Error("AcceptRuntimeOwnership: descriptor owner is not the new owner",
+			"runtime_id", newRt.ID,
+			"owner", newRt.EntityID,
+			"new_owner", newOwner,
+		)
+		return ErrRuntimeUpdateNotAllowed
+	}
+
+	// The descriptor version may be bumped as the new descriptor is subject
+	// to the same version requirements as any other runtime update.
+	rt := *newRt
+	rt.EntityID = currentRt.EntityID
+	rt.DescriptorVersion = currentRt.DescriptorVersion
+	if !bytes.Equal(cbor.Marshal(currentRt), cbor.Marshal(&rt)) {
+		logger.Error("AcceptRuntimeOwnership: trying to change the runtime descriptor",
+			"runtime_id", newRt.ID,
+		)
+		return ErrRuntimeUpdateNotAllowed
+	}
+	return nil
+}
+
+// VerifyRuntimeDeploymentsUpdate verifies that a runtime descriptor update does
+// not schedule any deployments retroactively. Deployments that are already
+// active at the given epoch may only be kept unchanged or removed.
+func VerifyRuntimeDeploymentsUpdate(logger *logging.Logger, currentRt, newRt *Runtime, epoch epochtime.EpochTime) error {
+	for _, d := range newRt.Deployments {
+		if d.ValidFrom > epoch {
+			continue
+		}
+
+		var exists bool
+		for _, cd := range currentRt.Deployments {
+			if cd.Equal(d) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			logger.Error("RegisterRuntime: trying to schedule a deployment retroactively",
+				"runtime_id", newRt.ID,
+				"valid_from", d.ValidFrom,
+				"epoch", epoch,
+			)
+			return ErrRuntimeUpdateNotAllowed
+		}
+	}
+	return nil
+}
+
 // SortNodeList sorts the given node list to ensure a canonical order.
 func SortNodeList(nodes []*node.Node) {
 	sort.Slice(nodes, func(i, j int) bool {
@@ -1287,6 +1512,13 @@ type Genesis struct {
 	Runtimes []*SignedRuntime `json:"runtimes,omitempty"`
 	// SuspendedRuntimes is the list of suspended runtimes.
 	SuspendedRuntimes []*SignedRuntime `json:"suspended_runtimes,omitempty"`
+	// GovernanceSuspendedRuntimes is the list of identifiers of suspended
+	// runtimes that were suspended via a SuspendRuntime transaction and can
+	// only be resumed via a ResumeRuntime transaction.
+	GovernanceSuspendedRuntimes []common.Namespace `json:"governance_suspended_runtimes,omitempty"`
+	// PendingRuntimeTransfers is the list of runtime ownership transfers that
+	// have been offered but not yet accepted.
+	PendingRuntimeTransfers []*PendingRuntimeTransfer `json:"pending_runtime_transfers,omitempty"`
 
 	// Nodes is the initial list of nodes.
 	Nodes []*node.MultiSignedNode `json:"nodes,omitempty"`
@@ -1321,12 +1553,21 @@ type ConsensusParameters struct {
 	// disabled outside of the genesis block.
 	DisableKeyManagerRuntimeRegistration bool `json:"disable_km_runtime_registration,omitempty"`
 
+	// RuntimeNamespaceRules are the rules restricting which entities may
+	// register runtimes in which ranges of the runtime ID space.
+	RuntimeNamespaceRules []RuntimeNamespaceRule `json:"runtime_namespace_rules,omitempty"`
+
 	// GasCosts are the registry transaction gas costs.
 	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
 
 	// MaxNodeExpiration is the maximum number of epochs relative to the epoch
 	// at registration time that a single node registration is valid for.
 	MaxNodeExpiration uint64 `json:"max_node_expiration,omitempty"`
+
+	// ReadinessAttestationExpiration is the number of epochs relative to
+	// the epoch at attestation time that a runtime readiness attestation is
+	// valid for. Zero means that readiness attestations are disabled.
+	ReadinessAttestationExpiration uint64 `json:"readiness_attestation_expiration,omitempty"`
 }
 
 const (
@@ -1346,19 +1587,35 @@ const (
 	// GasOpUpdateKeyManager is the gas operation identifier for key manager
 	// policy updates costs.
 	GasOpUpdateKeyManager transaction.Op = "update_keymanager"
+	// GasOpAttestRuntimeReadiness is the gas operation identifier for runtime
+	// readiness attestations.
+	GasOpAttestRuntimeReadiness transaction.Op = "attest_runtime_readiness"
+	// GasOpProveFreshness is the gas operation identifier for node freshness
+	// proofs.
+	GasOpProveFreshness transaction.Op = "prove_freshness"
+	// GasOpTransferRuntimeOwnership is the gas operation identifier for
+	// runtime ownership transfer offers.
+	GasOpTransferRuntimeOwnership transaction.Op = "transfer_runtime_ownership"
+	// GasOpAcceptRuntimeOwnership is the gas operation identifier for
+	// runtime ownership transfer acceptances.
+	GasOpAcceptRuntimeOwnership transaction.Op = "accept_runtime_ownership"
 )
 
 // XXX: Define reasonable default gas costs.
 
 // DefaultGasCosts are the "default" gas costs for operations.
 var DefaultGasCosts = transaction.Costs{
-	GasOpRegisterEntity:          1000,
-	GasOpDeregisterEntity:        1000,
-	GasOpRegisterNode:            1000,
-	GasOpUnfreezeNode:            1000,
-	GasOpRegisterRuntime:         1000,
-	GasOpRuntimeEpochMaintenance: 1000,
-	GasOpUpdateKeyManager:        1000,
+	GasOpRegisterEntity:           1000,
+	GasOpDeregisterEntity:         1000,
+	GasOpRegisterNode:             1000,
+	GasOpUnfreezeNode:             1000,
+	GasOpRegisterRuntime:          1000,
+	GasOpRuntimeEpochMaintenance:  1000,
+	GasOpUpdateKeyManager:         1000,
+	GasOpAttestRuntimeReadiness:   1000,
+	GasOpProveFreshness:           1000,
+	GasOpTransferRuntimeOwnership: 1000,
+	GasOpAcceptRuntimeOwnership:   1000,
 }
 
 const (
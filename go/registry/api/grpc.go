@@ -16,25 +16,33 @@ var (
 	serviceName = cmnGrpc.NewServiceName("Registry")
 
 	// methodGetEntity is the GetEntity method.
-	methodGetEntity = serviceName.NewMethod("GetEntity", IDQuery{})
+	methodGetEntity = serviceName.NewMethod("GetEntity", IDQuery{}).WithJSONGateway(entity.Entity{})
 	// methodGetEntities is the GetEntities method.
-	methodGetEntities = serviceName.NewMethod("GetEntities", int64(0))
+	methodGetEntities = serviceName.NewMethod("GetEntities", int64(0)).WithJSONGateway([]*entity.Entity{})
+	// methodGetEntitiesPage is the GetEntitiesPage method.
+	methodGetEntitiesPage = serviceName.NewMethod("GetEntitiesPage", PageQuery{}).WithJSONGateway(EntitiesPage{})
 	// methodGetNode is the GetNode method.
-	methodGetNode = serviceName.NewMethod("GetNode", IDQuery{})
+	methodGetNode = serviceName.NewMethod("GetNode", IDQuery{}).WithJSONGateway(node.Node{})
 	// methodGetNodeStatus is the GetNodeStatus method.
-	methodGetNodeStatus = serviceName.NewMethod("GetNodeStatus", IDQuery{})
+	methodGetNodeStatus = serviceName.NewMethod("GetNodeStatus", IDQuery{}).WithJSONGateway(NodeStatus{})
+	// methodGetNodeByConsensusAddress is the GetNodeByConsensusAddress method.
+	methodGetNodeByConsensusAddress = serviceName.NewMethod("GetNodeByConsensusAddress", ConsensusAddressQuery{}).WithJSONGateway(node.Node{})
 	// methodGetNodes is the GetNodes method.
-	methodGetNodes = serviceName.NewMethod("GetNodes", int64(0))
+	methodGetNodes = serviceName.NewMethod("GetNodes", int64(0)).WithJSONGateway([]*node.Node{})
+	// methodGetNodesPage is the GetNodesPage method.
+	methodGetNodesPage = serviceName.NewMethod("GetNodesPage", PageQuery{}).WithJSONGateway(NodesPage{})
+	// methodGetNodesForEntity is the GetNodesForEntity method.
+	methodGetNodesForEntity = serviceName.NewMethod("GetNodesForEntity", EntityNodesQuery{}).WithJSONGateway(NodesPage{})
 	// methodGetRuntime is the GetRuntime method.
-	methodGetRuntime = serviceName.NewMethod("GetRuntime", NamespaceQuery{})
+	methodGetRuntime = serviceName.NewMethod("GetRuntime", NamespaceQuery{}).WithJSONGateway(Runtime{})
 	// methodGetRuntimes is the GetRuntimes method.
-	methodGetRuntimes = serviceName.NewMethod("GetRuntimes", int64(0))
+	methodGetRuntimes = serviceName.NewMethod("GetRuntimes", int64(0)).WithJSONGateway([]*Runtime{})
 	// methodGetNodeList is the GetNodeList method.
-	methodGetNodeList = serviceName.NewMethod("GetNodeList", int64(0))
+	methodGetNodeList = serviceName.NewMethod("GetNodeList", int64(0)).WithJSONGateway(NodeList{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodGetEvents is the GetEvents method.
-	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0)).WithJSONGateway([]Event{})
 
 	// methodWatchEntities is the WatchEntities method.
 	methodWatchEntities = serviceName.NewMethod("WatchEntities", nil)
@@ -58,6 +66,10 @@ var (
 				MethodName: methodGetEntities.ShortName(),
 				Handler:    handlerGetEntities,
 			},
+			{
+				MethodName: methodGetEntitiesPage.ShortName(),
+				Handler:    handlerGetEntitiesPage,
+			},
 			{
 				MethodName: methodGetNode.ShortName(),
 				Handler:    handlerGetNode,
@@ -66,10 +78,22 @@ var (
 				MethodName: methodGetNodeStatus.ShortName(),
 				Handler:    handlerGetNodeStatus,
 			},
+			{
+				MethodName: methodGetNodeByConsensusAddress.ShortName(),
+				Handler:    handlerGetNodeByConsensusAddress,
+			},
 			{
 				MethodName: methodGetNodes.ShortName(),
 				Handler:    handlerGetNodes,
 			},
+			{
+				MethodName: methodGetNodesPage.ShortName(),
+				Handler:    handlerGetNodesPage,
+			},
+			{
+				MethodName: methodGetNodesForEntity.ShortName(),
+				Handler:    handlerGetNodesForEntity,
+			},
 			{
 				MethodName: methodGetRuntime.ShortName(),
 				Handler:    handlerGetRuntime,
@@ -162,6 +186,29 @@ func handlerGetEntities( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetEntitiesPage( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query PageQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetEntitiesPage(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEntitiesPage.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetEntitiesPage(ctx, req.(*PageQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetNode( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -208,6 +255,29 @@ func handlerGetNodeStatus( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerGetNodeByConsensusAddress( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query ConsensusAddressQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodeByConsensusAddress(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodeByConsensusAddress.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetNodeByConsensusAddress(ctx, req.(*ConsensusAddressQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetNodes( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -231,6 +301,52 @@ func handlerGetNodes( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetNodesPage( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query PageQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodesPage(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodesPage.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetNodesPage(ctx, req.(*PageQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetNodesForEntity( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query EntityNodesQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodesForEntity(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodesForEntity.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetNodesForEntity(ctx, req.(*EntityNodesQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetRuntime( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -483,6 +599,14 @@ func (c *registryClient) GetEntities(ctx context.Context, height int64) ([]*enti
 	return rsp, nil
 }
 
+func (c *registryClient) GetEntitiesPage(ctx context.Context, query *PageQuery) (*EntitiesPage, error) {
+	var rsp EntitiesPage
+	if err := c.conn.Invoke(ctx, methodGetEntitiesPage.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) WatchEntities(ctx context.Context) (<-chan *EntityEvent, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -534,6 +658,14 @@ func (c *registryClient) GetNodeStatus(ctx context.Context, query *IDQuery) (*No
 	return &rsp, nil
 }
 
+func (c *registryClient) GetNodeByConsensusAddress(ctx context.Context, query *ConsensusAddressQuery) (*node.Node, error) {
+	var rsp node.Node
+	if err := c.conn.Invoke(ctx, methodGetNodeByConsensusAddress.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) GetNodes(ctx context.Context, height int64) ([]*node.Node, error) {
 	var rsp []*node.Node
 	if err := c.conn.Invoke(ctx, methodGetNodes.FullName(), height, &rsp); err != nil {
@@ -542,6 +674,22 @@ func (c *registryClient) GetNodes(ctx context.Context, height int64) ([]*node.No
 	return rsp, nil
 }
 
+func (c *registryClient) GetNodesPage(ctx context.Context, query *PageQuery) (*NodesPage, error) {
+	var rsp NodesPage
+	if err := c.conn.Invoke(ctx, methodGetNodesPage.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *registryClient) GetNodesForEntity(ctx context.Context, query *EntityNodesQuery) (*NodesPage, error) {
+	var rsp NodesPage
+	if err := c.conn.Invoke(ctx, methodGetNodesForEntity.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) WatchNodes(ctx context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+// RuntimeNamespaceRule is a rule restricting which entities may register
+// runtimes with IDs in a given range of the runtime ID space.
+//
+// Ranges are defined by a prefix of the identifier component of the runtime
+// ID, excluding the flags.
+type RuntimeNamespaceRule struct {
+	// Prefix is the runtime identifier prefix that the rule applies to.
+	Prefix []byte `json:"prefix"`
+
+	// Entities is the set of entities allowed to register runtimes with IDs
+	// in the range. If empty, the range is reserved and no runtimes may be
+	// registered in it.
+	Entities map[signature.PublicKey]bool `json:"entities,omitempty"`
+}
+
+// ValidateBasic performs basic runtime namespace rule validity checks.
+func (r *RuntimeNamespaceRule) ValidateBasic() error {
+	if l := len(r.Prefix); l == 0 || l > common.NamespaceIDSize {
+		return fmt.Errorf("invalid prefix length: %d", l)
+	}
+	for id := range r.Entities {
+		if !id.IsValid() {
+			return fmt.Errorf("invalid entity ID: %s", id)
+		}
+	}
+	return nil
+}
+
+// SanityCheckRuntimeNamespaceRules examines the runtime namespace rules.
+func SanityCheckRuntimeNamespaceRules(rules []RuntimeNamespaceRule) error {
+	for i := range rules {
+		if err := rules[i].ValidateBasic(); err != nil {
+			return fmt.Errorf("runtime namespace rule %d: %w", i, err)
+		}
+
+		// Rules must not overlap as otherwise it would be ambiguous which
+		// one applies.
+		for j := 0; j < i; j++ {
+			if bytes.HasPrefix(rules[i].Prefix, rules[j].Prefix) || bytes.HasPrefix(rules[j].Prefix, rules[i].Prefix) {
+				return fmt.Errorf("runtime namespace rule %d: prefix %s overlaps with rule %d",
+					i,
+					hex.EncodeToString(rules[i].Prefix),
+					j,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyRuntimeNamespace verifies that the runtime's controlling entity is
+// allowed to register a runtime with the runtime's ID.
+func VerifyRuntimeNamespace(params *ConsensusParameters, rt *Runtime) error {
+	for _, rule := range params.RuntimeNamespaceRules {
+		if !rt.ID.HasIDPrefix(rule.Prefix) {
+			continue
+		}
+		if !rule.Entities[rt.EntityID] {
+			return fmt.Errorf("%w: runtime ID in range %s not allowed for entity",
+				ErrForbidden,
+				hex.EncodeToString(rule.Prefix),
+			)
+		}
+		return nil
+	}
+	return nil
+}
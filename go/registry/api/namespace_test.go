@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+func TestRuntimeNamespaceRules(t *testing.T) {
+	require := require.New(t)
+
+	entityA := memorySigner.NewTestSigner("runtime namespace entity A").Public()
+	entityB := memorySigner.NewTestSigner("runtime namespace entity B").Public()
+
+	rules := []RuntimeNamespaceRule{
+		// Range reserved for entity A.
+		{Prefix: []byte{0xaa}, Entities: map[signature.PublicKey]bool{entityA: true}},
+		// Range reserved for nobody.
+		{Prefix: []byte{0xbb, 0xbb}},
+	}
+	require.NoError(SanityCheckRuntimeNamespaceRules(rules), "SanityCheckRuntimeNamespaceRules")
+
+	for _, tc := range []struct {
+		rules []RuntimeNamespaceRule
+		msg   string
+	}{
+		{[]RuntimeNamespaceRule{{}}, "empty prefix should be invalid"},
+		{[]RuntimeNamespaceRule{{Prefix: make([]byte, common.NamespaceIDSize+1)}}, "too long prefix should be invalid"},
+		{[]RuntimeNamespaceRule{{Prefix: []byte{0xaa}}, {Prefix: []byte{0xaa, 0x01}}}, "overlapping prefixes should be invalid"},
+		{[]RuntimeNamespaceRule{{Prefix: []byte{0xaa, 0x01}}, {Prefix: []byte{0xaa}}}, "overlapping prefixes should be invalid"},
+	} {
+		require.Error(SanityCheckRuntimeNamespaceRules(tc.rules), tc.msg)
+	}
+
+	newRuntime := func(prefix []byte, entityID signature.PublicKey) *Runtime {
+		var id [common.NamespaceIDSize]byte
+		copy(id[:], prefix)
+		ns, err := common.NewNamespace(id, common.NamespaceTest)
+		require.NoError(err, "NewNamespace")
+		return &Runtime{ID: ns, EntityID: entityID}
+	}
+	params := &ConsensusParameters{RuntimeNamespaceRules: rules}
+
+	require.NoError(VerifyRuntimeNamespace(params, newRuntime([]byte{0xaa, 0x01}, entityA)), "allowed entity should be able to register in range")
+	err := VerifyRuntimeNamespace(params, newRuntime([]byte{0xaa, 0x01}, entityB))
+	require.True(errors.Is(err, ErrForbidden), "other entities should not be able to register in range")
+	err = VerifyRuntimeNamespace(params, newRuntime([]byte{0xbb, 0xbb}, entityA))
+	require.True(errors.Is(err, ErrForbidden), "no entity should be able to register in reserved range")
+	require.NoError(VerifyRuntimeNamespace(params, newRuntime([]byte{0xbb, 0xaa}, entityB)), "any entity should be able to register outside ranges")
+	require.NoError(VerifyRuntimeNamespace(&ConsensusParameters{}, newRuntime([]byte{0xaa}, entityB)), "any entity should be able to register without rules")
+}
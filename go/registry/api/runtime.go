@@ -1,9 +1,12 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,11 +14,14 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/jsonschema"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/prettyprint"
 	"github.com/oasislabs/oasis-core/go/common/sgx"
 	"github.com/oasislabs/oasis-core/go/common/version"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	storage "github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
 )
 
 var (
@@ -26,7 +32,10 @@ var (
 	// ID is malformed.
 	ErrMalformedStoreID = errors.New("runtime: Malformed store ID")
 
+	_ prettyprint.PrettyPrinter = (*Runtime)(nil)
 	_ prettyprint.PrettyPrinter = (*SignedRuntime)(nil)
+	_ jsonschema.Customizer     = (*Runtime)(nil)
+	_ jsonschema.Customizer     = RuntimeKind(0)
 )
 
 // RuntimeKind represents the runtime functionality.
@@ -64,6 +73,12 @@ func (k RuntimeKind) String() string {
 	}
 }
 
+// CustomizeJSONSchema refines the JSON schema generated for the type.
+func (k RuntimeKind) CustomizeJSONSchema(s *jsonschema.Schema) {
+	s.Description = "Runtime kind (" + KindCompute.String() + ": 1, " + KindKeyManager.String() + ": 2)."
+	s.Enum = []interface{}{KindCompute, KindKeyManager}
+}
+
 // FromString deserializes a string into a RuntimeKind.
 func (k *RuntimeKind) FromString(str string) error {
 	switch strings.ToLower(str) {
@@ -207,6 +222,11 @@ type Runtime struct { // nolint: maligned
 	// Version is the runtime version information.
 	Version VersionInfo `json:"versions"`
 
+	// Deployments are the scheduled runtime deployments, ordered by their
+	// activation epoch. Each deployment supersedes Version and any earlier
+	// deployment once it becomes active.
+	Deployments []*RuntimeDeployment `json:"deployments,omitempty"`
+
 	// KeyManager is the key manager runtime ID for this runtime.
 	KeyManager *common.Namespace `json:"key_manager,omitempty"`
 
@@ -250,11 +270,63 @@ func (r *Runtime) ValidateBasic(strictVersion bool) error {
 	return nil
 }
 
+// ValidateDeployments checks that the runtime deployments are ordered by
+// strictly increasing activation epochs and that deployments never
+// downgrade the runtime version.
+func (r *Runtime) ValidateDeployments() error {
+	prev := r.Version.Version
+	for i, d := range r.Deployments {
+		if d == nil {
+			return fmt.Errorf("deployment %d: missing deployment", i)
+		}
+		if i > 0 && d.ValidFrom <= r.Deployments[i-1].ValidFrom {
+			return fmt.Errorf("deployment %d: activation epoch %d not after previous deployment", i, d.ValidFrom)
+		}
+		if d.Version.Version.ToU64() < prev.ToU64() {
+			return fmt.Errorf("deployment %d: version %s is older than %s", i, d.Version.Version, prev)
+		}
+		prev = d.Version.Version
+	}
+	return nil
+}
+
+// ActiveDeployment returns the runtime version information that is active
+// at the given epoch. If no deployment is active at the given epoch, the
+// runtime's Version is returned.
+func (r *Runtime) ActiveDeployment(epoch epochtime.EpochTime) *VersionInfo {
+	active := &r.Version
+	for _, d := range r.Deployments {
+		if d.ValidFrom > epoch {
+			break
+		}
+		active = &d.Version
+	}
+	return active
+}
+
 // String returns a string representation of itself.
 func (r Runtime) String() string {
 	return "<Runtime id=" + r.ID.String() + ">"
 }
 
+// PrettyPrint writes a pretty-printed representation of the type
+// to the given writer.
+func (r Runtime) PrettyPrint(prefix string, w io.Writer) {
+	data, err := json.MarshalIndent(r, prefix, "  ")
+	if err != nil {
+		fmt.Fprintf(w, "%s<error: %s>\n", prefix, err)
+		return
+	}
+	fmt.Fprintf(w, "%s%s\n", prefix, data)
+}
+
+// CustomizeJSONSchema refines the JSON schema generated for the type.
+func (r Runtime) CustomizeJSONSchema(s *jsonschema.Schema) {
+	s.Title = "Runtime descriptor"
+	s.Properties["v"].Minimum = json.Number(strconv.Itoa(minRuntimeDescriptorVersion))
+	s.Properties["v"].Maximum = json.Number(strconv.Itoa(maxRuntimeDescriptorVersion))
+}
+
 // IsCompute returns true iff the runtime is a generic compute runtime.
 func (r *Runtime) IsCompute() bool {
 	return r.Kind == KindCompute
@@ -305,6 +377,26 @@ type VersionInfo struct {
 	TEE []byte `json:"tee,omitempty"`
 }
 
+// RuntimeDeployment is a runtime deployment scheduled to become active at a
+// given epoch.
+type RuntimeDeployment struct {
+	// ValidFrom is the epoch at which the deployment becomes active.
+	ValidFrom epochtime.EpochTime `json:"valid_from"`
+
+	// Version is the runtime version information of the deployment.
+	Version VersionInfo `json:"version"`
+}
+
+// Equal compares vs another RuntimeDeployment for equality.
+func (d *RuntimeDeployment) Equal(other *RuntimeDeployment) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	return d.ValidFrom == other.ValidFrom &&
+		d.Version.Version == other.Version.Version &&
+		bytes.Equal(d.Version.TEE, other.Version.TEE)
+}
+
 // VersionInfoIntelSGX is the SGX TEE version information.
 type VersionInfoIntelSGX struct {
 	// Enclaves is the allowed MRENCLAVE/MRSIGNER pairs.
@@ -332,6 +424,12 @@ type RuntimeGenesis struct {
 
 	// Round is the runtime round in the genesis.
 	Round uint64 `json:"round"`
+
+	// Checkpoint is an optional reference to a storage checkpoint of the
+	// state identified by the StateRoot. Storage nodes that do not have the
+	// state can restore it from the checkpoint, with the chunks being
+	// verified against the digests pinned here.
+	Checkpoint *checkpoint.Metadata `json:"checkpoint,omitempty"`
 }
 
 // Equal compares vs another RuntimeGenesis for equality.
@@ -353,12 +451,33 @@ func (rtg *RuntimeGenesis) Equal(cmp *RuntimeGenesis) bool {
 			return false
 		}
 	}
+	if (rtg.Checkpoint == nil) != (cmp.Checkpoint == nil) {
+		return false
+	}
+	if rtg.Checkpoint != nil && rtg.Checkpoint.EncodedHash() != cmp.Checkpoint.EncodedHash() {
+		return false
+	}
 	return true
 }
 
 // SanityCheck does basic sanity checking of RuntimeGenesis.
 // isGenesis is true, if it is called during consensus chain init.
 func (rtg *RuntimeGenesis) SanityCheck(isGenesis bool) error {
+	if rtg.Checkpoint != nil {
+		if len(rtg.State) != 0 {
+			return fmt.Errorf("runtimegenesis: sanity check failed: State must be empty when Checkpoint is set")
+		}
+		if rtg.StateRoot.IsEmpty() {
+			return fmt.Errorf("runtimegenesis: sanity check failed: StateRoot must be non-empty when Checkpoint is set")
+		}
+		if !rtg.Checkpoint.Root.Hash.Equal(&rtg.StateRoot) || rtg.Checkpoint.Root.Version != rtg.Round {
+			return fmt.Errorf("runtimegenesis: sanity check failed: Checkpoint root does not match StateRoot and Round")
+		}
+		if len(rtg.Checkpoint.Chunks) == 0 {
+			return fmt.Errorf("runtimegenesis: sanity check failed: Checkpoint has no chunks")
+		}
+	}
+
 	if isGenesis {
 		return nil
 	}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/version"
+)
+
+func TestRuntimeDeployments(t *testing.T) {
+	require := require.New(t)
+
+	v1 := VersionInfo{Version: version.Version{Major: 1}, TEE: []byte("v1")}
+	v2 := VersionInfo{Version: version.Version{Major: 2}, TEE: []byte("v2")}
+	v3 := VersionInfo{Version: version.Version{Major: 3}, TEE: []byte("v3")}
+
+	rt := &Runtime{
+		Version: v1,
+		Deployments: []*RuntimeDeployment{
+			{ValidFrom: 10, Version: v2},
+			{ValidFrom: 20, Version: v3},
+		},
+	}
+	require.NoError(rt.ValidateDeployments(), "ValidateDeployments")
+
+	// Active deployment.
+	require.Equal(&v1, rt.ActiveDeployment(0), "active deployment before any deployment")
+	require.Equal(&v1, rt.ActiveDeployment(9), "active deployment before any deployment")
+	require.Equal(&v2, rt.ActiveDeployment(10), "active deployment at activation epoch")
+	require.Equal(&v2, rt.ActiveDeployment(19), "active deployment between deployments")
+	require.Equal(&v3, rt.ActiveDeployment(100), "active deployment after last deployment")
+	require.Equal(&v1, (&Runtime{Version: v1}).ActiveDeployment(100), "active deployment without deployments")
+
+	// Invalid deployments.
+	invalid := &Runtime{
+		Version: v1,
+		Deployments: []*RuntimeDeployment{
+			{ValidFrom: 20, Version: v2},
+			{ValidFrom: 10, Version: v3},
+		},
+	}
+	require.Error(invalid.ValidateDeployments(), "deployments out of order")
+	invalid.Deployments = []*RuntimeDeployment{
+		{ValidFrom: 10, Version: v2},
+		{ValidFrom: 10, Version: v3},
+	}
+	require.Error(invalid.ValidateDeployments(), "duplicate activation epoch")
+	invalid.Deployments = []*RuntimeDeployment{
+		{ValidFrom: 10, Version: v3},
+		{ValidFrom: 20, Version: v2},
+	}
+	require.Error(invalid.ValidateDeployments(), "version downgrade")
+	invalid.Version = v2
+	invalid.Deployments = []*RuntimeDeployment{{ValidFrom: 10, Version: v1}}
+	require.Error(invalid.ValidateDeployments(), "version downgrade from base version")
+	invalid.Deployments = []*RuntimeDeployment{nil}
+	require.Error(invalid.ValidateDeployments(), "missing deployment")
+
+	// Updates.
+	logger := logging.GetLogger("registry/api/tests")
+	update := &Runtime{
+		Version: v1,
+		Deployments: []*RuntimeDeployment{
+			{ValidFrom: 10, Version: v2},
+			{ValidFrom: 30, Version: v3},
+		},
+	}
+	require.NoError(VerifyRuntimeDeploymentsUpdate(logger, rt, update, 15), "rescheduling a future deployment")
+	require.NoError(VerifyRuntimeDeploymentsUpdate(logger, rt, rt, 25), "unchanged deployments")
+	update.Deployments = []*RuntimeDeployment{{ValidFrom: 20, Version: v3}}
+	require.NoError(VerifyRuntimeDeploymentsUpdate(logger, rt, update, 25), "removing superseded deployments")
+	update.Deployments = []*RuntimeDeployment{{ValidFrom: 15, Version: v3}}
+	require.Error(VerifyRuntimeDeploymentsUpdate(logger, rt, update, 15), "scheduling at the current epoch")
+	update.Deployments = []*RuntimeDeployment{{ValidFrom: 10, Version: v3}}
+	require.Error(VerifyRuntimeDeploymentsUpdate(logger, rt, update, 15), "changing an active deployment")
+}
+
+func TestRuntimeOwnershipTransfer(t *testing.T) {
+	require := require.New(t)
+
+	logger := logging.GetLogger("registry/api/tests")
+	owner := memorySigner.NewTestSigner("runtime ownership transfer owner").Public()
+	newOwner := memorySigner.NewTestSigner("runtime ownership transfer new owner").Public()
+
+	current := &Runtime{
+		ID:       common.NewTestNamespaceFromSeed([]byte("runtime ownership transfer"), 0),
+		EntityID: owner,
+		Kind:     KindCompute,
+		AdmissionPolicy: RuntimeAdmissionPolicy{
+			AnyNode: &AnyNodeRuntimeAdmissionPolicy{},
+		},
+	}
+
+	transferred := *current
+	transferred.EntityID = newOwner
+	require.NoError(VerifyRuntimeOwnershipTransfer(logger, current, &transferred, newOwner), "changing only the owner")
+	transferred.DescriptorVersion = LatestRuntimeDescriptorVersion
+	require.NoError(VerifyRuntimeOwnershipTransfer(logger, current, &transferred, newOwner), "bumping the descriptor version")
+
+	require.Error(VerifyRuntimeOwnershipTransfer(logger, current, current, newOwner), "keeping the previous owner")
+	require.Error(VerifyRuntimeOwnershipTransfer(logger, current, &transferred, owner), "owner other than the offered entity")
+
+	changed := transferred
+	changed.AdmissionPolicy = RuntimeAdmissionPolicy{
+		EntityWhitelist: &EntityWhitelistRuntimeAdmissionPolicy{
+			Entities: map[signature.PublicKey]bool{newOwner: true},
+		},
+	}
+	require.Error(VerifyRuntimeOwnershipTransfer(logger, current, &changed, newOwner), "changing the admission policy")
+}
@@ -33,6 +33,10 @@ func (g *Genesis) SanityCheck(
 		}
 	}
 
+	if err := SanityCheckRuntimeNamespaceRules(g.Parameters.RuntimeNamespaceRules); err != nil {
+		return fmt.Errorf("registry: sanity check failed: %w", err)
+	}
+
 	// Check entities.
 	seenEntities, err := SanityCheckEntities(logger, g.Entities)
 	if err != nil {
@@ -44,6 +48,12 @@ func (g *Genesis) SanityCheck(
 	if err != nil {
 		return err
 	}
+	if err = SanityCheckGovernanceSuspendedRuntimes(g.GovernanceSuspendedRuntimes, runtimesLookup); err != nil {
+		return err
+	}
+	if err = SanityCheckPendingRuntimeTransfers(g.PendingRuntimeTransfers, seenEntities, runtimesLookup); err != nil {
+		return err
+	}
 
 	// Check nodes.
 	nodeLookup, err := SanityCheckNodes(logger, &g.Parameters, g.Nodes, seenEntities, runtimesLookup, true, baseEpoch)
@@ -132,6 +142,60 @@ func SanityCheckRuntimes(
 	return lookup, nil
 }
 
+// SanityCheckGovernanceSuspendedRuntimes examines the list of runtimes
+// suspended via governance. Each such runtime must be a suspended compute
+// runtime.
+func SanityCheckGovernanceSuspendedRuntimes(ids []common.Namespace, runtimesLookup RuntimeLookup) error {
+	seen := make(map[common.Namespace]bool)
+	for _, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("registry: sanity check failed: duplicate governance suspended runtime %s", id)
+		}
+		seen[id] = true
+
+		rt, err := runtimesLookup.SuspendedRuntime(context.Background(), id)
+		if err != nil {
+			return fmt.Errorf("registry: sanity check failed: governance suspended runtime %s is not suspended: %w", id, err)
+		}
+		if rt.Kind != KindCompute {
+			return fmt.Errorf("registry: sanity check failed: governance suspended runtime %s is not a compute runtime", id)
+		}
+	}
+	return nil
+}
+
+// SanityCheckPendingRuntimeTransfers examines the list of pending runtime
+// ownership transfers. Each transfer must refer to a distinct registered
+// runtime and offer it to a registered entity other than its current owner.
+func SanityCheckPendingRuntimeTransfers(
+	transfers []*PendingRuntimeTransfer,
+	seenEntities map[signature.PublicKey]*entity.Entity,
+	runtimesLookup RuntimeLookup,
+) error {
+	seen := make(map[common.Namespace]bool)
+	for i, transfer := range transfers {
+		if transfer == nil {
+			return fmt.Errorf("registry: sanity check failed: pending runtime transfer index %d is nil", i)
+		}
+		if seen[transfer.ID] {
+			return fmt.Errorf("registry: sanity check failed: duplicate pending transfer for runtime %s", transfer.ID)
+		}
+		seen[transfer.ID] = true
+
+		rt, err := runtimesLookup.AnyRuntime(context.Background(), transfer.ID)
+		if err != nil {
+			return fmt.Errorf("registry: sanity check failed: pending transfer for unknown runtime %s: %w", transfer.ID, err)
+		}
+		if rt.EntityID.Equal(transfer.NewOwner) {
+			return fmt.Errorf("registry: sanity check failed: pending transfer of runtime %s to its current owner", transfer.ID)
+		}
+		if seenEntities[transfer.NewOwner] == nil {
+			return fmt.Errorf("registry: sanity check failed: pending transfer of runtime %s to unknown entity %s", transfer.ID, transfer.NewOwner)
+		}
+	}
+	return nil
+}
+
 // SanityCheckNodes examines the nodes table.
 // Pass lookups of entities and runtimes from SanityCheckEntities
 // and SanityCheckRuntimes for cross referencing purposes.
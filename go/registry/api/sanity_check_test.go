@@ -0,0 +1,90 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+)
+
+func TestSanityCheckGovernanceSuspendedRuntimes(t *testing.T) {
+	require := require.New(t)
+
+	active := &Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("governance suspension active"), 0),
+		Kind: KindCompute,
+	}
+	suspended := &Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("governance suspension suspended"), 0),
+		Kind: KindCompute,
+	}
+	km := &Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("governance suspension key manager"), 0),
+		Kind: KindKeyManager,
+	}
+	lookup, err := newSanityCheckRuntimeLookup([]*Runtime{active}, []*Runtime{suspended, km})
+	require.NoError(err, "newSanityCheckRuntimeLookup")
+
+	err = SanityCheckGovernanceSuspendedRuntimes(nil, lookup)
+	require.NoError(err, "no governance suspended runtimes")
+	err = SanityCheckGovernanceSuspendedRuntimes([]common.Namespace{suspended.ID}, lookup)
+	require.NoError(err, "suspended compute runtime")
+
+	err = SanityCheckGovernanceSuspendedRuntimes([]common.Namespace{suspended.ID, suspended.ID}, lookup)
+	require.Error(err, "duplicate runtime")
+	err = SanityCheckGovernanceSuspendedRuntimes([]common.Namespace{active.ID}, lookup)
+	require.Error(err, "active runtime")
+	err = SanityCheckGovernanceSuspendedRuntimes([]common.Namespace{km.ID}, lookup)
+	require.Error(err, "key manager runtime")
+}
+
+func TestSanityCheckPendingRuntimeTransfers(t *testing.T) {
+	require := require.New(t)
+
+	owner := memorySigner.NewTestSigner("pending transfer owner").Public()
+	newOwner := memorySigner.NewTestSigner("pending transfer new owner").Public()
+	unknown := memorySigner.NewTestSigner("pending transfer unknown entity").Public()
+	entities := map[signature.PublicKey]*entity.Entity{
+		owner:    {ID: owner},
+		newOwner: {ID: newOwner},
+	}
+
+	active := &Runtime{
+		ID:       common.NewTestNamespaceFromSeed([]byte("pending transfer active"), 0),
+		EntityID: owner,
+	}
+	suspended := &Runtime{
+		ID:       common.NewTestNamespaceFromSeed([]byte("pending transfer suspended"), 0),
+		EntityID: owner,
+	}
+	lookup, err := newSanityCheckRuntimeLookup([]*Runtime{active}, []*Runtime{suspended})
+	require.NoError(err, "newSanityCheckRuntimeLookup")
+
+	err = SanityCheckPendingRuntimeTransfers(nil, entities, lookup)
+	require.NoError(err, "no pending transfers")
+	err = SanityCheckPendingRuntimeTransfers([]*PendingRuntimeTransfer{
+		{ID: active.ID, NewOwner: newOwner},
+		{ID: suspended.ID, NewOwner: newOwner},
+	}, entities, lookup)
+	require.NoError(err, "transfers of active and suspended runtimes")
+
+	err = SanityCheckPendingRuntimeTransfers([]*PendingRuntimeTransfer{nil}, entities, lookup)
+	require.Error(err, "nil transfer")
+	err = SanityCheckPendingRuntimeTransfers([]*PendingRuntimeTransfer{
+		{ID: active.ID, NewOwner: newOwner},
+		{ID: active.ID, NewOwner: newOwner},
+	}, entities, lookup)
+	require.Error(err, "duplicate transfer")
+	err = SanityCheckPendingRuntimeTransfers([]*PendingRuntimeTransfer{
+		{ID: common.NewTestNamespaceFromSeed([]byte("pending transfer unknown"), 0), NewOwner: newOwner},
+	}, entities, lookup)
+	require.Error(err, "unknown runtime")
+	err = SanityCheckPendingRuntimeTransfers([]*PendingRuntimeTransfer{{ID: active.ID, NewOwner: owner}}, entities, lookup)
+	require.Error(err, "transfer to current owner")
+	err = SanityCheckPendingRuntimeTransfers([]*PendingRuntimeTransfer{{ID: active.ID, NewOwner: unknown}}, entities, lookup)
+	require.Error(err, "transfer to unknown entity")
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
@@ -22,6 +23,12 @@ type NodeStatus struct {
 	// After the specified epoch passes, this flag needs to be explicitly
 	// cleared (set to zero) in order for the node to become unfrozen.
 	FreezeEndTime epochtime.EpochTime `json:"freeze_end_time"`
+	// ReadyRuntimes are the runtimes for which the node has attested
+	// readiness, mapped to the epoch after which the attestation expires.
+	ReadyRuntimes map[common.Namespace]epochtime.EpochTime `json:"ready_runtimes,omitempty"`
+	// LastFreshnessProof is the most recent freshness proof submitted by
+	// the node.
+	LastFreshnessProof *NodeFreshness `json:"last_freshness_proof,omitempty"`
 }
 
 // IsFrozen returns true if the node is currently frozen (prevented
@@ -35,7 +42,62 @@ func (ns *NodeStatus) Unfreeze() {
 	ns.FreezeEndTime = 0
 }
 
+// IsRuntimeReady returns true if the node has a readiness attestation for
+// the given runtime which has not expired as of the given epoch.
+func (ns NodeStatus) IsRuntimeReady(runtimeID common.Namespace, epoch epochtime.EpochTime) bool {
+	expiration, ok := ns.ReadyRuntimes[runtimeID]
+	return ok && epoch <= expiration
+}
+
 // UnfreezeNode is a request to unfreeze a frozen node.
 type UnfreezeNode struct {
 	NodeID signature.PublicKey `json:"node_id"`
 }
+
+// RuntimeReadinessAttestation is a node's attestation that it is ready to
+// serve the given runtimes (e.g., the hosted runtime has been started or the
+// runtime state has been synced to the latest round).
+type RuntimeReadinessAttestation struct {
+	Runtimes []common.Namespace `json:"runtimes"`
+}
+
+// MaxFreshnessProofBlobSize is the maximum size of the blob included in a
+// freshness proof.
+const MaxFreshnessProofBlobSize = 32
+
+// FreshnessProof is a node's proof that its registration (node) key is live.
+//
+// The proof itself is the transaction signature, the blob is an arbitrary
+// value chosen by the node (e.g., a challenge issued by a monitoring system).
+type FreshnessProof struct {
+	Blob []byte `json:"blob,omitempty"`
+}
+
+// NodeFreshness is a freshness proof as recorded in the node status.
+type NodeFreshness struct {
+	// Height is the block height at which the proof was recorded.
+	Height int64 `json:"height"`
+	// Blob is the blob included in the proof.
+	Blob []byte `json:"blob,omitempty"`
+}
+
+// TransferRuntimeOwnership is a request by the owning entity of a runtime to
+// transfer its ownership to another entity.
+//
+// The transfer only takes effect once the new owner accepts it. Offering the
+// runtime to its current owner cancels any pending transfer.
+type TransferRuntimeOwnership struct {
+	// ID is the runtime identifier.
+	ID common.Namespace `json:"id"`
+	// NewOwner is the entity the runtime is offered to.
+	NewOwner signature.PublicKey `json:"new_owner"`
+}
+
+// PendingRuntimeTransfer is a runtime ownership transfer that has been
+// offered by the owning entity but not yet accepted by the new owner.
+type PendingRuntimeTransfer struct {
+	// ID is the runtime identifier.
+	ID common.Namespace `json:"id"`
+	// NewOwner is the entity the runtime is offered to.
+	NewOwner signature.PublicKey `json:"new_owner"`
+}
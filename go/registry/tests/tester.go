@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto"
 	"errors"
+	"fmt"
 	"net"
 	"testing"
 	"time"
@@ -20,6 +21,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+	tmcrypto "github.com/oasislabs/oasis-core/go/consensus/tendermint/crypto"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	epochtimeTests "github.com/oasislabs/oasis-core/go/epochtime/tests"
 	"github.com/oasislabs/oasis-core/go/registry/api"
@@ -123,6 +125,20 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		testEntity, _, _ := entity.TestEntity()
 		require.Len(registeredEntities, len(entities)+1, "entities after registration")
 
+		var pagedEntities []*entity.Entity
+		query := &api.PageQuery{Height: consensusAPI.HeightLatest, Limit: 1}
+		for {
+			var page *api.EntitiesPage
+			page, err = backend.GetEntitiesPage(context.Background(), query)
+			require.NoError(err, "GetEntitiesPage")
+			pagedEntities = append(pagedEntities, page.Entities...)
+			if page.NextCursor == nil {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		require.ElementsMatch(registeredEntities, pagedEntities, "paged entities after registration")
+
 		seen := make(map[signature.PublicKey]bool)
 		for _, ent := range registeredEntities {
 			if ent.ID.Equal(testEntity.ID) {
@@ -269,12 +285,75 @@ func testRegistryEntityNodes( // nolint: gocyclo
 	t.Run("NodeList", func(t *testing.T) {
 		require := require.New(t)
 
+		nodeListCh, nodeListSub, err := backend.WatchNodeList(context.Background())
+		require.NoError(err, "WatchNodeList")
+		defer nodeListSub.Close()
+
 		expectedNodeList := getExpectedNodeList()
 		epoch = epochtimeTests.MustAdvanceEpoch(t, timeSource, 1)
 
 		registeredNodes, nerr := backend.GetNodes(context.Background(), consensusAPI.HeightLatest)
 		require.NoError(nerr, "GetNodes")
 		require.EqualValues(expectedNodeList, registeredNodes, "node list")
+
+		nodeList, nerr := backend.GetNodeList(context.Background(), consensusAPI.HeightLatest)
+		require.NoError(nerr, "GetNodeList")
+		require.Equal(epoch, nodeList.Epoch, "node list epoch")
+		require.EqualValues(expectedNodeList, nodeList.Nodes, "node list")
+
+		// The node list for the current epoch is sent upon subscription, so
+		// skip any node lists for previous epochs.
+	NodeListWaitLoop:
+		for {
+			select {
+			case nl := <-nodeListCh:
+				if nl.Epoch < epoch {
+					continue
+				}
+				require.Equal(epoch, nl.Epoch, "watched node list epoch")
+				require.EqualValues(expectedNodeList, nl.Nodes, "watched node list")
+				break NodeListWaitLoop
+			case <-time.After(recvTimeout):
+				t.Fatalf("failed to receive node list")
+			}
+		}
+
+		var pagedNodes []*node.Node
+		query := &api.PageQuery{Height: consensusAPI.HeightLatest, Limit: 2}
+		for {
+			page, perr := backend.GetNodesPage(context.Background(), query)
+			require.NoError(perr, "GetNodesPage")
+			pagedNodes = append(pagedNodes, page.Nodes...)
+			if page.NextCursor == nil {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		api.SortNodeList(pagedNodes)
+		require.EqualValues(expectedNodeList, pagedNodes, "paged node list")
+
+		// Query the nodes of each entity separately.
+		pagedNodes = nil
+		for _, ent := range entities {
+			entityQuery := &api.EntityNodesQuery{
+				PageQuery: api.PageQuery{Height: consensusAPI.HeightLatest, Limit: 1},
+				EntityID:  ent.Entity.ID,
+			}
+			for {
+				page, perr := backend.GetNodesForEntity(context.Background(), entityQuery)
+				require.NoError(perr, "GetNodesForEntity")
+				for _, n := range page.Nodes {
+					require.Equal(ent.Entity.ID, n.EntityID, "node should belong to the queried entity")
+				}
+				pagedNodes = append(pagedNodes, page.Nodes...)
+				if page.NextCursor == nil {
+					break
+				}
+				entityQuery.Cursor = page.NextCursor
+			}
+		}
+		api.SortNodeList(pagedNodes)
+		require.EqualValues(expectedNodeList, pagedNodes, "paged node list by entity")
 	})
 
 	t.Run("NodeUnfreeze", func(t *testing.T) {
@@ -320,6 +399,51 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		require.Equal(err, api.ErrBadEntityForNode)
 	})
 
+	t.Run("NodeFreshness", func(t *testing.T) {
+		require := require.New(t)
+
+		node := nodes[0][0]
+
+		ctx, cancel := context.WithTimeout(context.Background(), recvTimeout)
+		defer cancel()
+
+		// Look up the node by its consensus address.
+		address := []byte(tmcrypto.PublicKeyToTendermint(&node.Node.Consensus.ID).Address())
+		expectedNode, err := backend.GetNode(ctx, &api.IDQuery{ID: node.Node.ID, Height: consensusAPI.HeightLatest})
+		require.NoError(err, "GetNode")
+		nod, err := backend.GetNodeByConsensusAddress(ctx, &api.ConsensusAddressQuery{Address: address, Height: consensusAPI.HeightLatest})
+		require.NoError(err, "GetNodeByConsensusAddress")
+		require.EqualValues(expectedNode, nod, "retrieved node by consensus address")
+
+		_, err = backend.GetNodeByConsensusAddress(ctx, &api.ConsensusAddressQuery{Address: []byte("invalid"), Height: consensusAPI.HeightLatest})
+		require.Error(err, "GetNodeByConsensusAddress (with invalid address)")
+		require.Equal(api.ErrNoSuchNode, err)
+
+		// Prove freshness of the node key.
+		blob := []byte("freshness challenge")
+		tx := api.NewProveFreshnessTx(0, nil, &api.FreshnessProof{Blob: blob})
+		err = consensusAPI.SignAndSubmitTx(ctx, consensus, node.Signer, tx)
+		require.NoError(err, "ProveFreshness")
+
+		nodeStatus, err := backend.GetNodeStatus(ctx, &api.IDQuery{ID: node.Node.ID, Height: consensusAPI.HeightLatest})
+		require.NoError(err, "GetNodeStatus")
+		require.NotNil(nodeStatus.LastFreshnessProof, "LastFreshnessProof should be set")
+		require.EqualValues(blob, nodeStatus.LastFreshnessProof.Blob, "freshness proof blob")
+		require.True(nodeStatus.LastFreshnessProof.Height > 0, "freshness proof height")
+
+		// Try to prove freshness with an oversized blob (should fail).
+		tx = api.NewProveFreshnessTx(0, nil, &api.FreshnessProof{Blob: make([]byte, api.MaxFreshnessProofBlobSize+1)})
+		err = consensusAPI.SignAndSubmitTx(ctx, consensus, node.Signer, tx)
+		require.Error(err, "ProveFreshness (with oversized blob)")
+
+		// Try to prove freshness using the entity signing key (should fail
+		// as freshness proofs must be signed by the node key).
+		tx = api.NewProveFreshnessTx(0, nil, &api.FreshnessProof{Blob: blob})
+		err = consensusAPI.SignAndSubmitTx(ctx, consensus, entities[0].Signer, tx)
+		require.Error(err, "ProveFreshness (with invalid signer)")
+		require.Equal(api.ErrNoSuchNode, err)
+	})
+
 	t.Run("NodeExpiration", func(t *testing.T) {
 		require := require.New(t)
 
@@ -1092,6 +1216,11 @@ func NewTestEntities(seed []byte, n int) ([]*TestEntity, error) {
 			DescriptorVersion:      entity.LatestEntityDescriptorVersion,
 			ID:                     ent.Signer.Public(),
 			AllowEntitySignedNodes: true,
+			Metadata: &entity.Metadata{
+				Name:  fmt.Sprintf("Test entity %d", i),
+				URL:   "https://example.com",
+				Email: "entity@example.com",
+			},
 		}
 
 		ent.SignedRegistration, err = entity.SignEntity(ent.Signer, api.RegisterEntitySignatureContext, ent.Entity)
@@ -1111,6 +1240,33 @@ func NewTestEntities(seed []byte, n int) ([]*TestEntity, error) {
 		}
 		ent.invalidBefore = append(ent.invalidBefore, invalid1)
 
+		// Add a registration with metadata in a descriptor version that
+		// does not support it.
+		invalid2 := &invalidEntityRegistration{
+			descr: "Registering with metadata in an old descriptor should fail",
+		}
+		invEnt2 := *ent.Entity
+		invEnt2.DescriptorVersion = 1
+		invalid2.signed, err = entity.SignEntity(ent.Signer, api.RegisterEntitySignatureContext, &invEnt2)
+		if err != nil {
+			return nil, err
+		}
+		ent.invalidBefore = append(ent.invalidBefore, invalid2)
+
+		// Add a registration with invalid metadata.
+		invalid3 := &invalidEntityRegistration{
+			descr: "Registering with invalid metadata should fail",
+		}
+		invEnt3 := *ent.Entity
+		invEnt3.Metadata = &entity.Metadata{
+			URL: "http://example.com",
+		}
+		invalid3.signed, err = entity.SignEntity(ent.Signer, api.RegisterEntitySignatureContext, &invEnt3)
+		if err != nil {
+			return nil, err
+		}
+		ent.invalidBefore = append(ent.invalidBefore, invalid3)
+
 		entities = append(entities, &ent)
 	}
 
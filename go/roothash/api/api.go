@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/errors"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasislabs/oasis-core/go/registry/api"
@@ -51,15 +54,49 @@ var (
 	// ErrRuntimeSuspended is the error returned when the passed runtime is suspended.
 	ErrRuntimeSuspended = errors.New(ModuleName, 5, "roothash: runtime is suspended")
 
+	// ErrRuntimeNotTracked is the error returned when the block history of
+	// the passed runtime is not tracked by the node.
+	ErrRuntimeNotTracked = errors.New(ModuleName, 6, "roothash: runtime history is not tracked")
+
+	// ErrForbidden is the error returned when an operation is forbidden by
+	// policy.
+	ErrForbidden = errors.New(ModuleName, 7, "roothash: forbidden by policy")
+
+	// ErrInvalidEvidence is the error returned when the submitted evidence
+	// is invalid.
+	ErrInvalidEvidence = errors.New(ModuleName, 8, "roothash: invalid evidence")
+
+	// ErrDuplicateEvidence is the error returned when the submitted evidence
+	// has already been processed.
+	ErrDuplicateEvidence = errors.New(ModuleName, 9, "roothash: duplicate evidence")
+
+	// ErrRoundPruned is the error returned when a block is not available
+	// because its round has already been pruned from the block history.
+	ErrRoundPruned = errors.New(ModuleName, 10, "roothash: round has been pruned")
+
 	// MethodExecutorCommit is the method name for executor commit submission.
 	MethodExecutorCommit = transaction.NewMethodName(ModuleName, "ExecutorCommit", ExecutorCommit{})
 	// MethodMergeCommit is the method name for merge commit submission.
 	MethodMergeCommit = transaction.NewMethodName(ModuleName, "MergeCommit", MergeCommit{})
+	// MethodSuspendRuntime is the method name for suspending a runtime.
+	MethodSuspendRuntime = transaction.NewMethodName(ModuleName, "SuspendRuntime", SuspendRuntime{})
+	// MethodResumeRuntime is the method name for resuming a runtime.
+	MethodResumeRuntime = transaction.NewMethodName(ModuleName, "ResumeRuntime", ResumeRuntime{})
+	// MethodDebugForceRoundTimeout is the method name for forcing a round
+	// timeout.
+	MethodDebugForceRoundTimeout = transaction.NewMethodName(ModuleName, "DebugForceRoundTimeout", DebugForceRoundTimeout{})
+	// MethodSubmitEvidence is the method name for submitting evidence of
+	// executor node equivocation.
+	MethodSubmitEvidence = transaction.NewMethodName(ModuleName, "SubmitEvidence", Evidence{})
 
 	// Methods is a list of all methods supported by the roothash backend.
 	Methods = []transaction.MethodName{
 		MethodExecutorCommit,
 		MethodMergeCommit,
+		MethodSuspendRuntime,
+		MethodResumeRuntime,
+		MethodDebugForceRoundTimeout,
+		MethodSubmitEvidence,
 	}
 )
 
@@ -94,6 +131,18 @@ type Backend interface {
 	// GetEvents returns the events at specified block height.
 	GetEvents(ctx context.Context, height int64) ([]Event, error)
 
+	// GetBlocksByTimeRange returns all blocks of the given runtime with
+	// a timestamp within the given time range (inclusive), sorted by round.
+	//
+	// The history of the runtime must be tracked via TrackRuntime.
+	GetBlocksByTimeRange(ctx context.Context, runtimeID common.Namespace, from, to time.Time) ([]*block.Block, error)
+
+	// GetRoundEvents returns the events of the given runtime emitted at the
+	// consensus height at which the block of the given round was finalized.
+	//
+	// The history of the runtime must be tracked via TrackRuntime.
+	GetRoundEvents(ctx context.Context, runtimeID common.Namespace, round uint64) ([]Event, error)
+
 	// Cleanup cleans up the roothash backend.
 	Cleanup()
 }
@@ -126,6 +175,94 @@ func NewMergeCommitTx(nonce uint64, fee *transaction.Fee, runtimeID common.Names
 	})
 }
 
+// SuspendRuntime is the argument set for the SuspendRuntime method.
+type SuspendRuntime struct {
+	ID common.Namespace `json:"id"`
+}
+
+// NewSuspendRuntimeTx creates a new suspend runtime transaction.
+func NewSuspendRuntimeTx(nonce uint64, fee *transaction.Fee, runtimeID common.Namespace) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodSuspendRuntime, &SuspendRuntime{
+		ID: runtimeID,
+	})
+}
+
+// ResumeRuntime is the argument set for the ResumeRuntime method.
+type ResumeRuntime struct {
+	ID common.Namespace `json:"id"`
+}
+
+// NewResumeRuntimeTx creates a new resume runtime transaction.
+func NewResumeRuntimeTx(nonce uint64, fee *transaction.Fee, runtimeID common.Namespace) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodResumeRuntime, &ResumeRuntime{
+		ID: runtimeID,
+	})
+}
+
+// DebugForceRoundTimeout is the argument set for the DebugForceRoundTimeout
+// method.
+type DebugForceRoundTimeout struct {
+	ID common.Namespace `json:"id"`
+}
+
+// NewDebugForceRoundTimeoutTx creates a new debug force round timeout
+// transaction.
+func NewDebugForceRoundTimeoutTx(nonce uint64, fee *transaction.Fee, runtimeID common.Namespace) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodDebugForceRoundTimeout, &DebugForceRoundTimeout{
+		ID: runtimeID,
+	})
+}
+
+// Evidence is the argument set for the SubmitEvidence method.
+//
+// It is evidence of an executor node equivocating, i.e. signing two
+// conflicting executor commitments for the same round of a runtime.
+type Evidence struct {
+	ID      common.Namespace              `json:"id"`
+	CommitA commitment.ExecutorCommitment `json:"commit_a"`
+	CommitB commitment.ExecutorCommitment `json:"commit_b"`
+}
+
+// Verify verifies that the evidence proves equivocation and returns the
+// identifier of the equivocating node and the hash of the block that both
+// commitments build upon, which identifies the round.
+//
+// Note that the runtime identifier is not covered by the commitment
+// signatures. The returned block hash commits to the runtime namespace and
+// so it, rather than the evidence's ID, must be used to identify the
+// offence.
+func (ev *Evidence) Verify() (signature.PublicKey, hash.Hash, error) {
+	a, err := ev.CommitA.Open()
+	if err != nil {
+		return signature.PublicKey{}, hash.Hash{}, fmt.Errorf("%w: %s", ErrInvalidEvidence, err)
+	}
+	b, err := ev.CommitB.Open()
+	if err != nil {
+		return signature.PublicKey{}, hash.Hash{}, fmt.Errorf("%w: %s", ErrInvalidEvidence, err)
+	}
+
+	nodeID := a.Signature.PublicKey
+	if !nodeID.Equal(b.Signature.PublicKey) {
+		return signature.PublicKey{}, hash.Hash{}, fmt.Errorf("%w: commitments signed by different nodes", ErrInvalidEvidence)
+	}
+	if !a.Body.CommitteeID.Equal(&b.Body.CommitteeID) {
+		return signature.PublicKey{}, hash.Hash{}, fmt.Errorf("%w: commitments for different committees", ErrInvalidEvidence)
+	}
+	if !a.Body.Header.PreviousHash.Equal(&b.Body.Header.PreviousHash) {
+		return signature.PublicKey{}, hash.Hash{}, fmt.Errorf("%w: commitments for different rounds", ErrInvalidEvidence)
+	}
+	if a.MostlyEqual(*b) {
+		return signature.PublicKey{}, hash.Hash{}, fmt.Errorf("%w: commitments do not conflict", ErrInvalidEvidence)
+	}
+
+	return nodeID, a.Body.Header.PreviousHash, nil
+}
+
+// NewSubmitEvidenceTx creates a new submit evidence transaction.
+func NewSubmitEvidenceTx(nonce uint64, fee *transaction.Fee, evidence *Evidence) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodSubmitEvidence, evidence)
+}
+
 // AnnotatedBlock is an annotated roothash block.
 type AnnotatedBlock struct {
 	// Height is the underlying roothash backend's block height that
@@ -149,10 +286,19 @@ type ExecutionDiscrepancyDetectedEvent struct {
 type MergeDiscrepancyDetectedEvent struct {
 }
 
+// RuntimeSuspensionEvent is the event emitted when a runtime is suspended
+// or resumed via a SuspendRuntime or ResumeRuntime transaction.
+type RuntimeSuspensionEvent struct {
+	// Suspended is true iff the runtime was suspended and false iff it was
+	// resumed.
+	Suspended bool `json:"suspended"`
+}
+
 // Event is a protocol event.
 type Event struct {
 	ExecutionDiscrepancyDetected *ExecutionDiscrepancyDetectedEvent `json:"execution_discrepancy,omitempty"`
 	MergeDiscrepancyDetected     *MergeDiscrepancyDetectedEvent     `json:"merge_discrepancy,omitempty"`
+	RuntimeSuspension            *RuntimeSuspensionEvent            `json:"runtime_suspension,omitempty"`
 }
 
 // MetricsMonitorable is the interface exposed by backends capable of
@@ -179,6 +325,23 @@ type ConsensusParameters struct {
 	// GasCosts are the roothash transaction gas costs.
 	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
 
+	// MaxRuntimeMessages is the maximum number of messages that a runtime
+	// can emit in a single round. Zero means that no messages are allowed.
+	MaxRuntimeMessages uint32 `json:"max_runtime_messages,omitempty"`
+
+	// MaxRuntimeMessagesSize is the maximum total size (in bytes) of the
+	// CBOR-encoded messages that a runtime can emit in a single round.
+	MaxRuntimeMessagesSize uint64 `json:"max_runtime_messages_size,omitempty"`
+
+	// RuntimeMessageGasPrice is the price per gas unit that the runtime's
+	// owning entity account is charged for executing runtime messages.
+	RuntimeMessageGasPrice quantity.Quantity `json:"runtime_message_gas_price"`
+
+	// RuntimeSuspensionAuthorities is the set of accounts allowed to suspend
+	// and resume runtimes via SuspendRuntime and ResumeRuntime transactions.
+	// If empty, such transactions are not allowed.
+	RuntimeSuspensionAuthorities []signature.PublicKey `json:"runtime_suspension_authorities,omitempty"`
+
 	// DebugDoNotSuspendRuntimes is true iff runtimes should not be suspended
 	// for lack of paying maintenance fees.
 	DebugDoNotSuspendRuntimes bool `json:"debug_do_not_suspend_runtimes,omitempty"`
@@ -186,6 +349,10 @@ type ConsensusParameters struct {
 	// DebugBypassStake is true iff the roothash should bypass all of the staking
 	// related checks and operations.
 	DebugBypassStake bool `json:"debug_bypass_stake,omitempty"`
+
+	// DebugAllowForceRoundTimeout is true iff the round timeout of a runtime
+	// can be forced to expire via a DebugForceRoundTimeout transaction.
+	DebugAllowForceRoundTimeout bool `json:"debug_allow_force_round_timeout,omitempty"`
 }
 
 const (
@@ -193,14 +360,56 @@ const (
 	GasOpComputeCommit transaction.Op = "compute_commit"
 	// GasOpMergeCommit is the gas operation identifier for merge commits.
 	GasOpMergeCommit transaction.Op = "merge_commit"
+	// GasOpRuntimeMessage is the gas operation identifier for executing
+	// a runtime message. It is paid by the runtime's owning entity account.
+	GasOpRuntimeMessage transaction.Op = "runtime_message"
+	// GasOpSuspendRuntime is the gas operation identifier for suspending and
+	// resuming runtimes.
+	GasOpSuspendRuntime transaction.Op = "suspend_runtime"
+	// GasOpSubmitEvidence is the gas operation identifier for submitting
+	// evidence.
+	GasOpSubmitEvidence transaction.Op = "submit_evidence"
 )
 
 // XXX: Define reasonable default gas costs.
 
 // DefaultGasCosts are the "default" gas costs for operations.
 var DefaultGasCosts = transaction.Costs{
-	GasOpComputeCommit: 1000,
-	GasOpMergeCommit:   1000,
+	GasOpComputeCommit:  1000,
+	GasOpMergeCommit:    1000,
+	GasOpRuntimeMessage: 1000,
+	GasOpSuspendRuntime: 1000,
+	GasOpSubmitEvidence: 1000,
+}
+
+// IsRuntimeSuspensionAuthority returns true iff the given account is allowed
+// to suspend and resume runtimes.
+func (p *ConsensusParameters) IsRuntimeSuspensionAuthority(id signature.PublicKey) bool {
+	for _, v := range p.RuntimeSuspensionAuthorities {
+		if v.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRuntimeMessages checks that the given runtime messages emitted in
+// a single round are within the configured limits.
+func (p *ConsensusParameters) ValidateRuntimeMessages(msgs []*block.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	if uint64(len(msgs)) > uint64(p.MaxRuntimeMessages) {
+		return fmt.Errorf("%w: number of messages %d exceeds limit %d",
+			commitment.ErrInvalidMessages, len(msgs), p.MaxRuntimeMessages,
+		)
+	}
+	if size := uint64(len(cbor.Marshal(msgs))); size > p.MaxRuntimeMessagesSize {
+		return fmt.Errorf("%w: size of messages %d exceeds limit %d",
+			commitment.ErrInvalidMessages, size, p.MaxRuntimeMessagesSize,
+		)
+	}
+	return nil
 }
 
 // SanityCheckBlocks examines the blocks table.
@@ -217,16 +426,24 @@ func SanityCheckBlocks(blocks map[common.Namespace]*block.Block) error {
 
 // SanityCheck does basic sanity checking on the genesis state.
 func (g *Genesis) SanityCheck() error {
-	unsafeFlags := g.Parameters.DebugDoNotSuspendRuntimes || g.Parameters.DebugBypassStake
+	unsafeFlags := g.Parameters.DebugDoNotSuspendRuntimes || g.Parameters.DebugBypassStake || g.Parameters.DebugAllowForceRoundTimeout
 	if unsafeFlags && !flags.DebugDontBlameOasis() {
 		return fmt.Errorf("roothash: sanity check failed: one or more unsafe debug flags set")
 	}
+	for _, id := range g.Parameters.RuntimeSuspensionAuthorities {
+		if !id.IsValid() {
+			return fmt.Errorf("roothash: sanity check failed: invalid runtime suspension authority: %s", id)
+		}
+	}
 
 	// Check blocks.
-	for _, rtg := range g.RuntimeStates {
+	for id, rtg := range g.RuntimeStates {
 		if err := rtg.SanityCheck(true); err != nil {
 			return err
 		}
+		if rtg.Checkpoint != nil && !rtg.Checkpoint.Root.Namespace.Equal(&id) {
+			return fmt.Errorf("roothash: sanity check failed: checkpoint namespace does not match runtime %s", id)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+	"github.com/oasislabs/oasis-core/go/roothash/api/commitment"
+)
+
+func TestValidateRuntimeMessages(t *testing.T) {
+	require := require.New(t)
+
+	var params ConsensusParameters
+	require.NoError(params.ValidateRuntimeMessages(nil), "no messages should always be allowed")
+
+	msgs := []*block.Message{{}, {}}
+	err := params.ValidateRuntimeMessages(msgs)
+	require.Error(err, "messages should not be allowed by default")
+	require.True(errors.Is(err, commitment.ErrInvalidMessages))
+
+	params.MaxRuntimeMessages = 1
+	params.MaxRuntimeMessagesSize = 1024
+	err = params.ValidateRuntimeMessages(msgs)
+	require.Error(err, "number of messages over the limit should be rejected")
+	require.True(errors.Is(err, commitment.ErrInvalidMessages))
+
+	params.MaxRuntimeMessages = 2
+	require.NoError(params.ValidateRuntimeMessages(msgs), "messages within the limits should be allowed")
+
+	params.MaxRuntimeMessagesSize = 1
+	err = params.ValidateRuntimeMessages(msgs)
+	require.Error(err, "size of messages over the limit should be rejected")
+	require.True(errors.Is(err, commitment.ErrInvalidMessages))
+}
+
+func TestEvidenceVerify(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	signer := memorySigner.NewTestSigner("roothash/api: evidence node")
+	otherSigner := memorySigner.NewTestSigner("roothash/api: evidence other node")
+
+	sign := func(signer signature.Signer, committeeID, previousHash, ioRoot hash.Hash) commitment.ExecutorCommitment {
+		commit, err := commitment.SignExecutorCommitment(signer, &commitment.ComputeBody{
+			CommitteeID: committeeID,
+			Header: commitment.ComputeResultsHeader{
+				PreviousHash: previousHash,
+				IORoot:       ioRoot,
+			},
+		})
+		require.NoError(err, "SignExecutorCommitment")
+		return *commit
+	}
+
+	var committee, otherCommittee, round, otherRound, rootA, rootB hash.Hash
+	committee.FromBytes([]byte("committee"))
+	otherCommittee.FromBytes([]byte("other committee"))
+	round.FromBytes([]byte("round"))
+	otherRound.FromBytes([]byte("other round"))
+	rootA.FromBytes([]byte("root a"))
+	rootB.FromBytes([]byte("root b"))
+
+	ev := &Evidence{
+		CommitA: sign(signer, committee, round, rootA),
+		CommitB: sign(signer, committee, round, rootB),
+	}
+	nodeID, roundHash, err := ev.Verify()
+	require.NoError(err, "conflicting commitments should be valid evidence")
+	require.Equal(signer.Public(), nodeID, "equivocating node should be returned")
+	require.Equal(round, roundHash, "round should be returned")
+
+	for _, tc := range []struct {
+		msg string
+		ev  *Evidence
+	}{
+		{"identical commitments", &Evidence{CommitA: sign(signer, committee, round, rootA), CommitB: sign(signer, committee, round, rootA)}},
+		{"different rounds", &Evidence{CommitA: sign(signer, committee, round, rootA), CommitB: sign(signer, committee, otherRound, rootB)}},
+		{"different committees", &Evidence{CommitA: sign(signer, committee, round, rootA), CommitB: sign(signer, otherCommittee, round, rootB)}},
+		{"different nodes", &Evidence{CommitA: sign(signer, committee, round, rootA), CommitB: sign(otherSigner, committee, round, rootB)}},
+		{"invalid signature", &Evidence{CommitA: sign(signer, committee, round, rootA)}},
+	} {
+		_, _, err = tc.ev.Verify()
+		require.True(errors.Is(err, ErrInvalidEvidence), tc.msg)
+	}
+}
@@ -2,17 +2,27 @@ package block
 
 import (
 	"bytes"
-	"errors"
+	"fmt"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/errors"
 	storage "github.com/oasislabs/oasis-core/go/storage/api"
 )
 
-// ErrInvalidVersion is the error returned when a version is invalid.
-var ErrInvalidVersion = errors.New("roothash: invalid version")
+// moduleName is the module name used for namespacing errors.
+const moduleName = "roothash/block"
+
+var (
+	// ErrInvalidVersion is the error returned when a version is invalid.
+	ErrInvalidVersion = errors.New(moduleName, 1, "roothash: invalid version")
+
+	// ErrInvalidStorageReceipt is the error returned when a storage receipt
+	// does not match the block header or commitment it is verified against.
+	ErrInvalidStorageReceipt = errors.New(moduleName, 2, "roothash: invalid storage receipt")
+)
 
 // HeaderType is the type of header.
 type HeaderType uint8
@@ -149,21 +159,21 @@ func (h *Header) VerifyStorageReceiptSignatures() error {
 // matches the header.
 func (h *Header) VerifyStorageReceipt(receipt *storage.ReceiptBody) error {
 	if !receipt.Namespace.Equal(&h.Namespace) {
-		return errors.New("roothash: receipt has unexpected namespace")
+		return fmt.Errorf("%w: unexpected namespace", ErrInvalidStorageReceipt)
 	}
 
 	if receipt.Round != h.Round {
-		return errors.New("roothash: receipt has unexpected round")
+		return fmt.Errorf("%w: unexpected round", ErrInvalidStorageReceipt)
 	}
 
 	roots := h.RootsForStorageReceipt()
 	if len(receipt.Roots) != len(roots) {
-		return errors.New("roothash: receipt has unexpected number of roots")
+		return fmt.Errorf("%w: unexpected number of roots", ErrInvalidStorageReceipt)
 	}
 
 	for idx, v := range roots {
 		if !bytes.Equal(v[:], receipt.Roots[idx][:]) {
-			return errors.New("roothash: receipt has unexpected roots")
+			return fmt.Errorf("%w: unexpected roots", ErrInvalidStorageReceipt)
 		}
 	}
 
@@ -3,7 +3,7 @@ package commitment
 
 import (
 	"bytes"
-	"errors"
+	"fmt"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
@@ -108,21 +108,21 @@ func (m *ComputeBody) VerifyStorageReceiptSignatures(ns common.Namespace, round
 // matches the header.
 func (m *ComputeBody) VerifyStorageReceipt(ns common.Namespace, round uint64, receipt *storage.ReceiptBody) error {
 	if !receipt.Namespace.Equal(&ns) {
-		return errors.New("roothash: receipt has unexpected namespace")
+		return fmt.Errorf("%w: unexpected namespace", block.ErrInvalidStorageReceipt)
 	}
 
 	if receipt.Round != round {
-		return errors.New("roothash: receipt has unexpected round")
+		return fmt.Errorf("%w: unexpected round", block.ErrInvalidStorageReceipt)
 	}
 
 	roots := m.RootsForStorageReceipt()
 	if len(receipt.Roots) != len(roots) {
-		return errors.New("roothash: receipt has unexpected number of roots")
+		return fmt.Errorf("%w: unexpected number of roots", block.ErrInvalidStorageReceipt)
 	}
 
 	for idx, v := range roots {
 		if !bytes.Equal(v[:], receipt.Roots[idx][:]) {
-			return errors.New("roothash: receipt has unexpected roots")
+			return fmt.Errorf("%w: unexpected roots", block.ErrInvalidStorageReceipt)
 		}
 	}
 
@@ -171,7 +171,7 @@ func (c OpenExecutorCommitment) ToDDResult() interface{} {
 func (c *ExecutorCommitment) Open() (*OpenExecutorCommitment, error) {
 	var body ComputeBody
 	if err := c.Signed.Open(ExecutorSignatureContext, &body); err != nil {
-		return nil, errors.New("roothash/commitment: commitment has invalid signature")
+		return nil, ErrInvalidSignature
 	}
 
 	return &OpenExecutorCommitment{
@@ -2,8 +2,6 @@
 package commitment
 
 import (
-	"errors"
-
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/roothash/api/block"
@@ -57,7 +55,7 @@ func (c OpenMergeCommitment) ToDDResult() interface{} {
 func (c *MergeCommitment) Open() (*OpenMergeCommitment, error) {
 	var body MergeBody
 	if err := c.Signed.Open(MergeSignatureContext, &body); err != nil {
-		return nil, errors.New("roothash/commitment: merge commitment has invalid signature")
+		return nil, ErrInvalidSignature
 	}
 
 	return &OpenMergeCommitment{
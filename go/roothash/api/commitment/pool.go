@@ -33,6 +33,7 @@ var (
 	ErrInvalidCommitteeID     = errors.New(moduleName, 12, "roothash/commitment: invalid committee ID")
 	ErrTxnSchedSigInvalid     = errors.New(moduleName, 13, "roothash/commitment: txn scheduler signature invalid")
 	ErrInvalidMessages        = errors.New(moduleName, 14, "roothash/commitment: invalid messages")
+	ErrInvalidSignature       = errors.New(moduleName, 15, "roothash/commitment: commitment has invalid signature")
 )
 
 var logger *logging.Logger = logging.GetLogger("roothash/commitment/pool")
@@ -172,12 +173,6 @@ func (p *Pool) addOpenExecutorCommitment(
 		return ErrNoRuntime
 	}
 
-	// Make sure the commitment does not contain any messages as these are currently
-	// not supported and should be rejected.
-	if len(header.Messages) > 0 {
-		return ErrInvalidMessages
-	}
-
 	// Verify RAK-attestation.
 	if p.Runtime.TEEHardware != node.TEEHardwareInvalid {
 		n, err := nl.Node(ctx, id)
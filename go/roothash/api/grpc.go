@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+)
+
+var (
+	// serviceName is the gRPC service name.
+	serviceName = cmnGrpc.NewServiceName("RootHash")
+
+	// methodGetGenesisBlock is the GetGenesisBlock method.
+	methodGetGenesisBlock = serviceName.NewMethod("GetGenesisBlock", RuntimeQuery{}).WithJSONGateway(block.Block{})
+	// methodGetLatestBlock is the GetLatestBlock method.
+	methodGetLatestBlock = serviceName.NewMethod("GetLatestBlock", RuntimeQuery{}).WithJSONGateway(block.Block{})
+	// methodGetEvents is the GetEvents method.
+	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0)).WithJSONGateway([]Event{})
+	// methodGetBlocksByTimeRange is the GetBlocksByTimeRange method.
+	methodGetBlocksByTimeRange = serviceName.NewMethod("GetBlocksByTimeRange", TimeRangeQuery{}).WithJSONGateway([]*block.Block{})
+	// methodGetRoundEvents is the GetRoundEvents method.
+	methodGetRoundEvents = serviceName.NewMethod("GetRoundEvents", RoundQuery{}).WithJSONGateway([]Event{})
+
+	// serviceDesc is the gRPC service descriptor.
+	serviceDesc = grpc.ServiceDesc{
+		ServiceName: string(serviceName),
+		HandlerType: (*QueryBackend)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: methodGetGenesisBlock.ShortName(),
+				Handler:    handlerGetGenesisBlock,
+			},
+			{
+				MethodName: methodGetLatestBlock.ShortName(),
+				Handler:    handlerGetLatestBlock,
+			},
+			{
+				MethodName: methodGetEvents.ShortName(),
+				Handler:    handlerGetEvents,
+			},
+			{
+				MethodName: methodGetBlocksByTimeRange.ShortName(),
+				Handler:    handlerGetBlocksByTimeRange,
+			},
+			{
+				MethodName: methodGetRoundEvents.ShortName(),
+				Handler:    handlerGetRoundEvents,
+			},
+		},
+		Streams: []grpc.StreamDesc{},
+	}
+)
+
+// QueryBackend is the subset of the roothash backend that is available
+// remotely. The watch methods are not exposed as they are not suitable for
+// remote use, and neither is the expensive state export.
+type QueryBackend interface {
+	// GetGenesisBlock returns the genesis block.
+	GetGenesisBlock(ctx context.Context, runtimeID common.Namespace, height int64) (*block.Block, error)
+
+	// GetLatestBlock returns the latest block.
+	GetLatestBlock(ctx context.Context, runtimeID common.Namespace, height int64) (*block.Block, error)
+
+	// GetEvents returns the events at specified block height.
+	GetEvents(ctx context.Context, height int64) ([]Event, error)
+
+	// GetBlocksByTimeRange returns all blocks of the given runtime with
+	// a timestamp within the given time range (inclusive), sorted by round.
+	GetBlocksByTimeRange(ctx context.Context, runtimeID common.Namespace, from, to time.Time) ([]*block.Block, error)
+
+	// GetRoundEvents returns the events of the given runtime emitted at the
+	// consensus height at which the block of the given round was finalized.
+	GetRoundEvents(ctx context.Context, runtimeID common.Namespace, round uint64) ([]Event, error)
+}
+
+// RuntimeQuery is a runtime-specific query.
+type RuntimeQuery struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Height    int64            `json:"height"`
+}
+
+// TimeRangeQuery is a runtime-specific block time range query.
+type TimeRangeQuery struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+}
+
+// RoundQuery is a runtime-specific round query.
+type RoundQuery struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+}
+
+func handlerGetGenesisBlock( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query RuntimeQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryBackend).GetGenesisBlock(ctx, query.RuntimeID, query.Height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetGenesisBlock.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		q := req.(*RuntimeQuery)
+		return srv.(QueryBackend).GetGenesisBlock(ctx, q.RuntimeID, q.Height)
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetLatestBlock( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query RuntimeQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryBackend).GetLatestBlock(ctx, query.RuntimeID, query.Height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetLatestBlock.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		q := req.(*RuntimeQuery)
+		return srv.(QueryBackend).GetLatestBlock(ctx, q.RuntimeID, q.Height)
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetEvents( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryBackend).GetEvents(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEvents.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryBackend).GetEvents(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetBlocksByTimeRange( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query TimeRangeQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryBackend).GetBlocksByTimeRange(ctx, query.RuntimeID, query.From, query.To)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetBlocksByTimeRange.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		q := req.(*TimeRangeQuery)
+		return srv.(QueryBackend).GetBlocksByTimeRange(ctx, q.RuntimeID, q.From, q.To)
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetRoundEvents( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query RoundQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryBackend).GetRoundEvents(ctx, query.RuntimeID, query.Round)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRoundEvents.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		q := req.(*RoundQuery)
+		return srv.(QueryBackend).GetRoundEvents(ctx, q.RuntimeID, q.Round)
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+// RegisterService registers a new roothash query service with the given gRPC server.
+func RegisterService(server *grpc.Server, service QueryBackend) {
+	server.RegisterService(&serviceDesc, service)
+}
+
+type roothashClient struct {
+	conn *grpc.ClientConn
+}
+
+func (c *roothashClient) GetGenesisBlock(ctx context.Context, runtimeID common.Namespace, height int64) (*block.Block, error) {
+	var rsp block.Block
+	if err := c.conn.Invoke(ctx, methodGetGenesisBlock.FullName(), &RuntimeQuery{RuntimeID: runtimeID, Height: height}, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *roothashClient) GetLatestBlock(ctx context.Context, runtimeID common.Namespace, height int64) (*block.Block, error) {
+	var rsp block.Block
+	if err := c.conn.Invoke(ctx, methodGetLatestBlock.FullName(), &RuntimeQuery{RuntimeID: runtimeID, Height: height}, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *roothashClient) GetEvents(ctx context.Context, height int64) ([]Event, error) {
+	var rsp []Event
+	if err := c.conn.Invoke(ctx, methodGetEvents.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *roothashClient) GetBlocksByTimeRange(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	from time.Time,
+	to time.Time,
+) ([]*block.Block, error) {
+	var rsp []*block.Block
+	if err := c.conn.Invoke(ctx, methodGetBlocksByTimeRange.FullName(), &TimeRangeQuery{RuntimeID: runtimeID, From: from, To: to}, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *roothashClient) GetRoundEvents(ctx context.Context, runtimeID common.Namespace, round uint64) ([]Event, error) {
+	var rsp []Event
+	if err := c.conn.Invoke(ctx, methodGetRoundEvents.FullName(), &RoundQuery{RuntimeID: runtimeID, Round: round}, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// NewRootHashClient creates a new gRPC roothash query client.
+func NewRootHashClient(c *grpc.ClientConn) QueryBackend {
+	return &roothashClient{c}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/roothash/api/block"
@@ -33,6 +34,13 @@ type BlockHistory interface {
 	// GetBlock returns the block at a specific round.
 	GetBlock(ctx context.Context, round uint64) (*block.Block, error)
 
+	// GetAnnotatedBlock returns the annotated block at a specific round.
+	GetAnnotatedBlock(ctx context.Context, round uint64) (*AnnotatedBlock, error)
+
 	// GetLatestBlock returns the block at latest round.
 	GetLatestBlock(ctx context.Context) (*block.Block, error)
+
+	// GetBlocksByTimeRange returns all blocks with a timestamp within the given
+	// time range (inclusive), sorted by round.
+	GetBlocksByTimeRange(ctx context.Context, from, to time.Time) ([]*block.Block, error)
 }
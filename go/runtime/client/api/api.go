@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"math"
 
@@ -11,6 +12,7 @@ import (
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
 	"github.com/oasislabs/oasis-core/go/roothash/api/block"
 	enclaverpc "github.com/oasislabs/oasis-core/go/runtime/enclaverpc/api"
+	"github.com/oasislabs/oasis-core/go/runtime/transaction"
 )
 
 const (
@@ -54,6 +56,10 @@ type RuntimeClient interface {
 	// GetTxs fetches all runtime transactions in a given block.
 	GetTxs(ctx context.Context, request *GetTxsRequest) ([][]byte, error)
 
+	// GetTransactionsWithResults fetches all runtime transactions in a given
+	// round together with their outputs and emitted tags.
+	GetTransactionsWithResults(ctx context.Context, request *GetTransactionsRequest) ([]*TransactionWithResults, error)
+
 	// QueryTx queries the indexer for a specific runtime transaction.
 	QueryTx(ctx context.Context, request *QueryTxRequest) (*TxResult, error)
 
@@ -63,6 +69,14 @@ type RuntimeClient interface {
 	// WatchBlocks subscribes to blocks for a specific runtimes.
 	WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error)
 
+	// WatchEvents subscribes to events (tags) emitted by transactions of
+	// a specific runtime that match the given filter.
+	//
+	// Events of a round are only emitted after the round has been indexed
+	// (if tag indexing is enabled), so the transactions that emitted them
+	// can be queried.
+	WatchEvents(ctx context.Context, request *WatchEventsRequest) (<-chan *Event, pubsub.ClosableSubscription, error)
+
 	// WaitBlockIndexed waits for a runtime block to be indexed by the indexer.
 	WaitBlockIndexed(ctx context.Context, request *WaitBlockIndexedRequest) error
 
@@ -117,6 +131,22 @@ type GetTxsRequest struct {
 	IORoot    hash.Hash        `json:"io_root"`
 }
 
+// GetTransactionsRequest is a GetTransactionsWithResults request.
+type GetTransactionsRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+}
+
+// TransactionWithResults is a runtime transaction together with its results.
+type TransactionWithResults struct {
+	// Tx is the transaction input.
+	Tx []byte `json:"tx"`
+	// Result is the transaction output.
+	Result []byte `json:"result"`
+	// Tags are the tags emitted by the transaction.
+	Tags transaction.Tags `json:"tags"`
+}
+
 // QueryTxRequest is a QueryTx request.
 type QueryTxRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
@@ -166,3 +196,42 @@ type WaitBlockIndexedRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 	Round     uint64           `json:"round"`
 }
+
+// EventFilter is a filter for runtime events (tags).
+type EventFilter struct {
+	// Key is the tag key that events must have. If not set, events with
+	// any key match.
+	Key []byte `json:"key,omitempty"`
+	// KeyPrefix is the prefix that the tag key of events must have. If not
+	// set, events with any key match.
+	KeyPrefix []byte `json:"key_prefix,omitempty"`
+}
+
+// Matches returns true iff the given tag matches the filter.
+func (f *EventFilter) Matches(tag *transaction.Tag) bool {
+	if f.Key != nil && !bytes.Equal(tag.Key, f.Key) {
+		return false
+	}
+	return bytes.HasPrefix(tag.Key, f.KeyPrefix)
+}
+
+// WatchEventsRequest is a WatchEvents request.
+type WatchEventsRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Filter    EventFilter      `json:"filter"`
+}
+
+// Event is an event (tag) emitted by a runtime transaction.
+type Event struct {
+	// Round is the round of the block that contains the transaction.
+	Round uint64 `json:"round"`
+	// BlockHash is the hash of the block that contains the transaction.
+	BlockHash hash.Hash `json:"block_hash"`
+	// TxHash is the hash of the transaction that emitted the event.
+	TxHash hash.Hash `json:"tx_hash"`
+
+	// Key is the tag key.
+	Key []byte `json:"key"`
+	// Value is the tag value.
+	Value []byte `json:"value"`
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -214,6 +215,94 @@ func (c *runtimeClient) WatchBlocks(ctx context.Context, runtimeID common.Namesp
 	return c.common.consensus.RootHash().WatchBlocks(runtimeID)
 }
 
+// Implements api.RuntimeClient.
+func (c *runtimeClient) WatchEvents(ctx context.Context, request *api.WatchEventsRequest) (<-chan *api.Event, pubsub.ClosableSubscription, error) {
+	tagIndexer, err := c.tagIndexer(request.RuntimeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blkCh, blkSub, err := c.WatchBlocks(ctx, request.RuntimeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	ch := make(chan *api.Event)
+	go func() {
+		defer close(ch)
+		defer blkSub.Close()
+
+		for {
+			var annBlk *roothash.AnnotatedBlock
+			select {
+			case <-ctx.Done():
+				return
+			case annBlk = <-blkCh:
+				if annBlk == nil {
+					return
+				}
+			}
+
+			blk := annBlk.Block
+			if blk.Header.HeaderType != block.Normal || blk.Header.IORoot.IsEmpty() {
+				continue
+			}
+
+			// Wait for the round to be indexed so that the transactions that
+			// emitted the events can be queried.
+			err := tagIndexer.WaitBlockIndexed(ctx, blk.Header.Round)
+			switch {
+			case err == nil, errors.Is(err, tagindexer.ErrTagIndexerDisabled):
+			case ctx.Err() != nil:
+				return
+			default:
+				c.logger.Error("failed to wait for round to be indexed",
+					"err", err,
+					"runtime_id", request.RuntimeID,
+					"round", blk.Header.Round,
+				)
+				continue
+			}
+
+			tree := c.getTxnTree(blk)
+			tags, err := tree.GetTags(ctx)
+			tree.Close()
+			if err != nil {
+				c.logger.Error("failed to fetch tags",
+					"err", err,
+					"runtime_id", request.RuntimeID,
+					"round", blk.Header.Round,
+				)
+				continue
+			}
+
+			blockHash := blk.Header.EncodedHash()
+			for i := range tags {
+				tag := &tags[i]
+				if !request.Filter.Matches(tag) {
+					continue
+				}
+
+				ev := &api.Event{
+					Round:     blk.Header.Round,
+					BlockHash: blockHash,
+					TxHash:    tag.TxHash,
+					Key:       tag.Key,
+					Value:     tag.Value,
+				}
+				select {
+				case ch <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 // Implements api.RuntimeClient.
 func (c *runtimeClient) GetGenesisBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
 	return c.common.consensus.RootHash().GetGenesisBlock(ctx, runtimeID, consensus.HeightLatest)
@@ -339,6 +428,51 @@ func (c *runtimeClient) GetTxs(ctx context.Context, request *api.GetTxsRequest)
 	return inputs, nil
 }
 
+// Implements api.RuntimeClient.
+func (c *runtimeClient) GetTransactionsWithResults(ctx context.Context, request *api.GetTransactionsRequest) ([]*api.TransactionWithResults, error) {
+	blk, err := c.GetBlock(ctx, &api.GetBlockRequest{RuntimeID: request.RuntimeID, Round: request.Round})
+	if err != nil {
+		return nil, err
+	}
+	if blk.Header.IORoot.IsEmpty() {
+		return []*api.TransactionWithResults{}, nil
+	}
+
+	// The storage client verifies all fetched nodes against the IO root.
+	tree := c.getTxnTree(blk)
+	defer tree.Close()
+
+	txs, err := tree.GetTransactions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+	tags, err := tree.GetTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
+	txTags := make(map[hash.Hash]transaction.Tags)
+	for _, tag := range tags {
+		txTags[tag.TxHash] = append(txTags[tag.TxHash], tag)
+	}
+
+	// Return transactions in the order they were executed in.
+	sort.SliceStable(txs, func(i, j int) bool {
+		return txs[i].BatchOrder < txs[j].BatchOrder
+	})
+
+	results := []*api.TransactionWithResults{}
+	for _, tx := range txs {
+		results = append(results, &api.TransactionWithResults{
+			Tx:     tx.Input,
+			Result: tx.Output,
+			Tags:   txTags[tx.Hash()],
+		})
+	}
+
+	return results, nil
+}
+
 // Implements api.RuntimeClient.
 func (c *runtimeClient) GetBlockByHash(ctx context.Context, request *api.GetBlockByHashRequest) (*block.Block, error) {
 	tagIndexer, err := c.tagIndexer(request.RuntimeID)
@@ -35,6 +35,12 @@ func ClientImplementationTests(
 		defer cancelFunc()
 		testQuery(ctx, t, runtimeID, client)
 	})
+
+	t.Run("WatchEvents", func(t *testing.T) {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+		defer cancelFunc()
+		testWatchEvents(ctx, t, runtimeID, client)
+	})
 }
 
 func testSubmitTransaction(
@@ -153,6 +159,14 @@ func testQuery(
 	// Check for values from TestNode/Client/SubmitTx
 	require.EqualValues(t, []byte("octopus"), txns[0])
 
+	// Transactions with results (check the mock worker for content).
+	txnsWithResults, err := c.GetTransactionsWithResults(ctx, &api.GetTransactionsRequest{RuntimeID: runtimeID, Round: blk.Header.Round})
+	require.NoError(t, err, "GetTransactionsWithResults")
+	require.Len(t, txnsWithResults, 1)
+	require.EqualValues(t, []byte("octopus"), txnsWithResults[0].Tx)
+	require.EqualValues(t, []byte("octopus"), txnsWithResults[0].Result)
+	require.NotEmpty(t, txnsWithResults[0].Tags, "transaction tags")
+
 	// Test advanced transaction queries.
 	query := api.Query{
 		RoundMin: 0,
@@ -180,3 +194,38 @@ func testQuery(
 	require.EqualValues(t, genBlk, genBlk2, "GetGenesisBlock should match previous GetGenesisBlock")
 
 }
+
+func testWatchEvents(
+	ctx context.Context,
+	t *testing.T,
+	runtimeID common.Namespace,
+	c api.RuntimeClient,
+) {
+	ch, sub, err := c.WatchEvents(ctx, &api.WatchEventsRequest{
+		RuntimeID: runtimeID,
+		Filter: api.EventFilter{
+			KeyPrefix: []byte("txn_"),
+		},
+	})
+	require.NoError(t, err, "WatchEvents")
+	defer sub.Close()
+
+	// Submit a test transaction, the mock worker emits a txn_foo tag.
+	testInput := []byte("octopus")
+	_, err = c.SubmitTx(ctx, &api.SubmitTxRequest{Data: testInput, RuntimeID: runtimeID})
+	require.NoError(t, err, "SubmitTx")
+
+	select {
+	case ev := <-ch:
+		require.EqualValues(t, []byte("txn_foo"), ev.Key)
+		require.EqualValues(t, []byte("txn_bar"), ev.Value)
+
+		// The transaction that emitted the event should be indexed.
+		tx, err := c.GetTx(ctx, &api.GetTxRequest{RuntimeID: runtimeID, Round: ev.Round, Index: 0})
+		require.NoError(t, err, "GetTx")
+		require.EqualValues(t, ev.BlockHash, tx.Block.Header.EncodedHash())
+		require.EqualValues(t, testInput, tx.Input)
+	case <-ctx.Done():
+		t.Fatalf("failed to receive event")
+	}
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+)
+
+const (
+	// ArchiveFileExt is the extension of runtime history archive files.
+	ArchiveFileExt = ".jsonl"
+
+	archiveTmpPrefix = ".tmp-rounds-"
+)
+
+var _ PruneHandler = (*archiveHandler)(nil)
+
+// ArchiveFilename returns the name of the archive file containing the given
+// range of rounds (inclusive).
+//
+// Names sort by round when compared lexicographically.
+func ArchiveFilename(firstRound, lastRound uint64) string {
+	return fmt.Sprintf("rounds-%020d-%020d%s", firstRound, lastRound, ArchiveFileExt)
+}
+
+// archiveHandler is a prune handler that writes blocks that are about to be
+// pruned into flat archive files.
+type archiveHandler struct {
+	logger *logging.Logger
+
+	dir     string
+	history History
+}
+
+func (h *archiveHandler) Prune(ctx context.Context, rounds []uint64) error {
+	if len(rounds) == 0 {
+		return nil
+	}
+
+	fn := filepath.Join(h.dir, ArchiveFilename(rounds[0], rounds[len(rounds)-1]))
+	if err := h.writeArchive(ctx, fn, rounds); err != nil {
+		h.logger.Error("failed to archive pruned rounds",
+			"err", err,
+			"fn", fn,
+		)
+		return err
+	}
+
+	h.logger.Debug("archived pruned rounds",
+		"fn", fn,
+		"round_count", len(rounds),
+	)
+	return nil
+}
+
+func (h *archiveHandler) writeArchive(ctx context.Context, fn string, rounds []uint64) error {
+	// Write into a temporary file first so that archive files are never
+	// partially written, even if the node crashes.
+	f, err := ioutil.TempFile(h.dir, archiveTmpPrefix)
+	if err != nil {
+		return fmt.Errorf("runtime/history: failed to create archive file: %w", err)
+	}
+	tmpFn := f.Name()
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(tmpFn)
+	}()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, round := range rounds {
+		// The rounds are only pruned once all handlers succeed, so they are
+		// still available outside the pruning transaction.
+		blk, err := h.history.GetAnnotatedBlock(ctx, round)
+		if err != nil {
+			return fmt.Errorf("runtime/history: failed to get block for round %d: %w", round, err)
+		}
+		if err = enc.Encode(blk); err != nil {
+			return fmt.Errorf("runtime/history: failed to encode block for round %d: %w", round, err)
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("runtime/history: failed to write archive file: %w", err)
+	}
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("runtime/history: failed to sync archive file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("runtime/history: failed to close archive file: %w", err)
+	}
+	if err = os.Rename(tmpFn, fn); err != nil {
+		return fmt.Errorf("runtime/history: failed to rename archive file: %w", err)
+	}
+	return nil
+}
+
+// NewArchiveHandler creates a new prune handler that archives all rounds
+// pruned from the given history into the given directory.
+//
+// Each batch of pruned rounds is written into its own file (see
+// ArchiveFilename) where each line is a JSON-encoded roothash.AnnotatedBlock.
+// As pruning may be retried, the same round may appear in multiple archive
+// files, so consumers should deduplicate blocks by round.
+func NewArchiveHandler(dir string, history History) (PruneHandler, error) {
+	if err := common.Mkdir(dir); err != nil {
+		return nil, fmt.Errorf("runtime/history: failed to create archive directory: %w", err)
+	}
+
+	return &archiveHandler{
+		logger:  logging.GetLogger("history/archive").With("runtime_id", history.RuntimeID()),
+		dir:     dir,
+		history: history,
+	}, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/keyformat"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
 )
 
 const dbVersion = 1
@@ -205,6 +206,72 @@ func (d *DB) commit(blk *roothash.AnnotatedBlock) error {
 	})
 }
 
+func (d *DB) rollback(height int64) error {
+	return d.db.Update(func(tx *badger.Txn) error {
+		meta, err := d.queryGetMetadata(tx)
+		if err != nil {
+			return err
+		}
+
+		if height >= meta.LastConsensusHeight {
+			return nil
+		}
+
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix:         blockKeyFmt.Encode(),
+			PrefetchValues: true,
+			Reverse:        true,
+		})
+		defer it.Close()
+
+		// Start with the largest round and proceed backward until we find a block
+		// that was committed at or below the target consensus height.
+		var (
+			lastRound uint64
+			found     bool
+		)
+		var toDelete [][]byte
+		for it.Seek(blockKeyFmt.Encode(meta.LastRound)); it.Valid(); it.Next() {
+			item := it.Item()
+
+			var blk roothash.AnnotatedBlock
+			if err = item.Value(func(val []byte) error {
+				return cbor.Unmarshal(val, &blk)
+			}); err != nil {
+				return err
+			}
+
+			if blk.Height <= height {
+				lastRound = blk.Block.Header.Round
+				found = true
+				break
+			}
+			toDelete = append(toDelete, item.KeyCopy(nil))
+		}
+		if !found && len(toDelete) > 0 {
+			// All remaining blocks were committed above the target height, so
+			// there is no block the history could be rolled back to.
+			return fmt.Errorf("runtime/history: no block committed at or below consensus height %d", height)
+		}
+
+		for _, key := range toDelete {
+			if err = tx.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		d.logger.Info("rolled back history",
+			"height", height,
+			"last_round", lastRound,
+			"removed_blocks", len(toDelete),
+		)
+
+		meta.LastRound = lastRound
+		meta.LastConsensusHeight = height
+		return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(meta))
+	})
+}
+
 func (d *DB) getBlock(round uint64) (*roothash.AnnotatedBlock, error) {
 	var blk roothash.AnnotatedBlock
 	txErr := d.db.View(func(tx *badger.Txn) error {
@@ -212,7 +279,7 @@ func (d *DB) getBlock(round uint64) (*roothash.AnnotatedBlock, error) {
 		switch err {
 		case nil:
 		case badger.ErrKeyNotFound:
-			return roothash.ErrNotFound
+			return d.queryMissingBlockError(tx, round)
 		default:
 			return err
 		}
@@ -227,6 +294,96 @@ func (d *DB) getBlock(round uint64) (*roothash.AnnotatedBlock, error) {
 	return &blk, nil
 }
 
+// queryMissingBlockError returns the error for a block of the given round
+// that is not in the database, distinguishing rounds that have already been
+// pruned from rounds that are not (yet) available.
+func (d *DB) queryMissingBlockError(tx *badger.Txn, round uint64) error {
+	it := tx.NewIterator(badger.IteratorOptions{
+		Prefix:         blockKeyFmt.Encode(),
+		PrefetchValues: false,
+	})
+	defer it.Close()
+
+	it.Rewind()
+	if !it.Valid() {
+		return roothash.ErrNotFound
+	}
+
+	var earliestRound uint64
+	if !blockKeyFmt.Decode(it.Item().Key(), &earliestRound) {
+		return roothash.ErrNotFound
+	}
+	if round < earliestRound {
+		return roothash.ErrRoundPruned
+	}
+	return roothash.ErrNotFound
+}
+
+func (d *DB) getBlocksByTimeRange(from, to uint64) ([]*block.Block, error) {
+	var blks []*block.Block
+	txErr := d.db.View(func(tx *badger.Txn) error {
+		meta, err := d.queryGetMetadata(tx)
+		if err != nil {
+			return err
+		}
+
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix:         blockKeyFmt.Encode(),
+			PrefetchValues: false,
+		})
+		defer it.Close()
+
+		decodeBlock := func() (*roothash.AnnotatedBlock, error) {
+			var blk roothash.AnnotatedBlock
+			if err = it.Item().Value(func(val []byte) error {
+				return cbor.Unmarshal(val, &blk)
+			}); err != nil {
+				return nil, err
+			}
+			return &blk, nil
+		}
+
+		// Block timestamps are non-decreasing in rounds, so binary search for
+		// the first round with a timestamp at or after the start of the range.
+		// Some rounds may be missing (e.g., pruned or failed), so each probe
+		// uses the first available round at or after the probed round.
+		lo, hi := uint64(0), meta.LastRound+1
+		for lo < hi {
+			mid := lo + (hi-lo)/2
+			if it.Seek(blockKeyFmt.Encode(mid)); !it.Valid() {
+				hi = mid
+				continue
+			}
+
+			var blk *roothash.AnnotatedBlock
+			if blk, err = decodeBlock(); err != nil {
+				return err
+			}
+			if blk.Block.Header.Timestamp < from {
+				lo = blk.Block.Header.Round + 1
+			} else {
+				hi = mid
+			}
+		}
+
+		for it.Seek(blockKeyFmt.Encode(lo)); it.Valid(); it.Next() {
+			var blk *roothash.AnnotatedBlock
+			if blk, err = decodeBlock(); err != nil {
+				return err
+			}
+			if blk.Block.Header.Timestamp > to {
+				break
+			}
+			blks = append(blks, blk.Block)
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return blks, nil
+}
+
 func (d *DB) close() {
 	d.gc.Close()
 	d.db.Close()
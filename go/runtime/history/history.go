@@ -48,6 +48,12 @@ type History interface {
 	// Pruner returns the history pruner.
 	Pruner() Pruner
 
+	// RollbackToConsensusHeight removes all blocks committed at consensus
+	// heights above the given height and resets the last consensus height.
+	//
+	// This must only be called while the history is not being tracked.
+	RollbackToConsensusHeight(height int64) error
+
 	// Close closes the history keeper.
 	Close()
 }
@@ -76,18 +82,38 @@ func (h *nopHistory) GetBlock(ctx context.Context, round uint64) (*block.Block,
 	return nil, errNopHistory
 }
 
+func (h *nopHistory) GetAnnotatedBlock(ctx context.Context, round uint64) (*roothash.AnnotatedBlock, error) {
+	return nil, errNopHistory
+}
+
 func (h *nopHistory) GetLatestBlock(ctx context.Context) (*block.Block, error) {
 	return nil, errNopHistory
 }
 
+func (h *nopHistory) GetBlocksByTimeRange(ctx context.Context, from, to time.Time) ([]*block.Block, error) {
+	return nil, errNopHistory
+}
+
 func (h *nopHistory) Pruner() Pruner {
 	pruner, _ := NewNonePruner()(nil)
 	return pruner
 }
 
+func (h *nopHistory) RollbackToConsensusHeight(height int64) error {
+	return errNopHistory
+}
+
 func (h *nopHistory) Close() {
 }
 
+// timeToTimestamp converts the given time to a block timestamp.
+func timeToTimestamp(t time.Time) uint64 {
+	if t.Unix() < 0 {
+		return 0
+	}
+	return uint64(t.Unix())
+}
+
 // NewNop creates a new no-op runtime history keeper.
 func NewNop(runtimeID common.Namespace) History {
 	return &nopHistory{runtimeID: runtimeID}
@@ -148,6 +174,10 @@ func (h *runtimeHistory) GetBlock(ctx context.Context, round uint64) (*block.Blo
 	return annBlk.Block, nil
 }
 
+func (h *runtimeHistory) GetAnnotatedBlock(ctx context.Context, round uint64) (*roothash.AnnotatedBlock, error) {
+	return h.db.getBlock(round)
+}
+
 func (h *runtimeHistory) GetLatestBlock(ctx context.Context) (*block.Block, error) {
 	meta, err := h.db.metadata()
 	if err != nil {
@@ -161,10 +191,21 @@ func (h *runtimeHistory) GetLatestBlock(ctx context.Context) (*block.Block, erro
 	return annBlk.Block, nil
 }
 
+func (h *runtimeHistory) GetBlocksByTimeRange(ctx context.Context, from, to time.Time) ([]*block.Block, error) {
+	if to.Before(from) {
+		return nil, nil
+	}
+	return h.db.getBlocksByTimeRange(timeToTimestamp(from), timeToTimestamp(to))
+}
+
 func (h *runtimeHistory) Pruner() Pruner {
 	return h.pruner
 }
 
+func (h *runtimeHistory) RollbackToConsensusHeight(height int64) error {
+	return h.db.rollback(height)
+}
+
 func (h *runtimeHistory) Close() {
 	h.cancelCtx()
 	close(h.stopCh)
@@ -222,6 +263,7 @@ func New(dataDir string, runtimeID common.Namespace, cfg *Config) (History, erro
 	}
 	pruner, err := cfg.Pruner(db)
 	if err != nil {
+		db.close()
 		return nil, err
 	}
 
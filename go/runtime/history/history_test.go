@@ -2,9 +2,11 @@ package history
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -190,7 +192,7 @@ func TestHistoryPrune(t *testing.T) {
 		_, err = history.GetBlock(context.Background(), uint64(i))
 		if i <= 40 {
 			require.Error(err, "GetBlock should fail for pruned block %d", i)
-			require.Equal(roothash.ErrNotFound, err)
+			require.Equal(roothash.ErrRoundPruned, err)
 		} else {
 			require.NoError(err, "GetBlock(%d)", i)
 		}
@@ -251,3 +253,315 @@ func TestHistoryPruneError(t *testing.T) {
 		require.NoError(err, "GetBlock(%d)", i)
 	}
 }
+
+func TestHistoryPruneRetention(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-history-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune retention test ns"), 0)
+
+	_, err = New(dataDir, runtimeID, &Config{
+		Pruner:        NewRetentionPruner(RetentionPolicy{}),
+		PruneInterval: time.Hour,
+	})
+	require.Error(err, "New should fail with an unlimited retention policy")
+
+	history, err := New(dataDir, runtimeID, &Config{
+		Pruner: NewRetentionPruner(RetentionPolicy{
+			NumKept: 10,
+			MaxAge:  20*time.Minute + 30*time.Second,
+		}),
+		PruneInterval: time.Hour,
+	})
+	require.NoError(err, "New")
+	defer history.Close()
+
+	// Create some blocks, one per minute.
+	now := time.Now()
+	for i := 0; i <= 50; i++ {
+		blk := roothash.AnnotatedBlock{
+			Height: int64(i),
+			Block:  block.NewGenesisBlock(runtimeID, 0),
+		}
+		blk.Block.Header.Round = uint64(i)
+		blk.Block.Header.Timestamp = uint64(now.Add(time.Duration(i-50) * time.Minute).Unix())
+
+		err = history.Commit(&blk)
+		require.NoError(err, "Commit")
+	}
+
+	ph := testPruneHandler{
+		doneCh:     make(chan struct{}),
+		waitRounds: 30,
+	}
+	history.Pruner().RegisterHandler(&ph)
+
+	// Rounds are retained if they are either among the last 10 rounds or
+	// younger than the maximum age.
+	err = history.Pruner().Prune(context.Background(), 50)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, 30)
+	for i := 0; i <= 50; i++ {
+		_, err = history.GetBlock(context.Background(), uint64(i))
+		if i < 30 {
+			require.Equal(roothash.ErrRoundPruned, err, "GetBlock should fail for pruned block %d", i)
+		} else {
+			require.NoError(err, "GetBlock(%d)", i)
+		}
+	}
+
+	// The latest round should always be retained.
+	dataDir2 := filepath.Join(dataDir, "latest")
+	err = os.Mkdir(dataDir2, 0700)
+	require.NoError(err, "Mkdir")
+	history2, err := New(dataDir2, runtimeID, &Config{
+		Pruner:        NewRetentionPruner(RetentionPolicy{MaxAge: time.Minute}),
+		PruneInterval: time.Hour,
+	})
+	require.NoError(err, "New")
+	defer history2.Close()
+
+	for i := 0; i <= 1; i++ {
+		blk := roothash.AnnotatedBlock{
+			Height: int64(i),
+			Block:  block.NewGenesisBlock(runtimeID, 0),
+		}
+		blk.Block.Header.Round = uint64(i)
+
+		err = history2.Commit(&blk)
+		require.NoError(err, "Commit")
+	}
+
+	err = history2.Pruner().Prune(context.Background(), 1)
+	require.NoError(err, "Prune")
+	_, err = history2.GetBlock(context.Background(), 0)
+	require.Equal(roothash.ErrRoundPruned, err, "GetBlock should fail for pruned block")
+	_, err = history2.GetBlock(context.Background(), 1)
+	require.NoError(err, "GetBlock should succeed for the latest block")
+}
+
+func TestHistoryArchive(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-history-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history archive test ns"), 0)
+
+	history, err := New(dataDir, runtimeID, &Config{
+		Pruner:        NewKeepLastPruner(10),
+		PruneInterval: time.Hour,
+	})
+	require.NoError(err, "New")
+	defer history.Close()
+
+	archiveDir := filepath.Join(dataDir, "archive")
+	ah, err := NewArchiveHandler(archiveDir, history)
+	require.NoError(err, "NewArchiveHandler")
+	history.Pruner().RegisterHandler(ah)
+
+	// Create some blocks.
+	var committed []*roothash.AnnotatedBlock
+	for i := 0; i <= 50; i++ {
+		blk := roothash.AnnotatedBlock{
+			Height: int64(i),
+			Block:  block.NewGenesisBlock(runtimeID, 0),
+		}
+		blk.Block.Header.Round = uint64(i)
+
+		err = history.Commit(&blk)
+		require.NoError(err, "Commit")
+		committed = append(committed, &blk)
+	}
+
+	err = history.Pruner().Prune(context.Background(), 50)
+	require.NoError(err, "Prune")
+
+	// Ensure that all pruned blocks were archived.
+	files, err := ioutil.ReadDir(archiveDir)
+	require.NoError(err, "ReadDir")
+	require.Len(files, 1, "pruned rounds should be archived into a single file")
+	require.Equal(ArchiveFilename(0, 40), files[0].Name())
+
+	f, err := os.Open(filepath.Join(archiveDir, files[0].Name()))
+	require.NoError(err, "Open")
+	defer f.Close()
+
+	var archived []*roothash.AnnotatedBlock
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var blk roothash.AnnotatedBlock
+		err = dec.Decode(&blk)
+		require.NoError(err, "Decode")
+		archived = append(archived, &blk)
+	}
+	require.Equal(committed[:41], archived, "archive should contain the pruned blocks")
+
+	// Failing to archive should abort the prune.
+	err = os.RemoveAll(archiveDir)
+	require.NoError(err, "RemoveAll")
+	blk := roothash.AnnotatedBlock{
+		Height: 51,
+		Block:  block.NewGenesisBlock(runtimeID, 0),
+	}
+	blk.Block.Header.Round = 51
+	err = history.Commit(&blk)
+	require.NoError(err, "Commit")
+
+	err = history.Pruner().Prune(context.Background(), 51)
+	require.Error(err, "Prune should fail when archiving fails")
+	_, err = history.GetBlock(context.Background(), 41)
+	require.NoError(err, "GetBlock should succeed for a block that failed to be archived")
+}
+
+func TestHistoryRollback(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-history-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history rollback test ns"), 0)
+
+	history, err := New(dataDir, runtimeID, NewDefaultConfig())
+	require.NoError(err, "New")
+	defer history.Close()
+
+	// Create some blocks, one every other consensus height.
+	for i := 0; i <= 10; i++ {
+		blk := roothash.AnnotatedBlock{
+			Height: int64(2 * i),
+			Block:  block.NewGenesisBlock(runtimeID, 0),
+		}
+		blk.Block.Header.Round = uint64(i)
+
+		err = history.Commit(&blk)
+		require.NoError(err, "Commit")
+	}
+
+	// Rolling back to a higher height should be a no-op.
+	err = history.RollbackToConsensusHeight(30)
+	require.NoError(err, "RollbackToConsensusHeight")
+	lastHeight, err := history.LastConsensusHeight()
+	require.NoError(err, "LastConsensusHeight")
+	require.EqualValues(20, lastHeight)
+
+	err = history.RollbackToConsensusHeight(11)
+	require.NoError(err, "RollbackToConsensusHeight")
+
+	lastHeight, err = history.LastConsensusHeight()
+	require.NoError(err, "LastConsensusHeight")
+	require.EqualValues(11, lastHeight)
+
+	latestBlk, err := history.GetLatestBlock(context.Background())
+	require.NoError(err, "GetLatestBlock")
+	require.EqualValues(5, latestBlk.Header.Round, "latest block should be the last one at or below the height")
+
+	for i := 6; i <= 10; i++ {
+		_, err = history.GetBlock(context.Background(), uint64(i))
+		require.Error(err, "GetBlock should fail for rolled back block %d", i)
+		require.Equal(roothash.ErrNotFound, err)
+	}
+
+	// It should be possible to commit blocks again after a rollback.
+	blk := roothash.AnnotatedBlock{
+		Height: 12,
+		Block:  block.NewGenesisBlock(runtimeID, 0),
+	}
+	blk.Block.Header.Round = 6
+	err = history.Commit(&blk)
+	require.NoError(err, "Commit after rollback")
+
+	// Rolling back below the first block should fail and leave the history
+	// intact.
+	err = history.RollbackToConsensusHeight(-1)
+	require.Error(err, "RollbackToConsensusHeight below the first block")
+
+	lastHeight, err = history.LastConsensusHeight()
+	require.NoError(err, "LastConsensusHeight")
+	require.EqualValues(12, lastHeight)
+
+	latestBlk, err = history.GetLatestBlock(context.Background())
+	require.NoError(err, "GetLatestBlock")
+	require.EqualValues(6, latestBlk.Header.Round)
+}
+
+func TestHistoryTimeRange(t *testing.T) {
+	require := require.New(t)
+
+	// Create a new random temporary directory under /tmp.
+	dataDir, err := ioutil.TempDir("", "oasis-runtime-history-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history time range test ns"), 0)
+
+	history, err := New(dataDir, runtimeID, NewDefaultConfig())
+	require.NoError(err, "New")
+	defer history.Close()
+
+	ctx := context.Background()
+
+	blks, err := history.GetBlocksByTimeRange(ctx, time.Unix(0, 0), time.Unix(1000, 0))
+	require.NoError(err, "GetBlocksByTimeRange")
+	require.Empty(blks, "GetBlocksByTimeRange should return nothing for an empty history")
+
+	// Create some blocks with gaps in rounds and with multiple blocks sharing
+	// the same timestamp.
+	var committed []*block.Block
+	for i := 0; i <= 30; i++ {
+		if i%3 == 1 {
+			continue
+		}
+
+		blk := roothash.AnnotatedBlock{
+			Height: int64(i),
+			Block:  block.NewGenesisBlock(runtimeID, 0),
+		}
+		blk.Block.Header.Round = uint64(i)
+		blk.Block.Header.Timestamp = uint64(100 + 10*(i/2))
+
+		err = history.Commit(&blk)
+		require.NoError(err, "Commit")
+		committed = append(committed, blk.Block)
+	}
+
+	annBlk, err := history.GetAnnotatedBlock(ctx, 6)
+	require.NoError(err, "GetAnnotatedBlock")
+	require.EqualValues(6, annBlk.Height, "GetAnnotatedBlock should return the consensus height")
+	_, err = history.GetAnnotatedBlock(ctx, 7)
+	require.Equal(roothash.ErrNotFound, err, "GetAnnotatedBlock should fail for missing round")
+
+	for _, tc := range []struct {
+		from int64
+		to   int64
+	}{
+		{0, 1000},
+		{0, 99},
+		{251, 1000},
+		{100, 100},
+		{105, 135},
+		{110, 130},
+		{150, 150},
+		{250, 250},
+		{130, 120},
+	} {
+		var expected []*block.Block
+		for _, blk := range committed {
+			if blk.Header.Timestamp >= uint64(tc.from) && blk.Header.Timestamp <= uint64(tc.to) {
+				expected = append(expected, blk)
+			}
+		}
+
+		blks, err = history.GetBlocksByTimeRange(ctx, time.Unix(tc.from, 0), time.Unix(tc.to, 0))
+		require.NoError(err, "GetBlocksByTimeRange(%d, %d)", tc.from, tc.to)
+		require.Equal(expected, blks, "GetBlocksByTimeRange(%d, %d) should return the correct blocks", tc.from, tc.to)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
 )
 
 const (
@@ -15,6 +18,9 @@ const (
 	PrunerStrategyNone = "none"
 	// PrunerStrategyKeepLast is the name of the keep last pruner strategy.
 	PrunerStrategyKeepLast = "keep_last"
+	// PrunerStrategyRetention is the name of the retention policy pruner
+	// strategy.
+	PrunerStrategyRetention = "retention"
 )
 
 // PrunerFactory is the runtime history pruner factory interface.
@@ -56,50 +62,24 @@ func (p *prunerBase) RegisterHandler(handler PruneHandler) {
 	p.handlers = append(p.handlers, handler)
 }
 
-func newPrunerBase() prunerBase {
-	return prunerBase{}
-}
-
-type nonePruner struct {
-}
-
-func (p *nonePruner) RegisterHandler(handler PruneHandler) {
-}
-
-func (p *nonePruner) Prune(ctx context.Context, latestRound uint64) error {
-	return nil
-}
-
-// NewNonePruner creates a new pruner that never prunes anything.
-func NewNonePruner() PrunerFactory {
-	return func(db *DB) (Pruner, error) {
-		return &nonePruner{}, nil
-	}
-}
-
-type keepLastPruner struct {
-	prunerBase
-
-	logger *logging.Logger
-	db     *DB
-
-	numKept uint64
-}
-
-func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
-	if latestRound < p.numKept {
-		return nil
-	}
-
-	p.prunerBase.RLock()
-	defer p.prunerBase.RUnlock()
-
-	lastPrunedRound := latestRound - p.numKept
-
-	return p.db.db.Update(func(tx *badger.Txn) error {
-		// NOTE: Do not prefetch values as we are only looking at keys.
+// pruneFilter returns true iff the block of the given round should be
+// pruned. The item value is only prefetched if requested.
+//
+// It is called in increasing round order and pruning stops at the first
+// round that should not be pruned.
+type pruneFilter func(round uint64, item *badger.Item) (bool, error)
+
+// prune removes rounds from the start of history for as long as the filter
+// accepts them. All registered handlers are called before the removal is
+// committed and any handler failing aborts the prune.
+func (p *prunerBase) prune(ctx context.Context, logger *logging.Logger, db *DB, prefetchValues bool, filter pruneFilter) error {
+	p.RLock()
+	defer p.RUnlock()
+
+	return db.db.Update(func(tx *badger.Txn) error {
 		it := tx.NewIterator(badger.IteratorOptions{
-			Prefix: blockKeyFmt.Encode(),
+			Prefix:         blockKeyFmt.Encode(),
+			PrefetchValues: prefetchValues,
 		})
 		defer it.Close()
 
@@ -114,11 +94,15 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 				panic("runtime/history: bad iterator")
 			}
 
-			if round > lastPrunedRound {
+			ok, err := filter(round, item)
+			if err != nil {
+				return err
+			}
+			if !ok {
 				break
 			}
 
-			if err := tx.Delete(item.KeyCopy(nil)); err != nil {
+			if err = tx.Delete(item.KeyCopy(nil)); err != nil {
 				if err == badger.ErrTxnTooBig {
 					// We can't prune any more rounds in this transaction.
 					break
@@ -136,9 +120,9 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 
 		// Before pruning anything, run all prune handlers. If any of them
 		// fails we abort the prune.
-		for _, ph := range p.prunerBase.handlers {
+		for _, ph := range p.handlers {
 			if err := ph.Prune(ctx, pruned); err != nil {
-				p.logger.Error("prune handler failed, aborting prune",
+				logger.Error("prune handler failed, aborting prune",
 					"err", err,
 					"round_count", len(pruned),
 					"round_min", pruned[0],
@@ -152,6 +136,49 @@ func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
 	})
 }
 
+func newPrunerBase() prunerBase {
+	return prunerBase{}
+}
+
+type nonePruner struct {
+}
+
+func (p *nonePruner) RegisterHandler(handler PruneHandler) {
+}
+
+func (p *nonePruner) Prune(ctx context.Context, latestRound uint64) error {
+	return nil
+}
+
+// NewNonePruner creates a new pruner that never prunes anything.
+func NewNonePruner() PrunerFactory {
+	return func(db *DB) (Pruner, error) {
+		return &nonePruner{}, nil
+	}
+}
+
+type keepLastPruner struct {
+	prunerBase
+
+	logger *logging.Logger
+	db     *DB
+
+	numKept uint64
+}
+
+func (p *keepLastPruner) Prune(ctx context.Context, latestRound uint64) error {
+	if latestRound < p.numKept {
+		return nil
+	}
+
+	lastPrunedRound := latestRound - p.numKept
+
+	// NOTE: Do not prefetch values as we are only looking at keys.
+	return p.prune(ctx, p.logger, p.db, false, func(round uint64, item *badger.Item) (bool, error) {
+		return round <= lastPrunedRound, nil
+	})
+}
+
 // NewKeepLastPruner creates a pruner that keeps the last configured
 // number of rounds.
 func NewKeepLastPruner(numKept uint64) PrunerFactory {
@@ -164,3 +191,92 @@ func NewKeepLastPruner(numKept uint64) PrunerFactory {
 		}, nil
 	}
 }
+
+// RetentionPolicy is the runtime history retention policy.
+//
+// A round is retained as long as it is within any of the configured limits,
+// so rounds are only pruned after they fall outside all of them. The latest
+// round is always retained.
+type RetentionPolicy struct {
+	// NumKept is the number of last rounds to retain (zero for no limit).
+	NumKept uint64 `json:"num_kept"`
+
+	// MaxAge is the duration for which rounds are retained, based on the
+	// block timestamp (zero for no limit).
+	MaxAge time.Duration `json:"max_age"`
+}
+
+// Validate validates the retention policy.
+func (rp *RetentionPolicy) Validate() error {
+	if rp.NumKept == 0 && rp.MaxAge == 0 {
+		return fmt.Errorf("runtime/history: retention policy must limit the number of rounds or their age")
+	}
+	if rp.MaxAge < 0 {
+		return fmt.Errorf("runtime/history: negative retention max age: %s", rp.MaxAge)
+	}
+	return nil
+}
+
+// shouldPrune returns true iff the given round should be pruned at the given
+// time, given the latest round. The block is only loaded if needed.
+func (rp *RetentionPolicy) shouldPrune(round, latestRound uint64, now time.Time, getBlock func() (*roothash.AnnotatedBlock, error)) (bool, error) {
+	if round >= latestRound {
+		return false, nil
+	}
+	if rp.NumKept > 0 && latestRound-round < rp.NumKept {
+		return false, nil
+	}
+	if rp.MaxAge > 0 {
+		blk, err := getBlock()
+		if err != nil {
+			return false, err
+		}
+		if blk.Block.Header.Timestamp >= timeToTimestamp(now.Add(-rp.MaxAge)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+type retentionPruner struct {
+	prunerBase
+
+	logger *logging.Logger
+	db     *DB
+
+	policy RetentionPolicy
+}
+
+func (p *retentionPruner) Prune(ctx context.Context, latestRound uint64) error {
+	// Block timestamps are non-decreasing in rounds, so the retained rounds
+	// always form a suffix of history.
+	now := time.Now()
+	return p.prune(ctx, p.logger, p.db, p.policy.MaxAge > 0, func(round uint64, item *badger.Item) (bool, error) {
+		return p.policy.shouldPrune(round, latestRound, now, func() (*roothash.AnnotatedBlock, error) {
+			var blk roothash.AnnotatedBlock
+			if err := item.Value(func(val []byte) error {
+				return cbor.Unmarshal(val, &blk)
+			}); err != nil {
+				return nil, err
+			}
+			return &blk, nil
+		})
+	})
+}
+
+// NewRetentionPruner creates a pruner that prunes rounds outside the given
+// retention policy.
+func NewRetentionPruner(policy RetentionPolicy) PrunerFactory {
+	return func(db *DB) (Pruner, error) {
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+
+		return &retentionPruner{
+			prunerBase: newPrunerBase(),
+			logger:     logging.GetLogger("history/prune/retention"),
+			db:         db,
+			policy:     policy,
+		}, nil
+	}
+}
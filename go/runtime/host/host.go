@@ -26,6 +26,20 @@ type Config struct {
 
 	// MessageHandler is the message handler for the Runtime Host Protocol messages.
 	MessageHandler protocol.Handler
+
+	// Limits are optional resource limits for the provisioned runtime. Whether and how these are
+	// enforced is up to the used provisioner.
+	Limits ResourceLimits
+}
+
+// ResourceLimits are the resource limits for a provisioned runtime.
+type ResourceLimits struct {
+	// CPUPercent is the maximum CPU usage as a percentage of a single CPU (e.g., 150 means one and
+	// a half CPUs). Zero means no limit.
+	CPUPercent uint64
+
+	// Memory is the maximum amount of memory in bytes. Zero means no limit.
+	Memory uint64
 }
 
 // Provisioner is the runtime provisioner interface.
@@ -71,6 +85,7 @@ type Event struct {
 	FailedToStart *FailedToStartEvent
 	Stopped       *StoppedEvent
 	Updated       *UpdatedEvent
+	LimitExceeded *LimitExceededEvent
 }
 
 // StartedEvent is a runtime started event.
@@ -93,6 +108,13 @@ type FailedToStartEvent struct {
 type StoppedEvent struct {
 }
 
+// LimitExceededEvent is a runtime killed due to exceeding its resource limits event. It is always
+// followed by a StoppedEvent.
+type LimitExceededEvent struct {
+	// Resource is the resource whose limit has been exceeded.
+	Resource string
+}
+
 // UpdatedEvent is a runtime metadata updated event.
 type UpdatedEvent struct {
 	// CapabilityTEE is the updated runtime's CapabilityTEE. It may be nil in case the runtime is
@@ -70,8 +70,12 @@ type Connection interface {
 	// Close closes the connection.
 	Close()
 
-	// Call sends a request to the other side and returns the response or error.
-	Call(ctx context.Context, body *Body) (*Body, error)
+	// Call sends a request with the given priority class to the other side and
+	// returns the response or error.
+	//
+	// In case the context is cancelled before the response is received, the
+	// other side is notified that the request has been cancelled.
+	Call(ctx context.Context, priority Priority, body *Body) (*Body, error)
 
 	// InitHost performs initialization in host mode and transitions the connection to Ready state.
 	//
@@ -136,16 +140,19 @@ type connection struct { // nolint: maligned
 	conn  net.Conn
 	codec *cbor.MessageCodec
 
-	runtimeID common.Namespace
-	handler   Handler
+	runtimeID      common.Namespace
+	handler        Handler
+	runtimeLoggers map[string]*logging.Logger
 
-	state           state
-	pendingRequests map[uint64]chan *Body
-	nextRequestID   uint64
+	state            state
+	pendingRequests  map[uint64]chan *Body
+	nextRequestID    uint64
+	incomingRequests map[uint64]context.CancelFunc
 
-	outCh   chan *Message
-	closeCh chan struct{}
-	quitWg  sync.WaitGroup
+	outCh     chan *Message
+	outHighCh chan *Message
+	closeCh   chan struct{}
+	quitWg    sync.WaitGroup
 
 	logger *logging.Logger
 }
@@ -198,16 +205,16 @@ func (c *connection) Close() {
 }
 
 // Implements Connection.
-func (c *connection) Call(ctx context.Context, body *Body) (*Body, error) {
+func (c *connection) Call(ctx context.Context, priority Priority, body *Body) (*Body, error) {
 	if c.getState() != stateReady {
 		return nil, ErrNotReady
 	}
 
-	b, err := c.call(ctx, body)
+	b, err := c.call(ctx, priority, body)
 	return b, err
 }
 
-func (c *connection) call(ctx context.Context, body *Body) (result *Body, err error) {
+func (c *connection) call(ctx context.Context, priority Priority, body *Body) (result *Body, err error) {
 	start := time.Now()
 	defer func() {
 		if viper.GetString(metrics.CfgMetricsMode) != metrics.MetricsModeNone {
@@ -220,7 +227,7 @@ func (c *connection) call(ctx context.Context, body *Body) (result *Body, err er
 		}
 	}()
 
-	respCh, err := c.makeRequest(ctx, body)
+	id, respCh, err := c.makeRequest(ctx, priority, body)
 	if err != nil {
 		return nil, err
 	}
@@ -242,11 +249,42 @@ func (c *connection) call(ctx context.Context, body *Body) (result *Body, err er
 
 		return resp, nil
 	case <-ctx.Done():
+		c.cancelRequest(id)
 		return nil, ctx.Err()
 	}
 }
 
-func (c *connection) makeRequest(ctx context.Context, body *Body) (<-chan *Body, error) {
+// cancelRequest abandons an outstanding request and notifies the other side
+// that the request has been cancelled.
+func (c *connection) cancelRequest(id uint64) {
+	c.Lock()
+	_, ok := c.pendingRequests[id]
+	delete(c.pendingRequests, id)
+	c.Unlock()
+
+	if !ok {
+		// Response already received or connection closed.
+		return
+	}
+
+	msg := &Message{
+		ID:          id,
+		MessageType: MessageCancel,
+		Priority:    PriorityHigh,
+		Body:        Body{Empty: &Empty{}},
+		SpanContext: cbor.FixSliceForSerde(nil),
+	}
+	go func() {
+		if err := c.sendMessage(context.Background(), msg); err != nil {
+			c.logger.Warn("failed to send cancel message",
+				"err", err,
+				"id", id,
+			)
+		}
+	}()
+}
+
+func (c *connection) makeRequest(ctx context.Context, priority Priority, body *Body) (uint64, <-chan *Body, error) {
 	// Create channel for sending the response and grab next request identifier.
 	ch := make(chan *Body, 1)
 
@@ -271,21 +309,30 @@ func (c *connection) makeRequest(ctx context.Context, body *Body) (<-chan *Body,
 	msg := Message{
 		ID:          id,
 		MessageType: MessageRequest,
+		Priority:    priority,
 		Body:        *body,
 		SpanContext: scBinary,
 	}
 
 	// Queue the message.
 	if err := c.sendMessage(ctx, &msg); err != nil {
-		return nil, fmt.Errorf("failed to send message: %w", err)
+		c.Lock()
+		delete(c.pendingRequests, id)
+		c.Unlock()
+		return 0, nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
-	return ch, nil
+	return id, ch, nil
 }
 
 func (c *connection) sendMessage(ctx context.Context, msg *Message) error {
+	outCh := c.outCh
+	if msg.Priority == PriorityHigh {
+		outCh = c.outHighCh
+	}
+
 	select {
-	case c.outCh <- msg:
+	case outCh <- msg:
 		return nil
 	case <-c.closeCh:
 		return fmt.Errorf("connection closed")
@@ -298,17 +345,26 @@ func (c *connection) workerOutgoing() {
 	defer c.quitWg.Done()
 
 	for {
+		var msg *Message
+
+		// Always send high priority messages first.
 		select {
-		case msg := <-c.outCh:
-			// Outgoing message, send it.
-			if err := c.codec.Write(msg); err != nil {
-				c.logger.Error("error while sending message",
-					"err", err,
-				)
+		case msg = <-c.outHighCh:
+		default:
+			select {
+			case msg = <-c.outHighCh:
+			case msg = <-c.outCh:
+			case <-c.closeCh:
+				// Connection has terminated.
+				return
 			}
-		case <-c.closeCh:
-			// Connection has terminated.
-			return
+		}
+
+		// Outgoing message, send it.
+		if err := c.codec.Write(msg); err != nil {
+			c.logger.Error("error while sending message",
+				"err", err,
+			)
 		}
 	}
 }
@@ -328,6 +384,7 @@ func newResponseMessage(req *Message, body *Body) *Message {
 	return &Message{
 		ID:          req.ID,
 		MessageType: MessageResponse,
+		Priority:    req.Priority,
 		Body:        *body,
 		SpanContext: cbor.FixSliceForSerde(nil),
 	}
@@ -343,7 +400,7 @@ func (c *connection) handleMessage(ctx context.Context, message *Message) {
 		case state == stateInitializing:
 			// Only whitelisted methods are allowed.
 			body := message.Body
-			allowed = body.HostKeyManagerPolicyRequest != nil
+			allowed = body.HostKeyManagerPolicyRequest != nil || body.HostLogRequest != nil
 		case state == stateReady:
 			// All requests allowed.
 			allowed = true
@@ -377,12 +434,39 @@ func (c *connection) handleMessage(ctx context.Context, message *Message) {
 			}
 		}
 
-		// Call actual handler.
-		body, err := c.handler.Handle(ctx, &message.Body)
+		// Make it possible for the other side to cancel the request.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		c.Lock()
+		c.incomingRequests[message.ID] = cancel
+		c.Unlock()
+
+		// Call actual handler. Log records are handled by the connection itself
+		// so that they work the same for all handlers.
+		var (
+			body *Body
+			err  error
+		)
+		switch {
+		case message.Body.HostLogRequest != nil:
+			body, err = c.handleLogRequest(message.Body.HostLogRequest)
+		default:
+			body, err = c.handler.Handle(ctx, &message.Body)
+		}
 		if err != nil {
 			body = errorToBody(err)
 		}
 
+		c.Lock()
+		_, active := c.incomingRequests[message.ID]
+		delete(c.incomingRequests, message.ID)
+		c.Unlock()
+		if !active {
+			// The other side has cancelled the request, so it is no longer
+			// waiting for a response.
+			return
+		}
+
 		// Prepare and send response.
 		if err := c.sendMessage(ctx, newResponseMessage(message, body)); err != nil {
 			c.logger.Warn("failed to send response message",
@@ -405,6 +489,22 @@ func (c *connection) handleMessage(ctx context.Context, message *Message) {
 
 		respCh <- &message.Body
 		close(respCh)
+	case MessageCancel:
+		// Cancellation of a request made by the other side.
+		c.Lock()
+		cancel, ok := c.incomingRequests[message.ID]
+		delete(c.incomingRequests, message.ID)
+		c.Unlock()
+
+		if !ok {
+			// Request has already been handled.
+			break
+		}
+
+		c.logger.Debug("request cancelled by the other side",
+			"id", message.ID,
+		)
+		cancel()
 	default:
 		c.logger.Warn("received a malformed message from worker, ignoring",
 			"message", fmt.Sprintf("%+v", message),
@@ -482,11 +582,24 @@ func (c *connection) InitGuest(ctx context.Context, conn net.Conn) error {
 
 // Implements Connection.
 func (c *connection) InitHost(ctx context.Context, conn net.Conn) (*version.Version, error) {
+	info, err := c.initHost(ctx, conn, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rtVersion := version.FromU64(info.RuntimeVersion)
+	return &rtVersion, nil
+}
+
+// initHost performs initialization in host mode, allowing the runtime to open up to the given
+// number of connections in total, and returns the runtime's response to the handshake.
+func (c *connection) initHost(ctx context.Context, conn net.Conn, maxConnections uint16) (*RuntimeInfoResponse, error) {
 	c.initConn(conn)
 
 	// Check Runtime Host Protocol version.
-	rsp, err := c.call(ctx, &Body{RuntimeInfoRequest: &RuntimeInfoRequest{
-		RuntimeID: c.runtimeID,
+	rsp, err := c.call(ctx, PriorityNormal, &Body{RuntimeInfoRequest: &RuntimeInfoRequest{
+		RuntimeID:      c.runtimeID,
+		MaxConnections: maxConnections,
 	}})
 	switch {
 	default:
@@ -511,32 +624,38 @@ func (c *connection) InitHost(ctx context.Context, conn net.Conn) (*version.Vers
 		)
 	}
 
-	rtVersion := version.FromU64(info.RuntimeVersion)
-	c.logger.Info("runtime host protocol initialized", "runtime_version", rtVersion)
+	c.logger.Info("runtime host protocol initialized",
+		"runtime_version", version.FromU64(info.RuntimeVersion),
+	)
 
 	// Transition the protocol state to Ready.
 	c.Lock()
 	c.setStateLocked(stateReady)
 	c.Unlock()
 
-	return &rtVersion, nil
+	return info, nil
 }
 
 // NewConnection creates a new uninitialized RHP connection.
 func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler Handler) (Connection, error) {
+	return newConnection(logger, runtimeID, handler), nil
+}
+
+func newConnection(logger *logging.Logger, runtimeID common.Namespace, handler Handler) *connection {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(rhpCollectors...)
 	})
 
-	c := &connection{
-		runtimeID:       runtimeID,
-		handler:         handler,
-		state:           stateUninitialized,
-		pendingRequests: make(map[uint64]chan *Body),
-		outCh:           make(chan *Message),
-		closeCh:         make(chan struct{}),
-		logger:          logger,
+	return &connection{
+		runtimeID:        runtimeID,
+		handler:          handler,
+		runtimeLoggers:   make(map[string]*logging.Logger),
+		state:            stateUninitialized,
+		pendingRequests:  make(map[uint64]chan *Body),
+		incomingRequests: make(map[uint64]context.CancelFunc),
+		outCh:            make(chan *Message),
+		outHighCh:        make(chan *Message),
+		closeCh:          make(chan struct{}),
+		logger:           logger,
 	}
-
-	return c, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -69,25 +70,25 @@ func TestEchoRequestResponse(t *testing.T) {
 	require.Panics(func() { _ = protoB.InitGuest(context.Background(), connB) }, "connection reinit should panic")
 
 	reqA := Body{Empty: &Empty{}}
-	respA, err := protoA.Call(context.Background(), &reqA)
+	respA, err := protoA.Call(context.Background(), PriorityNormal, &reqA)
 	require.NoError(err, "A.Call()")
 	require.EqualValues(&reqA, respA, "A.Call()")
 	require.EqualValues(0, handlerA.calls, "Handler A must not be called")
 	require.EqualValues(1, handlerB.calls, "Handler B must be called")
 
 	reqB := Body{Empty: &Empty{}}
-	respB, err := protoB.Call(context.Background(), &reqB)
+	respB, err := protoB.Call(context.Background(), PriorityNormal, &reqB)
 	require.NoError(err, "B.Call()")
 	require.EqualValues(&reqB, respB, "B.Call()")
 	require.EqualValues(1, handlerA.calls, "Handler A must be called")
 	require.EqualValues(1, handlerB.calls, "Handler B must not be called")
 
 	protoA.Close()
-	_, err = protoA.Call(context.Background(), &reqA)
+	_, err = protoA.Call(context.Background(), PriorityNormal, &reqA)
 	require.Error(err, "A.Call() must error when connection is closed")
 
 	protoB.Close()
-	_, err = protoB.Call(context.Background(), &reqB)
+	_, err = protoB.Call(context.Background(), PriorityNormal, &reqB)
 	require.Error(err, "B.Call() must error when connection is closed")
 
 	require.Panics(func() { _, _ = protoA.InitHost(context.Background(), connA) }, "connection reinit should panic")
@@ -116,9 +117,121 @@ func TestBigMessage(t *testing.T) {
 
 	rq := make([]byte, 2000000)
 	reqA := Body{RuntimeRPCCallRequest: &RuntimeRPCCallRequest{Request: rq}}
-	respA, err := protoA.Call(context.Background(), &reqA)
+	respA, err := protoA.Call(context.Background(), PriorityNormal, &reqA)
 	require.NoError(err, "A.Call()")
 	require.EqualValues(&reqA, respA, "A.Call()")
 	require.EqualValues(0, handlerA.calls, "Handler A must not be called")
 	require.EqualValues(1, handlerB.calls, "Handler B must be called")
 }
+
+func TestLogRequest(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	connA, connB := net.Pipe()
+	handlerA := &testHandler{}
+	protoA, err := NewConnection(logger, runtimeID, handlerA)
+	require.NoError(err, "A.New()")
+	handlerB := &testHandler{}
+	protoB, err := NewConnection(logger, runtimeID, handlerB)
+	require.NoError(err, "B.New()")
+
+	err = protoA.InitGuest(context.Background(), connA)
+	require.NoError(err, "A.InitGuest()")
+	_, err = protoB.InitHost(context.Background(), connB)
+	require.NoError(err, "B.InitHost()")
+
+	rsp, err := protoA.Call(context.Background(), PriorityNormal, &Body{HostLogRequest: &HostLogRequest{
+		Level:   logging.LevelInfo,
+		Module:  "test",
+		Message: "hello from the runtime",
+		Fields:  map[string]string{"round": "42"},
+	}})
+	require.NoError(err, "A.Call(HostLogRequest)")
+	require.NotNil(rsp.HostLogResponse, "log request should succeed")
+	require.EqualValues(0, handlerB.calls, "Handler B must not be called for log requests")
+
+	_, err = protoA.Call(context.Background(), PriorityNormal, &Body{HostLogRequest: &HostLogRequest{
+		Level:   logging.LevelError + 1,
+		Module:  "test",
+		Message: "invalid level",
+	}})
+	require.Error(err, "log request with an invalid level should fail")
+
+	protoA.Close()
+	protoB.Close()
+}
+
+// blockingHandler blocks handling of ping requests until they are cancelled.
+type blockingHandler struct {
+	testHandler
+
+	startedCh   chan struct{}
+	cancelledCh chan struct{}
+}
+
+// Implements Handler.
+func (h *blockingHandler) Handle(ctx context.Context, body *Body) (*Body, error) {
+	if body.RuntimePingRequest == nil {
+		return h.testHandler.Handle(ctx, body)
+	}
+
+	close(h.startedCh)
+	<-ctx.Done()
+	close(h.cancelledCh)
+	return nil, ctx.Err()
+}
+
+func TestCancelRequest(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	connA, connB := net.Pipe()
+	handlerA := &blockingHandler{
+		startedCh:   make(chan struct{}),
+		cancelledCh: make(chan struct{}),
+	}
+	protoA, err := NewConnection(logger, runtimeID, handlerA)
+	require.NoError(err, "A.New()")
+	handlerB := &testHandler{}
+	protoB, err := NewConnection(logger, runtimeID, handlerB)
+	require.NoError(err, "B.New()")
+
+	err = protoA.InitGuest(context.Background(), connA)
+	require.NoError(err, "A.InitGuest()")
+	_, err = protoB.InitHost(context.Background(), connB)
+	require.NoError(err, "B.InitHost()")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		_, cerr := protoB.Call(ctx, PriorityNormal, &Body{RuntimePingRequest: &Empty{}})
+		errCh <- cerr
+	}()
+
+	select {
+	case <-handlerA.startedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to receive request")
+	}
+	cancel()
+	require.Equal(context.Canceled, <-errCh, "B.Call() should fail with a cancelled context")
+
+	// The other side should observe the cancellation.
+	select {
+	case <-handlerA.cancelledCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to receive request cancellation")
+	}
+
+	// The connection should remain usable, including for high priority requests.
+	reqB := Body{RuntimeRPCCallRequest: &RuntimeRPCCallRequest{Request: []byte("hello")}}
+	respB, err := protoB.Call(context.Background(), PriorityHigh, &reqB)
+	require.NoError(err, "B.Call()")
+	require.EqualValues(&reqB, respB, "B.Call()")
+
+	protoA.Close()
+	protoB.Close()
+}
@@ -0,0 +1,69 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+)
+
+// maxRuntimeLoggers is the maximum number of per-module runtime loggers
+// cached by a connection.
+const maxRuntimeLoggers = 128
+
+// runtimeLogModule returns the logging module used for log records emitted
+// by the given runtime module.
+//
+// Log levels for runtimes can be configured via the usual per-module log
+// level configuration, using either the "runtime/<runtime-id>" prefix for
+// all modules of a runtime or the full module name.
+func runtimeLogModule(runtimeID common.Namespace, module string) string {
+	return fmt.Sprintf("runtime/%s/%s", runtimeID, module)
+}
+
+func (c *connection) getRuntimeLogger(module string) *logging.Logger {
+	c.Lock()
+	defer c.Unlock()
+
+	if logger := c.runtimeLoggers[module]; logger != nil {
+		return logger
+	}
+
+	logger := logging.GetLogger(runtimeLogModule(c.runtimeID, module)).With("runtime_id", c.runtimeID)
+	if len(c.runtimeLoggers) < maxRuntimeLoggers {
+		c.runtimeLoggers[module] = logger
+	}
+	return logger
+}
+
+// handleLogRequest forwards a log record emitted by the runtime into the
+// node's logging pipeline.
+func (c *connection) handleLogRequest(rq *HostLogRequest) (*Body, error) {
+	keys := make([]string, 0, len(rq.Fields))
+	for k := range rq.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	keyvals := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		keyvals = append(keyvals, k, rq.Fields[k])
+	}
+
+	logger := c.getRuntimeLogger(rq.Module)
+	switch rq.Level {
+	case logging.LevelDebug:
+		logger.Debug(rq.Message, keyvals...)
+	case logging.LevelInfo:
+		logger.Info(rq.Message, keyvals...)
+	case logging.LevelWarn:
+		logger.Warn(rq.Message, keyvals...)
+	case logging.LevelError:
+		logger.Error(rq.Message, keyvals...)
+	default:
+		return nil, fmt.Errorf("rhp: invalid log level: %d", rq.Level)
+	}
+
+	return &Body{HostLogResponse: &Empty{}}, nil
+}
@@ -0,0 +1,364 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/errors"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+)
+
+const (
+	muxModuleName = "rhp/mux"
+
+	// muxReadBufferSize is the maximum amount of channel data sent in a single frame.
+	muxReadBufferSize = 64 * 1024
+	// muxChannelBacklog is the number of received frames that can be queued for
+	// a channel before receiving blocks for all channels.
+	muxChannelBacklog = 16
+	// muxAcceptBacklog is the number of channels opened by the other side that
+	// can be queued before being accepted.
+	muxAcceptBacklog = 16
+)
+
+var (
+	// ErrMuxClosed is the error reported when the multiplexed connection is closed.
+	ErrMuxClosed = errors.New(moduleName, 2, "rhp: multiplexed connection closed")
+
+	// ErrChannelExists is the error reported when a channel for the given runtime is
+	// already open.
+	ErrChannelExists = errors.New(moduleName, 3, "rhp: channel already exists")
+)
+
+// MuxConnection is a connection that multiplexes per-runtime logical channels
+// over a single underlying connection, so that a single sandbox process can
+// host multiple runtimes.
+//
+// Each logical channel is a net.Conn which can be used to initialize a regular
+// Runtime Host Protocol Connection via InitHost or InitGuest.
+type MuxConnection interface {
+	// Open opens a new logical channel for the given runtime.
+	Open(ctx context.Context, runtimeID common.Namespace) (net.Conn, error)
+
+	// Accept waits for the other side to open a new logical channel and returns
+	// the runtime identifier and the channel.
+	Accept(ctx context.Context) (common.Namespace, net.Conn, error)
+
+	// Close closes the underlying connection and all of the logical channels.
+	Close()
+}
+
+// muxFrameKind is the kind of a multiplexing protocol frame.
+type muxFrameKind uint8
+
+const (
+	muxFrameOpen  muxFrameKind = 1
+	muxFrameData  muxFrameKind = 2
+	muxFrameClose muxFrameKind = 3
+)
+
+// muxFrame is a multiplexing protocol frame.
+type muxFrame struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Kind      muxFrameKind     `json:"kind"`
+	Data      []byte           `json:"data,omitempty"`
+}
+
+type muxChannel struct {
+	mux *muxConnection
+
+	runtimeID common.Namespace
+
+	// local is the side of the channel returned to the user, remote is the side
+	// used by the channel workers.
+	local  net.Conn
+	remote net.Conn
+
+	inCh      chan []byte
+	closedCh  chan struct{}
+	closeOnce sync.Once
+}
+
+// workerOutgoing sends data written to the channel to the other side.
+func (ch *muxChannel) workerOutgoing() {
+	defer ch.mux.quitWg.Done()
+
+	buf := make([]byte, muxReadBufferSize)
+	for {
+		n, err := ch.remote.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+
+			if werr := ch.mux.writeFrame(&muxFrame{
+				RuntimeID: ch.runtimeID,
+				Kind:      muxFrameData,
+				Data:      data,
+			}); werr != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	// The channel has been closed locally, notify the other side.
+	ch.mux.removeChannel(ch, true)
+}
+
+// workerIncoming passes data received from the other side to the channel.
+func (ch *muxChannel) workerIncoming() {
+	defer ch.mux.quitWg.Done()
+
+	for {
+		select {
+		case data := <-ch.inCh:
+			_, _ = ch.remote.Write(data)
+		case <-ch.closedCh:
+			// Deliver any data that was received before the channel was closed.
+			for {
+				select {
+				case data := <-ch.inCh:
+					_, _ = ch.remote.Write(data)
+				default:
+					_ = ch.remote.Close()
+					return
+				}
+			}
+		}
+	}
+}
+
+type muxConnection struct {
+	sync.Mutex
+
+	conn  net.Conn
+	codec *cbor.MessageCodec
+
+	writeLock sync.Mutex
+
+	closed   bool
+	channels map[common.Namespace]*muxChannel
+
+	acceptCh chan *muxChannel
+	closeCh  chan struct{}
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	quitWg   sync.WaitGroup
+
+	logger *logging.Logger
+}
+
+func (m *muxConnection) writeFrame(frame *muxFrame) error {
+	m.writeLock.Lock()
+	defer m.writeLock.Unlock()
+
+	return m.codec.Write(frame)
+}
+
+func (m *muxConnection) newChannelLocked(runtimeID common.Namespace) *muxChannel {
+	local, remote := net.Pipe()
+	ch := &muxChannel{
+		mux:       m,
+		runtimeID: runtimeID,
+		local:     local,
+		remote:    remote,
+		inCh:      make(chan []byte, muxChannelBacklog),
+		closedCh:  make(chan struct{}),
+	}
+	m.channels[runtimeID] = ch
+
+	m.quitWg.Add(2)
+	go ch.workerOutgoing()
+	go ch.workerIncoming()
+
+	return ch
+}
+
+func (m *muxConnection) removeChannel(ch *muxChannel, notifyRemote bool) {
+	ch.closeOnce.Do(func() {
+		m.Lock()
+		if m.channels[ch.runtimeID] == ch {
+			delete(m.channels, ch.runtimeID)
+		}
+		m.Unlock()
+
+		close(ch.closedCh)
+
+		if notifyRemote {
+			_ = m.writeFrame(&muxFrame{
+				RuntimeID: ch.runtimeID,
+				Kind:      muxFrameClose,
+			})
+		}
+	})
+}
+
+// Implements MuxConnection.
+func (m *muxConnection) Open(ctx context.Context, runtimeID common.Namespace) (net.Conn, error) {
+	m.Lock()
+	if m.closed {
+		m.Unlock()
+		return nil, ErrMuxClosed
+	}
+	if _, exists := m.channels[runtimeID]; exists {
+		m.Unlock()
+		return nil, ErrChannelExists
+	}
+	ch := m.newChannelLocked(runtimeID)
+	m.Unlock()
+
+	if err := m.writeFrame(&muxFrame{
+		RuntimeID: runtimeID,
+		Kind:      muxFrameOpen,
+	}); err != nil {
+		m.removeChannel(ch, false)
+		return nil, fmt.Errorf("rhp: failed to open channel: %w", err)
+	}
+
+	return ch.local, nil
+}
+
+// Implements MuxConnection.
+func (m *muxConnection) Accept(ctx context.Context) (common.Namespace, net.Conn, error) {
+	select {
+	case ch := <-m.acceptCh:
+		return ch.runtimeID, ch.local, nil
+	case <-m.closeCh:
+		return common.Namespace{}, nil, ErrMuxClosed
+	case <-ctx.Done():
+		return common.Namespace{}, nil, ctx.Err()
+	}
+}
+
+// Implements MuxConnection.
+func (m *muxConnection) Close() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+
+		if err := m.conn.Close(); err != nil {
+			m.logger.Error("error while closing connection",
+				"err", err,
+			)
+		}
+	})
+
+	// Wait for all the connection-handling goroutines to terminate.
+	m.quitWg.Wait()
+}
+
+func (m *muxConnection) handleFrame(frame *muxFrame) {
+	m.Lock()
+	ch := m.channels[frame.RuntimeID]
+	m.Unlock()
+
+	switch frame.Kind {
+	case muxFrameOpen:
+		if ch != nil {
+			m.logger.Warn("received open for an already open channel, ignoring",
+				"runtime_id", frame.RuntimeID,
+			)
+			return
+		}
+
+		m.Lock()
+		if m.closed {
+			m.Unlock()
+			return
+		}
+		ch = m.newChannelLocked(frame.RuntimeID)
+		m.Unlock()
+
+		select {
+		case m.acceptCh <- ch:
+		case <-m.stopCh:
+		}
+	case muxFrameData:
+		if ch == nil {
+			m.logger.Warn("received data for an unknown channel, ignoring",
+				"runtime_id", frame.RuntimeID,
+			)
+			return
+		}
+
+		select {
+		case ch.inCh <- frame.Data:
+		case <-ch.closedCh:
+		case <-m.stopCh:
+		}
+	case muxFrameClose:
+		if ch == nil {
+			return
+		}
+		m.removeChannel(ch, false)
+	default:
+		m.logger.Warn("received a malformed frame, ignoring",
+			"kind", frame.Kind,
+			"runtime_id", frame.RuntimeID,
+		)
+	}
+}
+
+func (m *muxConnection) workerIncoming() {
+	defer func() {
+		// Close connection and signal that connection is closed.
+		_ = m.conn.Close()
+
+		m.Lock()
+		m.closed = true
+		channels := make([]*muxChannel, 0, len(m.channels))
+		for _, ch := range m.channels {
+			channels = append(channels, ch)
+		}
+		m.Unlock()
+
+		close(m.closeCh)
+
+		// Close all channels, including the local sides so that any pending
+		// channel operations get aborted.
+		for _, ch := range channels {
+			m.removeChannel(ch, false)
+			_ = ch.local.Close()
+		}
+
+		m.quitWg.Done()
+	}()
+
+	for {
+		var frame muxFrame
+		if err := m.codec.Read(&frame); err != nil {
+			m.logger.Debug("error while receiving frame",
+				"err", err,
+			)
+			return
+		}
+
+		m.handleFrame(&frame)
+	}
+}
+
+// NewMuxConnection creates a new multiplexed connection over the given
+// underlying connection.
+//
+// Both sides of the underlying connection must use a MuxConnection.
+func NewMuxConnection(logger *logging.Logger, conn net.Conn) (MuxConnection, error) {
+	m := &muxConnection{
+		conn:     conn,
+		codec:    cbor.NewMessageCodec(conn, muxModuleName),
+		channels: make(map[common.Namespace]*muxChannel),
+		acceptCh: make(chan *muxChannel, muxAcceptBacklog),
+		closeCh:  make(chan struct{}),
+		stopCh:   make(chan struct{}),
+		logger:   logger,
+	}
+
+	m.quitWg.Add(1)
+	go m.workerIncoming()
+
+	return m, nil
+}
@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+)
+
+func TestMuxConnection(t *testing.T) {
+	require := require.New(t)
+	logger := logging.GetLogger("test")
+	ctx := context.Background()
+
+	connA, connB := net.Pipe()
+	muxA, err := NewMuxConnection(logger, connA)
+	require.NoError(err, "NewMuxConnection")
+	muxB, err := NewMuxConnection(logger, connB)
+	require.NoError(err, "NewMuxConnection")
+
+	runtimeIDs := []common.Namespace{
+		common.NewTestNamespaceFromSeed([]byte("test mux conn 1"), 0),
+		common.NewTestNamespaceFromSeed([]byte("test mux conn 2"), 0),
+	}
+
+	var (
+		guests       []Connection
+		hosts        []Connection
+		guestHandler []*testHandler
+		hostHandler  []*testHandler
+	)
+	for _, runtimeID := range runtimeIDs {
+		hostConn, err := muxB.Open(ctx, runtimeID)
+		require.NoError(err, "Open")
+
+		_, err = muxB.Open(ctx, runtimeID)
+		require.Error(err, "Open should fail for an already open channel")
+
+		acceptedID, guestConn, err := muxA.Accept(ctx)
+		require.NoError(err, "Accept")
+		require.Equal(runtimeID, acceptedID, "accepted runtime ID should be correct")
+
+		gh := &testHandler{}
+		guest, err := NewConnection(logger, runtimeID, gh)
+		require.NoError(err, "NewConnection")
+		err = guest.InitGuest(ctx, guestConn)
+		require.NoError(err, "InitGuest")
+
+		hh := &testHandler{}
+		host, err := NewConnection(logger, runtimeID, hh)
+		require.NoError(err, "NewConnection")
+		_, err = host.InitHost(ctx, hostConn)
+		require.NoError(err, "InitHost")
+
+		guests = append(guests, guest)
+		hosts = append(hosts, host)
+		guestHandler = append(guestHandler, gh)
+		hostHandler = append(hostHandler, hh)
+	}
+
+	// Calls on each channel should only reach the corresponding runtime.
+	req := Body{Empty: &Empty{}}
+	resp, err := hosts[0].Call(ctx, PriorityNormal, &req)
+	require.NoError(err, "Call")
+	require.EqualValues(&req, resp, "Call")
+	require.EqualValues(1, guestHandler[0].calls, "handler of the first runtime must be called")
+	require.EqualValues(0, guestHandler[1].calls, "handler of the second runtime must not be called")
+
+	resp, err = guests[1].Call(ctx, PriorityNormal, &req)
+	require.NoError(err, "Call")
+	require.EqualValues(&req, resp, "Call")
+	require.EqualValues(1, hostHandler[1].calls, "host handler of the second runtime must be called")
+	require.EqualValues(0, hostHandler[0].calls, "host handler of the first runtime must not be called")
+
+	// Closing a channel should not affect other channels.
+	hosts[0].Close()
+	guests[0].Close()
+
+	resp, err = hosts[1].Call(ctx, PriorityNormal, &req)
+	require.NoError(err, "Call")
+	require.EqualValues(&req, resp, "Call")
+
+	// Closing the multiplexed connection should close all channels.
+	muxA.Close()
+	muxB.Close()
+
+	_, err = hosts[1].Call(ctx, PriorityNormal, &req)
+	require.Error(err, "Call must error when the multiplexed connection is closed")
+	_, _, err = muxA.Accept(ctx)
+	require.Error(err, "Accept must error when the multiplexed connection is closed")
+	_, err = muxB.Open(ctx, runtimeIDs[0])
+	require.Error(err, "Open must error when the multiplexed connection is closed")
+
+	hosts[1].Close()
+	guests[1].Close()
+}
@@ -0,0 +1,174 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/version"
+)
+
+// AcceptFunc accepts an additional connection opened by the runtime.
+type AcceptFunc func(ctx context.Context) (net.Conn, error)
+
+type pooledConnection struct {
+	*connection
+
+	inFlight uint64
+}
+
+// connectionPool is a Runtime Host Protocol connection that spreads requests
+// over multiple underlying connections opened by the runtime, so that
+// long-running requests do not block other requests.
+type connectionPool struct {
+	sync.Mutex
+
+	runtimeID      common.Namespace
+	handler        Handler
+	maxConnections uint16
+	accept         AcceptFunc
+
+	primary *connection
+	conns   []*pooledConnection
+
+	logger *logging.Logger
+}
+
+// Implements Connection.
+func (p *connectionPool) Close() {
+	p.Lock()
+	conns := p.conns
+	p.conns = nil
+	p.Unlock()
+
+	p.primary.Close()
+	for _, pc := range conns {
+		if pc.connection != p.primary {
+			pc.Close()
+		}
+	}
+}
+
+// Implements Connection.
+func (p *connectionPool) Call(ctx context.Context, priority Priority, body *Body) (*Body, error) {
+	// Pick the connection with the least requests in flight.
+	p.Lock()
+	if len(p.conns) == 0 {
+		p.Unlock()
+		return nil, ErrNotReady
+	}
+	pc := p.conns[0]
+	for _, c := range p.conns[1:] {
+		if c.inFlight < pc.inFlight {
+			pc = c
+		}
+	}
+	pc.inFlight++
+	p.Unlock()
+
+	defer func() {
+		p.Lock()
+		pc.inFlight--
+		p.Unlock()
+	}()
+
+	return pc.Call(ctx, priority, body)
+}
+
+// Implements Connection.
+func (p *connectionPool) InitHost(ctx context.Context, conn net.Conn) (*version.Version, error) {
+	info, err := p.primary.initHost(ctx, conn, p.maxConnections)
+	if err != nil {
+		return nil, err
+	}
+
+	numConns := info.Connections
+	if numConns == 0 {
+		numConns = 1
+	}
+	if numConns > p.maxConnections && numConns > 1 {
+		p.logger.Error("runtime opened too many connections",
+			"connections", numConns,
+			"max_connections", p.maxConnections,
+		)
+		return nil, fmt.Errorf("rhp: too many connections (max: %d got: %d)", p.maxConnections, numConns)
+	}
+
+	conns := []*pooledConnection{{connection: p.primary}}
+	closeAll := func() {
+		for _, pc := range conns[1:] {
+			pc.Close()
+		}
+	}
+	for i := uint16(1); i < numConns; i++ {
+		nc, err := p.accept(ctx)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("rhp: failed to accept additional connection: %w", err)
+		}
+
+		c := newConnection(p.logger.With("conn", i), p.runtimeID, p.handler)
+		// Additional connections are bound to the runtime by the initial handshake and
+		// require no handshake of their own.
+		if err = c.InitGuest(ctx, nc); err != nil {
+			closeAll()
+			return nil, err
+		}
+		conns = append(conns, &pooledConnection{connection: c})
+	}
+
+	p.logger.Info("runtime host protocol connections established",
+		"connections", numConns,
+	)
+
+	p.Lock()
+	p.conns = conns
+	p.Unlock()
+
+	rtVersion := version.FromU64(info.RuntimeVersion)
+	return &rtVersion, nil
+}
+
+// Implements Connection.
+func (p *connectionPool) InitGuest(ctx context.Context, conn net.Conn) error {
+	if err := p.primary.InitGuest(ctx, conn); err != nil {
+		return err
+	}
+
+	p.Lock()
+	p.conns = []*pooledConnection{{connection: p.primary}}
+	p.Unlock()
+
+	return nil
+}
+
+// NewConnectionPool creates a new uninitialized RHP connection that allows the runtime to open
+// up to maxConnections connections in total. Requests are load-balanced over all of the open
+// connections.
+//
+// The number of connections is negotiated during host initialization, after which the
+// additional connections are obtained via the given accept function. In guest mode only a
+// single connection is used.
+func NewConnectionPool(
+	logger *logging.Logger,
+	runtimeID common.Namespace,
+	handler Handler,
+	maxConnections uint16,
+	accept AcceptFunc,
+) (Connection, error) {
+	if maxConnections > 1 && accept == nil {
+		return nil, fmt.Errorf("rhp: accept function required for multiple connections")
+	}
+
+	return &connectionPool{
+		runtimeID:      runtimeID,
+		handler:        handler,
+		maxConnections: maxConnections,
+		accept:         accept,
+		primary:        newConnection(logger, runtimeID, handler),
+		logger:         logger,
+	}, nil
+}
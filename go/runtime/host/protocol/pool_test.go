@@ -0,0 +1,144 @@
+package protocol
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/version"
+)
+
+// poolGuestHandler is the guest handler of a single connection in a pool.
+type poolGuestHandler struct {
+	index int
+
+	// connections is the number of connections to report on handshake.
+	connections uint16
+	// open opens an additional connection.
+	open func(index int)
+
+	startedCh chan int
+	releaseCh chan struct{}
+}
+
+// Implements Handler.
+func (h *poolGuestHandler) Handle(ctx context.Context, body *Body) (*Body, error) {
+	switch {
+	case body.RuntimeInfoRequest != nil:
+		for i := 1; i < int(h.connections); i++ {
+			h.open(i)
+		}
+		return &Body{
+			RuntimeInfoResponse: &RuntimeInfoResponse{
+				ProtocolVersion: version.RuntimeProtocol.ToU64(),
+				Connections:     h.connections,
+			},
+		}, nil
+	case body.RuntimePingRequest != nil:
+		h.startedCh <- h.index
+		<-h.releaseCh
+		return &Body{Empty: &Empty{}}, nil
+	default:
+		return body, nil
+	}
+}
+
+func TestConnectionPool(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn pool"), 0)
+	logger := logging.GetLogger("test")
+
+	startedCh := make(chan int)
+	releaseCh := make(chan struct{})
+	acceptCh := make(chan net.Conn, 3)
+	var guests []Connection
+	var newGuestHandler func(index int, connections uint16) *poolGuestHandler
+	newGuestHandler = func(index int, connections uint16) *poolGuestHandler {
+		h := &poolGuestHandler{
+			index:       index,
+			connections: connections,
+			startedCh:   startedCh,
+			releaseCh:   releaseCh,
+		}
+		h.open = func(index int) {
+			guestConn, hostConn := net.Pipe()
+			guest, err := NewConnection(logger, runtimeID, newGuestHandler(index, 0))
+			require.NoError(err, "NewConnection")
+			require.NoError(guest.InitGuest(context.Background(), guestConn), "InitGuest")
+			guests = append(guests, guest)
+			acceptCh <- hostConn
+		}
+		return h
+	}
+	accept := func(ctx context.Context) (net.Conn, error) {
+		select {
+		case conn := <-acceptCh:
+			return conn, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	guestConn, hostConn := net.Pipe()
+	guest, err := NewConnection(logger, runtimeID, newGuestHandler(0, 3))
+	require.NoError(err, "NewConnection")
+	require.NoError(guest.InitGuest(context.Background(), guestConn), "InitGuest")
+	guests = append(guests, guest)
+
+	host, err := NewConnectionPool(logger, runtimeID, &testHandler{}, 3, accept)
+	require.NoError(err, "NewConnectionPool")
+	_, err = host.InitHost(context.Background(), hostConn)
+	require.NoError(err, "InitHost")
+	require.Len(guests, 3, "runtime should open all connections")
+
+	// Concurrent requests should be spread over all connections.
+	errCh := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, cerr := host.Call(context.Background(), PriorityNormal, &Body{RuntimePingRequest: &Empty{}})
+			errCh <- cerr
+		}()
+	}
+	seen := make(map[int]bool)
+	for i := 0; i < 3; i++ {
+		select {
+		case index := <-startedCh:
+			seen[index] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("failed to receive request")
+		}
+	}
+	require.Len(seen, 3, "requests should be spread over all connections")
+
+	close(releaseCh)
+	for i := 0; i < 3; i++ {
+		require.NoError(<-errCh, "Call")
+	}
+
+	host.Close()
+	for _, g := range guests {
+		g.Close()
+	}
+
+	// Runtimes must not open more connections than allowed.
+	guestConn, hostConn = net.Pipe()
+	guest, err = NewConnection(logger, runtimeID, &poolGuestHandler{
+		connections: 2,
+		open:        func(int) {},
+	})
+	require.NoError(err, "NewConnection")
+	require.NoError(guest.InitGuest(context.Background(), guestConn), "InitGuest")
+
+	host, err = NewConnectionPool(logger, runtimeID, &testHandler{}, 1, accept)
+	require.NoError(err, "NewConnectionPool")
+	_, err = host.InitHost(context.Background(), hostConn)
+	require.Error(err, "InitHost should fail when too many connections are opened")
+
+	host.Close()
+	guest.Close()
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/sgx/ias"
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api/block"
 	"github.com/oasislabs/oasis-core/go/roothash/api/commitment"
@@ -27,6 +28,8 @@ func (m MessageType) String() string {
 		return "request"
 	case MessageResponse:
 		return "response"
+	case MessageCancel:
+		return "cancel"
 	default:
 		return fmt.Sprintf("[malformed: %d]", m)
 	}
@@ -41,12 +44,41 @@ const (
 
 	// Response message.
 	MessageResponse MessageType = 2
+
+	// Cancel message, requesting the other side to abort processing of the
+	// request with the same identifier.
+	MessageCancel MessageType = 3
+)
+
+// Priority is a request priority class.
+type Priority uint8
+
+// String returns a string representation of a request priority class.
+func (p Priority) String() string {
+	switch p {
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("[malformed: %d]", p)
+	}
+}
+
+const (
+	// PriorityNormal is the priority class of regular requests.
+	PriorityNormal Priority = 0
+
+	// PriorityHigh is the priority class of requests that should be sent
+	// (and processed) ahead of any regular requests, e.g., aborts.
+	PriorityHigh Priority = 1
 )
 
 // Message is a protocol message.
 type Message struct {
 	ID          uint64      `json:"id"`
 	MessageType MessageType `json:"message_type"`
+	Priority    Priority    `json:"priority,omitempty"`
 	Body        Body        `json:"body"`
 	SpanContext []byte      `json:"span_context"`
 }
@@ -89,6 +121,8 @@ type Body struct {
 	HostLocalStorageGetResponse  *HostLocalStorageGetResponse  `json:",omitempty"`
 	HostLocalStorageSetRequest   *HostLocalStorageSetRequest   `json:",omitempty"`
 	HostLocalStorageSetResponse  *Empty                        `json:",omitempty"`
+	HostLogRequest               *HostLogRequest               `json:",omitempty"`
+	HostLogResponse              *Empty                        `json:",omitempty"`
 }
 
 // Type returns the message type by determining the name of the first non-nil member.
@@ -117,6 +151,11 @@ type Error struct {
 type RuntimeInfoRequest struct {
 	// RuntimeID is the assigned runtime ID of the loaded runtime.
 	RuntimeID common.Namespace `json:"runtime_id"`
+
+	// MaxConnections is the maximum number of connections, including the
+	// current one, that the runtime may open to the host. Zero means that
+	// only a single connection is allowed.
+	MaxConnections uint16 `json:"max_connections,omitempty"`
 }
 
 // RuntimeInfoResponse is a worker info response message body.
@@ -126,6 +165,11 @@ type RuntimeInfoResponse struct {
 
 	// RuntimeVersion is the version of the runtime.
 	RuntimeVersion uint64 `json:"runtime_version"`
+
+	// Connections is the number of connections, including the current one,
+	// that the runtime has opened to the host. Zero means that only a single
+	// connection is used.
+	Connections uint16 `json:"connections,omitempty"`
 }
 
 // RuntimeCapabilityTEERakInitRequest is a worker RFC 0009 CapabilityTEE
@@ -273,3 +317,15 @@ type HostLocalStorageSetRequest struct {
 	Key   []byte `json:"key"`
 	Value []byte `json:"value"`
 }
+
+// HostLogRequest is a host log request message body.
+type HostLogRequest struct {
+	// Level is the log level of the record.
+	Level logging.Level `json:"level"`
+	// Module is the runtime module that emitted the record.
+	Module string `json:"module"`
+	// Message is the log message.
+	Message string `json:"message"`
+	// Fields are optional structured key/value pairs attached to the record.
+	Fields map[string]string `json:"fields,omitempty"`
+}
@@ -168,6 +168,9 @@ func NewBubbleWrap(cfg Config) (Process, error) {
 		Args:   cliArgs,
 		Stdout: cfg.Stdout,
 		Stderr: cfg.Stderr,
+		// The sandbox only starts the binary after receiving the arguments below, so limits are
+		// always applied before the binary is executed.
+		Limits: cfg.Limits,
 		// Pass all the pipe file descriptors.
 		// NOTE: Entry i becomes file descriptor 3+i.
 		extraFiles: fdPipes.pipes,
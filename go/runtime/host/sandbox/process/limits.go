@@ -0,0 +1,54 @@
+package process
+
+import "fmt"
+
+// ResourceMemory is the name of the memory resource.
+const ResourceMemory = "memory"
+
+// Limits are the resource limits of a sandboxed process.
+//
+// On Linux, limits are enforced using cgroups if possible. In case cgroups are not available, the
+// memory limit falls back to limiting the address space via resource limits, but CPU limits can
+// not be enforced.
+type Limits struct {
+	// CPUPercent is the maximum CPU usage as a percentage of a single CPU (e.g., 150 means one and
+	// a half CPUs). Zero means no limit.
+	CPUPercent uint64
+
+	// Memory is the maximum amount of memory in bytes. Zero means no limit.
+	Memory uint64
+}
+
+// IsEmpty returns true iff no limits are set.
+func (l *Limits) IsEmpty() bool {
+	return l.CPUPercent == 0 && l.Memory == 0
+}
+
+// LimitExceededError is the termination error of a process that has been killed due to exceeding
+// its resource limits.
+type LimitExceededError struct {
+	// Resource is the resource whose limit has been exceeded.
+	Resource string
+
+	// Err is the original termination error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("process exceeded its %s limit: %s", e.Resource, e.Err)
+}
+
+// Unwrap returns the original termination error.
+func (e *LimitExceededError) Unwrap() error {
+	return e.Err
+}
+
+// limiter enforces resource limits on a running process.
+type limiter interface {
+	// exceeded returns the resource whose limit has been exceeded, if any.
+	exceeded() string
+
+	// close releases any resources held by the limiter after the process has terminated.
+	close()
+}
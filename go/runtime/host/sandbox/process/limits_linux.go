@@ -0,0 +1,194 @@
+// +build linux
+
+package process
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const cgroupCPUPeriod = 100_000
+
+// cgroupRoot is the mount point of the cgroup filesystem.
+var cgroupRoot = "/sys/fs/cgroup"
+
+type cgroupLimiter struct {
+	// dirs are the cgroup directories created for the process.
+	dirs []string
+	// memoryDir is the cgroup directory containing the memory controller files.
+	memoryDir string
+	// v2 is true iff the unified (v2) cgroup hierarchy is used.
+	v2 bool
+}
+
+func (l *cgroupLimiter) exceeded() string {
+	if l.memoryDir == "" {
+		return ""
+	}
+
+	// Both memory.events (v2) and memory.oom_control (v1) report the number of processes killed
+	// by the OOM killer as "oom_kill <count>".
+	fn := "memory.oom_control"
+	if l.v2 {
+		fn = "memory.events"
+	}
+	f, err := os.Open(filepath.Join(l.memoryDir, fn))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+		if n, _ := strconv.ParseUint(fields[1], 10, 64); n > 0 {
+			return ResourceMemory
+		}
+	}
+	return ""
+}
+
+func (l *cgroupLimiter) close() {
+	for _, dir := range l.dirs {
+		_ = os.Remove(dir)
+	}
+}
+
+func (l *cgroupLimiter) createDir(dir string) error {
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+	l.dirs = append(l.dirs, dir)
+	return nil
+}
+
+func writeCgroupFile(dir, fn, value string) error {
+	return ioutil.WriteFile(filepath.Join(dir, fn), []byte(value), 0644)
+}
+
+func newCgroupLimiter(pid int, limits Limits) (*cgroupLimiter, error) {
+	name := fmt.Sprintf("oasis-runtime-%d", pid)
+	pidStr := strconv.Itoa(pid)
+	cpuQuota := strconv.FormatUint(limits.CPUPercent*cgroupCPUPeriod/100, 10)
+
+	l := &cgroupLimiter{}
+	var ok bool
+	defer func() {
+		// Make sure any created cgroups are removed in case of errors.
+		if !ok {
+			l.close()
+		}
+	}()
+
+	var err error
+	if _, err = os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		// Unified (v2) hierarchy.
+		l.v2 = true
+		dir := filepath.Join(cgroupRoot, name)
+		if err = l.createDir(dir); err != nil {
+			return nil, err
+		}
+		if limits.Memory > 0 {
+			if err = writeCgroupFile(dir, "memory.max", strconv.FormatUint(limits.Memory, 10)); err != nil {
+				return nil, err
+			}
+			l.memoryDir = dir
+		}
+		if limits.CPUPercent > 0 {
+			if err = writeCgroupFile(dir, "cpu.max", fmt.Sprintf("%s %d", cpuQuota, cgroupCPUPeriod)); err != nil {
+				return nil, err
+			}
+		}
+		if err = writeCgroupFile(dir, "cgroup.procs", pidStr); err != nil {
+			return nil, err
+		}
+		ok = true
+		return l, nil
+	}
+
+	// Legacy (v1) hierarchy with a separate hierarchy per controller.
+	if limits.Memory > 0 {
+		dir := filepath.Join(cgroupRoot, "memory", name)
+		if err = l.createDir(dir); err != nil {
+			return nil, err
+		}
+		if err = writeCgroupFile(dir, "memory.limit_in_bytes", strconv.FormatUint(limits.Memory, 10)); err != nil {
+			return nil, err
+		}
+		if err = writeCgroupFile(dir, "cgroup.procs", pidStr); err != nil {
+			return nil, err
+		}
+		l.memoryDir = dir
+	}
+	if limits.CPUPercent > 0 {
+		dir := filepath.Join(cgroupRoot, "cpu", name)
+		if err = l.createDir(dir); err != nil {
+			return nil, err
+		}
+		if err = writeCgroupFile(dir, "cpu.cfs_period_us", strconv.Itoa(cgroupCPUPeriod)); err != nil {
+			return nil, err
+		}
+		if err = writeCgroupFile(dir, "cpu.cfs_quota_us", cpuQuota); err != nil {
+			return nil, err
+		}
+		if err = writeCgroupFile(dir, "cgroup.procs", pidStr); err != nil {
+			return nil, err
+		}
+	}
+	ok = true
+	return l, nil
+}
+
+// rlimitLimiter limits the process via resource limits. Processes exceeding the limits fail to
+// allocate memory instead of being killed, so exceeding the limits can not be detected.
+type rlimitLimiter struct{}
+
+func (l *rlimitLimiter) exceeded() string {
+	return ""
+}
+
+func (l *rlimitLimiter) close() {
+}
+
+func prlimit(pid int, resource int, limit *syscall.Rlimit) error {
+	_, _, errno := syscall.RawSyscall6(
+		syscall.SYS_PRLIMIT64,
+		uintptr(pid),
+		uintptr(resource),
+		uintptr(unsafe.Pointer(limit)), // nolint: gosec
+		0,
+		0,
+		0,
+	)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func applyLimits(pid int, limits Limits) (limiter, error) {
+	l, err := newCgroupLimiter(pid, limits)
+	if err == nil {
+		return l, nil
+	}
+
+	// Fall back to resource limits, which can only limit memory.
+	if limits.CPUPercent > 0 {
+		return nil, fmt.Errorf("failed to create cgroup required for CPU limits: %w", err)
+	}
+	rlimit := syscall.Rlimit{Cur: limits.Memory, Max: limits.Memory}
+	if err = prlimit(pid, syscall.RLIMIT_AS, &rlimit); err != nil {
+		return nil, fmt.Errorf("failed to set memory resource limit: %w", err)
+	}
+	return &rlimitLimiter{}, nil
+}
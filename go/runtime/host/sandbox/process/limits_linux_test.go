@@ -0,0 +1,130 @@
+// +build linux
+
+package process
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func withCgroupRoot(root string) func() {
+	oldRoot := cgroupRoot
+	cgroupRoot = root
+	return func() {
+		cgroupRoot = oldRoot
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err, "ReadFile")
+	return string(data)
+}
+
+func TestCgroupLimiter(t *testing.T) {
+	limits := Limits{
+		CPUPercent: 150,
+		Memory:     64 << 20,
+	}
+
+	t.Run("V2", func(t *testing.T) {
+		require := require.New(t)
+
+		root, err := ioutil.TempDir("", "oasis-runtime-host-sandbox-test_")
+		require.NoError(err, "TempDir")
+		defer os.RemoveAll(root)
+		defer withCgroupRoot(root)()
+		require.NoError(ioutil.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory"), 0644))
+
+		l, err := newCgroupLimiter(42, limits)
+		require.NoError(err, "newCgroupLimiter")
+		dir := filepath.Join(root, "oasis-runtime-42")
+		require.Equal("67108864", readFile(t, filepath.Join(dir, "memory.max")))
+		require.Equal("150000 100000", readFile(t, filepath.Join(dir, "cpu.max")))
+		require.Equal("42", readFile(t, filepath.Join(dir, "cgroup.procs")))
+
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 0\n"), 0644))
+		require.Empty(l.exceeded(), "limits should not be exceeded without OOM kills")
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0644))
+		require.Equal(ResourceMemory, l.exceeded(), "memory limit should be exceeded after an OOM kill")
+	})
+
+	t.Run("V1", func(t *testing.T) {
+		require := require.New(t)
+
+		root, err := ioutil.TempDir("", "oasis-runtime-host-sandbox-test_")
+		require.NoError(err, "TempDir")
+		defer os.RemoveAll(root)
+		defer withCgroupRoot(root)()
+		require.NoError(os.Mkdir(filepath.Join(root, "memory"), 0755))
+		require.NoError(os.Mkdir(filepath.Join(root, "cpu"), 0755))
+
+		l, err := newCgroupLimiter(42, limits)
+		require.NoError(err, "newCgroupLimiter")
+		memDir := filepath.Join(root, "memory", "oasis-runtime-42")
+		require.Equal("67108864", readFile(t, filepath.Join(memDir, "memory.limit_in_bytes")))
+		require.Equal("42", readFile(t, filepath.Join(memDir, "cgroup.procs")))
+		cpuDir := filepath.Join(root, "cpu", "oasis-runtime-42")
+		require.Equal("100000", readFile(t, filepath.Join(cpuDir, "cpu.cfs_period_us")))
+		require.Equal("150000", readFile(t, filepath.Join(cpuDir, "cpu.cfs_quota_us")))
+		require.Equal("42", readFile(t, filepath.Join(cpuDir, "cgroup.procs")))
+
+		require.NoError(ioutil.WriteFile(filepath.Join(memDir, "memory.oom_control"), []byte("oom_kill_disable 0\nunder_oom 0\noom_kill 2\n"), 0644))
+		require.Equal(ResourceMemory, l.exceeded(), "memory limit should be exceeded after an OOM kill")
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		require := require.New(t)
+
+		root, err := ioutil.TempDir("", "oasis-runtime-host-sandbox-test_")
+		require.NoError(err, "TempDir")
+		defer os.RemoveAll(root)
+		defer withCgroupRoot(root)()
+
+		_, err = newCgroupLimiter(42, limits)
+		require.Error(err, "newCgroupLimiter should fail without cgroup hierarchies")
+		require.NoDirExists(filepath.Join(root, "oasis-runtime-42"), "partially created cgroups should be removed")
+	})
+}
+
+func TestNakedLimitsFallback(t *testing.T) {
+	require := require.New(t)
+
+	// Make sure cgroups are not available so resource limits are used.
+	defer withCgroupRoot(filepath.Join(os.TempDir(), "oasis-runtime-host-sandbox-test-nonexistent"))()
+
+	const memoryLimit = 1 << 30
+	p, err := NewNaked(Config{
+		Path:   "/bin/sleep",
+		Args:   []string{"10"},
+		Limits: Limits{Memory: memoryLimit},
+	})
+	require.NoError(err, "NewNaked")
+	defer p.Kill()
+
+	limits := readFile(t, fmt.Sprintf("/proc/%d/limits", p.GetPID()))
+	var found bool
+	for _, line := range strings.Split(limits, "\n") {
+		if !strings.HasPrefix(line, "Max address space") {
+			continue
+		}
+		fields := strings.Fields(line)
+		require.Equal(fmt.Sprintf("%d", memoryLimit), fields[3], "soft limit should be set")
+		require.Equal(fmt.Sprintf("%d", memoryLimit), fields[4], "hard limit should be set")
+		found = true
+	}
+	require.True(found, "address space limit should be present")
+
+	_, err = NewNaked(Config{
+		Path:   "/bin/sleep",
+		Args:   []string{"10"},
+		Limits: Limits{CPUPercent: 50},
+	})
+	require.Error(err, "CPU limits should require cgroups")
+}
@@ -0,0 +1,9 @@
+// +build !linux
+
+package process
+
+import "fmt"
+
+func applyLimits(pid int, limits Limits) (limiter, error) {
+	return nil, fmt.Errorf("resource limits are not supported on this platform")
+}
@@ -13,7 +13,8 @@ import (
 type naked struct {
 	sync.Mutex
 
-	cmd *exec.Cmd
+	cmd     *exec.Cmd
+	limiter limiter
 
 	err    error
 	waitCh chan struct{}
@@ -116,8 +117,26 @@ func NewNaked(cfg Config) (Process, error) {
 		cmd:    cmd,
 		waitCh: make(chan struct{}),
 	}
+
+	// Apply any resource limits. Note that the process is already running at this point.
+	if !cfg.Limits.IsEmpty() {
+		l, err := applyLimits(cmd.Process.Pid, cfg.Limits)
+		if err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return nil, fmt.Errorf("failed to apply resource limits: %w", err)
+		}
+		n.limiter = l
+	}
+
 	go func() {
 		err := n.wait()
+		if n.limiter != nil {
+			if resource := n.limiter.exceeded(); resource != "" {
+				err = &LimitExceededError{Resource: resource, Err: err}
+			}
+			n.limiter.close()
+		}
 
 		n.Lock()
 		n.err = err
@@ -39,6 +39,9 @@ type Config struct {
 	// process' os.Stderr will be used.
 	Stderr io.Writer
 
+	// Limits are the resource limits of the process.
+	Limits Limits
+
 	extraFiles []*os.File
 }
 
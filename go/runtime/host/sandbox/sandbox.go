@@ -3,6 +3,7 @@ package sandbox
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -49,6 +50,10 @@ type Config struct {
 
 	// InsecureNoSandbox disables the sandbox and runs the runtime binary directly.
 	InsecureNoSandbox bool
+
+	// MaxConnections is the maximum number of Runtime Host Protocol connections that a runtime may
+	// open to the host. Zero means that only a single connection is used.
+	MaxConnections uint16
 }
 
 type provisioner struct {
@@ -106,7 +111,7 @@ func (r *sandboxedRuntime) Call(ctx context.Context, body *protocol.Body) (*prot
 	if conn == nil {
 		return nil, fmt.Errorf("runtime is not ready")
 	}
-	return r.conn.Call(ctx, body)
+	return r.conn.Call(ctx, protocol.PriorityNormal, body)
 }
 
 // Implements host.Runtime.
@@ -162,6 +167,13 @@ func (r *sandboxedRuntime) EmitEvent(ev *host.Event) {
 	r.notifier.Broadcast(ev)
 }
 
+func (r *sandboxedRuntime) getProcessLimits() process.Limits {
+	return process.Limits{
+		CPUPercent: r.rtCfg.Limits.CPUPercent,
+		Memory:     r.rtCfg.Limits.Memory,
+	}
+}
+
 func (r *sandboxedRuntime) startProcess() (err error) {
 	// Create a temporary directory.
 	runtimeDir, err := ioutil.TempDir("", "oasis-runtime")
@@ -179,8 +191,8 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		return fmt.Errorf("failed to create host socket: %w", err)
 	}
 
-	// Since we only accept a single connection, we should close the listener
-	// in any case.
+	// Since we only accept connections while starting the runtime, we should
+	// close the listener in any case.
 	defer listener.Close()
 
 	// Create the sandbox as configured.
@@ -202,6 +214,7 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		if cErr != nil {
 			return fmt.Errorf("failed to configure process: %w", cErr)
 		}
+		cfg.Limits = r.getProcessLimits()
 
 		p, err = process.NewNaked(cfg)
 		if err != nil {
@@ -213,6 +226,7 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		if cErr != nil {
 			return fmt.Errorf("failed to configure sandbox: %w", cErr)
 		}
+		cfg.Limits = r.getProcessLimits()
 
 		if cfg.BindRW == nil {
 			cfg.BindRW = make(map[string]string)
@@ -275,7 +289,20 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		"pid", p.GetPID(),
 	)
 
-	pc, err := protocol.NewConnection(r.logger, r.rtCfg.RuntimeID, r.rtCfg.MessageHandler)
+	// Additional connections are accepted on the same socket once the runtime has reported how
+	// many connections it opened.
+	accept := func(ctx context.Context) (net.Conn, error) {
+		deadline := time.Now().Add(runtimeConnectTimeout)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		if lerr := listener.SetDeadline(deadline); lerr != nil {
+			return nil, lerr
+		}
+		return listener.Accept()
+	}
+
+	pc, err := protocol.NewConnectionPool(r.logger, r.rtCfg.RuntimeID, r.rtCfg.MessageHandler, r.cfg.MaxConnections, accept)
 	if err != nil {
 		return fmt.Errorf("failed to create connection: %w", err)
 	}
@@ -330,7 +357,7 @@ func (r *sandboxedRuntime) handleRestartRequest(rq *restartRequest) error {
 	ctx, cancel := context.WithTimeout(context.Background(), runtimeInterruptTimeout)
 	defer cancel()
 
-	response, err := r.conn.Call(ctx, &protocol.Body{RuntimeAbortRequest: &protocol.Empty{}})
+	response, err := r.conn.Call(ctx, protocol.PriorityHigh, &protocol.Body{RuntimeAbortRequest: &protocol.Empty{}})
 	if err == nil && response.RuntimeAbortResponse != nil {
 		// Successful response, assume runtime is done.
 		return nil
@@ -460,8 +487,9 @@ func (r *sandboxedRuntime) manager() {
 			return
 		case <-r.process.Wait():
 			// Process has terminated.
+			perr := r.process.Error()
 			r.logger.Error("runtime process has terminated unexpectedly",
-				"err", r.process.Error(),
+				"err", perr,
 			)
 
 			r.Lock()
@@ -470,6 +498,16 @@ func (r *sandboxedRuntime) manager() {
 			r.conn = nil
 			r.Unlock()
 
+			// Notify subscribers in case the runtime has been killed due to exceeding its limits.
+			var leErr *process.LimitExceededError
+			if errors.As(perr, &leErr) {
+				r.notifier.Broadcast(&host.Event{
+					LimitExceeded: &host.LimitExceededEvent{
+						Resource: leErr.Resource,
+					},
+				})
+			}
+
 			// Notify subscribers that the runtime has stopped.
 			r.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
 			continue
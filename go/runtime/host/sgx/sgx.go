@@ -59,6 +59,10 @@ type Config struct {
 
 	// InsecureNoSandbox disables the sandbox and runs the loader directly.
 	InsecureNoSandbox bool
+
+	// MaxConnections is the maximum number of Runtime Host Protocol connections that a runtime may
+	// open to the host. Zero means that only a single connection is used.
+	MaxConnections uint16
 }
 
 // RuntimeExtra is the extra configuration for SGX runtimes.
@@ -229,6 +233,7 @@ func (s *sgxProvisioner) initCapabilityTEE(ctx context.Context, rt host.Runtime,
 
 	if _, err = conn.Call(
 		ctx,
+		protocol.PriorityNormal,
 		&protocol.Body{
 			RuntimeCapabilityTEERakInitRequest: &protocol.RuntimeCapabilityTEERakInitRequest{
 				TargetInfo: qi.TargetInfo,
@@ -254,6 +259,7 @@ func (s *sgxProvisioner) updateCapabilityTEE(ctx context.Context, ts *teeState,
 
 	rakQuoteRes, err := conn.Call(
 		ctx,
+		protocol.PriorityNormal,
 		&protocol.Body{
 			RuntimeCapabilityTEERakReportRequest: &protocol.Empty{},
 		},
@@ -294,6 +300,7 @@ func (s *sgxProvisioner) updateCapabilityTEE(ctx context.Context, ts *teeState,
 
 	_, err = conn.Call(
 		ctx,
+		protocol.PriorityNormal,
 		&protocol.Body{
 			RuntimeCapabilityTEERakAvrRequest: &protocol.RuntimeCapabilityTEERakAvrRequest{
 				AVR: *avrBundle,
@@ -367,6 +374,7 @@ func New(cfg Config) (host.Provisioner, error) {
 		GetSandboxConfig:  s.getSandboxConfig,
 		HostInitializer:   s.hostInitializer,
 		InsecureNoSandbox: cfg.InsecureNoSandbox,
+		MaxConnections:    cfg.MaxConnections,
 		Logger:            s.logger,
 	})
 	if err != nil {
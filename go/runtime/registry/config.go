@@ -2,12 +2,15 @@ package registry
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/runtime/history"
 	"github.com/oasislabs/oasis-core/go/runtime/tagindexer"
 )
@@ -21,11 +24,31 @@ const (
 	// CfgHistoryPrunerInterval configures the history pruner interval.
 	CfgHistoryPrunerInterval = "runtime.history.pruner.interval"
 	// CfgHistoryPrunerKeepLastNum configures the number of last kept
-	// rounds when using the "keep last" pruner strategy.
+	// rounds when using the "keep last" or "retention" pruner strategies.
 	CfgHistoryPrunerKeepLastNum = "runtime.history.pruner.num_kept"
+	// CfgHistoryPrunerMaxAge configures the maximum age of kept rounds
+	// when using the "retention" pruner strategy.
+	CfgHistoryPrunerMaxAge = "runtime.history.pruner.max_age"
+	// CfgHistoryPrunerRetention configures per-runtime retention policies
+	// when using the "retention" pruner strategy.
+	//
+	// Policies are in the format <runtime-id>:<num_kept>:<max_age>.
+	CfgHistoryPrunerRetention = "runtime.history.pruner.retention"
+	// CfgHistoryArchiveDir configures the directory pruned history is
+	// archived into.
+	CfgHistoryArchiveDir = "runtime.history.archive_dir"
 
 	// CfgTagIndexerBackend configures the history tag indexer backend.
 	CfgTagIndexerBackend = "runtime.history.tag_indexer.backend"
+
+	// CfgDescriptorUpdateStableBlocks configures the number of consensus
+	// blocks a runtime descriptor update must remain unchanged for before it
+	// is applied.
+	CfgDescriptorUpdateStableBlocks = "runtime.descriptor_update.stable_blocks"
+
+	// CfgSelfCheckRepair enables automatic repair of safely repairable
+	// divergences detected by the startup self-check.
+	CfgSelfCheckRepair = "runtime.self_check.repair"
 )
 
 // Flags has the configuration flags.
@@ -36,24 +59,100 @@ type RuntimeConfig struct {
 	// History configures the runtime history keeper.
 	History history.Config
 
+	// HistoryRetention overrides the history retention policy of specific
+	// runtimes.
+	HistoryRetention map[common.Namespace]history.RetentionPolicy
+
+	// HistoryArchiveDir is the directory pruned history is archived into.
+	// Archiving is disabled if empty.
+	HistoryArchiveDir string
+
 	// TagIndexer configures the tag indexer backend.
 	TagIndexer tagindexer.BackendFactory
+
+	// DescriptorUpdateStableBlocks is the number of consensus blocks a
+	// runtime descriptor update must remain unchanged for before it is
+	// applied. Zero means that updates are applied immediately.
+	DescriptorUpdateStableBlocks uint64
+}
+
+// historyConfig returns the history keeper configuration of the given
+// runtime.
+func (cfg *RuntimeConfig) historyConfig(id common.Namespace) *history.Config {
+	historyCfg := cfg.History
+	if policy, ok := cfg.HistoryRetention[id]; ok {
+		historyCfg.Pruner = history.NewRetentionPruner(policy)
+	}
+	return &historyCfg
+}
+
+// historyArchiveDir returns the directory pruned history of the given
+// runtime is archived into (if any).
+func (cfg *RuntimeConfig) historyArchiveDir(id common.Namespace) string {
+	if cfg.HistoryArchiveDir == "" {
+		return ""
+	}
+	return filepath.Join(cfg.HistoryArchiveDir, id.String())
+}
+
+func parseRetentionPolicies(rawPolicies []string) (map[common.Namespace]history.RetentionPolicy, error) {
+	rawMap, err := ParseRuntimeMap(rawPolicies)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[common.Namespace]history.RetentionPolicy, len(rawMap))
+	for id, rawPolicy := range rawMap {
+		atoms := strings.Split(rawPolicy, ":")
+		if len(atoms) != 2 {
+			return nil, fmt.Errorf("malformed retention policy for runtime %s: %s", id, rawPolicy)
+		}
+
+		var policy history.RetentionPolicy
+		if policy.NumKept, err = strconv.ParseUint(atoms[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("malformed retention policy for runtime %s: %w", id, err)
+		}
+		if policy.MaxAge, err = time.ParseDuration(atoms[1]); err != nil {
+			return nil, fmt.Errorf("malformed retention policy for runtime %s: %w", id, err)
+		}
+		if err = policy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid retention policy for runtime %s: %w", id, err)
+		}
+		policies[id] = policy
+	}
+	return policies, nil
 }
 
 func newConfig() (*RuntimeConfig, error) {
 	var cfg RuntimeConfig
 
 	strategy := viper.GetString(CfgHistoryPrunerStrategy)
+	rawRetention := viper.GetStringSlice(CfgHistoryPrunerRetention)
+	if len(rawRetention) > 0 && !strings.EqualFold(strategy, history.PrunerStrategyRetention) {
+		return nil, fmt.Errorf("runtime/registry: per-runtime retention policies require the %s history pruner strategy", history.PrunerStrategyRetention)
+	}
 	switch strings.ToLower(strategy) {
 	case history.PrunerStrategyNone:
 		cfg.History.Pruner = history.NewNonePruner()
 	case history.PrunerStrategyKeepLast:
 		numKept := viper.GetUint64(CfgHistoryPrunerKeepLastNum)
 		cfg.History.Pruner = history.NewKeepLastPruner(numKept)
+	case history.PrunerStrategyRetention:
+		cfg.History.Pruner = history.NewRetentionPruner(history.RetentionPolicy{
+			NumKept: viper.GetUint64(CfgHistoryPrunerKeepLastNum),
+			MaxAge:  viper.GetDuration(CfgHistoryPrunerMaxAge),
+		})
+
+		var err error
+		if cfg.HistoryRetention, err = parseRetentionPolicies(rawRetention); err != nil {
+			return nil, fmt.Errorf("runtime/registry: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("runtime/registry: unknown history pruner strategy: %s", strategy)
 	}
 
+	cfg.HistoryArchiveDir = viper.GetString(CfgHistoryArchiveDir)
+
 	cfg.History.PruneInterval = viper.GetDuration(CfgHistoryPrunerInterval)
 	if cfg.History.PruneInterval.Seconds() < 1.0 {
 		return nil, fmt.Errorf("runtime/registry: history prune interval must be >= 1s (got %s)", cfg.History.PruneInterval)
@@ -69,6 +168,8 @@ func newConfig() (*RuntimeConfig, error) {
 		return nil, fmt.Errorf("runtime/registry: unknown tag indexer backend: %s", tagIndexer)
 	}
 
+	cfg.DescriptorUpdateStableBlocks = viper.GetUint64(CfgDescriptorUpdateStableBlocks)
+
 	return &cfg, nil
 }
 
@@ -77,9 +178,16 @@ func init() {
 
 	Flags.String(CfgHistoryPrunerStrategy, history.PrunerStrategyNone, "History pruner strategy")
 	Flags.Duration(CfgHistoryPrunerInterval, 2*time.Minute, "History pruning interval")
-	Flags.Uint64(CfgHistoryPrunerKeepLastNum, 600, "Keep last history pruner: number of last rounds to keep")
+	Flags.Uint64(CfgHistoryPrunerKeepLastNum, 600, "Keep last and retention history pruners: number of last rounds to keep")
+	Flags.Duration(CfgHistoryPrunerMaxAge, 0, "Retention history pruner: maximum age of rounds to keep (0 for no limit)")
+	Flags.StringSlice(CfgHistoryPrunerRetention, nil, "Retention history pruner: per-runtime retention policy (<runtime-id>:<num_kept>:<max_age>)")
+	Flags.String(CfgHistoryArchiveDir, "", "Directory to archive pruned history into (disabled by default)")
 
 	Flags.String(CfgTagIndexerBackend, "", "Runtime tag indexer backend (disabled by default)")
 
+	Flags.Uint64(CfgDescriptorUpdateStableBlocks, 0, "Number of blocks a runtime descriptor update must be stable for before being applied (0 to apply immediately)")
+
+	Flags.Bool(CfgSelfCheckRepair, false, "Automatically repair safely repairable store divergences on startup")
+
 	_ = viper.BindPFlags(Flags)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/debounce"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
@@ -154,33 +155,86 @@ func (r *runtime) stop() {
 	r.history.Close()
 }
 
-func (r *runtime) watchUpdates(ctx context.Context, ch <-chan *registry.Runtime, sub pubsub.ClosableSubscription) {
+func (r *runtime) updateDescriptor(rt *registry.Runtime) {
+	r.logger.Debug("updated runtime descriptor",
+		"runtime", rt,
+	)
+
+	r.Lock()
+	initialized := r.descriptor != nil
+	r.descriptor = rt
+	r.Unlock()
+
+	if !initialized {
+		close(r.descriptorCh)
+	}
+
+	r.descriptorNotifier.Broadcast(rt)
+}
+
+func (r *runtime) watchUpdates(
+	ctx context.Context,
+	ch <-chan *registry.Runtime,
+	sub pubsub.ClosableSubscription,
+	stableBlocks uint64,
+) {
 	defer sub.Close()
 
+	// Descriptor updates are only applied after they have been stable for the
+	// configured number of blocks, to avoid needlessly tearing down and
+	// re-establishing everything that depends on the descriptor in case of
+	// rapid successive updates.
+	debouncer := debounce.NewBlockDebouncer(stableBlocks)
+	var (
+		blkCh  <-chan *consensus.Block
+		height int64
+	)
+	if debouncer.Enabled() {
+		var (
+			blkSub pubsub.ClosableSubscription
+			err    error
+		)
+		blkCh, blkSub, err = r.consensus.WatchBlocks(ctx)
+		if err != nil {
+			r.logger.Error("failed to watch consensus blocks, not debouncing descriptor updates",
+				"err", err,
+			)
+			debouncer = debounce.NewBlockDebouncer(0)
+		} else {
+			defer blkSub.Close()
+
+			// Seed the height so that early updates are debounced as well.
+			if blk, err := r.consensus.GetBlock(ctx, consensus.HeightLatest); err == nil {
+				height = blk.Height
+			}
+		}
+	}
+
 	var initialized bool
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case blk := <-blkCh:
+			height = blk.Height
+			for _, v := range debouncer.Tick(height) {
+				r.updateDescriptor(v.(*registry.Runtime))
+			}
 		case rt := <-ch:
 			if !rt.ID.Equal(&r.id) {
 				continue
 			}
 
-			r.logger.Debug("updated runtime descriptor",
-				"runtime", rt,
-			)
-
-			r.Lock()
-			r.descriptor = rt
-			r.Unlock()
-
 			if !initialized {
-				close(r.descriptorCh)
+				// The initial descriptor is always applied immediately.
+				r.updateDescriptor(rt)
 				initialized = true
+				continue
 			}
 
-			r.descriptorNotifier.Broadcast(rt)
+			for _, v := range debouncer.Update(r.id, rt, height) {
+				r.updateDescriptor(v.(*registry.Runtime))
+			}
 		}
 	}
 }
@@ -193,6 +247,7 @@ type runtimeRegistry struct {
 	dataDir   string
 	consensus consensus.Backend
 	identity  *identity.Identity
+	cfg       *RuntimeConfig
 
 	runtimes map[common.Namespace]*runtime
 }
@@ -220,7 +275,7 @@ func (r *runtimeRegistry) Runtimes() []Runtime {
 }
 
 func (r *runtimeRegistry) NewUnmanagedRuntime(ctx context.Context, runtimeID common.Namespace) (Runtime, error) {
-	return newRuntime(ctx, runtimeID, r.consensus, r.logger)
+	return newRuntime(ctx, runtimeID, r.consensus, r.cfg, r.logger)
 }
 
 func (r *runtimeRegistry) StorageRouter() storageAPI.Backend {
@@ -236,6 +291,22 @@ func (r *runtimeRegistry) Cleanup() {
 	}
 }
 
+// registerHistoryArchive registers a prune handler that archives pruned
+// history into the given directory. Archiving is disabled if the directory
+// is empty.
+func registerHistoryArchive(h history.History, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	ah, err := history.NewArchiveHandler(dir, h)
+	if err != nil {
+		return err
+	}
+	h.Pruner().RegisterHandler(ah)
+	return nil
+}
+
 func (r *runtimeRegistry) addSupportedRuntime(ctx context.Context, id common.Namespace, cfg *RuntimeConfig) error {
 	r.Lock()
 	defer r.Unlock()
@@ -254,10 +325,13 @@ func (r *runtimeRegistry) addSupportedRuntime(ctx context.Context, id common.Nam
 	}
 
 	// Create runtime history keeper.
-	history, err := history.New(path, id, &cfg.History)
+	history, err := history.New(path, id, cfg.historyConfig(id))
 	if err != nil {
 		return fmt.Errorf("runtime/registry: cannot create block history for runtime %s: %w", id, err)
 	}
+	if err = registerHistoryArchive(history, cfg.historyArchiveDir(id)); err != nil {
+		return fmt.Errorf("runtime/registry: cannot archive block history for runtime %s: %w", id, err)
+	}
 
 	// Create runtime-specific local storage backend.
 	localStorage, err := localstorage.New(path, LocalStorageFile, id)
@@ -288,7 +362,7 @@ func (r *runtimeRegistry) addSupportedRuntime(ctx context.Context, id common.Nam
 		return fmt.Errorf("runtime/registry: cannot track runtime %s: %w", id, err)
 	}
 
-	rt, err := newRuntime(ctx, id, r.consensus, r.logger)
+	rt, err := newRuntime(ctx, id, r.consensus, cfg, r.logger)
 	if err != nil {
 		return err
 	}
@@ -302,7 +376,13 @@ func (r *runtimeRegistry) addSupportedRuntime(ctx context.Context, id common.Nam
 	return nil
 }
 
-func newRuntime(ctx context.Context, id common.Namespace, consensus consensus.Backend, logger *logging.Logger) (*runtime, error) {
+func newRuntime(
+	ctx context.Context,
+	id common.Namespace,
+	consensus consensus.Backend,
+	cfg *RuntimeConfig,
+	logger *logging.Logger,
+) (*runtime, error) {
 	// Start watching this runtime's descriptor.
 	ch, sub, err := consensus.Registry().WatchRuntimes(ctx)
 	if err != nil {
@@ -318,7 +398,7 @@ func newRuntime(ctx context.Context, id common.Namespace, consensus consensus.Ba
 		descriptorNotifier: pubsub.NewBroker(true),
 		logger:             logger.With("runtime_id", id),
 	}
-	go rt.watchUpdates(watchCtx, ch, sub)
+	go rt.watchUpdates(watchCtx, ch, sub, cfg.DescriptorUpdateStableBlocks)
 
 	return rt, nil
 }
@@ -337,6 +417,7 @@ func New(ctx context.Context, dataDir string, consensus consensus.Backend, ident
 	if err != nil {
 		return nil, err
 	}
+	r.cfg = cfg
 
 	runtimes, err := ParseRuntimeMap(viper.GetStringSlice(CfgSupported))
 	if err != nil {
@@ -0,0 +1,305 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
+	clientAPI "github.com/oasislabs/oasis-core/go/runtime/client/api"
+	"github.com/oasislabs/oasis-core/go/runtime/history"
+	"github.com/oasislabs/oasis-core/go/runtime/tagindexer"
+	"github.com/oasislabs/oasis-core/go/storage"
+	"github.com/oasislabs/oasis-core/go/storage/database"
+	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	badgerNodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
+)
+
+// IssueSeverity is the severity of a detected consistency issue.
+type IssueSeverity uint8
+
+const (
+	// SeverityInfo is an issue that will resolve itself during normal operation.
+	SeverityInfo IssueSeverity = iota
+	// SeverityWarning is an issue that may cause degraded operation.
+	SeverityWarning
+	// SeverityError is an issue that will prevent the node from operating correctly.
+	SeverityError
+)
+
+// String returns a string representation of the issue severity.
+func (s IssueSeverity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("[unknown: %d]", uint8(s))
+	}
+}
+
+// ConsistencyIssue is a divergence between persisted stores detected during
+// the startup self-check.
+type ConsistencyIssue struct {
+	// RuntimeID is the runtime the issue relates to.
+	RuntimeID common.Namespace
+	// Store is the name of the lagging or diverged store.
+	Store string
+	// Severity is the issue severity.
+	Severity IssueSeverity
+	// Description is a human readable description of the divergence.
+	Description string
+	// Repair is the suggested repair action.
+	Repair string
+	// Repaired is true iff the issue has been automatically repaired.
+	Repaired bool
+}
+
+// SelfCheck verifies consistency between the persisted per-runtime stores
+// (runtime block history, storage node database and tag index) of all
+// configured runtimes and the given last committed consensus height.
+//
+// If repair is true, divergences that can safely be repaired are repaired.
+//
+// This must be called before the runtime registry is created as the stores
+// are opened directly.
+func SelfCheck(ctx context.Context, dataDir string, consensusHeight int64, repair bool) ([]*ConsistencyIssue, error) {
+	logger := logging.GetLogger("runtime/registry/selfcheck")
+
+	runtimes, err := ParseRuntimeMap(viper.GetStringSlice(CfgSupported))
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []*ConsistencyIssue
+	for id := range runtimes {
+		logger.Debug("checking runtime state consistency",
+			"runtime_id", id,
+			"consensus_height", consensusHeight,
+		)
+
+		rtIssues, err := selfCheckRuntime(ctx, dataDir, id, consensusHeight, repair)
+		if err != nil {
+			return nil, fmt.Errorf("runtime/registry: self-check failed for runtime %s: %w", id, err)
+		}
+		issues = append(issues, rtIssues...)
+	}
+	return issues, nil
+}
+
+func selfCheckRuntime(
+	ctx context.Context,
+	dataDir string,
+	id common.Namespace,
+	consensusHeight int64,
+	repair bool,
+) ([]*ConsistencyIssue, error) {
+	path := filepath.Join(dataDir, RuntimesDir, id.String())
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// No persisted state yet, nothing to check.
+		return nil, nil
+	}
+
+	var issues []*ConsistencyIssue
+
+	h, err := history.New(path, id, history.NewDefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open block history: %w", err)
+	}
+	defer h.Close()
+
+	// Make sure block history is not ahead of consensus. This can happen in case
+	// consensus state has been reset or restored from an earlier snapshot and
+	// would cause the roothash backend to fail when committing new blocks.
+	historyHeight, err := h.LastConsensusHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block history: %w", err)
+	}
+	if historyHeight > consensusHeight {
+		issue := &ConsistencyIssue{
+			RuntimeID: id,
+			Store:     "history",
+			Severity:  SeverityError,
+			Description: fmt.Sprintf("block history is ahead of consensus state (history height: %d consensus height: %d)",
+				historyHeight,
+				consensusHeight,
+			),
+			Repair: "roll back block history to the consensus height, blocks will be reindexed from consensus",
+		}
+		if repair {
+			if err = h.RollbackToConsensusHeight(consensusHeight); err != nil {
+				return nil, fmt.Errorf("failed to roll back block history: %w", err)
+			}
+			issue.Repaired = true
+		}
+		issues = append(issues, issue)
+	}
+
+	// Compare the storage node database (if any) with the block history.
+	storageIssue, err := selfCheckStorage(ctx, path, id, h)
+	if err != nil {
+		return nil, err
+	}
+	if storageIssue != nil {
+		issues = append(issues, storageIssue)
+	}
+
+	// Compare the tag index (if any) with the block history.
+	tagIndexerIssue, err := selfCheckTagIndexer(ctx, path, id, h, repair)
+	if err != nil {
+		return nil, err
+	}
+	if tagIndexerIssue != nil {
+		issues = append(issues, tagIndexerIssue)
+	}
+
+	return issues, nil
+}
+
+// latestHistoryRound returns the round of the latest block in the block
+// history or zero if the block history is empty.
+func latestHistoryRound(ctx context.Context, h history.History) (uint64, error) {
+	blk, err := h.GetLatestBlock(ctx)
+	switch err {
+	case nil:
+		return blk.Header.Round, nil
+	case roothash.ErrNotFound:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("failed to query latest block from block history: %w", err)
+	}
+}
+
+func selfCheckStorage(ctx context.Context, path string, id common.Namespace, h history.History) (*ConsistencyIssue, error) {
+	if viper.GetString(storage.CfgBackend) != database.BackendNameBadgerDB {
+		return nil, nil
+	}
+	dbPath := filepath.Join(path, database.DefaultFileName(database.BackendNameBadgerDB))
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	ndb, err := badgerNodedb.New(&nodedb.Config{
+		DB:        dbPath,
+		Namespace: id,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage node database: %w", err)
+	}
+	defer ndb.Close()
+
+	storageVersion, err := ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query storage node database: %w", err)
+	}
+
+	historyRound, err := latestHistoryRound(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case storageVersion > historyRound:
+		return &ConsistencyIssue{
+			RuntimeID: id,
+			Store:     "storage",
+			Severity:  SeverityWarning,
+			Description: fmt.Sprintf("storage is ahead of block history (storage version: %d history round: %d)",
+				storageVersion,
+				historyRound,
+			),
+			Repair: "block history will be reindexed from consensus, if the condition persists reset the runtime state",
+		}, nil
+	case storageVersion < historyRound:
+		return &ConsistencyIssue{
+			RuntimeID: id,
+			Store:     "storage",
+			Severity:  SeverityInfo,
+			Description: fmt.Sprintf("storage is behind block history (storage version: %d history round: %d)",
+				storageVersion,
+				historyRound,
+			),
+			Repair: "storage will be synced forward by the storage worker",
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func selfCheckTagIndexer(
+	ctx context.Context,
+	path string,
+	id common.Namespace,
+	h history.History,
+	repair bool,
+) (*ConsistencyIssue, error) {
+	if viper.GetString(CfgTagIndexerBackend) != tagindexer.BleveBackendName {
+		return nil, nil
+	}
+
+	backend, err := tagindexer.NewBleveBackend()(path, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tag index: %w", err)
+	}
+	defer backend.Close()
+
+	indexedRound, err := backend.LastIndexedRound(ctx)
+	switch err {
+	case nil:
+	case clientAPI.ErrNotFound:
+		// Nothing indexed yet, nothing to check.
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to query tag index: %w", err)
+	}
+
+	historyRound, err := latestHistoryRound(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case indexedRound > historyRound:
+		issue := &ConsistencyIssue{
+			RuntimeID: id,
+			Store:     "tag_indexer",
+			Severity:  SeverityWarning,
+			Description: fmt.Sprintf("tag index is ahead of block history (indexed round: %d history round: %d)",
+				indexedRound,
+				historyRound,
+			),
+			Repair: "prune tag index entries above the block history round, blocks will be reindexed",
+		}
+		if repair {
+			for round := historyRound + 1; round <= indexedRound; round++ {
+				if err = backend.Prune(ctx, round); err != nil {
+					return nil, fmt.Errorf("failed to prune tag index: %w", err)
+				}
+			}
+			issue.Repaired = true
+		}
+		return issue, nil
+	case indexedRound < historyRound:
+		return &ConsistencyIssue{
+			RuntimeID: id,
+			Store:     "tag_indexer",
+			Severity:  SeverityWarning,
+			Description: fmt.Sprintf("tag index is behind block history (indexed round: %d history round: %d)",
+				indexedRound,
+				historyRound,
+			),
+			Repair: "remove the tag index and reset the block history to reindex the missing rounds",
+		}, nil
+	default:
+		return nil, nil
+	}
+}
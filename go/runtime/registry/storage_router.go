@@ -9,7 +9,10 @@ import (
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
 )
 
-var _ api.Backend = (*storageRouter)(nil)
+var (
+	_ api.Backend       = (*storageRouter)(nil)
+	_ api.ReadTxBackend = (*storageRouter)(nil)
+)
 
 type storageRouter struct {
 	registry Registry
@@ -43,6 +46,14 @@ func (sr *storageRouter) SyncIterate(ctx context.Context, request *api.IterateRe
 	return rt.Storage().SyncIterate(ctx, request)
 }
 
+func (sr *storageRouter) NewReadTx(ioRoot, stateRoot api.Root) (api.ReadTx, error) {
+	rt, err := sr.getRuntime(ioRoot.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return api.BeginReadTx(rt.Storage(), ioRoot, stateRoot)
+}
+
 func (sr *storageRouter) Apply(ctx context.Context, request *api.ApplyRequest) ([]*api.Receipt, error) {
 	rt, err := sr.getRuntime(request.Namespace)
 	if err != nil {
@@ -20,8 +20,8 @@ var (
 	ErrTagTooLong = errors.New("tagindexer: tag too long to process")
 	// ErrCorrupted is the error when index corruption is detected.
 	ErrCorrupted = errors.New("tagindexer: index corrupted")
-
-	errNopBackend = errors.New("tagindexer: tag indexer is disabled")
+	// ErrTagIndexerDisabled is the error when the tag indexer is disabled.
+	ErrTagIndexerDisabled = errors.New("tagindexer: tag indexer is disabled")
 )
 
 // Result is a query result.
@@ -61,6 +61,11 @@ type QueryableBackend interface {
 
 	// WaitBlockIndexed waits for a block to be indexed by the indexer.
 	WaitBlockIndexed(ctx context.Context, round uint64) error
+
+	// LastIndexedRound returns the last indexed block round.
+	//
+	// If no blocks have been indexed, api.ErrNotFound is returned.
+	LastIndexedRound(ctx context.Context) (uint64, error)
 }
 
 // Backend is the tag indexer backend interface.
@@ -108,23 +113,27 @@ func (n *nopBackend) Prune(ctx context.Context, round uint64) error {
 }
 
 func (n *nopBackend) QueryBlock(ctx context.Context, blockHash hash.Hash) (uint64, error) {
-	return 0, errNopBackend
+	return 0, ErrTagIndexerDisabled
 }
 
 func (n *nopBackend) QueryTxn(ctx context.Context, key, value []byte) (uint64, hash.Hash, uint32, error) {
-	return 0, hash.Hash{}, 0, errNopBackend
+	return 0, hash.Hash{}, 0, ErrTagIndexerDisabled
 }
 
 func (n *nopBackend) QueryTxnByIndex(ctx context.Context, round uint64, index uint32) (hash.Hash, error) {
-	return hash.Hash{}, errNopBackend
+	return hash.Hash{}, ErrTagIndexerDisabled
 }
 
 func (n *nopBackend) QueryTxns(ctx context.Context, query api.Query) (Results, error) {
-	return nil, errNopBackend
+	return nil, ErrTagIndexerDisabled
 }
 
 func (n *nopBackend) WaitBlockIndexed(ctx context.Context, round uint64) error {
-	return errNopBackend
+	return ErrTagIndexerDisabled
+}
+
+func (n *nopBackend) LastIndexedRound(ctx context.Context) (uint64, error) {
+	return 0, ErrTagIndexerDisabled
 }
 
 func (n *nopBackend) Close() {
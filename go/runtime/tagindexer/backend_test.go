@@ -17,6 +17,9 @@ import (
 func testOperations(t *testing.T, backend Backend) {
 	ctx := context.Background()
 
+	_, err := backend.LastIndexedRound(ctx)
+	require.Equal(t, api.ErrNotFound, err, "LastIndexedRound must return a not found error")
+
 	tx1 := []byte("i am a transaction")
 	tx2 := []byte("i am a second transaction")
 	tx3 := []byte("i am a third transaction")
@@ -29,7 +32,7 @@ func testOperations(t *testing.T, backend Backend) {
 	var blockHash1 hash.Hash
 	blockHash1.FromBytes([]byte("this is a fake block hash 1"))
 
-	err := backend.Index(
+	err = backend.Index(
 		ctx,
 		42,
 		blockHash1,
@@ -109,6 +112,10 @@ func testOperations(t *testing.T, backend Backend) {
 	require.NoError(t, err, "QueryBlock")
 	require.EqualValues(t, 43, round)
 
+	round, err = backend.LastIndexedRound(ctx)
+	require.NoError(t, err, "LastIndexedRound")
+	require.EqualValues(t, 43, round)
+
 	round, txnHash, txnIndex, err = backend.QueryTxn(ctx, []byte("foo"), []byte("bar"))
 	require.NoError(t, err, "QueryTxn")
 	require.EqualValues(t, 43, round)
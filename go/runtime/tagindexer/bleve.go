@@ -353,6 +353,26 @@ func (b *bleveBackend) WaitBlockIndexed(ctx context.Context, round uint64) error
 	}
 }
 
+func (b *bleveBackend) LastIndexedRound(ctx context.Context) (uint64, error) {
+	rq := bleve.NewSearchRequest(queryByKindBlock)
+	rq.Size = 1
+	rq.SortBy([]string{"-" + fieldRound})
+	result, err := b.index.SearchInContext(ctx, rq)
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Hits) == 0 {
+		return 0, api.ErrNotFound
+	}
+
+	var decRound uint64
+	if !blockDocIDKeyFmt.Decode([]byte(result.Hits[0].ID), &decRound) {
+		return 0, ErrCorrupted
+	}
+
+	return decRound, nil
+}
+
 func (b *bleveBackend) Prune(ctx context.Context, round uint64) error {
 	rq := bleve.NewSearchRequest(queryByRound(round))
 	result, err := b.index.SearchInContext(ctx, rq)
@@ -145,6 +145,102 @@ func (c *Committee) EncodedMembersHash() hash.Hash {
 	return hash.NewFrom(c.Members)
 }
 
+// ElectionFailureReason is the reason why a committee election failed.
+type ElectionFailureReason uint8
+
+const (
+	// ElectionFailureInvalid is an invalid election failure reason.
+	ElectionFailureInvalid ElectionFailureReason = 0
+
+	// ElectionFailureEmptyCommittee indicates that the runtime is
+	// configured with an empty committee.
+	ElectionFailureEmptyCommittee ElectionFailureReason = 1
+
+	// ElectionFailureInsufficientNodes indicates that there are not
+	// enough eligible nodes to fill the committee.
+	ElectionFailureInsufficientNodes ElectionFailureReason = 2
+
+	// ElectionFailureInsufficientDiversity indicates that there are
+	// enough eligible nodes, but not enough of them belong to distinct
+	// entities to fill the committee without exceeding the maximum
+	// number of committee members per entity.
+	ElectionFailureInsufficientDiversity ElectionFailureReason = 3
+
+	// ElectionFailureInsufficientReadyNodes indicates that there are
+	// enough eligible nodes, but not enough of them have attested
+	// readiness for the runtime as required by the readiness policy.
+	ElectionFailureInsufficientReadyNodes ElectionFailureReason = 4
+)
+
+// String returns a string representation of an ElectionFailureReason.
+func (r ElectionFailureReason) String() string {
+	switch r {
+	case ElectionFailureInvalid:
+		return "invalid"
+	case ElectionFailureEmptyCommittee:
+		return "empty committee"
+	case ElectionFailureInsufficientNodes:
+		return "insufficient nodes"
+	case ElectionFailureInsufficientDiversity:
+		return "insufficient diversity"
+	case ElectionFailureInsufficientReadyNodes:
+		return "insufficient ready nodes"
+	default:
+		return fmt.Sprintf("[unknown reason: %d]", r)
+	}
+}
+
+// ReadinessPolicy is the policy for taking node runtime readiness
+// attestations into account when electing runtime committees.
+type ReadinessPolicy uint8
+
+const (
+	// ReadinessPolicyNone ignores runtime readiness attestations.
+	ReadinessPolicyNone ReadinessPolicy = 0
+
+	// ReadinessPolicyPrefer elects nodes that have attested readiness for
+	// the runtime before any other eligible nodes.
+	ReadinessPolicyPrefer ReadinessPolicy = 1
+
+	// ReadinessPolicyRequire only elects nodes that have attested readiness
+	// for the runtime.
+	ReadinessPolicyRequire ReadinessPolicy = 2
+)
+
+// String returns a string representation of a ReadinessPolicy.
+func (p ReadinessPolicy) String() string {
+	switch p {
+	case ReadinessPolicyNone:
+		return "none"
+	case ReadinessPolicyPrefer:
+		return "prefer"
+	case ReadinessPolicyRequire:
+		return "require"
+	default:
+		return fmt.Sprintf("[unknown policy: %d]", p)
+	}
+}
+
+// ElectionFailedEvent is the event emitted when a committee election fails.
+type ElectionFailedEvent struct {
+	// Kind is the kind of the committee that failed to be elected.
+	Kind CommitteeKind `json:"kind"`
+
+	// RuntimeID is the runtime ID of the committee that failed to be
+	// elected.
+	RuntimeID common.Namespace `json:"runtime_id"`
+
+	// Epoch is the epoch for which the election failed.
+	Epoch epochtime.EpochTime `json:"epoch"`
+
+	// Reason is the reason why the election failed.
+	Reason ElectionFailureReason `json:"reason"`
+
+	// KeptPrevious is true iff the previous committee was kept in place
+	// of the one that failed to be elected.
+	KeptPrevious bool `json:"kept_previous"`
+}
+
 // TokensPerVotingPower is the ratio of base units staked to validator power.
 var TokensPerVotingPower quantity.Quantity
 
@@ -198,6 +294,28 @@ type Backend interface {
 	// be sent immediately.
 	WatchCommittees(ctx context.Context) (<-chan *Committee, pubsub.ClosableSubscription, error)
 
+	// GetUpcomingCommittees returns the vector of committees elected ahead
+	// of the next epoch for a given runtime ID, at the specified block
+	// height.
+	//
+	// Upcoming committees are only elected when the election offset
+	// consensus parameter is set, and replace the current committees at
+	// the start of the epoch they are valid for.
+	GetUpcomingCommittees(ctx context.Context, request *GetCommitteesRequest) ([]*Committee, error)
+
+	// WatchUpcomingCommittees returns a channel that produces a stream of
+	// committees elected ahead of the next epoch.
+	WatchUpcomingCommittees(ctx context.Context) (<-chan *Committee, pubsub.ClosableSubscription, error)
+
+	// WatchElectionFailures returns a channel that produces a stream of
+	// failed committee elections.
+	WatchElectionFailures(ctx context.Context) (<-chan *ElectionFailedEvent, pubsub.ClosableSubscription, error)
+
+	// VerifyElection replays the election of the committee of the given
+	// kind and runtime at the specified block height and compares the
+	// result to the committee stored in the consensus state.
+	VerifyElection(ctx context.Context, request *VerifyElectionRequest) (*ElectionVerification, error)
+
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
@@ -211,6 +329,75 @@ type GetCommitteesRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 }
 
+// VerifyElectionRequest is a VerifyElection request.
+type VerifyElectionRequest struct {
+	// Height is the height of the block in which the committee was elected.
+	//
+	// The election is replayed using the beacon and the epoch at this height
+	// and the node registrations, stake and parameters as they were before
+	// the block at this height was processed.
+	Height    int64            `json:"height"`
+	Kind      CommitteeKind    `json:"kind"`
+	RuntimeID common.Namespace `json:"runtime_id"`
+}
+
+// ElectionMismatch is a difference between a replayed committee election
+// and the stored committee.
+type ElectionMismatch struct {
+	// Index is the position of the member in the committee.
+	Index int `json:"index"`
+
+	// Expected is the member elected by the replayed election, if any.
+	Expected *CommitteeNode `json:"expected,omitempty"`
+
+	// Actual is the member of the stored committee, if any.
+	Actual *CommitteeNode `json:"actual,omitempty"`
+}
+
+// ElectionVerification is the result of replaying a committee election.
+type ElectionVerification struct {
+	// Height is the block height at which the election was replayed.
+	Height int64 `json:"height"`
+
+	// Epoch is the epoch for which the election was replayed.
+	Epoch epochtime.EpochTime `json:"epoch"`
+
+	// Committee is the stored committee, if any.
+	Committee *Committee `json:"committee,omitempty"`
+
+	// Expected are the members elected by the replayed election.
+	Expected []*CommitteeNode `json:"expected,omitempty"`
+
+	// FailureReason is the reason why the replayed election failed or
+	// ElectionFailureInvalid if it succeeded.
+	FailureReason ElectionFailureReason `json:"failure_reason,omitempty"`
+
+	// KeptPrevious is true iff the replayed election failed and the stored
+	// committee is the previous committee that was kept in its place. The
+	// members of such a committee cannot be verified.
+	KeptPrevious bool `json:"kept_previous,omitempty"`
+
+	// Promoted is true iff the stored committee is an upcoming committee
+	// that was elected ahead of the epoch and promoted in place of the
+	// replayed election. The members of such a committee cannot be
+	// verified by replaying the election at the start of the epoch.
+	Promoted bool `json:"promoted,omitempty"`
+
+	// StaleCommittee is true iff the stored committee is not valid for the
+	// epoch of the replayed election.
+	StaleCommittee bool `json:"stale_committee,omitempty"`
+
+	// Mismatches are the differences between the members elected by the
+	// replayed election and the members of the stored committee.
+	Mismatches []*ElectionMismatch `json:"mismatches,omitempty"`
+}
+
+// IsValid returns true iff the stored committee matches the replayed
+// election.
+func (v *ElectionVerification) IsValid() bool {
+	return !v.StaleCommittee && len(v.Mismatches) == 0
+}
+
 // Genesis is the committee scheduler genesis state.
 type Genesis struct {
 	// Parameters are the scheduler consensus parameters.
@@ -231,6 +418,27 @@ type ConsensusParameters struct {
 	// may be elected per entity in a single validator set.
 	MaxValidatorsPerEntity int `json:"max_validators_per_entity"`
 
+	// MaxCommitteeMembersPerEntity is the maximum number of nodes that
+	// may be elected per entity in a single runtime committee (including
+	// backup workers). Zero means no limit.
+	MaxCommitteeMembersPerEntity int `json:"max_committee_members_per_entity,omitempty"`
+
+	// KeepCommitteeOnElectionFailure is true iff the previous committee
+	// should remain in place when a runtime committee election fails,
+	// instead of the committee being dropped.
+	KeepCommitteeOnElectionFailure bool `json:"keep_committee_on_election_failure,omitempty"`
+
+	// RuntimeReadinessPolicy is the policy for taking node runtime
+	// readiness attestations into account when electing runtime
+	// committees.
+	RuntimeReadinessPolicy ReadinessPolicy `json:"runtime_readiness_policy,omitempty"`
+
+	// ElectionOffset is the number of blocks before the start of an epoch
+	// at which the runtime committees for that epoch are elected and
+	// published as upcoming committees. Zero means that committees are
+	// elected at the start of the epoch.
+	ElectionOffset uint64 `json:"election_offset,omitempty"`
+
 	// DebugBypassStake is true iff the scheduler should bypass all of
 	// the staking related checks and operations.
 	DebugBypassStake bool `json:"debug_bypass_stake"`
@@ -252,6 +460,14 @@ func (g *Genesis) SanityCheck(stakingTotalSupply *quantity.Quantity) error {
 		return fmt.Errorf("scheduler: sanity check failed: one or more unsafe debug flags set")
 	}
 
+	if g.Parameters.MaxCommitteeMembersPerEntity < 0 {
+		return fmt.Errorf("scheduler: sanity check failed: maximum number of committee members per entity must be non-negative")
+	}
+
+	if g.Parameters.RuntimeReadinessPolicy > ReadinessPolicyRequire {
+		return fmt.Errorf("scheduler: sanity check failed: invalid runtime readiness policy: %s", g.Parameters.RuntimeReadinessPolicy)
+	}
+
 	if !g.Parameters.DebugBypassStake {
 		supplyPower, err := VotingPowerFromTokens(stakingTotalSupply)
 		if err != nil {
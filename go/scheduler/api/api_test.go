@@ -19,4 +19,7 @@ func TestSanityCheck(t *testing.T) {
 	q2e20 := quantity.NewQuantity()
 	require.NoError(t, q2e20.UnmarshalText([]byte("200_000_000_000_000_000_000")), "import q2e20")
 	require.Error(t, g.SanityCheck(q2e20), "sanity check total supply q2e20")
+
+	g.Parameters.MaxCommitteeMembersPerEntity = -1
+	require.Error(t, g.SanityCheck(q1e19), "sanity check negative max committee members per entity")
 }
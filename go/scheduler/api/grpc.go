@@ -17,11 +17,19 @@ var (
 	methodGetValidators = serviceName.NewMethod("GetValidators", int64(0))
 	// methodGetCommittees is the GetCommittees method.
 	methodGetCommittees = serviceName.NewMethod("GetCommittees", GetCommitteesRequest{})
+	// methodGetUpcomingCommittees is the GetUpcomingCommittees method.
+	methodGetUpcomingCommittees = serviceName.NewMethod("GetUpcomingCommittees", GetCommitteesRequest{})
+	// methodVerifyElection is the VerifyElection method.
+	methodVerifyElection = serviceName.NewMethod("VerifyElection", VerifyElectionRequest{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 
 	// methodWatchCommittees is the WatchCommittees method.
 	methodWatchCommittees = serviceName.NewMethod("WatchCommittees", nil)
+	// methodWatchElectionFailures is the WatchElectionFailures method.
+	methodWatchElectionFailures = serviceName.NewMethod("WatchElectionFailures", nil)
+	// methodWatchUpcomingCommittees is the WatchUpcomingCommittees method.
+	methodWatchUpcomingCommittees = serviceName.NewMethod("WatchUpcomingCommittees", nil)
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -36,6 +44,14 @@ var (
 				MethodName: methodGetCommittees.ShortName(),
 				Handler:    handlerGetCommittees,
 			},
+			{
+				MethodName: methodGetUpcomingCommittees.ShortName(),
+				Handler:    handlerGetUpcomingCommittees,
+			},
+			{
+				MethodName: methodVerifyElection.ShortName(),
+				Handler:    handlerVerifyElection,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -47,6 +63,16 @@ var (
 				Handler:       handlerWatchCommittees,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchElectionFailures.ShortName(),
+				Handler:       handlerWatchElectionFailures,
+				ServerStreams: true,
+			},
+			{
+				StreamName:    methodWatchUpcomingCommittees.ShortName(),
+				Handler:       handlerWatchUpcomingCommittees,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -97,6 +123,52 @@ func handlerGetCommittees( // nolint: golint
 	return interceptor(ctx, &req, info, handler)
 }
 
+func handlerGetUpcomingCommittees( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req GetCommitteesRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetUpcomingCommittees(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetUpcomingCommittees.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetUpcomingCommittees(ctx, req.(*GetCommitteesRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerVerifyElection( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req VerifyElectionRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).VerifyElection(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodVerifyElection.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).VerifyElection(ctx, req.(*VerifyElectionRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -148,6 +220,62 @@ func handlerWatchCommittees(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchElectionFailures(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchElectionFailures(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func handlerWatchUpcomingCommittees(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchUpcomingCommittees(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case c, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(c); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new scheduler service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -173,6 +301,22 @@ func (c *schedulerClient) GetCommittees(ctx context.Context, request *GetCommitt
 	return rsp, nil
 }
 
+func (c *schedulerClient) GetUpcomingCommittees(ctx context.Context, request *GetCommitteesRequest) ([]*Committee, error) {
+	var rsp []*Committee
+	if err := c.conn.Invoke(ctx, methodGetUpcomingCommittees.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *schedulerClient) VerifyElection(ctx context.Context, request *VerifyElectionRequest) (*ElectionVerification, error) {
+	var rsp ElectionVerification
+	if err := c.conn.Invoke(ctx, methodVerifyElection.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *schedulerClient) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -216,6 +360,76 @@ func (c *schedulerClient) WatchCommittees(ctx context.Context) (<-chan *Committe
 	return ch, sub, nil
 }
 
+func (c *schedulerClient) WatchElectionFailures(ctx context.Context) (<-chan *ElectionFailedEvent, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchElectionFailures.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *ElectionFailedEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev ElectionFailedEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (c *schedulerClient) WatchUpcomingCommittees(ctx context.Context) (<-chan *Committee, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[2], methodWatchUpcomingCommittees.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *Committee)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev Committee
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 func (c *schedulerClient) Cleanup() {
 }
 
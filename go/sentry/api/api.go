@@ -8,6 +8,10 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/node"
 )
 
+// UpstreamTLSPubKeysSignatureContext is the context used for signing upstream
+// node TLS public keys.
+var UpstreamTLSPubKeysSignatureContext = signature.NewContext("oasis-core/sentry: upstream TLS public keys")
+
 // SentryAddresses contains sentry node consensus and TLS addresses.
 type SentryAddresses struct {
 	Consensus []node.ConsensusAddress `json:"consensus"`
@@ -19,10 +23,39 @@ type Backend interface {
 	// Get addresses returns the list of consensus and TLS addresses of the sentry node.
 	GetAddresses(context.Context) (*SentryAddresses, error)
 
-	// SetUpstreamTLSPubKeys notifies the sentry node of the new TLS public keys used by its
-	// upstream node.
-	SetUpstreamTLSPubKeys(context.Context, []signature.PublicKey) error
+	// SetUpstreamTLSPubKeys notifies the sentry node of the new TLS public keys used by the
+	// upstream node that signed them.
+	SetUpstreamTLSPubKeys(context.Context, *SignedUpstreamTLSPubKeys) error
+
+	// GetUpstreamTLSPubKeys returns the TLS public keys of the given upstream node.
+	GetUpstreamTLSPubKeys(ctx context.Context, nodeID signature.PublicKey) ([]signature.PublicKey, error)
+}
+
+// UpstreamTLSPubKeys are the TLS public keys used by an upstream node.
+type UpstreamTLSPubKeys struct {
+	PubKeys []signature.PublicKey `json:"pub_keys"`
+}
+
+// SignedUpstreamTLSPubKeys are the TLS public keys of an upstream node, signed
+// by the upstream node's identity key.
+type SignedUpstreamTLSPubKeys struct {
+	signature.Signed
+}
+
+// Open first verifies the blob signature and then unmarshals the blob.
+func (s *SignedUpstreamTLSPubKeys) Open(pubKeys *UpstreamTLSPubKeys) error {
+	return s.Signed.Open(UpstreamTLSPubKeysSignatureContext, pubKeys)
+}
+
+// SignUpstreamTLSPubKeys signs the TLS public keys of an upstream node with
+// the upstream node's identity key.
+func SignUpstreamTLSPubKeys(signer signature.Signer, pubKeys *UpstreamTLSPubKeys) (*SignedUpstreamTLSPubKeys, error) {
+	signed, err := signature.SignSigned(signer, UpstreamTLSPubKeysSignatureContext, pubKeys)
+	if err != nil {
+		return nil, err
+	}
 
-	// GetUpstreamTLSPubKeys returns the TLS public keys of the sentry node's upstream node.
-	GetUpstreamTLSPubKeys(context.Context) ([]signature.PublicKey, error)
+	return &SignedUpstreamTLSPubKeys{
+		Signed: *signed,
+	}, nil
 }
@@ -17,10 +17,10 @@ var (
 	methodGetAddresses = serviceName.NewMethod("GetAddresses", nil)
 
 	// methodSetUpstreamTLSPubKeys is the SetUpstreamTLSPubKeys method.
-	methodSetUpstreamTLSPubKeys = serviceName.NewMethod("SetUpstreamTLSPubKeys", []signature.PublicKey{})
+	methodSetUpstreamTLSPubKeys = serviceName.NewMethod("SetUpstreamTLSPubKeys", SignedUpstreamTLSPubKeys{})
 
 	// methodGetUpstreamTLSPubKeys is the GetUpstreamTLSPubKeys method.
-	methodGetUpstreamTLSPubKeys = serviceName.NewMethod("GetUpstreamTLSPubKeys", nil)
+	methodGetUpstreamTLSPubKeys = serviceName.NewMethod("GetUpstreamTLSPubKeys", signature.PublicKey{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -69,19 +69,19 @@ func handlerSetUpstreamTLSPubKeys( // nolint: golint
 	dec func(interface{}) error,
 	interceptor grpc.UnaryServerInterceptor,
 ) (interface{}, error) {
-	var req []signature.PublicKey
+	var req SignedUpstreamTLSPubKeys
 	if err := dec(&req); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return nil, srv.(Backend).SetUpstreamTLSPubKeys(ctx, req)
+		return nil, srv.(Backend).SetUpstreamTLSPubKeys(ctx, &req)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
 		FullMethod: methodSetUpstreamTLSPubKeys.FullName(),
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return nil, srv.(Backend).SetUpstreamTLSPubKeys(ctx, *req.(*[]signature.PublicKey))
+		return nil, srv.(Backend).SetUpstreamTLSPubKeys(ctx, req.(*SignedUpstreamTLSPubKeys))
 	}
 	return interceptor(ctx, &req, info, handler)
 }
@@ -92,17 +92,21 @@ func handlerGetUpstreamTLSPubKeys( // nolint: golint
 	dec func(interface{}) error,
 	interceptor grpc.UnaryServerInterceptor,
 ) (interface{}, error) {
+	var nodeID signature.PublicKey
+	if err := dec(&nodeID); err != nil {
+		return nil, err
+	}
 	if interceptor == nil {
-		return srv.(Backend).GetUpstreamTLSPubKeys(ctx)
+		return srv.(Backend).GetUpstreamTLSPubKeys(ctx, nodeID)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
 		FullMethod: methodGetUpstreamTLSPubKeys.FullName(),
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(Backend).GetUpstreamTLSPubKeys(ctx)
+		return srv.(Backend).GetUpstreamTLSPubKeys(ctx, *req.(*signature.PublicKey))
 	}
-	return interceptor(ctx, nil, info, handler)
+	return interceptor(ctx, &nodeID, info, handler)
 }
 
 // RegisterService registers a new sentry service with the given gRPC server.
@@ -122,16 +126,16 @@ func (c *sentryClient) GetAddresses(ctx context.Context) (*SentryAddresses, erro
 	return &rsp, nil
 }
 
-func (c *sentryClient) SetUpstreamTLSPubKeys(ctx context.Context, pubKeys []signature.PublicKey) error {
+func (c *sentryClient) SetUpstreamTLSPubKeys(ctx context.Context, pubKeys *SignedUpstreamTLSPubKeys) error {
 	if err := c.conn.Invoke(ctx, methodSetUpstreamTLSPubKeys.FullName(), pubKeys, nil); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *sentryClient) GetUpstreamTLSPubKeys(ctx context.Context) ([]signature.PublicKey, error) {
+func (c *sentryClient) GetUpstreamTLSPubKeys(ctx context.Context, nodeID signature.PublicKey) ([]signature.PublicKey, error) {
 	var rsp []signature.PublicKey
-	if err := c.conn.Invoke(ctx, methodGetUpstreamTLSPubKeys.FullName(), nil, &rsp); err != nil {
+	if err := c.conn.Invoke(ctx, methodGetUpstreamTLSPubKeys.FullName(), nodeID, &rsp); err != nil {
 		return nil, err
 	}
 	return rsp, nil
@@ -25,7 +25,9 @@ type backend struct {
 	consensus consensus.Backend
 	identity  *identity.Identity
 
-	upstreamTLSPubKeys []signature.PublicKey
+	// upstreamTLSPubKeys are the TLS public keys of upstream nodes, indexed
+	// by the upstream node's ID.
+	upstreamTLSPubKeys map[signature.PublicKey][]signature.PublicKey
 }
 
 func (b *backend) GetAddresses(ctx context.Context) (*api.SentryAddresses, error) {
@@ -66,20 +68,34 @@ func (b *backend) GetAddresses(ctx context.Context) (*api.SentryAddresses, error
 	}, nil
 }
 
-func (b *backend) SetUpstreamTLSPubKeys(ctx context.Context, pubKeys []signature.PublicKey) error {
+func (b *backend) SetUpstreamTLSPubKeys(ctx context.Context, sigPubKeys *api.SignedUpstreamTLSPubKeys) error {
+	// Keep track of the keys of each upstream node separately, so that
+	// multiple upstream nodes can use the same sentry node and each of
+	// them can only be reached using its own keys.
+	var pubKeys api.UpstreamTLSPubKeys
+	if err := sigPubKeys.Open(&pubKeys); err != nil {
+		return fmt.Errorf("sentry: invalid upstream TLS public keys signature: %w", err)
+	}
+	nodeID := sigPubKeys.Signature.PublicKey
+
 	b.Lock()
 	defer b.Unlock()
 
-	b.upstreamTLSPubKeys = pubKeys
+	b.upstreamTLSPubKeys[nodeID] = pubKeys.PubKeys
+
+	b.logger.Debug("updated upstream TLS public keys",
+		"node_id", nodeID,
+		"num_keys", len(pubKeys.PubKeys),
+	)
 
 	return nil
 }
 
-func (b *backend) GetUpstreamTLSPubKeys(ctx context.Context) ([]signature.PublicKey, error) {
+func (b *backend) GetUpstreamTLSPubKeys(ctx context.Context, nodeID signature.PublicKey) ([]signature.PublicKey, error) {
 	b.RLock()
 	defer b.RUnlock()
 
-	return b.upstreamTLSPubKeys, nil
+	return b.upstreamTLSPubKeys[nodeID], nil
 }
 
 // New constructs a new sentry Backend instance.
@@ -92,9 +108,10 @@ func New(
 	}
 
 	b := &backend{
-		logger:    logging.GetLogger("sentry"),
-		consensus: consensusBackend,
-		identity:  identity,
+		logger:             logging.GetLogger("sentry"),
+		consensus:          consensusBackend,
+		identity:           identity,
+		upstreamTLSPubKeys: make(map[signature.PublicKey][]signature.PublicKey),
 	}
 
 	return b, nil
@@ -57,8 +57,62 @@ var (
 	// is specified in a query.
 	ErrInvalidThreshold = errors.New(ModuleName, 6, "staking: invalid threshold")
 
+	// ErrTooManyAllowances is the error returned when the number of allowances
+	// per account would exceed the maximum allowed number.
+	ErrTooManyAllowances = errors.New(ModuleName, 7, "staking: too many allowances")
+
+	// ErrTransfersDisabled is the error returned when transfers from the
+	// account are disabled.
+	ErrTransfersDisabled = errors.New(ModuleName, 8, "staking: transfers disabled")
+
+	// ErrDelegationDisabled is the error returned when delegating to or
+	// reclaiming from other accounts is disabled.
+	ErrDelegationDisabled = errors.New(ModuleName, 9, "staking: delegation disabled")
+
+	// ErrBelowMinDelegationAmount is the error returned when the amount of
+	// escrowed tokens is below the minimum delegation amount.
+	ErrBelowMinDelegationAmount = errors.New(ModuleName, 10, "staking: amount below minimum delegation amount")
+
+	// ErrAllowancesDisabled is the error returned when allowances are
+	// disabled.
+	ErrAllowancesDisabled = errors.New(ModuleName, 11, "staking: allowances disabled")
+
+	// ErrSelfAllowance is the error returned when an account attempts to
+	// give itself an allowance or to withdraw from itself.
+	ErrSelfAllowance = errors.New(ModuleName, 12, "staking: allowance owner and beneficiary must differ")
+
+	// ErrInsufficientAllowance is the error returned when a withdrawal
+	// exceeds the allowance.
+	ErrInsufficientAllowance = errors.New(ModuleName, 13, "staking: insufficient allowance")
+
+	// ErrInsufficientShares is the error returned when reclaiming more shares
+	// than the delegation holds.
+	ErrInsufficientShares = errors.New(ModuleName, 14, "staking: insufficient shares")
+
+	// ErrInvalidCommissionSchedule is the error returned when a commission
+	// schedule amendment violates the commission schedule rules or bounds.
+	ErrInvalidCommissionSchedule = errors.New(ModuleName, 15, "staking: invalid commission schedule amendment")
+
+	// ErrInvalidCommissionDestinations is the error returned when the
+	// commission destinations are invalid.
+	ErrInvalidCommissionDestinations = errors.New(ModuleName, 16, "staking: invalid commission destinations")
+
+	// ErrTransferBatchDisabled is the error returned when batch transfers
+	// are disabled.
+	ErrTransferBatchDisabled = errors.New(ModuleName, 17, "staking: batch transfers disabled")
+
+	// ErrTooManyTransfers is the error returned when a batch transfer
+	// contains more transfers than the maximum allowed number.
+	ErrTooManyTransfers = errors.New(ModuleName, 18, "staking: too many transfers in batch")
+
+	// ErrNoSuchPendingSlash is the error returned when a pending slash does
+	// not exist.
+	ErrNoSuchPendingSlash = errors.New(ModuleName, 19, "staking: no such pending slash")
+
 	// MethodTransfer is the method name for transfers.
 	MethodTransfer = transaction.NewMethodName(ModuleName, "Transfer", Transfer{})
+	// MethodTransferBatch is the method name for batch transfers.
+	MethodTransferBatch = transaction.NewMethodName(ModuleName, "TransferBatch", TransferBatch{})
 	// MethodBurn is the method name for burns.
 	MethodBurn = transaction.NewMethodName(ModuleName, "Burn", Burn{})
 	// MethodAddEscrow is the method name for escrows.
@@ -67,14 +121,27 @@ var (
 	MethodReclaimEscrow = transaction.NewMethodName(ModuleName, "ReclaimEscrow", ReclaimEscrow{})
 	// MethodAmendCommissionSchedule is the method name for amending commission schedules.
 	MethodAmendCommissionSchedule = transaction.NewMethodName(ModuleName, "AmendCommissionSchedule", AmendCommissionSchedule{})
+	// MethodSetCommissionDestinations is the method name for setting commission destinations.
+	MethodSetCommissionDestinations = transaction.NewMethodName(ModuleName, "SetCommissionDestinations", SetCommissionDestinations{})
+	// MethodAllow is the method name for setting a beneficiary allowance.
+	MethodAllow = transaction.NewMethodName(ModuleName, "Allow", Allow{})
+	// MethodWithdraw is the method name for withdrawing from an allowance.
+	MethodWithdraw = transaction.NewMethodName(ModuleName, "Withdraw", Withdraw{})
+	// MethodCancelSlash is the method name for voting to cancel a pending slash.
+	MethodCancelSlash = transaction.NewMethodName(ModuleName, "CancelSlash", CancelSlash{})
 
 	// Methods is the list of all methods supported by the staking backend.
 	Methods = []transaction.MethodName{
 		MethodTransfer,
+		MethodTransferBatch,
 		MethodBurn,
 		MethodAddEscrow,
 		MethodReclaimEscrow,
 		MethodAmendCommissionSchedule,
+		MethodSetCommissionDestinations,
+		MethodAllow,
+		MethodWithdraw,
+		MethodCancelSlash,
 	}
 )
 
@@ -107,9 +174,37 @@ type Backend interface {
 	// the given owner (delegator).
 	DebondingDelegations(ctx context.Context, query *OwnerQuery) (map[signature.PublicKey][]*DebondingDelegation, error)
 
+	// DelegationInfos returns the list of delegations for the given owner
+	// (delegator), together with their token value and the commission rate
+	// of the escrow account they are delegated to.
+	DelegationInfos(ctx context.Context, query *OwnerQuery) (map[signature.PublicKey]*DelegationInfo, error)
+
+	// EscrowSummary returns the summary of the escrow account of the given
+	// owner.
+	EscrowSummary(ctx context.Context, query *OwnerQuery) (*EscrowSummary, error)
+
+	// StakeClaims returns the stake claims against the escrow account of the
+	// given owner together with the amount of stake locked by each of them.
+	StakeClaims(ctx context.Context, query *OwnerQuery) (*StakeClaimSummary, error)
+
+	// PendingSlashes returns the slashes that have been scheduled but not
+	// yet executed or cancelled.
+	PendingSlashes(ctx context.Context, height int64) ([]*PendingSlash, error)
+
+	// SimulateEpochRewards simulates the disbursement of the epoch signing
+	// rewards at the next epoch transition, given the signing statistics,
+	// reward schedule and commission schedules at the specified block height.
+	SimulateEpochRewards(ctx context.Context, height int64) (*RewardSimulation, error)
+
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
+	// ExportLedger exports the accounts, delegations and debonding
+	// delegations at the specified block height in chunks, calling the
+	// given function for each chunk. The last chunk carries the hash of
+	// the exported ledger.
+	ExportLedger(ctx context.Context, query *LedgerExportQuery, fn func(*LedgerChunk) error) error
+
 	// Paremeters returns the staking consensus parameters.
 	ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error)
 
@@ -126,6 +221,10 @@ type Backend interface {
 	// general balance.
 	WatchEscrows(ctx context.Context) (<-chan *EscrowEvent, pubsub.ClosableSubscription, error)
 
+	// WatchEvents returns a channel that produces a stream of all staking
+	// events, annotated with the consensus height and transaction hash.
+	WatchEvents(ctx context.Context) (<-chan *Event, pubsub.ClosableSubscription, error)
+
 	// GetEvents returns the events at specified block height.
 	GetEvents(ctx context.Context, height int64) ([]Event, error)
 
@@ -159,6 +258,14 @@ type BurnEvent struct {
 	Tokens quantity.Quantity   `json:"tokens"`
 }
 
+// MintEvent is the event emitted when tokens are minted to pay rewards.
+//
+// Minted tokens are added to the common pool (and the total supply) and are
+// then paid out from the common pool.
+type MintEvent struct {
+	Tokens quantity.Quantity `json:"tokens"`
+}
+
 // EscrowEvent is an escrow event.
 type EscrowEvent struct {
 	Add     *AddEscrowEvent     `json:"add,omitempty"`
@@ -166,13 +273,22 @@ type EscrowEvent struct {
 	Reclaim *ReclaimEscrowEvent `json:"reclaim,omitempty"`
 }
 
-// Event signifies a staking event, returned via GetEvents.
+// Event signifies a staking event, returned via GetEvents and WatchEvents.
 type Event struct {
+	// Height is the consensus height at which the event was emitted.
+	Height int64 `json:"height,omitempty"`
+	// TxHash is the hash of the transaction that caused the event. It is set
+	// to the empty hash for events not caused by a transaction.
 	TxHash hash.Hash `json:"tx_hash,omitempty"`
 
 	TransferEvent *TransferEvent `json:"transfer,omitempty"`
 	BurnEvent     *BurnEvent     `json:"burn,omitempty"`
+	MintEvent     *MintEvent     `json:"mint,omitempty"`
 	EscrowEvent   *EscrowEvent   `json:"escrow,omitempty"`
+
+	CommissionDestinationsEvent *CommissionDestinationsEvent `json:"commission_destinations,omitempty"`
+	AllowanceChangeEvent        *AllowanceChangeEvent        `json:"allowance_change,omitempty"`
+	PendingSlashEvent           *PendingSlashEvent           `json:"pending_slash,omitempty"`
 }
 
 // AddEscrowEvent is the event emitted when a balance is transfered into
@@ -198,6 +314,26 @@ type ReclaimEscrowEvent struct {
 	Tokens quantity.Quantity   `json:"tokens"`
 }
 
+// CommissionDestinationsEvent is the event emitted when the commission
+// destinations of an escrow account are changed.
+type CommissionDestinationsEvent struct {
+	Escrow       signature.PublicKey     `json:"escrow"`
+	Destinations []CommissionDestination `json:"destinations,omitempty"`
+}
+
+// AllowanceChangeEvent is the event emitted when the allowance of a
+// beneficiary is changed, either by a call to Allow or Withdraw.
+type AllowanceChangeEvent struct {
+	Owner       signature.PublicKey `json:"owner"`
+	Beneficiary signature.PublicKey `json:"beneficiary"`
+	// Allowance is the new allowance of the beneficiary.
+	Allowance quantity.Quantity `json:"allowance"`
+	// Negative is true iff the allowance was decreased.
+	Negative bool `json:"negative,omitempty"`
+	// AmountChange is the absolute value of the allowance change.
+	AmountChange quantity.Quantity `json:"amount_change"`
+}
+
 // Transfer is a token transfer.
 type Transfer struct {
 	To     signature.PublicKey `json:"xfer_to"`
@@ -209,6 +345,16 @@ func NewTransferTx(nonce uint64, fee *transaction.Fee, xfer *Transfer) *transact
 	return transaction.NewTransaction(nonce, fee, MethodTransfer, xfer)
 }
 
+// TransferBatch is an atomic batch of token transfers from the same account.
+type TransferBatch struct {
+	Transfers []Transfer `json:"xfers"`
+}
+
+// NewTransferBatchTx creates a new batch transfer transaction.
+func NewTransferBatchTx(nonce uint64, fee *transaction.Fee, batch *TransferBatch) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodTransferBatch, batch)
+}
+
 // Burn is a token burn (destruction).
 type Burn struct {
 	Tokens quantity.Quantity `json:"burn_tokens"`
@@ -251,6 +397,53 @@ func NewAmendCommissionScheduleTx(nonce uint64, fee *transaction.Fee, amend *Ame
 	return transaction.NewTransaction(nonce, fee, MethodAmendCommissionSchedule, amend)
 }
 
+// SetCommissionDestinations sets the commission destinations of an escrow
+// account.
+type SetCommissionDestinations struct {
+	Destinations []CommissionDestination `json:"destinations,omitempty"`
+}
+
+// NewSetCommissionDestinationsTx creates a new set commission destinations transaction.
+func NewSetCommissionDestinationsTx(nonce uint64, fee *transaction.Fee, set *SetCommissionDestinations) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodSetCommissionDestinations, set)
+}
+
+// Allow is a beneficiary allowance configuration.
+type Allow struct {
+	Beneficiary signature.PublicKey `json:"beneficiary"`
+	// Negative is true iff the allowance should be decreased instead of
+	// increased.
+	Negative     bool              `json:"negative,omitempty"`
+	AmountChange quantity.Quantity `json:"amount_change"`
+}
+
+// NewAllowTx creates a new allow transaction.
+func NewAllowTx(nonce uint64, fee *transaction.Fee, allow *Allow) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodAllow, allow)
+}
+
+// Withdraw is a withdrawal from an account's general balance, which is
+// limited by the allowance the account owner has configured for the signer.
+type Withdraw struct {
+	From   signature.PublicKey `json:"from"`
+	Tokens quantity.Quantity   `json:"tokens"`
+}
+
+// NewWithdrawTx creates a new withdraw transaction.
+func NewWithdrawTx(nonce uint64, fee *transaction.Fee, withdraw *Withdraw) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodWithdraw, withdraw)
+}
+
+// CancelSlash is a slashing authority's vote to cancel a pending slash.
+type CancelSlash struct {
+	ID uint64 `json:"id"`
+}
+
+// NewCancelSlashTx creates a new cancel slash transaction.
+func NewCancelSlashTx(nonce uint64, fee *transaction.Fee, cancel *CancelSlash) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodCancelSlash, cancel)
+}
+
 // SharePool is a combined balance of several entries, the relative sizes
 // of which are tracked through shares.
 type SharePool struct {
@@ -310,8 +503,8 @@ func (p *SharePool) Deposit(shareDst, tokenSrc, tokenAmount *quantity.Quantity)
 	return nil
 }
 
-// tokensForShares computes the amount of tokens for the given amount of shares.
-func (p *SharePool) tokensForShares(amount *quantity.Quantity) (*quantity.Quantity, error) {
+// TokensForShares computes the amount of tokens for the given amount of shares.
+func (p *SharePool) TokensForShares(amount *quantity.Quantity) (*quantity.Quantity, error) {
 	if amount.IsZero() || p.Balance.IsZero() || p.TotalShares.IsZero() {
 		// No existing shares or no balance means no tokens.
 		return quantity.NewQuantity(), nil
@@ -336,7 +529,7 @@ func (p *SharePool) tokensForShares(amount *quantity.Quantity) (*quantity.Quanti
 // Withdraw moves tokens out of the combined balance, reducing the shares.
 // If an error occurs, the pool and affected accounts are left in an invalid state.
 func (p *SharePool) Withdraw(tokenDst, shareSrc, shareAmount *quantity.Quantity) error {
-	tokens, err := p.tokensForShares(shareAmount)
+	tokens, err := p.TokensForShares(shareAmount)
 	if err != nil {
 		return err
 	}
@@ -450,6 +643,10 @@ func (sa *StakeAccumulator) TotalClaims(thresholds map[ThresholdKind]quantity.Qu
 type GeneralAccount struct {
 	Balance quantity.Quantity `json:"balance"`
 	Nonce   uint64            `json:"nonce"`
+
+	// Allowances are the amounts that the given beneficiaries are allowed to
+	// withdraw from the account's general balance.
+	Allowances map[signature.PublicKey]quantity.Quantity `json:"allowances,omitempty"`
 }
 
 // EscrowAccount is an escrow account the balance of which is subject to
@@ -459,6 +656,11 @@ type EscrowAccount struct {
 	Debonding          SharePool          `json:"debonding"`
 	CommissionSchedule CommissionSchedule `json:"commission_schedule"`
 	StakeAccumulator   StakeAccumulator   `json:"stake_accumulator,omitempty"`
+
+	// CommissionDestinations are the destinations of the commission deposited
+	// during reward disbursement. If empty, all commission goes to the escrow
+	// account itself.
+	CommissionDestinations []CommissionDestination `json:"commission_destinations,omitempty"`
 }
 
 // CheckStakeClaims checks whether the escrow account balance satisfies all the stake claims.
@@ -501,6 +703,38 @@ func (e *EscrowAccount) AddStakeClaim(tm map[ThresholdKind]quantity.Quantity, cl
 	return nil
 }
 
+// StakeClaimSummary computes the amount of stake locked by each of the stake
+// claims against the escrow account under the given thresholds.
+func (e *EscrowAccount) StakeClaimSummary(tm map[ThresholdKind]quantity.Quantity) (*StakeClaimSummary, error) {
+	summary := &StakeClaimSummary{
+		Claims: make(map[StakeClaim]*StakeClaimInfo),
+	}
+	for claim, thresholds := range e.StakeAccumulator.Claims {
+		info := &StakeClaimInfo{
+			Thresholds: thresholds,
+		}
+		for _, kind := range thresholds {
+			q := tm[kind]
+			if err := info.Amount.Add(&q); err != nil {
+				return nil, fmt.Errorf("staking: failed to accumulate threshold: %w", err)
+			}
+		}
+		if err := summary.Total.Add(&info.Amount); err != nil {
+			return nil, fmt.Errorf("staking: failed to accumulate claim: %w", err)
+		}
+		summary.Claims[claim] = info
+	}
+
+	// Claims may exceed the balance in case stake was slashed.
+	if e.Active.Balance.Cmp(&summary.Total) > 0 {
+		summary.Available = *e.Active.Balance.Clone()
+		if err := summary.Available.Sub(&summary.Total); err != nil {
+			return nil, fmt.Errorf("staking: failed to compute available stake: %w", err)
+		}
+	}
+	return summary, nil
+}
+
 // RemoveStakeClaim removes a given stake claim.
 //
 // It is an error if the stake claim does not exist.
@@ -528,6 +762,60 @@ type DebondingDelegation struct {
 	DebondEndTime epochtime.EpochTime `json:"debond_end"`
 }
 
+// DelegationInfo is a delegation descriptor with additional information
+// about the escrow account it is delegated to.
+type DelegationInfo struct {
+	Delegation
+
+	// Pool is the active escrow pool of the escrow account. The share price
+	// is given by the ratio of its balance and total shares.
+	Pool SharePool `json:"pool"`
+	// Amount is the amount of tokens the delegated shares are worth.
+	Amount quantity.Quantity `json:"amount"`
+	// CommissionRate is the commission rate of the escrow account in effect
+	// at the queried height, denominated in CommissionRateDenominator.
+	CommissionRate quantity.Quantity `json:"commission_rate"`
+}
+
+// EscrowSummary is a summary of an escrow account.
+type EscrowSummary struct {
+	// Active is the total amount of tokens in the active escrow pool.
+	Active quantity.Quantity `json:"active"`
+	// Debonding is the total amount of tokens in the debonding escrow pool.
+	Debonding quantity.Quantity `json:"debonding"`
+	// CommissionRate is the commission rate in effect at the queried height,
+	// denominated in CommissionRateDenominator.
+	CommissionRate quantity.Quantity `json:"commission_rate"`
+	// CommissionStake is the amount of tokens in the active escrow pool held
+	// by the commission destinations.
+	//
+	// Commission is deposited into the active escrow pool on behalf of the
+	// commission destinations during reward disbursement, so this includes
+	// all commission earned and not yet reclaimed.
+	CommissionStake quantity.Quantity `json:"commission_stake"`
+}
+
+// StakeClaimInfo is a stake claim against an escrow account together with the
+// amount of stake it locks.
+type StakeClaimInfo struct {
+	// Thresholds are the threshold kinds that the claim consists of.
+	Thresholds []ThresholdKind `json:"thresholds"`
+	// Amount is the amount of stake locked by the claim.
+	Amount quantity.Quantity `json:"amount"`
+}
+
+// StakeClaimSummary is a summary of the stake claims against an escrow
+// account.
+type StakeClaimSummary struct {
+	// Claims are the stake claims against the escrow account.
+	Claims map[StakeClaim]*StakeClaimInfo `json:"claims,omitempty"`
+	// Total is the total amount of stake locked by all claims.
+	Total quantity.Quantity `json:"total"`
+	// Available is the amount of stake in the active escrow pool that is
+	// not locked by any claims and can be used to satisfy new claims.
+	Available quantity.Quantity `json:"available"`
+}
+
 // Genesis is the initial ledger balances at genesis for use in the genesis
 // block and test cases.
 type Genesis struct {
@@ -536,11 +824,14 @@ type Genesis struct {
 	TotalSupply   quantity.Quantity `json:"total_supply"`
 	CommonPool    quantity.Quantity `json:"common_pool"`
 	LastBlockFees quantity.Quantity `json:"last_block_fees"`
+	TotalMinted   quantity.Quantity `json:"total_minted"`
 
 	Ledger map[signature.PublicKey]*Account `json:"ledger,omitempty"`
 
 	Delegations          map[signature.PublicKey]map[signature.PublicKey]*Delegation            `json:"delegations,omitempty"`
 	DebondingDelegations map[signature.PublicKey]map[signature.PublicKey][]*DebondingDelegation `json:"debonding_delegations,omitempty"`
+
+	PendingSlashes []*PendingSlash `json:"pending_slashes,omitempty"`
 }
 
 // ConsensusParameters are the staking consensus parameters.
@@ -555,6 +846,21 @@ type ConsensusParameters struct {
 	GasCosts                          transaction.Costs                   `json:"gas_costs,omitempty"`
 	MinDelegationAmount               quantity.Quantity                   `json:"min_delegation"`
 
+	// SlashingAuthorities are the accounts that can vote to cancel pending
+	// slashes.
+	SlashingAuthorities map[signature.PublicKey]bool `json:"slashing_authorities,omitempty"`
+	// SlashCancelThreshold is the number of slashing authority votes needed
+	// to cancel a pending slash.
+	SlashCancelThreshold uint64 `json:"slash_cancel_threshold,omitempty"`
+
+	// MaxAllowances is the maximum number of allowances an account can have.
+	// Zero means that allowances are disabled.
+	MaxAllowances uint32 `json:"max_allowances,omitempty"`
+
+	// MaxTransferBatchSize is the maximum number of transfers in a single
+	// batch transfer. Zero means that batch transfers are disabled.
+	MaxTransferBatchSize uint32 `json:"max_transfer_batch_size,omitempty"`
+
 	DisableTransfers       bool                         `json:"disable_transfers,omitempty"`
 	DisableDelegation      bool                         `json:"disable_delegation,omitempty"`
 	UndisableTransfersFrom map[signature.PublicKey]bool `json:"undisable_transfers_from,omitempty"`
@@ -572,6 +878,14 @@ type ConsensusParameters struct {
 	// RewardFactorBlockProposed is the factor for a reward distributed per block
 	// to the entity that proposed the block.
 	RewardFactorBlockProposed quantity.Quantity `json:"reward_factor_block_proposed"`
+
+	// Minting are the reward minting parameters. If not set, all rewards are
+	// paid from the common pool.
+	Minting *MintingParameters `json:"minting,omitempty"`
+
+	// RewardSources are the accounts whose general balances are swept into
+	// the common pool at epoch boundaries according to their schedules.
+	RewardSources map[signature.PublicKey]RewardSource `json:"reward_sources,omitempty"`
 }
 
 const (
@@ -585,4 +899,12 @@ const (
 	GasOpReclaimEscrow transaction.Op = "reclaim_escrow"
 	// GasOpAmendCommissionSchedule is the gas operation identifier for amend commission schedule.
 	GasOpAmendCommissionSchedule transaction.Op = "amend_commission_schedule"
+	// GasOpSetCommissionDestinations is the gas operation identifier for set commission destinations.
+	GasOpSetCommissionDestinations transaction.Op = "set_commission_destinations"
+	// GasOpAllow is the gas operation identifier for allow.
+	GasOpAllow transaction.Op = "allow"
+	// GasOpWithdraw is the gas operation identifier for withdraw.
+	GasOpWithdraw transaction.Op = "withdraw"
+	// GasOpCancelSlash is the gas operation identifier for cancel slash.
+	GasOpCancelSlash transaction.Op = "cancel_slash"
 )
@@ -41,6 +41,22 @@ func TestConsensusParameters(t *testing.T) {
 		FeeSplitWeightNextPropose: mustInitQuantity(t, 0),
 	}
 	require.Error(degenerateFeeSplit.SanityCheck(), "consensus parameters with degenerate fee split should be invalid")
+
+	// Slash delays.
+	delayedSlash := ConsensusParameters{
+		Thresholds:         validThresholds,
+		FeeSplitWeightVote: mustInitQuantity(t, 1),
+		DebondingInterval:  10,
+		Slashing: map[SlashReason]Slash{
+			SlashRuntimeEquivocation: Slash{Delay: 9},
+		},
+	}
+	require.NoError(delayedSlash.SanityCheck(), "consensus parameters with slash delay less than debonding interval should be valid")
+	delayedSlash.Slashing[SlashRuntimeEquivocation] = Slash{Delay: 10}
+	require.Error(delayedSlash.SanityCheck(), "consensus parameters with slash delay not less than debonding interval should be invalid")
+	delayedSlash.Slashing[SlashDoubleSigning] = Slash{Delay: 1}
+	delete(delayedSlash.Slashing, SlashRuntimeEquivocation)
+	require.Error(delayedSlash.SanityCheck(), "consensus parameters with delayed consensus fault slash should be invalid")
 }
 
 func TestStakeAccumulator(t *testing.T) {
@@ -123,11 +139,30 @@ func TestStakeAccumulator(t *testing.T) {
 	err = acct.CheckStakeClaims(thresholds)
 	require.NoError(err, "escrow account should check out")
 
+	summary, err := acct.StakeClaimSummary(thresholds)
+	require.NoError(err, "StakeClaimSummary")
+	require.Len(summary.Claims, 2, "summary should contain two claims")
+	require.Equal([]ThresholdKind{KindEntity, KindNodeStorage}, summary.Claims[StakeClaim("claim1")].Thresholds)
+	require.Equal(qtyFromInt(3_000), summary.Claims[StakeClaim("claim1")].Amount, "claim1 amount")
+	require.Equal(qtyFromInt(10_000), summary.Claims[StakeClaim("claim3")].Amount, "claim3 amount")
+	require.Equal(qtyFromInt(13_000), summary.Total, "total claimed")
+	require.Equal(qtyFromInt(0), summary.Available, "nothing should be available")
+
 	// Reduce stake.
 	acct.Active.Balance = qtyFromInt(5_000)
 	err = acct.CheckStakeClaims(thresholds)
 	require.Error(err, "escrow account should no longer check out")
 	require.Equal(err, ErrInsufficientStake)
+
+	summary, err = acct.StakeClaimSummary(thresholds)
+	require.NoError(err, "StakeClaimSummary")
+	require.Equal(qtyFromInt(13_000), summary.Total, "total claimed")
+	require.True(summary.Available.IsZero(), "nothing should be available when claims exceed the balance")
+
+	acct.Active.Balance = qtyFromInt(20_000)
+	summary, err = acct.StakeClaimSummary(thresholds)
+	require.NoError(err, "StakeClaimSummary")
+	require.Equal(qtyFromInt(7_000), summary.Available, "available stake")
 }
 
 func qtyFromInt(n int) quantity.Quantity {
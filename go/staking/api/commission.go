@@ -289,6 +289,92 @@ func (cs *CommissionSchedule) CurrentRate(now epochtime.EpochTime) *quantity.Qua
 	return &latestStartedStep.Rate
 }
 
+// currentBound returns the latest bound step that has started or nil if no step has started.
+func (cs *CommissionSchedule) currentBound(now epochtime.EpochTime) *CommissionRateBoundStep {
+	var latestStartedStep *CommissionRateBoundStep
+	for i := range cs.Bounds {
+		step := &cs.Bounds[i]
+		if step.Start > now {
+			break
+		}
+		latestStartedStep = step
+	}
+	return latestStartedStep
+}
+
+// RateInterval is an interval of epochs during which the effective commission
+// rate and rate bounds stay the same.
+type RateInterval struct {
+	// Start is the first epoch of the interval.
+	Start epochtime.EpochTime `json:"start"`
+	// End is the epoch after the last epoch of the interval.
+	End epochtime.EpochTime `json:"end"`
+
+	// Rate is the effective commission rate or nil if no rate step is in
+	// effect during the interval.
+	Rate *quantity.Quantity `json:"rate,omitempty"`
+	// RateMin is the minimum rate or nil if no bound step is in effect
+	// during the interval.
+	RateMin *quantity.Quantity `json:"rate_min,omitempty"`
+	// RateMax is the maximum rate or nil if no bound step is in effect
+	// during the interval.
+	RateMax *quantity.Quantity `json:"rate_max,omitempty"`
+}
+
+// ProjectRates flattens the rate and bound steps into consecutive intervals
+// of constant effective commission rate and rate bounds, covering the epochs
+// in the window [from, to).
+func (cs *CommissionSchedule) ProjectRates(from, to epochtime.EpochTime) []RateInterval {
+	if to <= from {
+		return nil
+	}
+
+	// Collect the epochs within the window at which either the rate or the
+	// bounds change. Both step lists are sorted by start epoch.
+	changes := []epochtime.EpochTime{from}
+	ri, bi := 0, 0
+	for ri < len(cs.Rates) || bi < len(cs.Bounds) {
+		var next epochtime.EpochTime
+		switch {
+		case bi >= len(cs.Bounds) || (ri < len(cs.Rates) && cs.Rates[ri].Start <= cs.Bounds[bi].Start):
+			next = cs.Rates[ri].Start
+			ri++
+		default:
+			next = cs.Bounds[bi].Start
+			bi++
+		}
+		if next <= changes[len(changes)-1] {
+			continue
+		}
+		if next >= to {
+			break
+		}
+		changes = append(changes, next)
+	}
+
+	intervals := make([]RateInterval, 0, len(changes))
+	for i, start := range changes {
+		end := to
+		if i+1 < len(changes) {
+			end = changes[i+1]
+		}
+
+		interval := RateInterval{
+			Start: start,
+			End:   end,
+		}
+		if rate := cs.CurrentRate(start); rate != nil {
+			interval.Rate = rate.Clone()
+		}
+		if bound := cs.currentBound(start); bound != nil {
+			interval.RateMin = bound.RateMin.Clone()
+			interval.RateMax = bound.RateMax.Clone()
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals
+}
+
 func init() {
 	// Denominated in 1000th of a percent.
 	CommissionRateDenominator = quantity.NewQuantity()
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+)
+
+// MaxCommissionDestinations is the maximum number of commission destinations
+// that can be configured for an escrow account.
+const MaxCommissionDestinations = 16
+
+// CommissionDestination is a destination of a portion of the commission
+// deposited into an escrow account during reward disbursement.
+//
+// The commission portion is deposited into the escrow account as a delegation
+// made by the destination account.
+type CommissionDestination struct {
+	// Account is the destination account.
+	Account signature.PublicKey `json:"account"`
+	// Share is the portion of the commission that goes to the destination
+	// account, denominated in CommissionRateDenominator.
+	Share quantity.Quantity `json:"share"`
+}
+
+// ValidateCommissionDestinations validates a list of commission destinations.
+//
+// An empty list is valid and means that all commission goes to the escrow
+// account itself. Otherwise the shares must be non-zero and must add up to
+// exactly CommissionRateDenominator.
+func ValidateCommissionDestinations(dsts []CommissionDestination) error {
+	if len(dsts) == 0 {
+		return nil
+	}
+	if len(dsts) > MaxCommissionDestinations {
+		return fmt.Errorf("%d commission destinations exceeds maximum %d", len(dsts), MaxCommissionDestinations)
+	}
+
+	var total quantity.Quantity
+	seen := make(map[signature.PublicKey]bool)
+	for i, dst := range dsts {
+		if !dst.Account.IsValid() {
+			return fmt.Errorf("commission destination %d has invalid account: %s", i, dst.Account)
+		}
+		if seen[dst.Account] {
+			return fmt.Errorf("commission destination %d has duplicate account: %s", i, dst.Account)
+		}
+		seen[dst.Account] = true
+
+		if !dst.Share.IsValid() || dst.Share.IsZero() {
+			return fmt.Errorf("commission destination %d has invalid share", i)
+		}
+		if err := total.Add(&dst.Share); err != nil {
+			return fmt.Errorf("failed to accumulate commission destination shares: %w", err)
+		}
+	}
+	if total.Cmp(CommissionRateDenominator) != 0 {
+		return fmt.Errorf("commission destination shares %v do not add up to %v", total, CommissionRateDenominator)
+	}
+
+	return nil
+}
+
+// SplitCommission splits the commission amount among the given commission
+// destinations. The returned amounts are in the same order as destinations
+// and any remainder left after rounding is assigned to the first destination.
+//
+// The destinations must have been validated by ValidateCommissionDestinations.
+func SplitCommission(dsts []CommissionDestination, amount *quantity.Quantity) ([]*quantity.Quantity, error) {
+	if len(dsts) == 0 {
+		return nil, fmt.Errorf("no commission destinations")
+	}
+
+	parts := make([]*quantity.Quantity, len(dsts))
+	remaining := amount.Clone()
+	for i := len(dsts) - 1; i > 0; i-- {
+		part := amount.Clone()
+		// Multiply first.
+		if err := part.Mul(&dsts[i].Share); err != nil {
+			return nil, fmt.Errorf("failed multiplying by commission destination share: %w", err)
+		}
+		if err := part.Quo(CommissionRateDenominator); err != nil {
+			return nil, fmt.Errorf("failed dividing by commission rate denominator: %w", err)
+		}
+		if err := remaining.Sub(part); err != nil {
+			return nil, fmt.Errorf("failed subtracting commission destination part: %w", err)
+		}
+		parts[i] = part
+	}
+	parts[0] = remaining
+
+	return parts, nil
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+func TestValidateCommissionDestinations(t *testing.T) {
+	require := require.New(t)
+
+	a := memorySigner.NewTestSigner("commission destinations test: A").Public()
+	b := memorySigner.NewTestSigner("commission destinations test: B").Public()
+
+	require.NoError(ValidateCommissionDestinations(nil), "empty destinations")
+	require.NoError(ValidateCommissionDestinations([]CommissionDestination{
+		{Account: a, Share: mustInitQuantity(t, 100_000)},
+	}), "single destination")
+	require.NoError(ValidateCommissionDestinations([]CommissionDestination{
+		{Account: a, Share: mustInitQuantity(t, 60_000)},
+		{Account: b, Share: mustInitQuantity(t, 40_000)},
+	}), "two destinations")
+
+	requireErrorShowDiagnostic(t, ValidateCommissionDestinations([]CommissionDestination{
+		{Account: a, Share: mustInitQuantity(t, 60_000)},
+		{Account: b, Share: mustInitQuantity(t, 30_000)},
+	}), "shares under denominator")
+	requireErrorShowDiagnostic(t, ValidateCommissionDestinations([]CommissionDestination{
+		{Account: a, Share: mustInitQuantity(t, 60_000)},
+		{Account: b, Share: mustInitQuantity(t, 50_000)},
+	}), "shares over denominator")
+	requireErrorShowDiagnostic(t, ValidateCommissionDestinations([]CommissionDestination{
+		{Account: a, Share: mustInitQuantity(t, 100_000)},
+		{Account: b, Share: mustInitQuantity(t, 0)},
+	}), "zero share")
+	requireErrorShowDiagnostic(t, ValidateCommissionDestinations([]CommissionDestination{
+		{Account: a, Share: mustInitQuantity(t, 50_000)},
+		{Account: a, Share: mustInitQuantity(t, 50_000)},
+	}), "duplicate account")
+
+	var tooMany []CommissionDestination
+	for i := 0; i <= MaxCommissionDestinations; i++ {
+		tooMany = append(tooMany, CommissionDestination{
+			Account: memorySigner.NewTestSigner("commission destinations test: many " + string(rune('a'+i))).Public(),
+			Share:   mustInitQuantity(t, 1),
+		})
+	}
+	requireErrorShowDiagnostic(t, ValidateCommissionDestinations(tooMany), "too many destinations")
+}
+
+func TestSplitCommission(t *testing.T) {
+	require := require.New(t)
+
+	a := memorySigner.NewTestSigner("commission destinations test: A").Public()
+	b := memorySigner.NewTestSigner("commission destinations test: B").Public()
+	c := memorySigner.NewTestSigner("commission destinations test: C").Public()
+
+	_, err := SplitCommission(nil, mustInitQuantityP(t, 100))
+	require.Error(err, "no destinations")
+
+	dsts := []CommissionDestination{
+		{Account: a, Share: mustInitQuantity(t, 50_000)},
+		{Account: b, Share: mustInitQuantity(t, 25_000)},
+		{Account: c, Share: mustInitQuantity(t, 25_000)},
+	}
+	parts, err := SplitCommission(dsts, mustInitQuantityP(t, 1000))
+	require.NoError(err, "SplitCommission")
+	require.Len(parts, 3)
+	require.Equal(mustInitQuantityP(t, 500), parts[0])
+	require.Equal(mustInitQuantityP(t, 250), parts[1])
+	require.Equal(mustInitQuantityP(t, 250), parts[2])
+
+	// Remainder goes to the first destination.
+	parts, err = SplitCommission(dsts, mustInitQuantityP(t, 7))
+	require.NoError(err, "SplitCommission")
+	require.Equal(mustInitQuantityP(t, 5), parts[0])
+	require.Equal(mustInitQuantityP(t, 1), parts[1])
+	require.Equal(mustInitQuantityP(t, 1), parts[2])
+}
@@ -770,3 +770,36 @@ func TestCommissionSchedule(t *testing.T) {
 	require.Equal(t, epochtime.EpochTime(10), cs.Rates[0].Start, "prune 10 rates start")
 	require.Equal(t, epochtime.EpochTime(10), cs.Bounds[0].Start, "prune 10 bounds start")
 }
+
+func TestCommissionScheduleProjectRates(t *testing.T) {
+	require := require.New(t)
+
+	cs := CommissionSchedule{}
+	require.Nil(cs.ProjectRates(10, 10), "empty window")
+	require.Equal([]RateInterval{{Start: 0, End: 100}}, cs.ProjectRates(0, 100), "empty schedule")
+
+	cs = CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{Start: 10, Rate: mustInitQuantity(t, 50_000)},
+			{Start: 20, Rate: mustInitQuantity(t, 40_000)},
+			{Start: 40, Rate: mustInitQuantity(t, 30_000)},
+		},
+		Bounds: []CommissionRateBoundStep{
+			{Start: 10, RateMin: mustInitQuantity(t, 0), RateMax: mustInitQuantity(t, 100_000)},
+			{Start: 30, RateMin: mustInitQuantity(t, 10_000), RateMax: mustInitQuantity(t, 50_000)},
+		},
+	}
+	require.Equal([]RateInterval{
+		{Start: 5, End: 10},
+		{Start: 10, End: 20, Rate: mustInitQuantityP(t, 50_000), RateMin: mustInitQuantityP(t, 0), RateMax: mustInitQuantityP(t, 100_000)},
+		{Start: 20, End: 30, Rate: mustInitQuantityP(t, 40_000), RateMin: mustInitQuantityP(t, 0), RateMax: mustInitQuantityP(t, 100_000)},
+		{Start: 30, End: 40, Rate: mustInitQuantityP(t, 40_000), RateMin: mustInitQuantityP(t, 10_000), RateMax: mustInitQuantityP(t, 50_000)},
+		{Start: 40, End: 45, Rate: mustInitQuantityP(t, 30_000), RateMin: mustInitQuantityP(t, 10_000), RateMax: mustInitQuantityP(t, 50_000)},
+	}, cs.ProjectRates(5, 45), "full schedule")
+	require.Equal([]RateInterval{
+		{Start: 25, End: 30, Rate: mustInitQuantityP(t, 40_000), RateMin: mustInitQuantityP(t, 0), RateMax: mustInitQuantityP(t, 100_000)},
+	}, cs.ProjectRates(25, 30), "window within a single interval")
+	require.Equal([]RateInterval{
+		{Start: 50, End: 60, Rate: mustInitQuantityP(t, 30_000), RateMin: mustInitQuantityP(t, 10_000), RateMax: mustInitQuantityP(t, 50_000)},
+	}, cs.ProjectRates(50, 60), "window after the last step")
+}
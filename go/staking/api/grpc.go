@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"google.golang.org/grpc"
 
@@ -16,27 +18,37 @@ var (
 	serviceName = cmnGrpc.NewServiceName("Staking")
 
 	// methodTotalSupply is the TotalSupply method.
-	methodTotalSupply = serviceName.NewMethod("TotalSupply", int64(0))
+	methodTotalSupply = serviceName.NewMethod("TotalSupply", int64(0)).WithJSONGateway(quantity.Quantity{})
 	// methodCommonPool is the CommonPool method.
-	methodCommonPool = serviceName.NewMethod("CommonPool", int64(0))
+	methodCommonPool = serviceName.NewMethod("CommonPool", int64(0)).WithJSONGateway(quantity.Quantity{})
 	// methodLastBlockFees is the LastBlockFees method.
-	methodLastBlockFees = serviceName.NewMethod("LastBlockFees", int64(0))
+	methodLastBlockFees = serviceName.NewMethod("LastBlockFees", int64(0)).WithJSONGateway(quantity.Quantity{})
 	// methodThreshold is the Threshold method.
-	methodThreshold = serviceName.NewMethod("Threshold", ThresholdQuery{})
+	methodThreshold = serviceName.NewMethod("Threshold", ThresholdQuery{}).WithJSONGateway(quantity.Quantity{})
 	// methodAccounts is the Accounts method.
-	methodAccounts = serviceName.NewMethod("Accounts", int64(0))
+	methodAccounts = serviceName.NewMethod("Accounts", int64(0)).WithJSONGateway([]signature.PublicKey{})
 	// methodAccountInfo is the AccountInfo method.
-	methodAccountInfo = serviceName.NewMethod("AccountInfo", OwnerQuery{})
+	methodAccountInfo = serviceName.NewMethod("AccountInfo", OwnerQuery{}).WithJSONGateway(Account{})
 	// methodDelegations is the Delegations method.
-	methodDelegations = serviceName.NewMethod("Delegations", OwnerQuery{})
+	methodDelegations = serviceName.NewMethod("Delegations", OwnerQuery{}).WithJSONGateway(map[signature.PublicKey]*Delegation{})
 	// methodDebondingDelegations is the DebondingDelegations method.
-	methodDebondingDelegations = serviceName.NewMethod("DebondingDelegations", OwnerQuery{})
+	methodDebondingDelegations = serviceName.NewMethod("DebondingDelegations", OwnerQuery{}).WithJSONGateway(map[signature.PublicKey][]*DebondingDelegation{})
+	// methodDelegationInfos is the DelegationInfos method.
+	methodDelegationInfos = serviceName.NewMethod("DelegationInfos", OwnerQuery{}).WithJSONGateway(map[signature.PublicKey]*DelegationInfo{})
+	// methodEscrowSummary is the EscrowSummary method.
+	methodEscrowSummary = serviceName.NewMethod("EscrowSummary", OwnerQuery{}).WithJSONGateway(EscrowSummary{})
+	// methodStakeClaims is the StakeClaims method.
+	methodStakeClaims = serviceName.NewMethod("StakeClaims", OwnerQuery{}).WithJSONGateway(StakeClaimSummary{})
+	// methodPendingSlashes is the PendingSlashes method.
+	methodPendingSlashes = serviceName.NewMethod("PendingSlashes", int64(0)).WithJSONGateway([]*PendingSlash{})
+	// methodSimulateEpochRewards is the SimulateEpochRewards method.
+	methodSimulateEpochRewards = serviceName.NewMethod("SimulateEpochRewards", int64(0)).WithJSONGateway(RewardSimulation{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodConsensusParameters is the ConsensusParameters method.
-	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0))
+	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0)).WithJSONGateway(ConsensusParameters{})
 	// methodGetEvents is the GetEvents method.
-	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0)).WithJSONGateway([]Event{})
 
 	// methodWatchTransfers is the WatchTransfers method.
 	methodWatchTransfers = serviceName.NewMethod("WatchTransfers", nil)
@@ -44,6 +56,10 @@ var (
 	methodWatchBurns = serviceName.NewMethod("WatchBurns", nil)
 	// methodWatchEscrows is the WatchEscrows method.
 	methodWatchEscrows = serviceName.NewMethod("WatchEscrows", nil)
+	// methodWatchEvents is the WatchEvents method.
+	methodWatchEvents = serviceName.NewMethod("WatchEvents", nil)
+	// methodExportLedger is the ExportLedger method.
+	methodExportLedger = serviceName.NewMethod("ExportLedger", LedgerExportQuery{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -82,6 +98,26 @@ var (
 				MethodName: methodDebondingDelegations.ShortName(),
 				Handler:    handlerDebondingDelegations,
 			},
+			{
+				MethodName: methodDelegationInfos.ShortName(),
+				Handler:    handlerDelegationInfos,
+			},
+			{
+				MethodName: methodEscrowSummary.ShortName(),
+				Handler:    handlerEscrowSummary,
+			},
+			{
+				MethodName: methodStakeClaims.ShortName(),
+				Handler:    handlerStakeClaims,
+			},
+			{
+				MethodName: methodPendingSlashes.ShortName(),
+				Handler:    handlerPendingSlashes,
+			},
+			{
+				MethodName: methodSimulateEpochRewards.ShortName(),
+				Handler:    handlerSimulateEpochRewards,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -111,6 +147,16 @@ var (
 				Handler:       handlerWatchEscrows,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchEvents.ShortName(),
+				Handler:       handlerWatchEvents,
+				ServerStreams: true,
+			},
+			{
+				StreamName:    methodExportLedger.ShortName(),
+				Handler:       handlerExportLedger,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -299,6 +345,121 @@ func handlerDebondingDelegations( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerDelegationInfos( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query OwnerQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).DelegationInfos(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodDelegationInfos.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).DelegationInfos(ctx, req.(*OwnerQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerEscrowSummary( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query OwnerQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).EscrowSummary(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodEscrowSummary.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).EscrowSummary(ctx, req.(*OwnerQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerStakeClaims( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query OwnerQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).StakeClaims(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodStakeClaims.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).StakeClaims(ctx, req.(*OwnerQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerPendingSlashes( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).PendingSlashes(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodPendingSlashes.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).PendingSlashes(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerSimulateEpochRewards( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).SimulateEpochRewards(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSimulateEpochRewards.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).SimulateEpochRewards(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -452,6 +613,45 @@ func handlerWatchEscrows(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchEvents(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchEvents(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func handlerExportLedger(srv interface{}, stream grpc.ServerStream) error {
+	var query LedgerExportQuery
+	if err := stream.RecvMsg(&query); err != nil {
+		return err
+	}
+
+	return srv.(Backend).ExportLedger(stream.Context(), &query, func(chunk *LedgerChunk) error {
+		return stream.SendMsg(chunk)
+	})
+}
+
 // RegisterService registers a new staking backend service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -525,6 +725,46 @@ func (c *stakingClient) DebondingDelegations(ctx context.Context, query *OwnerQu
 	return rsp, nil
 }
 
+func (c *stakingClient) DelegationInfos(ctx context.Context, query *OwnerQuery) (map[signature.PublicKey]*DelegationInfo, error) {
+	var rsp map[signature.PublicKey]*DelegationInfo
+	if err := c.conn.Invoke(ctx, methodDelegationInfos.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *stakingClient) EscrowSummary(ctx context.Context, query *OwnerQuery) (*EscrowSummary, error) {
+	var rsp EscrowSummary
+	if err := c.conn.Invoke(ctx, methodEscrowSummary.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *stakingClient) StakeClaims(ctx context.Context, query *OwnerQuery) (*StakeClaimSummary, error) {
+	var rsp StakeClaimSummary
+	if err := c.conn.Invoke(ctx, methodStakeClaims.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *stakingClient) PendingSlashes(ctx context.Context, height int64) ([]*PendingSlash, error) {
+	var rsp []*PendingSlash
+	if err := c.conn.Invoke(ctx, methodPendingSlashes.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *stakingClient) SimulateEpochRewards(ctx context.Context, height int64) (*RewardSimulation, error) {
+	var rsp RewardSimulation
+	if err := c.conn.Invoke(ctx, methodSimulateEpochRewards.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *stakingClient) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -654,6 +894,91 @@ func (c *stakingClient) WatchEscrows(ctx context.Context) (<-chan *EscrowEvent,
 	return ch, sub, nil
 }
 
+func (c *stakingClient) WatchEvents(ctx context.Context) (<-chan *Event, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[3], methodWatchEvents.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *Event)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev Event
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (c *stakingClient) ExportLedger(ctx context.Context, query *LedgerExportQuery, fn func(*LedgerChunk) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[4], methodExportLedger.FullName())
+	if err != nil {
+		return err
+	}
+	if err = stream.SendMsg(query); err != nil {
+		return err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return err
+	}
+
+	// Verify that the export is complete and consistent before handing out
+	// the last chunk.
+	hasher := NewLedgerHasher()
+	var height int64
+	for index := uint64(0); ; index++ {
+		var chunk LedgerChunk
+		if err = stream.RecvMsg(&chunk); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("staking: ledger export ended without the last chunk")
+			}
+			return err
+		}
+
+		if index == 0 {
+			height = chunk.Height
+		}
+		if chunk.Index != index || chunk.Height != height {
+			return fmt.Errorf("staking: unexpected ledger export chunk %d at height %d", chunk.Index, chunk.Height)
+		}
+		hasher.AddChunk(&chunk)
+		if chunk.IsLast() {
+			if h := hasher.Hash(); !h.Equal(chunk.LedgerHash) || chunk.Exported != chunk.Total {
+				return fmt.Errorf("staking: ledger export verification failed")
+			}
+		}
+
+		if err = fn(&chunk); err != nil {
+			return err
+		}
+		if chunk.IsLast() {
+			return nil
+		}
+	}
+}
+
 func (c *stakingClient) Cleanup() {
 }
 
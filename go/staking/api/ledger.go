@@ -0,0 +1,199 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+// DefaultLedgerChunkSize is the default maximum number of entries in a
+// ledger export chunk.
+const DefaultLedgerChunkSize = 1000
+
+// LedgerExportQuery is a ledger export query.
+type LedgerExportQuery struct {
+	Height int64 `json:"height"`
+
+	// ChunkSize is the maximum number of entries in each chunk. If zero,
+	// DefaultLedgerChunkSize is used.
+	ChunkSize uint32 `json:"chunk_size,omitempty"`
+}
+
+// LedgerAccount is an exported ledger account entry.
+type LedgerAccount struct {
+	ID      signature.PublicKey `json:"id"`
+	Account *Account            `json:"account"`
+}
+
+// LedgerDelegation is an exported ledger delegation entry.
+type LedgerDelegation struct {
+	Escrow     signature.PublicKey `json:"escrow"`
+	Delegator  signature.PublicKey `json:"delegator"`
+	Delegation *Delegation         `json:"delegation"`
+}
+
+// LedgerDebondingDelegations is an exported ledger entry holding all of the
+// debonding delegations of a delegator from an escrow account.
+type LedgerDebondingDelegations struct {
+	Escrow      signature.PublicKey    `json:"escrow"`
+	Delegator   signature.PublicKey    `json:"delegator"`
+	Delegations []*DebondingDelegation `json:"delegations"`
+}
+
+// LedgerChunk is a chunk of the exported staking ledger.
+//
+// Accounts are exported first, followed by delegations and then debonding
+// delegations, each ordered by their identifiers.
+type LedgerChunk struct {
+	// Height is the consensus height at which the ledger was exported.
+	Height int64 `json:"height"`
+	// Index is the index of the chunk in the export.
+	Index uint64 `json:"index"`
+
+	Accounts             []*LedgerAccount              `json:"accounts,omitempty"`
+	Delegations          []*LedgerDelegation           `json:"delegations,omitempty"`
+	DebondingDelegations []*LedgerDebondingDelegations `json:"debonding_delegations,omitempty"`
+
+	// Exported is the number of entries exported up to and including this
+	// chunk.
+	Exported uint64 `json:"exported"`
+	// Total is the total number of entries in the export.
+	Total uint64 `json:"total"`
+
+	// LedgerHash is the hash of all exported entries (see LedgerHasher). It
+	// is only set in the last chunk of the export.
+	LedgerHash *hash.Hash `json:"ledger_hash,omitempty"`
+}
+
+// IsLast returns true iff this is the last chunk of the export.
+func (c *LedgerChunk) IsLast() bool {
+	return c.LedgerHash != nil
+}
+
+// LedgerHasher computes the hash of an exported ledger over all of its
+// entries in export order. The hash does not depend on the chunk size.
+type LedgerHasher struct {
+	b *hash.Builder
+}
+
+// AddChunk adds all entries of the given chunk to the hash.
+func (h *LedgerHasher) AddChunk(chunk *LedgerChunk) {
+	for _, e := range chunk.Accounts {
+		h.add(e)
+	}
+	for _, e := range chunk.Delegations {
+		h.add(e)
+	}
+	for _, e := range chunk.DebondingDelegations {
+		h.add(e)
+	}
+}
+
+func (h *LedgerHasher) add(entry interface{}) {
+	_, _ = h.b.Write(cbor.Marshal(entry))
+}
+
+// Hash returns the hash of all entries added so far.
+func (h *LedgerHasher) Hash() hash.Hash {
+	return h.b.Build()
+}
+
+// NewLedgerHasher creates a new ledger hasher.
+func NewLedgerHasher() *LedgerHasher {
+	return &LedgerHasher{b: hash.NewBuilder()}
+}
+
+// LedgerExporter splits the exported ledger into chunks.
+//
+// Entries must be added in export order, after which Finish must be called
+// to emit the last chunk.
+type LedgerExporter struct {
+	height    int64
+	chunkSize uint32
+	total     uint64
+	fn        func(*LedgerChunk) error
+
+	hasher   *LedgerHasher
+	chunk    *LedgerChunk
+	entries  uint32
+	index    uint64
+	exported uint64
+}
+
+// AddAccount adds an account entry to the export.
+func (e *LedgerExporter) AddAccount(id signature.PublicKey, acct *Account) error {
+	entry := &LedgerAccount{ID: id, Account: acct}
+	e.chunk.Accounts = append(e.chunk.Accounts, entry)
+	return e.added(entry)
+}
+
+// AddDelegation adds a delegation entry to the export.
+func (e *LedgerExporter) AddDelegation(escrowID, delegatorID signature.PublicKey, del *Delegation) error {
+	entry := &LedgerDelegation{Escrow: escrowID, Delegator: delegatorID, Delegation: del}
+	e.chunk.Delegations = append(e.chunk.Delegations, entry)
+	return e.added(entry)
+}
+
+// AddDebondingDelegations adds a debonding delegations entry to the export.
+func (e *LedgerExporter) AddDebondingDelegations(escrowID, delegatorID signature.PublicKey, debs []*DebondingDelegation) error {
+	entry := &LedgerDebondingDelegations{Escrow: escrowID, Delegator: delegatorID, Delegations: debs}
+	e.chunk.DebondingDelegations = append(e.chunk.DebondingDelegations, entry)
+	return e.added(entry)
+}
+
+func (e *LedgerExporter) added(entry interface{}) error {
+	e.hasher.add(entry)
+	e.entries++
+	e.exported++
+	if e.exported > e.total {
+		return fmt.Errorf("staking: ledger export has more than %d entries", e.total)
+	}
+
+	// Keep the last chunk around so that it can carry the ledger hash.
+	if e.entries < e.chunkSize || e.exported == e.total {
+		return nil
+	}
+	return e.flush()
+}
+
+func (e *LedgerExporter) flush() error {
+	chunk := e.chunk
+	chunk.Exported = e.exported
+
+	e.index++
+	e.entries = 0
+	e.chunk = &LedgerChunk{Height: e.height, Index: e.index, Total: e.total}
+
+	return e.fn(chunk)
+}
+
+// Finish emits the last chunk of the export.
+func (e *LedgerExporter) Finish() error {
+	if e.exported != e.total {
+		return fmt.Errorf("staking: ledger export has %d entries, expected %d", e.exported, e.total)
+	}
+
+	h := e.hasher.Hash()
+	e.chunk.LedgerHash = &h
+	return e.flush()
+}
+
+// NewLedgerExporter creates a new ledger exporter for a ledger with the
+// given total number of entries, that passes each chunk to the given
+// function.
+func NewLedgerExporter(height int64, total uint64, chunkSize uint32, fn func(*LedgerChunk) error) *LedgerExporter {
+	if chunkSize == 0 {
+		chunkSize = DefaultLedgerChunkSize
+	}
+
+	return &LedgerExporter{
+		height:    height,
+		chunkSize: chunkSize,
+		total:     total,
+		fn:        fn,
+		hasher:    NewLedgerHasher(),
+		chunk:     &LedgerChunk{Height: height, Total: total},
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+func TestLedgerExporter(t *testing.T) {
+	require := require.New(t)
+
+	var idA, idB, idC signature.PublicKey
+	idA[0], idB[0], idC[0] = 1, 2, 3
+
+	export := func(chunkSize uint32) []*LedgerChunk {
+		var chunks []*LedgerChunk
+		e := NewLedgerExporter(42, 5, chunkSize, func(chunk *LedgerChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		require.NoError(e.AddAccount(idA, &Account{General: GeneralAccount{Nonce: 1}}), "AddAccount")
+		require.NoError(e.AddAccount(idB, &Account{General: GeneralAccount{Nonce: 2}}), "AddAccount")
+		require.NoError(e.AddAccount(idC, &Account{General: GeneralAccount{Nonce: 3}}), "AddAccount")
+		require.NoError(e.AddDelegation(idA, idB, &Delegation{Shares: mustInitQuantity(t, 10)}), "AddDelegation")
+		require.NoError(e.AddDebondingDelegations(idA, idC, []*DebondingDelegation{{DebondEndTime: 5}}), "AddDebondingDelegations")
+		require.NoError(e.Finish(), "Finish")
+		return chunks
+	}
+
+	chunks := export(2)
+	require.Len(chunks, 3, "number of chunks")
+	for i, chunk := range chunks {
+		require.EqualValues(42, chunk.Height, "chunk %d height", i)
+		require.EqualValues(i, chunk.Index, "chunk %d index", i)
+		require.EqualValues(5, chunk.Total, "chunk %d total", i)
+		require.Equal(i == len(chunks)-1, chunk.IsLast(), "chunk %d last", i)
+	}
+	require.Len(chunks[0].Accounts, 2, "first chunk accounts")
+	require.Len(chunks[1].Accounts, 1, "second chunk accounts")
+	require.Len(chunks[1].Delegations, 1, "second chunk delegations")
+	require.Len(chunks[2].DebondingDelegations, 1, "last chunk debonding delegations")
+	require.EqualValues(2, chunks[0].Exported, "first chunk progress")
+	require.EqualValues(5, chunks[2].Exported, "last chunk progress")
+
+	// The ledger hash should match the hash of all entries.
+	hasher := NewLedgerHasher()
+	for _, chunk := range chunks {
+		hasher.AddChunk(chunk)
+	}
+	h := hasher.Hash()
+	require.True(h.Equal(chunks[2].LedgerHash), "ledger hash")
+
+	// The ledger hash should not depend on the chunk size.
+	chunks = export(0)
+	require.Len(chunks, 1, "number of chunks with default chunk size")
+	require.True(h.Equal(chunks[0].LedgerHash), "ledger hash with default chunk size")
+
+	// Empty ledgers should produce a single empty chunk.
+	chunks = nil
+	e := NewLedgerExporter(1, 0, 0, func(chunk *LedgerChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(e.Finish(), "Finish")
+	require.Len(chunks, 1, "number of chunks for an empty ledger")
+	require.True(chunks[0].IsLast(), "empty ledger chunk should be last")
+
+	// Exports with an unexpected number of entries should fail.
+	e = NewLedgerExporter(1, 1, 0, func(chunk *LedgerChunk) error { return nil })
+	require.Error(e.Finish(), "Finish with missing entries")
+	require.NoError(e.AddAccount(idA, &Account{}), "AddAccount")
+	require.Error(e.AddAccount(idB, &Account{}), "AddAccount with too many entries")
+}
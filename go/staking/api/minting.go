@@ -0,0 +1,66 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+)
+
+// MintingStep is one of the time periods in the minting schedule.
+type MintingStep struct {
+	// Until is the epoch (exclusive) until which the step is active.
+	Until epochtime.EpochTime `json:"until"`
+	// Rate is the portion of rewards that is minted, denominated in
+	// RewardAmountDenominator. The remainder is paid from the common pool.
+	Rate quantity.Quantity `json:"rate"`
+}
+
+// MintingParameters are the reward minting parameters.
+//
+// When configured, the given portion of each reward is minted (increasing the
+// total supply) instead of being paid from the common pool, for as long as the
+// total supply stays at or below the supply cap.
+type MintingParameters struct {
+	// Schedule is the minting schedule. Past the end of the schedule, all
+	// rewards are paid from the common pool.
+	Schedule []MintingStep `json:"schedule,omitempty"`
+	// SupplyCap is the hard cap on the total supply that minting never
+	// exceeds.
+	SupplyCap quantity.Quantity `json:"supply_cap"`
+}
+
+// SanityCheck performs a sanity check on the minting parameters.
+func (p *MintingParameters) SanityCheck() error {
+	if !p.SupplyCap.IsValid() || p.SupplyCap.IsZero() {
+		return fmt.Errorf("minting supply cap has invalid value")
+	}
+
+	var prevUntil epochtime.EpochTime
+	for i, step := range p.Schedule {
+		if i > 0 && step.Until <= prevUntil {
+			return fmt.Errorf("minting step %d ends at epoch %d, not after previous step ending at epoch %d", i, step.Until, prevUntil)
+		}
+		prevUntil = step.Until
+
+		if !step.Rate.IsValid() {
+			return fmt.Errorf("minting step %d has invalid rate", i)
+		}
+		if step.Rate.Cmp(RewardAmountDenominator) > 0 {
+			return fmt.Errorf("minting step %d rate %v over denominator %v", i, step.Rate, RewardAmountDenominator)
+		}
+	}
+
+	return nil
+}
+
+// CurrentRate returns the minting rate active at the given epoch or nil if
+// past the end of the schedule.
+func (p *MintingParameters) CurrentRate(now epochtime.EpochTime) *quantity.Quantity {
+	for _, step := range p.Schedule {
+		if now < step.Until {
+			return &step.Rate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+)
+
+// EconomicReport is a summary of the economic parameters and of the token
+// distribution of a network at a given epoch.
+type EconomicReport struct {
+	// Epoch is the epoch the report was generated for.
+	Epoch epochtime.EpochTime `json:"epoch"`
+
+	// Supply is the token distribution.
+	Supply SupplyReport `json:"supply"`
+	// Thresholds are the staking thresholds.
+	Thresholds map[ThresholdKind]quantity.Quantity `json:"thresholds,omitempty"`
+	// DebondingInterval is the debonding interval in epochs.
+	DebondingInterval epochtime.EpochTime `json:"debonding_interval"`
+	// MinDelegationAmount is the minimum amount of tokens that can be
+	// delegated.
+	MinDelegationAmount quantity.Quantity `json:"min_delegation"`
+
+	// Rewards are the reward parameters.
+	Rewards RewardsReport `json:"rewards"`
+	// Commission is the distribution of the commission rates and bounds.
+	Commission CommissionReport `json:"commission"`
+	// Emission is the projected emission of signing rewards until the end of
+	// the reward schedule.
+	Emission []*EmissionPeriod `json:"emission,omitempty"`
+}
+
+// SupplyReport is the token distribution of a network.
+type SupplyReport struct {
+	// TotalSupply is the total supply.
+	TotalSupply quantity.Quantity `json:"total_supply"`
+	// TotalMinted is the total amount of tokens minted as rewards.
+	TotalMinted quantity.Quantity `json:"total_minted"`
+	// SupplyCap is the cap on the total supply if minting is enabled.
+	SupplyCap *quantity.Quantity `json:"supply_cap,omitempty"`
+
+	// Circulating is the sum of the general balances of all accounts.
+	Circulating quantity.Quantity `json:"circulating"`
+	// EscrowActive is the sum of the active escrow balances of all
+	// accounts.
+	EscrowActive quantity.Quantity `json:"escrow_active"`
+	// EscrowDebonding is the sum of the debonding escrow balances of all
+	// accounts.
+	EscrowDebonding quantity.Quantity `json:"escrow_debonding"`
+	// CommonPool is the common pool balance.
+	CommonPool quantity.Quantity `json:"common_pool"`
+	// LastBlockFees are the collected fees of the last block.
+	LastBlockFees quantity.Quantity `json:"last_block_fees"`
+
+	// Accounts is the number of accounts.
+	Accounts int `json:"accounts"`
+	// EscrowAccounts is the number of accounts with a non-zero active escrow
+	// balance.
+	EscrowAccounts int `json:"escrow_accounts"`
+}
+
+// RewardsReport are the reward parameters of a network.
+type RewardsReport struct {
+	// FactorEpochSigned is the factor of the per-epoch signing reward.
+	FactorEpochSigned quantity.Quantity `json:"factor_epoch_signed"`
+	// FactorBlockProposed is the factor of the per-block proposal reward.
+	FactorBlockProposed quantity.Quantity `json:"factor_block_proposed"`
+	// SigningThresholdNumerator and SigningThresholdDenominator are the
+	// fraction of blocks an entity must sign to be eligible for the signing
+	// reward.
+	SigningThresholdNumerator   uint64 `json:"signing_threshold_numerator"`
+	SigningThresholdDenominator uint64 `json:"signing_threshold_denominator"`
+
+	// Schedule is the reward schedule.
+	Schedule []RewardStep `json:"schedule,omitempty"`
+	// Horizon is the epoch at which the reward schedule ends.
+	Horizon epochtime.EpochTime `json:"horizon"`
+	// RemainingEpochs is the number of epochs until the end of the reward
+	// schedule.
+	RemainingEpochs epochtime.EpochTime `json:"remaining_epochs"`
+
+	// Minting are the reward minting parameters if minting is enabled.
+	Minting *MintingParameters `json:"minting,omitempty"`
+}
+
+// CommissionReport is the distribution of the commission rates and bounds in
+// effect for the accounts with a non-zero active escrow balance.
+type CommissionReport struct {
+	// Rules are the commission schedule rules.
+	Rules CommissionScheduleRules `json:"rules"`
+
+	// Rates is the distribution of the commission rates.
+	Rates *RateDistribution `json:"rates,omitempty"`
+	// BoundsMin is the distribution of the lower commission rate bounds.
+	BoundsMin *RateDistribution `json:"bounds_min,omitempty"`
+	// BoundsMax is the distribution of the upper commission rate bounds.
+	BoundsMax *RateDistribution `json:"bounds_max,omitempty"`
+	// WithoutRate is the number of accounts without a commission rate.
+	WithoutRate int `json:"without_rate"`
+	// WithoutBound is the number of accounts without a commission rate bound.
+	WithoutBound int `json:"without_bound"`
+}
+
+// RateDistribution is the distribution of commission rates, denominated in
+// CommissionRateDenominator.
+type RateDistribution struct {
+	// Count is the number of rates.
+	Count int `json:"count"`
+	// Min is the lowest rate.
+	Min quantity.Quantity `json:"min"`
+	// Median is the median rate.
+	Median quantity.Quantity `json:"median"`
+	// Max is the highest rate.
+	Max quantity.Quantity `json:"max"`
+}
+
+func newRateDistribution(rates []*quantity.Quantity) *RateDistribution {
+	if len(rates) == 0 {
+		return nil
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Cmp(rates[j]) < 0 })
+	return &RateDistribution{
+		Count:  len(rates),
+		Min:    *rates[0].Clone(),
+		Median: *rates[len(rates)/2].Clone(),
+		Max:    *rates[len(rates)-1].Clone(),
+	}
+}
+
+// EmissionPeriod is the projected emission of signing rewards during a period
+// in which both the reward schedule scale and the minting rate are constant.
+type EmissionPeriod struct {
+	// Start is the first epoch of the period.
+	Start epochtime.EpochTime `json:"start"`
+	// End is the epoch (exclusive) at which the period ends.
+	End epochtime.EpochTime `json:"end"`
+	// Scale is the reward schedule scale.
+	Scale quantity.Quantity `json:"scale"`
+	// MintingRate is the minting rate or nil if no rewards are minted.
+	MintingRate *quantity.Quantity `json:"minting_rate,omitempty"`
+
+	// EpochReward is the projected amount of signing rewards per epoch.
+	EpochReward quantity.Quantity `json:"epoch_reward"`
+	// Reward is the projected amount of signing rewards during the period.
+	Reward quantity.Quantity `json:"reward"`
+	// Minted is the projected amount of tokens minted during the period.
+	Minted quantity.Quantity `json:"minted"`
+}
+
+// NewEconomicReport generates an economic report from the given staking
+// genesis state at the given epoch.
+//
+// The emission projection assumes that the escrow balances do not change and
+// that all accounts with a non-zero active escrow balance are eligible for the
+// signing reward in every epoch. Block proposal rewards and fees are not
+// included.
+func NewEconomicReport(g *Genesis, now epochtime.EpochTime) (*EconomicReport, error) {
+	params := &g.Parameters
+	report := &EconomicReport{
+		Epoch: now,
+		Supply: SupplyReport{
+			TotalSupply:   g.TotalSupply,
+			TotalMinted:   g.TotalMinted,
+			CommonPool:    g.CommonPool,
+			LastBlockFees: g.LastBlockFees,
+			Accounts:      len(g.Ledger),
+		},
+		Thresholds:          params.Thresholds,
+		DebondingInterval:   params.DebondingInterval,
+		MinDelegationAmount: params.MinDelegationAmount,
+		Rewards: RewardsReport{
+			FactorEpochSigned:           params.RewardFactorEpochSigned,
+			FactorBlockProposed:         params.RewardFactorBlockProposed,
+			SigningThresholdNumerator:   params.SigningRewardThresholdNumerator,
+			SigningThresholdDenominator: params.SigningRewardThresholdDenominator,
+			Schedule:                    params.RewardSchedule,
+			Minting:                     params.Minting,
+		},
+		Commission: CommissionReport{
+			Rules: params.CommissionScheduleRules,
+		},
+	}
+	if params.Minting != nil {
+		report.Supply.SupplyCap = &params.Minting.SupplyCap
+	}
+
+	var (
+		escrowAccounts              []*Account
+		rates, boundsMin, boundsMax []*quantity.Quantity
+	)
+	for _, acct := range g.Ledger {
+		if err := report.Supply.Circulating.Add(&acct.General.Balance); err != nil {
+			return nil, fmt.Errorf("staking: failed to add general balance: %w", err)
+		}
+		if err := report.Supply.EscrowActive.Add(&acct.Escrow.Active.Balance); err != nil {
+			return nil, fmt.Errorf("staking: failed to add active escrow balance: %w", err)
+		}
+		if err := report.Supply.EscrowDebonding.Add(&acct.Escrow.Debonding.Balance); err != nil {
+			return nil, fmt.Errorf("staking: failed to add debonding escrow balance: %w", err)
+		}
+
+		if acct.Escrow.Active.Balance.IsZero() {
+			continue
+		}
+		escrowAccounts = append(escrowAccounts, acct)
+
+		if rate := acct.Escrow.CommissionSchedule.CurrentRate(now); rate != nil {
+			rates = append(rates, rate)
+		} else {
+			report.Commission.WithoutRate++
+		}
+		if bound := acct.Escrow.CommissionSchedule.currentBound(now); bound != nil {
+			boundsMin = append(boundsMin, &bound.RateMin)
+			boundsMax = append(boundsMax, &bound.RateMax)
+		} else {
+			report.Commission.WithoutBound++
+		}
+	}
+	report.Supply.EscrowAccounts = len(escrowAccounts)
+	report.Commission.Rates = newRateDistribution(rates)
+	report.Commission.BoundsMin = newRateDistribution(boundsMin)
+	report.Commission.BoundsMax = newRateDistribution(boundsMax)
+
+	if n := len(params.RewardSchedule); n > 0 {
+		report.Rewards.Horizon = params.RewardSchedule[n-1].Until
+	}
+	if report.Rewards.Horizon > now {
+		report.Rewards.RemainingEpochs = report.Rewards.Horizon - now
+	}
+
+	var err error
+	if report.Emission, err = projectEmission(g, now, report.Rewards.Horizon, escrowAccounts); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func projectEmission(g *Genesis, now, horizon epochtime.EpochTime, escrowAccounts []*Account) ([]*EmissionPeriod, error) {
+	params := &g.Parameters
+
+	// The amount of tokens that can still be minted.
+	var mintable *quantity.Quantity
+	if params.Minting != nil {
+		mintable = params.Minting.SupplyCap.Clone()
+		if _, err := mintable.SubUpTo(&g.TotalSupply); err != nil {
+			return nil, fmt.Errorf("staking: failed to compute mintable amount: %w", err)
+		}
+	}
+
+	var periods []*EmissionPeriod
+	for start := now; start < horizon; {
+		// The period ends when either the reward step or the minting step
+		// changes.
+		var step *RewardStep
+		for i := range params.RewardSchedule {
+			if start < params.RewardSchedule[i].Until {
+				step = &params.RewardSchedule[i]
+				break
+			}
+		}
+		end := step.Until
+		var mintingRate *quantity.Quantity
+		if params.Minting != nil {
+			for _, ms := range params.Minting.Schedule {
+				if start < ms.Until {
+					mintingRate = ms.Rate.Clone()
+					if ms.Until < end {
+						end = ms.Until
+					}
+					break
+				}
+			}
+		}
+
+		period := &EmissionPeriod{
+			Start:       start,
+			End:         end,
+			Scale:       step.Scale,
+			MintingRate: mintingRate,
+		}
+		var epochMinted quantity.Quantity
+		for _, acct := range escrowAccounts {
+			reward, _, err := ComputeReward(acct, start, &params.RewardFactorEpochSigned, &step.Scale)
+			if err != nil {
+				return nil, err
+			}
+			if err = period.EpochReward.Add(reward); err != nil {
+				return nil, fmt.Errorf("staking: failed to add reward: %w", err)
+			}
+
+			if mintingRate == nil {
+				continue
+			}
+			// Minting is computed per reward, same as during disbursement.
+			if err = reward.Mul(mintingRate); err != nil {
+				return nil, fmt.Errorf("staking: failed multiplying by minting rate: %w", err)
+			}
+			if err = reward.Quo(RewardAmountDenominator); err != nil {
+				return nil, fmt.Errorf("staking: failed dividing by reward amount denominator: %w", err)
+			}
+			if err = epochMinted.Add(reward); err != nil {
+				return nil, fmt.Errorf("staking: failed to add minted amount: %w", err)
+			}
+		}
+
+		var epochs quantity.Quantity
+		if err := epochs.FromUint64(uint64(end - start)); err != nil {
+			return nil, err
+		}
+		period.Reward = *period.EpochReward.Clone()
+		if err := period.Reward.Mul(&epochs); err != nil {
+			return nil, fmt.Errorf("staking: failed multiplying by number of epochs: %w", err)
+		}
+		if mintable != nil {
+			if err := epochMinted.Mul(&epochs); err != nil {
+				return nil, fmt.Errorf("staking: failed multiplying by number of epochs: %w", err)
+			}
+			// Minting never exceeds the supply cap.
+			minted, err := mintable.SubUpTo(&epochMinted)
+			if err != nil {
+				return nil, fmt.Errorf("staking: failed to cap minted amount: %w", err)
+			}
+			period.Minted = *minted
+		}
+
+		periods = append(periods, period)
+		start = end
+	}
+	return periods, nil
+}
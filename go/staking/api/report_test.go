@@ -0,0 +1,121 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+)
+
+func TestEconomicReport(t *testing.T) {
+	require := require.New(t)
+
+	var idA, idB, idC signature.PublicKey
+	idA[0], idB[0], idC[0] = 1, 2, 3
+
+	g := &Genesis{
+		Parameters: ConsensusParameters{
+			DebondingInterval:       7,
+			RewardFactorEpochSigned: mustInitQuantity(t, 1),
+			RewardSchedule: []RewardStep{
+				{Until: 10, Scale: mustInitQuantity(t, 1000)},
+				{Until: 20, Scale: mustInitQuantity(t, 500)},
+			},
+			Minting: &MintingParameters{
+				Schedule: []MintingStep{
+					{Until: 15, Rate: mustInitQuantity(t, 50_000)},
+				},
+				SupplyCap: mustInitQuantity(t, 3_101_165),
+			},
+		},
+		TotalSupply: mustInitQuantity(t, 3_001_165),
+		CommonPool:  mustInitQuantity(t, 1000),
+		Ledger: map[signature.PublicKey]*Account{
+			idA: {
+				General: GeneralAccount{Balance: mustInitQuantity(t, 100)},
+				Escrow: EscrowAccount{
+					Active: SharePool{Balance: mustInitQuantity(t, 1_000_000)},
+					CommissionSchedule: CommissionSchedule{
+						Rates:  []CommissionRateStep{{Start: 0, Rate: mustInitQuantity(t, 20_000)}},
+						Bounds: []CommissionRateBoundStep{{Start: 0, RateMin: mustInitQuantity(t, 0), RateMax: mustInitQuantity(t, 50_000)}},
+					},
+				},
+			},
+			idB: {
+				General: GeneralAccount{Balance: mustInitQuantity(t, 50)},
+				Escrow: EscrowAccount{
+					Active: SharePool{Balance: mustInitQuantity(t, 2_000_000)},
+				},
+			},
+			idC: {
+				General: GeneralAccount{Balance: mustInitQuantity(t, 10)},
+				Escrow: EscrowAccount{
+					Debonding: SharePool{Balance: mustInitQuantity(t, 5)},
+				},
+			},
+		},
+	}
+
+	report, err := NewEconomicReport(g, 5)
+	require.NoError(err, "NewEconomicReport")
+
+	// Supply.
+	require.EqualValues(3, report.Supply.Accounts, "accounts")
+	require.EqualValues(2, report.Supply.EscrowAccounts, "escrow accounts")
+	require.Equal(mustInitQuantity(t, 160), report.Supply.Circulating, "circulating")
+	require.Equal(mustInitQuantity(t, 3_000_000), report.Supply.EscrowActive, "active escrow")
+	require.Equal(mustInitQuantity(t, 5), report.Supply.EscrowDebonding, "debonding escrow")
+	require.Equal(mustInitQuantityP(t, 3_101_165), report.Supply.SupplyCap, "supply cap")
+	require.EqualValues(7, report.DebondingInterval, "debonding interval")
+
+	// Reward schedule horizon.
+	require.EqualValues(20, report.Rewards.Horizon, "horizon")
+	require.EqualValues(15, report.Rewards.RemainingEpochs, "remaining epochs")
+
+	// Commission.
+	require.Equal(&RateDistribution{
+		Count:  1,
+		Min:    mustInitQuantity(t, 20_000),
+		Median: mustInitQuantity(t, 20_000),
+		Max:    mustInitQuantity(t, 20_000),
+	}, report.Commission.Rates, "commission rates")
+	require.EqualValues(1, report.Commission.BoundsMax.Count, "commission upper bounds")
+	require.Equal(mustInitQuantity(t, 50_000), report.Commission.BoundsMax.Max, "commission upper bounds")
+	require.EqualValues(1, report.Commission.WithoutRate, "accounts without commission rate")
+	require.EqualValues(1, report.Commission.WithoutBound, "accounts without commission bound")
+
+	// Emission is split at both reward and minting schedule steps and minting
+	// is capped by the supply cap.
+	require.Len(report.Emission, 3, "emission periods")
+	for i, expected := range []struct {
+		start, end                  uint64
+		epochReward, reward, minted int64
+		minting                     bool
+	}{
+		{5, 10, 30_000, 150_000, 75_000, true},
+		{10, 15, 15_000, 75_000, 25_000, true},
+		{15, 20, 15_000, 75_000, 0, false},
+	} {
+		period := report.Emission[i]
+		require.EqualValues(expected.start, period.Start, "period %d start", i)
+		require.EqualValues(expected.end, period.End, "period %d end", i)
+		require.Equal(mustInitQuantity(t, expected.epochReward), period.EpochReward, "period %d epoch reward", i)
+		require.Equal(mustInitQuantity(t, expected.reward), period.Reward, "period %d reward", i)
+		require.Equal(mustInitQuantity(t, expected.minted), period.Minted, "period %d minted", i)
+		require.Equal(expected.minting, period.MintingRate != nil, "period %d minting", i)
+	}
+
+	// Past the end of the reward schedule there is no emission.
+	report, err = NewEconomicReport(g, 20)
+	require.NoError(err, "NewEconomicReport")
+	require.Empty(report.Emission, "emission past the reward schedule")
+	require.EqualValues(0, report.Rewards.RemainingEpochs, "remaining epochs")
+
+	// Reports without any accounts should work.
+	report, err = NewEconomicReport(&Genesis{}, 0)
+	require.NoError(err, "NewEconomicReport")
+	require.Nil(report.Commission.Rates, "commission rates")
+	require.Equal(quantity.Quantity{}, report.Supply.Circulating, "circulating")
+}
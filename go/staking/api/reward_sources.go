@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+)
+
+// RewardSourceStep is one of the time periods in a reward source schedule.
+//
+// Exactly one of Amount and Rate must be set.
+type RewardSourceStep struct {
+	// Until is the epoch (exclusive) until which the step is active.
+	Until epochtime.EpochTime `json:"until"`
+	// Amount is the fixed amount of tokens swept into the common pool at
+	// each epoch boundary. If the general balance is lower, the whole
+	// general balance is swept.
+	Amount quantity.Quantity `json:"amount,omitempty"`
+	// Rate is the portion of the general balance swept into the common pool
+	// at each epoch boundary, denominated in RewardAmountDenominator.
+	Rate quantity.Quantity `json:"rate,omitempty"`
+}
+
+// SweepAmount returns the amount of tokens swept from the given general
+// balance.
+func (s *RewardSourceStep) SweepAmount(balance *quantity.Quantity) (*quantity.Quantity, error) {
+	if !s.Rate.IsZero() {
+		amount := balance.Clone()
+		if err := amount.Mul(&s.Rate); err != nil {
+			return nil, fmt.Errorf("amount.Mul: %w", err)
+		}
+		if err := amount.Quo(RewardAmountDenominator); err != nil {
+			return nil, fmt.Errorf("amount.Quo: %w", err)
+		}
+		return amount, nil
+	}
+
+	if s.Amount.Cmp(balance) > 0 {
+		return balance.Clone(), nil
+	}
+	return s.Amount.Clone(), nil
+}
+
+// RewardSource is the schedule of an account whose general balance is swept
+// into the common pool at epoch boundaries to fund staking rewards.
+type RewardSource struct {
+	// Schedule is the sweep schedule. Past the end of the schedule, nothing
+	// is swept.
+	Schedule []RewardSourceStep `json:"schedule,omitempty"`
+}
+
+// SanityCheck performs a sanity check on the reward source.
+func (s *RewardSource) SanityCheck() error {
+	var prevUntil epochtime.EpochTime
+	for i, step := range s.Schedule {
+		if i > 0 && step.Until <= prevUntil {
+			return fmt.Errorf("reward source step %d ends at epoch %d, not after previous step ending at epoch %d", i, step.Until, prevUntil)
+		}
+		prevUntil = step.Until
+
+		if !step.Amount.IsValid() {
+			return fmt.Errorf("reward source step %d has invalid amount", i)
+		}
+		if !step.Rate.IsValid() {
+			return fmt.Errorf("reward source step %d has invalid rate", i)
+		}
+		if step.Amount.IsZero() == step.Rate.IsZero() {
+			return fmt.Errorf("reward source step %d must have exactly one of amount and rate set", i)
+		}
+		if step.Rate.Cmp(RewardAmountDenominator) > 0 {
+			return fmt.Errorf("reward source step %d rate %v over denominator %v", i, step.Rate, RewardAmountDenominator)
+		}
+	}
+
+	return nil
+}
+
+// CurrentStep returns the reward source step active at the given epoch or nil
+// if past the end of the schedule.
+func (s *RewardSource) CurrentStep(now epochtime.EpochTime) *RewardSourceStep {
+	for i := range s.Schedule {
+		if now < s.Schedule[i].Until {
+			return &s.Schedule[i]
+		}
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
@@ -24,3 +26,71 @@ func init() {
 		panic(err)
 	}
 }
+
+// RewardSimulation is the projected disbursement of the epoch signing rewards
+// at the next epoch transition, based on the signing statistics collected so
+// far in the current epoch.
+type RewardSimulation struct {
+	// Epoch is the epoch at the start of which the rewards are disbursed.
+	Epoch epochtime.EpochTime `json:"epoch"`
+	// Rewards are the projected rewards of the escrow accounts that are
+	// currently eligible for signing rewards.
+	Rewards map[signature.PublicKey]*RewardProjection `json:"rewards,omitempty"`
+	// Total is the total projected amount of rewards, including commission.
+	Total quantity.Quantity `json:"total"`
+	// Minted is the projected amount of tokens minted to pay the rewards.
+	Minted quantity.Quantity `json:"minted"`
+	// InsufficientCommonPool is true iff the common pool, together with any
+	// minted tokens, would not be able to pay out all of the rewards.
+	InsufficientCommonPool bool `json:"insufficient_common_pool,omitempty"`
+}
+
+// RewardProjection is the projected reward of a single escrow account.
+type RewardProjection struct {
+	// Reward is the amount of tokens deposited into the active escrow pool
+	// on behalf of all delegators.
+	Reward quantity.Quantity `json:"reward"`
+	// Commission is the amount of tokens deposited into the active escrow
+	// pool on behalf of the commission destinations.
+	Commission quantity.Quantity `json:"commission"`
+	// CommissionRate is the commission rate in effect at the disbursement
+	// epoch, denominated in CommissionRateDenominator.
+	CommissionRate quantity.Quantity `json:"commission_rate"`
+}
+
+// ComputeReward computes the reward of the given account for the given reward
+// factor and reward schedule step scale, and the commission portion of it.
+// The returned commission is nil if the account has no commission rate in
+// effect at the given epoch.
+func ComputeReward(
+	acct *Account,
+	now epochtime.EpochTime,
+	factor *quantity.Quantity,
+	scale *quantity.Quantity,
+) (*quantity.Quantity, *quantity.Quantity, error) {
+	q := acct.Escrow.Active.Balance.Clone()
+	// Multiply first.
+	if err := q.Mul(factor); err != nil {
+		return nil, nil, fmt.Errorf("staking: failed multiplying by reward factor: %w", err)
+	}
+	if err := q.Mul(scale); err != nil {
+		return nil, nil, fmt.Errorf("staking: failed multiplying by reward step scale: %w", err)
+	}
+	if err := q.Quo(RewardAmountDenominator); err != nil {
+		return nil, nil, fmt.Errorf("staking: failed dividing by reward amount denominator: %w", err)
+	}
+
+	rate := acct.Escrow.CommissionSchedule.CurrentRate(now)
+	if rate == nil {
+		return q, nil, nil
+	}
+	com := q.Clone()
+	// Multiply first.
+	if err := com.Mul(rate); err != nil {
+		return nil, nil, fmt.Errorf("staking: failed multiplying by commission rate: %w", err)
+	}
+	if err := com.Quo(CommissionRateDenominator); err != nil {
+		return nil, nil, fmt.Errorf("staking: failed dividing by commission rate denominator: %w", err)
+	}
+	return q, com, nil
+}
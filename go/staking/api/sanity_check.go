@@ -36,6 +36,59 @@ func (p *ConsensusParameters) SanityCheck() error {
 		return fmt.Errorf("fee split proportions are all zero")
 	}
 
+	// Slashing.
+	for reason, slash := range p.Slashing {
+		if !slash.Amount.IsValid() {
+			return fmt.Errorf("slash amount for reason '%s' has invalid value", reason)
+		}
+		if reason.IsConsensusFault() && slash.Delay != 0 {
+			return fmt.Errorf("slash delay for consensus fault '%s' must be zero", reason)
+		}
+		// Make sure that stake cannot escape a pending slash by debonding
+		// before the slash is executed.
+		if slash.Delay != 0 && slash.Delay >= p.DebondingInterval {
+			return fmt.Errorf("slash delay for reason '%s' must be less than the debonding interval", reason)
+		}
+	}
+	if p.SlashCancelThreshold > uint64(len(p.SlashingAuthorities)) {
+		return fmt.Errorf("slash cancel threshold exceeds the number of slashing authorities")
+	}
+	if len(p.SlashingAuthorities) > 0 && p.SlashCancelThreshold == 0 {
+		return fmt.Errorf("slash cancel threshold must be non-zero when slashing authorities are configured")
+	}
+
+	// Minting.
+	if p.Minting != nil {
+		if err := p.Minting.SanityCheck(); err != nil {
+			return err
+		}
+	}
+
+	// Reward sources.
+	for id, src := range p.RewardSources {
+		if !id.IsValid() {
+			return fmt.Errorf("reward source account %s is invalid", id)
+		}
+		if err := src.SanityCheck(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SanityCheckMinting checks that the minted token totals are consistent with
+// the total supply and the minting parameters.
+func SanityCheckMinting(parameters *ConsensusParameters, totalSupply, totalMinted *quantity.Quantity) error {
+	if !totalMinted.IsValid() {
+		return fmt.Errorf("staking: sanity check failed: total minted is invalid")
+	}
+	if parameters.Minting == nil {
+		return nil
+	}
+	if totalSupply.Cmp(&parameters.Minting.SupplyCap) > 0 {
+		return fmt.Errorf("staking: sanity check failed: total supply (%s) exceeds minting supply cap (%s)", totalSupply, parameters.Minting.SupplyCap)
+	}
 	return nil
 }
 
@@ -63,6 +116,20 @@ func SanityCheckAccount(total *quantity.Quantity, parameters *ConsensusParameter
 	if err := commissionScheduleShallowCopy.PruneAndValidateForGenesis(&parameters.CommissionScheduleRules, now); err != nil {
 		return fmt.Errorf("staking: sanity check failed: commission schedule for account with ID %s is invalid: %+v", id, err)
 	}
+	if err := ValidateCommissionDestinations(acct.Escrow.CommissionDestinations); err != nil {
+		return fmt.Errorf("staking: sanity check failed: commission destinations for account with ID %s are invalid: %w", id, err)
+	}
+	if uint64(len(acct.General.Allowances)) > uint64(parameters.MaxAllowances) {
+		return fmt.Errorf("staking: sanity check failed: too many allowances for account with ID: %s", id)
+	}
+	for beneficiary, allowance := range acct.General.Allowances {
+		if !beneficiary.IsValid() || beneficiary.Equal(id) {
+			return fmt.Errorf("staking: sanity check failed: invalid allowance beneficiary %s for account with ID: %s", beneficiary, id)
+		}
+		if !allowance.IsValid() || allowance.IsZero() {
+			return fmt.Errorf("staking: sanity check failed: invalid allowance for beneficiary %s of account with ID: %s", beneficiary, id)
+		}
+	}
 
 	return nil
 }
@@ -160,6 +227,44 @@ func SanityCheckAccountShares(id signature.PublicKey, acct *Account, delegations
 	return nil
 }
 
+// SanityCheckPendingSlashes sanity checks pending slashes.
+func SanityCheckPendingSlashes(parameters *ConsensusParameters, slashes []*PendingSlash) error {
+	ids := make(map[uint64]bool)
+	for _, ps := range slashes {
+		if ps == nil {
+			return fmt.Errorf("staking: sanity check failed: pending slash is nil")
+		}
+		if ids[ps.ID] {
+			return fmt.Errorf("staking: sanity check failed: duplicate pending slash ID %d", ps.ID)
+		}
+		ids[ps.ID] = true
+
+		if ps.Reason.IsConsensusFault() {
+			return fmt.Errorf("staking: sanity check failed: pending slash %d is for a consensus fault", ps.ID)
+		}
+		if !ps.Amount.IsValid() {
+			return fmt.Errorf("staking: sanity check failed: pending slash %d has invalid amount", ps.ID)
+		}
+		if ps.ExecuteAt < ps.DetectedAt {
+			return fmt.Errorf("staking: sanity check failed: pending slash %d executes before it was detected", ps.ID)
+		}
+		if len(ps.CancelVotes) > 0 && uint64(len(ps.CancelVotes)) >= parameters.SlashCancelThreshold {
+			return fmt.Errorf("staking: sanity check failed: pending slash %d should have been cancelled", ps.ID)
+		}
+		votes := make(map[signature.PublicKey]bool)
+		for _, v := range ps.CancelVotes {
+			if !parameters.SlashingAuthorities[v] {
+				return fmt.Errorf("staking: sanity check failed: pending slash %d has cancel vote from non-authority %s", ps.ID, v)
+			}
+			if votes[v] {
+				return fmt.Errorf("staking: sanity check failed: pending slash %d has duplicate cancel vote from %s", ps.ID, v)
+			}
+			votes[v] = true
+		}
+	}
+	return nil
+}
+
 // SanityCheck does basic sanity checking on the genesis state.
 func (g *Genesis) SanityCheck(now epochtime.EpochTime) error { // nolint: gocyclo
 	if err := g.Parameters.SanityCheck(); err != nil {
@@ -200,6 +305,10 @@ func (g *Genesis) SanityCheck(now epochtime.EpochTime) error { // nolint: gocycl
 		return fmt.Errorf("staking: sanity check failed: balances in accounts plus common pool (%s) does not add up to total supply (%s)", total.String(), g.TotalSupply.String())
 	}
 
+	if err := SanityCheckMinting(&g.Parameters, &g.TotalSupply, &g.TotalMinted); err != nil {
+		return err
+	}
+
 	// All shares of all delegations for a given account must add up to account's Escrow.Active.TotalShares.
 	for id, delegations := range g.Delegations {
 		acct := g.Ledger[id]
@@ -229,5 +338,9 @@ func (g *Genesis) SanityCheck(now epochtime.EpochTime) error { // nolint: gocycl
 		}
 	}
 
+	if err := SanityCheckPendingSlashes(&g.Parameters, g.PendingSlashes); err != nil {
+		return err
+	}
+
 	return nil
 }
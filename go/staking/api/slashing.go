@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
@@ -11,8 +12,11 @@ type SlashReason int
 const (
 	// SlashDoubleSigning is slashing due to double signing.
 	SlashDoubleSigning SlashReason = 0
+	// SlashRuntimeEquivocation is slashing due to signing conflicting
+	// executor commitments for the same runtime round.
+	SlashRuntimeEquivocation SlashReason = 1
 
-	SlashMax = SlashDoubleSigning
+	SlashMax = SlashRuntimeEquivocation
 )
 
 // String returns a string representation of a SlashReason.
@@ -20,13 +24,74 @@ func (s SlashReason) String() string {
 	switch s {
 	case SlashDoubleSigning:
 		return "double-signing"
+	case SlashRuntimeEquivocation:
+		return "runtime-equivocation"
 	default:
 		return "[unknown slash reason]"
 	}
 }
 
+// IsConsensusFault returns true iff the slash reason is a consensus fault.
+//
+// Consensus faults are always slashed immediately, all other faults can be
+// subject to a delay during which the slash can be cancelled.
+func (s SlashReason) IsConsensusFault() bool {
+	return s == SlashDoubleSigning
+}
+
 // Slash is the per-reason slashing configuration.
 type Slash struct {
 	Amount         quantity.Quantity   `json:"amount"`
 	FreezeInterval epochtime.EpochTime `json:"freeze_interval"`
+
+	// Delay is the number of epochs between fault detection and slash
+	// execution, during which the pending slash can be cancelled by a quorum
+	// of slashing authorities. Zero means that the slash is executed
+	// immediately. Must be zero for consensus faults.
+	Delay epochtime.EpochTime `json:"delay,omitempty"`
+}
+
+// PendingSlash is a slash that has been scheduled for execution at a later
+// epoch and can still be cancelled.
+type PendingSlash struct {
+	// ID is the unique pending slash identifier.
+	ID uint64 `json:"id"`
+	// Reason is the reason for the slash.
+	Reason SlashReason `json:"reason"`
+	// EntityID is the entity whose escrow account will be slashed.
+	EntityID signature.PublicKey `json:"entity_id"`
+	// NodeID is the node that committed the fault.
+	NodeID signature.PublicKey `json:"node_id"`
+	// Amount is the amount of tokens that will be slashed.
+	Amount quantity.Quantity `json:"amount"`
+	// FreezeInterval is the number of epochs the node will be frozen for
+	// once the slash is executed.
+	FreezeInterval epochtime.EpochTime `json:"freeze_interval,omitempty"`
+	// DetectedAt is the epoch in which the fault was detected.
+	DetectedAt epochtime.EpochTime `json:"detected_at"`
+	// ExecuteAt is the epoch at which the slash will be executed unless it
+	// is cancelled before then.
+	ExecuteAt epochtime.EpochTime `json:"execute_at"`
+	// CancelVotes are the slashing authorities that voted to cancel the
+	// slash.
+	CancelVotes []signature.PublicKey `json:"cancel_votes,omitempty"`
+}
+
+// HasCancelVote returns true iff the given slashing authority has already
+// voted to cancel the slash.
+func (ps *PendingSlash) HasCancelVote(id signature.PublicKey) bool {
+	for _, v := range ps.CancelVotes {
+		if v.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingSlashEvent is the event emitted when a pending slash is scheduled,
+// cancelled or executed. Exactly one of the fields is set.
+type PendingSlashEvent struct {
+	Scheduled *PendingSlash `json:"scheduled,omitempty"`
+	Cancelled *PendingSlash `json:"cancelled,omitempty"`
+	Executed  *PendingSlash `json:"executed,omitempty"`
 }
@@ -114,6 +114,44 @@ func main() {
 					}
 				}
 			}
+
+			// Valid set commission destinations transactions.
+			commissionDstA := memorySigner.NewTestSigner("oasis-core staking test vectors: SetCommissionDestinations dst A")
+			commissionDstB := memorySigner.NewTestSigner("oasis-core staking test vectors: SetCommissionDestinations dst B")
+			for _, dsts := range [][]staking.CommissionDestination{
+				nil,
+				{
+					{Account: commissionDstA.Public(), Share: quantityInt64(100_000)},
+				},
+				{
+					{Account: commissionDstA.Public(), Share: quantityInt64(70_000)},
+					{Account: commissionDstB.Public(), Share: quantityInt64(30_000)},
+				},
+			} {
+				tx := staking.NewSetCommissionDestinationsTx(nonce, fee, &staking.SetCommissionDestinations{
+					Destinations: dsts,
+				})
+				vectors = append(vectors, makeTestVector("SetCommissionDestinations", tx))
+			}
+
+			// Valid allow and withdraw transactions.
+			beneficiary := memorySigner.NewTestSigner("oasis-core staking test vectors: Allow beneficiary")
+			for _, amt := range []int64{0, 1000, 10_000_000} {
+				for _, negative := range []bool{false, true} {
+					tx := staking.NewAllowTx(nonce, fee, &staking.Allow{
+						Beneficiary:  beneficiary.Public(),
+						Negative:     negative,
+						AmountChange: quantityInt64(amt),
+					})
+					vectors = append(vectors, makeTestVector("Allow", tx))
+				}
+
+				tx := staking.NewWithdrawTx(nonce, fee, &staking.Withdraw{
+					From:   beneficiary.Public(),
+					Tokens: quantityInt64(amt),
+				})
+				vectors = append(vectors, makeTestVector("Withdraw", tx))
+			}
 		}
 	}
 
@@ -95,6 +95,8 @@ func StakingImplementationTests(
 	}{
 		{"Thresholds", testThresholds},
 		{"LastBlockFees", testLastBlockFees},
+		{"SimulateEpochRewards", testSimulateEpochRewards},
+		{"ExportLedger", testExportLedger},
 		{"Transfer", testTransfer},
 		{"TransferSelf", testSelfTransfer},
 		{"Burn", testBurn},
@@ -121,6 +123,8 @@ func StakingClientImplementationTests(t *testing.T, backend api.Backend, consens
 	}{
 		{"Thresholds", testThresholds},
 		{"LastBlockFees", testLastBlockFees},
+		{"SimulateEpochRewards", testSimulateEpochRewards},
+		{"ExportLedger", testExportLedger},
 		{"Transfer", testTransfer},
 		{"TransferSelf", testSelfTransfer},
 		{"Burn", testBurn},
@@ -158,6 +162,63 @@ func testLastBlockFees(t *testing.T, state *stakingTestsState, backend api.Backe
 	require.True(lastBlockFees.IsZero(), "LastBlockFees - initial value")
 }
 
+func testSimulateEpochRewards(t *testing.T, state *stakingTestsState, backend api.Backend, consensus consensusAPI.Backend) {
+	require := require.New(t)
+
+	sim, err := backend.SimulateEpochRewards(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "SimulateEpochRewards")
+	require.NotNil(sim, "SimulateEpochRewards != nil")
+}
+
+func testExportLedger(t *testing.T, state *stakingTestsState, backend api.Backend, consensus consensusAPI.Backend) {
+	require := require.New(t)
+
+	blk, err := consensus.GetBlock(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetBlock")
+	genesis, err := backend.StateToGenesis(context.Background(), blk.Height)
+	require.NoError(err, "StateToGenesis")
+
+	var chunks []*api.LedgerChunk
+	err = backend.ExportLedger(context.Background(), &api.LedgerExportQuery{Height: blk.Height, ChunkSize: 2}, func(chunk *api.LedgerChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(err, "ExportLedger")
+	require.NotEmpty(chunks, "ExportLedger should return at least one chunk")
+	require.True(chunks[len(chunks)-1].IsLast(), "ExportLedger should end with the last chunk")
+
+	accounts := make(map[signature.PublicKey]*api.Account)
+	var delegations, debondingDelegations int
+	for _, chunk := range chunks {
+		require.Equal(blk.Height, chunk.Height, "chunk height")
+		for _, e := range chunk.Accounts {
+			accounts[e.ID] = e.Account
+		}
+		for _, e := range chunk.Delegations {
+			require.Equal(genesis.Delegations[e.Escrow][e.Delegator], e.Delegation, "exported delegation")
+			delegations++
+		}
+		for _, e := range chunk.DebondingDelegations {
+			require.Equal(genesis.DebondingDelegations[e.Escrow][e.Delegator], e.Delegations, "exported debonding delegations")
+			debondingDelegations++
+		}
+	}
+	require.Len(accounts, len(genesis.Ledger), "exported accounts")
+	for id, acct := range genesis.Ledger {
+		require.Equal(acct.General, accounts[id].General, "exported account general balance")
+		require.Equal(acct.Escrow.Active, accounts[id].Escrow.Active, "exported account active escrow")
+	}
+	var expectedDelegations, expectedDebondingDelegations int
+	for _, dels := range genesis.Delegations {
+		expectedDelegations += len(dels)
+	}
+	for _, debs := range genesis.DebondingDelegations {
+		expectedDebondingDelegations += len(debs)
+	}
+	require.Equal(expectedDelegations, delegations, "exported delegations")
+	require.Equal(expectedDebondingDelegations, debondingDelegations, "exported debonding delegations")
+}
+
 func testTransfer(t *testing.T, state *stakingTestsState, backend api.Backend, consensus consensusAPI.Backend) {
 	require := require.New(t)
 
@@ -167,6 +228,9 @@ func testTransfer(t *testing.T, state *stakingTestsState, backend api.Backend, c
 	srcAcc, err := backend.AccountInfo(context.Background(), &api.OwnerQuery{Owner: SrcID, Height: consensusAPI.HeightLatest})
 	require.NoError(err, "src: AccountInfo - before")
 
+	blk, err := consensus.GetBlock(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetBlock - before")
+
 	ch, sub, err := backend.WatchTransfers(context.Background())
 	require.NoError(err, "WatchTransfers")
 	defer sub.Close()
@@ -238,6 +302,17 @@ TransferWaitLoop:
 	require.Equal(dstAcc.General.Balance, newDstAcc.General.Balance, "dest: general balance - after")
 	require.EqualValues(dstAcc.General.Nonce, newDstAcc.General.Nonce, "dest: nonce - after")
 
+	// Queries at the height before the transfer should return the historical state.
+	oldSrcAcc, err := backend.AccountInfo(context.Background(), &api.OwnerQuery{Owner: SrcID, Height: blk.Height})
+	require.NoError(err, "src: AccountInfo - historical")
+	require.EqualValues(tx.Nonce, oldSrcAcc.General.Nonce, "src: nonce - historical")
+	_ = oldSrcAcc.General.Balance.Sub(&xfer.Tokens)
+	require.Equal(srcAcc.General.Balance, oldSrcAcc.General.Balance, "src: general balance - historical")
+	oldDstAcc, err := backend.AccountInfo(context.Background(), &api.OwnerQuery{Owner: DestID, Height: blk.Height})
+	require.NoError(err, "dest: AccountInfo - historical")
+	_ = oldDstAcc.General.Balance.Add(&xfer.Tokens)
+	require.Equal(dstAcc.General.Balance, oldDstAcc.General.Balance, "dest: general balance - historical")
+
 	// Transfers that exceed available balance should fail.
 	_ = newSrcAcc.General.Balance.Add(&qtyOne)
 	xfer.Tokens = newSrcAcc.General.Balance
@@ -326,6 +401,10 @@ func testBurn(t *testing.T, state *stakingTestsState, backend api.Backend, conse
 	require.NoError(err, "WatchBurns")
 	defer sub.Close()
 
+	evCh, evSub, err := backend.WatchEvents(context.Background())
+	require.NoError(err, "WatchEvents")
+	defer evSub.Close()
+
 	burn := &api.Burn{
 		Tokens: debug.QtyFromInt(math.MaxUint32),
 	}
@@ -355,6 +434,37 @@ func testBurn(t *testing.T, state *stakingTestsState, backend api.Backend, conse
 		t.Fatalf("failed to receive burn event")
 	}
 
+	// Make sure that WatchEvents also returns the burn event with height and
+	// transaction hash annotations.
+BurnEventWaitLoop:
+	for {
+		select {
+		case evt := <-evCh:
+			if evt.BurnEvent == nil {
+				continue
+			}
+			require.Equal(SrcID, evt.BurnEvent.Owner, "WatchEvents: owner")
+			require.Equal(burn.Tokens, evt.BurnEvent.Tokens, "WatchEvents: tokens")
+			require.True(evt.Height > 0, "WatchEvents should return a valid height")
+			require.False(evt.TxHash.IsEmpty(), "WatchEvents should return a valid txn hash")
+
+			evts, grr := backend.GetEvents(context.Background(), evt.Height)
+			require.NoError(grr, "GetEvents")
+			var gotIt bool
+			for _, e := range evts {
+				if e.BurnEvent != nil && e.TxHash.Equal(&evt.TxHash) {
+					require.Equal(evt.Height, e.Height, "GetEvents should return the same height")
+					gotIt = true
+					break
+				}
+			}
+			require.True(gotIt, "GetEvents at the annotated height should return burn event")
+			break BurnEventWaitLoop
+		case <-time.After(recvTimeout):
+			t.Fatalf("failed to receive burn event via WatchEvents")
+		}
+	}
+
 	_ = totalSupply.Sub(&burn.Tokens)
 	newTotalSupply, err := backend.TotalSupply(context.Background(), consensusAPI.HeightLatest)
 	require.NoError(err, "TotalSupply - after")
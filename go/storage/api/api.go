@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
@@ -43,6 +44,15 @@ var (
 	ErrNoMergeRoots = errors.New(ModuleName, 5, "storage: no roots to merge")
 	// ErrLimitReached means that a configured limit has been reached.
 	ErrLimitReached = errors.New(ModuleName, 6, "storage: limit reached")
+	// ErrRootNotPinned is the error returned when a read transaction is
+	// used to read from a root that it has not pinned.
+	ErrRootNotPinned = errors.New(ModuleName, 7, "storage: root not pinned by read transaction")
+	// ErrInvalidReadTxRoots is the error returned when the roots passed to
+	// a read transaction do not belong to the same runtime round.
+	ErrInvalidReadTxRoots = errors.New(ModuleName, 8, "storage: read transaction roots must belong to the same round")
+	// ErrRateLimited is the error returned when a client exceeds the
+	// configured request rate limits. Clients should back off and retry.
+	ErrRateLimited = errors.New(ModuleName, 9, "storage: rate limit exceeded")
 
 	// The following errors are reimports from NodeDB.
 
@@ -71,6 +81,9 @@ var (
 	ErrRootMustFollowOld = nodedb.ErrRootMustFollowOld
 	// ErrReadOnly indicates that the storage backend is read-only.
 	ErrReadOnly = nodedb.ErrReadOnly
+	// ErrQuotaExceeded indicates that a commit failed as it would exceed the
+	// configured storage quota.
+	ErrQuotaExceeded = nodedb.ErrQuotaExceeded
 
 	// ReceiptSignatureContext is the signature context used for verifying MKVS receipts.
 	ReceiptSignatureContext = signature.NewContext("oasis-core/storage: receipt", signature.WithChainSeparation())
@@ -99,9 +112,19 @@ type Config struct { // nolint: maligned
 	// MaxCacheSize is the maximum in-memory cache size for the database.
 	MaxCacheSize int64
 
+	// NodeCachePolicy is the eviction policy of the decoded node cache.
+	NodeCachePolicy string
+
+	// NodeCacheSize is the maximum number of nodes in the decoded node cache.
+	NodeCacheSize uint64
+
 	// DiscardWriteLogs will cause all write logs to be discarded.
 	DiscardWriteLogs bool
 
+	// WriteLogCompression is the compression algorithm used for persisted
+	// write logs.
+	WriteLogCompression string
+
 	// NoFsync will disable fsync() where possible.
 	NoFsync bool
 
@@ -110,6 +133,10 @@ type Config struct { // nolint: maligned
 
 	// ReadOnly will make the storage read-only.
 	ReadOnly bool
+
+	// Quota is the maximum amount of accounted data in bytes. If zero, the
+	// amount of data is not limited.
+	Quota uint64
 }
 
 // ToNodeDB converts from a Config to a node DB Config.
@@ -118,10 +145,15 @@ func (cfg *Config) ToNodeDB() *nodedb.Config {
 		DB:               cfg.DB,
 		Namespace:        cfg.Namespace,
 		MaxCacheSize:     cfg.MaxCacheSize,
+		NodeCachePolicy:  cfg.NodeCachePolicy,
+		NodeCacheSize:    cfg.NodeCacheSize,
 		NoFsync:          cfg.NoFsync,
 		MemoryOnly:       cfg.MemoryOnly,
 		ReadOnly:         cfg.ReadOnly,
 		DiscardWriteLogs: cfg.DiscardWriteLogs,
+
+		WriteLogCompression: cfg.WriteLogCompression,
+		Quota:               cfg.Quota,
 	}
 }
 
@@ -226,6 +258,9 @@ type Proof = syncer.Proof
 // NodeDB is a node database.
 type NodeDB = nodedb.NodeDB
 
+// Usage is the storage usage of a node database.
+type Usage = nodedb.Usage
+
 // ApplyOp is an apply operation within a batch of apply operations.
 type ApplyOp struct {
 	// SrcRound is the source root round.
@@ -359,6 +394,33 @@ type LocalBackend interface {
 type ClientBackend interface {
 	Backend
 
+	ReadTxBackend
+
 	// GetConnectedNodes returns currently connected storage nodes.
 	GetConnectedNodes() []*node.Node
+
+	// GetConnectionStatus returns the read statistics of all currently
+	// connected storage nodes.
+	GetConnectionStatus() []*ReplicaStatus
+}
+
+// ReplicaStatus is the status of a storage node (replica) as observed by a
+// storage client when reading from it.
+type ReplicaStatus struct {
+	// Node is the storage node descriptor.
+	Node *node.Node `json:"node"`
+
+	// Latency is the moving average of the latency of successful reads.
+	// It is zero if no read has succeeded yet.
+	Latency time.Duration `json:"latency"`
+	// Successes is the number of successful reads.
+	Successes uint64 `json:"successes"`
+	// Failures is the number of failed reads.
+	Failures uint64 `json:"failures"`
+	// ConsecutiveFailures is the number of reads that failed since the last
+	// successful read.
+	ConsecutiveFailures uint64 `json:"consecutive_failures"`
+	// DemotedUntil is the time until which the node is only used for reads
+	// in case all other nodes fail. It is zero if the node is not demoted.
+	DemotedUntil time.Time `json:"demoted_until,omitempty"`
 }
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/syncer"
+)
+
+// ReadTx is a read transaction that pins the IO and state roots of a single runtime round.
+//
+// Reads through the transaction are only permitted for the pinned roots. Trees created on top of
+// the transaction (e.g., using mkvs.NewWithRoot) verify all responses against the pinned roots.
+type ReadTx interface {
+	syncer.ReadSyncer
+
+	// IORoot returns the pinned IO root.
+	IORoot() Root
+
+	// StateRoot returns the pinned state root.
+	StateRoot() Root
+}
+
+// ReadTxBackend is a storage backend that supports read transactions.
+type ReadTxBackend interface {
+	// NewReadTx creates a new read transaction pinning the given IO and state roots.
+	NewReadTx(ioRoot, stateRoot Root) (ReadTx, error)
+}
+
+type readTx struct {
+	rs        syncer.ReadSyncer
+	ioRoot    Root
+	stateRoot Root
+}
+
+func (tx *readTx) checkRoot(root *Root) error {
+	if !root.Equal(&tx.ioRoot) && !root.Equal(&tx.stateRoot) {
+		return ErrRootNotPinned
+	}
+	return nil
+}
+
+func (tx *readTx) SyncGet(ctx context.Context, request *GetRequest) (*ProofResponse, error) {
+	if err := tx.checkRoot(&request.Tree.Root); err != nil {
+		return nil, err
+	}
+	return tx.rs.SyncGet(ctx, request)
+}
+
+func (tx *readTx) SyncGetPrefixes(ctx context.Context, request *GetPrefixesRequest) (*ProofResponse, error) {
+	if err := tx.checkRoot(&request.Tree.Root); err != nil {
+		return nil, err
+	}
+	return tx.rs.SyncGetPrefixes(ctx, request)
+}
+
+func (tx *readTx) SyncIterate(ctx context.Context, request *IterateRequest) (*ProofResponse, error) {
+	if err := tx.checkRoot(&request.Tree.Root); err != nil {
+		return nil, err
+	}
+	return tx.rs.SyncIterate(ctx, request)
+}
+
+func (tx *readTx) IORoot() Root {
+	return tx.ioRoot
+}
+
+func (tx *readTx) StateRoot() Root {
+	return tx.stateRoot
+}
+
+// NewReadTx creates a new read transaction pinning the given IO and state roots, which must belong
+// to the same runtime round. All reads are forwarded to the given read syncer.
+func NewReadTx(rs syncer.ReadSyncer, ioRoot, stateRoot Root) (ReadTx, error) {
+	if !ioRoot.Namespace.Equal(&stateRoot.Namespace) || ioRoot.Version != stateRoot.Version {
+		return nil, ErrInvalidReadTxRoots
+	}
+
+	return &readTx{
+		rs:        rs,
+		ioRoot:    ioRoot,
+		stateRoot: stateRoot,
+	}, nil
+}
+
+// BeginReadTx creates a new read transaction pinning the given IO and state roots using the given
+// backend. In case the backend does not support read transactions, a generic read transaction
+// that forwards all reads to the backend is created.
+func BeginReadTx(backend Backend, ioRoot, stateRoot Root) (ReadTx, error) {
+	if txBackend, ok := backend.(ReadTxBackend); ok {
+		return txBackend.NewReadTx(ioRoot, stateRoot)
+	}
+	return NewReadTx(backend, ioRoot, stateRoot)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+)
+
+type countingReadSyncer struct {
+	gets, getPrefixes, iterates int
+}
+
+func (rs *countingReadSyncer) SyncGet(ctx context.Context, request *GetRequest) (*ProofResponse, error) {
+	rs.gets++
+	return &ProofResponse{}, nil
+}
+
+func (rs *countingReadSyncer) SyncGetPrefixes(ctx context.Context, request *GetPrefixesRequest) (*ProofResponse, error) {
+	rs.getPrefixes++
+	return &ProofResponse{}, nil
+}
+
+func (rs *countingReadSyncer) SyncIterate(ctx context.Context, request *IterateRequest) (*ProofResponse, error) {
+	rs.iterates++
+	return &ProofResponse{}, nil
+}
+
+func TestReadTx(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var ns common.Namespace
+	ioRoot := Root{
+		Namespace: ns,
+		Version:   42,
+		Hash:      hash.NewFromBytes([]byte("io root")),
+	}
+	stateRoot := ioRoot
+	stateRoot.Hash = hash.NewFromBytes([]byte("state root"))
+	otherRoot := ioRoot
+	otherRoot.Hash = hash.NewFromBytes([]byte("other root"))
+
+	var rs countingReadSyncer
+	tx, err := NewReadTx(&rs, ioRoot, stateRoot)
+	require.NoError(err, "NewReadTx")
+	require.Equal(ioRoot, tx.IORoot(), "IORoot")
+	require.Equal(stateRoot, tx.StateRoot(), "StateRoot")
+
+	for _, root := range []Root{ioRoot, stateRoot} {
+		_, err = tx.SyncGet(ctx, &GetRequest{Tree: TreeID{Root: root}})
+		require.NoError(err, "SyncGet")
+		_, err = tx.SyncGetPrefixes(ctx, &GetPrefixesRequest{Tree: TreeID{Root: root}})
+		require.NoError(err, "SyncGetPrefixes")
+		_, err = tx.SyncIterate(ctx, &IterateRequest{Tree: TreeID{Root: root}})
+		require.NoError(err, "SyncIterate")
+	}
+	require.Equal(countingReadSyncer{2, 2, 2}, rs, "reads for pinned roots should be forwarded")
+
+	_, err = tx.SyncGet(ctx, &GetRequest{Tree: TreeID{Root: otherRoot}})
+	require.Equal(ErrRootNotPinned, err, "SyncGet should fail for roots that are not pinned")
+	_, err = tx.SyncGetPrefixes(ctx, &GetPrefixesRequest{Tree: TreeID{Root: otherRoot}})
+	require.Equal(ErrRootNotPinned, err, "SyncGetPrefixes should fail for roots that are not pinned")
+	_, err = tx.SyncIterate(ctx, &IterateRequest{Tree: TreeID{Root: otherRoot}})
+	require.Equal(ErrRootNotPinned, err, "SyncIterate should fail for roots that are not pinned")
+	require.Equal(countingReadSyncer{2, 2, 2}, rs, "reads for other roots should not be forwarded")
+
+	otherRoot.Version = 43
+	_, err = NewReadTx(&rs, ioRoot, otherRoot)
+	require.Equal(ErrInvalidReadTxRoots, err, "NewReadTx should fail for roots of different rounds")
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	cryptorand "crypto/rand"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/mathrand"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/runtime/committee"
+	"github.com/oasislabs/oasis-core/go/storage/api"
+)
+
+const (
+	// latencyDecay is the weight of a new latency sample in the moving
+	// average of a node's read latency.
+	latencyDecay = 0.3
+	// probeProbability is the probability that a read is first sent to a
+	// random healthy node instead of the fastest one, so that the latency
+	// estimates of the other nodes are kept up to date.
+	probeProbability = 0.1
+
+	// minDemotion is the duration for which a node is demoted after its
+	// first consecutive failure. Each further consecutive failure doubles
+	// the duration up to maxDemotion.
+	minDemotion = 1 * time.Second
+	// maxDemotion is the maximum duration for which a node is demoted.
+	maxDemotion = 1 * time.Minute
+)
+
+// replicaStats are the read statistics of a single storage node.
+type replicaStats struct {
+	latency             time.Duration
+	successes           uint64
+	failures            uint64
+	consecutiveFailures uint64
+	demotedUntil        time.Time
+}
+
+func (s *replicaStats) demotedAt(now time.Time) bool {
+	return now.Before(s.demotedUntil)
+}
+
+// balancer chooses the order in which storage nodes are tried for reads,
+// preferring nodes with low read latency and demoting failing nodes.
+type balancer struct {
+	sync.Mutex
+
+	rng      *rand.Rand
+	now      func() time.Time
+	replicas map[signature.PublicKey]*replicaStats
+}
+
+func (b *balancer) statsLocked(id signature.PublicKey) *replicaStats {
+	stats := b.replicas[id]
+	if stats == nil {
+		stats = new(replicaStats)
+		b.replicas[id] = stats
+	}
+	return stats
+}
+
+// order returns the order in which the given connections should be tried.
+//
+// Healthy nodes come first, nodes that were never read from (and therefore
+// have no latency estimate) before all others and the remaining ones by
+// their read latency. Occasionally a random healthy node is moved to the
+// front to probe its latency, unless an unmeasured node is already first.
+// Demoted nodes come last, the ones whose demotion expires first before the
+// others.
+func (b *balancer) order(conns []*committee.ClientConnWithMeta) []int {
+	b.Lock()
+	defer b.Unlock()
+
+	now := b.now()
+
+	// Forget about nodes that are no longer connected.
+	connected := make(map[signature.PublicKey]bool, len(conns))
+	for _, conn := range conns {
+		connected[conn.Node.ID] = true
+	}
+	for id := range b.replicas {
+		if !connected[id] {
+			delete(b.replicas, id)
+		}
+	}
+
+	// Shuffle first so that nodes with equal statistics are tried in
+	// random order.
+	order := b.rng.Perm(len(conns))
+	stats := make([]*replicaStats, len(conns))
+	for i, conn := range conns {
+		stats[i] = b.statsLocked(conn.Node.ID)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		si, sj := stats[order[i]], stats[order[j]]
+		di, dj := si.demotedAt(now), sj.demotedAt(now)
+		switch {
+		case di != dj:
+			return dj
+		case di:
+			return si.demotedUntil.Before(sj.demotedUntil)
+		default:
+			return si.latency < sj.latency
+		}
+	})
+
+	var healthy int
+	for _, idx := range order {
+		if stats[idx].demotedAt(now) {
+			break
+		}
+		healthy++
+	}
+	// There is no need to probe if the first node has no latency estimate
+	// yet as reading from it is already a probe.
+	if healthy > 1 && stats[order[0]].successes > 0 && b.rng.Float64() < probeProbability {
+		probe := 1 + b.rng.Intn(healthy-1)
+		order[0], order[probe] = order[probe], order[0]
+	}
+
+	return order
+}
+
+// reportSuccess records a successful read from the given node.
+func (b *balancer) reportSuccess(id signature.PublicKey, latency time.Duration) {
+	b.Lock()
+	defer b.Unlock()
+
+	stats := b.statsLocked(id)
+	if stats.successes == 0 {
+		stats.latency = latency
+	} else {
+		stats.latency = time.Duration(latencyDecay*float64(latency) + (1-latencyDecay)*float64(stats.latency))
+	}
+	stats.successes++
+	stats.consecutiveFailures = 0
+	stats.demotedUntil = time.Time{}
+}
+
+// reportFailure records a failed read from the given node and demotes it.
+func (b *balancer) reportFailure(id signature.PublicKey) {
+	b.Lock()
+	defer b.Unlock()
+
+	stats := b.statsLocked(id)
+	stats.failures++
+	stats.consecutiveFailures++
+
+	demotion := maxDemotion
+	if shift := stats.consecutiveFailures - 1; shift < 32 {
+		if d := minDemotion << shift; d < maxDemotion {
+			demotion = d
+		}
+	}
+	stats.demotedUntil = b.now().Add(demotion)
+}
+
+// status returns the read statistics of the given connections.
+func (b *balancer) status(conns []*committee.ClientConnWithMeta) []*api.ReplicaStatus {
+	b.Lock()
+	defer b.Unlock()
+
+	now := b.now()
+	result := make([]*api.ReplicaStatus, 0, len(conns))
+	for _, conn := range conns {
+		st := &api.ReplicaStatus{
+			Node: conn.Node,
+		}
+		if stats := b.replicas[conn.Node.ID]; stats != nil {
+			st.Latency = stats.latency
+			st.Successes = stats.successes
+			st.Failures = stats.failures
+			st.ConsecutiveFailures = stats.consecutiveFailures
+			if stats.demotedAt(now) {
+				st.DemotedUntil = stats.demotedUntil
+			}
+		}
+		result = append(result, st)
+	}
+	return result
+}
+
+func newBalancer() *balancer {
+	return &balancer{
+		rng:      rand.New(mathrand.New(cryptorand.Reader)),
+		now:      time.Now,
+		replicas: make(map[signature.PublicKey]*replicaStats),
+	}
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/runtime/committee"
+)
+
+func TestBalancer(t *testing.T) {
+	require := require.New(t)
+
+	var conns []*committee.ClientConnWithMeta
+	for i := 0; i < 3; i++ {
+		var id signature.PublicKey
+		id[0] = byte(i)
+		conns = append(conns, &committee.ClientConnWithMeta{Node: &node.Node{ID: id}})
+	}
+	ids := func(order []int) (result []byte) {
+		for _, idx := range order {
+			result = append(result, conns[idx].Node.ID[0])
+		}
+		return
+	}
+
+	now := time.Unix(1000, 0)
+	b := newBalancer()
+	b.now = func() time.Time { return now }
+
+	// Nodes without latency estimates are tried first.
+	b.reportSuccess(conns[0].Node.ID, 30*time.Millisecond)
+	b.reportSuccess(conns[1].Node.ID, 10*time.Millisecond)
+	for i := 0; i < 10; i++ {
+		require.EqualValues(2, ids(b.order(conns))[0], "unmeasured node should be tried first")
+	}
+
+	b.reportSuccess(conns[2].Node.ID, 20*time.Millisecond)
+
+	// Nodes are ordered by latency, with occasional probes of other nodes.
+	var fastest int
+	for i := 0; i < 1000; i++ {
+		order := ids(b.order(conns))
+		require.Len(order, 3)
+		if order[0] == 1 {
+			require.Equal([]byte{1, 2, 0}, order, "nodes should be ordered by latency")
+			fastest++
+		}
+	}
+	require.True(fastest > 800, "fastest node should be tried first most of the time")
+	require.True(fastest < 1000, "other nodes should be probed")
+
+	// Failing nodes are demoted with exponential backoff.
+	b.reportFailure(conns[1].Node.ID)
+	b.reportFailure(conns[1].Node.ID)
+	for i := 0; i < 10; i++ {
+		require.EqualValues(1, ids(b.order(conns))[2], "demoted node should be tried last")
+	}
+	status := b.status(conns)
+	require.Len(status, 3)
+	require.EqualValues(2, status[1].Failures)
+	require.EqualValues(2, status[1].ConsecutiveFailures)
+	require.Equal(now.Add(2*minDemotion), status[1].DemotedUntil)
+
+	now = now.Add(2 * minDemotion)
+	status = b.status(conns)
+	require.True(status[1].DemotedUntil.IsZero(), "demotion should expire")
+
+	for i := 0; i < 20; i++ {
+		b.reportFailure(conns[1].Node.ID)
+	}
+	require.Equal(now.Add(maxDemotion), b.status(conns)[1].DemotedUntil, "demotion should be capped")
+
+	// A successful read clears the demotion.
+	b.reportSuccess(conns[1].Node.ID, 10*time.Millisecond)
+	status = b.status(conns)
+	require.EqualValues(0, status[1].ConsecutiveFailures)
+	require.True(status[1].DemotedUntil.IsZero(), "successful read should clear the demotion")
+	require.EqualValues(2, status[1].Successes)
+
+	// Disconnected nodes are forgotten.
+	b.order(conns[:2])
+	require.EqualValues(0, b.status(conns)[2].Successes, "disconnected node should be forgotten")
+}
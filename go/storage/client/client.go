@@ -4,10 +4,9 @@ package client
 
 import (
 	"context"
-	cryptorand "crypto/rand"
 	"errors"
 	"io"
-	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -16,7 +15,7 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
-	"github.com/oasislabs/oasis-core/go/common/crypto/mathrand"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/runtime/committee"
@@ -48,6 +47,7 @@ type storageClientBackend struct {
 	logger *logging.Logger
 
 	committeeClient committee.Client
+	balancer        *balancer
 }
 
 // GetConnectedNodes returns registry node information about all connected
@@ -60,6 +60,12 @@ func (b *storageClientBackend) GetConnectedNodes() []*node.Node {
 	return nodes
 }
 
+// GetConnectionStatus returns the read statistics of all connected storage
+// nodes.
+func (b *storageClientBackend) GetConnectionStatus() []*api.ReplicaStatus {
+	return b.balancer.status(b.committeeClient.GetConnectionsWithMeta())
+}
+
 type grpcResponse struct {
 	resp interface{}
 	err  error
@@ -105,6 +111,9 @@ func (b *storageClientBackend) writeWithClient(
 				case errors.Is(rerr, api.ErrRootNotFound):
 					// Storage node may not have yet processed the epoch transition.
 					return rerr
+				case errors.Is(rerr, api.ErrRateLimited):
+					// Storage node is rate limiting us, back off.
+					return rerr
 				default:
 					// All other errors are permanent.
 					return backoff.Permanent(rerr)
@@ -283,6 +292,36 @@ func (b *storageClientBackend) readWithClient(
 	ctx context.Context,
 	ns common.Namespace,
 	fn func(context.Context, api.Backend) (interface{}, error),
+) (interface{}, error) {
+	return b.readWithPinnedClient(ctx, ns, nil, fn)
+}
+
+// nodePin tracks the storage node that a read transaction is pinned to.
+type nodePin struct {
+	sync.Mutex
+
+	id *signature.PublicKey
+}
+
+func (p *nodePin) get() *signature.PublicKey {
+	p.Lock()
+	defer p.Unlock()
+	return p.id
+}
+
+func (p *nodePin) set(id signature.PublicKey) {
+	p.Lock()
+	defer p.Unlock()
+	p.id = &id
+}
+
+// readWithPinnedClient performs a read using one of the connected storage nodes. In case a pin is
+// given, the pinned node is tried first and the pin is updated to the node that served the read.
+func (b *storageClientBackend) readWithPinnedClient(
+	ctx context.Context,
+	ns common.Namespace,
+	pin *nodePin,
+	fn func(context.Context, api.Backend) (interface{}, error),
 ) (interface{}, error) {
 	var resp interface{}
 	op := func() error {
@@ -295,15 +334,26 @@ func (b *storageClientBackend) readWithClient(
 			return ErrStorageNotAvailable
 		}
 
-		// TODO: Use a more clever approach to choose the order in which to read
-		// from the connected nodes:
-		// https://github.com/oasislabs/oasis-core/issues/1815.
-		rng := rand.New(mathrand.New(cryptorand.Reader))
+		order := b.balancer.order(conns)
+
+		// Try the pinned node first, if any, keeping the order of the others.
+		if pin != nil {
+			if pinnedID := pin.get(); pinnedID != nil {
+				for i, idx := range order {
+					if conns[idx].Node.ID.Equal(*pinnedID) {
+						copy(order[1:i+1], order[:i])
+						order[0] = idx
+						break
+					}
+				}
+			}
+		}
 
 		var err error
-		for _, randIndex := range rng.Perm(n) {
-			conn := conns[randIndex]
+		for _, idx := range order {
+			conn := conns[idx]
 
+			start := time.Now()
 			resp, err = fn(ctx, api.NewStorageClient(conn.ClientConn))
 			if ctx.Err() != nil {
 				return backoff.Permanent(ctx.Err())
@@ -314,8 +364,13 @@ func (b *storageClientBackend) readWithClient(
 					"err", err,
 					"runtime_id", ns,
 				)
+				b.balancer.reportFailure(conn.Node.ID)
 				continue
 			}
+			b.balancer.reportSuccess(conn.Node.ID, time.Since(start))
+			if pin != nil {
+				pin.set(conn.Node.ID)
+			}
 			return nil
 		}
 		return err
@@ -368,6 +423,66 @@ func (b *storageClientBackend) SyncIterate(ctx context.Context, request *api.Ite
 	return rsp.(*api.ProofResponse), nil
 }
 
+// pinnedReadSyncer is a read syncer that serves all reads from the same storage node for as long
+// as it is available.
+type pinnedReadSyncer struct {
+	b   *storageClientBackend
+	pin nodePin
+}
+
+func (rs *pinnedReadSyncer) SyncGet(ctx context.Context, request *api.GetRequest) (*api.ProofResponse, error) {
+	rsp, err := rs.b.readWithPinnedClient(
+		ctx,
+		request.Tree.Root.Namespace,
+		&rs.pin,
+		func(ctx context.Context, c api.Backend) (interface{}, error) {
+			return c.SyncGet(ctx, request)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rsp.(*api.ProofResponse), nil
+}
+
+func (rs *pinnedReadSyncer) SyncGetPrefixes(ctx context.Context, request *api.GetPrefixesRequest) (*api.ProofResponse, error) {
+	rsp, err := rs.b.readWithPinnedClient(
+		ctx,
+		request.Tree.Root.Namespace,
+		&rs.pin,
+		func(ctx context.Context, c api.Backend) (interface{}, error) {
+			return c.SyncGetPrefixes(ctx, request)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rsp.(*api.ProofResponse), nil
+}
+
+func (rs *pinnedReadSyncer) SyncIterate(ctx context.Context, request *api.IterateRequest) (*api.ProofResponse, error) {
+	rsp, err := rs.b.readWithPinnedClient(
+		ctx,
+		request.Tree.Root.Namespace,
+		&rs.pin,
+		func(ctx context.Context, c api.Backend) (interface{}, error) {
+			return c.SyncIterate(ctx, request)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rsp.(*api.ProofResponse), nil
+}
+
+// NewReadTx creates a new read transaction pinning the given IO and state roots.
+//
+// All reads within the transaction are served by the same storage node for as long as it is
+// available, so that both roots are read from a consistent view of the storage committee.
+func (b *storageClientBackend) NewReadTx(ioRoot, stateRoot api.Root) (api.ReadTx, error) {
+	return api.NewReadTx(&pinnedReadSyncer{b: b}, ioRoot, stateRoot)
+}
+
 func (b *storageClientBackend) GetDiff(ctx context.Context, request *api.GetDiffRequest) (api.WriteLogIterator, error) {
 	rsp, err := b.readWithClient(
 		ctx,
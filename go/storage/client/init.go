@@ -32,6 +32,7 @@ func newClient(
 		ctx:             ctx,
 		logger:          logging.GetLogger("storage/client"),
 		committeeClient: committeeClient,
+		balancer:        newBalancer(),
 	}
 	return b, nil
 }
@@ -64,6 +64,29 @@ func ClientWorkerTests(
 	require.EqualError(err, storageClient.ErrStorageNotAvailable.Error(), "storage client get before initialization")
 	require.Nil(r, "result should be nil")
 
+	// Read transactions should only serve the pinned roots.
+	stateRoot := root
+	stateRoot.Hash = hash.NewFromBytes([]byte("non-existing state"))
+	tx, err := client.(api.ClientBackend).NewReadTx(root, stateRoot)
+	require.NoError(err, "NewReadTx")
+	r, err = tx.SyncGet(ctx, &api.GetRequest{
+		Tree: api.TreeID{
+			Root:     stateRoot,
+			Position: stateRoot.Hash,
+		},
+	})
+	require.EqualError(err, storageClient.ErrStorageNotAvailable.Error(), "read transaction get before initialization")
+	require.Nil(r, "result should be nil")
+	otherRoot := root
+	otherRoot.Hash = hash.NewFromBytes([]byte("not pinned"))
+	_, err = tx.SyncGet(ctx, &api.GetRequest{
+		Tree: api.TreeID{
+			Root:     otherRoot,
+			Position: otherRoot.Hash,
+		},
+	})
+	require.Equal(api.ErrRootNotPinned, err, "read transaction get for a root that is not pinned")
+
 	// Advance the epoch.
 	timeSource := consensus.EpochTime().(epochtime.SetableBackend)
 	epochtimeTests.MustAdvanceEpoch(t, timeSource, 1)
@@ -109,6 +132,13 @@ recvLoop:
 	// Check that all scheduled storage nodes are connected to.
 	require.ElementsMatch(scheduledStorageNodes, connectedNodes, "storage client should be connected to scheduled storage nodes")
 
+	// Check that connection status is reported for all connected nodes.
+	var statusNodes []*node.Node
+	for _, st := range client.(api.ClientBackend).GetConnectionStatus() {
+		statusNodes = append(statusNodes, st.Node)
+	}
+	require.ElementsMatch(connectedNodes, statusNodes, "storage client should report status of connected nodes")
+
 	// Try getting path.
 	// TimeOut is expected, as test nodes do not actually start storage worker.
 	ctx, cancel = context.WithTimeout(ctx, 1*time.Second)
@@ -11,6 +11,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
 	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
 	badgerNodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
@@ -179,30 +180,21 @@ func (ba *databaseBackend) Initialized() <-chan struct{} {
 }
 
 func (ba *databaseBackend) SyncGet(ctx context.Context, request *api.GetRequest) (*api.ProofResponse, error) {
-	tree, err := ba.rootCache.GetTree(ctx, request.Tree.Root)
-	if err != nil {
-		return nil, err
-	}
+	tree := mkvs.NewSnapshot(ba.nodedb, request.Tree.Root)
 	defer tree.Close()
 
 	return tree.SyncGet(ctx, request)
 }
 
 func (ba *databaseBackend) SyncGetPrefixes(ctx context.Context, request *api.GetPrefixesRequest) (*api.ProofResponse, error) {
-	tree, err := ba.rootCache.GetTree(ctx, request.Tree.Root)
-	if err != nil {
-		return nil, err
-	}
+	tree := mkvs.NewSnapshot(ba.nodedb, request.Tree.Root)
 	defer tree.Close()
 
 	return tree.SyncGetPrefixes(ctx, request)
 }
 
 func (ba *databaseBackend) SyncIterate(ctx context.Context, request *api.IterateRequest) (*api.ProofResponse, error) {
-	tree, err := ba.rootCache.GetTree(ctx, request.Tree.Root)
-	if err != nil {
-		return nil, err
-	}
+	tree := mkvs.NewSnapshot(ba.nodedb, request.Tree.Root)
 	defer tree.Close()
 
 	return tree.SyncIterate(ctx, request)
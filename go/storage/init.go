@@ -18,6 +18,8 @@ import (
 	"github.com/oasislabs/oasis-core/go/storage/api"
 	"github.com/oasislabs/oasis-core/go/storage/client"
 	"github.com/oasislabs/oasis-core/go/storage/database"
+	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/cache"
 )
 
 const (
@@ -30,6 +32,23 @@ const (
 	// CfgMaxCacheSize configures the maximum in-memory cache size.
 	CfgMaxCacheSize = "storage.max_cache_size"
 
+	// CfgNodeCachePolicy configures the eviction policy of the decoded node
+	// cache.
+	CfgNodeCachePolicy = "storage.node_cache.policy"
+
+	// CfgNodeCacheSize configures the maximum number of nodes in the decoded
+	// node cache.
+	CfgNodeCacheSize = "storage.node_cache.size"
+
+	// CfgWriteLogCompression configures the compression algorithm used for
+	// persisted write logs.
+	CfgWriteLogCompression = "storage.write_log_compression"
+
+	// CfgQuota configures the per-runtime storage quotas. The quota of a
+	// runtime is configured by setting `storage.quota.<runtime-id>` to the
+	// maximum size (e.g., `10gb`).
+	CfgQuota = "storage.quota"
+
 	cfgCrashEnabled       = "storage.crash.enabled"
 	cfgInsecureSkipChecks = "storage.debug.insecure_skip_checks"
 )
@@ -54,6 +73,11 @@ func New(
 		InsecureSkipChecks: viper.GetBool(cfgInsecureSkipChecks) && cmdFlags.DebugDontBlameOasis(),
 		Namespace:          namespace,
 		MaxCacheSize:       int64(viper.GetSizeInBytes(CfgMaxCacheSize)),
+		NodeCachePolicy:    viper.GetString(CfgNodeCachePolicy),
+		NodeCacheSize:      viper.GetUint64(CfgNodeCacheSize),
+
+		WriteLogCompression: strings.ToLower(viper.GetString(CfgWriteLogCompression)),
+		Quota:               uint64(viper.GetSizeInBytes(CfgQuota + "." + namespace.String())),
 	}
 
 	var (
@@ -87,6 +111,9 @@ func init() {
 	Flags.Bool(cfgCrashEnabled, false, "Enable the crashing storage wrapper")
 	Flags.Int(CfgLRUSlots, 1000, "How many LRU slots to use for Apply call locks in the MKVS tree root cache")
 	Flags.String(CfgMaxCacheSize, "64mb", "Maximum in-memory cache size")
+	Flags.String(CfgNodeCachePolicy, cache.PolicyNone, fmt.Sprintf("Decoded node cache eviction policy (%s, %s, %s or %s)", cache.PolicyNone, cache.PolicyLRU, cache.Policy2Q, cache.PolicyClock))
+	Flags.Uint64(CfgNodeCacheSize, 100000, "Maximum number of nodes in the decoded node cache")
+	Flags.String(CfgWriteLogCompression, nodedb.WriteLogCompressionNone, fmt.Sprintf("Write log compression algorithm (%s, %s or %s)", nodedb.WriteLogCompressionNone, nodedb.WriteLogCompressionSnappy, nodedb.WriteLogCompressionZstd))
 
 	Flags.Bool(cfgInsecureSkipChecks, false, "INSECURE: Skip known root checks")
 
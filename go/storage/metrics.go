@@ -74,6 +74,17 @@ func (w *metricsWrapper) GetConnectedNodes() []*node.Node {
 	return []*node.Node{}
 }
 
+func (w *metricsWrapper) GetConnectionStatus() []*api.ReplicaStatus {
+	if clientBackend, ok := w.Backend.(api.ClientBackend); ok {
+		return clientBackend.GetConnectionStatus()
+	}
+	return []*api.ReplicaStatus{}
+}
+
+func (w *metricsWrapper) NewReadTx(ioRoot, stateRoot api.Root) (api.ReadTx, error) {
+	return api.BeginReadTx(w.Backend, ioRoot, stateRoot)
+}
+
 func (w *metricsWrapper) Apply(ctx context.Context, request *api.ApplyRequest) ([]*api.Receipt, error) {
 	start := time.Now()
 	receipts, err := w.Backend.Apply(ctx, request)
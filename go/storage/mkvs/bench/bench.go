@@ -0,0 +1,325 @@
+// Package bench implements reproducible MKVS macro-benchmarks.
+//
+// Each benchmark run applies a workload profile to a fresh node database
+// for a number of rounds, measuring the commit latency, the size of proofs
+// generated for sampled reads and the growth of the node database.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// BackendBadger is the on-disk Badger node database backend.
+	BackendBadger = "badger"
+	// BackendBadgerMemory is the memory-only Badger node database backend.
+	//
+	// Node database growth is not measured for this backend.
+	BackendBadgerMemory = "badger_memory"
+
+	// maxCacheSize is the maximum in-memory cache size of the node database.
+	maxCacheSize = 64 * 1024 * 1024
+)
+
+// Backends is the list of all supported node database backends.
+var Backends = []string{
+	BackendBadger,
+	BackendBadgerMemory,
+}
+
+// Config is a benchmark configuration.
+type Config struct {
+	// Profile is the workload profile.
+	Profile string `json:"profile"`
+	// Backend is the node database backend.
+	Backend string `json:"backend"`
+	// Seed is the seed of the workload generator.
+	Seed int64 `json:"seed"`
+
+	// Rounds is the number of committed rounds.
+	Rounds uint64 `json:"rounds"`
+	// OpsPerRound is the number of write operations in each round.
+	OpsPerRound uint64 `json:"ops_per_round"`
+	// KeySpace is the number of distinct keys that may be written. It is
+	// ignored by the sequential profile.
+	KeySpace uint64 `json:"key_space"`
+	// ValueSize is the size of each written value in bytes.
+	ValueSize uint64 `json:"value_size"`
+	// ProofSamples is the number of sampled reads in each round.
+	ProofSamples uint64 `json:"proof_samples"`
+
+	// NoFsync will disable fsync() in the node database where possible.
+	NoFsync bool `json:"no_fsync"`
+
+	// Dir is the directory holding the node database. If empty, a temporary
+	// directory is used and removed after the benchmark.
+	Dir string `json:"-"`
+}
+
+// Validate validates the benchmark configuration.
+func (c *Config) Validate() error {
+	var knownProfile, knownBackend bool
+	for _, p := range Profiles {
+		knownProfile = knownProfile || c.Profile == p
+	}
+	for _, b := range Backends {
+		knownBackend = knownBackend || c.Backend == b
+	}
+
+	switch {
+	case !knownProfile:
+		return fmt.Errorf("mkvs/bench: unknown workload profile '%s'", c.Profile)
+	case !knownBackend:
+		return fmt.Errorf("mkvs/bench: unknown node database backend '%s'", c.Backend)
+	case c.Rounds == 0:
+		return fmt.Errorf("mkvs/bench: number of rounds must be positive")
+	case c.OpsPerRound == 0:
+		return fmt.Errorf("mkvs/bench: number of operations per round must be positive")
+	case c.KeySpace == 0:
+		return fmt.Errorf("mkvs/bench: key space must be non-empty")
+	}
+	return nil
+}
+
+// DefaultConfig returns the default benchmark configuration for the given
+// workload profile.
+func DefaultConfig(profile string) Config {
+	return Config{
+		Profile:      profile,
+		Backend:      BackendBadger,
+		Seed:         42,
+		Rounds:       20,
+		OpsPerRound:  1000,
+		KeySpace:     100_000,
+		ValueSize:    128,
+		ProofSamples: 16,
+	}
+}
+
+// Stats are summary statistics of a series of measurements.
+type Stats struct {
+	Count uint64 `json:"count"`
+	Mean  int64  `json:"mean"`
+	Min   int64  `json:"min"`
+	Max   int64  `json:"max"`
+	P50   int64  `json:"p50"`
+	P90   int64  `json:"p90"`
+	P99   int64  `json:"p99"`
+}
+
+func newStats(samples []int64) Stats {
+	if len(samples) == 0 {
+		return Stats{}
+	}
+
+	sorted := append([]int64{}, samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum int64
+	for _, s := range sorted {
+		sum += s
+	}
+	percentile := func(p int) int64 {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+
+	return Stats{
+		Count: uint64(len(sorted)),
+		Mean:  sum / int64(len(sorted)),
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+	}
+}
+
+// RoundResult are the measurements of a single round.
+type RoundResult struct {
+	// Version is the version of the committed root.
+	Version uint64 `json:"version"`
+	// Root is the hash of the committed root.
+	Root hash.Hash `json:"root"`
+	// CommitLatency is the time it took to commit and finalize the round.
+	CommitLatency time.Duration `json:"commit_latency"`
+	// ProofSize is the mean size of proofs for sampled reads in bytes.
+	ProofSize int64 `json:"proof_size"`
+	// NodeDBSize is the size of the node database after the round in bytes.
+	NodeDBSize int64 `json:"node_db_size,omitempty"`
+}
+
+// Result is the result of a benchmark run.
+type Result struct {
+	// Config is the benchmark configuration.
+	Config Config `json:"config"`
+	// Duration is the total duration of the run.
+	Duration time.Duration `json:"duration"`
+
+	// CommitLatency are the commit latency statistics in nanoseconds.
+	CommitLatency Stats `json:"commit_latency"`
+	// ProofSize are the proof size statistics in bytes, over all sampled reads.
+	ProofSize Stats `json:"proof_size"`
+	// ProofEntries are the proof entry count statistics, over all sampled reads.
+	ProofEntries Stats `json:"proof_entries"`
+	// NodeDBGrowth are the per-round node database growth statistics in bytes.
+	//
+	// It is only set for backends that persist data to disk.
+	NodeDBGrowth *Stats `json:"node_db_growth,omitempty"`
+
+	// Rounds are the measurements of each round.
+	Rounds []*RoundResult `json:"rounds"`
+}
+
+// FinalRoot returns the hash of the last committed root.
+//
+// As workloads are deterministic, the final root only depends on the
+// configuration and can be used to check that two results are comparable.
+func (r *Result) FinalRoot() hash.Hash {
+	return r.Rounds[len(r.Rounds)-1].Root
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Run runs the benchmark with the given configuration.
+func Run(ctx context.Context, cfg *Config) (*Result, error) { // nolint: gocyclo
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	dir := cfg.Dir
+	if dir == "" {
+		var err error
+		if dir, err = ioutil.TempDir("", "mkvs-bench"); err != nil {
+			return nil, fmt.Errorf("mkvs/bench: failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+	}
+
+	var ns common.Namespace
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           dir,
+		NoFsync:      cfg.NoFsync,
+		MemoryOnly:   cfg.Backend == BackendBadgerMemory,
+		Namespace:    ns,
+		MaxCacheSize: maxCacheSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mkvs/bench: failed to open node database: %w", err)
+	}
+	defer ndb.Close()
+
+	measureSize := cfg.Backend != BackendBadgerMemory
+	var lastSize int64
+	if measureSize {
+		if lastSize, err = dirSize(dir); err != nil {
+			return nil, fmt.Errorf("mkvs/bench: failed to measure node database size: %w", err)
+		}
+	}
+
+	w := newWorkload(cfg)
+	root := node.Root{Namespace: ns}
+	root.Hash.Empty()
+
+	var (
+		commitLatencies []int64
+		proofSizes      []int64
+		proofEntries    []int64
+		growth          []int64
+	)
+	result := &Result{Config: *cfg}
+	start := time.Now()
+	for version := uint64(0); version < cfg.Rounds; version++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		for i := uint64(0); i < cfg.OpsPerRound; i++ {
+			if err = tree.Insert(ctx, w.writeKey(), w.value()); err != nil {
+				tree.Close()
+				return nil, fmt.Errorf("mkvs/bench: failed to insert: %w", err)
+			}
+		}
+
+		var rootHash hash.Hash
+		commitStart := time.Now()
+		_, rootHash, err = tree.Commit(ctx, ns, version)
+		tree.Close()
+		if err != nil {
+			return nil, fmt.Errorf("mkvs/bench: failed to commit round %d: %w", version, err)
+		}
+		if err = ndb.Finalize(ctx, version, []hash.Hash{rootHash}); err != nil {
+			return nil, fmt.Errorf("mkvs/bench: failed to finalize round %d: %w", version, err)
+		}
+		rr := &RoundResult{
+			Version:       version,
+			Root:          rootHash,
+			CommitLatency: time.Since(commitStart),
+		}
+		commitLatencies = append(commitLatencies, int64(rr.CommitLatency))
+		root = node.Root{Namespace: ns, Version: version, Hash: rootHash}
+
+		// Generate proofs for sampled reads from a fresh tree so that they are
+		// not affected by any previously cached nodes.
+		if cfg.ProofSamples > 0 {
+			snapshot := mkvs.NewWithRoot(nil, ndb, root)
+			var roundProofSize int64
+			for i := uint64(0); i < cfg.ProofSamples; i++ {
+				rsp, serr := w.sampleRead(ctx, snapshot, root)
+				if serr != nil {
+					snapshot.Close()
+					return nil, fmt.Errorf("mkvs/bench: failed to generate proof: %w", serr)
+				}
+				size := int64(len(cbor.Marshal(rsp.Proof)))
+				roundProofSize += size
+				proofSizes = append(proofSizes, size)
+				proofEntries = append(proofEntries, int64(len(rsp.Proof.Entries)))
+			}
+			snapshot.Close()
+			rr.ProofSize = roundProofSize / int64(cfg.ProofSamples)
+		}
+
+		if measureSize {
+			if rr.NodeDBSize, err = dirSize(dir); err != nil {
+				return nil, fmt.Errorf("mkvs/bench: failed to measure node database size: %w", err)
+			}
+			growth = append(growth, rr.NodeDBSize-lastSize)
+			lastSize = rr.NodeDBSize
+		}
+
+		result.Rounds = append(result.Rounds, rr)
+	}
+	result.Duration = time.Since(start)
+
+	result.CommitLatency = newStats(commitLatencies)
+	result.ProofSize = newStats(proofSizes)
+	result.ProofEntries = newStats(proofEntries)
+	if measureSize {
+		growthStats := newStats(growth)
+		result.NodeDBGrowth = &growthStats
+	}
+
+	return result, nil
+}
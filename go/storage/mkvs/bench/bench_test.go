@@ -0,0 +1,99 @@
+package bench
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig(profile, backend string) *Config {
+	cfg := DefaultConfig(profile)
+	cfg.Backend = backend
+	cfg.Rounds = 5
+	cfg.OpsPerRound = 200
+	cfg.KeySpace = 1000
+	cfg.ProofSamples = 4
+	cfg.NoFsync = true
+	return &cfg
+}
+
+func TestRun(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	for _, profile := range Profiles {
+		for _, backend := range Backends {
+			cfg := testConfig(profile, backend)
+			res, err := Run(ctx, cfg)
+			require.NoError(err, "Run(%s, %s)", profile, backend)
+			require.Len(res.Rounds, int(cfg.Rounds), "all rounds should be measured")
+			require.EqualValues(cfg.Rounds, res.CommitLatency.Count, "commit latency samples")
+			require.EqualValues(cfg.Rounds*cfg.ProofSamples, res.ProofSize.Count, "proof size samples")
+			require.True(res.ProofSize.Min > 0, "proofs should not be empty")
+			require.True(res.ProofSize.Min <= res.ProofSize.P50 && res.ProofSize.P50 <= res.ProofSize.Max)
+
+			switch backend {
+			case BackendBadgerMemory:
+				require.Nil(res.NodeDBGrowth, "node database growth should not be measured")
+			default:
+				require.NotNil(res.NodeDBGrowth, "node database growth should be measured")
+				require.True(res.Rounds[len(res.Rounds)-1].NodeDBSize > 0, "node database should grow")
+			}
+
+			// The same configuration should always result in the same roots.
+			again, err := Run(ctx, cfg)
+			require.NoError(err, "Run(%s, %s)", profile, backend)
+			require.Equal(res.FinalRoot(), again.FinalRoot(), "runs should be reproducible")
+		}
+	}
+
+	cfg := testConfig(ProfileUniform, BackendBadgerMemory)
+	cfg.Seed++
+	res, err := Run(ctx, cfg)
+	require.NoError(err, "Run")
+	other, err := Run(ctx, testConfig(ProfileUniform, BackendBadgerMemory))
+	require.NoError(err, "Run")
+	require.NotEqual(res.FinalRoot(), other.FinalRoot(), "different seeds should result in different roots")
+
+	cfg = testConfig("unknown", BackendBadger)
+	_, err = Run(ctx, cfg)
+	require.Error(err, "Run should fail with an unknown profile")
+	cfg = testConfig(ProfileUniform, "unknown")
+	_, err = Run(ctx, cfg)
+	require.Error(err, "Run should fail with an unknown backend")
+}
+
+func benchmarkProfile(b *testing.B, profile string) {
+	cfg := DefaultConfig(profile)
+	cfg.Rounds = 10
+	cfg.NoFsync = true
+
+	var res *Result
+	var err error
+	for n := 0; n < b.N; n++ {
+		if res, err = Run(context.Background(), &cfg); err != nil {
+			b.Fatalf("failed to run benchmark: %v", err)
+		}
+	}
+
+	b.ReportMetric(float64(res.CommitLatency.P50), "commit-p50-ns")
+	b.ReportMetric(float64(res.ProofSize.Mean), "proof-B")
+	b.ReportMetric(float64(res.NodeDBGrowth.Mean), "ndb-growth-B/round")
+}
+
+func BenchmarkUniform(b *testing.B) {
+	benchmarkProfile(b, ProfileUniform)
+}
+
+func BenchmarkZipfian(b *testing.B) {
+	benchmarkProfile(b, ProfileZipfian)
+}
+
+func BenchmarkSequential(b *testing.B) {
+	benchmarkProfile(b, ProfileSequential)
+}
+
+func BenchmarkPrefixScan(b *testing.B) {
+	benchmarkProfile(b, ProfilePrefixScan)
+}
@@ -0,0 +1,133 @@
+package bench
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/syncer"
+)
+
+const (
+	// ProfileUniform writes and reads keys chosen uniformly at random from
+	// the key space.
+	ProfileUniform = "uniform"
+	// ProfileZipfian writes and reads keys following a Zipfian distribution
+	// over the key space, so that a small set of hot keys is accessed most.
+	ProfileZipfian = "zipfian"
+	// ProfileSequential performs bulk inserts of sequentially increasing keys
+	// and reads them back by iterating from a random previously written key.
+	ProfileSequential = "sequential"
+	// ProfilePrefixScan writes keys spread over a small number of prefixes
+	// and reads them back via wide prefix scans.
+	ProfilePrefixScan = "prefix_scan"
+
+	// zipfExponent is the exponent of the Zipfian key distribution.
+	zipfExponent = 1.1
+
+	// sequentialPrefetch is the number of keys fetched by each iteration in
+	// the sequential profile.
+	sequentialPrefetch = 100
+
+	// prefixScanPrefixes is the number of distinct key prefixes used by the
+	// prefix scan profile.
+	prefixScanPrefixes = 16
+	// prefixScanLimit is the maximum number of keys fetched by each prefix
+	// scan in the prefix scan profile.
+	prefixScanLimit = 1000
+)
+
+// Profiles is the list of all supported workload profiles.
+var Profiles = []string{
+	ProfileUniform,
+	ProfileZipfian,
+	ProfileSequential,
+	ProfilePrefixScan,
+}
+
+// workload generates the operations of a workload profile.
+//
+// All randomness is derived from the configured seed so that the same
+// configuration always results in the same sequence of operations.
+type workload struct {
+	cfg *Config
+
+	rng  *rand.Rand
+	zipf *rand.Zipf
+
+	// written is the number of keys written by the sequential profile.
+	written uint64
+}
+
+func (w *workload) indexKey(index uint64) []byte {
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], index)
+	return key[:]
+}
+
+func (w *workload) prefixKey(index uint64) []byte {
+	return append([]byte{byte(index % prefixScanPrefixes)}, w.indexKey(index)...)
+}
+
+// writeKey returns the key of the next write operation.
+func (w *workload) writeKey() []byte {
+	switch w.cfg.Profile {
+	case ProfileZipfian:
+		return w.indexKey(w.zipf.Uint64())
+	case ProfileSequential:
+		w.written++
+		return w.indexKey(w.written - 1)
+	case ProfilePrefixScan:
+		return w.prefixKey(w.rng.Uint64() % w.cfg.KeySpace)
+	default:
+		return w.indexKey(w.rng.Uint64() % w.cfg.KeySpace)
+	}
+}
+
+// value returns the value of the next write operation.
+func (w *workload) value() []byte {
+	value := make([]byte, w.cfg.ValueSize)
+	_, _ = w.rng.Read(value)
+	return value
+}
+
+// sampleRead performs the next sampled read against the given read syncer
+// and returns the generated proof.
+func (w *workload) sampleRead(ctx context.Context, rs syncer.ReadSyncer, root node.Root) (*syncer.ProofResponse, error) {
+	tree := syncer.TreeID{Root: root, Position: root.Hash}
+
+	switch w.cfg.Profile {
+	case ProfileZipfian:
+		return rs.SyncGet(ctx, &syncer.GetRequest{
+			Tree: tree,
+			Key:  w.indexKey(w.zipf.Uint64()),
+		})
+	case ProfileSequential:
+		return rs.SyncIterate(ctx, &syncer.IterateRequest{
+			Tree:     tree,
+			Key:      w.indexKey(w.rng.Uint64() % w.written),
+			Prefetch: sequentialPrefetch,
+		})
+	case ProfilePrefixScan:
+		return rs.SyncGetPrefixes(ctx, &syncer.GetPrefixesRequest{
+			Tree:     tree,
+			Prefixes: [][]byte{{byte(w.rng.Intn(prefixScanPrefixes))}},
+			Limit:    prefixScanLimit,
+		})
+	default:
+		return rs.SyncGet(ctx, &syncer.GetRequest{
+			Tree: tree,
+			Key:  w.indexKey(w.rng.Uint64() % w.cfg.KeySpace),
+		})
+	}
+}
+
+func newWorkload(cfg *Config) *workload {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	return &workload{
+		cfg:  cfg,
+		rng:  rng,
+		zipf: rand.NewZipf(rng, zipfExponent, 1, cfg.KeySpace-1),
+	}
+}
@@ -30,7 +30,13 @@ type cache struct {
 	// syncRoot is the root at which all node database and syncer cache
 	// lookups will be done.
 	syncRoot node.Root
+	// spillRoot is the most recent interim root that dirty nodes have been
+	// spilled to (if any). As spilled nodes are stored under a newer version
+	// than the sync root, node database lookups are done at this root.
+	spillRoot *node.Root
 
+	// Approximate size of dirty nodes.
+	dirtySize uint64
 	// Current size of leaf values.
 	valueSize uint64
 	// Current number of internal nodes.
@@ -80,8 +86,10 @@ func (c *cache) close() {
 
 	// Reset sync root.
 	c.syncRoot = node.Root{}
+	c.spillRoot = nil
 
 	// Reset statistics.
+	c.dirtySize = 0
 	c.valueSize = 0
 	c.internalNodeCount = 0
 }
@@ -102,7 +110,21 @@ func (c *cache) setPendingRoot(ptr *node.Pointer) {
 	c.pendingRoot = ptr
 }
 
+// dirtyNodeSize returns the approximate size of a dirty node in bytes, not
+// including the size of any of its children.
+func dirtyNodeSize(n node.Node) uint64 {
+	switch n := n.(type) {
+	case *node.InternalNode:
+		return node.InternalNodeSize + uint64(len(n.Label))
+	case *node.LeafNode:
+		return n.Size()
+	default:
+		return 0
+	}
+}
+
 func (c *cache) newLeafNodePtr(n *node.LeafNode) *node.Pointer {
+	c.dirtySize += node.PointerSize + dirtyNodeSize(n)
 	return &node.Pointer{
 		Node: n,
 	}
@@ -116,6 +138,7 @@ func (c *cache) newLeafNode(key node.Key, val []byte) *node.Pointer {
 }
 
 func (c *cache) newInternalNodePtr(n *node.InternalNode) *node.Pointer {
+	c.dirtySize += node.PointerSize + dirtyNodeSize(n)
 	return &node.Pointer{
 		Node: n,
 	}
@@ -214,6 +237,9 @@ func (c *cache) rollbackNode(ptr *node.Pointer) {
 		return
 	}
 
+	// Node is becoming dirty again.
+	c.dirtySize += dirtyNodeSize(ptr.Node)
+
 	switch n := ptr.Node.(type) {
 	case *node.InternalNode:
 		if c.lruInternalPos == ptr.LRU {
@@ -357,7 +383,11 @@ func (c *cache) derefNodePtr(
 	}
 
 	// First, attempt to fetch from the local node database.
-	n, err := c.db.GetNode(c.syncRoot, ptr)
+	dbRoot := c.syncRoot
+	if c.spillRoot != nil {
+		dbRoot = *c.spillRoot
+	}
+	n, err := c.db.GetNode(dbRoot, ptr)
 	switch err {
 	case nil:
 		ptr.Node = n
@@ -130,70 +130,9 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 	}
 
 	// Import chunk into the node database.
-	emptyRoot := node.Root{
-		Namespace: chunk.Root.Namespace,
-		Version:   chunk.Root.Version,
-	}
-	emptyRoot.Hash.Empty()
-
-	batch := ndb.NewBatch(emptyRoot, chunk.Root.Version, true)
-	defer batch.Reset()
-
-	subtree := batch.MaybeStartSubtree(nil, 0, ptr)
-	if err = doRestoreChunk(ctx, batch, subtree, 0, ptr); err != nil {
-		return fmt.Errorf("chunk: node import failed: %w", err)
-	}
-	if err = subtree.Commit(); err != nil {
-		return fmt.Errorf("chunk: node import failed: %w", err)
-	}
-	if err = batch.Commit(chunk.Root); err != nil {
+	if err = db.ImportChunk(ctx, ndb, chunk.Root, ptr); err != nil {
 		return fmt.Errorf("chunk: node import failed: %w", err)
 	}
 
 	return nil
 }
-
-func doRestoreChunk(
-	ctx context.Context,
-	batch db.Batch,
-	subtree db.Subtree,
-	depth node.Depth,
-	ptr *node.Pointer,
-) (err error) {
-	if ptr == nil {
-		return
-	}
-
-	switch n := ptr.Node.(type) {
-	case nil:
-	case *node.InternalNode:
-		// Commit internal leaf (considered to be on the same depth as the internal node).
-		if err = doRestoreChunk(ctx, batch, subtree, depth, n.LeafNode); err != nil {
-			return
-		}
-
-		for _, subNode := range []*node.Pointer{n.Left, n.Right} {
-			newSubtree := batch.MaybeStartSubtree(subtree, depth+1, subNode)
-			if err = doRestoreChunk(ctx, batch, newSubtree, depth+1, subNode); err != nil {
-				return
-			}
-			if newSubtree != subtree {
-				if err = newSubtree.Commit(); err != nil {
-					return
-				}
-			}
-		}
-
-		// Store the node.
-		if err = subtree.PutNode(depth, ptr); err != nil {
-			return
-		}
-	case *node.LeafNode:
-		// Leaf node -- store the node.
-		if err = subtree.PutNode(depth, ptr); err != nil {
-			return
-		}
-	}
-
-	return
-}
@@ -0,0 +1,439 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// httpManifestName is the name of the manifest resource.
+	httpManifestName = "manifest"
+
+	// httpMaxAttempts is the maximum number of consecutive attempts to
+	// download a chunk without making any progress.
+	httpMaxAttempts = 5
+	// httpRetryInterval is the interval between download attempts.
+	httpRetryInterval = 1 * time.Second
+)
+
+// ManifestSignatureContext is the context used for signing checkpoint
+// manifests served over HTTP.
+var ManifestSignatureContext = signature.NewContext("oasis-core/storage: checkpoint manifest")
+
+// Manifest is a manifest of the checkpoints of a namespace that are served
+// over HTTP.
+type Manifest struct {
+	// Namespace is the namespace of the checkpoints.
+	Namespace common.Namespace `json:"namespace"`
+	// Checkpoints is the checkpoint metadata of all of the checkpoints.
+	Checkpoints []*Metadata `json:"checkpoints"`
+}
+
+// SignedManifest is a signed checkpoint manifest.
+type SignedManifest struct {
+	signature.Signed
+}
+
+// Open first verifies the blob signature and then unmarshals the blob.
+func (s *SignedManifest) Open(manifest *Manifest) error {
+	return s.Signed.Open(ManifestSignatureContext, manifest)
+}
+
+// SignManifest serializes the Manifest and signs the result.
+func SignManifest(signer signature.Signer, manifest *Manifest) (*SignedManifest, error) {
+	signed, err := signature.SignSigned(signer, ManifestSignatureContext, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedManifest{
+		Signed: *signed,
+	}, nil
+}
+
+// The HTTP resources are laid out as follows:
+//
+//   /<version>/<namespace>/manifest
+//   /<version>/<namespace>/<root version>/<root hash>/<chunk index>
+//
+// where the manifest is a CBOR-serialized SignedManifest and chunks are
+// served as-is.
+func manifestPath(version uint16, ns common.Namespace) string {
+	return path.Join("/", strconv.FormatUint(uint64(version), 10), ns.String(), httpManifestName)
+}
+
+func chunkPath(chunk *ChunkMetadata) string {
+	return path.Join(
+		"/",
+		strconv.FormatUint(uint64(chunk.Version), 10),
+		chunk.Root.Namespace.String(),
+		strconv.FormatUint(chunk.Root.Version, 10),
+		chunk.Root.Hash.String(),
+		strconv.FormatUint(chunk.Index, 10),
+	)
+}
+
+type httpHandler struct {
+	logger *logging.Logger
+
+	provider ChunkProvider
+	signer   signature.Signer
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.NotFound(w, r)
+		return
+	}
+	version, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	var ns common.Namespace
+	if err = ns.UnmarshalHex(parts[1]); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case len(parts) == 3 && parts[2] == httpManifestName:
+		h.serveManifest(w, r, uint16(version), ns)
+	case len(parts) == 5:
+		root := node.Root{Namespace: ns}
+		if root.Version, err = strconv.ParseUint(parts[2], 10, 64); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if err = root.Hash.UnmarshalHex(parts[3]); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		var index uint64
+		if index, err = strconv.ParseUint(parts[4], 10, 64); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		h.serveChunk(w, r, uint16(version), root, index)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *httpHandler) serveManifest(w http.ResponseWriter, r *http.Request, version uint16, ns common.Namespace) {
+	cps, err := h.provider.GetCheckpoints(r.Context(), &GetCheckpointsRequest{
+		Version:   version,
+		Namespace: ns,
+	})
+	if err != nil {
+		h.logger.Error("failed to get checkpoints",
+			"err", err,
+			"namespace", ns,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	signed, err := SignManifest(h.signer, &Manifest{
+		Namespace:   ns,
+		Checkpoints: cps,
+	})
+	if err != nil {
+		h.logger.Error("failed to sign checkpoint manifest",
+			"err", err,
+			"namespace", ns,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The set of checkpoints changes over time, so the manifest must not
+	// be cached.
+	w.Header().Set("Content-Type", "application/cbor")
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(cbor.Marshal(signed)))
+}
+
+func (h *httpHandler) serveChunk(w http.ResponseWriter, r *http.Request, version uint16, root node.Root, index uint64) {
+	cps, err := h.provider.GetCheckpoints(r.Context(), &GetCheckpointsRequest{
+		Version:     version,
+		Namespace:   root.Namespace,
+		RootVersion: &root.Version,
+	})
+	if err != nil {
+		h.logger.Error("failed to get checkpoints",
+			"err", err,
+			"root", root,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var chunk *ChunkMetadata
+	for _, cp := range cps {
+		if cp.Root.Hash.Equal(&root.Hash) {
+			chunk, _ = cp.GetChunkMetadata(index)
+			break
+		}
+	}
+	if chunk == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Chunks are small enough to be buffered, which enables range requests.
+	var buf bytes.Buffer
+	if err = h.provider.GetCheckpointChunk(r.Context(), chunk, &buf); err != nil {
+		if errors.Is(err, ErrChunkNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Error("failed to get checkpoint chunk",
+			"err", err,
+			"root", root,
+			"index", index,
+		)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// Chunks are content-addressed and immutable, so they can be cached
+	// indefinitely (e.g., by CDNs).
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", strconv.Quote(chunk.Digest.String()))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(buf.Bytes()))
+}
+
+// NewHTTPHandler creates a new HTTP handler that serves the checkpoints of
+// the given chunk provider, with manifests signed by the given signer.
+//
+// Chunk requests support HTTP range requests, so that interrupted downloads
+// can be resumed.
+func NewHTTPHandler(provider ChunkProvider, signer signature.Signer) http.Handler {
+	return &httpHandler{
+		logger:   logging.GetLogger("storage/mkvs/checkpoint/http"),
+		provider: provider,
+		signer:   signer,
+	}
+}
+
+type httpChunkProvider struct {
+	logger *logging.Logger
+
+	baseURL        *url.URL
+	client         *http.Client
+	manifestSigner signature.PublicKey
+}
+
+func (p *httpChunkProvider) resourceURL(resourcePath string) string {
+	u := *p.baseURL
+	u.Path = path.Join(u.Path, resourcePath)
+	return u.String()
+}
+
+func (p *httpChunkProvider) get(ctx context.Context, resourcePath string, offset uint64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.resourceURL(resourcePath), nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	return p.client.Do(req)
+}
+
+func (p *httpChunkProvider) GetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
+	if !p.manifestSigner.IsValid() {
+		return nil, fmt.Errorf("checkpoint/http: no trusted manifest signer configured")
+	}
+
+	rsp, err := p.get(ctx, manifestPath(request.Version, request.Namespace), 0)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint/http: failed to fetch manifest: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return []*Metadata{}, nil
+	default:
+		return nil, fmt.Errorf("checkpoint/http: failed to fetch manifest: %s", rsp.Status)
+	}
+
+	data, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint/http: failed to read manifest: %w", err)
+	}
+	var signed SignedManifest
+	if err = cbor.Unmarshal(data, &signed); err != nil {
+		return nil, fmt.Errorf("checkpoint/http: malformed manifest: %w", err)
+	}
+	if !signed.Signature.PublicKey.Equal(p.manifestSigner) {
+		return nil, fmt.Errorf("checkpoint/http: manifest signed by untrusted key %s", signed.Signature.PublicKey)
+	}
+	var manifest Manifest
+	if err = signed.Open(&manifest); err != nil {
+		return nil, fmt.Errorf("checkpoint/http: invalid manifest signature: %w", err)
+	}
+	if !manifest.Namespace.Equal(&request.Namespace) {
+		return nil, fmt.Errorf("checkpoint/http: manifest is for a different namespace")
+	}
+
+	cps := []*Metadata{}
+	for _, cp := range manifest.Checkpoints {
+		if cp.Version != request.Version || !cp.Root.Namespace.Equal(&request.Namespace) {
+			return nil, fmt.Errorf("checkpoint/http: manifest contains a foreign checkpoint")
+		}
+		if request.RootVersion != nil && cp.Root.Version != *request.RootVersion {
+			continue
+		}
+		cps = append(cps, cp)
+	}
+	return cps, nil
+}
+
+func (p *httpChunkProvider) GetCheckpointChunk(ctx context.Context, chunk *ChunkMetadata, w io.Writer) error {
+	// Verify the chunk digest as it is being downloaded, so that a corrupted
+	// chunk is detected even before it is imported.
+	hb := hash.NewBuilder()
+	cw := &countingWriter{w: io.MultiWriter(w, hb)}
+
+	var attempt int
+	for {
+		offset := cw.n
+		err := p.fetchChunk(ctx, chunk, cw)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ErrChunkNotFound) || ctx.Err() != nil {
+			return err
+		}
+
+		// Only give up when the download is not making any progress.
+		if cw.n > offset {
+			attempt = 0
+		}
+		attempt++
+		if attempt >= httpMaxAttempts {
+			return fmt.Errorf("checkpoint/http: failed to fetch chunk: %w", err)
+		}
+
+		p.logger.Warn("chunk download interrupted, resuming",
+			"err", err,
+			"root", chunk.Root,
+			"index", chunk.Index,
+			"offset", cw.n,
+		)
+
+		select {
+		case <-time.After(httpRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if digest := hb.Build(); !digest.Equal(&chunk.Digest) {
+		return fmt.Errorf("%w: digest incorrect (expected: %s got: %s)",
+			ErrChunkCorrupted,
+			chunk.Digest,
+			digest,
+		)
+	}
+	return nil
+}
+
+// fetchChunk fetches the remainder of the chunk, starting at the number of
+// bytes already written to the given writer.
+func (p *httpChunkProvider) fetchChunk(ctx context.Context, chunk *ChunkMetadata, cw *countingWriter) error {
+	rsp, err := p.get(ctx, chunkPath(chunk), cw.n)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	var body io.Reader = rsp.Body
+	switch rsp.StatusCode {
+	case http.StatusOK:
+		// The server ignored the range request, skip what we already have.
+		if _, err = io.CopyN(ioutil.Discard, body, int64(cw.n)); err != nil {
+			return err
+		}
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		if cw.n == 0 {
+			return fmt.Errorf("unexpected response: %s", rsp.Status)
+		}
+		// The chunk has already been fully fetched.
+		return nil
+	case http.StatusNotFound:
+		return ErrChunkNotFound
+	default:
+		return fmt.Errorf("unexpected response: %s", rsp.Status)
+	}
+
+	_, err = io.Copy(cw, body)
+	return err
+}
+
+// NewHTTPChunkProvider creates a new chunk provider that fetches checkpoints
+// served over HTTP(S) at the given base URL (e.g., via a CDN).
+//
+// Checkpoint manifests must be signed by the given manifest signer, while
+// chunks are verified against the digests in the checkpoint metadata.
+// Interrupted chunk downloads are resumed using HTTP range requests.
+//
+// If the manifest signer is not a valid public key (e.g., when the checkpoint
+// metadata is obtained elsewhere), only chunks can be fetched.
+func NewHTTPChunkProvider(baseURL string, manifestSigner signature.PublicKey) (ChunkProvider, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint/http: malformed base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("checkpoint/http: unsupported base URL scheme '%s'", u.Scheme)
+	}
+
+	return &httpChunkProvider{
+		logger:         logging.GetLogger("storage/mkvs/checkpoint/http"),
+		baseURL:        u,
+		client:         &http.Client{},
+		manifestSigner: manifestSigner,
+	}, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n uint64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += uint64(n)
+	return n, err
+}
@@ -0,0 +1,191 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+// interruptingHandler truncates the first response to each chunk request
+// that is not a range request, simulating an interrupted download.
+type interruptingHandler struct {
+	inner http.Handler
+
+	interrupted   map[string]bool
+	rangeRequests uint64
+}
+
+func (h *interruptingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Range") != "" {
+		atomic.AddUint64(&h.rangeRequests, 1)
+		h.inner.ServeHTTP(w, r)
+		return
+	}
+	if h.interrupted[r.URL.Path] || filepath.Base(r.URL.Path) == httpManifestName {
+		h.inner.ServeHTTP(w, r)
+		return
+	}
+	h.interrupted[r.URL.Path] = true
+
+	rec := httptest.NewRecorder()
+	h.inner.ServeHTTP(rec, r)
+	body := rec.Body.Bytes()
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(body[:len(body)/2])
+}
+
+func TestHTTPChunkProvider(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpoint.http")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+
+	ctx := context.Background()
+	tree := mkvs.New(nil, ndb)
+	for i := 0; i < 1000; i++ {
+		err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit")
+	root := node.Root{
+		Namespace: testNs,
+		Version:   0,
+		Hash:      rootHash,
+	}
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+	cp, err := fc.CreateCheckpoint(ctx, root, 16*1024)
+	require.NoError(err, "CreateCheckpoint")
+
+	// Serve the checkpoints over HTTP.
+	signer := memorySigner.NewTestSigner("oasis mkvs checkpoint http test signer")
+	handler := &interruptingHandler{
+		inner:       NewHTTPHandler(fc, signer),
+		interrupted: make(map[string]bool),
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	_, err = NewHTTPChunkProvider("ftp://example.com", signer.Public())
+	require.Error(err, "NewHTTPChunkProvider should fail with unsupported scheme")
+
+	// Manifests signed by an untrusted key should be rejected.
+	untrusted := memorySigner.NewTestSigner("oasis mkvs checkpoint http test untrusted signer")
+	hp, err := NewHTTPChunkProvider(srv.URL, untrusted.Public())
+	require.NoError(err, "NewHTTPChunkProvider")
+	_, err = hp.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1, Namespace: testNs})
+	require.Error(err, "GetCheckpoints should fail with untrusted manifest signer")
+
+	hp, err = NewHTTPChunkProvider(srv.URL, signer.Public())
+	require.NoError(err, "NewHTTPChunkProvider")
+
+	cps, err := hp.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1, Namespace: testNs})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 1, "there should be one checkpoint")
+	require.EqualValues(cp, cps[0], "checkpoint returned by GetCheckpoints should be correct")
+
+	otherVersion := uint64(42)
+	cps, err = hp.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1, Namespace: testNs, RootVersion: &otherVersion})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 0, "there should be no checkpoints for other root versions")
+
+	// Chunks should support range requests.
+	chunk0, err := cp.GetChunkMetadata(0)
+	require.NoError(err, "GetChunkMetadata")
+	var expected bytes.Buffer
+	err = fc.GetCheckpointChunk(ctx, chunk0, &expected)
+	require.NoError(err, "GetCheckpointChunk")
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+chunkPath(chunk0), nil)
+	require.NoError(err, "NewRequest")
+	req.Header.Set("Range", "bytes=10-")
+	rsp, err := srv.Client().Do(req)
+	require.NoError(err, "range request")
+	body, err := ioutil.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	require.NoError(err, "ReadAll")
+	require.Equal(http.StatusPartialContent, rsp.StatusCode, "range request should return partial content")
+	require.Equal(expected.Bytes()[10:], body, "range request should return the correct content")
+
+	// Restore the checkpoint from chunks fetched over HTTP, where the first
+	// download of each chunk is interrupted.
+	ndb2, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db2"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	rs, err := NewRestorer(ndb2)
+	require.NoError(err, "NewRestorer")
+	err = rs.StartRestore(ctx, cp)
+	require.NoError(err, "StartRestore")
+
+	rangeRequests := atomic.LoadUint64(&handler.rangeRequests)
+	for i := 0; i < len(cp.Chunks); i++ {
+		var cm *ChunkMetadata
+		cm, err = cp.GetChunkMetadata(uint64(i))
+		require.NoError(err, "GetChunkMetadata")
+
+		var buf bytes.Buffer
+		err = hp.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetCheckpointChunk")
+		_, err = rs.RestoreChunk(ctx, uint64(i), &buf)
+		require.NoError(err, "RestoreChunk")
+	}
+	require.EqualValues(rangeRequests+uint64(len(cp.Chunks)), atomic.LoadUint64(&handler.rangeRequests),
+		"interrupted downloads should be resumed using range requests",
+	)
+
+	err = ndb2.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+	require.NoError(err, "Finalize")
+	tree = mkvs.NewWithRoot(nil, ndb2, root)
+	for i := 0; i < 1000; i++ {
+		var value []byte
+		value, err = tree.Get(ctx, []byte(strconv.Itoa(i)))
+		require.NoError(err, "Get")
+		require.Equal([]byte(strconv.Itoa(i)), value)
+	}
+
+	// Fetching a non-existent chunk should fail.
+	invalidChunk := *chunk0
+	invalidChunk.Index = 999
+	err = hp.GetCheckpointChunk(ctx, &invalidChunk, ioutil.Discard)
+	require.Error(err, "GetCheckpointChunk on a non-existent chunk should fail")
+	require.True(errors.Is(err, ErrChunkNotFound))
+
+	// Fetching a chunk with an incorrect digest should fail.
+	corruptedChunk := *chunk0
+	corruptedChunk.Digest = cp.Chunks[1]
+	err = hp.GetCheckpointChunk(ctx, &corruptedChunk, ioutil.Discard)
+	require.Error(err, "GetCheckpointChunk with incorrect digest should fail")
+	require.True(errors.Is(err, ErrChunkCorrupted))
+}
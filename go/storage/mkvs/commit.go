@@ -40,6 +40,10 @@ func (t *tree) commitWithHooks(
 		return nil, hash.Hash{}, ErrClosed
 	}
 
+	if len(t.spilledNodes) > 0 && (!namespace.Equal(&t.spillNamespace) || version != t.spillVersion) {
+		return nil, hash.Hash{}, ErrSpillRootMismatch
+	}
+
 	oldRoot := t.cache.getSyncRoot()
 	if oldRoot.IsEmpty() {
 		oldRoot.Namespace = namespace
@@ -98,6 +102,13 @@ func (t *tree) commitWithHooks(
 		return nil, hash.Hash{}, err
 	}
 
+	// Retain any previously spilled nodes that are still part of the tree.
+	if len(t.spilledNodes) > 0 {
+		if err := batch.RetainNodes(t.retainedSpilledNodes()); err != nil {
+			return nil, hash.Hash{}, err
+		}
+	}
+
 	// And finally commit to the database.
 	if err := batch.Commit(root); err != nil {
 		return nil, hash.Hash{}, err
@@ -105,6 +116,9 @@ func (t *tree) commitWithHooks(
 
 	t.pendingWriteLog = make(map[string]*pendingEntry)
 	t.pendingRemovedNodes = nil
+	t.spilledNodes = make(map[hash.Hash]bool)
+	t.cache.spillRoot = nil
+	t.cache.dirtySize = 0
 	t.cache.setSyncRoot(root)
 
 	return log, rootHash, nil
@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
@@ -45,6 +46,23 @@ var (
 	ErrNotEarliest = errors.New(ModuleName, 11, "mkvs: version is not the earliest version")
 	// ErrReadOnly indicates that a write operation failed due to a read-only database.
 	ErrReadOnly = errors.New(ModuleName, 12, "mkvs: read-only database")
+	// ErrInvalidChunk indicates that a checkpoint chunk failed verification.
+	ErrInvalidChunk = errors.New(ModuleName, 13, "mkvs: invalid checkpoint chunk")
+	// ErrChunkIteratorInvalid indicates that a chunk iterator is not pointing
+	// to a valid chunk.
+	ErrChunkIteratorInvalid = errors.New(ModuleName, 14, "mkvs: chunk iterator is invalid")
+	// ErrQuotaExceeded indicates that a commit failed as it would exceed the
+	// configured storage quota.
+	ErrQuotaExceeded = errors.New(ModuleName, 15, "mkvs: storage quota exceeded")
+)
+
+const (
+	// WriteLogCompressionNone disables write log compression.
+	WriteLogCompressionNone = "none"
+	// WriteLogCompressionSnappy compresses write logs using Snappy.
+	WriteLogCompressionSnappy = "snappy"
+	// WriteLogCompressionZstd compresses write logs using Zstandard.
+	WriteLogCompressionZstd = "zstd"
 )
 
 // Config is the node database backend configuration.
@@ -67,8 +85,70 @@ type Config struct { // nolint: maligned
 	// MaxCacheSize is the maximum in-memory cache size for the database.
 	MaxCacheSize int64
 
+	// NodeCachePolicy is the eviction policy of the decoded node cache. If
+	// empty, decoded nodes are not cached.
+	NodeCachePolicy string
+
+	// NodeCacheSize is the maximum number of nodes in the decoded node cache.
+	NodeCacheSize uint64
+
 	// DiscardWriteLogs will cause all write logs to be discarded.
 	DiscardWriteLogs bool
+
+	// WriteLogCompression is the compression algorithm used for persisted
+	// write logs. If empty, write logs are not compressed.
+	//
+	// Write logs are always readable regardless of the algorithm that was
+	// configured at the time they were persisted.
+	WriteLogCompression string
+
+	// CheckpointChunkSize is the (approximate) size of chunks created by
+	// Checkpoint. If zero, DefaultCheckpointChunkSize is used.
+	CheckpointChunkSize uint64
+
+	// Quota is the maximum amount of accounted data in bytes. Commits that
+	// would exceed the quota fail with ErrQuotaExceeded. If zero, the amount
+	// of data is not limited.
+	Quota uint64
+}
+
+// VersionMetadata is the metadata recorded for a version.
+type VersionMetadata struct {
+	// Version is the version this metadata is for.
+	Version uint64 `json:"version"`
+	// CreatedAt is the time when the first root of the version was committed.
+	CreatedAt time.Time `json:"created_at"`
+	// ConsensusHeight is the consensus height of the block that produced the
+	// version. It is zero if not known.
+	ConsensusHeight int64 `json:"consensus_height,omitempty"`
+	// Finalized is true iff the version has been finalized.
+	Finalized bool `json:"finalized"`
+	// FinalizedAt is the time when the version was finalized.
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+
+	// Size is the amount of data in bytes written by all roots of the
+	// version.
+	Size uint64 `json:"size"`
+	// RootSizes is the amount of data in bytes written by each root of the
+	// version.
+	RootSizes map[hash.Hash]uint64 `json:"root_sizes,omitempty"`
+}
+
+// Usage is the storage usage of a node database.
+type Usage struct {
+	// Size is the amount of accounted data in bytes.
+	//
+	// Accounting is based on the serialized size of nodes and write logs at
+	// the time they are committed and released when they are removed on
+	// finalization or pruning, so it is an approximation of the size of live
+	// data. Data committed by software that did not perform accounting is
+	// not included.
+	Size uint64 `json:"size"`
+	// Quota is the configured quota in bytes. Zero means that the amount of
+	// data is not limited.
+	Quota uint64 `json:"quota,omitempty"`
+	// DiskSize is the size of the whole database on disk in bytes.
+	DiskSize int64 `json:"disk_size"`
 }
 
 // NodeDB is the persistence layer used for persisting the in-memory tree.
@@ -109,6 +189,34 @@ type NodeDB interface {
 	// Only the earliest version can be pruned, passing any other version will result in an error.
 	Prune(ctx context.Context, version uint64) error
 
+	// SetVersionConsensusHeight records the consensus height of the block
+	// that produced the given version.
+	SetVersionConsensusHeight(ctx context.Context, version uint64, height int64) error
+
+	// GetVersionMetadata returns the metadata recorded for the given version.
+	GetVersionMetadata(ctx context.Context, version uint64) (*VersionMetadata, error)
+
+	// GetVersions returns the metadata of all versions in the node database
+	// that have metadata recorded, ordered by version.
+	GetVersions(ctx context.Context) ([]*VersionMetadata, error)
+
+	// Checkpoint creates a checkpoint of all roots at the given (finalized)
+	// version and returns an iterator over its chunks.
+	//
+	// Chunks are created lazily while iterating, so the version must not be
+	// pruned until the iterator has been fully consumed.
+	Checkpoint(ctx context.Context, version uint64) (ChunkIterator, error)
+
+	// RestoreCheckpoint verifies and imports all chunks returned by the given
+	// iterator.
+	//
+	// The restored version is not finalized, so the caller needs to call
+	// Finalize after all chunks of a checkpoint have been restored.
+	RestoreCheckpoint(ctx context.Context, chunks ChunkIterator) error
+
+	// GetUsage returns the storage usage of the node database.
+	GetUsage(ctx context.Context) (*Usage, error)
+
 	// Size returns the size of the database in bytes.
 	Size() (int64, error)
 
@@ -152,6 +260,12 @@ type Batch interface {
 	// RemoveNodes marks nodes for eventual garbage collection.
 	RemoveNodes(nodes []node.Node) error
 
+	// RetainNodes marks nodes that have already been persisted by another
+	// (interim) root of the same version as also being part of the root
+	// committed by this batch, so that they are not garbage collected when
+	// only this root gets finalized.
+	RetainNodes(hashes []hash.Hash) error
+
 	// Commit commits the batch.
 	Commit(root node.Root) error
 
@@ -217,6 +331,30 @@ func (d *nopNodeDB) Prune(ctx context.Context, version uint64) error {
 	return nil
 }
 
+func (d *nopNodeDB) SetVersionConsensusHeight(ctx context.Context, version uint64, height int64) error {
+	return nil
+}
+
+func (d *nopNodeDB) GetVersionMetadata(ctx context.Context, version uint64) (*VersionMetadata, error) {
+	return nil, ErrVersionNotFound
+}
+
+func (d *nopNodeDB) GetVersions(ctx context.Context) ([]*VersionMetadata, error) {
+	return nil, nil
+}
+
+func (d *nopNodeDB) Checkpoint(ctx context.Context, version uint64) (ChunkIterator, error) {
+	return nil, ErrVersionNotFound
+}
+
+func (d *nopNodeDB) RestoreCheckpoint(ctx context.Context, chunks ChunkIterator) error {
+	return nil
+}
+
+func (d *nopNodeDB) GetUsage(ctx context.Context) (*Usage, error) {
+	return &Usage{}, nil
+}
+
 func (d *nopNodeDB) Size() (int64, error) {
 	return 0, nil
 }
@@ -245,6 +383,10 @@ func (b *nopBatch) RemoveNodes(nodes []node.Node) error {
 	return nil
 }
 
+func (b *nopBatch) RetainNodes(hashes []hash.Hash) error {
+	return nil
+}
+
 func (b *nopBatch) Reset() {
 }
 
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/syncer"
+)
+
+// DefaultCheckpointChunkSize is the default (approximate) size of checkpoint
+// chunks in bytes.
+const DefaultCheckpointChunkSize = 8 * 1024 * 1024
+
+// Chunk is a single chunk of a node database checkpoint.
+type Chunk struct {
+	// Root is the root that the chunk belongs to.
+	Root node.Root `json:"root"`
+	// Index is the index of the chunk among the chunks of the same root.
+	Index uint64 `json:"index"`
+	// Proof is the Merkle proof containing the nodes of the chunk. Each
+	// proof can be verified against the root independently of other chunks.
+	Proof syncer.Proof `json:"proof"`
+}
+
+// ChunkIterator iterates over checkpoint chunks.
+type ChunkIterator interface {
+	// Next advances the iterator to the next chunk and returns false if there
+	// are no more chunks.
+	Next() (bool, error)
+	// Value returns the chunk the iterator is currently pointing to.
+	Value() (*Chunk, error)
+}
+
+type staticChunkIterator struct {
+	cursor int
+	chunks []*Chunk
+}
+
+func (i *staticChunkIterator) Next() (bool, error) {
+	i.cursor++
+	if i.cursor >= len(i.chunks) {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (i *staticChunkIterator) Value() (*Chunk, error) {
+	if i.cursor < 0 || i.cursor >= len(i.chunks) {
+		return nil, ErrChunkIteratorInvalid
+	}
+	return i.chunks[i.cursor], nil
+}
+
+// NewStaticChunkIterator returns a new chunk iterator over the given chunks.
+func NewStaticChunkIterator(chunks []*Chunk) ChunkIterator {
+	return &staticChunkIterator{
+		cursor: -1,
+		chunks: chunks,
+	}
+}
+
+type pendingCheckpointNode struct {
+	ptr *node.Pointer
+	// path are the ancestors of the node, starting at the root.
+	path []node.Node
+}
+
+type checkpointIterator struct {
+	ctx       context.Context
+	ndb       NodeDB
+	roots     []node.Root
+	chunkSize uint64
+
+	root    node.Root
+	index   uint64
+	pending []*pendingCheckpointNode
+	current *Chunk
+}
+
+func (it *checkpointIterator) Next() (bool, error) {
+	it.current = nil
+
+	// Advance to the next root in case the current one has been fully processed.
+	for len(it.pending) == 0 {
+		if len(it.roots) == 0 {
+			return false, nil
+		}
+
+		it.root, it.roots = it.roots[0], it.roots[1:]
+		it.index = 0
+		it.pending = []*pendingCheckpointNode{
+			{ptr: &node.Pointer{Clean: true, Hash: it.root.Hash}},
+		}
+	}
+
+	// Build the chunk by traversing the tree in pre-order until the chunk becomes
+	// too large or the whole tree has been traversed.
+	pb := syncer.NewProofBuilder(it.root.Hash)
+	for len(it.pending) > 0 && pb.Size() < it.chunkSize {
+		if err := it.ctx.Err(); err != nil {
+			return false, err
+		}
+
+		pn := it.pending[len(it.pending)-1]
+		it.pending = it.pending[:len(it.pending)-1]
+		if pn.ptr.Hash.IsEmpty() {
+			continue
+		}
+
+		n, err := it.ndb.GetNode(it.root, pn.ptr)
+		if err != nil {
+			return false, fmt.Errorf("mkvs: failed to get node for checkpoint: %w", err)
+		}
+
+		// Include the path from the root, so that each chunk can be verified on its own.
+		for _, ancestor := range pn.path {
+			pb.Include(ancestor)
+		}
+		pb.Include(n)
+
+		if in, ok := n.(*node.InternalNode); ok {
+			path := append(pn.path[:len(pn.path):len(pn.path)], n)
+			// Push the right child first so that the left one gets processed first.
+			for _, child := range []*node.Pointer{in.Right, in.Left} {
+				if child == nil {
+					continue
+				}
+				it.pending = append(it.pending, &pendingCheckpointNode{ptr: child, path: path})
+			}
+		}
+	}
+
+	proof, err := pb.Build(it.ctx)
+	if err != nil {
+		return false, fmt.Errorf("mkvs: failed to build checkpoint chunk proof: %w", err)
+	}
+
+	it.current = &Chunk{
+		Root:  it.root,
+		Index: it.index,
+		Proof: *proof,
+	}
+	it.index++
+
+	return true, nil
+}
+
+func (it *checkpointIterator) Value() (*Chunk, error) {
+	if it.current == nil {
+		return nil, ErrChunkIteratorInvalid
+	}
+	return it.current, nil
+}
+
+// NewCheckpointIterator returns a new chunk iterator that creates a checkpoint
+// of the given roots by traversing the trees using the NodeDB API.
+//
+// Chunks are created lazily, so the node database must retain the roots until
+// the iterator has been fully consumed.
+func NewCheckpointIterator(ctx context.Context, ndb NodeDB, roots []node.Root, chunkSize uint64) ChunkIterator {
+	if chunkSize == 0 {
+		chunkSize = DefaultCheckpointChunkSize
+	}
+
+	return &checkpointIterator{
+		ctx:       ctx,
+		ndb:       ndb,
+		roots:     roots,
+		chunkSize: chunkSize,
+	}
+}
+
+// RestoreCheckpoint verifies the chunks returned by the given iterator and
+// imports them into the node database.
+//
+// The restored versions still need to be finalized by the caller.
+func RestoreCheckpoint(ctx context.Context, ndb NodeDB, chunks ChunkIterator) error {
+	for {
+		more, err := chunks.Next()
+		if err != nil {
+			return fmt.Errorf("mkvs: failed to get next chunk: %w", err)
+		}
+		if !more {
+			return nil
+		}
+
+		chunk, err := chunks.Value()
+		if err != nil {
+			return fmt.Errorf("mkvs: failed to get chunk: %w", err)
+		}
+		if err = RestoreChunk(ctx, ndb, chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// RestoreChunk verifies a single checkpoint chunk and imports it into the node
+// database.
+func RestoreChunk(ctx context.Context, ndb NodeDB, chunk *Chunk) error {
+	var pv syncer.ProofVerifier
+	ptr, err := pv.VerifyProof(ctx, chunk.Root.Hash, &chunk.Proof)
+	if err != nil {
+		return fmt.Errorf("%w: chunk %d of root %s: %s", ErrInvalidChunk, chunk.Index, chunk.Root, err.Error())
+	}
+
+	if err = ImportChunk(ctx, ndb, chunk.Root, ptr); err != nil {
+		return fmt.Errorf("mkvs: chunk %d of root %s: node import failed: %w", chunk.Index, chunk.Root, err)
+	}
+	return nil
+}
+
+// ImportChunk imports the (possibly incomplete) subtree rooted at the given
+// pointer into the node database as a chunk of the given root.
+func ImportChunk(ctx context.Context, ndb NodeDB, root node.Root, ptr *node.Pointer) error {
+	emptyRoot := node.Root{
+		Namespace: root.Namespace,
+		Version:   root.Version,
+	}
+	emptyRoot.Hash.Empty()
+
+	batch := ndb.NewBatch(emptyRoot, root.Version, true)
+	defer batch.Reset()
+
+	subtree := batch.MaybeStartSubtree(nil, 0, ptr)
+	if err := doImportChunk(ctx, batch, subtree, 0, ptr); err != nil {
+		return err
+	}
+	if err := subtree.Commit(); err != nil {
+		return err
+	}
+	return batch.Commit(root)
+}
+
+func doImportChunk(
+	ctx context.Context,
+	batch Batch,
+	subtree Subtree,
+	depth node.Depth,
+	ptr *node.Pointer,
+) (err error) {
+	if ptr == nil {
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	switch n := ptr.Node.(type) {
+	case nil:
+	case *node.InternalNode:
+		// Commit internal leaf (considered to be on the same depth as the internal node).
+		if err = doImportChunk(ctx, batch, subtree, depth, n.LeafNode); err != nil {
+			return
+		}
+
+		for _, subNode := range []*node.Pointer{n.Left, n.Right} {
+			newSubtree := batch.MaybeStartSubtree(subtree, depth+1, subNode)
+			if err = doImportChunk(ctx, batch, newSubtree, depth+1, subNode); err != nil {
+				return
+			}
+			if newSubtree != subtree {
+				if err = newSubtree.Commit(); err != nil {
+					return
+				}
+			}
+		}
+
+		// Store the node.
+		if err = subtree.PutNode(depth, ptr); err != nil {
+			return
+		}
+	case *node.LeafNode:
+		// Leaf node -- store the node.
+		if err = subtree.PutNode(depth, ptr); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -2,9 +2,12 @@
 package badger
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/dgraph-io/badger/v2/options"
@@ -16,11 +19,19 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/keyformat"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/cache"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/writelog"
 )
 
-const dbVersion = 3
+const (
+	dbVersion = 4
+
+	// dbVersionLegacyWriteLogs is the last database version that stored
+	// write logs without a format prefix. Such databases are migrated on
+	// open.
+	dbVersionLegacyWriteLogs = 3
+)
 
 var (
 	// nodeKeyFmt is the key format for nodes (node hash).
@@ -30,7 +41,8 @@ var (
 	// writeLogKeyFmt is the key format for write logs (version, new root,
 	// old root).
 	//
-	// Value is CBOR-serialized write log.
+	// Value is a format byte followed by the (optionally compressed)
+	// CBOR-serialized write log.
 	writeLogKeyFmt = keyformat.New(0x01, uint64(0), &hash.Hash{}, &hash.Hash{})
 	// rootsMetadataKeyFmt is the key format for roots metadata. The key format is (version).
 	//
@@ -46,6 +58,16 @@ var (
 	//
 	// Value is CBOR-serialized metadata.
 	metadataKeyFmt = keyformat.New(0x04)
+	// finalizeJournalKeyFmt is the key format for the journal of an in-progress
+	// finalization.
+	//
+	// Value is CBOR-serialized finalizeJournal.
+	finalizeJournalKeyFmt = keyformat.New(0x05)
+	// versionMetadataKeyFmt is the key format for version metadata. The key
+	// format is (version).
+	//
+	// Value is CBOR-serialized versionMetadata.
+	versionMetadataKeyFmt = keyformat.New(0x06, uint64(0))
 )
 
 // New creates a new BadgerDB-backed node database.
@@ -55,6 +77,17 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 		namespace:        cfg.Namespace,
 		readOnly:         cfg.ReadOnly,
 		discardWriteLogs: cfg.DiscardWriteLogs,
+
+		checkpointChunkSize: cfg.CheckpointChunkSize,
+		quota:               cfg.Quota,
+	}
+
+	var err error
+	if db.writeLogFormat, err = writeLogFormatFromCompression(cfg.WriteLogCompression); err != nil {
+		return nil, err
+	}
+	if db.nodeCache, err = cache.New(cfg.NodeCachePolicy, cfg.NodeCacheSize); err != nil {
+		return nil, err
 	}
 
 	opts := badger.DefaultOptions(cfg.DB)
@@ -72,7 +105,6 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 		opts = opts.WithInMemory(true).WithDir("").WithValueDir("")
 	}
 
-	var err error
 	if db.db, err = badger.OpenManaged(opts); err != nil {
 		return nil, fmt.Errorf("mkvs/badger: failed to open database: %w", err)
 	}
@@ -86,6 +118,12 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 		return nil, fmt.Errorf("mkvs/badger: failed to load metadata: %w", err)
 	}
 
+	// Complete any finalization that was interrupted by a crash.
+	if err = db.recoverFinalize(); err != nil {
+		_ = db.db.Close()
+		return nil, fmt.Errorf("mkvs/badger: failed to recover finalization: %w", err)
+	}
+
 	db.gc = cmnBadger.NewGCWorker(db.logger, db.db)
 
 	return db, nil
@@ -98,10 +136,17 @@ type badgerNodeDB struct { // nolint: maligned
 
 	readOnly         bool
 	discardWriteLogs bool
+	writeLogFormat   writeLogFormat
+
+	checkpointChunkSize uint64
+	quota               uint64
 
 	db *badger.DB
 	gc *cmnBadger.GCWorker
 
+	// nodeCache is the decoded node cache (nil if disabled).
+	nodeCache *cache.Cache
+
 	// metaUpdateLock must be held at any point where data at tsMetadata is read and updated. This
 	// is required because all metadata updates happen at the same timestamp and as such conflicts
 	// cannot be detected.
@@ -128,7 +173,15 @@ func (d *badgerNodeDB) load() error {
 			return err
 		}
 
-		if d.meta.value.Version != dbVersion {
+		switch d.meta.value.Version {
+		case dbVersion:
+		case dbVersionLegacyWriteLogs:
+			if d.readOnly {
+				return fmt.Errorf("database version %d requires migration, open in read-write mode",
+					d.meta.value.Version,
+				)
+			}
+		default:
 			return fmt.Errorf("incompatible database version (expected: %d got: %d)",
 				dbVersion,
 				d.meta.value.Version,
@@ -140,6 +193,20 @@ func (d *badgerNodeDB) load() error {
 				d.meta.value.Namespace,
 			)
 		}
+
+		if d.meta.value.Version == dbVersion {
+			return nil
+		}
+
+		// Migrate write logs, only updating the database version once the
+		// migration has completed.
+		d.logger.Info("migrating database",
+			"from_version", d.meta.value.Version,
+			"to_version", dbVersion,
+		)
+		if err = d.migrateWriteLogs(); err != nil {
+			return err
+		}
 	case badger.ErrKeyNotFound:
 	default:
 		return err
@@ -155,6 +222,12 @@ func (d *badgerNodeDB) load() error {
 	return tx.CommitAt(tsMetadata, nil)
 }
 
+func (d *badgerNodeDB) removeCachedNode(h hash.Hash) {
+	if d.nodeCache != nil {
+		d.nodeCache.Remove(h)
+	}
+}
+
 func (d *badgerNodeDB) sanityCheckNamespace(ns common.Namespace) error {
 	if !ns.Equal(&d.namespace) {
 		return api.ErrBadNamespace
@@ -175,6 +248,12 @@ func (d *badgerNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, er
 		return nil, api.ErrNodeNotFound
 	}
 
+	if d.nodeCache != nil {
+		if n, ok := d.nodeCache.Get(ptr.Hash); ok {
+			return n, nil
+		}
+	}
+
 	tx := d.db.NewTransactionAt(versionToTs(root.Version), false)
 	defer tx.Discard()
 	item, err := tx.Get(nodeKeyFmt.Encode(&ptr.Hash))
@@ -201,6 +280,10 @@ func (d *badgerNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, er
 		return nil, fmt.Errorf("mkvs/badger: failed to unmarshal node: %w", err)
 	}
 
+	if d.nodeCache != nil {
+		d.nodeCache.Put(n)
+	}
+
 	return n, nil
 }
 
@@ -311,7 +394,7 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot node.Root, end
 
 							var log api.HashedDBWriteLog
 							err = item.Value(func(data []byte) error {
-								return cbor.UnmarshalTrusted(data, &log)
+								return decodeWriteLogValue(data, &log)
 							})
 							if err != nil {
 								return node.Root{}, nil, err
@@ -405,7 +488,7 @@ func (d *badgerNodeDB) HasRoot(root node.Root) bool {
 	return rootsMeta.Roots[root.Hash] != nil
 }
 
-func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []hash.Hash) error { // nolint: gocyclo
+func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []hash.Hash) error {
 	if d.readOnly {
 		return api.ErrReadOnly
 	}
@@ -413,13 +496,6 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 	d.metaUpdateLock.Lock()
 	defer d.metaUpdateLock.Unlock()
 
-	// Version batch collects removals at the version timestamp.
-	versionBatch := d.db.NewWriteBatchAt(versionToTs(version))
-	defer versionBatch.Cancel()
-	// Transaction is used to read at the version timestamp.
-	tx := d.db.NewTransactionAt(versionToTs(version), true)
-	defer tx.Discard()
-
 	// Make sure that the previous version has been finalized.
 	lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion()
 	if version > 0 && exists && lastFinalizedVersion < (version-1) {
@@ -430,6 +506,32 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 		return api.ErrAlreadyFinalized
 	}
 
+	// Journal the finalization before making any changes, so that in case the
+	// process crashes during finalization, it can be completed on startup with
+	// the same set of finalized roots.
+	if err := d.saveFinalizeJournal(&finalizeJournal{Version: version, Roots: roots}); err != nil {
+		return err
+	}
+	if err := testFinalizeHook(finalizeStageJournaled); err != nil {
+		return err
+	}
+
+	return d.doFinalize(version, roots)
+}
+
+// doFinalize performs the finalization of the given version. The finalization
+// journal must have been saved before calling this method.
+//
+// This method must be idempotent, as it may be called again on startup after
+// a partially completed finalization.
+func (d *badgerNodeDB) doFinalize(version uint64, roots []hash.Hash) error { // nolint: gocyclo
+	// Version batch collects removals at the version timestamp.
+	versionBatch := d.db.NewWriteBatchAt(versionToTs(version))
+	defer versionBatch.Cancel()
+	// Transaction is used to read at the version timestamp.
+	tx := d.db.NewTransactionAt(versionToTs(version), true)
+	defer tx.Discard()
+
 	// Determine a set of finalized roots. Finalization is transitive, so if
 	// a parent root is finalized the child should be consider finalized too.
 	finalizedRoots := make(map[hash.Hash]bool)
@@ -540,12 +642,16 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 		if err := versionBatch.Delete(key); err != nil {
 			return err
 		}
+		d.removeCachedNode(h)
 	}
 
 	// Commit batch.
 	if err := versionBatch.Flush(); err != nil {
 		return err
 	}
+	if err := testFinalizeHook(finalizeStageNodesRemoved); err != nil {
+		return err
+	}
 
 	// Save roots metadata if changed.
 	if rootsChanged {
@@ -554,17 +660,114 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 		}
 	}
 
+	// Record the finalization time, unless we are completing an interrupted
+	// finalization which already recorded it.
+	versionMeta, exists, err := loadVersionMetadata(tx, version)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		versionMeta.CreatedAt = time.Now().Unix()
+	}
+	versionMetaChanged := !exists
+	if versionMeta.FinalizedAt == 0 {
+		versionMeta.FinalizedAt = time.Now().Unix()
+		versionMetaChanged = true
+	}
+
+	// Release the usage of removed non-finalized roots.
+	var released uint64
+	for rootHash, ru := range versionMeta.Roots {
+		if _, ok := rootsMeta.Roots[rootHash]; ok {
+			continue
+		}
+		released += ru.size()
+		delete(versionMeta.Roots, rootHash)
+		versionMetaChanged = true
+	}
+	if released > 0 {
+		if err = d.meta.updateSize(tx, 0, released); err != nil {
+			return fmt.Errorf("mkvs/badger: failed to update usage: %w", err)
+		}
+	}
+
+	if versionMetaChanged {
+		if err = versionMeta.save(tx); err != nil {
+			return fmt.Errorf("mkvs/badger: failed to save version metadata: %w", err)
+		}
+	}
+
 	// Update last finalized version.
 	if err := d.meta.setLastFinalizedVersion(tx, version); err != nil {
 		return fmt.Errorf("mkvs/badger: failed to set last finalized version: %w", err)
 	}
 
+	// Finalization is complete once the metadata is committed, so the journal
+	// is removed in the same transaction.
+	if err := tx.Delete(finalizeJournalKeyFmt.Encode()); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to remove finalize journal: %w", err)
+	}
+
 	if err := tx.CommitAt(tsMetadata, nil); err != nil {
 		return fmt.Errorf("mkvs/badger: failed to commit metadata: %w", err)
 	}
 	return nil
 }
 
+func (d *badgerNodeDB) saveFinalizeJournal(journal *finalizeJournal) error {
+	tx := d.db.NewTransactionAt(tsMetadata, true)
+	defer tx.Discard()
+
+	if err := tx.Set(finalizeJournalKeyFmt.Encode(), cbor.Marshal(journal)); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to save finalize journal: %w", err)
+	}
+	if err := tx.CommitAt(tsMetadata, nil); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to commit finalize journal: %w", err)
+	}
+	return nil
+}
+
+// recoverFinalize completes a finalization that was interrupted (e.g., due to
+// a crash) based on the finalization journal.
+func (d *badgerNodeDB) recoverFinalize() error {
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	item, err := tx.Get(finalizeJournalKeyFmt.Encode())
+	switch err {
+	case nil:
+	case badger.ErrKeyNotFound:
+		// No finalization in progress.
+		return nil
+	default:
+		return err
+	}
+
+	var journal finalizeJournal
+	if err = item.Value(func(data []byte) error {
+		return cbor.UnmarshalTrusted(data, &journal)
+	}); err != nil {
+		return fmt.Errorf("corrupted finalize journal: %w", err)
+	}
+
+	if d.readOnly {
+		d.logger.Warn("not completing interrupted finalization in read-only mode",
+			"version", journal.Version,
+		)
+		return nil
+	}
+
+	d.logger.Warn("completing interrupted finalization",
+		"version", journal.Version,
+		"roots", journal.Roots,
+	)
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	return d.doFinalize(journal.Version, journal.Roots)
+}
+
 func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 	if d.readOnly {
 		return api.ErrReadOnly
@@ -620,6 +823,7 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 				if innerErr = batch.Delete(nodeKeyFmt.Encode(&h)); innerErr != nil {
 					return false
 				}
+				d.removeCachedNode(h)
 			}
 			return true
 		})
@@ -631,10 +835,33 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 		}
 	}
 
+	// Release the usage of the pruned data. This includes the nodes of lone
+	// roots, the nodes removed by all roots and the write logs.
+	versionMeta, _, err := loadVersionMetadata(tx, version)
+	if err != nil {
+		return err
+	}
+	var released uint64
+	for rootHash, ru := range versionMeta.Roots {
+		released += ru.RemovedNodes + ru.WriteLogs
+		if maybeLoneRoots[rootHash] {
+			released += ru.Nodes
+		}
+	}
+	if released > 0 {
+		if err = d.meta.updateSize(tx, 0, released); err != nil {
+			return fmt.Errorf("mkvs/badger: failed to update usage: %w", err)
+		}
+	}
+
 	// Delete roots metadata.
 	if err := tx.Delete(rootsMetadataKeyFmt.Encode(version)); err != nil {
 		return fmt.Errorf("mkvs/badger: failed to remove roots metadata: %w", err)
 	}
+	// Delete version metadata.
+	if err := tx.Delete(versionMetadataKeyFmt.Encode(version)); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to remove version metadata: %w", err)
+	}
 
 	// Prune all write logs in version.
 	if !d.discardWriteLogs {
@@ -671,6 +898,177 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 	return nil
 }
 
+// ensureVersionMetadata makes sure that metadata exists for the given version,
+// recording the current time as its creation time if it does not.
+func (d *badgerNodeDB) ensureVersionMetadata(tx *badger.Txn, version uint64) error {
+	versionMeta, exists, err := loadVersionMetadata(tx, version)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	versionMeta.CreatedAt = time.Now().Unix()
+	if err = versionMeta.save(tx); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to save version metadata: %w", err)
+	}
+	return nil
+}
+
+// accountUsage adds the storage usage of a batch committed for the given root
+// to the version metadata and the total usage, enforcing the quota.
+func (d *badgerNodeDB) accountUsage(tx *badger.Txn, root node.Root, usage *rootUsage) error {
+	added := usage.size()
+	if d.quota > 0 && d.meta.getSize()+added > d.quota {
+		d.logger.Error("storage quota exceeded",
+			"root", root,
+			"size", d.meta.getSize(),
+			"added", added,
+			"quota", d.quota,
+		)
+		return api.ErrQuotaExceeded
+	}
+
+	versionMeta, _, err := loadVersionMetadata(tx, root.Version)
+	if err != nil {
+		return err
+	}
+	ru := versionMeta.rootUsage(root.Hash)
+	ru.Nodes += usage.Nodes
+	ru.RemovedNodes += usage.RemovedNodes
+	ru.WriteLogs += usage.WriteLogs
+	if err = versionMeta.save(tx); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to save version metadata: %w", err)
+	}
+
+	if err = d.meta.updateSize(tx, added, 0); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to update usage: %w", err)
+	}
+	return nil
+}
+
+func (d *badgerNodeDB) SetVersionConsensusHeight(ctx context.Context, version uint64, height int64) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	// If the version is earlier than the earliest version, it was pruned.
+	if version < d.meta.getEarliestVersion() {
+		return api.ErrVersionNotFound
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, true)
+	defer tx.Discard()
+
+	versionMeta, exists, err := loadVersionMetadata(tx, version)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		versionMeta.CreatedAt = time.Now().Unix()
+	}
+	versionMeta.ConsensusHeight = height
+	if err = versionMeta.save(tx); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to save version metadata: %w", err)
+	}
+	return tx.CommitAt(tsMetadata, nil)
+}
+
+func (d *badgerNodeDB) GetVersionMetadata(ctx context.Context, version uint64) (*api.VersionMetadata, error) {
+	// If the version is earlier than the earliest version, it was pruned.
+	if version < d.meta.getEarliestVersion() {
+		return nil, api.ErrVersionNotFound
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	versionMeta, exists, err := loadVersionMetadata(tx, version)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, api.ErrVersionNotFound
+	}
+	return versionMeta.toAPI(d.meta.getLastFinalizedVersion()), nil
+}
+
+func (d *badgerNodeDB) GetVersions(ctx context.Context) ([]*api.VersionMetadata, error) {
+	earliestVersion := d.meta.getEarliestVersion()
+	lastFinalizedVersion, finalizedExists := d.meta.getLastFinalizedVersion()
+
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: versionMetadataKeyFmt.Encode()})
+	defer it.Close()
+
+	var versions []*api.VersionMetadata
+	for it.Rewind(); it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var version uint64
+		if !versionMetadataKeyFmt.Decode(it.Item().Key(), &version) {
+			continue
+		}
+		// Pruned versions may still be present until compaction.
+		if version < earliestVersion {
+			continue
+		}
+
+		versionMeta := &versionMetadata{version: version}
+		if err := it.Item().Value(func(val []byte) error { return cbor.UnmarshalTrusted(val, versionMeta) }); err != nil {
+			return nil, fmt.Errorf("mkvs/badger: error reading version metadata: %w", err)
+		}
+		versions = append(versions, versionMeta.toAPI(lastFinalizedVersion, finalizedExists))
+	}
+	return versions, nil
+}
+
+func (d *badgerNodeDB) Checkpoint(ctx context.Context, version uint64) (api.ChunkIterator, error) {
+	// Only finalized versions can be checkpointed as otherwise the set of roots
+	// is not yet known.
+	lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion()
+	if !exists || version > lastFinalizedVersion {
+		return nil, api.ErrNotFinalized
+	}
+	if version < d.meta.getEarliestVersion() {
+		return nil, api.ErrVersionNotFound
+	}
+
+	rootHashes, err := d.GetRootsForVersion(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the chunks are always generated in the same order.
+	sort.Slice(rootHashes, func(i, j int) bool {
+		return bytes.Compare(rootHashes[i][:], rootHashes[j][:]) < 0
+	})
+
+	roots := make([]node.Root, 0, len(rootHashes))
+	for _, rootHash := range rootHashes {
+		roots = append(roots, node.Root{
+			Namespace: d.namespace,
+			Version:   version,
+			Hash:      rootHash,
+		})
+	}
+	return api.NewCheckpointIterator(ctx, d, roots, d.checkpointChunkSize), nil
+}
+
+func (d *badgerNodeDB) RestoreCheckpoint(ctx context.Context, chunks api.ChunkIterator) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+	return api.RestoreCheckpoint(ctx, d, chunks)
+}
+
 func (d *badgerNodeDB) NewBatch(oldRoot node.Root, version uint64, chunk bool) api.Batch {
 	// WARNING: There is a maximum batch size and maximum batch entry count.
 	// Both of these things are derived from the MaxTableSize option.
@@ -687,6 +1085,19 @@ func (d *badgerNodeDB) NewBatch(oldRoot node.Root, version uint64, chunk bool) a
 	}
 }
 
+func (d *badgerNodeDB) GetUsage(ctx context.Context) (*api.Usage, error) {
+	diskSize, err := d.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Usage{
+		Size:     d.meta.getSize(),
+		Quota:    d.quota,
+		DiskSize: diskSize,
+	}, nil
+}
+
 func (d *badgerNodeDB) Size() (int64, error) {
 	lsm, vlog := d.db.Size()
 	return lsm + vlog, nil
@@ -716,6 +1127,9 @@ type badgerBatch struct {
 	writeLog     writelog.WriteLog
 	annotations  writelog.Annotations
 	updatedNodes []updatedNode
+
+	// usage is the accounted storage usage of the batch.
+	usage rootUsage
 }
 
 func (ba *badgerBatch) MaybeStartSubtree(subtree api.Subtree, depth node.Depth, subtreeRoot *node.Pointer) api.Subtree {
@@ -744,10 +1158,28 @@ func (ba *badgerBatch) RemoveNodes(nodes []node.Node) error {
 	}
 
 	for _, n := range nodes {
+		h := n.GetHash()
 		ba.updatedNodes = append(ba.updatedNodes, updatedNode{
 			Removed: true,
-			Hash:    n.GetHash(),
+			Hash:    h,
 		})
+
+		data, err := n.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		ba.usage.RemovedNodes += uint64(len(nodeKeyFmt.Encode(&h)) + len(data))
+	}
+	return nil
+}
+
+func (ba *badgerBatch) RetainNodes(hashes []hash.Hash) error {
+	if ba.chunk {
+		return fmt.Errorf("mkvs/badger: cannot retain nodes in chunk mode")
+	}
+
+	for _, h := range hashes {
+		ba.updatedNodes = append(ba.updatedNodes, updatedNode{Hash: h})
 	}
 	return nil
 }
@@ -799,6 +1231,11 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 		if err = rootsMeta.save(tx); err != nil {
 			return fmt.Errorf("mkvs/badger: failed to save roots metadata: %w", err)
 		}
+
+		// Record the creation time of the version on its first root.
+		if err = ba.db.ensureVersionMetadata(tx, root.Version); err != nil {
+			return err
+		}
 	}
 
 	if ba.chunk {
@@ -839,14 +1276,23 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 		// Store write log.
 		if ba.writeLog != nil && ba.annotations != nil {
 			log := api.MakeHashedDBWriteLog(ba.writeLog, ba.annotations)
-			bytes := cbor.Marshal(log)
+			var bytes []byte
+			if bytes, err = encodeWriteLogValue(ba.db.writeLogFormat, cbor.Marshal(log)); err != nil {
+				return err
+			}
 			key := writeLogKeyFmt.Encode(root.Version, &root.Hash, &ba.oldRoot.Hash)
 			if err = ba.bat.Set(key, bytes); err != nil {
 				return fmt.Errorf("mkvs/badger: set new write log returned error: %w", err)
 			}
+			ba.usage.WriteLogs += uint64(len(key) + len(bytes))
 		}
 	}
 
+	// Account the storage usage of the batch.
+	if err = ba.db.accountUsage(tx, root, &ba.usage); err != nil {
+		return err
+	}
+
 	// Flush node updates.
 	if err = ba.bat.Flush(); err != nil {
 		return fmt.Errorf("mkvs/badger: failed to flush batch: %w", err)
@@ -860,6 +1306,7 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 	ba.writeLog = nil
 	ba.annotations = nil
 	ba.updatedNodes = nil
+	ba.usage = rootUsage{}
 
 	return ba.BaseBatch.Commit(root)
 }
@@ -869,6 +1316,7 @@ func (ba *badgerBatch) Reset() {
 	ba.writeLog = nil
 	ba.annotations = nil
 	ba.updatedNodes = nil
+	ba.usage = rootUsage{}
 }
 
 type badgerSubtree struct {
@@ -882,10 +1330,12 @@ func (s *badgerSubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
 	}
 
 	h := ptr.Node.GetHash()
+	key := nodeKeyFmt.Encode(&h)
 	s.batch.updatedNodes = append(s.batch.updatedNodes, updatedNode{Hash: h})
-	if err = s.batch.bat.Set(nodeKeyFmt.Encode(&h), data); err != nil {
+	if err = s.batch.bat.Set(key, data); err != nil {
 		return err
 	}
+	s.batch.usage.Nodes += uint64(len(key) + len(data))
 	return nil
 }
 
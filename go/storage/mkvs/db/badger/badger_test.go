@@ -0,0 +1,312 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+var testNs = common.NewTestNamespaceFromSeed([]byte("oasis mkvs badger test ns"), 0)
+
+var errTestCrash = errors.New("test: simulated crash")
+
+func TestFinalizeCrashRecovery(t *testing.T) {
+	for _, stage := range []finalizeStage{
+		finalizeStageJournaled,
+		finalizeStageNodesRemoved,
+	} {
+		t.Run(fmt.Sprintf("Stage%d", stage), func(t *testing.T) {
+			testFinalizeCrashRecovery(t, stage)
+		})
+	}
+}
+
+func testFinalizeCrashRecovery(t *testing.T, stage finalizeStage) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "mkvs.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	cfg := &api.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+	}
+	ndb, err := New(cfg)
+	require.NoError(err, "New")
+
+	emptyRoot := node.Root{Namespace: testNs}
+	emptyRoot.Hash.Empty()
+
+	// Create multiple roots in the same version, only some of which will be finalized.
+	var roots []node.Root
+	for i := 0; i < 3; i++ {
+		tree := mkvs.New(nil, ndb)
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("key %d", i)), []byte(fmt.Sprintf("value %d", i)))
+		require.NoError(err, "Insert")
+		_, rootHash, cerr := tree.Commit(ctx, testNs, 0)
+		require.NoError(cerr, "Commit")
+		tree.Close()
+
+		roots = append(roots, node.Root{Namespace: testNs, Version: 0, Hash: rootHash})
+	}
+	finalizedRoots := []hash.Hash{roots[0].Hash, roots[1].Hash}
+
+	// Simulate a crash during finalization.
+	testFinalizeHook = func(s finalizeStage) error {
+		if s == stage {
+			return errTestCrash
+		}
+		return nil
+	}
+	defer func() {
+		testFinalizeHook = func(finalizeStage) error { return nil }
+	}()
+
+	err = ndb.Finalize(ctx, 0, finalizedRoots)
+	require.Error(err, "Finalize should fail due to the simulated crash")
+	require.True(errors.Is(err, errTestCrash))
+	ndb.Close()
+
+	testFinalizeHook = func(finalizeStage) error { return nil }
+
+	// Reopening the database should complete the finalization.
+	ndb, err = New(cfg)
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	err = ndb.Finalize(ctx, 0, finalizedRoots)
+	require.Error(err, "Finalize should fail as the version has already been finalized")
+	require.True(errors.Is(err, api.ErrAlreadyFinalized))
+
+	rootHashes, err := ndb.GetRootsForVersion(ctx, 0)
+	require.NoError(err, "GetRootsForVersion")
+	require.ElementsMatch(finalizedRoots, rootHashes, "only finalized roots should remain")
+	require.False(ndb.HasRoot(roots[2]), "non-finalized root should be removed")
+
+	for i, root := range roots[:2] {
+		require.True(ndb.HasRoot(root), "finalized root should exist")
+
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		value, gerr := tree.Get(ctx, []byte(fmt.Sprintf("key %d", i)))
+		tree.Close()
+		require.NoError(gerr, "Get")
+		require.Equal([]byte(fmt.Sprintf("value %d", i)), value, "finalized root data should be available")
+
+		it, werr := ndb.GetWriteLog(ctx, emptyRoot, root)
+		require.NoError(werr, "GetWriteLog")
+		more, werr := it.Next()
+		require.NoError(werr, "it.Next()")
+		require.True(more, "write log of a finalized root should be available")
+	}
+
+	// The next version should be finalizable.
+	tree := mkvs.NewWithRoot(nil, ndb, roots[0])
+	err = tree.Insert(ctx, []byte("key next"), []byte("value next"))
+	require.NoError(err, "Insert")
+	_, rootHash, err := tree.Commit(ctx, testNs, 1)
+	require.NoError(err, "Commit")
+	tree.Close()
+
+	err = ndb.Finalize(ctx, 1, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize")
+}
+
+func TestVersionMetadata(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "mkvs.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := New(&api.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	versions, err := ndb.GetVersions(ctx)
+	require.NoError(err, "GetVersions")
+	require.Empty(versions, "empty database should have no versions")
+
+	var roots []hash.Hash
+	for version := uint64(0); version < 2; version++ {
+		tree := mkvs.New(nil, ndb)
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("key %d", version)), []byte("value"))
+		require.NoError(err, "Insert")
+		_, rootHash, cerr := tree.Commit(ctx, testNs, version)
+		require.NoError(cerr, "Commit")
+		tree.Close()
+
+		roots = append(roots, rootHash)
+	}
+
+	versions, err = ndb.GetVersions(ctx)
+	require.NoError(err, "GetVersions")
+	require.Len(versions, 2, "all committed versions should have metadata")
+	for i, meta := range versions {
+		require.EqualValues(i, meta.Version, "versions should be ordered")
+		require.False(meta.CreatedAt.IsZero(), "creation time should be recorded")
+		require.False(meta.Finalized, "version should not be finalized")
+		require.Nil(meta.FinalizedAt, "finalization time should not be recorded")
+	}
+
+	err = ndb.SetVersionConsensusHeight(ctx, 0, 42)
+	require.NoError(err, "SetVersionConsensusHeight")
+	err = ndb.Finalize(ctx, 0, []hash.Hash{roots[0]})
+	require.NoError(err, "Finalize")
+
+	meta, err := ndb.GetVersionMetadata(ctx, 0)
+	require.NoError(err, "GetVersionMetadata")
+	require.EqualValues(42, meta.ConsensusHeight, "consensus height should be recorded")
+	require.True(meta.Finalized, "version should be finalized")
+	require.NotNil(meta.FinalizedAt, "finalization time should be recorded")
+	require.False(meta.FinalizedAt.Before(meta.CreatedAt), "version should be finalized after it was created")
+
+	meta, err = ndb.GetVersionMetadata(ctx, 1)
+	require.NoError(err, "GetVersionMetadata")
+	require.EqualValues(0, meta.ConsensusHeight, "consensus height should not be known")
+	require.False(meta.Finalized, "version should not be finalized")
+
+	_, err = ndb.GetVersionMetadata(ctx, 2)
+	require.True(errors.Is(err, api.ErrVersionNotFound), "metadata of a missing version should not be found")
+
+	err = ndb.Finalize(ctx, 1, []hash.Hash{roots[1]})
+	require.NoError(err, "Finalize")
+	err = ndb.Prune(ctx, 0)
+	require.NoError(err, "Prune")
+
+	_, err = ndb.GetVersionMetadata(ctx, 0)
+	require.True(errors.Is(err, api.ErrVersionNotFound), "metadata of a pruned version should not be found")
+	err = ndb.SetVersionConsensusHeight(ctx, 0, 43)
+	require.True(errors.Is(err, api.ErrVersionNotFound), "pruned version should not be annotated")
+
+	versions, err = ndb.GetVersions(ctx)
+	require.NoError(err, "GetVersions")
+	require.Len(versions, 1, "pruned versions should not be listed")
+	require.EqualValues(1, versions[0].Version)
+	require.True(versions[0].Finalized, "version should be finalized")
+}
+
+func TestUsage(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "mkvs.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := New(&api.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	usage, err := ndb.GetUsage(ctx)
+	require.NoError(err, "GetUsage")
+	require.EqualValues(0, usage.Size, "empty database should have no usage")
+
+	commit := func(oldRoot node.Root, version uint64, key string) node.Root {
+		tree := mkvs.NewWithRoot(nil, ndb, oldRoot)
+		defer tree.Close()
+		err = tree.Insert(ctx, []byte(key), []byte("value"))
+		require.NoError(err, "Insert")
+		_, rootHash, cerr := tree.Commit(ctx, testNs, version)
+		require.NoError(cerr, "Commit")
+		return node.Root{Namespace: testNs, Version: version, Hash: rootHash}
+	}
+	emptyRoot := node.Root{Namespace: testNs}
+	emptyRoot.Hash.Empty()
+
+	// Commit two roots in version 0, only one of which is finalized.
+	root0 := commit(emptyRoot, 0, "key 0")
+	root0b := commit(emptyRoot, 0, "key 0b")
+
+	meta, err := ndb.GetVersionMetadata(ctx, 0)
+	require.NoError(err, "GetVersionMetadata")
+	require.Len(meta.RootSizes, 2, "usage of all roots should be accounted")
+	require.NotZero(meta.RootSizes[root0.Hash])
+	require.NotZero(meta.RootSizes[root0b.Hash])
+	require.EqualValues(meta.RootSizes[root0.Hash]+meta.RootSizes[root0b.Hash], meta.Size)
+	usage, err = ndb.GetUsage(ctx)
+	require.NoError(err, "GetUsage")
+	require.EqualValues(meta.Size, usage.Size, "usage should include all roots")
+
+	err = ndb.Finalize(ctx, 0, []hash.Hash{root0.Hash})
+	require.NoError(err, "Finalize")
+
+	meta, err = ndb.GetVersionMetadata(ctx, 0)
+	require.NoError(err, "GetVersionMetadata")
+	require.Len(meta.RootSizes, 1, "usage of non-finalized roots should be released")
+	size0 := meta.Size
+	usage, err = ndb.GetUsage(ctx)
+	require.NoError(err, "GetUsage")
+	require.EqualValues(size0, usage.Size, "usage of non-finalized roots should be released")
+
+	// Derive a root in version 1.
+	root1 := commit(root0, 1, "key 1")
+	err = ndb.Finalize(ctx, 1, []hash.Hash{root1.Hash})
+	require.NoError(err, "Finalize")
+
+	meta, err = ndb.GetVersionMetadata(ctx, 1)
+	require.NoError(err, "GetVersionMetadata")
+	size1 := meta.Size
+	usage, err = ndb.GetUsage(ctx)
+	require.NoError(err, "GetUsage")
+	require.EqualValues(size0+size1, usage.Size, "usage should include all versions")
+
+	// Pruning releases the write logs, but not the nodes that are still live.
+	err = ndb.Prune(ctx, 0)
+	require.NoError(err, "Prune")
+	usage, err = ndb.GetUsage(ctx)
+	require.NoError(err, "GetUsage")
+	require.True(usage.Size < size0+size1, "usage of pruned write logs should be released")
+	require.True(usage.Size > size1, "usage of live nodes should not be released")
+
+	// Accounting should persist across restarts.
+	ndb.Close()
+	ndb, err = New(&api.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+		Quota:        usage.Size + 1,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	reopenedUsage, err := ndb.GetUsage(ctx)
+	require.NoError(err, "GetUsage")
+	require.EqualValues(usage.Size, reopenedUsage.Size, "usage should persist")
+	require.EqualValues(usage.Size+1, reopenedUsage.Quota)
+
+	// Commits exceeding the quota should fail.
+	tree := mkvs.NewWithRoot(nil, ndb, root1)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("key 2"), []byte("value"))
+	require.NoError(err, "Insert")
+	_, _, err = tree.Commit(ctx, testNs, 2)
+	require.True(errors.Is(err, api.ErrQuotaExceeded), "commit exceeding the quota should fail")
+}
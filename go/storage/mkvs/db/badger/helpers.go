@@ -9,3 +9,22 @@ func versionToTs(version uint64) uint64 {
 	// Version 0 starts at timestamp after metadata.
 	return tsMetadata + 1 + version
 }
+
+// finalizeStage is a stage of finalization.
+type finalizeStage uint8
+
+const (
+	// finalizeStageJournaled is the stage after the finalization journal has
+	// been saved.
+	finalizeStageJournaled finalizeStage = iota
+	// finalizeStageNodesRemoved is the stage after the nodes of non-finalized
+	// roots have been removed, but before the metadata has been updated.
+	finalizeStageNodesRemoved
+)
+
+// testFinalizeHook is called at each stage of finalization. In case it returns
+// an error, finalization is aborted at that stage. This is only used in tests
+// to simulate crashes.
+var testFinalizeHook = func(finalizeStage) error {
+	return nil
+}
@@ -3,12 +3,14 @@ package badger
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
 )
 
 // serializedMetadata is the on-disk serialized metadata.
@@ -22,6 +24,9 @@ type serializedMetadata struct {
 	EarliestVersion uint64 `json:"earliest_version"`
 	// LastFinalizedVersion is the last finalized version.
 	LastFinalizedVersion *uint64 `json:"last_finalized_version"`
+
+	// Size is the amount of accounted data in bytes.
+	Size uint64 `json:"size,omitempty"`
 }
 
 // metadata is the database metadata.
@@ -77,6 +82,27 @@ func (m *metadata) setLastFinalizedVersion(tx *badger.Txn, version uint64) error
 	return m.save(tx)
 }
 
+func (m *metadata) getSize() uint64 {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.value.Size
+}
+
+func (m *metadata) updateSize(tx *badger.Txn, added, released uint64) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.value.Size += added
+	// Data committed before accounting was introduced has not been accounted
+	// for, so never release more than what is accounted.
+	if released > m.value.Size {
+		released = m.value.Size
+	}
+	m.value.Size -= released
+	return m.save(tx)
+}
+
 func (m *metadata) save(tx *badger.Txn) error {
 	return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(m.value))
 }
@@ -91,6 +117,16 @@ type updatedNode struct {
 	Hash    hash.Hash
 }
 
+// finalizeJournal is the journal of an in-progress finalization.
+//
+// NOTE: Public fields of this structure are part of the on-disk format.
+type finalizeJournal struct {
+	// Version is the version being finalized.
+	Version uint64 `json:"version"`
+	// Roots are the roots of the version being finalized.
+	Roots []hash.Hash `json:"roots"`
+}
+
 // rootsMetadata manages the roots metadata for a given version.
 //
 // NOTE: Public fields of this structure are part of the on-disk format.
@@ -125,3 +161,99 @@ func loadRootsMetadata(tx *badger.Txn, version uint64) (*rootsMetadata, error) {
 func (rm *rootsMetadata) save(tx *badger.Txn) error {
 	return tx.Set(rootsMetadataKeyFmt.Encode(rm.version), cbor.Marshal(rm))
 }
+
+// versionMetadata is the metadata recorded for a given version.
+//
+// NOTE: Public fields of this structure are part of the on-disk format.
+type versionMetadata struct {
+	// CreatedAt is the UNIX timestamp of when the first root of the version
+	// was committed.
+	CreatedAt int64 `json:"created_at"`
+	// ConsensusHeight is the consensus height of the block that produced
+	// the version.
+	ConsensusHeight int64 `json:"consensus_height,omitempty"`
+	// FinalizedAt is the UNIX timestamp of when the version was finalized.
+	FinalizedAt int64 `json:"finalized_at,omitempty"`
+	// Roots is the accounted storage usage of each root of the version.
+	Roots map[hash.Hash]*rootUsage `json:"roots,omitempty"`
+
+	// version is the version this metadata is for.
+	version uint64
+}
+
+// rootUsage is the accounted storage usage of a root.
+//
+// NOTE: Public fields of this structure are part of the on-disk format.
+type rootUsage struct {
+	// Nodes is the size of the nodes created by the root.
+	Nodes uint64 `json:"nodes,omitempty"`
+	// RemovedNodes is the size of the nodes removed by the root.
+	RemovedNodes uint64 `json:"removed_nodes,omitempty"`
+	// WriteLogs is the size of the write logs stored for the root.
+	WriteLogs uint64 `json:"write_logs,omitempty"`
+}
+
+// size returns the amount of data in bytes written by the root.
+func (ru *rootUsage) size() uint64 {
+	return ru.Nodes + ru.WriteLogs
+}
+
+// rootUsage returns the accounted storage usage of the given root, creating
+// it if it does not yet exist.
+func (vm *versionMetadata) rootUsage(root hash.Hash) *rootUsage {
+	if vm.Roots == nil {
+		vm.Roots = make(map[hash.Hash]*rootUsage)
+	}
+	ru := vm.Roots[root]
+	if ru == nil {
+		ru = &rootUsage{}
+		vm.Roots[root] = ru
+	}
+	return ru
+}
+
+// loadVersionMetadata loads the metadata for the given version from the
+// database. If no metadata exists, the returned metadata is empty and the
+// boolean is false.
+func loadVersionMetadata(tx *badger.Txn, version uint64) (*versionMetadata, bool, error) {
+	versionMeta := &versionMetadata{version: version}
+	item, err := tx.Get(versionMetadataKeyFmt.Encode(version))
+	switch err {
+	case nil:
+		if err = item.Value(func(val []byte) error { return cbor.UnmarshalTrusted(val, versionMeta) }); err != nil {
+			return nil, false, fmt.Errorf("mkvs/badger: error reading version metadata: %w", err)
+		}
+		return versionMeta, true, nil
+	case badger.ErrKeyNotFound:
+		return versionMeta, false, nil
+	default:
+		return nil, false, fmt.Errorf("mkvs/badger: error reading version metadata: %w", err)
+	}
+}
+
+// save saves the version metadata to the database.
+func (vm *versionMetadata) save(tx *badger.Txn) error {
+	return tx.Set(versionMetadataKeyFmt.Encode(vm.version), cbor.Marshal(vm))
+}
+
+// toAPI converts the version metadata into its API representation.
+func (vm *versionMetadata) toAPI(lastFinalizedVersion uint64, finalizedExists bool) *api.VersionMetadata {
+	meta := &api.VersionMetadata{
+		Version:         vm.version,
+		CreatedAt:       time.Unix(vm.CreatedAt, 0),
+		ConsensusHeight: vm.ConsensusHeight,
+		Finalized:       finalizedExists && vm.version <= lastFinalizedVersion,
+	}
+	if len(vm.Roots) > 0 {
+		meta.RootSizes = make(map[hash.Hash]uint64, len(vm.Roots))
+		for root, ru := range vm.Roots {
+			meta.RootSizes[root] = ru.size()
+			meta.Size += ru.size()
+		}
+	}
+	if vm.FinalizedAt != 0 {
+		finalizedAt := time.Unix(vm.FinalizedAt, 0)
+		meta.FinalizedAt = &finalizedAt
+	}
+	return meta
+}
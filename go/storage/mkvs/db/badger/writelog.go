@@ -0,0 +1,172 @@
+package badger
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/DataDog/zstd"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/golang/snappy"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+)
+
+// writeLogFormat is the format of a persisted write log value.
+//
+// Each persisted write log value is prefixed by a single format byte. As
+// CBOR-serialized write logs always start with an array header (major type
+// 4), the format byte values below can never be confused with a legacy
+// (unprefixed) write log value.
+type writeLogFormat uint8
+
+const (
+	// writeLogFormatPlain is an uncompressed CBOR-serialized write log.
+	writeLogFormatPlain writeLogFormat = 0x00
+	// writeLogFormatSnappy is a Snappy-compressed CBOR-serialized write log.
+	writeLogFormatSnappy writeLogFormat = 0x01
+	// writeLogFormatZstd is a Zstandard-compressed CBOR-serialized write log.
+	writeLogFormatZstd writeLogFormat = 0x02
+)
+
+// writeLogFormatFromCompression returns the write log format for the given
+// configured compression algorithm.
+func writeLogFormatFromCompression(compression string) (writeLogFormat, error) {
+	switch compression {
+	case "", api.WriteLogCompressionNone:
+		return writeLogFormatPlain, nil
+	case api.WriteLogCompressionSnappy:
+		return writeLogFormatSnappy, nil
+	case api.WriteLogCompressionZstd:
+		return writeLogFormatZstd, nil
+	default:
+		return 0, fmt.Errorf("mkvs/badger: unsupported write log compression: %s", compression)
+	}
+}
+
+// isLegacyWriteLogValue returns true iff the given value is a write log value
+// without a format prefix as stored by previous database versions.
+func isLegacyWriteLogValue(data []byte) bool {
+	return len(data) == 0 || writeLogFormat(data[0]) > writeLogFormatZstd
+}
+
+// encodeWriteLogValue prefixes and compresses a CBOR-serialized write log.
+func encodeWriteLogValue(format writeLogFormat, raw []byte) ([]byte, error) {
+	switch format {
+	case writeLogFormatPlain:
+		return append([]byte{byte(format)}, raw...), nil
+	case writeLogFormatSnappy:
+		dst := make([]byte, 1+snappy.MaxEncodedLen(len(raw)))
+		dst[0] = byte(format)
+		return dst[:1+len(snappy.Encode(dst[1:], raw))], nil
+	case writeLogFormatZstd:
+		compressed, err := zstd.Compress(nil, raw)
+		if err != nil {
+			return nil, fmt.Errorf("mkvs/badger: failed to compress write log: %w", err)
+		}
+		return append([]byte{byte(format)}, compressed...), nil
+	default:
+		return nil, fmt.Errorf("mkvs/badger: unsupported write log format: %d", format)
+	}
+}
+
+// decodeWriteLogValue decompresses and deserializes a persisted write log.
+func decodeWriteLogValue(data []byte, log *api.HashedDBWriteLog) error {
+	if len(data) == 0 {
+		return fmt.Errorf("mkvs/badger: malformed write log")
+	}
+
+	var (
+		raw []byte
+		err error
+	)
+	switch format := writeLogFormat(data[0]); format {
+	case writeLogFormatPlain:
+		raw = data[1:]
+	case writeLogFormatSnappy:
+		if raw, err = snappy.Decode(nil, data[1:]); err != nil {
+			return fmt.Errorf("mkvs/badger: failed to decompress write log: %w", err)
+		}
+	case writeLogFormatZstd:
+		if raw, err = zstd.Decompress(nil, data[1:]); err != nil {
+			return fmt.Errorf("mkvs/badger: failed to decompress write log: %w", err)
+		}
+	default:
+		return fmt.Errorf("mkvs/badger: unsupported write log format: %d", format)
+	}
+
+	return cbor.UnmarshalTrusted(raw, log)
+}
+
+// migrateWriteLogs rewrites all write logs stored without a format prefix
+// (database version 3) using the configured write log format.
+//
+// Each write log is rewritten at the same timestamp it was originally stored
+// at. The migration is idempotent so it can be safely resumed if interrupted
+// as already migrated values are skipped.
+func (d *badgerNodeDB) migrateWriteLogs() error {
+	tx := d.db.NewTransactionAt(math.MaxUint64, false)
+	defer tx.Discard()
+
+	var (
+		bat     *badger.WriteBatch
+		batTs   uint64
+		count   uint64
+		flushFn = func() error {
+			if bat == nil {
+				return nil
+			}
+			defer bat.Cancel()
+			if err := bat.Flush(); err != nil {
+				return fmt.Errorf("mkvs/badger: failed to flush write log migration batch: %w", err)
+			}
+			bat = nil
+			return nil
+		}
+	)
+
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = writeLogKeyFmt.Encode()
+	it := tx.NewIterator(opts)
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+
+		// Write log keys are ordered by version so all write logs for the
+		// same timestamp can be rewritten in a single batch.
+		if bat == nil || item.Version() != batTs {
+			if err := flushFn(); err != nil {
+				return err
+			}
+			batTs = item.Version()
+			bat = d.db.NewWriteBatchAt(batTs)
+		}
+
+		err := item.Value(func(data []byte) error {
+			if !isLegacyWriteLogValue(data) {
+				return nil
+			}
+
+			value, err := encodeWriteLogValue(d.writeLogFormat, data)
+			if err != nil {
+				return err
+			}
+			count++
+			return bat.Set(item.KeyCopy(nil), value)
+		})
+		if err != nil {
+			bat.Cancel()
+			return fmt.Errorf("mkvs/badger: failed to migrate write log: %w", err)
+		}
+	}
+	if err := flushFn(); err != nil {
+		return err
+	}
+
+	d.logger.Info("migrated write logs",
+		"count", count,
+	)
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/writelog"
+)
+
+func commitTestVersion(t *testing.T, ndb api.NodeDB, oldRoot node.Root, version uint64) node.Root {
+	require := require.New(t)
+	ctx := context.Background()
+
+	tree := mkvs.NewWithRoot(nil, ndb, oldRoot)
+	defer tree.Close()
+	for i := 0; i < 10; i++ {
+		err := tree.Insert(ctx, []byte(fmt.Sprintf("key %d %d", version, i)), []byte(fmt.Sprintf("value %d %d", version, i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, version)
+	require.NoError(err, "Commit")
+	err = ndb.Finalize(ctx, version, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize")
+
+	return node.Root{Namespace: testNs, Version: version, Hash: rootHash}
+}
+
+func requireTestWriteLog(t *testing.T, ndb api.NodeDB, oldRoot, root node.Root) {
+	require := require.New(t)
+
+	it, err := ndb.GetWriteLog(context.Background(), oldRoot, root)
+	require.NoError(err, "GetWriteLog")
+
+	var log writelog.WriteLog
+	for {
+		more, err := it.Next()
+		require.NoError(err, "it.Next()")
+		if !more {
+			break
+		}
+		entry, err := it.Value()
+		require.NoError(err, "it.Value()")
+		log = append(log, entry)
+	}
+
+	require.Len(log, 10, "write log should have all entries")
+	for i := 0; i < 10; i++ {
+		require.Contains(log, writelog.LogEntry{
+			Key:   []byte(fmt.Sprintf("key %d %d", root.Version, i)),
+			Value: []byte(fmt.Sprintf("value %d %d", root.Version, i)),
+		})
+	}
+}
+
+func TestWriteLogCompression(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	cfg := &api.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+	}
+
+	root := node.Root{Namespace: testNs}
+	root.Hash.Empty()
+
+	// Write logs persisted using different compression algorithms should
+	// remain readable regardless of the configured algorithm.
+	compressions := []string{
+		api.WriteLogCompressionNone,
+		api.WriteLogCompressionSnappy,
+		api.WriteLogCompressionZstd,
+	}
+	var roots []node.Root
+	for i, compression := range compressions {
+		cfg.WriteLogCompression = compression
+		ndb, err := New(cfg)
+		require.NoError(err, "New")
+
+		oldRoot := root
+		root = commitTestVersion(t, ndb, oldRoot, uint64(i))
+		roots = append(roots, oldRoot, root)
+
+		for j := 0; j < len(roots); j += 2 {
+			requireTestWriteLog(t, ndb, roots[j], roots[j+1])
+		}
+		ndb.Close()
+	}
+
+	cfg.WriteLogCompression = "invalid"
+	_, err = New(cfg)
+	require.Error(err, "New should fail with an unsupported write log compression")
+}
+
+func TestWriteLogMigration(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	cfg := &api.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+	}
+	ndb, err := New(cfg)
+	require.NoError(err, "New")
+
+	emptyRoot := node.Root{Namespace: testNs}
+	emptyRoot.Hash.Empty()
+	roots := []node.Root{emptyRoot}
+	for i := 0; i < 3; i++ {
+		roots = append(roots, commitTestVersion(t, ndb, roots[i], uint64(i)))
+	}
+
+	// Rewrite the database in the legacy format.
+	bdb := ndb.(*badgerNodeDB)
+	for i, root := range roots[1:] {
+		tx := bdb.db.NewTransactionAt(versionToTs(root.Version), true)
+		key := writeLogKeyFmt.Encode(root.Version, &root.Hash, &roots[i].Hash)
+		item, err := tx.Get(key)
+		require.NoError(err, "Get")
+		var log api.HashedDBWriteLog
+		err = item.Value(func(data []byte) error {
+			return decodeWriteLogValue(data, &log)
+		})
+		require.NoError(err, "decodeWriteLogValue")
+		require.NoError(tx.Set(key, cbor.Marshal(log)), "Set")
+		require.NoError(tx.CommitAt(versionToTs(root.Version), nil), "CommitAt")
+	}
+	bdb.meta.value.Version = dbVersionLegacyWriteLogs
+	tx := bdb.db.NewTransactionAt(tsMetadata, true)
+	require.NoError(bdb.meta.save(tx), "save")
+	require.NoError(tx.CommitAt(tsMetadata, nil), "CommitAt")
+	tx.Discard()
+	ndb.Close()
+
+	// Legacy databases must not be opened in read-only mode.
+	cfg.ReadOnly = true
+	_, err = New(cfg)
+	require.Error(err, "New should fail for a legacy database in read-only mode")
+
+	// Opening the database should migrate all write logs.
+	cfg.ReadOnly = false
+	cfg.WriteLogCompression = api.WriteLogCompressionZstd
+	ndb, err = New(cfg)
+	require.NoError(err, "New")
+	require.EqualValues(dbVersion, ndb.(*badgerNodeDB).meta.value.Version, "database version should be updated")
+	for i, root := range roots[1:] {
+		requireTestWriteLog(t, ndb, roots[i], root)
+
+		tx = ndb.(*badgerNodeDB).db.NewTransactionAt(versionToTs(root.Version), false)
+		item, err := tx.Get(writeLogKeyFmt.Encode(root.Version, &root.Hash, &roots[i].Hash))
+		require.NoError(err, "Get")
+		err = item.Value(func(data []byte) error {
+			require.EqualValues(writeLogFormatZstd, data[0], "write log should be migrated to the configured format")
+			return nil
+		})
+		require.NoError(err, "Value")
+		tx.Discard()
+	}
+	ndb.Close()
+
+	// Reopening the migrated database should not change anything.
+	ndb, err = New(cfg)
+	require.NoError(err, "New")
+	defer ndb.Close()
+	for i, root := range roots[1:] {
+		requireTestWriteLog(t, ndb, roots[i], root)
+	}
+}
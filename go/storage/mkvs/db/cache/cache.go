@@ -0,0 +1,165 @@
+// Package cache implements an in-memory cache of decoded node database nodes
+// with pluggable eviction policies.
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// PolicyNone disables the node cache.
+	PolicyNone = "none"
+	// PolicyLRU evicts the least recently used nodes first.
+	PolicyLRU = "lru"
+	// Policy2Q uses the 2Q algorithm, which protects frequently used nodes
+	// from being evicted by scans over many nodes that are only used once.
+	Policy2Q = "2q"
+	// PolicyClock uses the CLOCK algorithm, which approximates LRU without
+	// needing to update any ordering on lookups.
+	PolicyClock = "clock"
+)
+
+var (
+	cacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_node_cache_hit_count",
+			Help: "Number of node cache hits.",
+		},
+		[]string{"policy"},
+	)
+	cacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_node_cache_miss_count",
+			Help: "Number of node cache misses.",
+		},
+		[]string{"policy"},
+	)
+	cacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_node_cache_eviction_count",
+			Help: "Number of nodes evicted from the node cache.",
+		},
+		[]string{"policy"},
+	)
+
+	cacheCollectors = []prometheus.Collector{
+		cacheHits,
+		cacheMisses,
+		cacheEvictions,
+	}
+
+	metricsOnce sync.Once
+)
+
+// policy is a cache eviction policy.
+//
+// Policies are not safe for concurrent use, the Cache takes care of locking.
+type policy interface {
+	// get returns the cached node and marks it as used.
+	get(h hash.Hash) (node.Node, bool)
+	// put inserts or updates the node and returns the number of nodes that
+	// have been evicted to make room for it.
+	put(h hash.Hash, n node.Node) int
+	// remove removes the node from the cache.
+	remove(h hash.Hash)
+	// len returns the number of cached nodes.
+	len() int
+}
+
+// Cache is an in-memory cache of decoded nodes, keyed by node hash.
+//
+// As nodes are content-addressed, cached nodes never become stale, but they
+// should be removed when they are deleted from the node database.
+type Cache struct {
+	sync.Mutex
+
+	policy policy
+	labels prometheus.Labels
+}
+
+// Get returns a copy of the cached node with the given hash.
+func (c *Cache) Get(h hash.Hash) (node.Node, bool) {
+	c.Lock()
+	n, ok := c.policy.get(h)
+	c.Unlock()
+
+	if !ok {
+		cacheMisses.With(c.labels).Inc()
+		return nil, false
+	}
+	cacheHits.With(c.labels).Inc()
+
+	// Return a copy so that the caller may freely modify the node.
+	return n.Extract(), true
+}
+
+// Put inserts a copy of a clean node into the cache.
+func (c *Cache) Put(n node.Node) {
+	n = n.Extract()
+
+	c.Lock()
+	evicted := c.policy.put(n.GetHash(), n)
+	c.Unlock()
+
+	if evicted > 0 {
+		cacheEvictions.With(c.labels).Add(float64(evicted))
+	}
+}
+
+// Remove removes the node with the given hash from the cache.
+func (c *Cache) Remove(h hash.Hash) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.policy.remove(h)
+}
+
+// Len returns the number of cached nodes.
+func (c *Cache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.policy.len()
+}
+
+// New creates a new node cache using the given eviction policy and holding at
+// most capacity nodes.
+//
+// In case the policy is PolicyNone (or empty) or the capacity is zero, no
+// cache is created and nil is returned.
+func New(policyName string, capacity uint64) (*Cache, error) {
+	policyName = strings.ToLower(policyName)
+
+	var p policy
+	switch policyName {
+	case "", PolicyNone:
+		return nil, nil
+	case PolicyLRU:
+		p = newLRU(capacity)
+	case Policy2Q:
+		p = new2Q(capacity)
+	case PolicyClock:
+		p = newClock(capacity)
+	default:
+		return nil, fmt.Errorf("mkvs/cache: unsupported eviction policy: '%s'", policyName)
+	}
+	if capacity == 0 {
+		return nil, nil
+	}
+
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(cacheCollectors...)
+	})
+
+	return &Cache{
+		policy: p,
+		labels: prometheus.Labels{"policy": policyName},
+	}, nil
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+func makeTestNode(i int) node.Node {
+	n := &node.LeafNode{
+		Clean: true,
+		Key:   []byte(fmt.Sprintf("key %d", i)),
+		Value: []byte(fmt.Sprintf("value %d", i)),
+	}
+	n.UpdateHash()
+	return n
+}
+
+func TestNew(t *testing.T) {
+	require := require.New(t)
+
+	c, err := New(PolicyNone, 10)
+	require.NoError(err, "New")
+	require.Nil(c, "none policy should disable the cache")
+
+	c, err = New(PolicyLRU, 0)
+	require.NoError(err, "New")
+	require.Nil(c, "zero capacity should disable the cache")
+
+	_, err = New("random", 10)
+	require.Error(err, "New should fail with an unsupported policy")
+}
+
+func TestCache(t *testing.T) {
+	for _, policy := range []string{PolicyLRU, Policy2Q, PolicyClock} {
+		t.Run(policy, func(t *testing.T) {
+			testCache(t, policy)
+		})
+	}
+}
+
+func testCache(t *testing.T, policy string) {
+	require := require.New(t)
+
+	const capacity = 10
+	c, err := New(policy, capacity)
+	require.NoError(err, "New")
+
+	var nodes []node.Node
+	for i := 0; i < 2*capacity; i++ {
+		nodes = append(nodes, makeTestNode(i))
+	}
+
+	n0 := nodes[0]
+	_, ok := c.Get(n0.GetHash())
+	require.False(ok, "Get should miss on an empty cache")
+
+	c.Put(n0)
+	cached, ok := c.Get(n0.GetHash())
+	require.True(ok, "Get should hit after Put")
+	require.True(n0.Equal(cached), "cached node should be equal")
+	require.NotSame(n0, cached, "Get should return a copy")
+
+	c.Remove(n0.GetHash())
+	_, ok = c.Get(n0.GetHash())
+	require.False(ok, "Get should miss after Remove")
+	require.Equal(0, c.Len())
+
+	for _, n := range nodes {
+		c.Put(n)
+		require.LessOrEqual(c.Len(), capacity, "cache should not exceed capacity")
+	}
+	require.Equal(capacity, c.Len(), "cache should be full")
+
+	var hits int
+	for _, n := range nodes {
+		if _, ok = c.Get(n.GetHash()); ok {
+			hits++
+		}
+	}
+	require.Equal(capacity, hits, "exactly capacity nodes should be cached")
+}
+
+func TestLRUEviction(t *testing.T) {
+	require := require.New(t)
+
+	c, err := New(PolicyLRU, 2)
+	require.NoError(err, "New")
+
+	n0, n1, n2 := makeTestNode(0), makeTestNode(1), makeTestNode(2)
+	c.Put(n0)
+	c.Put(n1)
+	_, ok := c.Get(n0.GetHash())
+	require.True(ok)
+	c.Put(n2)
+
+	_, ok = c.Get(n0.GetHash())
+	require.True(ok, "recently used node should be retained")
+	_, ok = c.Get(n1.GetHash())
+	require.False(ok, "least recently used node should be evicted")
+}
+
+func Test2QScanResistance(t *testing.T) {
+	require := require.New(t)
+
+	const capacity = 8
+	c, err := New(Policy2Q, capacity)
+	require.NoError(err, "New")
+
+	// Insert a node, get it evicted from the recent queue and insert it again
+	// so that it is promoted to the frequent queue.
+	hot := makeTestNode(0)
+	c.Put(hot)
+	for i := 1; i <= capacity; i++ {
+		c.Put(makeTestNode(i))
+	}
+	_, ok := c.Get(hot.GetHash())
+	require.False(ok, "node should be evicted from the recent queue")
+	c.Put(hot)
+
+	// A scan over many nodes that are only used once should not evict it.
+	for i := 100; i < 100+4*capacity; i++ {
+		c.Put(makeTestNode(i))
+	}
+	_, ok = c.Get(hot.GetHash())
+	require.True(ok, "frequently used node should survive a scan")
+}
+
+func TestClockSecondChance(t *testing.T) {
+	require := require.New(t)
+
+	c, err := New(PolicyClock, 2)
+	require.NoError(err, "New")
+
+	n0, n1, n2 := makeTestNode(0), makeTestNode(1), makeTestNode(2)
+	c.Put(n0)
+	c.Put(n1)
+	_, ok := c.Get(n0.GetHash())
+	require.True(ok)
+	c.Put(n2)
+
+	_, ok = c.Get(n0.GetHash())
+	require.True(ok, "referenced node should get a second chance")
+	_, ok = c.Get(n1.GetHash())
+	require.False(ok, "unreferenced node should be evicted")
+}
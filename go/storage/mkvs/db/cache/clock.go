@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+type clockSlot struct {
+	hash       hash.Hash
+	node       node.Node
+	referenced bool
+}
+
+// clockPolicy is the CLOCK eviction policy.
+//
+// Cached nodes are kept in a circular buffer of slots. Each lookup only sets
+// the slot's referenced bit, while eviction advances the clock hand over the
+// slots, clearing the referenced bits until it finds an unreferenced slot.
+type clockPolicy struct {
+	capacity int
+
+	slots   []*clockSlot
+	hand    int
+	entries map[hash.Hash]int
+}
+
+func (p *clockPolicy) get(h hash.Hash) (node.Node, bool) {
+	idx, ok := p.entries[h]
+	if !ok {
+		return nil, false
+	}
+
+	slot := p.slots[idx]
+	slot.referenced = true
+	return slot.node, true
+}
+
+func (p *clockPolicy) put(h hash.Hash, n node.Node) int {
+	if idx, ok := p.entries[h]; ok {
+		slot := p.slots[idx]
+		slot.node = n
+		slot.referenced = true
+		return 0
+	}
+
+	if len(p.slots) < p.capacity {
+		p.entries[h] = len(p.slots)
+		p.slots = append(p.slots, &clockSlot{hash: h, node: n})
+		return 0
+	}
+
+	var evicted int
+	for {
+		slot := p.slots[p.hand]
+		switch {
+		case slot.node != nil && slot.referenced:
+			// Give the node a second chance.
+			slot.referenced = false
+		default:
+			if slot.node != nil {
+				delete(p.entries, slot.hash)
+				evicted++
+			}
+
+			slot.hash = h
+			slot.node = n
+			slot.referenced = false
+			p.entries[h] = p.hand
+			p.hand = (p.hand + 1) % len(p.slots)
+			return evicted
+		}
+		p.hand = (p.hand + 1) % len(p.slots)
+	}
+}
+
+func (p *clockPolicy) remove(h hash.Hash) {
+	idx, ok := p.entries[h]
+	if !ok {
+		return
+	}
+
+	// Leave an empty slot which will be reused by the clock hand.
+	p.slots[idx] = &clockSlot{}
+	delete(p.entries, h)
+}
+
+func (p *clockPolicy) len() int {
+	return len(p.entries)
+}
+
+func newClock(capacity uint64) policy {
+	return &clockPolicy{
+		capacity: int(capacity),
+		entries:  make(map[hash.Hash]int),
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"container/list"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+type lruEntry struct {
+	hash hash.Hash
+	node node.Node
+}
+
+// lruPolicy is the least-recently-used eviction policy.
+type lruPolicy struct {
+	capacity int
+
+	lru     *list.List
+	entries map[hash.Hash]*list.Element
+}
+
+func (p *lruPolicy) get(h hash.Hash) (node.Node, bool) {
+	elem, ok := p.entries[h]
+	if !ok {
+		return nil, false
+	}
+	p.lru.MoveToFront(elem)
+	return elem.Value.(*lruEntry).node, true
+}
+
+func (p *lruPolicy) put(h hash.Hash, n node.Node) int {
+	if elem, ok := p.entries[h]; ok {
+		elem.Value.(*lruEntry).node = n
+		p.lru.MoveToFront(elem)
+		return 0
+	}
+
+	var evicted int
+	for p.lru.Len() >= p.capacity {
+		elem := p.lru.Back()
+		p.lru.Remove(elem)
+		delete(p.entries, elem.Value.(*lruEntry).hash)
+		evicted++
+	}
+
+	p.entries[h] = p.lru.PushFront(&lruEntry{hash: h, node: n})
+	return evicted
+}
+
+func (p *lruPolicy) remove(h hash.Hash) {
+	if elem, ok := p.entries[h]; ok {
+		p.lru.Remove(elem)
+		delete(p.entries, h)
+	}
+}
+
+func (p *lruPolicy) len() int {
+	return p.lru.Len()
+}
+
+func newLRU(capacity uint64) policy {
+	return &lruPolicy{
+		capacity: int(capacity),
+		lru:      list.New(),
+		entries:  make(map[hash.Hash]*list.Element),
+	}
+}
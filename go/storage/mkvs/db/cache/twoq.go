@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"container/list"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// twoQRecentRatio is the fraction of the capacity used for nodes that
+	// have only been used once.
+	twoQRecentRatio = 0.25
+	// twoQGhostRatio is the fraction of the capacity used for remembering
+	// hashes of nodes recently evicted from the recent queue.
+	twoQGhostRatio = 0.5
+)
+
+// twoQPolicy is the 2Q eviction policy (the "full version" from the paper by
+// Johnson and Shasha).
+//
+// New nodes first enter the recent FIFO queue. When evicted from there, only
+// their hashes are remembered in the ghost queue. Nodes that are inserted
+// again while their hash is still in the ghost queue are considered frequently
+// used and enter the frequent LRU queue.
+type twoQPolicy struct {
+	capacity    int
+	recentSize  int
+	ghostSize   int
+	recent      *list.List
+	frequent    *list.List
+	ghost       *list.List
+	entries     map[hash.Hash]*list.Element
+	ghostHashes map[hash.Hash]*list.Element
+}
+
+type twoQEntry struct {
+	hash     hash.Hash
+	node     node.Node
+	frequent bool
+}
+
+func (p *twoQPolicy) get(h hash.Hash) (node.Node, bool) {
+	elem, ok := p.entries[h]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*twoQEntry)
+	if entry.frequent {
+		p.frequent.MoveToFront(elem)
+	}
+	return entry.node, true
+}
+
+func (p *twoQPolicy) put(h hash.Hash, n node.Node) int {
+	if elem, ok := p.entries[h]; ok {
+		entry := elem.Value.(*twoQEntry)
+		entry.node = n
+		if entry.frequent {
+			p.frequent.MoveToFront(elem)
+		}
+		return 0
+	}
+
+	evicted := p.reclaim()
+
+	if elem, ok := p.ghostHashes[h]; ok {
+		// Node has been seen recently, consider it frequently used.
+		p.ghost.Remove(elem)
+		delete(p.ghostHashes, h)
+
+		p.entries[h] = p.frequent.PushFront(&twoQEntry{hash: h, node: n, frequent: true})
+		return evicted
+	}
+
+	p.entries[h] = p.recent.PushFront(&twoQEntry{hash: h, node: n})
+	return evicted
+}
+
+// reclaim evicts nodes until there is room for a new node.
+func (p *twoQPolicy) reclaim() int {
+	var evicted int
+	for p.recent.Len()+p.frequent.Len() >= p.capacity {
+		if p.recent.Len() > 0 && (p.recent.Len() > p.recentSize || p.frequent.Len() == 0) {
+			// Evict from the recent queue and remember the hash.
+			elem := p.recent.Back()
+			entry := elem.Value.(*twoQEntry)
+			p.recent.Remove(elem)
+			delete(p.entries, entry.hash)
+
+			p.ghostHashes[entry.hash] = p.ghost.PushFront(entry.hash)
+			for p.ghost.Len() > p.ghostSize {
+				ghostElem := p.ghost.Back()
+				p.ghost.Remove(ghostElem)
+				delete(p.ghostHashes, ghostElem.Value.(hash.Hash))
+			}
+		} else {
+			// Evict from the frequent queue.
+			elem := p.frequent.Back()
+			p.frequent.Remove(elem)
+			delete(p.entries, elem.Value.(*twoQEntry).hash)
+		}
+		evicted++
+	}
+	return evicted
+}
+
+func (p *twoQPolicy) remove(h hash.Hash) {
+	if elem, ok := p.entries[h]; ok {
+		if elem.Value.(*twoQEntry).frequent {
+			p.frequent.Remove(elem)
+		} else {
+			p.recent.Remove(elem)
+		}
+		delete(p.entries, h)
+	}
+	if elem, ok := p.ghostHashes[h]; ok {
+		p.ghost.Remove(elem)
+		delete(p.ghostHashes, h)
+	}
+}
+
+func (p *twoQPolicy) len() int {
+	return p.recent.Len() + p.frequent.Len()
+}
+
+func new2Q(capacity uint64) policy {
+	recentSize := int(float64(capacity) * twoQRecentRatio)
+	if recentSize == 0 {
+		recentSize = 1
+	}
+
+	return &twoQPolicy{
+		capacity:    int(capacity),
+		recentSize:  recentSize,
+		ghostSize:   int(float64(capacity) * twoQGhostRatio),
+		recent:      list.New(),
+		frequent:    list.New(),
+		ghost:       list.New(),
+		entries:     make(map[hash.Hash]*list.Element),
+		ghostHashes: make(map[hash.Hash]*list.Element),
+	}
+}
@@ -2,13 +2,18 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/writelog"
 )
@@ -85,3 +90,81 @@ func TestHashedWriteLog(t *testing.T) {
 	}
 	require.Equal(t, i, len(wl))
 }
+
+func TestCheckpoint(t *testing.T) {
+	require := require.New(t)
+
+	ns := common.NewTestNamespaceFromSeed([]byte("oasis mkvs db checkpoint test ns"), 0)
+	newNodeDB := func() api.NodeDB {
+		ndb, err := badger.New(&api.Config{
+			Namespace:           ns,
+			MemoryOnly:          true,
+			CheckpointChunkSize: 16 * 1024,
+		})
+		require.NoError(err, "New")
+		return ndb
+	}
+
+	ctx := context.Background()
+	ndb := newNodeDB()
+	defer ndb.Close()
+
+	tree := mkvs.New(nil, ndb)
+	for i := 0; i < 1000; i++ {
+		err := tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 1)
+	require.NoError(err, "Commit")
+	tree.Close()
+	root := node.Root{Namespace: ns, Version: 1, Hash: rootHash}
+
+	// Non-finalized versions cannot be checkpointed.
+	_, err = ndb.Checkpoint(ctx, 1)
+	require.Error(err, "Checkpoint should fail for a non-finalized version")
+	require.True(errors.Is(err, api.ErrNotFinalized))
+
+	err = ndb.Finalize(ctx, 1, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize")
+
+	it, err := ndb.Checkpoint(ctx, 1)
+	require.NoError(err, "Checkpoint")
+	var chunks []*api.Chunk
+	for {
+		more, err := it.Next()
+		require.NoError(err, "it.Next()")
+		if !more {
+			break
+		}
+		chunk, err := it.Value()
+		require.NoError(err, "it.Value()")
+		require.Equal(root, chunk.Root, "chunk root should be correct")
+		require.EqualValues(len(chunks), chunk.Index, "chunk index should be correct")
+		chunks = append(chunks, chunk)
+	}
+	require.True(len(chunks) > 1, "checkpoint should be split into multiple chunks")
+
+	// Corrupted chunks should be rejected.
+	ndb2 := newNodeDB()
+	defer ndb2.Close()
+
+	corrupted := *chunks[0]
+	corrupted.Proof.Entries = append([][]byte{}, corrupted.Proof.Entries[1:]...)
+	err = ndb2.RestoreCheckpoint(ctx, api.NewStaticChunkIterator([]*api.Chunk{&corrupted}))
+	require.Error(err, "RestoreCheckpoint should fail with a corrupted chunk")
+	require.True(errors.Is(err, api.ErrInvalidChunk))
+
+	// Restoring all chunks should result in an identical tree.
+	err = ndb2.RestoreCheckpoint(ctx, api.NewStaticChunkIterator(chunks))
+	require.NoError(err, "RestoreCheckpoint")
+	err = ndb2.Finalize(ctx, 1, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize")
+
+	restored := mkvs.NewWithRoot(nil, ndb2, root)
+	defer restored.Close()
+	for i := 0; i < 1000; i++ {
+		value, err := restored.Get(ctx, []byte(strconv.Itoa(i)))
+		require.NoError(err, "Get")
+		require.Equal([]byte(strconv.Itoa(i)), value, "restored value should be correct")
+	}
+}
@@ -0,0 +1,152 @@
+// Package flat provides a canonical flat file export format for MKVS trees.
+//
+// The export consists of a CBOR-encoded header containing the format version
+// and the root that was exported, followed by a sequence of CBOR-encoded
+// key/value pairs in ascending key order. As the order and encoding of all
+// entries is fully determined by the tree contents, two exports of the same
+// root are always byte-for-byte identical.
+package flat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// FormatVersion is the version of the flat export format.
+	FormatVersion = 1
+
+	exportPrefetch = 10_000
+)
+
+var (
+	// ErrUnsupportedVersion is the error returned when the export uses an
+	// unsupported format version.
+	ErrUnsupportedVersion = errors.New("flat: unsupported export format version")
+
+	// ErrNotCanonical is the error returned when export entries are not in
+	// strictly ascending key order.
+	ErrNotCanonical = errors.New("flat: export entries are not in canonical order")
+
+	// ErrRootMismatch is the error returned when the imported tree does not
+	// hash to the root embedded in the export.
+	ErrRootMismatch = errors.New("flat: imported root mismatch")
+)
+
+// Header is the flat export header.
+type Header struct {
+	// Version is the export format version.
+	Version uint16 `json:"version"`
+
+	// Root is the root that was exported.
+	Root node.Root `json:"root"`
+}
+
+// Entry is a single key/value pair in the flat export.
+//
+// It is encoded as a two-element CBOR array of byte strings so that it can
+// easily be consumed by external tools.
+type Entry struct {
+	_ struct{} `cbor:",toarray"` //nolint
+
+	Key   []byte
+	Value []byte
+}
+
+// Export writes a flat export of all key/value pairs under the given root to
+// the given writer.
+//
+// The passed tree must be rooted at root.
+func Export(ctx context.Context, tree mkvs.ImmutableKeyValueTree, root node.Root, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := cbor.NewEncoder(bw)
+
+	if err := enc.Encode(&Header{Version: FormatVersion, Root: root}); err != nil {
+		return fmt.Errorf("flat: failed to encode header: %w", err)
+	}
+
+	it := tree.NewIterator(ctx, mkvs.IteratorPrefetch(exportPrefetch))
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		// Check if context got cancelled while iterating to abort early.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := enc.Encode(&Entry{Key: it.Key(), Value: it.Value()}); err != nil {
+			return fmt.Errorf("flat: failed to encode entry: %w", err)
+		}
+	}
+	if it.Err() != nil {
+		return fmt.Errorf("flat: failed to iterate: %w", it.Err())
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("flat: failed to flush export: %w", err)
+	}
+	return nil
+}
+
+// Import reads a flat export from the given reader and imports it into the
+// given node database, verifying that the resulting tree matches the root
+// embedded in the export. On success the imported root is returned.
+//
+// The caller is responsible for finalizing the root version in the node
+// database afterwards.
+func Import(ctx context.Context, ndb db.NodeDB, r io.Reader) (*node.Root, error) {
+	dec := cbor.NewDecoder(bufio.NewReader(r))
+
+	var hdr Header
+	if err := dec.Decode(&hdr); err != nil {
+		return nil, fmt.Errorf("flat: failed to decode header: %w", err)
+	}
+	if hdr.Version != FormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, hdr.Version)
+	}
+
+	tree := mkvs.New(nil, ndb, mkvs.WithoutWriteLog())
+	defer tree.Close()
+
+	var lastKey []byte
+	for first := true; ; first = false {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var entry Entry
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("flat: failed to decode entry: %w", err)
+		}
+
+		if !first && bytes.Compare(entry.Key, lastKey) <= 0 {
+			return nil, fmt.Errorf("%w: key %X follows %X", ErrNotCanonical, entry.Key, lastKey)
+		}
+		lastKey = entry.Key
+
+		if err := tree.Insert(ctx, entry.Key, entry.Value); err != nil {
+			return nil, fmt.Errorf("flat: failed to insert entry: %w", err)
+		}
+	}
+
+	if _, err := tree.CommitKnown(ctx, hdr.Root); err != nil {
+		if errors.Is(err, mkvs.ErrKnownRootMismatch) {
+			return nil, fmt.Errorf("%w: expected %s", ErrRootMismatch, hdr.Root.Hash)
+		}
+		return nil, fmt.Errorf("flat: failed to commit imported tree: %w", err)
+	}
+
+	return &hdr.Root, nil
+}
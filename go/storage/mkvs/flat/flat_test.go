@@ -0,0 +1,125 @@
+package flat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs"
+	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+var testNs = common.NewTestNamespaceFromSeed([]byte("oasis mkvs flat export test ns"), 0)
+
+func newTestNodeDB(t *testing.T) db.NodeDB {
+	ndb, err := badgerDb.New(&db.Config{
+		MemoryOnly:   true,
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(t, err, "New")
+	return ndb
+}
+
+func TestExportImport(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ndb := newTestNodeDB(t)
+	defer ndb.Close()
+
+	tree := mkvs.New(nil, ndb)
+	for i := 0; i < 1000; i++ {
+		err := tree.Insert(ctx, []byte(fmt.Sprintf("key %d", i)), []byte(fmt.Sprintf("value %d", i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 1)
+	require.NoError(err, "Commit")
+	tree.Close()
+
+	root := node.Root{Namespace: testNs, Version: 1, Hash: rootHash}
+	tree = mkvs.NewWithRoot(nil, ndb, root)
+	defer tree.Close()
+
+	var buf1, buf2 bytes.Buffer
+	err = Export(ctx, tree, root, &buf1)
+	require.NoError(err, "Export")
+	err = Export(ctx, tree, root, &buf2)
+	require.NoError(err, "Export")
+	require.Equal(buf1.Bytes(), buf2.Bytes(), "exports should be deterministic")
+
+	// Import into a fresh node database.
+	ndb2 := newTestNodeDB(t)
+	defer ndb2.Close()
+
+	imported, err := Import(ctx, ndb2, bytes.NewReader(buf1.Bytes()))
+	require.NoError(err, "Import")
+	require.EqualValues(root, *imported, "imported root should match")
+	err = ndb2.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+	require.NoError(err, "Finalize")
+
+	tree2 := mkvs.NewWithRoot(nil, ndb2, root)
+	defer tree2.Close()
+	value, err := tree2.Get(ctx, []byte("key 42"))
+	require.NoError(err, "Get")
+	require.EqualValues([]byte("value 42"), value)
+
+	// Re-exporting the imported tree should yield an identical export.
+	var buf3 bytes.Buffer
+	err = Export(ctx, tree2, root, &buf3)
+	require.NoError(err, "Export")
+	require.Equal(buf1.Bytes(), buf3.Bytes(), "re-export should be identical")
+}
+
+func TestImportInvalid(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	encode := func(hdr *Header, entries ...*Entry) []byte {
+		var buf bytes.Buffer
+		enc := cbor.NewEncoder(&buf)
+		require.NoError(enc.Encode(hdr))
+		for _, entry := range entries {
+			require.NoError(enc.Encode(entry))
+		}
+		return buf.Bytes()
+	}
+
+	var emptyHash hash.Hash
+	emptyHash.Empty()
+	root := node.Root{Namespace: testNs, Version: 1, Hash: emptyHash}
+
+	ndb := newTestNodeDB(t)
+	defer ndb.Close()
+
+	// Unsupported version.
+	_, err := Import(ctx, ndb, bytes.NewReader(encode(&Header{Version: 42, Root: root})))
+	require.Error(err, "Import should fail with unsupported version")
+	require.True(errors.Is(err, ErrUnsupportedVersion))
+
+	// Non-canonical entry order.
+	_, err = Import(ctx, ndb, bytes.NewReader(encode(
+		&Header{Version: FormatVersion, Root: root},
+		&Entry{Key: []byte("b"), Value: []byte("1")},
+		&Entry{Key: []byte("a"), Value: []byte("2")},
+	)))
+	require.Error(err, "Import should fail with non-canonical order")
+	require.True(errors.Is(err, ErrNotCanonical))
+
+	// Root mismatch.
+	_, err = Import(ctx, ndb, bytes.NewReader(encode(
+		&Header{Version: FormatVersion, Root: root},
+		&Entry{Key: []byte("a"), Value: []byte("1")},
+	)))
+	require.Error(err, "Import should fail with root mismatch")
+	require.True(errors.Is(err, ErrRootMismatch))
+}
@@ -46,7 +46,9 @@ func (t *tree) Insert(ctx context.Context, key []byte, value []byte) error {
 	}
 
 	t.cache.setPendingRoot(result.newRoot)
-	return nil
+
+	// Spill dirty nodes to the node database if over the memory budget.
+	return t.maybeSpill(ctx)
 }
 
 type insertResult struct {
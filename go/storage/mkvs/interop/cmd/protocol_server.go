@@ -86,6 +86,9 @@ func doProtoServer(cmd *cobra.Command, args []string) {
 		return
 	}
 	storage.RegisterService(grpcSrv.Server(), backend)
+	// Also allow the clients to create checkpoints, so that they can be
+	// fetched via the storage service.
+	registerInteropService(grpcSrv.Server(), backend.(storage.LocalBackend))
 
 	// Start the gRPC server.
 	if err := grpcSrv.Start(); err != nil {
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	storage "github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+var (
+	// interopServiceName is the gRPC service name of the interoperability
+	// test helper service.
+	interopServiceName = cmnGrpc.NewServiceName("StorageInterop")
+
+	// methodCreateCheckpoint is the CreateCheckpoint method.
+	methodCreateCheckpoint = interopServiceName.NewMethod("CreateCheckpoint", CreateCheckpointRequest{})
+
+	// interopServiceDesc is the gRPC service descriptor.
+	interopServiceDesc = grpc.ServiceDesc{
+		ServiceName: string(interopServiceName),
+		HandlerType: (*storage.LocalBackend)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: methodCreateCheckpoint.ShortName(),
+				Handler:    handlerCreateCheckpoint,
+			},
+		},
+		Streams: []grpc.StreamDesc{},
+	}
+)
+
+// CreateCheckpointRequest is a CreateCheckpoint request.
+type CreateCheckpointRequest struct {
+	Root      node.Root `json:"root"`
+	ChunkSize uint64    `json:"chunk_size"`
+}
+
+func handlerCreateCheckpoint( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req CreateCheckpointRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return createCheckpoint(ctx, srv.(storage.LocalBackend), &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCreateCheckpoint.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return createCheckpoint(ctx, srv.(storage.LocalBackend), req.(*CreateCheckpointRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func createCheckpoint(ctx context.Context, backend storage.LocalBackend, req *CreateCheckpointRequest) (*checkpoint.Metadata, error) {
+	return backend.Checkpointer().CreateCheckpoint(ctx, req.Root, req.ChunkSize)
+}
+
+// registerInteropService registers the interoperability test helper service
+// with the given gRPC server.
+func registerInteropService(server *grpc.Server, backend storage.LocalBackend) {
+	server.RegisterService(&interopServiceDesc, backend)
+}
@@ -26,10 +26,19 @@ func (t *tree) SyncIterate(ctx context.Context, request *syncer.IterateRequest)
 		return nil, syncer.ErrDirtyRoot
 	}
 
+	return doSyncIterate(ctx, t, request, &t.proofLimits)
+}
+
+func doSyncIterate(
+	ctx context.Context,
+	src nodeSource,
+	request *syncer.IterateRequest,
+	limits *syncer.ProofLimits,
+) (*syncer.ProofResponse, error) {
 	// Create an iterator which generates proofs. Always anchor the proof at the
 	// root as an iterator may encompass many subtrees. Make sure to propagate
 	// prefetching to any upstream remote syncers.
-	it := t.NewIterator(ctx,
+	it := newTreeIterator(ctx, src,
 		WithProof(request.Tree.Root.Hash),
 		IteratorPrefetch(request.Prefetch),
 	)
@@ -39,7 +48,14 @@ func (t *tree) SyncIterate(ctx context.Context, request *syncer.IterateRequest)
 	if it.Err() != nil {
 		return nil, it.Err()
 	}
+	// Stop early in case the proof is getting too large, the client will
+	// resume from the last item included in the proof.
+	var truncated bool
 	for i := 0; it.Valid() && i < int(request.Prefetch); i++ {
+		if limits.Exceeded(it.GetProofBuilder()) {
+			truncated = true
+			break
+		}
 		it.Next()
 	}
 	if it.Err() != nil {
@@ -53,7 +69,8 @@ func (t *tree) SyncIterate(ctx context.Context, request *syncer.IterateRequest)
 	}
 
 	return &syncer.ProofResponse{
-		Proof: *proof,
+		Proof:     *proof,
+		Truncated: truncated,
 	}, nil
 }
 
@@ -124,7 +141,7 @@ type pathAtom struct {
 
 type treeIterator struct {
 	ctx      context.Context
-	tree     *tree
+	src      nodeSource
 	prefetch uint16
 	err      error
 	pos      []pathAtom
@@ -154,10 +171,10 @@ func WithProof(root hash.Hash) IteratorOption {
 	}
 }
 
-func newTreeIterator(ctx context.Context, tree *tree, options ...IteratorOption) Iterator {
+func newTreeIterator(ctx context.Context, src nodeSource, options ...IteratorOption) Iterator {
 	it := &treeIterator{
-		ctx:  ctx,
-		tree: tree,
+		ctx: ctx,
+		src: src,
 	}
 
 	for _, v := range options {
@@ -195,7 +212,7 @@ func (it *treeIterator) Seek(key node.Key) {
 	}
 
 	it.reset()
-	err := it.doNext(it.tree.cache.pendingRoot, 0, node.Key{}, key, visitBefore)
+	err := it.doNext(it.src.rootPtr(), 0, node.Key{}, key, visitBefore)
 	if err != nil {
 		// Make sure to invalidate the iterator on error.
 		it.setError(err)
@@ -213,9 +230,9 @@ func (it *treeIterator) Next() {
 		remainder := it.pos[1:]
 
 		// Remember where the path from root to target node ends (will end).
-		it.tree.cache.markPosition()
+		it.src.markPosition()
 		for _, a := range remainder {
-			it.tree.cache.useNode(a.ptr)
+			it.src.useNode(a.ptr)
 		}
 
 		// Try to proceed with the current node. If we don't succeed, proceed to the
@@ -244,7 +261,7 @@ func (it *treeIterator) Next() {
 
 func (it *treeIterator) doNext(ptr *node.Pointer, bitDepth node.Depth, path node.Key, key node.Key, state visitState) error { // nolint: gocyclo
 	// Dereference the node, possibly making a remote request.
-	nd, err := it.tree.cache.derefNodePtr(it.ctx, ptr, it.tree.newFetcherSyncIterate(key, it.prefetch))
+	nd, err := it.src.derefNodePtr(it.ctx, ptr, it.src.newFetcherSyncIterate(key, it.prefetch))
 	if err != nil {
 		return err
 	}
@@ -357,6 +374,6 @@ func (it *treeIterator) GetProofBuilder() *syncer.ProofBuilder {
 func (it *treeIterator) Close() {
 	it.reset()
 	it.ctx = nil
-	it.tree = nil
+	it.src = nil
 	it.err = errClosed
 }
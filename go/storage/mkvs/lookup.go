@@ -27,7 +27,7 @@ func (t *tree) Get(ctx context.Context, key []byte) ([]byte, error) {
 	// Remember where the path from root to target node ends (will end).
 	t.cache.markPosition()
 
-	return t.doGet(ctx, t.cache.pendingRoot, 0, key, doGetOptions{}, false)
+	return doGet(ctx, t, t.cache.pendingRoot, 0, key, doGetOptions{}, false)
 }
 
 // Implements syncer.ReadSyncer.
@@ -45,15 +45,19 @@ func (t *tree) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer
 		return nil, syncer.ErrDirtyRoot
 	}
 
+	return doSyncGet(ctx, t, request)
+}
+
+func doSyncGet(ctx context.Context, src nodeSource, request *syncer.GetRequest) (*syncer.ProofResponse, error) {
 	// Remember where the path from root to target node ends (will end).
-	t.cache.markPosition()
+	src.markPosition()
 
 	pb := syncer.NewProofBuilder(request.Tree.Position)
 	opts := doGetOptions{
 		proofBuilder:    pb,
 		includeSiblings: request.IncludeSiblings,
 	}
-	if _, err := t.doGet(ctx, t.cache.pendingRoot, 0, request.Key, opts, false); err != nil {
+	if _, err := doGet(ctx, src, src.rootPtr(), 0, request.Key, opts, false); err != nil {
 		return nil, err
 	}
 	proof, err := pb.Build(ctx)
@@ -88,8 +92,9 @@ type doGetOptions struct {
 	includeSiblings bool
 }
 
-func (t *tree) doGet(
+func doGet(
 	ctx context.Context,
+	src nodeSource,
 	ptr *node.Pointer,
 	bitDepth node.Depth,
 	key node.Key,
@@ -101,7 +106,7 @@ func (t *tree) doGet(
 	}
 
 	// Dereference the node, possibly making a remote request.
-	nd, err := t.cache.derefNodePtr(ctx, ptr, t.newFetcherSyncGet(key, opts.includeSiblings))
+	nd, err := src.derefNodePtr(ctx, ptr, src.newFetcherSyncGet(key, opts.includeSiblings))
 	if err != nil {
 		return nil, err
 	}
@@ -133,11 +138,11 @@ func (t *tree) doGet(
 			// Include siblings before disabling the proof builder for the leaf node.
 			if opts.includeSiblings {
 				// Also fetch the left and right siblings.
-				_, err = t.doGet(ctx, n.Left, bitLength, key, opts, true)
+				_, err = doGet(ctx, src, n.Left, bitLength, key, opts, true)
 				if err != nil {
 					return nil, err
 				}
-				_, err = t.doGet(ctx, n.Right, bitLength, key, opts, true)
+				_, err = doGet(ctx, src, n.Right, bitLength, key, opts, true)
 				if err != nil {
 					return nil, err
 				}
@@ -146,7 +151,7 @@ func (t *tree) doGet(
 			// Omit the proof builder as the leaf node is always included with
 			// the internal node itself.
 			opts.proofBuilder = nil
-			return t.doGet(ctx, n.LeafNode, bitLength, key, opts, false)
+			return doGet(ctx, src, n.LeafNode, bitLength, key, opts, false)
 		}
 
 		// Lookup key is too short for the current n.Label. It's not stored.
@@ -157,14 +162,14 @@ func (t *tree) doGet(
 		// Continue recursively based on a bit value.
 		var value []byte
 		if key.GetBit(bitLength) {
-			value, err = t.doGet(ctx, n.Right, bitLength, key, opts, false)
+			value, err = doGet(ctx, src, n.Right, bitLength, key, opts, false)
 			if err != nil {
 				return nil, err
 			}
 
 			if opts.includeSiblings {
 				// Also fetch the left sibling.
-				_, err = t.doGet(ctx, n.Left, bitLength, key, opts, true)
+				_, err = doGet(ctx, src, n.Left, bitLength, key, opts, true)
 				if err != nil {
 					return nil, err
 				}
@@ -172,14 +177,14 @@ func (t *tree) doGet(
 			return value, nil
 		}
 
-		value, err = t.doGet(ctx, n.Left, bitLength, key, opts, false)
+		value, err = doGet(ctx, src, n.Left, bitLength, key, opts, false)
 		if err != nil {
 			return nil, err
 		}
 
 		if opts.includeSiblings {
 			// Also fetch the right sibling.
-			_, err = t.doGet(ctx, n.Right, bitLength, key, opts, true)
+			_, err = doGet(ctx, src, n.Right, bitLength, key, opts, true)
 			if err != nil {
 				return nil, err
 			}
@@ -20,6 +20,11 @@ var (
 	// ErrKnownRootMismatch is the error returned by CommitKnown when the known
 	// root mismatches.
 	ErrKnownRootMismatch = errors.New("mkvs: known root mismatch")
+
+	// ErrSpillRootMismatch is the error returned by Commit when the tree has
+	// spilled dirty nodes under a different namespace or version than the
+	// one being committed.
+	ErrSpillRootMismatch = errors.New("mkvs: spill root mismatch")
 )
 
 // ImmutableKeyValueTree is the immutable key-value store tree interface.
@@ -65,6 +70,23 @@ type OverlayTree interface {
 	Commit(ctx context.Context) error
 }
 
+// SpeculativeTree is a tree that layers uncommitted updates over a read-only
+// base tree. Reads observe all pending updates, while the base tree is never
+// modified.
+type SpeculativeTree interface {
+	KeyValueTree
+	ClosableTree
+
+	// Discard discards all pending updates.
+	//
+	// Any iterators created before calling this method MUST NOT be used
+	// anymore.
+	Discard()
+
+	// Promote applies all pending updates to the given tree and discards them.
+	Promote(ctx context.Context, dst KeyValueTree) error
+}
+
 // Tree is a general MKVS tree interface.
 type Tree interface {
 	KeyValueTree
@@ -8,10 +8,13 @@ import (
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/syncer"
 )
 
-var _ OverlayTree = (*treeOverlay)(nil)
+var (
+	_ OverlayTree     = (*treeOverlay)(nil)
+	_ SpeculativeTree = (*treeOverlay)(nil)
+)
 
 type treeOverlay struct {
-	inner   Tree
+	inner   ImmutableKeyValueTree
 	overlay Tree
 
 	dirty map[string]bool
@@ -25,6 +28,21 @@ type treeOverlay struct {
 //
 // The overlay is not safe for concurrent use.
 func NewOverlay(inner Tree) OverlayTree {
+	return newTreeOverlay(inner)
+}
+
+// NewSpeculative creates a new speculative tree that holds all updates in memory on top of the
+// given read-only base tree (e.g., a snapshot tree). Pending updates can either be discarded or
+// promoted to a different tree (e.g., the canonical tree opened at the same root).
+//
+// The base tree is never modified and is not closed when the speculative tree is closed.
+//
+// The speculative tree is not safe for concurrent use.
+func NewSpeculative(base ImmutableKeyValueTree) SpeculativeTree {
+	return newTreeOverlay(base)
+}
+
+func newTreeOverlay(inner ImmutableKeyValueTree) *treeOverlay {
 	return &treeOverlay{
 		inner:   inner,
 		overlay: New(nil, nil, WithoutWriteLog()),
@@ -91,12 +109,18 @@ func (o *treeOverlay) NewIterator(ctx context.Context, options ...IteratorOption
 
 // Implements OverlayTree.
 func (o *treeOverlay) Commit(ctx context.Context) error {
+	// Overlays created via NewOverlay always have a mutable inner tree.
+	return o.Promote(ctx, o.inner.(KeyValueTree))
+}
+
+// Implements SpeculativeTree.
+func (o *treeOverlay) Promote(ctx context.Context, dst KeyValueTree) error {
 	it := o.overlay.NewIterator(ctx)
 	defer it.Close()
 
 	// Insert all items present in the overlay.
 	for it.Rewind(); it.Valid(); it.Next() {
-		if err := o.inner.Insert(ctx, it.Key(), it.Value()); err != nil {
+		if err := dst.Insert(ctx, it.Key(), it.Value()); err != nil {
 			return err
 		}
 		delete(o.dirty, string(it.Key()))
@@ -107,14 +131,28 @@ func (o *treeOverlay) Commit(ctx context.Context) error {
 
 	// Any remaining dirty items must have been removed.
 	for key := range o.dirty {
-		if err := o.inner.Remove(ctx, []byte(key)); err != nil {
+		if err := dst.Remove(ctx, []byte(key)); err != nil {
 			return err
 		}
 	}
 
+	o.Discard()
+
 	return nil
 }
 
+// Implements SpeculativeTree.
+func (o *treeOverlay) Discard() {
+	if o.inner == nil {
+		return
+	}
+
+	o.overlay.Close()
+
+	o.overlay = New(nil, nil, WithoutWriteLog())
+	o.dirty = make(map[string]bool)
+}
+
 // Implements ClosableTree.
 func (o *treeOverlay) Close() {
 	if o.inner == nil {
@@ -169,7 +207,7 @@ func (it *treeOverlayIterator) Seek(key node.Key) {
 }
 
 func (it *treeOverlayIterator) Next() {
-	if !it.overlay.Valid() || it.inner.Key().Compare(it.overlay.Key()) <= 0 {
+	if it.inner.Valid() && (!it.overlay.Valid() || it.inner.Key().Compare(it.overlay.Key()) <= 0) {
 		// Key of inner iterator is smaller or equal than the key of the overlay iterator.
 		it.inner.Next()
 	} else {
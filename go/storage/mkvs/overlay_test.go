@@ -154,3 +154,106 @@ func TestOverlay(t *testing.T) {
 	_, err = tree.Get(ctx, []byte("key"))
 	require.NoError(err, "Get")
 }
+
+func TestSpeculative(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	base := New(nil, nil)
+	defer base.Close()
+
+	// Insert some items.
+	items := writelog.WriteLog{
+		writelog.LogEntry{Key: []byte("key 1"), Value: []byte("one")},
+		writelog.LogEntry{Key: []byte("key 2"), Value: []byte("two")},
+		writelog.LogEntry{Key: []byte("key 5"), Value: []byte("five")},
+	}
+	err := base.ApplyWriteLog(ctx, writelog.NewStaticIterator(items))
+	require.NoError(err, "ApplyWriteLog")
+
+	// Create a speculative tree.
+	spec := NewSpeculative(base)
+	defer spec.Close()
+
+	err = spec.Remove(ctx, []byte("key 2"))
+	require.NoError(err, "Remove")
+	err = spec.Insert(ctx, []byte("key 3"), []byte("three"))
+	require.NoError(err, "Insert")
+	err = spec.Insert(ctx, []byte("key 9"), []byte("nine"))
+	require.NoError(err, "Insert")
+
+	// State of speculative tree after updates. Note that the last item is past the end of the
+	// base tree.
+	updated := writelog.WriteLog{
+		writelog.LogEntry{Key: []byte("key 1"), Value: []byte("one")},
+		writelog.LogEntry{Key: []byte("key 3"), Value: []byte("three")},
+		writelog.LogEntry{Key: []byte("key 5"), Value: []byte("five")},
+		writelog.LogEntry{Key: []byte("key 9"), Value: []byte("nine")},
+	}
+
+	t.Run("Updates/Get", func(t *testing.T) {
+		for _, item := range updated {
+			var value []byte
+			value, err = spec.Get(ctx, item.Key)
+			require.NoError(err, "Get")
+			require.Equal(item.Value, value, "value from speculative tree should be correct")
+		}
+		var value []byte
+		value, err = spec.Get(ctx, []byte("key 2"))
+		require.NoError(err, "Get")
+		require.Nil(value, "removed value should not exist in speculative tree")
+	})
+
+	t.Run("Updates/Iterator", func(t *testing.T) {
+		it := spec.NewIterator(ctx)
+		defer it.Close()
+
+		testIterator(t, updated, it, []testCase{})
+	})
+
+	// Discard all updates.
+	spec.Discard()
+
+	t.Run("Discarded/Iterator", func(t *testing.T) {
+		it := spec.NewIterator(ctx)
+		defer it.Close()
+
+		testIterator(t, items, it, []testCase{})
+	})
+
+	// Redo the updates and promote them to a different tree.
+	err = spec.Remove(ctx, []byte("key 2"))
+	require.NoError(err, "Remove")
+	err = spec.Insert(ctx, []byte("key 3"), []byte("three"))
+	require.NoError(err, "Insert")
+	err = spec.Insert(ctx, []byte("key 9"), []byte("nine"))
+	require.NoError(err, "Insert")
+
+	dst := New(nil, nil)
+	defer dst.Close()
+	err = dst.ApplyWriteLog(ctx, writelog.NewStaticIterator(items))
+	require.NoError(err, "ApplyWriteLog")
+
+	err = spec.Promote(ctx, dst)
+	require.NoError(err, "Promote")
+
+	t.Run("Promoted/Iterator", func(t *testing.T) {
+		it := dst.NewIterator(ctx)
+		defer it.Close()
+
+		testIterator(t, updated, it, []testCase{})
+	})
+
+	// Make sure that the base tree was not modified and that promotion discarded the updates.
+	t.Run("Promoted/Base", func(t *testing.T) {
+		it := base.NewIterator(ctx)
+		defer it.Close()
+
+		testIterator(t, items, it, []testCase{})
+
+		sit := spec.NewIterator(ctx)
+		defer sit.Close()
+
+		testIterator(t, items, sit, []testCase{})
+	})
+}
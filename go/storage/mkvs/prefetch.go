@@ -27,7 +27,8 @@ func (t *tree) PrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit ui
 func (t *tree) doPrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit uint16) error {
 	// TODO: Can we avoid fetching items that we already have?
 
-	return t.cache.remoteSync(
+	var truncated bool
+	err := t.cache.remoteSync(
 		ctx,
 		t.cache.pendingRoot,
 		func(ctx context.Context, ptr *node.Pointer, rs syncer.ReadSyncer) (*syncer.Proof, error) {
@@ -42,9 +43,53 @@ func (t *tree) doPrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit
 			if err != nil {
 				return nil, err
 			}
+			truncated = rsp.Truncated
 			return &rsp.Proof, nil
 		},
 	)
+	if err != nil || !truncated {
+		return err
+	}
+
+	// The remote syncer truncated the proof, resume by iterating over the same
+	// prefixes. The iterator will skip over the items that have already been
+	// fetched and fetch the rest via SyncIterate, starting at the last seen key.
+	it := newTreeIterator(ctx, t, IteratorPrefetch(limit))
+	defer it.Close()
+
+	_, err = iteratePrefixes(it, prefixes, limit, nil)
+	return err
+}
+
+// iteratePrefixes iterates over all items under the given prefixes, up to
+// the given total number of items.
+//
+// If the stop function is non-nil and returns true before an item is
+// iterated over, the iteration stops and true is returned.
+func iteratePrefixes(it Iterator, prefixes [][]byte, limit uint16, stop func() bool) (bool, error) {
+	var total int
+	for _, prefix := range prefixes {
+		it.Seek(prefix)
+		if it.Err() != nil {
+			return false, it.Err()
+		}
+		for ; it.Valid(); total++ {
+			if total >= int(limit) {
+				return false, nil
+			}
+			if !bytes.HasPrefix(it.Key(), prefix) {
+				break
+			}
+			if stop != nil && stop() {
+				return true, nil
+			}
+			it.Next()
+		}
+		if it.Err() != nil {
+			return false, it.Err()
+		}
+	}
+	return false, nil
 }
 
 // Implements syncer.ReadSyncer.
@@ -72,28 +117,25 @@ func (t *tree) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesR
 		}
 	}
 
-	it := t.NewIterator(ctx, WithProof(request.Tree.Root.Hash))
+	return doSyncGetPrefixes(ctx, t, request, &t.proofLimits)
+}
+
+func doSyncGetPrefixes(
+	ctx context.Context,
+	src nodeSource,
+	request *syncer.GetPrefixesRequest,
+	limits *syncer.ProofLimits,
+) (*syncer.ProofResponse, error) {
+	it := newTreeIterator(ctx, src, WithProof(request.Tree.Root.Hash))
 	defer it.Close()
 
-	var total int
-prefixLoop:
-	for _, prefix := range request.Prefixes {
-		it.Seek(prefix)
-		if it.Err() != nil {
-			return nil, it.Err()
-		}
-		for ; it.Valid(); total++ {
-			if total >= int(request.Limit) {
-				break prefixLoop
-			}
-			if !bytes.HasPrefix(it.Key(), prefix) {
-				break
-			}
-			it.Next()
-		}
-		if it.Err() != nil {
-			return nil, it.Err()
-		}
+	// Stop early in case the proof is getting too large, the client will
+	// resume from the last item included in the proof.
+	truncated, err := iteratePrefixes(it, request.Prefixes, request.Limit, func() bool {
+		return limits.Exceeded(it.GetProofBuilder())
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	proof, err := it.GetProof()
@@ -102,6 +144,7 @@ prefixLoop:
 	}
 
 	return &syncer.ProofResponse{
-		Proof: *proof,
+		Proof:     *proof,
+		Truncated: truncated,
 	}, nil
 }
@@ -42,6 +42,11 @@ func (t *tree) RemoveExisting(ctx context.Context, key []byte) ([]byte, error) {
 	}
 
 	t.cache.setPendingRoot(newRoot)
+
+	// Spill dirty nodes to the node database if over the memory budget.
+	if err = t.maybeSpill(ctx); err != nil {
+		return nil, err
+	}
 	return existing, nil
 }
 
@@ -0,0 +1,231 @@
+package mkvs
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/syncer"
+)
+
+// DefaultSnapshotNodeCapacity is the default maximum number of decoded nodes
+// kept by a snapshot tree.
+const DefaultSnapshotNodeCapacity = 50_000
+
+var (
+	_ ImmutableKeyValueTree = (*SnapshotTree)(nil)
+	_ ClosableTree          = (*SnapshotTree)(nil)
+	_ syncer.ReadSyncer     = (*SnapshotTree)(nil)
+	_ nodeSource            = (*SnapshotTree)(nil)
+)
+
+// SnapshotTree is an immutable MKVS tree opened from an existing (finalized)
+// root in the node database.
+//
+// Unlike a regular tree, which serializes all access behind its cache lock,
+// a snapshot tree is safe for concurrent use and its lookups do not take any
+// locks. Nodes decoded by any of the readers are shared read-only between all
+// readers of the snapshot.
+//
+// Note that iterators obtained from a snapshot tree are still not safe for
+// concurrent use, but multiple iterators may be used concurrently.
+type SnapshotTree struct {
+	ndb  db.NodeDB
+	root node.Root
+	ptr  *node.Pointer
+
+	// nodes are the decoded nodes keyed by hash. Nodes are never modified
+	// after being inserted.
+	nodes        sync.Map
+	nodeCount    uint64
+	nodeCapacity uint64
+
+	proofLimits syncer.ProofLimits
+
+	closed uint32
+}
+
+// SnapshotOption is a configuration option used when instantiating the
+// snapshot tree.
+type SnapshotOption func(t *SnapshotTree)
+
+// SnapshotNodeCapacity sets the maximum number of decoded nodes kept by the
+// snapshot tree. Nodes over capacity are not kept and are fetched from the
+// node database on each use.
+//
+// If no capacity is specified, DefaultSnapshotNodeCapacity is used. If a
+// capacity of 0 is specified, all decoded nodes are kept.
+func SnapshotNodeCapacity(nodeCapacity uint64) SnapshotOption {
+	return func(t *SnapshotTree) {
+		t.nodeCapacity = nodeCapacity
+	}
+}
+
+// SnapshotSyncProofLimits sets the limits on the size of proofs generated by
+// the snapshot tree in response to SyncIterate and SyncGetPrefixes requests.
+//
+// If no limits are specified, syncer.DefaultProofLimits are used.
+func SnapshotSyncProofLimits(limits syncer.ProofLimits) SnapshotOption {
+	return func(t *SnapshotTree) {
+		t.proofLimits = limits
+	}
+}
+
+// NewSnapshot opens a snapshot tree for the given root, backed by the given
+// node database.
+//
+// The root must not be pruned while the snapshot tree is being used.
+func NewSnapshot(ndb db.NodeDB, root node.Root, options ...SnapshotOption) *SnapshotTree {
+	t := &SnapshotTree{
+		ndb:  ndb,
+		root: root,
+		ptr: &node.Pointer{
+			Clean: true,
+			Hash:  root.Hash,
+		},
+		nodeCapacity: DefaultSnapshotNodeCapacity,
+		proofLimits:  syncer.DefaultProofLimits,
+	}
+
+	for _, v := range options {
+		v(t)
+	}
+
+	return t
+}
+
+// Root returns the root of the snapshot tree.
+func (t *SnapshotTree) Root() node.Root {
+	return t.root
+}
+
+func (t *SnapshotTree) isClosed() bool {
+	return atomic.LoadUint32(&t.closed) == 1
+}
+
+// Implements ImmutableKeyValueTree.
+func (t *SnapshotTree) Get(ctx context.Context, key []byte) ([]byte, error) {
+	if t.isClosed() {
+		return nil, ErrClosed
+	}
+
+	return doGet(ctx, t, t.ptr, 0, key, doGetOptions{}, false)
+}
+
+// Implements ImmutableKeyValueTree.
+func (t *SnapshotTree) NewIterator(ctx context.Context, options ...IteratorOption) Iterator {
+	return newTreeIterator(ctx, t, options...)
+}
+
+// Implements syncer.ReadSyncer.
+func (t *SnapshotTree) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer.ProofResponse, error) {
+	if err := t.checkSyncRequest(&request.Tree); err != nil {
+		return nil, err
+	}
+
+	return doSyncGet(ctx, t, request)
+}
+
+// Implements syncer.ReadSyncer.
+func (t *SnapshotTree) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesRequest) (*syncer.ProofResponse, error) {
+	if err := t.checkSyncRequest(&request.Tree); err != nil {
+		return nil, err
+	}
+
+	return doSyncGetPrefixes(ctx, t, request, &t.proofLimits)
+}
+
+// Implements syncer.ReadSyncer.
+func (t *SnapshotTree) SyncIterate(ctx context.Context, request *syncer.IterateRequest) (*syncer.ProofResponse, error) {
+	if err := t.checkSyncRequest(&request.Tree); err != nil {
+		return nil, err
+	}
+
+	return doSyncIterate(ctx, t, request, &t.proofLimits)
+}
+
+func (t *SnapshotTree) checkSyncRequest(id *syncer.TreeID) error {
+	if t.isClosed() {
+		return ErrClosed
+	}
+	if !id.Root.Equal(&t.root) {
+		return syncer.ErrInvalidRoot
+	}
+	return nil
+}
+
+// Implements ClosableTree.
+func (t *SnapshotTree) Close() {
+	if !atomic.CompareAndSwapUint32(&t.closed, 0, 1) {
+		return
+	}
+
+	t.nodes.Range(func(key, value interface{}) bool {
+		t.nodes.Delete(key)
+		return true
+	})
+}
+
+func (t *SnapshotTree) rootPtr() *node.Pointer {
+	return t.ptr
+}
+
+func (t *SnapshotTree) derefNodePtr(ctx context.Context, ptr *node.Pointer, fetcher readSyncFetcher) (node.Node, error) {
+	if ptr == nil {
+		return nil, nil
+	}
+	// Pointers to nodes embedded in their parent (e.g., leaf nodes of internal
+	// nodes) are resolved on decode.
+	if ptr.Node != nil {
+		return ptr.Node, nil
+	}
+	if !ptr.Clean || ptr.Hash.IsEmpty() {
+		return nil, nil
+	}
+
+	if n, ok := t.nodes.Load(ptr.Hash); ok {
+		return n.(node.Node), nil
+	}
+
+	// NOTE: The passed pointer may be shared with other readers so it must
+	//       not be modified.
+	n, err := t.ndb.GetNode(t.root, &node.Pointer{Clean: true, Hash: ptr.Hash})
+	if err != nil {
+		return nil, err
+	}
+	t.maybeStoreNode(ptr.Hash, n)
+
+	return n, nil
+}
+
+func (t *SnapshotTree) maybeStoreNode(h hash.Hash, n node.Node) {
+	if t.nodeCapacity > 0 && atomic.AddUint64(&t.nodeCount, 1) > t.nodeCapacity {
+		atomic.AddUint64(&t.nodeCount, ^uint64(0))
+		return
+	}
+	if _, loaded := t.nodes.LoadOrStore(h, n); loaded && t.nodeCapacity > 0 {
+		// Another reader has stored the same node concurrently.
+		atomic.AddUint64(&t.nodeCount, ^uint64(0))
+	}
+}
+
+func (t *SnapshotTree) markPosition() {
+	// Snapshot trees do not track node usage.
+}
+
+func (t *SnapshotTree) useNode(ptr *node.Pointer) {
+	// Snapshot trees do not track node usage.
+}
+
+func (t *SnapshotTree) newFetcherSyncGet(key node.Key, includeSiblings bool) readSyncFetcher {
+	// Snapshot trees are never backed by a remote syncer.
+	return nil
+}
+
+func (t *SnapshotTree) newFetcherSyncIterate(key node.Key, prefetch uint16) readSyncFetcher {
+	// Snapshot trees are never backed by a remote syncer.
+	return nil
+}
@@ -0,0 +1,137 @@
+package mkvs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+var (
+	spillCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_spill_count",
+			Help: "Number of times dirty MKVS tree nodes exceeded the memory budget and were spilled.",
+		},
+	)
+	spilledNodeCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_spilled_node_count",
+			Help: "Number of dirty MKVS tree nodes spilled to the node database.",
+		},
+	)
+
+	spillCollectors = []prometheus.Collector{
+		spillCount,
+		spilledNodeCount,
+	}
+
+	metricsOnce sync.Once
+)
+
+// spillBatch is a batch wrapper that records the hashes of all persisted nodes.
+type spillBatch struct {
+	db.Batch
+
+	hashes []hash.Hash
+}
+
+func (b *spillBatch) MaybeStartSubtree(subtree db.Subtree, depth node.Depth, subtreeRoot *node.Pointer) db.Subtree {
+	parent, _ := subtree.(*spillSubtree)
+
+	var inner db.Subtree
+	if parent != nil {
+		inner = parent.Subtree
+	}
+	newInner := b.Batch.MaybeStartSubtree(inner, depth, subtreeRoot)
+	if parent != nil && newInner == parent.Subtree {
+		return parent
+	}
+	return &spillSubtree{Subtree: newInner, batch: b}
+}
+
+type spillSubtree struct {
+	db.Subtree
+
+	batch *spillBatch
+}
+
+func (s *spillSubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
+	if err := s.Subtree.PutNode(depth, ptr); err != nil {
+		return err
+	}
+	s.batch.hashes = append(s.batch.hashes, ptr.Node.GetHash())
+	return nil
+}
+
+// maybeSpill persists all dirty nodes to the node database as an interim root
+// in case they exceed the configured memory budget.
+//
+// The interim root is never finalized, so the spilled nodes are only retained
+// if they are still part of the tree when it is finally committed.
+func (t *tree) maybeSpill(ctx context.Context) error {
+	if t.memoryBudget == 0 || t.cache.dirtySize <= t.memoryBudget {
+		return nil
+	}
+
+	version := t.spillVersion
+	oldRoot := t.cache.getSyncRoot()
+	if oldRoot.IsEmpty() {
+		oldRoot.Namespace = t.spillNamespace
+		oldRoot.Version = version
+	}
+
+	batch := &spillBatch{Batch: t.cache.db.NewBatch(oldRoot, version, false)}
+	defer batch.Reset()
+
+	subtree := batch.MaybeStartSubtree(nil, 0, t.cache.pendingRoot)
+
+	rootHash, err := doCommit(ctx, t.cache, batch, subtree, 0, t.cache.pendingRoot, &version)
+	if err != nil {
+		return err
+	}
+	if err = subtree.Commit(); err != nil {
+		return err
+	}
+
+	root := node.Root{
+		Namespace: t.spillNamespace,
+		Version:   version,
+		Hash:      rootHash,
+	}
+	if err = batch.Commit(root); err != nil {
+		return err
+	}
+
+	for _, h := range batch.hashes {
+		t.spilledNodes[h] = true
+	}
+	t.cache.spillRoot = &root
+	t.cache.dirtySize = 0
+
+	spillCount.Inc()
+	spilledNodeCount.Add(float64(len(batch.hashes)))
+
+	return nil
+}
+
+// retainedSpilledNodes returns the hashes of spilled nodes that have not been
+// removed from the tree since they were spilled.
+func (t *tree) retainedSpilledNodes() []hash.Hash {
+	removed := make(map[hash.Hash]bool)
+	for _, n := range t.pendingRemovedNodes {
+		removed[n.GetHash()] = true
+	}
+
+	var hashes []hash.Hash
+	for h := range t.spilledNodes {
+		if !removed[h] {
+			hashes = append(hashes, h)
+		}
+	}
+	return hashes
+}
@@ -26,6 +26,37 @@ type Proof struct {
 	Entries [][]byte `json:"entries"`
 }
 
+// DefaultProofLimits are the default limits on the size of proofs generated
+// in response to read syncer requests.
+var DefaultProofLimits = ProofLimits{
+	MaxSize:  16 * 1024 * 1024,
+	MaxNodes: 100_000,
+}
+
+// ProofLimits are the limits on the size of proofs generated in response to
+// read syncer requests that may include an unbounded number of items.
+//
+// Once a limit is reached, no further items are included and the response
+// is marked as truncated. A zero limit means that there is no limit.
+type ProofLimits struct {
+	// MaxSize is the maximum proof size in bytes.
+	MaxSize uint64 `json:"max_size,omitempty"`
+	// MaxNodes is the maximum number of nodes included in the proof.
+	MaxNodes uint64 `json:"max_nodes,omitempty"`
+}
+
+// Exceeded returns true iff the proof being built by the given proof builder
+// has reached any of the limits.
+func (l *ProofLimits) Exceeded(b *ProofBuilder) bool {
+	if l.MaxSize > 0 && b.Size() >= l.MaxSize {
+		return true
+	}
+	if l.MaxNodes > 0 && b.NodeCount() >= l.MaxNodes {
+		return true
+	}
+	return false
+}
+
 type proofNode struct {
 	serialized []byte
 	children   []hash.Hash
@@ -109,6 +140,11 @@ func (b *ProofBuilder) Size() uint64 {
 	return b.size
 }
 
+// NodeCount returns the number of nodes included in this proof.
+func (b *ProofBuilder) NodeCount() uint64 {
+	return uint64(len(b.included))
+}
+
 // Build tries to build the proof.
 func (b *ProofBuilder) Build(ctx context.Context) (*Proof, error) {
 	proof := Proof{
@@ -53,6 +53,14 @@ type IterateRequest struct {
 // ProofResponse is a response for requests that produce proofs.
 type ProofResponse struct {
 	Proof Proof `json:"proof"`
+
+	// Truncated is true iff the proof was cut short due to the proof limits
+	// of the remote syncer, so it may not include all of the requested items.
+	//
+	// In this case the proof includes a prefix of the requested items in key
+	// iteration order and the client should resume from the last key that it
+	// has seen.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // ReadSyncer is the interface for synchronizing the in-memory cache
@@ -3,13 +3,39 @@ package mkvs
 import (
 	"context"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/syncer"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/writelog"
 )
 
-var _ Tree = (*tree)(nil)
+var (
+	_ Tree       = (*tree)(nil)
+	_ nodeSource = (*tree)(nil)
+)
+
+// nodeSource is a source of nodes for read-only tree traversals.
+type nodeSource interface {
+	// rootPtr returns the pointer to the root node of the traversed tree.
+	rootPtr() *node.Pointer
+
+	// derefNodePtr dereferences the given node pointer, using the given
+	// fetcher in case the node needs to be fetched from a remote syncer.
+	derefNodePtr(ctx context.Context, ptr *node.Pointer, fetcher readSyncFetcher) (node.Node, error)
+
+	// markPosition marks the position of the traversed path in the cache.
+	markPosition()
+
+	// useNode marks the node as used in the cache.
+	useNode(ptr *node.Pointer)
+
+	newFetcherSyncGet(key node.Key, includeSiblings bool) readSyncFetcher
+	newFetcherSyncIterate(key node.Key, prefetch uint16) readSyncFetcher
+}
 
 type tree struct {
 	cache *cache
@@ -21,6 +47,19 @@ type tree struct {
 	// in-memory tree and should be marked for garbage collection if this
 	// tree is committed to the node database.
 	pendingRemovedNodes []node.Node
+
+	// memoryBudget is the approximate maximum size of dirty nodes after
+	// which they are spilled to the node database (zero means unlimited).
+	memoryBudget   uint64
+	spillNamespace common.Namespace
+	spillVersion   uint64
+	// spilledNodes are the nodes that have been persisted by interim spills
+	// since the last commit.
+	spilledNodes map[hash.Hash]bool
+
+	// proofLimits are the limits on the size of proofs generated in response
+	// to SyncIterate and SyncGetPrefixes requests.
+	proofLimits syncer.ProofLimits
 }
 
 type pendingEntry struct {
@@ -71,6 +110,34 @@ func WithoutWriteLog() Option {
 	}
 }
 
+// MemoryBudget sets the approximate maximum size in bytes of dirty nodes kept
+// in memory.
+//
+// When the budget is exceeded during an update, all dirty nodes are spilled
+// to the node database as an interim root under the given namespace and
+// version, making them eligible for eviction from the in-memory cache. Interim
+// roots are never finalized, so the tree must eventually be committed under
+// the same namespace and version.
+//
+// If not specified, the dirty nodes are never spilled.
+func MemoryBudget(budgetBytes uint64, namespace common.Namespace, version uint64) Option {
+	return func(t *tree) {
+		t.memoryBudget = budgetBytes
+		t.spillNamespace = namespace
+		t.spillVersion = version
+	}
+}
+
+// SyncProofLimits sets the limits on the size of proofs generated by the tree
+// in response to SyncIterate and SyncGetPrefixes requests.
+//
+// If not specified, syncer.DefaultProofLimits are used.
+func SyncProofLimits(limits syncer.ProofLimits) Option {
+	return func(t *tree) {
+		t.proofLimits = limits
+	}
+}
+
 // New creates a new empty MKVS tree backed by the given node database.
 func New(rs syncer.ReadSyncer, ndb db.NodeDB, options ...Option) Tree {
 	if rs == nil {
@@ -84,12 +151,20 @@ func New(rs syncer.ReadSyncer, ndb db.NodeDB, options ...Option) Tree {
 		cache:           newCache(ndb, rs),
 		pendingWriteLog: make(map[string]*pendingEntry),
 		withoutWriteLog: false,
+		spilledNodes:    make(map[hash.Hash]bool),
+		proofLimits:     syncer.DefaultProofLimits,
 	}
 
 	for _, v := range options {
 		v(t)
 	}
 
+	if t.memoryBudget > 0 {
+		metricsOnce.Do(func() {
+			prometheus.MustRegister(spillCollectors...)
+		})
+	}
+
 	return t
 }
 
@@ -105,6 +180,22 @@ func NewWithRoot(rs syncer.ReadSyncer, ndb db.NodeDB, root node.Root, options ..
 	return t
 }
 
+func (t *tree) rootPtr() *node.Pointer {
+	return t.cache.pendingRoot
+}
+
+func (t *tree) derefNodePtr(ctx context.Context, ptr *node.Pointer, fetcher readSyncFetcher) (node.Node, error) {
+	return t.cache.derefNodePtr(ctx, ptr, fetcher)
+}
+
+func (t *tree) markPosition() {
+	t.cache.markPosition()
+}
+
+func (t *tree) useNode(ptr *node.Pointer) {
+	t.cache.useNode(ptr)
+}
+
 // Implements Tree.
 func (t *tree) NewIterator(ctx context.Context, options ...IteratorOption) Iterator {
 	return newTreeIterator(ctx, t, options...)
@@ -146,4 +237,5 @@ func (t *tree) Close() {
 
 	t.cache.close()
 	t.pendingWriteLog = nil
+	t.spilledNodes = nil
 }
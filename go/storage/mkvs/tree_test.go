@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -18,6 +19,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	db "github.com/oasislabs/oasis-core/go/storage/mkvs/db/api"
 	badgerDb "github.com/oasislabs/oasis-core/go/storage/mkvs/db/badger"
+	nodeCache "github.com/oasislabs/oasis-core/go/storage/mkvs/db/cache"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/node"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/syncer"
 	mkvsTests "github.com/oasislabs/oasis-core/go/storage/mkvs/tests"
@@ -812,6 +814,58 @@ func testSyncerPrefetchPrefixes(t *testing.T, ndb db.NodeDB, factory NodeDBFacto
 	require.EqualValues(t, 0, stats.SyncIterateCount, "SyncIterate should not be called")
 }
 
+func testSyncerProofLimits(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+	keys, values, root, tree := generatePopulatedTree(t, ndb)
+	defer tree.Close()
+
+	limits := syncer.ProofLimits{MaxNodes: 100}
+	snapshot := NewSnapshot(ndb, root, SnapshotSyncProofLimits(limits))
+	defer snapshot.Close()
+	treeID := syncer.TreeID{Root: root, Position: root.Hash}
+
+	// Proofs for small requests should not be truncated.
+	rsp, err := snapshot.SyncIterate(ctx, &syncer.IterateRequest{Tree: treeID, Prefetch: 10})
+	require.NoError(t, err, "SyncIterate")
+	require.False(t, rsp.Truncated, "SyncIterate proof should not be truncated")
+
+	// Proofs for large requests should be truncated.
+	rsp, err = snapshot.SyncIterate(ctx, &syncer.IterateRequest{Tree: treeID, Prefetch: 1000})
+	require.NoError(t, err, "SyncIterate")
+	require.True(t, rsp.Truncated, "SyncIterate proof should be truncated")
+	rsp, err = snapshot.SyncGetPrefixes(ctx, &syncer.GetPrefixesRequest{
+		Tree:     treeID,
+		Prefixes: [][]byte{[]byte("key")},
+		Limit:    1000,
+	})
+	require.NoError(t, err, "SyncGetPrefixes")
+	require.True(t, rsp.Truncated, "SyncGetPrefixes proof should be truncated")
+
+	var pv syncer.ProofVerifier
+	_, err = pv.VerifyProof(ctx, root.Hash, &rsp.Proof)
+	require.NoError(t, err, "truncated proof should verify")
+
+	// Prefetching should resume after a truncated proof.
+	stats := syncer.NewStatsCollector(snapshot)
+	remoteTree := NewWithRoot(stats, nil, root, Capacity(0, 0))
+	defer remoteTree.Close()
+
+	err = remoteTree.PrefetchPrefixes(ctx, [][]byte{[]byte("key")}, 1000)
+	require.NoError(t, err, "PrefetchPrefixes")
+	require.EqualValues(t, 1, stats.SyncGetPrefixesCount, "SyncGetPrefixes should be called exactly once")
+	require.True(t, stats.SyncIterateCount > 0, "SyncIterate should be used to resume prefetching")
+	iterateCount := stats.SyncIterateCount
+
+	// Ensure that everything is now cached.
+	for i, key := range keys {
+		v, err := remoteTree.Get(ctx, key)
+		require.NoError(t, err, "Get")
+		require.EqualValues(t, values[i], v)
+	}
+	require.EqualValues(t, 0, stats.SyncGetCount, "SyncGet should not be called")
+	require.EqualValues(t, iterateCount, stats.SyncIterateCount, "SyncIterate should not be called anymore")
+}
+
 func testValueEviction(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	ctx := context.Background()
 	tree := New(nil, ndb, Capacity(0, 512)).(*tree)
@@ -898,6 +952,59 @@ func testDebugDumpLocal(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	require.True(t, len(buffer.Bytes()) > 0)
 }
 
+func testSnapshot(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+	keys, values, root, tree := generatePopulatedTree(t, ndb)
+	defer tree.Close()
+
+	snapshot := NewSnapshot(ndb, root, SnapshotNodeCapacity(100))
+
+	// Perform lookups and iterations from multiple concurrent readers.
+	var wg sync.WaitGroup
+	for r := 0; r < 8; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < len(keys); i++ {
+				value, err := snapshot.Get(ctx, keys[i])
+				require.NoError(t, err, "Get")
+				require.Equal(t, values[i], value)
+			}
+
+			it := snapshot.NewIterator(ctx)
+			defer it.Close()
+
+			var count int
+			for it.Rewind(); it.Valid(); it.Next() {
+				count++
+			}
+			require.NoError(t, it.Err(), "iterator")
+			require.Equal(t, len(keys), count, "iterator should visit all keys")
+		}()
+	}
+	wg.Wait()
+
+	// Make sure the snapshot generates the same proofs as the regular tree.
+	for _, key := range keys[:10] {
+		request := &syncer.GetRequest{
+			Tree:            syncer.TreeID{Root: root, Position: root.Hash},
+			Key:             key,
+			IncludeSiblings: true,
+		}
+		expected, err := tree.SyncGet(ctx, request)
+		require.NoError(t, err, "SyncGet")
+		proof, err := snapshot.SyncGet(ctx, request)
+		require.NoError(t, err, "SyncGet")
+		require.Equal(t, expected, proof, "snapshot proof should match tree proof")
+	}
+
+	snapshot.Close()
+	_, err := snapshot.Get(ctx, keys[0])
+	require.Error(t, err, "Get after Close")
+	require.Equal(t, ErrClosed, err)
+}
+
 func testApplyWriteLog(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	keys, values := generateKeyValuePairsEx("", 100)
 
@@ -1984,6 +2091,83 @@ func testLargeUpdates(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	require.NoError(t, err, "Finalize")
 }
 
+func testMemoryBudget(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+	keys, values := generateKeyValuePairs()
+
+	// Create a root in version 0.
+	tr := New(nil, ndb)
+	for i := 0; i < len(keys)/2; i++ {
+		err := tr.Insert(ctx, keys[i], values[i])
+		require.NoError(t, err, "Insert")
+	}
+	_, rootHash0, err := tr.Commit(ctx, testNs, 0)
+	require.NoError(t, err, "Commit")
+	err = ndb.Finalize(ctx, 0, []hash.Hash{rootHash0})
+	require.NoError(t, err, "Finalize")
+	root0 := node.Root{Namespace: testNs, Version: 0, Hash: rootHash0}
+
+	// Derive a root in version 1 using a small memory budget and a small cache
+	// so that spilled nodes get evicted and need to be fetched again.
+	budgetTree := NewWithRoot(nil, ndb, root0, Capacity(16, 1024), MemoryBudget(4096, testNs, 1)).(*tree)
+	updateTree := func(tr Tree) {
+		for i := len(keys) / 2; i < len(keys); i++ {
+			err = tr.Insert(ctx, keys[i], values[i])
+			require.NoError(t, err, "Insert")
+		}
+		for i := 0; i < len(keys)/10; i++ {
+			err = tr.Remove(ctx, keys[i])
+			require.NoError(t, err, "Remove")
+		}
+	}
+	updateTree(budgetTree)
+	require.NotNil(t, budgetTree.cache.spillRoot, "dirty nodes should be spilled")
+	require.NotEmpty(t, budgetTree.spilledNodes, "dirty nodes should be spilled")
+	for i := 0; i < len(keys); i++ {
+		var value []byte
+		value, err = budgetTree.Get(ctx, keys[i])
+		require.NoError(t, err, "Get")
+		if i < len(keys)/10 {
+			require.Nil(t, value, "Get should return nil for removed keys")
+		} else {
+			require.EqualValues(t, values[i], value, "Get should return the correct value")
+		}
+	}
+	_, rootHash1, err := budgetTree.Commit(ctx, testNs, 1)
+	require.NoError(t, err, "Commit")
+
+	// Perform the same updates without a memory budget.
+	tr = NewWithRoot(nil, ndb, root0)
+	updateTree(tr)
+	_, expectedRootHash1, err := tr.Commit(ctx, testNs, 1)
+	require.NoError(t, err, "Commit")
+	require.Equal(t, expectedRootHash1, rootHash1, "spilling should not affect the root hash")
+
+	// Interim roots should be discarded on finalization, while all the nodes
+	// of the finalized root should be retained.
+	err = ndb.Finalize(ctx, 1, []hash.Hash{rootHash1})
+	require.NoError(t, err, "Finalize")
+	roots, err := ndb.GetRootsForVersion(ctx, 1)
+	require.NoError(t, err, "GetRootsForVersion")
+	require.Equal(t, []hash.Hash{rootHash1}, roots, "only the finalized root should remain")
+
+	tr = NewWithRoot(nil, ndb, node.Root{Namespace: testNs, Version: 1, Hash: rootHash1}, Capacity(0, 0))
+	for i := len(keys) / 10; i < len(keys); i++ {
+		var value []byte
+		value, err = tr.Get(ctx, keys[i])
+		require.NoError(t, err, "Get")
+		require.EqualValues(t, values[i], value, "Get should return the correct value")
+	}
+
+	// Committing under a different version than the spilled nodes should fail.
+	tr = NewWithRoot(nil, ndb, node.Root{Namespace: testNs, Version: 1, Hash: rootHash1}, MemoryBudget(1, testNs, 2))
+	err = tr.Insert(ctx, []byte("spill"), []byte("me"))
+	require.NoError(t, err, "Insert")
+	_, _, err = tr.Commit(ctx, testNs, 3)
+	require.Error(t, err, "Commit")
+	require.Equal(t, ErrSpillRootMismatch, err)
+}
+
 func testBackend(
 	t *testing.T,
 	initBackend func(t *testing.T) (NodeDBFactory, func()),
@@ -2006,10 +2190,12 @@ func testBackend(
 		{"SyncerInsert", testSyncerInsert},
 		{"SyncerNilNodes", testSyncerNilNodes},
 		{"SyncerPrefetchPrefixes", testSyncerPrefetchPrefixes},
+		{"SyncerProofLimits", testSyncerProofLimits},
 		{"ValueEviction", testValueEviction},
 		{"NodeEviction", testNodeEviction},
 		{"DoubleInsertWithEviction", testDoubleInsertWithEviction},
 		{"DebugDump", testDebugDumpLocal},
+		{"Snapshot", testSnapshot},
 		{"OnCommitHooks", testOnCommitHooks},
 		{"MergeWriteLog", testMergeWriteLog},
 		{"HasRoot", testHasRoot},
@@ -2027,6 +2213,7 @@ func testBackend(
 		{"SpecialCase4", testSpecialCase4},
 		{"SpecialCase5", testSpecialCase5},
 		{"LargeUpdates", testLargeUpdates},
+		{"MemoryBudget", testMemoryBudget},
 		{"Errors", testErrors},
 		{"IncompatibleDB", testIncompatibleDB},
 	}
@@ -2052,18 +2239,48 @@ func testBackend(
 }
 
 func TestBadgerBackend(t *testing.T) {
+	testBadgerBackend(t, nil, 0)
+}
+
+func TestBadgerBackendNodeCache(t *testing.T) {
+	// Rotate between the node cache policies on each test case instead of
+	// running the whole suite for each policy, as each run is quite heavy.
+	policies := []string{
+		nodeCache.PolicyLRU,
+		nodeCache.Policy2Q,
+		nodeCache.PolicyClock,
+	}
+	var testCase int
+	nextPolicy := func() string {
+		policy := policies[testCase%len(policies)]
+		testCase++
+		return policy
+	}
+
+	// Use a small cache so that evictions are exercised.
+	testBadgerBackend(t, nextPolicy, 64)
+}
+
+func testBadgerBackend(t *testing.T, nodeCachePolicy func() string, nodeCacheSize uint64) {
 	testBackend(t, func(t *testing.T) (NodeDBFactory, func()) {
 		// Create a new random temporary directory under /tmp.
 		dir, err := ioutil.TempDir("", "mkvs.test.badger")
 		require.NoError(t, err, "TempDir")
 
+		var policy string
+		if nodeCachePolicy != nil {
+			policy = nodeCachePolicy()
+		}
+
 		// Create a Badger-backed Node DB factory.
 		factory := func(ns common.Namespace) (db.NodeDB, error) {
 			return badgerDb.New(&db.Config{
-				DB:           dir,
-				NoFsync:      true,
-				Namespace:    ns,
-				MaxCacheSize: 16 * 1024 * 1024,
+				DB:              dir,
+				NoFsync:         true,
+				Namespace:       ns,
+				MaxCacheSize:    16 * 1024 * 1024,
+				NodeCachePolicy: policy,
+				NodeCacheSize:   nodeCacheSize,
 			})
 		}
 
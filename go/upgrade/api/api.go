@@ -34,7 +34,7 @@ const (
 
 	upgradeStageLast = UpgradeStageConsensus
 
-	// InvalidUpgradeHeight means the upgrade epoch hasn't been reached yet.
+	// InvalidUpgradeHeight means the upgrade boundary hasn't been reached yet.
 	InvalidUpgradeHeight = int64(0)
 )
 
@@ -60,6 +60,10 @@ var (
 
 	// ErrUpgradeInProgress is the error returned from CancelUpgrade when the upgrade being cancelled is already in progress.
 	ErrUpgradeInProgress = errors.New(ModuleName, 6, "upgrade: can not cancel upgrade in progress")
+
+	// ErrInvalidBinary is the error returned when the configured upgrade binary does not match
+	// the identifier in the upgrade descriptor.
+	ErrInvalidBinary = errors.New(ModuleName, 7, "upgrade: upgrade binary does not match descriptor identifier")
 )
 
 // Descriptor describes an upgrade.
@@ -72,7 +76,11 @@ type Descriptor struct {
 	// Upgrade methods other than "internal" may have differently formatted identifiers.
 	Identifier string `json:"identifier"`
 	// Epoch is the epoch at which the upgrade should happen.
-	Epoch epochtime.EpochTime `json:"epoch"`
+	Epoch epochtime.EpochTime `json:"epoch,omitempty"`
+	// Height is the consensus height at which the upgrade should happen.
+	//
+	// Exactly one of Epoch and Height must be set.
+	Height int64 `json:"height,omitempty"`
 }
 
 // IsValid checks if the upgrade descriptor is valid.
@@ -80,12 +88,21 @@ func (d Descriptor) IsValid() bool {
 	if d.Method != UpgradeMethInternal {
 		return false
 	}
-	if d.Epoch < 1 {
+	if (d.Epoch < 1) == (d.Height < 1) {
 		return false
 	}
 	return true
 }
 
+// IsBoundaryReached checks if the upgrade boundary (either the upgrade epoch
+// or the upgrade height) has been reached.
+func (d Descriptor) IsBoundaryReached(currentEpoch epochtime.EpochTime, currentHeight int64) bool {
+	if d.Height > 0 {
+		return currentHeight >= d.Height
+	}
+	return currentEpoch >= d.Epoch
+}
+
 // PendingUpgrade describes a currently pending upgrade and includes the
 // submitted upgrade descriptor.
 type PendingUpgrade struct {
@@ -97,7 +114,7 @@ type PendingUpgrade struct {
 	// RunningVersion is the version of the node trying to execute the descriptor.
 	RunningVersion string `json:"running_version"`
 
-	// UpgradeHeight is the height at which the upgrade epoch was reached
+	// UpgradeHeight is the height at which the upgrade boundary was reached
 	// (or InvalidUpgradeHeight if it hasn't been reached yet).
 	UpgradeHeight int64 `json:"upgrade_height"`
 
@@ -137,6 +154,10 @@ type Backend interface {
 	// CancelUpgrade cancels a pending upgrade, unless it is already in progress.
 	CancelUpgrade(context.Context) error
 
+	// GetPendingUpgrade returns the currently pending upgrade, or nil if
+	// there is no pending upgrade.
+	GetPendingUpgrade(context.Context) (*PendingUpgrade, error)
+
 	// StartupUpgrade performs the startup portion of the upgrade.
 	// It is idempotent with respect to the current upgrade descriptor.
 	StartupUpgrade() error
@@ -21,6 +21,10 @@ func (u *dummyUpgradeManager) CancelUpgrade(ctx context.Context) error {
 	return nil
 }
 
+func (u *dummyUpgradeManager) GetPendingUpgrade(ctx context.Context) (*api.PendingUpgrade, error) {
+	return nil, nil
+}
+
 func (u *dummyUpgradeManager) StartupUpgrade() error {
 	return nil
 }
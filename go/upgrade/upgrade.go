@@ -2,16 +2,27 @@
 //
 // After submitting an upgrade descriptor, the old node may continue
 // running or be restarted up to the point when the consensus layer reaches
-// the upgrade epoch. The new node may not be started until the old node has
-// reached the upgrade epoch.
+// the upgrade boundary (either an epoch or a height). The new node may not be
+// started until the old node has reached the upgrade boundary.
+//
+// An upgrade descriptor can either be submitted via the control API or be
+// provided in the node configuration. If the path to the new binary is also
+// configured, the binary is validated against the descriptor identifier on
+// submission and, once the old node has stopped at the upgrade boundary and is
+// restarted, it automatically hands off execution to the new binary.
 package upgrade
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"sync"
+	"syscall"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/logging"
@@ -22,7 +33,21 @@ import (
 	"github.com/oasislabs/oasis-core/go/upgrade/migrations"
 )
 
+const (
+	// CfgDescriptor is the path to an upgrade descriptor that should be
+	// submitted on node startup.
+	CfgDescriptor = "upgrade.descriptor"
+
+	// CfgBinary is the path to the upgraded node binary. If configured, the
+	// binary is validated against the upgrade descriptor and the node hands
+	// off execution to it once the upgrade boundary has been reached.
+	CfgBinary = "upgrade.binary"
+)
+
 var (
+	// Flags has the configuration flags.
+	Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
 	_ api.Backend = (*upgradeManager)(nil)
 
 	metadataStoreKey = []byte("descriptor")
@@ -36,6 +61,10 @@ func hashSelf() (*hash.Hash, error) {
 		return nil, err
 	}
 
+	return hashFile(path)
+}
+
+func hashFile(path string) (*hash.Hash, error) {
 	contents, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -49,11 +78,21 @@ func makeVersionString() string {
 	return version.SoftwareVersion
 }
 
+func matchesIdentifier(h *hash.Hash, descriptor *api.Descriptor) (bool, error) {
+	var identifier hash.Hash
+	if err := identifier.UnmarshalHex(descriptor.Identifier); err != nil {
+		return false, fmt.Errorf("can't decode upgrade identifier: %w", err)
+	}
+	return h.Equal(&identifier), nil
+}
+
 type upgradeManager struct {
 	store   *persistent.ServiceStore
 	pending *api.PendingUpgrade
 	lock    sync.Mutex
 
+	binaryPath string
+
 	ctx     *migrations.Context
 	handler migrations.Handler
 
@@ -64,9 +103,16 @@ func (u *upgradeManager) SubmitDescriptor(ctx context.Context, descriptor *api.D
 	u.lock.Lock()
 	defer u.lock.Unlock()
 
+	return u.submitDescriptorLocked(descriptor)
+}
+
+func (u *upgradeManager) submitDescriptorLocked(descriptor *api.Descriptor) error {
 	if u.pending != nil {
 		return api.ErrAlreadyPending
 	}
+	if err := u.validateBinary(descriptor); err != nil {
+		return err
+	}
 
 	u.pending = &api.PendingUpgrade{
 		Descriptor: descriptor,
@@ -76,11 +122,98 @@ func (u *upgradeManager) SubmitDescriptor(ctx context.Context, descriptor *api.D
 	u.logger.Info("received upgrade descriptor, scheduling shutdown",
 		"name", u.pending.Descriptor.Name,
 		"epoch", u.pending.Descriptor.Epoch,
+		"height", u.pending.Descriptor.Height,
 	)
 
 	return u.flushDescriptor()
 }
 
+// validateBinary checks that the configured upgrade binary (if any) matches
+// the identifier in the given upgrade descriptor.
+func (u *upgradeManager) validateBinary(descriptor *api.Descriptor) error {
+	if u.binaryPath == "" {
+		return nil
+	}
+
+	binaryHash, err := hashFile(u.binaryPath)
+	if err != nil {
+		return fmt.Errorf("can't hash upgrade binary: %w", err)
+	}
+	ok, err := matchesIdentifier(binaryHash, descriptor)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		u.logger.Error("upgrade binary does not match descriptor identifier",
+			"binary", u.binaryPath,
+			"binary_hash", binaryHash,
+			"identifier", descriptor.Identifier,
+		)
+		return api.ErrInvalidBinary
+	}
+	return nil
+}
+
+// handoff replaces the current process with the configured upgrade binary,
+// passing it the same arguments and environment. It only returns on failure.
+func (u *upgradeManager) handoff() error {
+	if err := u.validateBinary(u.pending.Descriptor); err != nil {
+		return err
+	}
+
+	u.logger.Warn("upgrade boundary reached, handing off to upgrade binary",
+		"name", u.pending.Descriptor.Name,
+		"binary", u.binaryPath,
+	)
+
+	args := append([]string{u.binaryPath}, os.Args[1:]...)
+	if err := syscall.Exec(u.binaryPath, args, os.Environ()); err != nil {
+		return fmt.Errorf("failed to execute upgrade binary: %w", err)
+	}
+	return nil
+}
+
+// submitConfiguredDescriptor submits the upgrade descriptor provided in the
+// node configuration, unless the same upgrade is already pending or this node
+// is already running the upgraded binary.
+func (u *upgradeManager) submitConfiguredDescriptor(path string) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't read upgrade descriptor: %w", err)
+	}
+	var descriptor api.Descriptor
+	if err = json.Unmarshal(raw, &descriptor); err != nil {
+		return fmt.Errorf("can't parse upgrade descriptor: %w", err)
+	}
+	if !descriptor.IsValid() {
+		return fmt.Errorf("upgrade descriptor is not valid")
+	}
+
+	if u.pending != nil {
+		if u.pending.Descriptor.Name == descriptor.Name {
+			return nil
+		}
+		return api.ErrAlreadyPending
+	}
+
+	thisHash, err := hashSelf()
+	if err != nil {
+		return err
+	}
+	upgraded, err := matchesIdentifier(thisHash, &descriptor)
+	if err != nil {
+		return err
+	}
+	if upgraded {
+		u.logger.Info("already running upgraded binary, ignoring configured upgrade descriptor",
+			"name", descriptor.Name,
+		)
+		return nil
+	}
+
+	return u.submitDescriptorLocked(&descriptor)
+}
+
 func (u *upgradeManager) CancelUpgrade(ctx context.Context) error {
 	u.lock.Lock()
 	defer u.lock.Unlock()
@@ -103,6 +236,17 @@ func (u *upgradeManager) CancelUpgrade(ctx context.Context) error {
 	return nil
 }
 
+func (u *upgradeManager) GetPendingUpgrade(ctx context.Context) (*api.PendingUpgrade, error) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if u.pending == nil {
+		return nil, nil
+	}
+	pending := *u.pending
+	return &pending, nil
+}
+
 func (u *upgradeManager) checkStatus() error {
 	var err error
 
@@ -136,12 +280,14 @@ func (u *upgradeManager) checkStatus() error {
 		return err
 	}
 
-	var upgraderHash hash.Hash
-	if err = upgraderHash.UnmarshalHex(u.pending.Descriptor.Identifier); err != nil {
-		return fmt.Errorf("can't decode stored upgrade identifier: %w", err)
+	isUpgrader, err := matchesIdentifier(thisHash, u.pending.Descriptor)
+	if err != nil {
+		return err
 	}
-
-	if !thisHash.Equal(&upgraderHash) {
+	if !isUpgrader {
+		if u.binaryPath != "" {
+			return u.handoff()
+		}
 		return api.ErrUpgradePending
 	}
 
@@ -221,16 +367,21 @@ func (u *upgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEpoch e
 		return nil
 	}
 
-	// If we haven't reached the upgrade epoch yet, we run normally;
+	// If we haven't reached the upgrade boundary yet, we run normally;
 	// startup made sure we're an appropriate binary for that.
 	if u.pending.UpgradeHeight == api.InvalidUpgradeHeight {
-		if currentEpoch < u.pending.Descriptor.Epoch {
+		if !u.pending.Descriptor.IsBoundaryReached(currentEpoch, currentHeight) {
 			return nil
 		}
 		u.pending.UpgradeHeight = currentHeight
 		if err := u.flushDescriptor(); err != nil {
 			return err
 		}
+		u.logger.Warn("reached upgrade boundary, stopping for upgrade",
+			"name", u.pending.Descriptor.Name,
+			"epoch", currentEpoch,
+			"height", currentHeight,
+		)
 		return api.ErrStopForUpgrade
 	}
 
@@ -241,7 +392,7 @@ func (u *upgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEpoch e
 	}
 
 	if u.pending.UpgradeHeight > currentHeight {
-		panic("consensus upgrade: UpgradeHeight is in the future but upgrade epoch seen already")
+		panic("consensus upgrade: UpgradeHeight is in the future but upgrade boundary seen already")
 	}
 
 	if !u.pending.HasStage(api.UpgradeStageConsensus) {
@@ -263,25 +414,42 @@ func (u *upgradeManager) Close() {
 
 // New constructs and returns a new upgrade manager. It also checks for and loads any
 // pending upgrade descriptors; if this node is not the one intended to be run according
-// to the loaded descriptor, New will return an error.
+// to the loaded descriptor, New will either hand off execution to the configured upgrade
+// binary or return an error.
 func New(store *persistent.CommonStore, dataDir string) (api.Backend, error) {
 	svcStore, err := store.GetServiceStore(api.ModuleName)
 	if err != nil {
 		return nil, err
 	}
 	upgrader := &upgradeManager{
-		store:  svcStore,
-		logger: logging.GetLogger(api.ModuleName),
+		store:      svcStore,
+		binaryPath: viper.GetString(CfgBinary),
+		logger:     logging.GetLogger(api.ModuleName),
 	}
 
-	if err := upgrader.checkStatus(); err != nil {
+	if err = upgrader.checkStatus(); err != nil {
 		return nil, err
 	}
 
-	if upgrader.pending != nil {
+	if path := viper.GetString(CfgDescriptor); path != "" {
+		if err = upgrader.submitConfiguredDescriptor(path); err != nil {
+			return nil, fmt.Errorf("failed to submit configured upgrade descriptor: %w", err)
+		}
+	}
+
+	// Migration handlers are only needed once the upgrade boundary has been reached,
+	// by which point this is the binary that knows about them.
+	if upgrader.pending != nil && upgrader.pending.UpgradeHeight != api.InvalidUpgradeHeight {
 		upgrader.ctx = migrations.NewContext(upgrader.pending, dataDir)
 		upgrader.handler = migrations.GetHandler(upgrader.ctx)
 	}
 
 	return upgrader, nil
 }
+
+func init() {
+	Flags.String(CfgDescriptor, "", "Path to an upgrade descriptor to submit on startup")
+	Flags.String(CfgBinary, "", "Path to the upgraded node binary to hand off to at the upgrade boundary")
+
+	_ = viper.BindPFlags(Flags)
+}
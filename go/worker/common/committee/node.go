@@ -7,8 +7,10 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/oasislabs/oasis-core/go/common/debounce"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	keymanagerApi "github.com/oasislabs/oasis-core/go/keymanager/api"
 	keymanagerClient "github.com/oasislabs/oasis-core/go/keymanager/client"
@@ -105,6 +107,8 @@ type Node struct {
 
 	hooks []NodeHooks
 
+	nodeUpdateStableBlocks uint64
+
 	// Mutable and shared between nodes' workers.
 	// Guarded by .CrossNode.
 	CrossNode    sync.Mutex
@@ -351,6 +355,42 @@ func (n *Node) worker() {
 	}
 	defer nodeUpsSub.Close()
 
+	// Node descriptor updates are only applied after they have been stable for
+	// the configured number of blocks, to avoid repeatedly re-establishing
+	// connections in case of rapid successive updates.
+	nodeUpsDebouncer := debounce.NewBlockDebouncer(n.nodeUpdateStableBlocks)
+	var (
+		consensusBlocks <-chan *consensus.Block
+		consensusHeight int64
+	)
+	if nodeUpsDebouncer.Enabled() {
+		var consensusBlocksSub pubsub.ClosableSubscription
+		consensusBlocks, consensusBlocksSub, err = n.Consensus.WatchBlocks(n.ctx)
+		if err != nil {
+			n.logger.Error("failed to subscribe to consensus blocks",
+				"err", err,
+			)
+			return
+		}
+		defer consensusBlocksSub.Close()
+
+		// Seed the height so that early node updates are debounced as well.
+		if blk, err := n.Consensus.GetBlock(n.ctx, consensus.HeightLatest); err == nil {
+			consensusHeight = blk.Height
+		}
+	}
+	handleNodeUpdates := func(ups []interface{}) {
+		if len(ups) == 0 {
+			return
+		}
+
+		n.CrossNode.Lock()
+		defer n.CrossNode.Unlock()
+		for _, up := range ups {
+			n.handleNodeUpdateLocked(up.(*committee.NodeUpdate))
+		}
+	}
+
 	// We are initialized.
 	close(n.initCh)
 
@@ -373,14 +413,20 @@ func (n *Node) worker() {
 				defer n.CrossNode.Unlock()
 				n.handleNewEventLocked(ev)
 			}()
+		case blk := <-consensusBlocks:
+			// Received a consensus block, apply any stable node updates.
+			consensusHeight = blk.Height
+			handleNodeUpdates(nodeUpsDebouncer.Tick(consensusHeight))
 		case up := <-nodeUps:
 			// Received a node update.
-			// TODO: Debounce/batch node updates.
-			func() {
-				n.CrossNode.Lock()
-				defer n.CrossNode.Unlock()
-				n.handleNodeUpdateLocked(up)
-			}()
+			if up.Update == nil {
+				// Committee changes must be applied in order with respect to
+				// any pending node descriptor updates.
+				handleNodeUpdates(append(nodeUpsDebouncer.Flush(), up))
+				continue
+			}
+
+			handleNodeUpdates(nodeUpsDebouncer.Update(up.Update.ID, up, consensusHeight))
 		}
 	}
 }
@@ -391,6 +437,7 @@ func NewNode(
 	keymanager keymanagerApi.Backend,
 	consensus consensus.Backend,
 	p2p *p2p.P2P,
+	nodeUpdateStableBlocks uint64,
 ) (*Node, error) {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(nodeCollectors...)
@@ -409,7 +456,10 @@ func NewNode(
 		stopCh:     make(chan struct{}),
 		quitCh:     make(chan struct{}),
 		initCh:     make(chan struct{}),
-		logger:     logging.GetLogger("worker/common/committee").With("runtime_id", runtime.ID()),
+
+		nodeUpdateStableBlocks: nodeUpdateStableBlocks,
+
+		logger: logging.GetLogger("worker/common/committee").With("runtime_id", runtime.ID()),
 	}
 
 	group, err := NewGroup(ctx, identity, runtime.ID(), n, consensus, p2p)
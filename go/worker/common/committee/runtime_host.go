@@ -31,6 +31,8 @@ type computeRuntimeHostHandler struct {
 	keyManager       keymanagerApi.Backend
 	keyManagerClient *keymanagerClient.Client
 	localStorage     localstorage.LocalStorage
+
+	handlers *hostHandlerRegistry
 }
 
 func (h *computeRuntimeHostHandler) Handle(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
@@ -116,6 +118,10 @@ func (h *computeRuntimeHostHandler) Handle(ctx context.Context, body *protocol.B
 		}
 		return &protocol.Body{HostLocalStorageSetResponse: &protocol.Empty{}}, nil
 	}
+	// Registered handlers.
+	if handler := h.handlers.lookup(body.Type()); handler != nil {
+		return handler.Handle(ctx, body)
+	}
 
 	return nil, errMethodNotSupported
 }
@@ -133,5 +139,6 @@ func (n *Node) NewRuntimeHostHandler() protocol.Handler {
 		n.KeyManager,
 		n.KeyManagerClient,
 		n.Runtime.LocalStorage(),
+		defaultHostHandlers,
 	}
 }
@@ -0,0 +1,81 @@
+package committee
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/oasislabs/oasis-core/go/runtime/host/protocol"
+)
+
+// builtinHostHandlers is the set of host request types handled directly by the compute runtime
+// host handler or by the protocol connection itself. These cannot be overridden by registered
+// handlers.
+var builtinHostHandlers = map[string]bool{
+	"HostLogRequest":              true,
+	"HostKeyManagerPolicyRequest": true,
+	"HostRPCCallRequest":          true,
+	"HostStorageSyncRequest":      true,
+	"HostLocalStorageGetRequest":  true,
+	"HostLocalStorageSetRequest":  true,
+}
+
+// defaultHostHandlers is the registry used by compute runtime host handlers.
+var defaultHostHandlers = newHostHandlerRegistry()
+
+// hostHandlerRegistry is a registry of additional runtime host request handlers, keyed by the
+// request type.
+type hostHandlerRegistry struct {
+	sync.RWMutex
+
+	handlers map[string]protocol.Handler
+}
+
+func (r *hostHandlerRegistry) register(requestType string, handler protocol.Handler) error {
+	if handler == nil {
+		return fmt.Errorf("runtime host: nil handler for request type '%s'", requestType)
+	}
+	if !strings.HasSuffix(requestType, "Request") {
+		return fmt.Errorf("runtime host: '%s' is not a request type", requestType)
+	}
+	if _, ok := reflect.TypeOf(protocol.Body{}).FieldByName(requestType); !ok {
+		return fmt.Errorf("runtime host: unknown request type '%s'", requestType)
+	}
+	if builtinHostHandlers[requestType] {
+		return fmt.Errorf("runtime host: request type '%s' is handled internally", requestType)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.handlers[requestType]; ok {
+		return fmt.Errorf("runtime host: handler for request type '%s' already registered", requestType)
+	}
+	r.handlers[requestType] = handler
+
+	return nil
+}
+
+func (r *hostHandlerRegistry) lookup(requestType string) protocol.Handler {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.handlers[requestType]
+}
+
+func newHostHandlerRegistry() *hostHandlerRegistry {
+	return &hostHandlerRegistry{
+		handlers: make(map[string]protocol.Handler),
+	}
+}
+
+// RegisterRuntimeHostHandler registers an additional handler for runtime host requests of the
+// given type, which is the name of the corresponding protocol.Body field.
+//
+// Registered handlers are used by all compute runtime host handlers created afterwards and
+// should be registered during initialization. Registering a handler for a request type that
+// is handled internally or already has a registered handler is an error.
+func RegisterRuntimeHostHandler(requestType string, handler protocol.Handler) error {
+	return defaultHostHandlers.register(requestType, handler)
+}
@@ -0,0 +1,44 @@
+package committee
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/runtime/host/protocol"
+)
+
+type pingHandler struct {
+	calls int
+}
+
+// Implements protocol.Handler.
+func (h *pingHandler) Handle(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	h.calls++
+	return &protocol.Body{Empty: &protocol.Empty{}}, nil
+}
+
+func TestRuntimeHostHandlerRegistry(t *testing.T) {
+	require := require.New(t)
+
+	registry := newHostHandlerRegistry()
+	ph := &pingHandler{}
+
+	require.NoError(registry.register("RuntimePingRequest", ph), "register")
+	require.Error(registry.register("RuntimePingRequest", ph), "register should fail on conflicts")
+	require.Error(registry.register("HostStorageSyncRequest", ph), "register should fail for built-in handlers")
+	require.Error(registry.register("HostLogRequest", ph), "register should fail for built-in handlers")
+	require.Error(registry.register("HostFooRequest", ph), "register should fail for unknown request types")
+	require.Error(registry.register("HostLogResponse", ph), "register should fail for response types")
+	require.Error(registry.register("RuntimeAbortRequest", nil), "register should fail for nil handlers")
+
+	h := &computeRuntimeHostHandler{handlers: registry}
+	rsp, err := h.Handle(context.Background(), &protocol.Body{RuntimePingRequest: &protocol.Empty{}})
+	require.NoError(err, "Handle")
+	require.NotNil(rsp.Empty, "response should come from the registered handler")
+	require.Equal(1, ph.calls, "registered handler should be called")
+
+	_, err = h.Handle(context.Background(), &protocol.Body{RuntimeAbortRequest: &protocol.Empty{}})
+	require.Equal(errMethodNotSupported, err, "unregistered request types should not be supported")
+}
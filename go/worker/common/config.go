@@ -2,6 +2,8 @@ package common
 
 import (
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	flag "github.com/spf13/pflag"
@@ -10,6 +12,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	ias "github.com/oasislabs/oasis-core/go/ias/api"
 	cmdFlags "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	runtimeHost "github.com/oasislabs/oasis-core/go/runtime/host"
@@ -42,9 +45,22 @@ var (
 	// The value should be a map of runtime IDs to corresponding resource
 	// paths.
 	CfgRuntimeSGXSignatures = "worker.runtime.sgx.signatures"
+	// CfgRuntimeCPULimits configures CPU limits for supported runtimes. The value should be a map
+	// of runtime IDs to the maximum CPU usage as a percentage of a single CPU.
+	CfgRuntimeCPULimits = "worker.runtime.limits.cpu"
+	// CfgRuntimeMemoryLimits configures memory limits for supported runtimes. The value should be
+	// a map of runtime IDs to the maximum amount of memory in bytes.
+	CfgRuntimeMemoryLimits = "worker.runtime.limits.memory"
+	// CfgRuntimeMaxConnections configures the maximum number of Runtime Host Protocol connections
+	// that a hosted runtime may open to the host.
+	CfgRuntimeMaxConnections = "worker.runtime.max_connections"
 
 	cfgStorageCommitTimeout = "worker.storage_commit_timeout"
 
+	// CfgNodeUpdateStableBlocks configures the number of consensus blocks a committee node
+	// descriptor update must remain unchanged for before it is applied.
+	CfgNodeUpdateStableBlocks = "worker.node_update.stable_blocks"
+
 	// Flags has the configuration flags.
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
 )
@@ -76,17 +92,82 @@ type Config struct { // nolint: maligned
 
 	StorageCommitTimeout time.Duration
 
+	// NodeUpdateStableBlocks is the number of consensus blocks a committee node descriptor update
+	// must remain unchanged for before it is applied. Zero means that updates are applied
+	// immediately.
+	NodeUpdateStableBlocks uint64
+
 	logger *logging.Logger
 }
 
 // RuntimeHostConfig is configuration for a worker that hosts runtimes.
 type RuntimeHostConfig struct {
+	sync.RWMutex
+
 	// Provisioners contains a set of supported runtime provisioners, based on TEE hardware.
 	Provisioners map[node.TEEHardware]runtimeHost.Provisioner
 
 	// Runtimes contains per-runtime provisioning configuration. Some fields may be omitted as they
 	// are provided when the runtime is provisioned.
+	//
+	// The configuration may be replaced by Reload, so it must only be accessed while holding the
+	// lock or via GetRuntimeConfig.
 	Runtimes map[common.Namespace]runtimeHost.Config
+
+	reloadNotifier *pubsub.Broker
+}
+
+// GetRuntimeConfig returns a copy of the provisioning configuration template for the given
+// runtime.
+func (c *RuntimeHostConfig) GetRuntimeConfig(id common.Namespace) (runtimeHost.Config, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	cfg, ok := c.Runtimes[id]
+	return cfg, ok
+}
+
+// Reload re-reads the configuration file and updates the per-runtime provisioning configuration.
+// Subscribers to WatchReloads are notified after the configuration has been updated.
+//
+// The set of hosted runtimes and the provisioners cannot be changed without restarting the node.
+func (c *RuntimeHostConfig) Reload() error {
+	if viper.ConfigFileUsed() != "" {
+		if err := viper.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to re-read configuration file: %w", err)
+		}
+	}
+
+	runtimes, err := newRuntimeHostRuntimes()
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if len(runtimes) != len(c.Runtimes) {
+		return fmt.Errorf("set of hosted runtimes cannot be changed without a restart")
+	}
+	for id := range runtimes {
+		if _, ok := c.Runtimes[id]; !ok {
+			return fmt.Errorf("set of hosted runtimes cannot be changed without a restart")
+		}
+	}
+	c.Runtimes = runtimes
+
+	c.reloadNotifier.Broadcast(struct{}{})
+
+	return nil
+}
+
+// WatchReloads subscribes to runtime host configuration reloads.
+func (c *RuntimeHostConfig) WatchReloads() (<-chan struct{}, pubsub.ClosableSubscription) {
+	typedCh := make(chan struct{})
+	sub := c.reloadNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
 }
 
 // GetNodeAddresses returns worker node addresses.
@@ -114,6 +195,76 @@ func (c *Config) GetNodeAddresses() ([]node.Address, error) {
 	return addresses, nil
 }
 
+func parseRuntimeLimits(cfgFlag string) (map[string]uint64, error) {
+	limits := make(map[string]uint64)
+	for runtimeID, v := range viper.GetStringMapString(cfgFlag) {
+		limit, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s value for runtime '%s': %w", cfgFlag, runtimeID, err)
+		}
+		limits[runtimeID] = limit
+	}
+	return limits, nil
+}
+
+// newRuntimeHostRuntimes creates the per-runtime provisioning configuration.
+func newRuntimeHostRuntimes() (map[common.Namespace]runtimeHost.Config, error) {
+	runtimeSGXSignatures := viper.GetStringMapString(CfgRuntimeSGXSignatures)
+	runtimeCPULimits, err := parseRuntimeLimits(CfgRuntimeCPULimits)
+	if err != nil {
+		return nil, err
+	}
+	runtimeMemoryLimits, err := parseRuntimeLimits(CfgRuntimeMemoryLimits)
+	if err != nil {
+		return nil, err
+	}
+	runtimePaths := viper.GetStringMapString(CfgRuntimePaths)
+	for _, limits := range []map[string]uint64{runtimeCPULimits, runtimeMemoryLimits} {
+		for runtimeID := range limits {
+			if _, ok := runtimePaths[runtimeID]; !ok {
+				return nil, fmt.Errorf("resource limits configured for unknown runtime '%s'", runtimeID)
+			}
+		}
+	}
+
+	runtimes := make(map[common.Namespace]runtimeHost.Config)
+	for runtimeID, path := range runtimePaths {
+		var id common.Namespace
+		if err = id.UnmarshalHex(runtimeID); err != nil {
+			return nil, fmt.Errorf("bad runtime identifier '%s': %w", runtimeID, err)
+		}
+
+		runtimeHostCfg := runtimeHost.Config{
+			RuntimeID: id,
+			Path:      path,
+			Limits: runtimeHost.ResourceLimits{
+				CPUPercent: runtimeCPULimits[runtimeID],
+				Memory:     runtimeMemoryLimits[runtimeID],
+			},
+		}
+
+		// This config is SGX specific, but that's all that's supported
+		// right now that needs this anyway, the non-SGX provisioner
+		// currently ignores this.
+		if sigPath := runtimeSGXSignatures[runtimeID]; sigPath != "" {
+			runtimeHostCfg.Extra = &hostSgx.RuntimeExtra{
+				SignaturePath: sigPath,
+			}
+		} else {
+			// HACK HACK HACK: Allow dummy SIGSTRUCT generation.
+			runtimeHostCfg.Extra = &hostSgx.RuntimeExtra{
+				UnsafeDebugGenerateSigstruct: true,
+			}
+		}
+
+		runtimes[id] = runtimeHostCfg
+	}
+	if len(runtimes) == 0 {
+		return nil, fmt.Errorf("no runtimes configured")
+	}
+	return runtimes, nil
+}
+
 // NewConfig creates a new worker config.
 func NewConfig(ias ias.Endpoint) (*Config, error) {
 	// Parse register address overrides.
@@ -133,11 +284,12 @@ func NewConfig(ias ias.Endpoint) (*Config, error) {
 	}
 
 	cfg := Config{
-		ClientPort:           uint16(viper.GetInt(CfgClientPort)),
-		ClientAddresses:      clientAddresses,
-		SentryAddresses:      sentryAddresses,
-		StorageCommitTimeout: viper.GetDuration(cfgStorageCommitTimeout),
-		logger:               logging.GetLogger("worker/config"),
+		ClientPort:             uint16(viper.GetInt(CfgClientPort)),
+		ClientAddresses:        clientAddresses,
+		SentryAddresses:        sentryAddresses,
+		StorageCommitTimeout:   viper.GetDuration(cfgStorageCommitTimeout),
+		NodeUpdateStableBlocks: viper.GetUint64(CfgNodeUpdateStableBlocks),
+		logger:                 logging.GetLogger("worker/config"),
 	}
 
 	// Check if any runtimes are configured to be hosted.
@@ -146,6 +298,7 @@ func NewConfig(ias ias.Endpoint) (*Config, error) {
 
 		// Register provisioners based on the configured provisioner.
 		var insecureNoSandbox bool
+		maxConnections := uint16(viper.GetUint(CfgRuntimeMaxConnections))
 		rh.Provisioners = make(map[node.TEEHardware]runtimeHost.Provisioner)
 		switch p := viper.GetString(CfgRuntimeProvisioner); p {
 		case RuntimeProvisionerMock:
@@ -168,6 +321,7 @@ func NewConfig(ias ias.Endpoint) (*Config, error) {
 			// Sandboxed provisioner, can be used with no TEE or with Intel SGX.
 			rh.Provisioners[node.TEEHardwareInvalid], err = hostSandbox.New(hostSandbox.Config{
 				InsecureNoSandbox: insecureNoSandbox,
+				MaxConnections:    maxConnections,
 			})
 			if err != nil {
 				return nil, fmt.Errorf("failed to create runtime provisioner: %w", err)
@@ -177,6 +331,7 @@ func NewConfig(ias ias.Endpoint) (*Config, error) {
 				LoaderPath:        viper.GetString(CfgRuntimeSGXLoader),
 				IAS:               ias,
 				InsecureNoSandbox: insecureNoSandbox,
+				MaxConnections:    maxConnections,
 			})
 			if err != nil {
 				return nil, fmt.Errorf("failed to create SGX runtime provisioner: %w", err)
@@ -186,38 +341,10 @@ func NewConfig(ias ias.Endpoint) (*Config, error) {
 		}
 
 		// Configure runtimes.
-		runtimeSGXSignatures := viper.GetStringMapString(CfgRuntimeSGXSignatures)
-		rh.Runtimes = make(map[common.Namespace]runtimeHost.Config)
-		for runtimeID, path := range viper.GetStringMapString(CfgRuntimePaths) {
-			var id common.Namespace
-			if err := id.UnmarshalHex(runtimeID); err != nil {
-				return nil, fmt.Errorf("bad runtime identifier '%s': %w", runtimeID, err)
-			}
-
-			runtimeHostCfg := runtimeHost.Config{
-				RuntimeID: id,
-				Path:      path,
-			}
-
-			// This config is SGX specific, but that's all that's supported
-			// right now that needs this anyway, the non-SGX provisioner
-			// currently ignores this.
-			if sigPath := runtimeSGXSignatures[runtimeID]; sigPath != "" {
-				runtimeHostCfg.Extra = &hostSgx.RuntimeExtra{
-					SignaturePath: sigPath,
-				}
-			} else {
-				// HACK HACK HACK: Allow dummy SIGSTRUCT generation.
-				runtimeHostCfg.Extra = &hostSgx.RuntimeExtra{
-					UnsafeDebugGenerateSigstruct: true,
-				}
-			}
-
-			rh.Runtimes[id] = runtimeHostCfg
-		}
-		if len(rh.Runtimes) == 0 {
-			return nil, fmt.Errorf("no runtimes configured")
+		if rh.Runtimes, err = newRuntimeHostRuntimes(); err != nil {
+			return nil, err
 		}
+		rh.reloadNotifier = pubsub.NewBroker(false)
 
 		cfg.RuntimeHost = &rh
 	}
@@ -234,8 +361,13 @@ func init() {
 	Flags.String(CfgRuntimeSGXLoader, "", "(for SGX runtimes) Path to SGXS runtime loader binary")
 	Flags.StringToString(CfgRuntimePaths, nil, "Paths to runtime resources (format: <rt1-ID>=<path>,<rt2-ID>=<path>)")
 	Flags.StringToString(CfgRuntimeSGXSignatures, nil, "(for SGX runtimes) Paths to signatures (format: <rt1-ID>=<path>,<rt2-ID>=<path>")
+	Flags.StringToString(CfgRuntimeCPULimits, nil, "Maximum CPU usage of runtimes as a percentage of a single CPU (format: <rt1-ID>=<percent>,<rt2-ID>=<percent>)")
+	Flags.StringToString(CfgRuntimeMemoryLimits, nil, "Maximum memory usage of runtimes in bytes (format: <rt1-ID>=<bytes>,<rt2-ID>=<bytes>)")
+	Flags.Uint16(CfgRuntimeMaxConnections, 4, "Maximum number of Runtime Host Protocol connections a runtime may open")
 
 	Flags.Duration(cfgStorageCommitTimeout, 5*time.Second, "Storage commit timeout")
 
+	Flags.Uint64(CfgNodeUpdateStableBlocks, 0, "Number of blocks a committee node descriptor update must be stable for before being applied (0 to apply immediately)")
+
 	_ = viper.BindPFlags(Flags)
 }
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	runtimeHost "github.com/oasislabs/oasis-core/go/runtime/host"
+)
+
+func TestRuntimeHostConfigReload(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		rtID1 = "8000000000000000000000000000000000000000000000000000000000000000"
+		rtID2 = "8000000000000000000000000000000000000000000000000000000000000001"
+	)
+	var id1 common.Namespace
+	require.NoError(id1.UnmarshalHex(rtID1), "UnmarshalHex")
+
+	viper.Set(CfgRuntimePaths, map[string]string{rtID1: "/path/to/runtime"})
+	defer viper.Set(CfgRuntimePaths, map[string]string{})
+
+	runtimes, err := newRuntimeHostRuntimes()
+	require.NoError(err, "newRuntimeHostRuntimes")
+	cfg := &RuntimeHostConfig{
+		Runtimes:       runtimes,
+		reloadNotifier: pubsub.NewBroker(false),
+	}
+
+	ch, sub := cfg.WatchReloads()
+	defer sub.Close()
+
+	// Changing the runtime configuration should be picked up.
+	viper.Set(CfgRuntimePaths, map[string]string{rtID1: "/path/to/new/runtime"})
+	require.NoError(cfg.Reload(), "Reload")
+	<-ch
+
+	var rtCfg runtimeHost.Config
+	var ok bool
+	rtCfg, ok = cfg.GetRuntimeConfig(id1)
+	require.True(ok, "GetRuntimeConfig")
+	require.Equal("/path/to/new/runtime", rtCfg.Path, "runtime path should be updated")
+
+	// Changing the set of runtimes should be rejected.
+	viper.Set(CfgRuntimePaths, map[string]string{rtID2: "/path/to/runtime"})
+	require.Error(cfg.Reload(), "Reload should fail when the set of runtimes changes")
+
+	rtCfg, ok = cfg.GetRuntimeConfig(id1)
+	require.True(ok, "GetRuntimeConfig")
+	require.Equal("/path/to/new/runtime", rtCfg.Path, "runtime configuration should be kept")
+}
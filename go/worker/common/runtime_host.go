@@ -3,13 +3,22 @@ package common
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	"github.com/oasislabs/oasis-core/go/runtime/host"
 	"github.com/oasislabs/oasis-core/go/runtime/host/protocol"
 	runtimeRegistry "github.com/oasislabs/oasis-core/go/runtime/registry"
 )
 
+// reloadStartTimeout is the maximum amount of time a reloaded runtime instance has to start
+// before the reload is abandoned and the old instance is kept.
+const reloadStartTimeout = 5 * time.Minute
+
 // RuntimeHostNode provides methods for nodes that need to host runtimes.
 type RuntimeHostNode struct {
 	sync.Mutex
@@ -24,7 +33,37 @@ type RuntimeHostNode struct {
 //
 // This method may return before the runtime is fully provisioned. The returned runtime will not be
 // started automatically, you must call Start explicitly.
+//
+// Once started, the returned runtime is re-provisioned whenever the runtime host configuration is
+// reloaded and the configuration of the runtime has changed. The new instance replaces the old one
+// after it has started and the old instance is stopped once all in-flight calls have completed.
 func (n *RuntimeHostNode) ProvisionHostedRuntime(ctx context.Context) (host.Runtime, error) {
+	inst, err := n.provisionInstance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rr := &reloadableRuntime{
+		node:     n,
+		ctx:      ctx,
+		current:  inst,
+		notifier: pubsub.NewBroker(false),
+		stopCh:   make(chan struct{}),
+		logger:   logging.GetLogger("worker/common/runtime-host").With("runtime_id", inst.cfg.RuntimeID),
+	}
+	if err = rr.watchInstance(inst); err != nil {
+		return nil, err
+	}
+
+	n.Lock()
+	n.runtime = rr
+	n.Unlock()
+
+	return rr, nil
+}
+
+// provisionInstance provisions a new instance of the runtime using the current configuration.
+func (n *RuntimeHostNode) provisionInstance(ctx context.Context) (*runtimeInstance, error) {
 	rt, err := n.factory.GetRuntime().RegistryDescriptor(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get runtime registry descriptor: %w", err)
@@ -36,10 +75,11 @@ func (n *RuntimeHostNode) ProvisionHostedRuntime(ctx context.Context) (host.Runt
 	}
 
 	// Get a copy of the configuration template for the given runtime and apply updates.
-	cfg, ok := n.cfg.Runtimes[rt.ID]
+	cfg, ok := n.cfg.GetRuntimeConfig(rt.ID)
 	if !ok {
 		return nil, fmt.Errorf("missing runtime host configuration for runtime '%s'", rt.ID)
 	}
+	tmpl := cfg
 	cfg.MessageHandler = n.factory.NewRuntimeHostHandler()
 
 	// Provision the runtime.
@@ -48,11 +88,12 @@ func (n *RuntimeHostNode) ProvisionHostedRuntime(ctx context.Context) (host.Runt
 		return nil, fmt.Errorf("failed to provision runtime: %w", err)
 	}
 
-	n.Lock()
-	n.runtime = prt
-	n.Unlock()
-
-	return prt, nil
+	return &runtimeInstance{
+		Runtime: prt,
+		cfg:     tmpl,
+		startCh: make(chan *host.Event, 1),
+		quitCh:  make(chan struct{}),
+	}, nil
 }
 
 // GetHostedRuntime returns the provisioned hosted runtime (if any).
@@ -84,3 +125,225 @@ func NewRuntimeHostNode(cfg *RuntimeHostConfig, factory RuntimeHostHandlerFactor
 		factory: factory,
 	}, nil
 }
+
+// runtimeInstance is a provisioned instance of a reloadable runtime.
+type runtimeInstance struct {
+	host.Runtime
+
+	// cfg is the configuration template the instance was provisioned with.
+	cfg host.Config
+
+	// calls tracks the in-flight calls, so the instance can be drained.
+	calls sync.WaitGroup
+
+	// startCh receives the outcome of starting the instance while it is pending.
+	startCh chan *host.Event
+	// quitCh is closed once the instance has been stopped.
+	quitCh   chan struct{}
+	quitOnce sync.Once
+}
+
+func (inst *runtimeInstance) stop() {
+	inst.quitOnce.Do(func() {
+		inst.Runtime.Stop()
+		close(inst.quitCh)
+	})
+}
+
+// reloadableRuntime is a hosted runtime that is re-provisioned on configuration reloads.
+type reloadableRuntime struct {
+	sync.RWMutex
+
+	node *RuntimeHostNode
+	ctx  context.Context
+
+	current  *runtimeInstance
+	notifier *pubsub.Broker
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+
+	logger *logging.Logger
+}
+
+func (rr *reloadableRuntime) getCurrent() *runtimeInstance {
+	rr.RLock()
+	defer rr.RUnlock()
+	return rr.current
+}
+
+// Implements host.Runtime.
+func (rr *reloadableRuntime) ID() common.Namespace {
+	return rr.getCurrent().ID()
+}
+
+// Implements host.Runtime.
+func (rr *reloadableRuntime) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	rr.RLock()
+	inst := rr.current
+	inst.calls.Add(1)
+	rr.RUnlock()
+	defer inst.calls.Done()
+
+	return inst.Call(ctx, body)
+}
+
+// Implements host.Runtime.
+func (rr *reloadableRuntime) WatchEvents(ctx context.Context) (<-chan *host.Event, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan *host.Event)
+	sub := rr.notifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
+// Implements host.Runtime.
+func (rr *reloadableRuntime) Start() error {
+	if err := rr.getCurrent().Start(); err != nil {
+		return err
+	}
+
+	rr.startOnce.Do(func() {
+		go rr.watchReloads()
+	})
+	return nil
+}
+
+// Implements host.Runtime.
+func (rr *reloadableRuntime) Restart(ctx context.Context) error {
+	return rr.getCurrent().Restart(ctx)
+}
+
+// Implements host.Runtime.
+func (rr *reloadableRuntime) Stop() {
+	rr.stopOnce.Do(func() {
+		close(rr.stopCh)
+	})
+
+	rr.getCurrent().stop()
+}
+
+// watchInstance forwards the events of the given instance while it is the current instance.
+func (rr *reloadableRuntime) watchInstance(inst *runtimeInstance) error {
+	ch, sub, err := inst.WatchEvents(rr.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to runtime events: %w", err)
+	}
+
+	go func() {
+		defer sub.Close()
+
+		for {
+			select {
+			case <-inst.quitCh:
+				return
+			case ev := <-ch:
+				rr.RLock()
+				if rr.current == inst {
+					rr.notifier.Broadcast(ev)
+					rr.RUnlock()
+					continue
+				}
+				rr.RUnlock()
+
+				// The instance is either still pending or has been replaced.
+				if ev.Started != nil || ev.FailedToStart != nil {
+					select {
+					case inst.startCh <- ev:
+					default:
+					}
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (rr *reloadableRuntime) watchReloads() {
+	ch, sub := rr.node.cfg.WatchReloads()
+	defer sub.Close()
+
+	for {
+		select {
+		case <-rr.stopCh:
+			return
+		case <-rr.ctx.Done():
+			return
+		case <-ch:
+			if err := rr.reload(); err != nil {
+				rr.logger.Error("failed to reload runtime, keeping the current instance",
+					"err", err,
+				)
+			}
+		}
+	}
+}
+
+// reload re-provisions the runtime if its configuration has changed and replaces the current
+// instance once the new instance has started.
+func (rr *reloadableRuntime) reload() error {
+	old := rr.getCurrent()
+
+	cfg, ok := rr.node.cfg.GetRuntimeConfig(old.cfg.RuntimeID)
+	if !ok {
+		return fmt.Errorf("missing runtime host configuration for runtime '%s'", old.cfg.RuntimeID)
+	}
+	if reflect.DeepEqual(cfg, old.cfg) {
+		rr.logger.Debug("runtime configuration unchanged, not reloading")
+		return nil
+	}
+
+	rr.logger.Info("runtime configuration changed, provisioning new instance",
+		"path", cfg.Path,
+	)
+
+	inst, err := rr.node.provisionInstance(rr.ctx)
+	if err != nil {
+		return err
+	}
+	if err = rr.watchInstance(inst); err != nil {
+		return err
+	}
+	if err = inst.Start(); err != nil {
+		inst.stop()
+		return fmt.Errorf("failed to start runtime: %w", err)
+	}
+
+	var started *host.Event
+	select {
+	case ev := <-inst.startCh:
+		if ev.FailedToStart != nil {
+			inst.stop()
+			return fmt.Errorf("runtime failed to start: %w", ev.FailedToStart.Error)
+		}
+		started = ev
+	case <-time.After(reloadStartTimeout):
+		inst.stop()
+		return fmt.Errorf("runtime failed to start in %s", reloadStartTimeout)
+	case <-rr.stopCh:
+		inst.stop()
+		return nil
+	}
+
+	// Switch to the new instance, so that new calls are routed to it, and notify the subscribers
+	// that the (new version of the) runtime has started.
+	rr.Lock()
+	rr.current = inst
+	rr.notifier.Broadcast(started)
+	rr.Unlock()
+
+	rr.logger.Info("switched to new runtime instance, draining the old instance",
+		"version", started.Started.Version,
+	)
+
+	// Gracefully drain the old instance.
+	go func() {
+		old.calls.Wait()
+		old.stop()
+
+		rr.logger.Info("old runtime instance stopped")
+	}()
+
+	return nil
+}
@@ -182,6 +182,7 @@ func (w *Worker) NewUnmanagedCommitteeNode(runtime runtimeRegistry.Runtime, enab
 		w.KeyManager,
 		w.Consensus,
 		p2p,
+		w.cfg.NodeUpdateStableBlocks,
 	)
 }
 
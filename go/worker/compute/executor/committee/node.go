@@ -33,6 +33,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/worker/common/p2p"
 	mergeCommittee "github.com/oasislabs/oasis-core/go/worker/compute/merge/committee"
 	"github.com/oasislabs/oasis-core/go/worker/registration"
+	storageCommittee "github.com/oasislabs/oasis-core/go/worker/storage/committee"
 )
 
 var (
@@ -96,6 +97,20 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	speculativeBatchCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_speculative_batch_count",
+			Help: "Number of batches processed speculatively.",
+		},
+		[]string{"runtime"},
+	)
+	discardedSpeculativeBatchCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_discarded_speculative_batch_count",
+			Help: "Number of speculatively processed batches that have been discarded.",
+		},
+		[]string{"runtime"},
+	)
 	nodeCollectors = []prometheus.Collector{
 		discrepancyDetectedCount,
 		abortedBatchCount,
@@ -104,6 +119,8 @@ var (
 		batchProcessingTime,
 		batchRuntimeProcessingTime,
 		batchSize,
+		speculativeBatchCount,
+		discardedSpeculativeBatchCount,
 	}
 
 	metricsOnce sync.Once
@@ -115,10 +132,16 @@ type Node struct {
 
 	commonNode *committee.Node
 	mergeNode  *mergeCommittee.Node
+	// storageNode is the local storage worker node for the runtime, if any.
+	storageNode *storageCommittee.Node
 
 	commonCfg    commonWorker.Config
 	roleProvider registration.RoleProvider
 
+	// speculativeExecution enables speculative processing of the next batch
+	// while waiting for the current round to be finalized.
+	speculativeExecution bool
+
 	ctx       context.Context
 	cancelCtx context.CancelFunc
 	stopCh    chan struct{}
@@ -360,6 +383,38 @@ func (n *Node) HandleEpochTransitionLocked(epoch *committee.EpochSnapshot) {
 // Guarded by n.commonNode.CrossNode.
 func (n *Node) HandleNewBlockEarlyLocked(blk *block.Block) {
 	crash.Here(crashPointRoothashReceiveAfter)
+
+	// A batch that is being processed speculatively remains valid in case the
+	// new block is the one that it is being processed against.
+	switch state := n.state.(type) {
+	case StateProcessingBatch:
+		if !state.speculative {
+			break
+		}
+		if !blk.Header.MostlyEqual(&state.block.Header) {
+			n.discardSpeculativeBatchLocked(&state)
+			return
+		}
+
+		n.logger.Info("speculatively processed batch confirmed",
+			"round", blk.Header.Round,
+		)
+		batchProcessingTime.With(n.getMetricLabels()).Observe(time.Since(state.prevBatchStartTime).Seconds())
+
+		state.block = blk
+		state.speculative = false
+		n.transitionLocked(state)
+		return
+	case StateWaitingForConfirmation:
+		if !blk.Header.MostlyEqual(&state.processing.block.Header) {
+			n.discardSpeculativeBatchLocked(&state.processing)
+			return
+		}
+
+		// The batch will be proposed once the new block has been processed.
+		return
+	}
+
 	// If we have seen a new block while a batch was processing, we need to
 	// abort it no matter what as any processed state may be invalid.
 	n.abortBatchLocked(errSeenNewerBlock)
@@ -370,6 +425,12 @@ func (n *Node) HandleNewBlockEarlyLocked(blk *block.Block) {
 func (n *Node) HandleNewBlockLocked(blk *block.Block) {
 	header := blk.Header
 
+	// The next round will be executed on top of the state of this block, so
+	// ask the local storage node (if any) to sync it before backfilling.
+	if n.storageNode != nil && n.commonNode.Group.GetEpochSnapshot().IsExecutorMember() {
+		n.storageNode.PrioritizeRound(header.Round)
+	}
+
 	// Perform actions based on current state.
 	switch state := n.state.(type) {
 	case StateWaitingForBlock:
@@ -413,6 +474,19 @@ func (n *Node) HandleNewBlockLocked(blk *block.Block) {
 
 		// Record time taken for successfully processing a batch.
 		batchProcessingTime.With(n.getMetricLabels()).Observe(time.Since(state.batchStartTime).Seconds())
+	case StateWaitingForConfirmation:
+		// The block that the batch has been processed against has been
+		// finalized, so the speculatively computed results can be proposed.
+		n.logger.Info("speculatively processed batch confirmed, proposing",
+			"round", blk.Header.Round,
+		)
+		batchProcessingTime.With(n.getMetricLabels()).Observe(time.Since(state.processing.prevBatchStartTime).Seconds())
+
+		processing := state.processing
+		processing.block = blk
+		processing.speculative = false
+		n.transitionLocked(processing)
+		n.proposeBatchLocked(&processing, state.result)
 	}
 }
 
@@ -465,8 +539,22 @@ func (n *Node) startProcessingBatchLocked(
 		panic("attempted to start processing batch with a nil block")
 	}
 
+	n.processBatchLocked(ioRoot, batch, batchSpanCtx, txnSchedSig, inputStorageSigs, n.commonNode.CurrentBlock, nil)
+}
+
+// Guarded by n.commonNode.CrossNode.
+func (n *Node) processBatchLocked(
+	ioRoot hash.Hash,
+	batch transaction.RawBatch,
+	batchSpanCtx opentracing.SpanContext,
+	txnSchedSig signature.Signature,
+	inputStorageSigs []signature.Signature,
+	blk *block.Block,
+	pendingFinalize *StateWaitingForFinalize,
+) {
 	n.logger.Debug("processing batch",
 		"batch", batch,
+		"speculative", pendingFinalize != nil,
 	)
 
 	// Create batch processing context and channel for receiving the response.
@@ -477,13 +565,28 @@ func (n *Node) startProcessingBatchLocked(
 		RuntimeExecuteTxBatchRequest: &protocol.RuntimeExecuteTxBatchRequest{
 			IORoot: ioRoot,
 			Inputs: batch,
-			Block:  *n.commonNode.CurrentBlock,
+			Block:  *blk,
 		},
 	}
 
-	batchStartTime := time.Now()
+	state := StateProcessingBatch{
+		ioRoot:           ioRoot,
+		batch:            batch,
+		batchSpanCtx:     batchSpanCtx,
+		batchStartTime:   time.Now(),
+		cancelFn:         cancel,
+		done:             done,
+		txnSchedSig:      txnSchedSig,
+		inputStorageSigs: inputStorageSigs,
+		block:            blk,
+	}
+	if pendingFinalize != nil {
+		state.speculative = true
+		state.prevBatchStartTime = pendingFinalize.batchStartTime
+	}
+
 	batchSize.With(n.getMetricLabels()).Observe(float64(len(batch)))
-	n.transitionLocked(StateProcessingBatch{ioRoot, batch, batchSpanCtx, batchStartTime, cancel, done, txnSchedSig, inputStorageSigs})
+	n.transitionLocked(state)
 
 	rt := n.GetHostedRuntime()
 	if rt == nil {
@@ -566,18 +669,82 @@ func (n *Node) abortBatchLocked(reason error) {
 
 	abortedBatchCount.With(n.getMetricLabels()).Inc()
 
+	// In case the batch was being processed speculatively, the round that we
+	// are waiting for is still the previous one.
+	batchStartTime := state.batchStartTime
+	if state.speculative {
+		batchStartTime = state.prevBatchStartTime
+	}
+
 	// After the batch has been aborted, we must wait for the round to be
 	// finalized.
 	n.transitionLocked(StateWaitingForFinalize{
-		batchStartTime: state.batchStartTime,
+		batchStartTime: batchStartTime,
 	})
 }
 
 // Guarded by n.commonNode.CrossNode.
-func (n *Node) proposeBatchLocked(batch *protocol.ComputedBatch) {
-	// We must be in ProcessingBatch state if we are here.
-	state := n.state.(StateProcessingBatch)
+func (n *Node) discardSpeculativeBatchLocked(state *StateProcessingBatch) {
+	n.logger.Warn("discarding speculatively processed batch as a different block has been finalized",
+		"expected_round", state.block.Header.Round,
+	)
+
+	// Cancel the batch processing context (if still running) and wait for it
+	// to finish.
+	state.cancel()
+
+	discardedSpeculativeBatchCount.With(n.getMetricLabels()).Inc()
+
+	// The round that the speculative batch has been waiting for has been
+	// finalized.
+	batchProcessingTime.With(n.getMetricLabels()).Observe(time.Since(state.prevBatchStartTime).Seconds())
+
+	n.transitionLocked(StateWaitingForBatch{})
+}
+
+// Guarded by n.commonNode.CrossNode.
+func (n *Node) startSpeculativeBatchLocked(
+	state *StateWaitingForFinalize,
+	ioRoot hash.Hash,
+	batch transaction.RawBatch,
+	batchSpanCtx opentracing.SpanContext,
+	hdr block.Header,
+	txnSchedSig signature.Signature,
+	inputStorageSigs []signature.Signature,
+) error {
+	// Backup workers only process batches after a discrepancy is detected.
+	epoch := n.commonNode.Group.GetEpochSnapshot()
+	if !epoch.IsExecutorWorker() {
+		return errIncorrectState
+	}
 
+	// The batch can only be processed speculatively in case it is based on the
+	// block that is expected to be produced by our proposed results.
+	lastHeader := n.commonNode.CurrentBlock.Header
+	proposed := state.proposedHeader
+	if proposed == nil ||
+		hdr.HeaderType != block.Normal ||
+		hdr.Round != lastHeader.Round+1 ||
+		!hdr.IsParentOf(&lastHeader) ||
+		!hdr.IORoot.Equal(&proposed.IORoot) ||
+		!hdr.StateRoot.Equal(&proposed.StateRoot) {
+		n.logger.Debug("not speculatively processing batch based on unexpected header",
+			"header", hdr,
+		)
+		return errIncorrectState
+	}
+
+	n.logger.Info("speculatively processing batch before round finalization",
+		"round", hdr.Round,
+	)
+	speculativeBatchCount.With(n.getMetricLabels()).Inc()
+
+	n.processBatchLocked(ioRoot, batch, batchSpanCtx, txnSchedSig, inputStorageSigs, &block.Block{Header: hdr}, state)
+	return nil
+}
+
+// Guarded by n.commonNode.CrossNode.
+func (n *Node) proposeBatchLocked(state *StateProcessingBatch, batch *protocol.ComputedBatch) {
 	crash.Here(crashPointBatchProposeBefore)
 
 	n.logger.Debug("proposing batch",
@@ -607,7 +774,7 @@ func (n *Node) proposeBatchLocked(batch *protocol.ComputedBatch) {
 		ctx, cancel := context.WithTimeout(ctx, n.commonCfg.StorageCommitTimeout)
 		defer cancel()
 
-		lastHeader := n.commonNode.CurrentBlock.Header
+		lastHeader := state.block.Header
 
 		// NOTE: Order is important for verifying the receipt.
 		applyOps := []storage.ApplyOp{
@@ -708,6 +875,7 @@ func (n *Node) proposeBatchLocked(batch *protocol.ComputedBatch) {
 
 	n.transitionLocked(StateWaitingForFinalize{
 		batchStartTime: state.batchStartTime,
+		proposedHeader: &proposedResults.Header,
 	})
 
 	if epoch.IsMergeMember() {
@@ -791,8 +959,18 @@ func (n *Node) handleExternalBatchLocked(
 	txnSchedSig signature.Signature,
 	inputStorageSigs []signature.Signature,
 ) error {
-	// If we are not waiting for a batch, don't do anything.
-	if _, ok := n.state.(StateWaitingForBatch); !ok {
+	// If we are not waiting for a batch, don't do anything unless we can
+	// process the batch speculatively while waiting for the round to be
+	// finalized.
+	var pendingFinalize *StateWaitingForFinalize
+	switch state := n.state.(type) {
+	case StateWaitingForBatch:
+	case StateWaitingForFinalize:
+		if !n.speculativeExecution {
+			return errIncorrectState
+		}
+		pendingFinalize = &state
+	default:
 		return errIncorrectState
 	}
 
@@ -814,6 +992,10 @@ func (n *Node) handleExternalBatchLocked(
 		return nil
 	}
 
+	if pendingFinalize != nil {
+		return n.startSpeculativeBatchLocked(pendingFinalize, ioRoot, batch, batchSpanCtx, hdr, txnSchedSig, inputStorageSigs)
+	}
+
 	// Check if we have the correct block -- in this case, start processing the batch.
 	if n.commonNode.CurrentBlock.Header.MostlyEqual(&hdr) {
 		n.maybeStartProcessingBatchLocked(ioRoot, batch, batchSpanCtx, txnSchedSig, inputStorageSigs)
@@ -929,6 +1111,11 @@ func (n *Node) worker() {
 			case ev.FailedToStart != nil, ev.Stopped != nil:
 				// Runtime failed to start or was stopped -- we can no longer service requests.
 				n.roleProvider.SetUnavailable()
+			case ev.LimitExceeded != nil:
+				// Runtime was killed due to exceeding its resource limits, a stop event will follow.
+				n.logger.Error("runtime exceeded its resource limits",
+					"resource", ev.LimitExceeded.Resource,
+				)
 			default:
 				// Unknown event.
 				n.logger.Warn("unknown worker event",
@@ -959,10 +1146,22 @@ func (n *Node) worker() {
 				defer n.commonNode.CrossNode.Unlock()
 
 				// To avoid stale events, check if the stored state is still valid.
-				if state, ok := n.state.(StateProcessingBatch); !ok || state.done != processingDoneCh {
+				state, ok := n.state.(StateProcessingBatch)
+				if !ok || state.done != processingDoneCh {
+					return
+				}
+
+				// Speculatively processed batches can only be proposed once the
+				// block they were processed against has been finalized.
+				if state.speculative {
+					n.logger.Info("waiting for round finalization to confirm speculatively processed batch")
+					n.transitionLocked(StateWaitingForConfirmation{
+						processing: state,
+						result:     batch,
+					})
 					return
 				}
-				n.proposeBatchLocked(batch)
+				n.proposeBatchLocked(&state, batch)
 			}()
 		case <-n.reselect:
 			// Recalculate select set.
@@ -973,8 +1172,10 @@ func (n *Node) worker() {
 func NewNode(
 	commonNode *committee.Node,
 	mergeNode *mergeCommittee.Node,
+	storageNode *storageCommittee.Node,
 	commonCfg commonWorker.Config,
 	roleProvider registration.RoleProvider,
+	speculativeExecution bool,
 ) (*Node, error) {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(nodeCollectors...)
@@ -989,20 +1190,22 @@ func NewNode(
 	ctx, cancel := context.WithCancel(context.Background())
 
 	n := &Node{
-		RuntimeHostNode:  rhn,
-		commonNode:       commonNode,
-		mergeNode:        mergeNode,
-		commonCfg:        commonCfg,
-		roleProvider:     roleProvider,
-		ctx:              ctx,
-		cancelCtx:        cancel,
-		stopCh:           make(chan struct{}),
-		quitCh:           make(chan struct{}),
-		initCh:           make(chan struct{}),
-		state:            StateNotReady{},
-		stateTransitions: pubsub.NewBroker(false),
-		reselect:         make(chan struct{}, 1),
-		logger:           logging.GetLogger("worker/executor/committee").With("runtime_id", commonNode.Runtime.ID()),
+		RuntimeHostNode:      rhn,
+		commonNode:           commonNode,
+		mergeNode:            mergeNode,
+		storageNode:          storageNode,
+		commonCfg:            commonCfg,
+		roleProvider:         roleProvider,
+		speculativeExecution: speculativeExecution,
+		ctx:                  ctx,
+		cancelCtx:            cancel,
+		stopCh:               make(chan struct{}),
+		quitCh:               make(chan struct{}),
+		initCh:               make(chan struct{}),
+		state:                StateNotReady{},
+		stateTransitions:     pubsub.NewBroker(false),
+		reselect:             make(chan struct{}, 1),
+		logger:               logging.GetLogger("worker/executor/committee").With("runtime_id", commonNode.Runtime.ID()),
 	}
 
 	return n, nil
@@ -10,6 +10,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
 	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+	"github.com/oasislabs/oasis-core/go/roothash/api/commitment"
 	"github.com/oasislabs/oasis-core/go/runtime/host/protocol"
 	"github.com/oasislabs/oasis-core/go/runtime/transaction"
 )
@@ -30,6 +31,8 @@ const (
 	ProcessingBatch = "ProcessingBatch"
 	// WaitingForFinalize is the name of StateWaitingForFinalize.
 	WaitingForFinalize = "WaitingForFinalize"
+	// WaitingForConfirmation is the name of StateWaitingForConfirmation.
+	WaitingForConfirmation = "WaitingForConfirmation"
 )
 
 // Valid state transitions.
@@ -81,15 +84,31 @@ var validStateTransitions = map[StateName][]StateName{
 	ProcessingBatch: {
 		// Batch has been successfully processed or has been aborted.
 		WaitingForFinalize,
+		// Speculatively processed batch has been confirmed by a new block.
+		ProcessingBatch,
+		// Speculatively processed batch is done, waiting for confirmation.
+		WaitingForConfirmation,
+		// Speculatively processed batch has been discarded by a new block.
+		WaitingForBatch,
 	},
 
 	// Transitions from WaitingForFinalize state.
 	WaitingForFinalize: {
 		// Round has been finalized.
 		WaitingForBatch,
+		// Received batch based on the expected block, processing speculatively.
+		ProcessingBatch,
 		// Epoch transition occurred and we are no longer in the committee.
 		NotReady,
 	},
+
+	// Transitions from WaitingForConfirmation state.
+	WaitingForConfirmation: {
+		// Speculatively processed batch has been confirmed by a new block.
+		ProcessingBatch,
+		// Speculatively processed batch has been discarded by a new block.
+		WaitingForBatch,
+	},
 }
 
 // NodeState is a node's state.
@@ -197,6 +216,13 @@ type StateProcessingBatch struct {
 	txnSchedSig signature.Signature
 	// Storage signatures for the I/O root containing the inputs.
 	inputStorageSigs []signature.Signature
+	// Block that the batch is being processed against.
+	block *block.Block
+	// Whether the batch is being processed speculatively, before the block
+	// that it is being processed against has been finalized.
+	speculative bool
+	// Timing for the previous batch in case processing is speculative.
+	prevBatchStartTime time.Time
 }
 
 // Name returns the name of the state.
@@ -219,6 +245,9 @@ func (s *StateProcessingBatch) cancel() {
 // StateWaitingForFinalize is the waiting for finalize state.
 type StateWaitingForFinalize struct {
 	batchStartTime time.Time
+	// Header of the proposed compute results in case the batch has been
+	// successfully proposed.
+	proposedHeader *commitment.ComputeResultsHeader
 }
 
 // Name returns the name of the state.
@@ -230,3 +259,24 @@ func (s StateWaitingForFinalize) Name() StateName {
 func (s StateWaitingForFinalize) String() string {
 	return string(s.Name())
 }
+
+// StateWaitingForConfirmation is the waiting for confirmation state.
+//
+// A speculatively processed batch is waiting for the block that it has been
+// processed against to be finalized before it can be proposed.
+type StateWaitingForConfirmation struct {
+	// State of the speculative batch processing.
+	processing StateProcessingBatch
+	// Result of speculative batch processing.
+	result *protocol.ComputedBatch
+}
+
+// Name returns the name of the state.
+func (s StateWaitingForConfirmation) Name() StateName {
+	return WaitingForConfirmation
+}
+
+// String returns a string representation of the state.
+func (s StateWaitingForConfirmation) String() string {
+	return string(s.Name())
+}
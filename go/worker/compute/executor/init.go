@@ -1,18 +1,61 @@
 package executor
 
 import (
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	runtimeRegistry "github.com/oasislabs/oasis-core/go/runtime/registry"
 	workerCommon "github.com/oasislabs/oasis-core/go/worker/common"
 	"github.com/oasislabs/oasis-core/go/worker/compute"
 	"github.com/oasislabs/oasis-core/go/worker/compute/merge"
 	"github.com/oasislabs/oasis-core/go/worker/registration"
+	workerStorage "github.com/oasislabs/oasis-core/go/worker/storage"
+)
+
+const (
+	// CfgSpeculativeExecutionRuntimes configures the runtimes for which the
+	// next batch is processed speculatively while waiting for the current
+	// round to be finalized.
+	CfgSpeculativeExecutionRuntimes = "worker.executor.speculative_execution.runtimes"
 )
 
+// Flags has the configuration flags.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// speculativeExecutionRuntimes reads the runtimes with speculative execution
+// enabled from viper.
+func speculativeExecutionRuntimes() (map[common.Namespace]bool, error) {
+	runtimes, err := runtimeRegistry.ParseRuntimeMap(viper.GetStringSlice(CfgSpeculativeExecutionRuntimes))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[common.Namespace]bool, len(runtimes))
+	for id := range runtimes {
+		result[id] = true
+	}
+	return result, nil
+}
+
 // New creates a new executor worker.
 func New(
 	dataDir string,
 	commonWorker *workerCommon.Worker,
 	mergeWorker *merge.Worker,
+	storageWorker *workerStorage.Worker,
 	registration *registration.Worker,
 ) (*Worker, error) {
-	return newWorker(dataDir, compute.Enabled(), commonWorker, mergeWorker, registration)
+	speculativeRuntimes, err := speculativeExecutionRuntimes()
+	if err != nil {
+		return nil, err
+	}
+
+	return newWorker(dataDir, compute.Enabled(), commonWorker, mergeWorker, storageWorker, registration, speculativeRuntimes)
+}
+
+func init() {
+	Flags.StringSlice(CfgSpeculativeExecutionRuntimes, nil, "Runtime ID(s) (hex-encoded) for which to speculatively process the next batch before the current round is finalized")
+
+	_ = viper.BindPFlags(Flags)
 }
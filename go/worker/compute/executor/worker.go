@@ -12,6 +12,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/worker/compute/executor/committee"
 	"github.com/oasislabs/oasis-core/go/worker/compute/merge"
 	"github.com/oasislabs/oasis-core/go/worker/registration"
+	workerStorage "github.com/oasislabs/oasis-core/go/worker/storage"
 )
 
 // Worker is an executor worker handling many runtimes.
@@ -20,10 +21,13 @@ type Worker struct {
 
 	commonWorker *workerCommon.Worker
 	merge        *merge.Worker
+	storage      *workerStorage.Worker
 	registration *registration.Worker
 
 	runtimes map[common.Namespace]*committee.Node
 
+	speculativeRuntimes map[common.Namespace]bool
+
 	ctx       context.Context
 	cancelCtx context.CancelFunc
 	quitCh    chan struct{}
@@ -139,10 +143,12 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 	id := commonNode.Runtime.ID()
 	w.logger.Info("registering new runtime",
 		"runtime_id", id,
+		"speculative_execution", w.speculativeRuntimes[id],
 	)
 
 	// Get other nodes from this runtime.
 	mergeNode := w.merge.GetRuntime(id)
+	storageNode := w.storage.GetRuntime(id)
 
 	rp, err := w.registration.NewRuntimeRoleProvider(node.RoleComputeWorker, id)
 	if err != nil {
@@ -150,7 +156,7 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 	}
 
 	// Create committee node for the given runtime.
-	node, err := committee.NewNode(commonNode, mergeNode, w.commonWorker.GetConfig(), rp)
+	node, err := committee.NewNode(commonNode, mergeNode, storageNode, w.commonWorker.GetConfig(), rp, w.speculativeRuntimes[id])
 	if err != nil {
 		return err
 	}
@@ -170,21 +176,25 @@ func newWorker(
 	enabled bool,
 	commonWorker *workerCommon.Worker,
 	merge *merge.Worker,
+	storage *workerStorage.Worker,
 	registration *registration.Worker,
+	speculativeRuntimes map[common.Namespace]bool,
 ) (*Worker, error) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 
 	w := &Worker{
-		enabled:      enabled,
-		commonWorker: commonWorker,
-		merge:        merge,
-		registration: registration,
-		runtimes:     make(map[common.Namespace]*committee.Node),
-		ctx:          ctx,
-		cancelCtx:    cancelCtx,
-		quitCh:       make(chan struct{}),
-		initCh:       make(chan struct{}),
-		logger:       logging.GetLogger("worker/executor"),
+		enabled:             enabled,
+		commonWorker:        commonWorker,
+		merge:               merge,
+		storage:             storage,
+		registration:        registration,
+		runtimes:            make(map[common.Namespace]*committee.Node),
+		speculativeRuntimes: speculativeRuntimes,
+		ctx:                 ctx,
+		cancelCtx:           cancelCtx,
+		quitCh:              make(chan struct{}),
+		initCh:              make(chan struct{}),
+		logger:              logging.GetLogger("worker/executor"),
 	}
 
 	if enabled {
@@ -21,12 +21,15 @@ type Algorithm interface {
 	// epoch transition, passing in an epoch snapshot.
 	EpochTransition(epoch *committee.EpochSnapshot) error
 
-	// ScheduleTx attempts to schedule a transaction.
+	// ScheduleTx attempts to schedule a transaction submitted by the given
+	// submitter. The submitter is an opaque identifier (e.g., the public key
+	// of the peer that submitted the transaction) which the scheduling
+	// algorithm may use to fairly share batches among submitters.
 	//
 	// The scheduling algorithm may peek into the transaction to extract
 	// metadata needed for scheduling. In this case, the transaction bytes
 	// must correspond to a transaction.TxnCall structure.
-	ScheduleTx(tx []byte) error
+	ScheduleTx(submitter string, tx []byte) error
 
 	// Flush flushes queued transactions.
 	Flush() error
@@ -34,6 +37,10 @@ type Algorithm interface {
 	// UnscheduledSize returns number of unscheduled items.
 	UnscheduledSize() int
 
+	// UnscheduledSizePerSubmitter returns the number of unscheduled items
+	// of each submitter with unscheduled items.
+	UnscheduledSizePerSubmitter() map[string]int
+
 	// IsQueued returns if a transaction is queued.
 	IsQueued(hash.Hash) bool
 
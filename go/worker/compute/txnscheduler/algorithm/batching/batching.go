@@ -2,12 +2,16 @@
 package batching
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	"github.com/oasislabs/oasis-core/go/worker/common/committee"
@@ -18,7 +22,9 @@ const (
 	// Name of the scheduling algorithm.
 	Name = registry.TxnSchedulerAlgorithmBatching
 
-	cfgMaxQueueSize = "worker.txnscheduler.batching.max_queue_size"
+	cfgMaxQueueSize          = "worker.txnscheduler.batching.max_queue_size"
+	cfgMaxSubmitterQueueSize = "worker.txnscheduler.batching.max_submitter_queue_size"
+	cfgSubmitterWeights      = "worker.txnscheduler.batching.submitter_weights"
 )
 
 // Flags has the configuration flag for the batching algorithm.
@@ -38,9 +44,11 @@ type batchingState struct {
 }
 
 type config struct {
-	maxQueueSize      uint64
-	maxBatchSize      uint64
-	maxBatchSizeBytes uint64
+	maxQueueSize          uint64
+	maxSubmitterQueueSize uint64
+	maxBatchSize          uint64
+	maxBatchSizeBytes     uint64
+	submitterWeights      map[string]uint64
 }
 
 func (s *batchingState) scheduleBatch(force bool) error {
@@ -67,7 +75,7 @@ func (s *batchingState) scheduleBatch(force bool) error {
 		return nil
 	}
 
-	batch, err := s.incomingQueue.Take(force)
+	calls, err := s.incomingQueue.Take(force)
 	if err != nil && err != errNoBatchAvailable {
 		s.logger.Error("failed to get batch from the queue",
 			"err", err,
@@ -75,11 +83,11 @@ func (s *batchingState) scheduleBatch(force bool) error {
 		return err
 	}
 
-	if len(batch) > 0 {
+	if len(calls) > 0 {
 		// Try to dispatch batch to the first committee.
-		if err := s.dispatcher.Dispatch(*committeeID, batch); err != nil {
+		if err := s.dispatcher.Dispatch(*committeeID, rawBatch(calls)); err != nil {
 			// Put the batch back into the incoming queue in case this failed.
-			if errAB := s.incomingQueue.AddBatch(batch); errAB != nil {
+			if errAB := s.incomingQueue.AddBatch(calls); errAB != nil {
 				s.logger.Error("failed to add batch back into the incoming queue",
 					"err", errAB,
 				)
@@ -99,8 +107,8 @@ func (s *batchingState) EpochTransition(epoch *committee.EpochSnapshot) error {
 	return nil
 }
 
-func (s *batchingState) ScheduleTx(tx []byte) error {
-	if err := s.incomingQueue.Add(tx); err != nil {
+func (s *batchingState) ScheduleTx(submitter string, tx []byte) error {
+	if err := s.incomingQueue.Add(submitter, tx); err != nil {
 		// Return success in case of duplicate calls to avoid the client
 		// mistaking this for an actual error.
 		if err == errCallAlreadyExists {
@@ -138,6 +146,10 @@ func (s *batchingState) UnscheduledSize() int {
 	return s.incomingQueue.Size()
 }
 
+func (s *batchingState) UnscheduledSizePerSubmitter() map[string]int {
+	return s.incomingQueue.SubmitterSizes()
+}
+
 func (s *batchingState) IsQueued(id hash.Hash) bool {
 	return s.incomingQueue.IsQueued(id)
 }
@@ -159,14 +171,43 @@ func (s *batchingState) IsInitialized() bool {
 // New creates a new batching algorithm.
 func New(maxBatchSize, maxBatchSizeBytes uint64) (api.Algorithm, error) {
 	cfg := config{
-		maxQueueSize:      uint64(viper.GetInt(cfgMaxQueueSize)),
-		maxBatchSize:      maxBatchSize,
-		maxBatchSizeBytes: maxBatchSizeBytes,
+		maxQueueSize:          uint64(viper.GetInt(cfgMaxQueueSize)),
+		maxSubmitterQueueSize: viper.GetUint64(cfgMaxSubmitterQueueSize),
+		maxBatchSize:          maxBatchSize,
+		maxBatchSizeBytes:     maxBatchSizeBytes,
+		submitterWeights:      make(map[string]uint64),
+	}
+	for _, v := range viper.GetStringSlice(cfgSubmitterWeights) {
+		// NOTE: A list is used instead of a map as map keys in configuration
+		//       files are not case sensitive while public keys are. As public
+		//       keys may end with '=' padding, split on the last separator.
+		sep := strings.LastIndex(v, "=")
+		if sep < 0 {
+			return nil, fmt.Errorf("batching: malformed submitter weight: '%s'", v)
+		}
+		rawPk, rawWeight := v[:sep], v[sep+1:]
+
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(rawPk)); err != nil {
+			return nil, fmt.Errorf("batching: malformed submitter public key '%s': %w", rawPk, err)
+		}
+		weight, err := strconv.ParseUint(rawWeight, 10, 64)
+		if err != nil || weight == 0 {
+			return nil, fmt.Errorf("batching: malformed weight of submitter '%s': '%s'", rawPk, rawWeight)
+		}
+		cfg.submitterWeights[pk.String()] = weight
 	}
+
 	batching := batchingState{
-		cfg:           cfg,
-		incomingQueue: newIncomingQueue(cfg.maxQueueSize, cfg.maxBatchSize, cfg.maxBatchSizeBytes),
-		logger:        logging.GetLogger("txn_scheduler/algo/batching"),
+		cfg: cfg,
+		incomingQueue: newIncomingQueue(
+			cfg.maxQueueSize,
+			cfg.maxSubmitterQueueSize,
+			cfg.maxBatchSize,
+			cfg.maxBatchSizeBytes,
+			cfg.submitterWeights,
+		),
+		logger: logging.GetLogger("txn_scheduler/algo/batching"),
 	}
 
 	return &batching, nil
@@ -174,6 +215,8 @@ func New(maxBatchSize, maxBatchSizeBytes uint64) (api.Algorithm, error) {
 
 func init() {
 	Flags.Uint64(cfgMaxQueueSize, 10000, "Maximum size of the batching queue")
+	Flags.Uint64(cfgMaxSubmitterQueueSize, 0, "Maximum number of queued transactions of a single submitter (0 = unlimited)")
+	Flags.StringSlice(cfgSubmitterWeights, []string{}, "Submitter batch assembly weights in the form <public key>=<weight> (default weight is 1)")
 
 	_ = viper.BindPFlags(Flags)
 }
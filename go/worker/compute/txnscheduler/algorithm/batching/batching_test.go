@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/worker/compute/txnscheduler/algorithm/tests"
 )
 
@@ -17,3 +18,24 @@ func TestBatchingAlgorithm(t *testing.T) {
 
 	tests.AlgorithmImplementationTests(t, algo)
 }
+
+func TestSubmitterWeights(t *testing.T) {
+	var pk signature.PublicKey
+	pk[0] = 1
+
+	viper.Set(cfgSubmitterWeights, []string{pk.String() + "=3"})
+	algo, err := New(10, 16*1024*1024)
+	require.NoError(t, err, "New()")
+	require.EqualValues(t, map[string]uint64{pk.String(): 3}, algo.(*batchingState).cfg.submitterWeights)
+
+	for _, weights := range [][]string{
+		{pk.String()},
+		{pk.String() + "=0"},
+		{"not a public key=1"},
+	} {
+		viper.Set(cfgSubmitterWeights, weights)
+		_, err = New(10, 16*1024*1024)
+		require.Error(t, err, "New() should fail with malformed weights: %v", weights)
+	}
+	viper.Set(cfgSubmitterWeights, []string{})
+}
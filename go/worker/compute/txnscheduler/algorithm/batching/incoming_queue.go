@@ -6,25 +6,67 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/runtime/transaction"
+	txnSchedulerApi "github.com/oasislabs/oasis-core/go/worker/compute/txnscheduler/api"
 )
 
 var (
-	errQueueFull         = errors.New("queue is full")
+	errQueueFull         = txnSchedulerApi.ErrQueueFull
 	errCallTooLarge      = errors.New("call too large")
 	errCallAlreadyExists = errors.New("call already exists in queue")
 	errNoBatchAvailable  = errors.New("no batch available in incoming queue")
 )
 
+// queuedCall is a call in the incoming queue.
+type queuedCall struct {
+	call      []byte
+	hash      hash.Hash
+	submitter string
+}
+
+func newQueuedCall(submitter string, call []byte) *queuedCall {
+	return &queuedCall{
+		call:      call,
+		hash:      hash.NewFromBytes(call),
+		submitter: submitter,
+	}
+}
+
+func rawBatch(calls []*queuedCall) transaction.RawBatch {
+	batch := make(transaction.RawBatch, 0, len(calls))
+	for _, qc := range calls {
+		batch = append(batch, qc.call)
+	}
+	return batch
+}
+
+// submitterQueue is the queue of calls of a single submitter.
+type submitterQueue struct {
+	submitter string
+	calls     []*queuedCall
+}
+
+// incomingQueue is the queue of incoming calls.
+//
+// Calls are bucketed per submitter and batches are assembled by taking calls
+// from the buckets in a round-robin fashion, so that a single submitter
+// cannot monopolize the batches.
 type incomingQueue struct {
 	sync.Mutex
 
-	queue          transaction.RawBatch
+	// buckets are the non-empty submitter buckets in round-robin order.
+	buckets        []*submitterQueue
+	queueSize      uint64
 	queueSizeBytes uint64
 	callHashes     map[hash.Hash]bool
 
-	maxQueueSize      uint64
-	maxBatchSize      uint64
-	maxBatchSizeBytes uint64
+	maxQueueSize          uint64
+	maxSubmitterQueueSize uint64
+	maxBatchSize          uint64
+	maxBatchSizeBytes     uint64
+
+	// weights are the number of calls taken from a submitter's bucket in
+	// each round. Submitters without a configured weight have a weight of 1.
+	weights map[string]uint64
 }
 
 // Size returns the size of the incoming queue.
@@ -32,7 +74,20 @@ func (q *incomingQueue) Size() int {
 	q.Lock()
 	defer q.Unlock()
 
-	return len(q.queue)
+	return int(q.queueSize)
+}
+
+// SubmitterSizes returns the size of the incoming queue for each submitter
+// with queued calls.
+func (q *incomingQueue) SubmitterSizes() map[string]int {
+	q.Lock()
+	defer q.Unlock()
+
+	sizes := make(map[string]int, len(q.buckets))
+	for _, b := range q.buckets {
+		sizes[b.submitter] = len(b.calls)
+	}
+	return sizes
 }
 
 // Clear clears the queue.
@@ -40,7 +95,8 @@ func (q *incomingQueue) Clear() {
 	q.Lock()
 	defer q.Unlock()
 
-	q.queue = make(transaction.RawBatch, 0)
+	q.buckets = nil
+	q.queueSize = 0
 	q.queueSizeBytes = 0
 	q.callHashes = make(map[hash.Hash]bool)
 }
@@ -60,13 +116,56 @@ func (q *incomingQueue) IsQueued(callHash hash.Hash) bool {
 }
 
 // NOTE: Assumes lock is held.
-func (q *incomingQueue) checkCallLocked(call []byte, callHash hash.Hash) error {
-	callSize := uint64(len(call))
+func (q *incomingQueue) bucketLocked(submitter string) *submitterQueue {
+	for _, b := range q.buckets {
+		if b.submitter == submitter {
+			return b
+		}
+	}
+	return nil
+}
+
+// NOTE: Assumes lock is held.
+func (q *incomingQueue) weightLocked(submitter string) uint64 {
+	if w, ok := q.weights[submitter]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// NOTE: Assumes lock is held.
+func (q *incomingQueue) checkRoomLocked(calls []*queuedCall) error {
+	if q.queueSize+uint64(len(calls)) > q.maxQueueSize {
+		return errQueueFull
+	}
+	if q.maxSubmitterQueueSize == 0 {
+		return nil
+	}
+
+	added := make(map[string]uint64)
+	for _, qc := range calls {
+		added[qc.submitter]++
+	}
+	for submitter, n := range added {
+		var size uint64
+		if b := q.bucketLocked(submitter); b != nil {
+			size = uint64(len(b.calls))
+		}
+		if size+n > q.maxSubmitterQueueSize {
+			return errQueueFull
+		}
+	}
+	return nil
+}
+
+// NOTE: Assumes lock is held.
+func (q *incomingQueue) checkCallLocked(qc *queuedCall) error {
+	callSize := uint64(len(qc.call))
 
 	if callSize > q.maxBatchSizeBytes {
 		return errCallTooLarge
 	}
-	if q.isQueuedLocked(callHash) {
+	if q.isQueuedLocked(qc.hash) {
 		return errCallAlreadyExists
 	}
 
@@ -74,133 +173,154 @@ func (q *incomingQueue) checkCallLocked(call []byte, callHash hash.Hash) error {
 }
 
 // NOTE: Assumes lock is held and that checkCallLocked has been called.
-func (q *incomingQueue) addCallLocked(call []byte, callHash hash.Hash) {
+func (q *incomingQueue) addCallLocked(qc *queuedCall) {
 	// Assuming checkCallLocked has been called before, this can happen if
 	// duplicate calls are in the same batch -- just ignore them.
-	if _, exists := q.callHashes[callHash]; exists {
+	if _, exists := q.callHashes[qc.hash]; exists {
 		return
 	}
 
-	q.queue = append(q.queue, call)
-	q.callHashes[callHash] = true
-	q.queueSizeBytes += uint64(len(call))
+	b := q.bucketLocked(qc.submitter)
+	if b == nil {
+		b = &submitterQueue{submitter: qc.submitter}
+		q.buckets = append(q.buckets, b)
+	}
+	b.calls = append(b.calls, qc)
+
+	q.callHashes[qc.hash] = true
+	q.queueSize++
+	q.queueSizeBytes += uint64(len(qc.call))
 }
 
-// Add adds a call to the incoming queue.
-func (q *incomingQueue) Add(call []byte) error {
-	callHash := hash.NewFromBytes(call)
+// Add adds a call from the given submitter to the incoming queue.
+func (q *incomingQueue) Add(submitter string, call []byte) error {
+	qc := newQueuedCall(submitter, call)
 
 	q.Lock()
 	defer q.Unlock()
 
 	// Check if there is room in the queue.
-	if uint64(len(q.queue)) >= q.maxQueueSize {
-		return errQueueFull
+	if err := q.checkRoomLocked([]*queuedCall{qc}); err != nil {
+		return err
 	}
 
-	if err := q.checkCallLocked(call, callHash); err != nil {
+	if err := q.checkCallLocked(qc); err != nil {
 		return err
 	}
 
-	q.addCallLocked(call, callHash)
+	q.addCallLocked(qc)
 
 	return nil
 }
 
 // AddBatch adds a batch of calls to the queue.
-func (q *incomingQueue) AddBatch(batch transaction.RawBatch) error {
-	// Compute all hashes before taking the lock.
-	var callHashes []hash.Hash
-	for _, call := range batch {
-		callHash := hash.NewFromBytes(call)
-		callHashes = append(callHashes, callHash)
-	}
-
+func (q *incomingQueue) AddBatch(batch []*queuedCall) error {
 	q.Lock()
 	defer q.Unlock()
 
 	// Check if there is room in the queue.
-	if uint64(len(q.queue)+len(batch)) >= q.maxQueueSize {
-		return errQueueFull
+	if err := q.checkRoomLocked(batch); err != nil {
+		return err
 	}
 
 	// First check all calls.
-	for i, call := range batch {
-		if err := q.checkCallLocked(call, callHashes[i]); err != nil {
+	for _, qc := range batch {
+		if err := q.checkCallLocked(qc); err != nil {
 			return err
 		}
 	}
 
 	// Then add all calls if checks passed.
-	for i, call := range batch {
-		q.addCallLocked(call, callHashes[i])
+	for _, qc := range batch {
+		q.addCallLocked(qc)
 	}
 
 	return nil
 }
 
 // Take attempts to take a batch from the incoming queue.
-func (q *incomingQueue) Take(force bool) (transaction.RawBatch, error) {
+//
+// Calls are taken from the submitter buckets in rounds, where in each round
+// every bucket contributes up to its weight in calls in order of submission.
+// A bucket whose next call does not fit into the batch contributes no more
+// calls. The bucket that goes first is rotated on every taken batch.
+func (q *incomingQueue) Take(force bool) ([]*queuedCall, error) {
 	q.Lock()
 	defer q.Unlock()
 
 	// Check if we have a batch ready.
-	queueSize := uint64(len(q.queue))
-	if queueSize == 0 {
+	if q.queueSize == 0 {
 		return nil, errNoBatchAvailable
 	}
-	if queueSize < q.maxBatchSize && q.queueSizeBytes < q.maxBatchSizeBytes && !force {
+	if q.queueSize < q.maxBatchSize && q.queueSizeBytes < q.maxBatchSizeBytes && !force {
 		return nil, errNoBatchAvailable
 	}
 
-	// NOTE: These "returned" variables signify what will be returned back
-	//       to the queue, not what will be returned from the function.
-	var returned transaction.RawBatch
-	var returnedSizeBytes uint64
-	returnedCallHashes := make(map[hash.Hash]bool)
-
-	var batch transaction.RawBatch
+	var batch []*queuedCall
 	var batchSizeBytes uint64
 
-	for _, call := range q.queue[:] {
-		shouldReturn := false
-		callSize := uint64(len(call))
-
-		// Check if the batch already has enough calls.
-		if uint64(len(batch)) >= q.maxBatchSize {
-			shouldReturn = true
+	blocked := make(map[*submitterQueue]bool)
+	for uint64(len(batch)) < q.maxBatchSize {
+		var progress bool
+		for _, b := range q.buckets {
+			for i := uint64(0); i < q.weightLocked(b.submitter); i++ {
+				if blocked[b] || len(b.calls) == 0 || uint64(len(batch)) >= q.maxBatchSize {
+					break
+				}
+
+				// Check if the call does not fit into a batch.
+				qc := b.calls[0]
+				callSize := uint64(len(qc.call))
+				if batchSizeBytes+callSize > q.maxBatchSizeBytes {
+					blocked[b] = true
+					break
+				}
+
+				// Take call.
+				batch = append(batch, qc)
+				batchSizeBytes += callSize
+				b.calls = b.calls[1:]
+				progress = true
+			}
 		}
-		// Check if the call does not fit into a batch.
-		if batchSizeBytes+callSize > q.maxBatchSizeBytes {
-			shouldReturn = true
+		if !progress {
+			break
 		}
+	}
 
-		if shouldReturn {
-			returned = append(returned, call)
-			returnedSizeBytes += callSize
-
-			callHash := hash.NewFromBytes(call)
-			returnedCallHashes[callHash] = true
-			continue
+	for _, qc := range batch {
+		delete(q.callHashes, qc.hash)
+	}
+	q.queueSize -= uint64(len(batch))
+	q.queueSizeBytes -= batchSizeBytes
+
+	// Rotate the buckets so that a different submitter goes first in the
+	// next batch and drop the empty ones.
+	buckets := make([]*submitterQueue, 0, len(q.buckets))
+	for i := range q.buckets {
+		b := q.buckets[(i+1)%len(q.buckets)]
+		if len(b.calls) > 0 {
+			buckets = append(buckets, b)
 		}
-
-		// Take call.
-		batch = append(batch, call)
-		batchSizeBytes += callSize
 	}
-
-	q.queue = returned
-	q.queueSizeBytes = returnedSizeBytes
-	q.callHashes = returnedCallHashes
+	q.buckets = buckets
 
 	return batch, nil
 }
 
-func newIncomingQueue(maxQueueSize, maxBatchSize, maxBatchSizeBytes uint64) *incomingQueue {
+func newIncomingQueue(
+	maxQueueSize uint64,
+	maxSubmitterQueueSize uint64,
+	maxBatchSize uint64,
+	maxBatchSizeBytes uint64,
+	weights map[string]uint64,
+) *incomingQueue {
 	return &incomingQueue{
-		callHashes:        make(map[hash.Hash]bool),
-		maxQueueSize:      maxQueueSize,
-		maxBatchSize:      maxBatchSize,
-		maxBatchSizeBytes: maxBatchSizeBytes,
+		callHashes:            make(map[hash.Hash]bool),
+		maxQueueSize:          maxQueueSize,
+		maxSubmitterQueueSize: maxSubmitterQueueSize,
+		maxBatchSize:          maxBatchSize,
+		maxBatchSizeBytes:     maxBatchSizeBytes,
+		weights:               weights,
 	}
 }
@@ -7,31 +7,39 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func queuedCalls(submitter string, calls ...string) (result []*queuedCall) {
+	for _, call := range calls {
+		result = append(result, newQueuedCall(submitter, []byte(call)))
+	}
+	return
+}
+
 func TestBasic(t *testing.T) {
-	queue := newIncomingQueue(51, 10, 100)
+	queue := newIncomingQueue(51, 0, 10, 100, nil)
 
-	err := queue.Add([]byte("hello world"))
+	err := queue.Add("", []byte("hello world"))
 	require.NoError(t, err, "Add")
 
-	err = queue.Add([]byte("hello world"))
+	err = queue.Add("", []byte("hello world"))
 	require.Error(t, err, "Add error on duplicates")
 
-	err = queue.Add(make([]byte, 200))
+	err = queue.Add("", make([]byte, 200))
 	require.Error(t, err, "Add error on oversized calls")
 
 	// Add some more calls.
 	for i := 0; i < 50; i++ {
-		err = queue.Add([]byte(fmt.Sprintf("call %d", i)))
+		err = queue.Add("", []byte(fmt.Sprintf("call %d", i)))
 		require.NoError(t, err, "Add")
 	}
 
-	err = queue.Add([]byte("another call"))
+	err = queue.Add("", []byte("another call"))
 	require.Error(t, err, "Add error on queue full")
 
 	require.EqualValues(t, 51, queue.Size(), "Size")
 
-	batch, err := queue.Take(false)
+	calls, err := queue.Take(false)
 	require.NoError(t, err, "Take")
+	batch := rawBatch(calls)
 	require.EqualValues(t, 10, len(batch), "Batch size")
 	require.EqualValues(t, 41, queue.Size(), "Size")
 
@@ -41,7 +49,7 @@ func TestBasic(t *testing.T) {
 	}
 
 	// Not a duplicate anymore.
-	err = queue.Add([]byte("hello world"))
+	err = queue.Add("", []byte("hello world"))
 	require.NoError(t, err, "Add")
 	require.EqualValues(t, 42, queue.Size(), "Size")
 
@@ -50,9 +58,9 @@ func TestBasic(t *testing.T) {
 }
 
 func TestNoBatchReady(t *testing.T) {
-	queue := newIncomingQueue(51, 10, 100)
+	queue := newIncomingQueue(51, 0, 10, 100, nil)
 
-	err := queue.Add([]byte("hello world"))
+	err := queue.Add("", []byte("hello world"))
 	require.NoError(t, err, "Add")
 
 	_, err = queue.Take(false)
@@ -60,9 +68,9 @@ func TestNoBatchReady(t *testing.T) {
 }
 
 func TestForceBatch(t *testing.T) {
-	queue := newIncomingQueue(51, 10, 100)
+	queue := newIncomingQueue(51, 0, 10, 100, nil)
 
-	err := queue.Add([]byte("hello world"))
+	err := queue.Add("", []byte("hello world"))
 	require.NoError(t, err, "Add")
 
 	batch, err := queue.Take(true)
@@ -72,26 +80,102 @@ func TestForceBatch(t *testing.T) {
 }
 
 func TestAddBatch(t *testing.T) {
-	queue := newIncomingQueue(51, 10, 100)
-
-	err := queue.AddBatch([][]byte{
-		[]byte("hello world"),
-		[]byte("hello world"),
-		[]byte("one"),
-		[]byte("two"),
-		[]byte("three"),
-	})
+	queue := newIncomingQueue(51, 0, 10, 100, nil)
+
+	err := queue.AddBatch(queuedCalls("",
+		"hello world",
+		"hello world",
+		"one",
+		"two",
+		"three",
+	))
 	require.NoError(t, err, "AddBatch")
 	require.EqualValues(t, 4, queue.Size(), "Size")
 
 	for i := 0; i < 10; i++ {
-		_ = queue.AddBatch([][]byte{
-			[]byte(fmt.Sprintf("a %d", i)),
-			[]byte(fmt.Sprintf("b %d", i)),
-			[]byte(fmt.Sprintf("c %d", i)),
-			[]byte(fmt.Sprintf("d %d", i)),
-			[]byte(fmt.Sprintf("e %d", i)),
-		})
+		_ = queue.AddBatch(queuedCalls("",
+			fmt.Sprintf("a %d", i),
+			fmt.Sprintf("b %d", i),
+			fmt.Sprintf("c %d", i),
+			fmt.Sprintf("d %d", i),
+			fmt.Sprintf("e %d", i),
+		))
 	}
 	require.True(t, queue.Size() <= 51, "queue must not overflow")
 }
+
+func TestFairness(t *testing.T) {
+	queue := newIncomingQueue(100, 0, 4, 100, nil)
+
+	// A spamming submitter should not be able to monopolize the batches.
+	for i := 0; i < 10; i++ {
+		err := queue.Add("spammer", []byte(fmt.Sprintf("spam %d", i)))
+		require.NoError(t, err, "Add")
+	}
+	err := queue.Add("alice", []byte("alice 0"))
+	require.NoError(t, err, "Add")
+	err = queue.Add("bob", []byte("bob 0"))
+	require.NoError(t, err, "Add")
+	err = queue.Add("bob", []byte("bob 1"))
+	require.NoError(t, err, "Add")
+	require.EqualValues(t, map[string]int{"spammer": 10, "alice": 1, "bob": 2}, queue.SubmitterSizes())
+
+	calls, err := queue.Take(false)
+	require.NoError(t, err, "Take")
+	require.EqualValues(t, rawBatch(queuedCalls("", "spam 0", "alice 0", "bob 0", "spam 1")), rawBatch(calls))
+
+	// The bucket that goes first should be rotated.
+	calls, err = queue.Take(false)
+	require.NoError(t, err, "Take")
+	require.EqualValues(t, rawBatch(queuedCalls("", "bob 1", "spam 2", "spam 3", "spam 4")), rawBatch(calls))
+	require.EqualValues(t, map[string]int{"spammer": 5}, queue.SubmitterSizes())
+
+	// A call that does not fit into the batch should not be overtaken by
+	// later calls of the same submitter.
+	queue = newIncomingQueue(100, 0, 4, 10, nil)
+	err = queue.Add("alice", []byte("aaaaaa"))
+	require.NoError(t, err, "Add")
+	err = queue.Add("bob", []byte("bbbbbb"))
+	require.NoError(t, err, "Add")
+	err = queue.Add("bob", []byte("b"))
+	require.NoError(t, err, "Add")
+	calls, err = queue.Take(false)
+	require.NoError(t, err, "Take")
+	require.EqualValues(t, rawBatch(queuedCalls("", "aaaaaa")), rawBatch(calls))
+}
+
+func TestWeights(t *testing.T) {
+	queue := newIncomingQueue(100, 0, 6, 100, map[string]uint64{"alice": 2})
+
+	for i := 0; i < 5; i++ {
+		err := queue.Add("alice", []byte(fmt.Sprintf("alice %d", i)))
+		require.NoError(t, err, "Add")
+		err = queue.Add("bob", []byte(fmt.Sprintf("bob %d", i)))
+		require.NoError(t, err, "Add")
+	}
+
+	calls, err := queue.Take(false)
+	require.NoError(t, err, "Take")
+	require.EqualValues(t, rawBatch(queuedCalls("",
+		"alice 0", "alice 1", "bob 0",
+		"alice 2", "alice 3", "bob 1",
+	)), rawBatch(calls))
+}
+
+func TestSubmitterQueueSize(t *testing.T) {
+	queue := newIncomingQueue(100, 2, 10, 100, nil)
+
+	err := queue.Add("alice", []byte("alice 0"))
+	require.NoError(t, err, "Add")
+	err = queue.Add("alice", []byte("alice 1"))
+	require.NoError(t, err, "Add")
+	err = queue.Add("alice", []byte("alice 2"))
+	require.Equal(t, errQueueFull, err, "Add error on submitter queue full")
+
+	// Other submitters should not be affected.
+	err = queue.AddBatch(queuedCalls("bob", "bob 0", "bob 1"))
+	require.NoError(t, err, "AddBatch")
+	err = queue.AddBatch(queuedCalls("bob", "bob 2"))
+	require.Equal(t, errQueueFull, err, "AddBatch error on submitter queue full")
+	require.EqualValues(t, 4, queue.Size(), "Size")
+}
@@ -58,7 +58,7 @@ func testScheduleTransactions(t *testing.T, td *testDispatcher, algorithm api.Al
 	// Test ScheduleTx.
 	testTx := []byte("hello world")
 	txBytes := hash.NewFromBytes(testTx)
-	err := algorithm.ScheduleTx(testTx)
+	err := algorithm.ScheduleTx("", testTx)
 	require.NoError(t, err, "ScheduleTx(testTx)")
 	require.True(t, algorithm.IsQueued(txBytes), "IsQueued(tx)")
 
@@ -76,7 +76,7 @@ func testScheduleTransactions(t *testing.T, td *testDispatcher, algorithm api.Al
 	testTx2 := []byte("hello world2")
 	tx2Bytes := hash.NewFromBytes(testTx2)
 
-	err = algorithm.ScheduleTx(testTx2)
+	err = algorithm.ScheduleTx("", testTx2)
 	require.NoError(t, err, "ScheduleTx(testTx2)")
 	require.True(t, algorithm.IsQueued(tx2Bytes), "IsQueued(tx)")
 	require.False(t, algorithm.IsQueued(txBytes), "IsQueued(tx)")
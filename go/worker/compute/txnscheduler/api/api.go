@@ -30,6 +30,10 @@ var (
 
 	// ErrEpochNumberMismatch is the error returned when epoch of client and compute node mismatch.
 	ErrEpochNumberMismatch = errors.New(ModuleName, 5, "txnscheduler: epoch number mismatch")
+
+	// ErrQueueFull is the error returned when the transaction scheduler
+	// queue has no room for the submitted transaction.
+	ErrQueueFull = errors.New(ModuleName, 6, "txnscheduler: queue is full")
 )
 
 // TransactionScheduler is the transaction scheduler API interface.
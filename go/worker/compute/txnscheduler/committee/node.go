@@ -14,6 +14,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/crash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	policyAPI "github.com/oasislabs/oasis-core/go/common/grpc/policy/api"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
@@ -49,8 +50,24 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	incomingQueueSubmitterSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_txnscheduler_incoming_queue_submitter_size",
+			Help: "Size of the incoming queue per submitter (number of entries).",
+		},
+		[]string{"runtime", "submitter"},
+	)
+	droppedTxCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_txnscheduler_dropped_tx_count",
+			Help: "Number of transactions dropped due to a full incoming queue.",
+		},
+		[]string{"runtime", "submitter"},
+	)
 	nodeCollectors = []prometheus.Collector{
 		incomingQueueSize,
+		incomingQueueSubmitterSize,
+		droppedTxCount,
 	}
 
 	metricsOnce sync.Once
@@ -75,6 +92,10 @@ type Node struct { // nolint: maligned
 	algorithmMutex sync.RWMutex
 	algorithm      txnSchedulerAlgorithmApi.Algorithm
 
+	// Submitters for which the per-submitter queue size is reported.
+	queueMetricsLock       sync.Mutex
+	queueMetricsSubmitters map[string]bool
+
 	ctx       context.Context
 	cancelCtx context.CancelFunc
 	stopCh    chan struct{}
@@ -137,6 +158,36 @@ func (n *Node) getMetricLabels() prometheus.Labels {
 	}
 }
 
+func (n *Node) getSubmitterMetricLabels(submitter string) prometheus.Labels {
+	return prometheus.Labels{
+		"runtime":   n.commonNode.Runtime.ID().String(),
+		"submitter": submitter,
+	}
+}
+
+// updateQueueMetrics updates the incoming queue size metrics.
+//
+// NOTE: The algorithm must be initialized.
+func (n *Node) updateQueueMetrics() {
+	n.queueMetricsLock.Lock()
+	defer n.queueMetricsLock.Unlock()
+
+	incomingQueueSize.With(n.getMetricLabels()).Set(float64(n.algorithm.UnscheduledSize()))
+
+	sizes := n.algorithm.UnscheduledSizePerSubmitter()
+	for submitter, size := range sizes {
+		incomingQueueSubmitterSize.With(n.getSubmitterMetricLabels(submitter)).Set(float64(size))
+		n.queueMetricsSubmitters[submitter] = true
+	}
+	// Stop reporting submitters that no longer have queued transactions.
+	for submitter := range n.queueMetricsSubmitters {
+		if _, ok := sizes[submitter]; !ok {
+			incomingQueueSubmitterSize.Delete(n.getSubmitterMetricLabels(submitter))
+			delete(n.queueMetricsSubmitters, submitter)
+		}
+	}
+}
+
 // HandlePeerMessage implements NodeHooks.
 func (n *Node) HandlePeerMessage(ctx context.Context, message *p2p.Message) (bool, error) {
 	return false, nil
@@ -236,11 +287,18 @@ func (n *Node) QueueCall(ctx context.Context, expectedEpochNumber epochtime.Epoc
 	if n.algorithm == nil || !n.algorithm.IsInitialized() {
 		return api.ErrNotReady
 	}
-	if err := n.algorithm.ScheduleTx(call); err != nil {
+	// Transactions are queued per submitter so that a single submitter cannot
+	// monopolize the batches. Submitters that cannot be identified share the
+	// same queue.
+	submitter, _ := policyAPI.SubjectFromGRPCContext(ctx)
+	if err := n.algorithm.ScheduleTx(submitter, call); err != nil {
+		if errors.Is(err, api.ErrQueueFull) {
+			droppedTxCount.With(n.getSubmitterMetricLabels(submitter)).Inc()
+		}
 		return err
 	}
 
-	incomingQueueSize.With(n.getMetricLabels()).Set(float64(n.algorithm.UnscheduledSize()))
+	n.updateQueueMetrics()
 
 	return nil
 }
@@ -314,7 +372,7 @@ func (n *Node) HandleEpochTransitionLocked(epoch *committee.EpochSnapshot) {
 	} else {
 		n.algorithm.Clear()
 		// Clear incoming queue if we are not a leader.
-		incomingQueueSize.With(n.getMetricLabels()).Set(0)
+		n.updateQueueMetrics()
 		n.transitionLocked(StateNotReady{})
 	}
 	// TODO: Make non-leader members follow.
@@ -572,6 +630,11 @@ func (n *Node) worker() {
 			case ev.FailedToStart != nil, ev.Stopped != nil:
 				// Runtime failed to start or was stopped -- we can no longer service requests.
 				n.roleProvider.SetUnavailable()
+			case ev.LimitExceeded != nil:
+				// Runtime was killed due to exceeding its resource limits, a stop event will follow.
+				n.logger.Error("runtime exceeded its resource limits",
+					"resource", ev.LimitExceeded.Resource,
+				)
 			default:
 				// Unknown event.
 				n.logger.Warn("unknown worker event",
@@ -581,6 +644,7 @@ func (n *Node) worker() {
 		case <-scheduleTicker.C:
 			// Flush a batch from algorithm.
 			n.algorithm.Flush()
+			n.updateQueueMetrics()
 		}
 	}
 }
@@ -605,19 +669,20 @@ func NewNode(
 	ctx, cancel := context.WithCancel(context.Background())
 
 	n := &Node{
-		RuntimeHostNode:  rhn,
-		checkTxEnabled:   checkTxEnabled,
-		commonNode:       commonNode,
-		executorNode:     executorNode,
-		roleProvider:     roleProvider,
-		ctx:              ctx,
-		cancelCtx:        cancel,
-		stopCh:           make(chan struct{}),
-		quitCh:           make(chan struct{}),
-		initCh:           make(chan struct{}),
-		state:            StateNotReady{},
-		stateTransitions: pubsub.NewBroker(false),
-		logger:           logging.GetLogger("worker/txnscheduler/committee").With("runtime_id", commonNode.Runtime.ID()),
+		RuntimeHostNode:        rhn,
+		checkTxEnabled:         checkTxEnabled,
+		commonNode:             commonNode,
+		executorNode:           executorNode,
+		roleProvider:           roleProvider,
+		ctx:                    ctx,
+		cancelCtx:              cancel,
+		stopCh:                 make(chan struct{}),
+		quitCh:                 make(chan struct{}),
+		initCh:                 make(chan struct{}),
+		queueMetricsSubmitters: make(map[string]bool),
+		state:                  StateNotReady{},
+		stateTransitions:       pubsub.NewBroker(false),
+		logger:                 logging.GetLogger("worker/txnscheduler/committee").With("runtime_id", commonNode.Runtime.ID()),
 	}
 
 	return n, nil
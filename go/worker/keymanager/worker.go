@@ -19,6 +19,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	"github.com/oasislabs/oasis-core/go/common/service"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/keymanager/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
@@ -365,6 +366,31 @@ func extractMessageResponsePayload(raw []byte) ([]byte, error) {
 	return cbor.Marshal(msg.Response.Body.Success), nil
 }
 
+// watchStatuses subscribes to key manager status updates, retrying until
+// either the subscription succeeds or the worker is stopped.
+func (w *Worker) watchStatuses() (<-chan *api.AnnotatedStatus, pubsub.ClosableSubscription, error) {
+	var (
+		ch  <-chan *api.AnnotatedStatus
+		sub pubsub.ClosableSubscription
+	)
+	watch := func() (err error) {
+		ch, sub, err = w.backend.WatchStatuses(w.ctx, consensus.HeightLatest)
+		if err != nil {
+			w.logger.Warn("failed to watch key manager statuses, retrying",
+				"err", err,
+			)
+		}
+		return
+	}
+
+	sched := backoff.NewExponentialBackOff()
+	sched.MaxElapsedTime = 0
+	if err := backoff.Retry(watch, backoff.WithContext(sched, w.ctx)); err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
 func (w *Worker) worker() { // nolint: gocyclo
 	defer close(w.quitCh)
 
@@ -382,8 +408,14 @@ func (w *Worker) worker() { // nolint: gocyclo
 	go knw.watchNodes()
 
 	// Subscribe to key manager status updates.
-	statusCh, statusSub := w.backend.WatchStatuses()
-	defer statusSub.Close()
+	statusCh, statusSub, err := w.watchStatuses()
+	if err != nil {
+		// The worker has been stopped.
+		return
+	}
+	defer func() {
+		statusSub.Close()
+	}()
 
 	// Subscribe to runtime registrations in order to know which runtimes
 	// are using us as a key manager.
@@ -427,13 +459,28 @@ func (w *Worker) worker() { // nolint: gocyclo
 				// Worker failed to start or was stopped -- we can no longer service requests.
 				currentStartedEvent = nil
 				w.roleProvider.SetUnavailable()
+			case ev.LimitExceeded != nil:
+				// Runtime was killed due to exceeding its resource limits, a stop event will follow.
+				w.logger.Error("runtime exceeded its resource limits",
+					"resource", ev.LimitExceeded.Resource,
+				)
 			default:
 				// Unknown event.
 				w.logger.Warn("unknown worker event",
 					"ev", ev,
 				)
 			}
-		case status := <-statusCh:
+		case annStatus, ok := <-statusCh:
+			if !ok {
+				w.logger.Warn("key manager status subscription closed, resubscribing")
+				statusSub.Close()
+				if statusCh, statusSub, err = w.watchStatuses(); err != nil {
+					return
+				}
+				continue
+			}
+
+			status := annStatus.Status
 			if !status.ID.Equal(&runtimeID) {
 				continue
 			}
@@ -2,6 +2,7 @@ package registration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -26,6 +27,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	runtimeRegistry "github.com/oasislabs/oasis-core/go/runtime/registry"
+	sentryAPI "github.com/oasislabs/oasis-core/go/sentry/api"
 	sentryClient "github.com/oasislabs/oasis-core/go/sentry/client"
 	workerCommon "github.com/oasislabs/oasis-core/go/worker/common"
 	"github.com/oasislabs/oasis-core/go/worker/common/p2p"
@@ -161,9 +163,13 @@ func DebugForceAllowUnroutableAddresses() {
 func (w *Worker) registrationLoop() { // nolint: gocyclo
 	// If we have any sentry nodes, let them know about our TLS certs.
 	if len(w.sentryAddresses) > 0 {
-		pubKeys := w.identity.GetTLSPubKeys()
 		for _, sentryAddr := range w.sentryAddresses {
 			pushCerts := func() error {
+				pubKeys, err := w.signTLSPubKeys()
+				if err != nil {
+					return backoff.Permanent(err)
+				}
+
 				client, err := sentryClient.New(sentryAddr, w.identity)
 				if err != nil {
 					return err
@@ -251,8 +257,10 @@ func (w *Worker) registrationLoop() { // nolint: gocyclo
 	var lastTLSRotationEpoch epochtime.EpochTime
 	tlsRotationPending := true
 	first := true
+	attestReadiness := true
 Loop:
 	for {
+		var epochChanged bool
 		select {
 		case <-w.stopCh:
 			return
@@ -261,6 +269,7 @@ Loop:
 			return
 		case epoch = <-ch:
 			// Epoch updated, check if we can submit a registration.
+			epochChanged = true
 
 			// Check if we need to rotate the node's TLS certificate.
 			if !w.identity.DoNotRotateTLS && !tlsRotationPending {
@@ -292,6 +301,12 @@ Loop:
 			// Notification that a role provider has been updated.
 		}
 
+		// Attest readiness for all runtimes that are ready to be served so that the scheduler can
+		// avoid electing this node into committees of runtimes it cannot currently serve.
+		if epochChanged && !first && attestReadiness && w.consensus != nil {
+			attestReadiness = w.attestRuntimeReadiness(epoch)
+		}
+
 		// If there are any role providers which are still not ready, we must wait for more
 		// notifications.
 		hooks := func() (h []RegisterNodeHook) {
@@ -663,12 +678,87 @@ func (w *Worker) registerNode(epoch epochtime.EpochTime, hook RegisterNodeHook)
 	return nil
 }
 
+// readyRuntimes returns the runtimes for which all of the runtime role providers are available.
+func (w *Worker) readyRuntimes() []common.Namespace {
+	w.RLock()
+	defer w.RUnlock()
+
+	var runtimes []common.Namespace
+	notReady := make(map[common.Namespace]bool)
+	for _, rp := range w.roleProviders {
+		rp.Lock()
+		runtimeID := rp.runtimeID
+		available := rp.hook != nil
+		rp.Unlock()
+
+		if runtimeID == nil {
+			continue
+		}
+		if _, seen := notReady[*runtimeID]; !seen {
+			runtimes = append(runtimes, *runtimeID)
+		}
+		notReady[*runtimeID] = notReady[*runtimeID] || !available
+	}
+
+	var ready []common.Namespace
+	for _, runtimeID := range runtimes {
+		if !notReady[runtimeID] {
+			ready = append(ready, runtimeID)
+		}
+	}
+	return ready
+}
+
+// attestRuntimeReadiness submits a readiness attestation for all runtimes that are ready to be
+// served. It returns false in case readiness attestations are disabled.
+func (w *Worker) attestRuntimeReadiness(epoch epochtime.EpochTime) bool {
+	runtimes := w.readyRuntimes()
+	if len(runtimes) == 0 {
+		return true
+	}
+
+	w.logger.Debug("attesting runtime readiness",
+		"epoch", epoch,
+		"runtimes", runtimes,
+	)
+
+	tx := registry.NewAttestRuntimeReadinessTx(0, nil, &registry.RuntimeReadinessAttestation{
+		Runtimes: runtimes,
+	})
+	err := consensus.SignAndSubmitTx(w.ctx, w.consensus, w.identity.NodeSigner, tx)
+	switch {
+	case err == nil:
+	case errors.Is(err, registry.ErrForbidden):
+		w.logger.Info("runtime readiness attestations are disabled")
+		return false
+	default:
+		w.logger.Error("failed to attest runtime readiness",
+			"err", err,
+		)
+	}
+	return true
+}
+
+// signTLSPubKeys signs the node's TLS public keys, so that sentry nodes can
+// attribute them to this node.
+func (w *Worker) signTLSPubKeys() (*sentryAPI.SignedUpstreamTLSPubKeys, error) {
+	return sentryAPI.SignUpstreamTLSPubKeys(w.identity.NodeSigner, &sentryAPI.UpstreamTLSPubKeys{
+		PubKeys: w.identity.GetTLSPubKeys(),
+	})
+}
+
 func (w *Worker) querySentries() ([]node.ConsensusAddress, []node.TLSAddress) {
 	var consensusAddrs []node.ConsensusAddress
 	var tlsAddrs []node.TLSAddress
 	var err error
 
-	pubKeys := w.identity.GetTLSPubKeys()
+	pubKeys, err := w.signTLSPubKeys()
+	if err != nil {
+		w.logger.Error("failed to sign TLS public keys for sentry nodes",
+			"err", err,
+		)
+		return nil, nil
+	}
 	for _, sentryAddr := range w.sentryAddresses {
 		var client *sentryClient.Client
 		client, err = sentryClient.New(sentryAddr, w.identity)
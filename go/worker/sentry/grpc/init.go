@@ -9,6 +9,7 @@ import (
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/resolver"
 	"google.golang.org/grpc/resolver/manual"
 
@@ -28,9 +29,11 @@ const (
 	// CfgEnabled enables the sentry grpc worker.
 	CfgEnabled = "worker.sentry.grpc.enabled"
 
-	// CfgUpstreamAddress is the grpc address of the upstream node.
+	// CfgUpstreamAddress are the grpc addresses of the upstream nodes, in
+	// order of preference.
 	CfgUpstreamAddress = "worker.sentry.grpc.upstream.address"
-	// CfgUpstreamID is the node ID of the upstream node.
+	// CfgUpstreamID are the node IDs of the upstream nodes, in the same order
+	// as the upstream addresses.
 	CfgUpstreamID = "worker.sentry.grpc.upstream.id"
 
 	// CfgClientAddresses are addresses on which the gRPC endpoint is reachable.
@@ -51,77 +54,96 @@ func GetNodeAddresses() ([]node.Address, error) {
 	return clientAddresses, nil
 }
 
-func initConnection(ctx context.Context, logger *logging.Logger, ident *identity.Identity, backend sentry.Backend) (*upstreamConn, error) {
+func initUpstreams(ctx context.Context, logger *logging.Logger, ident *identity.Identity, backend sentry.Backend) (*upstreamPool, error) {
 	var err error
 
-	addr := viper.GetString(CfgUpstreamAddress)
-
-	upstreamAddrs, err := configparser.ParseAddressList([]string{addr})
+	addrs := viper.GetStringSlice(CfgUpstreamAddress)
+	upstreamAddrs, err := configparser.ParseAddressList(addrs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse address: %s: %w", addr, err)
+		return nil, fmt.Errorf("failed to parse upstream addresses: %w", err)
 	}
-
-	upstreamNodeIDRaw := viper.GetString(CfgUpstreamID)
-	var upstreamNodeID signature.PublicKey
-	err = upstreamNodeID.UnmarshalText([]byte(upstreamNodeIDRaw))
-	if err != nil {
-		return nil, fmt.Errorf("malformed upstream node ID: %s: %w", upstreamNodeIDRaw, err)
+	if len(upstreamAddrs) == 0 {
+		return nil, fmt.Errorf("no upstream addresses configured")
 	}
 
-	logger.Info("upstream node ID is valid",
-		"upstream_node_id", upstreamNodeIDRaw,
-	)
-
-	// Get upstream node's TLS public keys.
-	upstreamPubKeys, err := backend.GetUpstreamTLSPubKeys(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get upstream node's TLS public keys: %w", err)
-	}
-	if len(upstreamPubKeys) == 0 {
-		return nil, fmt.Errorf("upstream node has no defined TLS public keys")
+	rawNodeIDs := viper.GetStringSlice(CfgUpstreamID)
+	if len(rawNodeIDs) != len(upstreamAddrs) {
+		return nil, fmt.Errorf("each upstream address must have a corresponding upstream node ID")
 	}
-	logger.Info("found public keys for upstream node",
-		"num_keys", len(upstreamPubKeys),
-	)
 
-	pubKeys := make(map[signature.PublicKey]bool)
-	for _, pk := range upstreamPubKeys {
-		pubKeys[pk] = true
-	}
-	creds, err := cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
-		CommonName:    identity.CommonName,
-		ServerPubKeys: pubKeys,
-		GetClientCertificate: func(cri *tlsPkg.CertificateRequestInfo) (*tlsPkg.Certificate, error) {
-			return ident.GetTLSCertificate(), nil
-		},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create TLS credentials: %w", err)
+	// Dial each upstream node separately, so that the health of each
+	// connection can be tracked independently and each upstream node is
+	// only accepted if it presents one of its own TLS public keys.
+	var upstreams []*upstream
+	closeUpstreams := func() {
+		for _, u := range upstreams {
+			u.conn.Close()
+		}
 	}
+	for i, addr := range upstreamAddrs {
+		var nodeID signature.PublicKey
+		if err = nodeID.UnmarshalText([]byte(rawNodeIDs[i])); err != nil {
+			closeUpstreams()
+			return nil, fmt.Errorf("malformed upstream node ID: %s: %w", rawNodeIDs[i], err)
+		}
 
-	// Dial node
-	manualResolver := manual.NewBuilderWithScheme("oasis-core-resolver")
-	conn, err := cmnGrpc.Dial("oasis-core-resolver:///",
-		grpc.WithTransportCredentials(creds),
-		// https://github.com/grpc/grpc-go/issues/3003
-		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
-		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
-		grpc.WithResolvers(manualResolver),
-	) //nolint: staticcheck
-	if err != nil {
-		return nil, fmt.Errorf("error dialing node: %w", err)
-	}
-	var resolverState resolver.State
-	for _, addr := range upstreamAddrs {
-		resolverState.Addresses = append(resolverState.Addresses, resolver.Address{Addr: addr.String()})
+		// Get upstream node's TLS public keys.
+		var upstreamPubKeys []signature.PublicKey
+		upstreamPubKeys, err = backend.GetUpstreamTLSPubKeys(ctx, nodeID)
+		if err != nil {
+			closeUpstreams()
+			return nil, fmt.Errorf("failed to get upstream node's TLS public keys: %w", err)
+		}
+		if len(upstreamPubKeys) == 0 {
+			closeUpstreams()
+			return nil, fmt.Errorf("upstream node %s has no defined TLS public keys", nodeID)
+		}
+		logger.Info("found public keys for upstream node",
+			"node_id", nodeID,
+			"address", addr,
+			"num_keys", len(upstreamPubKeys),
+		)
+
+		pubKeys := make(map[signature.PublicKey]bool)
+		for _, pk := range upstreamPubKeys {
+			pubKeys[pk] = true
+		}
+		var creds credentials.TransportCredentials
+		creds, err = cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
+			CommonName:    identity.CommonName,
+			ServerPubKeys: pubKeys,
+			GetClientCertificate: func(cri *tlsPkg.CertificateRequestInfo) (*tlsPkg.Certificate, error) {
+				return ident.GetTLSCertificate(), nil
+			},
+		})
+		if err != nil {
+			closeUpstreams()
+			return nil, fmt.Errorf("failed to create TLS credentials: %w", err)
+		}
+
+		manualResolver := manual.NewBuilderWithScheme("oasis-core-resolver")
+		var conn *grpc.ClientConn
+		conn, err = cmnGrpc.Dial("oasis-core-resolver:///",
+			grpc.WithTransportCredentials(creds),
+			grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+			grpc.WithResolvers(manualResolver),
+		) //nolint: staticcheck
+		if err != nil {
+			closeUpstreams()
+			return nil, fmt.Errorf("error dialing node %s: %w", addr, err)
+		}
+		manualResolver.UpdateState(resolver.State{
+			Addresses: []resolver.Address{{Addr: addr.String()}},
+		})
+
+		upstreams = append(upstreams, &upstream{
+			nodeID:  nodeID,
+			address: addr,
+			conn:    conn,
+		})
 	}
-	manualResolver.UpdateState(resolverState)
 
-	return &upstreamConn{
-		nodeID:  upstreamNodeID,
-		pubKeys: upstreamPubKeys,
-		conn:    conn,
-	}, nil
+	return newUpstreamPool(logger, upstreams), nil
 }
 
 // New creates a new sentry grpc worker.
@@ -147,16 +169,8 @@ func New(backend sentry.Backend, identity *identity.Identity) (*Worker, error) {
 	if g.enabled {
 		logger.Info("Initializing gRPC sentry worker")
 
-		g.upstreamDialer = func(ctx context.Context) (*grpc.ClientConn, error) {
-			g.upstreamDialerMutex.Lock()
-			defer g.upstreamDialerMutex.Unlock()
-
-			upstreamConn, err := initConnection(g.ctx, logger, identity, backend)
-			if err != nil {
-				return nil, fmt.Errorf("gRPC sentry worker initializing upstream connection failure: %w", err)
-			}
-			g.upstreamConn = upstreamConn
-			return upstreamConn.conn, nil
+		g.initUpstreams = func() (*upstreamPool, error) {
+			return initUpstreams(g.ctx, logger, identity, backend)
 		}
 
 		// Create externally-accessible proxy gRPC server.
@@ -167,7 +181,7 @@ func New(backend sentry.Backend, identity *identity.Identity) (*Worker, error) {
 			AuthFunc: g.authFunction(),
 			CustomOptions: []grpc.ServerOption{
 				// All unknown requests will be proxied to the upstream grpc server.
-				grpc.UnknownServiceHandler(proxy.Handler(g.upstreamDialer)),
+				grpc.UnknownServiceHandler(proxy.Handler(g.dialUpstream)),
 			},
 		}
 		grpcServer, err := cmnGrpc.NewServer(serverConfig)
@@ -182,8 +196,8 @@ func New(backend sentry.Backend, identity *identity.Identity) (*Worker, error) {
 
 func init() {
 	Flags.Bool(CfgEnabled, false, "Enable Sentry gRPC worker (NOTE: This should only be enabled on gRPC Sentry nodes.)")
-	Flags.String(CfgUpstreamAddress, "", "Address of the upstream node")
-	Flags.String(CfgUpstreamID, "", "ID of the upstream node")
+	Flags.StringSlice(CfgUpstreamAddress, []string{}, "Address(es) of the upstream node(s), in order of preference")
+	Flags.StringSlice(CfgUpstreamID, []string{}, "ID(s) of the upstream node(s), one for each upstream address")
 	Flags.StringSlice(CfgClientAddresses, []string{}, "Address/port(s) to use for client connections for accessing this node")
 	Flags.Uint16(CfgClientPort, 9100, "Port to use for incoming gRPC client connections")
 
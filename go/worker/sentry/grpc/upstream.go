@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
+)
+
+// upstream is a connection to a single upstream node address.
+type upstream struct {
+	nodeID  signature.PublicKey
+	address node.Address
+	conn    *grpc.ClientConn
+}
+
+// upstreamPool is a set of connections to upstream nodes that selects the
+// connection to use based on the health of the connections.
+//
+// Upstream nodes are ordered by preference. The most preferred healthy
+// upstream node is always used, so traffic fails over to less preferred
+// upstream nodes while more preferred ones are unhealthy, and fails back
+// once they recover.
+type upstreamPool struct {
+	sync.Mutex
+
+	logger *logging.Logger
+
+	upstreams []*upstream
+	current   *upstream
+
+	// changedCh is closed and replaced whenever the state of any upstream
+	// connection changes.
+	changedCh chan struct{}
+}
+
+// isHealthy returns true iff the connection to the given upstream node is
+// ready to be used.
+func (p *upstreamPool) isHealthy(u *upstream) bool {
+	return u.conn.GetState() == connectivity.Ready
+}
+
+// selectUpstream returns the most preferred healthy upstream node, if any,
+// and a channel that is closed once the state of any upstream changes.
+func (p *upstreamPool) selectUpstream() (*upstream, <-chan struct{}) {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, u := range p.upstreams {
+		if !p.isHealthy(u) {
+			continue
+		}
+
+		if p.current != u {
+			p.logger.Info("switching to upstream node",
+				"node_id", u.nodeID,
+				"address", u.address,
+				"previous_address", p.currentAddressLocked(),
+			)
+			p.current = u
+		}
+		return u, p.changedCh
+	}
+	return nil, p.changedCh
+}
+
+func (p *upstreamPool) currentAddressLocked() interface{} {
+	if p.current == nil {
+		return nil
+	}
+	return p.current.address
+}
+
+// Dial returns the connection to the most preferred healthy upstream node,
+// waiting until any upstream node becomes healthy or the context is done.
+func (p *upstreamPool) Dial(ctx context.Context) (*grpc.ClientConn, error) {
+	for {
+		u, changedCh := p.selectUpstream()
+		if u != nil {
+			return u.conn, nil
+		}
+
+		select {
+		case <-changedCh:
+		case <-ctx.Done():
+			return nil, status.Errorf(codes.Unavailable, "no healthy upstream node available")
+		}
+	}
+}
+
+// monitor watches the state of the connection to the given upstream node
+// until the context is done.
+func (p *upstreamPool) monitor(ctx context.Context, u *upstream) {
+	for {
+		state := u.conn.GetState()
+		p.logger.Debug("upstream connection state changed",
+			"address", u.address,
+			"state", state,
+		)
+
+		p.Lock()
+		close(p.changedCh)
+		p.changedCh = make(chan struct{})
+		p.Unlock()
+
+		if !u.conn.WaitForStateChange(ctx, state) {
+			return
+		}
+	}
+}
+
+// Start starts monitoring the upstream connections until the context is
+// done.
+func (p *upstreamPool) Start(ctx context.Context) {
+	for _, u := range p.upstreams {
+		go p.monitor(ctx, u)
+	}
+}
+
+// Close closes all upstream connections.
+func (p *upstreamPool) Close() {
+	for _, u := range p.upstreams {
+		u.conn.Close()
+	}
+}
+
+func newUpstreamPool(logger *logging.Logger, upstreams []*upstream) *upstreamPool {
+	return &upstreamPool{
+		logger:    logger,
+		upstreams: upstreams,
+		changedCh: make(chan struct{}),
+	}
+}
@@ -13,12 +13,10 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common/accessctl"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
-	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
 	"github.com/oasislabs/oasis-core/go/common/grpc/auth"
 	"github.com/oasislabs/oasis-core/go/common/grpc/policy"
 	policyAPI "github.com/oasislabs/oasis-core/go/common/grpc/policy/api"
-	grpcProxy "github.com/oasislabs/oasis-core/go/common/grpc/proxy"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/service"
@@ -35,9 +33,10 @@ type Worker struct { // nolint: maligned
 	ctx       context.Context
 	cancelCtx context.CancelFunc
 
-	initCh chan struct{}
-	stopCh chan struct{}
-	quitCh chan struct{}
+	initCh   chan struct{}
+	initOnce sync.Once
+	stopCh   chan struct{}
+	quitCh   chan struct{}
 
 	logger *logging.Logger
 
@@ -45,22 +44,24 @@ type Worker struct { // nolint: maligned
 	// Per service policy checkers.
 	grpcPolicyCheckers map[cmnGrpc.ServiceName]*policy.DynamicRuntimePolicyChecker
 
-	*upstreamConn
-
-	upstreamDialer      grpcProxy.Dialer
-	upstreamDialerMutex sync.Mutex
+	initUpstreams func() (*upstreamPool, error)
+	upstreams     *upstreamPool
 
 	grpc     *cmnGrpc.Server
 	identity *identity.Identity
 }
 
-type upstreamConn struct {
-	// ID of the upstream node.
-	nodeID signature.PublicKey
-	// TLS public keys for the upstream node.
-	pubKeys []signature.PublicKey
-	// Client connection to the upstream node.
-	conn *grpc.ClientConn
+// dialUpstream returns the connection to the most preferred healthy upstream
+// node.
+func (g *Worker) dialUpstream(ctx context.Context) (*grpc.ClientConn, error) {
+	g.RLock()
+	upstreams := g.upstreams
+	g.RUnlock()
+
+	if upstreams == nil {
+		return nil, status.Errorf(codes.Unavailable, "upstream connections not initialized")
+	}
+	return upstreams.Dial(ctx)
 }
 
 func (g *Worker) authFunction() auth.AuthenticationFunction {
@@ -171,27 +172,52 @@ func (g *Worker) worker() {
 	defer close(g.quitCh)
 	defer (g.cancelCtx)()
 
-	if g.upstreamConn == nil {
-		dialUpstream := func() error {
-			_, err := g.upstreamDialer(g.ctx)
-			if err != nil {
-				return err
-			}
-			return nil
-		}
+	var upstreams *upstreamPool
+	dialUpstreams := func() (err error) {
+		upstreams, err = g.initUpstreams()
+		return
+	}
 
-		sched := backoff.WithMaxRetries(backoff.NewConstantBackOff(1*time.Second), 60)
-		err := backoff.Retry(dialUpstream, backoff.WithContext(sched, g.ctx))
-		if err != nil {
-			g.logger.Error("unable to dial upstream node",
-				"err", err,
-			)
+	sched := backoff.WithMaxRetries(backoff.NewConstantBackOff(1*time.Second), 60)
+	err := backoff.Retry(dialUpstreams, backoff.WithContext(sched, g.ctx))
+	if err != nil {
+		g.logger.Error("unable to dial upstream nodes",
+			"err", err,
+		)
+		return
+	}
+	defer upstreams.Close()
+	upstreams.Start(g.ctx)
+
+	g.Lock()
+	g.upstreams = upstreams
+	g.Unlock()
+
+	// Watch policies, re-establishing the watch on the currently selected
+	// upstream node whenever the stream is closed.
+	for {
+		g.watchPolicies()
+
+		select {
+		case <-g.stopCh:
+			return
+		case <-g.grpc.Quit():
 			return
+		case <-time.After(1 * time.Second):
 		}
 	}
+}
+
+func (g *Worker) watchPolicies() {
+	conn, err := g.dialUpstream(g.ctx)
+	if err != nil {
+		g.logger.Error("failed to dial upstream node",
+			"err", err,
+		)
+		return
+	}
 
-	// Initialize policy watcher.
-	g.policyWatcher = policyAPI.NewPolicyWatcherClient(g.conn)
+	g.policyWatcher = policyAPI.NewPolicyWatcherClient(conn)
 	ch, sub, err := g.policyWatcher.WatchPolicies(g.ctx)
 	if err != nil {
 		g.logger.Error("failed to watch policies",
@@ -202,14 +228,15 @@ func (g *Worker) worker() {
 	defer sub.Close()
 
 	// Initialization complete.
-	close(g.initCh)
+	g.initOnce.Do(func() {
+		close(g.initCh)
+	})
 
-	// Watch policies.
 	for {
 		select {
 		case p, ok := <-ch:
 			if !ok {
-				g.logger.Error("WatchPolicies stream closed")
+				g.logger.Error("WatchPolicies stream closed, reconnecting")
 				return
 			}
 
@@ -6,7 +6,9 @@ import (
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/grpc/auth"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/sentry/api"
@@ -18,6 +20,10 @@ const (
 	CfgEnabled = "worker.sentry.enabled"
 	// CfgControlPort configures the sentry worker's control port.
 	CfgControlPort = "worker.sentry.control_port"
+	// CfgAuthorizedControlPubkeys configures the sentry client TLS public
+	// keys of upstream nodes that are allowed to use the sentry worker's
+	// control service.
+	CfgAuthorizedControlPubkeys = "worker.sentry.control.authorized_pubkey"
 )
 
 // Flags has the configuration flags.
@@ -113,6 +119,26 @@ func (w *Worker) Cleanup() {
 	w.grpcServer.Cleanup()
 }
 
+// controlAuthFunction returns the authentication function of the control
+// service, restricting access to the authorized upstream nodes.
+func (w *Worker) controlAuthFunction() (auth.AuthenticationFunction, error) {
+	rawPubKeys := viper.GetStringSlice(CfgAuthorizedControlPubkeys)
+	if len(rawPubKeys) == 0 {
+		w.logger.Warn("no authorized upstream nodes configured, any node is allowed to use the control service")
+		return auth.NoAuth, nil
+	}
+
+	authenticator := auth.NewPeerCertAuthenticator()
+	for _, rawPubKey := range rawPubKeys {
+		var pubKey signature.PublicKey
+		if err := pubKey.UnmarshalText([]byte(rawPubKey)); err != nil {
+			return nil, fmt.Errorf("malformed authorized control public key: %s: %w", rawPubKey, err)
+		}
+		authenticator.AllowPeerPublicKey(pubKey)
+	}
+	return authenticator.AuthFunc, nil
+}
+
 // New creates a new sentry worker.
 func New(backend api.Backend, identity *identity.Identity) (*Worker, error) {
 	w := &Worker{
@@ -123,10 +149,16 @@ func New(backend api.Backend, identity *identity.Identity) (*Worker, error) {
 	}
 
 	if w.enabled {
+		authFunc, err := w.controlAuthFunction()
+		if err != nil {
+			return nil, fmt.Errorf("worker/sentry: %w", err)
+		}
+
 		grpcServer, err := grpc.NewServer(&grpc.ServerConfig{
 			Name:     "sentry",
 			Port:     uint16(viper.GetInt(CfgControlPort)),
 			Identity: identity,
+			AuthFunc: authFunc,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("worker/sentry: failed to create a new gRPC server: %w", err)
@@ -149,6 +181,7 @@ func New(backend api.Backend, identity *identity.Identity) (*Worker, error) {
 func init() {
 	Flags.Bool(CfgEnabled, false, "Enable Sentry worker (NOTE: This should only be enabled on Sentry nodes.)")
 	Flags.Uint16(CfgControlPort, 9009, "Sentry worker's gRPC server port (NOTE: This should only be enabled on Sentry nodes.)")
+	Flags.StringSlice(CfgAuthorizedControlPubkeys, []string{}, "Sentry client TLS public key(s) of upstream nodes allowed to use the sentry worker's control service")
 	Flags.AddFlagSet(workerGrpcSentry.Flags)
 
 	_ = viper.BindPFlags(Flags)
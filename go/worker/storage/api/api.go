@@ -21,6 +21,9 @@ type StorageWorker interface {
 
 	// ForceFinalize forces finalization of a specific round.
 	ForceFinalize(ctx context.Context, request *ForceFinalizeRequest) error
+
+	// GetUsage retrieves the storage usage of a specific runtime.
+	GetUsage(ctx context.Context, request *GetUsageRequest) (*storage.Usage, error)
 }
 
 // GetLastSyncedRoundRequest is a GetLastSyncedRound request.
@@ -40,3 +43,8 @@ type ForceFinalizeRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 	Round     uint64           `json:"round"`
 }
+
+// GetUsageRequest is a GetUsage request.
+type GetUsageRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+}
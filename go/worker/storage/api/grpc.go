@@ -6,6 +6,7 @@ import (
 	"google.golang.org/grpc"
 
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	storage "github.com/oasislabs/oasis-core/go/storage/api"
 )
 
 var (
@@ -16,6 +17,8 @@ var (
 	methodGetLastSyncedRound = serviceName.NewMethod("GetLastSyncedRound", &GetLastSyncedRoundRequest{})
 	// methodForceFinalize is the ForceFinalize method.
 	methodForceFinalize = serviceName.NewMethod("ForceFinalize", &ForceFinalizeRequest{})
+	// methodGetUsage is the GetUsage method.
+	methodGetUsage = serviceName.NewMethod("GetUsage", &GetUsageRequest{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -30,6 +33,10 @@ var (
 				MethodName: methodForceFinalize.ShortName(),
 				Handler:    handlerForceFinalize,
 			},
+			{
+				MethodName: methodGetUsage.ShortName(),
+				Handler:    handlerGetUsage,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -81,6 +88,29 @@ func handlerForceFinalize( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerGetUsage( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(GetUsageRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageWorker).GetUsage(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetUsage.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageWorker).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 // RegisterService registers a new storage worker service with the given gRPC server.
 func RegisterService(server *grpc.Server, service StorageWorker) {
 	server.RegisterService(&serviceDesc, service)
@@ -102,6 +132,14 @@ func (c *storageWorkerClient) ForceFinalize(ctx context.Context, req *ForceFinal
 	return c.conn.Invoke(ctx, methodForceFinalize.FullName(), req, nil)
 }
 
+func (c *storageWorkerClient) GetUsage(ctx context.Context, req *GetUsageRequest) (*storage.Usage, error) {
+	var rsp storage.Usage
+	if err := c.conn.Invoke(ctx, methodGetUsage.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 // NewStorageWorkerClient creates a new gRPC transaction scheduler
 // client service.
 func NewStorageWorkerClient(c *grpc.ClientConn) StorageWorker {
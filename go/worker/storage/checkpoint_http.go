@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/checkpoint"
+)
+
+// checkpointHTTPServer serves storage checkpoints over plain HTTP.
+type checkpointHTTPServer struct {
+	logger *logging.Logger
+
+	address string
+
+	listener net.Listener
+	server   *http.Server
+}
+
+func (s *checkpointHTTPServer) start() error {
+	s.logger.Info("checkpoint HTTP endpoint is enabled",
+		"address", s.address,
+	)
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.server.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("checkpoint HTTP server terminated uncleanly",
+				"err", err,
+			)
+		}
+	}()
+
+	return nil
+}
+
+func (s *checkpointHTTPServer) stop() {
+	_ = s.server.Shutdown(context.Background())
+}
+
+func newCheckpointHTTPServer(address string, provider checkpoint.ChunkProvider, signer signature.Signer) *checkpointHTTPServer {
+	return &checkpointHTTPServer{
+		logger:  logging.GetLogger("worker/storage/checkpoint_http"),
+		address: address,
+		server:  &http.Server{Handler: checkpoint.NewHTTPHandler(provider, signer)},
+	}
+}
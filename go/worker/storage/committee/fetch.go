@@ -0,0 +1,117 @@
+package committee
+
+import (
+	"container/heap"
+	"sync"
+
+	mkvsNode "github.com/oasislabs/oasis-core/go/storage/mkvs/node"
+)
+
+// fetchRequest is a pending GetDiff operation.
+type fetchRequest struct {
+	round     uint64
+	prevRoot  mkvsNode.Root
+	thisRoot  mkvsNode.Root
+	fetchMask outstandingMask
+}
+
+// fetchQueue is a priority queue of pending fetches.
+//
+// State root fetches for rounds up to (and including) the priority round are
+// ordered before all other fetches as those are the roots needed by the local
+// executor. All other fetches are ordered by round, with state roots preceding
+// I/O roots of the same round.
+type fetchQueue struct {
+	requests []*fetchRequest
+
+	hasPriority   bool
+	priorityRound uint64
+}
+
+func (q *fetchQueue) isPriority(r *fetchRequest) bool {
+	return q.hasPriority && r.fetchMask == maskState && r.round <= q.priorityRound
+}
+
+// Sorting interface.
+func (q *fetchQueue) Len() int { return len(q.requests) }
+
+func (q *fetchQueue) Less(i, j int) bool {
+	ri, rj := q.requests[i], q.requests[j]
+	if pi, pj := q.isPriority(ri), q.isPriority(rj); pi != pj {
+		return pi
+	}
+	if ri.round != rj.round {
+		return ri.round < rj.round
+	}
+	return ri.fetchMask == maskState && rj.fetchMask != maskState
+}
+
+func (q *fetchQueue) Swap(i, j int) { q.requests[i], q.requests[j] = q.requests[j], q.requests[i] }
+
+// Push appends x as the last element in the heap's array.
+func (q *fetchQueue) Push(x interface{}) {
+	q.requests = append(q.requests, x.(*fetchRequest))
+}
+
+// Pop removes and returns the last element in the heap's array.
+func (q *fetchQueue) Pop() interface{} {
+	old := q.requests
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	q.requests = old[0 : n-1]
+	return x
+}
+
+// setPriorityRound updates the priority round and reorders the queue.
+func (q *fetchQueue) setPriorityRound(round uint64) bool {
+	if q.hasPriority && round <= q.priorityRound {
+		return false
+	}
+	q.hasPriority = true
+	q.priorityRound = round
+	heap.Init(q)
+	return true
+}
+
+// scheduleFetch queues a fetch and submits a job to the (shared) fetch pool.
+//
+// The job performs whichever pending fetch has the highest priority at the
+// time a pool worker becomes available, so that priority hints also affect
+// fetches that have already been scheduled.
+func (n *Node) scheduleFetch(fetcherGroup *sync.WaitGroup, req *fetchRequest) {
+	n.fetchLock.Lock()
+	heap.Push(&n.fetchQueue, req)
+	n.fetchLock.Unlock()
+
+	fetcherGroup.Add(1)
+	n.fetchPool.Submit(func() {
+		defer fetcherGroup.Done()
+
+		n.fetchLock.Lock()
+		next := heap.Pop(&n.fetchQueue).(*fetchRequest)
+		n.fetchLock.Unlock()
+
+		n.fetchDiff(next.round, &next.prevRoot, &next.thisRoot, next.fetchMask)
+	})
+}
+
+// PrioritizeRound hints the storage syncer that the state root of the given
+// round will be needed imminently (e.g., because the local executor will
+// process the next round on top of it). State root fetches up to the given
+// round are then performed before any other fetches, including I/O roots
+// which are not needed for execution.
+//
+// Hints are monotonic, hints for earlier rounds than a previous hint are
+// ignored.
+func (n *Node) PrioritizeRound(round uint64) {
+	n.fetchLock.Lock()
+	defer n.fetchLock.Unlock()
+
+	if n.fetchQueue.setPriorityRound(round) {
+		n.logger.Debug("prioritizing state sync",
+			"round", round,
+			"pending_fetches", n.fetchQueue.Len(),
+		)
+	}
+}
@@ -0,0 +1,47 @@
+package committee
+
+import (
+	"container/heap"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchQueue(t *testing.T) {
+	require := require.New(t)
+
+	var q fetchQueue
+	for round := uint64(1); round <= 3; round++ {
+		heap.Push(&q, &fetchRequest{round: round, fetchMask: maskIO})
+		heap.Push(&q, &fetchRequest{round: round, fetchMask: maskState})
+	}
+
+	pop := func() (uint64, outstandingMask) {
+		r := heap.Pop(&q).(*fetchRequest)
+		return r.round, r.fetchMask
+	}
+
+	// Without a hint, fetches are ordered by round, state roots first.
+	round, mask := pop()
+	require.EqualValues(1, round)
+	require.Equal(maskState, mask)
+
+	// With a hint, state roots up to the priority round come first.
+	require.True(q.setPriorityRound(3), "setPriorityRound")
+	require.False(q.setPriorityRound(2), "setPriorityRound should ignore earlier rounds")
+	for _, expected := range []struct {
+		round uint64
+		mask  outstandingMask
+	}{
+		{2, maskState},
+		{3, maskState},
+		{1, maskIO},
+		{2, maskIO},
+		{3, maskIO},
+	} {
+		round, mask = pop()
+		require.Equal(expected.round, round)
+		require.Equal(expected.mask, mask)
+	}
+	require.Equal(0, q.Len())
+}
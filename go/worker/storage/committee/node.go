@@ -1,6 +1,7 @@
 package committee
 
 import (
+	"bytes"
 	"container/heap"
 	"context"
 	"errors"
@@ -10,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/eapache/channels"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/accessctl"
@@ -40,6 +42,36 @@ var (
 
 	// ErrNonLocalBackend is the error returned when the storage backend doesn't implement the LocalBackend interface.
 	ErrNonLocalBackend = errors.New("storage: storage backend doesn't support local storage")
+
+	storageUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_storage_usage_bytes",
+			Help: "Accounted storage usage of the runtime (bytes).",
+		},
+		[]string{"runtime"},
+	)
+	storageDiskSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_storage_disk_size_bytes",
+			Help: "On-disk size of the runtime's node database (bytes).",
+		},
+		[]string{"runtime"},
+	)
+	storageQuota = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_storage_quota_bytes",
+			Help: "Storage quota of the runtime (bytes, zero if unlimited).",
+		},
+		[]string{"runtime"},
+	)
+
+	nodeCollectors = []prometheus.Collector{
+		storageUsage,
+		storageDiskSize,
+		storageQuota,
+	}
+
+	metricsOnce sync.Once
 )
 
 const (
@@ -158,7 +190,9 @@ type Node struct {
 	grpcPolicy     *policy.DynamicRuntimePolicyChecker
 	undefinedRound uint64
 
-	fetchPool *workerpool.Pool
+	fetchPool  *workerpool.Pool
+	fetchLock  sync.Mutex
+	fetchQueue fetchQueue
 
 	stateStore *persistent.ServiceStore
 
@@ -166,6 +200,8 @@ type Node struct {
 
 	checkpointer checkpoint.Checkpointer
 
+	genesisCheckpointProvider checkpoint.ChunkProvider
+
 	syncedLock  sync.RWMutex
 	syncedState watcherState
 
@@ -188,7 +224,12 @@ func NewNode(
 	roleProvider registration.RoleProvider,
 	workerCommonCfg workerCommon.Config,
 	checkpointerCfg checkpoint.CheckpointerConfig,
+	genesisCheckpointProvider checkpoint.ChunkProvider,
 ) (*Node, error) {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(nodeCollectors...)
+	})
+
 	localStorage, ok := commonNode.Storage.(storageApi.LocalBackend)
 	if !ok {
 		return nil, ErrNonLocalBackend
@@ -234,6 +275,13 @@ func NewNode(
 	}
 	node.storageClient = scl.(storageApi.ClientBackend)
 
+	// Fetch genesis checkpoints from other storage nodes unless another
+	// source has been configured.
+	node.genesisCheckpointProvider = genesisCheckpointProvider
+	if node.genesisCheckpointProvider == nil {
+		node.genesisCheckpointProvider = node.storageClient
+	}
+
 	// Create a new checkpointer.
 	checkpointerCfg = checkpoint.CheckpointerConfig{
 		Namespace:       commonNode.Runtime.ID(),
@@ -386,6 +434,11 @@ func (n *Node) GetLastSynced() (uint64, mkvsNode.Root, mkvsNode.Root) {
 	return n.syncedState.LastBlock.Round, n.syncedState.LastBlock.IORoot, n.syncedState.LastBlock.StateRoot
 }
 
+// GetUsage returns the storage usage of the runtime.
+func (n *Node) GetUsage(ctx context.Context) (*storageApi.Usage, error) {
+	return n.localStorage.NodeDB().GetUsage(ctx)
+}
+
 // ForceFinalize forces a storage finalization for the given round.
 func (n *Node) ForceFinalize(ctx context.Context, round uint64) error {
 	n.logger.Debug("forcing round finalization",
@@ -465,6 +518,28 @@ func (n *Node) fetchDiff(round uint64, prevRoot *mkvsNode.Root, thisRoot *mkvsNo
 	}
 }
 
+func (n *Node) applyDiff(diff *fetchedDiff) {
+	// Apply the write log if one exists.
+	if !diff.fetched {
+		return
+	}
+	_, err := n.localStorage.Apply(n.ctx, &storageApi.ApplyRequest{
+		Namespace: diff.thisRoot.Namespace,
+		SrcRound:  diff.prevRoot.Version,
+		SrcRoot:   diff.prevRoot.Hash,
+		DstRound:  diff.thisRoot.Version,
+		DstRoot:   diff.thisRoot.Hash,
+		WriteLog:  diff.writeLog,
+	})
+	if err != nil {
+		n.logger.Error("can't apply write log",
+			"err", err,
+			"old_root", diff.prevRoot,
+			"new_root", diff.thisRoot,
+		)
+	}
+}
+
 func (n *Node) finalize(summary *blockSummary) {
 	err := n.localStorage.NodeDB().Finalize(n.ctx, summary.Round, []hash.Hash{
 		summary.IORoot.Hash,
@@ -475,6 +550,9 @@ func (n *Node) finalize(summary *blockSummary) {
 		n.logger.Debug("storage round finalized",
 			"round", summary.Round,
 		)
+
+		n.recordConsensusHeight(summary.Round)
+		n.updateUsageMetrics()
 	case storageApi.ErrAlreadyFinalized:
 		// This can happen if we are restoring after a roothash migration or if
 		// we crashed before updating the sync state.
@@ -491,6 +569,51 @@ func (n *Node) finalize(summary *blockSummary) {
 	n.finalizeCh <- summary
 }
 
+// recordConsensusHeight records the consensus height of the block that
+// produced the given round in the version metadata of the node database.
+func (n *Node) recordConsensusHeight(round uint64) {
+	// The block may not be in the local history (e.g., for the dummy summary of
+	// an undefined round), in which case the height remains unknown.
+	blk, err := n.commonNode.Runtime.History().GetAnnotatedBlock(n.ctx, round)
+	if err != nil {
+		n.logger.Debug("can't get annotated block for round, not recording consensus height",
+			"err", err,
+			"round", round,
+		)
+		return
+	}
+
+	if err = n.localStorage.NodeDB().SetVersionConsensusHeight(n.ctx, round, blk.Height); err != nil {
+		n.logger.Warn("failed to record consensus height of storage round",
+			"err", err,
+			"round", round,
+			"height", blk.Height,
+		)
+	}
+}
+
+func (n *Node) getMetricLabels() prometheus.Labels {
+	return prometheus.Labels{
+		"runtime": n.commonNode.Runtime.ID().String(),
+	}
+}
+
+// updateUsageMetrics updates the storage usage metrics of the runtime.
+func (n *Node) updateUsageMetrics() {
+	usage, err := n.GetUsage(n.ctx)
+	if err != nil {
+		n.logger.Warn("failed to get storage usage",
+			"err", err,
+		)
+		return
+	}
+
+	labels := n.getMetricLabels()
+	storageUsage.With(labels).Set(float64(usage.Size))
+	storageDiskSize.With(labels).Set(float64(usage.DiskSize))
+	storageQuota.With(labels).Set(float64(usage.Quota))
+}
+
 type inFlight struct {
 	outstanding   outstandingMask
 	awaitingRetry outstandingMask
@@ -499,6 +622,16 @@ type inFlight struct {
 func (n *Node) initGenesis(rt *registryApi.Runtime) error {
 	n.logger.Info("initializing storage at genesis")
 
+	// Restore the state from a checkpoint in case one is referenced by the
+	// consensus genesis document.
+	restored, err := n.maybeRestoreGenesisCheckpoint(rt.ID)
+	if err != nil {
+		return err
+	}
+	if restored {
+		return nil
+	}
+
 	if rt.Genesis.State != nil {
 		var emptyRoot hash.Hash
 		emptyRoot.Empty()
@@ -507,7 +640,7 @@ func (n *Node) initGenesis(rt *registryApi.Runtime) error {
 			"state_root", rt.Genesis.StateRoot,
 		)
 
-		_, err := n.localStorage.Apply(n.ctx, &storageApi.ApplyRequest{
+		_, err = n.localStorage.Apply(n.ctx, &storageApi.ApplyRequest{
 			Namespace: rt.ID,
 			SrcRound:  rt.Genesis.Round,
 			SrcRoot:   emptyRoot,
@@ -535,6 +668,62 @@ func (n *Node) initGenesis(rt *registryApi.Runtime) error {
 	return nil
 }
 
+// maybeRestoreGenesisCheckpoint restores the runtime state from the storage
+// checkpoint referenced by the consensus genesis document (if any) and returns
+// true iff the state has been restored.
+//
+// The checkpoint chunks are fetched from other storage nodes (or the
+// configured genesis checkpoint source) and verified against the checkpoint
+// pinned in the genesis document.
+func (n *Node) maybeRestoreGenesisCheckpoint(id common.Namespace) (bool, error) {
+	doc, err := n.commonNode.Consensus.GetGenesisDocument(n.ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get genesis document: %w", err)
+	}
+	rtg := doc.RootHash.RuntimeStates[id]
+	if rtg == nil || rtg.Checkpoint == nil {
+		return false, nil
+	}
+
+	cp := rtg.Checkpoint
+	if n.localStorage.NodeDB().HasRoot(cp.Root) {
+		n.logger.Info("genesis checkpoint state already present",
+			"root", cp.Root,
+		)
+		return true, nil
+	}
+
+	n.logger.Info("restoring genesis state from checkpoint",
+		"root", cp.Root,
+		"num_chunks", len(cp.Chunks),
+	)
+
+	restorer := n.localStorage.Checkpointer()
+	if err = restorer.StartRestore(n.ctx, cp); err != nil {
+		return false, fmt.Errorf("failed to start genesis checkpoint restore: %w", err)
+	}
+	for idx := range cp.Chunks {
+		chunk, err := cp.GetChunkMetadata(uint64(idx))
+		if err != nil {
+			return false, err
+		}
+
+		var buf bytes.Buffer
+		if err = n.genesisCheckpointProvider.GetCheckpointChunk(n.ctx, chunk, &buf); err != nil {
+			return false, fmt.Errorf("failed to fetch genesis checkpoint chunk %d: %w", idx, err)
+		}
+		if _, err = restorer.RestoreChunk(n.ctx, uint64(idx), &buf); err != nil {
+			return false, fmt.Errorf("failed to restore genesis checkpoint chunk %d: %w", idx, err)
+		}
+	}
+
+	n.logger.Info("genesis state restored from checkpoint",
+		"root", cp.Root,
+	)
+
+	return true, nil
+}
+
 func (n *Node) worker() { // nolint: gocyclo
 	defer close(n.quitCh)
 	defer close(n.diffCh)
@@ -591,11 +780,36 @@ func (n *Node) worker() { // nolint: gocyclo
 	outOfOrderDiffs := &outOfOrderRoundQueue{}
 	outOfOrderApplieds := &outOfOrderRoundQueue{}
 	syncingRounds := make(map[uint64]*inFlight)
+	syncedRounds := make(map[uint64]bool)
 	hashCache := make(map[uint64]*blockSummary)
 	lastFullyAppliedRound := cachedLastRound
+	lastAppliedStateRound := cachedLastRound
 
 	heap.Init(outOfOrderDiffs)
 
+	// markApplied records that the given roots of a round have been applied
+	// and schedules the round for finalization once it is fully synced.
+	markApplied := func(round uint64, mask outstandingMask) {
+		syncing := syncingRounds[round]
+		syncing.outstanding &= ^mask
+		if syncing.outstanding != maskNone || syncing.awaitingRetry != maskNone {
+			return
+		}
+
+		n.logger.Debug("finished syncing round", "round", round)
+		delete(syncingRounds, round)
+		heap.Push(outOfOrderApplieds, hashCache[round])
+
+		// Rounds can finish syncing out of order as I/O roots do not depend
+		// on the previous round.
+		syncedRounds[round] = true
+		for syncedRounds[lastFullyAppliedRound+1] {
+			delete(syncedRounds, lastFullyAppliedRound+1)
+			lastFullyAppliedRound++
+			delete(hashCache, lastFullyAppliedRound-1)
+		}
+	}
+
 	close(n.initCh)
 
 	// We are now ready to service requests.
@@ -606,55 +820,27 @@ func (n *Node) worker() { // nolint: gocyclo
 
 	// Main processing loop. When a new block comes in, its state and io roots are inspected and their
 	// writelogs fetched from remote storage nodes in case we don't have them locally yet. Fetches are
-	// asynchronous and, once complete, trigger local Apply operations. These are serialized
-	// per round (all applies for a given round have to be complete before applying anyting for following
-	// rounds) using the outOfOrderDiffs priority queue and outOfOrderApplieds. Once a round has all its write
-	// logs applied, a Finalize for it is triggered, again serialized by round but otherwise asynchronous
-	// (outOfOrderApplieds and cachedLastRound).
+	// asynchronous (and ordered by the fetch queue, see PrioritizeRound) and, once complete, trigger
+	// local Apply operations. State root applies are serialized per round using the outOfOrderDiffs
+	// priority queue as each state root builds on the previous one, while I/O root applies happen as
+	// soon as they are fetched. Once a round has all its write logs applied, a Finalize for it is
+	// triggered, serialized by round but otherwise asynchronous (outOfOrderApplieds and cachedLastRound).
 mainLoop:
 	for {
 		// Drain the Apply and Finalize queues first, before waiting for new events in the select
 		// below. Applies are drained first, followed by finalizations (which are asynchronous
 		// but serialized, i.e. only one Finalize can be in progress at a time).
 
-		// Apply any writelogs that came in through fetchDiff, but only if they are for the round
-		// after the last fully applied one (lastFullyAppliedRound).
-		if len(*outOfOrderDiffs) > 0 && lastFullyAppliedRound+1 == (*outOfOrderDiffs)[0].GetRound() {
+		// Apply any state writelogs that came in through fetchDiff, but only if they are for the
+		// round after the last applied one (lastAppliedStateRound).
+		if len(*outOfOrderDiffs) > 0 && lastAppliedStateRound+1 == (*outOfOrderDiffs)[0].GetRound() {
 			lastDiff := heap.Pop(outOfOrderDiffs).(*fetchedDiff)
-			// Apply the write log if one exists.
-			if lastDiff.fetched {
-				_, err = n.localStorage.Apply(n.ctx, &storageApi.ApplyRequest{
-					Namespace: lastDiff.thisRoot.Namespace,
-					SrcRound:  lastDiff.prevRoot.Version,
-					SrcRoot:   lastDiff.prevRoot.Hash,
-					DstRound:  lastDiff.thisRoot.Version,
-					DstRoot:   lastDiff.thisRoot.Hash,
-					WriteLog:  lastDiff.writeLog,
-				})
-				if err != nil {
-					n.logger.Error("can't apply write log",
-						"err", err,
-						"old_root", lastDiff.prevRoot,
-						"new_root", lastDiff.thisRoot,
-					)
-				}
-			}
+			n.applyDiff(lastDiff)
+			lastAppliedStateRound = lastDiff.round
 
 			// Check if we have fully synced the given round. If we have, we can proceed
 			// with the Finalize operation.
-			syncing := syncingRounds[lastDiff.round]
-			syncing.outstanding &= ^lastDiff.fetchMask
-			if syncing.outstanding == maskNone && syncing.awaitingRetry == maskNone {
-				n.logger.Debug("finished syncing round", "round", lastDiff.round)
-				delete(syncingRounds, lastDiff.round)
-				summary := hashCache[lastDiff.round]
-				delete(hashCache, lastDiff.round-1)
-
-				// Finalize storage for this round. This happens asynchronously
-				// with respect to Apply operations for subsequent rounds.
-				lastFullyAppliedRound = lastDiff.round
-				heap.Push(outOfOrderApplieds, summary)
-			}
+			markApplied(lastDiff.round, lastDiff.fetchMask)
 
 			continue
 		}
@@ -723,6 +909,9 @@ mainLoop:
 			}
 
 			for i := lastFullyAppliedRound + 1; i <= blk.Header.Round; i++ {
+				if syncedRounds[i] {
+					continue
+				}
 				syncing, ok := syncingRounds[i]
 				if ok && syncing.outstanding == maskAll {
 					continue
@@ -741,7 +930,7 @@ mainLoop:
 					"awaiting_retry", syncing.awaitingRetry,
 				)
 
-				prev := hashCache[i-1] // Closures take refs, so they need new variables here.
+				prev := hashCache[i-1]
 				this := hashCache[i]
 				prevIORoot := mkvsNode.Root{ // IO roots aren't chained, so clear it (but leave cache intact).
 					Namespace: this.IORoot.Namespace,
@@ -752,19 +941,21 @@ mainLoop:
 				if (syncing.outstanding&maskIO) == 0 && (syncing.awaitingRetry&maskIO) != 0 {
 					syncing.outstanding |= maskIO
 					syncing.awaitingRetry &= ^maskIO
-					fetcherGroup.Add(1)
-					n.fetchPool.Submit(func() {
-						defer fetcherGroup.Done()
-						n.fetchDiff(this.Round, &prevIORoot, &this.IORoot, maskIO)
+					n.scheduleFetch(&fetcherGroup, &fetchRequest{
+						round:     this.Round,
+						prevRoot:  prevIORoot,
+						thisRoot:  this.IORoot,
+						fetchMask: maskIO,
 					})
 				}
 				if (syncing.outstanding&maskState) == 0 && (syncing.awaitingRetry&maskState) != 0 {
 					syncing.outstanding |= maskState
 					syncing.awaitingRetry &= ^maskState
-					fetcherGroup.Add(1)
-					n.fetchPool.Submit(func() {
-						defer fetcherGroup.Done()
-						n.fetchDiff(this.Round, &prev.StateRoot, &this.StateRoot, maskState)
+					n.scheduleFetch(&fetcherGroup, &fetchRequest{
+						round:     this.Round,
+						prevRoot:  prev.StateRoot,
+						thisRoot:  this.StateRoot,
+						fetchMask: maskState,
 					})
 				}
 			}
@@ -780,9 +971,17 @@ mainLoop:
 				)
 				syncingRounds[item.round].outstanding &= ^item.fetchMask
 				syncingRounds[item.round].awaitingRetry |= item.fetchMask
-			} else {
-				heap.Push(outOfOrderDiffs, item)
+				break
+			}
+
+			// I/O roots do not depend on the previous round, so they can be
+			// applied immediately.
+			if item.fetchMask == maskIO {
+				n.applyDiff(item)
+				markApplied(item.round, item.fetchMask)
+				break
 			}
+			heap.Push(outOfOrderDiffs, item)
 
 		case finalized := <-n.finalizeCh:
 			// No further sync or out of order handling needed here, since
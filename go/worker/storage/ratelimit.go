@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/accessctl"
+	"github.com/oasislabs/oasis-core/go/storage/api"
+)
+
+// rateLimiterPruneInterval is the interval after which idle rate limiter
+// entries are discarded.
+const rateLimiterPruneInterval = 1 * time.Minute
+
+// tokenBucket is a simple token bucket that refills at a fixed rate up to
+// one second worth of tokens.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+}
+
+func (b *tokenBucket) refill(elapsed time.Duration) {
+	b.tokens += b.rate * elapsed.Seconds()
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+}
+
+type rateLimiterKey struct {
+	runtimeID common.Namespace
+	subject   accessctl.Subject
+}
+
+type rateLimiterEntry struct {
+	requests tokenBucket
+	bytes    tokenBucket
+
+	lastUpdate time.Time
+}
+
+// rateLimiter limits the rate of requests and the request throughput for each
+// runtime and client TLS identity pair.
+type rateLimiter struct {
+	sync.Mutex
+
+	requestsPerSecond uint64
+	bytesPerSecond    uint64
+
+	entries   map[rateLimiterKey]*rateLimiterEntry
+	lastPrune time.Time
+
+	now func() time.Time
+}
+
+// Allow checks whether a request of the given size for the given runtime,
+// made by the client identified by the passed context, is within limits. In
+// case it is not, ErrRateLimited is returned.
+func (rl *rateLimiter) Allow(ctx context.Context, runtimeID common.Namespace, size uint64) error {
+	if rl == nil {
+		return nil
+	}
+
+	key := rateLimiterKey{
+		runtimeID: runtimeID,
+		subject:   peerSubjectFromContext(ctx),
+	}
+
+	rl.Lock()
+	defer rl.Unlock()
+
+	now := rl.now()
+	if now.Sub(rl.lastPrune) >= rateLimiterPruneInterval {
+		rl.pruneLocked(now)
+	}
+
+	entry := rl.entries[key]
+	if entry == nil {
+		entry = &rateLimiterEntry{
+			requests: tokenBucket{
+				rate:   float64(rl.requestsPerSecond),
+				tokens: float64(rl.requestsPerSecond),
+			},
+			bytes: tokenBucket{
+				rate:   float64(rl.bytesPerSecond),
+				tokens: float64(rl.bytesPerSecond),
+			},
+		}
+		rl.entries[key] = entry
+	} else {
+		elapsed := now.Sub(entry.lastUpdate)
+		entry.requests.refill(elapsed)
+		entry.bytes.refill(elapsed)
+	}
+	entry.lastUpdate = now
+
+	if rl.requestsPerSecond > 0 && entry.requests.tokens < 1 {
+		return api.ErrRateLimited
+	}
+	// A single request may be larger than the per-second byte limit, so
+	// only require some tokens to be available and allow the bucket to
+	// go into debt.
+	if rl.bytesPerSecond > 0 && entry.bytes.tokens <= 0 {
+		return api.ErrRateLimited
+	}
+
+	if rl.requestsPerSecond > 0 {
+		entry.requests.tokens--
+	}
+	if rl.bytesPerSecond > 0 {
+		entry.bytes.tokens -= float64(size)
+	}
+
+	return nil
+}
+
+func (rl *rateLimiter) pruneLocked(now time.Time) {
+	// Entries idle for long enough have full buckets and are equivalent to
+	// new entries.
+	for key, entry := range rl.entries {
+		elapsed := now.Sub(entry.lastUpdate)
+		if elapsed < rateLimiterPruneInterval {
+			continue
+		}
+		entry.requests.refill(elapsed)
+		entry.bytes.refill(elapsed)
+		entry.lastUpdate = now
+		if entry.requests.tokens >= entry.requests.rate && entry.bytes.tokens >= entry.bytes.rate {
+			delete(rl.entries, key)
+		}
+	}
+	rl.lastPrune = now
+}
+
+// newRateLimiter creates a new rate limiter. In case both limits are zero,
+// rate limiting is disabled and nil is returned.
+func newRateLimiter(requestsPerSecond, bytesPerSecond uint64) *rateLimiter {
+	if requestsPerSecond == 0 && bytesPerSecond == 0 {
+		return nil
+	}
+
+	return &rateLimiter{
+		requestsPerSecond: requestsPerSecond,
+		bytesPerSecond:    bytesPerSecond,
+		entries:           make(map[rateLimiterKey]*rateLimiterEntry),
+		now:               time.Now,
+	}
+}
+
+func peerSubjectFromContext(ctx context.Context) accessctl.Subject {
+	peer, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsAuth, ok := peer.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsAuth.State.PeerCertificates) != 1 {
+		return ""
+	}
+	return accessctl.SubjectFromX509Certificate(tlsAuth.State.PeerCertificates[0])
+}
+
+func writeLogSize(writeLog api.WriteLog) uint64 {
+	var size uint64
+	for _, entry := range writeLog {
+		size += uint64(len(entry.Key) + len(entry.Value))
+	}
+	return size
+}
+
+func applyBatchSize(request *api.ApplyBatchRequest) uint64 {
+	var size uint64
+	for _, op := range request.Ops {
+		size += writeLogSize(op.WriteLog)
+	}
+	return size
+}
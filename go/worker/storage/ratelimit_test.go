@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/storage/api"
+)
+
+func TestRateLimiter(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(newRateLimiter(0, 0), "rate limiter without limits should be disabled")
+	var disabled *rateLimiter
+	require.NoError(disabled.Allow(context.Background(), common.Namespace{}, 1<<20), "disabled rate limiter should allow everything")
+
+	now := time.Unix(1000, 0)
+	rl := newRateLimiter(2, 100)
+	rl.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	rt1 := common.NewTestNamespaceFromSeed([]byte("rate limiter runtime 1"), 0)
+	rt2 := common.NewTestNamespaceFromSeed([]byte("rate limiter runtime 2"), 0)
+
+	// Request rate limit.
+	require.NoError(rl.Allow(ctx, rt1, 10), "first request should be allowed")
+	require.NoError(rl.Allow(ctx, rt1, 10), "second request should be allowed")
+	err := rl.Allow(ctx, rt1, 10)
+	require.True(errors.Is(err, api.ErrRateLimited), "third request should be rate limited")
+
+	// Limits are per runtime.
+	require.NoError(rl.Allow(ctx, rt2, 10), "request for another runtime should be allowed")
+
+	// Buckets refill over time.
+	now = now.Add(500 * time.Millisecond)
+	require.NoError(rl.Allow(ctx, rt1, 10), "request should be allowed after refill")
+	err = rl.Allow(ctx, rt1, 10)
+	require.True(errors.Is(err, api.ErrRateLimited), "request should be rate limited again")
+
+	// Byte rate limit, a single large request is allowed but puts the bucket
+	// into debt.
+	now = now.Add(1 * time.Second)
+	require.NoError(rl.Allow(ctx, rt1, 250), "large request should be allowed")
+	now = now.Add(1 * time.Second)
+	err = rl.Allow(ctx, rt1, 10)
+	require.True(errors.Is(err, api.ErrRateLimited), "request should be rate limited while in debt")
+	now = now.Add(1 * time.Second)
+	require.NoError(rl.Allow(ctx, rt1, 10), "request should be allowed after debt is repaid")
+
+	// Idle entries are pruned.
+	now = now.Add(rateLimiterPruneInterval)
+	require.NoError(rl.Allow(ctx, rt2, 10), "request should be allowed")
+	require.Len(rl.entries, 1, "idle entries should be pruned")
+}
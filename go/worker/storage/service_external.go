@@ -23,8 +23,9 @@ var (
 
 // storageService is the service exposed to external clients via gRPC.
 type storageService struct {
-	w       *Worker
-	storage api.Backend
+	w           *Worker
+	storage     api.Backend
+	rateLimiter *rateLimiter
 
 	debugRejectUpdates bool
 }
@@ -83,6 +84,9 @@ func (s *storageService) Apply(ctx context.Context, request *api.ApplyRequest) (
 	if s.debugRejectUpdates {
 		return nil, errDebugRejectUpdates
 	}
+	if err := s.rateLimiter.Allow(ctx, request.Namespace, writeLogSize(request.WriteLog)); err != nil {
+		return nil, err
+	}
 
 	// Limit maximum number of entries in a write log.
 	cfg, err := s.getConfig(ctx, request.Namespace)
@@ -103,6 +107,9 @@ func (s *storageService) ApplyBatch(ctx context.Context, request *api.ApplyBatch
 	if s.debugRejectUpdates {
 		return nil, errDebugRejectUpdates
 	}
+	if err := s.rateLimiter.Allow(ctx, request.Namespace, applyBatchSize(request)); err != nil {
+		return nil, err
+	}
 
 	// Limit maximum number of operations in a batch.
 	cfg, err := s.getConfig(ctx, request.Namespace)
@@ -129,6 +136,9 @@ func (s *storageService) Merge(ctx context.Context, request *api.MergeRequest) (
 	if s.debugRejectUpdates {
 		return nil, errDebugRejectUpdates
 	}
+	if err := s.rateLimiter.Allow(ctx, request.Namespace, 0); err != nil {
+		return nil, err
+	}
 
 	// Limit maximum number of roots to merge.
 	cfg, err := s.getConfig(ctx, request.Namespace)
@@ -149,6 +159,9 @@ func (s *storageService) MergeBatch(ctx context.Context, request *api.MergeBatch
 	if s.debugRejectUpdates {
 		return nil, errDebugRejectUpdates
 	}
+	if err := s.rateLimiter.Allow(ctx, request.Namespace, 0); err != nil {
+		return nil, err
+	}
 
 	// Limit maximum number of operations in a batch.
 	cfg, err := s.getConfig(ctx, request.Namespace)
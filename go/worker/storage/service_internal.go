@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 
+	storageApi "github.com/oasislabs/oasis-core/go/storage/api"
 	"github.com/oasislabs/oasis-core/go/worker/storage/api"
 )
 
@@ -30,3 +31,12 @@ func (w *Worker) ForceFinalize(ctx context.Context, request *api.ForceFinalizeRe
 
 	return node.ForceFinalize(ctx, request.Round)
 }
+
+func (w *Worker) GetUsage(ctx context.Context, request *api.GetUsageRequest) (*storageApi.Usage, error) {
+	node := w.runtimes[request.RuntimeID]
+	if node == nil {
+		return nil, api.ErrRuntimeNotFound
+	}
+
+	return node.GetUsage(ctx)
+}
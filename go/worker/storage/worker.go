@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/grpc"
 	"github.com/oasislabs/oasis-core/go/common/grpc/policy"
 	"github.com/oasislabs/oasis-core/go/common/logging"
@@ -32,6 +33,19 @@ const (
 
 	// CfgWorkerCheckpointCheckInterval configures the checkpointer check interval.
 	CfgWorkerCheckpointCheckInterval = "worker.storage.checkpointer.check_interval"
+	// CfgWorkerCheckpointHTTPAddress configures the address of the HTTP
+	// endpoint serving storage checkpoints.
+	CfgWorkerCheckpointHTTPAddress = "worker.storage.checkpointer.http_address"
+	// CfgWorkerGenesisCheckpointURL configures the base URL of an HTTP(S)
+	// endpoint (e.g., a CDN) from which genesis checkpoints are fetched.
+	CfgWorkerGenesisCheckpointURL = "worker.storage.genesis_checkpoint_url"
+
+	// CfgWorkerRateLimitRequests configures the maximum number of update
+	// requests per second for each runtime and client.
+	CfgWorkerRateLimitRequests = "worker.storage.rate_limit.requests"
+	// CfgWorkerRateLimitBytes configures the maximum number of write log
+	// bytes per second for each runtime and client.
+	CfgWorkerRateLimitBytes = "worker.storage.rate_limit.bytes"
 
 	// CfgWorkerDebugIgnoreApply is a debug option that makes the worker ignore
 	// all apply operations.
@@ -66,6 +80,8 @@ type Worker struct {
 	fetchPool  *workerpool.Pool
 
 	grpcPolicy *policy.DynamicRuntimePolicyChecker
+
+	checkpointHTTP *checkpointHTTPServer
 }
 
 // New constructs a new storage worker.
@@ -103,6 +119,7 @@ func New(
 		api.RegisterService(s.commonWorker.Grpc.Server(), &storageService{
 			w:                  s,
 			storage:            s.commonWorker.RuntimeRegistry.StorageRouter(),
+			rateLimiter:        newRateLimiter(viper.GetUint64(CfgWorkerRateLimitRequests), viper.GetUint64(CfgWorkerRateLimitBytes)),
 			debugRejectUpdates: viper.GetBool(CfgWorkerDebugIgnoreApply) && flags.DebugDontBlameOasis(),
 		})
 
@@ -110,9 +127,29 @@ func New(
 			CheckInterval: viper.GetDuration(CfgWorkerCheckpointCheckInterval),
 		}
 
+		// Serve checkpoints over HTTP if configured.
+		if addr := viper.GetString(CfgWorkerCheckpointHTTPAddress); addr != "" {
+			s.checkpointHTTP = newCheckpointHTTPServer(
+				addr,
+				s.commonWorker.RuntimeRegistry.StorageRouter(),
+				s.commonWorker.Identity.NodeSigner,
+			)
+		}
+
+		// Fetch genesis checkpoints over HTTP if configured. The checkpoint
+		// metadata is pinned by the genesis document, so no manifests are
+		// needed (and thus no manifest signer is configured).
+		var genesisCheckpointProvider checkpoint.ChunkProvider
+		if baseURL := viper.GetString(CfgWorkerGenesisCheckpointURL); baseURL != "" {
+			genesisCheckpointProvider, err = checkpoint.NewHTTPChunkProvider(baseURL, signature.PublicKey{})
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		// Start storage node for every runtime.
 		for _, rt := range s.commonWorker.GetRuntimes() {
-			if err := s.registerRuntime(rt, checkpointerCfg); err != nil {
+			if err := s.registerRuntime(rt, checkpointerCfg, genesisCheckpointProvider); err != nil {
 				return nil, err
 			}
 		}
@@ -124,7 +161,19 @@ func New(
 	return s, nil
 }
 
-func (s *Worker) registerRuntime(commonNode *committeeCommon.Node, checkpointerCfg checkpoint.CheckpointerConfig) error {
+// GetRuntime returns a registered runtime.
+//
+// In case the runtime with the specified id was not registered it
+// returns nil.
+func (s *Worker) GetRuntime(id common.Namespace) *committee.Node {
+	return s.runtimes[id]
+}
+
+func (s *Worker) registerRuntime(
+	commonNode *committeeCommon.Node,
+	checkpointerCfg checkpoint.CheckpointerConfig,
+	genesisCheckpointProvider checkpoint.ChunkProvider,
+) error {
 	id := commonNode.Runtime.ID()
 	s.logger.Info("registering new runtime",
 		"runtime_id", id,
@@ -135,7 +184,16 @@ func (s *Worker) registerRuntime(commonNode *committeeCommon.Node, checkpointerC
 		return fmt.Errorf("failed to create role provider: %w", err)
 	}
 
-	node, err := committee.NewNode(commonNode, s.grpcPolicy, s.fetchPool, s.watchState, rp, s.commonWorker.GetConfig(), checkpointerCfg)
+	node, err := committee.NewNode(
+		commonNode,
+		s.grpcPolicy,
+		s.fetchPool,
+		s.watchState,
+		rp,
+		s.commonWorker.GetConfig(),
+		checkpointerCfg,
+		genesisCheckpointProvider,
+	)
 	if err != nil {
 		return err
 	}
@@ -176,6 +234,12 @@ func (s *Worker) Start() error {
 		return nil
 	}
 
+	if s.checkpointHTTP != nil {
+		if err := s.checkpointHTTP.start(); err != nil {
+			return err
+		}
+	}
+
 	// Wait for all runtimes to terminate.
 	go func() {
 		defer close(s.quitCh)
@@ -217,6 +281,9 @@ func (s *Worker) Stop() {
 		return
 	}
 
+	if s.checkpointHTTP != nil {
+		s.checkpointHTTP.stop()
+	}
 	for _, r := range s.runtimes {
 		r.Stop()
 	}
@@ -241,6 +308,10 @@ func init() {
 	Flags.Bool(CfgWorkerEnabled, false, "Enable storage worker")
 	Flags.Uint(cfgWorkerFetcherCount, 4, "Number of concurrent storage diff fetchers")
 	Flags.Duration(CfgWorkerCheckpointCheckInterval, 1*time.Minute, "Storage checkpointer check interval")
+	Flags.String(CfgWorkerCheckpointHTTPAddress, "", "Serve storage checkpoints over HTTP at given address")
+	Flags.String(CfgWorkerGenesisCheckpointURL, "", "Base URL of an HTTP(S) endpoint serving genesis checkpoints")
+	Flags.Uint64(CfgWorkerRateLimitRequests, 0, "Maximum update requests per second for each runtime and client (0 disables)")
+	Flags.Uint64(CfgWorkerRateLimitBytes, 0, "Maximum update write log bytes per second for each runtime and client (0 disables)")
 
 	Flags.Bool(CfgWorkerDebugIgnoreApply, false, "Ignore Apply operations (for debugging purposes)")
 	_ = Flags.MarkHidden(CfgWorkerDebugIgnoreApply)
@@ -170,6 +170,12 @@ impl Dispatcher {
 
         'dispatch: loop {
             match rx.recv() {
+                Ok((_, id, _)) if protocol.is_cancelled(id) => {
+                    // Request was cancelled by the worker host before it was
+                    // dispatched, there is no need to process it.
+                    debug!(self.logger, "Skipping cancelled request"; "id" => id);
+                    let _ = protocol.send_response(id, Body::Empty {});
+                }
                 Ok((
                     ctx,
                     id,
@@ -5,7 +5,7 @@ use crate::{
         version::Version,
     },
     dispatcher::{Dispatcher, Initializer},
-    protocol::{Protocol, Stream},
+    protocol::{Protocol, Stream, StreamConnector},
     rak::RAK,
 };
 
@@ -31,13 +31,18 @@ pub fn start_runtime(initializer: Box<dyn Initializer>, version: Version) {
     info!(logger, "Establishing connection with the worker host");
 
     #[cfg(not(target_env = "sgx"))]
-    let stream = match Stream::connect(env::var("OASIS_WORKER_HOST").unwrap_or_default()) {
+    let host = env::var("OASIS_WORKER_HOST").unwrap_or_default();
+    #[cfg(not(target_env = "sgx"))]
+    let stream = match Stream::connect(&host) {
         Err(error) => {
             error!(logger, "Failed to connect with the worker host"; "err" => %error);
             return;
         }
         Ok(stream) => stream,
     };
+    // Additional streams to the worker host use the same address.
+    #[cfg(not(target_env = "sgx"))]
+    let connector: Option<StreamConnector> = Some(Box::new(move || Ok(Stream::connect(&host)?)));
 
     #[cfg(target_env = "sgx")]
     let stream = match Stream::connect("worker-host") {
@@ -47,11 +52,16 @@ pub fn start_runtime(initializer: Box<dyn Initializer>, version: Version) {
         }
         Ok(stream) => stream,
     };
+    // The number of enclave threads is limited, so SGX runtimes only use a
+    // single stream to the worker host.
+    #[cfg(target_env = "sgx")]
+    let connector: Option<StreamConnector> = None;
 
     // Start handling protocol messages. This blocks the main thread forever
     // (or until we get a shutdown request).
     let protocol = Arc::new(Protocol::new(
         stream,
+        connector,
         rak.clone(),
         dispatcher.clone(),
         version,
@@ -4,8 +4,9 @@ use std::{
     io::{BufReader, BufWriter, Read, Write},
     sync::{
         atomic::{AtomicUsize, Ordering},
-        Arc, Mutex,
+        Arc, Mutex, RwLock,
     },
+    thread,
 };
 
 use byteorder::{BigEndian, ReadBytesExt, WriteBytesExt};
@@ -29,8 +30,14 @@ pub type Stream = ::std::os::unix::net::UnixStream;
 #[cfg(target_env = "sgx")]
 pub type Stream = ::std::net::TcpStream;
 
+/// Function used to open additional streams to the worker host.
+pub type StreamConnector = Box<dyn Fn() -> Fallible<Stream> + Send + Sync>;
+
 /// Maximum message size.
 const MAX_MESSAGE_SIZE: usize = 104_857_600; // 100MB
+/// Maximum number of streams (including the initial one) to open to the
+/// worker host.
+const MAX_STREAMS: u16 = 4;
 
 #[derive(Debug, Fail)]
 pub enum ProtocolError {
@@ -47,6 +54,33 @@ pub enum ProtocolError {
     RuntimeIDNotSet,
 }
 
+/// A stream to the worker host.
+struct ProtocolStream {
+    /// Mutex for sending outgoing messages.
+    outgoing_mutex: Mutex<()>,
+    /// Stream to the runtime host.
+    stream: Stream,
+}
+
+impl ProtocolStream {
+    fn new(stream: Stream) -> Arc<Self> {
+        Arc::new(Self {
+            outgoing_mutex: Mutex::new(()),
+            stream,
+        })
+    }
+}
+
+/// An incoming request queued to the dispatcher.
+struct InFlightRequest {
+    /// Index of the stream the request was received on.
+    stream: usize,
+    /// Request identifier assigned by the worker host.
+    id: u64,
+    /// Whether the worker host has cancelled the request.
+    cancelled: bool,
+}
+
 /// Runtime part of the runtime host protocol.
 pub struct Protocol {
     /// Logger.
@@ -56,14 +90,21 @@ pub struct Protocol {
     rak: Arc<RAK>,
     /// Incoming request dispatcher.
     dispatcher: Arc<Dispatcher>,
-    /// Mutex for sending outgoing messages.
-    outgoing_mutex: Mutex<()>,
-    /// Stream to the runtime host.
-    stream: Stream,
+    /// Streams to the runtime host, the first one being the initial stream.
+    streams: RwLock<Vec<Arc<ProtocolStream>>>,
+    /// Connector for opening additional streams to the runtime host.
+    connector: Option<StreamConnector>,
+    /// Stream selector for outgoing requests.
+    next_stream: AtomicUsize,
     /// Outgoing request identifier generator.
     last_request_id: AtomicUsize,
     /// Pending outgoing requests.
     pending_out_requests: Mutex<HashMap<u64, channel::Sender<Body>>>,
+    /// Incoming request identifier generator.
+    last_in_request_id: AtomicUsize,
+    /// Incoming requests queued to the dispatcher, keyed by their local
+    /// identifier.
+    in_flight_requests: Mutex<HashMap<u64, InFlightRequest>>,
     /// Runtime identifier.
     runtime_id: Mutex<Option<RuntimeId>>,
     /// Runtime version.
@@ -72,8 +113,12 @@ pub struct Protocol {
 
 impl Protocol {
     /// Create a new protocol handler instance.
+    ///
+    /// If a connector is given, additional streams to the worker host are
+    /// opened during initialization when the worker host allows it.
     pub fn new(
         stream: Stream,
+        connector: Option<StreamConnector>,
         rak: Arc<RAK>,
         dispatcher: Arc<Dispatcher>,
         runtime_version: Version,
@@ -84,10 +129,13 @@ impl Protocol {
             logger,
             rak,
             dispatcher,
-            outgoing_mutex: Mutex::new(()),
-            stream,
+            streams: RwLock::new(vec![ProtocolStream::new(stream)]),
+            connector,
+            next_stream: AtomicUsize::new(0),
             last_request_id: AtomicUsize::new(0),
             pending_out_requests: Mutex::new(HashMap::new()),
+            last_in_request_id: AtomicUsize::new(0),
+            in_flight_requests: Mutex::new(HashMap::new()),
             runtime_id: Mutex::new(None),
             runtime_version: runtime_version,
         }
@@ -108,19 +156,60 @@ impl Protocol {
     /// Start the protocol handler loop.
     pub fn start(self: &Arc<Protocol>) {
         info!(self.logger, "Starting protocol handler");
-        let mut reader = BufReader::new(&self.stream);
+        let stream = self.streams.read().unwrap()[0].clone();
+        self.run_stream(0, stream);
+
+        info!(self.logger, "Protocol handler is terminating");
+    }
+
+    /// Handle messages received on the given stream until it fails.
+    fn run_stream(self: &Arc<Protocol>, index: usize, stream: Arc<ProtocolStream>) {
+        let mut reader = BufReader::new(&stream.stream);
 
         'recv: loop {
-            match self.handle_message(&mut reader) {
+            match self.handle_message(index, &stream, &mut reader) {
                 Err(error) => {
-                    error!(self.logger, "Failed to handle message"; "err" => %error);
+                    error!(self.logger, "Failed to handle message"; "err" => %error, "stream" => index);
                     break 'recv;
                 }
                 Ok(()) => {}
             }
         }
+    }
 
-        info!(self.logger, "Protocol handler is terminating");
+    /// Open additional streams to the worker host so that there are at most
+    /// the given number of streams in total.
+    ///
+    /// Returns the total number of open streams.
+    fn open_streams(self: &Arc<Protocol>, max_streams: u16) -> u16 {
+        let mut streams = self.streams.write().unwrap();
+        let connector = match self.connector {
+            Some(ref connector) => connector,
+            None => return streams.len() as u16,
+        };
+
+        while streams.len() < max_streams.min(MAX_STREAMS) as usize {
+            let stream = match connector() {
+                Ok(stream) => ProtocolStream::new(stream),
+                Err(error) => {
+                    warn!(self.logger, "Failed to open additional stream"; "err" => %error);
+                    break;
+                }
+            };
+
+            let index = streams.len();
+            let p = self.clone();
+            let s = stream.clone();
+            if let Err(error) = thread::Builder::new().spawn(move || p.run_stream(index, s)) {
+                warn!(self.logger, "Failed to start additional stream handler"; "err" => %error);
+                break;
+            }
+            streams.push(stream);
+        }
+
+        info!(self.logger, "Opened streams to the worker host"; "streams" => streams.len());
+
+        streams.len() as u16
     }
 
     /// Make a new request to the worker host and wait for the response.
@@ -132,6 +221,7 @@ impl Protocol {
             body,
             span_context,
             message_type: MessageType::Request,
+            priority: 0,
         };
 
         // Create a response channel and register an outstanding pending request.
@@ -141,8 +231,12 @@ impl Protocol {
             pending_requests.insert(id, tx);
         }
 
-        // Write message to stream and wait for the response.
-        self.encode_message(message)?;
+        // Write message to the next stream and wait for the response.
+        let stream = {
+            let streams = self.streams.read().unwrap();
+            streams[self.next_stream.fetch_add(1, Ordering::SeqCst) % streams.len()].clone()
+        };
+        self.encode_message(&stream, message)?;
 
         match rx.recv()? {
             Body::Error { message, .. } => Err(format_err!("{}", message)),
@@ -151,13 +245,46 @@ impl Protocol {
     }
 
     /// Send an async response to a previous request back to the worker host.
+    ///
+    /// If the request has been cancelled by the worker host, no response is sent.
     pub fn send_response(&self, id: u64, body: Body) -> Fallible<()> {
-        self.encode_message(Message {
-            id,
-            body,
-            span_context: vec![],
-            message_type: MessageType::Response,
-        })
+        let request = {
+            let mut in_flight_requests = self.in_flight_requests.lock().unwrap();
+            in_flight_requests.remove(&id)
+        };
+        let request = match request {
+            Some(request) => request,
+            None => {
+                warn!(self.logger, "Attempted to respond to an unknown request"; "id" => id);
+                return Ok(());
+            }
+        };
+        if request.cancelled {
+            return Ok(());
+        }
+
+        let stream = self.streams.read().unwrap()[request.stream].clone();
+        self.encode_message(
+            &stream,
+            Message {
+                id: request.id,
+                body,
+                span_context: vec![],
+                message_type: MessageType::Response,
+                priority: 0,
+            },
+        )
+    }
+
+    /// Check whether an incoming request has been cancelled by the worker host.
+    ///
+    /// Long-running request handlers may use this to abort early.
+    pub fn is_cancelled(&self, id: u64) -> bool {
+        let in_flight_requests = self.in_flight_requests.lock().unwrap();
+        in_flight_requests
+            .get(&id)
+            .map(|request| request.cancelled)
+            .unwrap_or(false)
     }
 
     fn decode_message<R: Read>(&self, mut reader: R) -> Fallible<Message> {
@@ -173,9 +300,9 @@ impl Protocol {
         Ok(cbor::from_slice(&buffer)?)
     }
 
-    fn encode_message(&self, message: Message) -> Fallible<()> {
-        let _guard = self.outgoing_mutex.lock().unwrap();
-        let mut writer = BufWriter::new(&self.stream);
+    fn encode_message(&self, stream: &ProtocolStream, message: Message) -> Fallible<()> {
+        let _guard = stream.outgoing_mutex.lock().unwrap();
+        let mut writer = BufWriter::new(&stream.stream);
 
         let buffer = cbor::to_vec(&message);
         if buffer.len() > MAX_MESSAGE_SIZE {
@@ -188,7 +315,12 @@ impl Protocol {
         Ok(())
     }
 
-    fn handle_message<R: Read>(self: &Arc<Protocol>, reader: R) -> Fallible<()> {
+    fn handle_message<R: Read>(
+        self: &Arc<Protocol>,
+        index: usize,
+        stream: &ProtocolStream,
+        reader: R,
+    ) -> Fallible<()> {
         let message = self.decode_message(reader)?;
 
         match message.message_type {
@@ -198,7 +330,7 @@ impl Protocol {
                 let mut ctx = Context::background();
                 tracing::add_span_context(&mut ctx, message.span_context);
 
-                let body = match self.handle_request(ctx, id, message.body) {
+                let body = match self.handle_request(ctx, index, id, message.body) {
                     Ok(Some(result)) => result,
                     Ok(None) => {
                         // A message will be sent later by another thread so there
@@ -213,12 +345,16 @@ impl Protocol {
                 };
 
                 // Send response back.
-                self.encode_message(Message {
-                    id,
-                    message_type: MessageType::Response,
-                    body,
-                    span_context: vec![],
-                })?;
+                self.encode_message(
+                    stream,
+                    Message {
+                        id,
+                        message_type: MessageType::Response,
+                        priority: message.priority,
+                        body,
+                        span_context: vec![],
+                    },
+                )?;
             }
             MessageType::Response => {
                 // Response to our request.
@@ -238,28 +374,72 @@ impl Protocol {
                     }
                 }
             }
+            MessageType::Cancel => {
+                // Cancellation of a previous incoming request.
+                let mut in_flight_requests = self.in_flight_requests.lock().unwrap();
+                match in_flight_requests
+                    .values_mut()
+                    .find(|request| request.stream == index && request.id == message.id)
+                {
+                    Some(request) => {
+                        info!(self.logger, "Received request cancellation"; "msg_id" => message.id);
+                        request.cancelled = true;
+                    }
+                    None => {
+                        debug!(self.logger, "Received cancellation for unknown request"; "msg_id" => message.id);
+                    }
+                }
+            }
             _ => warn!(self.logger, "Received a malformed message"),
         }
 
         Ok(())
     }
 
+    fn queue_request(&self, ctx: Context, stream: usize, id: u64, request: Body) -> Fallible<()> {
+        // Requests from different streams may use the same identifier, so
+        // assign a local identifier to each queued request.
+        let local_id = self.last_in_request_id.fetch_add(1, Ordering::SeqCst) as u64;
+        self.in_flight_requests.lock().unwrap().insert(
+            local_id,
+            InFlightRequest {
+                stream,
+                id,
+                cancelled: false,
+            },
+        );
+        if let Err(error) = self.dispatcher.queue_request(ctx, local_id, request) {
+            self.in_flight_requests.lock().unwrap().remove(&local_id);
+            return Err(error);
+        }
+        Ok(())
+    }
+
     fn handle_request(
         self: &Arc<Protocol>,
         ctx: Context,
+        stream: usize,
         id: u64,
         request: Body,
     ) -> Fallible<Option<Body>> {
         match request {
-            Body::RuntimeInfoRequest { runtime_id } => {
+            Body::RuntimeInfoRequest {
+                runtime_id,
+                max_connections,
+            } => {
                 // Store the passed Runtime ID.
                 *self.runtime_id.lock().unwrap() = Some(runtime_id);
 
+                // Open any additional streams before responding as the worker
+                // host expects them to be open once it receives the response.
+                let connections = self.open_streams(max_connections);
+
                 self.dispatcher.start(self.clone());
 
                 Ok(Some(Body::RuntimeInfoResponse {
                     protocol_version: BUILD_INFO.protocol_version.into(),
                     runtime_version: self.runtime_version.into(),
+                    connections,
                 }))
             }
             Body::RuntimePingRequest {} => Ok(Some(Body::Empty {})),
@@ -306,22 +486,22 @@ impl Protocol {
             }
             req @ Body::RuntimeRPCCallRequest { .. } => {
                 self.can_handle_runtime_requests()?;
-                self.dispatcher.queue_request(ctx, id, req)?;
+                self.queue_request(ctx, stream, id, req)?;
                 Ok(None)
             }
             req @ Body::RuntimeLocalRPCCallRequest { .. } => {
                 self.can_handle_runtime_requests()?;
-                self.dispatcher.queue_request(ctx, id, req)?;
+                self.queue_request(ctx, stream, id, req)?;
                 Ok(None)
             }
             req @ Body::RuntimeCheckTxBatchRequest { .. } => {
                 self.can_handle_runtime_requests()?;
-                self.dispatcher.queue_request(ctx, id, req)?;
+                self.queue_request(ctx, stream, id, req)?;
                 Ok(None)
             }
             req @ Body::RuntimeExecuteTxBatchRequest { .. } => {
                 self.can_handle_runtime_requests()?;
-                self.dispatcher.queue_request(ctx, id, req)?;
+                self.queue_request(ctx, stream, id, req)?;
                 Ok(None)
             }
             req => {
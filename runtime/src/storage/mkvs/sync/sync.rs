@@ -53,6 +53,10 @@ pub struct IterateRequest {
 #[derive(Clone, Debug, Default, PartialEq, Serialize, Deserialize)]
 pub struct ProofResponse {
     pub proof: Proof,
+    /// Whether the proof was cut short due to the proof limits of the remote
+    /// syncer. Missing items are fetched on demand.
+    #[serde(default)]
+    pub truncated: bool,
 }
 
 /// ReadSync is the interface for synchronizing the in-memory cache
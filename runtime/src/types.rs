@@ -1,4 +1,6 @@
 //! Types used by the worker-host protocol.
+use std::collections::BTreeMap;
+
 use serde::{self, Deserializer, Serializer};
 use serde_bytes;
 use serde_derive::{Deserialize, Serialize};
@@ -64,10 +66,13 @@ pub enum Body {
     // Runtime interface.
     RuntimeInfoRequest {
         runtime_id: RuntimeId,
+        #[serde(default)]
+        max_connections: u16,
     },
     RuntimeInfoResponse {
         protocol_version: u64,
         runtime_version: u64,
+        connections: u16,
     },
     RuntimePingRequest {},
     RuntimeShutdownRequest {},
@@ -167,6 +172,52 @@ pub enum Body {
         value: Vec<u8>,
     },
     HostLocalStorageSetResponse {},
+    HostLogRequest {
+        level: LogLevel,
+        module: String,
+        message: String,
+        #[serde(default)]
+        fields: BTreeMap<String, String>,
+    },
+    HostLogResponse {},
+}
+
+/// Log level of a log record forwarded to the host.
+#[derive(Clone, Copy, Debug, PartialEq, Eq)]
+#[repr(u8)]
+pub enum LogLevel {
+    /// Debug messages.
+    Debug = 0,
+    /// Informative messages.
+    Info = 1,
+    /// Warning messages.
+    Warn = 2,
+    /// Error messages.
+    Error = 3,
+}
+
+impl serde::Serialize for LogLevel {
+    fn serialize<S>(&self, serializer: S) -> Result<S::Ok, S::Error>
+    where
+        S: Serializer,
+    {
+        serializer.serialize_u8(*self as u8)
+    }
+}
+
+impl<'de> serde::Deserialize<'de> for LogLevel {
+    fn deserialize<D>(deserializer: D) -> Result<Self, D::Error>
+    where
+        D: Deserializer<'de>,
+    {
+        match u8::deserialize(deserializer)? {
+            0 => Ok(LogLevel::Debug),
+            1 => Ok(LogLevel::Info),
+            2 => Ok(LogLevel::Warn),
+            3 => Ok(LogLevel::Error),
+            _ => Err(serde::de::Error::custom("invalid log level")),
+        }
+    }
 }
 
 #[derive(Clone, Copy, Debug)]
@@ -178,6 +229,8 @@ pub enum MessageType {
     Request = 1,
     /// Response.
     Response = 2,
+    /// Request cancellation.
+    Cancel = 3,
 }
 
 impl serde::Serialize for MessageType {
@@ -197,6 +250,7 @@ impl<'de> serde::Deserialize<'de> for MessageType {
         match u8::deserialize(deserializer)? {
             1 => Ok(MessageType::Request),
             2 => Ok(MessageType::Response),
+            3 => Ok(MessageType::Cancel),
             _ => Err(serde::de::Error::custom("invalid message type")),
         }
     }
@@ -209,6 +263,9 @@ pub struct Message {
     pub id: u64,
     /// Message type.
     pub message_type: MessageType,
+    /// Request priority class.
+    #[serde(default)]
+    pub priority: u8,
     /// Message body.
     pub body: Body,
     /// Opentracing's SpanContext serialized in binary format.